@@ -0,0 +1,296 @@
+package analyzer
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Kinds of static assets
+const (
+	AssetImage    = "image"
+	AssetStyle    = "style"
+	AssetData     = "data"
+	AssetFont     = "font"
+	AssetMedia    = "media"
+	AssetDocument = "document"
+	AssetMarkup   = "markup"
+)
+
+// assetKinds maps the extensions of static assets to their kind
+var assetKinds = map[string]string{
+	".png": AssetImage, ".jpg": AssetImage, ".jpeg": AssetImage, ".gif": AssetImage, ".svg": AssetImage,
+	".webp": AssetImage, ".avif": AssetImage, ".ico": AssetImage, ".bmp": AssetImage,
+	".css": AssetStyle, ".scss": AssetStyle, ".sass": AssetStyle, ".less": AssetStyle,
+	".json": AssetData, ".yaml": AssetData, ".yml": AssetData, ".csv": AssetData, ".xml": AssetData,
+	".txt": AssetData, ".toml": AssetData,
+	".woff": AssetFont, ".woff2": AssetFont, ".ttf": AssetFont, ".otf": AssetFont, ".eot": AssetFont,
+	".mp4": AssetMedia, ".webm": AssetMedia, ".mp3": AssetMedia, ".wav": AssetMedia, ".ogg": AssetMedia,
+	".pdf":  AssetDocument,
+	".html": AssetMarkup, ".htm": AssetMarkup,
+}
+
+// assetSourceExtensions are files outside the graph scanned for asset references
+var assetSourceExtensions = map[string]bool{
+	".css": true, ".scss": true, ".sass": true, ".less": true, ".html": true, ".htm": true,
+	".vue": true, ".svelte": true, ".astro": true,
+}
+
+// assetSkipDirs are never descended into when looking for assets
+var assetSkipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true, "build": true, "out": true,
+	".next": true, ".nuxt": true, ".dart_tool": true, ".codecontext": true, "coverage": true,
+}
+
+// assetDataDirs hold data files that are assets rather than configuration
+var assetDataDirs = map[string]bool{
+	"testdata": true, "fixtures": true, "__fixtures__": true, "data": true, "assets": true,
+	"static": true, "public": true, "resources": true,
+}
+
+// assetWebRoots serve their files at the root of a site, so "/logo.png" is public/logo.png
+var assetWebRoots = map[string]bool{"public": true, "static": true, "www": true, "wwwroot": true, "assets": true}
+
+var (
+	assetQuoted    = regexp.MustCompile(`["'` + "`" + `]([^"'` + "`" + `\s()<>*]+\.([A-Za-z0-9]+))(?:[?#][^"'` + "`" + `\s]*)?["'` + "`" + `]`)
+	assetCSSURL    = regexp.MustCompile(`\burl\(\s*["']?([^"')\s?#]+)`)
+	assetCSSImport = regexp.MustCompile(`@(?:import|use|forward)\s+(?:url\()?["']([^"']+)["']`)
+	goEmbed        = regexp.MustCompile(`^//go:embed\s+(.+)$`)
+)
+
+// AssetReference is a place in the code that names an asset
+type AssetReference struct {
+	File string `json:"file"` // Relative to the project root
+	Line int    `json:"line"`
+	Text string `json:"text"` // The reference as written
+}
+
+// AssetUsage is a static asset and the code that refers to it
+type AssetUsage struct {
+	Path       string           `json:"path"` // Relative to the project root
+	Kind       string           `json:"kind"`
+	References []AssetReference `json:"references"`
+}
+
+// AssetReport lists the static assets of a project and the references to
+// them that do not resolve to a file
+type AssetReport struct {
+	Assets  []AssetUsage     `json:"assets"` // Most referenced first
+	Missing []AssetReference `json:"missing"`
+}
+
+// FindAssetUsages walks root for static assets (images, styles, data
+// fixtures, fonts, media, documents and HTML templates) and finds the
+// string literals, CSS url() and @import rules and //go:embed directives
+// naming them in the source files of the graph, including tests, and in
+// stylesheets and markup. References resolve relative to the referring
+// file, to root, to web roots such as public/ for absolute URLs, and "@/"
+// to src/. Explicit relative or absolute paths that resolve to no file are
+// reported as missing. JSON, YAML and other data files are only listed
+// when referenced or kept in a data directory such as testdata/, so that
+// configuration files do not show up as unused.
+func FindAssetUsages(graph *types.CodeGraph, root string) AssetReport {
+	assets := make(map[string]*AssetUsage)
+	var sources []string
+	filepath.WalkDir(root, func(filePath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if filePath != root && (assetSkipDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(filePath))
+		rel := projectPath(root, filePath)
+		if kind, ok := assetKinds[ext]; ok {
+			assets[rel] = &AssetUsage{Path: rel, Kind: kind}
+		}
+		if assetSourceExtensions[ext] && graph.Files[filePath] == nil {
+			sources = append(sources, filePath)
+		}
+		return nil
+	})
+	for filePath := range graph.Files {
+		sources = append(sources, filePath)
+	}
+	sort.Strings(sources)
+
+	var report AssetReport
+	for _, filePath := range sources {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		from := projectPath(root, filePath)
+		for i, line := range strings.Split(string(content), "\n") {
+			for _, ref := range assetReferences(line) {
+				site := AssetReference{File: from, Line: i + 1, Text: ref}
+				resolved := resolveAsset(assets, from, ref)
+				for _, asset := range resolved {
+					if asset != from {
+						assets[asset].References = append(assets[asset].References, site)
+					}
+				}
+				if len(resolved) == 0 && explicitAssetPath(ref) {
+					report.Missing = append(report.Missing, site)
+				}
+			}
+			if m := goEmbed.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				site := AssetReference{File: from, Line: i + 1}
+				for _, pattern := range strings.Fields(m[1]) {
+					site.Text = strings.Trim(pattern, "\"`")
+					for _, asset := range embeddedAssets(assets, path.Dir(from), site.Text) {
+						assets[asset].References = append(assets[asset].References, site)
+					}
+				}
+			}
+		}
+	}
+
+	for _, asset := range assets {
+		if asset.Kind == AssetData && len(asset.References) == 0 && !inAssetDataDir(asset.Path) {
+			continue
+		}
+		report.Assets = append(report.Assets, *asset)
+	}
+	sort.Slice(report.Assets, func(i, j int) bool {
+		if len(report.Assets[i].References) != len(report.Assets[j].References) {
+			return len(report.Assets[i].References) > len(report.Assets[j].References)
+		}
+		return report.Assets[i].Path < report.Assets[j].Path
+	})
+	return report
+}
+
+// assetReferences returns the paths to assets named on a line of code,
+// stylesheet or markup, without query strings and fragments
+func assetReferences(line string) []string {
+	var refs []string
+	add := func(ref string) {
+		if ref == "" || strings.Contains(ref, "://") || strings.HasPrefix(ref, "//") || strings.HasPrefix(ref, "data:") ||
+			strings.Contains(ref, "${") || strings.Contains(ref, "{{") || slices.Contains(refs, ref) {
+			return
+		}
+		refs = append(refs, ref)
+	}
+	for _, m := range assetQuoted.FindAllStringSubmatch(line, -1) {
+		if _, ok := assetKinds["."+strings.ToLower(m[2])]; ok {
+			add(m[1])
+		}
+	}
+	for _, m := range assetCSSURL.FindAllStringSubmatch(line, -1) {
+		add(m[1])
+	}
+	for _, m := range assetCSSImport.FindAllStringSubmatch(line, -1) {
+		add(m[1])
+	}
+	return refs
+}
+
+// resolveAsset returns the assets a reference from a file can name
+func resolveAsset(assets map[string]*AssetUsage, from, ref string) []string {
+	ref = strings.SplitN(strings.SplitN(ref, "?", 2)[0], "#", 2)[0]
+	var candidates []string
+	switch {
+	case strings.HasPrefix(ref, "@/"):
+		candidates = []string{path.Join("src", ref[2:])}
+	case strings.HasPrefix(ref, "~"):
+		return nil // Packages in node_modules
+	case strings.HasPrefix(ref, "/"):
+		candidates = []string{strings.TrimPrefix(ref, "/")}
+		var served []string
+		for asset := range assets {
+			if prefix, ok := strings.CutSuffix(asset, ref); ok && assetWebRoots[path.Base(prefix)] {
+				served = append(served, asset)
+			}
+		}
+		sort.Strings(served)
+		candidates = append(candidates, served...)
+	default:
+		candidates = []string{path.Join(path.Dir(from), ref), path.Clean(ref)}
+	}
+	// Sass partials and imports without an extension
+	if strings.HasSuffix(from, ".scss") || strings.HasSuffix(from, ".sass") || strings.HasSuffix(from, ".less") {
+		ext := path.Ext(from)
+		for _, candidate := range append([]string(nil), candidates...) {
+			if path.Ext(candidate) == "" {
+				candidates = append(candidates, candidate+ext, path.Join(path.Dir(candidate), "_"+path.Base(candidate)+ext), candidate+".css")
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if assets[candidate] != nil {
+			if strings.HasPrefix(ref, "/") {
+				// Every web root serving the path is affected
+				var found []string
+				for _, c := range candidates {
+					if assets[c] != nil {
+						found = append(found, c)
+					}
+				}
+				return found
+			}
+			return []string{candidate}
+		}
+	}
+
+	// Bare paths such as "images/logo.png" are often relative to a
+	// directory the code runs in; use them when they name a single asset
+	if !explicitAssetPath(ref) && strings.Contains(ref, "/") {
+		var found []string
+		for asset := range assets {
+			if strings.HasSuffix(asset, "/"+ref) {
+				found = append(found, asset)
+			}
+		}
+		if len(found) == 1 {
+			return found
+		}
+	}
+	return nil
+}
+
+// embeddedAssets returns the assets a //go:embed pattern of a package
+// directory includes; directories include everything below them
+func embeddedAssets(assets map[string]*AssetUsage, dir, pattern string) []string {
+	var found []string
+	for asset := range assets {
+		rel, ok := strings.CutPrefix(asset, dir+"/")
+		if dir == "." {
+			rel, ok = asset, true
+		}
+		if !ok {
+			continue
+		}
+		pattern = strings.TrimPrefix(pattern, "all:")
+		if matched, _ := path.Match(pattern, rel); matched || strings.HasPrefix(rel, strings.TrimSuffix(pattern, "/")+"/") {
+			found = append(found, asset)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// explicitAssetPath reports whether a reference is a local path rather
+// than a name resolved at runtime
+func explicitAssetPath(ref string) bool {
+	return strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../") || strings.HasPrefix(ref, "/") || strings.HasPrefix(ref, "@/")
+}
+
+// inAssetDataDir reports whether a path is inside a directory holding data assets
+func inAssetDataDir(rel string) bool {
+	for _, dir := range strings.Split(path.Dir(rel), "/") {
+		if assetDataDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
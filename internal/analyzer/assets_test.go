@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAssetUsages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"public/logo.png":            "png",
+		"public/unused.svg":          "<svg/>",
+		"public/fonts/inter.woff2":   "font",
+		"src/images/hero.jpg":        "jpg",
+		"src/data/cities.json":       "[]",
+		"src/styles/_variables.scss": "$c: red;\n",
+		"src/styles/main.scss":       "@import 'variables';\n.hero { background: url('../images/hero.jpg'); }\n@font-face { src: url(\"/fonts/inter.woff2\"); }\n",
+		"src/App.tsx": "import './styles/main.scss';\nimport cities from '@/data/cities.json';\n\nexport function App() {\n  return <img src=\"/logo.png?v=2\" />;\n}\n" +
+			"const gone = require('./gone.png');\nconst remote = 'https://cdn.example.com/x.png';\n",
+		"index.html":                  "<link rel=\"stylesheet\" href=\"/styles.css\"><img src=\"public/logo.png\">\n",
+		"tsconfig.json":               "{}",
+		"server/templates.go":         "package server\n\nimport \"embed\"\n\n//go:embed templates/*.html\nvar templates embed.FS\n",
+		"server/templates/home.html":  "<h1>home</h1>\n",
+		"server/loader_test.go":       "package server\n\nimport (\n\t\"os\"\n\t\"testing\"\n)\n\nfunc TestLoad(t *testing.T) {\n\tos.ReadFile(\"testdata/users.json\")\n}\n",
+		"server/testdata/users.json":  "[]",
+		"server/testdata/orphan.json": "[]",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	report := FindAssetUsages(graph, dir)
+	usages := make(map[string][]string)
+	for _, asset := range report.Assets {
+		refs := []string{}
+		for _, ref := range asset.References {
+			refs = append(refs, fmt.Sprintf("%s:%d %s", ref.File, ref.Line, ref.Text))
+		}
+		usages[asset.Kind+" "+asset.Path] = refs
+	}
+	assert.Equal(t, map[string][]string{
+		"image public/logo.png":             {"index.html:1 public/logo.png", "src/App.tsx:5 /logo.png"},
+		"image public/unused.svg":           {},
+		"font public/fonts/inter.woff2":     {"src/styles/main.scss:3 /fonts/inter.woff2"},
+		"image src/images/hero.jpg":         {"src/styles/main.scss:2 ../images/hero.jpg"},
+		"data src/data/cities.json":         {"src/App.tsx:2 @/data/cities.json"},
+		"style src/styles/_variables.scss":  {"src/styles/main.scss:1 variables"},
+		"style src/styles/main.scss":        {"src/App.tsx:1 ./styles/main.scss"},
+		"markup index.html":                 {},
+		"markup server/templates/home.html": {"server/templates.go:5 templates/*.html"},
+		"data server/testdata/users.json":   {"server/loader_test.go:9 testdata/users.json"},
+		"data server/testdata/orphan.json":  {},
+	}, usages, "unreferenced configuration such as tsconfig.json is not an asset")
+	assert.Equal(t, "public/logo.png", report.Assets[0].Path, "most referenced first")
+
+	assert.Equal(t, []AssetReference{
+		{File: "index.html", Line: 1, Text: "/styles.css"},
+		{File: "src/App.tsx", Line: 7, Text: "./gone.png"},
+	}, report.Missing, "URLs are not local references")
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/benchmark/fixture"
+)
+
+func BenchmarkAnalyzeDirectory(b *testing.B) {
+	for _, size := range fixture.Sizes {
+		b.Run(string(size), func(b *testing.B) {
+			dir := b.TempDir()
+			if _, err := fixture.Generate(dir, size); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(graph.Files) == 0 {
+					b.Fatal("no files analyzed")
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkExcludeFixturePaths(b *testing.B) {
+	var paths []string
+	for path := range fixture.Files(fixture.Medium) {
+		paths = append(paths, filepath.Join("/repo", path), filepath.Join("/repo/node_modules", path))
+	}
+	sort.Strings(paths)
+	builder := NewGraphBuilder()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, path := range paths {
+			builder.Excluded(path)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipBenchmarks links a benchmark to a function it exercises
+const RelationshipBenchmarks RelationshipType = "benchmarks"
+
+// Benchmark frameworks
+const (
+	BenchmarkGo        = "go"        // func BenchmarkXxx(b *testing.B)
+	BenchmarkCriterion = "criterion" // Rust functions taking &mut Criterion
+	BenchmarkLibtest   = "libtest"   // Rust #[bench] functions
+	BenchmarkDivan     = "divan"     // Rust #[divan::bench] functions
+	BenchmarkJMH       = "jmh"       // Java and Kotlin @Benchmark methods
+)
+
+var (
+	// Go benchmarks: Benchmark followed by anything but a lower-case letter
+	goBenchmarkPattern = regexp.MustCompile(`^Benchmark($|[^a-z])`)
+	// Attribute and annotation lines marking a benchmark
+	libtestBenchPattern = regexp.MustCompile(`^#\[bench\]`)
+	divanBenchPattern   = regexp.MustCompile(`^#\[divan::bench\b`)
+	jmhBenchPattern     = regexp.MustCompile(`^@(?:org\.openjdk\.jmh\.annotations\.)?Benchmark\b`)
+)
+
+// benchmarkLanguages are the languages benchmark detection looks at
+var benchmarkLanguages = map[string]bool{"go": true, "rust": true, "java": true, "kotlin": true}
+
+// Benchmark is a benchmark function and the code it exercises
+type Benchmark struct {
+	Name      string            `json:"name"`
+	File      string            `json:"file"`
+	Line      int               `json:"line"`
+	Framework string            `json:"framework"`
+	Targets   []BenchmarkTarget `json:"targets"` // Functions called from the benchmark body
+	symbol    types.SymbolId
+}
+
+// BenchmarkTarget is a function a benchmark calls
+type BenchmarkTarget struct {
+	Name string         `json:"name"`
+	File string         `json:"file"`
+	Line int            `json:"line"`
+	Id   types.SymbolId `json:"id"`
+}
+
+// FindBenchmarks detects Go, Rust (criterion, #[bench], divan) and JMH
+// benchmarks and links each to the functions its body calls. Calls are
+// matched to functions of the same language by name, preferring those in the
+// benchmark's own directory, so targets are a hint of what a benchmark
+// measures rather than a proof.
+func FindBenchmarks(graph *types.CodeGraph) []Benchmark {
+	var benchmarks []Benchmark
+	benchmarkFiles := make(map[string]bool)
+	for path, file := range graph.Files {
+		if !benchmarkLanguages[file.Language] {
+			continue
+		}
+		if file.Language == "go" && !strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+		for _, span := range symbolSpans(graph, file) {
+			if !span.function {
+				continue
+			}
+			framework := benchmarkFramework(file.Language, span.symbol, lines)
+			if framework == "" {
+				continue
+			}
+			benchmark := Benchmark{
+				Name:      span.symbol.Name,
+				File:      path,
+				Line:      span.symbol.Location.StartLine,
+				Framework: framework,
+				symbol:    span.symbol.Id,
+			}
+			var calls []string
+			for line := span.start + 1; line <= span.end && line <= len(lines); line++ {
+				for _, m := range callSitePattern.FindAllStringSubmatch(lines[line-1], -1) {
+					if m[1] != benchmark.Name {
+						calls = appendUnique(calls, m[1])
+					}
+				}
+			}
+			benchmark.Targets = benchmarkCalls(calls)
+			benchmarks = append(benchmarks, benchmark)
+			benchmarkFiles[path] = true
+		}
+	}
+	if len(benchmarks) == 0 {
+		return nil
+	}
+
+	// Benchmarks exercise the functions of non-test, non-benchmark files
+	functions := make(map[string][]BenchmarkTarget)
+	for path, file := range graph.Files {
+		if file.IsTest || benchmarkFiles[path] {
+			continue
+		}
+		for _, id := range file.Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil || (symbol.Type != types.SymbolTypeFunction && symbol.Type != types.SymbolTypeMethod) {
+				continue
+			}
+			key := file.Language + "\x00" + symbol.Name
+			functions[key] = append(functions[key], BenchmarkTarget{
+				Name: symbol.Name,
+				File: path,
+				Line: symbol.Location.StartLine,
+				Id:   symbol.Id,
+			})
+		}
+	}
+
+	for i := range benchmarks {
+		benchmark := &benchmarks[i]
+		language := graph.Files[benchmark.File].Language
+		dir := filepath.Dir(benchmark.File)
+		var targets []BenchmarkTarget
+		for _, call := range benchmark.Targets {
+			candidates := functions[language+"\x00"+call.Name]
+			var local []BenchmarkTarget
+			for _, candidate := range candidates {
+				if filepath.Dir(candidate.File) == dir {
+					local = append(local, candidate)
+				}
+			}
+			if len(local) > 0 {
+				candidates = local
+			}
+			targets = append(targets, candidates...)
+		}
+		sort.Slice(targets, func(a, b int) bool {
+			if targets[a].File != targets[b].File {
+				return targets[a].File < targets[b].File
+			}
+			return targets[a].Line < targets[b].Line
+		})
+		benchmark.Targets = targets
+	}
+
+	sort.Slice(benchmarks, func(i, j int) bool {
+		if benchmarks[i].File != benchmarks[j].File {
+			return benchmarks[i].File < benchmarks[j].File
+		}
+		return benchmarks[i].Line < benchmarks[j].Line
+	})
+	return benchmarks
+}
+
+// benchmarkCalls wraps called names as unresolved targets
+func benchmarkCalls(calls []string) []BenchmarkTarget {
+	targets := make([]BenchmarkTarget, len(calls))
+	for i, call := range calls {
+		targets[i] = BenchmarkTarget{Name: call}
+	}
+	return targets
+}
+
+// benchmarkFramework returns the framework a function is a benchmark of, or
+// "" when it is not a benchmark
+func benchmarkFramework(language string, symbol *types.Symbol, lines []string) string {
+	switch language {
+	case "go":
+		if goBenchmarkPattern.MatchString(symbol.Name) && strings.Contains(symbol.Signature, "testing.B") {
+			return BenchmarkGo
+		}
+	case "rust":
+		for _, attribute := range declarationMarkers(lines, symbol.Location.StartLine, "#[") {
+			switch {
+			case libtestBenchPattern.MatchString(attribute):
+				return BenchmarkLibtest
+			case divanBenchPattern.MatchString(attribute):
+				return BenchmarkDivan
+			}
+		}
+		if strings.Contains(symbol.Signature, "Criterion") {
+			return BenchmarkCriterion
+		}
+	case "java", "kotlin":
+		for _, annotation := range declarationMarkers(lines, symbol.Location.StartLine, "@") {
+			if jmhBenchPattern.MatchString(annotation) {
+				return BenchmarkJMH
+			}
+		}
+	}
+	return ""
+}
+
+// declarationMarkers returns the attribute or annotation lines (starting with
+// prefix) around a declaration: those directly above it and, for parsers
+// whose symbols start at the first annotation, those from its start line on
+func declarationMarkers(lines []string, line int, prefix string) []string {
+	var markers []string
+	for i := line - 1; i >= 1 && i <= len(lines); i-- {
+		trimmed := strings.TrimSpace(lines[i-1])
+		if strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		if !strings.HasPrefix(trimmed, prefix) {
+			break
+		}
+		markers = append(markers, trimmed)
+	}
+	for i := line; i >= 1 && i <= len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i-1])
+		if !strings.HasPrefix(trimmed, prefix) {
+			break
+		}
+		markers = append(markers, trimmed)
+	}
+	return markers
+}
+
+// analyzeBenchmarks links benchmarks to the functions they exercise
+func (ra *RelationshipAnalyzer) analyzeBenchmarks(metrics *RelationshipMetrics) {
+	for _, benchmark := range FindBenchmarks(ra.graph) {
+		from := types.NodeId(fmt.Sprintf("symbol-%s", benchmark.symbol))
+		for _, target := range benchmark.Targets {
+			edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", RelationshipBenchmarks, benchmark.symbol, target.Id))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   from,
+				To:     types.NodeId(fmt.Sprintf("symbol-%s", target.Id)),
+				Type:   string(RelationshipBenchmarks),
+				Weight: 1.0,
+				Metadata: map[string]interface{}{
+					"framework": benchmark.Framework,
+				},
+			}
+			metrics.ByType[RelationshipBenchmarks]++
+		}
+	}
+}
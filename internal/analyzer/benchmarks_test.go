@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindBenchmarks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":             "module example.com/shop\n\ngo 1.22\n",
+		"cart/cart.go":       "package cart\n\nfunc Total(items []int) int {\n\treturn len(items)\n}\n\nfunc Discount(total int) int {\n\treturn total / 10\n}\n",
+		"other/other.go":     "package other\n\nfunc Total() int {\n\treturn 0\n}\n",
+		"cart/cart_test.go":  "package cart\n\nimport \"testing\"\n\nfunc TestTotal(t *testing.T) {\n\tTotal(nil)\n}\n\nfunc BenchmarkTotal(b *testing.B) {\n\tfor i := 0; i < b.N; i++ {\n\t\tDiscount(Total(nil))\n\t}\n}\n\nfunc Benchmarker(b *testing.B) {}\n",
+		"src/lib.rs":         "pub fn parse(input: &str) -> usize {\n    input.len()\n}\n",
+		"benches/parse.rs":   "use criterion::Criterion;\n\nfn bench_parse(c: &mut Criterion) {\n    c.bench_function(\"parse\", |b| b.iter(|| parse(\"x\")));\n}\n\n#[bench]\nfn bench_old(b: &mut Bencher) {\n    b.iter(|| parse(\"y\"));\n}\n\nfn helper() {}\n",
+		"jmh/CartBench.java": "package shop;\n\npublic class CartBench {\n    @Benchmark\n    @BenchmarkMode(Mode.Throughput)\n    public int total() {\n        return Cart.sum(new int[0]);\n    }\n\n    public void setup() {}\n}\n",
+		"src/Cart.java":      "package shop;\n\npublic class Cart {\n    public static int sum(int[] xs) {\n        return xs.length;\n    }\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	found := make(map[string]Benchmark)
+	for _, benchmark := range FindBenchmarks(graph) {
+		found[benchmark.Name] = benchmark
+	}
+	require.Len(t, found, 4, "Benchmarker, helpers and tests are not benchmarks")
+
+	targets := func(name string) []string {
+		var names []string
+		for _, target := range found[name].Targets {
+			rel, _ := filepath.Rel(dir, target.File)
+			names = append(names, target.Name+" "+filepath.ToSlash(rel))
+		}
+		return names
+	}
+	assert.Equal(t, BenchmarkGo, found["BenchmarkTotal"].Framework)
+	assert.Equal(t, []string{"Total cart/cart.go", "Discount cart/cart.go"}, targets("BenchmarkTotal"), "functions in the benchmark's package win")
+	assert.Equal(t, BenchmarkCriterion, found["bench_parse"].Framework)
+	assert.Equal(t, []string{"parse src/lib.rs"}, targets("bench_parse"))
+	assert.Equal(t, BenchmarkLibtest, found["bench_old"].Framework)
+	assert.Equal(t, BenchmarkJMH, found["total"].Framework)
+	assert.Equal(t, []string{"sum src/Cart.java"}, targets("total"))
+
+	benchmarked := 0
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipBenchmarks) {
+			benchmarked++
+		}
+	}
+	assert.Equal(t, 5, benchmarked, "one edge per benchmark and target")
+}
@@ -0,0 +1,300 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// DefaultChunkMaxLines is the longest chunk before it is split
+const DefaultChunkMaxLines = 200
+
+// ChunkKindModule marks the code of a file outside its symbols, such as
+// imports and top-level statements
+const ChunkKindModule = "module"
+
+// Chunk is a piece of a source file sized for retrieval: a top-level symbol
+// with its leading comments and decorators, or the code between symbols
+type Chunk struct {
+	ID        string   `json:"id"`   // file:start-end
+	File      string   `json:"file"` // Relative to the analyzed directory
+	Language  string   `json:"language"`
+	StartLine int      `json:"start_line"`
+	EndLine   int      `json:"end_line"`
+	Kind      string   `json:"kind"` // Symbol type, or module
+	Symbol    string   `json:"symbol,omitempty"`
+	FQN       string   `json:"fqn,omitempty"`
+	Signature string   `json:"signature,omitempty"`
+	Part      int      `json:"part,omitempty"`    // 1-based part of a symbol longer than the maximum, else 0
+	Parts     int      `json:"parts,omitempty"`   // Number of parts of a split symbol
+	Symbols   []string `json:"symbols,omitempty"` // Nested symbols declared in the chunk, such as methods
+	Summary   string   `json:"summary,omitempty"` // Summary of the file, when summaries are configured
+	Content   string   `json:"content"`
+}
+
+// Chunker splits the source files of an analyzed graph into retrieval chunks
+// along symbol boundaries, for feeding vector stores
+type Chunker struct {
+	graph     *types.CodeGraph
+	targetDir string
+	maxLines  int
+	redactor  *redact.Redactor
+}
+
+// NewChunker creates a chunker for a graph analyzed from targetDir
+func NewChunker(graph *types.CodeGraph, targetDir string) *Chunker {
+	return &Chunker{graph: graph, targetDir: targetDir, maxLines: DefaultChunkMaxLines}
+}
+
+// SetMaxLines sets the longest chunk; longer symbols are split at their
+// nested symbols, or else every n lines. n <= 0 keeps the default.
+func (c *Chunker) SetMaxLines(n int) {
+	if n > 0 {
+		c.maxLines = n
+	}
+}
+
+// SetRedactor masks secrets in chunk content and leaves out sensitive files
+func (c *Chunker) SetRedactor(r *redact.Redactor) {
+	c.redactor = r
+}
+
+// WriteJSONL writes one chunk per line, file by file in path order
+func (c *Chunker) WriteJSONL(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, path := range c.paths() {
+		for _, chunk := range c.fileChunks(path) {
+			if err := encoder.Encode(chunk); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Chunks returns the chunks of every file, in path and line order
+func (c *Chunker) Chunks() []Chunk {
+	var chunks []Chunk
+	for _, path := range c.paths() {
+		chunks = append(chunks, c.fileChunks(path)...)
+	}
+	return chunks
+}
+
+// paths lists the files to chunk; generated and sensitive files are left out
+func (c *Chunker) paths() []string {
+	var paths []string
+	for path, file := range c.graph.Files {
+		if file.IsGenerated || c.redactor.SensitivePath(projectPath(c.targetDir, path)) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// chunkSpan is a range of lines, 1-based and inclusive, with the top-level
+// symbol it holds and the symbols nested in that one
+type chunkSpan struct {
+	start, end int
+	symbol     *types.Symbol
+	nested     []*types.Symbol
+}
+
+// fileChunks splits one file into chunks
+func (c *Chunker) fileChunks(path string) []Chunk {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	file := c.graph.Files[path]
+
+	// Declarations start chunks; imports, variables and the like stay in the
+	// code around them. Of several declarations on a line the first ranked
+	// owns the chunk, so noise such as a namespace symbol for an exported
+	// class does not.
+	var symbols []*types.Symbol
+	for _, id := range file.Symbols {
+		symbol := c.graph.Symbols[id]
+		if symbol == nil || symbol.Location.StartLine < 1 || symbol.Location.StartLine > len(lines) {
+			continue
+		}
+		if _, ok := chunkKindRank[symbol.NormalizedKind()]; ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		a, b := symbols[i], symbols[j]
+		if a.Location.StartLine != b.Location.StartLine {
+			return a.Location.StartLine < b.Location.StartLine
+		}
+		return chunkRank(a) < chunkRank(b)
+	})
+
+	// Symbols inside a declaration with a known range are listed in its chunk;
+	// others on its line are dropped
+	var spans []chunkSpan
+	for _, symbol := range symbols {
+		if last := len(spans) - 1; last >= 0 && symbol.Location.StartLine <= max(spans[last].start, spans[last].end) {
+			if symbol.Location.StartLine > spans[last].start {
+				spans[last].nested = append(spans[last].nested, symbol)
+			}
+			continue
+		}
+		span := chunkSpan{start: symbol.Location.StartLine, symbol: symbol}
+		// Most parsers only record the declaration line; the end is found below
+		if symbol.Location.EndLine > symbol.Location.StartLine {
+			span.end = min(symbol.Location.EndLine, len(lines))
+		}
+		spans = append(spans, span)
+	}
+	floor := 1
+	for i := range spans {
+		start := spans[i].start
+		spans[i].start = leadingComments(lines, start, floor)
+		floor = max(start, spans[i].end) + 1
+	}
+	for i := range spans {
+		if spans[i].end > 0 {
+			continue
+		}
+		end := len(lines)
+		if i+1 < len(spans) {
+			end = spans[i+1].start - 1
+		}
+		spans[i].end = end
+	}
+
+	// The code between declarations becomes module chunks
+	var all []chunkSpan
+	next := 1
+	for _, span := range spans {
+		if span.start > next {
+			all = append(all, chunkSpan{start: next, end: span.start - 1})
+		}
+		all = append(all, span)
+		next = span.end + 1
+	}
+	if next <= len(lines) {
+		all = append(all, chunkSpan{start: next, end: len(lines)})
+	}
+
+	rel := projectPath(c.targetDir, path)
+	var chunks []Chunk
+	for _, span := range all {
+		for span.end >= span.start && strings.TrimSpace(lines[span.end-1]) == "" {
+			span.end--
+		}
+		if span.end < span.start {
+			continue
+		}
+		parts := c.split(span)
+		for p, part := range parts {
+			chunk := Chunk{
+				ID:        fmt.Sprintf("%s:%d-%d", rel, part.start, part.end),
+				File:      rel,
+				Language:  file.Language,
+				StartLine: part.start,
+				EndLine:   part.end,
+				Kind:      ChunkKindModule,
+				Summary:   file.Summary,
+				Content:   c.redactor.Text(strings.Join(lines[part.start-1:part.end], "\n")),
+			}
+			if symbol := span.symbol; symbol != nil {
+				chunk.Kind, chunk.Symbol, chunk.FQN, chunk.Signature = string(symbol.Type), symbol.Name, symbol.FullyQualifiedName, c.redactor.Text(symbol.Signature)
+			}
+			if len(parts) > 1 {
+				chunk.Part, chunk.Parts = p+1, len(parts)
+			}
+			for _, symbol := range span.nested {
+				if symbol.Location.StartLine >= part.start && symbol.Location.StartLine <= part.end {
+					chunk.Symbols = append(chunk.Symbols, symbol.Name)
+				}
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// split cuts a span longer than the maximum into parts, preferring the lines
+// where nested symbols start
+func (c *Chunker) split(span chunkSpan) []chunkSpan {
+	if span.end-span.start+1 <= c.maxLines {
+		return []chunkSpan{span}
+	}
+	cuts := make(map[int]bool)
+	for _, symbol := range span.nested {
+		cuts[symbol.Location.StartLine] = true
+	}
+
+	var parts []chunkSpan
+	start, lastCut := span.start, 0
+	for line := span.start + 1; line <= span.end; line++ {
+		if cuts[line] {
+			lastCut = line
+		}
+		if line-start+1 <= c.maxLines {
+			continue
+		}
+		// The part is full: end it before the last nested symbol, else here
+		end := line - 1
+		if lastCut > start {
+			end = lastCut - 1
+		}
+		parts = append(parts, chunkSpan{start: start, end: end, symbol: span.symbol})
+		start, lastCut = end+1, 0
+	}
+	return append(parts, chunkSpan{start: start, end: span.end, symbol: span.symbol})
+}
+
+// chunkKindRank lists the normalized kinds that start a chunk, the kind
+// that owns a line first
+var chunkKindRank = map[string]int{
+	types.SymbolKindClass: 0, types.SymbolKindInterface: 1, types.SymbolKindEnum: 2,
+	types.SymbolKindComponent: 3, types.SymbolKindFunction: 4, types.SymbolKindMethod: 5,
+	types.SymbolKindTypeAlias: 6, types.SymbolKindMacro: 7, types.SymbolKindNamespace: 8,
+}
+
+// chunkRank orders declarations on the same line. Symbols named after their
+// type, such as a "class" symbol, are parser noise and come last.
+func chunkRank(symbol *types.Symbol) int {
+	rank := chunkKindRank[symbol.NormalizedKind()]
+	if symbol.Name == string(symbol.Type) {
+		rank += len(chunkKindRank)
+	}
+	return rank
+}
+
+// leadingComments moves a symbol's first line up over the comment and
+// decorator lines directly above it, without going above floor
+func leadingComments(lines []string, start, floor int) int {
+	for start > floor {
+		line := strings.TrimSpace(lines[start-2])
+		if line == "" || !isCommentOrDecorator(line) {
+			break
+		}
+		start--
+	}
+	return start
+}
+
+// isCommentOrDecorator reports whether a trimmed line is a comment or an
+// annotation in one of the supported languages
+func isCommentOrDecorator(line string) bool {
+	for _, prefix := range []string{"//", "#", "/*", "*", "@", "--", ";;"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
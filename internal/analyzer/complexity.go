@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// decisionPattern matches the branch points counted by cyclomatic complexity:
+// conditionals, loops, switch cases, exception handlers and short-circuit
+// boolean operators
+var decisionPattern = regexp.MustCompile(`\b(?:if|elif|for|while|case|catch|except)\b|&&|\|\|`)
+
+// FunctionComplexity is the estimated cyclomatic complexity of a function
+type FunctionComplexity struct {
+	Function   string `json:"function"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Complexity int    `json:"complexity"` // 1 plus the branch points in the body
+}
+
+// AnalyzeComplexity estimates the cyclomatic complexity of every function and
+// method by counting branch points on the lines of its body, most complex
+// first. Comment lines are skipped; keywords inside strings are not, so the
+// numbers are an estimate meant for ranking and trends.
+func AnalyzeComplexity(graph *types.CodeGraph) []FunctionComplexity {
+	ra := &RelationshipAnalyzer{graph: graph}
+	var result []FunctionComplexity
+	ra.forEachSourceFile(func(filePath, content string) {
+		commentPrefix := "//"
+		if graph.Files[filePath].Language == "python" {
+			commentPrefix = "#"
+		}
+		functions := make(map[types.SymbolId]*FunctionComplexity)
+		var order []types.SymbolId
+		for i, line := range strings.Split(content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, commentPrefix) || strings.HasPrefix(trimmed, "*") {
+				continue
+			}
+			symbol := ra.enclosingSymbol(filePath, i+1)
+			if symbol == nil {
+				continue
+			}
+			fn := functions[symbol.Id]
+			if fn == nil {
+				fn = &FunctionComplexity{Function: symbol.Name, File: filePath, Line: symbol.Location.StartLine, Complexity: 1}
+				functions[symbol.Id] = fn
+				order = append(order, symbol.Id)
+			}
+			fn.Complexity += len(decisionPattern.FindAllString(line, -1))
+		}
+		for _, id := range order {
+			result = append(result, *functions[id])
+		}
+	})
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Complexity != result[j].Complexity {
+			return result[i].Complexity > result[j].Complexity
+		}
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result
+}
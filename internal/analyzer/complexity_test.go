@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeComplexity(t *testing.T) {
+	dir := t.TempDir()
+	source := `package orders
+
+// Validate checks an order
+func Validate(items []string, paid bool) error {
+	// if it is empty or unpaid, for example, it fails
+	if len(items) == 0 || !paid {
+		return errEmpty
+	}
+	for _, item := range items {
+		switch item {
+		case "":
+			return errEmpty
+		case "gift":
+			continue
+		}
+	}
+	return nil
+}
+
+func Total() int {
+	return 0
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "orders.go"), []byte(source), 0644))
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	functions := AnalyzeComplexity(graph)
+	require.Len(t, functions, 2)
+	assert.Equal(t, "Validate", functions[0].Function)
+	assert.Equal(t, 4, functions[0].Line)
+	assert.Equal(t, 6, functions[0].Complexity, "if, ||, for and two cases; the comment is skipped")
+	assert.Equal(t, FunctionComplexity{Function: "Total", File: filepath.Join(dir, "orders.go"), Line: 20, Complexity: 1}, functions[1])
+}
@@ -0,0 +1,168 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Kinds of concurrency constructs
+const (
+	ConcurrencySpawn   = "spawn"   // Starts concurrent work: goroutines, threads, isolates, executors, tasks
+	ConcurrencyAsync   = "async"   // async functions, await and coroutines
+	ConcurrencyChannel = "channel" // Message passing: channels, queues, ports
+	ConcurrencyLock    = "lock"    // Mutual exclusion: mutexes, synchronized blocks
+	ConcurrencyAtomic  = "atomic"  // Atomic and volatile variables
+	ConcurrencySync    = "sync"    // Other coordination: wait groups, latches, semaphores, futures
+)
+
+// concurrencyLanguages are the languages scanned for concurrency constructs
+var concurrencyLanguages = map[string]bool{
+	"go": true, "javascript": true, "typescript": true, "python": true, "java": true, "kotlin": true,
+	"dart": true, "cpp": true, "rust": true, "swift": true,
+}
+
+// concurrencyPattern recognizes one construct in a set of languages
+type concurrencyPattern struct {
+	kind      string
+	construct string
+	languages []string
+	pattern   *regexp.Regexp
+}
+
+var concurrencyPatterns = []concurrencyPattern{
+	// Go
+	{ConcurrencySpawn, "goroutine", []string{"go"}, regexp.MustCompile(`(?:^|[\s;{])go\s+(?:func\b|[\w.]+(?:\[[^\]]*\])?\()`)},
+	{ConcurrencyChannel, "chan", []string{"go"}, regexp.MustCompile(`\bchan\b|<-`)},
+	{ConcurrencyChannel, "select", []string{"go"}, regexp.MustCompile(`^\s*select\s*\{`)},
+	{ConcurrencyLock, "sync.Mutex", []string{"go"}, regexp.MustCompile(`\bsync\.(?:RW)?Mutex\b`)},
+	{ConcurrencySync, "sync.WaitGroup", []string{"go"}, regexp.MustCompile(`\bsync\.(?:WaitGroup|Once|Cond)\b|\berrgroup\.`)},
+	{ConcurrencyAtomic, "sync/atomic", []string{"go"}, regexp.MustCompile(`\batomic\.(?:Add|Load|Store|Swap|CompareAndSwap|Int32|Int64|Uint32|Uint64|Bool|Value|Pointer)\w*`)},
+
+	// JavaScript and TypeScript
+	{ConcurrencyAsync, "async", []string{"javascript", "typescript", "python", "dart", "swift"}, regexp.MustCompile(`\basync\s+(?:def|function|func)?\b|\basync\s*\(|\basync\s+\w+\s*=>|\)\s*async\s*(?:\{|=>)|\bawait\b`)},
+	{ConcurrencySpawn, "Worker", []string{"javascript", "typescript"}, regexp.MustCompile(`\bnew\s+(?:Worker|SharedWorker)\(`)},
+	{ConcurrencySync, "Promise.all", []string{"javascript", "typescript"}, regexp.MustCompile(`\bPromise\.(?:all|allSettled|race|any)\(`)},
+	{ConcurrencyAtomic, "Atomics", []string{"javascript", "typescript"}, regexp.MustCompile(`\bAtomics\.\w+|\bSharedArrayBuffer\b`)},
+
+	// Python
+	{ConcurrencySpawn, "thread", []string{"python"}, regexp.MustCompile(`\b(?:threading\.)?Thread\(|\bmultiprocessing\.Process\(|\b(?:Thread|Process)PoolExecutor\b|\basyncio\.(?:create_task|gather|to_thread)\(`)},
+	{ConcurrencyLock, "threading.Lock", []string{"python"}, regexp.MustCompile(`\b(?:threading|asyncio|multiprocessing)\.R?Lock\(`)},
+	{ConcurrencySync, "threading.Event", []string{"python"}, regexp.MustCompile(`\b(?:threading|asyncio)\.(?:Event|Semaphore|BoundedSemaphore|Condition|Barrier)\(`)},
+	{ConcurrencyChannel, "queue", []string{"python"}, regexp.MustCompile(`\b(?:queue|asyncio|multiprocessing)\.(?:Queue|LifoQueue|PriorityQueue)\(`)},
+
+	// Java and Kotlin
+	{ConcurrencySpawn, "thread", []string{"java", "kotlin"}, regexp.MustCompile(`\bnew\s+Thread\(|\bThread\s*\{|\bExecutors\.\w+\(|\bCompletableFuture\.(?:runAsync|supplyAsync)\(|\.(?:submit|execute)\(`)},
+	{ConcurrencySpawn, "coroutine", []string{"kotlin"}, regexp.MustCompile(`\b(?:launch|async|runBlocking)\s*(?:\([^)]*\))?\s*\{`)},
+	{ConcurrencyAsync, "suspend", []string{"kotlin"}, regexp.MustCompile(`\bsuspend\s+fun\b`)},
+	{ConcurrencyLock, "synchronized", []string{"java", "kotlin"}, regexp.MustCompile(`\bsynchronized\b|@Synchronized\b|\b(?:Reentrant(?:ReadWrite)?Lock|StampedLock)\b|\bMutex\(\)`)},
+	{ConcurrencyAtomic, "atomic", []string{"java", "kotlin"}, regexp.MustCompile(`\bAtomic(?:Integer|Long|Boolean|Reference)\b|\bvolatile\b|@Volatile\b`)},
+	{ConcurrencySync, "latch", []string{"java", "kotlin"}, regexp.MustCompile(`\b(?:CountDownLatch|CyclicBarrier|Semaphore|Phaser|ConcurrentHashMap)\b`)},
+	{ConcurrencyChannel, "queue", []string{"java", "kotlin"}, regexp.MustCompile(`\b\w*BlockingQueue\b|\bChannel<|\bChannel\(`)},
+
+	// Dart
+	{ConcurrencySpawn, "Isolate", []string{"dart"}, regexp.MustCompile(`\bIsolate\.(?:spawn|spawnUri|run)\(|\bcompute\(`)},
+	{ConcurrencyChannel, "port", []string{"dart"}, regexp.MustCompile(`\b(?:ReceivePort|SendPort|StreamController)\b`)},
+	{ConcurrencySync, "Completer", []string{"dart"}, regexp.MustCompile(`\bCompleter\b|\bFuture\.wait\(`)},
+
+	// C++
+	{ConcurrencySpawn, "std::thread", []string{"cpp"}, regexp.MustCompile(`\bstd::(?:thread|jthread|async)\b|\bpthread_create\(`)},
+	{ConcurrencyLock, "std::mutex", []string{"cpp"}, regexp.MustCompile(`\bstd::(?:mutex|shared_mutex|recursive_mutex|timed_mutex|lock_guard|unique_lock|scoped_lock|shared_lock)\b|\bpthread_mutex_\w+`)},
+	{ConcurrencyAtomic, "std::atomic", []string{"cpp"}, regexp.MustCompile(`\bstd::atomic\w*`)},
+	{ConcurrencySync, "std::condition_variable", []string{"cpp"}, regexp.MustCompile(`\bstd::(?:condition_variable|promise|future|shared_future|latch|barrier|counting_semaphore|binary_semaphore)\b`)},
+
+	// Rust
+	{ConcurrencySpawn, "spawn", []string{"rust"}, regexp.MustCompile(`\b(?:thread|tokio|task|async_std::task)::spawn\w*|\brayon::`)},
+	{ConcurrencyAsync, "async", []string{"rust"}, regexp.MustCompile(`\basync\s+(?:fn|move|\{)|\.await\b`)},
+	{ConcurrencyLock, "Mutex", []string{"rust"}, regexp.MustCompile(`\b(?:Mutex|RwLock)(?:<|::new)`)},
+	{ConcurrencyAtomic, "atomic", []string{"rust"}, regexp.MustCompile(`\bAtomic(?:Bool|Usize|Isize|[IU](?:8|16|32|64)|Ptr)\b`)},
+	{ConcurrencyChannel, "channel", []string{"rust"}, regexp.MustCompile(`\b(?:mpsc|broadcast|oneshot|watch|crossbeam_channel)::`)},
+
+	// Swift
+	{ConcurrencySpawn, "Task", []string{"swift"}, regexp.MustCompile(`\bTask(?:\.detached)?\s*(?:\([^)]*\))?\s*\{|\bDispatchQueue\b|\bOperationQueue\b`)},
+	{ConcurrencyLock, "lock", []string{"swift"}, regexp.MustCompile(`\b(?:NSLock|NSRecursiveLock|os_unfair_lock)\b|^\s*(?:\w+\s+)*actor\s+\w+`)},
+}
+
+// ConcurrencyUsage is one concurrency construct in a source file
+type ConcurrencyUsage struct {
+	Kind      string `json:"kind"`
+	Construct string `json:"construct"`
+	Line      int    `json:"line"`
+	Symbol    string `json:"symbol,omitempty"` // Enclosing function or method
+}
+
+// ConcurrencyFile lists the concurrency constructs of a file
+type ConcurrencyFile struct {
+	File     string             `json:"file"`
+	Language string             `json:"language"`
+	Usages   []ConcurrencyUsage `json:"usages"`
+}
+
+// Kinds returns the kinds of constructs the file uses, sorted
+func (f *ConcurrencyFile) Kinds() []string {
+	var kinds []string
+	for _, usage := range f.Usages {
+		kinds = appendUnique(kinds, usage.Kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Constructs counts the usages of each construct in the file
+func (f *ConcurrencyFile) Constructs() map[string]int {
+	counts := make(map[string]int)
+	for _, usage := range f.Usages {
+		counts[usage.Construct]++
+	}
+	return counts
+}
+
+// ConcurrencyMap indexes the goroutines, threads, isolates, async code,
+// channels, locks and atomics of each source file. Files come first when they
+// mix more kinds of constructs, since spawning work next to shared locks or
+// atomics is what needs the most careful review.
+func ConcurrencyMap(graph *types.CodeGraph) []ConcurrencyFile {
+	ra := &RelationshipAnalyzer{graph: graph}
+	var files []ConcurrencyFile
+	ra.forEachSourceFileIn(concurrencyLanguages, func(filePath, content string) {
+		language := graph.Files[filePath].Language
+		file := ConcurrencyFile{File: filePath, Language: language}
+		commentPrefix := "//"
+		if language == "python" {
+			commentPrefix = "#"
+		}
+		for i, line := range strings.Split(content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, commentPrefix) || strings.HasPrefix(trimmed, "*") {
+				continue
+			}
+			for _, pattern := range concurrencyPatterns {
+				if !slices.Contains(pattern.languages, language) || !pattern.pattern.MatchString(line) {
+					continue
+				}
+				usage := ConcurrencyUsage{Kind: pattern.kind, Construct: pattern.construct, Line: i + 1}
+				if symbol := ra.enclosingSymbol(filePath, i+1); symbol != nil {
+					usage.Symbol = symbol.Name
+				}
+				file.Usages = append(file.Usages, usage)
+			}
+		}
+		if len(file.Usages) > 0 {
+			files = append(files, file)
+		}
+	})
+	sort.Slice(files, func(i, j int) bool {
+		ki, kj := len(files[i].Kinds()), len(files[j].Kinds())
+		if ki != kj {
+			return ki > kj
+		}
+		if len(files[i].Usages) != len(files[j].Usages) {
+			return len(files[i].Usages) > len(files[j].Usages)
+		}
+		return files[i].File < files[j].File
+	})
+	return files
+}
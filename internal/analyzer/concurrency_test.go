@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyMap(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pool.go": `package pool
+
+import "sync"
+
+type Pool struct {
+	mu   sync.Mutex
+	jobs chan Job
+}
+
+// go run() is started per worker
+func (p *Pool) Start(n int) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go p.run(&wg)
+	}
+	wg.Wait()
+}
+
+func (p *Pool) run(wg *sync.WaitGroup) {
+	defer wg.Done()
+	for job := range p.jobs {
+		p.mu.Lock()
+		job.Do()
+		p.mu.Unlock()
+	}
+}
+`,
+		"api.ts": `export async function loadAll(ids: string[]) {
+  return Promise.all(ids.map((id) => fetchOne(id)));
+}
+
+async function fetchOne(id: string) {
+  const res = await fetch('/items/' + id);
+  return res.json();
+}
+`,
+		"plain.go": "package pool\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	concurrency := ConcurrencyMap(graph)
+	require.Len(t, concurrency, 2, "files without concurrency are left out")
+
+	pool := concurrency[0]
+	assert.Equal(t, filepath.Join(dir, "pool.go"), pool.File)
+	assert.Equal(t, []string{ConcurrencyChannel, ConcurrencyLock, ConcurrencySpawn, ConcurrencySync}, pool.Kinds())
+	assert.Equal(t, map[string]int{"sync.Mutex": 1, "chan": 1, "sync.WaitGroup": 2, "goroutine": 1}, pool.Constructs())
+	for _, usage := range pool.Usages {
+		if usage.Construct == "goroutine" {
+			assert.Equal(t, ConcurrencyUsage{Kind: ConcurrencySpawn, Construct: "goroutine", Line: 15, Symbol: "Start"}, usage)
+		}
+	}
+
+	api := concurrency[1]
+	assert.Equal(t, []string{ConcurrencyAsync, ConcurrencySync}, api.Kinds())
+	assert.Equal(t, map[string]int{"async": 3, "Promise.all": 1}, api.Constructs())
+}
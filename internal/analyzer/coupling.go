@@ -0,0 +1,225 @@
+package analyzer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// PackageCoupling holds the coupling metrics of a package (a directory of
+// source files), after Robert C. Martin's package metrics
+type PackageCoupling struct {
+	Package      string   `json:"package"` // Directory relative to the project root, "." for the root
+	Files        int      `json:"files"`
+	Afferent     int      `json:"afferent"`     // Ca: packages that depend on this one (fan-in)
+	Efferent     int      `json:"efferent"`     // Ce: packages this one depends on (fan-out)
+	Instability  float64  `json:"instability"`  // Ce / (Ca + Ce): 0 is maximally stable, 1 maximally unstable
+	Dependents   []string `json:"dependents"`   // Packages that depend on this one
+	Dependencies []string `json:"dependencies"` // Packages this one depends on
+}
+
+// AnalyzeCoupling computes afferent and efferent coupling and instability
+// for every package of the graph. A package depends on another when one of
+// its files imports a file of the other, through a resolved import edge or an
+// import path naming the other package's directory (Go import paths, Python
+// and Java dotted names). Test files are left out, since tests depending on
+// a package do not make it harder to change. Dart part files count as part
+// of their library.
+func AnalyzeCoupling(graph *types.CodeGraph) []PackageCoupling {
+	var paths []string
+	for path, file := range graph.Files {
+		if !file.IsTest && file.PartOf == "" {
+			paths = append(paths, path)
+		}
+	}
+	root := commonDir(paths)
+	packageOf := func(file string) string {
+		rel, err := filepath.Rel(root, filepath.Dir(file))
+		if err != nil {
+			return filepath.ToSlash(filepath.Dir(file))
+		}
+		return filepath.ToSlash(rel)
+	}
+
+	packages := make(map[string]*PackageCoupling)
+	dependencies := make(map[string]map[string]bool)
+	for _, path := range paths {
+		pkg := packageOf(path)
+		if packages[pkg] == nil {
+			packages[pkg] = &PackageCoupling{Package: pkg}
+			dependencies[pkg] = make(map[string]bool)
+		}
+		packages[pkg].Files++
+	}
+	addDependency := func(from, to string) {
+		if from != to && packages[from] != nil && packages[to] != nil {
+			dependencies[from][to] = true
+		}
+	}
+
+	// Resolved imports
+	for _, edge := range graph.Edges {
+		if edge.Type != string(RelationshipImport) {
+			continue
+		}
+		from := strings.TrimPrefix(string(edge.From), "file-")
+		to := strings.TrimPrefix(string(edge.To), "file-")
+		if from == string(edge.From) || to == string(edge.To) {
+			continue
+		}
+		if file := graph.Files[from]; file != nil && !file.IsTest {
+			addDependency(packageOf(from), packageOf(to))
+		}
+	}
+
+	// Import paths that name a package directory
+	module, moduleDir := findGoModule(root)
+	for _, path := range paths {
+		from := packageOf(path)
+		for _, imp := range graph.Files[path].Imports {
+			importPath := imp.Path
+			if module != "" && graph.Files[path].Language == "go" {
+				// Go imports name packages of this module by module path
+				rest, ok := strings.CutPrefix(strings.Trim(importPath, `"`), module+"/")
+				if !ok {
+					continue
+				}
+				importPath = packageOf(filepath.Join(moduleDir, filepath.FromSlash(rest), "x.go"))
+			}
+			if to := matchImportPackage(importPath, from, packages); to != "" {
+				addDependency(from, to)
+			}
+		}
+	}
+
+	result := make([]PackageCoupling, 0, len(packages))
+	for pkg, coupling := range packages {
+		for dependency := range dependencies[pkg] {
+			coupling.Dependencies = append(coupling.Dependencies, dependency)
+			packages[dependency].Dependents = append(packages[dependency].Dependents, pkg)
+		}
+	}
+	for _, coupling := range packages {
+		sort.Strings(coupling.Dependencies)
+		sort.Strings(coupling.Dependents)
+		coupling.Afferent = len(coupling.Dependents)
+		coupling.Efferent = len(coupling.Dependencies)
+		if total := coupling.Afferent + coupling.Efferent; total > 0 {
+			coupling.Instability = float64(coupling.Efferent) / float64(total)
+		}
+		result = append(result, *coupling)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Package < result[j].Package })
+	return result
+}
+
+// matchImportPackage returns the package an import path refers to: a
+// directory equal to the path, or ending with it when the path has at least
+// two segments. Dotted names become paths, and a trailing module or class
+// name is dropped when only its package matches.
+func matchImportPackage(importPath, from string, packages map[string]*PackageCoupling) string {
+	importPath = strings.Trim(importPath, `"'`)
+	if importPath == "" || strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		return "" // Relative JavaScript imports are resolved to files already
+	}
+
+	// Python relative imports: ".models" is a sibling, "..core" one level up
+	if strings.HasPrefix(importPath, ".") {
+		rest := strings.TrimLeft(importPath, ".")
+		dir := from
+		for i := 1; i < len(importPath)-len(rest); i++ {
+			dir = filepath.ToSlash(filepath.Dir(dir))
+		}
+		importPath = strings.ReplaceAll(rest, ".", "/")
+		if dir != "." {
+			importPath = strings.Trim(dir+"/"+importPath, "/")
+		}
+		if packages[importPath] != nil {
+			return importPath
+		}
+		if parent := filepath.ToSlash(filepath.Dir(importPath)); packages[parent] != nil {
+			return parent
+		}
+		return ""
+	}
+
+	if !strings.Contains(importPath, "/") && strings.Contains(importPath, ".") {
+		importPath = strings.ReplaceAll(strings.TrimSuffix(importPath, ".*"), ".", "/")
+	}
+	candidates := []string{importPath}
+	if i := strings.LastIndex(importPath, "/"); i > 0 {
+		candidates = append(candidates, importPath[:i])
+	}
+	for _, candidate := range candidates {
+		if packages[candidate] != nil {
+			return candidate
+		}
+		if !strings.Contains(candidate, "/") {
+			continue
+		}
+		var matched string
+		for pkg := range packages {
+			if strings.HasSuffix(pkg, "/"+candidate) && (matched == "" || len(pkg) < len(matched)) {
+				matched = pkg
+			}
+		}
+		if matched != "" {
+			return matched
+		}
+	}
+	return ""
+}
+
+// GoModulePath reads the module path from the go.mod in dir
+func GoModulePath(dir string) string {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if module, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(module), `"`)
+		}
+	}
+	return ""
+}
+
+// findGoModule looks for the go.mod in dir or its parents and returns the
+// module path and the directory holding it
+func findGoModule(dir string) (string, string) {
+	for {
+		if module := GoModulePath(dir); module != "" {
+			return module, dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// commonDir returns the deepest directory containing all paths
+func commonDir(paths []string) string {
+	if len(paths) == 0 {
+		return "."
+	}
+	dir := filepath.Dir(paths[0])
+	for _, path := range paths[1:] {
+		for dir != "." && dir != string(filepath.Separator) && !isWithinDir(path, dir) {
+			dir = filepath.Dir(dir)
+		}
+	}
+	return dir
+}
+
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
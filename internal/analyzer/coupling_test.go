@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeCoupling(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                        "module example.com/shop\n\ngo 1.22\n",
+		"cmd/shop/main.go":              "package main\n\nimport (\n\t\"fmt\"\n\t\"example.com/shop/internal/orders\"\n\t\"example.com/shop/internal/store\"\n)\n\nfunc main() { fmt.Println(orders.New(store.Open())) }\n",
+		"internal/orders/order.go":      "package orders\n\nimport \"example.com/shop/internal/store\"\n\nfunc New(s *store.Store) int { return 0 }\n",
+		"internal/orders/order_test.go": "package orders\n\nimport \"example.com/shop/internal/testkit\"\n\nfunc TestNew(t *testing.T) {}\n",
+		"internal/store/store.go":       "package store\n\ntype Store struct{}\n\nfunc Open() *Store { return nil }\n",
+		"internal/testkit/kit.go":       "package testkit\n\nfunc Fixture() {}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	packages := make(map[string]PackageCoupling)
+	for _, pkg := range AnalyzeCoupling(graph) {
+		packages[pkg.Package] = pkg
+	}
+	assert.Equal(t, PackageCoupling{Package: "internal/store", Files: 1, Afferent: 2, Efferent: 0, Instability: 0,
+		Dependents: []string{"cmd/shop", "internal/orders"}}, packages["internal/store"])
+	assert.Equal(t, 1, packages["internal/orders"].Afferent)
+	assert.Equal(t, 1, packages["internal/orders"].Efferent)
+	assert.Equal(t, 0.5, packages["internal/orders"].Instability)
+	assert.Equal(t, 1.0, packages["cmd/shop"].Instability)
+	assert.Equal(t, 0, packages["internal/testkit"].Afferent, "test files do not count")
+
+	markdown := NewMarkdownGenerator(graph).GenerateContextMap()
+	assert.Contains(t, markdown, "### 🧭 Package Coupling")
+	assert.Contains(t, markdown, "| `internal/store` | 2 | 0 | 0.00 |")
+}
+
+func TestMatchImportPackage(t *testing.T) {
+	packages := map[string]*PackageCoupling{
+		"src/app/models": {}, "src/app/services": {}, "src/app": {}, "com/acme/billing": {},
+	}
+	assert.Equal(t, "src/app/models", matchImportPackage("app.models", "src/app/services", packages))
+	assert.Equal(t, "src/app/models", matchImportPackage("app.models.user", "src/app/services", packages))
+	assert.Equal(t, "src/app/models", matchImportPackage(".models", "src/app", packages))
+	assert.Equal(t, "src/app/models", matchImportPackage("..models", "src/app/services", packages))
+	assert.Equal(t, "com/acme/billing", matchImportPackage("com.acme.billing.Invoice", "src/app", packages))
+	assert.Equal(t, "", matchImportPackage("react", "src/app", packages))
+	assert.Equal(t, "", matchImportPackage("./models", "src/app", packages))
+}
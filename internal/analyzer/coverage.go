@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/nuthan-ms/codecontext/internal/coverage"
+	"github.com/nuthan-ms/codecontext/internal/query"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// SetCoverageReports sets the coverage reports (Go coverprofile, lcov or
+// Cobertura) attached to files and symbols after parsing. Relative paths are
+// resolved against the analyzed directory and reports are re-read on every
+// analysis, so re-running the tests is enough to refresh them.
+func (gb *GraphBuilder) SetCoverageReports(paths []string) {
+	gb.coverageReports = paths
+}
+
+// addCoverage loads the configured coverage reports into the graph. Reports
+// that cannot be read are skipped with a progress message, since coverage
+// only refines the analysis.
+func (gb *GraphBuilder) addCoverage(targetDir string) {
+	if len(gb.coverageReports) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(gb.coverageReports))
+	for _, path := range gb.coverageReports {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(targetDir, path)
+		}
+		paths = append(paths, path)
+	}
+	report, err := coverage.LoadAll(paths)
+	if err != nil {
+		if gb.progressCallback != nil {
+			gb.progressCallback(fmt.Sprintf("⚠️ Coverage skipped: %v", err))
+		}
+		return
+	}
+	matched := ApplyCoverage(gb.graph, targetDir, report)
+	if gb.progressCallback != nil {
+		gb.progressCallback(fmt.Sprintf("🧪 Coverage attached to %d files", matched))
+	}
+}
+
+// ApplyCoverage attaches the line and function coverage of a report to the
+// graph's files and symbols, and returns the number of files found in it.
+// Symbols get the coverage of the lines they span (see symbolSpans).
+// Functions and methods count as entered when the report says so or, for
+// reports without function records (Go coverprofiles), when any of their
+// lines ran. Files missing from the report keep nil coverage: unknown rather
+// than untested.
+func ApplyCoverage(graph *types.CodeGraph, root string, report *coverage.Report) int {
+	matched := 0
+	for path, file := range graph.Files {
+		rel := projectPath(root, path)
+		covered := report.Lookup(root, rel)
+		if covered == nil {
+			file.Coverage = nil
+			continue
+		}
+		matched++
+		file.Coverage = &types.Coverage{Lines: covered.Total(), CoveredLines: covered.Covered()}
+
+		for _, span := range symbolSpans(graph, file) {
+			symbol, start, end := span.symbol, span.start, span.end
+			lines, coveredLines := covered.Range(start, end)
+			symbolCoverage := &types.Coverage{Lines: lines, CoveredLines: coveredLines}
+			if span.function {
+				symbolCoverage.Functions = 1
+				if fn := covered.FunctionAt(start, end); fn != nil {
+					if fn.Hits > 0 {
+						symbolCoverage.CoveredFunctions = 1
+					}
+				} else if coveredLines > 0 {
+					symbolCoverage.CoveredFunctions = 1
+				}
+				if len(covered.Functions) == 0 {
+					file.Coverage.Functions++
+					file.Coverage.CoveredFunctions += symbolCoverage.CoveredFunctions
+				}
+			}
+			if lines == 0 && symbolCoverage.Functions == 0 {
+				// Declarations without executable lines, such as types and constants
+				symbol.Coverage = nil
+				continue
+			}
+			symbol.Coverage = symbolCoverage
+		}
+
+		// Reports listing functions give the file's function coverage directly
+		for _, fn := range covered.Functions {
+			file.Coverage.Functions++
+			if fn.Hits > 0 {
+				file.Coverage.CoveredFunctions++
+			}
+		}
+	}
+	return matched
+}
+
+// symbolSpan is the range of lines a symbol covers in its file
+type symbolSpan struct {
+	symbol     *types.Symbol
+	start, end int
+	function   bool // Functions and methods
+}
+
+// symbolSpans returns the line ranges of a file's symbols by start line.
+// Functions whose parser only recorded the declaration line extend to the
+// next symbol or the end of the file.
+func symbolSpans(graph *types.CodeGraph, file *types.FileNode) []symbolSpan {
+	var symbols []*types.Symbol
+	for _, id := range file.Symbols {
+		if symbol := graph.Symbols[id]; symbol != nil {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i].Location.StartLine < symbols[j].Location.StartLine })
+
+	spans := make([]symbolSpan, 0, len(symbols))
+	for i, symbol := range symbols {
+		start, end := symbol.Location.StartLine, symbol.Location.EndLine
+		kind := symbol.NormalizedKind()
+		function := kind == types.SymbolKindFunction || kind == types.SymbolKindMethod
+		if end <= start && function {
+			end = file.Lines
+			for _, next := range symbols[i+1:] {
+				if next.Location.StartLine > start {
+					end = next.Location.StartLine - 1
+					break
+				}
+			}
+		}
+		if end < start {
+			end = start
+		}
+		spans = append(spans, symbolSpan{symbol: symbol, start: start, end: end, function: function})
+	}
+	return spans
+}
+
+// projectPath returns a graph file path relative to the analyzed directory,
+// with forward slashes, or the absolute path of a file outside it. Either
+// may be relative to the working directory.
+func projectPath(root, path string) string {
+	absRoot, err1 := filepath.Abs(root)
+	absPath, err2 := filepath.Abs(path)
+	if err1 == nil && err2 == nil {
+		path = query.RelativePath(absPath, absRoot)
+	}
+	return filepath.ToSlash(path)
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageReports(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/shop\n\ngo 1.22\n",
+		"orders/order.go": "package orders\n\ntype Order struct{}\n\nfunc New() *Order {\n\treturn &Order{}\n}\n\nfunc Cancel() {\n\tpanic(\"todo\")\n}\n",
+		"store/store.go":  "package store\n\nfunc Open() {}\n",
+		"coverage.out": "mode: set\n" +
+			"example.com/shop/orders/order.go:5.20,7.2 1 1\n" +
+			"example.com/shop/orders/order.go:9.15,11.2 1 0\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	builder := NewGraphBuilder()
+	builder.SetCoverageReports([]string{"coverage.out"})
+	graph, err := builder.AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	order := graph.Files[filepath.Join(dir, "orders/order.go")]
+	require.NotNil(t, order)
+	assert.Equal(t, &types.Coverage{Lines: 6, CoveredLines: 3, Functions: 2, CoveredFunctions: 1}, order.Coverage)
+	assert.Nil(t, graph.Files[filepath.Join(dir, "store/store.go")].Coverage, "files missing from the report have unknown coverage")
+
+	symbols := make(map[string]*types.Symbol)
+	for _, id := range order.Symbols {
+		symbols[graph.Symbols[id].Name] = graph.Symbols[id]
+	}
+	assert.Equal(t, &types.Coverage{Lines: 3, CoveredLines: 3, Functions: 1, CoveredFunctions: 1}, symbols["New"].Coverage)
+	assert.Equal(t, &types.Coverage{Lines: 3, CoveredLines: 0, Functions: 1, CoveredFunctions: 0}, symbols["Cancel"].Coverage)
+	assert.Nil(t, symbols["Order"].Coverage, "declarations without executable lines")
+}
+
+func TestHotspotsWeighUntestedCode(t *testing.T) {
+	graph := &types.CodeGraph{
+		Files: map[string]*types.FileNode{
+			"tested.go":   {Path: "tested.go", Coverage: &types.Coverage{Lines: 10, CoveredLines: 10}},
+			"untested.go": {Path: "untested.go", Coverage: &types.Coverage{Lines: 10, CoveredLines: 0}},
+			"unknown.go":  {Path: "unknown.go"},
+			"main.go":     {Path: "main.go"},
+		},
+		Edges: map[types.EdgeId]*types.GraphEdge{
+			"1": {From: "file-main.go", To: "file-tested.go", Type: string(RelationshipImport)},
+			"2": {From: "file-main.go", To: "file-untested.go", Type: string(RelationshipImport)},
+			"3": {From: "file-main.go", To: "file-unknown.go", Type: string(RelationshipImport)},
+		},
+	}
+	metrics := &RelationshipMetrics{}
+	NewRelationshipAnalyzer(graph).identifyHotspotFiles(metrics)
+
+	scores := make(map[string]float64)
+	for _, hotspot := range metrics.HotspotFiles {
+		scores[hotspot.FilePath] = hotspot.Score
+	}
+	assert.Equal(t, 2.0, scores["tested.go"])
+	assert.Equal(t, 2.0, scores["unknown.go"], "unknown coverage is not penalized")
+	assert.Equal(t, 4.0, scores["untested.go"])
+}
@@ -0,0 +1,41 @@
+package analyzer
+
+import (
+	"path/filepath"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// resolveModuleImport resolves a C++20 named module import, such as "math"
+// or the partition "math:detail", to the file declaring that module's
+// interface. Implementation units are never import targets. When several
+// files declare the module, the one closest to the importing file wins.
+// Modules outside the project, such as std, resolve to "".
+func (ra *RelationshipAnalyzer) resolveModuleImport(name, fromFile string) string {
+	if ra.moduleIndex == nil {
+		ra.moduleIndex = make(map[string][]string)
+		for path, file := range ra.graph.Files {
+			for _, symbolId := range file.Symbols {
+				symbol := ra.graph.Symbols[symbolId]
+				if symbol != nil && symbol.Type == types.SymbolTypeModule && symbol.MetadataString(parser.MetadataModuleUnit) != parser.CppModuleImplementation {
+					ra.moduleIndex[symbol.Name] = append(ra.moduleIndex[symbol.Name], path)
+				}
+			}
+		}
+	}
+
+	best := ""
+	bestShared := -1
+	for _, candidate := range ra.moduleIndex[name] {
+		if candidate == fromFile {
+			continue
+		}
+		shared := sharedPrefixLength(filepath.Dir(candidate), filepath.Dir(fromFile))
+		if shared > bestShared || shared == bestShared && candidate < best {
+			best = candidate
+			bestShared = shared
+		}
+	}
+	return best
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleImportRelationships(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/math.cppm":        "export module math;\nimport std;\nexport import :detail;\nexport int add(int a, int b);\n",
+		"src/math_detail.cppm": "export module math:detail;\nexport int twice(int a);\n",
+		"src/math.cpp":         "module math;\nint add(int a, int b) { return a + b; }\n",
+		"src/legacy.h":         "int legacy();\n",
+		"app/main.cpp":         "import math;\nimport \"legacy.h\";\nint main() { return add(1, 2); }\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	_, err = NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+
+	imports := make(map[string]string)
+	for _, edge := range graph.Edges {
+		if edge.Type != string(RelationshipImport) {
+			continue
+		}
+		from, _ := filepath.Rel(dir, string(edge.From)[len("file-"):])
+		to := string(edge.To)
+		if target, ok := edge.Metadata["resolved_path"].(string); ok {
+			to, _ = filepath.Rel(dir, target)
+		}
+		imports[filepath.ToSlash(from)+" -> "+filepath.ToSlash(to)] = ImportEdgeInfo(edge).Kinds[0]
+	}
+	assert.Equal(t, map[string]string{
+		"src/math.cppm -> external-std":         string(types.ImportKindModule),
+		"src/math.cppm -> src/math_detail.cppm": string(types.ImportKindReexport),
+		"src/math.cpp -> src/math.cppm":         string(types.ImportKindModule),
+		"app/main.cpp -> src/math.cppm":         string(types.ImportKindModule),
+		"app/main.cpp -> src/legacy.h":          string(types.ImportKindHeaderUnit),
+	}, imports, "module imports resolve by module name, header units like includes")
+}
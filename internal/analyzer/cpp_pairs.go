@@ -0,0 +1,201 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// C and C++ header/implementation relationships
+const (
+	RelationshipImplementedIn RelationshipType = "implemented-in" // Header file to its implementation file
+	RelationshipDefinedIn     RelationshipType = "defined-in"     // Function declaration to its definition
+)
+
+// cppHeaderExtensions and cppImplementationExtensions split C/C++ files into
+// the two sides of a header/implementation pair
+var (
+	cppHeaderExtensions = map[string]bool{
+		".h": true, ".hh": true, ".hpp": true, ".hxx": true, ".h++": true, ".cuh": true,
+	}
+	cppImplementationExtensions = map[string]bool{
+		".c": true, ".cc": true, ".cpp": true, ".cxx": true, ".c++": true, ".mm": true, ".cu": true,
+	}
+)
+
+// HeaderImplementations pairs C/C++ headers with the implementation file of
+// the same name, such as mesh.h with mesh.cpp. An implementation qualifies
+// when it includes the header or sits in the same directory. When several
+// do, one that includes the header wins, then the one closest to it.
+// Include edges must already be in the graph.
+func HeaderImplementations(graph *types.CodeGraph) map[string]string {
+	implementations := make(map[string][]string)
+	for path := range graph.Files {
+		if ext := strings.ToLower(filepath.Ext(path)); cppImplementationExtensions[ext] {
+			stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			implementations[stem] = append(implementations[stem], path)
+		}
+	}
+
+	pairs := make(map[string]string)
+	for header := range graph.Files {
+		if !cppHeaderExtensions[strings.ToLower(filepath.Ext(header))] {
+			continue
+		}
+		stem := strings.TrimSuffix(filepath.Base(header), filepath.Ext(header))
+		best, bestScore := "", -1
+		for _, candidate := range implementations[stem] {
+			includes := graph.Edges[types.EdgeId(fmt.Sprintf("import-%s-%s", candidate, header))] != nil
+			sameDir := filepath.Dir(candidate) == filepath.Dir(header)
+			if !includes && !sameDir {
+				continue
+			}
+			score := sharedPrefixLength(filepath.Dir(candidate), filepath.Dir(header))
+			if includes {
+				score += 1000
+			}
+			if score > bestScore || score == bestScore && candidate < best {
+				best, bestScore = candidate, score
+			}
+		}
+		if best != "" {
+			pairs[header] = best
+		}
+	}
+	return pairs
+}
+
+// analyzeHeaderPairs links headers to their implementation files and each
+// function or method declaration to its definition. Declarations are matched
+// within a header and its implementation, and within any single file, by
+// name, scope and parameter types. A lone definition with the same name,
+// scope and number of parameters is accepted when no types match, since
+// declarations and definitions often spell parameter types differently.
+func (ra *RelationshipAnalyzer) analyzeHeaderPairs(metrics *RelationshipMetrics) {
+	pairs := HeaderImplementations(ra.graph)
+	groups := make(map[string][]string)
+	paired := make(map[string]bool)
+	for header, implementation := range pairs {
+		edgeId := types.EdgeId(fmt.Sprintf("implemented-in-%s-%s", header, implementation))
+		ra.graph.Edges[edgeId] = &types.GraphEdge{
+			Id:       edgeId,
+			From:     types.NodeId("file-" + header),
+			To:       types.NodeId("file-" + implementation),
+			Type:     string(RelationshipImplementedIn),
+			Weight:   1.0,
+			Metadata: map[string]interface{}{"header": header, "implementation": implementation},
+		}
+		metrics.ByType[RelationshipImplementedIn]++
+		metrics.FileToFile++
+		groups[header] = append(groups[header], header, implementation)
+		paired[header], paired[implementation] = true, true
+	}
+	for path := range ra.graph.Files {
+		if !paired[path] && isCppSourcePath(path) {
+			groups[path] = []string{path}
+		}
+	}
+
+	for _, files := range groups {
+		var declarations, definitions []*types.Symbol
+		fileOf := make(map[types.SymbolId]string)
+		for _, path := range files {
+			for _, symbolId := range ra.graph.Files[path].Symbols {
+				symbol := ra.graph.Symbols[symbolId]
+				if symbol == nil {
+					continue
+				}
+				definition, ok := symbol.Metadata[parser.MetadataDefinition].(bool)
+				if !ok {
+					continue
+				}
+				fileOf[symbol.Id] = path
+				if definition {
+					definitions = append(definitions, symbol)
+				} else {
+					declarations = append(declarations, symbol)
+				}
+			}
+		}
+		sort.Slice(definitions, func(i, j int) bool { return definitions[i].Id < definitions[j].Id })
+
+		for _, declaration := range declarations {
+			definition := matchDefinition(declaration, definitions)
+			if definition == nil {
+				continue
+			}
+			from := types.NodeId("symbol-" + string(declaration.Id))
+			to := types.NodeId("symbol-" + string(definition.Id))
+			edgeId := types.EdgeId(fmt.Sprintf("defined-in-%s-%s", from, to))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   from,
+				To:     to,
+				Type:   string(RelationshipDefinedIn),
+				Weight: 1.0,
+				Metadata: map[string]interface{}{
+					"declaration_file": fileOf[declaration.Id],
+					"declaration_line": declaration.Location.StartLine,
+					"definition_file":  fileOf[definition.Id],
+					"definition_line":  definition.Location.StartLine,
+				},
+			}
+			metrics.ByType[RelationshipDefinedIn]++
+			metrics.SymbolToSymbol++
+		}
+	}
+}
+
+// matchDefinition finds the definition of a declaration among candidates
+func matchDefinition(declaration *types.Symbol, definitions []*types.Symbol) *types.Symbol {
+	scope := declaration.MetadataString(parser.MetadataScope)
+	params := declaration.MetadataString(parser.MetadataParameterTypes)
+	var sameArity []*types.Symbol
+	for _, definition := range definitions {
+		if definition.Name != declaration.Name || !sameCppScope(scope, definition.MetadataString(parser.MetadataScope)) {
+			continue
+		}
+		other := definition.MetadataString(parser.MetadataParameterTypes)
+		if other == params {
+			return definition
+		}
+		if cppArity(other) == cppArity(params) {
+			sameArity = append(sameArity, definition)
+		}
+	}
+	if len(sameArity) == 1 {
+		return sameArity[0]
+	}
+	return nil
+}
+
+// sameCppScope reports whether two scopes name the same namespace or class,
+// allowing one to be written relative to the other as after a using directive
+func sameCppScope(a, b string) bool {
+	return a == b || strings.HasSuffix(a, "::"+b) || strings.HasSuffix(b, "::"+a)
+}
+
+// cppArity counts the parameters in a comma-joined parameter type list
+func cppArity(params string) int {
+	if params == "" {
+		return 0
+	}
+	depth, count := 0, 1
+	for _, r := range params {
+		switch r {
+		case '<', '(':
+			depth++
+		case '>', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				count++
+			}
+		}
+	}
+	return count
+}
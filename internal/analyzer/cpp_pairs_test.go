@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderImplementationPairs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"include/mesh.h":  "#pragma once\nnamespace geo {\nclass Mesh {\npublic:\n    void draw(float scale) const;\n    int size() const;\n};\n}\n",
+		"src/mesh.cpp":    "#include \"mesh.h\"\nusing namespace geo;\nvoid Mesh::draw(float s) const {}\nint Mesh::size() const { return 0; }\n",
+		"tools/mesh.cpp":  "int size() { return 1; }\n",
+		"util/strings.h":  "int length(const char* s);\nint unused(void);\n",
+		"util/strings.c":  "int length(const char *text) { return 0; }\n",
+		"util/inline.hpp": "int twice(int v);\ninline int twice(int v) { return v * 2; }\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	_, err = NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+
+	rel := func(path string) string {
+		r, _ := filepath.Rel(dir, path)
+		return filepath.ToSlash(r)
+	}
+	pairs := make(map[string]string)
+	definitions := make(map[string]string)
+	for _, edge := range graph.Edges {
+		switch edge.Type {
+		case string(RelationshipImplementedIn):
+			pairs[rel(edge.Metadata["header"].(string))] = rel(edge.Metadata["implementation"].(string))
+		case string(RelationshipDefinedIn):
+			from := graph.Symbols[types.SymbolId(edge.From[len("symbol-"):])]
+			definitions[from.Name] = rel(edge.Metadata["declaration_file"].(string)) + " -> " + rel(edge.Metadata["definition_file"].(string))
+		}
+	}
+	assert.Equal(t, map[string]string{
+		"include/mesh.h": "src/mesh.cpp",
+		"util/strings.h": "util/strings.c",
+	}, pairs, "headers pair with the implementation including them or beside them")
+	assert.Equal(t, map[string]string{
+		"draw":   "include/mesh.h -> src/mesh.cpp",
+		"size":   "include/mesh.h -> src/mesh.cpp",
+		"length": "util/strings.h -> util/strings.c",
+		"twice":  "util/inline.hpp -> util/inline.hpp",
+	}, definitions, "declarations link to definitions with the same scope and parameter types")
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// C++ template relationships, from a specialization or explicit
+// instantiation to its primary template
+const (
+	RelationshipSpecializes  RelationshipType = "specializes"
+	RelationshipInstantiates RelationshipType = "instantiates"
+)
+
+// templateRelations maps symbol metadata keys to the edge type they produce
+var templateRelations = []struct {
+	metadataKey  string
+	relationship RelationshipType
+}{
+	{parser.MetadataSpecializes, RelationshipSpecializes},
+	{parser.MetadataInstantiates, RelationshipInstantiates},
+}
+
+// isTemplateVariant reports whether a symbol specializes or instantiates
+// another template rather than declaring one
+func isTemplateVariant(symbol *types.Symbol) bool {
+	return symbol.MetadataString(parser.MetadataSpecializes) != "" || symbol.MetadataString(parser.MetadataInstantiates) != ""
+}
+
+// analyzeTemplateRelationships links C++ template specializations and
+// explicit instantiations to their primary template. Templates declared
+// outside the repository, such as std::hash, become external nodes.
+func (ra *RelationshipAnalyzer) analyzeTemplateRelationships(metrics *RelationshipMetrics) {
+	primaries := make(map[string][]*types.Symbol)
+	for _, symbol := range ra.graph.Symbols {
+		if symbol.Type == types.SymbolTypeTemplate && !isTemplateVariant(symbol) {
+			primaries[symbol.Name] = append(primaries[symbol.Name], symbol)
+		}
+	}
+
+	for _, symbol := range ra.graph.Symbols {
+		if symbol.Type != types.SymbolTypeTemplate {
+			continue
+		}
+		for _, relation := range templateRelations {
+			name := symbol.MetadataString(relation.metadataKey)
+			if name == "" {
+				continue
+			}
+			from := types.NodeId(fmt.Sprintf("symbol-%s", symbol.Id))
+			metadata := map[string]interface{}{
+				"template":      name,
+				"template_args": symbol.MetadataString(parser.MetadataTemplateArgs),
+				"language":      symbol.Language,
+			}
+			if kind := symbol.MetadataString(parser.MetadataSpecialization); kind != "" {
+				metadata["specialization"] = kind
+			}
+
+			var to types.NodeId
+			if target := ra.resolveTypeName(primaries, name, symbol); target != nil {
+				to = types.NodeId(fmt.Sprintf("symbol-%s", target.Id))
+			} else {
+				to = types.NodeId(fmt.Sprintf("external-type-%s", name))
+				metadata["is_external"] = true
+			}
+
+			edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", relation.relationship, from, to))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:       edgeId,
+				From:     from,
+				To:       to,
+				Type:     string(relation.relationship),
+				Weight:   1.0,
+				Metadata: metadata,
+			}
+			metrics.ByType[relation.relationship]++
+			metrics.SymbolToSymbol++
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRelationships(t *testing.T) {
+	dir := t.TempDir()
+	header := "template <typename T>\nclass Vec {};\n\ntemplate <>\nclass Vec<bool> {};\n\ntemplate <typename T>\nclass Vec<T*> {};\n\n" +
+		"template <>\nstruct std::hash<Vec<int>> {};\n\nclass IntVec : public Vec<int> {};\n"
+	source := "#include \"vec.hpp\"\n\ntemplate class Vec<int>;\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vec.hpp"), []byte(header), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vec.cpp"), []byte(source), 0644))
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	metrics, err := NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+	assert.Equal(t, 3, metrics.ByType[RelationshipSpecializes])
+	assert.Equal(t, 1, metrics.ByType[RelationshipInstantiates])
+
+	var primary, full *TypeHierarchyNode
+	for _, symbol := range graph.Symbols {
+		if symbol.Name == "Vec" && symbol.Location.StartLine == 1 {
+			primary = &TypeHierarchyNode{SymbolId: symbol.Id}
+		}
+		if symbol.Name == "Vec" && symbol.Location.StartLine == 4 {
+			full = &TypeHierarchyNode{SymbolId: symbol.Id}
+		}
+	}
+	require.NotNil(t, primary)
+	require.NotNil(t, full)
+
+	hierarchy := NewTypeHierarchy(graph)
+	relationships := make(map[string]string)
+	for _, node := range hierarchy.Subtypes(primary.SymbolId, 1) {
+		relationships[node.Name+":"+filepath.Base(node.FilePath)] = node.Relationship
+	}
+	assert.Equal(t, map[string]string{
+		"Vec:vec.hpp":      string(RelationshipSpecializes),
+		"Vec<int>:vec.cpp": string(RelationshipInstantiates),
+		"IntVec:vec.hpp":   string(RelationshipExtends),
+	}, relationships, "both specializations, the instantiation and the subclass, which extends the primary template")
+	assert.Len(t, hierarchy.Subtypes(primary.SymbolId, 1), 4)
+
+	supertypes := hierarchy.Supertypes(full.SymbolId, 1)
+	require.Len(t, supertypes, 1)
+	assert.Equal(t, primary.SymbolId, supertypes[0].SymbolId)
+
+	external := 0
+	for _, edge := range graph.Edges {
+		if edge.To == "external-type-std::hash" {
+			external++
+			assert.Equal(t, "<Vec<int>>", edge.Metadata["template_args"])
+			assert.Equal(t, "full", edge.Metadata["specialization"])
+		}
+	}
+	assert.Equal(t, 1, external)
+}
@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipGeneratedFrom links a generated Dart part to the annotated
+// class its code was generated from
+const RelationshipGeneratedFrom RelationshipType = "generated-from"
+
+// Dart code generators run by build_runner
+const (
+	DartGeneratorFreezed          = "freezed"
+	DartGeneratorJsonSerializable = "json_serializable"
+)
+
+var (
+	// An annotated class, possibly with further annotations in between
+	dartCodegenClass = regexp.MustCompile(`(?m)^@(freezed|Freezed|unfreezed|JsonSerializable)\b(?:\([^)]*\))?\s*\n(?:@\w+(?:\([^)]*\))?\s*\n)*(?:(?:abstract|sealed|final|base)\s+)*class\s+(\w+)`)
+	// Top-level functions, classes and mixins of a generated part
+	dartGeneratedDeclaration = regexp.MustCompile(`(?m)^(?:(?:abstract|sealed|final|base)\s+)*(?:class|mixin)\s+([\w$]+)|^(?:[\w$<>?,]+[ \t]+)*([\w$]+)(?:<[^>(\n]*>)?\(`)
+)
+
+// dartGeneratorSuffixes maps the part files build_runner writes to the
+// generator writing them
+var dartGeneratorSuffixes = map[string]string{
+	".freezed.dart": DartGeneratorFreezed,
+	".g.dart":       DartGeneratorJsonSerializable,
+}
+
+// DartGeneratedModel is a class annotated for code generation and the
+// generated code derived from it
+type DartGeneratedModel struct {
+	Class      string                `json:"class"`
+	File       string                `json:"file"`
+	Line       int                   `json:"line"`
+	Annotation string                `json:"annotation"` // As written, without the @
+	Outputs    []DartGeneratedOutput `json:"outputs"`
+}
+
+// DartGeneratedOutput is the generated code of a model in one part file
+type DartGeneratedOutput struct {
+	File      string   `json:"file"`
+	Generator string   `json:"generator"`
+	Symbols   []string `json:"symbols"` // Generated declarations for the class
+}
+
+// DartCodegen finds the freezed and json_serializable models of the Dart
+// libraries and the declarations generated for them in the library's
+// .freezed.dart and .g.dart parts. A generated declaration belongs to the
+// model whose name it carries once the leading _ and $ are dropped, such as
+// _$UserFromJson, $UserCopyWith and _$$UserImplToJson for User; the
+// longest matching name wins. Changing a model requires running
+// build_runner to regenerate these parts.
+func DartCodegen(graph *types.CodeGraph) []DartGeneratedModel {
+	var libraries []string
+	for filePath, file := range graph.Files {
+		if file.Language == "dart" && len(file.Parts) > 0 {
+			libraries = append(libraries, filePath)
+		}
+	}
+	sort.Strings(libraries)
+
+	var models []DartGeneratedModel
+	for _, library := range libraries {
+		data, err := os.ReadFile(library)
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		var found []DartGeneratedModel
+		for _, m := range dartCodegenClass.FindAllStringSubmatchIndex(content, -1) {
+			found = append(found, DartGeneratedModel{
+				Class: content[m[4]:m[5]], File: library, Line: lineAt(content, m[0]), Annotation: content[m[2]:m[3]],
+			})
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		for _, part := range graph.Files[library].Parts {
+			generator := dartPartGenerator(part)
+			if generator == "" {
+				continue
+			}
+			data, err := os.ReadFile(part)
+			if err != nil {
+				continue
+			}
+			generated := make(map[int][]string)
+			for _, m := range dartGeneratedDeclaration.FindAllStringSubmatch(string(data), -1) {
+				name := m[1] + m[2]
+				if index := dartGeneratedModel(found, name); index >= 0 && !slices.Contains(generated[index], name) {
+					generated[index] = append(generated[index], name)
+				}
+			}
+			for index, symbols := range generated {
+				sort.Strings(symbols)
+				found[index].Outputs = append(found[index].Outputs, DartGeneratedOutput{File: part, Generator: generator, Symbols: symbols})
+			}
+		}
+		for _, model := range found {
+			if len(model.Outputs) > 0 {
+				sort.Slice(model.Outputs, func(i, j int) bool { return model.Outputs[i].File < model.Outputs[j].File })
+				models = append(models, model)
+			}
+		}
+	}
+	return models
+}
+
+// dartPartGenerator returns the generator writing a part file, or ""
+func dartPartGenerator(part string) string {
+	base := filepath.Base(part)
+	for suffix, generator := range dartGeneratorSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return generator
+		}
+	}
+	return ""
+}
+
+// dartGeneratedModel returns the index of the model a generated name was
+// derived from, or -1
+func dartGeneratedModel(models []DartGeneratedModel, name string) int {
+	rest := strings.TrimLeft(name, "_$")
+	best := -1
+	for i, model := range models {
+		tail, ok := strings.CutPrefix(rest, model.Class)
+		if !ok || (tail != "" && (tail[0] < 'A' || tail[0] > 'Z')) {
+			continue
+		}
+		if best < 0 || len(model.Class) > len(models[best].Class) {
+			best = i
+		}
+	}
+	return best
+}
+
+// analyzeDartCodegen links generated Dart parts to the models they were
+// generated from, so that changes to a model reach its generated code
+func (ra *RelationshipAnalyzer) analyzeDartCodegen(metrics *RelationshipMetrics) {
+	for _, model := range DartCodegen(ra.graph) {
+		target := types.NodeId("file-" + model.File)
+		for _, symbolId := range ra.graph.Files[model.File].Symbols {
+			if symbol := ra.graph.Symbols[symbolId]; symbol != nil && symbol.Name == model.Class && symbol.Type == types.SymbolTypeClass {
+				target = types.NodeId("symbol-" + string(symbolId))
+				break
+			}
+		}
+		for _, output := range model.Outputs {
+			edgeId := types.EdgeId(fmt.Sprintf("generated-from-%s-%s", output.File, model.Class))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   types.NodeId("file-" + output.File),
+				To:     target,
+				Type:   string(RelationshipGeneratedFrom),
+				Weight: 1.0,
+				Metadata: map[string]interface{}{
+					"class":       model.Class,
+					"generator":   output.Generator,
+					"symbols":     output.Symbols,
+					"source_file": model.File,
+					"source_line": model.Line,
+				},
+			}
+			metrics.ByType[RelationshipGeneratedFrom]++
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDartCodegen(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/user.dart": "import 'package:freezed_annotation/freezed_annotation.dart';\n\npart 'user.freezed.dart';\npart 'user.g.dart';\n\n" +
+			"@freezed\nclass User with _$User {\n  const factory User({required String name}) = _User;\n\n" +
+			"  factory User.fromJson(Map<String, dynamic> json) => _$UserFromJson(json);\n}\n\n" +
+			"@JsonSerializable(explicitToJson: true)\nclass UserProfile {\n  final String bio;\n  UserProfile(this.bio);\n" +
+			"  factory UserProfile.fromJson(Map<String, dynamic> json) => _$UserProfileFromJson(json);\n}\n\n" +
+			"class Plain {}\n",
+		"lib/user.g.dart": "// GENERATED CODE - DO NOT MODIFY BY HAND\n\npart of 'user.dart';\n\n" +
+			"_$UserImpl _$$UserImplFromJson(Map<String, dynamic> json) => _$UserImpl(\n      name: json['name'] as String,\n    );\n\n" +
+			"Map<String, dynamic> _$$UserImplToJson(_$UserImpl instance) => <String, dynamic>{\n      'name': instance.name,\n    };\n\n" +
+			"UserProfile _$UserProfileFromJson(Map<String, dynamic> json) => UserProfile(\n      json['bio'] as String,\n    );\n",
+		"lib/user.freezed.dart": "// coverage:ignore-file\n// GENERATED CODE - DO NOT MODIFY BY HAND\n\npart of 'user.dart';\n\n" +
+			"T _$identity<T>(T value) => value;\n\nUser _$UserFromJson(Map<String, dynamic> json) {\n  return _User.fromJson(json);\n}\n\n" +
+			"mixin _$User {\n  String get name => throw _privateConstructorUsedError;\n}\n\n" +
+			"abstract class $UserCopyWith<$Res> {\n  factory $UserCopyWith(User value, $Res Function(User) then) = _$UserCopyWithImpl<$Res, User>;\n}\n\n" +
+			"@JsonSerializable()\nclass _$UserImpl implements _User {\n  const _$UserImpl({required this.name});\n  final String name;\n}\n\n" +
+			"abstract class _User implements User {\n  const factory _User({required final String name}) = _$UserImpl;\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	library := filepath.Join(dir, "lib/user.dart")
+	freezedPart := filepath.Join(dir, "lib/user.freezed.dart")
+	jsonPart := filepath.Join(dir, "lib/user.g.dart")
+	assert.True(t, graph.Files[freezedPart].IsGenerated)
+	assert.True(t, graph.Files[jsonPart].IsGenerated)
+	assert.False(t, graph.Files[library].IsGenerated)
+
+	models := DartCodegen(graph)
+	require.Len(t, models, 2)
+	assert.Equal(t, DartGeneratedModel{
+		Class: "User", File: library, Line: 6, Annotation: "freezed",
+		Outputs: []DartGeneratedOutput{
+			{File: freezedPart, Generator: DartGeneratorFreezed, Symbols: []string{"$UserCopyWith", "_$User", "_$UserFromJson", "_$UserImpl", "_User"}},
+			{File: jsonPart, Generator: DartGeneratorJsonSerializable, Symbols: []string{"_$$UserImplFromJson", "_$$UserImplToJson"}},
+		},
+	}, models[0])
+	assert.Equal(t, DartGeneratedModel{
+		Class: "UserProfile", File: library, Line: 13, Annotation: "JsonSerializable",
+		Outputs: []DartGeneratedOutput{
+			{File: jsonPart, Generator: DartGeneratorJsonSerializable, Symbols: []string{"_$UserProfileFromJson"}},
+		},
+	}, models[1], "the longest model name owns a generated declaration")
+
+	metrics, err := NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+	assert.Equal(t, 3, metrics.ByType[RelationshipGeneratedFrom])
+	var targets []string
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipGeneratedFrom) && edge.Metadata["class"] == "User" {
+			symbol := graph.Symbols[types.SymbolId(edge.To[len("symbol-"):])]
+			require.NotNil(t, symbol, "edges point at the model class")
+			targets = append(targets, symbol.Name)
+		}
+	}
+	assert.Equal(t, []string{"User", "User"}, targets)
+}
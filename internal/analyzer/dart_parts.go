@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipHasPart links a Dart library to the part files it includes
+const RelationshipHasPart RelationshipType = "has-part"
+
+var (
+	dartLibraryDirective = regexp.MustCompile(`(?m)^library\s+([\w.]+)\s*;`)
+	dartPartDirective    = regexp.MustCompile(`(?m)^part\s+['"]([^'"]+)['"]\s*;`)
+	dartPartOfDirective  = regexp.MustCompile(`(?m)^part\s+of\s+(?:['"]([^'"]+)['"]|([\w.]+))\s*;`)
+)
+
+// mergeDartParts records which library each Dart part file belongs to. A
+// library and its parts are one compilation unit: the parts share the
+// library's imports and private names, so they count as a single file in
+// the graph metadata. Parts are matched from the library's part directives
+// and from their own part of directive, by URI or by library name.
+func (gb *GraphBuilder) mergeDartParts() {
+	libraries := make(map[string]string) // library name -> file
+	declared := make(map[string][]string)
+	partOf := make(map[string]string)
+	for filePath, file := range gb.graph.Files {
+		if file.Language != "dart" {
+			continue
+		}
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		if m := dartLibraryDirective.FindStringSubmatch(content); m != nil {
+			libraries[m[1]] = filePath
+		}
+		for _, m := range dartPartDirective.FindAllStringSubmatch(content, -1) {
+			declared[filePath] = append(declared[filePath], gb.normalizePath(filepath.Join(filepath.Dir(filePath), m[1])))
+		}
+		if m := dartPartOfDirective.FindStringSubmatch(content); m != nil {
+			if m[1] != "" {
+				partOf[filePath] = gb.normalizePath(filepath.Join(filepath.Dir(filePath), m[1]))
+			} else {
+				partOf[filePath] = "library:" + m[2]
+			}
+		}
+	}
+
+	owners := make(map[string]string)
+	for library, parts := range declared {
+		for _, part := range parts {
+			if gb.graph.Files[part] != nil && part != library {
+				owners[part] = library
+			}
+		}
+	}
+	// Parts the library does not list, such as those of a library outside the
+	// analyzed directory, only belong to a library found by their part of
+	for part, target := range partOf {
+		if owners[part] != "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(target, "library:"); ok {
+			target = libraries[name]
+		}
+		if target != "" && target != part && gb.graph.Files[target] != nil {
+			owners[part] = target
+		}
+	}
+
+	for part, library := range owners {
+		if gb.graph.Files[library].PartOf != "" {
+			// Parts cannot have parts of their own
+			continue
+		}
+		gb.graph.Files[part].PartOf = library
+		gb.graph.Files[library].Parts = append(gb.graph.Files[library].Parts, part)
+		if gb.graph.Metadata.Languages["dart"] > 0 {
+			gb.graph.Metadata.Languages["dart"]--
+		}
+	}
+	for _, file := range gb.graph.Files {
+		sort.Strings(file.Parts)
+	}
+}
+
+// logicalFileCount counts the files of the graph, with Dart parts counted
+// as part of their library
+func logicalFileCount(graph *types.CodeGraph) int {
+	count := 0
+	for _, file := range graph.Files {
+		if file.PartOf == "" {
+			count++
+		}
+	}
+	return count
+}
+
+// analyzeDartParts links Dart libraries to their part files
+func (ra *RelationshipAnalyzer) analyzeDartParts(metrics *RelationshipMetrics) {
+	for filePath, file := range ra.graph.Files {
+		for _, part := range file.Parts {
+			edgeId := types.EdgeId(fmt.Sprintf("has-part-%s-%s", filePath, part))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   types.NodeId("file-" + filePath),
+				To:     types.NodeId("file-" + part),
+				Type:   string(RelationshipHasPart),
+				Weight: 1.0,
+				Metadata: map[string]interface{}{
+					"library": filePath,
+					"part":    part,
+				},
+			}
+			metrics.ByType[RelationshipHasPart]++
+			metrics.FileToFile++
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeDartParts(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/user.dart":          "import 'address.dart';\n\npart 'user.g.dart';\npart 'src/user_json.dart';\n\nclass User {\n  final String name;\n  User(this.name);\n}\n",
+		"lib/user.g.dart":        "part of 'user.dart';\n\nUser _$UserFromJson(Map json) => User(json['name']);\n",
+		"lib/src/user_json.dart": "part of '../user.dart';\n\nclass UserJson {}\n",
+		"lib/shapes.dart":        "library app.shapes;\n\nclass Shape {}\n",
+		"lib/circle.dart":        "part of app.shapes;\n\nclass Circle extends Shape {}\n",
+		"lib/address.dart":       "class Address {}\n",
+		"lib/orphan_part.g.dart": "part of 'missing.dart';\n\nclass Orphan {}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	user := graph.Files[filepath.Join(dir, "lib/user.dart")]
+	require.NotNil(t, user)
+	assert.Equal(t, []string{filepath.Join(dir, "lib/src/user_json.dart"), filepath.Join(dir, "lib/user.g.dart")}, user.Parts)
+	assert.Equal(t, user.Path, graph.Files[filepath.Join(dir, "lib/user.g.dart")].PartOf)
+	assert.Equal(t, filepath.Join(dir, "lib/shapes.dart"), graph.Files[filepath.Join(dir, "lib/circle.dart")].PartOf, "part of by library name")
+	assert.Empty(t, graph.Files[filepath.Join(dir, "lib/orphan_part.g.dart")].PartOf, "the library is not in the project")
+
+	assert.Equal(t, 4, graph.Metadata.TotalFiles, "parts count as their library")
+	assert.Equal(t, 4, graph.Metadata.Languages["dart"])
+
+	metrics := graph.Metadata.Configuration["relationship_metrics"].(*RelationshipMetrics)
+	assert.Equal(t, 3, metrics.ByType[RelationshipHasPart])
+	assert.NotContains(t, metrics.IsolatedFiles, filepath.Join(dir, "lib/user.g.dart"))
+	assert.NotContains(t, metrics.IsolatedFiles, filepath.Join(dir, "lib/shapes.dart"))
+
+	for _, pkg := range AnalyzeCoupling(graph) {
+		if pkg.Package == "." {
+			assert.Equal(t, 4, pkg.Files, "user.g.dart and circle.dart are not counted")
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+var (
+	// Decorators and annotations leading a line: @Name, @Name(...), @module.name
+	leadingDecorators = regexp.MustCompile(`^(?:@[A-Za-z_][\w.]*(?:\([^)]*\))?\s*)+`)
+	decoratorName     = regexp.MustCompile(`@([A-Za-z_][\w.]*)`)
+	// Rust attributes: #[name], #[name(...)], #![name]
+	rustAttribute = regexp.MustCompile(`^#!?\[([\w:]+)`)
+)
+
+// decoratorLanguages are the languages decorator detection looks at
+var decoratorLanguages = map[string]bool{
+	"typescript": true, "javascript": true, "python": true, "java": true,
+	"dart": true, "swift": true, "rust": true,
+}
+
+// decoratorConcerns groups decorators into the cross-cutting concerns they
+// usually implement, by keywords of their lower-cased name. The first match
+// wins.
+var decoratorConcerns = []struct {
+	concern  string
+	keywords []string
+}{
+	{"auth", []string{"auth", "guard", "secured", "rolesallowed", "roles", "permission", "login_required"}},
+	{"caching", []string{"cache", "memoize", "lru_cache"}},
+	{"transactions", []string{"transaction", "atomic"}},
+	{"resilience", []string{"retry", "circuitbreaker", "ratelimit", "throttle", "backoff", "timeout"}},
+	{"observability", []string{"trace", "timed", "metric", "instrument", "logged"}},
+	{"validation", []string{"valid", "notnull", "notblank", "pattern"}},
+	{"scheduling", []string{"scheduled", "cron", "async", "periodic"}},
+}
+
+// DecoratorUsage is a decorator, annotation or attribute and where it is used
+type DecoratorUsage struct {
+	Name     string          `json:"name"` // With its marker: "@Transactional", "#[derive]"
+	Language string          `json:"language"`
+	Concern  string          `json:"concern,omitempty"` // Cross-cutting concern the name suggests
+	Count    int             `json:"count"`
+	Files    int             `json:"files"`
+	Sites    []DecoratorSite `json:"sites"` // By file and line
+}
+
+// DecoratorSite is one use of a decorator
+type DecoratorSite struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Symbol string `json:"symbol,omitempty"` // Declaration it decorates, when parsed
+}
+
+// FindDecorators indexes the decorators (TypeScript, JavaScript, Python),
+// annotations (Java, Dart, Swift) and attributes (Rust) used in the source
+// files, most used first. Names are kept as written, so @cache and
+// @functools.cache count separately. Test files are skipped.
+func FindDecorators(graph *types.CodeGraph) []DecoratorUsage {
+	usages := make(map[string]*DecoratorUsage)
+	files := make(map[string]map[string]bool)
+	(&RelationshipAnalyzer{graph: graph}).forEachSourceFileIn(decoratorLanguages, func(filePath, content string) {
+		fileNode := graph.Files[filePath]
+		lines := strings.Split(content, "\n")
+		for i, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			var names []string
+			if fileNode.Language == "rust" {
+				if m := rustAttribute.FindStringSubmatch(trimmed); m != nil {
+					names = []string{"#[" + m[1] + "]"}
+				}
+			} else if prefix := leadingDecorators.FindString(trimmed); prefix != "" {
+				for _, m := range decoratorName.FindAllStringSubmatch(prefix, -1) {
+					// Java annotation type declarations use the same marker
+					if m[1] != "interface" {
+						names = append(names, "@"+m[1])
+					}
+				}
+			}
+			if len(names) == 0 {
+				continue
+			}
+
+			site := DecoratorSite{File: filePath, Line: i + 1}
+			if symbol := decoratedSymbol(graph, fileNode, lines, i+1); symbol != nil {
+				site.Symbol = symbol.Name
+			}
+			for _, name := range names {
+				key := fileNode.Language + "\x00" + name
+				usage := usages[key]
+				if usage == nil {
+					usage = &DecoratorUsage{Name: name, Language: fileNode.Language, Concern: decoratorConcern(name)}
+					usages[key] = usage
+					files[key] = make(map[string]bool)
+				}
+				usage.Count++
+				usage.Sites = append(usage.Sites, site)
+				files[key][filePath] = true
+			}
+		}
+	})
+
+	result := make([]DecoratorUsage, 0, len(usages))
+	for key, usage := range usages {
+		usage.Files = len(files[key])
+		sort.Slice(usage.Sites, func(i, j int) bool {
+			if usage.Sites[i].File != usage.Sites[j].File {
+				return usage.Sites[i].File < usage.Sites[j].File
+			}
+			return usage.Sites[i].Line < usage.Sites[j].Line
+		})
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Language < result[j].Language
+	})
+	return result
+}
+
+// decoratedSymbol returns the symbol declared by the first line from line on
+// that is more than decorators. Parsers start symbols at either the decorator
+// or the declaration; the later one wins.
+func decoratedSymbol(graph *types.CodeGraph, fileNode *types.FileNode, lines []string, line int) *types.Symbol {
+	declaration := line
+	for ; declaration <= len(lines); declaration++ {
+		trimmed := strings.TrimSpace(lines[declaration-1])
+		if fileNode.Language == "rust" {
+			if !rustAttribute.MatchString(trimmed) {
+				break
+			}
+			continue
+		}
+		if strings.TrimSpace(leadingDecorators.ReplaceAllString(trimmed, "")) != "" {
+			break
+		}
+	}
+	var decorated *types.Symbol
+	for _, id := range fileNode.Symbols {
+		symbol := graph.Symbols[id]
+		if symbol == nil || symbol.Location.StartLine < line || symbol.Location.StartLine > declaration {
+			continue
+		}
+		if decorated == nil || symbol.Location.StartLine > decorated.Location.StartLine ||
+			(symbol.Location.StartLine == decorated.Location.StartLine && symbol.Name < decorated.Name) {
+			decorated = symbol
+		}
+	}
+	return decorated
+}
+
+// DecoratorConcernNames returns the cross-cutting concerns decorators are
+// grouped into
+func DecoratorConcernNames() []string {
+	names := make([]string, len(decoratorConcerns))
+	for i, group := range decoratorConcerns {
+		names[i] = group.concern
+	}
+	return names
+}
+
+// decoratorConcern returns the cross-cutting concern a decorator name
+// suggests, or "" when it suggests none
+func decoratorConcern(name string) string {
+	lower := strings.ToLower(name)
+	for _, group := range decoratorConcerns {
+		for _, keyword := range group.keywords {
+			if strings.Contains(lower, keyword) {
+				return group.concern
+			}
+		}
+	}
+	return ""
+}
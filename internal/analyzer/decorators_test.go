@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDecorators(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"api/views.py": "from functools import lru_cache\n\n@app.get(\"/users\")\n@login_required\ndef list_users():\n    return []\n\n\n" +
+			"@lru_cache(maxsize=32)\ndef load_config():\n    return {}\n\n\n@login_required\ndef delete_user():\n    pass\n",
+		"src/OrderService.java": "package shop;\n\n@Service\npublic class OrderService {\n    @Transactional @Timed\n    public void place() {}\n}\n\n" +
+			"public @interface Audited {}\n",
+		"src/cache.ts":        "/**\n * @param key the cache key\n */\n@Injectable()\nexport class CacheService {}\n",
+		"src/main.rs":         "#[derive(Debug, Clone)]\nstruct Config {}\n\n#[tokio::main]\nasync fn main() {}\n",
+		"tests/test_views.py": "@pytest.fixture\ndef client():\n    pass\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	found := make(map[string]DecoratorUsage)
+	for _, usage := range FindDecorators(graph) {
+		found[usage.Name] = usage
+	}
+	assert.NotContains(t, found, "@param", "doc comment tags are not decorators")
+	assert.NotContains(t, found, "@interface", "annotation type declarations are not uses")
+	assert.NotContains(t, found, "@pytest.fixture", "test files are skipped")
+
+	required := found["@login_required"]
+	assert.Equal(t, "python", required.Language)
+	assert.Equal(t, 2, required.Count)
+	assert.Equal(t, 1, required.Files)
+	assert.Equal(t, "auth", required.Concern)
+	require.Len(t, required.Sites, 2)
+	assert.Equal(t, 4, required.Sites[0].Line)
+	assert.Equal(t, "list_users", required.Sites[0].Symbol)
+	assert.Equal(t, "delete_user", required.Sites[1].Symbol)
+	assert.Equal(t, "list_users", found["@app.get"].Sites[0].Symbol, "stacked decorators share the declaration")
+
+	assert.Equal(t, "caching", found["@lru_cache"].Concern)
+	assert.Equal(t, "transactions", found["@Transactional"].Concern)
+	assert.Equal(t, "observability", found["@Timed"].Concern)
+	assert.Equal(t, "place", found["@Timed"].Sites[0].Symbol)
+	assert.Equal(t, "OrderService", found["@Service"].Sites[0].Symbol)
+	assert.Equal(t, "CacheService", found["@Injectable"].Sites[0].Symbol)
+	assert.Equal(t, "rust", found["#[derive]"].Language)
+	assert.Equal(t, "main", found["#[tokio::main]"].Sites[0].Symbol)
+
+	usages := FindDecorators(graph)
+	assert.Equal(t, "@login_required", usages[0].Name, "most used first")
+}
@@ -0,0 +1,657 @@
+package analyzer
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Package ecosystems whose manifests are checked against the imports of the code
+const (
+	EcosystemNPM  = "npm"
+	EcosystemGo   = "go"
+	EcosystemPyPI = "pypi"
+)
+
+// maxDependencyUses bounds the import sites recorded per missing dependency
+const maxDependencyUses = 5
+
+var (
+	goRequirePattern   = regexp.MustCompile(`^(\S+)\s+(\S+)`)
+	requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*(.*)$`)
+	requirementEgg     = regexp.MustCompile(`#egg=([A-Za-z0-9][A-Za-z0-9._-]*)`)
+	pythonNameSplitter = regexp.MustCompile(`[-_.]+`)
+)
+
+// npmDependencyScopes are the dependency sections of package.json; only
+// packages of the first are needed at runtime
+var npmDependencyScopes = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// nodeBuiltins are the core modules of Node.js, importable without "node:"
+var nodeBuiltins = map[string]bool{
+	"assert": true, "async_hooks": true, "buffer": true, "child_process": true, "cluster": true, "console": true,
+	"constants": true, "crypto": true, "dgram": true, "diagnostics_channel": true, "dns": true, "domain": true,
+	"events": true, "fs": true, "http": true, "http2": true, "https": true, "inspector": true, "module": true,
+	"net": true, "os": true, "path": true, "perf_hooks": true, "process": true, "punycode": true,
+	"querystring": true, "readline": true, "repl": true, "stream": true, "string_decoder": true, "sys": true,
+	"timers": true, "tls": true, "trace_events": true, "tty": true, "url": true, "util": true, "v8": true,
+	"vm": true, "wasi": true, "worker_threads": true, "zlib": true,
+}
+
+// pythonStdlib are the top-level modules of the Python standard library
+var pythonStdlib = map[string]bool{
+	"__future__": true, "abc": true, "aifc": true, "argparse": true, "array": true, "ast": true, "asynchat": true,
+	"asyncio": true, "asyncore": true, "atexit": true, "audioop": true, "base64": true, "bdb": true, "binascii": true,
+	"bisect": true, "builtins": true, "bz2": true, "cProfile": true, "calendar": true, "cgi": true, "cgitb": true,
+	"chunk": true, "cmath": true, "cmd": true, "code": true, "codecs": true, "codeop": true, "collections": true,
+	"colorsys": true, "compileall": true, "concurrent": true, "configparser": true, "contextlib": true,
+	"contextvars": true, "copy": true, "copyreg": true, "crypt": true, "csv": true, "ctypes": true, "curses": true,
+	"dataclasses": true, "datetime": true, "dbm": true, "decimal": true, "difflib": true, "dis": true,
+	"distutils": true, "doctest": true, "email": true, "encodings": true, "ensurepip": true, "enum": true,
+	"errno": true, "faulthandler": true, "fcntl": true, "filecmp": true, "fileinput": true, "fnmatch": true,
+	"fractions": true, "ftplib": true, "functools": true, "gc": true, "getopt": true, "getpass": true,
+	"gettext": true, "glob": true, "graphlib": true, "grp": true, "gzip": true, "hashlib": true, "heapq": true,
+	"hmac": true, "html": true, "http": true, "imaplib": true, "imghdr": true, "imp": true, "importlib": true,
+	"inspect": true, "io": true, "ipaddress": true, "itertools": true, "json": true, "keyword": true,
+	"lib2to3": true, "linecache": true, "locale": true, "logging": true, "lzma": true, "mailbox": true,
+	"mailcap": true, "marshal": true, "math": true, "mimetypes": true, "mmap": true, "modulefinder": true,
+	"msvcrt": true, "multiprocessing": true, "netrc": true, "nntplib": true, "numbers": true, "opcode": true,
+	"operator": true, "optparse": true, "os": true, "pathlib": true, "pdb": true, "pickle": true,
+	"pickletools": true, "pipes": true, "pkgutil": true, "platform": true, "plistlib": true, "poplib": true,
+	"posix": true, "posixpath": true, "pprint": true, "profile": true, "pstats": true, "pty": true, "pwd": true,
+	"py_compile": true, "pyclbr": true, "pydoc": true, "queue": true, "quopri": true, "random": true, "re": true,
+	"readline": true, "reprlib": true, "resource": true, "rlcompleter": true, "runpy": true, "sched": true,
+	"secrets": true, "select": true, "selectors": true, "shelve": true, "shlex": true, "shutil": true,
+	"signal": true, "site": true, "smtplib": true, "sndhdr": true, "socket": true, "socketserver": true,
+	"sqlite3": true, "ssl": true, "stat": true, "statistics": true, "string": true, "stringprep": true,
+	"struct": true, "subprocess": true, "sunau": true, "symtable": true, "sys": true, "sysconfig": true,
+	"syslog": true, "tabnanny": true, "tarfile": true, "telnetlib": true, "tempfile": true, "termios": true,
+	"textwrap": true, "threading": true, "time": true, "timeit": true, "tkinter": true, "token": true,
+	"tokenize": true, "tomllib": true, "trace": true, "traceback": true, "tracemalloc": true, "tty": true,
+	"turtle": true, "types": true, "typing": true, "unicodedata": true, "unittest": true, "urllib": true,
+	"uu": true, "uuid": true, "venv": true, "warnings": true, "wave": true, "weakref": true, "webbrowser": true,
+	"winreg": true, "winsound": true, "wsgiref": true, "xdrlib": true, "xml": true, "xmlrpc": true,
+	"zipapp": true, "zipfile": true, "zipimport": true, "zlib": true, "zoneinfo": true,
+}
+
+// pythonDistributions maps import names to the distributions providing
+// them where the two differ, normalized as by normalizePythonName
+var pythonDistributions = map[string][]string{
+	"PIL": {"pillow"}, "yaml": {"pyyaml"}, "bs4": {"beautifulsoup4"}, "sklearn": {"scikit_learn"},
+	"skimage": {"scikit_image"}, "cv2": {"opencv_python", "opencv_python_headless", "opencv_contrib_python"},
+	"dateutil": {"python_dateutil"}, "dotenv": {"python_dotenv"}, "jwt": {"pyjwt"}, "jose": {"python_jose"},
+	"psycopg2": {"psycopg2_binary"}, "rest_framework": {"djangorestframework"}, "serial": {"pyserial"},
+	"magic": {"python_magic"}, "Crypto": {"pycryptodome", "pycrypto"}, "OpenSSL": {"pyopenssl"},
+	"attr": {"attrs"}, "multipart": {"python_multipart"}, "slugify": {"python_slugify"},
+	"socketio": {"python_socketio"}, "telegram": {"python_telegram_bot"}, "docx": {"python_docx"},
+	"pptx": {"python_pptx"}, "git": {"gitpython"}, "MySQLdb": {"mysqlclient"}, "zmq": {"pyzmq"},
+	"usb": {"pyusb"}, "win32api": {"pywin32"}, "fitz": {"pymupdf"}, "gi": {"pygobject"}, "wx": {"wxpython"},
+	"corsheaders": {"django_cors_headers"}, "django_filters": {"django_filter"},
+	"debug_toolbar": {"django_debug_toolbar"}, "environ": {"django_environ"}, "storages": {"django_storages"},
+	"dns": {"dnspython"}, "ldap": {"python_ldap"}, "nacl": {"pynacl"}, "kafka": {"kafka_python"},
+	"grpc": {"grpcio"}, "google": {"protobuf"}, "pkg_resources": {"setuptools"}, "faiss": {"faiss_cpu"},
+	"Levenshtein": {"python_levenshtein"}, "engineio": {"python_engineio"},
+}
+
+// pythonTools are distributions used from the command line or by
+// configuration rather than imported, with their plugins (pytest_cov), so
+// they are never reported unused
+var pythonTools = []string{
+	"gunicorn", "uvicorn", "daphne", "hypercorn", "pytest", "black", "flake8", "mypy", "ruff", "isort",
+	"pylint", "pre_commit", "coverage", "tox", "nox", "wheel", "setuptools", "pip", "twine", "types",
+	"psycopg", "psycopg2", "mysqlclient", "sphinx", "ipython", "jupyter", "bandit",
+}
+
+// DeclaredDependency is a dependency listed in a manifest
+type DeclaredDependency struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Scope   string `json:"scope"` // Manifest section, or "dev" for development requirement files
+	Line    int    `json:"line"`
+}
+
+// DependencyUse is an import of a package
+type DependencyUse struct {
+	File string `json:"file"` // Relative to the project root
+	Line int    `json:"line"`
+}
+
+// UndeclaredDependency is a package imported by the code that its manifest
+// does not declare, or declares only for development
+type UndeclaredDependency struct {
+	Name    string          `json:"name"`
+	DevOnly bool            `json:"dev_only,omitempty"` // Declared as a development dependency only
+	Files   int             `json:"files"`              // Files importing it
+	Uses    []DependencyUse `json:"uses"`               // The first import sites, in file order
+}
+
+// ManifestCheck is the result of checking one manifest against the imports
+// of the code it covers
+type ManifestCheck struct {
+	File      string                 `json:"file"` // Relative to the project root
+	Ecosystem string                 `json:"ecosystem"`
+	Declared  int                    `json:"declared"`
+	Unused    []DeclaredDependency   `json:"unused,omitempty"`
+	Missing   []UndeclaredDependency `json:"missing,omitempty"`
+}
+
+// dependencyManifest is a parsed manifest and the directory whose code it covers
+type dependencyManifest struct {
+	check        ManifestCheck
+	dir          string // Relative to the project root, "" at the root
+	dependencies []DeclaredDependency
+	module       string // Go module path
+	development  bool   // A requirements file of development dependencies only
+	tools        []string
+	used         map[string]map[string]bool // Files importing each declared dependency
+}
+
+// CheckDependencies compares the dependencies declared by the package.json,
+// go.mod and requirements*.txt files under root with the imports of the code
+// they cover: the files below the manifest that no nearer manifest of the
+// same ecosystem covers. Runtime dependencies nothing imports are unused;
+// imported packages that no covering manifest declares are missing, and
+// those declared only for development but imported by production code are
+// missing as dev-only. Development dependencies and command-line tools are
+// never reported unused, since they are run rather than imported.
+func CheckDependencies(graph *types.CodeGraph, root string) []ManifestCheck {
+	var checks []ManifestCheck
+	for _, manifest := range analyzeDependencyManifests(graph, root) {
+		checks = append(checks, manifest.check)
+	}
+	return checks
+}
+
+// analyzeDependencyManifests parses the manifests under root, sorted by
+// path, and attributes the imports of the code to them
+func analyzeDependencyManifests(graph *types.CodeGraph, root string) []*dependencyManifest {
+	var manifests []*dependencyManifest
+	filepath.WalkDir(root, func(filePath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if filePath != root && (assetSkipDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		var manifest *dependencyManifest
+		switch name := entry.Name(); {
+		case name == "package.json":
+			manifest = parsePackageJSONDependencies(filePath)
+		case name == "go.mod":
+			manifest = parseGoModDependencies(filePath)
+		case isRequirementsFile(filePath):
+			manifest = parseRequirementsDependencies(filePath)
+		}
+		if manifest != nil {
+			manifest.check.File = projectPath(root, filePath)
+			manifest.dir = path.Dir(manifest.check.File)
+			if filepath.Base(filepath.Dir(filePath)) == "requirements" {
+				manifest.dir = path.Dir(manifest.dir)
+			}
+			if manifest.dir == "." {
+				manifest.dir = ""
+			}
+			manifest.check.Declared = len(manifest.dependencies)
+			manifest.used = make(map[string]map[string]bool)
+			manifests = append(manifests, manifest)
+		}
+		return nil
+	})
+	if len(manifests) == 0 {
+		return nil
+	}
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].check.File < manifests[j].check.File })
+
+	localPython := localPythonModules(graph, root)
+	// Imports resolved to project files, such as path aliases and workspace packages
+	resolved := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipImport) && edge.Metadata["resolved_path"] != nil {
+			importPath, _ := edge.Metadata["import_path"].(string)
+			resolved[strings.TrimPrefix(string(edge.From), "file-")+"\x00"+importPath] = true
+		}
+	}
+	type missingKey struct {
+		manifest *dependencyManifest
+		name     string
+	}
+	missing := make(map[missingKey]*UndeclaredDependency)
+	missingFiles := make(map[missingKey]map[string]bool)
+
+	var files []string
+	for filePath := range graph.Files {
+		files = append(files, filePath)
+	}
+	sort.Strings(files)
+	for _, filePath := range files {
+		fileNode := graph.Files[filePath]
+		ecosystem := importEcosystem(fileNode.Language)
+		if ecosystem == "" {
+			continue
+		}
+		rel := projectPath(root, filePath)
+		covering := coveringManifests(manifests, ecosystem, rel)
+		if len(covering) == 0 {
+			continue
+		}
+		development := fileNode.IsTest || isToolingFile(rel)
+		for _, imp := range fileNode.Imports {
+			name := importedPackage(ecosystem, imp.Path, covering[0].module, localPython)
+			if name == "" {
+				continue
+			}
+			declared, devOnly := false, true
+			// The use counts for the nearest manifest declaring the package
+			for _, manifest := range covering {
+				if dep := manifest.declares(name); dep != nil {
+					if !declared {
+						manifest.markUsed(dep.Name, rel)
+					}
+					declared = true
+					devOnly = devOnly && isDevelopmentScope(dep.Scope)
+				}
+			}
+			// Imports resolved to project files are not packages, and type-only
+			// imports are erased so development packages serve them
+			if resolved[filePath+"\x00"+imp.Path] || declared && (!devOnly || development || imp.Kind == types.ImportKindTypeOnly) {
+				continue
+			}
+			if ecosystem == EcosystemPyPI {
+				name, _, _ = strings.Cut(name, ".")
+			}
+			key := missingKey{covering[0], name}
+			dep := missing[key]
+			if dep == nil {
+				dep = &UndeclaredDependency{Name: name, DevOnly: declared}
+				missing[key] = dep
+				missingFiles[key] = make(map[string]bool)
+			}
+			if !missingFiles[key][rel] {
+				missingFiles[key][rel] = true
+				dep.Files++
+			}
+			if len(dep.Uses) < maxDependencyUses {
+				dep.Uses = append(dep.Uses, DependencyUse{File: rel, Line: imp.Location.Line})
+			}
+		}
+	}
+
+	for _, manifest := range manifests {
+		for _, tool := range manifest.tools {
+			if dep := manifest.declares(tool); dep != nil {
+				manifest.markUsed(dep.Name, "")
+			}
+		}
+		for _, dep := range manifest.dependencies {
+			if _, used := manifest.used[dep.Name]; !used && !isDevelopmentScope(dep.Scope) && !isRunOnlyDependency(manifest.check.Ecosystem, dep) {
+				manifest.check.Unused = append(manifest.check.Unused, dep)
+			}
+		}
+		for key, dep := range missing {
+			if key.manifest == manifest {
+				manifest.check.Missing = append(manifest.check.Missing, *dep)
+			}
+		}
+		sort.Slice(manifest.check.Missing, func(i, j int) bool {
+			return manifest.check.Missing[i].Name < manifest.check.Missing[j].Name
+		})
+	}
+	return manifests
+}
+
+// markUsed records a file importing a declared dependency; tools are used
+// without a file
+func (m *dependencyManifest) markUsed(name, file string) {
+	if m.used[name] == nil {
+		m.used[name] = make(map[string]bool)
+	}
+	if file != "" {
+		m.used[name][file] = true
+	}
+}
+
+// declares returns the declared dependency providing an imported package
+func (m *dependencyManifest) declares(name string) *DeclaredDependency {
+	var best *DeclaredDependency
+	for i := range m.dependencies {
+		dep := &m.dependencies[i]
+		switch m.check.Ecosystem {
+		case EcosystemGo:
+			// The longest module path containing the package wins
+			if (name == dep.Name || strings.HasPrefix(name, dep.Name+"/")) && (best == nil || len(dep.Name) > len(best.Name)) {
+				best = dep
+			}
+		case EcosystemPyPI:
+			if pythonProvides(dep.Name, name) {
+				return dep
+			}
+		default:
+			if dep.Name == name {
+				return dep
+			}
+		}
+	}
+	return best
+}
+
+// coveringManifests returns the manifests of an ecosystem whose directory
+// contains a file, nearest first. A Go file belongs to its nearest module only.
+func coveringManifests(manifests []*dependencyManifest, ecosystem, file string) []*dependencyManifest {
+	var covering []*dependencyManifest
+	for _, manifest := range manifests {
+		if manifest.check.Ecosystem == ecosystem && (manifest.dir == "" || strings.HasPrefix(file, manifest.dir+"/")) {
+			covering = append(covering, manifest)
+		}
+	}
+	// Runtime requirement files come before development ones of the same directory
+	sort.SliceStable(covering, func(i, j int) bool {
+		if len(covering[i].dir) != len(covering[j].dir) {
+			return len(covering[i].dir) > len(covering[j].dir)
+		}
+		return !covering[i].development && covering[j].development
+	})
+	if ecosystem == EcosystemGo && len(covering) > 0 {
+		nearest := covering[0].dir
+		for len(covering) > 1 && covering[len(covering)-1].dir != nearest {
+			covering = covering[:len(covering)-1]
+		}
+	}
+	return covering
+}
+
+// importEcosystem returns the ecosystem of the packages a language imports
+func importEcosystem(language string) string {
+	switch language {
+	case "javascript", "typescript":
+		return EcosystemNPM
+	case "go":
+		return EcosystemGo
+	case "python":
+		return EcosystemPyPI
+	}
+	return ""
+}
+
+// importedPackage returns the third-party package an import path names, or
+// "" for relative paths, standard library modules and the project's own code:
+// the package name of a bare npm specifier, the import path of a Go package,
+// or the dotted module path of a Python import
+func importedPackage(ecosystem, importPath, module string, localPython map[string]bool) string {
+	switch ecosystem {
+	case EcosystemNPM:
+		if importPath == "" || strings.HasPrefix(importPath, ".") || strings.HasPrefix(importPath, "/") ||
+			strings.Contains(importPath, ":") || strings.ContainsAny(importPath[:1], "~#@$") && !isScopedPackage(importPath) {
+			return "" // Relative, URL, node:/bun: protocol, or a path alias
+		}
+		parts := strings.SplitN(importPath, "/", 3)
+		if isScopedPackage(importPath) {
+			return parts[0] + "/" + parts[1]
+		}
+		if nodeBuiltins[parts[0]] {
+			return ""
+		}
+		return parts[0]
+	case EcosystemGo:
+		first, _, _ := strings.Cut(importPath, "/")
+		if !strings.Contains(first, ".") || module != "" && (importPath == module || strings.HasPrefix(importPath, module+"/")) {
+			return ""
+		}
+		return importPath
+	case EcosystemPyPI:
+		top, _, _ := strings.Cut(importPath, ".")
+		if top == "" || pythonStdlib[top] || localPython[top] {
+			return ""
+		}
+		return importPath
+	}
+	return ""
+}
+
+// isScopedPackage reports whether an npm specifier names a scoped package such as @org/name
+func isScopedPackage(specifier string) bool {
+	scope, rest, ok := strings.Cut(specifier, "/")
+	return ok && len(scope) > 1 && scope[0] == '@' && rest != "" && scope != "@"
+}
+
+// pythonProvides reports whether a distribution provides a dotted module
+// path: by its normalized name, by a dotted prefix such as google.cloud.storage
+// for google-cloud-storage, or through pythonDistributions
+func pythonProvides(distribution, modulePath string) bool {
+	normalized := normalizePythonName(distribution)
+	parts := strings.Split(modulePath, ".")
+	for i := len(parts); i > 0; i-- {
+		if normalizePythonName(strings.Join(parts[:i], ".")) == normalized {
+			return true
+		}
+	}
+	for _, candidate := range pythonDistributions[parts[0]] {
+		if candidate == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePythonName lowercases a distribution or module name and joins
+// its words with underscores
+func normalizePythonName(name string) string {
+	return pythonNameSplitter.ReplaceAllString(strings.ToLower(name), "_")
+}
+
+// localPythonModules returns the top-level names the project's own Python
+// files can be imported by: their directories and module names
+func localPythonModules(graph *types.CodeGraph, root string) map[string]bool {
+	local := make(map[string]bool)
+	for filePath, fileNode := range graph.Files {
+		if fileNode.Language != "python" {
+			continue
+		}
+		for _, part := range strings.Split(projectPath(root, filePath), "/") {
+			local[strings.TrimSuffix(part, ".py")] = true
+		}
+	}
+	return local
+}
+
+// isToolingFile reports whether a file configures or drives development
+// tools rather than shipping with the code, so it may import development
+// dependencies
+func isToolingFile(file string) bool {
+	base := path.Base(file)
+	switch {
+	case strings.Contains(base, ".config.") || strings.Contains(base, ".stories.") || strings.HasPrefix(base, "."):
+		return true
+	case base == "setup.py" || base == "conftest.py" || base == "noxfile.py" || base == "fabfile.py":
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		switch dir {
+		case "scripts", "tools", "test", "tests", "__tests__", "e2e", "cypress", "docs", "examples", "benchmarks":
+			return true
+		}
+	}
+	return false
+}
+
+// isDevelopmentScope reports whether a manifest section holds development dependencies
+func isDevelopmentScope(scope string) bool {
+	return scope == "devDependencies" || scope == "dev"
+}
+
+// isRunOnlyDependency reports whether a runtime dependency is used without
+// being imported: type packages, peer and optional dependencies, Go
+// requirements kept for other modules and Python command-line tools
+func isRunOnlyDependency(ecosystem string, dep DeclaredDependency) bool {
+	switch ecosystem {
+	case EcosystemNPM:
+		return dep.Scope != "dependencies" || strings.HasPrefix(dep.Name, "@types/")
+	case EcosystemGo:
+		return dep.Scope == "indirect"
+	case EcosystemPyPI:
+		normalized := normalizePythonName(dep.Name)
+		for _, tool := range pythonTools {
+			if normalized == tool || strings.HasPrefix(normalized, tool+"_") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parsePackageJSONDependencies reads the dependency sections of a package.json
+func parsePackageJSONDependencies(manifestPath string) *dependencyManifest {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil
+	}
+	content := string(data)
+	manifest := &dependencyManifest{check: ManifestCheck{Ecosystem: EcosystemNPM}}
+	for _, scope := range npmDependencyScopes {
+		var deps map[string]string
+		if json.Unmarshal(sections[scope], &deps) != nil || len(deps) == 0 {
+			continue
+		}
+		// The line of each entry is searched for after the section's key
+		start := strings.Index(content, `"`+scope+`"`)
+		for name, version := range deps {
+			line := 0
+			if start >= 0 {
+				if i := strings.Index(content[start:], `"`+name+`"`); i >= 0 {
+					line = strings.Count(content[:start+i], "\n") + 1
+				}
+			}
+			manifest.dependencies = append(manifest.dependencies, DeclaredDependency{Name: name, Version: version, Scope: scope, Line: line})
+		}
+	}
+	sortDeclaredDependencies(manifest.dependencies)
+	return manifest
+}
+
+// parseGoModDependencies reads the module path, requirements and tools of a go.mod
+func parseGoModDependencies(manifestPath string) *dependencyManifest {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	manifest := &dependencyManifest{check: ManifestCheck{Ecosystem: EcosystemGo}}
+	block := ""
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		code, comment, _ := strings.Cut(scanner.Text(), "//")
+		code = strings.TrimSpace(code)
+		directive := block
+		if block == "" {
+			var rest string
+			directive, rest, _ = strings.Cut(code, " ")
+			if rest = strings.TrimSpace(rest); rest == "(" {
+				block = directive
+				continue
+			}
+			code = rest
+		} else if code == ")" {
+			block = ""
+			continue
+		}
+		switch directive {
+		case "module":
+			manifest.module = strings.Trim(code, `"`)
+		case "require":
+			if match := goRequirePattern.FindStringSubmatch(code); match != nil {
+				scope := "require"
+				if strings.TrimSpace(comment) == "indirect" {
+					scope = "indirect"
+				}
+				manifest.dependencies = append(manifest.dependencies, DeclaredDependency{
+					Name: strings.Trim(match[1], `"`), Version: match[2], Scope: scope, Line: lineNum,
+				})
+			}
+		case "tool":
+			if code != "" {
+				manifest.tools = append(manifest.tools, strings.Trim(code, `"`))
+			}
+		}
+	}
+	sortDeclaredDependencies(manifest.dependencies)
+	return manifest
+}
+
+// isRequirementsFile reports whether a file lists pip requirements:
+// requirements*.txt, or any .txt file in a requirements directory
+func isRequirementsFile(filePath string) bool {
+	base := filepath.Base(filePath)
+	if filepath.Ext(base) != ".txt" {
+		return false
+	}
+	return strings.HasPrefix(base, "requirements") || filepath.Base(filepath.Dir(filePath)) == "requirements"
+}
+
+// parseRequirementsDependencies reads the requirements of a pip requirements
+// file. Files named for development or tests hold development requirements.
+func parseRequirementsDependencies(manifestPath string) *dependencyManifest {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	scope := "requirements"
+	base := strings.ToLower(filepath.Base(manifestPath))
+	for _, marker := range []string{"dev", "test", "lint", "docs", "ci"} {
+		if strings.Contains(base, marker) {
+			scope = "dev"
+		}
+	}
+	manifest := &dependencyManifest{check: ManifestCheck{Ecosystem: EcosystemPyPI}, development: scope == "dev"}
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, version := "", ""
+		if strings.HasPrefix(line, "-") {
+			// Options; editable and URL requirements name their package with #egg=
+			if match := requirementEgg.FindStringSubmatch(line); match != nil {
+				name = match[1]
+			}
+		} else if match := requirementPattern.FindStringSubmatch(line); match != nil && !strings.Contains(match[1], "://") {
+			name = match[1]
+			version, _, _ = strings.Cut(match[2], ";")
+			version = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(version), "@"))
+		}
+		if name != "" {
+			manifest.dependencies = append(manifest.dependencies, DeclaredDependency{Name: name, Version: version, Scope: scope, Line: lineNum})
+		}
+	}
+	sortDeclaredDependencies(manifest.dependencies)
+	return manifest
+}
+
+// sortDeclaredDependencies orders dependencies by line, then name
+func sortDeclaredDependencies(deps []DeclaredDependency) {
+	sort.Slice(deps, func(i, j int) bool {
+		if deps[i].Line != deps[j].Line {
+			return deps[i].Line < deps[j].Line
+		}
+		return deps[i].Name < deps[j].Name
+	})
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"web/package.json": `{
+  "name": "web",
+  "dependencies": {
+    "react": "^18.2.0",
+    "lodash": "^4.17.21",
+    "@scope/ui": "1.0.0",
+    "@types/react": "^18.0.0"
+  },
+  "devDependencies": {
+    "chalk": "^5.0.0",
+    "vitest": "^1.0.0"
+  }
+}
+`,
+		"web/src/app.ts": "import React from 'react';\nimport { Button } from '@scope/ui/button';\nimport axios from 'axios';\nimport chalk from 'chalk';\n" +
+			"import { readFile } from 'node:fs';\nimport path from 'path';\nimport { util } from './util';\nimport type { Options } from 'vitest';\n",
+		"web/src/util.ts":          "export const util = 1;\n",
+		"web/vite.config.ts":       "import chalk from 'chalk';\n",
+		"web/src/app.test.ts":      "import { test } from 'vitest';\nimport axios from 'axios';\n",
+		"svc/go.mod":               "module example.com/svc\n\ngo 1.24\n\nrequire (\n\tgithub.com/spf13/cobra v1.9.1\n\tgithub.com/old/unused v1.0.0\n\tgolang.org/x/sys v0.29.0 // indirect\n\tgolang.org/x/tools v0.30.0\n)\n\nrequire github.com/spf13/cobra/v2 v2.0.0\n\ntool golang.org/x/tools/cmd/stringer\n",
+		"svc/main.go":              "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/svc/internal/store\"\n\t\"github.com/google/uuid\"\n\t\"github.com/spf13/cobra/doc\"\n)\n\nfunc main() { fmt.Println(store.X, uuid.New(), doc.X) }\n",
+		"svc/internal/store/db.go": "package store\n\nvar X = 1\n",
+		"py/requirements.txt":      "requests==2.31.0  # HTTP\nPyYAML>=6.0 ; python_version >= '3.8'\ngunicorn\nunused-lib==1.0\n-e git+https://example.com/lib.git#egg=mylib\n",
+		"py/requirements-dev.txt":  "pytest\nfreezegun\n",
+		"py/app/main.py":           "import os\nimport requests\nimport yaml\nimport numpy.linalg\nfrom freezegun import freeze_time\nfrom app import util\nfrom . import helpers\nimport mylib\n",
+		"py/app/util.py":           "VALUE = 1\n",
+		"py/tests/test_main.py":    "import pytest\nfrom freezegun import freeze_time\nimport numpy\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	checks := CheckDependencies(graph, dir)
+	require.Len(t, checks, 4)
+	byFile := make(map[string]ManifestCheck)
+	for _, check := range checks {
+		byFile[check.File] = check
+	}
+
+	web := byFile["web/package.json"]
+	assert.Equal(t, EcosystemNPM, web.Ecosystem)
+	assert.Equal(t, 6, web.Declared)
+	assert.Equal(t, []DeclaredDependency{{Name: "lodash", Version: "^4.17.21", Scope: "dependencies", Line: 5}}, web.Unused,
+		"type packages and development dependencies are not reported unused")
+	require.Len(t, web.Missing, 2)
+	assert.Equal(t, UndeclaredDependency{Name: "axios", Files: 2, Uses: []DependencyUse{{File: "web/src/app.test.ts", Line: 2}, {File: "web/src/app.ts", Line: 3}}}, web.Missing[0])
+	assert.Equal(t, UndeclaredDependency{Name: "chalk", DevOnly: true, Files: 1, Uses: []DependencyUse{{File: "web/src/app.ts", Line: 4}}}, web.Missing[1],
+		"config files and type-only imports may use development dependencies")
+
+	svc := byFile["svc/go.mod"]
+	assert.Equal(t, EcosystemGo, svc.Ecosystem)
+	assert.Equal(t, 5, svc.Declared)
+	require.Len(t, svc.Unused, 2, "indirect requirements and tools are not reported unused")
+	assert.Equal(t, "github.com/old/unused", svc.Unused[0].Name)
+	assert.Equal(t, 7, svc.Unused[0].Line)
+	assert.Equal(t, "github.com/spf13/cobra/v2", svc.Unused[1].Name, "packages belong to the longest module path containing them")
+	require.Len(t, svc.Missing, 1)
+	assert.Equal(t, "github.com/google/uuid", svc.Missing[0].Name)
+
+	py := byFile["py/requirements.txt"]
+	assert.Equal(t, EcosystemPyPI, py.Ecosystem)
+	assert.Equal(t, 5, py.Declared)
+	assert.Equal(t, []DeclaredDependency{{Name: "unused-lib", Version: "==1.0", Scope: "requirements", Line: 4}}, py.Unused,
+		"distributions are matched to import names, and servers are run rather than imported")
+	require.Len(t, py.Missing, 2)
+	assert.Equal(t, "freezegun", py.Missing[0].Name)
+	assert.True(t, py.Missing[0].DevOnly)
+	assert.Equal(t, "numpy", py.Missing[1].Name)
+	assert.Equal(t, 2, py.Missing[1].Files)
+
+	dev := byFile["py/requirements-dev.txt"]
+	assert.Empty(t, dev.Unused)
+	assert.Empty(t, dev.Missing, "missing packages are reported on the first manifest covering the file")
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// DependencyHop is one step of a dependency path
+type DependencyHop struct {
+	From   string `json:"from"` // Relative to the project root
+	To     string `json:"to"`
+	Via    string `json:"via"`              // Relationship type, or "package" for files of the same Go package
+	Detail string `json:"detail,omitempty"` // Import path, or the symbols the relationship connects
+}
+
+// FindDependencyPath returns the shortest chain of dependencies leading
+// from one of the from files to one of the to files, both given as graph
+// paths, following the same steps as AnalyzeReachability. Among paths of
+// equal length the one through the alphabetically first files is chosen.
+// found is false when no path exists; a file in both sets is connected by an
+// empty path.
+func FindDependencyPath(graph *types.CodeGraph, root string, from, to []string) (hops []DependencyHop, found bool) {
+	targets := make(map[string]bool)
+	for _, path := range to {
+		targets[path] = true
+	}
+	steps := fileSteps(graph)
+
+	type arrival struct {
+		from string
+		step fileStep
+	}
+	reached := make(map[string]*arrival)
+	var queue []string
+	for _, path := range from {
+		if graph.Files[path] == nil || reached[path] != nil {
+			continue
+		}
+		if targets[path] {
+			return nil, true
+		}
+		reached[path] = &arrival{}
+		queue = append(queue, path)
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		for _, s := range steps[path] {
+			if reached[s.to] != nil {
+				continue
+			}
+			reached[s.to] = &arrival{from: path, step: s}
+			if !targets[s.to] {
+				queue = append(queue, s.to)
+				continue
+			}
+			for at := s.to; reached[at].from != ""; at = reached[at].from {
+				a := reached[at]
+				hops = append([]DependencyHop{{From: projectPath(root, a.from), To: projectPath(root, at), Via: a.step.via, Detail: a.step.detail}}, hops...)
+			}
+			return hops, true
+		}
+	}
+	return nil, false
+}
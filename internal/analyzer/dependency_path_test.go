@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDependencyPath(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                    "module example.com/app\n\ngo 1.24\n",
+		"cmd/app/main.go":           "package main\n\nimport \"example.com/app/internal/server\"\n\nfunc main() { server.Serve() }\n",
+		"internal/server/server.go": "package server\n\nfunc Serve() { routes() }\n",
+		"internal/server/routes.go": "package server\n\nimport \"example.com/app/internal/store\"\n\nfunc routes() { store.Open() }\n",
+		"internal/store/db.go":      "package store\n\nfunc Open() {}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	main := filepath.Join(dir, "cmd/app/main.go")
+	db := filepath.Join(dir, "internal/store/db.go")
+
+	hops, found := FindDependencyPath(graph, dir, []string{main}, []string{db})
+	require.True(t, found)
+	assert.Equal(t, []DependencyHop{
+		{From: "cmd/app/main.go", To: "internal/server/routes.go", Via: "imports", Detail: "example.com/app/internal/server"},
+		{From: "internal/server/routes.go", To: "internal/store/db.go", Via: "imports", Detail: "example.com/app/internal/store"},
+	}, hops)
+
+	_, found = FindDependencyPath(graph, dir, []string{db}, []string{main})
+	assert.False(t, found, "dependencies are followed one way")
+
+	hops, found = FindDependencyPath(graph, dir, []string{db}, []string{db})
+	assert.True(t, found)
+	assert.Empty(t, hops)
+}
@@ -0,0 +1,419 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+var (
+	djangoInstalledApps  = regexp.MustCompile(`(?s)\bINSTALLED_APPS\s*\+?=\s*[\[(](.*?)[\])]`)
+	djangoRootURLConf    = regexp.MustCompile(`\bROOT_URLCONF\s*=\s*["']([\w.]+)["']`)
+	djangoDottedName     = regexp.MustCompile(`["']([\w.]+)["']`)
+	djangoAppConfig      = regexp.MustCompile(`(?m)^class\s+(\w+)\(\s*(?:apps\.)?AppConfig\s*\)\s*:`)
+	djangoAppName        = regexp.MustCompile(`(?m)^\s+name\s*=\s*["']([\w.]+)["']`)
+	djangoAdminRegister  = regexp.MustCompile(`\badmin\.site\.register\(`)
+	djangoAdminDecorator = regexp.MustCompile(`(?m)^@admin\.register\(`)
+	djangoURLPattern     = regexp.MustCompile(`\b(re_path|path|url)\(`)
+	djangoRouterRegister = regexp.MustCompile(`\b(\w+)\.register\(`)
+	djangoClassName      = regexp.MustCompile(`(?m)^class\s+(\w+)`)
+	djangoAsView         = regexp.MustCompile(`\.as_view\(.*$`)
+)
+
+// djangoViewBases are the suffixes of the base classes of class-based views
+var djangoViewBases = []string{"View", "ViewSet", "APIView"}
+
+// DjangoApp is an application of a Django project
+type DjangoApp struct {
+	Name      string `json:"name"`             // Dotted name, from its AppConfig when it has one
+	Dir       string `json:"dir"`              // Relative to the project root
+	Config    string `json:"config,omitempty"` // AppConfig class
+	Installed bool   `json:"installed"`        // Listed in INSTALLED_APPS
+}
+
+// DjangoModel is a Django model with the admin registered for it
+type DjangoModel struct {
+	PythonModel
+	App   string `json:"app,omitempty"`
+	Admin string `json:"admin,omitempty"` // ModelAdmin class, "ModelAdmin" for the default one
+}
+
+// DjangoView is a function or class-based view
+type DjangoView struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"` // function or class
+	Base   string `json:"base,omitempty"`
+	App    string `json:"app,omitempty"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Routed bool   `json:"routed"` // Whether a URL pattern routes to it
+}
+
+// DjangoURL is a URL pattern and the view it routes to
+type DjangoURL struct {
+	Pattern  string `json:"pattern"` // Including the prefixes of the includes leading to it
+	Regex    bool   `json:"regex,omitempty"`
+	View     string `json:"view"` // As written, without .as_view()
+	Name     string `json:"name,omitempty"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	ViewFile string `json:"view_file,omitempty"` // Where the view is declared, when resolved
+	ViewLine int    `json:"view_line,omitempty"`
+}
+
+// DjangoReport is the structure of a Django project
+type DjangoReport struct {
+	Settings string        `json:"settings,omitempty"`
+	Apps     []DjangoApp   `json:"apps,omitempty"`
+	Models   []DjangoModel `json:"models,omitempty"`
+	Views    []DjangoView  `json:"views,omitempty"`
+	URLs     []DjangoURL   `json:"urls,omitempty"`
+}
+
+// djangoURLEntry is one entry of a urlpatterns list, or a viewset
+// registered on a REST framework router
+type djangoURLEntry struct {
+	route, view, name, router string
+	regex                     bool
+	include                   string // URL conf file an include() names
+	line                      int
+}
+
+// AnalyzeDjango maps a Django project: its apps and whether settings install
+// them, its models with the admins registered for them, its function and
+// class-based views, and the URL patterns routing to them. URL confs are
+// followed through include() from ROOT_URLCONF, or from every URL conf no
+// other includes, and REST framework router registrations are included where
+// the router's urls are. It returns nil when no file imports Django.
+func AnalyzeDjango(graph *types.CodeGraph, root string) *DjangoReport {
+	contents := make(map[string]string)
+	var paths []string
+	isDjango := false
+	for path, file := range graph.Files {
+		if file.Language != "python" || file.IsTest {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		contents[path] = string(content)
+		paths = append(paths, path)
+		if pythonFramework(string(content)) == "Django" {
+			isDjango = true
+		}
+	}
+	if !isDjango {
+		return nil
+	}
+	sort.Strings(paths)
+	scan := &middlewareScan{ra: &RelationshipAnalyzer{graph: graph}}
+	report := &DjangoReport{}
+
+	// Settings
+	installed := make(map[string]bool)
+	rootURLConf := ""
+	for _, path := range paths {
+		m := djangoInstalledApps.FindStringSubmatch(contents[path])
+		if m == nil {
+			continue
+		}
+		report.Settings = projectPath(root, path)
+		for _, app := range djangoDottedName.FindAllStringSubmatch(m[1], -1) {
+			name := app[1]
+			if idx := strings.Index(name, ".apps."); idx >= 0 {
+				name = name[:idx]
+			}
+			installed[name] = true
+		}
+		if conf := djangoRootURLConf.FindStringSubmatch(contents[path]); conf != nil {
+			rootURLConf = scan.pythonModuleFile(path, conf[1])
+		}
+		break
+	}
+
+	// Apps
+	apps := make(map[string]*DjangoApp)
+	for _, path := range paths {
+		dir := projectPath(root, filepath.Dir(path))
+		switch filepath.Base(path) {
+		case "apps.py":
+			m := djangoAppConfig.FindStringSubmatch(contents[path])
+			if m == nil {
+				continue
+			}
+			app := &DjangoApp{Name: strings.ReplaceAll(dir, "/", "."), Dir: dir, Config: m[1]}
+			if name := djangoAppName.FindStringSubmatch(contents[path]); name != nil {
+				app.Name = name[1]
+			}
+			apps[dir] = app
+		case "models.py":
+			if apps[dir] == nil {
+				apps[dir] = &DjangoApp{Name: strings.ReplaceAll(dir, "/", "."), Dir: dir}
+			}
+		}
+	}
+	for _, app := range apps {
+		app.Installed = installed[app.Name] || installed[lastSegment(app.Name)]
+		report.Apps = append(report.Apps, *app)
+	}
+	sort.Slice(report.Apps, func(i, j int) bool { return report.Apps[i].Name < report.Apps[j].Name })
+	appOf := func(file string) string {
+		best := ""
+		for dir := range apps {
+			if strings.HasPrefix(file, dir+"/") && len(dir) > len(best) {
+				best = dir
+			}
+		}
+		if best == "" {
+			return ""
+		}
+		return apps[best].Name
+	}
+
+	// Models and their admins
+	admins := make(map[string]string)
+	for _, path := range paths {
+		content := contents[path]
+		for _, loc := range djangoAdminRegister.FindAllStringIndex(content, -1) {
+			args := splitTopLevel(parenContents(content, loc[1]-1))
+			if len(args) == 0 {
+				continue
+			}
+			admin := "ModelAdmin"
+			if len(args) > 1 && !strings.Contains(args[1], "=") {
+				admin = args[1]
+			}
+			for _, model := range splitTopLevel(strings.Trim(args[0], "[]()")) {
+				admins[lastSegment(model)] = admin
+			}
+		}
+		for _, loc := range djangoAdminDecorator.FindAllStringIndex(content, -1) {
+			args := parenContents(content, loc[1]-1)
+			class := djangoClassName.FindStringSubmatch(content[loc[1]:])
+			if class == nil {
+				continue
+			}
+			for _, model := range splitTopLevel(args) {
+				if !strings.Contains(model, "=") {
+					admins[lastSegment(model)] = class[1]
+				}
+			}
+		}
+	}
+	for _, model := range pythonModels(graph, root, paths) {
+		if model.Kind == parser.PythonModelDjango {
+			report.Models = append(report.Models, DjangoModel{PythonModel: model, App: appOf(model.File), Admin: admins[model.Name]})
+		}
+	}
+
+	// Views
+	viewIndex := make(map[string]int)
+	viewClasses := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, path := range paths {
+			for _, id := range graph.Files[path].Symbols {
+				symbol := graph.Symbols[id]
+				if symbol == nil || symbol.Type != types.SymbolTypeClass || viewClasses[symbol.Name] {
+					continue
+				}
+				if djangoViewBase(symbol, viewClasses) != "" {
+					viewClasses[symbol.Name] = true
+					changed = true
+				}
+			}
+		}
+	}
+	for _, path := range paths {
+		file := projectPath(root, path)
+		for _, id := range graph.Files[path].Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil {
+				continue
+			}
+			view := DjangoView{Name: symbol.Name, App: appOf(file), File: file, Line: symbol.Location.StartLine}
+			switch {
+			case symbol.Type == types.SymbolTypeFunction && strings.HasPrefix(symbol.Signature, "(request"):
+				view.Kind = "function"
+			case symbol.Type == types.SymbolTypeClass && viewClasses[symbol.Name]:
+				view.Kind, view.Base = "class", djangoViewBase(symbol, viewClasses)
+			default:
+				continue
+			}
+			viewIndex[path+"\x00"+symbol.Name] = len(report.Views)
+			report.Views = append(report.Views, view)
+		}
+	}
+
+	// URL confs
+	entries := make(map[string][]djangoURLEntry)
+	included := make(map[string]bool)
+	var confs []string
+	for _, path := range paths {
+		content := contents[path]
+		start := strings.Index(content, "urlpatterns")
+		if start < 0 {
+			continue
+		}
+		confs = append(confs, path)
+		// Viewsets registered on a router are routed where its urls are included
+		registered := make(map[string][]djangoURLEntry)
+		var routers []string
+		for _, m := range djangoRouterRegister.FindAllStringSubmatchIndex(content, -1) {
+			router := content[m[2]:m[3]]
+			if router == "admin" || router == "site" {
+				continue
+			}
+			open := m[1] - 1
+			args := splitTopLevel(parenContents(content, open))
+			if len(args) < 2 {
+				continue
+			}
+			route, ok := unquoteRoutePath(strings.TrimLeft(args[0], "rRbBuU"))
+			if !ok {
+				continue
+			}
+			if registered[router] == nil {
+				routers = append(routers, router)
+			}
+			registered[router] = append(registered[router], djangoURLEntry{route: strings.TrimSuffix(route, "/") + "/", view: args[1], router: router, line: lineAt(content, open)})
+		}
+
+		for _, loc := range djangoURLPattern.FindAllStringSubmatchIndex(content[start:], -1) {
+			open := start + loc[1] - 1
+			args := splitTopLevel(parenContents(content, open))
+			if len(args) < 2 {
+				continue
+			}
+			route, ok := unquoteRoutePath(strings.TrimLeft(args[0], "rRbBuU"))
+			if !ok {
+				continue
+			}
+			kind := content[start+loc[2] : start+loc[3]]
+			entry := djangoURLEntry{route: route, regex: kind != "path", line: lineAt(content, open)}
+			if strings.HasPrefix(args[1], "include(") {
+				target := firstOf(splitTopLevel(parenContents(args[1], len("include"))))
+				if module := djangoDottedName.FindStringSubmatch(target); module != nil {
+					entry.include = scan.pythonModuleFile(path, module[1])
+					if entry.include != "" {
+						included[entry.include] = true
+						entries[path] = append(entries[path], entry)
+					}
+				} else if router, ok := strings.CutSuffix(target, ".urls"); ok {
+					for _, viewset := range registered[router] {
+						viewset.route = route + viewset.route
+						entries[path] = append(entries[path], viewset)
+					}
+					delete(registered, router)
+				}
+				continue
+			}
+			entry.view = strings.TrimSpace(djangoAsView.ReplaceAllString(args[1], ""))
+			for _, arg := range args[2:] {
+				if name, ok := strings.CutPrefix(arg, "name="); ok {
+					entry.name, _ = unquoteRoutePath(strings.TrimSpace(name))
+				}
+			}
+			entries[path] = append(entries[path], entry)
+		}
+		// Routers added to urlpatterns directly, as in "urlpatterns += router.urls"
+		for _, router := range routers {
+			entries[path] = append(entries[path], registered[router]...)
+		}
+	}
+
+	imports := make(map[string]map[string]importRef)
+	resolveView := func(path, expr string) (string, int) {
+		if imports[path] == nil {
+			imports[path] = scan.pythonImports(path, strings.Split(contents[path], "\n"))
+		}
+		file, name := path, expr
+		head, attribute, qualified := strings.Cut(expr, ".")
+		if ref, ok := imports[path][head]; ok {
+			switch {
+			case qualified && ref.name == "":
+				file, name = ref.file, attribute
+			case !qualified && ref.name != "":
+				file, name = ref.file, ref.name
+			default:
+				return "", 0
+			}
+		} else if qualified {
+			return "", 0
+		}
+		index, ok := viewIndex[file+"\x00"+name]
+		if !ok {
+			return "", 0
+		}
+		report.Views[index].Routed = true
+		return report.Views[index].File, report.Views[index].Line
+	}
+
+	var walk func(path, prefix string, visiting map[string]bool)
+	walk = func(path, prefix string, visiting map[string]bool) {
+		if visiting[path] {
+			return
+		}
+		visiting[path] = true
+		defer delete(visiting, path)
+		for _, entry := range entries[path] {
+			pattern := djangoJoinPattern(prefix, entry.route)
+			if entry.include != "" {
+				walk(entry.include, strings.TrimSuffix(pattern, "$"), visiting)
+				continue
+			}
+			url := DjangoURL{Pattern: pattern, Regex: entry.regex, View: entry.view, Name: entry.name, File: projectPath(root, path), Line: entry.line}
+			url.ViewFile, url.ViewLine = resolveView(path, entry.view)
+			report.URLs = append(report.URLs, url)
+		}
+	}
+	var roots []string
+	if rootURLConf != "" && entries[rootURLConf] != nil {
+		roots = append(roots, rootURLConf)
+	}
+	for _, path := range confs {
+		if !included[path] && path != rootURLConf {
+			roots = append(roots, path)
+		}
+	}
+	for _, path := range roots {
+		walk(path, "", map[string]bool{})
+	}
+
+	if len(report.Apps) == 0 && len(report.Models) == 0 && len(report.Views) == 0 && len(report.URLs) == 0 {
+		return nil
+	}
+	return report
+}
+
+// djangoViewBase returns the base class that makes a class a view: one
+// named like a Django or REST framework view, or a view of the project
+func djangoViewBase(class *types.Symbol, views map[string]bool) string {
+	for _, base := range class.MetadataStrings(parser.MetadataExtends) {
+		name := lastSegment(base)
+		if views[name] {
+			return base
+		}
+		for _, suffix := range djangoViewBases {
+			if strings.HasSuffix(name, suffix) {
+				return base
+			}
+		}
+	}
+	return ""
+}
+
+// djangoJoinPattern appends a URL pattern to the prefix of the includes
+// leading to it; regex anchors inside the joined pattern are dropped
+func djangoJoinPattern(prefix, route string) string {
+	if prefix == "" {
+		return route
+	}
+	return prefix + strings.TrimPrefix(route, "^")
+}
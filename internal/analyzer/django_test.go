@@ -0,0 +1,70 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeDjango(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"mysite/settings.py": "INSTALLED_APPS = [\n    \"django.contrib.admin\",\n    \"blog.apps.BlogConfig\",\n]\n\nROOT_URLCONF = \"mysite.urls\"\n",
+		"mysite/urls.py": "from django.contrib import admin\nfrom django.urls import include, path\n\n" +
+			"urlpatterns = [\n    path(\"admin/\", admin.site.urls),\n    path(\"blog/\", include(\"blog.urls\")),\n    path(\"api/\", include(\"blog.api\")),\n]\n",
+		"blog/apps.py": "from django.apps import AppConfig\n\n\nclass BlogConfig(AppConfig):\n    name = \"blog\"\n",
+		"blog/models.py": "from django.db import models\n\n\nclass Post(models.Model):\n    title = models.CharField(max_length=200)\n\n\n" +
+			"class Comment(models.Model):\n    post = models.ForeignKey(Post, on_delete=models.CASCADE)\n\n\nclass Tag(models.Model):\n    name = models.CharField(max_length=20)\n",
+		"blog/admin.py": "from django.contrib import admin\nfrom .models import Comment, Post\n\n\n" +
+			"@admin.register(Post)\nclass PostAdmin(admin.ModelAdmin):\n    list_display = [\"title\"]\n\n\nadmin.site.register(Comment)\n",
+		"blog/views.py": "from django.views.generic import ListView\nfrom django.shortcuts import render\n\n\n" +
+			"def post_detail(request, pk):\n    return render(request, \"post.html\")\n\n\n" +
+			"class PostList(ListView):\n    model = None\n\n\ndef archive(request):\n    return None\n",
+		"blog/urls.py": "from django.urls import path\nfrom . import views\n\n" +
+			"urlpatterns = [\n    path(\"\", views.PostList.as_view(), name=\"post-list\"),\n    path(\"<int:pk>/\", views.post_detail, name=\"post-detail\"),\n]\n",
+		"blog/api.py": "from django.urls import include, path\nfrom rest_framework import routers, viewsets\n\n\n" +
+			"class PostViewSet(viewsets.ModelViewSet):\n    queryset = None\n\n\nrouter = routers.DefaultRouter()\nrouter.register(r\"posts\", PostViewSet)\n\n" +
+			"urlpatterns = [\n    path(\"v1/\", include(router.urls)),\n]\n",
+		"notes/models.py": "from django.db import models\n\n\nclass Note(models.Model):\n    text = models.TextField()\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	report := AnalyzeDjango(graph, dir)
+	require.NotNil(t, report)
+	assert.Equal(t, "mysite/settings.py", report.Settings)
+	assert.Equal(t, []DjangoApp{
+		{Name: "blog", Dir: "blog", Config: "BlogConfig", Installed: true},
+		{Name: "notes", Dir: "notes"},
+	}, report.Apps)
+
+	admins := make(map[string]string)
+	for _, model := range report.Models {
+		admins[model.Name] = model.Admin
+		if model.Name == "Comment" {
+			assert.Equal(t, "blog", model.App)
+			assert.Equal(t, []string{"post: ForeignKey"}, model.Fields)
+		}
+	}
+	assert.Equal(t, map[string]string{"Post": "PostAdmin", "Comment": "ModelAdmin", "Tag": "", "Note": ""}, admins)
+
+	assert.Equal(t, []DjangoURL{
+		{Pattern: "admin/", View: "admin.site.urls", File: "mysite/urls.py", Line: 5},
+		{Pattern: "blog/", View: "views.PostList", Name: "post-list", File: "blog/urls.py", Line: 5, ViewFile: "blog/views.py", ViewLine: 9},
+		{Pattern: "blog/<int:pk>/", View: "views.post_detail", Name: "post-detail", File: "blog/urls.py", Line: 6, ViewFile: "blog/views.py", ViewLine: 5},
+		{Pattern: "api/v1/posts/", View: "PostViewSet", File: "blog/api.py", Line: 10, ViewFile: "blog/api.py", ViewLine: 5},
+	}, report.URLs)
+
+	routed := make(map[string]bool)
+	for _, view := range report.Views {
+		routed[view.Name] = view.Routed
+		if view.Name == "PostList" {
+			assert.Equal(t, "class", view.Kind)
+			assert.Equal(t, "ListView", view.Base)
+		}
+	}
+	assert.Equal(t, map[string]bool{"post_detail": true, "PostList": true, "archive": false, "PostViewSet": true}, routed)
+}
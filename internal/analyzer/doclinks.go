@@ -0,0 +1,310 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipDocuments links a markdown document to the code it describes
+const RelationshipDocuments RelationshipType = "documents"
+
+// maxDocSymbolCandidates skips code spans whose name matches too many symbols to be meaningful
+const maxDocSymbolCandidates = 3
+
+var (
+	docLinkPattern     = regexp.MustCompile(`!?\[([^\]]*)\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	docLinkDefPattern  = regexp.MustCompile(`^\s{0,3}\[([^\]]+)\]:\s*<?(\S+?)>?(?:\s+"[^"]*")?\s*$`)
+	docCodeSpanPattern = regexp.MustCompile("`([^`\n]+)`")
+	docIdentPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// docReferenceKind distinguishes explicit links from inline code mentions
+type docReferenceKind string
+
+const (
+	docReferenceLink docReferenceKind = "link"
+	docReferenceCode docReferenceKind = "code_reference"
+)
+
+// docReference is a candidate pointer from a markdown document into the codebase
+type docReference struct {
+	Kind   docReferenceKind
+	Target string // Link target or code span contents
+	Text   string // Link text or the code span itself
+	Line   int
+}
+
+// DocumentationReference describes a markdown document that refers to a file or one of its symbols
+type DocumentationReference struct {
+	DocPath string `json:"doc_path"`
+	Line    int    `json:"line"`
+	Kind    string `json:"kind"`             // "link" or "code_reference"
+	Text    string `json:"text,omitempty"`   // Link text or code span
+	Symbol  string `json:"symbol,omitempty"` // Referenced symbol, empty for whole-file references
+}
+
+// SetDocFiles sets the markdown documents scanned for links into the codebase.
+// Root-relative links ("/docs/x.go") are resolved against root.
+func (ra *RelationshipAnalyzer) SetDocFiles(root string, docFiles []string) {
+	ra.docRoot = root
+	ra.docFiles = docFiles
+}
+
+// analyzeDocumentationLinks creates doc→code edges from markdown links and code spans
+func (ra *RelationshipAnalyzer) analyzeDocumentationLinks(metrics *RelationshipMetrics) {
+	if len(ra.docFiles) == 0 {
+		return
+	}
+
+	symbolsByName := make(map[string][]*types.Symbol)
+	for _, symbol := range ra.graph.Symbols {
+		if symbol.Type == types.SymbolTypeImport || symbol.Type == types.SymbolTypeVariable {
+			continue
+		}
+		symbolsByName[symbol.Name] = append(symbolsByName[symbol.Name], symbol)
+	}
+
+	for _, docPath := range ra.docFiles {
+		content, err := os.ReadFile(docPath)
+		if err != nil {
+			continue
+		}
+
+		from := types.NodeId(fmt.Sprintf("file-%s", docPath))
+		for _, ref := range extractDocReferences(string(content)) {
+			var to types.NodeId
+			var weight float64
+			switch ref.Kind {
+			case docReferenceLink:
+				target := ra.resolveDocPath(ref.Target, docPath)
+				if target == "" {
+					continue
+				}
+				to = types.NodeId(fmt.Sprintf("file-%s", target))
+				weight = 1.0
+			case docReferenceCode:
+				if target := ra.resolveDocCodeSpan(ref.Target, docPath); target != "" {
+					to = types.NodeId(fmt.Sprintf("file-%s", target))
+				} else if symbol := lookupDocSymbol(symbolsByName, ref.Target); symbol != nil {
+					to = types.NodeId(fmt.Sprintf("symbol-%s", symbol.Id))
+				} else {
+					continue
+				}
+				weight = 0.5
+			}
+
+			edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", RelationshipDocuments, from, to))
+			if _, exists := ra.graph.Edges[edgeId]; exists {
+				continue // Keep the first mention in the document
+			}
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   from,
+				To:     to,
+				Type:   string(RelationshipDocuments),
+				Weight: weight,
+				Metadata: map[string]interface{}{
+					"doc_path": docPath,
+					"line":     ref.Line,
+					"kind":     string(ref.Kind),
+					"text":     ref.Text,
+				},
+			}
+			metrics.ByType[RelationshipDocuments]++
+		}
+	}
+}
+
+// extractDocReferences finds links and inline code spans in markdown, skipping fenced code blocks
+func extractDocReferences(content string) []docReference {
+	var refs []docReference
+	fence := ""
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		lineNum := i + 1
+		if match := docLinkDefPattern.FindStringSubmatch(line); match != nil {
+			refs = append(refs, docReference{Kind: docReferenceLink, Target: match[2], Text: match[1], Line: lineNum})
+			continue
+		}
+		for _, match := range docLinkPattern.FindAllStringSubmatch(line, -1) {
+			refs = append(refs, docReference{Kind: docReferenceLink, Target: match[2], Text: match[1], Line: lineNum})
+		}
+		// Code spans inside link text are already covered by the link itself
+		for _, match := range docCodeSpanPattern.FindAllStringSubmatch(docLinkPattern.ReplaceAllString(line, ""), -1) {
+			span := strings.TrimSpace(match[1])
+			refs = append(refs, docReference{Kind: docReferenceCode, Target: span, Text: span, Line: lineNum})
+		}
+	}
+	return refs
+}
+
+// resolveDocPath resolves a markdown link target to a file in the graph
+func (ra *RelationshipAnalyzer) resolveDocPath(target, docPath string) string {
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") || strings.HasPrefix(target, "#") {
+		return ""
+	}
+	if i := strings.IndexAny(target, "?#"); i != -1 {
+		target = target[:i]
+	}
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	if target == "" {
+		return ""
+	}
+
+	var candidate string
+	if strings.HasPrefix(target, "/") {
+		if ra.docRoot == "" {
+			return ""
+		}
+		candidate = filepath.Join(ra.docRoot, filepath.FromSlash(target))
+	} else {
+		candidate = filepath.Join(filepath.Dir(docPath), filepath.FromSlash(target))
+	}
+	if _, exists := ra.graph.Files[candidate]; exists {
+		return candidate
+	}
+	return ""
+}
+
+// resolveDocCodeSpan resolves a path-like code span (e.g. `internal/mcp/server.go`) to a file
+func (ra *RelationshipAnalyzer) resolveDocCodeSpan(span, docPath string) string {
+	if strings.ContainsAny(span, " \t") || filepath.Ext(span) == "" {
+		return ""
+	}
+	// Strip trailing line references such as server.go:42
+	if i := strings.LastIndex(span, ":"); i > 0 && !strings.HasPrefix(span[i:], ":/") {
+		span = span[:i]
+	}
+	if resolved := ra.resolveDocPath(span, docPath); resolved != "" {
+		return resolved
+	}
+	if ra.docRoot != "" {
+		candidate := filepath.Join(ra.docRoot, filepath.FromSlash(strings.TrimPrefix(span, "./")))
+		if _, exists := ra.graph.Files[candidate]; exists {
+			return candidate
+		}
+	}
+
+	// Fall back to a unique suffix match so bare file names like `graph.go` still link
+	suffix := string(filepath.Separator) + filepath.FromSlash(strings.TrimPrefix(span, "./"))
+	match := ""
+	for path := range ra.graph.Files {
+		if strings.HasSuffix(path, suffix) {
+			if match != "" {
+				return "" // Ambiguous
+			}
+			match = path
+		}
+	}
+	return match
+}
+
+// lookupDocSymbol resolves a code span such as `Parse()`, `pkg.Parse` or `Type::method` to a symbol
+func lookupDocSymbol(symbolsByName map[string][]*types.Symbol, span string) *types.Symbol {
+	name := strings.TrimSuffix(span, "()")
+	if i := strings.LastIndexAny(name, ".:#"); i != -1 {
+		name = name[i+1:]
+	}
+	if len(name) < 3 || !docIdentPattern.MatchString(name) {
+		return nil
+	}
+
+	candidates := symbolsByName[name]
+	if len(candidates) == 0 || len(candidates) > maxDocSymbolCandidates {
+		return nil
+	}
+	// Prefer declarations over members so `Parser` links the type rather than a field
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if docSymbolRank(candidate) < docSymbolRank(best) ||
+			(docSymbolRank(candidate) == docSymbolRank(best) && candidate.Id < best.Id) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// docSymbolRank orders symbol types by how likely documentation is to mean them
+func docSymbolRank(symbol *types.Symbol) int {
+	switch symbol.Type {
+	case types.SymbolTypeClass, types.SymbolTypeInterface, types.SymbolTypeType:
+		return 0
+	case types.SymbolTypeFunction:
+		return 1
+	case types.SymbolTypeMethod:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// DocumentationFor returns the markdown documents that link to filePath or mention its symbols
+func DocumentationFor(graph *types.CodeGraph, filePath string) []DocumentationReference {
+	fileNode, exists := graph.Files[filePath]
+	if !exists {
+		return nil
+	}
+
+	targets := map[types.NodeId]string{
+		types.NodeId(fmt.Sprintf("file-%s", filePath)): "",
+	}
+	for _, symbolId := range fileNode.Symbols {
+		if symbol, ok := graph.Symbols[symbolId]; ok {
+			targets[types.NodeId(fmt.Sprintf("symbol-%s", symbolId))] = symbol.Name
+		}
+	}
+
+	var refs []DocumentationReference
+	for _, edge := range graph.Edges {
+		if edge.Type != string(RelationshipDocuments) {
+			continue
+		}
+		symbolName, ok := targets[edge.To]
+		if !ok {
+			continue
+		}
+		ref := DocumentationReference{
+			DocPath: strings.TrimPrefix(string(edge.From), "file-"),
+			Symbol:  symbolName,
+		}
+		if line, ok := edge.Metadata["line"].(int); ok {
+			ref.Line = line
+		}
+		if kind, ok := edge.Metadata["kind"].(string); ok {
+			ref.Kind = kind
+		}
+		if text, ok := edge.Metadata["text"].(string); ok {
+			ref.Text = text
+		}
+		refs = append(refs, ref)
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].DocPath != refs[j].DocPath {
+			return refs[i].DocPath < refs[j].DocPath
+		}
+		return refs[i].Line < refs[j].Line
+	})
+	return refs
+}
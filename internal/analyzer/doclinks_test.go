@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractDocReferences(t *testing.T) {
+	content := "# Guide\n" +
+		"See [the parser](src/parser.go#L10) and ![diagram](docs/arch.png).\n" +
+		"Call `ParseFile()` to start.\n" +
+		"```go\n" +
+		"// [ignored](src/ignored.go) `Ignored`\n" +
+		"```\n" +
+		"[ref]: ./src/util.go \"Utilities\"\n"
+
+	refs := extractDocReferences(content)
+	require.Len(t, refs, 4)
+
+	assert.Equal(t, docReference{Kind: docReferenceLink, Target: "src/parser.go#L10", Text: "the parser", Line: 2}, refs[0])
+	assert.Equal(t, docReference{Kind: docReferenceLink, Target: "docs/arch.png", Text: "diagram", Line: 2}, refs[1])
+	assert.Equal(t, docReference{Kind: docReferenceCode, Target: "ParseFile()", Text: "ParseFile()", Line: 3}, refs[2])
+	assert.Equal(t, docReference{Kind: docReferenceLink, Target: "./src/util.go", Text: "ref", Line: 7}, refs[3])
+}
+
+func TestDocumentationLinks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/parser.go": "package src\n\n// ParseFile parses a file\nfunc ParseFile(path string) error {\n\treturn nil\n}\n",
+		"src/util.go":   "package src\n\nfunc helper() {}\n",
+		"README.md":     "# Project\n\nStart with [the parser](src/parser.go).\n",
+		"docs/guide.md": "# Guide\n\nCall `ParseFile()` or read `util.go`.\nExternal: [site](https://example.com).\n",
+	}
+	testutils.WriteTree(t, dir, files)
+
+	builder := NewGraphBuilder()
+	graph, err := builder.AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	parserPath := filepath.Join(dir, "src", "parser.go")
+	utilPath := filepath.Join(dir, "src", "util.go")
+	_, mdIndexed := graph.Files[filepath.Join(dir, "README.md")]
+	assert.False(t, mdIndexed, "markdown documents should not be added as source files")
+
+	docs := DocumentationFor(graph, parserPath)
+	require.Len(t, docs, 2)
+	assert.Equal(t, filepath.Join(dir, "README.md"), docs[0].DocPath)
+	assert.Equal(t, "link", docs[0].Kind)
+	assert.Equal(t, "the parser", docs[0].Text)
+	assert.Equal(t, 3, docs[0].Line)
+	assert.Equal(t, filepath.Join(dir, "docs", "guide.md"), docs[1].DocPath)
+	assert.Equal(t, "ParseFile", docs[1].Symbol)
+
+	docs = DocumentationFor(graph, utilPath)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "code_reference", docs[0].Kind)
+	assert.Equal(t, "util.go", docs[0].Text)
+
+	metrics, ok := graph.Metadata.Configuration["relationship_metrics"].(*RelationshipMetrics)
+	require.True(t, ok)
+	assert.Equal(t, 3, metrics.ByType[RelationshipDocuments])
+}
@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"hash/fnv"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// EmbeddingDimensions is the length of each symbol vector
+const EmbeddingDimensions = 256
+
+// embeddingMaxBodyLines bounds the body read for symbols without an end line
+const embeddingMaxBodyLines = 50
+
+// Weights of the places a word appears in, so names count for more than bodies
+const (
+	embeddingNameWeight      = 3
+	embeddingSignatureWeight = 2
+	embeddingDocWeight       = 2
+	embeddingBodyWeight      = 1
+)
+
+// embeddingStopWords are keywords and common identifiers that say nothing about a symbol
+var embeddingStopWords = map[string]bool{
+	"func": true, "function": true, "def": true, "return": true, "if": true, "else": true,
+	"for": true, "var": true, "let": true, "const": true, "self": true, "this": true,
+	"the": true, "and": true, "to": true, "of": true, "in": true, "is": true, "nil": true,
+	"null": true, "true": true, "false": true, "err": true, "error": true, "string": true,
+	"int": true, "new": true, "public": true, "private": true, "static": true, "void": true,
+}
+
+// EmbeddingIndex holds a vector per symbol built from the words of its name,
+// signature, documentation and body. Words are hashed into a fixed number of
+// dimensions, so vectors are comparable without a trained model or network
+// access. The deep profile stores the index under "embeddings" in the graph
+// metadata. Vectors are left out of saved graphs, which would otherwise grow
+// by a kilobyte per symbol.
+type EmbeddingIndex struct {
+	Dimensions int                          `json:"dimensions"`
+	Vectors    map[types.SymbolId][]float32 `json:"-"`
+}
+
+// SimilarSymbol is a symbol ranked by the cosine similarity of its vector
+type SimilarSymbol struct {
+	Id    types.SymbolId `json:"id"`
+	Score float64        `json:"score"`
+}
+
+// Embeddings returns the embedding index of a graph analyzed with the deep
+// profile, or nil
+func Embeddings(graph *types.CodeGraph) *EmbeddingIndex {
+	if graph == nil || graph.Metadata == nil {
+		return nil
+	}
+	index, _ := graph.Metadata.Configuration["embeddings"].(*EmbeddingIndex)
+	return index
+}
+
+// BuildEmbeddings embeds every symbol of the graph except imports. Symbol
+// bodies are read from disk once per file; unreadable files embed from the
+// name, signature and documentation alone.
+func BuildEmbeddings(graph *types.CodeGraph) *EmbeddingIndex {
+	index := &EmbeddingIndex{
+		Dimensions: EmbeddingDimensions,
+		Vectors:    make(map[types.SymbolId][]float32),
+	}
+	for path, file := range graph.Files {
+		var lines []string
+		if content, err := os.ReadFile(path); err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+		var starts []int
+		for _, id := range file.Symbols {
+			if symbol := graph.Symbols[id]; symbol != nil {
+				starts = append(starts, symbol.Location.StartLine)
+			}
+		}
+		sort.Ints(starts)
+		for _, id := range file.Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil || symbol.Type == types.SymbolTypeImport {
+				continue
+			}
+			body := symbolBody(symbol, lines, starts)
+			if vector := embedSymbol(symbol, body); vector != nil {
+				index.Vectors[id] = vector
+			}
+		}
+	}
+	return index
+}
+
+// symbolBody returns the lines after a symbol's declaration line. Parsers that
+// record no end line leave the body to run until the next symbol of the file.
+func symbolBody(symbol *types.Symbol, lines []string, starts []int) string {
+	start, end := symbol.Location.StartLine, symbol.Location.EndLine
+	if start <= 0 || start > len(lines) {
+		return ""
+	}
+	if end <= start {
+		end = start + embeddingMaxBodyLines
+		if next := sort.SearchInts(starts, start+1); next < len(starts) && starts[next]-1 < end {
+			end = starts[next] - 1
+		}
+	}
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// embedSymbol hashes the weighted words of a symbol and its body into a unit
+// vector, or returns nil when there are no words
+func embedSymbol(symbol *types.Symbol, body string) []float32 {
+	vector := make([]float32, EmbeddingDimensions)
+	add := func(text string, weight float32) {
+		for _, word := range identifierWords(text) {
+			h := fnv.New32a()
+			h.Write([]byte(word))
+			sum := h.Sum32()
+			// The top bit picks a sign so colliding words tend to cancel out
+			if sum&(1<<31) != 0 {
+				vector[sum%EmbeddingDimensions] -= weight
+			} else {
+				vector[sum%EmbeddingDimensions] += weight
+			}
+		}
+	}
+	add(symbol.Name, embeddingNameWeight)
+	add(symbol.Signature, embeddingSignatureWeight)
+	add(symbol.Documentation, embeddingDocWeight)
+	add(body, embeddingBodyWeight)
+
+	var norm float64
+	for _, v := range vector {
+		norm += float64(v) * float64(v)
+	}
+	if norm == 0 {
+		return nil
+	}
+	scale := float32(1 / math.Sqrt(norm))
+	for i := range vector {
+		vector[i] *= scale
+	}
+	return vector
+}
+
+// identifierWords splits text into lowercase words, breaking identifiers at
+// underscores, digits and camelCase humps: "parseHTTPRequest_v2" gives
+// "parse", "http" and "request"
+func identifierWords(text string) []string {
+	var words []string
+	runes := []rune(text)
+	flush := func(start, end int) {
+		if end-start < 2 {
+			return
+		}
+		word := strings.ToLower(string(runes[start:end]))
+		if !embeddingStopWords[word] {
+			words = append(words, word)
+		}
+	}
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) {
+			if start >= 0 {
+				flush(start, i)
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		// A hump starts at a capital after a lowercase letter, or at the last
+		// capital of an acronym followed by a lowercase letter
+		prev := runes[i-1]
+		if unicode.IsUpper(r) && (unicode.IsLower(prev) ||
+			unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			flush(start, i)
+			start = i
+		}
+	}
+	if start >= 0 {
+		flush(start, len(runes))
+	}
+	return words
+}
+
+// Similar returns up to limit symbols whose vectors are closest to the vector
+// of id, most similar first. Symbols scoring zero or less are left out.
+func (idx *EmbeddingIndex) Similar(id types.SymbolId, limit int) []SimilarSymbol {
+	if idx == nil {
+		return nil
+	}
+	target := idx.Vectors[id]
+	if target == nil {
+		return nil
+	}
+	var similar []SimilarSymbol
+	for other, vector := range idx.Vectors {
+		if other == id {
+			continue
+		}
+		var dot float64
+		for i, v := range vector {
+			dot += float64(v) * float64(target[i])
+		}
+		if dot > 0 {
+			similar = append(similar, SimilarSymbol{Id: other, Score: dot})
+		}
+	}
+	sort.Slice(similar, func(i, j int) bool {
+		if similar[i].Score != similar[j].Score {
+			return similar[i].Score > similar[j].Score
+		}
+		return similar[i].Id < similar[j].Id
+	})
+	if limit > 0 && len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar
+}
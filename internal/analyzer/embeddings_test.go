@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+func TestIdentifierWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"parseHTTPRequest_v2", []string{"parse", "http", "request"}},
+		{"user_account_id", []string{"user", "account", "id"}},
+		{"func (s *Server) Start() error", []string{"server", "start"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := identifierWords(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("identifierWords(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestBuildEmbeddingsSimilar(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := `package billing
+
+func ChargeInvoice(invoice Invoice) {
+	total := invoice.Total()
+	gateway.Charge(invoice.Customer, total)
+}
+
+func RefundInvoice(invoice Invoice) {
+	total := invoice.Total()
+	gateway.Refund(invoice.Customer, total)
+}
+
+func RenderLogo(canvas Canvas) {
+	canvas.DrawImage(logoPixels)
+}
+`
+	path := filepath.Join(tmpDir, "billing.go")
+	if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	graph := &types.CodeGraph{
+		Files:   map[string]*types.FileNode{path: {Path: path, Symbols: []types.SymbolId{"charge", "refund", "logo", "import"}}},
+		Symbols: map[types.SymbolId]*types.Symbol{},
+	}
+	for id, symbol := range map[types.SymbolId]*types.Symbol{
+		"charge": {Name: "ChargeInvoice", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 3}},
+		"refund": {Name: "RefundInvoice", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 8}},
+		"logo":   {Name: "RenderLogo", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 13}},
+		"import": {Name: "fmt", Type: types.SymbolTypeImport, Location: types.Location{StartLine: 1}},
+	} {
+		symbol.Id = id
+		graph.Symbols[id] = symbol
+	}
+
+	index := BuildEmbeddings(graph)
+	if len(index.Vectors) != 3 {
+		t.Fatalf("expected 3 vectors without the import, got %d", len(index.Vectors))
+	}
+	similar := index.Similar("charge", 5)
+	if len(similar) == 0 || similar[0].Id != "refund" {
+		t.Fatalf("expected RefundInvoice to be most similar to ChargeInvoice, got %v", similar)
+	}
+	for _, match := range similar {
+		if match.Id == "logo" && match.Score >= similar[0].Score {
+			t.Errorf("RenderLogo should rank below RefundInvoice: %v", similar)
+		}
+	}
+	if got := index.Similar("charge", 1); len(got) != 1 {
+		t.Errorf("limit not applied: %v", got)
+	}
+	if got := index.Similar("missing", 5); got != nil {
+		t.Errorf("unknown symbol should have no similar symbols, got %v", got)
+	}
+}
@@ -0,0 +1,182 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Entry point kinds
+const (
+	EntryPointMain    = "main"    // Program mains: func main, if __name__ == "__main__", static void main
+	EntryPointCLI     = "cli"     // Command-line parsers: cobra, urfave/cli, click, argparse, commander, clap
+	EntryPointHandler = "handler" // Serverless handlers: AWS Lambda, Google Cloud Functions
+	EntryPointServer  = "server"  // Server bootstrap: ListenAndServe, app.listen, uvicorn.run, SpringApplication.run
+)
+
+// entryPointPattern recognizes one kind of entry point in some languages. A
+// first capture group, when present and matched, names the entry function.
+type entryPointPattern struct {
+	kind      string
+	languages []string
+	re        *regexp.Regexp
+	declares  string // Function the line declares, which must be parsed there
+	topLevel  bool   // Matched against unindented lines only
+}
+
+var entryPointPatterns = []entryPointPattern{
+	// Program mains
+	{EntryPointMain, []string{"go"}, regexp.MustCompile(`^func main\(\)`), "main", true},
+	{EntryPointMain, []string{"python"}, regexp.MustCompile(`^if\s+__name__\s*==\s*["']__main__["']`), "", true},
+	{EntryPointMain, []string{"rust"}, regexp.MustCompile(`^(?:pub\s+)?(?:async\s+)?fn main\(\)`), "main", true},
+	{EntryPointMain, []string{"java", "kotlin"}, regexp.MustCompile(`\bstatic\s+void\s+main\s*\(|^fun main\(`), "main", false},
+	{EntryPointMain, []string{"cpp"}, regexp.MustCompile(`^int\s+main\s*\(`), "main", true},
+	{EntryPointMain, []string{"dart"}, regexp.MustCompile(`^(?:Future<void>|void)?\s*main\s*\(`), "main", true},
+	{EntryPointMain, []string{"javascript", "typescript"}, regexp.MustCompile(`\brequire\.main\s*===?\s*module\b`), "", false},
+
+	// Command-line interfaces
+	{EntryPointCLI, []string{"go"}, regexp.MustCompile(`\b\w*(?:Cmd|Command)\.Execute(?:Context)?\(|&cli\.App\{|\.Run\((?:context\.\w+\(\)\s*,\s*)?os\.Args\)`), "", false},
+	{EntryPointCLI, []string{"python"}, regexp.MustCompile(`^@click\.(?:command|group)\b|\bargparse\.ArgumentParser\(|\btyper\.Typer\(`), "", false},
+	{EntryPointCLI, []string{"javascript", "typescript"}, regexp.MustCompile(`\bprogram\s*\.parse(?:Async)?\(|\byargs\((?:process\.argv|hideBin)`), "", false},
+	{EntryPointCLI, []string{"rust"}, regexp.MustCompile(`#\[derive\([^)]*\bParser\b`), "", false},
+	{EntryPointCLI, []string{"java", "kotlin"}, regexp.MustCompile(`@(?:picocli\.CommandLine\.)?Command\(`), "", false},
+
+	// Serverless handlers
+	{EntryPointHandler, []string{"go"}, regexp.MustCompile(`\blambda\.Start(?:WithOptions)?\(|\bfunctions\.(?:HTTP|CloudEvent)\(`), "", false},
+	{EntryPointHandler, []string{"python"}, regexp.MustCompile(`^def\s+(\w*handler)\s*\(\s*event\s*,\s*context\b|^@functions_framework\.(?:http|cloud_event)\b`), "", false},
+	{EntryPointHandler, []string{"javascript", "typescript"}, regexp.MustCompile(`^export\s+(?:const|async\s+function|function)\s+(handler)\b|\b(?:module\.)?exports\.(handler)\s*=|\bfunctions\.(?:http|cloudEvent)\(`), "", false},
+	{EntryPointHandler, []string{"java", "kotlin"}, regexp.MustCompile(`\bRequestHandler<|\bRequestStreamHandler\b`), "", false},
+	{EntryPointHandler, []string{"rust"}, regexp.MustCompile(`\blambda_runtime::run\(|\blambda_http::run\(`), "", false},
+
+	// Server bootstrap
+	{EntryPointServer, []string{"go"}, regexp.MustCompile(`\bListenAndServe(?:TLS)?\(|\bgrpc\.NewServer\(`), "", false},
+	{EntryPointServer, []string{"python"}, regexp.MustCompile(`\buvicorn\.run\(|\bweb\.run_app\(|\bapp\.run\(|\bserve_forever\(`), "", false},
+	{EntryPointServer, []string{"javascript", "typescript"}, regexp.MustCompile(`\b(?:app|server|fastify|httpServer)\.listen\(|\bNestFactory\.create\(|\bBun\.serve\(|\bDeno\.serve\(`), "", false},
+	{EntryPointServer, []string{"java", "kotlin"}, regexp.MustCompile(`\bSpringApplication\.run\(|\brunApplication<|\bembeddedServer\(`), "", false},
+	{EntryPointServer, []string{"rust"}, regexp.MustCompile(`\bHttpServer::new\(|\baxum::serve\(|\bServer::bind\(|\bwarp::serve\(`), "", false},
+}
+
+// entryPointLanguages are the languages entry point detection looks at
+var entryPointLanguages = map[string]bool{}
+
+func init() {
+	for _, pattern := range entryPointPatterns {
+		for _, language := range pattern.languages {
+			entryPointLanguages[language] = true
+		}
+	}
+}
+
+// EntryPoint is where a program, command, handler or server starts
+type EntryPoint struct {
+	File      string   `json:"file"`
+	Package   string   `json:"package"` // Directory relative to the project root, "." for the root
+	Kind      string   `json:"kind"`
+	Function  string   `json:"function,omitempty"` // Enclosing function, when the entry point is inside one
+	Line      int      `json:"line"`
+	Statement string   `json:"statement"` // The matching line, trimmed
+	WiresUp   []string `json:"wires_up"`  // Packages the entry point's package depends on
+}
+
+// codeMatch returns the submatches of the first match of re in text starting
+// in code, given the same text with comments and string literals blanked out
+func codeMatch(re *regexp.Regexp, text, code string) []string {
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		if loc[0] < len(code) && code[loc[0]] != text[loc[0]] {
+			continue // Inside a comment or string literal
+		}
+		m := make([]string, len(loc)/2)
+		for g := range m {
+			if loc[2*g] >= 0 {
+				m[g] = text[loc[2*g]:loc[2*g+1]]
+			}
+		}
+		return m
+	}
+	return nil
+}
+
+// FindEntryPoints finds program mains, command-line parsers, serverless
+// handlers and server bootstrap code, at most one of each kind per file, and
+// the packages each one wires up: those its package depends on. Test files
+// are skipped.
+func FindEntryPoints(graph *types.CodeGraph) []EntryPoint {
+	ra := &RelationshipAnalyzer{graph: graph}
+	var entryPoints []EntryPoint
+	ra.forEachSourceFileIn(entryPointLanguages, func(filePath, content string) {
+		language := graph.Files[filePath].Language
+		found := make(map[string]bool)
+		code := strings.Split(maskLiterals(content, language), "\n")
+		for i, line := range strings.Split(content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#!") {
+				continue
+			}
+			for _, pattern := range entryPointPatterns {
+				if found[pattern.kind] || !slices.Contains(pattern.languages, language) {
+					continue
+				}
+				text, offset := trimmed, strings.Index(line, trimmed)
+				if pattern.topLevel {
+					text, offset = line, 0
+				}
+				m := codeMatch(pattern.re, text, code[i][offset:])
+				if m == nil {
+					continue
+				}
+				entryPoint := EntryPoint{File: filePath, Kind: pattern.kind, Line: i + 1, Statement: trimmed}
+				symbol := ra.enclosingSymbol(filePath, i+1)
+				switch {
+				case pattern.declares != "":
+					// Declarations inside string literals have no symbol of their own
+					if symbol == nil || symbol.Name != pattern.declares {
+						continue
+					}
+					entryPoint.Function = symbol.Name
+				case len(m) > 1 && strings.Join(m[1:], "") != "":
+					entryPoint.Function = strings.Join(m[1:], "")
+				case symbol != nil && symbol.Location.StartLine < i+1 && trimmed != line:
+					// Indented statements run inside the function declared above
+					entryPoint.Function = symbol.Name
+				}
+				found[pattern.kind] = true
+				entryPoints = append(entryPoints, entryPoint)
+			}
+		}
+	})
+	if len(entryPoints) == 0 {
+		return nil
+	}
+
+	var paths []string
+	for path, file := range graph.Files {
+		if !file.IsTest {
+			paths = append(paths, path)
+		}
+	}
+	root := commonDir(paths)
+	dependencies := make(map[string][]string)
+	for _, pkg := range AnalyzeCoupling(graph) {
+		dependencies[pkg.Package] = pkg.Dependencies
+	}
+	for i := range entryPoints {
+		pkg := filepath.Dir(entryPoints[i].File)
+		if rel, err := filepath.Rel(root, pkg); err == nil {
+			pkg = rel
+		}
+		entryPoints[i].Package = filepath.ToSlash(pkg)
+		entryPoints[i].WiresUp = dependencies[entryPoints[i].Package]
+	}
+
+	sort.Slice(entryPoints, func(i, j int) bool {
+		if entryPoints[i].File != entryPoints[j].File {
+			return entryPoints[i].File < entryPoints[j].File
+		}
+		return entryPoints[i].Line < entryPoints[j].Line
+	})
+	return entryPoints
+}
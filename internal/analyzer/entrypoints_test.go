@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindEntryPoints(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/shop\n\ngo 1.22\n",
+		"cmd/shop/main.go":      "package main\n\nimport (\n\t\"net/http\"\n\n\t\"example.com/shop/orders\"\n)\n\nfunc main() {\n\thttp.Handle(\"/\", orders.Handler())\n\thttp.ListenAndServe(\":8080\", nil)\n}\n",
+		"orders/orders.go":      "package orders\n\nimport \"net/http\"\n\nfunc Handler() http.Handler {\n\treturn nil\n}\n\nconst template = `\nfunc main() {\n}\n`\n",
+		"lambda/handler.py":     "import json\n\ndef lambda_handler(event, context):\n    return json.dumps(event)\n",
+		"tools/report.py":       "import argparse\n\ndef run():\n    parser = argparse.ArgumentParser()\n    parser.parse_args()\n\nif __name__ == \"__main__\":\n    run()\n",
+		"web/server.ts":         "import express from 'express';\n\nconst app = express();\napp.listen(3000);\n",
+		"api/index.js":          "exports.handler = async (event) => {\n  return { statusCode: 200 };\n};\n",
+		"orders/orders_test.go": "package orders\n\nimport \"testing\"\n\nfunc TestMain(m *testing.M) {\n\tm.Run()\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	var found []string
+	for _, entryPoint := range FindEntryPoints(graph) {
+		found = append(found, entryPoint.Package+"/"+filepath.Base(entryPoint.File)+" "+entryPoint.Kind+" "+entryPoint.Function)
+		if entryPoint.Kind == EntryPointMain && entryPoint.Package == "cmd/shop" {
+			assert.Equal(t, []string{"orders"}, entryPoint.WiresUp)
+		}
+	}
+	assert.Equal(t, []string{
+		"api/index.js handler handler",
+		"cmd/shop/main.go main main",
+		"cmd/shop/main.go server main",
+		"lambda/handler.py handler lambda_handler",
+		"tools/report.py cli run",
+		"tools/report.py main ",
+		"web/server.ts server ",
+	}, found, "declarations inside string literals and tests are skipped")
+
+	markdown := NewMarkdownGenerator(graph).GenerateContextMap()
+	assert.Contains(t, markdown, "### 🚪 Entry Points")
+	assert.Contains(t, markdown, "| `cmd/shop/main.go:9` | main | `main` | `orders` |")
+}
+
+func TestFindEntryPointsSkipsLiteralsAndComments(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"fixtures/project.go": "package fixtures\n\n// Servers call http.ListenAndServe(addr, handler)\nfunc CreateProject() map[string]string {\n\treturn map[string]string{\n\t\t\"server.go\": \"func serve() error {\\n\\treturn http.ListenAndServe(\\\":8080\\\", nil)\\n}\",\n\t\t\"cmd.go\": `rootCmd.Execute()`,\n\t}\n}\n",
+		"tools/notes.py":      "\"\"\"\nRun with uvicorn.run(app)\n\"\"\"\n\n# app.run() starts the dev server\ndef notes():\n    return 'app.run()'\n",
+		"web/docs.ts":         "/* app.listen(3000) */\nexport const usage = `server.listen(8080)`;\n",
+		"web/server.ts":       "const banner = \"ready\"; app.listen(3000);\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	var found []string
+	for _, entryPoint := range FindEntryPoints(graph) {
+		found = append(found, entryPoint.Package+"/"+filepath.Base(entryPoint.File)+" "+entryPoint.Kind)
+	}
+	assert.Equal(t, []string{"web/server.ts server"}, found, "only the call after a string literal on its line is code")
+}
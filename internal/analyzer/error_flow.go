@@ -0,0 +1,479 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Kinds of error origins in Go code
+const (
+	ErrorOriginSentinel = "sentinel" // A package-level error variable such as ErrNotFound
+	ErrorOriginType     = "type"     // A value of a type with an Error() string method
+	ErrorOriginNew      = "new"      // An errors.New or fmt.Errorf without %w at the return site
+)
+
+// maxErrorTraceDepth bounds how many calls an error is traced through
+const maxErrorTraceDepth = 8
+
+var (
+	goFuncPattern      = regexp.MustCompile(`(?m)^func\s+(?:\(\s*(?:\w+\s+)?\*?\s*(\w+)(?:\[[^\]]*\])?\s*\)\s*)?(\w+)\s*(?:\[[^\]]*\])?\(`)
+	goErrorTypePattern = regexp.MustCompile(`(?m)^func\s+\(\s*\w*\s*\*?\s*(\w+)\s*\)\s*Error\(\)\s+string\b`)
+	goSentinelPattern  = regexp.MustCompile(`(?m)^(?:var\s+)?[ \t]*(\w+)\s*=\s*(?:errors\.New|fmt\.Errorf)\(\s*"((?:[^"\\]|\\.)*)"`)
+	goErrorsNewPattern = regexp.MustCompile(`\berrors\.New\(\s*"((?:[^"\\]|\\.)*)"`)
+	goErrorfPattern    = regexp.MustCompile(`\bfmt\.Errorf\(\s*"((?:[^"\\]|\\.)*)"([^\n]*)`)
+	goJoinPattern      = regexp.MustCompile(`\berrors\.Join\(([^\n]*)`)
+	goErrAssignPattern = regexp.MustCompile(`(?m)^\s*(?:if\s+)?(?:[\w.]+\s*,\s*)*(err\w*)\s*:?=\s*(?:&)?([\w.]+)(?:\[[^\]]*\])?\(`)
+	goIdentPattern     = regexp.MustCompile(`[A-Za-z_][\w.]*`)
+	goReturnPattern    = regexp.MustCompile(`(?m)^\s*return\b(.*)$`)
+	goCallPattern      = regexp.MustCompile(`^([\w.]+)(?:\[[^\]]*\])?\(`)
+	goErrorWordPattern = regexp.MustCompile(`\berror\b`)
+)
+
+// ErrorOrigin is a place where a function creates an error or returns a
+// known one
+type ErrorOrigin struct {
+	Kind    string `json:"kind"`              // sentinel, type or new
+	Name    string `json:"name"`              // Sentinel or type name, or the message of a new error
+	Line    int    `json:"line"`              // Line of the origin
+	Wrapped bool   `json:"wrapped,omitempty"` // Wrapped with fmt.Errorf("...: %w", ...)
+}
+
+// ErrorFunction describes the errors a Go function returns
+type ErrorFunction struct {
+	Name         string        `json:"name"`
+	Receiver     string        `json:"receiver,omitempty"`
+	File         string        `json:"file"`
+	Line         int           `json:"line"`
+	ReturnsError bool          `json:"returns_error"`
+	Origins      []ErrorOrigin `json:"origins,omitempty"`
+	Calls        []string      `json:"calls,omitempty"` // Functions whose error result this function checks or propagates
+}
+
+// QualifiedName returns Receiver.Name for methods and Name for functions
+func (f *ErrorFunction) QualifiedName() string {
+	if f.Receiver != "" {
+		return f.Receiver + "." + f.Name
+	}
+	return f.Name
+}
+
+// ErrorDefinition is a sentinel error variable or an error type
+type ErrorDefinition struct {
+	Kind    string `json:"kind"` // sentinel or type
+	Name    string `json:"name"`
+	Message string `json:"message,omitempty"` // Message of a sentinel
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+}
+
+// ErrorFlow is the error-flow view of a Go codebase: which functions return
+// errors, where errors are created and which calls they travel through
+type ErrorFlow struct {
+	Definitions []ErrorDefinition
+	Functions   []*ErrorFunction
+
+	byName      map[string][]*ErrorFunction // Function name -> functions
+	definitions map[string]ErrorDefinition  // Sentinel or type name -> definition
+}
+
+// ErrorTrace is a chain of calls an error travels through, from the function
+// asked about down to the function where the error originates
+type ErrorTrace struct {
+	Origin ErrorOrigin
+	Path   []*ErrorFunction // Path[0] is the starting function, the last one creates the error
+}
+
+// AnalyzeErrorFlow scans the Go source files of the graph for functions
+// returning error, sentinel errors, error types and the calls errors are
+// propagated from. Calls are matched to functions by name.
+func AnalyzeErrorFlow(graph *types.CodeGraph) *ErrorFlow {
+	flow := &ErrorFlow{
+		byName:      make(map[string][]*ErrorFunction),
+		definitions: make(map[string]ErrorDefinition),
+	}
+
+	type goFile struct {
+		path, content string
+		bodies        [][2]int // Byte ranges of function bodies
+	}
+	var files []goFile
+	(&RelationshipAnalyzer{graph: graph}).forEachSourceFile(func(filePath, content string) {
+		if graph.Files[filePath].Language == "go" {
+			files = append(files, goFile{path: filePath, content: content})
+		}
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	// Definitions first, so function bodies can refer to sentinels and types in other files
+	for i := range files {
+		file := &files[i]
+		for _, m := range goFuncPattern.FindAllStringSubmatchIndex(file.content, -1) {
+			if bodyStart, bodyEnd := goFuncBody(file.content, m[1]-1); bodyStart >= 0 {
+				file.bodies = append(file.bodies, [2]int{bodyStart, bodyEnd})
+			}
+		}
+		for _, m := range goSentinelPattern.FindAllStringSubmatchIndex(file.content, -1) {
+			if insideRanges(m[0], file.bodies) {
+				continue
+			}
+			name := file.content[m[2]:m[3]]
+			flow.addDefinition(ErrorDefinition{Kind: ErrorOriginSentinel, Name: name, Message: file.content[m[4]:m[5]], File: file.path, Line: lineAt(file.content, m[2])})
+		}
+		for _, m := range goErrorTypePattern.FindAllStringSubmatchIndex(file.content, -1) {
+			name := file.content[m[2]:m[3]]
+			flow.addDefinition(ErrorDefinition{Kind: ErrorOriginType, Name: name, File: file.path, Line: lineAt(file.content, m[0])})
+		}
+	}
+
+	for _, file := range files {
+		for _, m := range goFuncPattern.FindAllStringSubmatchIndex(file.content, -1) {
+			bodyStart, bodyEnd := goFuncBody(file.content, m[1]-1)
+			if bodyStart < 0 {
+				continue
+			}
+			fn := &ErrorFunction{
+				Name: file.content[m[4]:m[5]],
+				File: file.path,
+				Line: lineAt(file.content, m[0]),
+			}
+			if m[2] >= 0 {
+				fn.Receiver = file.content[m[2]:m[3]]
+			}
+			if fn.Name == "Error" && flow.definitions[fn.Receiver].Kind == ErrorOriginType {
+				continue
+			}
+			fn.ReturnsError = goResultsIncludeError(file.content[m[1]-1 : bodyStart])
+			flow.scanBody(fn, file.content, bodyStart, bodyEnd)
+			if !fn.ReturnsError && len(fn.Origins) == 0 {
+				continue
+			}
+			flow.Functions = append(flow.Functions, fn)
+			flow.byName[fn.Name] = append(flow.byName[fn.Name], fn)
+		}
+	}
+	return flow
+}
+
+func (flow *ErrorFlow) addDefinition(definition ErrorDefinition) {
+	if _, exists := flow.definitions[definition.Name]; exists {
+		return
+	}
+	flow.definitions[definition.Name] = definition
+	flow.Definitions = append(flow.Definitions, definition)
+}
+
+// Definition returns the sentinel or error type with the given name
+func (flow *ErrorFlow) Definition(name string) (ErrorDefinition, bool) {
+	definition, ok := flow.definitions[lastSegment(name)]
+	return definition, ok
+}
+
+// FunctionsNamed returns the functions with a name, which may be qualified
+// with a receiver or package ("Store.Get", "store.Get")
+func (flow *ErrorFlow) FunctionsNamed(name string) []*ErrorFunction {
+	candidates := flow.byName[lastSegment(name)]
+	if !strings.Contains(name, ".") {
+		return candidates
+	}
+	qualifier := name[:strings.LastIndex(name, ".")]
+	var matched []*ErrorFunction
+	for _, fn := range candidates {
+		if strings.EqualFold(fn.QualifiedName(), name) || strings.EqualFold(filepath.Base(filepath.Dir(fn.File)), qualifier) {
+			matched = append(matched, fn)
+		}
+	}
+	if len(matched) == 0 {
+		return candidates
+	}
+	return matched
+}
+
+// scanBody records the errors a function body creates or returns and the
+// calls whose errors it handles
+func (flow *ErrorFlow) scanBody(fn *ErrorFunction, content string, start, end int) {
+	body := content[start:end]
+	baseLine := lineAt(content, start)
+	addOrigin := func(origin ErrorOrigin) {
+		for _, existing := range fn.Origins {
+			if existing.Kind == origin.Kind && existing.Name == origin.Name {
+				return
+			}
+		}
+		fn.Origins = append(fn.Origins, origin)
+	}
+
+	for _, m := range goErrorsNewPattern.FindAllStringSubmatchIndex(body, -1) {
+		addOrigin(ErrorOrigin{Kind: ErrorOriginNew, Name: body[m[2]:m[3]], Line: baseLine + strings.Count(body[:m[0]], "\n")})
+	}
+	for _, m := range goErrorfPattern.FindAllStringSubmatchIndex(body, -1) {
+		format, args := body[m[2]:m[3]], body[m[4]:m[5]]
+		line := baseLine + strings.Count(body[:m[0]], "\n")
+		if !strings.Contains(format, "%w") {
+			addOrigin(ErrorOrigin{Kind: ErrorOriginNew, Name: format, Line: line})
+			continue
+		}
+		for _, ident := range goIdentPattern.FindAllString(args, -1) {
+			if definition, ok := flow.Definition(ident); ok && definition.Kind == ErrorOriginSentinel {
+				addOrigin(ErrorOrigin{Kind: ErrorOriginSentinel, Name: definition.Name, Line: line, Wrapped: true})
+			}
+		}
+	}
+
+	// Sentinels and error types in return statements
+	for _, m := range goReturnPattern.FindAllStringSubmatchIndex(body, -1) {
+		results := body[m[2]:m[3]]
+		line := baseLine + strings.Count(body[:m[0]], "\n")
+		if strings.Contains(results, "fmt.Errorf(") {
+			continue // Handled above
+		}
+		for _, ident := range goIdentPattern.FindAllString(results, -1) {
+			definition, ok := flow.Definition(ident)
+			if !ok {
+				continue
+			}
+			if definition.Kind == ErrorOriginType && !strings.Contains(results, lastSegment(ident)+"{") && !strings.Contains(results, lastSegment(ident)+"(") {
+				continue
+			}
+			addOrigin(ErrorOrigin{Kind: definition.Kind, Name: definition.Name, Line: line})
+		}
+	}
+	// Error types built before the return, e.g. err := &NotFoundError{...}
+	for _, definition := range flow.Definitions {
+		name := definition.Name
+		if definition.Kind != ErrorOriginType {
+			continue
+		}
+		if idx := strings.Index(body, name+"{"); idx >= 0 {
+			addOrigin(ErrorOrigin{Kind: ErrorOriginType, Name: name, Line: baseLine + strings.Count(body[:idx], "\n")})
+		}
+	}
+	sort.SliceStable(fn.Origins, func(i, j int) bool { return fn.Origins[i].Line < fn.Origins[j].Line })
+
+	// Calls whose error is assigned to err and passed on, or returned directly
+	// with "return f(...)". The error is followed until err is assigned again.
+	assignments := goErrAssignPattern.FindAllStringSubmatchIndex(body, -1)
+	for i, m := range assignments {
+		name, end := body[m[2]:m[3]], len(body)
+		for _, next := range assignments[i+1:] {
+			if body[next[2]:next[3]] == name {
+				end = next[0]
+				break
+			}
+		}
+		if propagatesError(body[m[1]:end], name) {
+			fn.Calls = appendUnique(fn.Calls, lastSegment(body[m[4]:m[5]]))
+		}
+	}
+	if fn.ReturnsError {
+		for _, m := range goReturnPattern.FindAllStringSubmatch(body, -1) {
+			// Only the last result is the error
+			call := goCallPattern.FindStringSubmatch(lastResult(m[1]))
+			if call != nil && !strings.HasPrefix(call[1], "fmt.") && !strings.HasPrefix(call[1], "errors.") {
+				fn.Calls = appendUnique(fn.Calls, lastSegment(call[1]))
+			}
+		}
+	}
+}
+
+// propagatesError reports whether code passes the error variable name on:
+// returned as is, wrapped with %w or joined with errors.Join. Errors replaced
+// by another, as in "return &NotFoundError{}", are not propagated.
+func propagatesError(code, name string) bool {
+	for _, m := range goReturnPattern.FindAllStringSubmatch(code, -1) {
+		if lastResult(m[1]) == name {
+			return true
+		}
+	}
+	passes := func(args string) bool {
+		return slices.Contains(goIdentPattern.FindAllString(args, -1), name)
+	}
+	for _, m := range goErrorfPattern.FindAllStringSubmatch(code, -1) {
+		if strings.Contains(m[1], "%w") && passes(m[2]) {
+			return true
+		}
+	}
+	for _, m := range goJoinPattern.FindAllStringSubmatch(code, -1) {
+		if passes(m[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastResult returns the last expression of a return statement's results
+func lastResult(results string) string {
+	depth := 0
+	last := 0
+	for i, c := range results {
+		switch c {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				last = i + 1
+			}
+		}
+	}
+	return strings.TrimSpace(results[last:])
+}
+
+// Sources traces the errors a function can return to where they originate,
+// following the calls it propagates errors from
+func (flow *ErrorFlow) Sources(fn *ErrorFunction) []ErrorTrace {
+	var traces []ErrorTrace
+	seen := make(map[string]bool) // Origin function and origin, to report each once
+	var walk func(current *ErrorFunction, path []*ErrorFunction)
+	walk = func(current *ErrorFunction, path []*ErrorFunction) {
+		path = append(path, current)
+		for _, origin := range current.Origins {
+			key := current.File + ":" + current.QualifiedName() + ":" + origin.Kind + ":" + origin.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			traces = append(traces, ErrorTrace{Origin: origin, Path: append([]*ErrorFunction(nil), path...)})
+		}
+		if len(path) >= maxErrorTraceDepth {
+			return
+		}
+		for _, callee := range current.Calls {
+			for _, next := range flow.byName[callee] {
+				if !next.ReturnsError || containsErrorFunction(path, next) {
+					continue
+				}
+				walk(next, path)
+			}
+		}
+	}
+	walk(fn, nil)
+	return traces
+}
+
+// Propagation lists the call chains through which an error reaches callers:
+// each trace starts at a function that returns the error directly or through
+// its callees, and ends at the function that creates it
+func (flow *ErrorFlow) Propagation(name string) []ErrorTrace {
+	name = lastSegment(name)
+	callers := make(map[string][]*ErrorFunction)
+	for _, fn := range flow.Functions {
+		for _, callee := range fn.Calls {
+			callers[callee] = append(callers[callee], fn)
+		}
+	}
+
+	var traces []ErrorTrace
+	for _, fn := range flow.Functions {
+		for _, origin := range fn.Origins {
+			if origin.Name != name {
+				continue
+			}
+			// Walk up from the origin through the callers propagating it
+			var walk func(path []*ErrorFunction)
+			walk = func(path []*ErrorFunction) {
+				traces = append(traces, ErrorTrace{Origin: origin, Path: append([]*ErrorFunction(nil), path...)})
+				if len(path) >= maxErrorTraceDepth {
+					return
+				}
+				for _, caller := range callers[path[0].Name] {
+					if !caller.ReturnsError || containsErrorFunction(path, caller) {
+						continue
+					}
+					walk(append([]*ErrorFunction{caller}, path...))
+				}
+			}
+			walk([]*ErrorFunction{fn})
+		}
+	}
+	sort.SliceStable(traces, func(i, j int) bool { return len(traces[i].Path) < len(traces[j].Path) })
+	return traces
+}
+
+func containsErrorFunction(path []*ErrorFunction, fn *ErrorFunction) bool {
+	for _, existing := range path {
+		if existing == fn {
+			return true
+		}
+	}
+	return false
+}
+
+// goFuncBody returns the byte range of the body of the function whose
+// parameter list opens at paren, or -1 for declarations without a body
+func goFuncBody(content string, paren int) (int, int) {
+	depth := 0
+	for i := paren; i < len(content); i++ {
+		switch content[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case '{':
+			if depth > 0 {
+				continue
+			}
+			// "interface{}" or "struct{...}" in the results is not the body
+			if strings.HasSuffix(content[:i], "interface") || strings.HasSuffix(content[:i], "struct") {
+				depth++
+				continue
+			}
+			// gofmt ends function bodies with "}" at the start of a line
+			end := strings.Index(content[i:], "\n}")
+			if end < 0 {
+				return i, len(content)
+			}
+			return i, i + end + 2
+		case '}':
+			depth--
+		case '\n':
+			if depth == 0 {
+				return -1, -1
+			}
+		}
+	}
+	return -1, -1
+}
+
+// goResultsIncludeError reports whether the signature from the parameter
+// list on declares error among its results
+func goResultsIncludeError(signature string) bool {
+	depth := 0
+	for i, c := range signature {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return goErrorWordPattern.MatchString(signature[i+1:])
+			}
+		}
+	}
+	return false
+}
+
+func insideRanges(offset int, ranges [][2]int) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func lineAt(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+func lastSegment(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
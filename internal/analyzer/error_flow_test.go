@@ -0,0 +1,157 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeErrorFlow(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"store/store.go": `package store
+
+import "errors"
+
+var ErrNotFound = errors.New("record not found")
+
+type ConflictError struct {
+	ID int
+}
+
+func (e *ConflictError) Error() string {
+	return "conflict"
+}
+
+func (s *Store) Get(id int) (*Record, error) {
+	if id == 0 {
+		return nil, ErrNotFound
+	}
+	return s.records[id], nil
+}
+
+func (s *Store) Save(r *Record) error {
+	if r.Version < 0 {
+		return &ConflictError{ID: r.ID}
+	}
+	return errors.New("store is read-only")
+}
+`,
+		"service/orders.go": `package service
+
+func (o *Orders) Load(id int) (*Order, error) {
+	rec, err := o.store.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("load order %d: %w", id, err)
+	}
+	if rec.Deleted {
+		return nil, fmt.Errorf("load order %d: %w", id, store.ErrNotFound)
+	}
+	return toOrder(rec), nil
+}
+
+func (o *Orders) Find(id int) (*Order, error) {
+	if err := o.Load(id); err != nil {
+		return nil, &store.ConflictError{ID: id}
+	}
+	return nil, nil
+}
+
+func (o *Orders) Sync(order *Order) error {
+	if err := o.store.Save(order.Record()); err != nil {
+		return errors.Join(err, o.flush())
+	}
+	return nil
+}
+
+func (o *Orders) Update(order *Order) error {
+	if _, err := o.Load(order.ID); err != nil {
+		return err
+	}
+	return o.store.Save(order.Record())
+}
+
+func toOrder(rec *Record) *Order {
+	return &Order{ID: rec.ID}
+}
+`,
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	flow := AnalyzeErrorFlow(graph)
+
+	sentinel, ok := flow.Definition("store.ErrNotFound")
+	require.True(t, ok)
+	assert.Equal(t, ErrorDefinition{Kind: ErrorOriginSentinel, Name: "ErrNotFound", Message: "record not found", File: filepath.Join(dir, "store", "store.go"), Line: 5}, sentinel)
+	conflict, ok := flow.Definition("ConflictError")
+	require.True(t, ok)
+	assert.Equal(t, ErrorOriginType, conflict.Kind)
+
+	// Functions that neither return nor create errors, and Error methods, are left out
+	assert.Empty(t, flow.FunctionsNamed("toOrder"))
+	assert.Empty(t, flow.FunctionsNamed("Error"))
+
+	get := flow.FunctionsNamed("Store.Get")
+	require.Len(t, get, 1)
+	assert.True(t, get[0].ReturnsError)
+	assert.Equal(t, []ErrorOrigin{{Kind: ErrorOriginSentinel, Name: "ErrNotFound", Line: 17}}, get[0].Origins)
+
+	load := flow.FunctionsNamed("service.Load")
+	require.Len(t, load, 1)
+	assert.Equal(t, []string{"Get"}, load[0].Calls)
+	require.Len(t, load[0].Origins, 1)
+	assert.True(t, load[0].Origins[0].Wrapped)
+
+	update := flow.FunctionsNamed("Update")
+	require.Len(t, update, 1)
+	assert.Equal(t, []string{"Load", "Save"}, update[0].Calls)
+
+	// Replaced errors are not traced through the call that returned them
+	find := flow.FunctionsNamed("Find")
+	require.Len(t, find, 1)
+	assert.Empty(t, find[0].Calls)
+	require.Len(t, flow.Sources(find[0]), 1)
+	assert.Equal(t, "ConflictError", flow.Sources(find[0])[0].Origin.Name)
+
+	sync := flow.FunctionsNamed("Sync")
+	require.Len(t, sync, 1)
+	assert.Equal(t, []string{"Save"}, sync[0].Calls, "joined errors are propagated")
+
+	// Update's errors come from the sentinel (wrapped in Load and returned by
+	// Get), the conflict type and a plain errors.New in Save
+	sources := make(map[string][]string)
+	for _, trace := range flow.Sources(update[0]) {
+		var path []string
+		for _, fn := range trace.Path {
+			path = append(path, fn.QualifiedName())
+		}
+		sources[trace.Origin.Name] = append(sources[trace.Origin.Name], filepath.Base(trace.Path[len(trace.Path)-1].File)+":"+joinPath(path))
+	}
+	assert.Equal(t, []string{"orders.go:Orders.Update > Orders.Load", "store.go:Orders.Update > Orders.Load > Store.Get"}, sources["ErrNotFound"])
+	assert.Equal(t, []string{"store.go:Orders.Update > Store.Save"}, sources["ConflictError"])
+	assert.Equal(t, []string{"store.go:Orders.Update > Store.Save"}, sources["store is read-only"])
+
+	// The sentinel reaches Update through Load from both Get and Load itself
+	propagation := flow.Propagation("ErrNotFound")
+	require.NotEmpty(t, propagation)
+	var longest []string
+	for _, fn := range propagation[len(propagation)-1].Path {
+		longest = append(longest, fn.QualifiedName())
+	}
+	assert.Equal(t, []string{"Orders.Update", "Orders.Load", "Store.Get"}, longest)
+}
+
+func joinPath(names []string) string {
+	path := ""
+	for i, name := range names {
+		if i > 0 {
+			path += " > "
+		}
+		path += name
+	}
+	return path
+}
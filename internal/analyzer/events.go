@@ -0,0 +1,303 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipPublishesTo links a message producer to a consumer of the same topic
+const RelationshipPublishesTo RelationshipType = "publishes-to"
+
+// Brokers recognized by the event flow analysis
+const (
+	BrokerKafka        = "kafka"
+	BrokerSNS          = "sns"
+	BrokerSQS          = "sqs"
+	BrokerNATS         = "nats"
+	BrokerEventEmitter = "event-emitter"
+	BrokerPubSub       = "pubsub" // Redis, Google Pub/Sub and other publish/subscribe clients
+)
+
+const (
+	eventRoleProducer = "producer"
+	eventRoleConsumer = "consumer"
+)
+
+// eventPattern extracts topic names for one broker and role; the first group holds the topic
+// or, for list-valued patterns, a comma separated list of quoted topics
+type eventPattern struct {
+	broker string
+	role   string
+	re     *regexp.Regexp
+	list   bool
+}
+
+const quotedTopic = "[\"'`]([^\"'`\\s]+)[\"'`]"
+
+var eventPatterns = []eventPattern{
+	// Kafka: kafkajs, sarama, kafka-go, kafka-python/confluent-kafka, Spring Kafka
+	{BrokerKafka, eventRoleProducer, regexp.MustCompile(`\bproducer\.send\(\s*\{[^}]*?\btopic\s*:\s*` + quotedTopic), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`\bconsumer\.subscribe\(\s*\{[^}]*?\btopic\s*:\s*` + quotedTopic), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`\bconsumer\.subscribe\(\s*\{[^}]*?\btopics\s*:\s*\[([^\]]+)\]`), true},
+	{BrokerKafka, eventRoleProducer, regexp.MustCompile(`sarama\.ProducerMessage\{[^}]*?\bTopic\s*:\s*"([^"]+)"`), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`\.ConsumePartition\(\s*"([^"]+)"`), false},
+	{BrokerKafka, eventRoleProducer, regexp.MustCompile(`kafka\.(?:Writer|Message)\{[^}]*?\bTopic\s*:\s*"([^"]+)"`), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`kafka\.ReaderConfig\{[^}]*?\bTopic\s*:\s*"([^"]+)"`), false},
+	{BrokerKafka, eventRoleProducer, regexp.MustCompile(`\bproducer\.(?:send|produce)\(\s*` + quotedTopic), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`\bKafkaConsumer\(\s*` + quotedTopic), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`\bconsumer\.subscribe\(\s*\[([^\]]+)\]`), true},
+	{BrokerKafka, eventRoleProducer, regexp.MustCompile(`\bkafkaTemplate\.send\(\s*"([^"]+)"`), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`@KafkaListener\([^)]*?\btopics\s*=\s*"([^"]+)"`), false},
+	{BrokerKafka, eventRoleConsumer, regexp.MustCompile(`@KafkaListener\([^)]*?\btopics\s*=\s*\{([^}]+)\}`), true},
+
+	// AWS SNS/SQS: topic and queue names are the last segment of the ARN or URL
+	{BrokerSNS, eventRoleProducer, regexp.MustCompile(`\bTopicArn\s*[:=]\s*` + quotedTopic), false},
+	{BrokerSQS, eventRoleProducer, regexp.MustCompile(`\b(?:sendMessage|SendMessageCommand|send_message)\(\s*\{?[^)]*?\bQueueUrl\s*[:=]\s*` + quotedTopic), false},
+	{BrokerSQS, eventRoleConsumer, regexp.MustCompile(`\b(?:receiveMessage|ReceiveMessageCommand|receive_message)\(\s*\{?[^)]*?\bQueueUrl\s*[:=]\s*` + quotedTopic), false},
+
+	// NATS subjects
+	{BrokerNATS, eventRoleProducer, regexp.MustCompile(`\b(?:nc|js|conn|nats)\.(?:Publish|publish)\(\s*` + quotedTopic), false},
+	{BrokerNATS, eventRoleConsumer, regexp.MustCompile(`\b(?:nc|js|conn|nats)\.(?:Subscribe|subscribe|QueueSubscribe|ChanSubscribe)\(\s*` + quotedTopic), false},
+
+	// Node EventEmitter and socket-style events
+	{BrokerEventEmitter, eventRoleProducer, regexp.MustCompile(`\.emit\(\s*` + quotedTopic), false},
+	{BrokerEventEmitter, eventRoleConsumer, regexp.MustCompile(`\.(?:on|once|addListener|prependListener)\(\s*` + quotedTopic), false},
+
+	// Generic publish/subscribe clients (Redis, Google Pub/Sub, ...)
+	{BrokerPubSub, eventRoleProducer, regexp.MustCompile(`\.(?:publish|Publish)\(\s*` + quotedTopic), false},
+	{BrokerPubSub, eventRoleConsumer, regexp.MustCompile(`\.(?:subscribe|Subscribe|psubscribe)\(\s*` + quotedTopic), false},
+}
+
+// builtinEventNames are lifecycle events emitted by streams, sockets and processes; linking
+// every emit('error') to every on('error') would bury the real flows
+var builtinEventNames = map[string]bool{
+	"error": true, "close": true, "end": true, "finish": true, "data": true, "open": true, "ready": true,
+	"connect": true, "connection": true, "disconnect": true, "message": true, "exit": true, "drain": true,
+	"readable": true, "listening": true, "request": true, "response": true, "timeout": true,
+	"SIGINT": true, "SIGTERM": true, "uncaughtException": true, "unhandledRejection": true,
+}
+
+// EventEndpoint is a producer or consumer of a topic
+type EventEndpoint struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Symbol string `json:"symbol,omitempty"` // Enclosing function or method
+	Topic  string `json:"topic"`            // Topic as written, may contain wildcards for consumers
+}
+
+// EventFlow groups the producers and consumers of one topic
+type EventFlow struct {
+	Topic     string          `json:"topic"`
+	Broker    string          `json:"broker"`
+	Producers []EventEndpoint `json:"producers"`
+	Consumers []EventEndpoint `json:"consumers"`
+}
+
+// eventOccurrence is a producer or consumer found in a source file
+type eventOccurrence struct {
+	broker string
+	role   string
+	topic  string
+	file   string
+	line   int
+}
+
+// analyzeEventFlows links producers to consumers of the same topic with publishes-to edges
+func (ra *RelationshipAnalyzer) analyzeEventFlows(metrics *RelationshipMetrics) {
+	for _, flow := range ra.eventFlows() {
+		for _, producer := range flow.Producers {
+			from := ra.enclosingNode(producer.File, producer.Line)
+			for _, consumer := range flow.Consumers {
+				to := ra.enclosingNode(consumer.File, consumer.Line)
+				if from == to {
+					continue
+				}
+				edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s:%d-%s:%d", RelationshipPublishesTo, flow.Topic,
+					producer.File, producer.Line, consumer.File, consumer.Line))
+				ra.graph.Edges[edgeId] = &types.GraphEdge{
+					Id:     edgeId,
+					From:   from,
+					To:     to,
+					Type:   string(RelationshipPublishesTo),
+					Weight: 1.0,
+					Metadata: map[string]interface{}{
+						"topic":       flow.Topic,
+						"broker":      flow.Broker,
+						"source_file": producer.File,
+						"source_line": producer.Line,
+						"target_file": consumer.File,
+						"target_line": consumer.Line,
+					},
+				}
+				metrics.ByType[RelationshipPublishesTo]++
+			}
+		}
+	}
+}
+
+// EventFlows scans the graph's source files for message producers and consumers and groups
+// them by broker and topic. Topics with only producers or only consumers are included.
+func EventFlows(graph *types.CodeGraph) []EventFlow {
+	return (&RelationshipAnalyzer{graph: graph}).eventFlows()
+}
+
+func (ra *RelationshipAnalyzer) eventFlows() []EventFlow {
+	var occurrences []eventOccurrence
+	ra.forEachSourceFile(func(filePath, content string) {
+		occurrences = append(occurrences, extractEventOccurrences(content, filePath)...)
+	})
+
+	flows := make(map[string]*EventFlow)
+	var consumers []eventOccurrence
+	for _, occurrence := range occurrences {
+		if occurrence.role == eventRoleConsumer {
+			consumers = append(consumers, occurrence)
+			continue
+		}
+		key := occurrence.broker + "\x00" + occurrence.topic
+		flow, exists := flows[key]
+		if !exists {
+			flow = &EventFlow{Topic: occurrence.topic, Broker: occurrence.broker}
+			flows[key] = flow
+		}
+		flow.Producers = append(flow.Producers, ra.eventEndpoint(occurrence))
+	}
+
+	// Consumers join every flow whose topic they subscribe to; wildcard subscriptions may join several
+	for _, consumer := range consumers {
+		matched := false
+		for _, flow := range flows {
+			if flow.Broker == consumer.broker && topicMatches(consumer.topic, flow.Topic) && len(flow.Producers) > 0 {
+				flow.Consumers = append(flow.Consumers, ra.eventEndpoint(consumer))
+				matched = true
+			}
+		}
+		if matched {
+			continue
+		}
+		key := consumer.broker + "\x00" + consumer.topic
+		flow, exists := flows[key]
+		if !exists {
+			flow = &EventFlow{Topic: consumer.topic, Broker: consumer.broker}
+			flows[key] = flow
+		}
+		flow.Consumers = append(flow.Consumers, ra.eventEndpoint(consumer))
+	}
+
+	result := make([]EventFlow, 0, len(flows))
+	for _, flow := range flows {
+		sortEventEndpoints(flow.Producers)
+		sortEventEndpoints(flow.Consumers)
+		result = append(result, *flow)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Topic != result[j].Topic {
+			return result[i].Topic < result[j].Topic
+		}
+		return result[i].Broker < result[j].Broker
+	})
+	return result
+}
+
+// extractEventOccurrences finds producers and consumers in a source file. A call matched by
+// a broker-specific pattern is not reported again by the generic pub/sub patterns.
+func extractEventOccurrences(content, filePath string) []eventOccurrence {
+	var occurrences []eventOccurrence
+	seen := make(map[string]bool)
+	for _, pattern := range eventPatterns {
+		for _, m := range pattern.re.FindAllStringSubmatchIndex(content, -1) {
+			line := strings.Count(content[:m[0]], "\n") + 1
+			raw := content[m[2]:m[3]]
+			topics := []string{raw}
+			if pattern.list {
+				topics = quotedStrings(raw)
+			}
+			for _, topic := range topics {
+				topic = topicName(pattern.broker, topic)
+				if topic == "" || (pattern.broker == BrokerEventEmitter && builtinEventNames[topic]) {
+					continue
+				}
+				key := fmt.Sprintf("%s:%d:%s", pattern.role, line, topic)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				occurrences = append(occurrences, eventOccurrence{
+					broker: pattern.broker,
+					role:   pattern.role,
+					topic:  topic,
+					file:   filePath,
+					line:   line,
+				})
+			}
+		}
+	}
+	return occurrences
+}
+
+var quotedStringPattern = regexp.MustCompile(quotedTopic)
+
+func quotedStrings(list string) []string {
+	var values []string
+	for _, m := range quotedStringPattern.FindAllStringSubmatch(list, -1) {
+		values = append(values, m[1])
+	}
+	return values
+}
+
+// topicName normalizes a topic as written: SNS ARNs and SQS URLs are reduced to their name,
+// and topics built from template variables are dropped
+func topicName(broker, topic string) string {
+	if strings.Contains(topic, "${") || strings.Contains(topic, "{{") || strings.Contains(topic, "%s") {
+		return ""
+	}
+	switch broker {
+	case BrokerSNS:
+		topic = topic[strings.LastIndex(topic, ":")+1:]
+	case BrokerSQS:
+		topic = topic[strings.LastIndex(topic, "/")+1:]
+	}
+	return topic
+}
+
+// topicMatches reports whether a subscription matches a published topic. NATS-style
+// wildcards are supported: "*" matches one token and ">" the remaining tokens.
+func topicMatches(subscription, topic string) bool {
+	if subscription == topic {
+		return true
+	}
+	if !strings.ContainsAny(subscription, "*>") {
+		return false
+	}
+	want, got := strings.Split(subscription, "."), strings.Split(topic, ".")
+	for i, token := range want {
+		if token == ">" {
+			return len(got) > i
+		}
+		if i >= len(got) || (token != "*" && token != got[i]) {
+			return false
+		}
+	}
+	return len(want) == len(got)
+}
+
+func (ra *RelationshipAnalyzer) eventEndpoint(occurrence eventOccurrence) EventEndpoint {
+	endpoint := EventEndpoint{File: occurrence.file, Line: occurrence.line, Topic: occurrence.topic}
+	if symbol := ra.enclosingSymbol(occurrence.file, occurrence.line); symbol != nil {
+		endpoint.Symbol = symbol.Name
+	}
+	return endpoint
+}
+
+func sortEventEndpoints(endpoints []EventEndpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].File != endpoints[j].File {
+			return endpoints[i].File < endpoints[j].File
+		}
+		return endpoints[i].Line < endpoints[j].Line
+	})
+}
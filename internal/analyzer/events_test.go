@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicMatches(t *testing.T) {
+	assert.True(t, topicMatches("orders.created", "orders.created"))
+	assert.True(t, topicMatches("orders.*", "orders.created"))
+	assert.False(t, topicMatches("orders.*", "orders.created.eu"))
+	assert.True(t, topicMatches("orders.>", "orders.created.eu"))
+	assert.False(t, topicMatches("orders.>", "orders"))
+	assert.False(t, topicMatches("orders", "payments"))
+}
+
+func TestExtractEventOccurrences(t *testing.T) {
+	content := `await producer.send({ topic: 'orders', messages })
+await consumer.subscribe({ topics: ['orders', 'refunds'] })
+bus.emit('user:signup', user)
+stream.on('error', handle)
+nc.publish("audit.login", data)
+await sns.send(new PublishCommand({ TopicArn: 'arn:aws:sns:us-east-1:123:alerts', Message }))
+`
+	occurrences := extractEventOccurrences(content, "worker.ts")
+
+	var got []string
+	for _, occurrence := range occurrences {
+		got = append(got, occurrence.broker+" "+occurrence.role+" "+occurrence.topic)
+	}
+	assert.ElementsMatch(t, []string{
+		"kafka producer orders",
+		"kafka consumer orders",
+		"kafka consumer refunds",
+		"event-emitter producer user:signup",
+		"nats producer audit.login",
+		"sns producer alerts",
+	}, got, "builtin stream events and duplicate generic matches are skipped")
+}
+
+func TestEventFlowEdges(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"orders/publish.go": `package orders
+
+func PlaceOrder(nc *nats.Conn) {
+	nc.Publish("orders.created", payload)
+}
+`,
+		"billing/listen.go": `package billing
+
+func Listen(nc *nats.Conn) {
+	nc.Subscribe("orders.*", onOrder)
+}
+`,
+		"notify/worker.py": `def run(consumer):
+    consumer.subscribe(['shipments'])
+`,
+	}
+	testutils.WriteTree(t, dir, files)
+
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	flows := EventFlows(graph)
+	require.Len(t, flows, 2)
+
+	assert.Equal(t, "orders.created", flows[0].Topic)
+	assert.Equal(t, BrokerNATS, flows[0].Broker)
+	require.Len(t, flows[0].Producers, 1)
+	assert.Equal(t, "PlaceOrder", flows[0].Producers[0].Symbol)
+	require.Len(t, flows[0].Consumers, 1, "wildcard subscriptions join matching topics")
+	assert.Equal(t, "orders.*", flows[0].Consumers[0].Topic)
+	assert.Equal(t, "Listen", flows[0].Consumers[0].Symbol)
+
+	assert.Equal(t, "shipments", flows[1].Topic)
+	assert.Empty(t, flows[1].Producers)
+	assert.Len(t, flows[1].Consumers, 1)
+
+	var edges int
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipPublishesTo) {
+			edges++
+			assert.Equal(t, "orders.created", edge.Metadata["topic"])
+			assert.Contains(t, string(edge.From), "PlaceOrder")
+			assert.Contains(t, string(edge.To), "Listen")
+		}
+	}
+	assert.Equal(t, 1, edges)
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Feature flag providers recognized by the flag index
+const (
+	FlagProviderLaunchDarkly = "launchdarkly"
+	FlagProviderUnleash      = "unleash"
+	FlagProviderFlagsmith    = "flagsmith"
+	FlagProviderSplit        = "split"
+	FlagProviderGrowthBook   = "growthbook"
+	FlagProviderOpenFeature  = "openfeature"
+	FlagProviderCustom       = "custom" // Project-specific helpers configured via feature_flag_helpers
+)
+
+const quotedFlag = "[\"'`]([\\w.:/-]+)[\"'`]"
+
+// flagPattern matches an SDK call whose first quoted argument is the flag key
+type flagPattern struct {
+	provider string
+	re       *regexp.Regexp
+}
+
+var flagPatterns = []flagPattern{
+	{FlagProviderLaunchDarkly, regexp.MustCompile(`\.(?:[Bb]ool|[Ss]tring|[Ii]nt|[Ff]loat64|[Ff]loat|[Jj]son|JSON|[Nn]umber)?[Vv]ariation(?:Detail)?(?:Ctx)?\(\s*` + quotedFlag)},
+	{FlagProviderUnleash, regexp.MustCompile(`\b(?:unleash|client|Unleash)\.(?:isEnabled|IsEnabled|is_enabled|getVariant|GetVariant|get_variant)\(\s*` + quotedFlag)},
+	{FlagProviderUnleash, regexp.MustCompile(`\b(?:useFlag|useVariant)\(\s*` + quotedFlag)},
+	{FlagProviderFlagsmith, regexp.MustCompile(`\bflagsmith\.(?:hasFeature|getValue)\(\s*` + quotedFlag)},
+	{FlagProviderFlagsmith, regexp.MustCompile(`\.(?:has_feature|get_feature_value|is_feature_enabled|IsFeatureEnabled)\(\s*` + quotedFlag)},
+	{FlagProviderSplit, regexp.MustCompile(`\.(?:getTreatment|GetTreatment|get_treatment|getTreatmentWithConfig)\(\s*(?:[\w.]+\s*,\s*)?` + quotedFlag)},
+	{FlagProviderGrowthBook, regexp.MustCompile(`\b(?:\w+\.)?(?:isOn|isOff|getFeatureValue|evalFeature|useFeatureIsOn|useFeatureValue|IsOn|EvalFeature|is_on|eval_feature)\(\s*` + quotedFlag)},
+	{FlagProviderOpenFeature, regexp.MustCompile(`\.(?:getBooleanValue|getStringValue|getNumberValue|getObjectValue|getBooleanDetails|BooleanValue|StringValue|IntValue|FloatValue|ObjectValue|get_boolean_value|get_string_value)\(\s*(?:ctx\s*,\s*)?` + quotedFlag)},
+}
+
+// FlagUsage is a single evaluation of a feature flag
+type FlagUsage struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Symbol   string `json:"symbol,omitempty"` // Enclosing function or method
+	Provider string `json:"provider"`
+	Call     string `json:"call"` // The matched call, e.g. .variation("new-checkout"
+}
+
+// flagOccurrence pairs a usage with the flag key it evaluates
+type flagOccurrence struct {
+	key   string
+	usage FlagUsage
+}
+
+// FeatureFlag groups every usage of one flag key
+type FeatureFlag struct {
+	Key       string      `json:"key"`
+	Providers []string    `json:"providers"`
+	Usages    []FlagUsage `json:"usages"`
+}
+
+// Files returns the number of distinct files the flag is used in
+func (f *FeatureFlag) Files() int {
+	files := make(map[string]bool)
+	for _, usage := range f.Usages {
+		files[usage.File] = true
+	}
+	return len(files)
+}
+
+// CompileFlagHelpers turns helper names such as "isFeatureEnabled", "flags.Enabled" or
+// "*.FlagOn" into patterns matching calls whose first quoted argument is the flag key.
+// "*" matches a single identifier.
+func CompileFlagHelpers(helpers []string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, helper := range helpers {
+		helper = strings.TrimSpace(helper)
+		if helper == "" {
+			continue
+		}
+		expr := strings.ReplaceAll(regexp.QuoteMeta(helper), `\*`, `\w+`)
+		pattern, err := regexp.Compile(`\b` + expr + `\(\s*` + quotedFlag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid feature flag helper %q: %w", helper, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// FeatureFlags indexes feature flag evaluations across the graph's source files, using the
+// built-in SDK patterns plus the given project-specific helper patterns
+func FeatureFlags(graph *types.CodeGraph, helpers []*regexp.Regexp) []FeatureFlag {
+	ra := &RelationshipAnalyzer{graph: graph}
+	flags := make(map[string]*FeatureFlag)
+
+	ra.forEachSourceFile(func(filePath, content string) {
+		for _, occurrence := range extractFlagUsages(content, filePath, helpers) {
+			key, usage := occurrence.key, occurrence.usage
+			if symbol := ra.enclosingSymbol(filePath, usage.Line); symbol != nil {
+				usage.Symbol = symbol.Name
+			}
+
+			flag, exists := flags[key]
+			if !exists {
+				flag = &FeatureFlag{Key: key}
+				flags[key] = flag
+			}
+			flag.Usages = append(flag.Usages, usage)
+			flag.Providers = appendUnique(flag.Providers, usage.Provider)
+		}
+	})
+
+	result := make([]FeatureFlag, 0, len(flags))
+	for _, flag := range flags {
+		sort.Slice(flag.Usages, func(i, j int) bool {
+			if flag.Usages[i].File != flag.Usages[j].File {
+				return flag.Usages[i].File < flag.Usages[j].File
+			}
+			return flag.Usages[i].Line < flag.Usages[j].Line
+		})
+		sort.Strings(flag.Providers)
+		result = append(result, *flag)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// extractFlagUsages finds flag evaluations in a source file. A call matched by several
+// patterns is reported once, with custom helpers taking precedence.
+func extractFlagUsages(content, filePath string, helpers []*regexp.Regexp) []flagOccurrence {
+	patterns := make([]flagPattern, 0, len(helpers)+len(flagPatterns))
+	for _, helper := range helpers {
+		patterns = append(patterns, flagPattern{provider: FlagProviderCustom, re: helper})
+	}
+	patterns = append(patterns, flagPatterns...)
+
+	var occurrences []flagOccurrence
+	seen := make(map[int]bool) // Offset of the flag key
+	for _, pattern := range patterns {
+		for _, m := range pattern.re.FindAllStringSubmatchIndex(content, -1) {
+			if seen[m[2]] {
+				continue
+			}
+			seen[m[2]] = true
+			occurrences = append(occurrences, flagOccurrence{
+				key: content[m[2]:m[3]],
+				usage: FlagUsage{
+					File:     filePath,
+					Line:     strings.Count(content[:m[0]], "\n") + 1,
+					Provider: pattern.provider,
+					Call:     strings.Join(strings.Fields(content[m[0]:m[1]]), " "),
+				},
+			})
+		}
+	}
+	return occurrences
+}
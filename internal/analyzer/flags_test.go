@@ -0,0 +1,79 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileFlagHelpers(t *testing.T) {
+	helpers, err := CompileFlagHelpers([]string{"isFeatureEnabled", "*.FlagOn", " "})
+	require.NoError(t, err)
+	require.Len(t, helpers, 2)
+
+	assert.True(t, helpers[0].MatchString(`if (isFeatureEnabled("beta-search")) {`))
+	assert.True(t, helpers[1].MatchString(`if cfg.FlagOn("dark-mode") {`))
+	assert.False(t, helpers[1].MatchString(`if FlagOn("dark-mode") {`), "a receiver is required by the pattern")
+}
+
+func TestFeatureFlags(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"web/checkout.ts": `export function renderCheckout(user) {
+  if (ldClient.variation('new-checkout', false)) {
+    return newCheckout(user);
+  }
+  return legacy(user);
+}
+`,
+		"api/handlers.go": `package api
+
+func Checkout(w http.ResponseWriter, r *http.Request) {
+	enabled, _ := ld.BoolVariation("new-checkout", ctx, false)
+	if features.FlagOn("rate-limit-v2") {
+		limit()
+	}
+}
+`,
+		"worker/jobs.py": `def run():
+    if unleash.is_enabled("batch-exports"):
+        export()
+`,
+		"api/handlers_test.go": `package api
+
+func TestCheckout(t *testing.T) {
+	ld.BoolVariation("test-only-flag", ctx, false)
+}
+`,
+	}
+	testutils.WriteTree(t, dir, files)
+
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	helpers, err := CompileFlagHelpers([]string{"features.FlagOn"})
+	require.NoError(t, err)
+	flags := FeatureFlags(graph, helpers)
+
+	var keys []string
+	for _, flag := range flags {
+		keys = append(keys, flag.Key)
+	}
+	assert.Equal(t, []string{"batch-exports", "new-checkout", "rate-limit-v2"}, keys, "test files are not indexed")
+
+	checkout := flags[1]
+	assert.Equal(t, []string{FlagProviderLaunchDarkly}, checkout.Providers)
+	require.Len(t, checkout.Usages, 2)
+	assert.Equal(t, 2, checkout.Files())
+	assert.Equal(t, filepath.Join(dir, "api", "handlers.go"), checkout.Usages[0].File)
+	assert.Equal(t, 4, checkout.Usages[0].Line)
+	assert.Equal(t, "Checkout", checkout.Usages[0].Symbol)
+	assert.Equal(t, `.BoolVariation("new-checkout"`, checkout.Usages[0].Call)
+	assert.Equal(t, "renderCheckout", checkout.Usages[1].Symbol)
+
+	assert.Equal(t, []string{FlagProviderCustom}, flags[2].Providers)
+	assert.Equal(t, []string{FlagProviderUnleash}, flags[0].Providers)
+}
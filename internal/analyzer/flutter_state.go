@@ -0,0 +1,393 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Kinds of Flutter state holders
+const (
+	FlutterStateBloc           = "bloc"
+	FlutterStateCubit          = "cubit"
+	FlutterStateRiverpod       = "riverpod"
+	FlutterStateChangeNotifier = "provider" // ChangeNotifier classes shared with the provider package
+)
+
+var (
+	flutterClass = regexp.MustCompile(`(?m)^(?:(?:abstract|sealed|final|base)\s+)*class\s+(\w+)(?:<[^>{]*>)?((?:\s+(?:extends|with|implements)\s+[^{]+)?)\s*\{`)
+	// The holder a class extends: Bloc<Event, State>, Cubit<State>, ChangeNotifier or a Riverpod notifier
+	flutterHolderBase = regexp.MustCompile(`\b(?:extends\s+(?:Hydrated)?(Bloc|Cubit)\s*<|(?:extends|with)\s+(ChangeNotifier)\b|extends\s+((?:Async|Stream|State)?Notifier)\s*<|extends\s+_\$(\w+))`)
+	flutterStateOf    = regexp.MustCompile(`\bextends\s+(?:Consumer)?State<\s*([A-Z]\w*)\s*>`)
+	flutterWidgetBase = regexp.MustCompile(`\bextends\s+(?:Stateless|Stateful|Consumer|ConsumerStateful|Hook|HookConsumer)Widget\b`)
+
+	riverpodProvider  = regexp.MustCompile(`(?m)^(?:final|const|var)\s+(\w+)\s*=\s*((?:Async|Stream)?Notifier(?:Provider)?|StateNotifierProvider|StateProvider|FutureProvider|StreamProvider|ChangeNotifierProvider|Provider)\b(?:\.(?:family|autoDispose))*(?:\s*<[^(]*>)?\s*(?:\.(?:family|autoDispose))*\s*\(`)
+	riverpodGenerated = regexp.MustCompile(`(?m)^@[Rr]iverpod(?:\([^)]*\))?\s+(?:(class)\s+(\w+)|(?:[\w<>?,\s]+?)\s+(\w+)\s*\()`)
+	riverpodRef       = regexp.MustCompile(`\bref\.(watch|read|listen)\(\s*(\w+)`)
+	flutterContextUse = regexp.MustCompile(`\bcontext\.(watch|read|select)<\s*([A-Z]\w*)\s*[,>]|\b(?:Bloc|MultiBloc)?Provider\.of<\s*([A-Z]\w*)\s*>\(`)
+	flutterBuilderUse = regexp.MustCompile(`\b(Consumer|Selector|BlocBuilder|BlocListener|BlocConsumer|BlocSelector)<\s*([A-Z]\w*)\s*[,>]`)
+	flutterProvided   = regexp.MustCompile(`\b(?:BlocProvider|ChangeNotifierProvider|ListenableProvider|RepositoryProvider)(?:<\s*([A-Z]\w*)\s*>)?(?:\.value)?\(\s*(?:key\s*:[^,]*,\s*)?(?:create\s*:\s*\([^)]*\)\s*=>\s*|value\s*:\s*)([A-Z]\w*)\s*\(`)
+	// Fields are declared at the first indentation level of a class body
+	dartFieldType       = regexp.MustCompile(`(?m)^(?: {2}|\t)(?:late\s+)?(?:final\s+)?([A-Z]\w*)(?:<[^>;]*>)?\??\s+_?\w+\s*[;=]`)
+	dartConstructorArgs = regexp.MustCompile(`(?:^|[\s(,{])(?:required\s+)?([A-Z]\w*)(?:<[^>]*>)?\??\s+_?\w+\s*(?:[,)}=])`)
+	dartInstantiation   = regexp.MustCompile(`\b([A-Z]\w*)\s*(?:\(|\.new\b)`)
+)
+
+// FlutterStateHolder is a Bloc, Cubit, Riverpod provider or ChangeNotifier,
+// with the repositories and providers it depends on and the widgets using it
+type FlutterStateHolder struct {
+	Name         string            `json:"name"` // Class or provider name
+	Kind         string            `json:"kind"` // bloc, cubit, riverpod or provider
+	Type         string            `json:"type,omitempty"`
+	File         string            `json:"file"` // Relative to the project root
+	Line         int               `json:"line"`
+	Dependencies []string          `json:"dependencies,omitempty"` // Project classes and providers it depends on
+	Consumers    []FlutterConsumer `json:"consumers,omitempty"`
+}
+
+// FlutterConsumer is a widget reading, watching or providing a state holder
+type FlutterConsumer struct {
+	Widget string `json:"widget"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Access string `json:"access"` // watch, read, listen, select, provide or the builder widget used
+}
+
+// FlutterStateReport is the state architecture of a Flutter project
+type FlutterStateReport struct {
+	Holders      []FlutterStateHolder `json:"holders"`
+	Repositories []string             `json:"repositories,omitempty"` // Dependencies that are not state holders
+	Widgets      int                  `json:"widgets"`                // Widgets using any state holder
+}
+
+// Counts returns the number of state holders of each kind
+func (r *FlutterStateReport) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, holder := range r.Holders {
+		counts[holder.Kind]++
+	}
+	return counts
+}
+
+// flutterClassInfo is a class of a Dart file and its body
+type flutterClassInfo struct {
+	name, header, body, file string
+	line, start, end         int
+}
+
+// AnalyzeFlutterState maps the Blocs, Cubits, Riverpod providers and
+// ChangeNotifiers of a project's Dart files to the widgets that watch,
+// read, listen to or provide them and to the repositories, services and
+// other providers they depend on. It returns nil when the project has none.
+func AnalyzeFlutterState(graph *types.CodeGraph, root string) *FlutterStateReport {
+	files := make(map[string]string)
+	(&RelationshipAnalyzer{graph: graph}).forEachSourceFileIn(map[string]bool{"dart": true}, func(filePath, content string) {
+		files[filePath] = content
+	})
+	var paths []string
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	classes := make(map[string]*flutterClassInfo)
+	fileClasses := make(map[string][]*flutterClassInfo)
+	for _, filePath := range paths {
+		content := files[filePath]
+		for _, m := range flutterClass.FindAllStringSubmatchIndex(content, -1) {
+			end := matchingBrace(content, m[1]-1)
+			class := &flutterClassInfo{
+				name: content[m[2]:m[3]], header: content[m[4]:m[5]], body: content[m[1]:end],
+				file: filePath, line: lineAt(content, m[0]), start: m[0], end: end,
+			}
+			if classes[class.name] == nil {
+				classes[class.name] = class
+			}
+			fileClasses[filePath] = append(fileClasses[filePath], class)
+		}
+	}
+
+	holders := make(map[string]*FlutterStateHolder)
+	var order []string
+	add := func(holder *FlutterStateHolder) {
+		if holders[holder.Name] == nil {
+			holders[holder.Name] = holder
+			order = append(order, holder.Name)
+		}
+	}
+	for _, filePath := range paths {
+		for _, class := range fileClasses[filePath] {
+			m := flutterHolderBase.FindStringSubmatch(class.header)
+			if m == nil {
+				continue
+			}
+			holder := &FlutterStateHolder{Name: class.name, File: projectPath(root, filePath), Line: class.line}
+			switch {
+			case m[1] == "Bloc":
+				holder.Kind, holder.Type = FlutterStateBloc, "Bloc"
+			case m[1] == "Cubit":
+				holder.Kind, holder.Type = FlutterStateCubit, "Cubit"
+			case m[2] != "":
+				holder.Kind, holder.Type = FlutterStateChangeNotifier, "ChangeNotifier"
+			case m[3] != "":
+				// Notifier classes are exposed through a provider declared separately
+				holder.Kind, holder.Type = FlutterStateRiverpod, m[3]
+			default:
+				// @riverpod classes extend the generated _$Name and are read as nameProvider
+				holder.Kind, holder.Type = FlutterStateRiverpod, "Notifier"
+				holder.Name = lowerFirst(class.name) + "Provider"
+			}
+			holder.Dependencies = classDependencies(class, classes)
+			add(holder)
+		}
+
+		content := files[filePath]
+		for _, m := range riverpodProvider.FindAllStringSubmatchIndex(content, -1) {
+			body := content[m[1]:dartStatementEnd(content, m[1])]
+			holder := &FlutterStateHolder{Name: content[m[2]:m[3]], Kind: FlutterStateRiverpod, Type: content[m[4]:m[5]], File: projectPath(root, filePath), Line: lineAt(content, m[0])}
+			holder.Dependencies = bodyDependencies(body, classes)
+			add(holder)
+		}
+		for _, m := range riverpodGenerated.FindAllStringSubmatchIndex(content, -1) {
+			if m[2] >= 0 {
+				continue // Classes are handled with the other notifiers
+			}
+			name := content[m[6]:m[7]]
+			open := strings.IndexAny(content[m[1]:], "{=")
+			body := ""
+			if open >= 0 {
+				if content[m[1]+open] == '{' {
+					body = content[m[1]+open : matchingBrace(content, m[1]+open)]
+				} else {
+					body = content[m[1]+open : dartStatementEnd(content, m[1]+open)]
+				}
+			}
+			holder := &FlutterStateHolder{Name: name + "Provider", Kind: FlutterStateRiverpod, Type: "Provider", File: projectPath(root, filePath), Line: lineAt(content, m[0])}
+			holder.Dependencies = bodyDependencies(body, classes)
+			add(holder)
+		}
+	}
+	if len(holders) == 0 {
+		return nil
+	}
+	// A Notifier class is reported once, as the provider exposing it
+	merged := make(map[string]bool)
+	for _, name := range order {
+		holder := holders[name]
+		if holder.Kind != FlutterStateRiverpod {
+			continue
+		}
+		var dependencies []string
+		for _, dependency := range holder.Dependencies {
+			if notifier := holders[dependency]; notifier != nil && notifier.Kind == FlutterStateRiverpod && classes[dependency] != nil {
+				merged[dependency] = true
+				dependencies = append(dependencies, notifier.Dependencies...)
+				continue
+			}
+			dependencies = append(dependencies, dependency)
+		}
+		holder.Dependencies = sortedUnique(dependencies)
+	}
+
+	widgets := make(map[string]bool)
+	for _, filePath := range paths {
+		content := files[filePath]
+		record := func(name string, offset int, access string) {
+			holder := holders[name]
+			if holder == nil {
+				return
+			}
+			class := enclosingFlutterClass(fileClasses[filePath], offset)
+			if class == nil || flutterHolderBase.MatchString(class.header) {
+				return // Holders using each other are dependencies
+			}
+			widget := class.name
+			if m := flutterStateOf.FindStringSubmatch(class.header); m != nil {
+				widget = m[1]
+			} else if !flutterWidgetBase.MatchString(class.header) {
+				return
+			}
+			consumer := FlutterConsumer{Widget: widget, File: projectPath(root, filePath), Line: lineAt(content, offset), Access: access}
+			for _, existing := range holder.Consumers {
+				if existing == consumer {
+					return
+				}
+			}
+			holder.Consumers = append(holder.Consumers, consumer)
+			widgets[filePath+"\x00"+widget] = true
+		}
+		for _, m := range riverpodRef.FindAllStringSubmatchIndex(content, -1) {
+			record(content[m[4]:m[5]], m[0], content[m[2]:m[3]])
+		}
+		for _, m := range flutterContextUse.FindAllStringSubmatchIndex(content, -1) {
+			if m[2] >= 0 {
+				record(content[m[4]:m[5]], m[0], content[m[2]:m[3]])
+			} else {
+				record(content[m[6]:m[7]], m[0], "read")
+			}
+		}
+		for _, m := range flutterBuilderUse.FindAllStringSubmatchIndex(content, -1) {
+			record(content[m[4]:m[5]], m[0], content[m[2]:m[3]])
+		}
+		for _, m := range flutterProvided.FindAllStringSubmatchIndex(content, -1) {
+			name := content[m[4]:m[5]]
+			if m[2] >= 0 {
+				name = content[m[2]:m[3]]
+			}
+			record(name, m[0], "provide")
+		}
+	}
+
+	report := &FlutterStateReport{Widgets: len(widgets)}
+	repositories := make(map[string]bool)
+	for _, name := range order {
+		holder := holders[name]
+		if merged[name] {
+			continue
+		}
+		for _, dependency := range holder.Dependencies {
+			if holders[dependency] == nil {
+				repositories[dependency] = true
+			}
+		}
+		sort.Slice(holder.Consumers, func(i, j int) bool {
+			if holder.Consumers[i].File != holder.Consumers[j].File {
+				return holder.Consumers[i].File < holder.Consumers[j].File
+			}
+			return holder.Consumers[i].Line < holder.Consumers[j].Line
+		})
+		report.Holders = append(report.Holders, *holder)
+	}
+	for name := range repositories {
+		report.Repositories = append(report.Repositories, name)
+	}
+	sort.Strings(report.Repositories)
+	sort.Slice(report.Holders, func(i, j int) bool {
+		if len(report.Holders[i].Consumers) != len(report.Holders[j].Consumers) {
+			return len(report.Holders[i].Consumers) > len(report.Holders[j].Consumers)
+		}
+		return report.Holders[i].Name < report.Holders[j].Name
+	})
+	return report
+}
+
+// classDependencies returns the project classes a state holder class keeps
+// in fields or receives in its constructors, and the providers it reads
+func classDependencies(class *flutterClassInfo, classes map[string]*flutterClassInfo) []string {
+	seen := make(map[string]bool)
+	for _, m := range dartFieldType.FindAllStringSubmatch(class.body, -1) {
+		seen[m[1]] = true
+	}
+	constructor := regexp.MustCompile(`\b` + regexp.QuoteMeta(class.name) + `(?:\.\w+)?\s*\(`)
+	for _, loc := range constructor.FindAllStringIndex(class.body, -1) {
+		args := class.body[loc[1]:]
+		if end := closingParen(args); end >= 0 {
+			args = args[:end+1]
+		}
+		for _, m := range dartConstructorArgs.FindAllStringSubmatch(args, -1) {
+			seen[m[1]] = true
+		}
+	}
+	var names []string
+	for name := range seen {
+		if isFlutterDependency(name, class.name, classes) {
+			names = append(names, name)
+		}
+	}
+	for _, m := range riverpodRef.FindAllStringSubmatch(class.body, -1) {
+		names = append(names, m[2])
+	}
+	return sortedUnique(names)
+}
+
+// bodyDependencies returns the providers a provider body reads and the
+// project classes it creates
+func bodyDependencies(body string, classes map[string]*flutterClassInfo) []string {
+	var names []string
+	for _, m := range riverpodRef.FindAllStringSubmatch(body, -1) {
+		names = append(names, m[2])
+	}
+	for _, m := range dartInstantiation.FindAllStringSubmatch(body, -1) {
+		if isFlutterDependency(m[1], "", classes) {
+			names = append(names, m[1])
+		}
+	}
+	return sortedUnique(names)
+}
+
+// isFlutterDependency reports whether a type is a project class other than
+// a widget or the class itself
+func isFlutterDependency(name, self string, classes map[string]*flutterClassInfo) bool {
+	class := classes[name]
+	if class == nil || name == self {
+		return false
+	}
+	return !flutterWidgetBase.MatchString(class.header) && flutterStateOf.FindStringSubmatch(class.header) == nil
+}
+
+// enclosingFlutterClass returns the class of a file whose body holds offset
+func enclosingFlutterClass(classes []*flutterClassInfo, offset int) *flutterClassInfo {
+	for _, class := range classes {
+		if offset >= class.start && offset < class.end {
+			return class
+		}
+	}
+	return nil
+}
+
+// dartStatementEnd returns the offset of the semicolon ending the statement
+// starting at start, skipping nested brackets
+func dartStatementEnd(content string, start int) int {
+	depth := 0
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ';':
+			if depth <= 0 {
+				return i
+			}
+		}
+	}
+	return len(content)
+}
+
+// closingParen returns the index of the parenthesis closing the list text
+// starts inside of, or -1
+func closingParen(text string) int {
+	depth := 1
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func sortedUnique(names []string) []string {
+	sort.Strings(names)
+	var unique []string
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
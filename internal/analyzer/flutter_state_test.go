@@ -0,0 +1,61 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeFlutterState(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lib/data/auth_repository.dart": "class AuthRepository {\n  Future<User> login(String name) async => User(name);\n}\n\nclass User {\n  User(this.name);\n  final String name;\n}\n",
+		"lib/auth/auth_bloc.dart": "abstract class AuthEvent {}\n\nclass LoginRequested extends AuthEvent {}\n\n" +
+			"class AuthBloc extends Bloc<AuthEvent, AuthState> {\n  AuthBloc({required this.repository}) : super(AuthInitial()) {\n    on<LoginRequested>((event, emit) async {\n      final User user = await repository.login('ada');\n    });\n  }\n\n  final AuthRepository repository;\n}\n",
+		"lib/cart/cart.dart": "class CartModel extends ChangeNotifier {\n  final List<String> items = [];\n}\n",
+		"lib/providers.dart": "final authRepositoryProvider = Provider((ref) => AuthRepository());\n\n" +
+			"class Counter extends Notifier<int> {\n  @override\n  int build() => ref.watch(authRepositoryProvider).hashCode;\n}\n\n" +
+			"final counterProvider = NotifierProvider<Counter, int>(Counter.new);\n",
+		"lib/app.dart": "class App extends StatelessWidget {\n  Widget build(BuildContext context) {\n    return BlocProvider(\n      create: (_) => AuthBloc(repository: AuthRepository()),\n" +
+			"      child: ChangeNotifierProvider(create: (_) => CartModel(), child: const HomePage()),\n    );\n  }\n}\n",
+		"lib/home_page.dart": "class HomePage extends ConsumerStatefulWidget {\n  const HomePage({super.key});\n}\n\n" +
+			"class _HomePageState extends ConsumerState<HomePage> {\n  Widget build(BuildContext context) {\n    final count = ref.watch(counterProvider);\n    final cart = context.watch<CartModel>();\n" +
+			"    return BlocBuilder<AuthBloc, AuthState>(\n      builder: (context, state) => Text('$count'),\n    );\n  }\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	report := AnalyzeFlutterState(graph, dir)
+	require.NotNil(t, report)
+	holders := make(map[string]FlutterStateHolder)
+	for _, holder := range report.Holders {
+		holders[holder.Name] = holder
+	}
+	assert.Len(t, report.Holders, 4, "the Counter notifier is reported with counterProvider")
+	assert.Equal(t, map[string]int{"bloc": 1, "provider": 1, "riverpod": 2}, report.Counts())
+
+	auth := holders["AuthBloc"]
+	assert.Equal(t, FlutterStateBloc, auth.Kind)
+	assert.Equal(t, "lib/auth/auth_bloc.dart", auth.File)
+	assert.Equal(t, []string{"AuthRepository"}, auth.Dependencies, "locals of methods are not dependencies")
+	assert.Equal(t, []FlutterConsumer{
+		{Widget: "App", File: "lib/app.dart", Line: 3, Access: "provide"},
+		{Widget: "HomePage", File: "lib/home_page.dart", Line: 9, Access: "BlocBuilder"},
+	}, auth.Consumers)
+
+	counter := holders["counterProvider"]
+	assert.Equal(t, "NotifierProvider", counter.Type)
+	assert.Equal(t, []string{"authRepositoryProvider"}, counter.Dependencies)
+	assert.Equal(t, []FlutterConsumer{{Widget: "HomePage", File: "lib/home_page.dart", Line: 7, Access: "watch"}}, counter.Consumers)
+
+	cart := holders["CartModel"]
+	assert.Equal(t, FlutterStateChangeNotifier, cart.Kind)
+	assert.Len(t, cart.Consumers, 2)
+
+	assert.Equal(t, []string{"AuthRepository"}, holders["authRepositoryProvider"].Dependencies)
+	assert.Equal(t, []string{"AuthRepository"}, report.Repositories)
+	assert.Equal(t, 2, report.Widgets)
+}
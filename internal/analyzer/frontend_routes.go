@@ -0,0 +1,663 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Ways frontend routes are declared
+const (
+	FrontendRouterJSX      = "react-router" // <Route path=... element={<Page />}>
+	FrontendRouterConfig   = "route-config" // { path: ..., component: Page } in React Router, Vue Router or Angular
+	FrontendRouterNextPage = "next-pages"   // Next.js pages/ directory
+	FrontendRouterNextApp  = "next-app"     // Next.js app/ directory
+)
+
+var (
+	// React Router elements
+	jsxRouteTag       = regexp.MustCompile(`<Route\b|</Route\s*>`)
+	jsxRoutePath      = regexp.MustCompile(`\bpath\s*=\s*\{?\s*["'` + "`" + `]([^"'` + "`" + `]*)["'` + "`" + `]`)
+	jsxRouteIndex     = regexp.MustCompile(`\sindex(?:\s|/|>|=\{true\})`)
+	jsxRouteElement   = regexp.MustCompile(`\belement\s*=\s*\{`)
+	jsxRouteComponent = regexp.MustCompile(`\b(?:component|Component)\s*=\s*\{\s*([A-Z][\w.]*)`)
+	jsxComponentTag   = regexp.MustCompile(`<([A-Z][\w.]*)`)
+
+	// Route objects
+	routeObjectPath      = regexp.MustCompile(`\bpath\s*:\s*["'` + "`" + `]([^"'` + "`" + `]*)["'` + "`" + `]`)
+	routeObjectComponent = regexp.MustCompile(`\b(?:component|Component|element)\s*:\s*<?\s*([A-Z][\w.]*)`)
+	routeObjectLazy      = regexp.MustCompile(`\b(?:component|loadComponent|loadChildren|lazy)\s*:\s*(?:async\s*)?\(\)\s*=>\s*import\(\s*["']([^"']+)["']\s*\)(?:\s*\.then\(\s*\(?\s*\w+\s*\)?\s*=>\s*\w+\.(\w+))?`)
+
+	jsDefaultExport = regexp.MustCompile(`\bexport\s+default\s+(?:async\s+)?(?:function\s*\*?|class)?\s*(\w+)`)
+	jsIdentifier    = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+	jsStringLiteral = regexp.MustCompile(`"(?:[^"\\\n]|\\.)*"|'(?:[^'\\\n]|\\.)*'`)
+	// Declarations at the start of a line: functions, classes and variables, exported or not
+	jsTopLevelDeclaration = regexp.MustCompile(`(?m)^(?:export\s+)?(?:default\s+)?(?:async\s+)?(?:function\s*\*?|class|const|let|var)\s+([A-Za-z_$][\w$]*)`)
+)
+
+// frontendLanguages are the languages frontend routes and components are read from
+var frontendLanguages = map[string]bool{"javascript": true, "typescript": true}
+
+// FrontendRoute is a page of a web frontend and the backend endpoints its
+// components call
+type FrontendRoute struct {
+	Path          string            `json:"path"` // Including the paths of the routes it is nested in
+	Router        string            `json:"router"`
+	Component     string            `json:"component,omitempty"`
+	File          string            `json:"file"` // Where the route is declared
+	Line          int               `json:"line"`
+	ComponentFile string            `json:"component_file,omitempty"`
+	Files         []string          `json:"files"` // Files of the components, hooks and modules the page uses, sorted
+	Calls         []FrontendAPICall `json:"calls"`
+}
+
+// FrontendAPICall is a backend endpoint called from a page's code
+type FrontendAPICall struct {
+	Endpoint   Endpoint `json:"endpoint"`
+	ClientPath string   `json:"client_path"` // URL as written at the call site
+	File       string   `json:"file"`
+	Line       int      `json:"line"`
+	Handler    string   `json:"handler,omitempty"` // Route handler, or the symbol around the endpoint definition
+}
+
+// frontendRef is an exported name of a file: "default" for its default
+// export, "" for the whole module
+type frontendRef struct {
+	file, name string
+}
+
+// FindFrontendRoutes detects the routes of web frontends (React Router
+// elements, route objects of React Router, Vue Router and Angular, and the
+// Next.js pages and app directories) and follows each page's component
+// through the components, hooks and modules it uses to the fetch and axios
+// calls matched to backend endpoints by the calls-service relationships.
+// Only the imported names a component refers to are followed, so a page
+// using one function of an API module is not charged with the others.
+func FindFrontendRoutes(graph *types.CodeGraph) []FrontendRoute {
+	ra := &RelationshipAnalyzer{graph: graph}
+	calls := make(map[string][]*types.GraphEdge)
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipCallsService) {
+			file, _ := edge.Metadata["source_file"].(string)
+			calls[file] = append(calls[file], edge)
+		}
+	}
+
+	contents := make(map[string]string)
+	var routes []FrontendRoute
+	var roots [][]frontendRef
+	ra.forEachSourceFileIn(frontendLanguages, func(filePath, content string) {
+		contents[filePath] = content
+		if !strings.Contains(content, "path") {
+			return
+		}
+		imports := ra.javaScriptImports(filePath, content)
+		// Components come from an import or are declared in the file
+		component := func(name string) frontendRef {
+			if ref, ok := imports[strings.SplitN(name, ".", 2)[0]]; ok {
+				return frontendRef{ref.file, ref.name}
+			}
+			for _, id := range graph.Files[filePath].Symbols {
+				if symbol := graph.Symbols[id]; symbol != nil && symbol.Name == name {
+					return frontendRef{filePath, name}
+				}
+			}
+			return frontendRef{}
+		}
+		for _, route := range jsxRoutes(content) {
+			routes = append(routes, FrontendRoute{Path: route.path, Router: FrontendRouterJSX, Component: route.components[len(route.components)-1], File: filePath, Line: route.line})
+			var refs []frontendRef
+			for _, name := range route.components {
+				refs = append(refs, component(name))
+			}
+			roots = append(roots, refs)
+		}
+		for _, route := range routeObjects(content) {
+			ref := component(route.component)
+			if route.importPath != "" {
+				ref = frontendRef{ra.resolveJavaScriptImport(route.importPath, filePath), firstNonEmpty(route.component, "default")}
+			}
+			routes = append(routes, FrontendRoute{Path: route.path, Router: FrontendRouterConfig, Component: route.component, File: filePath, Line: route.line})
+			roots = append(roots, []frontendRef{ref})
+		}
+	})
+	for _, page := range nextPages(graph) {
+		routes = append(routes, page)
+		roots = append(roots, []frontendRef{{page.File, "default"}})
+	}
+
+	if len(routes) == 0 {
+		return nil
+	}
+
+	// Handlers of routes registered at the top level of a file come from the route itself
+	handlers := make(map[string]string)
+	for _, chain := range FindMiddlewareChains(graph) {
+		handlers[fmt.Sprintf("%s:%d", chain.File, chain.Line)] = chain.Handler
+	}
+	walk := &componentWalk{ra: ra, calls: calls, handlers: handlers, contents: contents, imports: make(map[string]map[string]importRef)}
+	for i := range routes {
+		route := &routes[i]
+		if file := roots[i][len(roots[i])-1].file; graph.Files[file] != nil {
+			route.ComponentFile = file
+		}
+		if route.Component == "" && route.ComponentFile != "" {
+			route.Component = defaultExportName(walk.content(route.ComponentFile))
+		}
+		walk.follow(route, roots[i])
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		if routes[i].File != routes[j].File {
+			return routes[i].File < routes[j].File
+		}
+		return routes[i].Line < routes[j].Line
+	})
+	return routes
+}
+
+// componentWalk follows page components through the names they use
+type componentWalk struct {
+	ra       *RelationshipAnalyzer
+	calls    map[string][]*types.GraphEdge // calls-service edges by calling file
+	handlers map[string]string             // Route handlers by "file:line"
+	contents map[string]string
+	imports  map[string]map[string]importRef
+}
+
+func (w *componentWalk) content(file string) string {
+	if text, ok := w.contents[file]; ok {
+		return text
+	}
+	data, _ := os.ReadFile(file)
+	w.contents[file] = string(data)
+	return w.contents[file]
+}
+
+// follow collects the files a route's components reach and the endpoints
+// called from them. Each exported name is read on its own: the identifiers
+// of its declaration lead to the imports and declarations of the file it
+// uses, and calls inside the declaration are the ones counted.
+func (w *componentWalk) follow(route *FrontendRoute, roots []frontendRef) {
+	graph := w.ra.graph
+	files := make(map[string]bool)
+	seenCalls := make(map[types.EdgeId]bool)
+	visited := make(map[frontendRef]bool)
+	queue := append([]frontendRef(nil), roots...)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		fileNode := graph.Files[ref.file]
+		if visited[ref] || fileNode == nil || !frontendLanguages[fileNode.Language] {
+			continue
+		}
+		visited[ref] = true
+		files[ref.file] = true
+
+		content := w.content(ref.file)
+		text, first, last := declarationText(content, ref.name)
+		for _, edge := range w.calls[ref.file] {
+			line := metadataInt(edge.Metadata["source_line"])
+			if !seenCalls[edge.Id] && line >= first && line <= last {
+				seenCalls[edge.Id] = true
+				route.Calls = append(route.Calls, w.call(edge))
+			}
+		}
+
+		if w.imports[ref.file] == nil {
+			w.imports[ref.file] = w.ra.javaScriptImports(ref.file, content)
+		}
+		used := make(map[string]bool)
+		var names []string
+		for _, name := range jsIdentifier.FindAllString(jsStringLiteral.ReplaceAllString(text, ``), -1) {
+			if !used[name] {
+				used[name] = true
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			target, imported := w.imports[ref.file][name]
+			switch {
+			case imported && target.name != "":
+				queue = append(queue, frontendRef{target.file, target.name})
+			case imported:
+				// Namespace imports follow the members used through them
+				members := regexp.MustCompile(`\b`+regexp.QuoteMeta(name)+`\.(\w+)`).FindAllStringSubmatch(text, -1)
+				if len(members) == 0 {
+					queue = append(queue, frontendRef{target.file, ""})
+				}
+				for _, member := range members {
+					queue = append(queue, frontendRef{target.file, member[1]})
+				}
+			case ref.name != "" && name != ref.name && jsDeclarationOffset(content, name) >= 0:
+				queue = append(queue, frontendRef{ref.file, name})
+			}
+		}
+	}
+
+	route.Files = make([]string, 0, len(files))
+	for file := range files {
+		route.Files = append(route.Files, file)
+	}
+	sort.Strings(route.Files)
+	sort.Slice(route.Calls, func(a, b int) bool {
+		if route.Calls[a].File != route.Calls[b].File {
+			return route.Calls[a].File < route.Calls[b].File
+		}
+		return route.Calls[a].Line < route.Calls[b].Line
+	})
+}
+
+// call describes the endpoint a calls-service edge leads to
+func (w *componentWalk) call(edge *types.GraphEdge) FrontendAPICall {
+	str := func(key string) string {
+		value, _ := edge.Metadata[key].(string)
+		return value
+	}
+	call := FrontendAPICall{
+		Endpoint: Endpoint{
+			Protocol: str("protocol"),
+			Method:   str("method"),
+			Path:     str("path"),
+			File:     str("target_file"),
+			Line:     metadataInt(edge.Metadata["target_line"]),
+		},
+		ClientPath: str("client_path"),
+		File:       str("source_file"),
+		Line:       metadataInt(edge.Metadata["source_line"]),
+	}
+	if handler, ok := w.handlers[fmt.Sprintf("%s:%d", call.Endpoint.File, call.Endpoint.Line)]; ok {
+		call.Handler = handler
+	} else if symbol := w.ra.graph.Symbols[types.SymbolId(strings.TrimPrefix(string(edge.To), "symbol-"))]; symbol != nil {
+		call.Handler = symbol.Name
+	}
+	return call
+}
+
+// declarationText returns the source of a top-level declaration and its
+// first and last lines. "default" names the default export; the whole file
+// is returned for "" and for names it does not declare.
+func declarationText(content, name string) (string, int, int) {
+	start := -1
+	switch name {
+	case "":
+	case "default":
+		// export default Name; refers to a declaration elsewhere in the file
+		if start = jsDeclarationOffset(content, defaultExportName(content)); start < 0 {
+			if loc := jsDefaultExport.FindStringIndex(content); loc != nil {
+				start = loc[0]
+			}
+		}
+	default:
+		start = jsDeclarationOffset(content, name)
+	}
+	if start < 0 {
+		return content, 1, strings.Count(content, "\n") + 1
+	}
+
+	end := len(content)
+	if blank := strings.Index(content[start:], "\n\n"); blank >= 0 {
+		end = start + blank
+	}
+	if brace := declarationBody(content[start:end]); brace >= 0 {
+		if content[start+brace] == '{' {
+			end = matchingBrace(content, start+brace) + 1
+		} else {
+			end = start + brace + 1
+		}
+	}
+	end = min(end, len(content))
+	return content[start:end], strings.Count(content[:start], "\n") + 1, strings.Count(content[:end], "\n") + 1
+}
+
+// declarationBody returns the offset of the brace opening a declaration's
+// body, or of the semicolon ending a declaration without one, or -1.
+// Parameter lists and return type annotations are skipped, so destructured
+// props as in function Orders({ id }) do not open the body.
+func declarationBody(text string) int {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			i = skipTypeAnnotation(text, matchingParen(text, i)+1) - 1
+		case '{', ';':
+			return i
+		}
+	}
+	return -1
+}
+
+// skipTypeAnnotation returns the offset after a ": Type" annotation at start,
+// or start when there is none
+func skipTypeAnnotation(text string, start int) int {
+	i := start
+	for i < len(text) && (text[i] == ' ' || text[i] == '\t') {
+		i++
+	}
+	if i >= len(text) || text[i] != ':' {
+		return start
+	}
+	i++
+	for i < len(text) && (text[i] == ' ' || text[i] == '\t') {
+		i++
+	}
+	// Object types are braced like the body that follows them
+	if i < len(text) && text[i] == '{' {
+		i = matchingBrace(text, i) + 1
+	}
+	depth := 0
+	for ; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '=' && i+1 < len(text) && text[i+1] == '>' && depth == 0:
+			return i
+		case c == '<' || c == '(' || c == '[':
+			depth++
+		case c == '>' || c == ')' || c == ']':
+			depth--
+		case (c == '{' || c == ';') && depth == 0:
+			return i
+		}
+	}
+	return i
+}
+
+// jsDeclarationOffset returns the offset of the top-level function, class
+// or variable declaring a name, or -1
+func jsDeclarationOffset(content, name string) int {
+	for _, loc := range jsTopLevelDeclaration.FindAllStringSubmatchIndex(content, -1) {
+		if content[loc[2]:loc[3]] == name {
+			return loc[0]
+		}
+	}
+	return -1
+}
+
+// defaultExportName returns the name a file exports by default, or "" for
+// anonymous default exports
+func defaultExportName(content string) string {
+	if m := jsDefaultExport.FindStringSubmatch(content); m != nil && m[1] != "function" && m[1] != "class" {
+		return m[1]
+	}
+	return ""
+}
+
+// declaredRoute is a route found in a router definition
+type declaredRoute struct {
+	path       string
+	line       int
+	components []string // JSX elements, outermost first
+	component  string
+	importPath string // Lazily loaded module of route objects
+}
+
+// jsxRoutes finds the <Route> elements of a file that render a component,
+// with the paths of the routes they are nested in
+func jsxRoutes(content string) []declaredRoute {
+	var routes []declaredRoute
+	var parents []string
+	for _, loc := range jsxRouteTag.FindAllStringIndex(content, -1) {
+		if content[loc[0]+1] == '/' {
+			if len(parents) > 0 {
+				parents = parents[:len(parents)-1]
+			}
+			continue
+		}
+		end := jsxTagEnd(content, loc[1])
+		tag := content[loc[0]:end]
+		parent := ""
+		if len(parents) > 0 {
+			parent = parents[len(parents)-1]
+		}
+		path := parent
+		if m := jsxRoutePath.FindStringSubmatch(tag); m != nil && !jsxRouteIndex.MatchString(tag) {
+			path = nestedRoutePath(parent, m[1])
+		}
+		if !strings.HasSuffix(strings.TrimSpace(tag), "/>") {
+			parents = append(parents, path)
+		}
+
+		var components []string
+		if m := jsxRouteElement.FindStringIndex(tag); m != nil {
+			element := tag[m[1]:matchingBrace(tag, m[1]-1)]
+			for _, c := range jsxComponentTag.FindAllStringSubmatch(element, -1) {
+				components = append(components, c[1])
+			}
+		} else if m := jsxRouteComponent.FindStringSubmatch(tag); m != nil {
+			components = []string{m[1]}
+		}
+		if len(components) > 0 {
+			routes = append(routes, declaredRoute{path: firstNonEmpty(path, "/"), line: strings.Count(content[:loc[0]], "\n") + 1, components: components})
+		}
+	}
+	return routes
+}
+
+// jsxTagEnd returns the offset just past the ">" closing the tag that
+// starts before from, skipping attribute expressions and strings
+func jsxTagEnd(content string, from int) int {
+	depth := 0
+	var quote byte
+	for i := from; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case c == '>' && depth <= 0:
+			return i + 1
+		}
+	}
+	return len(content)
+}
+
+// routeObjects finds the route objects of a file that name a component or
+// load one lazily, with the paths of the route objects they are nested in
+func routeObjects(content string) []declaredRoute {
+	type object struct {
+		start, end int
+		path       string
+		own        string // The object without its nested objects and arrays
+	}
+	var objects []*object
+	for _, loc := range routeObjectPath.FindAllStringSubmatchIndex(content, -1) {
+		start := enclosingBrace(content, loc[0])
+		if start < 0 {
+			continue
+		}
+		end := matchingBrace(content, start)
+		objects = append(objects, &object{start: start, end: end, path: content[loc[2]:loc[3]], own: topLevelText(content[start+1 : end])})
+	}
+
+	full := make(map[*object]string)
+	var routes []declaredRoute
+	for _, obj := range objects {
+		parent := ""
+		var enclosing *object
+		for _, other := range objects {
+			if other != obj && other.start < obj.start && other.end > obj.end && (enclosing == nil || other.start > enclosing.start) {
+				enclosing = other
+			}
+		}
+		if enclosing != nil {
+			parent = full[enclosing]
+		}
+		full[obj] = nestedRoutePath(parent, obj.path)
+		if !routeObjectPath.MatchString(obj.own) {
+			continue // The path belongs to a nested object
+		}
+
+		route := declaredRoute{path: full[obj], line: strings.Count(content[:obj.start], "\n") + 1}
+		if m := routeObjectLazy.FindStringSubmatch(obj.own); m != nil {
+			route.importPath, route.component = m[1], m[2]
+		} else if m := routeObjectComponent.FindStringSubmatch(obj.own); m != nil {
+			route.component = m[1]
+		} else {
+			continue
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// nestedRoutePath resolves a route path against the path of the route it is
+// nested in; paths starting with "/" are absolute
+func nestedRoutePath(parent, path string) string {
+	if strings.HasPrefix(path, "/") {
+		return path
+	}
+	return joinRoutePath(firstNonEmpty(parent, "/"), path)
+}
+
+// enclosingBrace returns the offset of the unclosed "{" before offset, or -1
+func enclosingBrace(content string, offset int) int {
+	depth := 0
+	for i := offset - 1; i >= 0; i-- {
+		switch content[i] {
+		case '}':
+			depth++
+		case '{':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// matchingBrace returns the offset of the "}" closing the "{" at start
+func matchingBrace(content string, start int) int {
+	depth := 0
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(content)
+}
+
+// matchingParen returns the offset of the ")" closing the "(" at start
+func matchingParen(content string, start int) int {
+	depth := 0
+	for i := start; i < len(content); i++ {
+		switch content[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(content)
+}
+
+// topLevelText blanks the nested objects and arrays of an object's body
+func topLevelText(body string) string {
+	out := []byte(body)
+	depth := 0
+	for i, c := range out {
+		switch c {
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+		}
+		if depth > 0 || c == '}' || c == ']' {
+			out[i] = ' '
+		}
+	}
+	return string(out)
+}
+
+// nextPages returns the pages of the Next.js projects in the graph, found
+// by their next.config file or, failing that, imports of next
+func nextPages(graph *types.CodeGraph) []FrontendRoute {
+	var roots, paths []string
+	usesNext := false
+	for path := range graph.Files {
+		paths = append(paths, path)
+		if strings.HasPrefix(filepath.Base(path), "next.config.") {
+			roots = append(roots, filepath.Dir(path))
+		}
+	}
+	if len(roots) == 0 {
+		for _, edge := range graph.Edges {
+			if edge.Type == string(RelationshipImport) {
+				if spec, _ := edge.Metadata["import_path"].(string); spec == "next" || strings.HasPrefix(spec, "next/") {
+					usesNext = true
+					break
+				}
+			}
+		}
+		if !usesNext {
+			return nil
+		}
+		roots = append(roots, commonDir(paths))
+	}
+
+	var pages []FrontendRoute
+	for _, path := range paths {
+		fileNode := graph.Files[path]
+		ext := filepath.Ext(path)
+		if fileNode.IsTest || !frontendLanguages[fileNode.Language] {
+			continue
+		}
+		for _, root := range roots {
+			rel, err := filepath.Rel(root, path)
+			if err != nil || strings.HasPrefix(rel, "..") {
+				continue
+			}
+			rel = strings.TrimPrefix(filepath.ToSlash(rel), "src/")
+			var route, router string
+			if rest, ok := strings.CutPrefix(rel, "pages/"); ok {
+				rest = strings.TrimSuffix(rest, ext)
+				if strings.HasPrefix(rest, "api/") || strings.HasPrefix(filepath.Base(rest), "_") {
+					continue
+				}
+				if rest == "index" {
+					rest = ""
+				}
+				route, router = "/"+strings.TrimSuffix(rest, "/index"), FrontendRouterNextPage
+			} else if rest, ok := strings.CutPrefix(rel, "app/"); ok && strings.TrimSuffix(filepath.Base(rest), ext) == "page" {
+				var segments []string
+				for _, segment := range strings.Split(filepath.ToSlash(filepath.Dir(rest)), "/") {
+					// Route groups and parallel route slots are not part of the URL
+					if segment != "." && !strings.HasPrefix(segment, "(") && !strings.HasPrefix(segment, "@") {
+						segments = append(segments, segment)
+					}
+				}
+				route, router = "/"+strings.Join(segments, "/"), FrontendRouterNextApp
+			} else {
+				continue
+			}
+			if route != "/" {
+				route = strings.TrimSuffix(route, "/")
+			}
+			pages = append(pages, FrontendRoute{Path: route, Router: router, File: path, Line: 1})
+			break
+		}
+	}
+	return pages
+}
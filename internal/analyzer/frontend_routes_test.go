@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindFrontendRoutes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"server/app.js": "const express = require('express');\nconst app = express();\napp.get('/api/users/:id', getUser);\napp.get('/api/orders', listOrders);\n" +
+			"app.post('/api/cart', addToCart);\napp.get('/api/stats', stats);\n",
+		// An API module whose functions are used by different pages
+		"web/src/api.ts": "import axios from 'axios';\n\nexport function getUser(id: string) {\n  return axios.get(`/api/users/${id}`);\n}\n\n" +
+			"export function getOrders() {\n  return fetch('/api/orders');\n}\n\nexport const addToCart = (item: string) => axios.post('/api/cart', { item });\n",
+		// React Router elements with a layout route, an index route and a wrapped element
+		"web/src/App.tsx": "import { Routes, Route } from 'react-router-dom';\nimport AdminLayout from './AdminLayout';\nimport { Dashboard } from './Dashboard';\nimport { UserDetail } from './UserDetail';\n\n" +
+			"export function App() {\n  return (\n    <Routes>\n      <Route path=\"/admin\" element={<AdminLayout />}>\n        <Route index element={<Dashboard />} />\n" +
+			"        <Route path=\"users/:id\" element={<RequireAuth><UserDetail /></RequireAuth>} />\n      </Route>\n    </Routes>\n  );\n}\n",
+		"web/src/AdminLayout.tsx": "export default function AdminLayout() {\n  return <div />;\n}\n",
+		"web/src/Dashboard.tsx": "import * as api from './api';\n\nexport function Dashboard() {\n  const orders = api.getOrders();\n  return <div>{loadStats()}</div>;\n}\n\n" +
+			"function loadStats() {\n  return fetch('/api/stats');\n}\n",
+		"web/src/UserDetail.tsx": "import { getUser } from './api';\n\nexport function UserDetail() {\n  getUser('1');\n  return <div />;\n}\n",
+		// Route objects with a nested, lazily loaded child
+		"web/src/router.ts": "import ShopLayout from './ShopLayout.vue';\n\nexport const routes = [\n  {\n    path: '/shop',\n    component: ShopLayout,\n" +
+			"    children: [\n      { path: 'cart', component: () => import('./Cart') },\n    ],\n  },\n];\n",
+		"web/src/Cart.tsx": "import { addToCart } from './api';\n\nexport default function Cart() {\n  return <button onClick={() => addToCart('x')} />;\n}\n",
+		// Next.js pages and app directories
+		"storefront/next.config.js":                   "module.exports = {};\n",
+		"storefront/pages/index.tsx":                  "export default function Home() {\n  return <main />;\n}\n",
+		"storefront/pages/orders/[id].tsx":            "export default function Order() {\n  fetch('/api/orders');\n  return <main />;\n}\n",
+		"storefront/pages/_app.tsx":                   "export default function MyApp() {\n  return null;\n}\n",
+		"storefront/pages/api/health.ts":              "export default function handler() {}\n",
+		"storefront/app/(marketing)/pricing/page.tsx": "export default function Pricing() {\n  return <main />;\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	var summary []string
+	for _, route := range FindFrontendRoutes(graph) {
+		line := fmt.Sprintf("%s %s %s", route.Router, route.Path, route.Component)
+		if route.ComponentFile != "" {
+			line += " in " + projectPath(dir, route.ComponentFile)
+		}
+		for _, call := range route.Calls {
+			line += fmt.Sprintf(" | %s from %s:%d to %s", call.Endpoint, projectPath(dir, call.File), call.Line, call.Handler)
+		}
+		summary = append(summary, line)
+	}
+	assert.Equal(t, []string{
+		"next-pages / Home in storefront/pages/index.tsx",
+		"react-router /admin AdminLayout in web/src/AdminLayout.tsx",
+		"react-router /admin Dashboard in web/src/Dashboard.tsx | GET /api/stats from web/src/Dashboard.tsx:9 to stats | GET /api/orders from web/src/api.ts:8 to listOrders",
+		"react-router /admin/users/:id UserDetail in web/src/UserDetail.tsx | GET /api/users/:id from web/src/api.ts:4 to getUser",
+		"next-pages /orders/[id] Order in storefront/pages/orders/[id].tsx | GET /api/orders from storefront/pages/orders/[id].tsx:2 to listOrders",
+		"next-app /pricing Pricing in storefront/app/(marketing)/pricing/page.tsx",
+		"route-config /shop ShopLayout",
+		"route-config /shop/cart Cart in web/src/Cart.tsx | POST /api/cart from web/src/api.ts:11 to addToCart",
+	}, summary, "pages only reach the API functions they use; Next.js API routes and _app are not pages")
+}
+
+func TestDeclarationText(t *testing.T) {
+	content := "import x from 'x';\n\nconst Page = () => {\n  return 1;\n\n};\n\nexport default Page;\n"
+	text, first, last := declarationText(content, "default")
+	assert.Equal(t, "const Page = () => {\n  return 1;\n\n}", text)
+	assert.Equal(t, []int{3, 6}, []int{first, last})
+
+	_, first, last = declarationText(content, "missing")
+	assert.Equal(t, []int{1, 9}, []int{first, last}, "unknown names stand for the whole file")
+}
+
+func TestDeclarationTextDestructuredProps(t *testing.T) {
+	content := "export function Orders({ id }) {\n  return fetch(`/api/orders/${id}`);\n}\n\n" +
+		"const Cart = ({ items }: { items: Item[] }): JSX.Element => {\n  return <List items={items} />;\n};\n\n" +
+		"const Total = ({ sum }) => (\n  <span>{sum}</span>\n);\n"
+
+	text, first, last := declarationText(content, "Orders")
+	assert.Equal(t, "export function Orders({ id }) {\n  return fetch(`/api/orders/${id}`);\n}", text)
+	assert.Equal(t, []int{1, 3}, []int{first, last})
+
+	text, first, last = declarationText(content, "Cart")
+	assert.Equal(t, "const Cart = ({ items }: { items: Item[] }): JSX.Element => {\n  return <List items={items} />;\n}", text)
+	assert.Equal(t, []int{5, 7}, []int{first, last}, "the props and return type do not open the body")
+
+	_, first, last = declarationText(content, "Total")
+	assert.Equal(t, []int{9, 11}, []int{first, last})
+}
@@ -0,0 +1,390 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Kinds of global state
+const (
+	GlobalVariable  = "variable"  // A package-level or module-level variable
+	GlobalStatic    = "static"    // A static field of a class
+	GlobalSingleton = "singleton" // A shared instance, such as `static let shared` or `INSTANCE`
+)
+
+// globalStateLanguages are the languages scanned for global mutable state
+var globalStateLanguages = map[string]bool{
+	"go": true, "javascript": true, "typescript": true, "python": true, "java": true, "kotlin": true,
+	"dart": true, "cpp": true, "rust": true, "swift": true,
+}
+
+var (
+	goGlobalVarPattern      = regexp.MustCompile(`^var\s+(\w+)\b(.*)$`)
+	goGlobalVarBlockPattern = regexp.MustCompile(`^\t(\w+)\b(.*)$`)
+	jsGlobalVarPattern      = regexp.MustCompile(`^(?:export\s+)?(?:let|var)\s+(\w+)\b(.*)$`)
+	pythonGlobalVarPattern  = regexp.MustCompile(`^(\w+)\s*(?::\s*[^=]+)?=\s*([^=].*)$`)
+	pythonGlobalDeclPattern = regexp.MustCompile(`^\s+global\s+([\w\s,]+)$`)
+	javaStaticFieldPattern  = regexp.MustCompile(`^\s+(?:(?:public|private|protected|volatile|transient)\s+)*static\s+(?:(?:volatile|transient)\s+)*(?:[\w.]+(?:<[^=;]*>)?(?:\[\])*)\s+(\w+)\s*(=.*|;.*)$`)
+	kotlinTopVarPattern     = regexp.MustCompile(`^(?:(?:private|internal|public)\s+)?(?:lateinit\s+)?var\s+(\w+)\b(.*)$`)
+	dartGlobalVarPattern    = regexp.MustCompile(`^(?:late\s+)?(?:var|int|double|num|bool|String|[A-Z][\w<>,?\s]*?)\??\s+(\w+)\s*(=.*|;.*)$`)
+	dartStaticFieldPattern  = regexp.MustCompile(`^\s+static\s+(?:late\s+)?(?:var|[\w<>,?]+)\s+(\w+)\s*(=.*|;.*)$`)
+	cppGlobalVarPattern     = regexp.MustCompile(`^(?:static\s+|extern\s+|inline\s+)*(?:[\w:]+(?:<[^;()]*>)?)\s*[*&]?\s*(\w+)\s*(=[^=].*|;.*|\{.*)$`)
+	rustStaticPattern       = regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?static\s+(mut\s+)?(\w+)\s*:\s*([^=]+)(=.*)?$`)
+	swiftGlobalVarPattern   = regexp.MustCompile(`^(?:(?:public|private|fileprivate|internal)\s+)?var\s+(\w+)\b(.*)$`)
+	swiftStaticPattern      = regexp.MustCompile(`^\s+(?:(?:public|private|fileprivate|internal)\s+)?(?:static|class)\s+(var|let)\s+(\w+)\b(.*)$`)
+
+	// Initializers that are effectively constant: compiled patterns, sentinel errors, templates
+	constantInitializerPattern = regexp.MustCompile(`\b(?:regexp\.MustCompile|errors\.New|template\.Must|re\.compile|Pattern\.compile)\(`)
+	// Rust types that are mutable through a shared reference
+	rustInteriorMutabilityPattern = regexp.MustCompile(`\b(?:Mutex|RwLock|RefCell|Cell|OnceLock|OnceCell|Lazy|Atomic\w+)\b`)
+	// Names used for shared instances
+	singletonNamePattern = regexp.MustCompile(`(?i)^_?(?:instance|shared|singleton|default(?:instance)?|current)$`)
+	// Words, for indexing the lines that may mention a global
+	globalWordPattern = regexp.MustCompile(`\w+`)
+	// Python literals and calls that create mutable containers
+	pythonMutableValuePattern = regexp.MustCompile(`^(?:\[|\{|(?:list|dict|set|defaultdict|OrderedDict|Counter|deque)\()`)
+)
+
+// GlobalState is a package- or module-level variable, static field or
+// singleton, with the functions that modify it after initialization
+type GlobalState struct {
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Language string   `json:"language"`
+	Mutators []string `json:"mutators,omitempty"` // Functions that assign or modify it, as name (file:line)
+}
+
+// globalDeclaration is a global found in a file, before its mutations are known
+type globalDeclaration struct {
+	GlobalState
+	dir string
+}
+
+// AnalyzeGlobalState finds package-level mutable variables, static fields and
+// singletons and the functions that modify them. Constants, final fields and
+// variables initialized with compiled patterns or sentinel errors are left out.
+// Mutations are found by name: assignments, increments, index assignments and
+// mutating calls such as append, push or put. Globals modified by the most
+// functions come first, since they couple code the import graph does not link.
+func AnalyzeGlobalState(graph *types.CodeGraph) []GlobalState {
+	ra := &RelationshipAnalyzer{graph: graph}
+	type sourceFile struct {
+		path, language string
+		lines          []string
+	}
+	var files []sourceFile
+	var globals []*globalDeclaration
+	(&RelationshipAnalyzer{graph: graph}).forEachSourceFileIn(globalStateLanguages, func(filePath, content string) {
+		file := sourceFile{path: filePath, language: graph.Files[filePath].Language, lines: strings.Split(content, "\n")}
+		files = append(files, file)
+		for _, global := range findGlobals(file.lines, file.language) {
+			global.File = filePath
+			global.Language = file.language
+			global.dir = filepath.Dir(filePath)
+			globals = append(globals, global)
+		}
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	// Each file is matched against one pattern naming every global it may
+	// modify, and only on lines that mention one of them
+	byLanguage := make(map[string][]*globalDeclaration)
+	for _, global := range globals {
+		byLanguage[global.Language] = append(byLanguage[global.Language], global)
+	}
+	targetsByScope := make(map[string]*mutationTargets)
+	for _, file := range files {
+		// Go globals are visible unqualified within their directory only
+		scope := file.language
+		if file.language == "go" {
+			scope += ":" + filepath.Dir(file.path)
+		}
+		targets, ok := targetsByScope[scope]
+		if !ok {
+			targets = newMutationTargets(filepath.Dir(file.path), byLanguage[file.language])
+			targetsByScope[scope] = targets
+		}
+		if targets == nil {
+			continue
+		}
+		var pythonGlobals map[string]bool
+		if file.language == "python" {
+			pythonGlobals = declaredPythonGlobals(file.lines)
+		}
+		index := identifierLines(file.lines)
+		var candidates []int
+		for name := range targets.names {
+			candidates = append(candidates, index[name]...)
+		}
+		sort.Ints(candidates)
+		for n, i := range candidates {
+			if n > 0 && candidates[n-1] == i {
+				continue
+			}
+			line := file.lines[i]
+			for _, match := range targets.pattern.FindAllStringSubmatch(line, -1) {
+				ref := match[1] + match[2] + match[4] + match[5]
+				for _, global := range targets.globals[ref] {
+					if file.path == global.File && i+1 == global.Line {
+						continue
+					}
+					// A plain assignment in a Python function makes a local unless declared global
+					if global.Language == "python" && !pythonGlobals[global.Name] && match[3] != "" {
+						continue
+					}
+					symbol := ra.enclosingSymbol(file.path, i+1)
+					if symbol == nil {
+						continue
+					}
+					global.Mutators = appendUnique(global.Mutators, fmt.Sprintf("%s (%s:%d)", symbol.Name, filepath.Base(file.path), symbol.Location.StartLine))
+				}
+			}
+		}
+	}
+
+	result := make([]GlobalState, len(globals))
+	for i, global := range globals {
+		result[i] = global.GlobalState
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Mutators) != len(result[j].Mutators) {
+			return len(result[i].Mutators) > len(result[j].Mutators)
+		}
+		if result[i].File != result[j].File {
+			return result[i].File < result[j].File
+		}
+		return result[i].Line < result[j].Line
+	})
+	return result
+}
+
+// findGlobals returns the mutable globals declared in a file
+func findGlobals(lines []string, language string) []*globalDeclaration {
+	var globals []*globalDeclaration
+	add := func(name, kind, initializer string, line int) {
+		if name == "_" || constantInitializerPattern.MatchString(initializer) {
+			return
+		}
+		if singletonNamePattern.MatchString(name) {
+			kind = GlobalSingleton
+		}
+		globals = append(globals, &globalDeclaration{GlobalState: GlobalState{Name: name, Kind: kind, Line: line}})
+	}
+
+	var pythonGlobals map[string]bool
+	if language == "python" {
+		pythonGlobals = declaredPythonGlobals(lines)
+	}
+
+	inVarBlock := false
+	depth := 0 // Brace depth, for languages whose globals sit at the top level
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		topLevel := depth == 0
+		// C++ globals are usually declared inside namespaces
+		if !(language == "cpp" && strings.HasPrefix(trimmed, "namespace")) {
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+		}
+		if depth < 0 {
+			depth = 0
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+
+		switch language {
+		case "go":
+			switch {
+			case line == "var (":
+				inVarBlock = true
+			case inVarBlock && line == ")":
+				inVarBlock = false
+			case inVarBlock:
+				if m := goGlobalVarBlockPattern.FindStringSubmatch(line); m != nil && !strings.HasPrefix(m[1], "Err") {
+					add(m[1], GlobalVariable, m[2], i+1)
+				}
+			default:
+				if m := goGlobalVarPattern.FindStringSubmatch(line); m != nil && !strings.HasPrefix(m[1], "Err") {
+					add(m[1], GlobalVariable, m[2], i+1)
+				}
+			}
+		case "javascript", "typescript":
+			if m := jsGlobalVarPattern.FindStringSubmatch(line); m != nil && topLevel {
+				add(m[1], GlobalVariable, m[2], i+1)
+			}
+		case "python":
+			// Module-level containers, and any module-level name a function declares global
+			if m := pythonGlobalVarPattern.FindStringSubmatch(line); m != nil {
+				value := strings.TrimSpace(m[2])
+				if pythonMutableValuePattern.MatchString(value) || pythonGlobals[m[1]] {
+					add(m[1], GlobalVariable, "", i+1)
+				}
+			}
+		case "java", "kotlin":
+			if strings.Contains(line, " final ") || strings.Contains(line, "(") && !strings.Contains(line, "=") {
+				continue
+			}
+			if m := javaStaticFieldPattern.FindStringSubmatch(line); m != nil {
+				add(m[1], GlobalStatic, m[2], i+1)
+			} else if m := kotlinTopVarPattern.FindStringSubmatch(line); m != nil && language == "kotlin" && topLevel {
+				add(m[1], GlobalVariable, m[2], i+1)
+			}
+		case "dart":
+			if strings.Contains(line, "final ") || strings.Contains(line, "const ") {
+				continue
+			}
+			if m := dartGlobalVarPattern.FindStringSubmatch(line); m != nil && topLevel && !isDartDeclarationKeyword(m[1]) {
+				add(m[1], GlobalVariable, m[2], i+1)
+			} else if m := dartStaticFieldPattern.FindStringSubmatch(line); m != nil {
+				add(m[1], GlobalStatic, m[2], i+1)
+			}
+		case "cpp":
+			if !topLevel || strings.Contains(line, "(") && !strings.Contains(line, "=") {
+				continue
+			}
+			first := strings.Fields(trimmed)[0]
+			switch first {
+			case "const", "constexpr", "constinit", "typedef", "using", "return", "class", "struct", "enum", "union", "namespace", "template", "#include", "#define", "extern\"C\"":
+				continue
+			}
+			if strings.Contains(line, " const ") || strings.Contains(line, "constexpr") {
+				continue
+			}
+			if m := cppGlobalVarPattern.FindStringSubmatch(line); m != nil {
+				add(m[1], GlobalVariable, m[2], i+1)
+			}
+		case "rust":
+			if m := rustStaticPattern.FindStringSubmatch(line); m != nil && (m[1] != "" || rustInteriorMutabilityPattern.MatchString(m[3])) {
+				add(m[2], GlobalStatic, "", i+1)
+			}
+		case "swift":
+			if m := swiftStaticPattern.FindStringSubmatch(line); m != nil {
+				// `static let shared = Foo()` is a singleton; other lets are constants
+				if m[1] == "let" && !singletonNamePattern.MatchString(m[2]) {
+					continue
+				}
+				add(m[2], GlobalStatic, m[3], i+1)
+			} else if m := swiftGlobalVarPattern.FindStringSubmatch(line); m != nil && topLevel {
+				add(m[1], GlobalVariable, m[2], i+1)
+			}
+		}
+	}
+	return globals
+}
+
+// mutationTargets are the globals a file may modify, with one pattern
+// matching a modification of any of them
+type mutationTargets struct {
+	pattern *regexp.Regexp
+	names   map[string]bool                 // Names of the globals; lines without one are skipped
+	globals map[string][]*globalDeclaration // Globals by the reference the pattern captures
+}
+
+// newMutationTargets collects the globals of one language that code in dir
+// may modify: all of them, except that Go globals from other directories are
+// only reachable exported and qualified with their package name. It returns
+// nil when there are none.
+func newMutationTargets(dir string, globals []*globalDeclaration) *mutationTargets {
+	targets := &mutationTargets{names: make(map[string]bool), globals: make(map[string][]*globalDeclaration)}
+	var local, qualified []string
+	for _, global := range globals {
+		ref := global.Name
+		if global.Language == "go" && global.dir != dir {
+			if !isExportedGoName(global.Name) {
+				continue
+			}
+			ref = filepath.Base(global.dir) + "." + global.Name
+			if targets.globals[ref] == nil {
+				qualified = append(qualified, ref)
+			}
+		} else if targets.globals[ref] == nil {
+			local = append(local, ref)
+		}
+		targets.globals[ref] = append(targets.globals[ref], global)
+		targets.names[global.Name] = true
+	}
+	if len(targets.globals) == 0 {
+		return nil
+	}
+	targets.pattern = mutationPattern(local, qualified)
+	return targets
+}
+
+// mutationPattern matches statements that modify a global, referred to on
+// its own by one of the local names or as one of the qualified
+// qualifier.name references; never as a field of some other value. The
+// reference is captured by group 1 (local), 2 (qualified), 4 (delete) or 5
+// (append). Group 3 is set for plain assignments, which Python only treats as
+// global with a global declaration.
+func mutationPattern(local, qualified []string) *regexp.Regexp {
+	alternation := func(refs ...[]string) string {
+		var all []string
+		for _, r := range refs {
+			all = append(all, r...)
+		}
+		if len(all) == 0 {
+			return `[^\s\S]` // Matches nothing
+		}
+		// Longest first, so a name is not matched by a prefix of it
+		sort.Slice(all, func(i, j int) bool {
+			if len(all[i]) != len(all[j]) {
+				return len(all[i]) > len(all[j])
+			}
+			return all[i] < all[j]
+		})
+		quoted := make([]string, len(all))
+		for i, ref := range all {
+			quoted[i] = regexp.QuoteMeta(ref)
+		}
+		return strings.Join(quoted, "|")
+	}
+	all := alternation(local, qualified)
+	return regexp.MustCompile(`(?:(?:^|[^\w.])(` + alternation(local) + `)|\b(` + alternation(qualified) + `))` +
+		`(?:(\s*=[^=])|\s*(?:\+|-|\*|/|\||&|\?\?)=|\s*(?:\+\+|--)|\s*\[[^\]]*\]\s*=[^=]|\.(?:append|push|add|put|set|insert|remove|delete|clear|pop|update|extend|unshift|splice|Store|Add|Swap|Set|Delete)\w*\()` +
+		`|\bdelete\(\s*(` + all + `)\s*,|=\s*append\(\s*(` + all + `)\b`)
+}
+
+// identifierLines indexes the lines of a file by the words on them
+func identifierLines(lines []string) map[string][]int {
+	index := make(map[string][]int)
+	for i, line := range lines {
+		for _, word := range globalWordPattern.FindAllString(line, -1) {
+			if n := len(index[word]); n == 0 || index[word][n-1] != i {
+				index[word] = append(index[word], i)
+			}
+		}
+	}
+	return index
+}
+
+// declaredPythonGlobals returns the names functions in a file declare global
+func declaredPythonGlobals(lines []string) map[string]bool {
+	declared := make(map[string]bool)
+	for _, line := range lines {
+		if m := pythonGlobalDeclPattern.FindStringSubmatch(line); m != nil {
+			for _, name := range strings.Split(m[1], ",") {
+				declared[strings.TrimSpace(name)] = true
+			}
+		}
+	}
+	return declared
+}
+
+func isExportedGoName(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
+
+func isDartDeclarationKeyword(name string) bool {
+	switch name {
+	case "class", "enum", "mixin", "extension", "typedef", "import", "export", "part", "library":
+		return true
+	}
+	return false
+}
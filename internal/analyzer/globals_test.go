@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeGlobalState(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"registry/registry.go": `package registry
+
+import (
+	"errors"
+	"regexp"
+)
+
+var ErrMissing = errors.New("missing")
+
+var (
+	handlers = map[string]Handler{}
+	namePattern = regexp.MustCompile("^[a-z]+$")
+	Verbose bool
+)
+
+func Register(name string, h Handler) {
+	handlers[name] = h
+}
+
+func Reset() {
+	handlers = map[string]Handler{}
+	Verbose = false
+}
+
+func Lookup(name string) Handler {
+	return handlers[name]
+}
+`,
+		"cmd/main.go": `package main
+
+func configure() {
+	registry.Verbose = true
+}
+`,
+		"state.py": `cache = {}
+MAX_SIZE = 100
+counter = 0
+
+
+def remember(key, value):
+    cache[key] = value
+
+
+def bump():
+    global counter
+    counter += 1
+`,
+		"Config.java": `public class Config {
+    private static Config instance;
+    private static final int LIMIT = 10;
+    static int loads = 0;
+
+    public static Config get() {
+        if (instance == null) {
+            instance = new Config();
+        }
+        loads++;
+        return instance;
+    }
+}
+`,
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	globals := make(map[string]GlobalState)
+	for _, global := range AnalyzeGlobalState(graph) {
+		globals[global.Name] = global
+	}
+	assert.NotContains(t, globals, "ErrMissing", "sentinel errors are constant")
+	assert.NotContains(t, globals, "namePattern", "compiled patterns are constant")
+	assert.NotContains(t, globals, "MAX_SIZE", "module-level scalars without a global declaration are constants")
+	assert.NotContains(t, globals, "LIMIT")
+
+	assert.Equal(t, GlobalState{Name: "handlers", Kind: GlobalVariable, File: filepath.Join(dir, "registry", "registry.go"), Line: 11, Language: "go",
+		Mutators: []string{"Register (registry.go:16)", "Reset (registry.go:20)"}}, globals["handlers"])
+	assert.ElementsMatch(t, []string{"Reset (registry.go:20)", "configure (main.go:3)"}, globals["Verbose"].Mutators)
+
+	assert.Equal(t, []string{"remember (state.py:6)"}, globals["cache"].Mutators)
+	assert.Equal(t, []string{"bump (state.py:10)"}, globals["counter"].Mutators)
+
+	assert.Equal(t, GlobalSingleton, globals["instance"].Kind)
+	assert.Equal(t, []string{"get (Config.java:6)"}, globals["instance"].Mutators)
+	assert.Equal(t, GlobalStatic, globals["loads"].Kind)
+
+	markdown := NewMarkdownGenerator(graph).GenerateContextMap()
+	assert.Contains(t, markdown, "## 🌐 Global Mutable State")
+	assert.Contains(t, markdown, "| `handlers` | variable | `registry.go:11` | Register (registry.go:16), Reset (registry.go:20) |")
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// goPredeclaredConstraints are embedded in constraint interfaces and name no
+// declaration worth linking to
+var goPredeclaredConstraints = map[string]bool{"any": true, "comparable": true}
+
+// analyzeGoEmbedding links Go types to the types they embed. An interface
+// embedding another interface extends it; a struct embedding a field
+// contains it and gains its promoted fields and methods. Embedded types
+// declared outside the project, such as sync.Mutex, point to an external node.
+func (ra *RelationshipAnalyzer) analyzeGoEmbedding(metrics *RelationshipMetrics) {
+	all, _ := ra.goTypes()
+	for _, t := range all {
+		relationship := RelationshipContains
+		if t.symbol.NormalizedKind() == types.SymbolKindInterface {
+			relationship = RelationshipExtends
+		}
+		for _, embed := range t.symbol.MetadataStrings(parser.MetadataEmbeds) {
+			name, _ := goEmbeddedName(embed)
+			if goPredeclaredConstraints[name] {
+				continue
+			}
+			from := types.NodeId("symbol-" + string(t.symbol.Id))
+			metadata := map[string]interface{}{
+				"embedded": embed,
+				"language": "go",
+			}
+			if relationship == RelationshipExtends {
+				metadata["supertype"] = name
+			}
+			var to types.NodeId
+			if target := goResolveType(name, t.dir, all); target != nil && target.symbol.Id != t.symbol.Id {
+				to = types.NodeId("symbol-" + string(target.symbol.Id))
+			} else {
+				to = types.NodeId("external-type-" + name)
+				metadata["is_external"] = true
+			}
+
+			edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", relationship, from, to))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:       edgeId,
+				From:     from,
+				To:       to,
+				Type:     string(relationship),
+				Weight:   1.0,
+				Metadata: metadata,
+			}
+			metrics.ByType[relationship]++
+			metrics.SymbolToSymbol++
+		}
+	}
+}
+
+// promoteGoMethods adds the methods promoted from the embedded fields of a
+// struct to its method sets. Embedding T promotes the value methods of T to
+// the struct and all methods of T to a pointer to it; embedding *T or an
+// interface promotes all of their methods to both.
+func promoteGoMethods(t goType, all []goType, sets goMethodSets, resolved map[types.SymbolId][]string, promoted, visiting map[string]bool) {
+	key := goTypeKey(t.dir, t.symbol.Name)
+	if promoted[key] || visiting[key] {
+		return
+	}
+	visiting[key] = true
+	defer delete(visiting, key)
+
+	for _, embed := range t.symbol.MetadataStrings(parser.MetadataEmbeds) {
+		name, pointer := goEmbeddedName(embed)
+		var valueMethods, pointerMethods []string
+		if known, ok := goWellKnownInterfaces[name]; ok {
+			valueMethods, pointerMethods = known, known
+		} else if target := goResolveType(name, t.dir, all); target != nil {
+			if target.symbol.NormalizedKind() == types.SymbolKindInterface {
+				if methods, ok := goInterfaceMethods(*target, all, resolved, map[types.SymbolId]bool{}); ok {
+					valueMethods, pointerMethods = methods, methods
+				}
+			} else {
+				promoteGoMethods(*target, all, sets, resolved, promoted, visiting)
+				targetKey := goTypeKey(target.dir, target.symbol.Name)
+				valueMethods, pointerMethods = goMethodNames(sets.value[targetKey]), goMethodNames(sets.pointer[targetKey])
+				if pointer {
+					valueMethods = pointerMethods
+				}
+			}
+		}
+		if len(pointerMethods) == 0 {
+			continue
+		}
+		if sets.pointer[key] == nil {
+			sets.pointer[key] = make(map[string]bool)
+			sets.value[key] = make(map[string]bool)
+		}
+		for _, method := range valueMethods {
+			sets.value[key][method] = true
+		}
+		for _, method := range pointerMethods {
+			sets.pointer[key][method] = true
+		}
+	}
+	promoted[key] = true
+}
+
+// goEmbeddedName returns the type an embedded field names, without type
+// arguments, and whether it is embedded as a pointer
+func goEmbeddedName(embed string) (string, bool) {
+	name := strings.TrimPrefix(embed, "*")
+	if idx := strings.Index(name, "["); idx >= 0 {
+		name = name[:idx]
+	}
+	return name, strings.HasPrefix(embed, "*")
+}
+
+// goMethodNames lists a method set in order
+func goMethodNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoEmbedding(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"store/store.go":  "package store\n\ntype Reader interface {\n\tGet(key string) (string, error)\n}\n\ntype Store interface {\n\tReader\n\tPut(key, value string) error\n}\n\ntype Number interface {\n\t~int | ~float64\n}\n",
+		"store/base.go":   "package store\n\ntype Base struct{}\n\nfunc (b *Base) Get(key string) (string, error) { return \"\", nil }\nfunc (b Base) Put(key, value string) error { return nil }\n",
+		"store/server.go": "package store\n\nimport \"sync\"\n\ntype Server struct {\n\tsync.Mutex\n\tBase\n\tname string\n}\n\ntype Shared struct {\n\t*Base\n}\n\ntype Cache[K comparable, V any] struct {\n\tReader\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	_, err = NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+
+	name := func(node types.NodeId) string {
+		if symbol := graph.Symbols[types.SymbolId(strings.TrimPrefix(string(node), "symbol-"))]; symbol != nil {
+			return symbol.Name
+		}
+		return string(node)
+	}
+	edges := make(map[string]interface{})
+	for _, edge := range graph.Edges {
+		switch edge.Type {
+		case string(RelationshipContains), string(RelationshipExtends):
+			edges[name(edge.From)+" "+edge.Type+" "+name(edge.To)] = edge.Metadata["embedded"]
+		case string(RelationshipImplements):
+			edges[name(edge.From)+" "+edge.Type+" "+name(edge.To)] = edge.Metadata["pointer_receiver"]
+		}
+	}
+	assert.Equal(t, map[string]interface{}{
+		"Store extends Reader":                     "Reader",
+		"Server contains external-type-sync.Mutex": "sync.Mutex",
+		"Server contains Base":                     "Base",
+		"Shared contains Base":                     "*Base",
+		"Cache contains Reader":                    "Reader",
+		"Base implements Reader":                   true,
+		"Base implements Store":                    true,
+		"Server implements Reader":                 true,
+		"Server implements Store":                  true,
+		"Shared implements Reader":                 false,
+		"Shared implements Store":                  false,
+		"Cache implements Reader":                  false,
+	}, edges, "embedding links types and promotes the methods of embedded fields")
+}
@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// goWellKnownInterfaces are the method sets of standard library interfaces
+// that project interfaces often embed, in parser.MetadataMethodSignature form
+var goWellKnownInterfaces = map[string][]string{
+	"any":                nil,
+	"error":              {"Error()string"},
+	"fmt.Stringer":       {"String()string"},
+	"io.Reader":          {"Read([]byte)(int,error)"},
+	"io.Writer":          {"Write([]byte)(int,error)"},
+	"io.Closer":          {"Close()error"},
+	"io.ReadWriter":      {"Read([]byte)(int,error)", "Write([]byte)(int,error)"},
+	"io.ReadCloser":      {"Read([]byte)(int,error)", "Close()error"},
+	"io.WriteCloser":     {"Write([]byte)(int,error)", "Close()error"},
+	"io.ReadWriteCloser": {"Read([]byte)(int,error)", "Write([]byte)(int,error)", "Close()error"},
+	"sort.Interface":     {"Len()int", "Less(int,int)bool", "Swap(int,int)"},
+}
+
+// goType is a Go type with the package directory it is declared in
+type goType struct {
+	symbol *types.Symbol
+	dir    string
+}
+
+// goMethodSets holds the method sets of Go types, keyed by goTypeKey. The
+// pointer method set of T includes the methods declared on T.
+type goMethodSets struct {
+	value   map[string]map[string]bool
+	pointer map[string]map[string]bool
+}
+
+// goTypes collects the Go types of the project and the methods declared on
+// each of them
+func (ra *RelationshipAnalyzer) goTypes() ([]goType, goMethodSets) {
+	var all []goType
+	sets := goMethodSets{value: make(map[string]map[string]bool), pointer: make(map[string]map[string]bool)}
+	paths := make([]string, 0, len(ra.graph.Files))
+	for path, file := range ra.graph.Files {
+		if file.Language == "go" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		for _, symbolId := range ra.graph.Files[path].Symbols {
+			symbol := ra.graph.Symbols[symbolId]
+			if symbol == nil {
+				continue
+			}
+			switch symbol.Type {
+			case types.SymbolTypeType:
+				all = append(all, goType{symbol: symbol, dir: dir})
+			case types.SymbolTypeMethod:
+				receiver := symbol.MetadataString(parser.MetadataReceiver)
+				signature := symbol.MetadataString(parser.MetadataMethodSignature)
+				if receiver == "" || signature == "" {
+					continue
+				}
+				key := goTypeKey(dir, receiver)
+				if sets.pointer[key] == nil {
+					sets.pointer[key] = make(map[string]bool)
+					sets.value[key] = make(map[string]bool)
+				}
+				sets.pointer[key][signature] = true
+				if pointer, _ := symbol.Metadata[parser.MetadataPointerReceiver].(bool); !pointer {
+					sets.value[key][signature] = true
+				}
+			}
+		}
+	}
+	return all, sets
+}
+
+// analyzeGoInterfaces adds implements edges from Go types to the interfaces
+// in the project they satisfy. Go has no implements clause, so a type
+// implements an interface when its method set has every method of the
+// interface, including those of the interfaces it embeds, with the same
+// parameter and result types. Methods promoted from embedded fields count.
+// Constraint interfaces, interfaces embedding types that cannot be resolved
+// and empty interfaces are skipped.
+func (ra *RelationshipAnalyzer) analyzeGoInterfaces(metrics *RelationshipMetrics) {
+	all, sets := ra.goTypes()
+	resolved := make(map[types.SymbolId][]string)
+	var interfaces, concrete []goType
+	for _, t := range all {
+		switch {
+		case t.symbol.NormalizedKind() == types.SymbolKindInterface:
+			interfaces = append(interfaces, t)
+		case t.symbol.Subtype() != "alias":
+			concrete = append(concrete, t)
+		}
+	}
+	promoted := make(map[string]bool)
+	for _, t := range concrete {
+		promoteGoMethods(t, all, sets, resolved, promoted, map[string]bool{})
+	}
+
+	for _, iface := range interfaces {
+		if len(iface.symbol.MetadataStrings(parser.MetadataTypeSet)) > 0 {
+			continue
+		}
+		methods, ok := goInterfaceMethods(iface, all, resolved, map[types.SymbolId]bool{})
+		if !ok || len(methods) == 0 {
+			continue
+		}
+		for _, t := range concrete {
+			key := goTypeKey(t.dir, t.symbol.Name)
+			if !goHasMethods(sets.pointer[key], methods) {
+				continue
+			}
+			from := types.NodeId("symbol-" + string(t.symbol.Id))
+			to := types.NodeId("symbol-" + string(iface.symbol.Id))
+			edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", RelationshipImplements, from, to))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   from,
+				To:     to,
+				Type:   string(RelationshipImplements),
+				Weight: 1.0,
+				Metadata: map[string]interface{}{
+					"supertype":        iface.symbol.Name,
+					"language":         "go",
+					"implicit":         true,
+					"pointer_receiver": !goHasMethods(sets.value[key], methods),
+				},
+			}
+			metrics.ByType[RelationshipImplements]++
+			metrics.SymbolToSymbol++
+		}
+	}
+}
+
+// goInterfaceMethods returns the full method set of an interface, and false
+// when one of its embedded types cannot be resolved to an interface
+func goInterfaceMethods(iface goType, all []goType, resolved map[types.SymbolId][]string, visiting map[types.SymbolId]bool) ([]string, bool) {
+	if methods, ok := resolved[iface.symbol.Id]; ok {
+		return methods, methods != nil
+	}
+	if visiting[iface.symbol.Id] {
+		return nil, false
+	}
+	visiting[iface.symbol.Id] = true
+	defer delete(visiting, iface.symbol.Id)
+
+	methods := append([]string{}, iface.symbol.MetadataStrings(parser.MetadataInterfaceMethods)...)
+	for _, embed := range iface.symbol.MetadataStrings(parser.MetadataEmbeds) {
+		if known, ok := goWellKnownInterfaces[embed]; ok {
+			methods = append(methods, known...)
+			continue
+		}
+		embedded := goResolveType(embed, iface.dir, all)
+		if embedded == nil || embedded.symbol.NormalizedKind() != types.SymbolKindInterface {
+			resolved[iface.symbol.Id] = nil
+			return nil, false
+		}
+		inherited, ok := goInterfaceMethods(*embedded, all, resolved, visiting)
+		if !ok {
+			resolved[iface.symbol.Id] = nil
+			return nil, false
+		}
+		methods = append(methods, inherited...)
+	}
+	resolved[iface.symbol.Id] = methods
+	return methods, true
+}
+
+// goResolveType finds the type a name used in a package refers to: an
+// unqualified name declared in the same package, or a qualified one such as
+// "store.Reader" declared in a package directory named after the qualifier.
+// Type arguments are ignored.
+func goResolveType(name, dir string, all []goType) *goType {
+	if idx := strings.Index(name, "["); idx >= 0 {
+		name = name[:idx]
+	}
+	qualifier := ""
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		qualifier, name = name[:idx], name[idx+1:]
+	}
+	var found *goType
+	for i, candidate := range all {
+		if candidate.symbol.Name != name {
+			continue
+		}
+		if qualifier == "" && candidate.dir == dir || qualifier != "" && filepath.Base(candidate.dir) == qualifier {
+			if found != nil && qualifier != "" {
+				// Ambiguous between packages of the same name
+				return nil
+			}
+			found = &all[i]
+		}
+	}
+	return found
+}
+
+// goHasMethods reports whether a method set has every method in methods
+func goHasMethods(set map[string]bool, methods []string) bool {
+	for _, method := range methods {
+		if !set[method] {
+			return false
+		}
+	}
+	return true
+}
+
+// goTypeKey identifies a Go type by its package directory and name
+func goTypeKey(dir, name string) string {
+	return dir + "\x00" + name
+}
@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoInterfaceImplementations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"store/store.go":  "package store\n\nimport \"io\"\n\ntype Reader interface {\n\tGet(key string) ([]byte, error)\n}\n\ntype Store interface {\n\tReader\n\tio.Closer\n\tPut(key string, value []byte) error\n}\n\ntype Number interface {\n\t~int | ~float64\n}\n\ntype Any interface{}\n",
+		"store/memory.go": "package store\n\ntype Memory struct{ data map[string][]byte }\n\nfunc (m *Memory) Get(k string) ([]byte, error) { return m.data[k], nil }\nfunc (m *Memory) Put(k string, v []byte) error { return nil }\nfunc (m *Memory) Close() error { return nil }\n",
+		"cache/cache.go":  "package cache\n\nimport \"time\"\n\ntype Cache struct{}\n\nfunc (c Cache) Get(key string) ([]byte, error) { return nil, nil }\nfunc (c Cache) TTL() time.Duration { return 0 }\n",
+		"cache/broken.go": "package cache\n\ntype Broken struct{}\n\nfunc (b Broken) Get(key string) []byte { return nil }\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	_, err = NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+
+	implements := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		if edge.Type != string(RelationshipImplements) {
+			continue
+		}
+		from := graph.Symbols[types.SymbolId(edge.From[len("symbol-"):])]
+		to := graph.Symbols[types.SymbolId(edge.To[len("symbol-"):])]
+		require.NotNil(t, from)
+		require.NotNil(t, to)
+		assert.Equal(t, true, edge.Metadata["implicit"])
+		implements[from.Name+" -> "+to.Name] = edge.Metadata["pointer_receiver"].(bool)
+	}
+	assert.Equal(t, map[string]bool{
+		"Memory -> Reader": true,
+		"Memory -> Store":  true,
+		"Cache -> Reader":  false,
+	}, implements, "types implement interfaces whose methods, embedded ones included, they all have")
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -13,6 +14,9 @@ import (
 	"github.com/nuthan-ms/codecontext/internal/cache"
 	"github.com/nuthan-ms/codecontext/internal/git"
 	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/internal/summarize"
+	"github.com/nuthan-ms/codecontext/pkg/plugin"
 	"github.com/nuthan-ms/codecontext/pkg/types"
 )
 
@@ -133,7 +137,7 @@ func getDefaultExcludePatterns() []string {
 			// .NET
 			"bin/**",
 			"obj/**",
-			"packages/**",
+			"packages/*.*/**", // NuGet's Name.Version folders, not JavaScript workspaces
 			".vs/**",
 			"*.dll",
 			"*.exe",
@@ -150,6 +154,9 @@ func getDefaultExcludePatterns() []string {
 			"test-results/**",
 			"jest-cache/**",
 
+			// CodeContext's own caches, snapshots and annotations
+			".codecontext/**",
+
 			// Version control
 			".git/**",
 			".svn/**",
@@ -232,6 +239,20 @@ type GraphBuilder struct {
 	excludePatterns    []string
 	includePatterns    []string // Negation patterns (starting with !)
 	useDefaultExcludes bool
+	includeDirs        []string                  // C/C++ include search directories
+	docFiles           []string                  // Markdown documents found during the walk
+	packageManifests   []string                  // package.json files found during the walk
+	externalWorkspaces []string                  // Workspace package names whose imports stay external
+	plugins            []plugin.Analyzer         // Custom analyzers run after the built-in analysis
+	redactor           *redact.Redactor          // Masks secrets once analysis completes
+	summarizer         *summarize.Summarizer     // Writes file summaries with an LLM, nil offline
+	semanticConfig     *git.SemanticConfig       // Semantic neighborhood thresholds (nil = profile defaults)
+	profile            Profile                   // Analysis stages to run
+	lazySemantic       bool                      // Leave semantic neighborhoods to AnalyzeSemanticNeighborhoods
+	coverageReports    []string                  // Coverage reports attached to files and symbols
+	mutationReports    []string                  // Mutation testing reports attached to files and symbols
+	indexes            []string                  // SCIP and LSIF indexes merged into the relationships
+	languageOverrides  []parser.LanguageOverride // Languages set by path
 
 	// Thread-safe pattern caching
 	patternMu      sync.RWMutex
@@ -251,18 +272,14 @@ type GraphBuilder struct {
 func NewGraphBuilder() *GraphBuilder {
 	return &GraphBuilder{
 		parser: parser.NewManager(),
-		graph: &types.CodeGraph{
-			Nodes:    make(map[types.NodeId]*types.GraphNode),
-			Edges:    make(map[types.EdgeId]*types.GraphEdge),
-			Files:    make(map[string]*types.FileNode),
-			Symbols:  make(map[types.SymbolId]*types.Symbol),
-			Metadata: &types.GraphMetadata{},
-		},
+		graph:  newCodeGraph(),
 		progressConfig: ProgressConfig{
 			Interval:       DefaultProgressInterval,
 			ShowPercentage: false, // Default: don't show percentage (requires pre-counting)
 		},
 		useDefaultExcludes: true, // Use default exclude patterns by default
+		profile:            ProfileBalanced,
+		plugins:            plugin.Registered(),
 		excludePatterns:    []string{},
 		includePatterns:    []string{},
 		patternsDirty:      true, // Force initial cache build
@@ -273,6 +290,26 @@ func NewGraphBuilder() *GraphBuilder {
 	}
 }
 
+// AnalyzeFunc analyzes a directory into a graph. Callers that configure their
+// builders, or limit how many analyses run at once, pass their own.
+type AnalyzeFunc func(dir string) (*types.CodeGraph, error)
+
+// AnalyzeDirectory analyzes dir with a builder of default settings
+func AnalyzeDirectory(dir string) (*types.CodeGraph, error) {
+	return NewGraphBuilder().AnalyzeDirectory(dir)
+}
+
+// newCodeGraph returns an empty graph
+func newCodeGraph() *types.CodeGraph {
+	return &types.CodeGraph{
+		Nodes:    make(map[types.NodeId]*types.GraphNode),
+		Edges:    make(map[types.EdgeId]*types.GraphEdge),
+		Files:    make(map[string]*types.FileNode),
+		Symbols:  make(map[types.SymbolId]*types.Symbol),
+		Metadata: &types.GraphMetadata{},
+	}
+}
+
 // SetLogger sets a logger for pattern error reporting
 func (gb *GraphBuilder) SetLogger(logger *log.Logger) {
 	gb.logger = logger
@@ -464,6 +501,34 @@ func (gb *GraphBuilder) SetExcludePatterns(patterns []string) {
 	gb.clearNormalizationCaches()
 }
 
+// SetIncludeDirs sets the directories searched when resolving C/C++ #include paths.
+// Relative directories are resolved against the analyzed directory.
+func (gb *GraphBuilder) SetIncludeDirs(dirs []string) {
+	gb.includeDirs = dirs
+}
+
+// SetExternalWorkspacePackages sets the workspace package names, or path.Match
+// patterns such as "@myorg/*", whose imports are kept as external dependencies
+// instead of being resolved into the monorepo
+func (gb *GraphBuilder) SetExternalWorkspacePackages(patterns []string) {
+	gb.externalWorkspaces = patterns
+}
+
+// SetLanguageOverrides sets the languages of files by path glob, relative to
+// the analyzed directory, overriding their extension, shebang or modeline
+func (gb *GraphBuilder) SetLanguageOverrides(overrides []parser.LanguageOverride) {
+	gb.languageOverrides = overrides
+}
+
+// LoadWASMGrammars loads tree-sitter grammars compiled to WASM and maps their file
+// extensions for analysis. A relative grammar directory is resolved against baseDir.
+func (gb *GraphBuilder) LoadWASMGrammars(config parser.WASMGrammarConfig, baseDir string) error {
+	if config.Dir != "" && !filepath.IsAbs(config.Dir) {
+		config.Dir = filepath.Join(baseDir, config.Dir)
+	}
+	return gb.parser.LoadWASMGrammars(config)
+}
+
 // clearNormalizationCaches clears the path normalization caches
 func (gb *GraphBuilder) clearNormalizationCaches() {
 	gb.normCacheMu.Lock()
@@ -474,6 +539,64 @@ func (gb *GraphBuilder) clearNormalizationCaches() {
 	gb.patternCache = make(map[string]string, 256)
 }
 
+// SetPlugins replaces the custom analyzers run after the built-in analysis.
+// By default every analyzer registered with plugin.Register is run.
+func (gb *GraphBuilder) SetPlugins(analyzers []plugin.Analyzer) {
+	gb.plugins = analyzers
+}
+
+// SetRedactor sets the redactor applied to every analyzed graph
+func (gb *GraphBuilder) SetRedactor(r *redact.Redactor) {
+	gb.redactor = r
+}
+
+// SetSummarizer sets the summarizer that adds natural language summaries to
+// analyzed files. Nil, the default, keeps analysis offline.
+func (gb *GraphBuilder) SetSummarizer(s *summarize.Summarizer) {
+	gb.summarizer = s
+}
+
+// Redactor returns the redactor applied to analyzed graphs, or nil
+func (gb *GraphBuilder) Redactor() *redact.Redactor {
+	return gb.redactor
+}
+
+// Summarizer returns the summarizer of analyzed files, or nil offline
+func (gb *GraphBuilder) Summarizer() *summarize.Summarizer {
+	return gb.summarizer
+}
+
+// SetProfile selects the analysis stages run by AnalyzeDirectory
+func (gb *GraphBuilder) SetProfile(profile Profile) {
+	gb.profile = profile
+}
+
+// Profile returns the selected analysis profile
+func (gb *GraphBuilder) Profile() Profile {
+	return gb.profile
+}
+
+// SetLazySemantic defers semantic neighborhood analysis: AnalyzeDirectory skips
+// the git history and callers run AnalyzeSemanticNeighborhoods when needed
+func (gb *GraphBuilder) SetLazySemantic(lazy bool) {
+	gb.lazySemantic = lazy
+}
+
+// SetSemanticConfig sets the thresholds for semantic neighborhood analysis.
+// Nil restores the profile's defaults.
+func (gb *GraphBuilder) SetSemanticConfig(config *git.SemanticConfig) {
+	gb.semanticConfig = config
+}
+
+// ClearCaches drops cached path normalizations and parsed ASTs, so settings that
+// change how files are selected or parsed apply to every file
+func (gb *GraphBuilder) ClearCaches() {
+	gb.clearNormalizationCaches()
+	if astCache := gb.parser.GetASTCache(); astCache != nil {
+		astCache.Clear()
+	}
+}
+
 // SetUseDefaultExcludes sets whether to use default exclude patterns
 func (gb *GraphBuilder) SetUseDefaultExcludes(use bool) {
 	gb.patternMu.Lock()
@@ -509,7 +632,9 @@ func (gb *GraphBuilder) SetProgressConfig(config ProgressConfig) {
 func (gb *GraphBuilder) AnalyzeDirectory(targetDir string) (*types.CodeGraph, error) {
 	start := time.Now()
 
-	// Initialize graph metadata
+	// Start from an empty graph so files from earlier runs, other directories or
+	// since-excluded paths do not linger
+	gb.graph = newCodeGraph()
 	gb.graph.Metadata = &types.GraphMetadata{
 		Generated:    time.Now(),
 		Version:      "2.0.0",
@@ -518,8 +643,18 @@ func (gb *GraphBuilder) AnalyzeDirectory(targetDir string) (*types.CodeGraph, er
 		Languages:    make(map[string]int),
 	}
 
+	// Overrides match paths relative to the analyzed directory
+	if err := gb.parser.SetLanguageOverrides(targetDir, gb.languageOverrides); err != nil && gb.progressCallback != nil {
+		gb.progressCallback(fmt.Sprintf("⚠️ Language overrides skipped: %v", err))
+	}
+	gb.parser.SetLightParsing(gb.profile.lightParsing())
+	// Symbol IDs stay the same whichever directory the analysis runs from
+	gb.parser.SetRoot(targetDir)
+
 	// Walk directory and process files
 	fileCount := 0
+	gb.docFiles = nil
+	gb.packageManifests = nil
 	err := filepath.Walk(targetDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -528,8 +663,13 @@ func (gb *GraphBuilder) AnalyzeDirectory(targetDir string) (*types.CodeGraph, er
 		// Normalize path immediately for consistent handling
 		path = gb.normalizePath(path)
 
-		// Skip directories and unsupported files
-		if info.IsDir() || !gb.isSupportedFile(path) {
+		// Skip directories, and files of unknown extensions that are not
+		// regular files
+		if info.IsDir() {
+			return nil
+		}
+		supported := gb.isSupportedFile(path)
+		if !supported && !info.Mode().IsRegular() {
 			return nil
 		}
 
@@ -545,6 +685,12 @@ func (gb *GraphBuilder) AnalyzeDirectory(targetDir string) (*types.CodeGraph, er
 			return nil
 		}
 
+		// Other files are read only once excluded paths are skipped, for a
+		// language override, shebang or modeline
+		if !supported && gb.parser.DetectLanguage(path) == nil {
+			return nil
+		}
+
 		fileCount++
 
 		// Update progress at configured intervals for staged display
@@ -552,6 +698,17 @@ func (gb *GraphBuilder) AnalyzeDirectory(targetDir string) (*types.CodeGraph, er
 			gb.progressCallback(fmt.Sprintf("📄 Parsing files... (%d files)", fileCount))
 		}
 
+		// Markdown is not parsed into symbols but is scanned later for links into the code
+		if filepath.Ext(path) == ".md" {
+			gb.docFiles = append(gb.docFiles, path)
+			return nil
+		}
+
+		// Package names map monorepo imports such as "@myorg/utils" to their sources
+		if filepath.Base(path) == "package.json" {
+			gb.packageManifests = append(gb.packageManifests, path)
+		}
+
 		return gb.processFile(path)
 	})
 
@@ -564,37 +721,64 @@ func (gb *GraphBuilder) AnalyzeDirectory(targetDir string) (*types.CodeGraph, er
 		gb.progressCallback(fmt.Sprintf("✅ Parsing complete (%d files)", fileCount))
 	}
 
+	// Count Dart part files as part of their library
+	gb.mergeDartParts()
+
+	// Attach test coverage before relationship analysis weighs hotspots by it
+	gb.addCoverage(targetDir)
+	gb.addMutations(targetDir)
+
 	// Build relationships between files
 	if gb.progressCallback != nil {
 		gb.progressCallback("🔗 Building relationships...")
 	}
-	gb.buildFileRelationships()
+	gb.buildFileRelationships(targetDir)
 
 	if gb.progressCallback != nil {
 		gb.progressCallback("✅ Relationships built")
 	}
 
-	// Build semantic neighborhoods if git repository
-	if gb.progressCallback != nil {
-		gb.progressCallback("📊 Analyzing git history...")
+	// Precise cross-references from compiler indexes confirm and extend the inferred ones
+	if len(gb.indexes) > 0 {
+		gb.addIndexes(targetDir)
 	}
-	semanticResult, err := gb.buildSemanticNeighborhoods(targetDir)
-	if err == nil && semanticResult != nil {
-		// Add semantic analysis results to metadata
-		if gb.graph.Metadata.Configuration == nil {
-			gb.graph.Metadata.Configuration = make(map[string]interface{})
+
+	// Build semantic neighborhoods if git repository
+	switch {
+	case !gb.profile.gitAnalysis():
+		if gb.progressCallback != nil {
+			gb.progressCallback(fmt.Sprintf("⏭️ Git analysis skipped (%s profile)", gb.profile))
 		}
-		gb.graph.Metadata.Configuration["semantic_neighborhoods"] = semanticResult
+	case gb.lazySemantic:
+		if gb.progressCallback != nil {
+			gb.progressCallback("⏭️ Git analysis deferred until requested")
+		}
+	default:
+		gb.addSemanticNeighborhoods(targetDir)
+	}
 
+	if gb.profile.embeddings() {
+		gb.addEmbeddings()
+	}
+
+	// Let custom analyzers contribute to the graph
+	if len(gb.plugins) > 0 {
 		if gb.progressCallback != nil {
-			gb.progressCallback("✅ Git analysis complete")
+			gb.progressCallback(fmt.Sprintf("🧩 Running %d plugins...", len(gb.plugins)))
 		}
-	} else if gb.progressCallback != nil {
-		gb.progressCallback("⚠️ Git analysis skipped")
+		gb.applyPlugins()
+	}
+
+	// Summarize files with the configured LLM
+	if gb.summarizer != nil {
+		gb.applySummaries(targetDir)
 	}
 
+	// Mask secrets before the graph leaves the builder
+	gb.redactor.Graph(gb.graph, targetDir)
+
 	// Update metadata
-	gb.graph.Metadata.TotalFiles = len(gb.graph.Files)
+	gb.graph.Metadata.TotalFiles = logicalFileCount(gb.graph)
 	gb.graph.Metadata.TotalSymbols = len(gb.graph.Symbols)
 	gb.graph.Metadata.AnalysisTime = time.Since(start)
 
@@ -680,9 +864,13 @@ func (gb *GraphBuilder) processFile(filePath string) error {
 }
 
 // buildFileRelationships analyzes imports to build file-to-file relationships
-func (gb *GraphBuilder) buildFileRelationships() {
+func (gb *GraphBuilder) buildFileRelationships(targetDir string) {
 	// Use the enhanced relationship analyzer
 	analyzer := NewRelationshipAnalyzer(gb.graph)
+	analyzer.SetIncludeDirs(gb.resolveIncludeDirs(targetDir))
+	analyzer.SetDocFiles(gb.normalizePath(targetDir), gb.docFiles)
+	analyzer.SetSymbolUsage(gb.profile.symbolUsage())
+	analyzer.SetWorkspacePackages(LoadWorkspacePackages(gb.packageManifests), gb.externalWorkspaces)
 
 	// Perform comprehensive relationship analysis
 	metrics, err := analyzer.AnalyzeAllRelationships()
@@ -767,6 +955,18 @@ func (gb *GraphBuilder) resolveImportPath(importPath, fromFile string) string {
 	return ""
 }
 
+// resolveIncludeDirs makes configured include directories absolute relative to targetDir
+func (gb *GraphBuilder) resolveIncludeDirs(targetDir string) []string {
+	dirs := make([]string, 0, len(gb.includeDirs))
+	for _, dir := range gb.includeDirs {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(targetDir, dir)
+		}
+		dirs = append(dirs, gb.normalizePath(dir))
+	}
+	return dirs
+}
+
 // isSupportedFile checks if a file is supported for parsing
 func (gb *GraphBuilder) isSupportedFile(path string) bool {
 	ext := filepath.Ext(path)
@@ -774,6 +974,8 @@ func (gb *GraphBuilder) isSupportedFile(path string) bool {
 	supportedExtensions := []string{
 		// JavaScript/TypeScript
 		".ts", ".tsx", ".js", ".jsx", ".mts", ".cts", ".mjs", ".cjs",
+		// Vue and Svelte single-file components
+		".vue", ".svelte",
 		// Go
 		".go",
 		// Python
@@ -782,13 +984,26 @@ func (gb *GraphBuilder) isSupportedFile(path string) bool {
 		".java",
 		// Rust
 		".rs",
+		// C and C++
+		".c", ".cpp", ".cxx", ".cc", ".c++", ".hpp", ".hxx", ".hh", ".h++", ".h",
+		".cppm", ".ixx", ".mpp", ".cxxm", ".ccm", ".c++m",
+		// Objective-C++, CUDA and Metal
+		".mm", ".cu", ".cuh", ".metal",
+		// Shell scripts
+		".sh", ".bash", ".zsh",
+		// Dart
+		".dart",
+		// Jupyter notebooks
+		".ipynb",
+		// Jinja, Django, ERB, Handlebars and Go templates
+		".j2", ".jinja", ".jinja2", ".njk", ".twig", ".erb", ".hbs", ".handlebars", ".mustache", ".tmpl", ".gotmpl", ".gohtml",
 		// Config files
 		".json", ".yaml", ".yml",
 		// Markdown (for documentation)
 		".md",
 	}
 
-	return slices.Contains(supportedExtensions, ext)
+	return slices.Contains(supportedExtensions, ext) || gb.parser.HasWASMExtension(ext)
 }
 
 // getMergedPatterns returns the combined exclude patterns (defaults + user patterns)
@@ -854,6 +1069,11 @@ func (gb *GraphBuilder) buildPatternsUncached(defaultPatterns []string) []string
 	return result
 }
 
+// Excluded reports whether the exclude patterns skip a path
+func (gb *GraphBuilder) Excluded(path string) bool {
+	return gb.shouldSkipPath(path)
+}
+
 // shouldSkipPath checks if a path should be skipped during analysis
 func (gb *GraphBuilder) shouldSkipPath(path string) bool {
 	// Normalize path for consistent comparison across platforms
@@ -1056,15 +1276,20 @@ func (gb *GraphBuilder) GetSupportedLanguages() []types.Language {
 
 // GetFileStats returns statistics about the analyzed files
 func (gb *GraphBuilder) GetFileStats() map[string]interface{} {
-	if gb.graph.Metadata == nil {
+	return FileStats(gb.graph)
+}
+
+// FileStats returns statistics about the files of an analyzed graph
+func FileStats(graph *types.CodeGraph) map[string]interface{} {
+	if graph == nil || graph.Metadata == nil {
 		return map[string]interface{}{}
 	}
 
 	return map[string]interface{}{
-		"totalFiles":   gb.graph.Metadata.TotalFiles,
-		"totalSymbols": gb.graph.Metadata.TotalSymbols,
-		"languages":    gb.graph.Metadata.Languages,
-		"analysisTime": gb.graph.Metadata.AnalysisTime,
+		"totalFiles":   graph.Metadata.TotalFiles,
+		"totalSymbols": graph.Metadata.TotalSymbols,
+		"languages":    graph.Metadata.Languages,
+		"analysisTime": graph.Metadata.AnalysisTime,
 	}
 }
 
@@ -1073,6 +1298,7 @@ type SemanticAnalysisResult struct {
 	SemanticNeighborhoods  []git.SemanticNeighborhood  `json:"semantic_neighborhoods"`
 	EnhancedNeighborhoods  []git.EnhancedNeighborhood  `json:"enhanced_neighborhoods"`
 	ClusteredNeighborhoods []git.ClusteredNeighborhood `json:"clustered_neighborhoods"`
+	CommitTypes            git.CommitTypeStats         `json:"commit_types"` // Conventional commit types over the analysis period
 	AnalysisMetadata       SemanticAnalysisMetadata    `json:"analysis_metadata"`
 	Error                  string                      `json:"error,omitempty"`
 }
@@ -1096,8 +1322,105 @@ type QualityScores struct {
 	OverallQualityRating      string  `json:"overall_quality_rating"`
 }
 
+// applyPlugins runs the custom analyzers and records their failures in the graph metadata
+// under "plugin_errors"; a failing plugin never fails the analysis
+func (gb *GraphBuilder) applyPlugins() {
+	errs := plugin.Apply(context.Background(), gb.graph, gb.plugins)
+	if len(errs) == 0 {
+		return
+	}
+
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+		if gb.logger != nil {
+			gb.logger.Printf("Warning: %v", err)
+		}
+		if gb.progressCallback != nil {
+			gb.progressCallback(fmt.Sprintf("⚠️ %v", err))
+		}
+	}
+	if gb.graph.Metadata.Configuration == nil {
+		gb.graph.Metadata.Configuration = make(map[string]interface{})
+	}
+	gb.graph.Metadata.Configuration["plugin_errors"] = messages
+}
+
+// applySummaries adds file summaries and records the run in the graph metadata
+// under "summary_stats", and a failure under "summary_errors"; files without a
+// summary never fail the analysis
+func (gb *GraphBuilder) applySummaries(targetDir string) {
+	if gb.progressCallback != nil {
+		gb.progressCallback("📝 Summarizing files...")
+	}
+	stats, err := gb.summarizer.Apply(context.Background(), gb.graph, targetDir, gb.redactor)
+	if gb.progressCallback != nil {
+		gb.progressCallback(fmt.Sprintf("📝 Summaries: %d cached, %d generated, %d stale, %d pending", stats.Cached, stats.Generated, stats.Stale, stats.Pending))
+	}
+	if gb.graph.Metadata.Configuration == nil {
+		gb.graph.Metadata.Configuration = make(map[string]interface{})
+	}
+	gb.graph.Metadata.Configuration["summary_stats"] = stats
+	if err == nil {
+		return
+	}
+	if gb.logger != nil {
+		gb.logger.Printf("Warning: %v", err)
+	}
+	if gb.progressCallback != nil {
+		gb.progressCallback(fmt.Sprintf("⚠️ %v", err))
+	}
+	gb.graph.Metadata.Configuration["summary_errors"] = []string{err.Error()}
+}
+
+// addEmbeddings stores the symbol embedding index in the graph metadata
+func (gb *GraphBuilder) addEmbeddings() {
+	if gb.progressCallback != nil {
+		gb.progressCallback("🧬 Embedding symbols...")
+	}
+	index := BuildEmbeddings(gb.graph)
+	if gb.graph.Metadata.Configuration == nil {
+		gb.graph.Metadata.Configuration = make(map[string]interface{})
+	}
+	gb.graph.Metadata.Configuration["embeddings"] = index
+	if gb.progressCallback != nil {
+		gb.progressCallback(fmt.Sprintf("✅ Embedded %d symbols", len(index.Vectors)))
+	}
+}
+
+// addSemanticNeighborhoods stores the semantic analysis of targetDir in the graph metadata
+func (gb *GraphBuilder) addSemanticNeighborhoods(targetDir string) {
+	if gb.progressCallback != nil {
+		gb.progressCallback("📊 Analyzing git history...")
+	}
+	semanticResult, err := gb.buildSemanticNeighborhoods(targetDir, gb.graph, gb.profile)
+	if err == nil && semanticResult != nil {
+		// Add semantic analysis results to metadata
+		if gb.graph.Metadata.Configuration == nil {
+			gb.graph.Metadata.Configuration = make(map[string]interface{})
+		}
+		gb.graph.Metadata.Configuration["semantic_neighborhoods"] = semanticResult
+
+		if gb.progressCallback != nil {
+			gb.progressCallback("✅ Git analysis complete")
+		}
+	} else if gb.progressCallback != nil {
+		gb.progressCallback("⚠️ Git analysis skipped")
+	}
+}
+
+// AnalyzeSemanticNeighborhoods builds the semantic neighborhoods of targetDir
+// from its git history, scoring them against a graph analyzed from the same
+// directory. The profile supplies the thresholds unless a semantic config is set.
+func (gb *GraphBuilder) AnalyzeSemanticNeighborhoods(targetDir string, graph *types.CodeGraph, profile Profile) (*SemanticAnalysisResult, error) {
+	if !profile.gitAnalysis() {
+		return nil, fmt.Errorf("the %s profile skips git analysis", profile)
+	}
+	return gb.buildSemanticNeighborhoods(targetDir, graph, profile)
+}
+
 // buildSemanticNeighborhoods analyzes git patterns and builds semantic neighborhoods
-func (gb *GraphBuilder) buildSemanticNeighborhoods(targetDir string) (*SemanticAnalysisResult, error) {
+func (gb *GraphBuilder) buildSemanticNeighborhoods(targetDir string, graph *types.CodeGraph, profile Profile) (*SemanticAnalysisResult, error) {
 	start := time.Now()
 
 	// Initialize git analyzer
@@ -1122,8 +1445,11 @@ func (gb *GraphBuilder) buildSemanticNeighborhoods(targetDir string) (*SemanticA
 		}, nil
 	}
 
-	// Create semantic analyzer with default config
-	semanticConfig := git.DefaultSemanticConfig()
+	// Create semantic analyzer with the configured thresholds
+	semanticConfig := gb.semanticConfig
+	if semanticConfig == nil {
+		semanticConfig = profile.semanticConfig()
+	}
 	semanticAnalyzer, err := git.NewSemanticAnalyzer(targetDir, semanticConfig)
 	if err != nil {
 		return &SemanticAnalysisResult{
@@ -1149,12 +1475,13 @@ func (gb *GraphBuilder) buildSemanticNeighborhoods(targetDir string) (*SemanticA
 
 	// Build enhanced neighborhoods using graph integration
 	integrationConfig := git.DefaultIntegrationConfig()
-	graphIntegration := git.NewGraphIntegration(semanticAnalyzer, gb.graph, integrationConfig)
+	graphIntegration := git.NewGraphIntegration(semanticAnalyzer, graph, integrationConfig)
 
 	enhancedNeighborhoods, err := graphIntegration.BuildEnhancedNeighborhoods()
 	if err != nil {
 		return &SemanticAnalysisResult{
 			SemanticNeighborhoods: analysisResult.Neighborhoods,
+			CommitTypes:           analysisResult.CommitTypes,
 			Error:                 fmt.Sprintf("Failed to build enhanced neighborhoods: %v", err),
 			AnalysisMetadata: SemanticAnalysisMetadata{
 				IsGitRepository:    true,
@@ -1171,6 +1498,7 @@ func (gb *GraphBuilder) buildSemanticNeighborhoods(targetDir string) (*SemanticA
 	if err != nil {
 		return &SemanticAnalysisResult{
 			SemanticNeighborhoods: analysisResult.Neighborhoods,
+			CommitTypes:           analysisResult.CommitTypes,
 			EnhancedNeighborhoods: enhancedNeighborhoods,
 			Error:                 fmt.Sprintf("Failed to build clustered neighborhoods: %v", err),
 			AnalysisMetadata: SemanticAnalysisMetadata{
@@ -1200,6 +1528,7 @@ func (gb *GraphBuilder) buildSemanticNeighborhoods(targetDir string) (*SemanticA
 		SemanticNeighborhoods:  analysisResult.Neighborhoods,
 		EnhancedNeighborhoods:  enhancedNeighborhoods,
 		ClusteredNeighborhoods: clusteredNeighborhoods,
+		CommitTypes:            analysisResult.CommitTypes,
 		AnalysisMetadata: SemanticAnalysisMetadata{
 			IsGitRepository:    true,
 			AnalysisPeriodDays: semanticConfig.AnalysisPeriodDays,
@@ -154,6 +154,9 @@ func TestIsSupportedFile(t *testing.T) {
 		{"test.py", true},
 		{"test.go", true},
 		{"README.md", true},
+		{"engine.cpp", true},
+		{"engine.h", true},
+		{"engine.hpp", true},
 	}
 
 	for _, test := range tests {
@@ -1529,3 +1532,41 @@ func TestDoubleStarPatternEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestSymbolIdsIndependentOfWorkingDirectory(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "project")
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := "export class UserStore {\n  load() {}\n}\n\nexport function helper() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "src", "store.ts"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	analyze := func(workingDir, targetDir string) []string {
+		t.Chdir(workingDir)
+		graph, err := NewGraphBuilder().AnalyzeDirectory(targetDir)
+		if err != nil {
+			t.Fatalf("AnalyzeDirectory(%q) from %s failed: %v", targetDir, workingDir, err)
+		}
+		var ids []string
+		for id, symbol := range graph.Symbols {
+			ids = append(ids, string(id)+" "+symbol.FullyQualifiedName)
+		}
+		slices.Sort(ids)
+		return ids
+	}
+
+	fromProject := analyze(dir, ".")
+	fromParent := analyze(parent, "project")
+	if len(fromProject) == 0 {
+		t.Fatal("Expected symbols to be extracted")
+	}
+	if !slices.Equal(fromProject, fromParent) {
+		t.Errorf("Symbol IDs depend on the working directory:\n%v\n%v", fromProject, fromParent)
+	}
+	if !slices.ContainsFunc(fromProject, func(id string) bool { return strings.HasSuffix(id, " src/store.ts::UserStore.load") }) {
+		t.Errorf("Expected a method qualified by its relative path and class, got %v", fromProject)
+	}
+}
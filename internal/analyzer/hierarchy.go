@@ -0,0 +1,252 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipMixesIn marks a class applying a mixin (Dart `with`)
+const RelationshipMixesIn RelationshipType = "mixes-in"
+
+// inheritanceRelations maps symbol metadata keys to the edge type they produce
+var inheritanceRelations = []struct {
+	metadataKey  string
+	relationship RelationshipType
+}{
+	{parser.MetadataExtends, RelationshipExtends},
+	{parser.MetadataImplements, RelationshipImplements},
+	{parser.MetadataMixins, RelationshipMixesIn},
+}
+
+// analyzeInheritanceRelationships creates extends/implements/mixes-in edges between type symbols
+func (ra *RelationshipAnalyzer) analyzeInheritanceRelationships(metrics *RelationshipMetrics) {
+	index := ra.buildTypeIndex()
+
+	for _, symbol := range ra.graph.Symbols {
+		if symbol.Metadata == nil {
+			continue
+		}
+
+		for _, relation := range inheritanceRelations {
+			for _, superName := range symbol.MetadataStrings(relation.metadataKey) {
+				from := types.NodeId(fmt.Sprintf("symbol-%s", symbol.Id))
+				metadata := map[string]interface{}{
+					"supertype": superName,
+					"language":  symbol.Language,
+				}
+
+				var to types.NodeId
+				if target := ra.resolveTypeName(index, superName, symbol); target != nil {
+					to = types.NodeId(fmt.Sprintf("symbol-%s", target.Id))
+				} else {
+					// Framework or library base classes (e.g. StatelessWidget) live outside the repo
+					to = types.NodeId(fmt.Sprintf("external-type-%s", superName))
+					metadata["is_external"] = true
+				}
+
+				edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", relation.relationship, from, to))
+				ra.graph.Edges[edgeId] = &types.GraphEdge{
+					Id:       edgeId,
+					From:     from,
+					To:       to,
+					Type:     string(relation.relationship),
+					Weight:   1.0,
+					Metadata: metadata,
+				}
+				metrics.ByType[relation.relationship]++
+				metrics.SymbolToSymbol++
+			}
+		}
+	}
+}
+
+// buildTypeIndex indexes class-like symbols by simple name
+func (ra *RelationshipAnalyzer) buildTypeIndex() map[string][]*types.Symbol {
+	index := make(map[string][]*types.Symbol)
+	for _, symbol := range ra.graph.Symbols {
+		// Specializations share the primary template's name; supertypes name the primary
+		if isTypeSymbol(symbol.Type) && !isTemplateVariant(symbol) {
+			index[symbol.Name] = append(index[symbol.Name], symbol)
+		}
+	}
+	return index
+}
+
+// resolveTypeName finds the declaration for a supertype name, preferring the same file then language
+func (ra *RelationshipAnalyzer) resolveTypeName(index map[string][]*types.Symbol, name string, from *types.Symbol) *types.Symbol {
+	simple := name
+	if idx := strings.LastIndexAny(simple, ".:"); idx != -1 {
+		simple = simple[idx+1:]
+	}
+
+	candidates := index[simple]
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	fromFile := types.FilePathFromQualifiedName(from.FullyQualifiedName)
+	var sameFile, sameLanguage *types.Symbol
+	for _, candidate := range candidates {
+		if candidate.Id == from.Id {
+			continue
+		}
+		if types.FilePathFromQualifiedName(candidate.FullyQualifiedName) == fromFile {
+			// A C++ class template is both a template and a class symbol; the
+			// template is the one its specializations link to
+			if sameFile == nil || candidate.Type == types.SymbolTypeTemplate {
+				sameFile = candidate
+			}
+			continue
+		}
+		if candidate.Language == from.Language && (sameLanguage == nil || candidate.Type == types.SymbolTypeTemplate) {
+			sameLanguage = candidate
+		}
+	}
+	if sameFile != nil {
+		return sameFile
+	}
+	return sameLanguage
+}
+
+// isTypeSymbol reports whether a symbol declares a type that can participate in a hierarchy
+func isTypeSymbol(symbolType types.SymbolType) bool {
+	switch symbolType {
+	case types.SymbolTypeClass, types.SymbolTypeInterface, types.SymbolTypeType,
+		types.SymbolTypeWidget, types.SymbolTypeStateClass, types.SymbolTypeMixin,
+		types.SymbolTypeComponent, types.SymbolTypeService, types.SymbolTypeDirective,
+		types.SymbolTypeTemplate:
+		return true
+	}
+	return false
+}
+
+// TypeHierarchyNode is one entry in a supertype or subtype tree
+type TypeHierarchyNode struct {
+	Name         string               `json:"name"`
+	SymbolId     types.SymbolId       `json:"symbol_id,omitempty"`
+	FilePath     string               `json:"file_path,omitempty"`
+	Line         int                  `json:"line,omitempty"`
+	Relationship string               `json:"relationship"`
+	IsExternal   bool                 `json:"is_external,omitempty"`
+	Children     []*TypeHierarchyNode `json:"children,omitempty"`
+}
+
+// TypeHierarchy answers supertype/subtype queries over inheritance edges
+type TypeHierarchy struct {
+	graph      *types.CodeGraph
+	supertypes map[types.NodeId][]*types.GraphEdge
+	subtypes   map[types.NodeId][]*types.GraphEdge
+	files      map[types.SymbolId]string
+}
+
+// NewTypeHierarchy indexes the inheritance edges of a graph
+func NewTypeHierarchy(graph *types.CodeGraph) *TypeHierarchy {
+	th := &TypeHierarchy{
+		graph:      graph,
+		supertypes: make(map[types.NodeId][]*types.GraphEdge),
+		subtypes:   make(map[types.NodeId][]*types.GraphEdge),
+		files:      make(map[types.SymbolId]string),
+	}
+
+	for path, file := range graph.Files {
+		for _, id := range file.Symbols {
+			th.files[id] = path
+		}
+	}
+
+	for _, edge := range graph.Edges {
+		if IsInheritanceEdge(edge.Type) {
+			th.supertypes[edge.From] = append(th.supertypes[edge.From], edge)
+			th.subtypes[edge.To] = append(th.subtypes[edge.To], edge)
+		}
+	}
+
+	// Sort for deterministic output
+	for _, edges := range th.supertypes {
+		sort.Slice(edges, func(i, j int) bool { return edges[i].To < edges[j].To })
+	}
+	for _, edges := range th.subtypes {
+		sort.Slice(edges, func(i, j int) bool { return edges[i].From < edges[j].From })
+	}
+
+	return th
+}
+
+// IsInheritanceEdge reports whether an edge type describes a type hierarchy relationship
+func IsInheritanceEdge(edgeType string) bool {
+	switch RelationshipType(edgeType) {
+	case RelationshipExtends, RelationshipImplements, RelationshipMixesIn,
+		RelationshipSpecializes, RelationshipInstantiates:
+		return true
+	}
+	return false
+}
+
+// Supertypes returns the tree of types the symbol inherits from, up to maxDepth levels
+func (th *TypeHierarchy) Supertypes(symbolId types.SymbolId, maxDepth int) []*TypeHierarchyNode {
+	start := types.NodeId(fmt.Sprintf("symbol-%s", symbolId))
+	return th.walk(start, maxDepth, true, map[types.NodeId]bool{start: true})
+}
+
+// Subtypes returns the tree of types inheriting from the symbol, up to maxDepth levels
+func (th *TypeHierarchy) Subtypes(symbolId types.SymbolId, maxDepth int) []*TypeHierarchyNode {
+	start := types.NodeId(fmt.Sprintf("symbol-%s", symbolId))
+	return th.walk(start, maxDepth, false, map[types.NodeId]bool{start: true})
+}
+
+// walk recursively follows inheritance edges in one direction, guarding against cycles
+func (th *TypeHierarchy) walk(nodeId types.NodeId, depth int, up bool, visited map[types.NodeId]bool) []*TypeHierarchyNode {
+	if depth <= 0 {
+		return nil
+	}
+
+	edges := th.subtypes[nodeId]
+	if up {
+		edges = th.supertypes[nodeId]
+	}
+
+	var result []*TypeHierarchyNode
+	for _, edge := range edges {
+		next := edge.From
+		if up {
+			next = edge.To
+		}
+		if visited[next] {
+			continue
+		}
+
+		node := th.describeNode(next, edge)
+		visited[next] = true
+		if !node.IsExternal {
+			node.Children = th.walk(next, depth-1, up, visited)
+		}
+		delete(visited, next)
+
+		result = append(result, node)
+	}
+	return result
+}
+
+// describeNode builds a hierarchy entry for a graph node reached through edge
+func (th *TypeHierarchy) describeNode(nodeId types.NodeId, edge *types.GraphEdge) *TypeHierarchyNode {
+	node := &TypeHierarchyNode{Relationship: edge.Type}
+
+	id := string(nodeId)
+	if strings.HasPrefix(id, "symbol-") {
+		if symbol := th.graph.Symbols[types.SymbolId(strings.TrimPrefix(id, "symbol-"))]; symbol != nil {
+			node.Name = symbol.Name
+			node.SymbolId = symbol.Id
+			node.FilePath = th.files[symbol.Id]
+			node.Line = symbol.Location.StartLine
+			return node
+		}
+	}
+
+	node.Name = strings.TrimPrefix(id, "external-type-")
+	node.IsExternal = true
+	return node
+}
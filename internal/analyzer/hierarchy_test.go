@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+func createHierarchyGraph() *types.CodeGraph {
+	graph := &types.CodeGraph{
+		Nodes:    make(map[types.NodeId]*types.GraphNode),
+		Edges:    make(map[types.EdgeId]*types.GraphEdge),
+		Files:    make(map[string]*types.FileNode),
+		Symbols:  make(map[types.SymbolId]*types.Symbol),
+		Metadata: &types.GraphMetadata{},
+	}
+
+	addClass := func(id, name, file string, metadata map[string]interface{}) {
+		graph.Symbols[types.SymbolId(id)] = &types.Symbol{
+			Id:                 types.SymbolId(id),
+			Name:               name,
+			Type:               types.SymbolTypeClass,
+			Language:           "typescript",
+			FullyQualifiedName: types.QualifiedName(file, name),
+			Location:           types.Location{StartLine: 1},
+			Metadata:           metadata,
+		}
+	}
+
+	addClass("base", "BaseService", "src/base.ts", nil)
+	addClass("logger", "Loggable", "src/base.ts", nil)
+	addClass("user", "UserService", "src/user.ts", map[string]interface{}{
+		"extends":    []string{"BaseService"},
+		"implements": []string{"Loggable", "Disposable"},
+	})
+	addClass("admin", "AdminService", "src/admin.ts", map[string]interface{}{
+		"extends": []interface{}{"UserService"},
+	})
+
+	return graph
+}
+
+func TestInheritanceEdges(t *testing.T) {
+	graph := createHierarchyGraph()
+	graph.Symbols["base"].Metadata = map[string]interface{}{"mixins": []string{"Loggable"}}
+	metrics := &RelationshipMetrics{ByType: make(map[RelationshipType]int)}
+
+	NewRelationshipAnalyzer(graph).analyzeInheritanceRelationships(metrics)
+
+	if metrics.ByType[RelationshipExtends] != 2 {
+		t.Errorf("Expected 2 extends edges, got %d", metrics.ByType[RelationshipExtends])
+	}
+	if metrics.ByType[RelationshipImplements] != 2 {
+		t.Errorf("Expected 2 implements edges, got %d", metrics.ByType[RelationshipImplements])
+	}
+	if edge := graph.Edges["mixes-in-symbol-base-symbol-logger"]; edge == nil || edge.Type != "mixes-in" {
+		t.Errorf("Expected a mixes-in edge from BaseService to Loggable, got %+v", edge)
+	}
+
+	external := 0
+	for _, edge := range graph.Edges {
+		if edge.To == "external-type-Disposable" {
+			external++
+		}
+	}
+	if external != 1 {
+		t.Errorf("Expected unresolved Disposable to become an external edge, got %d", external)
+	}
+}
+
+func TestTypeHierarchyTraversal(t *testing.T) {
+	graph := createHierarchyGraph()
+	metrics := &RelationshipMetrics{ByType: make(map[RelationshipType]int)}
+	NewRelationshipAnalyzer(graph).analyzeInheritanceRelationships(metrics)
+
+	hierarchy := NewTypeHierarchy(graph)
+
+	supertypes := hierarchy.Supertypes("admin", 5)
+	if len(supertypes) != 1 || supertypes[0].Name != "UserService" {
+		t.Fatalf("Expected AdminService to extend UserService, got %+v", supertypes)
+	}
+	if len(supertypes[0].Children) != 3 {
+		t.Errorf("Expected UserService to have 3 supertypes, got %d", len(supertypes[0].Children))
+	}
+
+	if limited := hierarchy.Supertypes("admin", 1); len(limited[0].Children) != 0 {
+		t.Error("Expected max depth to stop traversal")
+	}
+
+	subtypes := hierarchy.Subtypes("base", 5)
+	if len(subtypes) != 1 || subtypes[0].Name != "UserService" {
+		t.Fatalf("Expected UserService as subtype of BaseService, got %+v", subtypes)
+	}
+	if len(subtypes[0].Children) != 1 || subtypes[0].Children[0].Name != "AdminService" {
+		t.Errorf("Expected AdminService as transitive subtype, got %+v", subtypes[0].Children)
+	}
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// ImportEdge summarizes the import statements behind one import edge
+type ImportEdge struct {
+	Count      int      // Import statements between the two files
+	Kinds      []string // Distinct import kinds, sorted
+	Specifiers []string // Imported names across all statements
+	TypeOnly   bool     // Every statement imports types only
+	Lazy       bool     // Every statement loads the target at runtime
+	Loaders    []string // Code-splitting wrappers of the lazy statements, such as "React.lazy"
+	Lines      []int    // Lines of the import statements
+}
+
+// addImportEdge records an import statement as an edge from one file to a
+// target. Repeated statements between the same pair add to the existing edge
+// instead of replacing it.
+func (ra *RelationshipAnalyzer) addImportEdge(edgeId types.EdgeId, from, to types.NodeId, weight float64, imp *types.Import, extra map[string]interface{}) {
+	edge, exists := ra.graph.Edges[edgeId]
+	if !exists {
+		edge = &types.GraphEdge{
+			Id:       edgeId,
+			From:     from,
+			To:       to,
+			Type:     string(RelationshipImport),
+			Weight:   weight,
+			Metadata: map[string]interface{}{"import_path": imp.Path},
+		}
+		for key, value := range extra {
+			edge.Metadata[key] = value
+		}
+		ra.graph.Edges[edgeId] = edge
+	}
+
+	var info ImportEdge
+	if exists {
+		info = ImportEdgeInfo(edge)
+	}
+	info.Count++
+	if imp.Kind != "" && !slices.Contains(info.Kinds, string(imp.Kind)) {
+		info.Kinds = append(info.Kinds, string(imp.Kind))
+		sort.Strings(info.Kinds)
+	}
+	for _, name := range imp.Specifiers {
+		if !slices.Contains(info.Specifiers, name) {
+			info.Specifiers = append(info.Specifiers, name)
+		}
+	}
+	info.TypeOnly = imp.Kind == types.ImportKindTypeOnly && (!exists || info.TypeOnly)
+	info.Lazy = imp.Lazy && (!exists || info.Lazy)
+	if imp.Loader != "" && !slices.Contains(info.Loaders, imp.Loader) {
+		info.Loaders = append(info.Loaders, imp.Loader)
+	}
+	info.Lines = append(info.Lines, imp.Location.Line)
+
+	isDefault, _ := edge.Metadata["is_default"].(bool)
+	edge.Metadata["is_default"] = isDefault || imp.IsDefault
+	edge.Metadata["count"] = info.Count
+	edge.Metadata["kinds"] = info.Kinds
+	edge.Metadata["specifiers"] = info.Specifiers
+	edge.Metadata["type_only"] = info.TypeOnly
+	edge.Metadata["lazy"] = info.Lazy
+	if len(info.Loaders) > 0 {
+		edge.Metadata["loaders"] = info.Loaders
+	}
+	edge.Metadata["lines"] = info.Lines
+}
+
+// ImportEdgeInfo reads the import metadata of an edge. It accepts metadata
+// decoded from JSON snapshots as well as metadata built in memory; edges
+// without it count as one statement.
+func ImportEdgeInfo(edge *types.GraphEdge) ImportEdge {
+	info := ImportEdge{
+		Count:      metadataInt(edge.Metadata["count"]),
+		Kinds:      metadataStrings(edge.Metadata["kinds"]),
+		Specifiers: metadataStrings(edge.Metadata["specifiers"]),
+		Loaders:    metadataStrings(edge.Metadata["loaders"]),
+	}
+	if info.Count == 0 {
+		info.Count = 1
+	}
+	info.TypeOnly, _ = edge.Metadata["type_only"].(bool)
+	info.Lazy, _ = edge.Metadata["lazy"].(bool)
+	switch lines := edge.Metadata["lines"].(type) {
+	case []int:
+		info.Lines = append(info.Lines, lines...)
+	case []interface{}:
+		for _, line := range lines {
+			info.Lines = append(info.Lines, metadataInt(line))
+		}
+	}
+	return info
+}
+
+// HasKind reports whether any statement behind the edge has the import kind
+func (e ImportEdge) HasKind(kind types.ImportKind) bool {
+	return slices.Contains(e.Kinds, string(kind))
+}
+
+func metadataInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func metadataStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return append([]string(nil), v...)
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
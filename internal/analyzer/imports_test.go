@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportEdgeMultiplicity(t *testing.T) {
+	graph := &types.CodeGraph{
+		Edges:   make(map[types.EdgeId]*types.GraphEdge),
+		Files:   make(map[string]*types.FileNode),
+		Symbols: make(map[types.SymbolId]*types.Symbol),
+	}
+	graph.Files["src/app.ts"] = &types.FileNode{Path: "src/app.ts", Imports: []*types.Import{
+		{Path: "./user", Specifiers: []string{"User"}, Kind: types.ImportKindTypeOnly, Location: types.FileLocation{Line: 1}},
+		{Path: "./user", Specifiers: []string{"loadUser", "User"}, Kind: types.ImportKindNamed, Location: types.FileLocation{Line: 2}},
+		{Path: "./theme", Kind: types.ImportKindTypeOnly, Location: types.FileLocation{Line: 3}},
+		{Path: "react", Specifiers: []string{"React"}, IsDefault: true, Kind: types.ImportKindDefault, Location: types.FileLocation{Line: 4}},
+		{Path: "react", Specifiers: []string{"useState"}, Kind: types.ImportKindNamed, Location: types.FileLocation{Line: 5}},
+	}}
+	graph.Files["src/user.ts"] = &types.FileNode{Path: "src/user.ts"}
+	graph.Files["src/theme.ts"] = &types.FileNode{Path: "src/theme.ts"}
+
+	metrics := &RelationshipMetrics{ByType: make(map[RelationshipType]int)}
+	NewRelationshipAnalyzer(graph).analyzeImportRelationships(metrics)
+	assert.Equal(t, 5, metrics.ByType[RelationshipImport], "every statement is counted")
+
+	user := graph.Edges["import-src/app.ts-src/user.ts"]
+	require.NotNil(t, user)
+	info := ImportEdgeInfo(user)
+	assert.Equal(t, 2, info.Count)
+	assert.Equal(t, []string{"named", "type_only"}, info.Kinds)
+	assert.Equal(t, []string{"User", "loadUser"}, info.Specifiers)
+	assert.False(t, info.TypeOnly, "a value import makes the dependency a runtime one")
+	assert.Equal(t, []int{1, 2}, info.Lines)
+
+	assert.True(t, ImportEdgeInfo(graph.Edges["import-src/app.ts-src/theme.ts"]).TypeOnly)
+
+	react := graph.Edges["external-import-src/app.ts-react"]
+	require.NotNil(t, react)
+	assert.Equal(t, 2, ImportEdgeInfo(react).Count)
+	assert.Equal(t, true, react.Metadata["is_default"])
+	assert.Equal(t, true, react.Metadata["is_external"])
+
+	// Metadata survives a JSON round trip, as in MCP graph snapshots
+	data, err := json.Marshal(user)
+	require.NoError(t, err)
+	var decoded types.GraphEdge
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, info, ImportEdgeInfo(&decoded))
+	assert.True(t, ImportEdgeInfo(&decoded).HasKind(types.ImportKindTypeOnly))
+
+	assert.Equal(t, 1, ImportEdgeInfo(&types.GraphEdge{}).Count, "edges without metadata are one statement")
+}
+
+func TestLazyImportEdges(t *testing.T) {
+	graph := &types.CodeGraph{
+		Edges:   make(map[types.EdgeId]*types.GraphEdge),
+		Files:   make(map[string]*types.FileNode),
+		Symbols: make(map[types.SymbolId]*types.Symbol),
+	}
+	graph.Files["src/app.ts"] = &types.FileNode{Path: "src/app.ts", Imports: []*types.Import{
+		{Path: "./settings", Kind: types.ImportKindDynamic, Lazy: true, Loader: "React.lazy"},
+		{Path: "./chart", Kind: types.ImportKindDynamic, Lazy: true},
+		{Path: "./chart", Kind: types.ImportKindNamed, Specifiers: []string{"ChartProps"}},
+	}}
+	graph.Files["src/settings.ts"] = &types.FileNode{Path: "src/settings.ts"}
+	graph.Files["src/chart.ts"] = &types.FileNode{Path: "src/chart.ts"}
+
+	metrics := &RelationshipMetrics{ByType: make(map[RelationshipType]int)}
+	NewRelationshipAnalyzer(graph).analyzeImportRelationships(metrics)
+
+	settings := ImportEdgeInfo(graph.Edges["import-src/app.ts-src/settings.ts"])
+	assert.True(t, settings.Lazy)
+	assert.Equal(t, []string{"React.lazy"}, settings.Loaders)
+
+	chart := ImportEdgeInfo(graph.Edges["import-src/app.ts-src/chart.ts"])
+	assert.False(t, chart.Lazy, "a static import makes the dependency a build-time one")
+	assert.True(t, chart.HasKind(types.ImportKindDynamic))
+}
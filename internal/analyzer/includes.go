@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// cppSourceExtensions lists the extensions treated as C/C++ translation units,
+// headers, C++20 module interface units or Objective-C++, CUDA and Metal sources
+var cppSourceExtensions = map[string]bool{
+	".cpp": true, ".cxx": true, ".cc": true, ".c++": true, ".c": true,
+	".hpp": true, ".hxx": true, ".hh": true, ".h++": true, ".h": true,
+	".cppm": true, ".ixx": true, ".mpp": true, ".cxxm": true, ".ccm": true, ".c++m": true,
+	".mm": true, ".cu": true, ".cuh": true, ".metal": true,
+}
+
+// isCppSourcePath reports whether a file path belongs to C/C++ code
+func isCppSourcePath(path string) bool {
+	return cppSourceExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// resolveIncludePath resolves an #include path to a file in the graph.
+// Quoted includes are searched relative to the including file first, then the
+// configured include directories, then by unique path suffix across the project.
+// System (<...>) includes skip the including file's directory.
+func (ra *RelationshipAnalyzer) resolveIncludePath(imp *types.Import, fromFile string) string {
+	isSystem := imp.IsSystem
+	includePath := filepath.Clean(filepath.FromSlash(imp.Path))
+	if filepath.IsAbs(includePath) || (isSystem && strings.HasPrefix(includePath, "..")) {
+		return ""
+	}
+
+	if !isSystem {
+		candidate := filepath.Join(filepath.Dir(fromFile), includePath)
+		if _, exists := ra.graph.Files[candidate]; exists {
+			return candidate
+		}
+	}
+
+	for _, dir := range ra.includeDirs {
+		candidate := filepath.Join(dir, includePath)
+		if _, exists := ra.graph.Files[candidate]; exists {
+			return candidate
+		}
+	}
+
+	// Bare system headers like <vector> or <stdio.h> are never project files
+	if isSystem && !strings.ContainsRune(includePath, filepath.Separator) {
+		return ""
+	}
+
+	return ra.matchHeaderSuffix(includePath, fromFile)
+}
+
+// matchHeaderSuffix finds project files whose path ends with includePath,
+// preferring the one closest to the including file when several match
+func (ra *RelationshipAnalyzer) matchHeaderSuffix(includePath, fromFile string) string {
+	if ra.headerIndex == nil {
+		ra.headerIndex = make(map[string][]string)
+		for path := range ra.graph.Files {
+			if isCppSourcePath(path) {
+				base := filepath.Base(path)
+				ra.headerIndex[base] = append(ra.headerIndex[base], path)
+			}
+		}
+	}
+
+	suffix := string(filepath.Separator) + includePath
+	best := ""
+	bestShared := -1
+	for _, candidate := range ra.headerIndex[filepath.Base(includePath)] {
+		if candidate == fromFile || !strings.HasSuffix(candidate, suffix) {
+			continue
+		}
+		shared := sharedPrefixLength(filepath.Dir(candidate), filepath.Dir(fromFile))
+		if shared > bestShared || shared == bestShared && candidate < best {
+			best = candidate
+			bestShared = shared
+		}
+	}
+	return best
+}
+
+// sharedPrefixLength counts the leading path components two directories have in common
+func sharedPrefixLength(a, b string) int {
+	aParts := strings.Split(a, string(filepath.Separator))
+	bParts := strings.Split(b, string(filepath.Separator))
+	count := 0
+	for count < len(aParts) && count < len(bParts) && aParts[count] == bParts[count] {
+		count++
+	}
+	return count
+}
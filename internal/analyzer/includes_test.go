@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+func createIncludeGraph() *types.CodeGraph {
+	graph := &types.CodeGraph{
+		Nodes:    make(map[types.NodeId]*types.GraphNode),
+		Edges:    make(map[types.EdgeId]*types.GraphEdge),
+		Files:    make(map[string]*types.FileNode),
+		Symbols:  make(map[types.SymbolId]*types.Symbol),
+		Metadata: &types.GraphMetadata{},
+	}
+
+	for _, path := range []string{
+		"/proj/src/engine.cpp",
+		"/proj/src/engine.h",
+		"/proj/include/core/types.h",
+		"/proj/lib/net/socket.h",
+		"/proj/tests/net/socket.h",
+	} {
+		graph.Files[path] = &types.FileNode{Path: path, Language: "cpp"}
+	}
+
+	return graph
+}
+
+func TestResolveIncludePath(t *testing.T) {
+	graph := createIncludeGraph()
+	ra := NewRelationshipAnalyzer(graph)
+	ra.SetIncludeDirs([]string{"/proj/include"})
+
+	tests := []struct {
+		name     string
+		include  *types.Import
+		fromFile string
+		expected string
+	}{
+		{"quoted sibling header", &types.Import{Path: "engine.h"}, "/proj/src/engine.cpp", "/proj/src/engine.h"},
+		{"include dir", &types.Import{Path: "core/types.h"}, "/proj/src/engine.cpp", "/proj/include/core/types.h"},
+		{"system include via include dir", &types.Import{Path: "core/types.h", IsSystem: true}, "/proj/src/engine.cpp", "/proj/include/core/types.h"},
+		{"standard library header", &types.Import{Path: "vector", IsSystem: true}, "/proj/src/engine.cpp", ""},
+		{"system include skips sibling", &types.Import{Path: "engine.h", IsSystem: true}, "/proj/src/engine.cpp", ""},
+		{"suffix match prefers closest", &types.Import{Path: "net/socket.h"}, "/proj/tests/net_test.cpp", "/proj/tests/net/socket.h"},
+		{"unknown header", &types.Import{Path: "missing.h"}, "/proj/src/engine.cpp", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ra.resolveIncludePath(tt.include, tt.fromFile); got != tt.expected {
+				t.Errorf("resolveIncludePath(%q) = %q, want %q", tt.include.Path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIncludeEdges(t *testing.T) {
+	graph := createIncludeGraph()
+	graph.Files["/proj/src/engine.cpp"].Imports = []*types.Import{
+		{Path: "engine.h"},
+		{Path: "vector", IsSystem: true},
+	}
+
+	metrics := &RelationshipMetrics{ByType: make(map[RelationshipType]int)}
+	NewRelationshipAnalyzer(graph).analyzeImportRelationships(metrics)
+
+	edge := graph.Edges["import-/proj/src/engine.cpp-/proj/src/engine.h"]
+	if edge == nil {
+		t.Fatal("Expected include edge from engine.cpp to engine.h")
+	}
+	if edge.To != "file-/proj/src/engine.h" {
+		t.Errorf("Unexpected edge target %s", edge.To)
+	}
+	if graph.Edges["external-import-/proj/src/engine.cpp-vector"] == nil {
+		t.Error("Expected <vector> to be recorded as an external include")
+	}
+}
+
+func TestDialectSourcesIncluded(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"gpu/kernels.cuh":       "__global__ void saxpy(int n, float a, const float* x, float* y);\n",
+		"gpu/kernels.cu":        "#include \"kernels.cuh\"\n__global__ void saxpy(int n, float a, const float* x, float* y) {}\n",
+		"shaders/Shaders.metal": "#include <metal_stdlib>\nkernel void add(device float* out [[buffer(0)]]) {}\n",
+		"app/Renderer.h":        "#import <Foundation/Foundation.h>\n@interface Renderer : NSObject\n- (void)draw;\n@end\n",
+		"app/Renderer.mm":       "#import \"Renderer.h\"\n@implementation Renderer\n- (void)draw {}\n@end\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name := range files {
+		file := graph.Files[filepath.Join(dir, name)]
+		if file == nil {
+			t.Errorf("Expected %s in the graph", name)
+		} else if file.Language != "cpp" || len(file.Symbols) == 0 {
+			t.Errorf("Expected C++ symbols in %s, got language %q and %d symbols", name, file.Language, len(file.Symbols))
+		}
+	}
+
+	if _, err := NewRelationshipAnalyzer(graph).AnalyzeAllRelationships(); err != nil {
+		t.Fatal(err)
+	}
+	for from, to := range map[string]string{"gpu/kernels.cu": "gpu/kernels.cuh", "app/Renderer.mm": "app/Renderer.h"} {
+		from, to = filepath.Join(dir, from), filepath.Join(dir, to)
+		if graph.Edges[types.EdgeId("import-"+from+"-"+to)] == nil {
+			t.Errorf("Expected an include edge from %s to %s", from, to)
+		}
+	}
+}
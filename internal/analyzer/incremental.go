@@ -116,6 +116,7 @@ func NewIncrementalAnalyzer(baseDir string, config *IncrementalConfig) (*Increme
 		analysisCache: make(map[string]*types.AST),
 		lastAnalysis:  time.Now(),
 	}
+	analyzer.parser.SetRoot(baseDir)
 
 	return analyzer, nil
 }
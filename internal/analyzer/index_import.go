@@ -0,0 +1,226 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/codeindex"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// EdgeProvenanceKey is the edge metadata key naming the index format ("scip"
+// or "lsif") a reference edge was imported or confirmed from. Edges without
+// it were inferred by the parsers.
+const EdgeProvenanceKey = "provenance"
+
+// IndexImport reports what one SCIP or LSIF index added to the graph
+type IndexImport struct {
+	Index       string `json:"index"`
+	Format      string `json:"format"`
+	Definitions int    `json:"definitions"` // Index definitions matched to graph symbols
+	References  int    `json:"references"`  // Reference edges added or confirmed
+}
+
+// SetIndexes sets the SCIP and LSIF indexes whose cross-references are
+// merged into the graph after relationship analysis. Relative paths are
+// resolved against the analyzed directory; indexes are re-read on every
+// analysis.
+func (gb *GraphBuilder) SetIndexes(paths []string) {
+	gb.indexes = paths
+}
+
+// addIndexes merges the configured indexes into the graph. Indexes that
+// cannot be read are skipped with a progress message, since they only
+// refine the analysis.
+func (gb *GraphBuilder) addIndexes(targetDir string) {
+	var imports []IndexImport
+	for _, path := range gb.indexes {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(targetDir, path)
+		}
+		index, err := codeindex.Load(path)
+		if err != nil {
+			if gb.progressCallback != nil {
+				gb.progressCallback(fmt.Sprintf("⚠️ Index skipped: %v", err))
+			}
+			continue
+		}
+		imported := ApplyIndex(gb.graph, targetDir, index)
+		if gb.progressCallback != nil {
+			gb.progressCallback(fmt.Sprintf("🧭 %s: %d definitions, %d references imported", filepath.Base(path), imported.Definitions, imported.References))
+		}
+		imports = append(imports, imported)
+	}
+	if len(imports) > 0 {
+		gb.graph.Metadata.Configuration["index_imports"] = imports
+	}
+}
+
+// ApplyIndex merges the cross-references of an index into the graph: each
+// definition is matched to the graph symbol declared on its line, and each
+// reference becomes a "references" edge from the symbol enclosing it to the
+// symbol it refers to. Existing edges between the same symbols are confirmed
+// rather than duplicated. Occurrences in files outside the graph are ignored.
+func ApplyIndex(graph *types.CodeGraph, root string, index *codeindex.Index) IndexImport {
+	imported := IndexImport{Index: index.Path, Format: index.Format}
+
+	files := make(map[string]string, len(graph.Files)) // Project path -> graph path
+	for path := range graph.Files {
+		files[projectPath(root, path)] = path
+	}
+	// Index paths are relative to the index's root, which may be a subdirectory
+	prefix := ""
+	if index.Root != "" {
+		if rel := projectPath(root, index.Root); rel != "." && !strings.HasPrefix(rel, "..") && !filepath.IsAbs(rel) {
+			prefix = rel + "/"
+		}
+	}
+	fileOf := func(occurrence codeindex.Occurrence) string {
+		if filepath.IsAbs(occurrence.File) {
+			return files[projectPath(root, occurrence.File)]
+		}
+		return files[prefix+occurrence.File]
+	}
+	spans := make(map[string][]symbolSpan)
+	spansOf := func(path string) []symbolSpan {
+		if _, ok := spans[path]; !ok {
+			spans[path] = symbolSpans(graph, graph.Files[path])
+		}
+		return spans[path]
+	}
+
+	ids := make([]string, 0, len(index.Symbols))
+	for id := range index.Symbols {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		indexed := index.Symbols[id]
+		name := indexSymbolName(id)
+		var targets []*types.Symbol
+		var targetPath string
+		for _, definition := range indexed.Definitions() {
+			if path := fileOf(definition); path != "" {
+				if target := symbolDefinedAt(spansOf(path), definition.Line, name); target != nil {
+					if len(targets) == 0 {
+						targetPath = path
+					}
+					targets = append(targets, target)
+				}
+			}
+		}
+		if len(targets) == 0 {
+			continue
+		}
+		imported.Definitions += len(targets)
+		// A C/C++ declaration and its definition are one symbol in the index;
+		// references point at the first
+		target := targets[0]
+
+		for _, reference := range indexed.References() {
+			path := fileOf(reference)
+			if path == "" {
+				continue
+			}
+			from := enclosingSymbol(spansOf(path), reference.Line)
+			if from == nil || from.Id == target.Id {
+				continue
+			}
+			addIndexedReference(graph, index, from, path, target, targetPath, reference.Line)
+			imported.References++
+		}
+	}
+	return imported
+}
+
+// addIndexedReference adds a reference edge or confirms the inferred one
+func addIndexedReference(graph *types.CodeGraph, index *codeindex.Index, from *types.Symbol, fromPath string, to *types.Symbol, toPath string, line int) {
+	edgeId := types.EdgeId(fmt.Sprintf("ref-%s-%s", from.Id, to.Id))
+	edge := graph.Edges[edgeId]
+	if edge == nil {
+		edge = &types.GraphEdge{
+			Id:   edgeId,
+			From: types.NodeId(fmt.Sprintf("symbol-%s", from.Id)),
+			To:   types.NodeId(fmt.Sprintf("symbol-%s", to.Id)),
+			Type: string(RelationshipReferences),
+			Metadata: map[string]interface{}{
+				"reference_type": "usage",
+				"source_file":    fromPath,
+				"target_file":    toPath,
+			},
+		}
+		graph.Edges[edgeId] = edge
+	}
+	if edge.Metadata == nil {
+		edge.Metadata = make(map[string]interface{})
+	}
+	edge.Weight = 1.0
+	edge.Metadata[EdgeProvenanceKey] = index.Format
+	edge.Metadata["index"] = filepath.Base(index.Path)
+	lines, _ := edge.Metadata["lines"].([]int)
+	edge.Metadata["lines"] = append(lines, line)
+}
+
+// symbolDefinedAt returns the symbol declared on a line, preferring one
+// named name. Symbols whose parser recorded the line of a decorator or
+// annotation above the name match when they carry the name.
+func symbolDefinedAt(spans []symbolSpan, line int, name string) *types.Symbol {
+	var found *types.Symbol
+	for _, span := range spans {
+		symbol := span.symbol
+		switch {
+		case symbol.Location.StartLine == line && symbol.Name == name:
+			return symbol
+		case symbol.Location.StartLine == line && found == nil:
+			found = symbol
+		case symbol.Name == name && symbol.Location.StartLine < line && symbol.Location.StartLine >= line-3 && found == nil:
+			found = symbol
+		}
+	}
+	return found
+}
+
+// enclosingSymbol returns the innermost symbol whose span holds a line
+func enclosingSymbol(spans []symbolSpan, line int) *types.Symbol {
+	var found *symbolSpan
+	for i := range spans {
+		span := &spans[i]
+		if span.start <= line && line <= span.end && (found == nil || span.start >= found.start) {
+			found = span
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	return found.symbol
+}
+
+// indexSymbolName returns the simple name at the end of a SCIP symbol
+// ("... `pkg/shop`/Cart#Add()." is Add) or LSIF moniker ("gomod:shop:Cart.Add")
+func indexSymbolName(id string) string {
+	name := strings.TrimSuffix(id, ".")
+	if strings.HasSuffix(name, ")") {
+		// Method disambiguators such as "(+1)"
+		if i := strings.LastIndex(name, "("); i >= 0 {
+			name = name[:i]
+		}
+	}
+	name = strings.TrimRight(name, "#/.!:")
+	if strings.HasSuffix(name, "]") {
+		// Type parameters such as "[T]"
+		if i := strings.LastIndex(name, "["); i >= 0 {
+			name = name[:i]
+		}
+	}
+	if strings.HasSuffix(name, "`") {
+		if i := strings.LastIndex(name[:len(name)-1], "`"); i >= 0 {
+			return strings.ReplaceAll(name[i+1:len(name)-1], "``", "`")
+		}
+	}
+	if i := strings.LastIndexAny(name, "/#.: "); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/codeindex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyIndex(t *testing.T) {
+	dir := t.TempDir()
+	cart := `package shop
+
+type Cart struct {
+	Items []int
+}
+
+func (c *Cart) Add(item int) {
+	c.Items = append(c.Items, item)
+}
+`
+	checkout := `package shop
+
+func Checkout(c *Cart) {
+	c.Add(1)
+	c.Add(2)
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cart.go"), []byte(cart), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checkout.go"), []byte(checkout), 0644))
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	const add = "scip-go gomod shop v1 `shop`/Cart#Add()."
+	index := &codeindex.Index{
+		Path:   filepath.Join(dir, "index.scip"),
+		Format: codeindex.FormatSCIP,
+		Root:   dir,
+		Symbols: map[string]*codeindex.Symbol{
+			add: {ID: add, Occurrences: []codeindex.Occurrence{
+				{File: "cart.go", Line: 7, Column: 15, Definition: true},
+				{File: "checkout.go", Line: 4, Column: 3},
+				{File: "checkout.go", Line: 5, Column: 3},
+				{File: "vendor/other.go", Line: 1, Column: 0},
+			}},
+		},
+	}
+	imported := ApplyIndex(graph, dir, index)
+	assert.Equal(t, 1, imported.Definitions)
+	assert.Equal(t, 2, imported.References, "occurrences outside the graph are ignored")
+
+	var found bool
+	for _, edge := range graph.Edges {
+		if edge.Metadata[EdgeProvenanceKey] == nil {
+			continue
+		}
+		found = true
+		assert.Equal(t, string(RelationshipReferences), edge.Type)
+		assert.Equal(t, "scip", edge.Metadata[EdgeProvenanceKey])
+		assert.Equal(t, "index.scip", edge.Metadata["index"])
+		assert.Equal(t, []int{4, 5}, edge.Metadata["lines"])
+		from := graph.Nodes[edge.From]
+		to := graph.Nodes[edge.To]
+		require.NotNil(t, from)
+		require.NotNil(t, to)
+		assert.Equal(t, "Checkout", from.Label)
+		assert.Equal(t, "Add", to.Label)
+	}
+	assert.True(t, found, "the reference from Checkout to Add is imported")
+}
+
+func TestIndexSymbolName(t *testing.T) {
+	assert.Equal(t, "Add", indexSymbolName("scip-go gomod shop v1 `shop`/Cart#Add()."))
+	assert.Equal(t, "Add", indexSymbolName("scip-go gomod shop v1 `shop`/Cart#Add(+1)."))
+	assert.Equal(t, "Cart", indexSymbolName("scip-go gomod shop v1 `shop`/Cart#"))
+	assert.Equal(t, "Map", indexSymbolName("scip-go gomod shop v1 `shop`/Map[T]#"))
+	assert.Equal(t, "a.go", indexSymbolName("scip-go gomod shop v1 `a.go`/"))
+	assert.Equal(t, "Add", indexSymbolName("gomod:shop:Cart.Add"))
+}
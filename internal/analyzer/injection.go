@@ -0,0 +1,547 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipInjects links a class or provider to a dependency its
+// dependency injection container injects into it
+const RelationshipInjects RelationshipType = "injects"
+
+// Dependency injection frameworks
+const (
+	InjectionNest    = "nestjs"  // @Injectable classes and module providers
+	InjectionAngular = "angular" // @Injectable/@Component classes and inject() calls
+	InjectionSpring  = "spring"  // Spring and JSR-330 components, @Autowired fields and @Bean methods
+	InjectionWire    = "wire"    // Google Wire provider sets
+	InjectionFx      = "fx"      // Uber fx providers and invocations
+)
+
+var (
+	// TypeScript: decorators registering a class with the container
+	tsInjectableDecorator = regexp.MustCompile(`^@(Injectable|Controller|Component|Directive|Pipe|Resolver|WebSocketGateway)\s*\(`)
+	tsClassDeclaration    = regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+(\w+)`)
+	tsInjectToken         = regexp.MustCompile(`@Inject\(\s*(?:forwardRef\(\s*\(\)\s*=>\s*)?['"]?([\w.]+)`)
+	tsParameterType       = regexp.MustCompile(`:\s*([A-Z]\w*)`)
+	tsInjectCall          = regexp.MustCompile(`\binject\(\s*([A-Z]\w*)`)
+	// NestJS and Angular providers binding a token to a class: { provide: X, useClass: Y }
+	providerBinding = regexp.MustCompile(`provide:\s*['"]?([\w.]+)['"]?\s*,\s*use(?:Class|Existing):\s*(\w+)`)
+
+	// Java: stereotype annotations, injection points and bean factories
+	springComponentAnnotation = regexp.MustCompile(`^@(?:Component|Service|Repository|Controller|RestController|Configuration|Named|Singleton)\b`)
+	javaClassDeclaration      = regexp.MustCompile(`^(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:public|protected|private|abstract|final|static)\s+)*(?:class|record)\s+(\w+)`)
+	springInjectAnnotation    = regexp.MustCompile(`^@(?:Autowired|Inject|Resource)\b`)
+	springQualifier           = regexp.MustCompile(`@(?:Qualifier|Named)\(\s*(?:value\s*=\s*)?"([^"]+)"`)
+	javaAnnotation            = regexp.MustCompile(`@\w+(?:\([^)]*\))?\s*`)
+	javaLeadingAnnotations    = regexp.MustCompile(`^(?:@\w+(?:\([^)]*\))?\s*)+`)
+	javaFieldDeclaration      = regexp.MustCompile(`^(?:(?:private|protected|public|final|static)\s+)*([A-Z]\w*)(?:<.*>)?\s+\w+\s*(?:;|=)`)
+	javaMethodDeclaration     = regexp.MustCompile(`^(?:(?:public|protected|private|static|final)\s+)*([A-Z]\w*)(?:<.*>)?\s+\w+\s*\(`)
+
+	// Go: container calls listing providers, and interface bindings
+	goContainerCall = regexp.MustCompile(`\b(wire\.Build|wire\.NewSet|fx\.Provide|fx\.Invoke)\(`)
+	goWireBind      = regexp.MustCompile(`^wire\.Bind\(\s*new\(\s*\*?([\w.]+)\s*\)\s*,\s*new\(\s*\*?([\w.]+)\s*\)`)
+	goIdentifier    = regexp.MustCompile(`^[\w.]+$`)
+)
+
+// injectionLanguages are the languages dependency injection detection looks at
+var injectionLanguages = map[string]bool{"typescript": true, "javascript": true, "java": true, "go": true}
+
+// goBuiltinTypes are never provided by a container
+var goBuiltinTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true, "int": true, "int8": true, "int16": true, "int32": true,
+	"int64": true, "rune": true, "string": true, "uint": true, "uint8": true, "uint16": true,
+	"uint32": true, "uint64": true, "uintptr": true,
+}
+
+// injection is one dependency a container hands to a consumer
+type injection struct {
+	consumer   *types.Symbol
+	dependency string // Type name as declared
+	token      string // Injection token or qualifier, when not the type itself
+	framework  string
+}
+
+// goProvider is a function listed in a Wire provider set or an fx option
+type goProvider struct {
+	name      string
+	dir       string
+	invoke    bool // fx.Invoke: the function consumes its parameters but provides nothing
+	framework string
+}
+
+// analyzeInjection resolves what dependency injection containers wire into
+// each class or provider: constructor parameters and injected fields of
+// NestJS, Angular and Spring components, and the parameters of Wire and fx
+// providers. Dependencies bound to an implementation (provide/useClass,
+// wire.Bind) or, in Java, interfaces with a single implementation resolve to
+// the implementation, recording the declared type as "via".
+func (ra *RelationshipAnalyzer) analyzeInjection(metrics *RelationshipMetrics) {
+	var injections []injection
+	var providers []goProvider
+	bindings := make(map[string]string) // Framework and token to the bound type
+	index := ra.buildTypeIndex()
+	ra.forEachSourceFileIn(injectionLanguages, func(filePath, content string) {
+		switch ra.graph.Files[filePath].Language {
+		case "typescript", "javascript":
+			injections = append(injections, ra.scanTypeScriptInjections(filePath, content, bindings)...)
+		case "java":
+			injections = append(injections, ra.scanSpringInjections(filePath, content, index)...)
+		case "go":
+			providers = append(providers, scanGoProviders(filePath, content, bindings)...)
+		}
+	})
+	injections = append(injections, ra.goProviderInjections(index, providers)...)
+	if len(injections) == 0 {
+		return
+	}
+
+	implementers := make(map[types.NodeId][]types.NodeId)
+	for _, edge := range ra.graph.Edges {
+		if edge.Type == string(RelationshipImplements) {
+			implementers[edge.To] = append(implementers[edge.To], edge.From)
+		}
+	}
+
+	for _, inj := range injections {
+		name, via := inj.dependency, ""
+		if bound, ok := bindings[inj.framework+"\x00"+inj.token]; ok && inj.token != "" {
+			name, via = bound, inj.token
+		} else if bound, ok := bindings[inj.framework+"\x00"+inj.dependency]; ok {
+			name, via = bound, inj.dependency
+		}
+
+		from := types.NodeId(fmt.Sprintf("symbol-%s", inj.consumer.Id))
+		metadata := map[string]interface{}{
+			"framework":  inj.framework,
+			"dependency": inj.dependency,
+		}
+		if inj.token != "" {
+			metadata["token"] = inj.token
+		}
+
+		var to types.NodeId
+		target := ra.resolveTypeName(index, name, inj.consumer)
+		if target != nil && target.Id == inj.consumer.Id {
+			continue
+		}
+		if target != nil {
+			to = types.NodeId(fmt.Sprintf("symbol-%s", target.Id))
+			// Spring autowires an interface with the one class implementing it
+			if impls := implementers[to]; via == "" && inj.framework == InjectionSpring && len(impls) == 1 {
+				to, via = impls[0], target.Name
+			}
+		} else {
+			// Framework and library types (e.g. HttpClient, *sql.DB) live outside the repo
+			to = types.NodeId(fmt.Sprintf("external-type-%s", name))
+			metadata["is_external"] = true
+		}
+		if via != "" {
+			metadata["via"] = via
+		}
+
+		edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", RelationshipInjects, from, to))
+		if _, exists := ra.graph.Edges[edgeId]; exists {
+			continue
+		}
+		ra.graph.Edges[edgeId] = &types.GraphEdge{
+			Id:       edgeId,
+			From:     from,
+			To:       to,
+			Type:     string(RelationshipInjects),
+			Weight:   1.0,
+			Metadata: metadata,
+		}
+		metrics.ByType[RelationshipInjects]++
+		metrics.SymbolToSymbol++
+	}
+}
+
+// scanTypeScriptInjections finds the constructor parameters and inject()
+// calls of decorated NestJS and Angular classes, and records the provider
+// bindings the file declares
+func (ra *RelationshipAnalyzer) scanTypeScriptInjections(filePath, content string, bindings map[string]string) []injection {
+	framework := ""
+	switch {
+	case strings.Contains(content, "@angular/"):
+		framework = InjectionAngular
+	case strings.Contains(content, "@nestjs/"):
+		framework = InjectionNest
+	}
+	for _, m := range providerBinding.FindAllStringSubmatch(content, -1) {
+		bindings[firstNonEmpty(framework, InjectionNest)+"\x00"+lastSegment(m[1])] = m[2]
+	}
+
+	var injections []injection
+	var consumer *types.Symbol
+	classFramework, decorator := "", ""
+	offset := 0
+	for _, line := range strings.Split(content, "\n") {
+		lineStart := offset
+		offset += len(line) + 1
+		trimmed := strings.TrimSpace(line)
+		if m := tsInjectableDecorator.FindStringSubmatch(trimmed); m != nil {
+			decorator = m[1]
+			continue
+		}
+		if m := tsClassDeclaration.FindStringSubmatch(trimmed); m != nil {
+			consumer = nil
+			if decorator != "" {
+				consumer = ra.fileTypeSymbol(filePath, m[1])
+				classFramework = framework
+				if classFramework == "" {
+					// Without imports to go by, view decorators are Angular's
+					classFramework = InjectionNest
+					if decorator == "Component" || decorator == "Directive" || decorator == "Pipe" {
+						classFramework = InjectionAngular
+					}
+				}
+			}
+			decorator = ""
+			continue
+		}
+		if consumer == nil {
+			continue
+		}
+
+		add := func(dependency, token string) {
+			if token == dependency {
+				token = ""
+			}
+			injections = append(injections, injection{consumer: consumer, dependency: dependency, token: token, framework: classFramework})
+		}
+		if i := strings.Index(line, "constructor("); i >= 0 {
+			for _, param := range splitTopLevel(parenContents(content, lineStart+i+len("constructor"))) {
+				token := ""
+				if m := tsInjectToken.FindStringSubmatch(param); m != nil {
+					token = lastSegment(m[1])
+				}
+				// The type follows the decorators' arguments
+				if m := tsParameterType.FindStringSubmatch(param[strings.LastIndex(param, ")")+1:]); m != nil {
+					add(m[1], token)
+				} else if token != "" {
+					add(token, "")
+				}
+			}
+		}
+		for _, m := range tsInjectCall.FindAllStringSubmatch(line, -1) {
+			add(m[1], "")
+		}
+	}
+	return injections
+}
+
+// scanSpringInjections finds the constructor parameters and @Autowired or
+// @Inject fields of Spring components, and the parameters of @Bean methods
+func (ra *RelationshipAnalyzer) scanSpringInjections(filePath, content string, index map[string][]*types.Symbol) []injection {
+	var injections []injection
+	var consumer *types.Symbol
+	var constructor *regexp.Regexp
+	component, injectNext, beanNext := false, false, false
+	qualifier := ""
+	offset := 0
+	for i, line := range strings.Split(content, "\n") {
+		lineStart := offset
+		offset += len(line) + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "/*") {
+			continue
+		}
+		if springComponentAnnotation.MatchString(trimmed) {
+			component = true
+		}
+		if m := javaClassDeclaration.FindStringSubmatch(trimmed); m != nil {
+			consumer, constructor = nil, nil
+			if component {
+				consumer = ra.fileTypeSymbol(filePath, m[1])
+				constructor = regexp.MustCompile(`^(?:(?:public|protected|private)\s+)?` + regexp.QuoteMeta(m[1]) + `\s*\(`)
+			}
+			component, injectNext, beanNext = false, false, false
+			continue
+		}
+		if consumer == nil {
+			continue
+		}
+
+		add := func(to *types.Symbol, dependency, token string) {
+			injections = append(injections, injection{consumer: to, dependency: dependency, token: token, framework: InjectionSpring})
+		}
+		if springInjectAnnotation.MatchString(trimmed) {
+			injectNext = true
+		}
+		if strings.HasPrefix(trimmed, "@Bean") {
+			beanNext = true
+		}
+		if m := springQualifier.FindStringSubmatch(trimmed); m != nil {
+			qualifier = m[1]
+		}
+		declaration := strings.TrimSpace(javaLeadingAnnotations.ReplaceAllString(trimmed, ""))
+		if declaration == "" {
+			continue
+		}
+		// Parameters open at the declaration's first parenthesis, after any annotations
+		params := func() []string {
+			open := strings.LastIndex(line, declaration)
+			if open < 0 {
+				return nil
+			}
+			return splitTopLevel(parenContents(content, lineStart+open+strings.Index(declaration, "(")))
+		}
+
+		switch {
+		case constructor.MatchString(declaration):
+			for _, param := range params() {
+				if dependency, token := javaParameter(param); dependency != "" {
+					add(consumer, dependency, token)
+				}
+			}
+		case beanNext:
+			if m := javaMethodDeclaration.FindStringSubmatch(declaration); m != nil {
+				// The bean is the returned type, or the factory method when it is not in the repo
+				bean := ra.resolveTypeName(index, m[1], consumer)
+				if bean == nil {
+					bean = ra.enclosingSymbol(filePath, i+1)
+				}
+				if bean != nil {
+					for _, param := range params() {
+						if dependency, token := javaParameter(param); dependency != "" {
+							add(bean, dependency, token)
+						}
+					}
+				}
+			}
+		case injectNext:
+			if m := javaFieldDeclaration.FindStringSubmatch(declaration); m != nil {
+				add(consumer, m[1], qualifier)
+			}
+		}
+		injectNext, beanNext, qualifier = false, false, ""
+	}
+	return injections
+}
+
+// javaParameter returns the type and qualifier of a constructor or method
+// parameter, or "" for primitives, strings and @Value configuration
+func javaParameter(param string) (string, string) {
+	if strings.Contains(param, "@Value(") {
+		return "", ""
+	}
+	token := ""
+	if m := springQualifier.FindStringSubmatch(param); m != nil {
+		token = m[1]
+	}
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(javaAnnotation.ReplaceAllString(param, "")), "final "))
+	if len(fields) == 0 {
+		return "", ""
+	}
+	typeName := fields[0]
+	if i := strings.Index(typeName, "<"); i >= 0 {
+		typeName = typeName[:i]
+	}
+	typeName = lastSegment(typeName)
+	if typeName == "" || typeName[0] < 'A' || typeName[0] > 'Z' || typeName == "String" {
+		return "", ""
+	}
+	return typeName, token
+}
+
+// scanGoProviders finds the functions passed to Wire provider sets and fx
+// options, and records wire.Bind interface bindings
+func scanGoProviders(filePath, content string, bindings map[string]string) []goProvider {
+	var providers []goProvider
+	for _, loc := range goContainerCall.FindAllStringSubmatchIndex(content, -1) {
+		call := content[loc[2]:loc[3]]
+		provider := goProvider{dir: filepath.Dir(filePath), invoke: call == "fx.Invoke", framework: InjectionWire}
+		if strings.HasPrefix(call, "fx.") {
+			provider.framework = InjectionFx
+		}
+		for _, arg := range splitTopLevel(parenContents(content, loc[3])) {
+			if m := goWireBind.FindStringSubmatch(arg); m != nil {
+				bindings[InjectionWire+"\x00"+lastSegment(m[1])] = lastSegment(m[2])
+				continue
+			}
+			if strings.HasPrefix(arg, "fx.Annotate(") {
+				if args := splitTopLevel(parenContents(arg, len("fx.Annotate"))); len(args) > 0 {
+					arg = args[0]
+				}
+			}
+			// Nested provider sets are calls of their own; values and structs provide no function
+			if goIdentifier.MatchString(arg) {
+				provider.name = lastSegment(arg)
+				providers = append(providers, provider)
+			}
+		}
+	}
+	return providers
+}
+
+// goProviderInjections turns Go providers into injections: the parameters
+// of a provider are injected into the type it returns, or into the function
+// itself for fx.Invoke and providers returning types outside the repo
+func (ra *RelationshipAnalyzer) goProviderInjections(index map[string][]*types.Symbol, providers []goProvider) []injection {
+	if len(providers) == 0 {
+		return nil
+	}
+	functions := make(map[string][]*types.Symbol)
+	for _, file := range ra.graph.Files {
+		if file.Language != "go" || file.IsTest {
+			continue
+		}
+		for _, id := range file.Symbols {
+			if symbol := ra.graph.Symbols[id]; symbol != nil && symbol.Type == types.SymbolTypeFunction {
+				functions[symbol.Name] = append(functions[symbol.Name], symbol)
+			}
+		}
+	}
+	for _, candidates := range functions {
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].FullyQualifiedName < candidates[j].FullyQualifiedName })
+	}
+
+	var injections []injection
+	seen := make(map[string]bool)
+	for _, provider := range providers {
+		fn := goProviderFunction(functions[provider.name], provider.dir)
+		if fn == nil || seen[string(fn.Id)+"\x00"+provider.framework] {
+			continue
+		}
+		seen[string(fn.Id)+"\x00"+provider.framework] = true
+
+		i := strings.Index(fn.Signature, fn.Name+"(")
+		if i < 0 {
+			continue
+		}
+		open := i + len(fn.Name)
+		params := parenContents(fn.Signature, open)
+		consumer := fn
+		if !provider.invoke {
+			results := strings.TrimSpace(fn.Signature[min(open+len(params)+2, len(fn.Signature)):])
+			if strings.HasPrefix(results, "(") {
+				if list := splitTopLevel(parenContents(results, 0)); len(list) > 0 {
+					results = list[0]
+				}
+			}
+			if result := goTypeName(results); result != "" {
+				if target := ra.resolveTypeName(index, result, fn); target != nil {
+					consumer = target
+				}
+			}
+		}
+		for _, param := range goParameterTypes(params) {
+			injections = append(injections, injection{consumer: consumer, dependency: param, framework: provider.framework})
+		}
+	}
+	return injections
+}
+
+// goProviderFunction picks the provider among functions of that name,
+// preferring the directory listing it
+func goProviderFunction(candidates []*types.Symbol, dir string) *types.Symbol {
+	for _, candidate := range candidates {
+		if filepath.Dir(types.FilePathFromQualifiedName(candidate.FullyQualifiedName)) == dir {
+			return candidate
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// goParameterTypes returns the type names of a Go parameter list, once each.
+// Grouped parameters ("a, b Repo") share the type written after the last one.
+func goParameterTypes(params string) []string {
+	entries := splitTopLevel(params)
+	named := false
+	for _, entry := range entries {
+		if len(strings.Fields(entry)) > 1 {
+			named = true
+		}
+	}
+	var typeNames []string
+	for _, entry := range entries {
+		fields := strings.Fields(entry)
+		if named {
+			if len(fields) < 2 {
+				continue
+			}
+			entry = strings.Join(fields[1:], " ")
+		}
+		if typeName := goTypeName(entry); typeName != "" {
+			typeNames = appendUnique(typeNames, typeName)
+		}
+	}
+	return typeNames
+}
+
+// goTypeName returns the named type of a Go type expression without
+// pointers, slices, package qualifier or type arguments, or "" for builtin,
+// function, map and channel types
+func goTypeName(expr string) string {
+	expr = strings.TrimLeft(strings.TrimSpace(expr), "*[].")
+	if strings.HasPrefix(expr, "func") || strings.HasPrefix(expr, "map[") || strings.HasPrefix(expr, "chan ") ||
+		strings.HasPrefix(expr, "interface") || strings.HasPrefix(expr, "struct") {
+		return ""
+	}
+	if i := strings.IndexAny(expr, "[ "); i >= 0 {
+		expr = expr[:i]
+	}
+	expr = lastSegment(expr)
+	if expr == "" || goBuiltinTypes[expr] {
+		return ""
+	}
+	return expr
+}
+
+// fileTypeSymbol returns the class-like symbol of that name declared in a file
+func (ra *RelationshipAnalyzer) fileTypeSymbol(filePath, name string) *types.Symbol {
+	fileNode := ra.graph.Files[filePath]
+	if fileNode == nil {
+		return nil
+	}
+	for _, id := range fileNode.Symbols {
+		if symbol := ra.graph.Symbols[id]; symbol != nil && symbol.Name == name && isTypeSymbol(symbol.Type) {
+			return symbol
+		}
+	}
+	return nil
+}
+
+// parenContents returns the text between the parenthesis at open and its
+// matching close, or up to the end of the text when it is unbalanced
+func parenContents(text string, open int) string {
+	if open < 0 || open >= len(text) || text[open] != '(' {
+		return ""
+	}
+	depth := 0
+	for i := open; i < len(text); i++ {
+		switch text[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return text[open+1 : i]
+			}
+		}
+	}
+	return text[open+1:]
+}
+
+// splitTopLevel splits a list at the commas outside brackets and returns
+// the trimmed, non-empty items
+func splitTopLevel(list string) []string {
+	var items []string
+	for _, item := range parser.SplitTopLevel(list, ',') {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
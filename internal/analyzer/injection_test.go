@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+func TestAnalyzeInjection(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// NestJS: constructor parameters, an @Inject token and a module binding
+		"nest/users.service.ts": "import { Injectable, Inject } from '@nestjs/common';\n\nexport abstract class Mailer {}\n\n" +
+			"@Injectable()\nexport class UsersService {\n  constructor(\n    private readonly repo: UsersRepository,\n    @Inject(forwardRef(() => Mailer)) private mailer: Mailer,\n    private config: ConfigService,\n  ) {}\n}\n",
+		"nest/users.repository.ts": "import { Injectable } from '@nestjs/common';\n\n@Injectable()\nexport class UsersRepository {}\n\n@Injectable()\nexport class SmtpMailer {}\n",
+		"nest/users.module.ts":     "import { Module } from '@nestjs/common';\n\n@Module({\n  providers: [UsersService, { provide: Mailer, useClass: SmtpMailer }],\n})\nexport class UsersModule {}\n",
+		"nest/plain.ts":            "export class Plain {\n  constructor(private repo: UsersRepository) {}\n}\n",
+		// Angular: inject() calls
+		"web/cart.component.ts": "import { Component, inject } from '@angular/core';\n\n@Component({ selector: 'app-cart' })\nexport class CartComponent {\n  private http = inject(HttpClient);\n}\n",
+		// Spring: @Autowired fields, constructors, a single implementation and @Bean methods
+		"spring/OrderService.java": "package shop;\n\n@Service\npublic class OrderService {\n    @Autowired\n    @Qualifier(\"stripe\")\n    private PaymentClient payments;\n\n" +
+			"    private final OrderRepository repository;\n\n    public OrderService(OrderRepository repository, int retries) {\n        this.repository = repository;\n    }\n}\n",
+		"spring/OrderRepository.java": "package shop;\n\npublic interface OrderRepository {\n}\n",
+		"spring/JpaOrders.java":       "package shop;\n\n@Repository\npublic class JpaOrders implements OrderRepository {\n}\n",
+		"spring/PaymentClient.java":   "package shop;\n\n@Component\npublic class PaymentClient {\n}\n",
+		"spring/AppConfig.java":       "package shop;\n\n@Configuration\npublic class AppConfig {\n    @Bean\n    public PaymentClient paymentClient(@Value(\"${url}\") String url, Clock clock) {\n        return new PaymentClient();\n    }\n}\n",
+		// Go: Wire provider sets with a binding, and fx options
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"app/server.go": "package app\n\ntype Repo interface{ Get() string }\n\ntype Store struct{}\n\ntype Logger struct{}\n\ntype Server struct{}\n\n" +
+			"func NewStore() *Store { return &Store{} }\n\nfunc NewLogger() *Logger { return &Logger{} }\n\n" +
+			"func NewServer(repo Repo, log *Logger, name string) (*Server, error) {\n\treturn &Server{}, nil\n}\n\n" +
+			"func Register(s *Server, lc fx.Lifecycle) {}\n",
+		"app/wire.go": "package app\n\nimport \"github.com/google/wire\"\n\nfunc InitializeServer() (*Server, error) {\n\twire.Build(NewServer, NewStore, NewLogger, wire.Bind(new(Repo), new(*Store)))\n\treturn nil, nil\n}\n",
+		"app/main.go": "package app\n\nfunc Run() {\n\tfx.New(fx.Provide(NewLogger), fx.Invoke(Register))\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	name := func(node types.NodeId) string {
+		if symbol := graph.Symbols[types.SymbolId(strings.TrimPrefix(string(node), "symbol-"))]; symbol != nil {
+			return symbol.Name
+		}
+		return string(node)
+	}
+	var injected []string
+	for _, edge := range graph.Edges {
+		if edge.Type != string(RelationshipInjects) {
+			continue
+		}
+		line := edge.Metadata["framework"].(string) + " " + name(edge.From) + " <- " + name(edge.To)
+		if via, ok := edge.Metadata["via"]; ok {
+			line += " via " + via.(string)
+		}
+		injected = append(injected, line)
+	}
+	sort.Strings(injected)
+	assert.Equal(t, []string{
+		"angular CartComponent <- external-type-HttpClient",
+		"fx Register <- Server",
+		"fx Register <- external-type-Lifecycle",
+		"nestjs UsersService <- SmtpMailer via Mailer",
+		"nestjs UsersService <- UsersRepository",
+		"nestjs UsersService <- external-type-ConfigService",
+		"spring OrderService <- JpaOrders via OrderRepository",
+		"spring OrderService <- PaymentClient",
+		"spring PaymentClient <- external-type-Clock",
+		"wire Server <- Logger",
+		"wire Server <- Store via Repo",
+	}, injected, "undecorated classes, primitives and providers without parameters inject nothing")
+}
+
+func TestGoParameterTypes(t *testing.T) {
+	assert.Equal(t, []string{"Repo", "Logger"}, goParameterTypes("a, b Repo, log *pkg.Logger, n int"))
+	assert.Equal(t, []string{"Repo", "Cache"}, goParameterTypes("Repo, []*Cache[string], error"))
+	assert.Empty(t, goParameterTypes(""))
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeLanguageDetection(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"scripts/deploy":         "#!/usr/bin/env bash\ndeploy() {\n    echo deploying\n}\n",
+		"tools/migrate":          "#!/usr/bin/env python3\ndef migrate():\n    pass\n",
+		"hooks/pre-commit.hook":  "def check():\n    pass\n",
+		"node_modules/.bin/tool": "#!/usr/bin/env node\nfunction tool() {}\n",
+		"README":                 "Plain text\n",
+		"assets/logo.png":        "\x89PNG\x00\x00",
+		"src/main.go":            "package main\n\nfunc main() {}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+
+	builder := NewGraphBuilder()
+	builder.SetLanguageOverrides([]parser.LanguageOverride{{Path: "hooks/*.hook", Language: "python"}})
+	graph, err := builder.AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	languages := make(map[string]string)
+	for path, file := range graph.Files {
+		rel, err := filepath.Rel(dir, path)
+		require.NoError(t, err)
+		languages[filepath.ToSlash(rel)] = file.Language
+	}
+	assert.Equal(t, map[string]string{
+		"scripts/deploy":        "shell",
+		"tools/migrate":         "python",
+		"hooks/pre-commit.hook": "python",
+		"src/main.go":           "go",
+	}, languages, "excluded directories, text and binary files are left out")
+
+	var names []string
+	for _, symbol := range graph.Symbols {
+		names = append(names, symbol.Name)
+	}
+	assert.Subset(t, names, []string{"deploy", "migrate", "check"})
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// architectureLayers lists the conventional layers from top to bottom with the
+// directory names that place a package in them
+var architectureLayers = []struct {
+	name string
+	dirs []string
+}{
+	{"ui", []string{"ui", "view", "views", "components", "pages", "screens", "widgets", "frontend", "web", "handler", "handlers", "controller", "controllers", "routes", "api", "http", "cli", "cmd"}},
+	{"application", []string{"service", "services", "usecase", "usecases", "application"}},
+	{"domain", []string{"domain", "model", "models", "entity", "entities"}},
+	{"data", []string{"data", "db", "database", "repo", "repository", "repositories", "store", "storage", "persistence", "dao", "infra", "infrastructure"}},
+}
+
+// ArchitectureLayer is a layer detected from directory names, with its packages
+type ArchitectureLayer struct {
+	Name     string   `json:"name"`
+	Packages []string `json:"packages"`
+}
+
+// LayerViolation is a package depending on a package of a layer above its own
+type LayerViolation struct {
+	From      string `json:"from"`
+	FromLayer string `json:"from_layer"`
+	To        string `json:"to"`
+	ToLayer   string `json:"to_layer"`
+}
+
+// LayerArchitecture holds the detected layers from top to bottom and the
+// dependencies going against that direction
+type LayerArchitecture struct {
+	Layers     []ArchitectureLayer `json:"layers"`
+	Violations []LayerViolation    `json:"violations"`
+}
+
+// InferLayers detects architectural layers without user-declared rules. A
+// package belongs to the layer named by its deepest directory that matches a
+// conventional name (handlers, services, models, store...). Layers start in
+// the conventional ui → application → domain → data order, and two adjacent
+// layers swap when the project's dependencies mostly go the other way, so a
+// data layer implementing domain interfaces ends up above the domain.
+// Dependencies going up the resulting order are violations.
+func InferLayers(graph *types.CodeGraph) LayerArchitecture {
+	coupling := AnalyzeCoupling(graph)
+	layerOf := make(map[string]string)
+	members := make(map[string][]string)
+	for _, pkg := range coupling {
+		if layer := packageLayer(pkg.Package); layer != "" {
+			layerOf[pkg.Package] = layer
+			members[layer] = append(members[layer], pkg.Package)
+		}
+	}
+
+	// Package dependencies between layers
+	crossings := make(map[string]map[string]int)
+	for _, pkg := range coupling {
+		from := layerOf[pkg.Package]
+		for _, dependency := range pkg.Dependencies {
+			if to := layerOf[dependency]; from != "" && to != "" && from != to {
+				if crossings[from] == nil {
+					crossings[from] = make(map[string]int)
+				}
+				crossings[from][to]++
+			}
+		}
+	}
+
+	var order []string
+	for _, layer := range architectureLayers {
+		if len(members[layer.name]) > 0 {
+			order = append(order, layer.name)
+		}
+	}
+	for pass := 0; pass < len(order); pass++ {
+		swapped := false
+		for i := 0; i+1 < len(order); i++ {
+			upper, lower := order[i], order[i+1]
+			if crossings[lower][upper] > crossings[upper][lower] {
+				order[i], order[i+1] = lower, upper
+				swapped = true
+			}
+		}
+		if !swapped {
+			break
+		}
+	}
+
+	var architecture LayerArchitecture
+	position := make(map[string]int)
+	for i, layer := range order {
+		position[layer] = i
+		architecture.Layers = append(architecture.Layers, ArchitectureLayer{Name: layer, Packages: members[layer]})
+	}
+	for _, pkg := range coupling {
+		from, ok := layerOf[pkg.Package]
+		if !ok {
+			continue
+		}
+		for _, dependency := range pkg.Dependencies {
+			if to, ok := layerOf[dependency]; ok && position[to] < position[from] {
+				architecture.Violations = append(architecture.Violations, LayerViolation{
+					From: pkg.Package, FromLayer: from, To: dependency, ToLayer: to,
+				})
+			}
+		}
+	}
+	return architecture
+}
+
+// packageLayer returns the layer named by the deepest matching directory of
+// a package, or "" when no directory has a conventional layer name
+func packageLayer(pkg string) string {
+	segments := strings.Split(strings.ToLower(pkg), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		for _, layer := range architectureLayers {
+			if slices.Contains(layer.dirs, segments[i]) {
+				return layer.name
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferLayers(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                      "module example.com/shop\n\ngo 1.22\n",
+		"internal/handlers/orders.go": "package handlers\n\nimport \"example.com/shop/internal/services\"\n\nfunc List() { services.Orders() }\n",
+		"internal/services/orders.go": "package services\n\nimport (\n\t\"example.com/shop/internal/models\"\n\t\"example.com/shop/internal/store\"\n)\n\nfunc Orders() []models.Order { return store.All() }\n",
+		"internal/store/orders.go":    "package store\n\nimport \"example.com/shop/internal/models\"\n\nfunc All() []models.Order { return nil }\n",
+		"internal/models/order.go":    "package models\n\nimport \"example.com/shop/internal/handlers\"\n\ntype Order struct{}\n\nvar _ = handlers.List\n",
+		"internal/version/version.go": "package version\n\nconst Version = \"1.0\"\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	architecture := InferLayers(graph)
+	assert.Equal(t, []ArchitectureLayer{
+		{Name: "ui", Packages: []string{"handlers"}},
+		{Name: "application", Packages: []string{"services"}},
+		{Name: "data", Packages: []string{"store"}},
+		{Name: "domain", Packages: []string{"models"}},
+	}, architecture.Layers, "the store depends on the models, so data sits above domain")
+	assert.Equal(t, []LayerViolation{
+		{From: "models", FromLayer: "domain", To: "handlers", ToLayer: "ui"},
+	}, architecture.Violations)
+
+	markdown := NewMarkdownGenerator(graph).GenerateContextMap()
+	assert.Contains(t, markdown, "### 🏛️ Layers (auto-detected)")
+	assert.Contains(t, markdown, "ui           handlers\n  ↓\napplication  services\n  ↓\ndata         store\n")
+	assert.Contains(t, markdown, "- `models` (domain) → `handlers` (ui)")
+}
+
+func TestPackageLayer(t *testing.T) {
+	assert.Equal(t, "ui", packageLayer("src/components/cart"))
+	assert.Equal(t, "data", packageLayer("internal/api/store"))
+	assert.Equal(t, "domain", packageLayer("app/Models"))
+	assert.Equal(t, "", packageLayer("internal/version"))
+	assert.Equal(t, "", packageLayer("."))
+}
@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"io"
+	"unicode/utf16"
+)
+
+// LSIFVersion is the LSIF format version written by WriteLSIF
+const LSIFVersion = "0.5.0"
+
+// lsifPosition is a zero-based line and UTF-16 character offset
+type lsifPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// lsifWriter writes LSIF vertices and edges as JSON lines, numbering them
+type lsifWriter struct {
+	encoder *json.Encoder
+	id      int
+	err     error
+}
+
+func (l *lsifWriter) vertex(label string, fields map[string]interface{}) int {
+	return l.emit("vertex", label, fields)
+}
+
+func (l *lsifWriter) edge(label string, fields map[string]interface{}) int {
+	return l.emit("edge", label, fields)
+}
+
+func (l *lsifWriter) emit(kind, label string, fields map[string]interface{}) int {
+	l.id++
+	element := map[string]interface{}{"id": l.id, "type": kind, "label": label}
+	for key, value := range fields {
+		element[key] = value
+	}
+	if l.err == nil {
+		l.err = l.encoder.Encode(element)
+	}
+	return l.id
+}
+
+// WriteLSIF writes the index as LSIF JSON lines: a definition result, hover
+// result and moniker per symbol, and implementation results for the types
+// that others extend or implement
+func (e *IndexExporter) WriteLSIF(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	l := &lsifWriter{encoder: encoder}
+	root := e.projectRoot()
+	documents := e.documents()
+
+	languages := make(map[string]int)
+	for _, document := range documents {
+		languages[document.language]++
+	}
+	primary := ""
+	for language, count := range languages {
+		if count > languages[primary] || count == languages[primary] && language < primary {
+			primary = language
+		}
+	}
+
+	l.vertex("metaData", map[string]interface{}{
+		"version":          LSIFVersion,
+		"projectRoot":      root,
+		"positionEncoding": "utf-16",
+		"toolInfo":         map[string]string{"name": IndexScheme, "version": e.version},
+	})
+	project := l.vertex("project", map[string]interface{}{"kind": primary})
+
+	type located struct{ document, rangeID int }
+	ranges := make(map[string][]located) // Definition ranges by symbol
+	resultSets := make(map[string]int)
+	var documentIDs []int
+	for _, document := range documents {
+		documentID := l.vertex("document", map[string]interface{}{"uri": root + document.path, "languageId": document.language})
+		documentIDs = append(documentIDs, documentID)
+
+		var contained []int
+		var symbols []string // In order of their first definition
+		for _, definition := range document.definitions {
+			tag := definition.tag
+			start := lsifPosition{Line: tag.Line - 1, Character: tag.column16}
+			end := lsifPosition{Line: start.Line, Character: start.Character + len(utf16.Encode([]rune(tag.Name)))}
+			rangeID := l.vertex("range", map[string]interface{}{"start": start, "end": end})
+			contained = append(contained, rangeID)
+			ranges[definition.symbol] = append(ranges[definition.symbol], located{documentID, rangeID})
+
+			// A declaration and its definition share a result set
+			if resultSet, ok := resultSets[definition.symbol]; ok {
+				l.edge("next", map[string]interface{}{"outV": rangeID, "inV": resultSet})
+				continue
+			}
+			resultSet := l.vertex("resultSet", nil)
+			resultSets[definition.symbol] = resultSet
+			symbols = append(symbols, definition.symbol)
+			l.edge("next", map[string]interface{}{"outV": rangeID, "inV": resultSet})
+
+			var contents []interface{}
+			if definition.declaration != "" {
+				contents = append(contents, map[string]string{"language": tag.Language, "value": definition.declaration})
+			}
+			if definition.documentation != "" {
+				contents = append(contents, definition.documentation)
+			}
+			if len(contents) > 0 {
+				hover := l.vertex("hoverResult", map[string]interface{}{"result": map[string]interface{}{"contents": contents}})
+				l.edge("textDocument/hover", map[string]interface{}{"outV": resultSet, "inV": hover})
+			}
+
+			kind := "export"
+			if tag.Access == "private" {
+				kind = "local"
+			}
+			moniker := l.vertex("moniker", map[string]interface{}{"scheme": IndexScheme, "identifier": definition.symbol, "kind": kind})
+			l.edge("moniker", map[string]interface{}{"outV": resultSet, "inV": moniker})
+		}
+
+		// Symbols name their file, so all their definitions are in this document
+		for _, symbol := range symbols {
+			items := make([]int, 0, len(ranges[symbol]))
+			for _, r := range ranges[symbol] {
+				items = append(items, r.rangeID)
+			}
+			result := l.vertex("definitionResult", nil)
+			l.edge("textDocument/definition", map[string]interface{}{"outV": resultSets[symbol], "inV": result})
+			l.edge("item", map[string]interface{}{"outV": result, "inVs": items, "document": documentID})
+		}
+		if len(contained) > 0 {
+			l.edge("contains", map[string]interface{}{"outV": documentID, "inVs": contained})
+		}
+	}
+
+	// Supertypes list the definitions of the types extending or implementing them
+	implementations := make(map[string][]located)
+	var supertypes []string
+	for _, document := range documents {
+		for _, definition := range document.definitions {
+			for _, supertype := range definition.implements {
+				if _, ok := resultSets[supertype]; !ok {
+					continue
+				}
+				if _, ok := implementations[supertype]; !ok {
+					supertypes = append(supertypes, supertype)
+				}
+				implementations[supertype] = append(implementations[supertype], ranges[definition.symbol]...)
+			}
+		}
+	}
+	for _, supertype := range supertypes {
+		result := l.vertex("implementationResult", nil)
+		l.edge("textDocument/implementation", map[string]interface{}{"outV": resultSets[supertype], "inV": result})
+		byDocument := make(map[int][]int)
+		var order []int
+		for _, r := range implementations[supertype] {
+			if _, ok := byDocument[r.document]; !ok {
+				order = append(order, r.document)
+			}
+			byDocument[r.document] = append(byDocument[r.document], r.rangeID)
+		}
+		for _, documentID := range order {
+			l.edge("item", map[string]interface{}{"outV": result, "inVs": byDocument[documentID], "document": documentID})
+		}
+	}
+
+	if len(documentIDs) > 0 {
+		l.edge("contains", map[string]interface{}{"outV": project, "inVs": documentIDs})
+	}
+	return l.err
+}
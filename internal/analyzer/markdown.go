@@ -2,69 +2,117 @@ package analyzer
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/rank"
 	"github.com/nuthan-ms/codecontext/pkg/types"
 )
 
 // MarkdownGenerator generates rich markdown content from analyzed code graphs
 type MarkdownGenerator struct {
-	graph *types.CodeGraph
+	graph      *types.CodeGraph
+	topN       int
+	publicOnly bool
 }
 
 // NewMarkdownGenerator creates a new markdown generator
 func NewMarkdownGenerator(graph *types.CodeGraph) *MarkdownGenerator {
-	return &MarkdownGenerator{graph: graph}
+	return &MarkdownGenerator{graph: graph, topN: rank.DefaultN}
+}
+
+// SetTopN sets the length of ranked summary lists such as the most imported
+// modules. n <= 0 keeps the default.
+func (mg *MarkdownGenerator) SetTopN(n int) {
+	if n > 0 {
+		mg.topN = n
+	}
+}
+
+// SetPublicOnly limits the symbol analysis to the public API, leaving out
+// private, protected and package-private symbols
+func (mg *MarkdownGenerator) SetPublicOnly(publicOnly bool) {
+	mg.publicOnly = publicOnly
 }
 
 // GenerateContextMap generates a comprehensive context map in markdown format
 func (mg *MarkdownGenerator) GenerateContextMap() string {
 	var sb strings.Builder
+	// Writing to a strings.Builder cannot fail
+	_ = mg.WriteContextMap(&sb)
+	return sb.String()
+}
 
-	// Header
-	sb.WriteString(mg.generateHeader())
-	sb.WriteString("\n\n")
-
-	// Overview
-	sb.WriteString(mg.generateOverview())
-	sb.WriteString("\n\n")
-
-	// File Analysis
-	sb.WriteString(mg.generateFileAnalysis())
-	sb.WriteString("\n\n")
-
-	// Symbol Analysis
-	sb.WriteString(mg.generateSymbolAnalysis())
-	sb.WriteString("\n\n")
-
-	// Language Statistics
-	sb.WriteString(mg.generateLanguageStats())
-	sb.WriteString("\n\n")
+// WriteContextMap writes the context map to w section by section, so large
+// repositories never hold the whole document in memory. Sections that grow
+// with the repository are handed to w in chunks of whole lines.
+func (mg *MarkdownGenerator) WriteContextMap(w io.Writer) error {
+	cw := &chunkWriter{w: w}
+	sections := []func(cw *chunkWriter){
+		text(mg.generateHeader),
+		text(mg.generateOverview),
+		mg.writeFileAnalysis,
+		text(mg.generateSymbolAnalysis),
+		text(mg.generateLanguageStats),
+		text(mg.generateImportAnalysis),
+		mg.writeRelationshipAnalysis,
+		mg.writeReliability,
+		mg.writeGlobalState,
+		text(mg.generateSemanticNeighborhoods),
+		mg.writeProjectStructure,
+	}
+	for _, section := range sections {
+		section(cw)
+		cw.WriteString("\n\n")
+		cw.flush()
+		if cw.err != nil {
+			return cw.err
+		}
+	}
+	cw.WriteString(mg.generateFooter())
+	cw.flush()
+	return cw.err
+}
 
-	// Import Analysis
-	sb.WriteString(mg.generateImportAnalysis())
-	sb.WriteString("\n\n")
+// markdownChunkSize is how much of a section is buffered before it is written
+const markdownChunkSize = 64 * 1024
 
-	// Relationship Analysis
-	sb.WriteString(mg.generateRelationshipAnalysis())
-	sb.WriteString("\n\n")
+// chunkWriter buffers markdown and passes it to w in chunks. The first write
+// error is kept and later output is dropped.
+type chunkWriter struct {
+	w   io.Writer
+	buf strings.Builder
+	err error
+}
 
-	// Semantic Neighborhoods Analysis
-	sb.WriteString(mg.generateSemanticNeighborhoods())
-	sb.WriteString("\n\n")
+func (cw *chunkWriter) WriteString(s string) {
+	cw.buf.WriteString(s)
+}
 
-	// Project Structure
-	sb.WriteString(mg.generateProjectStructure())
-	sb.WriteString("\n\n")
+// endLine flushes the buffer once it exceeds a chunk. It is only called after
+// complete lines, so every chunk ends at a line boundary.
+func (cw *chunkWriter) endLine() {
+	if cw.buf.Len() >= markdownChunkSize {
+		cw.flush()
+	}
+}
 
-	// Footer
-	sb.WriteString(mg.generateFooter())
+func (cw *chunkWriter) flush() {
+	if cw.err == nil && cw.buf.Len() > 0 {
+		_, cw.err = io.WriteString(cw.w, cw.buf.String())
+	}
+	cw.buf.Reset()
+}
 
-	return sb.String()
+// text adapts a section that is rendered as one string
+func text(generate func() string) func(cw *chunkWriter) {
+	return func(cw *chunkWriter) {
+		cw.WriteString(generate())
+	}
 }
 
 // generateHeader creates the document header
@@ -100,18 +148,134 @@ This context map was generated using **real Tree-sitter parsing** and provides c
 		mg.graph.Metadata.TotalFiles,
 		mg.graph.Metadata.TotalSymbols,
 		len(mg.graph.Metadata.Languages),
-		len(mg.graph.Edges))
+		len(mg.graph.Edges)) + mg.generateEntryPointOverview() + mg.generateCouplingOverview() + mg.generateLayerOverview()
 }
 
-// generateFileAnalysis creates the file analysis section
-func (mg *MarkdownGenerator) generateFileAnalysis() string {
+// generateEntryPointOverview lists where programs, commands, serverless
+// handlers and servers start, with the packages each one wires up
+func (mg *MarkdownGenerator) generateEntryPointOverview() string {
+	entryPoints := FindEntryPoints(mg.graph)
+	if len(entryPoints) == 0 {
+		return ""
+	}
+
 	var sb strings.Builder
-	sb.WriteString("## 📁 File Analysis\n\n")
+	sb.WriteString("\n\n### 🚪 Entry Points\n\n")
+	sb.WriteString("| Entry Point | Kind | Function | Wires Up |\n")
+	sb.WriteString("|-------------|------|----------|----------|\n")
+	for i, entryPoint := range entryPoints {
+		if i == mg.topN {
+			sb.WriteString(fmt.Sprintf("\n*... and %d more entry points*\n", len(entryPoints)-i))
+			break
+		}
+		function := "-"
+		if entryPoint.Function != "" {
+			function = "`" + entryPoint.Function + "`"
+		}
+		wiresUp := "-"
+		if packages := entryPoint.WiresUp; len(packages) > 0 {
+			more := ""
+			if len(packages) > maxWiredPackages {
+				more = fmt.Sprintf(" (+%d more)", len(packages)-maxWiredPackages)
+				packages = packages[:maxWiredPackages]
+			}
+			wiresUp = "`" + strings.Join(packages, "`, `") + "`" + more
+		}
+		location := filepath.ToSlash(filepath.Join(entryPoint.Package, filepath.Base(entryPoint.File)))
+		sb.WriteString(fmt.Sprintf("| `%s:%d` | %s | %s | %s |\n", location, entryPoint.Line, entryPoint.Kind, function, wiresUp))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
 
-	if len(mg.graph.Files) == 0 {
-		sb.WriteString("*No files analyzed.*\n")
+// maxWiredPackages caps the packages listed per entry point
+const maxWiredPackages = 5
+
+// generateCouplingOverview summarizes package coupling: the packages most
+// depended on and how unstable they are. Instability near 1 in a package
+// many others depend on marks a module that is risky to change.
+func (mg *MarkdownGenerator) generateCouplingOverview() string {
+	var coupled []PackageCoupling
+	for _, pkg := range AnalyzeCoupling(mg.graph) {
+		if pkg.Afferent+pkg.Efferent > 0 {
+			coupled = append(coupled, pkg)
+		}
+	}
+	if len(coupled) == 0 {
+		return ""
+	}
+	sort.SliceStable(coupled, func(i, j int) bool {
+		if coupled[i].Afferent != coupled[j].Afferent {
+			return coupled[i].Afferent > coupled[j].Afferent
+		}
+		return coupled[i].Efferent > coupled[j].Efferent
+	})
+
+	var sb strings.Builder
+	sb.WriteString("\n\n### 🧭 Package Coupling\n\n")
+	sb.WriteString("| Package | Fan-in (Ca) | Fan-out (Ce) | Instability |\n")
+	sb.WriteString("|---------|-------------|--------------|-------------|\n")
+	for i, pkg := range coupled {
+		if i == mg.topN {
+			sb.WriteString(fmt.Sprintf("\n*... and %d more packages*\n", len(coupled)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %d | %d | %.2f |\n", pkg.Package, pkg.Afferent, pkg.Efferent, pkg.Instability))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// generateLayerOverview draws the layers inferred from directory names and
+// dependency direction, top to bottom, and lists dependencies going upward.
+// Shown once at least two layers are detected.
+func (mg *MarkdownGenerator) generateLayerOverview() string {
+	architecture := InferLayers(mg.graph)
+	if len(architecture.Layers) < 2 {
+		return ""
+	}
+
+	width := 0
+	for _, layer := range architecture.Layers {
+		width = max(width, len(layer.Name))
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n### 🏛️ Layers (auto-detected)\n\n```\n")
+	for i, layer := range architecture.Layers {
+		if i > 0 {
+			sb.WriteString("  ↓\n")
+		}
+		packages := layer.Packages
+		more := ""
+		if len(packages) > mg.topN {
+			more = fmt.Sprintf(" (+%d more)", len(packages)-mg.topN)
+			packages = packages[:mg.topN]
+		}
+		sb.WriteString(fmt.Sprintf("%-*s  %s%s\n", width, layer.Name, strings.Join(packages, ", "), more))
+	}
+	sb.WriteString("```\n")
+
+	if len(architecture.Violations) == 0 {
+		sb.WriteString("\n*No dependencies against the layer order.*")
 		return sb.String()
 	}
+	sb.WriteString(fmt.Sprintf("\n**Layer violations:** %d\n\n", len(architecture.Violations)))
+	for i, violation := range architecture.Violations {
+		if i == mg.topN {
+			sb.WriteString(fmt.Sprintf("\n*... and %d more violations*\n", len(architecture.Violations)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("- `%s` (%s) → `%s` (%s)\n", violation.From, violation.FromLayer, violation.To, violation.ToLayer))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// writeFileAnalysis writes the file analysis section
+func (mg *MarkdownGenerator) writeFileAnalysis(cw *chunkWriter) {
+	cw.WriteString("## 📁 File Analysis\n\n")
+
+	if len(mg.graph.Files) == 0 {
+		cw.WriteString("*No files analyzed.*\n")
+		return
+	}
 
 	// Sort files by path for consistent output
 	files := make([]*types.FileNode, 0, len(mg.graph.Files))
@@ -122,8 +286,8 @@ func (mg *MarkdownGenerator) generateFileAnalysis() string {
 		return files[i].Path < files[j].Path
 	})
 
-	sb.WriteString("| File | Language | Lines | Symbols | Imports | Type |\n")
-	sb.WriteString("|------|----------|-------|---------|---------|------|\n")
+	cw.WriteString("| File | Language | Lines | Symbols | Imports | Type |\n")
+	cw.WriteString("|------|----------|-------|---------|---------|------|\n")
 
 	for _, file := range files {
 		fileType := "source"
@@ -133,16 +297,33 @@ func (mg *MarkdownGenerator) generateFileAnalysis() string {
 			fileType = "generated"
 		}
 
-		sb.WriteString(fmt.Sprintf("| `%s` | %s | %d | %d | %d | %s |\n",
+		cw.WriteString(fmt.Sprintf("| `%s` | %s | %d | %d | %d | %s |\n",
 			file.Path,
 			file.Language,
 			file.Lines,
 			file.SymbolCount,
 			file.ImportCount,
 			fileType))
+		cw.endLine()
 	}
+	mg.writeModuleSummaries(cw, files)
+}
 
-	return sb.String()
+// writeModuleSummaries lists the natural language summaries of files, which
+// only exist when a summarizer is configured
+func (mg *MarkdownGenerator) writeModuleSummaries(cw *chunkWriter, files []*types.FileNode) {
+	header := false
+	for _, file := range files {
+		if file.Summary == "" {
+			continue
+		}
+		if !header {
+			cw.WriteString("\n### 📝 Module Summaries\n\n")
+			header = true
+		}
+		cw.WriteString(fmt.Sprintf("- **`%s`**: %s\n", file.Path, file.Summary))
+		cw.endLine()
+	}
 }
 
 // generateSymbolAnalysis creates the symbol analysis section
@@ -150,14 +331,24 @@ func (mg *MarkdownGenerator) generateSymbolAnalysis() string {
 	var sb strings.Builder
 	sb.WriteString("## 🔍 Symbol Analysis\n\n")
 
-	if len(mg.graph.Symbols) == 0 {
+	symbols := make([]*types.Symbol, 0, len(mg.graph.Symbols))
+	for _, symbol := range mg.graph.Symbols {
+		if !mg.publicOnly || symbol.IsPublic() {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if mg.publicOnly {
+		sb.WriteString("*Public API only.*\n\n")
+	}
+
+	if len(symbols) == 0 {
 		sb.WriteString("*No symbols extracted.*\n")
 		return sb.String()
 	}
 
 	// Count symbols by type
 	symbolCounts := make(map[types.SymbolType]int)
-	for _, symbol := range mg.graph.Symbols {
+	for _, symbol := range symbols {
 		symbolCounts[symbol.Type]++
 	}
 
@@ -169,16 +360,12 @@ func (mg *MarkdownGenerator) generateSymbolAnalysis() string {
 	}
 
 	// Show detailed symbol list for smaller projects
-	if len(mg.graph.Symbols) <= 50 {
+	if len(symbols) <= 50 {
 		sb.WriteString("\n### Symbol Details\n\n")
 		sb.WriteString("| Symbol | Type | File | Line | Signature |\n")
 		sb.WriteString("|--------|------|------|------|----------|\n")
 
 		// Sort symbols by file and line
-		symbols := make([]*types.Symbol, 0, len(mg.graph.Symbols))
-		for _, symbol := range mg.graph.Symbols {
-			symbols = append(symbols, symbol)
-		}
 		sort.Slice(symbols, func(i, j int) bool {
 			if symbols[i].FullyQualifiedName != symbols[j].FullyQualifiedName {
 				return symbols[i].FullyQualifiedName < symbols[j].FullyQualifiedName
@@ -195,7 +382,7 @@ func (mg *MarkdownGenerator) generateSymbolAnalysis() string {
 			sb.WriteString(fmt.Sprintf("| `%s` | %s | `%s` | %d | `%s` |\n",
 				symbol.Name,
 				symbol.Type,
-				filepath.Base(symbol.FullyQualifiedName),
+				filepath.Base(types.FilePathFromQualifiedName(symbol.FullyQualifiedName)),
 				symbol.Location.StartLine,
 				signature))
 		}
@@ -250,10 +437,14 @@ func (mg *MarkdownGenerator) generateImportAnalysis() string {
 	importCounts := make(map[string]int)
 	internalImports := 0
 	externalImports := 0
+	lazyImports := 0
 
 	for _, file := range mg.graph.Files {
 		for _, imp := range file.Imports {
 			importCounts[imp.Path]++
+			if imp.Lazy {
+				lazyImports++
+			}
 
 			if strings.HasPrefix(imp.Path, "./") || strings.HasPrefix(imp.Path, "../") {
 				internalImports++
@@ -266,108 +457,109 @@ func (mg *MarkdownGenerator) generateImportAnalysis() string {
 	sb.WriteString(fmt.Sprintf("- **Total Import Statements**: %d\n", internalImports+externalImports))
 	sb.WriteString(fmt.Sprintf("- **Internal Imports**: %d (relative paths)\n", internalImports))
 	sb.WriteString(fmt.Sprintf("- **External Imports**: %d (packages/modules)\n", externalImports))
+	if lazyImports > 0 {
+		sb.WriteString(fmt.Sprintf("- **Lazy Imports**: %d (loaded at runtime)\n", lazyImports))
+	}
 	sb.WriteString(fmt.Sprintf("- **Unique Modules**: %d\n\n", len(importCounts)))
 
 	if len(importCounts) > 0 {
-		// Show most imported modules
-		type importStat struct {
-			path  string
-			count int
-		}
-
-		imports := make([]importStat, 0, len(importCounts))
-		for path, count := range importCounts {
-			imports = append(imports, importStat{path, count})
-		}
-		sort.Slice(imports, func(i, j int) bool {
-			return imports[i].count > imports[j].count
-		})
+		// Show the most imported modules, ties ordered by path
+		ranking := rank.Top(importCounts, mg.topN)
 
 		sb.WriteString("### Most Imported Modules\n\n")
 		sb.WriteString("| Module | Import Count |\n")
 		sb.WriteString("|--------|-------------|\n")
 
-		// Show top 10 or all if fewer
-		limit := 10
-		if len(imports) < limit {
-			limit = len(imports)
+		for _, imp := range ranking.Entries {
+			sb.WriteString(fmt.Sprintf("| `%s` | %d |\n", imp.Key, imp.Count))
 		}
-
-		for i := 0; i < limit; i++ {
-			imp := imports[i]
-			sb.WriteString(fmt.Sprintf("| `%s` | %d |\n", imp.path, imp.count))
+		if note := ranking.Note("imports"); note != "" {
+			sb.WriteString(fmt.Sprintf("\n*%s*\n", note))
 		}
 	}
 
 	return sb.String()
 }
 
-// generateRelationshipAnalysis creates the relationship analysis section
-func (mg *MarkdownGenerator) generateRelationshipAnalysis() string {
-	var sb strings.Builder
-	sb.WriteString("## 🔗 Relationship Analysis\n\n")
+// writeRelationshipAnalysis writes the relationship analysis section
+func (mg *MarkdownGenerator) writeRelationshipAnalysis(cw *chunkWriter) {
+	cw.WriteString("## 🔗 Relationship Analysis\n\n")
 
 	// Check if relationship metrics are available
 	if mg.graph.Metadata.Configuration == nil {
-		sb.WriteString("*Relationship analysis not available.*\n")
-		return sb.String()
+		cw.WriteString("*Relationship analysis not available.*\n")
+		return
 	}
 
 	metricsInterface, exists := mg.graph.Metadata.Configuration["relationship_metrics"]
 	if !exists {
-		sb.WriteString("*Relationship metrics not found.*\n")
-		return sb.String()
+		cw.WriteString("*Relationship metrics not found.*\n")
+		return
 	}
 
 	metrics, ok := metricsInterface.(*RelationshipMetrics)
 	if !ok {
-		sb.WriteString("*Invalid relationship metrics format.*\n")
-		return sb.String()
+		cw.WriteString("*Invalid relationship metrics format.*\n")
+		return
 	}
 
 	// Summary
-	sb.WriteString("### 📊 Relationship Summary\n\n")
-	sb.WriteString(fmt.Sprintf("- **Total Relationships**: %d\n", metrics.TotalRelationships))
-	sb.WriteString(fmt.Sprintf("- **File-to-File**: %d\n", metrics.FileToFile))
-	sb.WriteString(fmt.Sprintf("- **Symbol-to-Symbol**: %d\n", metrics.SymbolToSymbol))
-	sb.WriteString(fmt.Sprintf("- **Cross-File References**: %d\n", metrics.CrossFileRefs))
-	sb.WriteString("\n")
+	cw.WriteString("### 📊 Relationship Summary\n\n")
+	cw.WriteString(fmt.Sprintf("- **Total Relationships**: %d\n", metrics.TotalRelationships))
+	cw.WriteString(fmt.Sprintf("- **File-to-File**: %d\n", metrics.FileToFile))
+	cw.WriteString(fmt.Sprintf("- **Symbol-to-Symbol**: %d\n", metrics.SymbolToSymbol))
+	cw.WriteString(fmt.Sprintf("- **Cross-File References**: %d\n", metrics.CrossFileRefs))
+	cw.WriteString("\n")
 
 	// Relationships by type
 	if len(metrics.ByType) > 0 {
-		sb.WriteString("### 🔍 Relationship Types\n\n")
-		sb.WriteString("| Type | Count | Description |\n")
-		sb.WriteString("|------|-------|-------------|\n")
+		cw.WriteString("### 🔍 Relationship Types\n\n")
+		cw.WriteString("| Type | Count | Description |\n")
+		cw.WriteString("|------|-------|-------------|\n")
 
 		for relType, count := range metrics.ByType {
 			description := mg.getRelationshipDescription(relType)
-			sb.WriteString(fmt.Sprintf("| %s | %d | %s |\n", relType, count, description))
+			cw.WriteString(fmt.Sprintf("| %s | %d | %s |\n", relType, count, description))
 		}
-		sb.WriteString("\n")
+		cw.WriteString("\n")
 	}
 
 	// Circular dependencies
 	if len(metrics.CircularDeps) > 0 {
-		sb.WriteString("### ⚠️ Circular Dependencies\n\n")
-		sb.WriteString(fmt.Sprintf("Found %d circular dependencies:\n\n", len(metrics.CircularDeps)))
+		cw.WriteString("### ⚠️ Circular Dependencies\n\n")
+		cw.WriteString(fmt.Sprintf("Found %d circular dependencies:\n\n", len(metrics.CircularDeps)))
 
 		for i, dep := range metrics.CircularDeps {
-			sb.WriteString(fmt.Sprintf("**Circular Dependency %d** (%s):\n", i+1, dep.Type))
-			sb.WriteString("```\n")
-			sb.WriteString(strings.Join(dep.Path, " → "))
-			sb.WriteString("\n```\n\n")
+			cw.WriteString(fmt.Sprintf("**Circular Dependency %d** (%s):\n", i+1, dep.Type))
+			cw.WriteString("```\n")
+			cw.WriteString(strings.Join(dep.Path, " → "))
+			cw.WriteString("\n```\n\n")
+			cw.endLine()
 		}
 	} else {
-		sb.WriteString("### ✅ No Circular Dependencies\n\n")
-		sb.WriteString("No circular dependencies detected in the codebase.\n\n")
+		cw.WriteString("### ✅ No Circular Dependencies\n\n")
+		cw.WriteString("No circular dependencies detected in the codebase.\n\n")
 	}
 
 	// Hotspot files
 	if len(metrics.HotspotFiles) > 0 {
-		sb.WriteString("### 🔥 Hotspot Files\n\n")
-		sb.WriteString("Files with high dependency activity:\n\n")
-		sb.WriteString("| File | Imports | References | Score |\n")
-		sb.WriteString("|------|---------|------------|-------|\n")
+		cw.WriteString("### 🔥 Hotspot Files\n\n")
+		withCoverage := false
+		for _, hotspot := range metrics.HotspotFiles {
+			if hotspot.Coverage != nil {
+				withCoverage = true
+				break
+			}
+		}
+		if withCoverage {
+			cw.WriteString("Files with high dependency activity, weighted up by untested lines:\n\n")
+			cw.WriteString("| File | Imports | References | Coverage | Score |\n")
+			cw.WriteString("|------|---------|------------|----------|-------|\n")
+		} else {
+			cw.WriteString("Files with high dependency activity:\n\n")
+			cw.WriteString("| File | Imports | References | Score |\n")
+			cw.WriteString("|------|---------|------------|-------|\n")
+		}
 
 		// Sort by score (descending)
 		hotspots := make([]FileHotspot, len(metrics.HotspotFiles))
@@ -378,25 +570,130 @@ func (mg *MarkdownGenerator) generateRelationshipAnalysis() string {
 
 		for _, hotspot := range hotspots {
 			fileName := filepath.Base(hotspot.FilePath)
-			sb.WriteString(fmt.Sprintf("| `%s` | %d | %d | %.1f |\n",
-				fileName, hotspot.ImportCount, hotspot.ReferenceCount, hotspot.Score))
+			if withCoverage {
+				covered := "-"
+				if hotspot.Coverage != nil {
+					covered = fmt.Sprintf("%.0f%%", hotspot.Coverage.LinePercent())
+				}
+				cw.WriteString(fmt.Sprintf("| `%s` | %d | %d | %s | %.1f |\n",
+					fileName, hotspot.ImportCount, hotspot.ReferenceCount, covered, hotspot.Score))
+			} else {
+				cw.WriteString(fmt.Sprintf("| `%s` | %d | %d | %.1f |\n",
+					fileName, hotspot.ImportCount, hotspot.ReferenceCount, hotspot.Score))
+			}
+			cw.endLine()
 		}
-		sb.WriteString("\n")
+		cw.WriteString("\n")
 	}
 
 	// Isolated files
 	if len(metrics.IsolatedFiles) > 0 {
-		sb.WriteString("### 🏝️ Isolated Files\n\n")
-		sb.WriteString("Files with no import/export relationships:\n\n")
+		cw.WriteString("### 🏝️ Isolated Files\n\n")
+		cw.WriteString("Files with no import/export relationships:\n\n")
 
 		for _, filePath := range metrics.IsolatedFiles {
 			fileName := filepath.Base(filePath)
-			sb.WriteString(fmt.Sprintf("- `%s`\n", fileName))
+			cw.WriteString(fmt.Sprintf("- `%s`\n", fileName))
+			cw.endLine()
 		}
-		sb.WriteString("\n")
+		cw.WriteString("\n")
+	}
+}
+
+// writeReliability writes the panics and exceptions that escape to callers
+// without being recovered
+func (mg *MarkdownGenerator) writeReliability(cw *chunkWriter) {
+	cw.WriteString("## 🛡️ Reliability\n\n")
+	mg.writePanicEscapes(cw)
+	mg.writeWeaklyTested(cw)
+}
+
+// writePanicEscapes writes the panics and exceptions no caller recovers from
+func (mg *MarkdownGenerator) writePanicEscapes(cw *chunkWriter) {
+	escapes := AnalyzePanicEscapes(mg.graph)
+	if len(escapes) == 0 {
+		cw.WriteString("No panic, throw or raise escapes to callers without a recover, catch or except.\n\n")
+		return
 	}
 
-	return sb.String()
+	cw.WriteString("### ⚠️ Unrecovered Panics and Exceptions\n\n")
+	cw.WriteString("Functions that panic or throw where no caller recovers, matched by name through the call graph:\n\n")
+	cw.WriteString("| Function | Location | Statement | Unrecovered Callers |\n")
+	cw.WriteString("|----------|----------|-----------|---------------------|\n")
+	for i, escape := range escapes {
+		if i == mg.topN {
+			cw.WriteString(fmt.Sprintf("\n*... and %d more*\n", len(escapes)-i))
+			break
+		}
+		callers := "-"
+		if len(escape.Callers) > 0 {
+			callers = "`" + strings.Join(escape.Callers, "`, `") + "`"
+		}
+		statement := strings.ReplaceAll(escape.Statement, "|", "\\|")
+		if len(statement) > 60 {
+			statement = statement[:57] + "..."
+		}
+		cw.WriteString(fmt.Sprintf("| `%s` | `%s:%d` | `%s` | %s |\n", escape.Function, filepath.Base(escape.File), escape.Line, statement, callers))
+		cw.endLine()
+	}
+	cw.WriteString("\n")
+}
+
+// writeWeaklyTested writes the symbols whose tests let mutants survive, when
+// mutation testing results were attached to the graph
+func (mg *MarkdownGenerator) writeWeaklyTested(cw *chunkWriter) {
+	weak := FindWeaklyTested(mg.graph)
+	if len(weak) == 0 {
+		return
+	}
+	cw.WriteString("### 🧬 Weakly Tested Critical Code\n\n")
+	cw.WriteString("Symbols where mutation testing left mutants alive, weighted by the files depending on them:\n\n")
+	cw.WriteString("| Symbol | Location | Surviving Mutants | Mutation Score | Referenced By |\n")
+	cw.WriteString("|--------|----------|-------------------|----------------|---------------|\n")
+	for i, w := range weak {
+		if i == mg.topN {
+			cw.WriteString(fmt.Sprintf("\n*... and %d more*\n", len(weak)-i))
+			break
+		}
+		cw.WriteString(fmt.Sprintf("| `%s` | `%s:%d` | %d | %.0f%% | %d |\n", w.Symbol.Name, filepath.Base(w.File), w.Symbol.Location.StartLine, w.Survived, w.Score, w.References))
+		cw.endLine()
+	}
+	cw.WriteString("\n")
+}
+
+// writeGlobalState writes the package-level mutable variables, static fields
+// and singletons with the functions that modify them
+func (mg *MarkdownGenerator) writeGlobalState(cw *chunkWriter) {
+	cw.WriteString("## 🌐 Global Mutable State\n\n")
+
+	globals := AnalyzeGlobalState(mg.graph)
+	if len(globals) == 0 {
+		cw.WriteString("No package-level mutable variables, static fields or singletons found.\n")
+		return
+	}
+
+	mutated := 0
+	for _, global := range globals {
+		if len(global.Mutators) > 0 {
+			mutated++
+		}
+	}
+	cw.WriteString(fmt.Sprintf("Found %d globals, %d modified by functions. Shared state couples the code that modifies it even where no import links it:\n\n", len(globals), mutated))
+	cw.WriteString("| Name | Kind | Location | Modified By |\n")
+	cw.WriteString("|------|------|----------|-------------|\n")
+	for i, global := range globals {
+		if i == mg.topN {
+			cw.WriteString(fmt.Sprintf("\n*... and %d more*\n", len(globals)-i))
+			break
+		}
+		mutators := "-"
+		if len(global.Mutators) > 0 {
+			mutators = strings.Join(global.Mutators, ", ")
+		}
+		cw.WriteString(fmt.Sprintf("| `%s` | %s | `%s:%d` | %s |\n", global.Name, global.Kind, filepath.Base(global.File), global.Line, mutators))
+		cw.endLine()
+	}
+	cw.WriteString("\n")
 }
 
 // getRelationshipDescription returns a description for a relationship type
@@ -413,24 +710,55 @@ func (mg *MarkdownGenerator) getRelationshipDescription(relType RelationshipType
 	case RelationshipReferences:
 		return "Symbol references another symbol"
 	case RelationshipContains:
-		return "File contains symbols"
+		return "File contains symbols, or a Go struct embeds a type"
 	case RelationshipUses:
 		return "Symbol uses another symbol"
 	case RelationshipDepends:
 		return "Component depends on another component"
+	case RelationshipCallsService:
+		return "Client call reaches the HTTP/gRPC endpoint serving it"
+	case RelationshipBenchmarks:
+		return "Benchmark exercises a function"
+	case RelationshipImplementedIn:
+		return "C/C++ header is implemented in a source file"
+	case RelationshipDefinedIn:
+		return "C/C++ function declaration is defined elsewhere"
+	case RelationshipSpecializes:
+		return "C++ template specialization specializes its primary template"
+	case RelationshipInstantiates:
+		return "C++ explicit instantiation instantiates its primary template"
+	case RelationshipGeneratedFrom:
+		return "Generated Dart part is generated from an annotated class"
+	case RelationshipHasPart:
+		return "Dart library includes a part file"
+	case RelationshipDocuments:
+		return "Markdown document describes code"
+	case RelationshipPublishesTo:
+		return "Message producer publishes to a consumer of the same topic"
+	case RelationshipMixesIn:
+		return "Class applies a mixin"
+	case RelationshipInjects:
+		return "Dependency injection container injects a dependency into a class"
+	case RelationshipDispatches:
+		return "Code dispatches an action or event to a state store"
+	case RelationshipSelectsFrom:
+		return "Code reads state from a state store"
+	case RelationshipStories:
+		return "Storybook stories file documents a component"
+	case RelationshipRenders:
+		return "Code renders a view template"
 	default:
 		return "Unknown relationship type"
 	}
 }
 
-// generateProjectStructure creates the project structure section
-func (mg *MarkdownGenerator) generateProjectStructure() string {
-	var sb strings.Builder
-	sb.WriteString("## 📁 Project Structure\n\n")
+// writeProjectStructure writes the project structure section
+func (mg *MarkdownGenerator) writeProjectStructure(cw *chunkWriter) {
+	cw.WriteString("## 📁 Project Structure\n\n")
 
 	if len(mg.graph.Files) == 0 {
-		sb.WriteString("*No files to display.*\n")
-		return sb.String()
+		cw.WriteString("*No files to display.*\n")
+		return
 	}
 
 	// Build directory tree
@@ -450,7 +778,7 @@ func (mg *MarkdownGenerator) generateProjectStructure() string {
 	}
 	sort.Strings(sortedDirs)
 
-	sb.WriteString("```\n")
+	cw.WriteString("```\n")
 	for _, dir := range sortedDirs {
 		files := dirs[dir]
 		sort.Strings(files)
@@ -458,18 +786,17 @@ func (mg *MarkdownGenerator) generateProjectStructure() string {
 		if dir == "" {
 			// Root files
 			for _, file := range files {
-				sb.WriteString(fmt.Sprintf("%s\n", file))
+				cw.WriteString(fmt.Sprintf("%s\n", file))
 			}
 		} else {
-			sb.WriteString(fmt.Sprintf("%s/\n", dir))
+			cw.WriteString(fmt.Sprintf("%s/\n", dir))
 			for _, file := range files {
-				sb.WriteString(fmt.Sprintf("├── %s\n", file))
+				cw.WriteString(fmt.Sprintf("├── %s\n", file))
 			}
 		}
+		cw.endLine()
 	}
-	sb.WriteString("```\n")
-
-	return sb.String()
+	cw.WriteString("```\n")
 }
 
 // generateFooter creates the document footer
@@ -545,6 +872,12 @@ func (mg *MarkdownGenerator) generateSemanticNeighborhoods() string {
 	sb.WriteString(mg.generateSemanticOverview(semanticResult))
 	sb.WriteString("\n")
 
+	// Conventional commit types of the period
+	if semanticResult.CommitTypes.Conventional > 0 {
+		sb.WriteString(mg.generateCommitTypeBreakdown(semanticResult.CommitTypes))
+		sb.WriteString("\n")
+	}
+
 	// Basic semantic neighborhoods
 	if len(semanticResult.SemanticNeighborhoods) > 0 {
 		sb.WriteString(mg.generateBasicNeighborhoods(semanticResult.SemanticNeighborhoods))
@@ -590,6 +923,38 @@ func (mg *MarkdownGenerator) generateSemanticOverview(result *SemanticAnalysisRe
 	return sb.String()
 }
 
+// generateCommitTypeBreakdown creates the conventional commit type table
+func (mg *MarkdownGenerator) generateCommitTypeBreakdown(stats git.CommitTypeStats) string {
+	var sb strings.Builder
+
+	sb.WriteString("### 🏷️ Commit Types\n\n")
+	sb.WriteString(fmt.Sprintf("%d of %d commits follow [Conventional Commits](https://www.conventionalcommits.org)", stats.Conventional, stats.Total))
+	if stats.Breaking > 0 {
+		sb.WriteString(fmt.Sprintf(", %d marked breaking", stats.Breaking))
+	}
+	sb.WriteString(":\n\n")
+
+	sb.WriteString("| Type | Commits | Share |\n")
+	sb.WriteString("|------|---------|-------|\n")
+	for _, commitType := range stats.SortedTypes() {
+		count := stats.Types[commitType]
+		sb.WriteString(fmt.Sprintf("| `%s` | %d | %.0f%% |\n", commitType, count, float64(count)*100/float64(stats.Conventional)))
+	}
+
+	if len(stats.Scopes) > 0 {
+		scopes := git.CommitTypeStats{Types: stats.Scopes}.SortedTypes()
+		if len(scopes) > mg.topN {
+			scopes = scopes[:mg.topN]
+		}
+		for i, scope := range scopes {
+			scopes[i] = fmt.Sprintf("`%s` (%d)", scope, stats.Scopes[scope])
+		}
+		sb.WriteString(fmt.Sprintf("\n**Most active scopes:** %s\n", strings.Join(scopes, ", ")))
+	}
+
+	return sb.String()
+}
+
 // generateBasicNeighborhoods creates the basic semantic neighborhoods section
 func (mg *MarkdownGenerator) generateBasicNeighborhoods(neighborhoods []git.SemanticNeighborhood) string {
 	var sb strings.Builder
@@ -606,11 +971,15 @@ func (mg *MarkdownGenerator) generateBasicNeighborhoods(neighborhoods []git.Sema
 	sortedNeighborhoods := make([]git.SemanticNeighborhood, len(neighborhoods))
 	copy(sortedNeighborhoods, neighborhoods)
 	sort.Slice(sortedNeighborhoods, func(i, j int) bool {
-		return sortedNeighborhoods[i].CorrelationStrength > sortedNeighborhoods[j].CorrelationStrength
+		a, b := sortedNeighborhoods[i], sortedNeighborhoods[j]
+		if a.CorrelationStrength != b.CorrelationStrength {
+			return a.CorrelationStrength > b.CorrelationStrength
+		}
+		return a.Name < b.Name
 	})
 
 	for i, neighborhood := range sortedNeighborhoods {
-		if i >= 10 { // Limit to top 10 for readability
+		if i >= mg.topN { // Limit to the top neighborhoods for readability
 			break
 		}
 
@@ -619,6 +988,13 @@ func (mg *MarkdownGenerator) generateBasicNeighborhoods(neighborhoods []git.Sema
 		sb.WriteString(fmt.Sprintf("- **Change Frequency**: %d changes\n", neighborhood.ChangeFrequency))
 		sb.WriteString(fmt.Sprintf("- **Last Changed**: %s\n", neighborhood.LastChanged.Format("2006-01-02")))
 		sb.WriteString(fmt.Sprintf("- **Files**: %d files\n", len(neighborhood.Files)))
+		if len(neighborhood.CommitTypes) > 0 {
+			activity := git.FormatCommitTypes(neighborhood.CommitTypes)
+			if neighborhood.Label != "" {
+				activity = fmt.Sprintf("%s (%s)", neighborhood.Label, activity)
+			}
+			sb.WriteString(fmt.Sprintf("- **Activity**: %s\n", activity))
+		}
 
 		// Show file list
 		if len(neighborhood.Files) > 0 {
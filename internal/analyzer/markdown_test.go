@@ -0,0 +1,146 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingWriter keeps every write separately
+type recordingWriter struct {
+	writes []string
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func createLargeGraph(files int) *types.CodeGraph {
+	graph := &types.CodeGraph{
+		Files:   make(map[string]*types.FileNode),
+		Symbols: make(map[types.SymbolId]*types.Symbol),
+		Edges:   make(map[types.EdgeId]*types.GraphEdge),
+		Metadata: &types.GraphMetadata{
+			TotalFiles: files,
+			Languages:  map[string]int{"go": files},
+			Generated:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			Version:    "test",
+		},
+	}
+	for i := 0; i < files; i++ {
+		path := fmt.Sprintf("pkg%03d/file%04d.go", i/50, i)
+		graph.Files[path] = &types.FileNode{Path: path, Language: "go", Lines: i}
+	}
+	return graph
+}
+
+func TestWriteContextMap(t *testing.T) {
+	generator := NewMarkdownGenerator(createLargeGraph(3000))
+
+	var out recordingWriter
+	require.NoError(t, generator.WriteContextMap(&out))
+
+	assert.Equal(t, generator.GenerateContextMap(), strings.Join(out.writes, ""))
+	assert.Greater(t, len(out.writes), 10, "sections are written separately and large ones in chunks")
+	for _, chunk := range out.writes[:len(out.writes)-1] {
+		// A chunk overshoots by at most one row or directory listing
+		assert.LessOrEqual(t, len(chunk), markdownChunkSize+4096)
+		assert.True(t, strings.HasSuffix(chunk, "\n"), "chunks end at line boundaries")
+	}
+	assert.Contains(t, out.writes[0], "# CodeContext Map")
+	assert.Contains(t, out.writes[len(out.writes)-1], "Generated by CodeContext")
+}
+
+func TestWriteContextMapError(t *testing.T) {
+	err := NewMarkdownGenerator(createLargeGraph(10)).WriteContextMap(failingWriter{})
+	assert.EqualError(t, err, "disk full")
+}
+
+func TestModuleSummaries(t *testing.T) {
+	graph := createLargeGraph(3)
+	assert.NotContains(t, NewMarkdownGenerator(graph).GenerateContextMap(), "Module Summaries")
+
+	for path, file := range graph.Files {
+		if file.Lines == 1 {
+			file.Summary = "Loads the configuration."
+			assert.Contains(t, NewMarkdownGenerator(graph).GenerateContextMap(),
+				"### 📝 Module Summaries\n\n- **`"+path+"`**: Loads the configuration.\n")
+		}
+	}
+}
+
+func TestImportAnalysisRanking(t *testing.T) {
+	graph := createLargeGraph(0)
+	imports := map[string]int{"react": 4, "lodash": 2, "./util": 2, "./api": 2, "zod": 1}
+	i := 0
+	for path, count := range imports {
+		for n := 0; n < count; n++ {
+			file := fmt.Sprintf("src/f%d.ts", i)
+			i++
+			graph.Files[file] = &types.FileNode{Path: file, Imports: []*types.Import{{Path: path}}}
+		}
+	}
+
+	generator := NewMarkdownGenerator(graph)
+	generator.SetTopN(3)
+	section := generator.generateImportAnalysis()
+
+	assert.Contains(t, section, "| `react` | 4 |\n| `./api` | 2 |\n| `./util` | 2 |\n")
+	assert.NotContains(t, section, "`lodash`")
+	assert.Contains(t, section, "…and 2 more (1 also with 2 imports)")
+}
+
+func TestRelationshipDescriptions(t *testing.T) {
+	// Every RelationshipType constant of the package, so new types need a description
+	sources, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	var relTypes []RelationshipType
+	for _, source := range sources {
+		if strings.HasSuffix(source, "_test.go") {
+			continue
+		}
+		file, err := goparser.ParseFile(token.NewFileSet(), source, nil, 0)
+		require.NoError(t, err)
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != "RelationshipType" {
+					continue
+				}
+				for _, literal := range value.Values {
+					name, err := strconv.Unquote(literal.(*ast.BasicLit).Value)
+					require.NoError(t, err)
+					relTypes = append(relTypes, RelationshipType(name))
+				}
+			}
+		}
+	}
+	require.Contains(t, relTypes, RelationshipCallsService)
+
+	mg := NewMarkdownGenerator(createLargeGraph(0))
+	for _, relType := range relTypes {
+		assert.NotEqual(t, "Unknown relationship type", mg.getRelationshipDescription(relType), relType)
+	}
+}
@@ -0,0 +1,917 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Web frameworks whose middleware chains are reconstructed
+const (
+	MiddlewareExpress = "express"
+	MiddlewareKoa     = "koa"
+	MiddlewareGin     = "gin"
+	MiddlewareFastAPI = "fastapi"
+)
+
+// Middleware scopes, from outermost to innermost
+const (
+	MiddlewareScopeGlobal = "global" // Registered on the application, runs for every route below it
+	MiddlewareScopeRouter = "router" // Registered on a router, group or mount
+	MiddlewareScopeRoute  = "route"  // Listed on the route itself
+)
+
+var (
+	// Express and Koa
+	jsRouterCreation = regexp.MustCompile(`\b(?:const|let|var)\s+(\w+)\s*=\s*(express\(\)|(?:express\.)?Router\(|new\s+(?:Koa|Router|KoaRouter)\()`)
+	jsRouterRoute    = regexp.MustCompile(`\b(\w+)\.(get|post|put|patch|delete|all|head|options)\(`)
+	jsRouterUse      = regexp.MustCompile(`\b(\w+)\.use\(`)
+	jsRouterExport   = regexp.MustCompile(`(?:module\.exports\s*=|export\s+default)\s+(\w+)`)
+	jsDefaultImport  = regexp.MustCompile(`\bimport\s+(\w+)\s*(?:,\s*\{[^}]*\}\s*)?from\s+['"]([^'"]+)['"]`)
+	jsNamedImport    = regexp.MustCompile(`\bimport\s*(?:\w+\s*,\s*)?\{([^}]*)\}\s*from\s+['"]([^'"]+)['"]`)
+	jsStarImport     = regexp.MustCompile(`\bimport\s*\*\s*as\s+(\w+)\s+from\s+['"]([^'"]+)['"]`)
+	jsRequire        = regexp.MustCompile(`\b(?:const|let|var)\s+(\w+)\s*=\s*require\(\s*['"]([^'"]+)['"]\s*\)`)
+	jsLazyImport     = regexp.MustCompile(`\b(?:const|let|var)\s+(\w+)\s*=\s*(?:React\.)?lazy\(\s*\(\)\s*=>\s*import\(\s*['"]([^'"]+)['"]`)
+	koaRouterPrefix  = regexp.MustCompile(`prefix\s*:\s*['"]([^'"]*)['"]`)
+
+	// Gin
+	ginEngineCreation = regexp.MustCompile(`\b(\w+)\s*:?=\s*gin\.(Default|New)\(\)`)
+	ginGroupCreation  = regexp.MustCompile(`\b(\w+)\s*:?=\s*(\w+)\.Group\(`)
+	ginRoute          = regexp.MustCompile(`\b(\w+)\.(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|Any)\(`)
+	ginUse            = regexp.MustCompile(`\b(\w+)\.Use\(`)
+	ginRouterParam    = regexp.MustCompile(`(\w+)\s+\*?gin\.(?:RouterGroup|Engine|IRouter|IRoutes)\b`)
+	goCallWithArgs    = regexp.MustCompile(`\b([\w.]+)\(`)
+
+	// FastAPI
+	fastAPICreation     = regexp.MustCompile(`^(\w+)\s*=\s*(FastAPI|APIRouter)\(`)
+	fastAPIMiddleware   = regexp.MustCompile(`^(\w+)\.add_middleware\(\s*([\w.]+)`)
+	fastAPIHTTPMiddle   = regexp.MustCompile(`^@(\w+)\.middleware\(\s*["']http["']`)
+	fastAPIInclude      = regexp.MustCompile(`^(\w+)\.include_router\(`)
+	fastAPIRoute        = regexp.MustCompile(`^@(\w+)\.(get|post|put|patch|delete|head|options|api_route|websocket)\(`)
+	fastAPIPrefix       = regexp.MustCompile(`\bprefix\s*=\s*["']([^"']*)["']`)
+	fastAPIDependencies = regexp.MustCompile(`\bdependencies\s*=\s*\[`)
+	fastAPIDepends      = regexp.MustCompile(`\b(?:Depends|Security)\(\s*([\w.]+)`)
+	pythonFromImport    = regexp.MustCompile(`^from\s+([\w.]+)\s+import\s+(.+)$`)
+)
+
+// middlewareLanguages are the languages middleware reconstruction looks at
+var middlewareLanguages = map[string]bool{"javascript": true, "typescript": true, "go": true, "python": true}
+
+// MiddlewareStep is one middleware or dependency a request passes through
+type MiddlewareStep struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+	File  string `json:"file"` // Where it is registered
+	Line  int    `json:"line"`
+}
+
+// MiddlewareChain is the ordered middleware of one route
+type MiddlewareChain struct {
+	Framework  string           `json:"framework"`
+	Method     string           `json:"method"`
+	Path       string           `json:"path"` // Including the prefixes of the routers it is mounted under
+	Handler    string           `json:"handler"`
+	File       string           `json:"file"`
+	Line       int              `json:"line"`
+	Middleware []MiddlewareStep `json:"middleware"` // In execution order, before the handler
+	Mounted    bool             `json:"mounted"`    // Whether the route's router is reachable from an application
+}
+
+// webRouter is an application, router or route group of a web framework
+type webRouter struct {
+	file      string
+	framework string
+	root      bool   // An application or engine
+	ordered   bool   // Middleware only applies to routes registered after it
+	prefix    string // Set on the router itself (Koa and FastAPI routers)
+	uses      []webUse
+	routes    []webRoute
+	mounts    []*webMount
+	parents   []*webMount
+	exported  bool
+}
+
+type webUse struct {
+	name string
+	line int
+	path string // Only routes under this path run it, when set
+}
+
+type webRoute struct {
+	method, path, handler string
+	line                  int
+	middleware            []string
+}
+
+// webMount attaches a child router to a parent under a prefix
+type webMount struct {
+	parent     *webRouter
+	target     string // Expression naming the child, resolved once every file is scanned
+	child      *webRouter
+	prefix     string
+	middleware []string
+	line       int
+}
+
+// importRef is a name imported from another file
+type importRef struct {
+	file string // Resolved file in the graph
+	name string // Exported name, "default" for default exports, "" for the module itself
+}
+
+// middlewareScan collects the routers of every file before mounts are resolved
+type middlewareScan struct {
+	ra      *RelationshipAnalyzer
+	routers map[string]*webRouter // By file, function (Go) and variable
+	imports map[string]map[string]importRef
+	goFuncs map[string][]*webRouter // Go functions taking a router, by name
+}
+
+func routerKey(file, function, name string) string {
+	return file + "\x00" + function + "\x00" + name
+}
+
+// FindMiddlewareChains reconstructs the middleware each route of an
+// Express, Koa, Gin or FastAPI application passes through, in execution
+// order: the application's middleware, then that of each router or group it
+// is mounted under, then the route's own. Express, Koa and Gin middleware
+// only applies to routes registered after it. FastAPI middleware runs
+// last-added first, before the dependencies of the app, routers and route.
+// Routers mounted from other files are followed through imports, and Gin
+// groups through functions taking a *gin.RouterGroup.
+func FindMiddlewareChains(graph *types.CodeGraph) []MiddlewareChain {
+	scan := &middlewareScan{
+		ra:      &RelationshipAnalyzer{graph: graph},
+		routers: make(map[string]*webRouter),
+		imports: make(map[string]map[string]importRef),
+		goFuncs: make(map[string][]*webRouter),
+	}
+	// Routers are declared across files before the calls using them are scanned
+	var jsFiles, goFiles []string
+	contents := make(map[string]string)
+	(&RelationshipAnalyzer{graph: graph}).forEachSourceFileIn(middlewareLanguages, func(filePath, content string) {
+		switch graph.Files[filePath].Language {
+		case "javascript", "typescript":
+			if scan.addJavaScriptRouters(filePath, content) {
+				jsFiles = append(jsFiles, filePath)
+				contents[filePath] = content
+			}
+		case "python":
+			scan.scanFastAPI(filePath, content)
+		case "go":
+			if strings.Contains(content, "gin.") {
+				scan.addGinParams(filePath)
+				goFiles = append(goFiles, filePath)
+				contents[filePath] = content
+			}
+		}
+	})
+	sort.Strings(jsFiles)
+	for _, filePath := range jsFiles {
+		scan.scanJavaScript(filePath, contents[filePath])
+	}
+	sort.Strings(goFiles)
+	for _, filePath := range goFiles {
+		scan.scanGin(filePath, contents[filePath])
+	}
+
+	keys := make([]string, 0, len(scan.routers))
+	for key := range scan.routers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		router := scan.routers[key]
+		for _, mount := range router.mounts {
+			if mount.child == nil {
+				mount.child = scan.resolveMount(router, mount.target)
+			}
+			if mount.child != nil && mount.child != router {
+				mount.child.parents = append(mount.child.parents, mount)
+			}
+		}
+	}
+
+	var chains []MiddlewareChain
+	for _, key := range keys {
+		router := scan.routers[key]
+		for _, route := range router.routes {
+			for _, path := range routerPaths(router, nil) {
+				chains = append(chains, buildChain(path, router, route))
+			}
+		}
+	}
+	sort.SliceStable(chains, func(i, j int) bool {
+		if chains[i].File != chains[j].File {
+			return chains[i].File < chains[j].File
+		}
+		if chains[i].Line != chains[j].Line {
+			return chains[i].Line < chains[j].Line
+		}
+		return chains[i].Path < chains[j].Path
+	})
+	return chains
+}
+
+// routerPaths returns the chains of mounts leading from an application (or
+// an unmounted router) down to a router, outermost first
+func routerPaths(router *webRouter, visiting []*webRouter) [][]*webMount {
+	for _, seen := range visiting {
+		if seen == router {
+			return nil
+		}
+	}
+	if len(router.parents) == 0 {
+		return [][]*webMount{nil}
+	}
+	var paths [][]*webMount
+	for _, mount := range router.parents {
+		for _, path := range routerPaths(mount.parent, append(visiting, router)) {
+			paths = append(paths, append(append([]*webMount{}, path...), mount))
+		}
+	}
+	if len(paths) == 0 {
+		return [][]*webMount{nil}
+	}
+	return paths
+}
+
+// buildChain assembles the middleware of a route reached through mounts
+func buildChain(mounts []*webMount, router *webRouter, route webRoute) MiddlewareChain {
+	chain := MiddlewareChain{
+		Framework: router.framework,
+		Method:    route.method,
+		Handler:   route.handler,
+		File:      router.file,
+		Line:      route.line,
+	}
+	top := router
+	if len(mounts) > 0 {
+		top = mounts[0].parent
+	}
+	chain.Mounted = top.root
+
+	// The path each router is reached at, then the route's full path
+	bases := make([]string, len(mounts))
+	path := ""
+	for i, mount := range mounts {
+		bases[i] = joinRoutePath(path, mount.parent.prefix)
+		path = joinRoutePath(bases[i], mount.prefix)
+	}
+	base := joinRoutePath(path, router.prefix)
+	chain.Path = joinRoutePath(base, route.path)
+
+	addUses := func(r *webRouter, base string, before int) {
+		for _, use := range r.uses {
+			if r.ordered && use.line >= before {
+				continue
+			}
+			if use.path != "" && !underRoutePath(chain.Path, joinRoutePath(base, use.path)) {
+				continue
+			}
+			chain.Middleware = append(chain.Middleware, MiddlewareStep{Name: use.name, Scope: routerScope(r), File: r.file, Line: use.line})
+		}
+	}
+	for i, mount := range mounts {
+		addUses(mount.parent, bases[i], mount.line)
+		for _, name := range mount.middleware {
+			chain.Middleware = append(chain.Middleware, MiddlewareStep{Name: name, Scope: MiddlewareScopeRouter, File: mount.parent.file, Line: mount.line})
+		}
+	}
+	addUses(router, base, route.line)
+	for _, name := range route.middleware {
+		chain.Middleware = append(chain.Middleware, MiddlewareStep{Name: name, Scope: MiddlewareScopeRoute, File: router.file, Line: route.line})
+	}
+	return chain
+}
+
+// underRoutePath reports whether a route path is at or below a prefix,
+// matching whole segments
+func underRoutePath(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func routerScope(router *webRouter) string {
+	if router.root {
+		return MiddlewareScopeGlobal
+	}
+	return MiddlewareScopeRouter
+}
+
+// joinRoutePath appends a route path to a prefix with a single slash
+func joinRoutePath(prefix, path string) string {
+	if path == "" || path == "/" {
+		if prefix == "" {
+			return path
+		}
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// addJavaScriptRouters records the Express and Koa applications and
+// routers a file creates and exports, and reports whether there are any
+func (scan *middlewareScan) addJavaScriptRouters(filePath, content string) bool {
+	framework := MiddlewareExpress
+	if strings.Contains(content, "'koa'") || strings.Contains(content, "\"koa\"") || strings.Contains(content, "koa-router") || strings.Contains(content, "@koa/router") {
+		framework = MiddlewareKoa
+	}
+	local := make(map[string]*webRouter)
+	for _, loc := range jsRouterCreation.FindAllStringSubmatchIndex(content, -1) {
+		name, creation := content[loc[2]:loc[3]], content[loc[4]:loc[5]]
+		router := &webRouter{file: filePath, framework: framework, ordered: true}
+		router.root = creation == "express()" || strings.Contains(creation, "Koa")
+		if m := koaRouterPrefix.FindStringSubmatch(parenContents(content, loc[5]-1)); m != nil {
+			router.prefix = m[1]
+		}
+		local[name] = router
+		scan.routers[routerKey(filePath, "", name)] = router
+	}
+	for _, m := range jsRouterExport.FindAllStringSubmatch(content, -1) {
+		if router := local[m[1]]; router != nil {
+			router.exported = true
+		}
+	}
+	return len(local) > 0
+}
+
+// scanJavaScript records the middleware, routes and mounts of the Express
+// and Koa routers of a file
+func (scan *middlewareScan) scanJavaScript(filePath, content string) {
+	local := scan.localRouters(filePath)
+	scan.imports[filePath] = scan.ra.javaScriptImports(filePath, content)
+
+	for _, loc := range jsRouterUse.FindAllStringSubmatchIndex(content, -1) {
+		router := local[content[loc[2]:loc[3]]]
+		if router == nil {
+			continue
+		}
+		line := lineAt(content, loc[0])
+		args := splitTopLevel(parenContents(content, loc[1]-1))
+		prefix := ""
+		if len(args) > 0 {
+			if path, ok := unquoteRoutePath(args[0]); ok {
+				prefix, args = path, args[1:]
+			}
+		}
+		// Routers among the arguments are mounted; the middleware before them runs for the mount only
+		var middleware []string
+		mounted := false
+		for _, arg := range expandArrays(args) {
+			target := strings.TrimSuffix(arg, ".routes()")
+			if target == arg && scan.javaScriptRouter(filePath, local, arg) == nil {
+				if !strings.HasSuffix(arg, ".allowedMethods()") {
+					middleware = append(middleware, middlewareName(arg))
+				}
+				continue
+			}
+			router.mounts = append(router.mounts, &webMount{parent: router, target: target, prefix: prefix, middleware: middleware, line: line})
+			mounted = true
+		}
+		if !mounted {
+			for _, name := range middleware {
+				router.uses = append(router.uses, webUse{name: name, line: line, path: prefix})
+			}
+		}
+	}
+
+	for _, loc := range jsRouterRoute.FindAllStringSubmatchIndex(content, -1) {
+		router := local[content[loc[2]:loc[3]]]
+		if router == nil {
+			continue
+		}
+		args := splitTopLevel(parenContents(content, loc[1]-1))
+		if len(args) < 2 {
+			continue // app.get("setting") reads Express settings
+		}
+		path, ok := unquoteRoutePath(args[0])
+		if !ok {
+			continue
+		}
+		route := webRoute{method: strings.ToUpper(content[loc[4]:loc[5]]), path: path, line: lineAt(content, loc[0])}
+		handlers := expandArrays(args[1:])
+		route.handler = middlewareName(handlers[len(handlers)-1])
+		for _, arg := range handlers[:len(handlers)-1] {
+			route.middleware = append(route.middleware, middlewareName(arg))
+		}
+		router.routes = append(router.routes, route)
+	}
+}
+
+// javaScriptImports resolves the default, named, namespace, required and
+// lazy imports of a file to the files they come from
+func (ra *RelationshipAnalyzer) javaScriptImports(filePath, content string) map[string]importRef {
+	imports := make(map[string]importRef)
+	resolve := func(spec string) string {
+		return ra.resolveJavaScriptImport(spec, filePath)
+	}
+	for _, m := range jsDefaultImport.FindAllStringSubmatch(content, -1) {
+		if file := resolve(m[2]); file != "" {
+			imports[m[1]] = importRef{file: file, name: "default"}
+		}
+	}
+	for _, pattern := range []*regexp.Regexp{jsRequire, jsLazyImport} {
+		for _, m := range pattern.FindAllStringSubmatch(content, -1) {
+			if file := resolve(m[2]); file != "" {
+				imports[m[1]] = importRef{file: file, name: "default"}
+			}
+		}
+	}
+	for _, m := range jsStarImport.FindAllStringSubmatch(content, -1) {
+		if file := resolve(m[2]); file != "" {
+			imports[m[1]] = importRef{file: file}
+		}
+	}
+	for _, m := range jsNamedImport.FindAllStringSubmatch(content, -1) {
+		file := resolve(m[2])
+		if file == "" {
+			continue
+		}
+		for _, specifier := range strings.Split(m[1], ",") {
+			fields := strings.Fields(specifier)
+			switch {
+			case len(fields) == 1:
+				imports[fields[0]] = importRef{file: file, name: fields[0]}
+			case len(fields) == 3 && fields[1] == "as":
+				imports[fields[2]] = importRef{file: file, name: fields[0]}
+			}
+		}
+	}
+	return imports
+}
+
+// resolveJavaScriptImport returns the graph file an import specifier names,
+// or "" for packages and files outside the graph
+func (ra *RelationshipAnalyzer) resolveJavaScriptImport(spec, fromFile string) string {
+	if resolved := ra.resolveImportPath(spec, fromFile); resolved != "" {
+		return resolved
+	}
+	if candidate := filepath.Join(filepath.Dir(fromFile), spec); ra.graph.Files[candidate] != nil {
+		return candidate
+	}
+	return ""
+}
+
+// javaScriptRouter returns the router a mounted expression names: a router
+// of the file or one imported from another file
+func (scan *middlewareScan) javaScriptRouter(filePath string, local map[string]*webRouter, expr string) *webRouter {
+	if router := local[expr]; router != nil {
+		return router
+	}
+	ref, ok := scan.imports[filePath][expr]
+	if !ok {
+		return nil
+	}
+	if ref.name != "default" {
+		return scan.routers[routerKey(ref.file, "", ref.name)]
+	}
+	return scan.exportedRouter(ref.file)
+}
+
+// exportedRouter returns the router a file exports, or its only router
+func (scan *middlewareScan) exportedRouter(file string) *webRouter {
+	var routers []*webRouter
+	for key, router := range scan.routers {
+		if strings.HasPrefix(key, file+"\x00") && !router.root {
+			if router.exported {
+				return router
+			}
+			routers = append(routers, router)
+		}
+	}
+	if len(routers) == 1 {
+		return routers[0]
+	}
+	return nil
+}
+
+// localRouters returns the routers declared at the top level of a file, by variable
+func (scan *middlewareScan) localRouters(filePath string) map[string]*webRouter {
+	local := make(map[string]*webRouter)
+	for key, router := range scan.routers {
+		if name, ok := strings.CutPrefix(key, filePath+"\x00\x00"); ok {
+			local[name] = router
+		}
+	}
+	return local
+}
+
+// resolveMount finds the child router a mount names once every file is scanned
+func (scan *middlewareScan) resolveMount(parent *webRouter, target string) *webRouter {
+	switch parent.framework {
+	case MiddlewareFastAPI:
+		return scan.pythonRouter(parent.file, target)
+	case MiddlewareExpress, MiddlewareKoa:
+		return scan.javaScriptRouter(parent.file, scan.localRouters(parent.file), target)
+	}
+	return nil
+}
+
+// scanFastAPI records the FastAPI apps and routers of a file, their
+// middleware and dependencies, routes and included routers
+func (scan *middlewareScan) scanFastAPI(filePath, content string) {
+	if !strings.Contains(content, "FastAPI") && !strings.Contains(content, "APIRouter") {
+		return
+	}
+	lines := strings.Split(content, "\n")
+	offsets := lineOffsets(lines)
+	local := make(map[string]*webRouter)
+	middleware := make(map[*webRouter][]webUse)
+	dependencies := make(map[*webRouter][]webUse)
+	scan.imports[filePath] = scan.pythonImports(filePath, lines)
+
+	for i, line := range lines {
+		if m := fastAPICreation.FindStringSubmatch(line); m != nil {
+			args := parenContents(content, offsets[i]+len(m[0])-1)
+			router := &webRouter{file: filePath, framework: MiddlewareFastAPI, root: m[2] == "FastAPI"}
+			if prefix := fastAPIPrefix.FindStringSubmatch(args); prefix != nil {
+				router.prefix = prefix[1]
+			}
+			for _, name := range fastAPIDependencyNames(args) {
+				dependencies[router] = append(dependencies[router], webUse{name: name, line: i + 1})
+			}
+			local[m[1]] = router
+			scan.routers[routerKey(filePath, "", m[1])] = router
+		}
+	}
+	if len(local) == 0 {
+		return
+	}
+
+	for i, line := range lines {
+		switch {
+		case fastAPIMiddleware.MatchString(line):
+			m := fastAPIMiddleware.FindStringSubmatch(line)
+			if router := local[m[1]]; router != nil {
+				middleware[router] = append(middleware[router], webUse{name: m[2], line: i + 1})
+			}
+		case fastAPIHTTPMiddle.MatchString(line):
+			m := fastAPIHTTPMiddle.FindStringSubmatch(line)
+			if router := local[m[1]]; router != nil {
+				if def, _ := nextPythonDef(lines, i+1); def >= 0 {
+					middleware[router] = append(middleware[router], webUse{name: pythonDefName(lines[def]), line: i + 1})
+				}
+			}
+		case fastAPIInclude.MatchString(line):
+			m := fastAPIInclude.FindStringSubmatch(line)
+			router := local[m[1]]
+			if router == nil {
+				continue
+			}
+			args := parenContents(content, offsets[i]+len(m[0])-1)
+			list := splitTopLevel(args)
+			if len(list) == 0 {
+				continue
+			}
+			mount := &webMount{parent: router, target: list[0], middleware: fastAPIDependencyNames(args), line: i + 1}
+			if prefix := fastAPIPrefix.FindStringSubmatch(args); prefix != nil {
+				mount.prefix = prefix[1]
+			}
+			router.mounts = append(router.mounts, mount)
+		case fastAPIRoute.MatchString(line):
+			m := fastAPIRoute.FindStringSubmatch(line)
+			router := local[m[1]]
+			if router == nil {
+				continue
+			}
+			args := parenContents(content, offsets[i]+len(m[0])-1)
+			list := splitTopLevel(args)
+			if len(list) == 0 {
+				continue
+			}
+			path, ok := unquoteRoutePath(list[0])
+			if !ok {
+				continue
+			}
+			method := strings.ToUpper(m[2])
+			if m[2] == "api_route" {
+				method = "ANY"
+			}
+			route := webRoute{method: method, path: path, line: i + 1, middleware: fastAPIDependencyNames(args)}
+			if def, start := nextPythonDef(lines, i+1); def >= 0 {
+				route.handler = pythonDefName(lines[def])
+				// Parameter dependencies run after those listed on the decorator
+				for _, d := range fastAPIDepends.FindAllStringSubmatch(parenContents(content, offsets[def]+start), -1) {
+					route.middleware = append(route.middleware, d[1])
+				}
+			}
+			router.routes = append(router.routes, route)
+		}
+	}
+
+	// Starlette wraps the app in the last added middleware first
+	for _, router := range local {
+		uses := middleware[router]
+		for i := len(uses) - 1; i >= 0; i-- {
+			router.uses = append(router.uses, uses[i])
+		}
+		router.uses = append(router.uses, dependencies[router]...)
+	}
+}
+
+// fastAPIDependencyNames returns the dependencies listed in the
+// dependencies=[...] argument of a call
+func fastAPIDependencyNames(args string) []string {
+	loc := fastAPIDependencies.FindStringIndex(args)
+	if loc == nil {
+		return nil
+	}
+	list := args[loc[1]:]
+	if end := strings.Index(list, "]"); end >= 0 {
+		list = list[:end]
+	}
+	var names []string
+	for _, m := range fastAPIDepends.FindAllStringSubmatch(list, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// nextPythonDef returns the index of the first def line from line on, past
+// the remaining decorators and their arguments, and the offset of its
+// parameter list within it
+func nextPythonDef(lines []string, from int) (int, int) {
+	for i := from; i < len(lines) && i < from+maxDecoratorLines; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, "def ") || strings.HasPrefix(trimmed, "async def ") {
+			return i, strings.Index(lines[i], "(")
+		}
+	}
+	return -1, -1
+}
+
+// maxDecoratorLines bounds how far a decorator is from the def it decorates
+const maxDecoratorLines = 20
+
+func pythonDefName(line string) string {
+	name := strings.TrimSpace(line)
+	name = strings.TrimPrefix(strings.TrimPrefix(name, "async "), "def ")
+	if i := strings.Index(name, "("); i >= 0 {
+		name = name[:i]
+	}
+	return strings.TrimSpace(name)
+}
+
+// pythonImports resolves "from module import name" statements to files:
+// the module's file with the imported name, or the submodule's file itself
+func (scan *middlewareScan) pythonImports(filePath string, lines []string) map[string]importRef {
+	imports := make(map[string]importRef)
+	for _, line := range lines {
+		m := pythonFromImport.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		for _, specifier := range strings.Split(strings.Trim(m[2], "()"), ",") {
+			fields := strings.Fields(specifier)
+			if len(fields) == 0 {
+				continue
+			}
+			name, alias := fields[0], fields[0]
+			if len(fields) == 3 && fields[1] == "as" {
+				alias = fields[2]
+			}
+			submodule := m[1] + "." + name
+			if strings.HasSuffix(m[1], ".") {
+				submodule = m[1] + name
+			}
+			if file := scan.pythonModuleFile(filePath, submodule); file != "" {
+				imports[alias] = importRef{file: file}
+			} else if file := scan.pythonModuleFile(filePath, m[1]); file != "" {
+				imports[alias] = importRef{file: file, name: name}
+			}
+		}
+	}
+	return imports
+}
+
+// pythonModuleFile returns the graph file of a module, relative modules
+// resolved against the importing file and absolute ones by path suffix
+func (scan *middlewareScan) pythonModuleFile(fromFile, module string) string {
+	var candidates []string
+	if strings.HasPrefix(module, ".") {
+		rest := strings.TrimLeft(module, ".")
+		dir := filepath.Dir(fromFile)
+		for i := 1; i < len(module)-len(rest); i++ {
+			dir = filepath.Dir(dir)
+		}
+		base := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(rest, ".", "/")))
+		candidates = append(candidates, base+".py", filepath.Join(base, "__init__.py"))
+		for _, candidate := range candidates {
+			if scan.ra.graph.Files[candidate] != nil {
+				return candidate
+			}
+		}
+		return ""
+	}
+	rel := filepath.FromSlash(strings.ReplaceAll(module, ".", "/"))
+	var matches []string
+	for path := range scan.ra.graph.Files {
+		if strings.HasSuffix(path, string(filepath.Separator)+rel+".py") || strings.HasSuffix(path, string(filepath.Separator)+filepath.Join(rel, "__init__.py")) {
+			matches = append(matches, path)
+		}
+	}
+	sort.Strings(matches)
+	if len(matches) > 0 {
+		return matches[0]
+	}
+	return ""
+}
+
+// pythonRouter returns the router an include_router argument names:
+// "router", "users_router" or "users.router"
+func (scan *middlewareScan) pythonRouter(filePath, expr string) *webRouter {
+	if router := scan.routers[routerKey(filePath, "", expr)]; router != nil {
+		return router
+	}
+	head, attribute, qualified := strings.Cut(expr, ".")
+	ref, ok := scan.imports[filePath][head]
+	if !ok {
+		return nil
+	}
+	switch {
+	case qualified && ref.name == "":
+		return scan.routers[routerKey(ref.file, "", attribute)]
+	case !qualified && ref.name != "":
+		return scan.routers[routerKey(ref.file, "", ref.name)]
+	}
+	return nil
+}
+
+// addGinParams records the routers Go functions of a file take as
+// parameters, so calls passing a router can be followed
+func (scan *middlewareScan) addGinParams(filePath string) {
+	for _, id := range scan.ra.graph.Files[filePath].Symbols {
+		symbol := scan.ra.graph.Symbols[id]
+		if symbol == nil || symbol.Type != types.SymbolTypeFunction {
+			continue
+		}
+		if m := ginRouterParam.FindStringSubmatch(symbol.Signature); m != nil {
+			router := &webRouter{file: filePath, framework: MiddlewareGin, ordered: true}
+			scan.routers[routerKey(filePath, symbol.Name, m[1])] = router
+			scan.goFuncs[symbol.Name] = append(scan.goFuncs[symbol.Name], router)
+		}
+	}
+}
+
+// scanGin records the Gin engines and groups of a file, their middleware,
+// routes and the functions routers are passed to
+func (scan *middlewareScan) scanGin(filePath, content string) {
+	lines := strings.Split(content, "\n")
+	offsets := lineOffsets(lines)
+	function := func(line int) string {
+		if symbol := scan.ra.enclosingSymbol(filePath, line); symbol != nil {
+			return symbol.Name
+		}
+		return ""
+	}
+	lookup := func(fn, name string) *webRouter {
+		if router := scan.routers[routerKey(filePath, fn, name)]; router != nil {
+			return router
+		}
+		return scan.routers[routerKey(filePath, "", name)]
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		fn := function(i + 1)
+		if m := ginEngineCreation.FindStringSubmatch(line); m != nil {
+			router := &webRouter{file: filePath, framework: MiddlewareGin, root: true, ordered: true}
+			if m[2] == "Default" {
+				router.uses = []webUse{{name: "gin.Logger()", line: i + 1}, {name: "gin.Recovery()", line: i + 1}}
+			}
+			scan.routers[routerKey(filePath, fn, m[1])] = router
+			continue
+		}
+		if loc := ginGroupCreation.FindStringSubmatchIndex(line); loc != nil {
+			parent := lookup(fn, line[loc[4]:loc[5]])
+			if parent != nil {
+				args := splitTopLevel(parenContents(content, offsets[i]+loc[1]-1))
+				child := &webRouter{file: filePath, framework: MiddlewareGin, ordered: true}
+				mount := &webMount{parent: parent, child: child, line: i + 1}
+				if len(args) > 0 {
+					mount.prefix, _ = unquoteRoutePath(args[0])
+					for _, arg := range args[1:] {
+						mount.middleware = append(mount.middleware, middlewareName(arg))
+					}
+				}
+				parent.mounts = append(parent.mounts, mount)
+				scan.routers[routerKey(filePath, fn, line[loc[2]:loc[3]])] = child
+				continue
+			}
+		}
+		if loc := ginUse.FindStringSubmatchIndex(line); loc != nil {
+			if router := lookup(fn, line[loc[2]:loc[3]]); router != nil {
+				for _, arg := range splitTopLevel(parenContents(content, offsets[i]+loc[1]-1)) {
+					router.uses = append(router.uses, webUse{name: middlewareName(arg), line: i + 1})
+				}
+				continue
+			}
+		}
+		if loc := ginRoute.FindStringSubmatchIndex(line); loc != nil {
+			if router := lookup(fn, line[loc[2]:loc[3]]); router != nil {
+				args := splitTopLevel(parenContents(content, offsets[i]+loc[1]-1))
+				if path, ok := unquoteRoutePath(firstOf(args)); ok && len(args) >= 2 {
+					route := webRoute{method: strings.ToUpper(line[loc[4]:loc[5]]), path: path, line: i + 1}
+					route.handler = middlewareName(args[len(args)-1])
+					for _, arg := range args[1 : len(args)-1] {
+						route.middleware = append(route.middleware, middlewareName(arg))
+					}
+					router.routes = append(router.routes, route)
+				}
+				continue
+			}
+		}
+
+		// Routers passed to functions registering routes on them
+		for _, loc := range goCallWithArgs.FindAllStringSubmatchIndex(line, -1) {
+			callee := line[loc[2]:loc[3]]
+			candidates := scan.goFuncs[lastSegment(callee)]
+			if len(candidates) == 0 {
+				continue
+			}
+			var parent *webRouter
+			for _, arg := range splitTopLevel(parenContents(line, loc[1]-1)) {
+				if router := lookup(fn, arg); router != nil {
+					parent = router
+					break
+				}
+			}
+			if parent == nil {
+				continue
+			}
+			qualified := strings.Contains(callee, ".")
+			for _, child := range candidates {
+				if child == parent || (filepath.Dir(child.file) == filepath.Dir(filePath)) == qualified {
+					continue
+				}
+				parent.mounts = append(parent.mounts, &webMount{parent: parent, child: child, line: i + 1})
+			}
+		}
+	}
+}
+
+// middlewareName renders a middleware or handler argument: identifiers and
+// calls as written, inline functions as "(inline)"
+func middlewareName(arg string) string {
+	arg = strings.Join(strings.Fields(arg), " ")
+	if strings.Contains(arg, "=>") || strings.HasPrefix(arg, "function") || strings.HasPrefix(arg, "async ") ||
+		strings.HasPrefix(arg, "func(") || strings.HasPrefix(arg, "func (") || strings.HasPrefix(arg, "lambda") {
+		return "(inline)"
+	}
+	if len(arg) > 60 {
+		if i := strings.Index(arg, "("); i >= 0 {
+			return arg[:i] + "(…)"
+		}
+	}
+	return arg
+}
+
+// expandArrays flattens array arguments: app.use([a, b]) registers a and b
+func expandArrays(args []string) []string {
+	var expanded []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "[") && strings.HasSuffix(arg, "]") {
+			expanded = append(expanded, splitTopLevel(arg[1:len(arg)-1])...)
+			continue
+		}
+		expanded = append(expanded, arg)
+	}
+	return expanded
+}
+
+// unquoteRoutePath returns the path of a quoted route argument
+func unquoteRoutePath(arg string) (string, bool) {
+	if len(arg) < 2 {
+		return "", false
+	}
+	quote := arg[0]
+	if (quote != '"' && quote != '\'' && quote != '`') || arg[len(arg)-1] != quote {
+		return "", false
+	}
+	return arg[1 : len(arg)-1], true
+}
+
+func firstOf(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return items[0]
+}
+
+// lineOffsets returns the byte offset each line starts at
+func lineOffsets(lines []string) []int {
+	offsets := make([]int, len(lines))
+	offset := 0
+	for i, line := range lines {
+		offsets[i] = offset
+		offset += len(line) + 1
+	}
+	return offsets
+}
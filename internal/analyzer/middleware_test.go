@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainSummary renders a chain as "GET /path: a(global) b(router) c(route) -> handler"
+func chainSummary(chain MiddlewareChain) string {
+	summary := chain.Method + " " + chain.Path + ":"
+	for _, step := range chain.Middleware {
+		summary += " " + step.Name + "(" + step.Scope + ")"
+	}
+	return summary + " -> " + chain.Handler
+}
+
+func analyzeMiddlewareFixture(t *testing.T, files map[string]string) []string {
+	dir := t.TempDir()
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	var summaries []string
+	for _, chain := range FindMiddlewareChains(graph) {
+		summaries = append(summaries, chainSummary(chain))
+	}
+	return summaries
+}
+
+func TestFindMiddlewareChainsExpress(t *testing.T) {
+	summaries := analyzeMiddlewareFixture(t, map[string]string{
+		"app.js": "const express = require('express');\nconst users = require('./routes/users');\n\nconst app = express();\n" +
+			"app.use(cors());\napp.use(express.json());\napp.get('/health', health);\napp.use('/admin', requireAdmin);\n" +
+			"app.use(logger);\napp.use('/api/users', authenticate, users);\napp.set('port', 3000);\napp.get('port');\n",
+		"routes/users.js": "const express = require('express');\nconst router = express.Router();\n\nrouter.use(loadTenant);\n" +
+			"router.get('/:id', validate, (req, res) => res.json({}));\nrouter.post('/', [validate, rateLimit({ max: 10 })], createUser);\n\nmodule.exports = router;\n",
+	})
+	assert.Equal(t, []string{
+		"GET /health: cors()(global) express.json()(global) -> health",
+		"GET /api/users/:id: cors()(global) express.json()(global) logger(global) authenticate(router) loadTenant(router) validate(route) -> (inline)",
+		"POST /api/users: cors()(global) express.json()(global) logger(global) authenticate(router) loadTenant(router) validate(route) rateLimit({ max: 10 })(route) -> createUser",
+	}, summaries, "middleware registered after a route or under another path does not run for it")
+}
+
+func TestFindMiddlewareChainsKoa(t *testing.T) {
+	summaries := analyzeMiddlewareFixture(t, map[string]string{
+		"server.ts": "import Koa from 'koa';\nimport Router from '@koa/router';\nimport { router as items } from './items';\n\n" +
+			"const app = new Koa();\nconst api = new Router({ prefix: '/api' });\n\napp.use(bodyParser());\n" +
+			"api.use('/items', items.routes());\napp.use(api.routes());\napp.use(api.allowedMethods());\n",
+		"items.ts": "import Router from '@koa/router';\n\nexport const router = new Router();\n\nrouter.get('/', auth, listItems);\n",
+	})
+	assert.Equal(t, []string{
+		"GET /api/items: bodyParser()(global) auth(route) -> listItems",
+	}, summaries)
+}
+
+func TestFindMiddlewareChainsGin(t *testing.T) {
+	summaries := analyzeMiddlewareFixture(t, map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"main.go": "package main\n\nimport (\n\t\"github.com/gin-gonic/gin\"\n\n\t\"example.com/shop/orders\"\n)\n\n" +
+			"func main() {\n\tr := gin.Default()\n\tr.Use(cors.New())\n\tapi := r.Group(\"/api\", auth.Required())\n\t{\n\t\tapi.GET(\"/ping\", ping)\n\t}\n" +
+			"\tv1 := api.Group(\"/v1\")\n\tv1.Use(audit)\n\torders.Register(v1)\n\tr.Run()\n}\n",
+		"orders/routes.go": "package orders\n\nimport \"github.com/gin-gonic/gin\"\n\n" +
+			"func Register(rg *gin.RouterGroup) {\n\trg.GET(\"/orders\", cache(30), list)\n\trg.POST(\"/orders\", func(c *gin.Context) {})\n}\n",
+	})
+	assert.Equal(t, []string{
+		"GET /api/ping: gin.Logger()(global) gin.Recovery()(global) cors.New()(global) auth.Required()(router) -> ping",
+		"GET /api/v1/orders: gin.Logger()(global) gin.Recovery()(global) cors.New()(global) auth.Required()(router) audit(router) cache(30)(route) -> list",
+		"POST /api/v1/orders: gin.Logger()(global) gin.Recovery()(global) cors.New()(global) auth.Required()(router) audit(router) -> (inline)",
+	}, summaries)
+}
+
+func TestFindMiddlewareChainsFastAPI(t *testing.T) {
+	summaries := analyzeMiddlewareFixture(t, map[string]string{
+		"app/main.py": "from fastapi import FastAPI, Depends\nfrom .routers import users\n\n" +
+			"app = FastAPI(dependencies=[Depends(verify_key)])\napp.add_middleware(CORSMiddleware, allow_origins=['*'])\napp.add_middleware(GZipMiddleware)\n\n\n" +
+			"@app.middleware(\"http\")\nasync def add_timing(request, call_next):\n    return await call_next(request)\n\n\n" +
+			"app.include_router(users.router, prefix=\"/api\", dependencies=[Depends(get_tenant)])\n",
+		"app/routers/__init__.py": "# Routers\n",
+		"app/routers/users.py": "from fastapi import APIRouter, Depends\n\nrouter = APIRouter(prefix=\"/users\", dependencies=[Depends(get_user)])\n\n\n" +
+			"@router.get(\"/{user_id}\", dependencies=[Depends(audit)])\nasync def read_user(user_id: int, db=Depends(get_db)):\n    return {}\n",
+	})
+	assert.Equal(t, []string{
+		"GET /api/users/{user_id}: add_timing(global) GZipMiddleware(global) CORSMiddleware(global) verify_key(global) get_tenant(router) get_user(router) audit(route) get_db(route) -> read_user",
+	}, summaries, "the last added middleware runs first, dependencies after all middleware")
+}
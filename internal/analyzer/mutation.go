@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/nuthan-ms/codecontext/internal/coverage"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// SetMutationReports sets the mutation testing reports (Stryker JSON,
+// go-mutesting output or report.json) attached to files and symbols after
+// parsing. Relative paths are resolved against the analyzed directory.
+func (gb *GraphBuilder) SetMutationReports(paths []string) {
+	gb.mutationReports = paths
+}
+
+// addMutations loads the configured mutation reports into the graph, skipping
+// them with a progress message when they cannot be read
+func (gb *GraphBuilder) addMutations(targetDir string) {
+	if len(gb.mutationReports) == 0 {
+		return
+	}
+	paths := make([]string, 0, len(gb.mutationReports))
+	for _, path := range gb.mutationReports {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(targetDir, path)
+		}
+		paths = append(paths, path)
+	}
+	report, err := coverage.LoadMutations(paths)
+	if err != nil {
+		if gb.progressCallback != nil {
+			gb.progressCallback(fmt.Sprintf("⚠️ Mutation results skipped: %v", err))
+		}
+		return
+	}
+	matched := ApplyMutations(gb.graph, targetDir, report)
+	if gb.progressCallback != nil {
+		gb.progressCallback(fmt.Sprintf("🧬 Mutation results attached to %d files", matched))
+	}
+}
+
+// ApplyMutations counts the mutants of a report against the graph's files and
+// the innermost symbol spanning each mutant's line, and returns the number of
+// files found in it. Ignored mutants (compile errors, skipped) are not
+// counted. Files missing from the report keep nil scores.
+func ApplyMutations(graph *types.CodeGraph, root string, report *coverage.MutationReport) int {
+	matched := 0
+	for path, file := range graph.Files {
+		mutants := report.Lookup(root, projectPath(root, path))
+		if len(mutants) == 0 {
+			file.Mutants = nil
+			continue
+		}
+		matched++
+		file.Mutants = &types.MutationScore{}
+		spans := symbolSpans(graph, file)
+		for _, span := range spans {
+			span.symbol.Mutants = nil
+		}
+		for _, mutant := range mutants {
+			if mutant.Status == coverage.MutantIgnored {
+				continue
+			}
+			countMutant(file.Mutants, mutant.Status)
+
+			var inner *symbolSpan
+			for i := range spans {
+				span := &spans[i]
+				if mutant.Line < span.start || mutant.Line > span.end {
+					continue
+				}
+				if inner == nil || span.end-span.start < inner.end-inner.start {
+					inner = span
+				}
+			}
+			if inner == nil {
+				continue
+			}
+			if inner.symbol.Mutants == nil {
+				inner.symbol.Mutants = &types.MutationScore{}
+			}
+			countMutant(inner.symbol.Mutants, mutant.Status)
+		}
+	}
+	return matched
+}
+
+// countMutant adds a tested mutant to a score
+func countMutant(score *types.MutationScore, status string) {
+	switch status {
+	case coverage.MutantKilled:
+		score.Killed++
+	case coverage.MutantSurvived:
+		score.Survived++
+	case coverage.MutantNoCoverage:
+		score.NoCoverage++
+	}
+}
+
+// WeaklyTested is a symbol whose tests let mutants survive
+type WeaklyTested struct {
+	Symbol     *types.Symbol
+	File       string
+	Survived   int     // Surviving and uncovered mutants
+	Score      float64 // Mutation score, from 0 to 100
+	References int     // Files and packages referring to the symbol's file
+}
+
+// FindWeaklyTested returns the symbols with surviving or uncovered mutants,
+// most critical first: surviving mutants weighted by how many files depend on
+// the symbol's file, since a gap in the tests of widely used code reaches
+// furthest
+func FindWeaklyTested(graph *types.CodeGraph) []WeaklyTested {
+	references := fileReferences(graph)
+	var weak []WeaklyTested
+	for path, file := range graph.Files {
+		if file.IsTest {
+			continue
+		}
+		for _, id := range file.Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil || symbol.Mutants == nil {
+				continue
+			}
+			survived := symbol.Mutants.Survived + symbol.Mutants.NoCoverage
+			if survived == 0 {
+				continue
+			}
+			weak = append(weak, WeaklyTested{
+				Symbol:     symbol,
+				File:       path,
+				Survived:   survived,
+				Score:      symbol.Mutants.Percent(),
+				References: references[path],
+			})
+		}
+	}
+	sort.Slice(weak, func(i, j int) bool {
+		wi := weak[i].Survived * (1 + weak[i].References)
+		wj := weak[j].Survived * (1 + weak[j].References)
+		if wi != wj {
+			return wi > wj
+		}
+		if weak[i].File != weak[j].File {
+			return weak[i].File < weak[j].File
+		}
+		return weak[i].Symbol.Location.StartLine < weak[j].Symbol.Location.StartLine
+	})
+	return weak
+}
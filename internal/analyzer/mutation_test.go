@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationReports(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/shop\n\ngo 1.22\n",
+		"orders/order.go": "package orders\n\ntype Order struct{}\n\nfunc New() *Order {\n\treturn &Order{}\n}\n\nfunc Cancel(o *Order) bool {\n\treturn o != nil\n}\n",
+		"main.go":         "package main\n\nimport \"example.com/shop/orders\"\n\nfunc main() {\n\torders.Cancel(orders.New())\n}\n",
+		"report.json": `{
+  "escaped": [
+    {"mutator": {"mutatorName": "branch/if", "originalFilePath": "orders/order.go", "originalStartLine": 10}},
+    {"mutator": {"mutatorName": "expression/remove", "originalFilePath": "orders/order.go", "originalStartLine": 10}}
+  ],
+  "killed": [{"mutator": {"mutatorName": "statement/remove", "originalFilePath": "orders/order.go", "originalStartLine": 6}}],
+  "errored": [{"mutator": {"mutatorName": "branch/case", "originalFilePath": "orders/order.go", "originalStartLine": 6}}]
+}`,
+	}
+	testutils.WriteTree(t, dir, files)
+	builder := NewGraphBuilder()
+	builder.SetMutationReports([]string{"report.json"})
+	graph, err := builder.AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	order := graph.Files[filepath.Join(dir, "orders/order.go")]
+	require.NotNil(t, order)
+	assert.Equal(t, &types.MutationScore{Killed: 1, Survived: 2}, order.Mutants, "errored mutants are not counted")
+	assert.Nil(t, graph.Files[filepath.Join(dir, "main.go")].Mutants)
+
+	symbols := make(map[string]*types.Symbol)
+	for _, id := range order.Symbols {
+		symbols[graph.Symbols[id].Name] = graph.Symbols[id]
+	}
+	assert.Equal(t, &types.MutationScore{Killed: 1}, symbols["New"].Mutants)
+	assert.Equal(t, &types.MutationScore{Survived: 2}, symbols["Cancel"].Mutants)
+	assert.Nil(t, symbols["Order"].Mutants)
+
+	weak := FindWeaklyTested(graph)
+	require.Len(t, weak, 1)
+	assert.Equal(t, "Cancel", weak[0].Symbol.Name)
+	assert.Equal(t, 2, weak[0].Survived)
+	assert.Equal(t, 0.0, weak[0].Score)
+	assert.Equal(t, 1, weak[0].References, "main.go imports the package")
+
+	markdown := NewMarkdownGenerator(graph).GenerateContextMap()
+	assert.Contains(t, markdown, "### 🧬 Weakly Tested Critical Code")
+	assert.Contains(t, markdown, "| `Cancel` | `order.go:9` | 2 | 0% | 1 |")
+}
@@ -0,0 +1,375 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// NamingStyle is the way an identifier joins its words
+type NamingStyle string
+
+// Naming styles. A single lowercase or uppercase word fits several styles.
+const (
+	StyleCamel     NamingStyle = "camelCase"
+	StylePascal    NamingStyle = "PascalCase"
+	StyleSnake     NamingStyle = "snake_case"
+	StyleScreaming NamingStyle = "SCREAMING_SNAKE_CASE"
+	StyleKebab     NamingStyle = "kebab-case"
+	StyleLower     NamingStyle = "lowercase" // Fits camel, snake and kebab case
+	StyleUpper     NamingStyle = "UPPERCASE" // Fits screaming snake and Pascal case
+	StyleMixed     NamingStyle = "mixed"     // Separators mixed with capitals, e.g. Get_value
+)
+
+// namingStyles are the styles a convention can be, in tie-breaking order
+var namingStyles = []NamingStyle{StyleCamel, StylePascal, StyleSnake, StyleScreaming, StyleKebab}
+
+var namingStyleAliases = map[string]NamingStyle{
+	"camel":     StyleCamel,
+	"pascal":    StylePascal,
+	"snake":     StyleSnake,
+	"screaming": StyleScreaming,
+	"kebab":     StyleKebab,
+}
+
+// minModuleNames is how many names of a kind a module needs before its own
+// majority style, rather than the project's, is its convention
+const minModuleNames = 3
+
+// NamingProfile fixes the style expected of names by language and kind, as
+// in {"python": {"function": "snake_case"}}. Kinds are symbol types
+// (function, method, class, interface, type, enum, variable, constant),
+// "component" for capitalized functions of JSX and TSX files, and "file" for
+// file names. Conventions left out follow the majority of the code.
+type NamingProfile map[string]map[string]string
+
+// NamingConvention is the style names of a kind follow in one language
+type NamingConvention struct {
+	Language   string      `json:"language"`
+	Kind       string      `json:"kind"`
+	Style      NamingStyle `json:"style"`
+	Configured bool        `json:"configured,omitempty"` // Set by the profile rather than the majority
+	Names      int         `json:"names"`
+	Matching   int         `json:"matching"` // Names fitting the style
+}
+
+// LocalConvention is a module following a style of its own
+type LocalConvention struct {
+	Language string      `json:"language"`
+	Kind     string      `json:"kind"`
+	Style    NamingStyle `json:"style"`
+	Project  NamingStyle `json:"project"` // Style of the rest of the project
+}
+
+// NamingDeviation is a name breaking the convention of its module
+type NamingDeviation struct {
+	File     string      `json:"file"` // Relative to the project root
+	Line     int         `json:"line,omitempty"`
+	Name     string      `json:"name"`
+	Language string      `json:"language"`
+	Kind     string      `json:"kind"`
+	Style    NamingStyle `json:"style"`
+	Expected NamingStyle `json:"expected"`
+}
+
+// ModuleNaming is the naming of one directory
+type ModuleNaming struct {
+	Module     string            `json:"module"` // Relative to the project root, "." for the root
+	Local      []LocalConvention `json:"local,omitempty"`
+	Deviations []NamingDeviation `json:"deviations,omitempty"`
+}
+
+// NamingReport is the naming consistency of a project
+type NamingReport struct {
+	Conventions []NamingConvention `json:"conventions"`
+	Modules     []ModuleNaming     `json:"modules"` // Modules with local conventions or deviations, most deviations first
+	Deviations  int                `json:"deviations"`
+}
+
+// ParseNamingStyle accepts a style by its name in any case, or by its first
+// word, e.g. "snake"
+func ParseNamingStyle(name string) (NamingStyle, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	for _, style := range namingStyles {
+		if key == strings.ToLower(string(style)) {
+			return style, nil
+		}
+	}
+	if style, ok := namingStyleAliases[key]; ok {
+		return style, nil
+	}
+	return "", fmt.Errorf("unknown naming style %q (use camelCase, PascalCase, snake_case, SCREAMING_SNAKE_CASE or kebab-case)", name)
+}
+
+// ClassifyName returns the style of a name, ignoring leading and trailing
+// underscores and dollar signs. Names shorter than two letters have none.
+func ClassifyName(name string) NamingStyle {
+	name = strings.Trim(name, "_$")
+	if utf8.RuneCountInString(name) < 2 {
+		return ""
+	}
+	var upper, lower, underscore, hyphen bool
+	for _, r := range name {
+		switch {
+		case r == '_':
+			underscore = true
+		case r == '-':
+			hyphen = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		}
+	}
+	first, _ := utf8.DecodeRuneInString(name)
+	switch {
+	case underscore && hyphen, hyphen && upper, underscore && upper && lower:
+		return StyleMixed
+	case hyphen:
+		return StyleKebab
+	case underscore && upper:
+		return StyleScreaming
+	case underscore:
+		return StyleSnake
+	case !upper:
+		return StyleLower
+	case !lower:
+		return StyleUpper
+	case unicode.IsUpper(first):
+		return StylePascal
+	}
+	return StyleCamel
+}
+
+// Fits reports whether a name of the given style follows the convention s
+func (s NamingStyle) Fits(style NamingStyle) bool {
+	switch style {
+	case s:
+		return true
+	case StyleLower:
+		return s == StyleCamel || s == StyleSnake || s == StyleKebab
+	case StyleUpper:
+		return s == StyleScreaming || s == StylePascal
+	}
+	return false
+}
+
+// namedEntity is a file or symbol name with its style
+type namedEntity struct {
+	language, kind, module string
+	file, name             string
+	line                   int
+	style                  NamingStyle
+}
+
+// AnalyzeNaming finds the naming convention of each kind of name in each
+// language, per project and per directory, and the names breaking the
+// convention of their directory. A directory follows its own majority style
+// when it has enough names and the profile sets no convention. Go names are
+// classified with their first letter lowercased, since capitalization
+// exports them. Test and generated files are left out.
+func AnalyzeNaming(graph *types.CodeGraph, root string, profile NamingProfile) (*NamingReport, error) {
+	configured := make(map[[2]string]NamingStyle)
+	for language, kinds := range profile {
+		for kind, name := range kinds {
+			style, err := ParseNamingStyle(name)
+			if err != nil {
+				return nil, fmt.Errorf("naming convention for %s %s: %w", language, kind, err)
+			}
+			configured[[2]string{strings.ToLower(language), strings.ToLower(kind)}] = style
+		}
+	}
+
+	var entities []namedEntity
+	for file, node := range graph.Files {
+		if node.IsTest || node.IsGenerated || !entryPointLanguages[node.Language] {
+			continue
+		}
+		rel := projectPath(root, file)
+		module := path.Dir(rel)
+		base := path.Base(rel)
+		if stem, _, _ := strings.Cut(base, "."); stem != "" {
+			if style := ClassifyName(stem); style != "" {
+				entities = append(entities, namedEntity{language: node.Language, kind: "file", module: module, file: rel, name: base, style: style})
+			}
+		}
+		for _, id := range node.Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil || !refactorSymbol(symbol) {
+				continue
+			}
+			name := symbol.Name
+			if strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__") {
+				continue // Python special methods
+			}
+			classified := name
+			if node.Language == "go" {
+				first, size := utf8.DecodeRuneInString(name)
+				classified = string(unicode.ToLower(first)) + name[size:]
+			}
+			style := ClassifyName(classified)
+			kind := namingKind(symbol, base, style)
+			if style == "" || kind == "" {
+				continue
+			}
+			entities = append(entities, namedEntity{language: node.Language, kind: kind, module: module, file: rel, name: name, line: symbol.Location.StartLine, style: style})
+		}
+	}
+
+	projectCounts := make(map[[2]string]map[NamingStyle]int)
+	moduleCounts := make(map[[3]string]map[NamingStyle]int)
+	for _, entity := range entities {
+		key := [2]string{entity.language, entity.kind}
+		moduleKey := [3]string{entity.module, entity.language, entity.kind}
+		if projectCounts[key] == nil {
+			projectCounts[key] = make(map[NamingStyle]int)
+		}
+		if moduleCounts[moduleKey] == nil {
+			moduleCounts[moduleKey] = make(map[NamingStyle]int)
+		}
+		projectCounts[key][entity.style]++
+		moduleCounts[moduleKey][entity.style]++
+	}
+
+	report := &NamingReport{}
+	projectStyle := make(map[[2]string]NamingStyle)
+	for key, counts := range projectCounts {
+		style, ok := configured[key]
+		if !ok {
+			style = majorityStyle(counts, 0)
+		}
+		if style == "" {
+			continue
+		}
+		projectStyle[key] = style
+		convention := NamingConvention{Language: key[0], Kind: key[1], Style: style, Configured: ok}
+		for named, count := range counts {
+			convention.Names += count
+			if style.Fits(named) {
+				convention.Matching += count
+			}
+		}
+		report.Conventions = append(report.Conventions, convention)
+	}
+	sort.Slice(report.Conventions, func(i, j int) bool {
+		a, b := report.Conventions[i], report.Conventions[j]
+		if a.Language != b.Language {
+			return a.Language < b.Language
+		}
+		return a.Kind < b.Kind
+	})
+
+	modules := make(map[string]*ModuleNaming)
+	moduleOf := func(name string) *ModuleNaming {
+		if modules[name] == nil {
+			modules[name] = &ModuleNaming{Module: name}
+		}
+		return modules[name]
+	}
+	moduleStyle := make(map[[3]string]NamingStyle)
+	for moduleKey, counts := range moduleCounts {
+		key := [2]string{moduleKey[1], moduleKey[2]}
+		style := projectStyle[key]
+		if _, ok := configured[key]; !ok {
+			if local := majorityStyle(counts, minModuleNames); local != "" {
+				style = local
+			}
+		}
+		moduleStyle[moduleKey] = style
+		if style != "" && style != projectStyle[key] {
+			module := moduleOf(moduleKey[0])
+			module.Local = append(module.Local, LocalConvention{Language: key[0], Kind: key[1], Style: style, Project: projectStyle[key]})
+		}
+	}
+	for _, entity := range entities {
+		expected := moduleStyle[[3]string{entity.module, entity.language, entity.kind}]
+		if expected == "" || expected.Fits(entity.style) {
+			continue
+		}
+		module := moduleOf(entity.module)
+		module.Deviations = append(module.Deviations, NamingDeviation{
+			File:     entity.file,
+			Line:     entity.line,
+			Name:     entity.name,
+			Language: entity.language,
+			Kind:     entity.kind,
+			Style:    entity.style,
+			Expected: expected,
+		})
+		report.Deviations++
+	}
+
+	for _, module := range modules {
+		sort.Slice(module.Local, func(i, j int) bool {
+			a, b := module.Local[i], module.Local[j]
+			if a.Language != b.Language {
+				return a.Language < b.Language
+			}
+			return a.Kind < b.Kind
+		})
+		sort.Slice(module.Deviations, func(i, j int) bool {
+			a, b := module.Deviations[i], module.Deviations[j]
+			if a.File != b.File {
+				return a.File < b.File
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Name < b.Name
+		})
+		report.Modules = append(report.Modules, *module)
+	}
+	sort.Slice(report.Modules, func(i, j int) bool {
+		a, b := report.Modules[i], report.Modules[j]
+		if len(a.Deviations) != len(b.Deviations) {
+			return len(a.Deviations) > len(b.Deviations)
+		}
+		return a.Module < b.Module
+	})
+	return report, nil
+}
+
+// namingKind returns the kind of name a symbol is compared with, or "" for
+// symbols with no convention of their own such as constructors. Variables
+// in screaming snake case are constants, and capitalized functions of JSX
+// and TSX files are components.
+func namingKind(symbol *types.Symbol, file string, style NamingStyle) string {
+	switch symbol.Type {
+	case types.SymbolTypeFunction:
+		if (strings.HasSuffix(file, ".jsx") || strings.HasSuffix(file, ".tsx")) && style == StylePascal {
+			return "component"
+		}
+		return "function"
+	case types.SymbolTypeVariable:
+		if style == StyleScreaming {
+			return "constant"
+		}
+		return "variable"
+	case types.SymbolTypeMethod, types.SymbolTypeClass, types.SymbolTypeInterface, types.SymbolTypeType,
+		types.SymbolTypeEnum, types.SymbolTypeConstant, types.SymbolTypeComponent, types.SymbolTypeHook:
+		return string(symbol.Type)
+	}
+	return ""
+}
+
+// majorityStyle returns the style most names follow among the names whose
+// style is unambiguous, or "" when fewer than min names are. Ties go to the
+// first style of namingStyles.
+func majorityStyle(counts map[NamingStyle]int, min int) NamingStyle {
+	best, total := NamingStyle(""), 0
+	for _, style := range namingStyles {
+		total += counts[style]
+		if counts[style] > 0 && (best == "" || counts[style] > counts[best]) {
+			best = style
+		}
+	}
+	if total < min {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyName(t *testing.T) {
+	for name, style := range map[string]NamingStyle{
+		"parseRow":     StyleCamel,
+		"ParseRow":     StylePascal,
+		"parse_row":    StyleSnake,
+		"_parse_row":   StyleSnake,
+		"MAX_SIZE":     StyleScreaming,
+		"user-profile": StyleKebab,
+		"parse":        StyleLower,
+		"URL":          StyleUpper,
+		"Get_value":    StyleMixed,
+		"x":            "",
+	} {
+		assert.Equal(t, style, ClassifyName(name), name)
+	}
+	assert.True(t, StyleSnake.Fits(StyleLower))
+	assert.True(t, StylePascal.Fits(StyleUpper))
+	assert.False(t, StyleCamel.Fits(StyleSnake))
+
+	style, err := ParseNamingStyle("snake")
+	require.NoError(t, err)
+	assert.Equal(t, StyleSnake, style)
+	style, err = ParseNamingStyle("PASCALCASE")
+	require.NoError(t, err)
+	assert.Equal(t, StylePascal, style)
+	_, err = ParseNamingStyle("hungarian")
+	assert.Error(t, err)
+}
+
+func TestAnalyzeNaming(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                       "module example.com/app\n\ngo 1.24\n",
+		"internal/store/store.go":      "package store\n\nfunc Open() {}\n\nfunc closeAll() {}\n\nfunc parse_row() {}\n",
+		"internal/store/page_cache.go": "package store\n\nfunc pageCache() {}\n",
+		"internal/store/row_cache.go":  "package store\n\nfunc rowCache() {}\n",
+		"internal/store/rowReader.go":  "package store\n\nfunc rowReader() {}\n",
+		"app/models.py":                "MAX_SIZE = 10\n\nclass UserModel:\n    pass\n\ndef load_user():\n    pass\n\ndef delete_user():\n    pass\n\ndef list_users():\n    pass\n\ndef find_user():\n    pass\n\ndef update_user():\n    pass\n\ndef saveUser():\n    pass\n",
+		"legacy/api.py":                "def getUser():\n    pass\n\ndef putUser():\n    pass\n\ndef listUsers():\n    pass\n",
+		"app/test_models.py":           "def testLoad():\n    pass\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	report, err := AnalyzeNaming(graph, dir, nil)
+	require.NoError(t, err)
+	conventions := make(map[string]NamingConvention)
+	for _, convention := range report.Conventions {
+		conventions[convention.Language+"/"+convention.Kind] = convention
+	}
+	assert.Equal(t, NamingConvention{Language: "go", Kind: "file", Style: StyleSnake, Names: 4, Matching: 3}, conventions["go/file"])
+	assert.Equal(t, StyleCamel, conventions["go/function"].Style, "exported Go names count as camel case")
+	assert.Equal(t, NamingConvention{Language: "python", Kind: "function", Style: StyleSnake, Names: 9, Matching: 5}, conventions["python/function"])
+	assert.Equal(t, StyleScreaming, conventions["python/constant"].Style)
+
+	modules := make(map[string]ModuleNaming)
+	for _, module := range report.Modules {
+		modules[module.Module] = module
+	}
+	assert.Equal(t, []NamingDeviation{
+		{File: "internal/store/rowReader.go", Name: "rowReader.go", Language: "go", Kind: "file", Style: StyleCamel, Expected: StyleSnake},
+		{File: "internal/store/store.go", Line: 7, Name: "parse_row", Language: "go", Kind: "function", Style: StyleSnake, Expected: StyleCamel},
+	}, modules["internal/store"].Deviations)
+	assert.Equal(t, []NamingDeviation{
+		{File: "app/models.py", Line: 21, Name: "saveUser", Language: "python", Kind: "function", Style: StyleCamel, Expected: StyleSnake},
+	}, modules["app"].Deviations, "test files are left out")
+	assert.Equal(t, []LocalConvention{{Language: "python", Kind: "function", Style: StyleCamel, Project: StyleSnake}}, modules["legacy"].Local)
+	assert.Empty(t, modules["legacy"].Deviations, "the module follows its own convention")
+	assert.Equal(t, 3, report.Deviations)
+
+	report, err = AnalyzeNaming(graph, dir, NamingProfile{"python": {"function": "snake"}})
+	require.NoError(t, err)
+	modules = make(map[string]ModuleNaming)
+	for _, module := range report.Modules {
+		modules[module.Module] = module
+	}
+	assert.Empty(t, modules["legacy"].Local, "configured conventions hold in every module")
+	assert.Len(t, modules["legacy"].Deviations, 3)
+	assert.Equal(t, "legacy", report.Modules[0].Module)
+
+	_, err = AnalyzeNaming(graph, dir, NamingProfile{"go": {"file": "hungarian"}})
+	assert.ErrorContains(t, err, "naming convention for go file")
+}
@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// IndexScheme is the scheme of the symbols in SCIP and LSIF indexes
+const IndexScheme = "codecontext"
+
+// IndexExporter writes the definitions of an analyzed graph as a SCIP or
+// LSIF index, so code navigation tools such as Sourcegraph can use them.
+// The graph has no reference positions yet, so indexes hold definitions,
+// their documentation and the types they extend or implement.
+type IndexExporter struct {
+	graph     *types.CodeGraph
+	targetDir string
+	version   string
+	redactor  *redact.Redactor
+}
+
+// NewIndexExporter creates an index exporter for a graph analyzed from targetDir
+func NewIndexExporter(graph *types.CodeGraph, targetDir string) *IndexExporter {
+	return &IndexExporter{graph: graph, targetDir: targetDir}
+}
+
+// SetVersion sets the tool version recorded in the index metadata
+func (e *IndexExporter) SetVersion(version string) {
+	e.version = version
+}
+
+// SetRedactor leaves out sensitive files and masks secrets in documentation
+func (e *IndexExporter) SetRedactor(r *redact.Redactor) {
+	e.redactor = r
+}
+
+// indexDocument is a source file with its definitions
+type indexDocument struct {
+	path        string // Relative to the analyzed directory
+	language    string
+	definitions []indexDefinition
+}
+
+// indexDefinition is a symbol definition with its SCIP symbol string
+type indexDefinition struct {
+	tag           Tag
+	symbol        string
+	enclosing     string   // Symbol of the enclosing type, if any
+	declaration   string   // Source line, unless it holds a secret
+	documentation string   // Doc comments
+	implements    []string // Symbols of the types it extends or implements
+}
+
+// documents collects the definitions of every file, in path and line order
+func (e *IndexExporter) documents() []indexDocument {
+	generator := NewTagGenerator(e.graph, e.targetDir)
+	generator.SetRedactor(e.redactor)
+	tags := generator.Tags()
+
+	root := scipPackage(e.targetDir)
+	symbols := make(map[types.SymbolId]string, len(tags))
+	for _, tag := range tags {
+		symbols[tag.symbol.Id] = root + scipScope(tag) + scipDescriptor(tag)
+	}
+
+	// Supertypes come from the extends and implements edges between symbols
+	supertypes := make(map[types.SymbolId][]string)
+	for _, edge := range e.graph.Edges {
+		if edge.Type != string(RelationshipExtends) && edge.Type != string(RelationshipImplements) {
+			continue
+		}
+		from := types.SymbolId(strings.TrimPrefix(string(edge.From), "symbol-"))
+		if target, ok := symbols[types.SymbolId(strings.TrimPrefix(string(edge.To), "symbol-"))]; ok {
+			supertypes[from] = append(supertypes[from], target)
+		}
+	}
+
+	var documents []indexDocument
+	for _, tag := range tags {
+		if len(documents) == 0 || documents[len(documents)-1].path != tag.File {
+			documents = append(documents, indexDocument{path: tag.File, language: tag.Language})
+		}
+		definition := indexDefinition{
+			tag:           tag,
+			symbol:        symbols[tag.symbol.Id],
+			declaration:   strings.TrimSpace(tag.Text),
+			documentation: e.redactor.Text(strings.TrimSpace(tag.symbol.Documentation)),
+		}
+		if tag.Scope != "" {
+			definition.enclosing = root + scipScope(tag)
+		}
+		implements := supertypes[tag.symbol.Id]
+		sort.Strings(implements)
+		for i, symbol := range implements {
+			if i == 0 || symbol != implements[i-1] {
+				definition.implements = append(definition.implements, symbol)
+			}
+		}
+		last := &documents[len(documents)-1]
+		last.definitions = append(last.definitions, definition)
+	}
+	return documents
+}
+
+// projectRoot returns the file URI of the analyzed directory, with a trailing slash
+func (e *IndexExporter) projectRoot() string {
+	root, err := filepath.Abs(e.targetDir)
+	if err != nil {
+		root = e.targetDir
+	}
+	return "file://" + strings.TrimSuffix(filepath.ToSlash(root), "/") + "/"
+}
+
+// scipPackage returns the scheme and package of the project's symbols, named
+// after the analyzed directory
+func scipPackage(targetDir string) string {
+	name := "."
+	if root, err := filepath.Abs(targetDir); err == nil && filepath.Base(root) != string(filepath.Separator) {
+		name = strings.ReplaceAll(filepath.Base(root), " ", "  ")
+	}
+	return IndexScheme + " . " + name + " . "
+}
+
+// scipScope returns the descriptors of a tag's file and enclosing scopes
+func scipScope(tag Tag) string {
+	descriptors := scipName(tag.File) + "/"
+	if tag.Scope == "" {
+		return descriptors
+	}
+	parts := strings.Split(tag.Scope, "::")
+	for i, part := range parts {
+		if i == len(parts)-1 && tag.ScopeKind != "namespace" {
+			descriptors += scipName(part) + "#"
+		} else {
+			descriptors += scipName(part) + "/"
+		}
+	}
+	return descriptors
+}
+
+// scipDescriptor returns the descriptor of a tag itself, whose suffix tells
+// types, methods, namespaces, macros and terms apart
+func scipDescriptor(tag Tag) string {
+	name := scipName(tag.Name)
+	switch tag.Kind {
+	case "c", "s", "i", "g", "t":
+		return name + "#"
+	case "f", "m":
+		return name + "()."
+	case "n":
+		return name + "/"
+	case "d":
+		return name + "!"
+	default:
+		return name + "."
+	}
+}
+
+var scipSimpleName = regexp.MustCompile(`^[A-Za-z0-9_+\-$]+$`)
+
+// scipName escapes a name that is not a simple identifier in backticks
+func scipName(name string) string {
+	if scipSimpleName.MatchString(name) {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIndexProject(t *testing.T) (string, *IndexExporter) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "shop")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	source := `package shop
+
+type Pricer interface {
+	Price() int
+}
+
+type Cart struct {
+	Items []int
+}
+
+func (c *Cart) Price() int {
+	return len(c.Items)
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cart.go"), []byte(source), 0644))
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	exporter := NewIndexExporter(graph, dir)
+	exporter.SetVersion("1.0.0")
+	return dir, exporter
+}
+
+func TestIndexSymbols(t *testing.T) {
+	_, exporter := writeIndexProject(t)
+	documents := exporter.documents()
+	require.Len(t, documents, 1)
+	assert.Equal(t, "cart.go", documents[0].path)
+
+	bySymbol := make(map[string]indexDefinition)
+	for _, definition := range documents[0].definitions {
+		bySymbol[definition.symbol] = definition
+	}
+	price, ok := bySymbol["codecontext . shop . `cart.go`/Cart#Price()."]
+	require.True(t, ok, "methods are scoped by their receiver: %v", bySymbol)
+	assert.Equal(t, "codecontext . shop . `cart.go`/Cart#", price.enclosing)
+	assert.Equal(t, "func (c *Cart) Price() int {", price.declaration)
+	assert.Equal(t, 15, price.tag.column)
+
+	cart := bySymbol["codecontext . shop . `cart.go`/Cart#"]
+	assert.Equal(t, []string{"codecontext . shop . `cart.go`/Pricer#"}, cart.implements)
+
+	assert.Equal(t, "`a/b.go`", scipName("a/b.go"))
+	assert.Equal(t, "`x``y`", scipName("x`y"))
+	assert.Equal(t, "Vec", scipName("Vec"))
+	assert.Equal(t, "`shop.go`/math/Vec#", scipScope(Tag{File: "shop.go", Scope: "math::Vec", ScopeKind: "class"}))
+}
+
+func TestWriteSCIP(t *testing.T) {
+	dir, exporter := writeIndexProject(t)
+	var out bytes.Buffer
+	require.NoError(t, exporter.WriteSCIP(&out))
+
+	index := protoFields(t, out.Bytes())
+	require.Len(t, index[scipIndexMetadata], 1)
+	metadata := protoFields(t, index[scipIndexMetadata][0])
+	assert.Equal(t, "file://"+filepath.ToSlash(dir)+"/", string(metadata[scipMetadataProjectRoot][0]))
+	toolInfo := protoFields(t, metadata[scipMetadataToolInfo][0])
+	assert.Equal(t, "codecontext", string(toolInfo[scipToolInfoName][0]))
+	assert.Equal(t, "1.0.0", string(toolInfo[scipToolInfoVersion][0]))
+
+	require.Len(t, index[scipIndexDocuments], 1)
+	document := protoFields(t, index[scipIndexDocuments][0])
+	assert.Equal(t, "cart.go", string(document[scipDocumentRelativePath][0]))
+	assert.Equal(t, "Go", string(document[scipDocumentLanguage][0]))
+	assert.Len(t, document[scipDocumentOccurrences], 3)
+	assert.Len(t, document[scipDocumentSymbols], 3)
+
+	var found bool
+	for _, raw := range document[scipDocumentOccurrences] {
+		occurrence := protoFields(t, raw)
+		if string(occurrence[scipOccurrenceSymbol][0]) != "codecontext . shop . `cart.go`/Cart#" {
+			continue
+		}
+		found = true
+		assert.Equal(t, []uint64{6, 5, 9}, protoVarints(t, occurrence[scipOccurrenceRange][0]), "zero-based line, start and end column")
+	}
+	assert.True(t, found)
+
+	for _, raw := range document[scipDocumentSymbols] {
+		information := protoFields(t, raw)
+		if string(information[scipSymbolDisplayName][0]) != "Cart" {
+			continue
+		}
+		assert.Equal(t, "```go\ntype Cart struct {\n```", string(information[scipSymbolDocumentation][0]))
+		relationship := protoFields(t, information[scipSymbolRelationships][0])
+		assert.Equal(t, "codecontext . shop . `cart.go`/Pricer#", string(relationship[scipRelationshipSymbol][0]))
+	}
+}
+
+func TestWriteLSIF(t *testing.T) {
+	_, exporter := writeIndexProject(t)
+	var out bytes.Buffer
+	require.NoError(t, exporter.WriteLSIF(&out))
+
+	var elements []map[string]interface{}
+	labels := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var element map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &element))
+		elements = append(elements, element)
+		if element["type"] == "vertex" {
+			labels[element["label"].(string)]++
+		}
+	}
+	assert.Equal(t, "metaData", elements[0]["label"])
+	assert.Equal(t, LSIFVersion, elements[0]["version"])
+	assert.Equal(t, 1, labels["document"])
+	assert.Equal(t, 3, labels["range"])
+	assert.Equal(t, 3, labels["definitionResult"])
+	assert.Equal(t, 3, labels["moniker"])
+	assert.Equal(t, 1, labels["implementationResult"])
+
+	// Vertices are emitted before the edges that connect them
+	seen := make(map[float64]bool)
+	for _, element := range elements {
+		if element["type"] == "edge" {
+			ids := []interface{}{element["outV"]}
+			if inV, ok := element["inV"]; ok {
+				ids = append(ids, inV)
+			}
+			if inVs, ok := element["inVs"].([]interface{}); ok {
+				ids = append(ids, inVs...)
+			}
+			for _, id := range ids {
+				assert.True(t, seen[id.(float64)], "edge %v refers to a later vertex", element)
+			}
+		}
+		seen[element["id"].(float64)] = true
+	}
+}
+
+// protoFields decodes the varint and length-delimited fields of a protobuf message
+func protoFields(t *testing.T, b []byte) map[int][][]byte {
+	t.Helper()
+	fields := make(map[int][][]byte)
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		require.Positive(t, n)
+		b = b[n:]
+		switch key & 7 {
+		case 0:
+			_, n = binary.Uvarint(b)
+			require.Positive(t, n)
+			fields[int(key>>3)] = append(fields[int(key>>3)], b[:n])
+			b = b[n:]
+		case 2:
+			length, n := binary.Uvarint(b)
+			require.Positive(t, n)
+			b = b[n:]
+			fields[int(key>>3)] = append(fields[int(key>>3)], b[:length])
+			b = b[length:]
+		default:
+			t.Fatalf("unexpected wire type %d", key&7)
+		}
+	}
+	return fields
+}
+
+// protoVarints decodes a packed repeated varint field
+func protoVarints(t *testing.T, b []byte) []uint64 {
+	t.Helper()
+	var values []uint64
+	for len(b) > 0 {
+		value, n := binary.Uvarint(b)
+		require.Positive(t, n)
+		values = append(values, value)
+		b = b[n:]
+	}
+	return values
+}
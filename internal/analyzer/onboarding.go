@@ -0,0 +1,397 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/buildsys"
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/internal/query"
+	"github.com/nuthan-ms/codecontext/internal/rank"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Onboarding document limits keep the output concise
+const (
+	maxReadmeParagraphs  = 3
+	maxOnboardingKeyFile = 10
+	maxTasksPerCategory  = 5
+)
+
+// OnboardingGenerator synthesizes a "how this repo works" guide for new contributors
+type OnboardingGenerator struct {
+	graph     *types.CodeGraph
+	targetDir string
+}
+
+// NewOnboardingGenerator creates an onboarding generator for an analyzed directory
+func NewOnboardingGenerator(graph *types.CodeGraph, targetDir string) *OnboardingGenerator {
+	return &OnboardingGenerator{graph: graph, targetDir: targetDir}
+}
+
+// Generate produces the onboarding document in markdown format
+func (og *OnboardingGenerator) Generate() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# How %s Works\n\n", og.projectName()))
+	sb.WriteString("*A quick orientation for new contributors and coding agents, generated by CodeContext.*\n\n")
+
+	sections := []string{
+		og.generateAbout(),
+		og.generateTechStack(),
+		og.generateLayout(),
+		og.generateLayers(),
+		og.generateKeyFiles(),
+		og.generateTasks(),
+		og.generateBuildTargets(),
+		og.generateNextSteps(),
+	}
+	for _, section := range sections {
+		if section != "" {
+			sb.WriteString(section)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// projectName derives a display name from the target directory
+func (og *OnboardingGenerator) projectName() string {
+	if abs, err := filepath.Abs(og.targetDir); err == nil {
+		return filepath.Base(abs)
+	}
+	return "This Repository"
+}
+
+// generateAbout summarizes the opening paragraphs of the README
+func (og *OnboardingGenerator) generateAbout() string {
+	for _, name := range []string{"README.md", "README", "README.rst", "readme.md"} {
+		content, err := os.ReadFile(filepath.Join(og.targetDir, name))
+		if err != nil {
+			continue
+		}
+		paragraphs := readmeParagraphs(string(content), maxReadmeParagraphs)
+		if len(paragraphs) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("## 📖 What It Is\n\n%s\n\n*From `%s`.*\n", strings.Join(paragraphs, "\n\n"), name)
+	}
+	return ""
+}
+
+// readmeParagraphs returns the first prose paragraphs, skipping headings, badges, code and HTML
+func readmeParagraphs(content string, limit int) []string {
+	var paragraphs []string
+	var current []string
+	inCode := false
+
+	flush := func() {
+		if len(current) > 0 {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			flush()
+			continue
+		}
+		if inCode {
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "<") ||
+			strings.HasPrefix(trimmed, "[![") || strings.HasPrefix(trimmed, "![") ||
+			strings.HasPrefix(trimmed, "|") || strings.HasPrefix(trimmed, "---") {
+			flush()
+			if len(paragraphs) >= limit {
+				break
+			}
+			continue
+		}
+		current = append(current, trimmed)
+	}
+	flush()
+
+	if len(paragraphs) > limit {
+		paragraphs = paragraphs[:limit]
+	}
+	return paragraphs
+}
+
+// generateTechStack lists languages by file count and detected frameworks
+func (og *OnboardingGenerator) generateTechStack() string {
+	if len(og.graph.Files) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 🧰 Tech Stack\n\n")
+
+	for _, entry := range rank.Top(og.graph.Metadata.Languages, 0).Entries {
+		sb.WriteString(fmt.Sprintf("- **%s** — %d files\n", entry.Key, entry.Count))
+	}
+
+	detector := parser.NewFrameworkDetector(og.targetDir)
+	frameworks := make(map[string]int)
+	for path, file := range og.graph.Files {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if framework := detector.DetectFramework(path, file.Language, string(content)); framework != "" {
+			frameworks[framework]++
+		}
+	}
+	if len(frameworks) > 0 {
+		names := make([]string, 0, len(frameworks))
+		for _, entry := range rank.Top(frameworks, 0).Entries {
+			names = append(names, fmt.Sprintf("%s (%d files)", entry.Key, entry.Count))
+		}
+		sb.WriteString(fmt.Sprintf("\n**Frameworks:** %s\n", strings.Join(names, ", ")))
+	}
+
+	return sb.String()
+}
+
+// generateLayout describes the top-level directories and what they contain
+func (og *OnboardingGenerator) generateLayout() string {
+	type dirStats struct {
+		files     int
+		symbols   int
+		tests     int
+		languages map[string]int
+	}
+
+	dirs := make(map[string]*dirStats)
+	for path, file := range og.graph.Files {
+		top := "(root)"
+		if parts := strings.Split(og.relativePath(path), "/"); len(parts) > 1 {
+			top = parts[0] + "/"
+		}
+		stats := dirs[top]
+		if stats == nil {
+			stats = &dirStats{languages: make(map[string]int)}
+			dirs[top] = stats
+		}
+		stats.files++
+		stats.symbols += file.SymbolCount
+		stats.languages[file.Language]++
+		if file.IsTest {
+			stats.tests++
+		}
+	}
+	if len(dirs) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(dirs))
+	for name := range dirs {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if dirs[names[i]].files != dirs[names[j]].files {
+			return dirs[names[i]].files > dirs[names[j]].files
+		}
+		return names[i] < names[j]
+	})
+
+	var sb strings.Builder
+	sb.WriteString("## 🗺️ Repository Layout\n\n")
+	sb.WriteString("| Directory | Files | Symbols | Main Language | Role |\n")
+	sb.WriteString("|-----------|-------|---------|---------------|------|\n")
+	for _, name := range names {
+		stats := dirs[name]
+		mainLanguage := ""
+		if top := rank.Top(stats.languages, 1).Entries; len(top) > 0 {
+			mainLanguage = top[0].Key
+		}
+		role := directoryRole(name)
+		if role == "" && stats.tests*2 > stats.files {
+			role = "tests"
+		}
+		sb.WriteString(fmt.Sprintf("| `%s` | %d | %d | %s | %s |\n", name, stats.files, stats.symbols, mainLanguage, role))
+	}
+
+	return sb.String()
+}
+
+// directoryRole guesses a directory's purpose from conventional names
+func directoryRole(dir string) string {
+	switch strings.TrimSuffix(strings.ToLower(dir), "/") {
+	case "cmd", "bin", "app", "apps":
+		return "entry points"
+	case "internal", "src", "lib", "pkg", "core":
+		return "core code"
+	case "test", "tests", "spec", "__tests__", "e2e":
+		return "tests"
+	case "docs", "doc", "documentation":
+		return "documentation"
+	case "scripts", "tools", "hack":
+		return "tooling"
+	case "examples", "example", "samples":
+		return "examples"
+	case "config", "configs", "deploy", "deployments", ".github":
+		return "configuration"
+	case "web", "frontend", "ui", "client":
+		return "frontend"
+	case "api", "server", "backend":
+		return "backend"
+	}
+	return ""
+}
+
+// generateLayers draws the architectural layers inferred from directory names
+// and dependency direction, top to bottom, once at least two are detected
+func (og *OnboardingGenerator) generateLayers() string {
+	architecture := InferLayers(og.graph)
+	if len(architecture.Layers) < 2 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 🏛️ Layers\n\n")
+	sb.WriteString("Code is layered from top to bottom; each layer should only depend on the layers below it:\n\n")
+	for i, layer := range architecture.Layers {
+		packages := layer.Packages
+		more := ""
+		if len(packages) > maxOnboardingKeyFile {
+			more = fmt.Sprintf(" (+%d more)", len(packages)-maxOnboardingKeyFile)
+			packages = packages[:maxOnboardingKeyFile]
+		}
+		sb.WriteString(fmt.Sprintf("%d. **%s** — `%s`%s\n", i+1, layer.Name, strings.Join(packages, "`, `"), more))
+	}
+	if len(architecture.Violations) > 0 {
+		sb.WriteString(fmt.Sprintf("\n⚠️ %d dependencies go against this order, e.g. `%s` (%s) → `%s` (%s).\n",
+			len(architecture.Violations), architecture.Violations[0].From, architecture.Violations[0].FromLayer,
+			architecture.Violations[0].To, architecture.Violations[0].ToLayer))
+	}
+	return sb.String()
+}
+
+// generateKeyFiles lists entry points and the most depended-upon files
+func (og *OnboardingGenerator) generateKeyFiles() string {
+	// Files whose content starts a program, command, handler or server
+	var entryPoints []string
+	kinds := make(map[string][]string)
+	for _, entryPoint := range FindEntryPoints(og.graph) {
+		path := og.relativePath(entryPoint.File)
+		if kinds[path] == nil {
+			entryPoints = append(entryPoints, path)
+		}
+		kinds[path] = append(kinds[path], entryPoint.Kind)
+	}
+	sort.Strings(entryPoints)
+	if len(entryPoints) > maxOnboardingKeyFile {
+		entryPoints = entryPoints[:maxOnboardingKeyFile]
+	}
+
+	var hotspots []FileHotspot
+	if metrics, ok := og.graph.Metadata.Configuration["relationship_metrics"].(*RelationshipMetrics); ok && metrics != nil {
+		hotspots = metrics.HotspotFiles
+		if len(hotspots) > maxOnboardingKeyFile {
+			hotspots = hotspots[:maxOnboardingKeyFile]
+		}
+	}
+
+	if len(entryPoints) == 0 && len(hotspots) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 🔑 Key Files\n\n")
+	if len(entryPoints) > 0 {
+		sb.WriteString("**Entry points** — start reading here:\n\n")
+		for _, path := range entryPoints {
+			sb.WriteString(fmt.Sprintf("- `%s` (%s)\n", path, strings.Join(kinds[path], ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	if len(hotspots) > 0 {
+		sb.WriteString("**Most connected files** — changes here ripple widely:\n\n")
+		for _, hotspot := range hotspots {
+			sb.WriteString(fmt.Sprintf("- `%s` (%d imports, %d references)\n",
+				og.relativePath(hotspot.FilePath), hotspot.ImportCount, hotspot.ReferenceCount))
+		}
+	}
+
+	return sb.String()
+}
+
+// generateTasks shows how to build, test and lint the project
+func (og *OnboardingGenerator) generateTasks() string {
+	tasks, err := buildsys.ScanTasks(og.targetDir)
+	if err != nil || len(tasks) == 0 {
+		return ""
+	}
+
+	byCategory := make(map[string][]*buildsys.Task)
+	for _, task := range tasks {
+		byCategory[task.Category] = append(byCategory[task.Category], task)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 🛠️ Common Tasks\n\n")
+	for _, category := range []string{buildsys.CategoryBuild, buildsys.CategoryTest, buildsys.CategoryLint, buildsys.CategoryFormat, buildsys.CategoryRun} {
+		categoryTasks := byCategory[category]
+		if len(categoryTasks) == 0 {
+			continue
+		}
+		if len(categoryTasks) > maxTasksPerCategory {
+			categoryTasks = categoryTasks[:maxTasksPerCategory]
+		}
+		sb.WriteString(fmt.Sprintf("**%s:**\n\n", strings.ToUpper(category[:1])+category[1:]))
+		for _, task := range categoryTasks {
+			line := fmt.Sprintf("- `%s`", task.Invocation)
+			if task.Description != "" {
+				line += " — " + task.Description
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// generateBuildTargets summarizes the build targets declared in the repository
+func (og *OnboardingGenerator) generateBuildTargets() string {
+	index, err := buildsys.Scan(og.targetDir)
+	if err != nil || len(index.Targets) == 0 {
+		return ""
+	}
+
+	counts := make(map[string]int)
+	for _, target := range index.Targets {
+		counts[fmt.Sprintf("%s %s", target.System, target.Kind)]++
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 🏗️ Build Targets\n\n")
+	for _, entry := range rank.Top(counts, 0).Entries {
+		sb.WriteString(fmt.Sprintf("- %d × %s\n", entry.Count, entry.Key))
+	}
+	sb.WriteString("\nUse the `get_build_targets` MCP tool to see which targets a change affects.\n")
+	return sb.String()
+}
+
+// generateNextSteps points newcomers at the next things to explore
+func (og *OnboardingGenerator) generateNextSteps() string {
+	return `## 🚀 Next Steps
+
+1. Skim the entry points above to see how the program starts.
+2. Run the test task to confirm your environment works.
+3. Use ` + "`codecontext generate`" + ` for the full context map, or the MCP tools (` + "`search_symbols`, `get_dependencies`" + `) to explore specific areas.
+`
+}
+
+// relativePath renders a graph path relative to the target directory
+func (og *OnboardingGenerator) relativePath(path string) string {
+	return filepath.ToSlash(query.RelativePath(path, og.targetDir))
+}
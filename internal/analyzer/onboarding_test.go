@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+)
+
+func TestReadmeParagraphs(t *testing.T) {
+	readme := "# Tool\n\n[![CI](badge.svg)](ci)\n\nTool turns code into context\nfor assistants.\n\n```bash\nmake install\n```\n\n## Usage\n\nRun it.\n\nThird.\n\nFourth.\n"
+
+	paragraphs := readmeParagraphs(readme, 3)
+	expected := []string{"Tool turns code into context for assistants.", "Run it.", "Third."}
+	if len(paragraphs) != len(expected) {
+		t.Fatalf("Expected %d paragraphs, got %d: %v", len(expected), len(paragraphs), paragraphs)
+	}
+	for i := range expected {
+		if paragraphs[i] != expected[i] {
+			t.Errorf("Paragraph %d = %q, want %q", i, paragraphs[i], expected[i])
+		}
+	}
+}
+
+func TestOnboardingGenerator(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"README.md":                     "# Demo\n\nDemo service that greets people.\n",
+		"Makefile":                      "test: ## Run tests\n\tgo test ./...\n\nbuild:\n\tgo build ./cmd/demo\n",
+		"go.mod":                        "module example.com/demo\n\ngo 1.22\n",
+		"cmd/demo/main.go":              "package main\n\nfunc main() {}\n",
+		"internal/greet.go":             "package internal\n\nfunc Greet(name string) string { return name }\n",
+		"internal/greet_test.go":        "package internal\n\nimport \"testing\"\n\nfunc TestGreet(t *testing.T) {}\n",
+		"internal/handlers/hello.go":    "package handlers\n\nimport \"example.com/demo/internal/services\"\n\nfunc Hello() string { return services.Greeting() }\n",
+		"internal/services/greeting.go": "package services\n\nfunc Greeting() string { return \"hello\" }\n",
+	}
+	testutils.WriteTree(t, tempDir, files)
+
+	builder := NewGraphBuilder()
+	graph, err := builder.AnalyzeDirectory(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %v", err)
+	}
+
+	doc := NewOnboardingGenerator(graph, tempDir).Generate()
+
+	for _, want := range []string{
+		"## 📖 What It Is",
+		"Demo service that greets people.",
+		"## 🧰 Tech Stack",
+		"## 🗺️ Repository Layout",
+		"| `internal/` |",
+		"## 🔑 Key Files",
+		"`cmd/demo/main.go` (main)",
+		"## 🏛️ Layers",
+		"1. **ui** — `cmd/demo`, `internal/handlers`",
+		"2. **application** — `internal/services`",
+		"## 🛠️ Common Tasks",
+		"`make test` — Run tests",
+		"`make build`",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Onboarding document missing %q\n%s", want, doc)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// panicPatterns match the statements of one language that abort the current
+// function and the constructs that stop a panic or exception on its way to
+// the caller
+type panicPatterns struct {
+	panic   *regexp.Regexp
+	recover *regexp.Regexp
+}
+
+var (
+	goPanicPatterns = panicPatterns{
+		panic:   regexp.MustCompile(`\bpanic\(`),
+		recover: regexp.MustCompile(`\brecover\(\)`),
+	}
+	pythonPanicPatterns = panicPatterns{
+		panic:   regexp.MustCompile(`^\s*raise\b`),
+		recover: regexp.MustCompile(`^\s*except\b`),
+	}
+	throwPanicPatterns = panicPatterns{
+		panic:   regexp.MustCompile(`\bthrow\s+\S`),
+		recover: regexp.MustCompile(`\bcatch\b|\.catch\(`),
+	}
+
+	// panicPatternsByLanguage holds the languages the analysis covers
+	panicPatternsByLanguage = map[string]panicPatterns{
+		"go":         goPanicPatterns,
+		"python":     pythonPanicPatterns,
+		"javascript": throwPanicPatterns,
+		"typescript": throwPanicPatterns,
+		"java":       throwPanicPatterns,
+	}
+
+	// Call sites; the names are matched against the functions in the graph
+	callSitePattern = regexp.MustCompile(`\b([A-Za-z_]\w*)\s*\(`)
+)
+
+// PanicEscape is a panic, throw or raise that no caller recovers from
+type PanicEscape struct {
+	Function  string   `json:"function"`
+	File      string   `json:"file"`
+	Line      int      `json:"line"`      // Line of the panic or throw
+	Statement string   `json:"statement"` // The panicking line, trimmed
+	Callers   []string `json:"callers"`   // Functions the panic passes through unrecovered, nearest first
+}
+
+// panicFunction is what a function body contributes to panic escape analysis
+type panicFunction struct {
+	symbol    *types.Symbol
+	file      string
+	language  string
+	panicLine int
+	statement string
+	recovers  bool
+	calls     []string
+}
+
+// AnalyzePanicEscapes finds functions that panic or throw without recovering
+// and follows their callers: a panic escapes when no function on the way up
+// the call graph recovers (panic and recover in Go, raise and except in
+// Python, throw and catch in JavaScript, TypeScript and Java). Calls are
+// matched to functions by name within a language, so the result is a hint to
+// review rather than a proof.
+func AnalyzePanicEscapes(graph *types.CodeGraph) []PanicEscape {
+	ra := &RelationshipAnalyzer{graph: graph}
+	functions := make(map[types.SymbolId]*panicFunction)
+	ra.forEachSourceFile(func(filePath, content string) {
+		language := graph.Files[filePath].Language
+		patterns, ok := panicPatternsByLanguage[language]
+		if !ok {
+			return
+		}
+		commentPrefix := "//"
+		if language == "python" {
+			commentPrefix = "#"
+		}
+		for i, line := range strings.Split(content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, commentPrefix) || strings.HasPrefix(trimmed, "*") {
+				continue
+			}
+			symbol := ra.enclosingSymbol(filePath, i+1)
+			if symbol == nil {
+				continue
+			}
+			fn := functions[symbol.Id]
+			if fn == nil {
+				fn = &panicFunction{symbol: symbol, file: filePath, language: language}
+				functions[symbol.Id] = fn
+			}
+			if patterns.recover.MatchString(line) {
+				fn.recovers = true
+			}
+			if fn.panicLine == 0 && patterns.panic.MatchString(line) {
+				fn.panicLine = i + 1
+				fn.statement = trimmed
+			}
+			if i+1 == symbol.Location.StartLine {
+				continue // The declaration itself
+			}
+			for _, m := range callSitePattern.FindAllStringSubmatch(line, -1) {
+				fn.calls = appendUnique(fn.calls, m[1])
+			}
+		}
+	})
+
+	// Callers by language and callee name
+	callers := make(map[string][]*panicFunction)
+	for _, fn := range functions {
+		for _, callee := range fn.calls {
+			if callee != fn.symbol.Name {
+				callers[fn.language+":"+callee] = append(callers[fn.language+":"+callee], fn)
+			}
+		}
+	}
+	for _, list := range callers {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].file != list[j].file {
+				return list[i].file < list[j].file
+			}
+			return list[i].symbol.Location.StartLine < list[j].symbol.Location.StartLine
+		})
+	}
+
+	var escapes []PanicEscape
+	for _, fn := range functions {
+		if fn.panicLine == 0 || fn.recovers {
+			continue
+		}
+		// Walk up the callers; a recovering caller stops the panic on that path
+		var passedThrough []string
+		recovered := false
+		visited := map[*panicFunction]bool{fn: true}
+		queue := []*panicFunction{fn}
+		for len(queue) > 0 && !recovered {
+			current := queue[0]
+			queue = queue[1:]
+			for _, caller := range callers[current.language+":"+current.symbol.Name] {
+				if visited[caller] {
+					continue
+				}
+				visited[caller] = true
+				if caller.recovers {
+					recovered = true
+					break
+				}
+				passedThrough = append(passedThrough, caller.symbol.Name)
+				queue = append(queue, caller)
+			}
+		}
+		if recovered {
+			continue
+		}
+		escapes = append(escapes, PanicEscape{
+			Function:  fn.symbol.Name,
+			File:      fn.file,
+			Line:      fn.panicLine,
+			Statement: fn.statement,
+			Callers:   passedThrough,
+		})
+	}
+	sort.Slice(escapes, func(i, j int) bool {
+		if len(escapes[i].Callers) != len(escapes[j].Callers) {
+			return len(escapes[i].Callers) > len(escapes[j].Callers)
+		}
+		if escapes[i].File != escapes[j].File {
+			return escapes[i].File < escapes[j].File
+		}
+		return escapes[i].Line < escapes[j].Line
+	})
+	return escapes
+}
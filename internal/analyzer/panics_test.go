@@ -0,0 +1,134 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePanicEscapes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.go": `package app
+
+// MustLoad panics when the config is invalid
+func MustLoad(path string) *Config {
+	cfg, err := parse(path)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func Start() {
+	cfg := MustLoad("app.yaml")
+	run(cfg)
+}
+
+func Index(i int) int {
+	if i < 0 {
+		panic("negative index")
+	}
+	return i
+}
+
+func SafeIndex(i int) (n int) {
+	defer func() {
+		if recover() != nil {
+			n = -1
+		}
+	}()
+	return Index(i)
+}
+`,
+		"client.js": `function fetchUser(id) {
+  if (!id) {
+    throw new Error('id is required');
+  }
+  return api.get(id);
+}
+
+function loadProfile(id) {
+  try {
+    return fetchUser(id);
+  } catch (err) {
+    return null;
+  }
+}
+`,
+		"worker.py": `def process(job):
+    if job is None:
+        raise ValueError("no job")
+    return job.run()
+
+
+def handle(job):
+    return process(job)
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	escapes := AnalyzePanicEscapes(graph)
+	byFunction := make(map[string]PanicEscape)
+	for _, escape := range escapes {
+		byFunction[escape.Function] = escape
+	}
+	require.Len(t, byFunction, 2, "Index is recovered by SafeIndex and fetchUser is caught by loadProfile: %+v", escapes)
+
+	assert.Equal(t, PanicEscape{Function: "MustLoad", File: filepath.Join(dir, "config.go"), Line: 7, Statement: "panic(err)", Callers: []string{"Start"}}, byFunction["MustLoad"])
+	assert.Equal(t, 3, byFunction["process"].Line)
+	assert.Equal(t, []string{"handle"}, byFunction["process"].Callers)
+
+	markdown := NewMarkdownGenerator(graph).GenerateContextMap()
+	assert.Contains(t, markdown, "## 🛡️ Reliability")
+	assert.Contains(t, markdown, "| `MustLoad` | `config.go:7` | `panic(err)` | `Start` |")
+}
+
+func TestPanicPatternsByLanguage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Python and JavaScript keywords are ordinary identifiers in Go
+		"retry.go": `package app
+
+func Retry(n int) int {
+	raise := n * 2
+	throw := raise + 1
+	return throw
+}
+
+func Guard(n int) int {
+	catch := Must(n)
+	return catch
+}
+
+func Must(n int) int {
+	if n < 0 {
+		panic("negative")
+	}
+	return n
+}
+`,
+		// Go's panic is an ordinary function in Python
+		"views.py": `def render(request):
+    panic(request)
+    return request
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	escapes := AnalyzePanicEscapes(graph)
+	require.Len(t, escapes, 1, "only Must panics: %+v", escapes)
+	assert.Equal(t, "Must", escapes[0].Function)
+	assert.Equal(t, []string{"Guard"}, escapes[0].Callers, "a variable named catch does not recover")
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+var goPackageClause = regexp.MustCompile(`(?m)^package\s+(\w+)`)
+
+// PopularityOptions filters RankSymbolPopularity
+type PopularityOptions struct {
+	Kind    string // Symbol kind or type, e.g. "function" (default: all)
+	Package string // Directory relative to the project root, subdirectories included
+	Prefix  string // Case-insensitive start of the name
+}
+
+// PopularSymbol is a symbol with how widely the rest of the code uses it
+type PopularSymbol struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	File       string `json:"file"` // Relative to the project root
+	Line       int    `json:"line"`
+	Package    string `json:"package"` // Directory of the file, "." for the root
+	Signature  string `json:"signature,omitempty"`
+	References int    `json:"references"` // Uses in other non-test files
+	Files      int    `json:"files"`      // Non-test files using it
+}
+
+// RankSymbolPopularity counts, for every symbol of a non-test file, the uses
+// of its name in the other non-test files depending on that file: those
+// importing it or its package, and the other files of its Go package. Names
+// listed in an import statement or a Go package clause are not counted as
+// uses. Local variables, main and init functions, and symbols nothing else
+// uses are left out. The most widely used symbols come first, by number of
+// files and then of references.
+func RankSymbolPopularity(graph *types.CodeGraph, root string, opts PopularityOptions) []PopularSymbol {
+	pkgFilter := strings.Trim(path.Clean("/"+strings.TrimSpace(opts.Package)), "/")
+	prefix := strings.ToLower(opts.Prefix)
+
+	dependents := make(map[string][]string)
+	for from, steps := range fileSteps(graph) {
+		if graph.Files[from].IsTest {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, step := range steps {
+			if step.to != from && !seen[step.to] {
+				seen[step.to] = true
+				dependents[step.to] = append(dependents[step.to], from)
+			}
+		}
+	}
+
+	contents := make(map[string]string)
+	read := func(file string) string {
+		if content, ok := contents[file]; ok {
+			return content
+		}
+		data, _ := os.ReadFile(file)
+		contents[file] = string(data)
+		return contents[file]
+	}
+	tokenCounts := make(map[string]map[string]int)
+	tokensOf := func(file string) map[string]int {
+		if counts, ok := tokenCounts[file]; ok {
+			return counts
+		}
+		counts := make(map[string]int)
+		for _, token := range refactorIdentifier.FindAllString(read(file), -1) {
+			counts[token]++
+		}
+		if match := goPackageClause.FindStringSubmatch(read(file)); match != nil && graph.Files[file].Language == "go" {
+			counts[match[1]]--
+		}
+		for _, imp := range graph.Files[file].Imports {
+			for _, specifier := range imp.Specifiers {
+				counts[specifier]--
+			}
+		}
+		tokenCounts[file] = counts
+		return counts
+	}
+
+	var ranked []PopularSymbol
+	for file, node := range graph.Files {
+		if node.IsTest || node.IsGenerated || len(dependents[file]) == 0 {
+			continue
+		}
+		rel := projectPath(root, file)
+		pkg := path.Dir(rel)
+		if pkgFilter != "" && pkg != pkgFilter && !strings.HasPrefix(pkg, pkgFilter+"/") {
+			continue
+		}
+		var symbols []*types.Symbol
+		for _, id := range node.Symbols {
+			if symbol := graph.Symbols[id]; symbol != nil && refactorSymbol(symbol) && !entryPointSymbols[symbol.Name] {
+				symbols = append(symbols, symbol)
+			}
+		}
+		if len(symbols) == 0 {
+			continue
+		}
+		// Locals are found from the functions around them, before filtering
+		for _, symbol := range withoutLocals(symbols, strings.Split(read(file), "\n")) {
+			if opts.Kind != "" && !symbol.MatchesKind(opts.Kind) {
+				continue
+			}
+			if prefix != "" && !strings.HasPrefix(strings.ToLower(symbol.Name), prefix) {
+				continue
+			}
+			popular := PopularSymbol{
+				Name:      symbol.Name,
+				Kind:      string(symbol.Type),
+				File:      rel,
+				Line:      symbol.Location.StartLine,
+				Package:   pkg,
+				Signature: symbol.Signature,
+			}
+			for _, dependent := range dependents[file] {
+				if uses := tokensOf(dependent)[symbol.Name]; uses > 0 {
+					popular.References += uses
+					popular.Files++
+				}
+			}
+			if popular.Files > 0 {
+				ranked = append(ranked, popular)
+			}
+		}
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Files != b.Files {
+			return a.Files > b.Files
+		}
+		if a.References != b.References {
+			return a.References > b.References
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return ranked
+}
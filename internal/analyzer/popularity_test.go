@@ -0,0 +1,55 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankSymbolPopularity(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                     "module example.com/app\n\ngo 1.24\n",
+		"internal/text/slug.go":      "package text\n\nconst Separator = \"-\"\n\nfunc Slugify(s string) string {\n\tvar result = s + Separator\n\treturn result\n}\n\nfunc Truncate(s string) string { return s }\n",
+		"internal/text/slug_test.go": "package text\n\nimport \"testing\"\n\nfunc TestTruncate(t *testing.T) { Truncate(\"a\"); Truncate(\"b\"); Truncate(\"c\") }\n",
+		"cmd/app/main.go":            "package main\n\nimport \"example.com/app/internal/text\"\n\nfunc main() {\n\tvar result = text.Slugify(\"a\") + text.Slugify(\"b\")\n\t_ = result\n}\n",
+		"cmd/app/flags.go":           "package main\n\nimport \"example.com/app/internal/text\"\n\nfunc flags() string { return text.Slugify(\"c\") + text.Truncate(\"d\") }\n",
+		"web/format.js":              "export function formatDate(d) { return String(d); }\nexport function pad(v) { return v; }\n",
+		"web/app.js":                 "import { formatDate, pad } from './format';\n\nexport function render() { return formatDate(1) + formatDate(2); }\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	ranked := RankSymbolPopularity(graph, dir, PopularityOptions{})
+	var names []string
+	for _, symbol := range ranked {
+		names = append(names, symbol.Name)
+	}
+	assert.Equal(t, []string{"Slugify", "formatDate", "Truncate"}, names, "imported but unused names, local variables and uses in tests do not count")
+	assert.Equal(t, PopularSymbol{
+		Name:       "Slugify",
+		Kind:       "function",
+		File:       "internal/text/slug.go",
+		Line:       5,
+		Package:    "internal/text",
+		Signature:  "func Slugify(s string) string",
+		References: 3,
+		Files:      2,
+	}, ranked[0])
+	assert.Equal(t, 2, ranked[1].References)
+
+	ranked = RankSymbolPopularity(graph, dir, PopularityOptions{Package: "web"})
+	require.Len(t, ranked, 1)
+	assert.Equal(t, "formatDate", ranked[0].Name)
+
+	ranked = RankSymbolPopularity(graph, dir, PopularityOptions{Prefix: "tr", Kind: "function"})
+	require.Len(t, ranked, 1)
+	assert.Equal(t, "Truncate", ranked[0].Name)
+
+	ranked = RankSymbolPopularity(graph, dir, PopularityOptions{Kind: "variable"})
+	assert.Empty(t, ranked, "local variables stay out when filtering by kind")
+	assert.Empty(t, RankSymbolPopularity(graph, dir, PopularityOptions{Kind: "class"}))
+}
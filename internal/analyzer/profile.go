@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/nuthan-ms/codecontext/internal/git"
+)
+
+// Profile names a bundle of analysis settings that trades depth for speed
+type Profile string
+
+const (
+	// ProfileFast parses Go, Python, JavaScript, TypeScript, Java and Rust with
+	// the regex parsers of the parser package, which find declarations and
+	// imports only, and links imports, inheritance and docs: no git history,
+	// neighborhood clustering, symbol usage or call edges
+	ProfileFast Profile = "fast"
+	// ProfileBalanced runs every analysis stage with the default thresholds
+	ProfileBalanced Profile = "balanced"
+	// ProfileDeep mines a longer git history, including doc and config files,
+	// for larger semantic neighborhoods, and embeds symbols to find similar ones
+	ProfileDeep Profile = "deep"
+)
+
+// Profiles lists the profile names in order of depth
+var Profiles = []Profile{ProfileFast, ProfileBalanced, ProfileDeep}
+
+// ParseProfile validates a profile name. An empty name selects balanced.
+func ParseProfile(name string) (Profile, error) {
+	if name == "" {
+		return ProfileBalanced, nil
+	}
+	for _, profile := range Profiles {
+		if string(profile) == name {
+			return profile, nil
+		}
+	}
+	return "", fmt.Errorf("unknown analysis profile %q (use fast, balanced or deep)", name)
+}
+
+// gitAnalysis reports whether the profile builds semantic neighborhoods from git history
+func (p Profile) gitAnalysis() bool {
+	return p != ProfileFast
+}
+
+// lightParsing reports whether the profile parses with the regex parsers
+// instead of tree-sitter where a language has one
+func (p Profile) lightParsing() bool {
+	return p == ProfileFast
+}
+
+// embeddings reports whether the profile builds the symbol embedding index
+func (p Profile) embeddings() bool {
+	return p == ProfileDeep
+}
+
+// symbolUsage reports whether the profile links symbol usages and calls
+func (p Profile) symbolUsage() bool {
+	return p != ProfileFast
+}
+
+// semanticConfig returns the profile's semantic analysis thresholds
+func (p Profile) semanticConfig() *git.SemanticConfig {
+	config := git.DefaultSemanticConfig()
+	if p == ProfileDeep {
+		config.AnalysisPeriodDays = 90
+		config.MaxNeighborhoodSize = 25
+		config.IncludeDocFiles = true
+		config.IncludeConfigFiles = true
+	}
+	return config
+}
@@ -0,0 +1,175 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Profile
+		wantErr bool
+	}{
+		{"", ProfileBalanced, false},
+		{"fast", ProfileFast, false},
+		{"balanced", ProfileBalanced, false},
+		{"deep", ProfileDeep, false},
+		{"thorough", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseProfile(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseProfile(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseProfile(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProfileSemanticConfig(t *testing.T) {
+	balanced := ProfileBalanced.semanticConfig()
+	deep := ProfileDeep.semanticConfig()
+	if deep.AnalysisPeriodDays <= balanced.AnalysisPeriodDays {
+		t.Errorf("deep period %d should exceed balanced %d", deep.AnalysisPeriodDays, balanced.AnalysisPeriodDays)
+	}
+	if deep.MaxNeighborhoodSize <= balanced.MaxNeighborhoodSize {
+		t.Errorf("deep neighborhoods %d should exceed balanced %d", deep.MaxNeighborhoodSize, balanced.MaxNeighborhoodSize)
+	}
+	if !deep.IncludeDocFiles || !deep.IncludeConfigFiles {
+		t.Error("deep profile should include doc and config files")
+	}
+}
+
+func TestSymbolUsageDisabled(t *testing.T) {
+	analyzer := NewRelationshipAnalyzer(createTestGraph())
+	analyzer.SetSymbolUsage(false)
+
+	metrics, err := analyzer.AnalyzeAllRelationships()
+	if err != nil {
+		t.Fatalf("AnalyzeAllRelationships() error = %v", err)
+	}
+	if metrics.ByType[RelationshipReferences] != 0 || metrics.ByType[RelationshipCalls] != 0 {
+		t.Errorf("expected no reference or call edges, got %v", metrics.ByType)
+	}
+	if metrics.ByType[RelationshipImport] == 0 {
+		t.Error("imports should still be analyzed")
+	}
+}
+
+func TestFastProfileSkipsGitAnalysis(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.ts"), []byte("export function main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := NewGraphBuilder()
+	builder.SetProfile(ProfileFast)
+	var messages []string
+	builder.SetProgressCallback(func(message string) {
+		messages = append(messages, message)
+	})
+
+	graph, err := builder.AnalyzeDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDirectory() error = %v", err)
+	}
+	if len(graph.Files) != 1 {
+		t.Errorf("expected 1 file, got %d", len(graph.Files))
+	}
+	if _, ok := graph.Metadata.Configuration["semantic_neighborhoods"]; ok {
+		t.Error("fast profile should not build semantic neighborhoods")
+	}
+	joined := strings.Join(messages, "\n")
+	if !strings.Contains(joined, "Git analysis skipped (fast profile)") || strings.Contains(joined, "Analyzing git history") {
+		t.Errorf("unexpected progress messages:\n%s", joined)
+	}
+}
+
+func TestLazySemanticDefersGitAnalysis(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.ts"), []byte("export function main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	builder := NewGraphBuilder()
+	builder.SetLazySemantic(true)
+	var messages []string
+	builder.SetProgressCallback(func(message string) {
+		messages = append(messages, message)
+	})
+
+	graph, err := builder.AnalyzeDirectory(tmpDir)
+	if err != nil {
+		t.Fatalf("AnalyzeDirectory() error = %v", err)
+	}
+	joined := strings.Join(messages, "\n")
+	if !strings.Contains(joined, "Git analysis deferred") || strings.Contains(joined, "Analyzing git history") {
+		t.Errorf("unexpected progress messages:\n%s", joined)
+	}
+
+	result, err := builder.AnalyzeSemanticNeighborhoods(tmpDir, graph, ProfileBalanced)
+	if err != nil {
+		t.Fatalf("AnalyzeSemanticNeighborhoods() error = %v", err)
+	}
+	if result.AnalysisMetadata.IsGitRepository {
+		t.Error("temp dir should not be reported as a git repository")
+	}
+	if _, err := builder.AnalyzeSemanticNeighborhoods(tmpDir, graph, ProfileFast); err == nil {
+		t.Error("fast profile should refuse semantic analysis")
+	}
+}
+
+func TestFastProfileParsesLightly(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "export function main() {\n  const local = 1;\n  return local;\n}\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.ts"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range []Profile{ProfileFast, ProfileBalanced} {
+		builder := NewGraphBuilder()
+		builder.SetProfile(profile)
+		graph, err := builder.AnalyzeDirectory(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzeDirectory(%s) error = %v", profile, err)
+		}
+		names := make(map[string]bool)
+		for _, symbol := range graph.Symbols {
+			names[symbol.Name] = true
+		}
+		if !names["main"] {
+			t.Errorf("%s profile should find main, got %v", profile, names)
+		}
+		// The regex parsers only see declarations, not locals
+		if names["local"] != (profile != ProfileFast) {
+			t.Errorf("%s profile: local variable found = %v", profile, names["local"])
+		}
+	}
+}
+
+func TestDeepProfileBuildsEmbeddings(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.ts"), []byte("export function main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range Profiles {
+		builder := NewGraphBuilder()
+		builder.SetProfile(profile)
+		graph, err := builder.AnalyzeDirectory(tmpDir)
+		if err != nil {
+			t.Fatalf("AnalyzeDirectory(%s) error = %v", profile, err)
+		}
+		index := Embeddings(graph)
+		if (index != nil) != (profile == ProfileDeep) {
+			t.Errorf("%s profile: embeddings built = %v", profile, index != nil)
+		}
+		if index != nil && len(index.Vectors) == 0 {
+			t.Error("deep profile should embed main")
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of pub dependencies
+const (
+	PubSourceHosted = "hosted"
+	PubSourceSDK    = "sdk"
+	PubSourcePath   = "path"
+	PubSourceGit    = "git"
+)
+
+var (
+	pubPinnedVersion = regexp.MustCompile(`^\d+\.\d+\.\d+(?:[-+][\w.+-]*)?$`)
+	dartQuoted       = regexp.MustCompile(`['"]([^'"$\s]+\.[A-Za-z0-9]+)['"]`)
+)
+
+// pubPackageCategories groups well-known packages by what they are used for
+var pubPackageCategories = map[string]string{
+	"flutter_bloc": "state", "bloc": "state", "provider": "state", "riverpod": "state", "flutter_riverpod": "state",
+	"hooks_riverpod": "state", "get": "state", "mobx": "state", "flutter_mobx": "state", "redux": "state",
+	"go_router": "navigation", "auto_route": "navigation", "beamer": "navigation",
+	"dio": "networking", "http": "networking", "retrofit": "networking", "chopper": "networking", "graphql_flutter": "networking",
+	"build_runner": "codegen", "freezed": "codegen", "freezed_annotation": "codegen", "json_serializable": "codegen",
+	"json_annotation": "codegen", "riverpod_generator": "codegen", "injectable_generator": "codegen", "flutter_gen_runner": "codegen",
+	"shared_preferences": "storage", "hive": "storage", "sqflite": "storage", "drift": "storage", "isar": "storage",
+	"get_it": "injection", "injectable": "injection",
+	"mockito": "testing", "mocktail": "testing", "bloc_test": "testing", "integration_test": "testing",
+}
+
+// PubDependency is a package a pubspec depends on
+type PubDependency struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint,omitempty"` // Version constraint, "any" for unconstrained hosted packages
+	Source     string `json:"source"`               // hosted, sdk, path or git
+	Location   string `json:"location,omitempty"`   // SDK name, path or git URL
+	Scope      string `json:"scope"`                // dependencies, dev_dependencies or dependency_overrides
+}
+
+// Style classifies how tightly a dependency is constrained: pinned, caret,
+// range or any for hosted packages, and its source otherwise
+func (d PubDependency) Style() string {
+	switch {
+	case d.Source != PubSourceHosted:
+		return d.Source
+	case d.Constraint == "any":
+		return "any"
+	case pubPinnedVersion.MatchString(d.Constraint):
+		return "pinned"
+	case strings.HasPrefix(d.Constraint, "^"):
+		return "caret"
+	}
+	return "range"
+}
+
+// Category returns what a well-known package is used for, or ""
+func (d PubDependency) Category() string {
+	if strings.HasPrefix(d.Name, "firebase_") || d.Name == "cloud_firestore" {
+		return "firebase"
+	}
+	return pubPackageCategories[d.Name]
+}
+
+// PubAsset is an entry of the flutter assets list of a pubspec and the code
+// loading its files
+type PubAsset struct {
+	Entry      string           `json:"entry"` // As declared, relative to the package
+	Flavors    []string         `json:"flavors,omitempty"`
+	Files      []string         `json:"files,omitempty"` // Relative to the project root
+	References []AssetReference `json:"references,omitempty"`
+	Missing    bool             `json:"missing,omitempty"` // The file or directory does not exist
+}
+
+// PubFont is a font family declared in a pubspec
+type PubFont struct {
+	Family string   `json:"family"`
+	Files  []string `json:"files"` // As declared, relative to the package
+}
+
+// Pubspec is a Dart or Flutter package manifest
+type Pubspec struct {
+	File         string           `json:"file"` // Relative to the project root
+	Name         string           `json:"name"`
+	Version      string           `json:"version,omitempty"`
+	SDK          string           `json:"sdk,omitempty"`     // Dart SDK constraint
+	Flutter      string           `json:"flutter,omitempty"` // Flutter SDK constraint
+	Dependencies []PubDependency  `json:"dependencies,omitempty"`
+	Assets       []PubAsset       `json:"assets,omitempty"`
+	Fonts        []PubFont        `json:"fonts,omitempty"`
+	Flavors      []string         `json:"flavors,omitempty"`
+	Undeclared   []AssetReference `json:"undeclared,omitempty"` // Code loading package files no asset entry covers
+}
+
+// UsesFlutter reports whether the package depends on the Flutter SDK
+func (p *Pubspec) UsesFlutter() bool {
+	for _, dep := range p.Dependencies {
+		if dep.Source == PubSourceSDK && dep.Location == "flutter" {
+			return true
+		}
+	}
+	return false
+}
+
+// pubspecFile is the part of pubspec.yaml that is analyzed
+type pubspecFile struct {
+	Name                string                 `yaml:"name"`
+	Version             string                 `yaml:"version"`
+	Environment         map[string]interface{} `yaml:"environment"`
+	Dependencies        map[string]interface{} `yaml:"dependencies"`
+	DevDependencies     map[string]interface{} `yaml:"dev_dependencies"`
+	DependencyOverrides map[string]interface{} `yaml:"dependency_overrides"`
+	Flutter             struct {
+		Assets []interface{} `yaml:"assets"`
+		Fonts  []struct {
+			Family string `yaml:"family"`
+			Fonts  []struct {
+				Asset string `yaml:"asset"`
+			} `yaml:"fonts"`
+		} `yaml:"fonts"`
+	} `yaml:"flutter"`
+	// Flavors configured for flutter_flavorizr
+	Flavorizr struct {
+		Flavors map[string]interface{} `yaml:"flavors"`
+	} `yaml:"flavorizr"`
+}
+
+// FindPubspecs parses the pubspec.yaml files under root for their
+// dependency constraints, declared assets, fonts and flavors. Flavors come
+// from flavor-specific asset entries and the flutter_flavorizr section.
+// Each declared asset is linked to the string literals naming its files in
+// the Dart code of the package; directory entries cover only the files
+// directly inside them, as Flutter bundles them. Literals naming an
+// existing asset file of the package that no entry covers are reported as
+// undeclared, since loading them fails at runtime.
+func FindPubspecs(graph *types.CodeGraph, root string) []Pubspec {
+	var manifests []string
+	filepath.WalkDir(root, func(filePath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if filePath != root && (assetSkipDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() == "pubspec.yaml" {
+			manifests = append(manifests, filePath)
+		}
+		return nil
+	})
+	sort.Strings(manifests)
+
+	var pubspecs []Pubspec
+	for _, manifest := range manifests {
+		data, err := os.ReadFile(manifest)
+		if err != nil {
+			continue
+		}
+		var file pubspecFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			continue
+		}
+		pubspec := Pubspec{File: projectPath(root, manifest), Name: file.Name, Version: file.Version}
+		if sdk, ok := file.Environment["sdk"]; ok {
+			pubspec.SDK = fmt.Sprint(sdk)
+		}
+		if flutter, ok := file.Environment["flutter"]; ok {
+			pubspec.Flutter = fmt.Sprint(flutter)
+		}
+		for _, scope := range []struct {
+			name string
+			deps map[string]interface{}
+		}{{"dependencies", file.Dependencies}, {"dev_dependencies", file.DevDependencies}, {"dependency_overrides", file.DependencyOverrides}} {
+			for name, spec := range scope.deps {
+				pubspec.Dependencies = append(pubspec.Dependencies, parsePubDependency(name, spec, scope.name))
+			}
+		}
+		sort.SliceStable(pubspec.Dependencies, func(i, j int) bool {
+			if pubspec.Dependencies[i].Scope != pubspec.Dependencies[j].Scope {
+				return pubspec.Dependencies[i].Scope < pubspec.Dependencies[j].Scope
+			}
+			return pubspec.Dependencies[i].Name < pubspec.Dependencies[j].Name
+		})
+
+		dir := filepath.Dir(manifest)
+		var flavors []string
+		for _, entry := range file.Flutter.Assets {
+			var asset PubAsset
+			switch entry := entry.(type) {
+			case string:
+				asset.Entry = entry
+			case map[string]interface{}:
+				asset.Entry, _ = entry["path"].(string)
+				if list, ok := entry["flavors"].([]interface{}); ok {
+					for _, flavor := range list {
+						asset.Flavors = append(asset.Flavors, fmt.Sprint(flavor))
+					}
+				}
+			}
+			if asset.Entry == "" {
+				continue
+			}
+			asset.Files, asset.Missing = pubAssetFiles(root, dir, asset.Entry)
+			flavors = append(flavors, asset.Flavors...)
+			pubspec.Assets = append(pubspec.Assets, asset)
+		}
+		for _, font := range file.Flutter.Fonts {
+			family := PubFont{Family: font.Family}
+			for _, f := range font.Fonts {
+				family.Files = append(family.Files, f.Asset)
+			}
+			pubspec.Fonts = append(pubspec.Fonts, family)
+		}
+		for flavor := range file.Flavorizr.Flavors {
+			flavors = append(flavors, flavor)
+		}
+		pubspec.Flavors = sortedUnique(flavors)
+		pubspecs = append(pubspecs, pubspec)
+	}
+
+	linkPubAssets(graph, root, pubspecs)
+	return pubspecs
+}
+
+// parsePubDependency reads a dependency written as a version constraint or
+// as a map with an sdk, path, git or hosted source
+func parsePubDependency(name string, spec interface{}, scope string) PubDependency {
+	dep := PubDependency{Name: name, Source: PubSourceHosted, Scope: scope}
+	switch spec := spec.(type) {
+	case nil:
+		dep.Constraint = "any"
+	case string:
+		dep.Constraint = spec
+	case map[string]interface{}:
+		if version, ok := spec["version"]; ok {
+			dep.Constraint = fmt.Sprint(version)
+		}
+		switch {
+		case spec["sdk"] != nil:
+			dep.Source, dep.Location = PubSourceSDK, fmt.Sprint(spec["sdk"])
+		case spec["path"] != nil:
+			dep.Source, dep.Location = PubSourcePath, fmt.Sprint(spec["path"])
+		case spec["git"] != nil:
+			dep.Source = PubSourceGit
+			if git, ok := spec["git"].(map[string]interface{}); ok {
+				dep.Location = fmt.Sprint(git["url"])
+				if ref, ok := git["ref"]; ok {
+					dep.Location += "@" + fmt.Sprint(ref)
+				}
+			} else {
+				dep.Location = fmt.Sprint(spec["git"])
+			}
+		case dep.Constraint == "":
+			dep.Constraint = "any"
+		}
+	default:
+		dep.Constraint = fmt.Sprint(spec)
+	}
+	return dep
+}
+
+// pubAssetFiles returns the project-relative files an asset entry of the
+// package in dir bundles, and whether the entry names nothing on disk
+func pubAssetFiles(root, dir, entry string) ([]string, bool) {
+	target := filepath.Join(dir, filepath.FromSlash(entry))
+	info, err := os.Stat(target)
+	if err != nil {
+		return nil, true
+	}
+	if !info.IsDir() {
+		return []string{projectPath(root, target)}, false
+	}
+	entries, err := os.ReadDir(target)
+	if err != nil {
+		return nil, false
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			files = append(files, projectPath(root, filepath.Join(target, e.Name())))
+		}
+	}
+	return files, false
+}
+
+// linkPubAssets finds the string literals of the Dart files of each package
+// naming its asset files, relative to the package or as packages/<name>/
+func linkPubAssets(graph *types.CodeGraph, root string, pubspecs []Pubspec) {
+	if len(pubspecs) == 0 {
+		return
+	}
+	var paths []string
+	for filePath, file := range graph.Files {
+		if file.Language == "dart" {
+			paths = append(paths, filePath)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		rel := projectPath(root, filePath)
+		// The package of a file is the closest pubspec above it
+		owner := -1
+		for i := range pubspecs {
+			dir := path.Dir(pubspecs[i].File)
+			if (dir == "." || strings.HasPrefix(rel, dir+"/")) && (owner < 0 || len(dir) > len(path.Dir(pubspecs[owner].File))) {
+				owner = i
+			}
+		}
+		if owner < 0 {
+			continue
+		}
+		pubspec := &pubspecs[owner]
+		dir := path.Dir(pubspec.File)
+		declared := make(map[string]int)
+		for i, asset := range pubspec.Assets {
+			for _, file := range asset.Files {
+				if _, ok := declared[file]; !ok {
+					declared[file] = i
+				}
+			}
+		}
+
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "import ") || strings.HasPrefix(trimmed, "export ") || strings.HasPrefix(trimmed, "part ") {
+				continue
+			}
+			for _, m := range dartQuoted.FindAllStringSubmatch(line, -1) {
+				ref := strings.TrimPrefix(m[1], "packages/"+pubspec.Name+"/")
+				if strings.Contains(ref, "://") || strings.HasSuffix(ref, ".dart") || strings.HasPrefix(ref, "/") {
+					continue
+				}
+				target := path.Join(dir, ref)
+				site := AssetReference{File: rel, Line: i + 1, Text: m[1]}
+				if index, ok := declared[target]; ok {
+					pubspec.Assets[index].References = append(pubspec.Assets[index].References, site)
+					continue
+				}
+				if _, ok := assetKinds[strings.ToLower(path.Ext(ref))]; !ok {
+					continue
+				}
+				if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(target))); err == nil && !info.IsDir() {
+					pubspec.Undeclared = append(pubspec.Undeclared, site)
+				}
+			}
+		}
+	}
+}
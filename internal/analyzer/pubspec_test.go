@@ -0,0 +1,86 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPubspecs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app/pubspec.yaml": "name: shop\nversion: 1.2.0+3\nenvironment:\n  sdk: '>=3.0.0 <4.0.0'\n  flutter: '>=3.19.0'\n" +
+			"dependencies:\n  flutter:\n    sdk: flutter\n  flutter_bloc: ^8.1.3\n  dio: 5.4.0\n  intl: any\n  collection: '>=1.17.0 <2.0.0'\n" +
+			"  core:\n    path: ../core\n  shared_ui:\n    git:\n      url: https://example.com/shared_ui.git\n      ref: main\n" +
+			"dev_dependencies:\n  build_runner:\n  flutter_test:\n    sdk: flutter\n" +
+			"flutter:\n  assets:\n    - assets/images/\n    - assets/config.json\n    - assets/missing.png\n    - path: assets/dev/\n      flavors:\n        - dev\n" +
+			"  fonts:\n    - family: Inter\n      fonts:\n        - asset: fonts/Inter-Regular.ttf\n" +
+			"flavorizr:\n  flavors:\n    dev: {}\n    prod: {}\n",
+		"app/assets/images/logo.png":       "png",
+		"app/assets/images/unused.png":     "png",
+		"app/assets/images/icons/cart.png": "png",
+		"app/assets/config.json":           "{}",
+		"app/assets/dev/banner.png":        "png",
+		"app/lib/home.dart":                "import 'package:flutter/material.dart';\n\nclass Home extends StatelessWidget {\n  Widget build(BuildContext context) {\n    return Image.asset('assets/images/logo.png');\n  }\n}\n",
+		"app/lib/cart.dart":                "class Cart {\n  final icon = 'assets/images/icons/cart.png';\n  final logo = 'packages/shop/assets/images/logo.png';\n  Future load() => rootBundle.loadString('assets/config.json');\n}\n",
+		"core/pubspec.yaml":                "name: core\nenvironment:\n  sdk: ^3.0.0\n",
+		"core/lib/core.dart":               "const seed = 'assets/images/logo.png';\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	pubspecs := FindPubspecs(graph, dir)
+	require.Len(t, pubspecs, 2)
+	app := pubspecs[0]
+	assert.Equal(t, "app/pubspec.yaml", app.File)
+	assert.Equal(t, "shop", app.Name)
+	assert.Equal(t, "1.2.0+3", app.Version)
+	assert.Equal(t, ">=3.0.0 <4.0.0", app.SDK)
+	assert.Equal(t, ">=3.19.0", app.Flutter)
+	assert.True(t, app.UsesFlutter())
+	assert.False(t, pubspecs[1].UsesFlutter())
+
+	styles := make(map[string]string)
+	for _, dep := range app.Dependencies {
+		styles[dep.Name] = dep.Style()
+	}
+	assert.Equal(t, map[string]string{
+		"flutter": "sdk", "flutter_bloc": "caret", "dio": "pinned", "intl": "any", "collection": "range",
+		"core": "path", "shared_ui": "git", "build_runner": "any", "flutter_test": "sdk",
+	}, styles)
+	assert.Equal(t, PubDependency{Name: "shared_ui", Source: PubSourceGit, Location: "https://example.com/shared_ui.git@main", Scope: "dependencies"}, findPubDependency(app, "shared_ui"))
+	assert.Equal(t, "dev_dependencies", findPubDependency(app, "build_runner").Scope)
+	assert.Equal(t, "state", findPubDependency(app, "flutter_bloc").Category())
+	assert.Equal(t, "codegen", findPubDependency(app, "build_runner").Category())
+
+	assert.Equal(t, []string{"dev", "prod"}, app.Flavors)
+	assert.Equal(t, []PubFont{{Family: "Inter", Files: []string{"fonts/Inter-Regular.ttf"}}}, app.Fonts)
+
+	require.Len(t, app.Assets, 4)
+	images := app.Assets[0]
+	assert.Equal(t, "assets/images/", images.Entry)
+	assert.Equal(t, []string{"app/assets/images/logo.png", "app/assets/images/unused.png"}, images.Files, "subdirectories need their own entry")
+	assert.Equal(t, []AssetReference{
+		{File: "app/lib/cart.dart", Line: 3, Text: "packages/shop/assets/images/logo.png"},
+		{File: "app/lib/home.dart", Line: 5, Text: "assets/images/logo.png"},
+	}, images.References)
+	assert.Equal(t, []AssetReference{{File: "app/lib/cart.dart", Line: 4, Text: "assets/config.json"}}, app.Assets[1].References)
+	assert.True(t, app.Assets[2].Missing)
+	assert.Equal(t, []string{"dev"}, app.Assets[3].Flavors)
+	assert.Equal(t, []string{"app/assets/dev/banner.png"}, app.Assets[3].Files)
+	assert.Equal(t, []AssetReference{{File: "app/lib/cart.dart", Line: 2, Text: "assets/images/icons/cart.png"}}, app.Undeclared)
+
+	assert.Empty(t, pubspecs[1].Undeclared, "references resolve against the package of the file")
+}
+
+func findPubDependency(pubspec Pubspec, name string) PubDependency {
+	for _, dep := range pubspec.Dependencies {
+		if dep.Name == name {
+			return dep
+		}
+	}
+	return PubDependency{}
+}
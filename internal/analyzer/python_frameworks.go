@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+var (
+	pythonFrameworkImport = regexp.MustCompile(`(?m)^\s*(?:from|import)\s+(fastapi|flask|django|rest_framework)\b`)
+	// Route decorators: @app.get("/users"), @router.post(path="/"), @bp.route("/", methods=["POST"])
+	pythonRouteDecorator = regexp.MustCompile(`^[\w.]+\.(route|get|post|put|patch|delete|head|options|websocket)\(\s*(?:(?:path|rule)\s*=\s*)?["']([^"']*)["']`)
+	pythonRouteMethods   = regexp.MustCompile(`methods\s*=\s*[\[(]([^\])]*)`)
+	// Django REST framework function views: @api_view(["GET", "POST"])
+	pythonAPIView      = regexp.MustCompile(`^(?:[\w.]+\.)?api_view\(\s*[\[(]?([^\])]*)`)
+	pythonQuoted       = regexp.MustCompile(`["'](\w+)["']`)
+	pythonFixture      = regexp.MustCompile(`^(?:pytest\.)?fixture\b(?:\((.*)\))?$`)
+	pythonFixtureScope = regexp.MustCompile(`scope\s*=\s*["'](\w+)["']`)
+)
+
+// PythonRoute is a view function registered by a route decorator
+type PythonRoute struct {
+	Framework string `json:"framework"` // FastAPI, Flask or Django
+	Method    string `json:"method"`    // GET, POST, ..., comma-joined when the view takes several
+	Path      string `json:"path,omitempty"`
+	Handler   string `json:"handler"`
+	File      string `json:"file"` // Relative to the project root
+	Line      int    `json:"line"`
+	Async     bool   `json:"async,omitempty"`
+}
+
+// PythonModel is a dataclass, attrs class, pydantic model or Django model
+type PythonModel struct {
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"` // parser.PythonModel* kind
+	Bases  []string `json:"bases,omitempty"`
+	Fields []string `json:"fields,omitempty"` // Declared in the class itself, as "name: type"
+	File   string   `json:"file"`
+	Line   int      `json:"line"`
+}
+
+// PythonFixture is a pytest fixture
+type PythonFixture struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"` // function unless set in the decorator
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+}
+
+// PythonFrameworkReport is what the decorators and base classes of a
+// project's Python code say about its web framework, data models and tests
+type PythonFrameworkReport struct {
+	Routes   []PythonRoute   `json:"routes,omitempty"`
+	Models   []PythonModel   `json:"models,omitempty"`
+	Fixtures []PythonFixture `json:"fixtures,omitempty"`
+}
+
+// AnalyzePythonFrameworks lists the routes, models and pytest fixtures
+// declared in a project's Python files from the decorators and model kinds
+// the parser records. A route's framework is taken from the imports of its
+// file. Classes extending a model of another file are models of the same
+// kind. It returns nil when the project has none.
+func AnalyzePythonFrameworks(graph *types.CodeGraph, root string) *PythonFrameworkReport {
+	var paths []string
+	for path, file := range graph.Files {
+		if file.Language == "python" {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	report := &PythonFrameworkReport{Models: pythonModels(graph, root, paths)}
+	for _, path := range paths {
+		framework := ""
+		if content, err := os.ReadFile(path); err == nil {
+			framework = pythonFramework(string(content))
+		}
+		for _, symbolId := range graph.Files[path].Symbols {
+			symbol := graph.Symbols[symbolId]
+			if symbol == nil || symbol.Type == types.SymbolTypeClass {
+				continue
+			}
+			async, _ := symbol.Metadata[parser.MetadataAsync].(bool)
+			for _, decorator := range symbol.MetadataStrings(parser.MetadataDecorators) {
+				location := projectPath(root, path)
+				if route, ok := pythonRoute(decorator, framework); ok {
+					route.Handler, route.File, route.Line, route.Async = symbol.Name, location, symbol.Location.StartLine, async
+					report.Routes = append(report.Routes, route)
+				} else if m := pythonFixture.FindStringSubmatch(decorator); m != nil {
+					scope := "function"
+					if s := pythonFixtureScope.FindStringSubmatch(m[1]); s != nil {
+						scope = s[1]
+					}
+					report.Fixtures = append(report.Fixtures, PythonFixture{Name: symbol.Name, Scope: scope, File: location, Line: symbol.Location.StartLine})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(report.Routes, func(i, j int) bool {
+		a, b := report.Routes[i], report.Routes[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	if len(report.Routes) == 0 && len(report.Models) == 0 && len(report.Fixtures) == 0 {
+		return nil
+	}
+	return report
+}
+
+// pythonModels lists the model classes of the given Python files in order.
+// Classes extending a model of another file are models of the same kind.
+func pythonModels(graph *types.CodeGraph, root string, paths []string) []PythonModel {
+	var classes []*types.Symbol
+	classFiles := make(map[*types.Symbol]string)
+	for _, path := range paths {
+		for _, symbolId := range graph.Files[path].Symbols {
+			if symbol := graph.Symbols[symbolId]; symbol != nil && symbol.Type == types.SymbolTypeClass {
+				classes = append(classes, symbol)
+				classFiles[symbol] = path
+			}
+		}
+	}
+
+	kinds := make(map[string]string)
+	for changed := true; changed; {
+		changed = false
+		for _, class := range classes {
+			if kinds[class.Name] != "" {
+				continue
+			}
+			kind := class.MetadataString(parser.MetadataModel)
+			for _, base := range class.MetadataStrings(parser.MetadataExtends) {
+				if kind == "" {
+					kind = kinds[lastSegment(base)]
+				}
+			}
+			if kind != "" {
+				kinds[class.Name] = kind
+				changed = true
+			}
+		}
+	}
+	var models []PythonModel
+	for _, class := range classes {
+		kind := class.MetadataString(parser.MetadataModel)
+		if kind == "" {
+			kind = kinds[class.Name]
+		}
+		if kind == "" {
+			continue
+		}
+		models = append(models, PythonModel{
+			Name: class.Name, Kind: kind,
+			Bases:  class.MetadataStrings(parser.MetadataExtends),
+			Fields: class.MetadataStrings(parser.MetadataFields),
+			File:   projectPath(root, classFiles[class]), Line: class.Location.StartLine,
+		})
+	}
+	return models
+}
+
+// Counts returns the number of models of each kind
+func (r *PythonFrameworkReport) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, model := range r.Models {
+		counts[model.Kind]++
+	}
+	return counts
+}
+
+// pythonFramework names the web framework a Python file imports
+func pythonFramework(content string) string {
+	switch m := pythonFrameworkImport.FindStringSubmatch(content); {
+	case m == nil:
+		return ""
+	case m[1] == "fastapi":
+		return "FastAPI"
+	case m[1] == "flask":
+		return "Flask"
+	default:
+		return "Django"
+	}
+}
+
+// pythonRoute reads the method and path of a route decorator. Without a
+// framework import, route() is taken for Flask and the method decorators
+// for FastAPI.
+func pythonRoute(decorator, framework string) (PythonRoute, bool) {
+	if m := pythonAPIView.FindStringSubmatch(decorator); m != nil {
+		return PythonRoute{Framework: "Django", Method: pythonRouteMethodList(m[1], "GET")}, true
+	}
+	m := pythonRouteDecorator.FindStringSubmatch(decorator)
+	if m == nil {
+		return PythonRoute{}, false
+	}
+	route := PythonRoute{Framework: framework, Path: m[2], Method: strings.ToUpper(m[1])}
+	if m[1] == "route" {
+		route.Method = "GET"
+		if methods := pythonRouteMethods.FindStringSubmatch(decorator); methods != nil {
+			route.Method = pythonRouteMethodList(methods[1], "GET")
+		}
+		if route.Framework == "" {
+			route.Framework = "Flask"
+		}
+	} else if route.Framework == "" {
+		route.Framework = "FastAPI"
+	}
+	return route, true
+}
+
+// pythonRouteMethodList joins the quoted HTTP methods of a list such as
+// ["GET", "POST"], or returns fallback for an empty list
+func pythonRouteMethodList(list, fallback string) string {
+	var methods []string
+	for _, m := range pythonQuoted.FindAllStringSubmatch(list, -1) {
+		methods = append(methods, strings.ToUpper(m[1]))
+	}
+	if len(methods) == 0 {
+		return fallback
+	}
+	return strings.Join(methods, ",")
+}
@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePythonFrameworks(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"api/schemas.py": "from pydantic import BaseModel\n\n\nclass Entity(BaseModel):\n    id: int\n\n\nclass User(Entity):\n    name: str\n",
+		"api/routes.py": "from fastapi import APIRouter\nfrom .schemas import Entity\n\nrouter = APIRouter()\n\n\n" +
+			"class Admin(Entity):\n    role: str = \"admin\"\n\n\n" +
+			"@router.get(\"/users/{id}\")\nasync def get_user(id: int):\n    return None\n\n\n" +
+			"@router.post(\"/users\")\ndef create_user(user: dict):\n    return user\n",
+		"web/app.py": "from flask import Flask\n\napp = Flask(__name__)\n\n\n" +
+			"@app.route(\"/login\", methods=[\"GET\", \"POST\"])\ndef login():\n    return \"\"\n",
+		"blog/models.py":    "from django.db import models\n\n\nclass Post(models.Model):\n    title = models.CharField(max_length=200)\n    author = models.ForeignKey(\"User\", on_delete=models.CASCADE)\n",
+		"tests/conftest.py": "import pytest\n\n\n@pytest.fixture(scope=\"session\")\ndef client():\n    return None\n\n\n@pytest.fixture\ndef user():\n    return None\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	report := AnalyzePythonFrameworks(graph, dir)
+	require.NotNil(t, report)
+	assert.Equal(t, []PythonRoute{
+		{Framework: "Flask", Method: "GET,POST", Path: "/login", Handler: "login", File: "web/app.py", Line: 7},
+		{Framework: "FastAPI", Method: "POST", Path: "/users", Handler: "create_user", File: "api/routes.py", Line: 17},
+		{Framework: "FastAPI", Method: "GET", Path: "/users/{id}", Handler: "get_user", File: "api/routes.py", Line: 12, Async: true},
+	}, report.Routes)
+
+	models := make(map[string]PythonModel)
+	for _, model := range report.Models {
+		models[model.Name] = model
+	}
+	assert.Equal(t, map[string]int{"pydantic": 3, "django": 1}, report.Counts())
+	assert.Equal(t, []string{"id: int"}, models["Entity"].Fields)
+	assert.Equal(t, []string{"name: str"}, models["User"].Fields, "extending a model of the same file")
+	assert.Equal(t, "pydantic", models["Admin"].Kind, "extending a model of another file")
+	assert.Equal(t, []string{"title: CharField", "author: ForeignKey"}, models["Post"].Fields)
+
+	assert.Equal(t, []PythonFixture{
+		{Name: "client", Scope: "session", File: "tests/conftest.py", Line: 5},
+		{Name: "user", Scope: "function", File: "tests/conftest.py", Line: 10},
+	}, report.Fixtures)
+}
@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// ReachableFile is a file reached from the roots of a reachability walk
+type ReachableFile struct {
+	File  string `json:"file"`           // Relative to the project root
+	Depth int    `json:"depth"`          // Steps from the nearest root, 0 for roots
+	From  string `json:"from,omitempty"` // File it was first reached from
+	Via   string `json:"via,omitempty"`  // Relationship it was reached through, or "package" for files of the same Go package
+}
+
+// Reachability is the code reachable from a set of root files, and the code
+// that is not
+type Reachability struct {
+	Roots       []string        `json:"roots"`       // Relative to the project root
+	Reachable   []ReachableFile `json:"reachable"`   // Nearest first
+	Unreachable []string        `json:"unreachable"` // Source files no root reaches
+}
+
+// EntryPointFiles returns the non-test files where programs start: those
+// with a detected main, command-line parser, serverless handler or server
+// bootstrap, and those whose name or main and init functions run them
+// without being referenced.
+func EntryPointFiles(graph *types.CodeGraph) []string {
+	seen := make(map[string]bool)
+	for _, entryPoint := range FindEntryPoints(graph) {
+		seen[entryPoint.File] = true
+	}
+	for path, file := range graph.Files {
+		if !file.IsTest && isEntryPoint(graph, path, file) {
+			seen[path] = true
+		}
+	}
+	files := make([]string, 0, len(seen))
+	for path := range seen {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// AnalyzeReachability walks the graph from the root files, given as graph
+// paths, or from every entry point when roots is empty. Reachability is
+// tracked per file: a file reaches the files its imports resolve to and those
+// its symbols call, reference or otherwise use, every file of the packages
+// its import paths name (Go import paths, Python and Java dotted names), and
+// the other files of its Go package. Documentation links are not followed.
+// The non-test, non-generated source files with symbols that no root reaches
+// are reported unreachable; with the default roots they are dead code
+// candidates, including code only tests use.
+func AnalyzeReachability(graph *types.CodeGraph, root string, roots []string) Reachability {
+	if len(roots) == 0 {
+		roots = EntryPointFiles(graph)
+	}
+
+	steps := fileSteps(graph)
+
+	result := Reachability{}
+	reached := make(map[string]*ReachableFile)
+	var queue []string
+	for _, path := range roots {
+		if graph.Files[path] == nil || reached[path] != nil {
+			continue
+		}
+		result.Roots = append(result.Roots, projectPath(root, path))
+		reached[path] = &ReachableFile{File: projectPath(root, path)}
+		queue = append(queue, path)
+	}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		for _, s := range steps[path] {
+			if reached[s.to] != nil {
+				continue
+			}
+			reached[s.to] = &ReachableFile{File: projectPath(root, s.to), Depth: reached[path].Depth + 1, From: projectPath(root, path), Via: s.via}
+			queue = append(queue, s.to)
+		}
+	}
+
+	for path, file := range graph.Files {
+		if reachedFile := reached[path]; reachedFile != nil {
+			result.Reachable = append(result.Reachable, *reachedFile)
+		} else if entryPointLanguages[file.Language] && !file.IsTest && !file.IsGenerated && len(file.Symbols) > 0 {
+			result.Unreachable = append(result.Unreachable, projectPath(root, path))
+		}
+	}
+	sort.Slice(result.Reachable, func(i, j int) bool {
+		if result.Reachable[i].Depth != result.Reachable[j].Depth {
+			return result.Reachable[i].Depth < result.Reachable[j].Depth
+		}
+		return result.Reachable[i].File < result.Reachable[j].File
+	})
+	sort.Strings(result.Unreachable)
+	return result
+}
+
+// fileStep is one way a file depends on another
+type fileStep struct {
+	to     string
+	via    string // Relationship type, or "package" for files of the same Go package
+	detail string // Import path, or the symbols the relationship connects
+}
+
+// fileSteps returns, for each file, the files it depends on: those its
+// import and symbol edges lead to, every non-test file of the packages its
+// import paths name, and the other files of its Go package. Containment and
+// documentation edges are left out. Steps are sorted by target file.
+func fileSteps(graph *types.CodeGraph) map[string][]fileStep {
+	symbolFile := make(map[types.SymbolId]string)
+	for path, file := range graph.Files {
+		for _, id := range file.Symbols {
+			symbolFile[id] = path
+		}
+	}
+	fileOf := func(node types.NodeId) (string, string) {
+		if path, ok := strings.CutPrefix(string(node), "file-"); ok {
+			return path, ""
+		}
+		if id, ok := strings.CutPrefix(string(node), "symbol-"); ok {
+			if symbol := graph.Symbols[types.SymbolId(id)]; symbol != nil {
+				return symbolFile[symbol.Id], symbol.Name
+			}
+		}
+		return "", ""
+	}
+	steps := make(map[string][]fileStep)
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipContains) || edge.Type == string(RelationshipDocuments) {
+			continue
+		}
+		from, fromSymbol := fileOf(edge.From)
+		to, toSymbol := fileOf(edge.To)
+		if from == "" || to == "" || from == to || graph.Files[to] == nil {
+			continue
+		}
+		detail := ""
+		switch {
+		case fromSymbol != "" && toSymbol != "":
+			detail = fromSymbol + " → " + toSymbol
+		case toSymbol != "":
+			detail = toSymbol
+		default:
+			detail, _ = edge.Metadata["import_path"].(string)
+		}
+		steps[from] = append(steps[from], fileStep{to, edge.Type, detail})
+	}
+
+	// Import paths naming packages, resolved the way AnalyzeCoupling does
+	var paths []string
+	for path, file := range graph.Files {
+		if !file.IsTest {
+			paths = append(paths, path)
+		}
+	}
+	packageRoot := commonDir(paths)
+	packageOf := func(file string) string {
+		rel, err := filepath.Rel(packageRoot, filepath.Dir(file))
+		if err != nil {
+			return filepath.ToSlash(filepath.Dir(file))
+		}
+		return filepath.ToSlash(rel)
+	}
+	packageFiles := make(map[string][]string)
+	packages := make(map[string]*PackageCoupling)
+	for _, path := range paths {
+		pkg := packageOf(path)
+		packageFiles[pkg] = append(packageFiles[pkg], path)
+		packages[pkg] = &PackageCoupling{Package: pkg}
+	}
+	module, moduleDir := findGoModule(packageRoot)
+	for path, file := range graph.Files {
+		from := packageOf(path)
+		for _, imp := range file.Imports {
+			importPath := imp.Path
+			if module != "" && file.Language == "go" {
+				rest, ok := strings.CutPrefix(strings.Trim(importPath, `"`), module+"/")
+				if !ok {
+					continue
+				}
+				importPath = packageOf(filepath.Join(moduleDir, filepath.FromSlash(rest), "x.go"))
+			}
+			if to := matchImportPackage(importPath, from, packages); to != "" && to != from {
+				for _, target := range packageFiles[to] {
+					steps[path] = append(steps[path], fileStep{target, string(RelationshipImport), strings.Trim(imp.Path, `"'`)})
+				}
+			}
+		}
+		if file.Language == "go" {
+			for _, sibling := range packageFiles[from] {
+				if sibling != path && graph.Files[sibling].Language == "go" {
+					steps[path] = append(steps[path], fileStep{sibling, "package", ""})
+				}
+			}
+		}
+	}
+
+	for _, next := range steps {
+		sort.Slice(next, func(i, j int) bool {
+			if next[i].to != next[j].to {
+				return next[i].to < next[j].to
+			}
+			if next[i].via != next[j].via {
+				return next[i].via < next[j].via
+			}
+			return next[i].detail < next[j].detail
+		})
+	}
+	return steps
+}
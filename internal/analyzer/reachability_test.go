@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeReachability(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                      "module example.com/app\n\ngo 1.24\n",
+		"cmd/app/main.go":             "package main\n\nimport \"example.com/app/internal/server\"\n\nfunc main() { server.Serve() }\n",
+		"internal/server/server.go":   "package server\n\nfunc Serve() { routes() }\n",
+		"internal/server/routes.go":   "package server\n\nfunc routes() {}\n",
+		"internal/legacy/old.go":      "package legacy\n\nfunc Old() {}\n",
+		"internal/legacy/old_test.go": "package legacy\n\nimport \"testing\"\n\nfunc TestOld(t *testing.T) { Old() }\n",
+		"web/index.js":                "import { format } from './lib/format';\n\nexport function start() { return format(1); }\n",
+		"web/lib/format.js":           "export function format(value) { return String(value); }\n",
+		"web/lib/unused.js":           "export function unused() { return 1; }\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	reachability := AnalyzeReachability(graph, dir, nil)
+	assert.Equal(t, []string{"cmd/app/main.go", "web/index.js"}, reachability.Roots, "entry points are the default roots")
+	assert.Equal(t, []ReachableFile{
+		{File: "cmd/app/main.go"},
+		{File: "web/index.js"},
+		{File: "internal/server/routes.go", Depth: 1, From: "cmd/app/main.go", Via: "imports"},
+		{File: "internal/server/server.go", Depth: 1, From: "cmd/app/main.go", Via: "imports"},
+		{File: "web/lib/format.js", Depth: 1, From: "web/index.js", Via: "imports"},
+	}, reachability.Reachable)
+	assert.Equal(t, []string{"internal/legacy/old.go", "web/lib/unused.js"}, reachability.Unreachable,
+		"code only tests use is unreachable")
+
+	reachability = AnalyzeReachability(graph, dir, []string{filepath.Join(dir, "internal/server/routes.go")})
+	assert.Equal(t, []ReachableFile{
+		{File: "internal/server/routes.go"},
+		{File: "internal/server/server.go", Depth: 1, From: "internal/server/routes.go", Via: "package"},
+	}, reachability.Reachable, "files of a Go package are compiled together")
+	assert.Contains(t, reachability.Unreachable, "cmd/app/main.go")
+}
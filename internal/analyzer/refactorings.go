@@ -0,0 +1,579 @@
+package analyzer
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+var (
+	refactorIdentifier = regexp.MustCompile(`[A-Za-z_$][A-Za-z0-9_$]*`)
+	refactorMember     = regexp.MustCompile(`\b(this|self|[A-Za-z_]\w*)\.([A-Za-z_]\w*)`)
+	goReceiverName     = regexp.MustCompile(`^func\s*\(\s*([A-Za-z_]\w*)\s`)
+)
+
+// refactorLinkage is the least average affinity between two groups of
+// symbols for them to be kept together
+const refactorLinkage = 0.25
+
+// RefactorOptions configures SuggestRefactorings
+type RefactorOptions struct {
+	MinSymbols int // Symbols a file needs to be a candidate (default 20)
+	MinMethods int // Methods a class needs to be a candidate (default 15)
+
+	// Blame returns the commit that last changed each line of a file, given
+	// as a graph path, or nil when the history is unknown
+	Blame func(path string) []string
+
+	// Neighborhoods lists the files of each change neighborhood, relative to
+	// the project or repository root
+	Neighborhoods [][]string
+}
+
+// SplitGroup is a set of symbols used and changed together, suggested to
+// move out on its own
+type SplitGroup struct {
+	Anchor     string   `json:"anchor"`     // Most connected symbol of the group
+	Symbols    []string `json:"symbols"`    // In declaration order
+	Lines      int      `json:"lines"`      // Lines the symbols span
+	Dependents []string `json:"dependents"` // Files using the group, relative to the project root
+}
+
+// RefactoringCandidate is a file or class that has grown too large
+type RefactoringCandidate struct {
+	Kind          string       `json:"kind"` // "file" or "class"
+	File          string       `json:"file"` // Relative to the project root
+	Class         string       `json:"class,omitempty"`
+	Line          int          `json:"line,omitempty"` // Declaration line of the class
+	Lines         int          `json:"lines"`
+	Symbols       int          `json:"symbols"`       // Symbols of the file, or methods of the class
+	FanIn         int          `json:"fan_in"`        // Non-test files depending on the file
+	FanOut        int          `json:"fan_out"`       // Files the file depends on
+	Neighborhoods int          `json:"neighborhoods"` // Change neighborhoods the file belongs to
+	Groups        []SplitGroup `json:"groups,omitempty"`
+	Ungrouped     []string     `json:"ungrouped,omitempty"` // Symbols tied to no group
+}
+
+// refactorUnit is a declaration that moves as a whole when splitting: a
+// top-level declaration with its Go methods, or a method of a class
+type refactorUnit struct {
+	name   string
+	names  []string // Names other code uses it by
+	line   int
+	ranges [][2]int // 1-based inclusive line ranges
+	tokens map[string]bool
+	fields map[string]bool // Attributes accessed through this, self or the receiver
+}
+
+// SuggestRefactorings finds god-files, files declaring many symbols, and
+// god-classes, classes with many methods, and proposes where to split them.
+// The symbols of a candidate are grouped by how they are used together:
+// symbols naming each other, methods sharing attributes, dependent files
+// using both, and lines last changed by the same commits. A split is only
+// suggested when at least two groups of two or more symbols emerge.
+// Candidates with a suggested split come first, largest first.
+func SuggestRefactorings(graph *types.CodeGraph, root string, opts RefactorOptions) []RefactoringCandidate {
+	if opts.MinSymbols <= 0 {
+		opts.MinSymbols = 20
+	}
+	if opts.MinMethods <= 0 {
+		opts.MinMethods = 15
+	}
+
+	fanIn := make(map[string]map[string]bool)
+	fanOut := make(map[string]map[string]bool)
+	for from, steps := range fileSteps(graph) {
+		for _, step := range steps {
+			if step.via == "package" || step.to == from {
+				continue
+			}
+			if fanOut[from] == nil {
+				fanOut[from] = make(map[string]bool)
+			}
+			fanOut[from][step.to] = true
+			if graph.Files[from].IsTest {
+				continue
+			}
+			if fanIn[step.to] == nil {
+				fanIn[step.to] = make(map[string]bool)
+			}
+			fanIn[step.to][from] = true
+		}
+	}
+	dependentTokens := make(map[string]map[string]bool)
+	tokensOf := func(file string) map[string]bool {
+		if tokens, ok := dependentTokens[file]; ok {
+			return tokens
+		}
+		data, _ := os.ReadFile(file)
+		tokens := identifierSet(string(data))
+		dependentTokens[file] = tokens
+		return tokens
+	}
+
+	var candidates []RefactoringCandidate
+	for file, node := range graph.Files {
+		if node.IsTest || node.IsGenerated {
+			continue
+		}
+		var symbols []*types.Symbol
+		for _, id := range node.Symbols {
+			if symbol := graph.Symbols[id]; symbol != nil && refactorSymbol(symbol) {
+				symbols = append(symbols, symbol)
+			}
+		}
+		if len(symbols) < opts.MinSymbols && len(symbols) < opts.MinMethods {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		symbols = withoutLocals(symbols, lines)
+		if len(symbols) < opts.MinSymbols && len(symbols) < opts.MinMethods {
+			continue
+		}
+
+		rel := projectPath(root, file)
+		base := RefactoringCandidate{
+			File:          rel,
+			FanIn:         len(fanIn[file]),
+			FanOut:        len(fanOut[file]),
+			Neighborhoods: neighborhoodsOf(rel, opts.Neighborhoods),
+		}
+		var blame []string
+		if opts.Blame != nil {
+			blame = opts.Blame(file)
+		}
+		var dependents []string
+		for dependent := range fanIn[file] {
+			dependents = append(dependents, dependent)
+		}
+		sort.Strings(dependents)
+
+		if len(symbols) >= opts.MinSymbols {
+			units := fileUnits(symbols, lines)
+			if len(units) >= 2 {
+				candidate := base
+				candidate.Kind = "file"
+				candidate.Lines = len(lines)
+				candidate.Symbols = len(symbols)
+				affinity := unitAffinity(units, blame)
+				users := make(map[string][]string)
+				for _, dependent := range dependents {
+					tokens := tokensOf(dependent)
+					var used []int
+					for i, unit := range units {
+						if mentionsAny(tokens, unit.names) {
+							used = append(used, i)
+						}
+					}
+					for a := 0; a < len(used); a++ {
+						for b := a + 1; b < len(used); b++ {
+							affinity[used[a]][used[b]]++
+							affinity[used[b]][used[a]]++
+						}
+					}
+					for _, i := range used {
+						users[units[i].name] = append(users[units[i].name], projectPath(root, dependent))
+					}
+				}
+				candidate.Groups, candidate.Ungrouped = splitUnits(units, affinity, users)
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		for _, class := range symbols {
+			methods := classMethods(class, symbols, lines)
+			if len(methods) < opts.MinMethods {
+				continue
+			}
+			candidate := base
+			candidate.Kind = "class"
+			candidate.Class = class.Name
+			candidate.Line = class.Location.StartLine
+			candidate.Lines = declarationEnd(lines, class.Location.StartLine) - class.Location.StartLine + 1
+			candidate.Symbols = len(methods)
+			receivers := make(map[string]bool)
+			for _, method := range methods {
+				if match := goReceiverName.FindStringSubmatch(method.sym.Signature); match != nil {
+					receivers[match[1]] = true
+					span := method.unit.ranges[0]
+					candidate.Lines += span[1] - span[0] + 1 // Go methods live outside the type
+				}
+			}
+			units := make([]*refactorUnit, len(methods))
+			for i, method := range methods {
+				units[i] = method.unit
+				units[i].fields = memberAccesses(unitText(units[i], lines), receivers)
+			}
+			candidate.Groups, candidate.Ungrouped = splitUnits(units, unitAffinity(units, blame), nil)
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if (len(a.Groups) > 0) != (len(b.Groups) > 0) {
+			return len(a.Groups) > 0
+		}
+		if a.Symbols != b.Symbols {
+			return a.Symbols > b.Symbols
+		}
+		if a.FanIn+a.FanOut != b.FanIn+b.FanOut {
+			return a.FanIn+a.FanOut > b.FanIn+b.FanOut
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Class < b.Class
+	})
+	return candidates
+}
+
+// refactorSymbol reports whether a symbol is a declaration worth moving,
+// leaving out imports, keys, properties and parser artifacts
+func refactorSymbol(symbol *types.Symbol) bool {
+	switch symbol.Type {
+	case types.SymbolTypeImport, types.SymbolTypeNamespace, types.SymbolTypeProperty, types.SymbolTypeConfigKey:
+		return false
+	}
+	name := symbol.Name
+	return name != "" && name != "unknown" && name != "function" && name != "class" && !strings.ContainsAny(name, "( \t")
+}
+
+// withoutLocals sorts symbols by line and drops the variables and constants
+// declared within the body of a function or method
+func withoutLocals(symbols []*types.Symbol, lines []string) []*types.Symbol {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return symbols[i].Location.StartLine < symbols[j].Location.StartLine
+	})
+	var bodies [][2]int
+	for _, symbol := range symbols {
+		if symbol.Type == types.SymbolTypeFunction || isMethodSymbol(symbol) {
+			start := symbol.Location.StartLine
+			bodies = append(bodies, [2]int{start, declarationEnd(lines, start)})
+		}
+	}
+	var kept []*types.Symbol
+	for _, symbol := range symbols {
+		local := false
+		if symbol.Type == types.SymbolTypeVariable || symbol.Type == types.SymbolTypeConstant {
+			line := symbol.Location.StartLine
+			for _, body := range bodies {
+				if line > body[0] && line <= body[1] {
+					local = true
+					break
+				}
+			}
+		}
+		if !local {
+			kept = append(kept, symbol)
+		}
+	}
+	return kept
+}
+
+// isMethodSymbol reports whether a symbol is a method of a class or type
+func isMethodSymbol(symbol *types.Symbol) bool {
+	switch symbol.Type {
+	case types.SymbolTypeMethod, types.SymbolTypeConstructor, types.SymbolTypeDestructor, types.SymbolTypeOperator,
+		types.SymbolTypeLifecycle, types.SymbolTypeLifecycleMethod, types.SymbolTypeBuildMethod:
+		return true
+	}
+	return false
+}
+
+// fileUnits returns the top-level declarations of a file. Go methods join
+// the unit of their receiver type when it is declared in the same file.
+func fileUnits(symbols []*types.Symbol, lines []string) []*refactorUnit {
+	var units []*refactorUnit
+	byName := make(map[string]*refactorUnit)
+	var methods []*types.Symbol
+	covered := 0
+	for _, symbol := range symbols {
+		start := symbol.Location.StartLine
+		if receiver, ok := symbol.Metadata["receiver"].(string); ok && receiver != "" {
+			methods = append(methods, symbol)
+			continue
+		}
+		if start <= covered {
+			continue // Nested in the previous declaration
+		}
+		end := declarationEnd(lines, start)
+		unit := &refactorUnit{name: symbol.Name, names: []string{symbol.Name}, line: start, ranges: [][2]int{{start, end}}}
+		units = append(units, unit)
+		if byName[symbol.Name] == nil {
+			byName[symbol.Name] = unit
+		}
+		covered = end
+	}
+	for _, method := range methods {
+		start := method.Location.StartLine
+		span := [2]int{start, declarationEnd(lines, start)}
+		if unit := byName[method.Metadata["receiver"].(string)]; unit != nil {
+			unit.names = append(unit.names, method.Name)
+			unit.ranges = append(unit.ranges, span)
+			continue
+		}
+		units = append(units, &refactorUnit{name: method.Name, names: []string{method.Name}, line: start, ranges: [][2]int{span}})
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].line < units[j].line })
+	for _, unit := range units {
+		unit.tokens = identifierSet(unitText(unit, lines))
+	}
+	return units
+}
+
+type classMethod struct {
+	sym  *types.Symbol
+	unit *refactorUnit
+}
+
+// classMethods returns the methods of a class: those declared within its
+// body, or for Go types the methods of the file with it as their receiver
+func classMethods(class *types.Symbol, symbols []*types.Symbol, lines []string) []classMethod {
+	switch class.Type {
+	case types.SymbolTypeClass, types.SymbolTypeType, types.SymbolTypeInterface, types.SymbolTypeStateClass, types.SymbolTypeWidget:
+	default:
+		return nil
+	}
+	start := class.Location.StartLine
+	end := declarationEnd(lines, start)
+	var methods []classMethod
+	for _, symbol := range symbols {
+		if !isMethodSymbol(symbol) {
+			continue
+		}
+		line := symbol.Location.StartLine
+		receiver, _ := symbol.Metadata["receiver"].(string)
+		if receiver != class.Name && (receiver != "" || line <= start || line > end) {
+			continue
+		}
+		unit := &refactorUnit{name: symbol.Name, names: []string{symbol.Name}, line: line, ranges: [][2]int{{line, declarationEnd(lines, line)}}}
+		unit.tokens = identifierSet(unitText(unit, lines))
+		methods = append(methods, classMethod{sym: symbol, unit: unit})
+	}
+	return methods
+}
+
+// declarationEnd returns the last line of the declaration starting at line:
+// the lines indented deeper than it, and a closing bracket at its own
+// indentation. Symbols only record where they start, so their extent is
+// recovered from the layout of the source.
+func declarationEnd(lines []string, line int) int {
+	if line < 1 || line > len(lines) {
+		return line
+	}
+	indent := indentation(lines[line-1])
+	end := line
+	for i := line; i < len(lines); i++ {
+		text := strings.TrimSpace(lines[i])
+		if text == "" {
+			continue
+		}
+		if indentation(lines[i]) > indent {
+			end = i + 1
+			continue
+		}
+		if strings.IndexAny(text[:1], "}])") == 0 {
+			end = i + 1
+			if strings.HasSuffix(text, "{") || strings.HasSuffix(text, "(") {
+				continue // e.g. the ") {" ending a wrapped signature
+			}
+		} else if text == "end" || strings.HasPrefix(text, "end ") {
+			end = i + 1
+		}
+		break
+	}
+	return end
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func unitText(unit *refactorUnit, lines []string) string {
+	var text strings.Builder
+	for _, span := range unit.ranges {
+		for line := span[0]; line <= span[1] && line <= len(lines); line++ {
+			text.WriteString(lines[line-1])
+			text.WriteString("\n")
+		}
+	}
+	return text.String()
+}
+
+func identifierSet(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range refactorIdentifier.FindAllString(text, -1) {
+		tokens[token] = true
+	}
+	return tokens
+}
+
+// memberAccesses returns the attributes text reaches through this, self or
+// one of the given receiver names
+func memberAccesses(text string, receivers map[string]bool) map[string]bool {
+	fields := make(map[string]bool)
+	for _, match := range refactorMember.FindAllStringSubmatch(text, -1) {
+		if match[1] == "this" || match[1] == "self" || receivers[match[1]] {
+			fields[match[2]] = true
+		}
+	}
+	return fields
+}
+
+func mentionsAny(tokens map[string]bool, names []string) bool {
+	for _, name := range names {
+		if tokens[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// unitAffinity weighs how strongly each pair of units belongs together:
+// one for each unit naming the other, one for each attribute both access,
+// and up to three for commits that last changed lines of both. Commits
+// touching more than half of the units say nothing about grouping and are
+// ignored, as are uncommitted lines.
+func unitAffinity(units []*refactorUnit, blame []string) [][]float64 {
+	affinity := make([][]float64, len(units))
+	for i := range affinity {
+		affinity[i] = make([]float64, len(units))
+	}
+	commits := make([]map[string]bool, len(units))
+	reach := make(map[string]int)
+	for i, unit := range units {
+		commits[i] = make(map[string]bool)
+		for _, span := range unit.ranges {
+			for line := span[0]; line <= span[1] && line <= len(blame); line++ {
+				if commit := blame[line-1]; commit != "" && strings.Trim(commit, "0") != "" {
+					commits[i][commit] = true
+				}
+			}
+		}
+		for commit := range commits[i] {
+			reach[commit]++
+		}
+	}
+
+	for i, a := range units {
+		for j, b := range units {
+			if i >= j {
+				continue
+			}
+			weight := 0.0
+			if mentionsAny(a.tokens, b.names) {
+				weight++
+			}
+			if mentionsAny(b.tokens, a.names) {
+				weight++
+			}
+			for field := range a.fields {
+				if b.fields[field] {
+					weight++
+				}
+			}
+			shared := 0
+			for commit := range commits[i] {
+				if commits[j][commit] && reach[commit]*2 <= len(units) {
+					shared++
+				}
+			}
+			weight += float64(min(shared, 3))
+			affinity[i][j] = weight
+			affinity[j][i] = weight
+		}
+	}
+	return affinity
+}
+
+// splitUnits clusters units by average linkage, merging the two groups with
+// the highest mean affinity until no pair reaches refactorLinkage. Groups
+// are returned when at least two of them hold two or more units; units left
+// alone are returned as ungrouped.
+func splitUnits(units []*refactorUnit, affinity [][]float64, users map[string][]string) ([]SplitGroup, []string) {
+	var clusters [][]int
+	for i := range units {
+		clusters = append(clusters, []int{i})
+	}
+	for {
+		best, bi, bj := 0.0, -1, -1
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				total := 0.0
+				for _, a := range clusters[i] {
+					for _, b := range clusters[j] {
+						total += affinity[a][b]
+					}
+				}
+				if mean := total / float64(len(clusters[i])*len(clusters[j])); mean > best {
+					best, bi, bj = mean, i, j
+				}
+			}
+		}
+		if bi < 0 || best < refactorLinkage {
+			break
+		}
+		clusters[bi] = append(clusters[bi], clusters[bj]...)
+		clusters = append(clusters[:bj], clusters[bj+1:]...)
+	}
+
+	var groups []SplitGroup
+	var ungrouped []string
+	for _, cluster := range clusters {
+		if len(cluster) < 2 {
+			ungrouped = append(ungrouped, units[cluster[0]].name)
+			continue
+		}
+		sort.Ints(cluster)
+		group := SplitGroup{}
+		anchor, strongest := -1, -1.0
+		var dependents []string
+		for _, i := range cluster {
+			group.Symbols = append(group.Symbols, units[i].name)
+			for _, span := range units[i].ranges {
+				group.Lines += span[1] - span[0] + 1
+			}
+			internal := 0.0
+			for _, j := range cluster {
+				internal += affinity[i][j]
+			}
+			if internal > strongest {
+				anchor, strongest = i, internal
+			}
+			dependents = append(dependents, users[units[i].name]...)
+		}
+		group.Anchor = units[anchor].name
+		group.Dependents = sortedUnique(dependents)
+		groups = append(groups, group)
+	}
+	if len(groups) < 2 {
+		return nil, nil
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return len(groups[i].Symbols) > len(groups[j].Symbols) })
+	return groups, ungrouped
+}
+
+// neighborhoodsOf counts the change neighborhoods listing file, whose paths
+// may be relative to a repository root above the project root
+func neighborhoodsOf(file string, neighborhoods [][]string) int {
+	count := 0
+	for _, files := range neighborhoods {
+		for _, other := range files {
+			other = path.Clean(strings.TrimPrefix(other, "./"))
+			if other == file || strings.HasSuffix(other, "/"+file) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestRefactorings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/utils.js": `export function parseHeader(text) {
+  return splitFields(text);
+}
+
+export function splitFields(text) {
+  return text.split(",");
+}
+
+export function parseBody(text) {
+  return parseHeader(text);
+}
+
+export function sendMail(to) {
+  return formatMail(to);
+}
+
+export function formatMail(to) {
+  return to + mailFooter();
+}
+
+export function mailFooter() {
+  return "--";
+}
+
+export function retry(fn) {
+  return fn();
+}
+
+export function backoff(n) {
+  return n * 2;
+}
+`,
+		"src/reader.js": "import { parseBody } from './utils';\n\nexport function read(text) { return parseBody(text); }\n",
+		"src/mailer.js": "import { sendMail } from './utils';\n\nexport function notify(to) { return sendMail(to); }\n",
+		"store/store.go": `package store
+
+type Store struct {
+	db     map[string]string
+	mailer []string
+}
+
+func (s *Store) Get(key string) string {
+	var value = s.db[key]
+	return value
+}
+
+func (s *Store) Put(key, value string) {
+	s.db[key] = value
+}
+
+func (s *Store) Notify(to string) {
+	s.mailer = append(s.mailer, to)
+}
+
+func (s *Store) Outbox() []string {
+	return s.mailer
+}
+`,
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	utils := filepath.Join(dir, "src/utils.js")
+	blame := func(path string) []string {
+		if path != utils {
+			return nil
+		}
+		var commits []string
+		for i := range strings.Split(files["src/utils.js"], "\n") {
+			commit := "aaaa"
+			if i >= 24 {
+				commit = "bbbb" // retry and backoff changed together
+			}
+			commits = append(commits, commit)
+		}
+		return commits
+	}
+	candidates := SuggestRefactorings(graph, dir, RefactorOptions{
+		MinSymbols:    8,
+		MinMethods:    4,
+		Blame:         blame,
+		Neighborhoods: [][]string{{"pkg/src/utils.js", "pkg/src/reader.js"}, {"src/utils.js"}, {"store/store.go"}},
+	})
+	require.Len(t, candidates, 2)
+
+	file := candidates[0]
+	assert.Equal(t, "file", file.Kind)
+	assert.Equal(t, "src/utils.js", file.File)
+	assert.Equal(t, 8, file.Symbols)
+	assert.Equal(t, 2, file.FanIn)
+	assert.Equal(t, 2, file.Neighborhoods)
+	require.Len(t, file.Groups, 3)
+	assert.Equal(t, SplitGroup{Anchor: "parseHeader", Symbols: []string{"parseHeader", "splitFields", "parseBody"}, Lines: 9, Dependents: []string{"src/reader.js"}}, file.Groups[0])
+	assert.Equal(t, SplitGroup{Anchor: "formatMail", Symbols: []string{"sendMail", "formatMail", "mailFooter"}, Lines: 9, Dependents: []string{"src/mailer.js"}}, file.Groups[1])
+	assert.Equal(t, []string{"retry", "backoff"}, file.Groups[2].Symbols, "co-changed lines tie otherwise unrelated functions")
+	assert.Empty(t, file.Ungrouped)
+
+	class := candidates[1]
+	assert.Equal(t, "class", class.Kind)
+	assert.Equal(t, "store/store.go", class.File)
+	assert.Equal(t, "Store", class.Class)
+	assert.Equal(t, 3, class.Line)
+	assert.Equal(t, 4, class.Symbols)
+	assert.Equal(t, 17, class.Lines, "the struct and its methods")
+	require.Len(t, class.Groups, 2)
+	assert.Equal(t, []string{"Get", "Put"}, class.Groups[0].Symbols, "methods sharing attributes stay together")
+	assert.Equal(t, []string{"Notify", "Outbox"}, class.Groups[1].Symbols)
+
+	candidates = SuggestRefactorings(graph, dir, RefactorOptions{MinSymbols: 6, MinMethods: 10})
+	require.Len(t, candidates, 1, "local variables do not count towards the symbols of store.go")
+	assert.Equal(t, []string{"retry", "backoff"}, candidates[0].Ungrouped, "without history nothing ties them")
+}
+
+func TestDeclarationEnd(t *testing.T) {
+	lines := strings.Split("func run(\n\tctx context.Context,\n) error {\n\treturn nil\n}\n\nfunc next() {}\n", "\n")
+	assert.Equal(t, 5, declarationEnd(lines, 1))
+	assert.Equal(t, 7, declarationEnd(lines, 7))
+
+	lines = strings.Split("class Store:\n    def get(self):\n        return 1\n\n    def put(self):\n        pass\n\ndef helper():\n    pass\n", "\n")
+	assert.Equal(t, 6, declarationEnd(lines, 1))
+	assert.Equal(t, 3, declarationEnd(lines, 2))
+}
@@ -3,14 +3,32 @@ package analyzer
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/nuthan-ms/codecontext/internal/parser"
 	"github.com/nuthan-ms/codecontext/pkg/types"
 )
 
 // RelationshipAnalyzer analyzes various types of relationships between code elements
 type RelationshipAnalyzer struct {
-	graph *types.CodeGraph
+	graph       *types.CodeGraph
+	includeDirs []string // Extra directories searched for C/C++ includes
+
+	headerIndex map[string][]string // Lazily built basename -> header paths index
+	moduleIndex map[string][]string // Lazily built C++ module name -> interface unit paths index
+
+	docRoot  string   // Repository root for resolving root-relative doc links
+	docFiles []string // Markdown documents scanned for links into the code
+
+	workspacePackages  []WorkspacePackage // Monorepo packages that bare imports resolve to
+	externalWorkspaces []string           // Workspace package names kept as external imports
+
+	skipUsage bool // Skip symbol usage and call edges
+
+	sources map[string]string // Lazily read contents of the scanned source files
+
+	symbolFiles map[types.SymbolId]string // Lazily built symbol -> file path index
 }
 
 // NewRelationshipAnalyzer creates a new relationship analyzer
@@ -20,6 +38,30 @@ func NewRelationshipAnalyzer(graph *types.CodeGraph) *RelationshipAnalyzer {
 	}
 }
 
+// fileOf returns the path of the file declaring symbol, as the graph keys it
+func (ra *RelationshipAnalyzer) fileOf(symbol *types.Symbol) string {
+	if ra.symbolFiles == nil {
+		ra.symbolFiles = make(map[types.SymbolId]string)
+		for path, file := range ra.graph.Files {
+			for _, id := range file.Symbols {
+				ra.symbolFiles[id] = path
+			}
+		}
+	}
+	return ra.symbolFiles[symbol.Id]
+}
+
+// SetIncludeDirs sets additional directories used to resolve C/C++ #include paths
+func (ra *RelationshipAnalyzer) SetIncludeDirs(dirs []string) {
+	ra.includeDirs = dirs
+}
+
+// SetSymbolUsage enables or disables the symbol usage and call analysis, the most
+// expensive relationship passes
+func (ra *RelationshipAnalyzer) SetSymbolUsage(enabled bool) {
+	ra.skipUsage = !enabled
+}
+
 // RelationshipType represents different types of relationships
 type RelationshipType string
 
@@ -55,10 +97,11 @@ type CircularDependency struct {
 
 // FileHotspot represents a file with high dependency activity
 type FileHotspot struct {
-	FilePath       string  `json:"file_path"`
-	ImportCount    int     `json:"import_count"`
-	ReferenceCount int     `json:"reference_count"`
-	Score          float64 `json:"score"`
+	FilePath       string          `json:"file_path"`
+	ImportCount    int             `json:"import_count"`
+	ReferenceCount int             `json:"reference_count"`
+	Coverage       *types.Coverage `json:"coverage,omitempty"` // Nil without a coverage report
+	Score          float64         `json:"score"`
 }
 
 // AnalyzeAllRelationships performs comprehensive relationship analysis
@@ -73,11 +116,58 @@ func (ra *RelationshipAnalyzer) AnalyzeAllRelationships() (*RelationshipMetrics,
 	// Analyze import relationships
 	ra.analyzeImportRelationships(metrics)
 
-	// Analyze symbol usage relationships
-	ra.analyzeSymbolUsageRelationships(metrics)
+	// Link Dart libraries to their part files
+	ra.analyzeDartParts(metrics)
+
+	// Link generated Dart parts to the models they were generated from
+	ra.analyzeDartCodegen(metrics)
+
+	// Pair C/C++ headers with their implementations and declarations with definitions
+	ra.analyzeHeaderPairs(metrics)
+
+	if !ra.skipUsage {
+		// Analyze symbol usage relationships
+		ra.analyzeSymbolUsageRelationships(metrics)
+
+		// Analyze call relationships
+		ra.analyzeCallRelationships(metrics)
+
+		// Link benchmarks to the functions they exercise
+		ra.analyzeBenchmarks(metrics)
+	}
+
+	// Analyze class hierarchy (extends/implements/mixins)
+	ra.analyzeInheritanceRelationships(metrics)
+
+	// Link Go types to the types they embed
+	ra.analyzeGoEmbedding(metrics)
+
+	// Match Go types to the interfaces their method sets satisfy
+	ra.analyzeGoInterfaces(metrics)
 
-	// Analyze call relationships
-	ra.analyzeCallRelationships(metrics)
+	// Link C++ template specializations and instantiations to their primary template
+	ra.analyzeTemplateRelationships(metrics)
+
+	// Link web handlers to the view templates they render
+	ra.analyzeTemplateRenders(metrics)
+
+	// Resolve what dependency injection containers wire into each class
+	ra.analyzeInjection(metrics)
+
+	// Match HTTP/gRPC clients to the endpoints that serve them
+	ra.analyzeServiceBoundaries(metrics)
+
+	// Link message producers to consumers of the same topic
+	ra.analyzeEventFlows(metrics)
+
+	// Link markdown documentation to the files and symbols it references
+	ra.analyzeDocumentationLinks(metrics)
+
+	// Link Storybook stories to the components they document
+	ra.analyzeStories(metrics)
+
+	// Link components to the state stores they dispatch to and select from
+	ra.analyzeStateFlows(metrics)
 
 	// Detect circular dependencies
 	ra.detectCircularDependencies(metrics)
@@ -102,49 +192,43 @@ func (ra *RelationshipAnalyzer) analyzeImportRelationships(metrics *Relationship
 
 	for filePath, fileNode := range ra.graph.Files {
 		for _, imp := range fileNode.Imports {
-			targetFile := ra.resolveImportPath(imp.Path, filePath)
+			var targetFile string
+			if isCppSourcePath(filePath) && imp.Module {
+				targetFile = ra.resolveModuleImport(imp.Path, filePath)
+			} else if isCppSourcePath(filePath) {
+				targetFile = ra.resolveIncludePath(imp, filePath)
+			} else if isShellPath(filePath) {
+				targetFile = ra.resolveSourcePath(imp.Path, filePath)
+			} else if parser.IsTemplateLanguage(fileNode.Language) {
+				targetFile = ra.resolveTemplatePath(imp.Path, filePath)
+			} else {
+				targetFile = ra.resolveImportPath(imp.Path, filePath)
+			}
 
+			from := types.NodeId(fmt.Sprintf("file-%s", filePath))
+			var workspace string
+			if pkg, _ := ra.workspacePackageFor(imp.Path); pkg != nil && isJavaScriptPath(filePath) {
+				workspace = pkg.Name
+			}
 			if targetFile != "" {
 				// Create or update import relationship
 				edgeId := types.EdgeId(fmt.Sprintf("import-%s-%s", filePath, targetFile))
-
-				if _, exists := ra.graph.Edges[edgeId]; !exists {
-					edge := &types.GraphEdge{
-						Id:     edgeId,
-						From:   types.NodeId(fmt.Sprintf("file-%s", filePath)),
-						To:     types.NodeId(fmt.Sprintf("file-%s", targetFile)),
-						Type:   string(RelationshipImport),
-						Weight: 1.0,
-						Metadata: map[string]interface{}{
-							"import_path":   imp.Path,
-							"specifiers":    imp.Specifiers,
-							"is_default":    imp.IsDefault,
-							"resolved_path": targetFile,
-						},
-					}
-					ra.graph.Edges[edgeId] = edge
+				extra := map[string]interface{}{"resolved_path": targetFile}
+				if workspace != "" {
+					extra["workspace_package"] = workspace
 				}
-
-				importCount++
+				ra.addImportEdge(edgeId, from, types.NodeId(fmt.Sprintf("file-%s", targetFile)), 1.0, imp, extra)
 			} else {
 				// External import
 				edgeId := types.EdgeId(fmt.Sprintf("external-import-%s-%s", filePath, imp.Path))
-				edge := &types.GraphEdge{
-					Id:     edgeId,
-					From:   types.NodeId(fmt.Sprintf("file-%s", filePath)),
-					To:     types.NodeId(fmt.Sprintf("external-%s", imp.Path)),
-					Type:   string(RelationshipImport),
-					Weight: 0.5, // Lower weight for external imports
-					Metadata: map[string]interface{}{
-						"import_path": imp.Path,
-						"specifiers":  imp.Specifiers,
-						"is_default":  imp.IsDefault,
-						"is_external": true,
-					},
+				extra := map[string]interface{}{"is_external": true}
+				if workspace != "" {
+					extra["workspace_package"] = workspace
 				}
-				ra.graph.Edges[edgeId] = edge
-				importCount++
+				ra.addImportEdge(edgeId, from, types.NodeId(fmt.Sprintf("external-%s", imp.Path)), 0.5, imp, // Lower weight for external imports
+					extra)
 			}
+			importCount++
 		}
 	}
 
@@ -183,12 +267,12 @@ func (ra *RelationshipAnalyzer) analyzeSymbolUsageRelationships(metrics *Relatio
 							"reference_type": ref.Type,
 							"context":        ref.Context,
 							"source_file":    filePath,
-							"target_file":    targetSymbol.FullyQualifiedName,
+							"target_file":    ra.fileOf(targetSymbol),
 						},
 					}
 					ra.graph.Edges[edgeId] = edge
 
-					if symbol.FullyQualifiedName != targetSymbol.FullyQualifiedName {
+					if types.FilePathFromQualifiedName(symbol.FullyQualifiedName) != types.FilePathFromQualifiedName(targetSymbol.FullyQualifiedName) {
 						referenceCount++
 					}
 					usageCount++
@@ -221,6 +305,9 @@ func (ra *RelationshipAnalyzer) analyzeCallRelationships(metrics *RelationshipMe
 		}
 	}
 
+	// Shell scripts record the commands each function invokes, so their calls are exact
+	callCount += ra.analyzeShellCalls()
+
 	metrics.ByType[RelationshipCalls] = callCount
 }
 
@@ -298,7 +385,7 @@ func (ra *RelationshipAnalyzer) identifyHotspotFiles(metrics *RelationshipMetric
 
 	// Count incoming and outgoing dependencies
 	for _, edge := range ra.graph.Edges {
-		if edge.Type == string(RelationshipImport) {
+		if edge.Type == string(RelationshipImport) || edge.Type == string(RelationshipHasPart) {
 			fromFile := ra.extractFileFromNodeId(edge.From)
 			toFile := ra.extractFileFromNodeId(edge.To)
 
@@ -312,10 +399,14 @@ func (ra *RelationshipAnalyzer) identifyHotspotFiles(metrics *RelationshipMetric
 	}
 
 	// Calculate scores and identify top hotspots
-	for _, hotspot := range fileScores {
+	for filePath, hotspot := range fileScores {
 		// Simple scoring: imports + references * 2 (being referenced is more important)
 		hotspot.Score = float64(hotspot.ImportCount) + float64(hotspot.ReferenceCount)*2.0
 
+		// Untested code is riskier to depend on: up to twice the score when no line is covered
+		hotspot.Coverage = ra.graph.Files[filePath].Coverage
+		hotspot.Score *= 1 + hotspot.Coverage.Untested()
+
 		// Only include files with significant activity
 		if hotspot.Score >= 2.0 {
 			metrics.HotspotFiles = append(metrics.HotspotFiles, *hotspot)
@@ -329,7 +420,7 @@ func (ra *RelationshipAnalyzer) findIsolatedFiles(metrics *RelationshipMetrics)
 
 	// Mark files that have any edges
 	for _, edge := range ra.graph.Edges {
-		if edge.Type == string(RelationshipImport) {
+		if edge.Type == string(RelationshipImport) || edge.Type == string(RelationshipHasPart) {
 			fromFile := ra.extractFileFromNodeId(edge.From)
 			toFile := ra.extractFileFromNodeId(edge.To)
 
@@ -479,7 +570,20 @@ func (ra *RelationshipAnalyzer) resolveImportPath(importPath, fromFile string) s
 				return candidate
 			}
 		}
+		return ""
 	}
 
+	// Imports of sibling packages in a monorepo, such as "@myorg/utils"
+	if isJavaScriptPath(fromFile) {
+		return ra.resolveWorkspaceImport(importPath)
+	}
 	return ""
 }
+
+// appendUnique appends value to values unless it is already present
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"path"
+	"sort"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// BrokenDependency is a dependency of remaining code on removed code
+type BrokenDependency struct {
+	File   string `json:"file"`   // Dependent file, relative to the project root
+	Target string `json:"target"` // Removed file it depends on
+	Via    string `json:"via"`    // Relationship type
+	Detail string `json:"detail,omitempty"`
+	Test   bool   `json:"test,omitempty"` // The dependent file is a test
+}
+
+// DependentPackage is a package that depends on removed code
+type DependentPackage struct {
+	Package      string             `json:"package"` // Directory relative to the project root, "." for the root
+	Files        int                `json:"files"`   // Files of the package that would break
+	Dependencies []BrokenDependency `json:"dependencies"`
+}
+
+// RemovalImpact is what removing a set of files would break
+type RemovalImpact struct {
+	Removed  []string           `json:"removed"`  // Relative to the project root
+	Broken   int                `json:"broken"`   // Broken dependencies in all packages
+	Packages []DependentPackage `json:"packages"` // Most broken dependencies first
+}
+
+// SimulateRemoval reports every dependency that deleting the given files,
+// as graph paths, would break: the imports, calls, references and other
+// relationships leading into them from files that stay, the same steps
+// AnalyzeReachability follows. Files of the same Go package depending on
+// each other only by sharing the package are not counted. Dependencies are
+// grouped by the package of the dependent file.
+func SimulateRemoval(graph *types.CodeGraph, root string, files []string) RemovalImpact {
+	removed := make(map[string]bool)
+	impact := RemovalImpact{}
+	for _, file := range files {
+		if graph.Files[file] != nil && !removed[file] {
+			removed[file] = true
+			impact.Removed = append(impact.Removed, projectPath(root, file))
+		}
+	}
+	sort.Strings(impact.Removed)
+
+	byPackage := make(map[string]*DependentPackage)
+	packageFiles := make(map[string]map[string]bool)
+	for from, steps := range fileSteps(graph) {
+		if removed[from] {
+			continue
+		}
+		rel := projectPath(root, from)
+		for _, step := range steps {
+			if !removed[step.to] || step.via == "package" {
+				continue
+			}
+			pkg := path.Dir(rel)
+			if byPackage[pkg] == nil {
+				byPackage[pkg] = &DependentPackage{Package: pkg}
+				packageFiles[pkg] = make(map[string]bool)
+			}
+			byPackage[pkg].Dependencies = append(byPackage[pkg].Dependencies, BrokenDependency{
+				File:   rel,
+				Target: projectPath(root, step.to),
+				Via:    step.via,
+				Detail: step.detail,
+				Test:   graph.Files[from].IsTest,
+			})
+			packageFiles[pkg][rel] = true
+		}
+	}
+
+	for pkg, dependent := range byPackage {
+		dependent.Files = len(packageFiles[pkg])
+		sort.Slice(dependent.Dependencies, func(i, j int) bool {
+			a, b := dependent.Dependencies[i], dependent.Dependencies[j]
+			if a.File != b.File {
+				return a.File < b.File
+			}
+			if a.Target != b.Target {
+				return a.Target < b.Target
+			}
+			if a.Via != b.Via {
+				return a.Via < b.Via
+			}
+			return a.Detail < b.Detail
+		})
+		impact.Broken += len(dependent.Dependencies)
+		impact.Packages = append(impact.Packages, *dependent)
+	}
+	sort.Slice(impact.Packages, func(i, j int) bool {
+		a, b := impact.Packages[i], impact.Packages[j]
+		if len(a.Dependencies) != len(b.Dependencies) {
+			return len(a.Dependencies) > len(b.Dependencies)
+		}
+		return a.Package < b.Package
+	})
+	return impact
+}
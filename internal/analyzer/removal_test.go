@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateRemoval(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                         "module example.com/app\n\ngo 1.24\n",
+		"cmd/app/main.go":                "package main\n\nimport \"example.com/app/internal/server\"\n\nfunc main() { server.Serve() }\n",
+		"internal/server/server.go":      "package server\n\nfunc Serve() { routes() }\n",
+		"internal/server/routes.go":      "package server\n\nimport \"example.com/app/internal/store\"\n\nfunc routes() { store.Open() }\n",
+		"internal/server/routes_test.go": "package server\n\nimport (\n\t\"testing\"\n\n\t\"example.com/app/internal/store\"\n)\n\nfunc TestRoutes(t *testing.T) { store.Open() }\n",
+		"internal/store/db.go":           "package store\n\nfunc Open() {}\n",
+		"internal/store/cache.go":        "package store\n\nfunc cache() {}\n",
+		"web/index.js":                   "import { legacy } from './legacy';\n\nexport function start() { return legacy(); }\n",
+		"web/legacy.js":                  "export function legacy() { return 1; }\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	impact := SimulateRemoval(graph, dir, []string{filepath.Join(dir, "internal/store/db.go"), filepath.Join(dir, "internal/store/cache.go")})
+	assert.Equal(t, []string{"internal/store/cache.go", "internal/store/db.go"}, impact.Removed)
+	assert.Equal(t, 4, impact.Broken, "files of the removed package depending on each other do not count")
+	require.Len(t, impact.Packages, 1)
+	server := impact.Packages[0]
+	assert.Equal(t, "internal/server", server.Package)
+	assert.Equal(t, 2, server.Files)
+	assert.Equal(t, BrokenDependency{File: "internal/server/routes.go", Target: "internal/store/cache.go", Via: "imports", Detail: "example.com/app/internal/store"}, server.Dependencies[0])
+	assert.True(t, server.Dependencies[3].Test)
+
+	impact = SimulateRemoval(graph, dir, []string{filepath.Join(dir, "web/legacy.js")})
+	require.Len(t, impact.Packages, 1)
+	assert.Equal(t, "web", impact.Packages[0].Package)
+	assert.Equal(t, BrokenDependency{File: "web/index.js", Target: "web/legacy.js", Via: "imports", Detail: "./legacy"}, impact.Packages[0].Dependencies[0])
+
+	impact = SimulateRemoval(graph, dir, []string{filepath.Join(dir, "cmd/app/main.go")})
+	assert.Zero(t, impact.Broken)
+	assert.Empty(t, impact.Packages)
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// SCIP field numbers and enum values, from scip.proto
+const (
+	scipIndexMetadata  = 1
+	scipIndexDocuments = 2
+
+	scipMetadataToolInfo     = 2
+	scipMetadataProjectRoot  = 3
+	scipMetadataTextEncoding = 4
+	scipTextEncodingUTF8     = 1
+
+	scipToolInfoName    = 1
+	scipToolInfoVersion = 2
+
+	scipDocumentRelativePath     = 1
+	scipDocumentOccurrences      = 2
+	scipDocumentSymbols          = 3
+	scipDocumentLanguage         = 4
+	scipDocumentPositionEncoding = 6
+	scipPositionEncodingUTF8     = 1
+
+	scipOccurrenceRange       = 1
+	scipOccurrenceSymbol      = 2
+	scipOccurrenceSymbolRoles = 3
+	scipSymbolRoleDefinition  = 1
+
+	scipSymbolSymbol          = 1
+	scipSymbolDocumentation   = 3
+	scipSymbolRelationships   = 4
+	scipSymbolDisplayName     = 6
+	scipSymbolEnclosingSymbol = 8
+
+	scipRelationshipSymbol           = 1
+	scipRelationshipIsImplementation = 3
+)
+
+// scipLanguages maps language names to the names in SCIP's Language enum
+var scipLanguages = map[string]string{
+	"c": "C", "cpp": "CPP", "csharp": "CSharp", "dart": "Dart", "go": "Go",
+	"java": "Java", "javascript": "JavaScript", "kotlin": "Kotlin", "php": "PHP",
+	"python": "Python", "ruby": "Ruby", "rust": "Rust", "shell": "ShellScript",
+	"swift": "Swift", "typescript": "TypeScript",
+}
+
+// WriteSCIP writes the index in the SCIP protobuf format, with one document
+// per source file
+func (e *IndexExporter) WriteSCIP(w io.Writer) error {
+	var toolInfo []byte
+	toolInfo = protoString(toolInfo, scipToolInfoName, IndexScheme)
+	toolInfo = protoString(toolInfo, scipToolInfoVersion, e.version)
+	var metadata []byte
+	metadata = protoBytes(metadata, scipMetadataToolInfo, toolInfo)
+	metadata = protoString(metadata, scipMetadataProjectRoot, e.projectRoot())
+	metadata = protoVarint(metadata, scipMetadataTextEncoding, scipTextEncodingUTF8)
+
+	index := protoBytes(nil, scipIndexMetadata, metadata)
+	for _, document := range e.documents() {
+		index = protoBytes(index, scipIndexDocuments, scipDocument(document))
+	}
+	_, err := w.Write(index)
+	return err
+}
+
+// scipDocument encodes a file's definition occurrences and symbol information
+func scipDocument(document indexDocument) []byte {
+	language := document.language
+	if name, ok := scipLanguages[language]; ok {
+		language = name
+	}
+	var encoded []byte
+	encoded = protoString(encoded, scipDocumentRelativePath, document.path)
+	encoded = protoString(encoded, scipDocumentLanguage, language)
+	encoded = protoVarint(encoded, scipDocumentPositionEncoding, scipPositionEncodingUTF8)
+
+	seen := make(map[string]bool)
+	for _, definition := range document.definitions {
+		tag := definition.tag
+		var ranges []byte
+		for _, n := range []int{tag.Line - 1, tag.column, tag.column + len(tag.Name)} {
+			ranges = binary.AppendUvarint(ranges, uint64(n))
+		}
+		var occurrence []byte
+		occurrence = protoBytes(occurrence, scipOccurrenceRange, ranges)
+		occurrence = protoString(occurrence, scipOccurrenceSymbol, definition.symbol)
+		occurrence = protoVarint(occurrence, scipOccurrenceSymbolRoles, scipSymbolRoleDefinition)
+		encoded = protoBytes(encoded, scipDocumentOccurrences, occurrence)
+
+		// A declaration and its definition share a symbol, described once
+		if seen[definition.symbol] {
+			continue
+		}
+		seen[definition.symbol] = true
+		var information []byte
+		information = protoString(information, scipSymbolSymbol, definition.symbol)
+		if definition.declaration != "" {
+			information = protoString(information, scipSymbolDocumentation, "```"+document.language+"\n"+definition.declaration+"\n```")
+		}
+		information = protoString(information, scipSymbolDocumentation, definition.documentation)
+		for _, supertype := range definition.implements {
+			var relationship []byte
+			relationship = protoString(relationship, scipRelationshipSymbol, supertype)
+			relationship = protoVarint(relationship, scipRelationshipIsImplementation, 1)
+			information = protoBytes(information, scipSymbolRelationships, relationship)
+		}
+		information = protoString(information, scipSymbolDisplayName, tag.Name)
+		information = protoString(information, scipSymbolEnclosingSymbol, definition.enclosing)
+		encoded = protoBytes(encoded, scipDocumentSymbols, information)
+	}
+	return encoded
+}
+
+// protoVarint appends a varint field
+func protoVarint(b []byte, field int, value uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3)
+	return binary.AppendUvarint(b, value)
+}
+
+// protoBytes appends a length-delimited field, such as an embedded message
+func protoBytes(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// protoString appends a string field, leaving out empty strings as proto3 does
+func protoString(b []byte, field int, value string) []byte {
+	if value == "" {
+		return b
+	}
+	return protoBytes(b, field, []byte(value))
+}
@@ -0,0 +1,419 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipCallsService links an HTTP/gRPC client call to the endpoint that serves it
+const RelationshipCallsService RelationshipType = "calls-service"
+
+const (
+	protocolHTTP = "http"
+	protocolGRPC = "grpc"
+)
+
+// scannedSourceLanguages are the languages scanned for service calls, events and feature flags
+var scannedSourceLanguages = map[string]bool{
+	"go": true, "javascript": true, "typescript": true, "python": true, "java": true, "kotlin": true,
+}
+
+const quoted = "[\"'`]([^\"'`\\s]+)[\"'`]"
+
+var (
+	// HTTP endpoint definitions: net/http, gin/echo/chi/fiber, express, Flask/FastAPI, Spring
+	httpHandleFuncPattern = regexp.MustCompile(`\.Handle(?:Func)?\(\s*"(?:(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\s+)?(/[^"]*)"`)
+	httpRouterPattern     = regexp.MustCompile(`\b(?:app|router|r|e|g|mux|server|group|v\d+)\.(get|post|put|patch|delete|GET|POST|PUT|PATCH|DELETE|Get|Post|Put|Patch|Delete)\(\s*` + quoted + `\s*,`)
+	httpDecoratorPattern  = regexp.MustCompile(`@\w+\.(route|get|post|put|patch|delete)\(\s*` + quoted)
+	springMappingPattern  = regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Request)Mapping\(\s*(?:(?:value|path)\s*=\s*)?"(/[^"]*)"`)
+
+	// HTTP client calls: fetch, axios (and axios.create instances), net/http, requests/httpx
+	fetchPattern         = regexp.MustCompile(`\bfetch\(\s*` + quoted)
+	axiosPattern         = regexp.MustCompile(`\baxios\.(get|post|put|patch|delete)\(\s*` + quoted)
+	axiosInstancePattern = regexp.MustCompile(`(\w+)\s*=\s*axios\.create\(\s*\{[^}]*?baseURL\s*:\s*` + quoted)
+	goHTTPPattern        = regexp.MustCompile(`\bhttp\.(Get|Post|Head)\(\s*"([^"]+)"`)
+	goNewRequestPattern  = regexp.MustCompile(`\bhttp\.NewRequest(?:WithContext)?\((?:\s*ctx\s*,)?\s*(?:"(\w+)"|http\.Method(\w+))\s*,\s*"([^"]+)"`)
+	pythonHTTPPattern    = regexp.MustCompile(`\b(?:requests|httpx|session|client)\.(get|post|put|patch|delete)\(\s*f?` + quoted)
+
+	// gRPC servers and clients across the common code generators
+	grpcServerPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bRegister(\w+)Server\(`),                  // Go
+		regexp.MustCompile(`\badd_(\w+)Servicer_to_server\(`),          // Python
+		regexp.MustCompile(`\bextends\s+(\w+)Grpc\.\w+ImplBase\b`),     // Java
+		regexp.MustCompile(`\baddService\(\s*[\w.]*?(\w+)\.service\b`), // Node
+	}
+	grpcClientPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bNew(\w+)Client\(`),                         // Go
+		regexp.MustCompile(`\b(\w+)Stub\(`),                              // Python
+		regexp.MustCompile(`\b(\w+)Grpc\.new(?:Blocking|Future)?Stub\(`), // Java
+	}
+)
+
+// serviceEndpoint is an HTTP route or gRPC service found in source code
+type serviceEndpoint struct {
+	Protocol string
+	Method   string // HTTP method, empty when any method matches
+	Path     string // HTTP route or gRPC service name
+	File     string
+	Line     int
+	segments []string
+}
+
+// Endpoint is an HTTP route or gRPC service defined in source code
+type Endpoint struct {
+	Protocol string `json:"protocol"`
+	Method   string `json:"method,omitempty"` // HTTP method, empty when any method matches
+	Path     string `json:"path"`             // HTTP route or gRPC service name
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String renders the endpoint as "GET /orders" or "grpc OrderService"
+func (e Endpoint) String() string {
+	if e.Protocol == protocolGRPC {
+		return protocolGRPC + " " + e.Path
+	}
+	return strings.TrimSpace(e.Method + " " + e.Path)
+}
+
+// ServedEndpoints lists the HTTP routes and gRPC services defined in the
+// graph's source files, sorted by file and line
+func ServedEndpoints(graph *types.CodeGraph) []Endpoint {
+	var endpoints []Endpoint
+	(&RelationshipAnalyzer{graph: graph}).forEachSourceFile(func(filePath, content string) {
+		servers, _ := extractServiceEndpoints(content, filePath)
+		for _, server := range servers {
+			endpoints = append(endpoints, Endpoint{
+				Protocol: server.Protocol, Method: server.Method, Path: server.Path, File: server.File, Line: server.Line,
+			})
+		}
+	})
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].File != endpoints[j].File {
+			return endpoints[i].File < endpoints[j].File
+		}
+		return endpoints[i].Line < endpoints[j].Line
+	})
+	return endpoints
+}
+
+// ServiceCall describes a client call matched to the endpoint that serves it
+type ServiceCall struct {
+	Protocol   string `json:"protocol"`
+	Method     string `json:"method,omitempty"`
+	Path       string `json:"path"`
+	ClientFile string `json:"client_file"`
+	ClientLine int    `json:"client_line"`
+	ServerFile string `json:"server_file"`
+	ServerLine int    `json:"server_line"`
+}
+
+// forEachSourceFile calls fn with the content of every non-test file in a scanned language
+func (ra *RelationshipAnalyzer) forEachSourceFile(fn func(filePath, content string)) {
+	ra.forEachSourceFileIn(scannedSourceLanguages, fn)
+}
+
+// forEachSourceFileIn calls fn with the content of every non-test file in one of languages
+func (ra *RelationshipAnalyzer) forEachSourceFileIn(languages map[string]bool, fn func(filePath, content string)) {
+	for filePath, fileNode := range ra.graph.Files {
+		if !languages[fileNode.Language] || fileNode.IsTest {
+			continue
+		}
+		content, ok := ra.sourceContent(filePath)
+		if !ok {
+			continue
+		}
+		fn(filePath, content)
+	}
+}
+
+// sourceContent returns the content of a file, reading it on first use so the
+// scans of one analysis share a single read per file
+func (ra *RelationshipAnalyzer) sourceContent(filePath string) (string, bool) {
+	if content, ok := ra.sources[filePath]; ok {
+		return content, true
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", false
+	}
+	if ra.sources == nil {
+		ra.sources = make(map[string]string)
+	}
+	ra.sources[filePath] = string(data)
+	return ra.sources[filePath], true
+}
+
+// analyzeServiceBoundaries matches HTTP/gRPC clients to endpoint definitions in other files
+// and creates calls-service edges between the enclosing symbols
+func (ra *RelationshipAnalyzer) analyzeServiceBoundaries(metrics *RelationshipMetrics) {
+	var servers, clients []serviceEndpoint
+	ra.forEachSourceFile(func(filePath, content string) {
+		fileServers, fileClients := extractServiceEndpoints(content, filePath)
+		servers = append(servers, fileServers...)
+		clients = append(clients, fileClients...)
+	})
+	if len(servers) == 0 || len(clients) == 0 {
+		return
+	}
+
+	for _, client := range clients {
+		server := matchServiceEndpoint(client, servers)
+		if server == nil {
+			continue
+		}
+
+		from := ra.enclosingNode(client.File, client.Line)
+		to := ra.enclosingNode(server.File, server.Line)
+		edgeId := types.EdgeId(fmt.Sprintf("%s-%s:%d-%s", RelationshipCallsService, client.File, client.Line, to))
+		ra.graph.Edges[edgeId] = &types.GraphEdge{
+			Id:     edgeId,
+			From:   from,
+			To:     to,
+			Type:   string(RelationshipCallsService),
+			Weight: 1.0,
+			Metadata: map[string]interface{}{
+				"protocol":    client.Protocol,
+				"method":      firstNonEmpty(client.Method, server.Method),
+				"path":        server.Path,
+				"client_path": client.Path,
+				"source_file": client.File,
+				"source_line": client.Line,
+				"target_file": server.File,
+				"target_line": server.Line,
+			},
+		}
+		metrics.ByType[RelationshipCallsService]++
+		metrics.CrossFileRefs++
+	}
+}
+
+// extractServiceEndpoints finds endpoint definitions and client calls in a source file
+func extractServiceEndpoints(content, filePath string) (servers, clients []serviceEndpoint) {
+	add := func(list *[]serviceEndpoint, protocol, method, path string, offset int) {
+		endpoint := serviceEndpoint{
+			Protocol: protocol,
+			Method:   strings.ToUpper(method),
+			Path:     path,
+			File:     filePath,
+			Line:     strings.Count(content[:offset], "\n") + 1,
+		}
+		if method == "route" || method == "Request" {
+			endpoint.Method = ""
+		}
+		if protocol == protocolHTTP {
+			endpoint.segments = routeSegments(path)
+		}
+		*list = append(*list, endpoint)
+	}
+
+	for _, m := range httpHandleFuncPattern.FindAllStringSubmatchIndex(content, -1) {
+		add(&servers, protocolHTTP, submatch(content, m, 1), submatch(content, m, 2), m[0])
+	}
+	for _, pattern := range []*regexp.Regexp{httpRouterPattern, httpDecoratorPattern, springMappingPattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			add(&servers, protocolHTTP, submatch(content, m, 1), submatch(content, m, 2), m[0])
+		}
+	}
+
+	for _, m := range fetchPattern.FindAllStringSubmatchIndex(content, -1) {
+		add(&clients, protocolHTTP, "", submatch(content, m, 1), m[0])
+	}
+	for _, pattern := range []*regexp.Regexp{axiosPattern, goHTTPPattern, pythonHTTPPattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			add(&clients, protocolHTTP, submatch(content, m, 1), submatch(content, m, 2), m[0])
+		}
+	}
+	for _, m := range goNewRequestPattern.FindAllStringSubmatchIndex(content, -1) {
+		add(&clients, protocolHTTP, submatch(content, m, 1)+submatch(content, m, 2), submatch(content, m, 3), m[0])
+	}
+	for _, m := range axiosInstancePattern.FindAllStringSubmatchIndex(content, -1) {
+		instance, baseURL := submatch(content, m, 1), submatch(content, m, 2)
+		calls := regexp.MustCompile(`\b` + regexp.QuoteMeta(instance) + `\.(get|post|put|patch|delete)\(\s*` + quoted)
+		for _, call := range calls.FindAllStringSubmatchIndex(content, -1) {
+			path := strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(submatch(content, call, 2), "/")
+			add(&clients, protocolHTTP, submatch(content, call, 1), path, call[0])
+		}
+	}
+
+	for _, pattern := range grpcServerPatterns {
+		for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			add(&servers, protocolGRPC, "", submatch(content, m, 1), m[0])
+		}
+	}
+	for _, pattern := range grpcClientPatterns {
+		for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			add(&clients, protocolGRPC, "", submatch(content, m, 1), m[0])
+		}
+	}
+	return servers, clients
+}
+
+func submatch(content string, indices []int, group int) string {
+	if 2*group+1 >= len(indices) || indices[2*group] < 0 {
+		return ""
+	}
+	return content[indices[2*group]:indices[2*group+1]]
+}
+
+// routeSegments normalizes a route or URL into path segments, replacing parameters
+// (":id", "{id}", "<int:id>", "${id}", "[id]") with "*"
+func routeSegments(route string) []string {
+	if parsed, err := url.Parse(route); err == nil && parsed.Host != "" {
+		route = parsed.Path
+	} else if i := strings.Index(route, "://"); i >= 0 {
+		// Template URLs such as `${BASE}/users` do not parse; drop everything up to the path
+		rest := route[i+3:]
+		route = rest[strings.Index(rest+"/", "/"):]
+	}
+	route = strings.SplitN(route, "?", 2)[0]
+	route = strings.SplitN(route, "#", 2)[0]
+
+	var segments []string
+	for _, segment := range strings.Split(route, "/") {
+		switch {
+		case segment == "":
+			continue
+		case strings.HasPrefix(segment, ":"), strings.HasPrefix(segment, "{"), strings.HasPrefix(segment, "<"),
+			strings.HasPrefix(segment, "["), strings.Contains(segment, "${"):
+			segments = append(segments, "*")
+		default:
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// matchServiceEndpoint returns the server endpoint a client call targets, or nil.
+// Clients never match endpoints in their own file.
+func matchServiceEndpoint(client serviceEndpoint, servers []serviceEndpoint) *serviceEndpoint {
+	var best *serviceEndpoint
+	bestScore := -1
+	for i := range servers {
+		server := &servers[i]
+		if server.Protocol != client.Protocol || server.File == client.File {
+			continue
+		}
+		if client.Protocol == protocolGRPC {
+			if server.Path == client.Path {
+				return server
+			}
+			continue
+		}
+		if client.Method != "" && server.Method != "" && client.Method != server.Method {
+			continue
+		}
+		if score, ok := routeMatchScore(client.segments, server.segments); ok && score > bestScore {
+			best, bestScore = server, score
+		}
+	}
+	return best
+}
+
+// routeMatchScore matches a client path against a route. Clients may carry a path prefix
+// (e.g. a gateway or base URL) before the route, so the route is aligned to the end.
+// Literal segment matches score higher than parameter matches.
+func routeMatchScore(client, route []string) (int, bool) {
+	if len(route) == 0 || len(client) < len(route) || len(client) > len(route)+2 {
+		return 0, false
+	}
+	offset := len(client) - len(route)
+	score := 0
+	for i, segment := range route {
+		other := client[offset+i]
+		switch {
+		case segment == other && segment != "*":
+			score += 2
+		case segment == "*" || other == "*":
+			score++
+		default:
+			return 0, false
+		}
+	}
+	return score - offset, true
+}
+
+// enclosingNode returns the node of the function or method containing line, or the file node
+func (ra *RelationshipAnalyzer) enclosingNode(filePath string, line int) types.NodeId {
+	if symbol := ra.enclosingSymbol(filePath, line); symbol != nil {
+		return types.NodeId(fmt.Sprintf("symbol-%s", symbol.Id))
+	}
+	return types.NodeId(fmt.Sprintf("file-%s", filePath))
+}
+
+// enclosingSymbol returns the function or method containing line, or nil at file level
+func (ra *RelationshipAnalyzer) enclosingSymbol(filePath string, line int) *types.Symbol {
+	fileNode, ok := ra.graph.Files[filePath]
+	if !ok {
+		return nil
+	}
+	var enclosing *types.Symbol
+	for _, symbolId := range fileNode.Symbols {
+		symbol := ra.graph.Symbols[symbolId]
+		if symbol == nil || symbol.IsEmbedded() || (symbol.Type != types.SymbolTypeFunction && symbol.Type != types.SymbolTypeMethod) {
+			continue
+		}
+		if symbol.Location.StartLine > line {
+			continue
+		}
+		// Most parsers only record the start line, so an end line is trusted only when it spans lines
+		if symbol.Location.EndLine > symbol.Location.StartLine && symbol.Location.EndLine < line {
+			continue
+		}
+		if enclosing == nil || symbol.Location.StartLine > enclosing.Location.StartLine {
+			enclosing = symbol
+		}
+	}
+	return enclosing
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// ServiceCallsFor returns the outgoing and incoming service calls of filePath
+func ServiceCallsFor(graph *types.CodeGraph, filePath string) (outgoing, incoming []ServiceCall) {
+	for _, edge := range graph.Edges {
+		if edge.Type != string(RelationshipCallsService) {
+			continue
+		}
+		call := ServiceCall{}
+		call.Protocol, _ = edge.Metadata["protocol"].(string)
+		call.Method, _ = edge.Metadata["method"].(string)
+		call.Path, _ = edge.Metadata["path"].(string)
+		call.ClientFile, _ = edge.Metadata["source_file"].(string)
+		call.ClientLine, _ = edge.Metadata["source_line"].(int)
+		call.ServerFile, _ = edge.Metadata["target_file"].(string)
+		call.ServerLine, _ = edge.Metadata["target_line"].(int)
+
+		if call.ClientFile == filePath {
+			outgoing = append(outgoing, call)
+		}
+		if call.ServerFile == filePath {
+			incoming = append(incoming, call)
+		}
+	}
+	sortServiceCalls(outgoing)
+	sortServiceCalls(incoming)
+	return outgoing, incoming
+}
+
+func sortServiceCalls(calls []ServiceCall) {
+	sort.Slice(calls, func(i, j int) bool {
+		if calls[i].ClientFile != calls[j].ClientFile {
+			return calls[i].ClientFile < calls[j].ClientFile
+		}
+		return calls[i].ClientLine < calls[j].ClientLine
+	})
+}
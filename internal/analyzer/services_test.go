@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteSegments(t *testing.T) {
+	assert.Equal(t, []string{"api", "users", "*"}, routeSegments("/api/users/:id"))
+	assert.Equal(t, []string{"users", "*", "posts"}, routeSegments("/users/{userId}/posts"))
+	assert.Equal(t, []string{"users", "*"}, routeSegments("/users/<int:user_id>"))
+	assert.Equal(t, []string{"users", "42"}, routeSegments("http://user-service:8080/users/42?expand=true"))
+	assert.Equal(t, []string{"*", "users", "*"}, routeSegments("${API_URL}/users/${id}"))
+}
+
+func TestRouteMatchScore(t *testing.T) {
+	score, ok := routeMatchScore(routeSegments("/users/42"), routeSegments("/users/:id"))
+	assert.True(t, ok)
+	literal, ok := routeMatchScore(routeSegments("/users/me"), routeSegments("/users/me"))
+	assert.True(t, ok)
+	assert.Greater(t, literal, score, "literal routes beat parameterized ones")
+
+	_, ok = routeMatchScore(routeSegments("/gateway/users/42"), routeSegments("/users/:id"))
+	assert.True(t, ok, "clients may call through a path prefix")
+	_, ok = routeMatchScore(routeSegments("/orders/42"), routeSegments("/users/:id"))
+	assert.False(t, ok)
+}
+
+func TestServiceBoundaryEdges(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"services/users/main.go": `package main
+
+import "net/http"
+
+func listUsers(w http.ResponseWriter, r *http.Request) {}
+
+func main() {
+	http.HandleFunc("GET /users/{id}", listUsers)
+	pb.RegisterBillingServiceServer(server, &billing{})
+}
+`,
+		"web/src/api.ts": `export async function loadUser(id: string) {
+  const res = await fetch(` + "`${USERS_URL}/users/${id}`" + `);
+  return res.json();
+}
+
+export async function ping() {
+  return fetch("/health");
+}
+`,
+		"services/orders/client.go": `package orders
+
+func newBilling(conn *grpc.ClientConn) {
+	client := pb.NewBillingServiceClient(conn)
+	_ = client
+}
+`,
+	}
+	testutils.WriteTree(t, dir, files)
+
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	serverFile := filepath.Join(dir, "services", "users", "main.go")
+	clientFile := filepath.Join(dir, "web", "src", "api.ts")
+
+	outgoing, _ := ServiceCallsFor(graph, clientFile)
+	require.Len(t, outgoing, 1, "only the /users call has a matching endpoint")
+	assert.Equal(t, "http", outgoing[0].Protocol)
+	assert.Equal(t, "GET", outgoing[0].Method)
+	assert.Equal(t, "/users/{id}", outgoing[0].Path)
+	assert.Equal(t, serverFile, outgoing[0].ServerFile)
+	assert.Equal(t, 8, outgoing[0].ServerLine)
+	assert.Equal(t, 2, outgoing[0].ClientLine)
+
+	_, incoming := ServiceCallsFor(graph, serverFile)
+	require.Len(t, incoming, 2)
+	assert.Equal(t, "grpc", incoming[0].Protocol)
+	assert.Equal(t, "BillingService", incoming[0].Path)
+	assert.Equal(t, filepath.Join(dir, "services", "orders", "client.go"), incoming[0].ClientFile)
+
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipCallsService) && edge.Metadata["source_file"] == clientFile {
+			assert.Contains(t, string(edge.From), "loadUser", "edges start at the enclosing function")
+		}
+	}
+}
+
+func TestSourceFilesReadOnce(t *testing.T) {
+	dir := t.TempDir()
+	testutils.WriteTree(t, dir, map[string]string{"main.go": "package main\n", "main_test.go": "package main\n"})
+	path := filepath.Join(dir, "main.go")
+	graph := &types.CodeGraph{Files: map[string]*types.FileNode{
+		path:                               {Path: path, Language: "go"},
+		filepath.Join(dir, "main_test.go"): {Path: filepath.Join(dir, "main_test.go"), Language: "go", IsTest: true},
+	}}
+	ra := &RelationshipAnalyzer{graph: graph}
+
+	var seen []string
+	ra.forEachSourceFile(func(filePath, content string) { seen = append(seen, filePath) })
+	assert.Equal(t, []string{path}, seen, "test files are skipped")
+
+	// Later scans of the same analysis reuse the first read
+	require.NoError(t, os.WriteFile(path, []byte("package changed\n"), 0644))
+	ra.forEachSourceFileIn(map[string]bool{"go": true}, func(filePath, content string) {
+		assert.Equal(t, "package main\n", content)
+	})
+}
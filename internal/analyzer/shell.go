@@ -0,0 +1,98 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// isShellPath reports whether a file is a shell script handled by the shell parser
+func isShellPath(path string) bool {
+	switch filepath.Ext(path) {
+	case ".sh", ".bash", ".zsh":
+		return true
+	}
+	return false
+}
+
+// resolveSourcePath resolves the target of `source`/`.` relative to the sourcing script,
+// then relative to its ancestor directories since scripts are often run from the repo root
+func (ra *RelationshipAnalyzer) resolveSourcePath(sourcePath, fromFile string) string {
+	if sourcePath == "" || strings.Contains(sourcePath, "$") {
+		return "" // Still depends on a runtime variable
+	}
+	if filepath.IsAbs(sourcePath) {
+		if _, exists := ra.graph.Files[filepath.Clean(sourcePath)]; exists {
+			return filepath.Clean(sourcePath)
+		}
+		return ""
+	}
+
+	rel := filepath.FromSlash(sourcePath)
+	for dir := filepath.Dir(fromFile); ; dir = filepath.Dir(dir) {
+		candidate := filepath.Join(dir, rel)
+		if _, exists := ra.graph.Files[candidate]; exists {
+			return candidate
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+	return ""
+}
+
+// analyzeShellCalls links shell functions to the functions they invoke, preferring a
+// definition in the same script over one in another script
+func (ra *RelationshipAnalyzer) analyzeShellCalls() int {
+	functions := make(map[string][]*types.Symbol)
+	for _, symbol := range ra.graph.Symbols {
+		if symbol.Language == "shell" && symbol.Type == types.SymbolTypeFunction {
+			functions[symbol.Name] = append(functions[symbol.Name], symbol)
+		}
+	}
+
+	callCount := 0
+	for _, candidates := range functions {
+		for _, caller := range candidates {
+			callerFile := ra.fileOf(caller)
+			for _, command := range caller.MetadataStrings(parser.MetadataShellCommands) {
+				target := ra.pickShellFunction(functions[command], callerFile)
+				if target == nil || target == caller {
+					continue
+				}
+
+				edgeId := types.EdgeId(fmt.Sprintf("call-%s-%s", caller.Id, target.Id))
+				ra.graph.Edges[edgeId] = &types.GraphEdge{
+					Id:     edgeId,
+					From:   types.NodeId(fmt.Sprintf("symbol-%s", caller.Id)),
+					To:     types.NodeId(fmt.Sprintf("symbol-%s", target.Id)),
+					Type:   string(RelationshipCalls),
+					Weight: 1.0,
+					Metadata: map[string]interface{}{
+						"command":     command,
+						"source_file": callerFile,
+						"target_file": ra.fileOf(target),
+					},
+				}
+				callCount++
+			}
+		}
+	}
+	return callCount
+}
+
+// pickShellFunction chooses the definition a command refers to, or nil if it is ambiguous
+func (ra *RelationshipAnalyzer) pickShellFunction(candidates []*types.Symbol, callerFile string) *types.Symbol {
+	for _, candidate := range candidates {
+		if ra.fileOf(candidate) == callerFile {
+			return candidate
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellSourceAndCallEdges(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"scripts/deploy.sh":     "#!/bin/bash\nsource \"$(dirname \"$0\")/lib/log.sh\"\n. scripts/env.sh\n\ndeploy() {\n    log_info \"deploying\"\n    kubectl apply -f k8s/\n}\n",
+		"scripts/lib/log.sh":    "log_info() {\n    echo \"[info] $*\"\n}\n",
+		"scripts/env.sh":        "export APP_ENV=prod\n",
+		"scripts/unrelated.zsh": "source \"$CONFIG_DIR/settings.zsh\"\n",
+	}
+	testutils.WriteTree(t, dir, files)
+
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	deployPath := filepath.Join(dir, "scripts", "deploy.sh")
+	importTargets := make(map[types.NodeId]bool)
+	var callEdge *types.GraphEdge
+	for _, edge := range graph.Edges {
+		switch {
+		case edge.Type == string(RelationshipImport) && edge.From == types.NodeId("file-"+deployPath):
+			importTargets[edge.To] = true
+		case edge.Type == string(RelationshipCalls):
+			callEdge = edge
+		}
+	}
+
+	assert.True(t, importTargets[types.NodeId("file-"+filepath.Join(dir, "scripts", "lib", "log.sh"))], "sourced sibling script should resolve")
+	assert.True(t, importTargets[types.NodeId("file-"+filepath.Join(dir, "scripts", "env.sh"))], "root-relative source should resolve")
+
+	require.NotNil(t, callEdge, "deploy should call log_info from the sourced script")
+	assert.Equal(t, "log_info", callEdge.Metadata["command"])
+	assert.Equal(t, filepath.Join(dir, "scripts", "lib", "log.sh"), callEdge.Metadata["target_file"])
+}
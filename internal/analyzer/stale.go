@@ -0,0 +1,222 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/internal/coverage"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// StaleOptions are the thresholds a file must meet to be reported as stale
+type StaleOptions struct {
+	MinAgeDays    int       // Days since the last change (default 180)
+	MaxReferences int       // Referring files and importing packages (default 0)
+	MaxCoverage   float64   // Percent of covered lines, when coverage is known (default 20)
+	Now           time.Time // Reference time for ages (default time.Now())
+}
+
+// StaleFile is a source file that looks like a candidate for removal
+type StaleFile struct {
+	File         string    `json:"file"` // Relative to the project root
+	Language     string    `json:"language"`
+	Lines        int       `json:"lines"`
+	LastModified time.Time `json:"last_modified"`
+	AgeDays      int       `json:"age_days"`
+	References   int       `json:"references"` // Other non-test files and packages referring to it
+	Coverage     float64   `json:"coverage"`   // Percent of covered lines, -1 when unknown
+}
+
+// entryPointSymbols are functions run without being referenced
+var entryPointSymbols = map[string]bool{"main": true, "init": true}
+
+// FindStaleCode combines how long ago files changed, how many other files
+// refer to them and, when a report is given, their test coverage, to flag
+// files that are probably dead. lastModified holds the last change of each
+// file by path relative to root, usually from git; without it the
+// modification times of the files on disk are used. Tests, generated files,
+// files without symbols and entry points are never reported. With a
+// coverage report, files missing from it count as uncovered; without one,
+// coverage already attached to the graph is used where known. The stalest
+// files come first: fewest references, then oldest.
+func FindStaleCode(graph *types.CodeGraph, root string, lastModified map[string]time.Time, report *coverage.Report, opts StaleOptions) []StaleFile {
+	if opts.MinAgeDays <= 0 {
+		opts.MinAgeDays = 180
+	}
+	if opts.MaxCoverage <= 0 {
+		opts.MaxCoverage = 20
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	references := fileReferences(graph)
+	detected := make(map[string]bool)
+	for _, entryPoint := range FindEntryPoints(graph) {
+		detected[entryPoint.File] = true
+	}
+
+	var stale []StaleFile
+	for path, file := range graph.Files {
+		if file.IsTest || file.IsGenerated || len(file.Symbols) == 0 || detected[path] || isEntryPoint(graph, path, file) {
+			continue
+		}
+		rel := projectPath(root, path)
+
+		var modified time.Time
+		if lastModified != nil {
+			var tracked bool
+			if modified, tracked = lastModified[rel]; !tracked {
+				// Untracked files are new
+				continue
+			}
+		} else if info, err := os.Stat(path); err == nil {
+			modified = info.ModTime()
+		} else {
+			continue
+		}
+		age := int(opts.Now.Sub(modified).Hours() / 24)
+		if age < opts.MinAgeDays {
+			continue
+		}
+
+		if references[path] > opts.MaxReferences {
+			continue
+		}
+
+		percent := -1.0
+		if report != nil {
+			percent = 0
+			if covered := report.Lookup(root, rel); covered != nil {
+				percent = covered.Percent()
+			}
+		} else if file.Coverage != nil {
+			percent = file.Coverage.LinePercent()
+		}
+		if percent > opts.MaxCoverage {
+			continue
+		}
+
+		stale = append(stale, StaleFile{
+			File:         rel,
+			Language:     file.Language,
+			Lines:        file.Lines,
+			LastModified: modified,
+			AgeDays:      age,
+			References:   references[path],
+			Coverage:     percent,
+		})
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		a, b := stale[i], stale[j]
+		if a.References != b.References {
+			return a.References < b.References
+		}
+		if a.AgeDays != b.AgeDays {
+			return a.AgeDays > b.AgeDays
+		}
+		return a.File < b.File
+	})
+	return stale
+}
+
+// fileReferences counts the non-test files and packages referring to each
+// file, through imports, calls or any other relationship to the file or its
+// symbols except containment and documentation. Packages importing the file's
+// package count too, for languages whose imports name packages rather than
+// files, unless one of their files already refers to it.
+func fileReferences(graph *types.CodeGraph) map[string]int {
+	symbolFile := make(map[types.SymbolId]string)
+	for path, file := range graph.Files {
+		for _, id := range file.Symbols {
+			symbolFile[id] = path
+		}
+	}
+	fileOf := func(node types.NodeId) string {
+		if path, ok := strings.CutPrefix(string(node), "file-"); ok {
+			return path
+		}
+		if id, ok := strings.CutPrefix(string(node), "symbol-"); ok {
+			return symbolFile[types.SymbolId(id)]
+		}
+		return ""
+	}
+
+	referrers := make(map[string]map[string]bool)
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipContains) || edge.Type == string(RelationshipDocuments) {
+			continue
+		}
+		from, to := fileOf(edge.From), fileOf(edge.To)
+		if from == "" || to == "" || from == to {
+			continue
+		}
+		if file := graph.Files[from]; file == nil || file.IsTest {
+			continue
+		}
+		if referrers[to] == nil {
+			referrers[to] = make(map[string]bool)
+		}
+		referrers[to][from] = true
+	}
+
+	var paths []string
+	for path, file := range graph.Files {
+		if !file.IsTest {
+			paths = append(paths, path)
+		}
+	}
+	packageRoot := commonDir(paths)
+	packageOf := func(file string) string {
+		rel, err := filepath.Rel(packageRoot, filepath.Dir(file))
+		if err != nil {
+			return filepath.ToSlash(filepath.Dir(file))
+		}
+		return filepath.ToSlash(rel)
+	}
+	dependents := make(map[string][]string)
+	for _, pkg := range AnalyzeCoupling(graph) {
+		dependents[pkg.Package] = pkg.Dependents
+	}
+
+	references := make(map[string]int)
+	for _, path := range paths {
+		referringPackages := make(map[string]bool)
+		for from := range referrers[path] {
+			referringPackages[packageOf(from)] = true
+		}
+		count := len(referrers[path])
+		for _, pkg := range dependents[packageOf(path)] {
+			if !referringPackages[pkg] {
+				count++
+			}
+		}
+		references[path] = count
+	}
+	return references
+}
+
+// entryPointNames are file names that conventionally start a program or package
+var entryPointNames = map[string]bool{
+	"main.go": true, "main.py": true, "__main__.py": true, "app.py": true, "manage.py": true,
+	"index.ts": true, "index.js": true, "main.ts": true, "main.js": true, "server.ts": true, "server.js": true,
+	"main.rs": true, "lib.rs": true, "main.dart": true, "main.cpp": true, "App.tsx": true, "App.jsx": true,
+	"Main.java": true, "Application.java": true,
+}
+
+// isEntryPoint reports whether a file runs without being referenced: its
+// name conventionally starts a program or package, or it defines main or init
+func isEntryPoint(graph *types.CodeGraph, path string, file *types.FileNode) bool {
+	if base := filepath.Base(path); entryPointNames[base] || base == "__init__.py" {
+		return true
+	}
+	for _, id := range file.Symbols {
+		if symbol := graph.Symbols[id]; symbol != nil && entryPointSymbols[symbol.Name] {
+			return true
+		}
+	}
+	return false
+}
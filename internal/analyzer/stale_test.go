@@ -0,0 +1,77 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/internal/coverage"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStaleCode(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                      "module example.com/shop\n\ngo 1.22\n",
+		"cmd/shop/main.go":            "package main\n\nimport \"example.com/shop/internal/orders\"\n\nfunc main() { orders.New() }\n",
+		"internal/orders/order.go":    "package orders\n\nfunc New() int { return 0 }\n",
+		"internal/legacy/old.go":      "package legacy\n\nfunc Convert() int { return 1 }\n",
+		"internal/legacy/fresh.go":    "package legacy\n\nfunc Fresh() int { return 2 }\n",
+		"internal/legacy/wip.go":      "package legacy\n\nfunc Draft() int { return 3 }\n",
+		"internal/legacy/old_test.go": "package legacy\n\nfunc TestConvert(t *testing.T) {}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0)
+	lastModified := map[string]time.Time{
+		"cmd/shop/main.go":            old,
+		"internal/orders/order.go":    old,
+		"internal/legacy/old.go":      old,
+		"internal/legacy/fresh.go":    now.AddDate(0, -1, 0),
+		"internal/legacy/old_test.go": old,
+		// wip.go is not committed yet
+	}
+
+	stale := FindStaleCode(graph, dir, lastModified, nil, StaleOptions{Now: now})
+	require.Len(t, stale, 1, "entry points, imported packages, recent, untracked and test files are left out")
+	assert.Equal(t, "internal/legacy/old.go", stale[0].File)
+	assert.Equal(t, 0, stale[0].References)
+	assert.Equal(t, 731, stale[0].AgeDays)
+	assert.Equal(t, -1.0, stale[0].Coverage)
+
+	stale = FindStaleCode(graph, dir, lastModified, nil, StaleOptions{Now: now, MaxReferences: 1})
+	assert.Len(t, stale, 2, "order.go is imported by one package")
+
+	report := coverage.NewReport()
+	require.NoError(t, report.Add([]byte("mode: set\nexample.com/shop/internal/legacy/old.go:3.24,3.34 1 1\n")))
+	assert.Empty(t, FindStaleCode(graph, dir, lastModified, report, StaleOptions{Now: now}), "covered code is not stale")
+
+	report = coverage.NewReport()
+	require.NoError(t, report.Add([]byte("mode: set\nexample.com/shop/internal/orders/order.go:3.20,3.30 1 1\n")))
+	stale = FindStaleCode(graph, dir, lastModified, report, StaleOptions{Now: now})
+	require.Len(t, stale, 1)
+	assert.Equal(t, 0.0, stale[0].Coverage, "files missing from the report are uncovered")
+}
+
+func TestFindStaleCodeWithoutHistory(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "legacy", "old.go")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("package legacy\n\nfunc Convert() int { return 1 }\n"), 0644))
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	assert.Empty(t, FindStaleCode(graph, dir, nil, nil, StaleOptions{}), "just written")
+
+	old := time.Now().AddDate(-1, 0, 0)
+	require.NoError(t, os.Chtimes(path, old, old))
+	stale := FindStaleCode(graph, dir, nil, nil, StaleOptions{})
+	require.Len(t, stale, 1, "file modification times stand in for git history")
+	assert.Equal(t, "legacy/old.go", stale[0].File)
+}
@@ -0,0 +1,685 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Relationships between state stores and the code using them
+const (
+	// RelationshipDispatches links code to the store whose action or event it dispatches
+	RelationshipDispatches RelationshipType = "dispatches"
+	// RelationshipSelectsFrom links code to the store whose state it reads
+	RelationshipSelectsFrom RelationshipType = "selects-from"
+)
+
+// State management libraries recognized by the state flow analysis
+const (
+	StateLibraryRedux   = "redux" // Redux Toolkit slices
+	StateLibraryPinia   = "pinia"
+	StateLibraryZustand = "zustand"
+	StateLibraryBloc    = "bloc" // Bloc and Cubit classes
+)
+
+// stateLanguages are the languages stores are declared and used in
+var stateLanguages = map[string]bool{"javascript": true, "typescript": true, "vue": true, "svelte": true, "dart": true}
+
+var (
+	reduxSlice       = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*createSlice\s*\(\s*\{`)
+	reduxSliceName   = regexp.MustCompile(`\bname\s*:\s*["'` + "`" + `]([^"'` + "`" + `]+)["'` + "`" + `]`)
+	reduxReducers    = regexp.MustCompile(`\breducers\s*:\s*\{`)
+	reduxDispatch    = regexp.MustCompile(`\bdispatch\(\s*(?:([A-Za-z_$][\w$]*)\.actions\.)?([A-Za-z_$][\w$]*)\s*\(`)
+	reduxActionType  = regexp.MustCompile(`\bdispatch\(\s*\{\s*type\s*:\s*["'` + "`" + `]([^"'` + "`" + `/]+)/([^"'` + "`" + `]+)["'` + "`" + `]`)
+	reduxSelectorUse = regexp.MustCompile(`\buse\w*Selector\(\s*`)
+	// (state) => state.cart.items, ({ cart }) => cart.items, or a selector function
+	reduxStateArrow = regexp.MustCompile(`^\(?\s*([A-Za-z_$][\w$]*)\s*(?::\s*[\w.$<>]+)?\s*\)?\s*=>\s*([A-Za-z_$][\w$]*)\.([A-Za-z_$][\w$]*)(?:\.([A-Za-z_$][\w$]*))?`)
+	reduxSelectorFn = regexp.MustCompile(`^([A-Za-z_$][\w$]*)\s*[,)]`)
+
+	zustandImport = regexp.MustCompile(`from\s+["']zustand(?:/[\w-]+)?["']`)
+	zustandCreate = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*create(?:Store)?\s*(?:<[^>]*>)?\s*\(`)
+	// The state creator returns the initial state and actions: (set, get) => ({ ... })
+	zustandObject = regexp.MustCompile(`=>\s*\(\s*\{`)
+
+	piniaStore      = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*defineStore\s*\(\s*(?:["'` + "`" + `]([^"'` + "`" + `]+)["'` + "`" + `]\s*,\s*)?`)
+	piniaId         = regexp.MustCompile(`\bid\s*:\s*["'` + "`" + `]([^"'` + "`" + `]+)["'` + "`" + `]`)
+	piniaActions    = regexp.MustCompile(`\bactions\s*:\s*\{`)
+	piniaSetupFuncs = regexp.MustCompile(`(?m)^\s*(?:(?:async\s+)?function\s+([A-Za-z_$][\w$]*)|(?:const|let)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:async\s+)?(?:function\b|\([^)]*\)\s*=>|[A-Za-z_$][\w$]*\s*=>))`)
+
+	blocClass     = regexp.MustCompile(`(?m)^\s*(?:abstract\s+)?class\s+([A-Z]\w*)(?:<[^>]*>)?\s+extends\s+(?:Hydrated)?(Bloc|Cubit)<\s*([\w?]+)`)
+	dartClass     = regexp.MustCompile(`(?m)^\s*(?:(?:abstract|sealed|final|base)\s+)*class\s+([A-Z]\w*)(?:<[^>]*>)?\s+(?:extends|implements)\s+([A-Z]\w*)`)
+	blocOnHandler = regexp.MustCompile(`\bon<\s*([A-Z]\w*)\s*>\s*\(`)
+	cubitMethod   = regexp.MustCompile(`(?m)^\s+(?:Future<[^>]*>|void|FutureOr<[^>]*>)\s+([a-z]\w*)\s*\(`)
+	blocRead      = regexp.MustCompile(`(?:\bcontext\.read|\bBlocProvider\.of)<\s*([A-Z]\w*)\s*>\(\s*(?:context\s*)?\)\s*\.\s*(\w+)\(\s*(?:const\s+)?([A-Z]\w*)?`)
+	blocAdd       = regexp.MustCompile(`\.add\(\s*(?:const\s+)?([A-Z]\w*)\s*[.(]`)
+	blocWatch     = regexp.MustCompile(`\b(?:Bloc(?:Builder|Selector|Listener|Consumer)<\s*([A-Z]\w*)\s*,|context\.(?:watch|select)<\s*([A-Z]\w*)\s*[,>]|context\.select\(\s*\(\s*([A-Z]\w*)\s+\w+\s*\))`)
+	dartTopLevel  = regexp.MustCompile(`(?m)^(?:(?:abstract|sealed|final|base)\s+)*class\s+(\w+)`)
+)
+
+// StateStore is a Redux slice, Pinia or Zustand store, or Bloc or Cubit
+// class, with the actions it handles and the code using it
+type StateStore struct {
+	Name        string        `json:"name"`    // Slice name, store id or class name
+	Library     string        `json:"library"` // redux, pinia, zustand or bloc
+	Kind        string        `json:"kind,omitempty"`
+	Symbol      string        `json:"symbol"` // Variable or class declaring the store
+	File        string        `json:"file"`
+	Line        int           `json:"line"`
+	Actions     []StateAction `json:"actions"`
+	Dispatchers []StateAccess `json:"dispatchers"`
+	Selectors   []StateAccess `json:"selectors"`
+}
+
+// StateAction is an action, reducer, event or method that changes a store
+type StateAction struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// StateAccess is code dispatching to or reading from a store
+type StateAccess struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Symbol string `json:"symbol,omitempty"` // Enclosing component, function or class
+	Name   string `json:"name,omitempty"`   // Action dispatched or state read
+}
+
+// stateScan holds the stores of every file while usages are resolved
+type stateScan struct {
+	ra     *RelationshipAnalyzer
+	stores []*StateStore
+	files  map[string]string
+	seen   map[string]bool
+}
+
+// StateFlows finds the state stores of the graph's JavaScript, TypeScript,
+// Vue, Svelte and Dart files and the code that dispatches to them or
+// selects from them, sorted by library and name
+func StateFlows(graph *types.CodeGraph) []StateStore {
+	return (&RelationshipAnalyzer{graph: graph}).stateFlows()
+}
+
+func (ra *RelationshipAnalyzer) stateFlows() []StateStore {
+	scan := &stateScan{ra: ra, files: make(map[string]string), seen: make(map[string]bool)}
+	ra.forEachSourceFileIn(stateLanguages, func(filePath, content string) {
+		scan.files[filePath] = content
+	})
+	var paths []string
+	for filePath := range scan.files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	var dartEvents []dartSubclass
+	for _, filePath := range paths {
+		content := scan.files[filePath]
+		if filepath.Ext(filePath) == ".dart" {
+			scan.findBlocs(filePath, content)
+			for _, m := range dartClass.FindAllStringSubmatchIndex(content, -1) {
+				dartEvents = append(dartEvents, dartSubclass{content[m[2]:m[3]], content[m[4]:m[5]], filePath, lineAt(content, m[0])})
+			}
+			continue
+		}
+		scan.findReduxSlices(filePath, content)
+		scan.findZustandStores(filePath, content)
+		scan.findPiniaStores(filePath, content)
+	}
+	if len(scan.stores) == 0 {
+		return nil
+	}
+	scan.addBlocEvents(dartEvents)
+
+	for _, filePath := range paths {
+		content := scan.files[filePath]
+		if filepath.Ext(filePath) == ".dart" {
+			scan.findBlocUsages(filePath, content)
+			continue
+		}
+		scan.findReduxUsages(filePath, content)
+		scan.findHookStoreUsages(filePath, content)
+	}
+
+	result := make([]StateStore, 0, len(scan.stores))
+	for _, store := range scan.stores {
+		sortStateAccesses(store.Dispatchers)
+		sortStateAccesses(store.Selectors)
+		result = append(result, *store)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Library != result[j].Library {
+			return result[i].Library < result[j].Library
+		}
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].File < result[j].File
+	})
+	return result
+}
+
+// findReduxSlices reads the createSlice calls of a file; the reducers are the actions
+func (scan *stateScan) findReduxSlices(filePath, content string) {
+	for _, m := range reduxSlice.FindAllStringSubmatchIndex(content, -1) {
+		open := m[1] - 1
+		end := matchingBrace(content, open)
+		if end < 0 {
+			continue
+		}
+		body := content[open:end]
+		store := &StateStore{Name: content[m[2]:m[3]], Library: StateLibraryRedux, Kind: "slice", Symbol: content[m[2]:m[3]], File: filePath, Line: lineAt(content, m[0])}
+		if name := reduxSliceName.FindStringSubmatch(topLevelText(body[1:])); name != nil {
+			store.Name = name[1]
+		}
+		if loc := reduxReducers.FindStringIndex(body); loc != nil {
+			reducersOpen := open + loc[1] - 1
+			for _, key := range objectKeys(content, reducersOpen) {
+				store.Actions = append(store.Actions, StateAction{Name: key.name, File: filePath, Line: lineAt(content, key.offset)})
+			}
+		}
+		scan.stores = append(scan.stores, store)
+	}
+}
+
+// findZustandStores reads the create calls of files importing zustand; the
+// functions of the state are its actions
+func (scan *stateScan) findZustandStores(filePath, content string) {
+	if !zustandImport.MatchString(content) {
+		return
+	}
+	for _, m := range zustandCreate.FindAllStringSubmatchIndex(content, -1) {
+		store := &StateStore{Name: content[m[2]:m[3]], Library: StateLibraryZustand, Kind: "store", Symbol: content[m[2]:m[3]], File: filePath, Line: lineAt(content, m[0])}
+		if loc := zustandObject.FindStringIndex(content[m[1]:]); loc != nil {
+			for _, key := range objectKeys(content, m[1]+loc[1]-1) {
+				if key.function {
+					store.Actions = append(store.Actions, StateAction{Name: key.name, File: filePath, Line: lineAt(content, key.offset)})
+				}
+			}
+		}
+		scan.stores = append(scan.stores, store)
+	}
+}
+
+// findPiniaStores reads the defineStore calls of a file: the actions option
+// of option stores and the functions of setup stores are their actions
+func (scan *stateScan) findPiniaStores(filePath, content string) {
+	for _, m := range piniaStore.FindAllStringSubmatchIndex(content, -1) {
+		hook := content[m[2]:m[3]]
+		store := &StateStore{Name: hook, Library: StateLibraryPinia, Kind: "store", Symbol: hook, File: filePath, Line: lineAt(content, m[0])}
+		if m[4] >= 0 {
+			store.Name = content[m[4]:m[5]]
+		}
+		rest := content[m[1]:]
+		switch {
+		case strings.HasPrefix(rest, "{"):
+			end := matchingBrace(content, m[1])
+			if end < 0 {
+				break
+			}
+			body := content[m[1]:end]
+			if id := piniaId.FindStringSubmatch(topLevelText(body[1:])); id != nil && m[4] < 0 {
+				store.Name = id[1]
+			}
+			if loc := piniaActions.FindStringIndex(body); loc != nil {
+				for _, key := range objectKeys(content, m[1]+loc[1]-1) {
+					store.Actions = append(store.Actions, StateAction{Name: key.name, File: filePath, Line: lineAt(content, key.offset)})
+				}
+			}
+		default:
+			// Setup stores: () => { ...; return { ... } }
+			open := strings.IndexByte(rest, '{')
+			if open < 0 {
+				break
+			}
+			end := matchingBrace(content, m[1]+open)
+			if end < 0 {
+				break
+			}
+			body := content[m[1]+open : end]
+			for _, f := range piniaSetupFuncs.FindAllStringSubmatchIndex(body, -1) {
+				name := ""
+				if f[2] >= 0 {
+					name = body[f[2]:f[3]]
+				} else {
+					name = body[f[4]:f[5]]
+				}
+				store.Actions = append(store.Actions, StateAction{Name: name, File: filePath, Line: lineAt(content, m[1]+open+f[0]) + strings.Count(body[f[0]:f[1]], "\n") - strings.Count(strings.TrimLeft(body[f[0]:f[1]], "\n"), "\n")})
+			}
+		}
+		scan.stores = append(scan.stores, store)
+	}
+}
+
+// findBlocs reads the Bloc and Cubit classes of a Dart file. Cubit actions
+// are their public methods; Bloc events are added from their event classes.
+func (scan *stateScan) findBlocs(filePath, content string) {
+	for _, m := range blocClass.FindAllStringSubmatchIndex(content, -1) {
+		store := &StateStore{Name: content[m[2]:m[3]], Library: StateLibraryBloc, Kind: strings.ToLower(content[m[4]:m[5]]), Symbol: content[m[2]:m[3]], File: filePath, Line: lineAt(content, m[0])}
+		open := strings.IndexByte(content[m[1]:], '{')
+		if open < 0 {
+			continue
+		}
+		end := matchingBrace(content, m[1]+open)
+		if end < 0 {
+			end = len(content)
+		}
+		body := content[m[1]+open : end]
+		if store.Kind == "bloc" {
+			// The event type of Bloc<Event, State>, resolved to its subclasses later
+			store.Actions = append(store.Actions, StateAction{Name: content[m[6]:m[7]]})
+			for _, h := range blocOnHandler.FindAllStringSubmatchIndex(body, -1) {
+				store.Actions = append(store.Actions, StateAction{Name: body[h[2]:h[3]], File: filePath, Line: lineAt(content, m[1]+open+h[0])})
+			}
+		} else {
+			for _, method := range cubitMethod.FindAllStringSubmatchIndex(body, -1) {
+				store.Actions = append(store.Actions, StateAction{Name: body[method[2]:method[3]], File: filePath, Line: lineAt(content, m[1]+open+method[2])})
+			}
+		}
+		scan.stores = append(scan.stores, store)
+	}
+}
+
+// dartSubclass is a Dart class and the class it extends or implements
+type dartSubclass struct {
+	name, parent, file string
+	line               int
+}
+
+// addBlocEvents replaces the event type of each Bloc with the event classes
+// extending it, keeping the events handled with on<Event> that are not
+func (scan *stateScan) addBlocEvents(classes []dartSubclass) {
+	for _, store := range scan.stores {
+		if store.Kind != "bloc" || len(store.Actions) == 0 {
+			continue
+		}
+		eventType := store.Actions[0].Name
+		actions := store.Actions[1:]
+		var events []StateAction
+		for _, class := range classes {
+			if class.parent == eventType {
+				events = append(events, StateAction{Name: class.name, File: class.file, Line: class.line})
+			}
+		}
+		for _, handled := range actions {
+			if !hasStateAction(events, handled.Name) {
+				events = append(events, handled)
+			}
+		}
+		store.Actions = events
+	}
+}
+
+// findReduxUsages records the dispatch calls and useSelector hooks of a file
+func (scan *stateScan) findReduxUsages(filePath, content string) {
+	var imports map[string]importRef
+	importsOf := func() map[string]importRef {
+		if imports == nil {
+			imports = scan.ra.javaScriptImports(filePath, content)
+		}
+		return imports
+	}
+
+	for _, m := range reduxDispatch.FindAllStringSubmatchIndex(content, -1) {
+		action := content[m[4]:m[5]]
+		var store *StateStore
+		if m[2] >= 0 {
+			store = scan.reduxStore(filePath, content[m[2]:m[3]], "", importsOf())
+		} else {
+			store = scan.reduxStore(filePath, "", action, importsOf())
+		}
+		if store != nil {
+			scan.record(store, true, filePath, content, m[0], action)
+		}
+	}
+	for _, m := range reduxActionType.FindAllStringSubmatchIndex(content, -1) {
+		for _, store := range scan.stores {
+			if store.Library == StateLibraryRedux && store.Name == content[m[2]:m[3]] {
+				scan.record(store, true, filePath, content, m[0], content[m[4]:m[5]])
+			}
+		}
+	}
+
+	for _, m := range reduxSelectorUse.FindAllStringIndex(content, -1) {
+		rest := content[m[1]:]
+		if arrow := reduxStateArrow.FindStringSubmatch(rest); arrow != nil {
+			// (state) => state.cart.items reads the cart slice; ({ cart }) => cart.items is not matched
+			if arrow[1] != arrow[2] {
+				continue
+			}
+			for _, store := range scan.stores {
+				if store.Library == StateLibraryRedux && store.Name == arrow[3] {
+					scan.record(store, false, filePath, content, m[0], strings.TrimSuffix(arrow[3]+"."+arrow[4], "."))
+				}
+			}
+			continue
+		}
+		if fn := reduxSelectorFn.FindStringSubmatch(rest); fn != nil {
+			// Selectors exported from a slice file read that slice
+			if ref, ok := importsOf()[fn[1]]; ok {
+				for _, store := range scan.stores {
+					if store.Library == StateLibraryRedux && store.File == ref.file {
+						scan.record(store, false, filePath, content, m[0], fn[1])
+					}
+				}
+			}
+		}
+	}
+}
+
+// reduxStore resolves a dispatched slice variable or action creator to its
+// slice: through the file's imports, in the file itself, or by a name only
+// one slice of the project has
+func (scan *stateScan) reduxStore(filePath, sliceVar, action string, imports map[string]importRef) *StateStore {
+	name, file := sliceVar, filePath
+	if sliceVar == "" {
+		name = action
+	}
+	if ref, ok := imports[name]; ok {
+		file = ref.file
+		if ref.name != "" && ref.name != "default" {
+			name = ref.name
+		}
+	}
+	var candidates []*StateStore
+	for _, store := range scan.stores {
+		if store.Library != StateLibraryRedux {
+			continue
+		}
+		if (sliceVar != "" && store.Symbol == name) || (sliceVar == "" && hasStateAction(store.Actions, name)) {
+			if store.File == file {
+				return store
+			}
+			candidates = append(candidates, store)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// findHookStoreUsages records the uses of Zustand and Pinia store hooks:
+// selectors, destructured state and the members of the store object they
+// return. Members that are actions dispatch, the others select.
+func (scan *stateScan) findHookStoreUsages(filePath, content string) {
+	for _, store := range scan.stores {
+		if store.Library != StateLibraryZustand && store.Library != StateLibraryPinia {
+			continue
+		}
+		hook := regexp.QuoteMeta(store.Symbol)
+		classify := func(offset int, name string) {
+			scan.record(store, hasStateAction(store.Actions, name), filePath, content, offset, name)
+		}
+		calls := regexp.MustCompile(`\b`+hook+`\s*\(`).FindAllStringIndex(content, -1)
+		for _, m := range calls {
+			if filePath == store.File && lineAt(content, m[0]) == store.Line {
+				continue
+			}
+			rest := content[m[1]:]
+			if arrow := reduxStateArrow.FindStringSubmatch(rest); arrow != nil && arrow[1] == arrow[2] {
+				classify(m[0], arrow[3])
+				continue
+			}
+			// const { items, addItem } = useCartStore() or const cart = useCartStore()
+			before := content[:m[0]]
+			if d := regexp.MustCompile(`(?:const|let|var)\s*\{([^}]*)\}\s*=\s*(?:storeToRefs\(\s*)?$`).FindStringSubmatch(before); d != nil {
+				for _, field := range strings.Split(d[1], ",") {
+					if name := strings.TrimSpace(strings.Split(field, ":")[0]); name != "" {
+						classify(m[0], name)
+					}
+				}
+				continue
+			}
+			if v := regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*$`).FindStringSubmatch(before); v != nil {
+				members := regexp.MustCompile(`\b`+regexp.QuoteMeta(v[1])+`\.([A-Za-z_$][\w$]*)`).FindAllStringSubmatchIndex(content, -1)
+				for _, member := range members {
+					if member[0] > m[0] {
+						classify(member[0], content[member[2]:member[3]])
+					}
+				}
+				// storeToRefs(cart) reads the whole state
+				if regexp.MustCompile(`storeToRefs\(\s*` + regexp.QuoteMeta(v[1]) + `\s*\)`).MatchString(content) {
+					classify(m[0], "")
+				}
+				if len(members) > 0 {
+					continue
+				}
+			}
+			classify(m[0], "")
+		}
+		// useCartStore.getState().addItem(...) and useCartStore.setState(...) outside components
+		for _, m := range regexp.MustCompile(`\b`+hook+`\.(?:getState\(\)\.([A-Za-z_$][\w$]*)|(setState)\()`).FindAllStringSubmatchIndex(content, -1) {
+			if m[2] >= 0 {
+				classify(m[0], content[m[2]:m[3]])
+			} else {
+				scan.record(store, true, filePath, content, m[0], "setState")
+			}
+		}
+	}
+}
+
+// findBlocUsages records the events added to Blocs, the Cubit methods
+// called and the widgets building, listening to or selecting from them
+func (scan *stateScan) findBlocUsages(filePath, content string) {
+	blocs := make(map[string]*StateStore)
+	for _, store := range scan.stores {
+		if store.Library == StateLibraryBloc {
+			blocs[store.Name] = store
+		}
+	}
+	for _, m := range blocRead.FindAllStringSubmatchIndex(content, -1) {
+		store := blocs[content[m[2]:m[3]]]
+		if store == nil {
+			continue
+		}
+		name := content[m[4]:m[5]]
+		if name == "add" && m[6] >= 0 {
+			name = content[m[6]:m[7]]
+		}
+		scan.record(store, true, filePath, content, m[0], name)
+	}
+	// bloc.add(Event()) on a Bloc held in a variable: the event names the Bloc
+	for _, m := range blocAdd.FindAllStringSubmatchIndex(content, -1) {
+		event := content[m[2]:m[3]]
+		var match *StateStore
+		for _, store := range blocs {
+			if store.Kind == "bloc" && hasStateAction(store.Actions, event) {
+				if match != nil {
+					match = nil
+					break
+				}
+				match = store
+			}
+		}
+		if match != nil {
+			scan.record(match, true, filePath, content, m[0], event)
+		}
+	}
+	for _, m := range blocWatch.FindAllStringSubmatchIndex(content, -1) {
+		for group := 2; group < len(m); group += 2 {
+			if m[group] < 0 {
+				continue
+			}
+			if store := blocs[content[m[group]:m[group+1]]]; store != nil {
+				scan.record(store, false, filePath, content, m[0], "")
+			}
+		}
+	}
+}
+
+// record adds a dispatch or selection at offset, once per line, store and name
+func (scan *stateScan) record(store *StateStore, dispatch bool, filePath, content string, offset int, name string) {
+	line := lineAt(content, offset)
+	key := fmt.Sprintf("%s\x00%d\x00%s\x00%s\x00%t", filePath, line, store.File+store.Symbol, name, dispatch)
+	if scan.seen[key] {
+		return
+	}
+	scan.seen[key] = true
+	access := StateAccess{File: filePath, Line: line, Name: name, Symbol: scan.enclosing(filePath, content, offset)}
+	if dispatch {
+		store.Dispatchers = append(store.Dispatchers, access)
+	} else {
+		store.Selectors = append(store.Selectors, access)
+	}
+}
+
+// enclosing names the component, function or class around an offset: the
+// top-level declaration before it, or the component a Vue or Svelte file is
+func (scan *stateScan) enclosing(filePath, content string, offset int) string {
+	switch filepath.Ext(filePath) {
+	case ".vue", ".svelte":
+		if fileNode := scan.ra.graph.Files[filePath]; fileNode != nil {
+			for _, symbolId := range fileNode.Symbols {
+				if symbol := scan.ra.graph.Symbols[symbolId]; symbol != nil && symbol.Type == types.SymbolTypeComponent {
+					return symbol.Name
+				}
+			}
+		}
+		return ""
+	case ".dart":
+		name := ""
+		for _, m := range dartTopLevel.FindAllStringSubmatchIndex(content[:offset], -1) {
+			name = content[m[2]:m[3]]
+		}
+		return name
+	}
+	name := ""
+	for _, m := range jsTopLevelDeclaration.FindAllStringSubmatchIndex(content[:offset], -1) {
+		name = content[m[2]:m[3]]
+	}
+	return name
+}
+
+// analyzeStateFlows links the code using a store to it with dispatches and
+// selects-from edges
+func (ra *RelationshipAnalyzer) analyzeStateFlows(metrics *RelationshipMetrics) {
+	for _, store := range ra.stateFlows() {
+		to := ra.enclosingNode(store.File, store.Line)
+		add := func(relationship RelationshipType, access StateAccess) {
+			from := ra.enclosingNode(access.File, access.Line)
+			if from == to {
+				return
+			}
+			edgeId := types.EdgeId(fmt.Sprintf("%s-%s:%d-%s:%s", relationship, access.File, access.Line, to, access.Name))
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   from,
+				To:     to,
+				Type:   string(relationship),
+				Weight: 0.8,
+				Metadata: map[string]interface{}{
+					"store":       store.Name,
+					"library":     store.Library,
+					"name":        access.Name,
+					"source_file": access.File,
+					"source_line": access.Line,
+				},
+			}
+			metrics.ByType[relationship]++
+		}
+		for _, access := range store.Dispatchers {
+			add(RelationshipDispatches, access)
+		}
+		for _, access := range store.Selectors {
+			add(RelationshipSelectsFrom, access)
+		}
+	}
+}
+
+// objectKey is a property of an object literal
+type objectKey struct {
+	name     string
+	offset   int
+	function bool // Methods and properties holding a function
+}
+
+var objectFunctionValue = regexp.MustCompile(`^\s*(?:async\s+)?(?:function\b|\([^()]*(?:\([^()]*\)[^()]*)*\)\s*(?::\s*[^=]+?)?\s*=>|[A-Za-z_$][\w$]*\s*=>)`)
+
+// objectKeys returns the properties of the object literal opening at open
+func objectKeys(content string, open int) []objectKey {
+	end := matchingBrace(content, open)
+	if end < 0 {
+		return nil
+	}
+	var keys []objectKey
+	depth := 0
+	expectKey := true
+	var quote byte
+	for i := open + 1; i < end; i++ {
+		c := content[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+			expectKey = false
+		case c == '/' && i+1 < end && content[i+1] == '/':
+			i += strings.IndexByte(content[i:end], '\n')
+			if i < open {
+				i = end
+			}
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			expectKey = true
+		case depth == 0 && expectKey && (c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'):
+			j := i
+			for j < end && (content[j] == '_' || content[j] == '$' || content[j] >= 'a' && content[j] <= 'z' || content[j] >= 'A' && content[j] <= 'Z' || content[j] >= '0' && content[j] <= '9') {
+				j++
+			}
+			name := content[i:j]
+			if name == "async" || name == "get" || name == "set" {
+				// Modifiers of the method that follows
+				if next := strings.TrimLeft(content[j:end], " \t"); next != "" && next[0] != '(' && next[0] != ':' {
+					i = j - 1
+					continue
+				}
+			}
+			rest := strings.TrimLeft(content[j:end], " \t\r\n")
+			switch {
+			case strings.HasPrefix(rest, "("):
+				keys = append(keys, objectKey{name: name, offset: i, function: true})
+			case strings.HasPrefix(rest, ":"):
+				keys = append(keys, objectKey{name: name, offset: i, function: objectFunctionValue.MatchString(rest[1:])})
+			}
+			expectKey = false
+			i = j - 1
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r':
+			expectKey = false
+		}
+	}
+	return keys
+}
+
+func hasStateAction(actions []StateAction, name string) bool {
+	for _, action := range actions {
+		if action.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortStateAccesses(accesses []StateAccess) {
+	sort.Slice(accesses, func(i, j int) bool {
+		if accesses[i].File != accesses[j].File {
+			return accesses[i].File < accesses[j].File
+		}
+		if accesses[i].Line != accesses[j].Line {
+			return accesses[i].Line < accesses[j].Line
+		}
+		return accesses[i].Name < accesses[j].Name
+	})
+}
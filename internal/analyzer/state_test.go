@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateFlows(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/store/cartSlice.ts": "import { createSlice } from '@reduxjs/toolkit';\n\nconst cartSlice = createSlice({\n  name: 'cart',\n  initialState: { items: [] },\n  reducers: {\n" +
+			"    addItem(state, action) {\n      state.items.push(action.payload);\n    },\n    clear: (state) => {\n      state.items = [];\n    },\n  },\n});\n\n" +
+			"export const { addItem, clear } = cartSlice.actions;\nexport const selectCount = (state) => state.cart.items.length;\nexport default cartSlice.reducer;\n",
+		"src/CartButton.tsx": "import { useDispatch, useSelector } from 'react-redux';\nimport { addItem, selectCount } from './store/cartSlice';\n\n" +
+			"export function CartButton() {\n  const dispatch = useDispatch();\n  const count = useSelector(selectCount);\n  const items = useSelector((state) => state.cart.items);\n" +
+			"  return <button onClick={() => dispatch(addItem(1))}>{count}</button>;\n}\n",
+		"src/store/theme.ts": "import { create } from 'zustand';\n\nexport const useTheme = create((set) => ({\n  mode: 'light',\n  toggle: () => set((s) => ({ mode: s.mode === 'light' ? 'dark' : 'light' })),\n}));\n",
+		"src/Header.tsx":     "import { useTheme } from './store/theme';\n\nexport const Header = () => {\n  const mode = useTheme((s) => s.mode);\n  const { toggle } = useTheme();\n  return <h1 onClick={toggle}>{mode}</h1>;\n};\n",
+		"src/stores/user.ts": "import { defineStore } from 'pinia';\n\nexport const useUserStore = defineStore('user', {\n  state: () => ({ name: '' }),\n  actions: {\n    async login(name) {\n      this.name = name;\n    },\n  },\n});\n",
+		"src/Profile.vue":    "<template><p>{{ user.name }}</p></template>\n<script setup>\nimport { useUserStore } from './stores/user';\nconst user = useUserStore();\nuser.login('ada');\n</script>\n",
+		"lib/counter_bloc.dart": "abstract class CounterEvent {}\n\nclass Increment extends CounterEvent {}\n\nclass Reset extends CounterEvent {}\n\n" +
+			"class CounterBloc extends Bloc<CounterEvent, int> {\n  CounterBloc() : super(0) {\n    on<Increment>((event, emit) => emit(state + 1));\n    on<Reset>((event, emit) => emit(0));\n  }\n}\n",
+		"lib/counter_page.dart": "class CounterPage extends StatelessWidget {\n  Widget build(BuildContext context) {\n    return BlocBuilder<CounterBloc, int>(\n" +
+			"      builder: (context, count) => TextButton(\n        onPressed: () => context.read<CounterBloc>().add(Increment()),\n        child: Text('$count'),\n      ),\n    );\n  }\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	stores := StateFlows(graph)
+	require.Len(t, stores, 4)
+	byName := make(map[string]StateStore)
+	for _, store := range stores {
+		byName[store.Name] = store
+	}
+
+	cart := byName["cart"]
+	assert.Equal(t, StateLibraryRedux, cart.Library)
+	assert.Equal(t, "cartSlice", cart.Symbol)
+	assert.Equal(t, []string{"addItem", "clear"}, stateActionNames(cart.Actions))
+	assert.Equal(t, []StateAccess{{File: filepath.Join(dir, "src/CartButton.tsx"), Line: 8, Symbol: "CartButton", Name: "addItem"}}, cart.Dispatchers)
+	assert.Equal(t, []StateAccess{
+		{File: filepath.Join(dir, "src/CartButton.tsx"), Line: 6, Symbol: "CartButton", Name: "selectCount"},
+		{File: filepath.Join(dir, "src/CartButton.tsx"), Line: 7, Symbol: "CartButton", Name: "cart.items"},
+	}, cart.Selectors)
+
+	theme := byName["useTheme"]
+	assert.Equal(t, StateLibraryZustand, theme.Library)
+	assert.Equal(t, []string{"toggle"}, stateActionNames(theme.Actions), "only functions of the state are actions")
+	assert.Equal(t, []StateAccess{{File: filepath.Join(dir, "src/Header.tsx"), Line: 5, Symbol: "Header", Name: "toggle"}}, theme.Dispatchers)
+	assert.Equal(t, []StateAccess{{File: filepath.Join(dir, "src/Header.tsx"), Line: 4, Symbol: "Header", Name: "mode"}}, theme.Selectors)
+
+	user := byName["user"]
+	assert.Equal(t, StateLibraryPinia, user.Library)
+	assert.Equal(t, []string{"login"}, stateActionNames(user.Actions))
+	require.Len(t, user.Dispatchers, 1)
+	assert.Equal(t, "login", user.Dispatchers[0].Name)
+	assert.Equal(t, "Profile", user.Dispatchers[0].Symbol)
+
+	counter := byName["CounterBloc"]
+	assert.Equal(t, StateLibraryBloc, counter.Library)
+	assert.Equal(t, "bloc", counter.Kind)
+	assert.Equal(t, []string{"Increment", "Reset"}, stateActionNames(counter.Actions))
+	assert.Equal(t, []StateAccess{{File: filepath.Join(dir, "lib/counter_page.dart"), Line: 5, Symbol: "CounterPage", Name: "Increment"}}, counter.Dispatchers)
+	assert.Equal(t, []StateAccess{{File: filepath.Join(dir, "lib/counter_page.dart"), Line: 3, Symbol: "CounterPage"}}, counter.Selectors)
+
+	metrics, err := NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+	assert.Equal(t, 4, metrics.ByType[RelationshipDispatches])
+	assert.Equal(t, 4, metrics.ByType[RelationshipSelectsFrom])
+}
+
+func stateActionNames(actions []StateAction) []string {
+	var names []string
+	for _, action := range actions {
+		names = append(names, action.Name)
+	}
+	return names
+}
@@ -0,0 +1,291 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipStories links a Storybook stories file to the component it documents
+const RelationshipStories RelationshipType = "stories"
+
+var (
+	storyFileName = regexp.MustCompile(`\.stories\.(?:js|jsx|mjs|cjs|ts|tsx|mts|cts)$`)
+	// Component Story Format: the default export (or the meta object it names)
+	// sets the title and component, every other named export is a story
+	storyMetaTitle     = regexp.MustCompile(`\btitle\s*:\s*["'` + "`" + `]([^"'` + "`" + `]+)["'` + "`" + `]`)
+	storyMetaComponent = regexp.MustCompile(`\bcomponent\s*:\s*([A-Za-z_$][\w$]*)`)
+	storyExport        = regexp.MustCompile(`(?m)^export\s+(?:const|let|var|function)\s+([A-Za-z_$][\w$]*)`)
+	storyDefaultObject = regexp.MustCompile(`\bexport\s+default\s*\{`)
+	// storiesOf('Title', module).add('story', ...) of Storybook 5 and earlier
+	storiesOfCall = regexp.MustCompile(`\bstoriesOf\(\s*["'` + "`" + `]([^"'` + "`" + `]+)["'` + "`" + `]`)
+	storiesOfAdd  = regexp.MustCompile(`\.add\(\s*["'` + "`" + `]([^"'` + "`" + `]+)["'` + "`" + `]`)
+	// Function components return JSX
+	jsxReturn   = regexp.MustCompile(`(?:\breturn|=>)\s*\(?\s*<[A-Za-z>]`)
+	storyIdPart = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// StoryFile is a Storybook stories file and the component it documents
+type StoryFile struct {
+	File          string         `json:"file"`
+	Title         string         `json:"title,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	ComponentFile string         `json:"component_file,omitempty"`
+	Stories       []StoryExample `json:"stories"`
+}
+
+// StoryExample is one story of a stories file
+type StoryExample struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	Id   string `json:"id,omitempty"` // Storybook story id, when the title is known
+}
+
+// StoryComponent is a component declared in the project
+type StoryComponent struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// StorybookReport is the Storybook stories of a project and the components
+// no story documents
+type StorybookReport struct {
+	Files      []StoryFile      `json:"files"`
+	Components int              `json:"components"` // Components found in .jsx and .tsx files
+	Unstoried  []StoryComponent `json:"unstoried"`
+}
+
+// FindStories reads the *.stories.* files of the graph, in Component Story
+// Format or the older storiesOf API, and links each to the component it
+// documents: the component of its default export, resolved through the
+// file's imports, or else a component named like the file or the last part
+// of its title. Components are the exported declarations with a capitalized
+// name that return JSX in .jsx and .tsx files; those no stories file
+// documents are reported as unstoried. It returns nil when the project has
+// no stories.
+func FindStories(graph *types.CodeGraph) *StorybookReport {
+	return (&RelationshipAnalyzer{graph: graph}).findStories()
+}
+
+func (ra *RelationshipAnalyzer) findStories() *StorybookReport {
+	report := &StorybookReport{}
+	var components []StoryComponent
+	ra.forEachSourceFileIn(frontendLanguages, func(filePath, content string) {
+		if storyFileName.MatchString(filePath) {
+			report.Files = append(report.Files, readStoryFile(ra, filePath, content))
+			return
+		}
+		if ext := filepath.Ext(filePath); ext != ".jsx" && ext != ".tsx" {
+			return
+		}
+		declarations := jsTopLevelDeclaration.FindAllStringSubmatchIndex(content, -1)
+		for i, loc := range declarations {
+			name := content[loc[2]:loc[3]]
+			if !strings.HasPrefix(content[loc[0]:], "export") || name[0] < 'A' || name[0] > 'Z' {
+				continue
+			}
+			// Declarations run to the next one; the braces of destructured props would end them early
+			end := len(content)
+			if i+1 < len(declarations) {
+				end = declarations[i+1][0]
+			}
+			if jsxReturn.MatchString(content[loc[0]:end]) {
+				components = append(components, StoryComponent{Name: name, File: filePath, Line: strings.Count(content[:loc[0]], "\n") + 1})
+			}
+		}
+	})
+	if len(report.Files) == 0 {
+		return nil
+	}
+
+	// Files without a resolved component fall back to a component named like them
+	byName := make(map[string][]StoryComponent)
+	for _, component := range components {
+		byName[component.Name] = append(byName[component.Name], component)
+	}
+	documented := make(map[string]bool)
+	for i := range report.Files {
+		story := &report.Files[i]
+		if story.ComponentFile == "" {
+			base := filepath.Base(story.File)
+			names := []string{story.Component, base[:strings.Index(base, ".stories.")]}
+			if story.Title != "" {
+				names = append(names, strings.ReplaceAll(story.Title[strings.LastIndex(story.Title, "/")+1:], " ", ""))
+			}
+			for _, name := range names {
+				if candidate := closestStoryComponent(byName[name], story.File); candidate != nil {
+					story.Component, story.ComponentFile = candidate.Name, candidate.File
+					break
+				}
+			}
+		}
+		documented[story.ComponentFile+"\x00"+story.Component] = true
+	}
+
+	report.Components = len(components)
+	for _, component := range components {
+		if !documented[component.File+"\x00"+component.Name] {
+			report.Unstoried = append(report.Unstoried, component)
+		}
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].File < report.Files[j].File
+	})
+	sort.Slice(report.Unstoried, func(i, j int) bool {
+		if report.Unstoried[i].File != report.Unstoried[j].File {
+			return report.Unstoried[i].File < report.Unstoried[j].File
+		}
+		return report.Unstoried[i].Line < report.Unstoried[j].Line
+	})
+	return report
+}
+
+// readStoryFile reads the title, component and stories of a stories file
+func readStoryFile(ra *RelationshipAnalyzer, filePath, content string) StoryFile {
+	story := StoryFile{File: filePath}
+	lineOf := func(offset int) int {
+		return strings.Count(content[:offset], "\n") + 1
+	}
+
+	if m := storiesOfCall.FindStringSubmatch(content); m != nil {
+		story.Title = m[1]
+		for _, loc := range storiesOfAdd.FindAllStringSubmatchIndex(content, -1) {
+			story.Stories = append(story.Stories, StoryExample{Name: content[loc[2]:loc[3]], Line: lineOf(loc[0])})
+		}
+		// The component is the first imported one the stories render
+		imports := ra.javaScriptImports(filePath, content)
+		for _, m := range jsxComponentTag.FindAllStringSubmatch(content, -1) {
+			if _, ok := imports[m[1]]; ok {
+				story.Component = m[1]
+				break
+			}
+		}
+	} else {
+		meta := storyMeta(content)
+		if m := storyMetaTitle.FindStringSubmatch(meta); m != nil {
+			story.Title = m[1]
+		}
+		if m := storyMetaComponent.FindStringSubmatch(meta); m != nil {
+			story.Component = m[1]
+		}
+		for _, loc := range storyExport.FindAllStringSubmatchIndex(content, -1) {
+			story.Stories = append(story.Stories, StoryExample{Name: content[loc[2]:loc[3]], Line: lineOf(loc[0])})
+		}
+	}
+	if story.Title != "" {
+		for i := range story.Stories {
+			story.Stories[i].Id = storyId(story.Title, story.Stories[i].Name)
+		}
+	}
+
+	if story.Component != "" {
+		if ref, ok := ra.javaScriptImports(filePath, content)[story.Component]; ok {
+			story.ComponentFile = ref.file
+			if ref.name != "default" && ref.name != "" {
+				story.Component = ref.name
+			} else if name := defaultExportName(ra.fileContent(ref.file)); name != "" {
+				story.Component = name
+			}
+		} else if m := regexp.MustCompile(`\bimport\s+` + regexp.QuoteMeta(story.Component) + `\s+from\s+['"](\.[^'"]+)['"]`).FindStringSubmatch(content); m != nil {
+			// Single-file components such as .vue and .svelte files are not in the graph
+			if candidate := filepath.Join(filepath.Dir(filePath), m[1]); filepath.Ext(candidate) != "" {
+				if _, err := os.Stat(candidate); err == nil {
+					story.ComponentFile = candidate
+				}
+			}
+		}
+	}
+	return story
+}
+
+// storyMeta returns the object a stories file exports by default: the
+// literal after export default, or the variable it names
+func storyMeta(content string) string {
+	if loc := storyDefaultObject.FindStringIndex(content); loc != nil {
+		return topLevelText(content[loc[1]:matchingBrace(content, loc[1]-1)])
+	}
+	if name := defaultExportName(content); name != "" {
+		if start := jsDeclarationOffset(content, name); start >= 0 {
+			if open := strings.IndexByte(content[start:], '{'); open >= 0 {
+				return topLevelText(content[start+open+1 : matchingBrace(content, start+open)])
+			}
+		}
+	}
+	return ""
+}
+
+// storyId returns the id Storybook gives a story: the title and the export
+// name lower-cased, with runs of other characters turned into "-"
+func storyId(title, name string) string {
+	sanitize := func(s string) string {
+		// Export names are split into words first: PrimaryLarge is primary-large
+		var words strings.Builder
+		for i, c := range s {
+			if i > 0 && c >= 'A' && c <= 'Z' && s[i-1] >= 'a' && s[i-1] <= 'z' {
+				words.WriteByte('-')
+			}
+			words.WriteRune(c)
+		}
+		return strings.Trim(storyIdPart.ReplaceAllString(strings.ToLower(words.String()), "-"), "-")
+	}
+	return sanitize(strings.ReplaceAll(title, " ", "")) + "--" + sanitize(name)
+}
+
+// closestStoryComponent returns the candidate in the stories file's
+// directory, or else the one whose directory shares the longest prefix with it
+func closestStoryComponent(candidates []StoryComponent, storyFile string) *StoryComponent {
+	var best *StoryComponent
+	bestLength := -1
+	for i := range candidates {
+		length := len(commonDir([]string{candidates[i].File, storyFile}))
+		if length > bestLength {
+			best, bestLength = &candidates[i], length
+		}
+	}
+	return best
+}
+
+// fileContent returns the content of a file, or "" when it cannot be read
+func (ra *RelationshipAnalyzer) fileContent(path string) string {
+	content, _ := ra.sourceContent(path)
+	return content
+}
+
+// analyzeStories links stories files to the components they document
+func (ra *RelationshipAnalyzer) analyzeStories(metrics *RelationshipMetrics) {
+	report := ra.findStories()
+	if report == nil {
+		return
+	}
+	for _, story := range report.Files {
+		if story.ComponentFile == "" {
+			continue
+		}
+		names := make([]string, len(story.Stories))
+		for i, example := range story.Stories {
+			names[i] = example.Name
+		}
+		from := types.NodeId(fmt.Sprintf("file-%s", story.File))
+		to := types.NodeId(fmt.Sprintf("file-%s", story.ComponentFile))
+		edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", RelationshipStories, from, to))
+		ra.graph.Edges[edgeId] = &types.GraphEdge{
+			Id:     edgeId,
+			From:   from,
+			To:     to,
+			Type:   string(RelationshipStories),
+			Weight: 0.5,
+			Metadata: map[string]interface{}{
+				"component": story.Component,
+				"title":     story.Title,
+				"stories":   strings.Join(names, ", "),
+			},
+		}
+		metrics.ByType[RelationshipStories]++
+	}
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStories(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/Button.tsx": "export function Button({ label }: { label: string }) {\n  return <button>{label}</button>;\n}\n",
+		"src/Button.stories.tsx": "import type { Meta } from '@storybook/react';\nimport { Button } from './Button';\n\nconst meta: Meta<typeof Button> = {\n  title: 'Forms/Button',\n  component: Button,\n};\nexport default meta;\n\n" +
+			"export const Primary = { args: { label: 'OK' } };\nexport const PrimaryLarge = { args: { label: 'Big' } };\n",
+		"src/Card.jsx":          "export default function Card() {\n  return <div className=\"card\" />;\n}\n",
+		"src/legacy.stories.js": "import { storiesOf } from '@storybook/react';\nimport Card from './Card';\n\nstoriesOf('Legacy/Card', module)\n  .add('empty', () => <Card />)\n  .add('with title', () => <Card />);\n",
+		"src/Modal.tsx":         "export const Modal = () => (\n  <div role=\"dialog\" />\n);\n\nexport const useModal = () => null;\n",
+		"src/utils.ts":          "export function Format() { return ''; }\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	report := FindStories(graph)
+	require.NotNil(t, report)
+	require.Len(t, report.Files, 2)
+
+	button := report.Files[0]
+	assert.Equal(t, filepath.Join(dir, "src/Button.stories.tsx"), button.File)
+	assert.Equal(t, "Forms/Button", button.Title)
+	assert.Equal(t, "Button", button.Component)
+	assert.Equal(t, filepath.Join(dir, "src/Button.tsx"), button.ComponentFile)
+	assert.Equal(t, []StoryExample{
+		{Name: "Primary", Line: 10, Id: "forms-button--primary"},
+		{Name: "PrimaryLarge", Line: 11, Id: "forms-button--primary-large"},
+	}, button.Stories)
+
+	legacy := report.Files[1]
+	assert.Equal(t, "Card", legacy.Component, "default imports resolve to the exported name")
+	assert.Equal(t, filepath.Join(dir, "src/Card.jsx"), legacy.ComponentFile)
+	assert.Equal(t, []StoryExample{
+		{Name: "empty", Line: 5, Id: "legacy-card--empty"},
+		{Name: "with title", Line: 6, Id: "legacy-card--with-title"},
+	}, legacy.Stories)
+
+	assert.Equal(t, 3, report.Components, "hooks and .ts functions are not components")
+	assert.Equal(t, []StoryComponent{{Name: "Modal", File: filepath.Join(dir, "src/Modal.tsx"), Line: 1}}, report.Unstoried)
+
+	metrics, err := NewRelationshipAnalyzer(graph).AnalyzeAllRelationships()
+	require.NoError(t, err)
+	assert.Equal(t, 2, metrics.ByType[RelationshipStories])
+}
+
+func TestFindStoriesWithoutStories(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "App.tsx"), []byte("export const App = () => <div />;\n"), 0644))
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	assert.Nil(t, FindStories(graph))
+}
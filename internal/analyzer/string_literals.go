@@ -0,0 +1,330 @@
+package analyzer
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// String literal kinds, from the call the literal is passed to
+const (
+	StringKindLog    = "log"
+	StringKindError  = "error"
+	StringKindRoute  = "route"
+	StringKindString = "string"
+)
+
+// minIndexedStringLength skips short literals like "id" or ", " that would
+// match almost any query
+const minIndexedStringLength = 4
+
+var (
+	// Calls whose string argument ends up in an error or exception
+	errorCallPattern = regexp.MustCompile(`(?i)(?:\berrors?\.(?:New|Wrap|Wrapf|Errorf)|\bfmt\.Errorf|\bpanic|\bthrow\s+new\s+\w*|\braise\s+\w*|\bnew\s+\w*(?:Error|Exception)|\b\w+(?:Error|Exception)|\breject)\s*\(\s*(?:[\w.]+\s*,\s*)?$`)
+	// Logging calls, including structured loggers and console output
+	logCallPattern = regexp.MustCompile(`(?i)(?:\b(?:log|logger|logging|console|slog|zap|zerolog|klog|glog|l|lg|sugar)\b(?:\.\w+)*\.(?:trace|debug|info|warn|warning|error|fatal|critical|exception|print|printf|println|log|infof|debugf|warnf|errorf|fatalf|panicf|msg|msgf|infow|errorw|warnw|debugw)|\bprint(?:ln|f)?)\s*\(\s*(?:[\w.]+\s*,\s*)?$`)
+	// Router registrations taking a path
+	routeCallPattern = regexp.MustCompile(`(?i)(?:\.(?:get|post|put|patch|delete|head|options|all|use|route|handle|handlefunc|group|any)|@(?:app|router|blueprint|bp|api)\.(?:get|post|put|patch|delete|route)|@(?:Get|Post|Put|Patch|Delete|Request)Mapping)\s*\(\s*(?:value\s*=\s*|path\s*=\s*)?$`)
+
+	// Python string prefixes such as f"..." and rb"..."
+	stringPrefixPattern = regexp.MustCompile(`\b[rRbBuUfF]{1,2}$`)
+
+	// Placeholders in format strings and templates: %s, %-5d, %[1]v, {}, {0}, {name}, ${expr}, $name
+	formatPlaceholderPattern = regexp.MustCompile(`%[-+# 0]*(?:\[\d+\])?\d*(?:\.\d+)?[a-zA-Z%]|\$\{[^}]*\}|\$\w+|\{[\w.]*\}`)
+)
+
+// StringLiteral is a string literal in a source file
+type StringLiteral struct {
+	Value  string `json:"value"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Kind   string `json:"kind"`             // log, error, route or string
+	Symbol string `json:"symbol,omitempty"` // Enclosing function or method
+}
+
+// StringMatch is a literal found for a query, with how it matched
+type StringMatch struct {
+	StringLiteral
+	Match string `json:"match"` // exact, format (the query is an instance of a format string) or substring
+}
+
+// StringIndex maps string literals to where they are written
+type StringIndex struct {
+	Literals []StringLiteral
+}
+
+// BuildStringIndex collects the string literals of the graph's source files.
+// It reads every file, so it is built on demand rather than during analysis.
+func BuildStringIndex(graph *types.CodeGraph) *StringIndex {
+	ra := &RelationshipAnalyzer{graph: graph}
+	index := &StringIndex{}
+	(&RelationshipAnalyzer{graph: graph}).forEachSourceFile(func(filePath, content string) {
+		for _, literal := range extractStringLiterals(content, filePath, graph.Files[filePath].Language) {
+			if symbol := ra.enclosingSymbol(filePath, literal.Line); symbol != nil {
+				literal.Symbol = symbol.Name
+			}
+			index.Literals = append(index.Literals, literal)
+		}
+	})
+	sort.Slice(index.Literals, func(i, j int) bool {
+		if index.Literals[i].File != index.Literals[j].File {
+			return index.Literals[i].File < index.Literals[j].File
+		}
+		return index.Literals[i].Line < index.Literals[j].Line
+	})
+	return index
+}
+
+// Find returns the literals a piece of text comes from: literals equal to it,
+// format strings and templates it is an instance of ("user 42 not found" for
+// "user %d not found"), and literals containing it. Matching ignores case and
+// surrounding whitespace; exact matches come first. kind limits the results to
+// one kind of literal when set.
+func (idx *StringIndex) Find(text, kind string) []StringMatch {
+	query := strings.ToLower(strings.TrimSpace(text))
+	if query == "" {
+		return nil
+	}
+
+	var exact, format, substring []StringMatch
+	for _, literal := range idx.Literals {
+		if kind != "" && literal.Kind != kind {
+			continue
+		}
+		value := strings.ToLower(strings.TrimSpace(literal.Value))
+		switch {
+		case value == query:
+			exact = append(exact, StringMatch{literal, "exact"})
+		case matchesFormat(value, query):
+			format = append(format, StringMatch{literal, "format"})
+		case strings.Contains(value, query) || (len(value) >= minIndexedStringLength*2 && strings.Contains(query, value)):
+			substring = append(substring, StringMatch{literal, "substring"})
+		}
+	}
+	return append(append(exact, format...), substring...)
+}
+
+// matchesFormat reports whether text is an instance of a literal containing
+// format placeholders, anywhere in text (error messages get wrapped)
+func matchesFormat(literal, text string) bool {
+	parts := formatPlaceholderPattern.Split(literal, -1)
+	if len(parts) < 2 {
+		return false
+	}
+	var pattern strings.Builder
+	fixed := 0
+	for i, part := range parts {
+		if i > 0 {
+			pattern.WriteString(".+?")
+		}
+		pattern.WriteString(regexp.QuoteMeta(part))
+		fixed += len(strings.TrimSpace(part))
+	}
+	// A literal that is all placeholders ("%s: %v") would match anything
+	if fixed < minIndexedStringLength {
+		return false
+	}
+	re, err := regexp.Compile(pattern.String())
+	return err == nil && re.MatchString(text)
+}
+
+// extractStringLiterals scans a source file for string literals, skipping
+// comments, Python docstrings and literals too short or without letters to be
+// worth searching
+func extractStringLiterals(content, filePath, language string) []StringLiteral {
+	hashComments := language == "python"
+	var literals []StringLiteral
+	line := 1
+	lineStart := 0
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\n':
+			line++
+			lineStart = i + 1
+			continue
+		case hashComments && c == '#':
+			i = skipToLineEnd(content, i)
+			continue
+		case !hashComments && c == '/' && i+1 < len(content) && content[i+1] == '/':
+			i = skipToLineEnd(content, i)
+			continue
+		case !hashComments && c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				return literals
+			}
+			line += strings.Count(content[i:i+2+end], "\n")
+			i += end + 3
+			lineStart = strings.LastIndex(content[:i+1], "\n") + 1
+			continue
+		case c != '"' && c != '\'' && c != '`':
+			continue
+		}
+
+		// Single quotes are characters in Go, Java and Kotlin
+		if c == '\'' && language != "python" && language != "javascript" && language != "typescript" {
+			from := i + 1
+			if from < len(content) && content[from] == '\\' {
+				from += 2 // '\'' and '\\'
+			}
+			if from < len(content) {
+				if end := strings.IndexByte(content[from:], '\''); end >= 0 && end <= 8 {
+					i = from + end
+				}
+			}
+			continue
+		}
+
+		startLine := line
+		before := content[lineStart:i]
+		var value string
+		var end int
+		if hashComments && strings.HasPrefix(content[i:], strings.Repeat(string(c), 3)) {
+			// Triple-quoted strings are docstrings or long text, not messages
+			closing := strings.Index(content[i+3:], strings.Repeat(string(c), 3))
+			if closing < 0 {
+				return literals
+			}
+			end = i + 3 + closing + 2
+			line += strings.Count(content[i:end], "\n")
+			if nl := strings.LastIndex(content[:end], "\n"); nl >= lineStart {
+				lineStart = nl + 1
+			}
+			i = end
+			continue
+		}
+		value, end = scanQuoted(content, i, c == '`')
+		if end < 0 {
+			return literals
+		}
+		if newlines := strings.Count(content[i:end], "\n"); newlines > 0 {
+			line += newlines
+			lineStart = strings.LastIndex(content[:end], "\n") + 1
+		}
+		i = end
+
+		if len(value) < minIndexedStringLength || !strings.ContainsFunc(value, unicode.IsLetter) {
+			continue
+		}
+		literals = append(literals, StringLiteral{
+			Value: value,
+			File:  filePath,
+			Line:  startLine,
+			Kind:  stringLiteralKind(before, value),
+		})
+	}
+	return literals
+}
+
+// maskLiterals blanks out the comments and string literals of a source file,
+// keeping newlines so offsets and line numbers match the original
+func maskLiterals(content, language string) string {
+	hashComments := language == "python"
+	quotedChars := language != "python" && language != "javascript" && language != "typescript" && language != "dart"
+	masked := []byte(content)
+	blank := func(from, to int) {
+		for j := from; j < to && j < len(masked); j++ {
+			if masked[j] != '\n' {
+				masked[j] = ' '
+			}
+		}
+	}
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case hashComments && c == '#', !hashComments && c == '/' && i+1 < len(content) && content[i+1] == '/':
+			end := skipToLineEnd(content, i)
+			blank(i, end+1)
+			i = end
+		case !hashComments && c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				blank(i, len(content))
+				return string(masked)
+			}
+			blank(i, i+end+4)
+			i += end + 3
+		case c == '\'' && quotedChars:
+			// Characters in Go, Java and Kotlin, as in extractStringLiterals
+			from := i + 1
+			if from < len(content) && content[from] == '\\' {
+				from += 2
+			}
+			if from < len(content) {
+				if end := strings.IndexByte(content[from:], '\''); end >= 0 && end <= 8 {
+					blank(i, from+end+1)
+					i = from + end
+				}
+			}
+		case c == '"' || c == '\'' || c == '`':
+			if hashComments && strings.HasPrefix(content[i:], strings.Repeat(string(c), 3)) {
+				closing := strings.Index(content[i+3:], strings.Repeat(string(c), 3))
+				if closing < 0 {
+					blank(i, len(content))
+					return string(masked)
+				}
+				blank(i, i+closing+6)
+				i += closing + 5
+				continue
+			}
+			_, end := scanQuoted(content, i, c == '`')
+			if end < 0 {
+				blank(i, len(content))
+				return string(masked)
+			}
+			blank(i, end+1)
+			i = end
+		}
+	}
+	return string(masked)
+}
+
+// scanQuoted reads the literal opening at start and returns its content and
+// the offset of the closing quote, or -1 when the file ends first. Only
+// backquoted literals may span lines.
+func scanQuoted(content string, start int, multiline bool) (string, int) {
+	quote := content[start]
+	var value strings.Builder
+	for i := start + 1; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == quote:
+			return value.String(), i
+		case c == '\\' && i+1 < len(content) && quote != '`':
+			value.WriteByte(c)
+			value.WriteByte(content[i+1])
+			i++
+		case c == '\n' && !multiline:
+			// Unterminated on this line; resume scanning at the newline
+			return "", i - 1
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", -1
+}
+
+func skipToLineEnd(content string, i int) int {
+	if end := strings.IndexByte(content[i:], '\n'); end >= 0 {
+		return i + end - 1
+	}
+	return len(content)
+}
+
+// stringLiteralKind classifies a literal by the code before it on its line
+func stringLiteralKind(before, value string) string {
+	before = stringPrefixPattern.ReplaceAllString(before, "")
+	switch {
+	case errorCallPattern.MatchString(before):
+		return StringKindError
+	case logCallPattern.MatchString(before):
+		return StringKindLog
+	case strings.HasPrefix(value, "/") && routeCallPattern.MatchString(before):
+		return StringKindRoute
+	}
+	return StringKindString
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractStringLiterals(t *testing.T) {
+	goSource := "package api\n\n// \"commented out\"\nfunc Pay(id int) error {\n\tsep := ','\n\tlog.Printf(\"charging order %d\", id)\n\tr.HandleFunc(\"/payments/{id}\", handler)\n\tquery := `SELECT amount\nFROM payments`\n\treturn fmt.Errorf(\"payment %d failed: %w\", id, err)\n}\n"
+	literals := extractStringLiterals(goSource, "api.go", "go")
+
+	got := make(map[string]StringLiteral)
+	for _, literal := range literals {
+		got[literal.Value] = literal
+	}
+	require.Len(t, got, 4)
+	assert.NotContains(t, got, "commented out")
+	assert.Equal(t, StringLiteral{Value: "charging order %d", File: "api.go", Line: 6, Kind: StringKindLog}, got["charging order %d"])
+	assert.Equal(t, StringKindRoute, got["/payments/{id}"].Kind)
+	assert.Equal(t, 8, got["SELECT amount\nFROM payments"].Line)
+	assert.Equal(t, StringLiteral{Value: "payment %d failed: %w", File: "api.go", Line: 10, Kind: StringKindError}, got["payment %d failed: %w"])
+
+	pySource := "def charge():\n    \"\"\"Charge the card.\"\"\"\n    # raise ValueError(\"ignored\")\n    raise ValueError(f\"card {card_id} declined\")\n"
+	literals = extractStringLiterals(pySource, "pay.py", "python")
+	require.Len(t, literals, 1)
+	assert.Equal(t, StringLiteral{Value: "card {card_id} declined", File: "pay.py", Line: 4, Kind: StringKindError}, literals[0])
+}
+
+func TestStringIndexFind(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"api/payments.go":      "package api\n\nfunc Charge(id int) error {\n\treturn fmt.Errorf(\"payment %d failed: %s\", id, reason)\n}\n",
+		"web/checkout.ts":      "export function checkout(id) {\n  console.error(`payment ${id} failed: card declined`);\n  throw new Error('card declined');\n}\n",
+		"api/payments_test.go": "package api\n\nfunc TestCharge(t *testing.T) {\n\tt.Fatal(\"card declined\")\n}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	index := BuildStringIndex(graph)
+
+	matches := index.Find("Card declined", "")
+	require.Len(t, matches, 2, "test files are not indexed")
+	assert.Equal(t, "exact", matches[0].Match)
+	assert.Equal(t, filepath.Join(dir, "web", "checkout.ts"), matches[0].File)
+	assert.Equal(t, 3, matches[0].Line)
+	assert.Equal(t, "checkout", matches[0].Symbol)
+	assert.Equal(t, "substring", matches[1].Match)
+
+	// A runtime message finds the format strings it was built from, and
+	// literals it contains
+	matches = index.Find("request failed: payment 42 failed: card declined", "")
+	require.Len(t, matches, 3)
+	assert.Equal(t, "format", matches[0].Match)
+	assert.Equal(t, "format", matches[1].Match)
+	assert.Equal(t, "card declined", matches[2].Value)
+
+	matches = index.Find("payment 42 failed: insufficient funds", StringKindError)
+	require.Len(t, matches, 1)
+	assert.Equal(t, filepath.Join(dir, "api", "payments.go"), matches[0].File)
+	assert.Equal(t, "Charge", matches[0].Symbol)
+
+	assert.Empty(t, index.Find("   ", ""))
+}
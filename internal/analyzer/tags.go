@@ -0,0 +1,393 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// ctagsPatternLimit is the longest source line used as a search pattern;
+// longer lines are cut and lose their end anchor, as Universal Ctags does
+const ctagsPatternLimit = 96
+
+// Tag is a symbol definition in a tags file
+type Tag struct {
+	Name      string
+	File      string // Relative to the base directory, slash-separated
+	Line      int
+	Kind      string // Single-letter ctags kind
+	Language  string
+	Scope     string // Enclosing type or namespace, such as "Cart" or "math::Vec"
+	ScopeKind string // Kind of the scope, such as "struct" or "class"
+	Signature string // Parameter list, when known
+	Access    string // Visibility, when known
+	Text      string // Source line; empty when it holds a secret
+	Offset    int    // Byte offset of the line in the file
+
+	symbol   *types.Symbol
+	column   int // Byte offset of the name in its line
+	column16 int // UTF-16 offset of the name in its line
+}
+
+// TagGenerator writes the symbol table of an analyzed graph as a ctags or
+// etags file, so editors can jump to definitions in every supported language
+type TagGenerator struct {
+	graph     *types.CodeGraph
+	targetDir string
+	baseDir   string
+	redactor  *redact.Redactor
+}
+
+// NewTagGenerator creates a tag generator for a graph analyzed from targetDir
+func NewTagGenerator(graph *types.CodeGraph, targetDir string) *TagGenerator {
+	return &TagGenerator{graph: graph, targetDir: targetDir, baseDir: targetDir}
+}
+
+// SetBaseDir sets the directory file names are relative to, which should be
+// the directory of the tags file. It defaults to the analyzed directory.
+func (g *TagGenerator) SetBaseDir(dir string) {
+	g.baseDir = dir
+}
+
+// SetRedactor leaves out sensitive files and lines that hold secrets; such
+// lines are addressed by number instead of by pattern
+func (g *TagGenerator) SetRedactor(r *redact.Redactor) {
+	g.redactor = r
+}
+
+// tagKinds maps normalized kinds to ctags kind letters
+var tagKinds = map[string]string{
+	types.SymbolKindFunction:  "f",
+	types.SymbolKindMethod:    "m",
+	types.SymbolKindClass:     "c",
+	types.SymbolKindComponent: "c",
+	types.SymbolKindInterface: "i",
+	types.SymbolKindEnum:      "g",
+	types.SymbolKindTypeAlias: "t",
+	types.SymbolKindConstant:  "C",
+	types.SymbolKindVariable:  "v",
+	types.SymbolKindProperty:  "p",
+	types.SymbolKindNamespace: "n",
+	types.SymbolKindMacro:     "d",
+	types.SymbolKindConfig:    "k",
+}
+
+// scopeKinds names the kinds that enclose other symbols in scope fields
+var scopeKinds = map[string]string{"c": "class", "s": "struct", "i": "interface", "g": "enum", "n": "namespace"}
+
+// tagKind returns the ctags kind of a symbol, or "" for symbols left out of
+// tags files such as imports
+func tagKind(symbol *types.Symbol) string {
+	kind := tagKinds[symbol.NormalizedKind()]
+	if kind == "c" && symbol.Subtype() == "struct" {
+		return "s"
+	}
+	return kind
+}
+
+// Tags returns the tags of every file, in path and line order
+func (g *TagGenerator) Tags() []Tag {
+	var paths []string
+	for path := range g.graph.Files {
+		if g.redactor.SensitivePath(projectPath(g.targetDir, path)) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	scopes := g.scopeIndex()
+	var tags []Tag
+	for _, path := range paths {
+		tags = append(tags, g.fileTags(path, scopes)...)
+	}
+	return tags
+}
+
+// WriteCtags writes tags in the extended ctags format read by Vim, Neovim and
+// most other editors, sorted by name
+func (g *TagGenerator) WriteCtags(w io.Writer) error {
+	tags := g.Tags()
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].Name != tags[j].Name {
+			return tags[i].Name < tags[j].Name
+		}
+		if tags[i].File != tags[j].File {
+			return tags[i].File < tags[j].File
+		}
+		return tags[i].Line < tags[j].Line
+	})
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "!_TAG_FILE_FORMAT\t2\t/extended format; --format=1 will not append ;\" to lines/\n")
+	fmt.Fprintf(out, "!_TAG_FILE_SORTED\t1\t/0=unsorted, 1=sorted, 2=foldcase/\n")
+	fmt.Fprintf(out, "!_TAG_PROGRAM_NAME\tcodecontext\t//\n")
+	fmt.Fprintf(out, "!_TAG_PROGRAM_URL\thttps://github.com/nuthan-ms/codecontext\t//\n")
+	for _, tag := range tags {
+		fmt.Fprintf(out, "%s\t%s\t%s;\"\t%s\tline:%d", tag.Name, tag.File, ctagsAddress(tag), tag.Kind, tag.Line)
+		if tag.Language != "" {
+			fmt.Fprintf(out, "\tlanguage:%s", ctagsField(tag.Language))
+		}
+		if tag.Scope != "" {
+			fmt.Fprintf(out, "\t%s:%s", tag.ScopeKind, ctagsField(tag.Scope))
+		}
+		if tag.Signature != "" {
+			fmt.Fprintf(out, "\tsignature:%s", ctagsField(tag.Signature))
+		}
+		if tag.Access != "" {
+			fmt.Fprintf(out, "\taccess:%s", ctagsField(tag.Access))
+		}
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
+
+// WriteEtags writes tags in the etags format read by Emacs, one section per file
+func (g *TagGenerator) WriteEtags(w io.Writer) error {
+	tags := g.Tags()
+	out := bufio.NewWriter(w)
+	for start := 0; start < len(tags); {
+		end := start
+		var section strings.Builder
+		for ; end < len(tags) && tags[end].File == tags[start].File; end++ {
+			tag := tags[end]
+			text := tag.Text
+			if text == "" {
+				text = tag.Name
+			} else if end := tag.column + len(tag.Name); end <= len(text) {
+				text = text[:end]
+			}
+			fmt.Fprintf(&section, "%s\x7f%s\x01%d,%d\n", text, tag.Name, tag.Line, tag.Offset)
+		}
+		fmt.Fprintf(out, "\x0c\n%s,%d\n%s", tags[start].File, section.Len(), section.String())
+		start = end
+	}
+	return out.Flush()
+}
+
+// fileTags lists the tags of one file
+func (g *TagGenerator) fileTags(path string, scopes map[string]string) []Tag {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(string(content), "\n")
+	offsets := make([]int, len(lines))
+	for i := 1; i < len(lines); i++ {
+		offsets[i] = offsets[i-1] + len(lines[i-1]) + 1
+	}
+	file := g.graph.Files[path]
+
+	// Of several symbols with one name on a line the first ranked is kept,
+	// so noise such as a namespace symbol for an exported class is not
+	var symbols []*types.Symbol
+	for _, id := range file.Symbols {
+		symbol := g.graph.Symbols[id]
+		if symbol == nil || symbol.Location.StartLine < 1 || symbol.Location.StartLine > len(lines) {
+			continue
+		}
+		if tagKind(symbol) != "" && validTagName(symbol) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.SliceStable(symbols, func(i, j int) bool {
+		a, b := symbols[i], symbols[j]
+		if a.Location.StartLine != b.Location.StartLine {
+			return a.Location.StartLine < b.Location.StartLine
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return chunkRank(a) < chunkRank(b)
+	})
+
+	rel := filepath.ToSlash(path)
+	if base, err := filepath.Abs(g.baseDir); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			if r, err := filepath.Rel(base, abs); err == nil {
+				rel = filepath.ToSlash(r)
+			}
+		}
+	}
+	dir := filepath.Dir(path)
+
+	var tags []Tag
+	var enclosing *Tag // Last class-like tag, the scope of methods without one
+	for i, symbol := range symbols {
+		if i > 0 && symbol.Location.StartLine == symbols[i-1].Location.StartLine && symbol.Name == symbols[i-1].Name {
+			continue
+		}
+		line := strings.TrimSuffix(lines[symbol.Location.StartLine-1], "\r")
+		column := identifierIndex(line, symbol.Name)
+		column16 := len(utf16.Encode([]rune(line[:column])))
+		if g.redactor.Text(line) != line {
+			line = ""
+		}
+		tag := Tag{
+			Name:      symbol.Name,
+			File:      rel,
+			Line:      symbol.Location.StartLine,
+			Kind:      tagKind(symbol),
+			Language:  file.Language,
+			Signature: tagSignature(symbol),
+			Access:    symbol.Visibility,
+			Text:      line,
+			Offset:    offsets[symbol.Location.StartLine-1],
+			symbol:    symbol,
+			column:    column,
+			column16:  column16,
+		}
+
+		if receiver := symbol.MetadataString(parser.MetadataReceiver); receiver != "" {
+			tag.Scope, tag.ScopeKind = receiver, scopeKind(scopes, dir, receiver, "type")
+		} else if scope := symbol.MetadataString(parser.MetadataScope); scope != "" {
+			name := scope
+			if i := strings.LastIndex(scope, "::"); i >= 0 {
+				name = scope[i+2:]
+			}
+			tag.Scope, tag.ScopeKind = scope, scopeKind(scopes, dir, name, "namespace")
+		} else if (tag.Kind == "m" || tag.Kind == "p") && enclosing != nil {
+			tag.Scope, tag.ScopeKind = enclosing.Name, scopeKinds[enclosing.Kind]
+		}
+		if _, ok := scopeKinds[tag.Kind]; ok && tag.Kind != "n" {
+			enclosing = &tag
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// scopeIndex maps the names of class-like symbols to their scope kind, keyed
+// by directory and name and by name alone
+func (g *TagGenerator) scopeIndex() map[string]string {
+	scopes := make(map[string]string)
+	for path, file := range g.graph.Files {
+		for _, id := range file.Symbols {
+			symbol := g.graph.Symbols[id]
+			if symbol == nil {
+				continue
+			}
+			if kind, ok := scopeKinds[tagKind(symbol)]; ok {
+				scopes[filepath.Dir(path)+"\x00"+symbol.Name] = kind
+				if _, seen := scopes[symbol.Name]; !seen {
+					scopes[symbol.Name] = kind
+				}
+			}
+		}
+	}
+	return scopes
+}
+
+// scopeKind looks up the kind of a scope declared in dir, else anywhere
+func scopeKind(scopes map[string]string, dir, name, fallback string) string {
+	if kind, ok := scopes[dir+"\x00"+name]; ok {
+		return kind
+	}
+	if kind, ok := scopes[name]; ok {
+		return kind
+	}
+	return fallback
+}
+
+// placeholderTagNames are the names parsers give symbols they could not name
+var placeholderTagNames = map[string]bool{"unknown": true, "anonymous": true, "class": true, "function": true}
+
+// validTagName leaves out parser noise such as a placeholder name, a symbol
+// named after its type or a name holding part of a parameter list
+func validTagName(symbol *types.Symbol) bool {
+	name := symbol.Name
+	if name == "" || placeholderTagNames[name] || name == string(symbol.Type) || name == symbol.NormalizedKind() {
+		return false
+	}
+	if strings.HasPrefix(name, "operator") {
+		return true
+	}
+	return !strings.ContainsAny(name, " \t\r\n(),")
+}
+
+// tagSignature returns the parameter list of a function's signature, which
+// may spell out the whole declaration
+func tagSignature(symbol *types.Symbol) string {
+	signature := strings.TrimSpace(symbol.Signature)
+	if i := strings.Index(signature, symbol.Name+"("); i >= 0 {
+		signature = signature[i+len(symbol.Name):]
+	}
+	if !strings.HasPrefix(signature, "(") {
+		return ""
+	}
+	// Return types and qualifiers after the closing parenthesis are dropped
+	depth := 0
+	for i, r := range signature {
+		if r == '(' {
+			depth++
+		} else if r == ')' {
+			if depth--; depth == 0 {
+				signature = signature[:i+1]
+				break
+			}
+		}
+	}
+	return strings.Join(strings.Fields(signature), " ")
+}
+
+// identifierIndex returns the offset of name in line as a whole word, else
+// of its first occurrence, else 0
+func identifierIndex(line, name string) int {
+	first := -1
+	for from := 0; from < len(line); {
+		i := strings.Index(line[from:], name)
+		if i < 0 {
+			break
+		}
+		i += from
+		if first < 0 {
+			first = i
+		}
+		end := i + len(name)
+		if (i == 0 || !isIdentifierByte(line[i-1])) && (end == len(line) || !isIdentifierByte(line[end])) {
+			return i
+		}
+		from = i + 1
+	}
+	return max(first, 0)
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || b == '$' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
+
+// ctagsAddress returns the search pattern that finds a tag's line, or its
+// line number when the line is unknown
+func ctagsAddress(tag Tag) string {
+	if tag.Text == "" {
+		return fmt.Sprint(tag.Line)
+	}
+	text, anchor := tag.Text, "$"
+	if len(text) > ctagsPatternLimit {
+		cut := ctagsPatternLimit
+		for cut > 0 && !isRuneStart(text[cut]) {
+			cut--
+		}
+		text, anchor = text[:cut], ""
+	}
+	text = strings.NewReplacer(`\`, `\\`, `/`, `\/`).Replace(text)
+	return "/^" + text + anchor + "/"
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// ctagsField escapes a field value as the extended format requires
+func ctagsField(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
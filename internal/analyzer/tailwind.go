@@ -0,0 +1,613 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// tailwindPrefixes are the utility class prefixes generated from each theme section
+var tailwindPrefixes = map[string][]string{
+	"colors": {"bg", "text", "border", "border-x", "border-y", "border-t", "border-r", "border-b", "border-l", "ring", "ring-offset",
+		"outline", "fill", "stroke", "from", "via", "to", "divide", "placeholder", "accent", "caret", "decoration", "shadow"},
+	"spacing": {"p", "px", "py", "pt", "pr", "pb", "pl", "ps", "pe", "m", "mx", "my", "mt", "mr", "mb", "ml", "ms", "me",
+		"gap", "gap-x", "gap-y", "space-x", "space-y", "w", "h", "size", "min-w", "min-h", "max-w", "max-h",
+		"inset", "inset-x", "inset-y", "top", "right", "bottom", "left", "start", "end", "translate-x", "translate-y",
+		"scroll-m", "scroll-p", "basis", "indent"},
+	"fontSize":            {"text"},
+	"fontFamily":          {"font"},
+	"fontWeight":          {"font"},
+	"lineHeight":          {"leading"},
+	"letterSpacing":       {"tracking"},
+	"borderRadius":        {"rounded", "rounded-t", "rounded-r", "rounded-b", "rounded-l", "rounded-tl", "rounded-tr", "rounded-br", "rounded-bl"},
+	"borderWidth":         {"border", "border-x", "border-y", "border-t", "border-r", "border-b", "border-l"},
+	"boxShadow":           {"shadow"},
+	"dropShadow":          {"drop-shadow"},
+	"opacity":             {"opacity"},
+	"zIndex":              {"z"},
+	"width":               {"w"},
+	"height":              {"h"},
+	"minWidth":            {"min-w"},
+	"minHeight":           {"min-h"},
+	"maxWidth":            {"max-w"},
+	"maxHeight":           {"max-h"},
+	"animation":           {"animate"},
+	"transitionDuration":  {"duration"},
+	"backgroundImage":     {"bg"},
+	"gridTemplateColumns": {"grid-cols"},
+	"gridTemplateRows":    {"grid-rows"},
+	"aspectRatio":         {"aspect"},
+}
+
+// tailwindCSSThemeSections maps Tailwind v4 @theme variable namespaces to theme sections
+var tailwindCSSThemeSections = map[string]string{
+	"color": "colors", "spacing": "spacing", "text": "fontSize", "font": "fontFamily", "font-weight": "fontWeight",
+	"leading": "lineHeight", "tracking": "letterSpacing", "radius": "borderRadius", "shadow": "boxShadow",
+	"drop-shadow": "dropShadow", "animate": "animation", "breakpoint": "screens", "aspect": "aspectRatio",
+}
+
+// tailwindContentExtensions are scanned for class names besides the graph's JavaScript and TypeScript
+var tailwindContentExtensions = map[string]bool{
+	".html": true, ".htm": true, ".vue": true, ".svelte": true, ".astro": true, ".mdx": true, ".php": true,
+	".erb": true, ".hbs": true, ".njk": true, ".jinja": true, ".j2": true, ".css": true, ".scss": true,
+}
+
+var (
+	tailwindConfigName  = regexp.MustCompile(`^tailwind\.config\.(?:js|cjs|mjs|ts|cts|mts)$`)
+	tailwindTheme       = regexp.MustCompile(`\btheme\s*:\s*\{`)
+	tailwindPluginCall  = regexp.MustCompile(`\b(addUtilities|addComponents|matchUtilities|matchComponents)\(\s*\{`)
+	tailwindCSSLayer    = regexp.MustCompile(`@layer\s+(utilities|components)\s*\{`)
+	tailwindCSSUtility  = regexp.MustCompile(`@utility\s+([\w-]+?)(-\*)?\s*\{`)
+	tailwindCSSTheme    = regexp.MustCompile(`@theme(?:\s+\w+)*\s*\{`)
+	tailwindCSSVariable = regexp.MustCompile(`--([\w-]+)\s*:\s*([^;]+);`)
+	tailwindCSSImport   = regexp.MustCompile(`@import\s+["']tailwindcss["']|@tailwind\s+(?:base|components|utilities)`)
+	cssClassSelector    = regexp.MustCompile(`\.([A-Za-z_-][\w-]*)`)
+	tailwindClassSplit  = regexp.MustCompile("[\\s\"'`{}<>;,=()]+")
+)
+
+// TailwindToken is a design token of the Tailwind theme
+type TailwindToken struct {
+	Section  string `json:"section"` // Theme section, such as colors or spacing
+	Name     string `json:"name"`    // As used in class names: "brand-500"
+	Value    string `json:"value,omitempty"`
+	Extended bool   `json:"extended"` // Added under theme.extend rather than replacing the defaults
+	Uses     int    `json:"uses"`
+	Files    int    `json:"files"`
+}
+
+// TailwindUtility is a utility or component class defined by the project
+type TailwindUtility struct {
+	Name   string `json:"name"` // Class name; "text-shadow-*" for utilities taking values
+	Kind   string `json:"kind"` // utility or component
+	File   string `json:"file"` // Relative to the project root
+	Line   int    `json:"line"`
+	Uses   int    `json:"uses"`
+	Files  int    `json:"files"`
+	prefix bool
+}
+
+// TailwindReport is the Tailwind setup of a project and how its theme and
+// custom classes are used
+type TailwindReport struct {
+	Config    string            `json:"config"`    // Config file, or the stylesheet with the @theme of Tailwind v4
+	Tokens    []TailwindToken   `json:"tokens"`    // Most used first
+	Utilities []TailwindUtility `json:"utilities"` // Most used first
+	Files     int               `json:"files"`     // Files scanned for class names
+}
+
+// Unused returns the design tokens and custom classes no file uses
+func (r *TailwindReport) Unused() ([]TailwindToken, []TailwindUtility) {
+	var tokens []TailwindToken
+	var utilities []TailwindUtility
+	for _, token := range r.Tokens {
+		if token.Uses == 0 {
+			tokens = append(tokens, token)
+		}
+	}
+	for _, utility := range r.Utilities {
+		if utility.Uses == 0 {
+			utilities = append(utilities, utility)
+		}
+	}
+	return tokens, utilities
+}
+
+// AnalyzeTailwind reads the theme of tailwind.config.js (or the @theme
+// blocks of Tailwind v4 stylesheets), the utilities and components added by
+// plugins, @layer and @utility, and counts how often their classes appear
+// in the project's markup, components and stylesheets, including @apply.
+// Classes are matched the way Tailwind finds them, as tokens of the source
+// text, with variants, important markers and opacity modifiers removed;
+// breakpoints count as used through their variants. It returns nil when the
+// project does not use Tailwind.
+func AnalyzeTailwind(graph *types.CodeGraph, root string) *TailwindReport {
+	var configs, stylesheets, content []string
+	filepath.WalkDir(root, func(filePath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if entry.IsDir() {
+			if filePath != root && (assetSkipDirs[entry.Name()] || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(filePath))
+		switch {
+		case tailwindConfigName.MatchString(entry.Name()):
+			configs = append(configs, filePath)
+			return nil
+		case ext == ".css" || ext == ".scss":
+			stylesheets = append(stylesheets, filePath)
+		}
+		if tailwindContentExtensions[ext] && graph.Files[filePath] == nil {
+			content = append(content, filePath)
+		}
+		return nil
+	})
+	for filePath, file := range graph.Files {
+		if frontendLanguages[file.Language] && !file.IsTest && !tailwindConfigName.MatchString(filepath.Base(filePath)) {
+			content = append(content, filePath)
+		}
+	}
+	sort.Strings(configs)
+	sort.Strings(stylesheets)
+	sort.Strings(content)
+
+	report := &TailwindReport{}
+	var tokens []TailwindToken
+	var utilities []TailwindUtility
+	if len(configs) > 0 {
+		report.Config = projectPath(root, configs[0])
+		if data, err := os.ReadFile(configs[0]); err == nil {
+			tokens = tailwindConfigTokens(string(data))
+			utilities = tailwindPluginUtilities(string(data), report.Config)
+		}
+	}
+	for _, filePath := range stylesheets {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		css := string(data)
+		rel := projectPath(root, filePath)
+		if report.Config == "" && (tailwindCSSImport.MatchString(css) || tailwindCSSTheme.MatchString(css)) {
+			report.Config = rel
+		}
+		tokens = append(tokens, tailwindCSSTokens(css)...)
+		utilities = append(utilities, tailwindCSSUtilities(css, rel)...)
+	}
+	if report.Config == "" {
+		return nil
+	}
+
+	// Every class a token or custom class generates, by class name
+	tokenClasses := make(map[string][]int)
+	screens := make(map[string][]int)
+	for i, token := range tokens {
+		if token.Section == "screens" {
+			screens[token.Name] = append(screens[token.Name], i)
+			continue
+		}
+		for _, prefix := range tailwindPrefixes[token.Section] {
+			class := prefix + "-" + token.Name
+			if token.Name == "" {
+				class = prefix
+			}
+			tokenClasses[class] = append(tokenClasses[class], i)
+		}
+	}
+	utilityClasses := make(map[string]int)
+	for i, utility := range utilities {
+		utilityClasses[strings.TrimSuffix(utility.Name, "-*")] = i
+	}
+
+	tokenFiles := make([]map[string]bool, len(tokens))
+	utilityFiles := make([]map[string]bool, len(utilities))
+	mark := func(files []map[string]bool, i int, file string) {
+		if files[i] == nil {
+			files[i] = make(map[string]bool)
+		}
+		files[i][file] = true
+	}
+	for _, filePath := range content {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		report.Files++
+		candidates := tailwindClassSplit.Split(string(data), -1)
+		for c, candidate := range candidates {
+			variants, class := splitTailwindVariants(candidate)
+			if class == "" || (c > 0 && candidates[c-1] == "@utility") {
+				continue
+			}
+			for _, variant := range variants {
+				for _, i := range screens[variant] {
+					tokens[i].Uses++
+					mark(tokenFiles, i, filePath)
+				}
+			}
+			for _, name := range tailwindClassForms(class) {
+				for _, i := range tokenClasses[name] {
+					tokens[i].Uses++
+					mark(tokenFiles, i, filePath)
+				}
+				if i, ok := utilityClasses[name]; ok {
+					utilities[i].Uses++
+					mark(utilityFiles, i, filePath)
+				} else {
+					for i, utility := range utilities {
+						if utility.prefix && strings.HasPrefix(name, strings.TrimSuffix(utility.Name, "*")) {
+							utilities[i].Uses++
+							mark(utilityFiles, i, filePath)
+						}
+					}
+				}
+			}
+		}
+	}
+	for i := range tokens {
+		tokens[i].Files = len(tokenFiles[i])
+	}
+	for i := range utilities {
+		utilities[i].Files = len(utilityFiles[i])
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		if tokens[i].Uses != tokens[j].Uses {
+			return tokens[i].Uses > tokens[j].Uses
+		}
+		if tokens[i].Section != tokens[j].Section {
+			return tokens[i].Section < tokens[j].Section
+		}
+		return tokens[i].Name < tokens[j].Name
+	})
+	sort.SliceStable(utilities, func(i, j int) bool {
+		if utilities[i].Uses != utilities[j].Uses {
+			return utilities[i].Uses > utilities[j].Uses
+		}
+		return utilities[i].Name < utilities[j].Name
+	})
+	report.Tokens, report.Utilities = tokens, utilities
+	return report
+}
+
+// splitTailwindVariants splits "md:hover:!-mt-2" into its variants and the
+// class without the important and negative markers
+func splitTailwindVariants(candidate string) ([]string, string) {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range candidate {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ':':
+			if depth == 0 {
+				parts = append(parts, candidate[start:i])
+				start = i + 1
+			}
+		}
+	}
+	class := strings.TrimSuffix(strings.TrimPrefix(candidate[start:], "!"), "!")
+	return parts, strings.TrimPrefix(class, "-")
+}
+
+// tailwindClassForms returns a class with and without its opacity
+// modifier, "bg-brand/50" and "bg-brand"
+func tailwindClassForms(class string) []string {
+	if i := strings.LastIndex(class, "/"); i > 0 && !strings.Contains(class[i:], "]") {
+		return []string{class, class[:i]}
+	}
+	return []string{class}
+}
+
+// tailwindConfigTokens returns the design tokens of the theme of a
+// tailwind.config file, flattening nested keys with "-"
+func tailwindConfigTokens(config string) []TailwindToken {
+	loc := tailwindTheme.FindStringIndex(config)
+	if loc == nil {
+		return nil
+	}
+	theme, _ := parseJSObject(config, loc[1]-1)
+	var tokens []TailwindToken
+	add := func(section string, value jsValue, extended bool) {
+		flattenJSObject("", value, func(name, text string) {
+			tokens = append(tokens, TailwindToken{Section: section, Name: name, Value: text, Extended: extended})
+		})
+	}
+	for _, entry := range theme.object {
+		if entry.key == "extend" {
+			for _, section := range entry.value.object {
+				add(section.key, section.value, true)
+			}
+			continue
+		}
+		add(entry.key, entry.value, false)
+	}
+	return tokens
+}
+
+// flattenJSObject calls fn for every leaf of a theme value, joining keys
+// with "-" and dropping DEFAULT
+func flattenJSObject(prefix string, value jsValue, fn func(name, text string)) {
+	if value.object == nil {
+		if prefix != "" {
+			fn(prefix, value.text)
+		}
+		return
+	}
+	for _, entry := range value.object {
+		name := entry.key
+		if name == "DEFAULT" {
+			name = prefix
+		} else if prefix != "" {
+			name = prefix + "-" + name
+		}
+		if entry.value.object == nil {
+			fn(name, entry.value.text)
+		} else {
+			flattenJSObject(name, entry.value, fn)
+		}
+	}
+}
+
+// tailwindPluginUtilities returns the classes plugins of a config file add
+// with addUtilities, addComponents, matchUtilities and matchComponents
+func tailwindPluginUtilities(config, file string) []TailwindUtility {
+	var utilities []TailwindUtility
+	for _, m := range tailwindPluginCall.FindAllStringSubmatchIndex(config, -1) {
+		call := config[m[2]:m[3]]
+		kind := "utility"
+		if strings.HasSuffix(call, "Components") {
+			kind = "component"
+		}
+		object, _ := parseJSObject(config, m[1]-1)
+		for _, entry := range object.object {
+			utility := TailwindUtility{Kind: kind, File: file, Line: strings.Count(config[:m[0]], "\n") + 1}
+			if strings.HasPrefix(call, "match") {
+				utility.Name, utility.prefix = entry.key+"-*", true
+			} else if names := cssClassSelector.FindStringSubmatch(entry.key); names != nil {
+				utility.Name = names[1]
+			} else {
+				continue
+			}
+			utilities = append(utilities, utility)
+		}
+	}
+	return utilities
+}
+
+// tailwindCSSUtilities returns the classes a stylesheet adds in @layer
+// utilities and components blocks and with the @utility rule of Tailwind v4
+func tailwindCSSUtilities(css, file string) []TailwindUtility {
+	var utilities []TailwindUtility
+	seen := make(map[string]bool)
+	for _, m := range tailwindCSSLayer.FindAllStringSubmatchIndex(css, -1) {
+		kind := "utility"
+		if css[m[2]:m[3]] == "components" {
+			kind = "component"
+		}
+		block := css[m[1]:matchingBrace(css, m[1]-1)]
+		// Selectors are the text before each rule's "{" at the top of the block
+		for j := 0; j < len(block); {
+			open := strings.IndexByte(block[j:], '{')
+			if open < 0 {
+				break
+			}
+			for _, loc := range cssClassSelector.FindAllStringSubmatchIndex(block[j:j+open], -1) {
+				name := block[j+loc[2] : j+loc[3]]
+				if !seen[name] {
+					seen[name] = true
+					line := strings.Count(css[:m[1]+j+loc[0]], "\n") + 1
+					utilities = append(utilities, TailwindUtility{Name: name, Kind: kind, File: file, Line: line})
+				}
+			}
+			j = matchingBrace(block, j+open) + 1
+		}
+	}
+	for _, m := range tailwindCSSUtility.FindAllStringSubmatchIndex(css, -1) {
+		utility := TailwindUtility{Name: css[m[2]:m[3]], Kind: "utility", File: file, Line: strings.Count(css[:m[0]], "\n") + 1}
+		if m[4] >= 0 {
+			utility.Name, utility.prefix = utility.Name+"-*", true
+		}
+		utilities = append(utilities, utility)
+	}
+	return utilities
+}
+
+// tailwindCSSTokens returns the design tokens of the @theme blocks of a
+// Tailwind v4 stylesheet, such as --color-brand-500
+func tailwindCSSTokens(css string) []TailwindToken {
+	var tokens []TailwindToken
+	for _, loc := range tailwindCSSTheme.FindAllStringIndex(css, -1) {
+		block := css[loc[1]:matchingBrace(css, loc[1]-1)]
+		for _, m := range tailwindCSSVariable.FindAllStringSubmatch(block, -1) {
+			// The longest namespace wins: --font-weight-bold is a font weight
+			var namespace string
+			for candidate := range tailwindCSSThemeSections {
+				if strings.HasPrefix(m[1], candidate+"-") && len(candidate) > len(namespace) {
+					namespace = candidate
+				}
+			}
+			if namespace == "" {
+				continue
+			}
+			tokens = append(tokens, TailwindToken{
+				Section:  tailwindCSSThemeSections[namespace],
+				Name:     strings.TrimPrefix(m[1], namespace+"-"),
+				Value:    strings.TrimSpace(m[2]),
+				Extended: true,
+			})
+		}
+	}
+	return tokens
+}
+
+// jsValue is a value of a JavaScript object literal: an object, or the
+// text of anything else (strings unquoted, arrays joined with ", ")
+type jsValue struct {
+	object []jsEntry
+	text   string
+}
+
+type jsEntry struct {
+	key   string
+	value jsValue
+}
+
+// parseJSObject reads the object literal starting with the "{" at start and
+// returns it with the offset past its "}". Spreads, methods and computed
+// keys are skipped; other expressions are kept as text.
+func parseJSObject(src string, start int) (jsValue, int) {
+	value := jsValue{object: []jsEntry{}}
+	i := start + 1
+	for {
+		i = skipJSSpace(src, i)
+		if i >= len(src) {
+			return value, i
+		}
+		if src[i] == '}' {
+			return value, i + 1
+		}
+		if src[i] == ',' {
+			i++
+			continue
+		}
+
+		var key string
+		switch c := src[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return value, len(src)
+			}
+			key, i = src[i+1:i+1+end], i+end+2
+		case isJSIdentifierByte(c):
+			end := i
+			for end < len(src) && isJSIdentifierByte(src[end]) {
+				end++
+			}
+			key, i = src[i:end], end
+		default:
+			// Spreads and computed keys
+			_, i = parseJSExpression(src, i)
+			continue
+		}
+
+		i = skipJSSpace(src, i)
+		if i >= len(src) || src[i] != ':' {
+			// Shorthand properties and methods
+			_, i = parseJSExpression(src, i)
+			continue
+		}
+		var entry jsValue
+		entry, i = parseJSValue(src, skipJSSpace(src, i+1))
+		value.object = append(value.object, jsEntry{key: key, value: entry})
+	}
+}
+
+// parseJSValue reads one value of an object literal
+func parseJSValue(src string, i int) (jsValue, int) {
+	if i >= len(src) {
+		return jsValue{}, i
+	}
+	switch c := src[i]; c {
+	case '{':
+		return parseJSObject(src, i)
+	case '"', '\'', '`':
+		end := strings.IndexByte(src[i+1:], c)
+		if end < 0 {
+			return jsValue{}, len(src)
+		}
+		next := skipJSSpace(src, i+end+2)
+		if next < len(src) && (src[next] == ',' || src[next] == '}' || src[next] == ']') {
+			return jsValue{text: src[i+1 : i+1+end]}, next
+		}
+	case '[':
+		var items []string
+		j := i + 1
+		for {
+			j = skipJSSpace(src, j)
+			if j >= len(src) || src[j] == ']' {
+				return jsValue{text: strings.Join(items, ", ")}, j + 1
+			}
+			if src[j] == ',' {
+				j++
+				continue
+			}
+			var item jsValue
+			item, j = parseJSValue(src, j)
+			if item.object == nil {
+				items = append(items, item.text)
+			}
+		}
+	}
+	text, end := parseJSExpression(src, i)
+	return jsValue{text: strings.TrimSpace(text)}, end
+}
+
+// parseJSExpression skips an expression up to the "," or closing bracket
+// ending it at its depth, returning its text
+func parseJSExpression(src string, i int) (string, int) {
+	start, depth := i, 0
+	var quote byte
+	for ; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '{' || c == '[' || c == '(':
+			depth++
+		case c == '}' || c == ']' || c == ')':
+			if depth == 0 {
+				return src[start:i], i
+			}
+			depth--
+		case c == ',' && depth == 0:
+			return src[start:i], i
+		}
+	}
+	return src[start:], i
+}
+
+// skipJSSpace skips whitespace and comments
+func skipJSSpace(src string, i int) int {
+	for i < len(src) {
+		switch {
+		case src[i] == ' ' || src[i] == '\t' || src[i] == '\n' || src[i] == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				return len(src)
+			}
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return len(src)
+			}
+			i += end + 4
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func isJSIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
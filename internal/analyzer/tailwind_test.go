@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func analyzeTailwindFixture(t *testing.T, files map[string]string) *TailwindReport {
+	t.Helper()
+	dir := t.TempDir()
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	return AnalyzeTailwind(graph, dir)
+}
+
+func TestAnalyzeTailwindConfig(t *testing.T) {
+	report := analyzeTailwindFixture(t, map[string]string{
+		"tailwind.config.js": `const plugin = require('tailwindcss/plugin');
+
+module.exports = {
+  content: ['./src/**/*.{ts,tsx,html}'],
+  theme: {
+    screens: { tablet: '640px', desktop: '1280px' },
+    extend: {
+      colors: {
+        brand: { DEFAULT: '#1d4ed8', 500: '#3b82f6', 900: '#1e3a8a' },
+        'accent-warm': '#f97316',
+      },
+      spacing: { '18': '4.5rem' },
+      fontFamily: { display: ['Inter', 'sans-serif'] }, // A comment
+      borderRadius: { xl2: '1.25rem' },
+    },
+  },
+  plugins: [
+    plugin(function ({ addUtilities, matchUtilities }) {
+      addUtilities({ '.text-balance': { textWrap: 'balance' }, '.scrollbar-none': { scrollbarWidth: 'none' } });
+      matchUtilities({ 'text-shadow': (value) => ({ textShadow: value }) }, { values: {} });
+    }),
+  ],
+};
+`,
+		"src/App.tsx": "export function App() {\n  return (\n    <div className=\"bg-brand p-18 tablet:text-brand-500 hover:bg-brand/50 font-display\">\n" +
+			"      <h1 className={cn('text-balance', active && '!-mt-18')}>Hi</h1>\n      <p className=\"text-shadow-lg\">x</p>\n    </div>\n  );\n}\n",
+		"src/styles.css": "@tailwind base;\n@tailwind utilities;\n\n@layer components {\n  .btn-primary {\n    @apply bg-brand text-white rounded-xl2;\n  }\n" +
+			"  .card, .card-title:hover {\n    color: red;\n  }\n}\n",
+	})
+	require.NotNil(t, report)
+	assert.Equal(t, "tailwind.config.js", report.Config)
+	assert.Equal(t, 2, report.Files)
+
+	var tokens []string
+	for _, token := range report.Tokens {
+		tokens = append(tokens, fmt.Sprintf("%s %s=%s uses %d in %d", token.Section, token.Name, token.Value, token.Uses, token.Files))
+	}
+	assert.Equal(t, []string{
+		"colors brand=#1d4ed8 uses 3 in 2",
+		"spacing 18=4.5rem uses 2 in 1",
+		"borderRadius xl2=1.25rem uses 1 in 1",
+		"colors brand-500=#3b82f6 uses 1 in 1",
+		"fontFamily display=Inter, sans-serif uses 1 in 1",
+		"screens tablet=640px uses 1 in 1",
+		"colors accent-warm=#f97316 uses 0 in 0",
+		"colors brand-900=#1e3a8a uses 0 in 0",
+		"screens desktop=1280px uses 0 in 0",
+	}, tokens, "variants, important and negative markers and opacity modifiers do not hide a token")
+	assert.False(t, report.Tokens[len(report.Tokens)-1].Extended, "screens replace the defaults")
+
+	var unused []string
+	_, utilities := report.Unused()
+	for _, utility := range utilities {
+		unused = append(unused, fmt.Sprintf("%s %s %s:%d", utility.Kind, utility.Name, utility.File, utility.Line))
+	}
+	assert.Equal(t, []string{
+		"component btn-primary src/styles.css:5",
+		"component card src/styles.css:8",
+		"component card-title src/styles.css:8",
+		"utility scrollbar-none tailwind.config.js:19",
+	}, unused, "text-shadow-lg uses the text-shadow utility")
+}
+
+func TestAnalyzeTailwindTheme(t *testing.T) {
+	report := analyzeTailwindFixture(t, map[string]string{
+		"app/globals.css": "@import \"tailwindcss\";\n\n@theme {\n  --color-mint-500: oklch(0.72 0.11 178);\n  --font-weight-heavy: 900;\n  --breakpoint-3xl: 120rem;\n}\n\n" +
+			"@utility content-auto {\n  content-visibility: auto;\n}\n",
+		"app/page.tsx": "export default function Page() {\n  return <main className=\"3xl:bg-mint-500 content-auto\" />;\n}\n",
+	})
+	require.NotNil(t, report)
+	assert.Equal(t, "app/globals.css", report.Config)
+
+	uses := make(map[string]int)
+	for _, token := range report.Tokens {
+		uses[token.Section+" "+token.Name] = token.Uses
+	}
+	assert.Equal(t, map[string]int{"colors mint-500": 1, "fontWeight heavy": 0, "screens 3xl": 1}, uses)
+	require.Len(t, report.Utilities, 1)
+	assert.Equal(t, 1, report.Utilities[0].Uses)
+
+	assert.Nil(t, analyzeTailwindFixture(t, map[string]string{"main.go": "package main\n\nfunc main() {}\n"}))
+}
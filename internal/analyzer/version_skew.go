@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// VersionSkew is a third-party library that manifests of the same
+// ecosystem declare at different versions
+type VersionSkew struct {
+	Ecosystem string          `json:"ecosystem"`
+	Name      string          `json:"name"`
+	Uses      int             `json:"uses"`     // Files importing the library
+	Versions  []SkewedVersion `json:"versions"` // Most used first
+}
+
+// SkewedVersion is one of the versions a skewed library is declared at
+type SkewedVersion struct {
+	Version   string           `json:"version"`
+	Uses      int              `json:"uses"`
+	Manifests []SkewedManifest `json:"manifests"`
+}
+
+// SkewedManifest is a manifest declaring a skewed library
+type SkewedManifest struct {
+	File  string `json:"file"` // Relative to the project root
+	Scope string `json:"scope"`
+	Line  int    `json:"line"`
+	Uses  int    `json:"uses"` // Files importing the library for which this manifest is the nearest declaring it
+}
+
+// FindVersionSkew reports the libraries that the package.json, go.mod and
+// requirements*.txt files under root declare at more than one version, with
+// the files importing each version. Versions are compared as written, so
+// "^1.2.0" and "1.2.0" differ. Local and workspace packages, unpinned
+// requirements, peer dependencies and indirect Go requirements are left out.
+// The libraries declared at the most versions come first, then the most used.
+func FindVersionSkew(graph *types.CodeGraph, root string) []VersionSkew {
+	type library struct{ ecosystem, name string }
+	versions := make(map[library]map[string]*SkewedVersion)
+	names := make(map[library]string) // As first declared, since Python names are normalized
+	for _, manifest := range analyzeDependencyManifests(graph, root) {
+		ecosystem := manifest.check.Ecosystem
+		for _, dep := range manifest.dependencies {
+			version := comparableVersion(ecosystem, dep)
+			if version == "" {
+				continue
+			}
+			key := library{ecosystem, dep.Name}
+			if ecosystem == EcosystemPyPI {
+				key.name = normalizePythonName(dep.Name)
+			}
+			if versions[key] == nil {
+				versions[key] = make(map[string]*SkewedVersion)
+				names[key] = dep.Name
+			}
+			skewed := versions[key][version]
+			if skewed == nil {
+				skewed = &SkewedVersion{Version: version}
+				versions[key][version] = skewed
+			}
+			uses := len(manifest.used[dep.Name])
+			skewed.Uses += uses
+			skewed.Manifests = append(skewed.Manifests, SkewedManifest{File: manifest.check.File, Scope: dep.Scope, Line: dep.Line, Uses: uses})
+		}
+	}
+
+	var skews []VersionSkew
+	for key, byVersion := range versions {
+		if len(byVersion) < 2 {
+			continue
+		}
+		skew := VersionSkew{Ecosystem: key.ecosystem, Name: names[key]}
+		for _, version := range byVersion {
+			skew.Uses += version.Uses
+			skew.Versions = append(skew.Versions, *version)
+		}
+		sort.Slice(skew.Versions, func(i, j int) bool {
+			if skew.Versions[i].Uses != skew.Versions[j].Uses {
+				return skew.Versions[i].Uses > skew.Versions[j].Uses
+			}
+			return skew.Versions[i].Version < skew.Versions[j].Version
+		})
+		skews = append(skews, skew)
+	}
+	sort.Slice(skews, func(i, j int) bool {
+		switch {
+		case len(skews[i].Versions) != len(skews[j].Versions):
+			return len(skews[i].Versions) > len(skews[j].Versions)
+		case skews[i].Uses != skews[j].Uses:
+			return skews[i].Uses > skews[j].Uses
+		case skews[i].Ecosystem != skews[j].Ecosystem:
+			return skews[i].Ecosystem < skews[j].Ecosystem
+		}
+		return skews[i].Name < skews[j].Name
+	})
+	return skews
+}
+
+// comparableVersion returns the version a dependency is declared at, or ""
+// when it does not pin a registry version
+func comparableVersion(ecosystem string, dep DeclaredDependency) string {
+	version := strings.Join(strings.Fields(dep.Version), "")
+	switch ecosystem {
+	case EcosystemNPM:
+		if dep.Scope == "peerDependencies" || version == "*" || version == "latest" ||
+			strings.Contains(version, ":") || strings.Contains(version, "/") {
+			return "" // workspace:, file:, link:, npm: aliases, git and GitHub sources
+		}
+	case EcosystemGo:
+		if dep.Scope == "indirect" {
+			return ""
+		}
+	case EcosystemPyPI:
+		if strings.Contains(version, "://") {
+			return ""
+		}
+	}
+	return version
+}
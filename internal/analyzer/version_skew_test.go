@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindVersionSkew(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"package.json":                       `{"name": "monorepo", "devDependencies": {"typescript": "^5.4.0"}}`,
+		"packages/web/package.json":          `{"name": "web", "dependencies": {"react": "^18.2.0", "lodash": "^4.17.21", "@repo/ui": "workspace:*"}, "devDependencies": {"typescript": "^5.4.0"}}`,
+		"packages/admin/package.json":        `{"name": "admin", "dependencies": {"react": "^17.0.2", "lodash": "^4.17.21", "@repo/ui": "workspace:^"}, "peerDependencies": {"lodash": ">=4"}}`,
+		"packages/docs/package.json":         `{"name": "docs", "dependencies": {"react": "^18.2.0"}, "devDependencies": {"typescript": "~5.1.0"}}`,
+		"packages/web/src/App.jsx":           "import React from 'react';\n",
+		"packages/web/src/Page.jsx":          "import { useState } from 'react';\nimport get from 'lodash/get';\n",
+		"packages/admin/src/index.js":        "import React from 'react';\n",
+		"services/api/requirements.txt":      "requests==2.31.0\n",
+		"services/worker/requirements.txt":   "Requests==2.28.0\nboto3\n",
+		"services/worker/requirements-2.txt": "boto3\n",
+		"services/api/app.py":                "import requests\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	skews := FindVersionSkew(graph, dir)
+	require.Len(t, skews, 3, "workspace packages, peer ranges and unpinned requirements are not skew")
+
+	react := skews[0]
+	assert.Equal(t, EcosystemNPM, react.Ecosystem)
+	assert.Equal(t, "react", react.Name)
+	assert.Equal(t, 3, react.Uses)
+	require.Len(t, react.Versions, 2)
+	assert.Equal(t, SkewedVersion{Version: "^18.2.0", Uses: 2, Manifests: []SkewedManifest{
+		{File: "packages/docs/package.json", Scope: "dependencies", Line: 1, Uses: 0},
+		{File: "packages/web/package.json", Scope: "dependencies", Line: 1, Uses: 2},
+	}}, react.Versions[0])
+	assert.Equal(t, "^17.0.2", react.Versions[1].Version)
+	assert.Equal(t, 1, react.Versions[1].Uses)
+
+	assert.Equal(t, "requests", skews[1].Name, "Python names are compared normalized")
+	assert.Equal(t, []string{"==2.31.0", "==2.28.0"}, []string{skews[1].Versions[0].Version, skews[1].Versions[1].Version})
+
+	assert.Equal(t, "typescript", skews[2].Name)
+	assert.Len(t, skews[2].Versions, 2)
+}
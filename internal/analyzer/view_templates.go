@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// RelationshipRenders links the code that renders a view to its template
+const RelationshipRenders RelationshipType = "renders"
+
+// renderLanguages are the languages of web backends scanned for template rendering
+var renderLanguages = map[string]bool{
+	"go": true, "javascript": true, "typescript": true, "python": true, "java": true,
+}
+
+var (
+	// Flask, Django, Express/Koa, Go html/template and gin/echo, Spring MVC.
+	// Up to two arguments such as a request or a status code may precede the name.
+	renderCallPattern = regexp.MustCompile(`\b(render_template|render_to_string|render_to_response|get_template|select_template|TemplateResponse|render|Render|renderFile|ExecuteTemplate|HTML|ParseFiles|ModelAndView)\s*\(\s*(?:[\w.&*\[\]]+\s*,\s*){0,2}["'` + "`" + `]([^"'` + "`" + `\n]+)["'` + "`" + `]`)
+	// Django class-based views
+	templateNamePattern = regexp.MustCompile(`\btemplate_name\s*=\s*["']([^"'\n]+)["']`)
+
+	renderKeyword   = regexp.MustCompile(`^(\w+)\s*=[^=]`)
+	renderObjectKey = regexp.MustCompile(`^["']?(\w+)["']?\s*:`)
+)
+
+// templateRender is a place where code renders a template
+type templateRender struct {
+	Template  string
+	Line      int
+	Variables []string // Names of the context variables passed, sorted
+}
+
+// analyzeTemplateRenders links the handlers of web backends to the templates
+// they render, with the context variables they pass
+func (ra *RelationshipAnalyzer) analyzeTemplateRenders(metrics *RelationshipMetrics) {
+	ra.forEachSourceFileIn(renderLanguages, func(filePath, content string) {
+		for _, render := range extractTemplateRenders(content) {
+			target := ra.resolveTemplatePath(render.Template, filePath)
+			if target == "" {
+				continue
+			}
+			from := ra.enclosingNode(filePath, render.Line)
+			to := types.NodeId(fmt.Sprintf("file-%s", target))
+			edgeId := types.EdgeId(fmt.Sprintf("%s-%s-%s", RelationshipRenders, from, to))
+			if _, exists := ra.graph.Edges[edgeId]; exists {
+				continue
+			}
+			ra.graph.Edges[edgeId] = &types.GraphEdge{
+				Id:     edgeId,
+				From:   from,
+				To:     to,
+				Type:   string(RelationshipRenders),
+				Weight: 0.8,
+				Metadata: map[string]interface{}{
+					"file":      filePath,
+					"line":      render.Line,
+					"template":  render.Template,
+					"variables": render.Variables,
+				},
+			}
+			metrics.ByType[RelationshipRenders]++
+		}
+	})
+}
+
+// extractTemplateRenders finds the templates rendered by a source file
+func extractTemplateRenders(content string) []templateRender {
+	var renders []templateRender
+	for _, match := range renderCallPattern.FindAllStringSubmatchIndex(content, -1) {
+		renders = append(renders, templateRender{
+			Template:  content[match[4]:match[5]],
+			Line:      strings.Count(content[:match[0]], "\n") + 1,
+			Variables: renderVariables(content[match[1]:]),
+		})
+	}
+	for _, match := range templateNamePattern.FindAllStringSubmatchIndex(content, -1) {
+		renders = append(renders, templateRender{
+			Template: content[match[2]:match[3]],
+			Line:     strings.Count(content[:match[0]], "\n") + 1,
+		})
+	}
+	return renders
+}
+
+// renderVariables returns the context variables passed after the template
+// name: keyword arguments and the keys of a map or object literal
+func renderVariables(rest string) []string {
+	var variables []string
+	for _, argument := range splitArguments(rest) {
+		argument = strings.TrimSpace(argument)
+		// Django passes its context as context={...}
+		if match := renderKeyword.FindStringSubmatch(argument); match != nil && match[1] != "context" {
+			variables = append(variables, match[1])
+			continue
+		}
+		open := strings.IndexByte(argument, '{')
+		if open < 0 || !strings.HasSuffix(argument, "}") {
+			continue
+		}
+		for _, entry := range splitArguments(argument[open+1:len(argument)-1] + ")") {
+			entry = strings.TrimSpace(entry)
+			if match := renderObjectKey.FindStringSubmatch(entry); match != nil {
+				variables = append(variables, match[1])
+			} else if jsIdentifier.FindString(entry) == entry && entry != "" {
+				variables = append(variables, entry) // Shorthand property
+			}
+		}
+	}
+	return sortedUnique(variables)
+}
+
+// splitArguments splits call arguments at top-level commas, up to the
+// parenthesis closing the call
+func splitArguments(text string) []string {
+	var arguments []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			if depth == 0 {
+				return append(arguments, text[start:i])
+			}
+			depth--
+		case c == ',' && depth == 0:
+			arguments = append(arguments, text[start:i])
+			start = i + 1
+		}
+	}
+	return arguments
+}
+
+// resolveTemplatePath resolves a rendered, extended or included template
+// name to a template file: relative to fromFile, as a path suffix with or
+// without extensions, as a Rails partial, or as a Go named template. The
+// candidate closest to fromFile wins.
+func (ra *RelationshipAnalyzer) resolveTemplatePath(name, fromFile string) string {
+	if name == "" {
+		return ""
+	}
+	name = filepath.FromSlash(name)
+	if strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../") {
+		candidate := filepath.Join(filepath.Dir(fromFile), name)
+		if file, exists := ra.graph.Files[candidate]; exists && parser.IsTemplateLanguage(file.Language) {
+			return candidate
+		}
+	}
+	name = strings.TrimLeft(name, "./")
+
+	var candidates []string
+	for path, file := range ra.graph.Files {
+		if path != fromFile && parser.IsTemplateLanguage(file.Language) && templateNameMatches(path, name) {
+			candidates = append(candidates, path)
+		}
+	}
+	if len(candidates) == 0 {
+		// {{template "name"}} and ExecuteTemplate invoke a {{define "name"}}
+		for path, file := range ra.graph.Files {
+			if path == fromFile || file.Language != parser.LanguageGoTemplate {
+				continue
+			}
+			for _, symbolId := range file.Symbols {
+				if symbol := ra.graph.Symbols[symbolId]; symbol != nil && symbol.Name == name &&
+					(symbol.Subtype() == "define" || symbol.Subtype() == "block") {
+					candidates = append(candidates, path)
+					break
+				}
+			}
+		}
+	}
+
+	best := ""
+	bestShared := -1
+	for _, candidate := range candidates {
+		shared := sharedPrefixLength(filepath.Dir(candidate), filepath.Dir(fromFile))
+		if shared > bestShared || shared == bestShared && candidate < best {
+			best = candidate
+			bestShared = shared
+		}
+	}
+	return best
+}
+
+// templateNameMatches reports whether a template file is named by name:
+// "users/show.html" matches views/users/show.html and views/users/show.html.erb,
+// "users/form" also matches the partial views/users/_form.html.erb
+func templateNameMatches(path, name string) bool {
+	suffix := string(filepath.Separator) + name
+	if strings.HasSuffix(path, suffix) {
+		return true
+	}
+	dir, base := filepath.Split(path)
+	if i := strings.IndexByte(base, '.'); i > 0 {
+		base = base[:i]
+	}
+	stripped := dir + base
+	if strings.HasSuffix(stripped, suffix) || strings.HasSuffix(path, suffix+filepath.Ext(path)) {
+		return true
+	}
+	partialDir, partialBase := filepath.Split(name)
+	return strings.HasSuffix(stripped, string(filepath.Separator)+filepath.Join(partialDir, "_"+partialBase))
+}
@@ -0,0 +1,89 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeTemplateRenders(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app/views.py": "from flask import render_template\n\n\ndef show_order(order_id):\n    order = load(order_id)\n" +
+			"    return render_template(\"orders/show.html\", order=order, title=\"Order\")\n",
+		"app/templates/base.html":         "<html>{% block content %}{% endblock %}</html>\n",
+		"app/templates/orders/show.html":  "{% extends \"base.html\" %}\n{% block content %}{{ order.id }}{% endblock %}\n",
+		"web/server.js":                   "function home(req, res) {\n  res.render('index', { title: 'Home', user });\n}\n",
+		"web/views/index.hbs":             "<h1>{{title}}</h1>{{> footer}}\n",
+		"web/views/partials/footer.hbs":   "<footer>{{year}}</footer>\n",
+		"server/handlers.go":              "package server\n\nfunc About(w http.ResponseWriter, r *http.Request) {\n\ttmpl.ExecuteTemplate(w, \"about\", data)\n}\n",
+		"server/templates/about.tmpl":     "{{define \"about\"}}{{template \"layout\" .}}{{end}}\n",
+		"server/templates/layout.gohtml":  "{{define \"layout\"}}<p>{{.Name}}</p>{{end}}\n",
+		"server/templates/unused.tmpl":    "{{define \"unused\"}}{{end}}\n",
+		"app/templates/orders/_row.html":  "{% block row %}{% endblock %}\n",
+		"app/templates/orders/notes.html": "{% include \"./_row.html\" %}\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "jinja", graph.Files[filepath.Join(dir, "app/templates/orders/show.html")].Language, "HTML templates are recognized by their tags")
+
+	renders := make(map[string]*types.GraphEdge)
+	imports := make(map[string]string)
+	for _, edge := range graph.Edges {
+		switch edge.Type {
+		case string(RelationshipRenders):
+			renders[string(edge.To)] = edge
+		case "imports":
+			if resolved, ok := edge.Metadata["resolved_path"].(string); ok {
+				imports[string(edge.From)] = resolved
+			}
+		}
+	}
+
+	assert.Len(t, renders, 3, "templates nothing renders are left unlinked")
+
+	flask := renders[fmt.Sprintf("file-%s", filepath.Join(dir, "app/templates/orders/show.html"))]
+	require.NotNil(t, flask)
+	assert.Contains(t, string(flask.From), "show_order")
+	assert.Equal(t, 6, flask.Metadata["line"])
+	assert.Equal(t, []string{"order", "title"}, flask.Metadata["variables"])
+
+	express := renders[fmt.Sprintf("file-%s", filepath.Join(dir, "web/views/index.hbs"))]
+	require.NotNil(t, express, "names without extensions resolve to the engine's files")
+	assert.Equal(t, []string{"title", "user"}, express.Metadata["variables"])
+
+	goRender := renders[fmt.Sprintf("file-%s", filepath.Join(dir, "server/templates/about.tmpl"))]
+	require.NotNil(t, goRender, "Go templates resolve by the names they define")
+	assert.Contains(t, string(goRender.From), "About")
+
+	file := func(name string) string { return fmt.Sprintf("file-%s", filepath.Join(dir, name)) }
+	assert.Equal(t, filepath.Join(dir, "app/templates/base.html"), imports[file("app/templates/orders/show.html")])
+	assert.Equal(t, filepath.Join(dir, "web/views/partials/footer.hbs"), imports[file("web/views/index.hbs")])
+	assert.Equal(t, filepath.Join(dir, "server/templates/layout.gohtml"), imports[file("server/templates/about.tmpl")])
+	assert.Equal(t, filepath.Join(dir, "app/templates/orders/_row.html"), imports[file("app/templates/orders/notes.html")])
+}
+
+func TestRenderVariables(t *testing.T) {
+	tests := []struct {
+		rest     string
+		expected []string
+	}{
+		{`, user=user, items=items[:5])`, []string{"items", "user"}},
+		{`, {"title": title, 'count': len(x)})`, []string{"count", "title"}},
+		{`, context={"page": page})`, []string{"page"}},
+		{`, { title: 'Home', user, ...rest })`, []string{"title", "user"}},
+		{`, gin.H{"name": name})`, []string{"name"}},
+		{`, data)`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rest, func(t *testing.T) {
+			assert.Equal(t, tt.expected, renderVariables(tt.rest))
+		})
+	}
+}
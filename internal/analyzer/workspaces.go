@@ -0,0 +1,235 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// WorkspacePackage is a JavaScript/TypeScript package of a monorepo, found by
+// the name field of its package.json. Imports of the name resolve into Dir.
+type WorkspacePackage struct {
+	Name    string
+	Dir     string
+	Entries []string          // Entry files from source, module, main and types, relative to Dir
+	Exports map[string]string // Subpath exports such as "./utils" -> "./src/utils.ts"
+}
+
+// packageManifest is the part of package.json used for resolution
+type packageManifest struct {
+	Name    string          `json:"name"`
+	Source  string          `json:"source"`
+	Module  string          `json:"module"`
+	Main    string          `json:"main"`
+	Types   string          `json:"types"`
+	Exports json.RawMessage `json:"exports"`
+}
+
+// exportConditions are the conditional export keys tried in order
+var exportConditions = []string{"source", "types", "import", "module", "default", "require"}
+
+// LoadWorkspacePackages reads package.json files into workspace packages.
+// Manifests without a name or that fail to parse are skipped. When two
+// manifests declare the same name the first path in sort order wins.
+func LoadWorkspacePackages(manifests []string) []WorkspacePackage {
+	sorted := append([]string(nil), manifests...)
+	sort.Strings(sorted)
+
+	seen := make(map[string]bool)
+	var packages []WorkspacePackage
+	for _, manifestPath := range sorted {
+		data, err := os.ReadFile(manifestPath)
+		if err != nil {
+			continue
+		}
+		var manifest packageManifest
+		if err := json.Unmarshal(data, &manifest); err != nil || manifest.Name == "" || seen[manifest.Name] {
+			continue
+		}
+		seen[manifest.Name] = true
+
+		pkg := WorkspacePackage{Name: manifest.Name, Dir: filepath.Dir(manifestPath)}
+		for _, entry := range []string{manifest.Source, manifest.Module, manifest.Main, manifest.Types} {
+			if entry != "" {
+				pkg.Entries = append(pkg.Entries, entry)
+			}
+		}
+		pkg.Exports = parseExports(manifest.Exports)
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+// parseExports flattens the exports field into subpath -> file. Conditional
+// exports use the first condition of exportConditions; patterns with "*" are
+// left out.
+func parseExports(raw json.RawMessage) map[string]string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var exports interface{}
+	if err := json.Unmarshal(raw, &exports); err != nil {
+		return nil
+	}
+
+	result := make(map[string]string)
+	if object, ok := exports.(map[string]interface{}); ok && !hasSubpathKeys(object) {
+		// "exports": {"import": "./src/index.ts"} is shorthand for the "." subpath
+		exports = map[string]interface{}{".": object}
+	}
+	switch v := exports.(type) {
+	case string:
+		result["."] = v
+	case map[string]interface{}:
+		for subpath, target := range v {
+			if strings.Contains(subpath, "*") {
+				continue
+			}
+			if file := exportTarget(target); file != "" {
+				result[subpath] = file
+			}
+		}
+	}
+	return result
+}
+
+func hasSubpathKeys(object map[string]interface{}) bool {
+	for key := range object {
+		if strings.HasPrefix(key, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+func exportTarget(target interface{}) string {
+	switch v := target.(type) {
+	case string:
+		if strings.Contains(v, "*") {
+			return ""
+		}
+		return v
+	case map[string]interface{}:
+		for _, condition := range exportConditions {
+			if nested, ok := v[condition]; ok {
+				if file := exportTarget(nested); file != "" {
+					return file
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// isJavaScriptPath reports whether a file is JavaScript or TypeScript, the
+// languages that import workspace packages by name
+func isJavaScriptPath(file string) bool {
+	switch filepath.Ext(file) {
+	case ".ts", ".tsx", ".js", ".jsx", ".mts", ".cts", ".mjs", ".cjs":
+		return true
+	}
+	return false
+}
+
+// SetWorkspacePackages sets the monorepo packages that bare imports such as
+// "@myorg/utils" resolve to. Imports of packages whose name matches one of the
+// external patterns ("@myorg/legacy", "@myorg/*" or "*") stay external edges.
+func (ra *RelationshipAnalyzer) SetWorkspacePackages(packages []WorkspacePackage, external []string) {
+	ra.workspacePackages = packages
+	ra.externalWorkspaces = external
+}
+
+// workspacePackageFor returns the workspace package an import refers to and the
+// subpath within it ("" for the package root)
+func (ra *RelationshipAnalyzer) workspacePackageFor(importPath string) (*WorkspacePackage, string) {
+	var match *WorkspacePackage
+	for i := range ra.workspacePackages {
+		pkg := &ra.workspacePackages[i]
+		if importPath != pkg.Name && !strings.HasPrefix(importPath, pkg.Name+"/") {
+			continue
+		}
+		// Prefer the longest name so "@myorg/ui/icons" beats "@myorg/ui"
+		if match == nil || len(pkg.Name) > len(match.Name) {
+			match = pkg
+		}
+	}
+	if match == nil {
+		return nil, ""
+	}
+	return match, strings.TrimPrefix(strings.TrimPrefix(importPath, match.Name), "/")
+}
+
+// isExternalWorkspace reports whether imports of the package are configured to
+// stay external
+func (ra *RelationshipAnalyzer) isExternalWorkspace(name string) bool {
+	for _, pattern := range ra.externalWorkspaces {
+		if pattern == name {
+			return true
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveWorkspaceImport resolves an import of a sibling workspace package to a
+// file in its source directory
+func (ra *RelationshipAnalyzer) resolveWorkspaceImport(importPath string) string {
+	pkg, subpath := ra.workspacePackageFor(importPath)
+	if pkg == nil || ra.isExternalWorkspace(pkg.Name) {
+		return ""
+	}
+
+	var candidates []string
+	if target, ok := pkg.Exports["./"+subpath]; ok && subpath != "" {
+		candidates = append(candidates, target)
+	}
+	if subpath == "" {
+		if target, ok := pkg.Exports["."]; ok {
+			candidates = append(candidates, target)
+		}
+		candidates = append(candidates, pkg.Entries...)
+		candidates = append(candidates, "src/index", "index")
+	} else {
+		candidates = append(candidates, subpath, "src/"+subpath)
+	}
+
+	for _, candidate := range candidates {
+		if file := ra.resolveSourceFile(filepath.Join(pkg.Dir, filepath.FromSlash(candidate))); file != "" {
+			return file
+		}
+	}
+	return ""
+}
+
+// resolveSourceFile finds the analyzed file for a module path: the path itself,
+// with a JavaScript/TypeScript extension added or swapped (compiled "./x.js"
+// entries point at "./x.ts" sources) or the index file of a directory
+func (ra *RelationshipAnalyzer) resolveSourceFile(base string) string {
+	if _, exists := ra.graph.Files[base]; exists {
+		return base
+	}
+	extensions := []string{".ts", ".tsx", ".js", ".jsx", ".mts", ".mjs", ".cts", ".cjs"}
+	stems := []string{base}
+	if ext := filepath.Ext(base); ext != "" {
+		stems = append(stems, strings.TrimSuffix(base, ext))
+	}
+	for _, stem := range stems {
+		for _, ext := range extensions {
+			if _, exists := ra.graph.Files[stem+ext]; exists {
+				return stem + ext
+			}
+		}
+	}
+	for _, ext := range extensions {
+		candidate := filepath.Join(base, "index"+ext)
+		if _, exists := ra.graph.Files[candidate]; exists {
+			return candidate
+		}
+	}
+	return ""
+}
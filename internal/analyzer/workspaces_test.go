@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWorkspace(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"package.json":                           `{"name": "monorepo", "private": true, "workspaces": ["packages/*"]}`,
+		"packages/utils/package.json":            `{"name": "@myorg/utils", "main": "dist/index.js"}`,
+		"packages/utils/src/index.ts":            "export const format = (s: string) => s.trim();\n",
+		"packages/utils/src/dates.ts":            "export const today = () => new Date();\n",
+		"packages/ui/package.json":               `{"name": "@myorg/ui", "exports": {".": {"types": "./lib/main.ts", "default": "./dist/main.js"}, "./button": "./lib/button.tsx"}}`,
+		"packages/ui/lib/main.ts":                "export * from './button';\n",
+		"packages/ui/lib/button.tsx":             "import { format } from '@myorg/utils';\nexport const Button = () => format(' ok ');\n",
+		"packages/legacy/package.json":           `{"name": "@myorg/legacy", "source": "src/legacy.js"}`,
+		"packages/legacy/src/legacy.js":          "module.exports = {};\n",
+		"apps/web/src/app.ts":                    "import { format } from '@myorg/utils';\nimport { today } from '@myorg/utils/dates';\nimport { Button } from '@myorg/ui/button';\nimport * as ui from '@myorg/ui';\nimport legacy from '@myorg/legacy';\nimport React from 'react';\n",
+		"packages/broken/package.json":           `{"name": `,
+		"node_modules/@myorg/utils/package.json": `{"name": "@myorg/utils"}`,
+	}
+	testutils.WriteTree(t, dir, files)
+	return dir
+}
+
+// importEdgesFrom maps the import paths of a file's import edges to the edges
+func importEdgesFrom(graph *types.CodeGraph, file string) map[string]*types.GraphEdge {
+	edges := make(map[string]*types.GraphEdge)
+	for _, edge := range graph.Edges {
+		if edge.Type == string(RelationshipImport) && edge.From == types.NodeId("file-"+file) {
+			edges[edge.Metadata["import_path"].(string)] = edge
+		}
+	}
+	return edges
+}
+
+func TestWorkspacePackageImports(t *testing.T) {
+	dir := writeWorkspace(t)
+	builder := NewGraphBuilder()
+	builder.SetExternalWorkspacePackages([]string{"@myorg/leg*"})
+	graph, err := builder.AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	pkgFile := func(parts ...string) types.NodeId {
+		return types.NodeId("file-" + filepath.Join(append([]string{dir, "packages"}, parts...)...))
+	}
+	edges := importEdgesFrom(graph, filepath.Join(dir, "apps", "web", "src", "app.ts"))
+
+	tests := []struct {
+		importPath string
+		to         types.NodeId
+		workspace  string
+	}{
+		{"@myorg/utils", pkgFile("utils", "src", "index.ts"), "@myorg/utils"},       // main points at the build output
+		{"@myorg/utils/dates", pkgFile("utils", "src", "dates.ts"), "@myorg/utils"}, // subpath under src/
+		{"@myorg/ui/button", pkgFile("ui", "lib", "button.tsx"), "@myorg/ui"},       // subpath export
+		{"@myorg/ui", pkgFile("ui", "lib", "main.ts"), "@myorg/ui"},                 // conditional export
+		{"@myorg/legacy", "external-@myorg/legacy", "@myorg/legacy"},                // configured as external
+		{"react", "external-react", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			edge := edges[tt.importPath]
+			require.NotNil(t, edge)
+			assert.Equal(t, tt.to, edge.To)
+			if tt.workspace == "" {
+				assert.NotContains(t, edge.Metadata, "workspace_package")
+			} else {
+				assert.Equal(t, tt.workspace, edge.Metadata["workspace_package"])
+			}
+		})
+	}
+
+	// Cross-package edges between library packages resolve too
+	buttonEdges := importEdgesFrom(graph, filepath.Join(dir, "packages", "ui", "lib", "button.tsx"))
+	require.NotNil(t, buttonEdges["@myorg/utils"])
+	assert.Equal(t, pkgFile("utils", "src", "index.ts"), buttonEdges["@myorg/utils"].To)
+}
+
+func TestLoadWorkspacePackages(t *testing.T) {
+	dir := writeWorkspace(t)
+	manifests := []string{
+		filepath.Join(dir, "packages", "ui", "package.json"),
+		filepath.Join(dir, "packages", "broken", "package.json"),
+		filepath.Join(dir, "packages", "utils", "package.json"),
+		filepath.Join(dir, "packages", "utils", "package.json"),
+	}
+
+	packages := LoadWorkspacePackages(manifests)
+	require.Len(t, packages, 2, "unparsable and duplicate manifests are skipped")
+	assert.Equal(t, "@myorg/ui", packages[0].Name)
+	assert.Equal(t, map[string]string{".": "./lib/main.ts", "./button": "./lib/button.tsx"}, packages[0].Exports)
+	assert.Equal(t, "@myorg/utils", packages[1].Name)
+	assert.Equal(t, filepath.Join(dir, "packages", "utils"), packages[1].Dir)
+	assert.Equal(t, []string{"dist/index.js"}, packages[1].Entries)
+}
@@ -0,0 +1,214 @@
+package annotations
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileName is where annotations are stored, relative to the project directory
+const FileName = ".codecontext/annotations.json"
+
+// Annotation is a note attached to a file or to a symbol within a file
+type Annotation struct {
+	ID        string    `json:"id"`
+	File      string    `json:"file"`             // Relative to the project directory, slash-separated
+	Symbol    string    `json:"symbol,omitempty"` // Empty for file-level notes
+	Text      string    `json:"text"`
+	Author    string    `json:"author,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Target returns "file" or "file#symbol"
+func (a Annotation) Target() string {
+	if a.Symbol == "" {
+		return a.File
+	}
+	return a.File + "#" + a.Symbol
+}
+
+// Store holds the annotations of one project directory
+type Store struct {
+	Version     int          `json:"version"`
+	Annotations []Annotation `json:"annotations"`
+
+	path string
+}
+
+// updateMutex serializes read-modify-write cycles within the process
+var updateMutex sync.Mutex
+
+// Load reads the annotations of a project directory. A missing file is an empty store.
+func Load(baseDir string) (*Store, error) {
+	store := &Store{Version: 1, path: filepath.Join(baseDir, filepath.FromSlash(FileName))}
+	data, err := os.ReadFile(store.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations: %w", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("invalid annotations file %s: %w", store.path, err)
+	}
+	return store, nil
+}
+
+// Update loads the store, applies fn and saves the result if fn succeeds
+func Update(baseDir string, fn func(*Store) error) error {
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	store, err := Load(baseDir)
+	if err != nil {
+		return err
+	}
+	if err := fn(store); err != nil {
+		return err
+	}
+	return store.save()
+}
+
+// save writes the store through a temporary file so readers never see a partial file
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create annotations directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode annotations: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write annotations: %w", err)
+	}
+	return nil
+}
+
+// Add validates and appends an annotation, assigning its id and timestamp
+func (s *Store) Add(annotation Annotation) (Annotation, error) {
+	annotation.File = NormalizePath(annotation.File)
+	annotation.Text = strings.TrimSpace(annotation.Text)
+	if annotation.File == "" {
+		return Annotation{}, fmt.Errorf("annotation file is required")
+	}
+	if annotation.Text == "" {
+		return Annotation{}, fmt.Errorf("annotation text is required")
+	}
+
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return Annotation{}, fmt.Errorf("failed to generate annotation id: %w", err)
+	}
+	annotation.ID = hex.EncodeToString(id)
+	if annotation.CreatedAt.IsZero() {
+		annotation.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	s.Annotations = append(s.Annotations, annotation)
+	return annotation, nil
+}
+
+// Remove deletes the annotation with the given id
+func (s *Store) Remove(id string) (Annotation, error) {
+	for i, annotation := range s.Annotations {
+		if annotation.ID == id {
+			s.Annotations = append(s.Annotations[:i], s.Annotations[i+1:]...)
+			return annotation, nil
+		}
+	}
+	return Annotation{}, fmt.Errorf("annotation %q not found", id)
+}
+
+// ForFile returns the annotations on a file and on its symbols, file-level notes first
+func (s *Store) ForFile(file string) []Annotation {
+	file = NormalizePath(file)
+	var matched []Annotation
+	for _, annotation := range s.Annotations {
+		if annotation.File == file {
+			matched = append(matched, annotation)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Symbol == "" && matched[j].Symbol != ""
+	})
+	return matched
+}
+
+// ForSymbol returns the annotations on a symbol. An empty file matches the symbol in any file.
+func (s *Store) ForSymbol(file, symbol string) []Annotation {
+	file = NormalizePath(file)
+	var matched []Annotation
+	for _, annotation := range s.Annotations {
+		if annotation.Symbol == symbol && (file == "" || annotation.File == file) {
+			matched = append(matched, annotation)
+		}
+	}
+	return matched
+}
+
+// ParseTarget splits "file#symbol" into its parts
+func ParseTarget(target string) (file, symbol string) {
+	file, symbol, _ = strings.Cut(target, "#")
+	return NormalizePath(file), symbol
+}
+
+// NormalizePath cleans a project-relative path to the slash-separated stored form
+func NormalizePath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "./")
+}
+
+// RelativePath makes path relative to baseDir when it lies inside it
+func RelativePath(path, baseDir string) string {
+	if filepath.IsAbs(path) {
+		if abs, err := filepath.Abs(baseDir); err == nil {
+			baseDir = abs
+		}
+	}
+	if rel, err := filepath.Rel(baseDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return NormalizePath(rel)
+	}
+	return NormalizePath(path)
+}
+
+// Markdown renders annotations as a list, with symbol names when showSymbol is set
+func Markdown(annotations []Annotation, showSymbol bool) string {
+	var out strings.Builder
+	for _, annotation := range annotations {
+		out.WriteString("- ")
+		if showSymbol && annotation.Symbol != "" {
+			out.WriteString(fmt.Sprintf("`%s`: ", annotation.Symbol))
+		}
+		out.WriteString(fmt.Sprintf("%s _(%s)_\n", annotation.Text, annotation.Details()))
+	}
+	return out.String()
+}
+
+// Details lists the author, date, tags and id of the note
+func (a Annotation) Details() string {
+	var details []string
+	if a.Author != "" {
+		details = append(details, a.Author)
+	}
+	details = append(details, a.CreatedAt.Format("2006-01-02"))
+	if len(a.Tags) > 0 {
+		details = append(details, "#"+strings.Join(a.Tags, " #"))
+	}
+	details = append(details, "id "+a.ID)
+	return strings.Join(details, ", ")
+}
@@ -0,0 +1,94 @@
+package annotations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Load(dir)
+	require.NoError(t, err)
+	assert.Empty(t, store.Annotations)
+
+	var fileNote, symbolNote Annotation
+	require.NoError(t, Update(dir, func(store *Store) error {
+		if symbolNote, err = store.Add(Annotation{File: "./internal/db/pool.go", Symbol: "Acquire", Text: " Caller must Release ", Tags: []string{"gotcha"}}); err != nil {
+			return err
+		}
+		fileNote, err = store.Add(Annotation{File: "internal/db/pool.go", Text: "Owned by the storage team", Author: "dana"})
+		return err
+	}))
+	assert.Len(t, fileNote.ID, 8)
+	assert.NotEqual(t, fileNote.ID, symbolNote.ID)
+	assert.Equal(t, "internal/db/pool.go#Acquire", symbolNote.Target())
+	assert.FileExists(t, filepath.Join(dir, ".codecontext", "annotations.json"))
+
+	store, err = Load(dir)
+	require.NoError(t, err)
+	require.Len(t, store.Annotations, 2)
+	assert.Equal(t, "Caller must Release", store.Annotations[0].Text)
+
+	// File-level notes come first
+	notes := store.ForFile("internal/db/pool.go")
+	require.Len(t, notes, 2)
+	assert.Equal(t, fileNote.ID, notes[0].ID)
+	assert.Len(t, store.ForSymbol("internal/db/pool.go", "Acquire"), 1)
+	assert.Len(t, store.ForSymbol("", "Acquire"), 1)
+	assert.Empty(t, store.ForSymbol("other.go", "Acquire"))
+
+	// Failed updates are not saved
+	assert.Error(t, Update(dir, func(store *Store) error {
+		store.Annotations = nil
+		_, err := store.Remove("missing")
+		return err
+	}))
+	require.NoError(t, Update(dir, func(store *Store) error {
+		_, err := store.Remove(symbolNote.ID)
+		return err
+	}))
+	store, err = Load(dir)
+	require.NoError(t, err)
+	assert.Len(t, store.Annotations, 1)
+}
+
+func TestAddValidation(t *testing.T) {
+	store := &Store{}
+	_, err := store.Add(Annotation{File: "a.go"})
+	assert.Error(t, err)
+	_, err = store.Add(Annotation{Text: "note"})
+	assert.Error(t, err)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".codecontext"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".codecontext", "annotations.json"), []byte("{"), 0644))
+	_, err := Load(dir)
+	assert.Error(t, err)
+}
+
+func TestPaths(t *testing.T) {
+	file, symbol := ParseTarget("./src/app.ts#App")
+	assert.Equal(t, "src/app.ts", file)
+	assert.Equal(t, "App", symbol)
+
+	assert.Equal(t, "src/app.ts", RelativePath("/repo/src/app.ts", "/repo"))
+	assert.Equal(t, "src/app.ts", RelativePath("project/src/app.ts", "project"))
+	assert.Equal(t, "/elsewhere/app.ts", RelativePath("/elsewhere/app.ts", "/repo"))
+}
+
+func TestMarkdown(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	markdown := Markdown([]Annotation{
+		{ID: "1", File: "a.go", Text: "File note", CreatedAt: created},
+		{ID: "2", File: "a.go", Symbol: "Run", Text: "Not reentrant", Author: "sam", Tags: []string{"gotcha", "perf"}, CreatedAt: created},
+	}, true)
+	assert.Equal(t, "- File note _(2026-03-01, id 1)_\n- `Run`: Not reentrant _(sam, 2026-03-01, #gotcha #perf, id 2)_\n", markdown)
+}
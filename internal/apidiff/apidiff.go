@@ -0,0 +1,306 @@
+package apidiff
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+var (
+	// identifierPattern filters out parser artifacts such as "f(a," or keywords
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+	// goReceiverPattern captures the receiver type of a Go method signature
+	goReceiverPattern = regexp.MustCompile(`^func\s*\(\s*\w*\s*\*?\s*(\w+)`)
+)
+
+// skippedKinds are symbol kinds that are not part of a public API
+var skippedKinds = map[types.SymbolType]bool{
+	types.SymbolTypeImport:    true,
+	types.SymbolTypeNamespace: true,
+	types.SymbolTypeProperty:  true,
+	types.SymbolTypeConfigKey: true,
+	types.SymbolTypeResource:  true,
+}
+
+// Symbol is a public symbol of a package
+type Symbol struct {
+	Package   string `json:"package"` // Directory relative to the project root, "." for the root
+	Name      string `json:"name"`    // Methods are qualified by their type, e.g. "Store.Get"
+	Kind      string `json:"kind"`
+	Signature string `json:"signature,omitempty"`
+	File      string `json:"file"` // Relative to the project root
+	Line      int    `json:"line"`
+	Language  string `json:"language"`
+}
+
+// API is the public surface of a source tree
+type API struct {
+	Symbols   map[string]Symbol   // By package and name
+	Endpoints []analyzer.Endpoint // HTTP routes and gRPC services, files relative to the root
+}
+
+// key identifies a symbol across revisions
+func (s Symbol) key() string {
+	return s.Package + "\x00" + s.Name
+}
+
+// Extract collects the public symbols and endpoints of a graph analyzed from
+// root. Test files are left out. When several symbols share a package and
+// name, the first one by file and line is kept.
+func Extract(graph *types.CodeGraph, root string) *API {
+	api := &API{Symbols: make(map[string]Symbol)}
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return filepath.ToSlash(path)
+	}
+
+	paths := make([]string, 0, len(graph.Files))
+	for path, file := range graph.Files {
+		if !file.IsTest {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		file := graph.Files[path]
+		var classes []*types.Symbol
+		var symbols []*types.Symbol
+		for _, id := range file.Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil {
+				continue
+			}
+			if symbol.Type == types.SymbolTypeClass || symbol.Type == types.SymbolTypeInterface {
+				classes = append(classes, symbol)
+			}
+			if symbol.IsPublic() && !skippedKinds[symbol.Type] && !symbol.IsEmbedded() && identifierPattern.MatchString(symbol.Name) &&
+				symbol.Name != "function" && symbol.Name != "class" {
+				symbols = append(symbols, symbol)
+			}
+		}
+		sort.SliceStable(symbols, func(i, j int) bool { return symbols[i].Location.StartLine < symbols[j].Location.StartLine })
+
+		rel := relative(path)
+		pkg := filepath.ToSlash(filepath.Dir(rel))
+		for _, symbol := range symbols {
+			entry := Symbol{
+				Package:   pkg,
+				Name:      symbol.Name,
+				Kind:      string(symbol.Type),
+				Signature: strings.Join(strings.Fields(symbol.Signature), " "),
+				File:      rel,
+				Line:      symbol.Location.StartLine,
+				Language:  file.Language,
+			}
+			if symbol.Type == types.SymbolTypeMethod {
+				if owner := methodOwner(symbol, file.Language, classes); owner != "" {
+					entry.Name = owner + "." + symbol.Name
+				}
+			}
+			if _, exists := api.Symbols[entry.key()]; !exists {
+				api.Symbols[entry.key()] = entry
+			}
+		}
+	}
+
+	for _, endpoint := range analyzer.ServedEndpoints(graph) {
+		endpoint.File = relative(endpoint.File)
+		api.Endpoints = append(api.Endpoints, endpoint)
+	}
+	return api
+}
+
+// methodOwner returns the type a method belongs to: the receiver of a Go
+// method, else the nearest class declared above it
+func methodOwner(method *types.Symbol, language string, classes []*types.Symbol) string {
+	if language == "go" {
+		if m := goReceiverPattern.FindStringSubmatch(method.Signature); m != nil {
+			return m[1]
+		}
+		return ""
+	}
+	var owner *types.Symbol
+	for _, class := range classes {
+		if class.Location.StartLine <= method.Location.StartLine && (owner == nil || class.Location.StartLine > owner.Location.StartLine) {
+			owner = class
+		}
+	}
+	if owner == nil {
+		return ""
+	}
+	return owner.Name
+}
+
+// LoadDir extracts the public API of a directory as it is on disk
+func LoadDir(dir string, analyze analyzer.AnalyzeFunc) (*API, error) {
+	graph, err := analyze(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", dir, err)
+	}
+	return Extract(graph, dir), nil
+}
+
+// LoadRef extracts the public API of the repository directory as it was
+// committed at ref
+func LoadRef(ctx context.Context, repo *git.GitAnalyzer, ref string, analyze analyzer.AnalyzeFunc) (*API, error) {
+	var api *API
+	err := repo.WithTree(ctx, ref, func(dir string) error {
+		var err error
+		api, err = LoadDir(dir, analyze)
+		return err
+	})
+	return api, err
+}
+
+// ChangeType classifies a change to a public symbol
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed" // Same package and name, different signature or kind
+	ChangeMoved   ChangeType = "moved"   // Same name, kind and signature in another package
+)
+
+// Change is a difference in the public API between two revisions
+type Change struct {
+	Type ChangeType `json:"type"`
+	Old  *Symbol    `json:"old,omitempty"` // Nil for added symbols
+	New  *Symbol    `json:"new,omitempty"` // Nil for removed symbols
+}
+
+// Symbol returns the symbol as of the newer revision, or the removed one
+func (c Change) Symbol() Symbol {
+	if c.New != nil {
+		return *c.New
+	}
+	return *c.Old
+}
+
+// Diff holds the differences between two revisions of a directory
+type Diff struct {
+	From             string              `json:"from"`
+	To               string              `json:"to"` // Empty for the working tree
+	Changes          []Change            `json:"changes"`
+	AddedEndpoints   []analyzer.Endpoint `json:"added_endpoints,omitempty"`
+	RemovedEndpoints []analyzer.Endpoint `json:"removed_endpoints,omitempty"`
+	Moves            []git.FileMove      `json:"moves,omitempty"`
+}
+
+// CompareRefs analyzes dir at two git refs with analyze and compares their
+// public APIs. An empty to compares with the working tree.
+func CompareRefs(ctx context.Context, dir, from, to string, analyze analyzer.AnalyzeFunc) (*Diff, error) {
+	repo, err := git.NewGitAnalyzer(dir)
+	if err != nil {
+		return nil, err
+	}
+	fromCommit, err := repo.ResolveRef(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	oldAPI, err := LoadRef(ctx, repo, fromCommit, analyze)
+	if err != nil {
+		return nil, err
+	}
+
+	var toCommit string
+	var newAPI *API
+	if to == "" {
+		newAPI, err = LoadDir(dir, analyze)
+	} else if toCommit, err = repo.ResolveRef(ctx, to); err == nil {
+		newAPI, err = LoadRef(ctx, repo, toCommit, analyze)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := Compare(oldAPI, newAPI)
+	diff.From, diff.To = from, to
+	if diff.Moves, err = repo.GetFileMoves(ctx, fromCommit, toCommit); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// Compare lists the public symbols and endpoints added, removed, changed or
+// moved between two APIs, sorted by package and name
+func Compare(oldAPI, newAPI *API) *Diff {
+	diff := &Diff{}
+	var added, removed []Symbol
+	for key, oldSymbol := range oldAPI.Symbols {
+		newSymbol, ok := newAPI.Symbols[key]
+		switch {
+		case !ok:
+			removed = append(removed, oldSymbol)
+		case oldSymbol.Kind != newSymbol.Kind || oldSymbol.Signature != newSymbol.Signature:
+			diff.Changes = append(diff.Changes, Change{Type: ChangeChanged, Old: &oldSymbol, New: &newSymbol})
+		}
+	}
+	for key, newSymbol := range newAPI.Symbols {
+		if _, ok := oldAPI.Symbols[key]; !ok {
+			added = append(added, newSymbol)
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool { return added[i].key() < added[j].key() })
+	sort.Slice(removed, func(i, j int) bool { return removed[i].key() < removed[j].key() })
+
+	// A removed symbol reappearing unchanged in exactly one other package moved
+	byIdentity := make(map[string][]int)
+	identity := func(s Symbol) string { return s.Name + "\x00" + s.Kind + "\x00" + s.Signature }
+	for i, symbol := range added {
+		byIdentity[identity(symbol)] = append(byIdentity[identity(symbol)], i)
+	}
+	moved := make(map[int]bool)
+	for _, oldSymbol := range removed {
+		candidates := byIdentity[identity(oldSymbol)]
+		if len(candidates) == 1 && !moved[candidates[0]] {
+			moved[candidates[0]] = true
+			newSymbol := added[candidates[0]]
+			diff.Changes = append(diff.Changes, Change{Type: ChangeMoved, Old: &oldSymbol, New: &newSymbol})
+			continue
+		}
+		diff.Changes = append(diff.Changes, Change{Type: ChangeRemoved, Old: &oldSymbol})
+	}
+	for i, symbol := range added {
+		if !moved[i] {
+			diff.Changes = append(diff.Changes, Change{Type: ChangeAdded, New: &symbol})
+		}
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i].Symbol(), diff.Changes[j].Symbol()
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Name < b.Name
+	})
+
+	diff.AddedEndpoints = endpointsMissing(newAPI.Endpoints, oldAPI.Endpoints)
+	diff.RemovedEndpoints = endpointsMissing(oldAPI.Endpoints, newAPI.Endpoints)
+	return diff
+}
+
+// endpointsMissing returns the endpoints of a that b does not serve
+func endpointsMissing(a, b []analyzer.Endpoint) []analyzer.Endpoint {
+	served := make(map[string]bool)
+	for _, endpoint := range b {
+		served[endpoint.String()] = true
+	}
+	var missing []analyzer.Endpoint
+	for _, endpoint := range a {
+		if !served[endpoint.String()] {
+			missing = append(missing, endpoint)
+			served[endpoint.String()] = true
+		}
+	}
+	return missing
+}
@@ -0,0 +1,105 @@
+package apidiff
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitFiles writes files into a repository, removing those with empty
+// content, and commits and tags the result
+func commitFiles(t *testing.T, dir, tag string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if content == "" {
+			require.NoError(t, os.Remove(path))
+			continue
+		}
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", tag},
+		{"tag", tag},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func TestCompareRefs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command("git", "init", "-q")
+	cmd.Dir = dir
+	require.NoError(t, cmd.Run())
+
+	helpers := "package util\n\n// Slug makes a URL-safe name\nfunc Slug(name string) string {\n\treturn name\n}\n\nfunc trim(s string) string { return s }\n"
+	commitFiles(t, dir, "v1.0.0", map[string]string{
+		"store/store.go":  "package store\n\ntype Store struct{}\n\nfunc (s *Store) Get(key string) string { return \"\" }\n\nfunc (s *Store) Close() {}\n",
+		"api/routes.go":   "package api\n\nimport \"net/http\"\n\nfunc Routes(mux *http.ServeMux) {\n\tmux.HandleFunc(\"/orders\", nil)\n}\n",
+		"util/helpers.go": helpers,
+		"util/format.go":  "package util\n\nfunc Money(cents int) string { return \"\" }\n",
+	})
+	commitFiles(t, dir, "v1.1.0", map[string]string{
+		"store/store.go":  "package store\n\ntype Store struct{}\n\nfunc (s *Store) Get(key string) (string, error) { return \"\", nil }\n\nfunc Open(path string) *Store { return nil }\n",
+		"api/routes.go":   "package api\n\nimport \"net/http\"\n\nfunc Routes(mux *http.ServeMux) {\n\tmux.HandleFunc(\"/orders\", nil)\n\tmux.HandleFunc(\"POST /refunds\", nil)\n}\n",
+		"util/helpers.go": "",
+		"text/helpers.go": "package text\n" + helpers[len("package util\n"):],
+	})
+
+	diff, err := CompareRefs(context.Background(), dir, "v1.0.0", "v1.1.0", analyzer.AnalyzeDirectory)
+	require.NoError(t, err)
+
+	changes := make(map[string]Change)
+	for _, change := range diff.Changes {
+		changes[change.Symbol().Package+" "+change.Symbol().Name] = change
+	}
+	assert.Len(t, diff.Changes, 4)
+	assert.Equal(t, ChangeChanged, changes["store Store.Get"].Type)
+	assert.Equal(t, "func (s *Store) Get(key string) (string, error)", changes["store Store.Get"].New.Signature)
+	assert.Equal(t, ChangeRemoved, changes["store Store.Close"].Type)
+	assert.Equal(t, ChangeAdded, changes["store Open"].Type)
+	assert.Equal(t, ChangeMoved, changes["text Slug"].Type)
+	assert.Equal(t, "util", changes["text Slug"].Old.Package)
+	assert.NotContains(t, changes, "text trim", "unexported symbols are not part of the API")
+
+	require.Len(t, diff.AddedEndpoints, 1)
+	assert.Equal(t, "POST /refunds", diff.AddedEndpoints[0].String())
+	assert.Empty(t, diff.RemovedEndpoints)
+	require.Len(t, diff.Moves, 1)
+	assert.Equal(t, "util/helpers.go", diff.Moves[0].From)
+	assert.Equal(t, "text/helpers.go", diff.Moves[0].To)
+
+	notes := diff.ReleaseNotes()
+	assert.Contains(t, notes, "# Release Notes Draft: v1.0.0 → v1.1.0")
+	assert.Contains(t, notes, "## `store`\n\n**Added**\n\n- `Open` (function): `func Open(path string) *Store`\n")
+	assert.Contains(t, notes, "- `Store.Get` (method): `func (s *Store) Get(key string) string` → `func (s *Store) Get(key string) (string, error)`")
+	assert.Contains(t, notes, "**Removed**\n\n- `Store.Close` (method)")
+	assert.Contains(t, notes, "## `text`\n\n**Moved**\n\n- `Slug` (function) from `util`")
+	assert.Contains(t, notes, "**New**\n\n- `POST /refunds` (`api/routes.go:7`)")
+	assert.Contains(t, notes, "## File Moves\n\n- `util/helpers.go` → `text/helpers.go` (")
+
+	// The working tree matches the last tag
+	diff, err = CompareRefs(context.Background(), dir, "v1.1.0", "", analyzer.AnalyzeDirectory)
+	require.NoError(t, err)
+	assert.Contains(t, diff.ReleaseNotes(), "v1.1.0 → working tree\n")
+	assert.Contains(t, diff.ReleaseNotes(), "_No public API changes._")
+
+	_, err = CompareRefs(context.Background(), dir, "v9.9.9", "", analyzer.AnalyzeDirectory)
+	assert.ErrorContains(t, err, `unknown git ref "v9.9.9"`)
+	_, err = CompareRefs(context.Background(), dir, "--output=x", "", analyzer.AnalyzeDirectory)
+	assert.ErrorContains(t, err, "invalid git ref")
+}
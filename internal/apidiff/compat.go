@@ -0,0 +1,416 @@
+package apidiff
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+)
+
+// Compatibility rule ids, reported as the Rule of each violation
+const (
+	RuleRemoved   = "api-removed"   // A public symbol disappeared
+	RuleMoved     = "api-moved"     // A public symbol moved to another package
+	RuleKind      = "api-kind"      // A public symbol changed kind, e.g. class to interface
+	RuleSignature = "api-signature" // Parameters or results changed
+	RuleEndpoint  = "api-endpoint"  // An HTTP route or gRPC service disappeared
+)
+
+var (
+	pythonOptionalPattern = regexp.MustCompile(`^Optional\[(.*)\]$`)
+	pythonUnionPattern    = regexp.MustCompile(`^Union\[(.*)\]$`)
+	goMethodPattern       = regexp.MustCompile(`^func\s*\(`)
+)
+
+// severityRank orders severities, in increasing order
+var severityRank = map[string]int{rules.SeverityNote: 1, rules.SeverityWarning: 2, rules.SeverityError: 3}
+
+// CompatibilityRules describe the compatibility rule ids for SARIF and
+// Markdown reports
+var CompatibilityRules = []*rules.Rule{
+	rules.Builtin(RuleRemoved, rules.SeverityError, "public symbols must not be removed"),
+	rules.Builtin(RuleMoved, rules.SeverityError, "public symbols must not move to another package"),
+	rules.Builtin(RuleKind, rules.SeverityError, "public symbols must keep their kind"),
+	rules.Builtin(RuleSignature, rules.SeverityError, "signatures must stay compatible with existing callers"),
+	rules.Builtin(RuleEndpoint, rules.SeverityError, "served endpoints must not be removed"),
+}
+
+// topTypes accept any value, so changing from one to a concrete type narrows it
+var topTypes = map[string]bool{
+	"any": true, "unknown": true, "interface{}": true, "object": true, "Object": true, "Any": true, "typing.Any": true,
+}
+
+// param is a parsed parameter of a signature
+type param struct {
+	name     string
+	typ      string // Empty when the signature has no types
+	optional bool   // Has a default value or is marked optional
+	variadic bool
+}
+
+// Incompatibilities classifies the changes that can break users of the public
+// API: errors break callers (removed or moved symbols, required parameters
+// added, narrowed or changed types, changed results, removed endpoints),
+// warnings may break some of them (renamed Python parameters, Go parameter
+// types widened, unparsable signature changes) and notes are compatible but
+// visible (optional parameters added, parameter types widened). Added symbols
+// are compatible and not reported. Violations are sorted by severity, then
+// package and name.
+func (d *Diff) Incompatibilities() []rules.Violation {
+	var violations []rules.Violation
+	for _, change := range d.Changes {
+		symbol := change.Symbol()
+		name := qualifiedName(symbol)
+		switch change.Type {
+		case ChangeRemoved:
+			violations = append(violations, rules.Violation{
+				Rule: RuleRemoved, Severity: rules.SeverityError, File: change.Old.File, Line: change.Old.Line,
+				Message: fmt.Sprintf("%s %s was removed", change.Old.Kind, name),
+			})
+		case ChangeMoved:
+			violations = append(violations, rules.Violation{
+				Rule: RuleMoved, Severity: rules.SeverityError, File: change.New.File, Line: change.New.Line,
+				Message: fmt.Sprintf("%s %s moved from package %s", change.New.Kind, name, change.Old.Package),
+			})
+		case ChangeChanged:
+			if change.Old.Kind != change.New.Kind {
+				violations = append(violations, rules.Violation{
+					Rule: RuleKind, Severity: rules.SeverityError, File: change.New.File, Line: change.New.Line,
+					Message: fmt.Sprintf("%s changed from %s to %s", name, change.Old.Kind, change.New.Kind),
+				})
+				continue
+			}
+			severity, reasons := compareSignatures(change.Old.Signature, change.New.Signature, symbol.Language)
+			violations = append(violations, rules.Violation{
+				Rule: RuleSignature, Severity: severity, File: change.New.File, Line: change.New.Line,
+				Message: fmt.Sprintf("%s: %s", name, strings.Join(reasons, "; ")),
+			})
+		}
+	}
+	for _, endpoint := range d.RemovedEndpoints {
+		violations = append(violations, rules.Violation{
+			Rule: RuleEndpoint, Severity: rules.SeverityError, File: endpoint.File, Line: endpoint.Line,
+			Message: fmt.Sprintf("endpoint %s was removed", endpoint),
+		})
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return severityRank[violations[i].Severity] > severityRank[violations[j].Severity]
+	})
+	return violations
+}
+
+// qualifiedName names a symbol with its package, e.g. "store.Store.Get"
+func qualifiedName(symbol Symbol) string {
+	if symbol.Package == "." {
+		return symbol.Name
+	}
+	return symbol.Package + "." + symbol.Name
+}
+
+// compareSignatures returns the severity of a signature change and why
+func compareSignatures(oldSignature, newSignature, language string) (string, []string) {
+	oldParams, oldResult, oldOK := parseSignature(oldSignature, language)
+	newParams, newResult, newOK := parseSignature(newSignature, language)
+	if !oldOK || !newOK {
+		return rules.SeverityWarning, []string{fmt.Sprintf("signature changed from `%s` to `%s`", oldSignature, newSignature)}
+	}
+
+	severity := rules.SeverityNote
+	var reasons []string
+	report := func(level, reason string) {
+		if severityRank[level] > severityRank[severity] {
+			severity = level
+		}
+		reasons = append(reasons, reason)
+	}
+
+	for i := 0; i < len(oldParams) || i < len(newParams); i++ {
+		switch {
+		case i >= len(oldParams):
+			p := newParams[i]
+			switch {
+			case p.variadic && language == "go":
+				report(rules.SeverityWarning, fmt.Sprintf("variadic parameter %s added (breaks function values)", p.name))
+			case p.optional || p.variadic:
+				report(rules.SeverityNote, fmt.Sprintf("optional parameter %s added", p.name))
+			default:
+				report(rules.SeverityError, fmt.Sprintf("required parameter %s added", p.name))
+			}
+		case i >= len(newParams):
+			report(rules.SeverityError, fmt.Sprintf("parameter %s removed", oldParams[i].name))
+		default:
+			oldParam, newParam := oldParams[i], newParams[i]
+			if oldParam.optional && !newParam.optional && !newParam.variadic {
+				report(rules.SeverityError, fmt.Sprintf("parameter %s is now required", newParam.name))
+			}
+			if oldParam.variadic != newParam.variadic {
+				report(rules.SeverityError, fmt.Sprintf("parameter %s changed between variadic and single", newParam.name))
+			}
+			switch compareTypes(oldParam.typ, newParam.typ) {
+			case typeNarrowed:
+				report(rules.SeverityError, fmt.Sprintf("parameter %s narrowed from %s to %s", newParam.name, oldParam.typ, newParam.typ))
+			case typeChanged:
+				report(rules.SeverityError, fmt.Sprintf("parameter %s changed type from %s to %s", newParam.name, oldParam.typ, newParam.typ))
+			case typeWidened:
+				if language == "go" {
+					report(rules.SeverityWarning, fmt.Sprintf("parameter %s widened from %s to %s (breaks function values)", newParam.name, oldParam.typ, newParam.typ))
+				} else {
+					report(rules.SeverityNote, fmt.Sprintf("parameter %s widened from %s to %s", newParam.name, oldParam.typ, newParam.typ))
+				}
+			}
+			if oldParam.name != newParam.name && oldParam.name != "" && newParam.name != "" {
+				if language == "python" {
+					report(rules.SeverityWarning, fmt.Sprintf("parameter %s renamed to %s (breaks keyword arguments)", oldParam.name, newParam.name))
+				} else {
+					report(rules.SeverityNote, fmt.Sprintf("parameter %s renamed to %s", oldParam.name, newParam.name))
+				}
+			}
+		}
+	}
+
+	switch compareTypes(oldResult, newResult) {
+	case typeNarrowed:
+		report(rules.SeverityNote, fmt.Sprintf("result narrowed from %s to %s", orNone(oldResult), orNone(newResult)))
+	case typeWidened, typeChanged:
+		report(rules.SeverityError, fmt.Sprintf("result changed from %s to %s", orNone(oldResult), orNone(newResult)))
+	}
+
+	if len(reasons) == 0 {
+		reasons = append(reasons, fmt.Sprintf("signature reformatted from `%s` to `%s`", oldSignature, newSignature))
+	}
+	return severity, reasons
+}
+
+func orNone(typ string) string {
+	if typ == "" {
+		return "nothing"
+	}
+	return typ
+}
+
+// parseSignature splits a signature into parameters and result type. Go
+// signatures start with "func" and an optional receiver; other languages
+// record the parameter list, followed by ": T" or "-> T" when typed.
+func parseSignature(signature, language string) ([]param, string, bool) {
+	groups := parenGroups(signature)
+	index := 0
+	if language == "go" && goMethodPattern.MatchString(signature) {
+		index = 1 // Skip the receiver
+	}
+	if index >= len(groups) {
+		return nil, "", false
+	}
+	open, close := groups[index][0], groups[index][1]
+	result := strings.TrimSpace(signature[close+1:])
+	result = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(result, "->"), ":"))
+	result = strings.TrimSuffix(strings.TrimSpace(strings.TrimSuffix(result, "{")), ":")
+
+	var params []param
+	parts := parser.SplitTopLevel(signature[open+1:close], ',')
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		params = append(params, parseParam(part, language))
+	}
+	if language == "go" {
+		// "a, b int" declares both a and b as int
+		for i := len(params) - 2; i >= 0; i-- {
+			if params[i].typ == "" && params[i+1].typ != "" && params[i].name != "" {
+				params[i].typ = params[i+1].typ
+			}
+		}
+	}
+	return params, strings.TrimSpace(result), true
+}
+
+// parseParam parses one parameter in the style of its language
+func parseParam(part, language string) param {
+	var p param
+	if name, value, ok := cutTopLevel(part, '='); ok && value != "" {
+		p.optional = true
+		part = strings.TrimSpace(name)
+	}
+	switch language {
+	case "go":
+		fields := strings.Fields(part)
+		if len(fields) == 1 {
+			// A lone word is a name grouped with the next type, or an unnamed parameter's type
+			if strings.ContainsAny(fields[0], ".*[]") || strings.HasPrefix(fields[0], "...") {
+				p.typ = fields[0]
+			} else {
+				p.name = fields[0]
+			}
+		} else {
+			p.name = fields[0]
+			p.typ = strings.Join(fields[1:], " ")
+		}
+		if strings.HasPrefix(p.typ, "...") {
+			p.variadic = true
+		}
+	case "java", "csharp", "cpp", "c":
+		var fields []string
+		for _, field := range strings.Fields(part) {
+			if !strings.HasPrefix(field, "@") && field != "final" {
+				fields = append(fields, field)
+			}
+		}
+		if len(fields) > 0 {
+			p.name = fields[len(fields)-1]
+			p.typ = strings.Join(fields[:len(fields)-1], " ")
+		}
+		p.variadic = strings.Contains(p.typ, "...")
+	default:
+		name, typ, _ := cutTopLevel(part, ':')
+		p.name, p.typ = strings.TrimSpace(name), strings.TrimSpace(typ)
+		if strings.HasSuffix(p.name, "?") {
+			p.optional = true
+			p.name = strings.TrimSuffix(p.name, "?")
+		}
+		if strings.HasPrefix(p.name, "...") || strings.HasPrefix(p.name, "*") {
+			p.variadic = true
+		}
+	}
+	return p
+}
+
+// Type relations between two revisions of a parameter or result type
+const (
+	typeSame = iota
+	typeNarrowed
+	typeWidened
+	typeChanged
+)
+
+// compareTypes relates a new type to an old one. Unions compare as sets of
+// members; untyped and any-like types accept everything.
+func compareTypes(oldType, newType string) int {
+	oldType, newType = strings.Join(strings.Fields(oldType), ""), strings.Join(strings.Fields(newType), "")
+	if oldType == newType {
+		return typeSame
+	}
+	oldTop, newTop := oldType == "" || topTypes[oldType], newType == "" || topTypes[newType]
+	switch {
+	case oldTop && newTop:
+		return typeSame
+	case oldTop:
+		return typeNarrowed
+	case newTop:
+		return typeWidened
+	}
+
+	oldSet, newSet := unionMembers(oldType), unionMembers(newType)
+	switch {
+	case isSubset(newSet, oldSet):
+		return typeNarrowed
+	case isSubset(oldSet, newSet):
+		return typeWidened
+	}
+	return typeChanged
+}
+
+// unionMembers splits "A | B", "Optional[A]" and "Union[A, B]" into members
+func unionMembers(typ string) map[string]bool {
+	if m := pythonOptionalPattern.FindStringSubmatch(typ); m != nil {
+		typ = m[1] + "|None"
+	} else if m := pythonUnionPattern.FindStringSubmatch(typ); m != nil {
+		typ = strings.Join(parser.SplitTopLevel(m[1], ','), "|")
+	}
+	members := make(map[string]bool)
+	for _, member := range parser.SplitTopLevel(typ, '|') {
+		members[strings.TrimSpace(member)] = true
+	}
+	return members
+}
+
+// isSubset reports whether a is a proper subset of b
+func isSubset(a, b map[string]bool) bool {
+	if len(a) >= len(b) {
+		return false
+	}
+	for member := range a {
+		if !b[member] {
+			return false
+		}
+	}
+	return true
+}
+
+// parenGroups returns the start and end offsets of the top-level parentheses
+func parenGroups(s string) [][2]int {
+	var groups [][2]int
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				groups = append(groups, [2]int{start, i})
+			}
+		}
+	}
+	return groups
+}
+
+// cutTopLevel cuts s around the first sep outside brackets
+func cutTopLevel(s string, sep rune) (string, string, bool) {
+	parts := parser.SplitTopLevel(s, sep)
+	if len(parts) < 2 {
+		return s, "", false
+	}
+	return parts[0], strings.Join(parts[1:], string(sep)), true
+}
+
+// CompatibilityReport renders the incompatibilities as Markdown, grouped by
+// severity, with a verdict against the failOn threshold
+func (d *Diff) CompatibilityReport(violations []rules.Violation, failOn string) string {
+	to := d.To
+	if to == "" {
+		to = "working tree"
+	}
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("# API Compatibility: %s → %s\n\n", d.From, to))
+
+	errors := rules.AtLeast(violations, rules.SeverityError)
+	warnings := rules.AtLeast(violations, rules.SeverityWarning) - errors
+	failing := rules.AtLeast(violations, failOn)
+	if failing > 0 {
+		out.WriteString(fmt.Sprintf("**Verdict:** ❌ incompatible (%d findings at or above %s)\n", failing, failOn))
+	} else {
+		out.WriteString("**Verdict:** ✅ compatible\n")
+	}
+	out.WriteString(fmt.Sprintf("**Findings:** %d errors, %d warnings, %d notes\n", errors, warnings, len(violations)-errors-warnings))
+
+	sections := []struct{ title, severity string }{
+		{"Errors", rules.SeverityError},
+		{"Warnings", rules.SeverityWarning},
+		{"Notes", rules.SeverityNote},
+	}
+	for _, section := range sections {
+		var lines []string
+		for _, v := range violations {
+			if v.Severity != section.severity {
+				continue
+			}
+			location := v.File
+			if v.Line > 0 {
+				location = fmt.Sprintf("%s:%d", v.File, v.Line)
+			}
+			lines = append(lines, fmt.Sprintf("- %s (`%s`, %s)", v.Message, location, v.Rule))
+		}
+		if len(lines) > 0 {
+			out.WriteString(fmt.Sprintf("\n## %s\n\n%s\n", section.title, strings.Join(lines, "\n")))
+		}
+	}
+	return out.String()
+}
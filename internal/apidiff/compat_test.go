@@ -0,0 +1,105 @@
+package apidiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIncompatibilities(t *testing.T) {
+	api := func(symbols ...Symbol) *API {
+		result := &API{Symbols: make(map[string]Symbol)}
+		for _, symbol := range symbols {
+			result.Symbols[symbol.key()] = symbol
+		}
+		return result
+	}
+	goFunc := func(name, signature string) Symbol {
+		return Symbol{Package: "store", Name: name, Kind: "function", Signature: signature, File: "store/store.go", Line: 3, Language: "go"}
+	}
+	tsFunc := func(name, signature string) Symbol {
+		return Symbol{Package: "web", Name: name, Kind: "function", Signature: signature, File: "web/api.ts", Line: 7, Language: "typescript"}
+	}
+	pyFunc := func(name, signature string) Symbol {
+		return Symbol{Package: "lib", Name: name, Kind: "function", Signature: signature, File: "lib/util.py", Line: 1, Language: "python"}
+	}
+
+	oldAPI := api(
+		goFunc("Open", "func Open(path string) *Store"),
+		goFunc("Store.Get", "func (s *Store) Get(key string) string"),
+		goFunc("Store.Put", "func (s *Store) Put(key, value string)"),
+		goFunc("Close", "func Close()"),
+		tsFunc("fetchUser", "(id: string | number)"),
+		tsFunc("render", "(node: Node): string"),
+		tsFunc("Config", "class Config"),
+		pyFunc("slug", "(name, sep='-')"),
+		pyFunc("parse", "(text: str)"),
+	)
+	config := tsFunc("Config", "interface Config")
+	config.Kind = "interface"
+	newAPI := api(
+		goFunc("Open", "func Open(path string, opts ...Option) *Store"),
+		goFunc("Store.Get", "func (s *Store) Get(key string) (string, error)"),
+		goFunc("Store.Put", "func (s *Store) Put(key, value string)"),
+		goFunc("Flush", "func Flush()"),
+		tsFunc("fetchUser", "(id: string)"),
+		tsFunc("render", "(node: Node, options?: Options): string"),
+		config,
+		pyFunc("slug", "(name, separator='-')"),
+		pyFunc("parse", "(text: Optional[str])"),
+	)
+
+	violations := Compare(oldAPI, newAPI).Incompatibilities()
+	messages := make(map[string]rules.Violation)
+	for _, v := range violations {
+		messages[v.Message] = v
+	}
+
+	expected := map[string]string{
+		"function store.Close was removed":                                        rules.SeverityError,
+		"store.Store.Get: result changed from string to (string, error)":          rules.SeverityError,
+		"store.Open: variadic parameter opts added (breaks function values)":      rules.SeverityWarning,
+		"web.fetchUser: parameter id narrowed from string | number to string":     rules.SeverityError,
+		"web.render: optional parameter options added":                            rules.SeverityNote,
+		"web.Config changed from function to interface":                           rules.SeverityError,
+		"lib.slug: parameter sep renamed to separator (breaks keyword arguments)": rules.SeverityWarning,
+		"lib.parse: parameter text widened from str to Optional[str]":             rules.SeverityNote,
+	}
+	for message, severity := range expected {
+		v, ok := messages[message]
+		if assert.True(t, ok, "missing %q in %v", message, violations) {
+			assert.Equal(t, severity, v.Severity, message)
+		}
+	}
+	assert.Len(t, violations, len(expected), "unchanged and added symbols are not reported")
+	assert.Equal(t, rules.SeverityError, violations[0].Severity, "errors come first")
+	assert.Equal(t, rules.SeverityNote, violations[len(violations)-1].Severity)
+
+	removed := messages["function store.Close was removed"]
+	assert.Equal(t, RuleRemoved, removed.Rule)
+	assert.Equal(t, "store/store.go", removed.File)
+}
+
+func TestCompatibilityReport(t *testing.T) {
+	diff := &Diff{From: "v1.0.0", To: "v2.0.0"}
+	violations := []rules.Violation{
+		{Rule: RuleRemoved, Severity: rules.SeverityError, Message: "function store.Close was removed", File: "store/store.go", Line: 9},
+		{Rule: RuleSignature, Severity: rules.SeverityNote, Message: "web.render: optional parameter options added", File: "web/api.ts", Line: 7},
+	}
+
+	report := diff.CompatibilityReport(violations, rules.SeverityError)
+	assert.Contains(t, report, "# API Compatibility: v1.0.0 → v2.0.0")
+	assert.Contains(t, report, "❌ incompatible (1 findings at or above error)")
+	assert.Contains(t, report, "**Findings:** 1 errors, 0 warnings, 1 notes")
+	assert.Contains(t, report, "- function store.Close was removed (`store/store.go:9`, api-removed)")
+	require.Contains(t, report, "## Notes")
+	assert.Less(t, strings.Index(report, "## Errors"), strings.Index(report, "## Notes"))
+
+	diff.To = ""
+	report = diff.CompatibilityReport(violations[1:], rules.SeverityError)
+	assert.Contains(t, report, "v1.0.0 → working tree")
+	assert.Contains(t, report, "✅ compatible")
+}
@@ -0,0 +1,103 @@
+package apidiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReleaseNotes renders the diff as a draft changelog: public symbol changes
+// grouped by package, then endpoint changes and file moves
+func (d *Diff) ReleaseNotes() string {
+	to := d.To
+	if to == "" {
+		to = "working tree"
+	}
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("# Release Notes Draft: %s → %s\n\n", d.From, to))
+	out.WriteString("_Generated from the public symbols at both revisions; review and reword before publishing._\n")
+
+	if len(d.Changes) == 0 && len(d.AddedEndpoints) == 0 && len(d.RemovedEndpoints) == 0 && len(d.Moves) == 0 {
+		out.WriteString("\n_No public API changes._\n")
+		return out.String()
+	}
+
+	sections := []struct {
+		title      string
+		changeType ChangeType
+	}{
+		{"Added", ChangeAdded},
+		{"Changed", ChangeChanged},
+		{"Moved", ChangeMoved},
+		{"Removed", ChangeRemoved},
+	}
+	for _, pkg := range d.Packages() {
+		out.WriteString(fmt.Sprintf("\n## `%s`\n", pkg))
+		for _, section := range sections {
+			var lines []string
+			for _, change := range d.Changes {
+				symbol := change.Symbol()
+				if symbol.Package != pkg || change.Type != section.changeType {
+					continue
+				}
+				line := fmt.Sprintf("- `%s` (%s)", symbol.Name, symbol.Kind)
+				switch change.Type {
+				case ChangeAdded:
+					if symbol.Signature != "" {
+						line += fmt.Sprintf(": `%s`", symbol.Signature)
+					}
+				case ChangeChanged:
+					if change.Old.Kind != change.New.Kind {
+						line = fmt.Sprintf("- `%s`: %s → %s", symbol.Name, change.Old.Kind, change.New.Kind)
+					} else {
+						line += fmt.Sprintf(": `%s` → `%s`", change.Old.Signature, change.New.Signature)
+					}
+				case ChangeMoved:
+					line += fmt.Sprintf(" from `%s`", change.Old.Package)
+				}
+				lines = append(lines, line)
+			}
+			if len(lines) > 0 {
+				out.WriteString(fmt.Sprintf("\n**%s**\n\n%s\n", section.title, strings.Join(lines, "\n")))
+			}
+		}
+	}
+
+	if len(d.AddedEndpoints) > 0 || len(d.RemovedEndpoints) > 0 {
+		out.WriteString("\n## Endpoints\n")
+		if len(d.AddedEndpoints) > 0 {
+			out.WriteString("\n**New**\n\n")
+			for _, endpoint := range d.AddedEndpoints {
+				out.WriteString(fmt.Sprintf("- `%s` (`%s:%d`)\n", endpoint, endpoint.File, endpoint.Line))
+			}
+		}
+		if len(d.RemovedEndpoints) > 0 {
+			out.WriteString("\n**Removed**\n\n")
+			for _, endpoint := range d.RemovedEndpoints {
+				out.WriteString(fmt.Sprintf("- `%s`\n", endpoint))
+			}
+		}
+	}
+
+	if len(d.Moves) > 0 {
+		out.WriteString("\n## File Moves\n\n")
+		for _, move := range d.Moves {
+			out.WriteString(fmt.Sprintf("- `%s` → `%s`", move.From, move.To))
+			if move.Similarity < 100 {
+				out.WriteString(fmt.Sprintf(" (%d%% similar)", move.Similarity))
+			}
+			out.WriteString("\n")
+		}
+	}
+	return out.String()
+}
+
+// Packages lists the packages with symbol changes, in order
+func (d *Diff) Packages() []string {
+	var packages []string
+	for _, change := range d.Changes {
+		if pkg := change.Symbol().Package; len(packages) == 0 || packages[len(packages)-1] != pkg {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
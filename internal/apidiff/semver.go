@@ -0,0 +1,330 @@
+package apidiff
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+)
+
+// Bump is a semantic version increment
+type Bump string
+
+const (
+	BumpNone  Bump = "none"
+	BumpPatch Bump = "patch"
+	BumpMinor Bump = "minor"
+	BumpMajor Bump = "major"
+)
+
+var bumpRank = map[Bump]int{BumpNone: 0, BumpPatch: 1, BumpMinor: 2, BumpMajor: 3}
+
+// manifestPatterns read the name and version of a module from its manifest
+var manifestPatterns = map[string][2]*regexp.Regexp{
+	"go.mod":         {regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)`), nil},
+	"package.json":   {regexp.MustCompile(`"name"\s*:\s*"([^"]+)"`), regexp.MustCompile(`"version"\s*:\s*"([^"]+)"`)},
+	"pubspec.yaml":   {regexp.MustCompile(`(?m)^name:\s*(\S+)`), regexp.MustCompile(`(?m)^version:\s*['"]?([^\s'"]+)`)},
+	"Cargo.toml":     {regexp.MustCompile(`(?m)^name\s*=\s*"([^"]+)"`), regexp.MustCompile(`(?m)^version\s*=\s*"([^"]+)"`)},
+	"pyproject.toml": {regexp.MustCompile(`(?m)^name\s*=\s*"([^"]+)"`), regexp.MustCompile(`(?m)^version\s*=\s*"([^"]+)"`)},
+	"setup.py":       {regexp.MustCompile(`name\s*=\s*['"]([^'"]+)`), regexp.MustCompile(`version\s*=\s*['"]([^'"]+)`)},
+}
+
+// versionPattern matches a semantic version, alone or at the end of a tag
+// such as "v1.2.0", "api/v1.2.0" or "api@1.2.0"
+var versionPattern = regexp.MustCompile(`(?:^|[/@-])v?(\d+)\.(\d+)\.(\d+)(?:[-+][0-9A-Za-z.-]+)?$`)
+
+// Module is a separately versioned unit of the project: a directory with a
+// manifest, or the project root
+type Module struct {
+	Dir     string `json:"dir"` // Relative to the project root, "." for the root
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"` // From the manifest, if it declares one
+}
+
+// VersionSuggestion is the suggested next version of a module
+type VersionSuggestion struct {
+	Module  Module   `json:"module"`
+	Current string   `json:"current,omitempty"` // Manifest version, else the version in the from ref
+	Bump    Bump     `json:"bump"`
+	Next    string   `json:"next,omitempty"`
+	Reasons []string `json:"reasons"` // Most significant first
+}
+
+// VersionReport holds the suggestions for the modules that changed
+type VersionReport struct {
+	From        string              `json:"from"`
+	To          string              `json:"to"` // Empty for the working tree
+	Suggestions []VersionSuggestion `json:"suggestions"`
+	Unchanged   int                 `json:"unchanged"` // Modules without changes
+}
+
+// SuggestVersions compares dir at two git refs, analyzed with analyze, and
+// suggests a semantic version bump for each module: major for incompatible API
+// changes and breaking commits, minor for API additions and feat commits, patch
+// for other changes. Modules still at 0.x bump the minor version for breaking
+// changes and the patch version otherwise. An empty to compares with the
+// working tree.
+func SuggestVersions(ctx context.Context, dir, from, to string, analyze analyzer.AnalyzeFunc) (*VersionReport, error) {
+	diff, err := CompareRefs(ctx, dir, from, to, analyze)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := git.NewGitAnalyzer(dir)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := repo.GetCommitsBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return Suggest(diff, commits, FindModules(dir)), nil
+}
+
+// Suggest combines the API diff and the conventional commits between two
+// revisions into a version suggestion per module. Commit files and symbol
+// files are relative to the project root.
+func Suggest(diff *Diff, commits []git.CommitInfo, modules []Module) *VersionReport {
+	suggestions := make(map[string]*VersionSuggestion)
+	for _, module := range modules {
+		suggestions[module.Dir] = &VersionSuggestion{Module: module, Bump: BumpNone}
+	}
+	if suggestions["."] == nil {
+		suggestions["."] = &VersionSuggestion{Module: Module{Dir: ".", Name: "."}, Bump: BumpNone}
+	}
+	raise := func(file string, bump Bump, reason string) {
+		suggestion := suggestions[moduleOf(file, suggestions)]
+		if bumpRank[bump] > bumpRank[suggestion.Bump] {
+			suggestion.Bump = bump
+		}
+		if bump != BumpNone && !containsReason(suggestion.Reasons, reason) {
+			suggestion.Reasons = append(suggestion.Reasons, reason)
+		}
+	}
+
+	for _, v := range diff.Incompatibilities() {
+		if v.Severity == rules.SeverityError {
+			raise(v.File, BumpMajor, "breaking: "+v.Message)
+		} else {
+			raise(v.File, BumpMinor, "changed: "+v.Message)
+		}
+	}
+	for _, change := range diff.Changes {
+		if change.Type == ChangeAdded {
+			raise(change.New.File, BumpMinor, fmt.Sprintf("added %s %s", change.New.Kind, qualifiedName(*change.New)))
+		}
+	}
+	for _, endpoint := range diff.AddedEndpoints {
+		raise(endpoint.File, BumpMinor, fmt.Sprintf("added endpoint %s", endpoint))
+	}
+
+	for _, commit := range commits {
+		header, _, _ := strings.Cut(commit.Message, "\n")
+		bump, label := BumpPatch, "commit"
+		if conventional, ok := git.ParseConventionalCommit(commit.Message); ok {
+			switch {
+			case conventional.Breaking:
+				bump, label = BumpMajor, "breaking commit"
+			case conventional.Type == "feat":
+				bump, label = BumpMinor, "feature"
+			case git.IsMaintenanceType(conventional.Type):
+				bump = BumpNone
+			case conventional.Type == "fix":
+				label = "fix"
+			}
+		}
+		touched := make(map[string]bool)
+		for _, file := range commit.Files {
+			if module := moduleOf(file, suggestions); !touched[module] {
+				touched[module] = true
+				raise(file, bump, fmt.Sprintf("%s %.7s: %s", label, commit.Hash, header))
+			}
+		}
+	}
+
+	report := &VersionReport{From: diff.From, To: diff.To}
+	for _, suggestion := range suggestions {
+		if suggestion.Bump == BumpNone {
+			report.Unchanged++
+			continue
+		}
+		suggestion.Current = suggestion.Module.Version
+		if suggestion.Current == "" && versionPattern.MatchString(diff.From) {
+			suggestion.Current = diff.From
+		}
+		suggestion.Next = nextVersion(suggestion.Current, suggestion.Bump)
+		sort.SliceStable(suggestion.Reasons, func(i, j int) bool {
+			return reasonRank(suggestion.Reasons[i]) > reasonRank(suggestion.Reasons[j])
+		})
+		report.Suggestions = append(report.Suggestions, *suggestion)
+	}
+	sort.Slice(report.Suggestions, func(i, j int) bool {
+		return report.Suggestions[i].Module.Dir < report.Suggestions[j].Module.Dir
+	})
+	return report
+}
+
+func containsReason(reasons []string, reason string) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// reasonRank orders reasons by the bump they imply
+func reasonRank(reason string) int {
+	switch {
+	case strings.HasPrefix(reason, "breaking"):
+		return 3
+	case strings.HasPrefix(reason, "added"), strings.HasPrefix(reason, "changed"), strings.HasPrefix(reason, "feature"):
+		return 2
+	}
+	return 1
+}
+
+// moduleOf returns the directory of the innermost module containing file
+func moduleOf(file string, modules map[string]*VersionSuggestion) string {
+	for dir := filepath.ToSlash(filepath.Dir(file)); ; dir = filepath.ToSlash(filepath.Dir(dir)) {
+		if modules[dir] != nil {
+			return dir
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return "."
+		}
+	}
+}
+
+// nextVersion applies a bump to a version, keeping a tag prefix such as "v"
+// or "api/v". Versions below 1.0.0 treat breaking changes as minor and other
+// changes as patch bumps.
+func nextVersion(current string, bump Bump) string {
+	m := versionPattern.FindStringSubmatchIndex(current)
+	if m == nil {
+		return ""
+	}
+	prefix := current[:m[2]]
+	major, _ := strconv.Atoi(current[m[2]:m[3]])
+	minor, _ := strconv.Atoi(current[m[4]:m[5]])
+	patch, _ := strconv.Atoi(current[m[6]:m[7]])
+	if major == 0 && bump != BumpPatch {
+		bump = map[Bump]Bump{BumpMajor: BumpMinor, BumpMinor: BumpPatch}[bump]
+	}
+	switch bump {
+	case BumpMajor:
+		major, minor, patch = major+1, 0, 0
+	case BumpMinor:
+		minor, patch = minor+1, 0
+	case BumpPatch:
+		patch++
+	}
+	return fmt.Sprintf("%s%d.%d.%d", prefix, major, minor, patch)
+}
+
+// FindModules lists the directories of dir holding a manifest (go.mod,
+// package.json, pubspec.yaml, Cargo.toml, pyproject.toml or setup.py), with
+// the name and version they declare, and the root itself. Dependency and
+// hidden directories are skipped.
+func FindModules(dir string) []Module {
+	var modules []Module
+	seen := make(map[string]bool)
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" || name == "third_party" || name == "build" || name == "dist") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		patterns, ok := manifestPatterns[name]
+		if !ok {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil || seen[filepath.ToSlash(rel)] {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		module := Module{Dir: filepath.ToSlash(rel), Name: filepath.Base(filepath.Join(dir, rel))}
+		if m := patterns[0].FindSubmatch(data); m != nil {
+			module.Name = string(m[1])
+		}
+		if patterns[1] != nil {
+			if m := patterns[1].FindSubmatch(data); m != nil {
+				module.Version = string(m[1])
+			}
+		}
+		seen[module.Dir] = true
+		modules = append(modules, module)
+		return nil
+	})
+	if !seen["."] {
+		// The project root is versioned as a whole when it has no manifest
+		name := dir
+		if abs, err := filepath.Abs(dir); err == nil {
+			name = filepath.Base(abs)
+		}
+		modules = append([]Module{{Dir: ".", Name: name}}, modules...)
+	}
+	return modules
+}
+
+// Markdown renders the suggestions as a table followed by the reasons for
+// each module
+func (r *VersionReport) Markdown() string {
+	to := r.To
+	if to == "" {
+		to = "working tree"
+	}
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("# Version Suggestions: %s → %s\n\n", r.From, to))
+	if len(r.Suggestions) == 0 {
+		out.WriteString("_No releasable changes._\n")
+		return out.String()
+	}
+
+	out.WriteString("| Module | Directory | Current | Bump | Next |\n")
+	out.WriteString("|--------|-----------|---------|------|------|\n")
+	for _, s := range r.Suggestions {
+		out.WriteString(fmt.Sprintf("| %s | `%s` | %s | **%s** | %s |\n",
+			s.Module.Name, s.Module.Dir, orDash(s.Current), s.Bump, orDash(s.Next)))
+	}
+	if r.Unchanged > 0 {
+		out.WriteString(fmt.Sprintf("\n_%d modules without changes._\n", r.Unchanged))
+	}
+
+	const maxReasons = 10
+	for _, s := range r.Suggestions {
+		out.WriteString(fmt.Sprintf("\n## %s (%s)\n\n", s.Module.Name, s.Bump))
+		for i, reason := range s.Reasons {
+			if i == maxReasons {
+				out.WriteString(fmt.Sprintf("- _…and %d more_\n", len(s.Reasons)-maxReasons))
+				break
+			}
+			out.WriteString("- " + reason + "\n")
+		}
+	}
+	return out.String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
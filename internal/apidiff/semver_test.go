@@ -0,0 +1,92 @@
+package apidiff
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		current string
+		bump    Bump
+		want    string
+	}{
+		{"1.2.3", BumpMajor, "2.0.0"},
+		{"v1.2.3", BumpMinor, "v1.3.0"},
+		{"api/v1.2.3", BumpPatch, "api/v1.2.4"},
+		{"web@1.2.3-rc.1", BumpMinor, "web@1.3.0"},
+		{"0.4.1", BumpMajor, "0.5.0"},
+		{"0.4.1", BumpMinor, "0.4.2"},
+		{"main", BumpMajor, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, nextVersion(tt.current, tt.bump), "%s %s", tt.current, tt.bump)
+	}
+}
+
+func TestFindModules(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                   "module example.com/shop\n\ngo 1.24\n",
+		"packages/ui/package.json": `{"name": "@shop/ui", "version": "2.1.0"}`,
+		"packages/ui/node_modules/x/package.json": `{"name": "x", "version": "9.9.9"}`,
+		"tools/gen/pyproject.toml":                "[project]\nname = \"shop-gen\"\nversion = \"0.3.0\"\n",
+	}
+	testutils.WriteTree(t, dir, files)
+
+	assert.Equal(t, []Module{
+		{Dir: ".", Name: "example.com/shop"},
+		{Dir: "packages/ui", Name: "@shop/ui", Version: "2.1.0"},
+		{Dir: "tools/gen", Name: "shop-gen", Version: "0.3.0"},
+	}, FindModules(dir))
+}
+
+func TestSuggest(t *testing.T) {
+	modules := []Module{
+		{Dir: ".", Name: "shop"},
+		{Dir: "packages/ui", Name: "@shop/ui", Version: "2.1.0"},
+		{Dir: "tools/gen", Name: "shop-gen", Version: "0.3.0"},
+		{Dir: "tools/lint", Name: "shop-lint", Version: "1.0.0"},
+	}
+	removed := Symbol{Package: "store", Name: "Close", Kind: "function", File: "store/store.go", Line: 9, Language: "go"}
+	added := Symbol{Package: "packages/ui/src", Name: "Button", Kind: "function", File: "packages/ui/src/button.ts", Line: 1, Language: "typescript"}
+	diff := &Diff{From: "v1.4.0", Changes: []Change{
+		{Type: ChangeRemoved, Old: &removed},
+		{Type: ChangeAdded, New: &added},
+	}}
+	commits := []git.CommitInfo{
+		{Hash: "aaaaaaaaaa", Message: "fix(gen): escape names", Files: []string{"tools/gen/main.py"}},
+		{Hash: "bbbbbbbbbb", Message: "docs: lint usage", Files: []string{"tools/lint/README.md"}},
+		{Hash: "cccccccccc", Message: "feat(ui)!: drop IE support", Files: []string{"packages/ui/src/button.ts", "packages/ui/package.json"}},
+	}
+
+	report := Suggest(diff, commits, modules)
+	require.Len(t, report.Suggestions, 3)
+	assert.Equal(t, 1, report.Unchanged, "docs-only changes need no release")
+
+	root := report.Suggestions[0]
+	assert.Equal(t, ".", root.Module.Dir)
+	assert.Equal(t, BumpMajor, root.Bump)
+	assert.Equal(t, "v1.4.0", root.Current, "modules without a manifest version use the from tag")
+	assert.Equal(t, "v2.0.0", root.Next)
+	assert.Equal(t, []string{"breaking: function store.Close was removed"}, root.Reasons)
+
+	ui := report.Suggestions[1]
+	assert.Equal(t, BumpMajor, ui.Bump)
+	assert.Equal(t, "3.0.0", ui.Next)
+	assert.Equal(t, []string{"breaking commit ccccccc: feat(ui)!: drop IE support", "added function packages/ui/src.Button"}, ui.Reasons)
+
+	gen := report.Suggestions[2]
+	assert.Equal(t, BumpPatch, gen.Bump)
+	assert.Equal(t, "0.3.1", gen.Next)
+
+	markdown := report.Markdown()
+	assert.Contains(t, markdown, "# Version Suggestions: v1.4.0 → working tree")
+	assert.Contains(t, markdown, "| @shop/ui | `packages/ui` | 2.1.0 | **major** | 3.0.0 |")
+	assert.Contains(t, markdown, "_1 modules without changes._")
+	assert.Contains(t, markdown, "## shop-gen (patch)\n\n- fix aaaaaaa: fix(gen): escape names")
+}
@@ -0,0 +1,175 @@
+// Package benchmark times the analysis pipeline on generated fixture
+// repositories and compares the timings of two builds to catch regressions.
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/benchmark/fixture"
+	"github.com/nuthan-ms/codecontext/internal/parser"
+)
+
+// Result is the timing of one benchmark, such as "analyze/small"
+type Result struct {
+	Name        string `json:"name"`
+	Files       int    `json:"files"`
+	Iterations  int    `json:"iterations"`
+	NsPerOp     int64  `json:"ns_per_op"` // Median over the iterations
+	BytesPerOp  uint64 `json:"bytes_per_op"`
+	AllocsPerOp uint64 `json:"allocs_per_op"`
+}
+
+// Report is the output of one benchmark run
+type Report struct {
+	Version   string    `json:"version"`
+	GoVersion string    `json:"go_version"`
+	Platform  string    `json:"platform"`
+	RanAt     time.Time `json:"ran_at"`
+	Results   []Result  `json:"results"`
+}
+
+// stage is one benchmarked part of the pipeline, run against a generated fixture
+type stage struct {
+	name string
+	run  func(dir string, paths []string) error
+}
+
+var stages = []stage{
+	{"analyze", analyzeFixture},
+	{"parse", parseFixture},
+	{"exclude", excludeFixture},
+}
+
+// Run benchmarks every stage on each fixture size. Each stage runs once to
+// warm up and then iterations times.
+func Run(sizes []fixture.Size, iterations int, version string) (*Report, error) {
+	if iterations < 1 {
+		iterations = 1
+	}
+	report := &Report{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		RanAt:     time.Now().UTC(),
+	}
+	for _, size := range sizes {
+		results, err := runSize(size, iterations)
+		if err != nil {
+			return nil, err
+		}
+		report.Results = append(report.Results, results...)
+	}
+	return report, nil
+}
+
+func runSize(size fixture.Size, iterations int) ([]Result, error) {
+	dir, err := os.MkdirTemp("", "codecontext-bench-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	count, err := fixture.Generate(dir, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s fixture: %w", size, err)
+	}
+	var paths []string
+	for path := range fixture.Files(size) {
+		paths = append(paths, filepath.Join(dir, filepath.FromSlash(path)))
+	}
+	sort.Strings(paths)
+
+	var results []Result
+	for _, st := range stages {
+		result, err := measure(st, dir, paths, iterations)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", st.name, size, err)
+		}
+		result.Name = st.name + "/" + string(size)
+		result.Files = count
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func measure(st stage, dir string, paths []string, iterations int) (Result, error) {
+	if err := st.run(dir, paths); err != nil {
+		return Result{}, err
+	}
+	durations := make([]time.Duration, 0, iterations)
+	var before, after runtime.MemStats
+	var bytes, allocs uint64
+	for i := 0; i < iterations; i++ {
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		if err := st.run(dir, paths); err != nil {
+			return Result{}, err
+		}
+		durations = append(durations, time.Since(start))
+		runtime.ReadMemStats(&after)
+		bytes += after.TotalAlloc - before.TotalAlloc
+		allocs += after.Mallocs - before.Mallocs
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	return Result{
+		Iterations:  iterations,
+		NsPerOp:     durations[len(durations)/2].Nanoseconds(),
+		BytesPerOp:  bytes / uint64(iterations),
+		AllocsPerOp: allocs / uint64(iterations),
+	}, nil
+}
+
+// analyzeFixture runs a cold AnalyzeDirectory
+func analyzeFixture(dir string, _ []string) error {
+	builder := analyzer.NewGraphBuilder()
+	graph, err := builder.AnalyzeDirectory(dir)
+	if err != nil {
+		return err
+	}
+	if len(graph.Files) == 0 {
+		return fmt.Errorf("no files analyzed")
+	}
+	return nil
+}
+
+// parseFixture parses every source file and extracts its symbols and imports
+func parseFixture(_ string, paths []string) error {
+	manager := parser.NewManager()
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".md") {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		ast, err := manager.Parse(string(content), path)
+		if err != nil {
+			return err
+		}
+		if _, err := manager.ExtractSymbols(ast); err != nil {
+			return err
+		}
+		if _, err := manager.ExtractImports(ast); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// excludeFixture matches every path against the default exclude patterns
+func excludeFixture(dir string, paths []string) error {
+	builder := analyzer.NewGraphBuilder()
+	for _, path := range paths {
+		builder.Excluded(path)
+		builder.Excluded(filepath.Join(dir, "node_modules", filepath.Base(path)))
+	}
+	return nil
+}
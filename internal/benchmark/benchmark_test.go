@@ -0,0 +1,50 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/benchmark/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the analysis pipeline")
+	}
+	report, err := Run([]fixture.Size{fixture.Small}, 1, "test")
+	require.NoError(t, err)
+	assert.Equal(t, "test", report.Version)
+
+	require.Len(t, report.Results, 3)
+	for i, name := range []string{"analyze/small", "parse/small", "exclude/small"} {
+		assert.Equal(t, name, report.Results[i].Name)
+		assert.Equal(t, 61, report.Results[i].Files)
+		assert.Positive(t, report.Results[i].NsPerOp)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := &Report{Version: "1.0.0", Results: []Result{
+		{Name: "analyze/small", NsPerOp: 1000, AllocsPerOp: 10},
+		{Name: "parse/small", NsPerOp: 1000},
+		{Name: "exclude/small", NsPerOp: 1000},
+	}}
+	head := &Report{Version: "1.1.0", Results: []Result{
+		{Name: "parse/small", NsPerOp: 1050},
+		{Name: "analyze/small", NsPerOp: 1200, AllocsPerOp: 12},
+		{Name: "analyze/medium", NsPerOp: 9000},
+	}}
+
+	comparisons := Compare(base, head, 0.1)
+	require.Len(t, comparisons, 2, "benchmarks missing from either report are skipped")
+	assert.Equal(t, "analyze/small", comparisons[0].Name)
+	assert.InDelta(t, 0.2, comparisons[0].Change, 1e-9)
+	assert.True(t, comparisons[0].Regression)
+	assert.False(t, comparisons[1].Regression, "5% is within the threshold")
+	assert.Equal(t, 1, Regressions(comparisons))
+
+	out := FormatComparisons(base, head, comparisons)
+	assert.Contains(t, out, "REGRESSION")
+	assert.Contains(t, out, "+20.0%")
+}
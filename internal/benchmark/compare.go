@@ -0,0 +1,76 @@
+package benchmark
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Comparison is the change of one benchmark between two reports
+type Comparison struct {
+	Name       string  `json:"name"`
+	BaseNs     int64   `json:"base_ns_per_op"`
+	HeadNs     int64   `json:"head_ns_per_op"`
+	Change     float64 `json:"change"` // Relative change of ns/op, 0.1 = 10% slower
+	BaseAllocs uint64  `json:"base_allocs_per_op"`
+	HeadAllocs uint64  `json:"head_allocs_per_op"`
+	Regression bool    `json:"regression"`
+}
+
+// Compare matches the benchmarks of two reports by name. A benchmark regressed
+// when its head time exceeds the base time by more than threshold (0.1 = 10%).
+// Benchmarks missing from either report are left out.
+func Compare(base, head *Report, threshold float64) []Comparison {
+	baseByName := make(map[string]Result, len(base.Results))
+	for _, result := range base.Results {
+		baseByName[result.Name] = result
+	}
+	var comparisons []Comparison
+	for _, h := range head.Results {
+		b, ok := baseByName[h.Name]
+		if !ok || b.NsPerOp == 0 {
+			continue
+		}
+		change := float64(h.NsPerOp-b.NsPerOp) / float64(b.NsPerOp)
+		comparisons = append(comparisons, Comparison{
+			Name:       h.Name,
+			BaseNs:     b.NsPerOp,
+			HeadNs:     h.NsPerOp,
+			Change:     change,
+			BaseAllocs: b.AllocsPerOp,
+			HeadAllocs: h.AllocsPerOp,
+			Regression: change > threshold,
+		})
+	}
+	sort.Slice(comparisons, func(i, j int) bool { return comparisons[i].Name < comparisons[j].Name })
+	return comparisons
+}
+
+// Regressions counts the comparisons that regressed
+func Regressions(comparisons []Comparison) int {
+	count := 0
+	for _, c := range comparisons {
+		if c.Regression {
+			count++
+		}
+	}
+	return count
+}
+
+// FormatComparisons renders comparisons as a text table
+func FormatComparisons(base, head *Report, comparisons []Comparison) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "base: %s (%s)\nhead: %s (%s)\n\n", base.Version, base.GoVersion, head.Version, head.GoVersion)
+	fmt.Fprintf(&b, "%-18s %12s %12s %9s %14s\n", "benchmark", "base", "head", "change", "allocs/op")
+	for _, c := range comparisons {
+		marker := ""
+		if c.Regression {
+			marker = "  REGRESSION"
+		}
+		fmt.Fprintf(&b, "%-18s %12s %12s %+8.1f%% %6d → %-6d%s\n", c.Name,
+			time.Duration(c.BaseNs).Round(time.Microsecond), time.Duration(c.HeadNs).Round(time.Microsecond),
+			c.Change*100, c.BaseAllocs, c.HeadAllocs, marker)
+	}
+	return b.String()
+}
@@ -0,0 +1,267 @@
+// Package fixture generates reproducible synthetic repositories for benchmarks.
+// The same size always produces the same files, so timings from different
+// builds are comparable.
+package fixture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Size names a fixture repository
+type Size string
+
+const (
+	// Small has 6 modules of 10 files
+	Small Size = "small"
+	// Medium has 24 modules of 25 files
+	Medium Size = "medium"
+)
+
+// Sizes lists the fixture sizes, smallest first
+var Sizes = []Size{Small, Medium}
+
+// shape returns the number of modules and files per module of a size
+func (s Size) shape() (modules, files int) {
+	if s == Medium {
+		return 24, 25
+	}
+	return 6, 10
+}
+
+// ParseSize validates a fixture size name
+func ParseSize(name string) (Size, error) {
+	for _, size := range Sizes {
+		if string(size) == name {
+			return size, nil
+		}
+	}
+	return "", fmt.Errorf("unknown fixture size %q (use small or medium)", name)
+}
+
+// Files returns the fixture's files keyed by slash-separated relative path.
+// Modules rotate between TypeScript, Go and Python; files import each other
+// within and across modules and define a class with methods and functions
+// that call each other.
+func Files(size Size) map[string]string {
+	modules, perModule := size.shape()
+	files := make(map[string]string, modules*perModule+1)
+	for m := 0; m < modules; m++ {
+		for f := 0; f < perModule; f++ {
+			var path, content string
+			switch m % 3 {
+			case 0:
+				path, content = typeScriptFile(m, f)
+			case 1:
+				path, content = goFile(m, f)
+			default:
+				path, content = pythonFile(m, f)
+			}
+			files[path] = content
+		}
+	}
+	files["README.md"] = readme(size, modules, perModule)
+	return files
+}
+
+// Generate writes the fixture into dir and returns the number of files written
+func Generate(dir string, size Size) (int, error) {
+	files := Files(size)
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(full, []byte(files[path]), 0644); err != nil {
+			return 0, err
+		}
+	}
+	return len(paths), nil
+}
+
+func readme(size Size, modules, perModule int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Benchmark fixture (%s)\n\n", size)
+	fmt.Fprintf(&b, "%d modules with %d files each.\n\n", modules, perModule)
+	for m := 0; m < modules; m++ {
+		fmt.Fprintf(&b, "- `%s`\n", moduleDir(m))
+	}
+	return b.String()
+}
+
+func moduleDir(m int) string {
+	return fmt.Sprintf("mod%02d", m)
+}
+
+func name(m, f int) string {
+	return fmt.Sprintf("Service%02d%02d", m, f)
+}
+
+// deps returns the files a file imports as module/file pairs: the two files
+// before it and one file of the previous module in the same language
+func deps(m, f int) [][2]int {
+	var out [][2]int
+	for _, d := range []int{f - 1, f - 2} {
+		if d >= 0 {
+			out = append(out, [2]int{m, d})
+		}
+	}
+	if m >= 3 {
+		out = append(out, [2]int{m - 3, f % 3})
+	}
+	return out
+}
+
+func typeScriptFile(m, f int) (string, string) {
+	var b strings.Builder
+	for _, d := range deps(m, f) {
+		prefix := "./"
+		if d[0] != m {
+			prefix = "../" + moduleDir(d[0]) + "/"
+		}
+		fmt.Fprintf(&b, "import { %s } from '%sservice%02d';\n", name(d[0], d[1]), prefix, d[1])
+	}
+	n := name(m, f)
+	fmt.Fprintf(&b, `
+export interface %[1]sOptions {
+  id: number;
+  label: string;
+  enabled?: boolean;
+}
+
+/** %[1]s coordinates records for module %[2]d. */
+export class %[1]s {
+  private readonly items: Map<number, string> = new Map();
+
+  constructor(private readonly options: %[1]sOptions) {}
+
+  add(id: number, value: string): void {
+    this.items.set(id, normalize%[1]s(value));
+  }
+
+  get(id: number): string | undefined {
+    return this.items.get(id);
+  }
+
+  size(): number {
+    return this.items.size;
+  }
+}
+
+export function normalize%[1]s(value: string): string {
+  return value.trim().toLowerCase();
+}
+
+export function create%[1]s(id: number): %[1]s {
+  const service = new %[1]s({ id, label: '%[1]s' });
+  service.add(id, 'initial');
+  return service;
+}
+`, n, m)
+	return fmt.Sprintf("%s/service%02d.ts", moduleDir(m), f), b.String()
+}
+
+func goFile(m, f int) (string, string) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\n", moduleDir(m))
+	var external []string
+	for _, d := range deps(m, f) {
+		if d[0] != m {
+			external = append(external, moduleDir(d[0]))
+		}
+	}
+	b.WriteString("import (\n\t\"fmt\"\n\t\"strings\"\n")
+	for _, pkg := range external {
+		fmt.Fprintf(&b, "\n\t\"example.com/fixture/%s\"\n", pkg)
+	}
+	b.WriteString(")\n")
+	n := name(m, f)
+	fmt.Fprintf(&b, `
+// %[1]s coordinates records for module %[2]d
+type %[1]s struct {
+	items map[int]string
+	label string
+}
+
+// New%[1]s creates an empty %[1]s
+func New%[1]s(label string) *%[1]s {
+	return &%[1]s{items: make(map[int]string), label: label}
+}
+
+// Add stores a normalized value
+func (s *%[1]s) Add(id int, value string) {
+	s.items[id] = Normalize%[1]s(value)
+}
+
+// Get returns a stored value
+func (s *%[1]s) Get(id int) (string, error) {
+	value, ok := s.items[id]
+	if !ok {
+		return "", fmt.Errorf("no item %%d", id)
+	}
+	return value, nil
+}
+
+// Normalize%[1]s trims and lowercases a value
+func Normalize%[1]s(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+`, n, m)
+	for _, d := range deps(m, f) {
+		pkg := ""
+		if d[0] != m {
+			pkg = moduleDir(d[0]) + "."
+		}
+		fmt.Fprintf(&b, "\nvar _ = %sNew%s\n", pkg, name(d[0], d[1]))
+	}
+	return fmt.Sprintf("%s/service%02d.go", moduleDir(m), f), b.String()
+}
+
+func pythonFile(m, f int) (string, string) {
+	var b strings.Builder
+	for _, d := range deps(m, f) {
+		if d[0] == m {
+			fmt.Fprintf(&b, "from .service%02d import %s\n", d[1], name(d[0], d[1]))
+		} else {
+			fmt.Fprintf(&b, "from %s.service%02d import %s\n", moduleDir(d[0]), d[1], name(d[0], d[1]))
+		}
+	}
+	n := name(m, f)
+	fmt.Fprintf(&b, `
+
+class %[1]s:
+    """Coordinates records for module %[2]d."""
+
+    def __init__(self, label):
+        self.label = label
+        self.items = {}
+
+    def add(self, key, value):
+        self.items[key] = normalize_%[3]s(value)
+
+    def get(self, key):
+        return self.items.get(key)
+
+    def size(self):
+        return len(self.items)
+
+
+def normalize_%[3]s(value):
+    return value.strip().lower()
+
+
+def create_%[3]s(key):
+    service = %[1]s("%[1]s")
+    service.add(key, "initial")
+    return service
+`, n, m, strings.ToLower(n))
+	return fmt.Sprintf("%s/service%02d.py", moduleDir(m), f), b.String()
+}
@@ -0,0 +1,42 @@
+package fixture
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiles(t *testing.T) {
+	small := Files(Small)
+	assert.Len(t, small, 61)
+	assert.Len(t, Files(Medium), 601)
+	assert.Equal(t, small, Files(Small), "fixtures are deterministic")
+
+	assert.Contains(t, small["mod00/service02.ts"], "from './service01'")
+	assert.Contains(t, small["mod03/service04.ts"], "from '../mod00/service01'")
+	assert.Contains(t, small["mod01/service00.go"], "package mod01")
+	assert.Contains(t, small["mod02/service01.py"], "from .service00 import Service0200")
+}
+
+func TestParseSize(t *testing.T) {
+	size, err := ParseSize("medium")
+	require.NoError(t, err)
+	assert.Equal(t, Medium, size)
+
+	_, err = ParseSize("huge")
+	assert.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	count, err := Generate(dir, Small)
+	require.NoError(t, err)
+	assert.Equal(t, 61, count)
+
+	content, err := os.ReadFile(filepath.Join(dir, "mod04", "service09.go"))
+	require.NoError(t, err)
+	assert.Equal(t, Files(Small)["mod04/service09.go"], string(content))
+}
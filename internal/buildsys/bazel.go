@@ -0,0 +1,254 @@
+package buildsys
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// bazelRulePattern matches a top-level rule invocation such as cc_library(
+var bazelRulePattern = regexp.MustCompile(`(?m)^([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+
+// bazelStringPattern matches single- or double-quoted Starlark string literals
+var bazelStringPattern = regexp.MustCompile(`"([^"\\]*(?:\\.[^"\\]*)*)"|'([^'\\]*(?:\\.[^'\\]*)*)'`)
+
+// bazelIdentifierPattern matches a keyword argument name
+var bazelIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// bazelNonTargetRules are top-level calls that never declare a buildable target
+var bazelNonTargetRules = map[string]bool{
+	"load": true, "package": true, "licenses": true, "exports_files": true,
+	"package_group": true, "workspace": true,
+}
+
+// parseBazelFile extracts rule targets from a BUILD or BUILD.bazel file
+func parseBazelFile(root, path string) ([]*Target, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	pkg := relativeTo(root, dir)
+	if pkg == "." {
+		pkg = ""
+	}
+	buildFile := relativeTo(root, path)
+	text := stripStarlarkComments(string(content))
+
+	var targets []*Target
+	for _, loc := range bazelRulePattern.FindAllStringSubmatchIndex(text, -1) {
+		rule := text[loc[2]:loc[3]]
+		if bazelNonTargetRules[rule] {
+			continue
+		}
+		body, ok := balancedStarlarkParens(text[loc[1]-1:])
+		if !ok {
+			continue
+		}
+
+		attrs := parseStarlarkAttrs(body)
+		name := firstString(attrs["name"])
+		if name == "" {
+			continue
+		}
+
+		var files, globs []string
+		for _, attr := range []string{"srcs", "hdrs", "textual_hdrs"} {
+			f, g := starlarkFileEntries(attrs[attr])
+			files = append(files, f...)
+			globs = append(globs, g...)
+		}
+		sources := expandSources(root, dir, append(files, globs...))
+
+		var deps []string
+		for _, attr := range []string{"deps", "implementation_deps", "runtime_deps", "embed"} {
+			for _, dep := range starlarkStrings(attrs[attr]) {
+				deps = append(deps, normalizeBazelLabel(dep, pkg))
+			}
+		}
+
+		targets = append(targets, &Target{
+			Name:         "//" + pkg + ":" + name,
+			Kind:         bazelRuleKind(rule),
+			System:       SystemBazel,
+			Rule:         rule,
+			BuildFile:    buildFile,
+			Sources:      sources,
+			Dependencies: deps,
+		})
+	}
+
+	return targets, nil
+}
+
+// bazelRuleKind infers the target kind from the rule name's suffix
+func bazelRuleKind(rule string) TargetKind {
+	switch {
+	case strings.HasSuffix(rule, "_test"):
+		return KindTest
+	case strings.HasSuffix(rule, "_binary"):
+		return KindExecutable
+	case strings.HasSuffix(rule, "_library"):
+		return KindLibrary
+	}
+	return KindOther
+}
+
+// normalizeBazelLabel expands relative labels to the //package:name form
+func normalizeBazelLabel(label, pkg string) string {
+	switch {
+	case strings.HasPrefix(label, "@"):
+		return label
+	case strings.HasPrefix(label, ":"):
+		return "//" + pkg + label
+	case strings.HasPrefix(label, "//"):
+		if !strings.Contains(label, ":") {
+			return label + ":" + filepath.Base(label)
+		}
+		return label
+	default:
+		return "//" + pkg + ":" + label
+	}
+}
+
+// stripStarlarkComments removes # comments outside of string literals
+func stripStarlarkComments(content string) string {
+	var sb strings.Builder
+	var quote rune
+	inComment := false
+	for _, r := range content {
+		switch {
+		case inComment:
+			if r == '\n' {
+				inComment = false
+				sb.WriteRune(r)
+			}
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			sb.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			sb.WriteRune(r)
+		case r == '#':
+			inComment = true
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// balancedStarlarkParens returns the text inside the parenthesized group that s starts with
+func balancedStarlarkParens(s string) (string, bool) {
+	depth := 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+			if depth == 0 {
+				return s[1:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseStarlarkAttrs splits call arguments into keyword -> raw value text
+func parseStarlarkAttrs(body string) map[string]string {
+	attrs := make(map[string]string)
+	depth := 0
+	var quote rune
+	start := 0
+	flush := func(end int) {
+		arg := strings.TrimSpace(body[start:end])
+		if eq := strings.Index(arg, "="); eq > 0 {
+			key := strings.TrimSpace(arg[:eq])
+			if bazelIdentifierPattern.MatchString(key) {
+				attrs[key] = strings.TrimSpace(arg[eq+1:])
+			}
+		}
+	}
+	for i, r := range body {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == ',' && depth == 0:
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(body))
+	return attrs
+}
+
+// starlarkStrings returns every string literal in a value expression
+func starlarkStrings(value string) []string {
+	var result []string
+	for _, match := range bazelStringPattern.FindAllStringSubmatch(value, -1) {
+		if match[1] != "" {
+			result = append(result, match[1])
+		} else if match[2] != "" {
+			result = append(result, match[2])
+		}
+	}
+	return result
+}
+
+// firstString returns the first string literal in a value expression
+func firstString(value string) string {
+	if values := starlarkStrings(value); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// starlarkFileEntries separates plain file names from glob() include patterns,
+// skipping labels that refer to other targets' outputs
+func starlarkFileEntries(value string) (files, globs []string) {
+	for {
+		idx := strings.Index(value, "glob(")
+		if idx == -1 {
+			break
+		}
+		body, ok := balancedStarlarkParens(value[idx+4:])
+		if !ok {
+			break
+		}
+		// Only the first positional list holds include patterns; exclude= is ignored
+		if open := strings.Index(body, "["); open != -1 {
+			if list, ok := balancedStarlarkParens(body[open:]); ok {
+				globs = append(globs, starlarkStrings(list)...)
+			}
+		}
+		value = value[:idx] + value[idx+4+len(body)+2:]
+	}
+
+	for _, entry := range starlarkStrings(value) {
+		if strings.HasPrefix(entry, ":") || strings.HasPrefix(entry, "//") || strings.HasPrefix(entry, "@") {
+			continue
+		}
+		files = append(files, entry)
+	}
+	return files, globs
+}
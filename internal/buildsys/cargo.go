@@ -0,0 +1,160 @@
+package buildsys
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// cargoManifest is the subset of Cargo.toml needed to enumerate targets
+type cargoManifest struct {
+	Package *struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	Lib *struct {
+		Name string `toml:"name"`
+		Path string `toml:"path"`
+	} `toml:"lib"`
+	Bin          []cargoTarget          `toml:"bin"`
+	Test         []cargoTarget          `toml:"test"`
+	Dependencies map[string]interface{} `toml:"dependencies"`
+	DevDeps      map[string]interface{} `toml:"dev-dependencies"`
+}
+
+// cargoTarget is a [[bin]] or [[test]] table
+type cargoTarget struct {
+	Name string `toml:"name"`
+	Path string `toml:"path"`
+}
+
+// parseCargoFile extracts library, binary and test targets from a Cargo.toml
+func parseCargoFile(root, path string) ([]*Target, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest cargoManifest
+	if err := toml.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	if manifest.Package == nil || manifest.Package.Name == "" {
+		return nil, nil // Virtual workspace manifests declare no targets of their own
+	}
+
+	dir := filepath.Dir(path)
+	buildFile := relativeTo(root, path)
+	crate := manifest.Package.Name
+	deps := cargoDependencyNames(manifest.Dependencies)
+
+	var targets []*Target
+	add := func(name string, kind TargetKind, rule string, sources []string, deps []string) {
+		targets = append(targets, &Target{
+			Name:         name,
+			Kind:         kind,
+			System:       SystemCargo,
+			Rule:         rule,
+			BuildFile:    buildFile,
+			Sources:      sources,
+			Dependencies: deps,
+		})
+	}
+
+	// Binaries declared explicitly or by convention (src/main.rs, src/bin/*.rs)
+	binPaths := make(map[string]bool)
+	bins := manifest.Bin
+	if len(bins) == 0 && FileExists(filepath.Join(dir, "src", "main.rs")) {
+		bins = append(bins, cargoTarget{Name: crate, Path: "src/main.rs"})
+	}
+	for _, bin := range bins {
+		binPath := bin.Path
+		if binPath == "" {
+			binPath = "src/bin/" + bin.Name + ".rs"
+		}
+		binPaths[filepath.Clean(filepath.Join(dir, filepath.FromSlash(binPath)))] = true
+	}
+	autoBins, _ := filepath.Glob(filepath.Join(dir, "src", "bin", "*.rs"))
+	for _, autoBin := range autoBins {
+		if !binPaths[autoBin] {
+			bins = append(bins, cargoTarget{Name: strings.TrimSuffix(filepath.Base(autoBin), ".rs"), Path: relativeTo(dir, autoBin)})
+			binPaths[autoBin] = true
+		}
+	}
+
+	// The library owns every module under src/ that is not a binary root
+	libPath := filepath.Join(dir, "src", "lib.rs")
+	libName := crate
+	if manifest.Lib != nil {
+		if manifest.Lib.Path != "" {
+			libPath = filepath.Join(dir, filepath.FromSlash(manifest.Lib.Path))
+		}
+		if manifest.Lib.Name != "" {
+			libName = manifest.Lib.Name
+		}
+	}
+	hasLib := FileExists(libPath)
+	if hasLib {
+		var sources []string
+		for _, file := range globFiles(filepath.Join(filepath.Dir(libPath), "**", "*.rs")) {
+			if !binPaths[file] && !strings.HasPrefix(file, filepath.Join(dir, "src", "bin")+string(filepath.Separator)) {
+				sources = append(sources, relativeTo(root, file))
+			}
+		}
+		add(libName, KindLibrary, "lib", sources, deps)
+	}
+
+	for _, bin := range bins {
+		binDeps := deps
+		if hasLib {
+			binDeps = append([]string{libName}, deps...)
+		}
+		add(bin.Name, KindExecutable, "bin", expandSources(root, dir, []string{bin.Path}), binDeps)
+	}
+
+	// Integration tests live in tests/*.rs unless declared explicitly
+	tests := manifest.Test
+	if len(tests) == 0 {
+		autoTests, _ := filepath.Glob(filepath.Join(dir, "tests", "*.rs"))
+		for _, autoTest := range autoTests {
+			tests = append(tests, cargoTarget{Name: strings.TrimSuffix(filepath.Base(autoTest), ".rs"), Path: relativeTo(dir, autoTest)})
+		}
+	}
+	for _, test := range tests {
+		testPath := test.Path
+		if testPath == "" {
+			testPath = "tests/" + test.Name + ".rs"
+		}
+		testDeps := append(cargoDependencyNames(manifest.DevDeps), deps...)
+		if hasLib {
+			testDeps = append([]string{libName}, testDeps...)
+		}
+		add(crate+"::"+test.Name, KindTest, "test", expandSources(root, dir, []string{testPath}), testDeps)
+	}
+
+	return targets, nil
+}
+
+// cargoDependencyNames returns the crate names of a dependency table, sorted
+func cargoDependencyNames(deps map[string]interface{}) []string {
+	names := make([]string, 0, len(deps))
+	for name, spec := range deps {
+		// `foo = { package = "real-name" }` renames the dependency
+		if table, ok := spec.(map[string]interface{}); ok {
+			if pkg, ok := table["package"].(string); ok && pkg != "" {
+				name = pkg
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FileExists reports whether path names an existing regular file
+func FileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
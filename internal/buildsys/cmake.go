@@ -0,0 +1,282 @@
+package buildsys
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// cmakeCommandPattern matches the start of a CMake command invocation
+var cmakeCommandPattern = regexp.MustCompile(`(?m)^[ \t]*([A-Za-z_][A-Za-z0-9_]*)[ \t]*\(`)
+
+// cmakeVariablePattern matches ${VAR} references
+var cmakeVariablePattern = regexp.MustCompile(`\$\{([A-Za-z0-9_]+)\}`)
+
+// cmakeLibraryKeywords are add_library options that are not source files
+var cmakeLibraryKeywords = map[string]bool{
+	"STATIC": true, "SHARED": true, "MODULE": true, "OBJECT": true,
+	"INTERFACE": true, "EXCLUDE_FROM_ALL": true, "GLOBAL": true,
+}
+
+// cmakeExecutableKeywords are add_executable options that are not source files
+var cmakeExecutableKeywords = map[string]bool{
+	"WIN32": true, "MACOSX_BUNDLE": true, "EXCLUDE_FROM_ALL": true,
+}
+
+// cmakeScopeKeywords separate argument groups in target_* commands
+var cmakeScopeKeywords = map[string]bool{
+	"PRIVATE": true, "PUBLIC": true, "INTERFACE": true,
+	"LINK_PRIVATE": true, "LINK_PUBLIC": true, "LINK_INTERFACE_LIBRARIES": true,
+}
+
+// cmakeCommand is a parsed command invocation
+type cmakeCommand struct {
+	name string
+	args []string
+}
+
+// parseCMakeFile extracts add_library/add_executable targets from a CMakeLists.txt
+func parseCMakeFile(root, path string) ([]*Target, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	buildFile := relativeTo(root, path)
+	variables := map[string][]string{
+		"CMAKE_CURRENT_SOURCE_DIR": {dir},
+		"CMAKE_CURRENT_LIST_DIR":   {dir},
+		"PROJECT_SOURCE_DIR":       {dir},
+		"CMAKE_SOURCE_DIR":         {root},
+	}
+
+	var targets []*Target
+	byName := make(map[string]*Target)
+
+	for _, cmd := range parseCMakeCommands(string(content)) {
+		args := expandCMakeArgs(cmd.args, variables)
+
+		switch cmd.name {
+		case "project":
+			if len(args) > 0 {
+				variables["PROJECT_NAME"] = []string{args[0]}
+			}
+
+		case "set":
+			if len(args) > 0 {
+				variables[args[0]] = trimCMakeSetOptions(args[1:])
+			}
+
+		case "list":
+			if len(args) > 2 && strings.EqualFold(args[0], "APPEND") {
+				variables[args[1]] = append(variables[args[1]], args[2:]...)
+			}
+
+		case "add_library", "add_executable":
+			if len(args) == 0 {
+				continue
+			}
+			keywords := cmakeLibraryKeywords
+			kind := KindLibrary
+			if cmd.name == "add_executable" {
+				keywords = cmakeExecutableKeywords
+				kind = KindExecutable
+			}
+
+			var sources []string
+			skip := false
+			for _, arg := range args[1:] {
+				if arg == "IMPORTED" || arg == "ALIAS" {
+					skip = true // Not built from sources in this project
+					break
+				}
+				if !keywords[arg] {
+					sources = append(sources, arg)
+				}
+			}
+			if skip {
+				continue
+			}
+
+			target := &Target{
+				Name:      args[0],
+				Kind:      kind,
+				System:    SystemCMake,
+				Rule:      cmd.name,
+				BuildFile: buildFile,
+				Sources:   expandSources(root, dir, cmakeSourceFiles(sources)),
+			}
+			targets = append(targets, target)
+			byName[target.Name] = target
+
+		case "target_sources":
+			if len(args) > 1 {
+				if target := byName[args[0]]; target != nil {
+					target.Sources = append(target.Sources, expandSources(root, dir, cmakeSourceFiles(withoutScopes(args[1:])))...)
+				}
+			}
+
+		case "target_link_libraries":
+			if len(args) > 1 {
+				if target := byName[args[0]]; target != nil {
+					target.Dependencies = append(target.Dependencies, withoutScopes(args[1:])...)
+				}
+			}
+
+		case "add_test":
+			// add_test(NAME name COMMAND exe ...) marks an executable target as a test
+			for i, arg := range args {
+				if arg == "COMMAND" && i+1 < len(args) {
+					if target := byName[args[i+1]]; target != nil {
+						target.Kind = KindTest
+					}
+				}
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// parseCMakeCommands splits CMake source into command invocations with their arguments
+func parseCMakeCommands(content string) []cmakeCommand {
+	content = stripCMakeComments(content)
+
+	var commands []cmakeCommand
+	for _, loc := range cmakeCommandPattern.FindAllStringSubmatchIndex(content, -1) {
+		name := strings.ToLower(content[loc[2]:loc[3]])
+		body, ok := balancedParens(content[loc[1]-1:])
+		if !ok {
+			continue
+		}
+		commands = append(commands, cmakeCommand{name: name, args: splitCMakeArgs(body)})
+	}
+	return commands
+}
+
+// stripCMakeComments removes # comments outside of quoted strings
+func stripCMakeComments(content string) string {
+	var sb strings.Builder
+	inQuote := false
+	inComment := false
+	for _, r := range content {
+		switch {
+		case inComment:
+			if r == '\n' {
+				inComment = false
+				sb.WriteRune(r)
+			}
+		case r == '"':
+			inQuote = !inQuote
+			sb.WriteRune(r)
+		case r == '#' && !inQuote:
+			inComment = true
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// balancedParens returns the text inside the parenthesized group that s starts with
+func balancedParens(s string) (string, bool) {
+	depth := 0
+	inQuote := false
+	for i, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 {
+				return s[1:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// splitCMakeArgs splits a command body on whitespace, honouring quoted arguments
+func splitCMakeArgs(body string) []string {
+	var args []string
+	var current strings.Builder
+	inQuote := false
+	flush := func() {
+		if current.Len() > 0 {
+			args = append(args, current.String())
+			current.Reset()
+		}
+	}
+	for _, r := range body {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+		case !inQuote && (r == ' ' || r == '\t' || r == '\n' || r == '\r'):
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return args
+}
+
+// expandCMakeArgs substitutes known variables; list variables expand to multiple arguments
+func expandCMakeArgs(args []string, variables map[string][]string) []string {
+	var expanded []string
+	for _, arg := range args {
+		if match := cmakeVariablePattern.FindStringSubmatch(arg); match != nil && match[0] == arg {
+			if values, ok := variables[match[1]]; ok {
+				expanded = append(expanded, values...)
+				continue
+			}
+		}
+		arg = cmakeVariablePattern.ReplaceAllStringFunc(arg, func(ref string) string {
+			name := cmakeVariablePattern.FindStringSubmatch(ref)[1]
+			if values, ok := variables[name]; ok {
+				return strings.Join(values, ";")
+			}
+			return ref
+		})
+		expanded = append(expanded, strings.Split(arg, ";")...)
+	}
+	return expanded
+}
+
+// trimCMakeSetOptions drops trailing CACHE/PARENT_SCOPE options from set() values
+func trimCMakeSetOptions(values []string) []string {
+	for i, value := range values {
+		if value == "CACHE" || value == "PARENT_SCOPE" {
+			return values[:i]
+		}
+	}
+	return values
+}
+
+// withoutScopes removes PRIVATE/PUBLIC/INTERFACE keywords from target_* arguments
+func withoutScopes(args []string) []string {
+	var result []string
+	for _, arg := range args {
+		if !cmakeScopeKeywords[arg] {
+			result = append(result, arg)
+		}
+	}
+	return result
+}
+
+// cmakeSourceFiles drops entries that are generator expressions or unresolved variables
+func cmakeSourceFiles(entries []string) []string {
+	var files []string
+	for _, entry := range entries {
+		if entry == "" || strings.Contains(entry, "${") || strings.Contains(entry, "$<") {
+			continue
+		}
+		files = append(files, entry)
+	}
+	return files
+}
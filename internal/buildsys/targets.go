@@ -0,0 +1,265 @@
+// Package buildsys extracts build targets from CMake, Bazel and Cargo build files
+// and maps source files to the targets that compile them.
+package buildsys
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/query"
+)
+
+// System identifies the build tool that declared a target
+type System string
+
+const (
+	SystemCMake System = "cmake"
+	SystemBazel System = "bazel"
+	SystemCargo System = "cargo"
+)
+
+// TargetKind classifies what a target produces
+type TargetKind string
+
+const (
+	KindLibrary    TargetKind = "library"
+	KindExecutable TargetKind = "executable"
+	KindTest       TargetKind = "test"
+	KindOther      TargetKind = "other"
+)
+
+// Target is a single buildable unit declared in a build file
+type Target struct {
+	Name         string     `json:"name"`
+	Kind         TargetKind `json:"kind"`
+	System       System     `json:"system"`
+	Rule         string     `json:"rule,omitempty"` // e.g. add_library, cc_binary, [[bin]]
+	BuildFile    string     `json:"build_file"`     // Relative to the scanned root
+	Sources      []string   `json:"sources"`        // Relative to the scanned root
+	Dependencies []string   `json:"dependencies"`   // Target names/labels this target links against
+}
+
+// Index holds all targets found under a root directory
+type Index struct {
+	Root    string
+	Targets []*Target
+
+	byFile map[string][]*Target
+	byName map[string]*Target
+}
+
+// skipDirs are never descended into when looking for build files
+var skipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "target": true,
+	"build": true, "out": true, "dist": true, ".dart_tool": true,
+	"bazel-bin": true, "bazel-out": true, "bazel-testlogs": true,
+}
+
+// Scan walks root and extracts targets from every recognized build file
+func Scan(root string) (*Index, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root %s: %w", root, err)
+	}
+
+	var targets []*Target
+	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Unreadable entries are skipped rather than aborting the scan
+		}
+		if info.IsDir() {
+			if path != absRoot && (skipDirs[info.Name()] || strings.HasPrefix(info.Name(), "bazel-")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var found []*Target
+		var parseErr error
+		switch info.Name() {
+		case "CMakeLists.txt":
+			found, parseErr = parseCMakeFile(absRoot, path)
+		case "BUILD", "BUILD.bazel":
+			found, parseErr = parseBazelFile(absRoot, path)
+		case "Cargo.toml":
+			found, parseErr = parseCargoFile(absRoot, path)
+		default:
+			return nil
+		}
+		if parseErr != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, parseErr)
+		}
+		targets = append(targets, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIndex(absRoot, targets), nil
+}
+
+// NewIndex builds lookup tables over a set of targets
+func NewIndex(root string, targets []*Target) *Index {
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].BuildFile != targets[j].BuildFile {
+			return targets[i].BuildFile < targets[j].BuildFile
+		}
+		return targets[i].Name < targets[j].Name
+	})
+
+	idx := &Index{
+		Root:    root,
+		Targets: targets,
+		byFile:  make(map[string][]*Target),
+		byName:  make(map[string]*Target),
+	}
+	for _, target := range targets {
+		// Cargo binaries share the crate name; dependencies on the name mean the library
+		if existing := idx.byName[target.Name]; existing == nil || existing.Kind != KindLibrary {
+			idx.byName[target.Name] = target
+		}
+		if target.System == SystemBazel {
+			// Bazel labels are also addressable by their short name within the package
+			if i := strings.LastIndex(target.Name, ":"); i != -1 {
+				if _, taken := idx.byName[target.Name[i+1:]]; !taken {
+					idx.byName[target.Name[i+1:]] = target
+				}
+			}
+		}
+		for _, source := range target.Sources {
+			idx.byFile[source] = append(idx.byFile[source], target)
+		}
+	}
+	return idx
+}
+
+// TargetsForFile returns the targets that compile the given file
+func (idx *Index) TargetsForFile(path string) []*Target {
+	return idx.byFile[idx.relative(path)]
+}
+
+// AffectedTargets returns every target whose sources include one of the changed files,
+// plus all targets that transitively depend on those targets
+func (idx *Index) AffectedTargets(changedFiles []string) []*Target {
+	dependents := make(map[*Target][]*Target)
+	for _, target := range idx.Targets {
+		for _, dep := range target.Dependencies {
+			if depTarget := idx.byName[dep]; depTarget != nil && depTarget != target {
+				dependents[depTarget] = append(dependents[depTarget], target)
+			}
+		}
+	}
+
+	affected := make(map[*Target]bool)
+	var queue []*Target
+	for _, file := range changedFiles {
+		for _, target := range idx.TargetsForFile(file) {
+			if !affected[target] {
+				affected[target] = true
+				queue = append(queue, target)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[current] {
+			if !affected[dependent] {
+				affected[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+
+	result := make([]*Target, 0, len(affected))
+	for _, target := range idx.Targets {
+		if affected[target] {
+			result = append(result, target)
+		}
+	}
+	return result
+}
+
+// relative converts a path to the slash-separated form used for target sources
+func (idx *Index) relative(path string) string {
+	if filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(idx.Root, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// relativeTo returns path relative to root in slash form
+func relativeTo(root, path string) string {
+	return filepath.ToSlash(query.RelativePath(path, root))
+}
+
+// expandSources resolves source entries relative to dir, expanding glob patterns
+func expandSources(root, dir string, entries []string) []string {
+	var sources []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		rel := relativeTo(root, path)
+		if !seen[rel] {
+			seen[rel] = true
+			sources = append(sources, rel)
+		}
+	}
+
+	for _, entry := range entries {
+		if entry == "" {
+			continue
+		}
+		path := entry
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, filepath.FromSlash(entry))
+		}
+		if strings.ContainsAny(entry, "*?[") {
+			for _, match := range globFiles(path) {
+				add(match)
+			}
+			continue
+		}
+		add(filepath.Clean(path))
+	}
+	return sources
+}
+
+// globFiles expands a pattern, supporting ** for recursive directory matching
+func globFiles(pattern string) []string {
+	if !strings.Contains(pattern, "**") {
+		matches, _ := filepath.Glob(pattern)
+		return matches
+	}
+
+	base := pattern[:strings.Index(pattern, "**")]
+	rest := strings.TrimPrefix(pattern[len(base)+2:], string(filepath.Separator))
+	restDepth := strings.Count(rest, string(filepath.Separator)) + 1
+
+	var matches []string
+	filepath.Walk(filepath.Clean(base), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if rest == "" {
+			matches = append(matches, path)
+			return nil
+		}
+		// Match the pattern tail against the same number of trailing path components
+		parts := strings.Split(path, string(filepath.Separator))
+		if len(parts) < restDepth {
+			return nil
+		}
+		tail := strings.Join(parts[len(parts)-restDepth:], string(filepath.Separator))
+		if ok, _ := filepath.Match(rest, tail); ok {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches
+}
@@ -0,0 +1,129 @@
+package buildsys
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func targetNames(targets []*Target) []string {
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+	return names
+}
+
+func TestScanCMake(t *testing.T) {
+	root := t.TempDir()
+	testutils.WriteTree(t, root, map[string]string{
+		"CMakeLists.txt": `project(engine)
+set(CORE_SOURCES
+    src/core.cpp # main implementation
+    src/util.cpp)
+add_library(core STATIC ${CORE_SOURCES})
+target_sources(core PRIVATE src/extra.cpp)
+add_executable(app src/main.cpp)
+target_link_libraries(app PRIVATE core)
+add_executable(core_tests tests/core_test.cpp)
+target_link_libraries(core_tests core)
+add_test(NAME core_tests COMMAND core_tests)
+add_library(fmt::fmt ALIAS fmt)
+`,
+		"src/core.cpp": "", "src/util.cpp": "", "src/extra.cpp": "", "src/main.cpp": "",
+		"tests/core_test.cpp": "",
+	})
+
+	idx, err := Scan(root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"core", "app", "core_tests"}, targetNames(idx.Targets))
+
+	core := idx.TargetsForFile("src/extra.cpp")
+	require.Len(t, core, 1)
+	assert.Equal(t, "core", core[0].Name)
+	assert.Equal(t, KindLibrary, core[0].Kind)
+	assert.ElementsMatch(t, []string{"src/core.cpp", "src/util.cpp", "src/extra.cpp"}, core[0].Sources)
+
+	tests := idx.TargetsForFile(filepath.Join(root, "tests", "core_test.cpp"))
+	require.Len(t, tests, 1)
+	assert.Equal(t, KindTest, tests[0].Kind)
+
+	affected := idx.AffectedTargets([]string{"src/util.cpp"})
+	assert.ElementsMatch(t, []string{"core", "app", "core_tests"}, targetNames(affected))
+
+	affected = idx.AffectedTargets([]string{"src/main.cpp"})
+	assert.Equal(t, []string{"app"}, targetNames(affected))
+}
+
+func TestScanBazel(t *testing.T) {
+	root := t.TempDir()
+	testutils.WriteTree(t, root, map[string]string{
+		"lib/BUILD.bazel": `load("@rules_cc//cc:defs.bzl", "cc_library")
+
+cc_library(
+    name = "net",
+    srcs = glob(["*.cc"], exclude = ["*_test.cc"]),
+    hdrs = ["net.h"],
+    deps = ["//base", ":gen"],
+)
+
+cc_test(
+    name = 'net_test',
+    srcs = ['net_test.cc'],
+    deps = [':net'],
+)
+`,
+		"base/BUILD": `cc_library(name = "base", srcs = ["base.cc"])`,
+		"lib/net.cc": "", "lib/net.h": "", "lib/net_test.cc": "", "base/base.cc": "",
+	})
+
+	idx, err := Scan(root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"//lib:net", "//lib:net_test", "//base:base"}, targetNames(idx.Targets))
+
+	net := idx.TargetsForFile("lib/net.h")
+	require.Len(t, net, 1)
+	assert.Equal(t, "cc_library", net[0].Rule)
+	assert.Contains(t, net[0].Dependencies, "//base:base")
+	assert.Contains(t, net[0].Dependencies, "//lib:gen")
+
+	affected := idx.AffectedTargets([]string{"base/base.cc"})
+	assert.ElementsMatch(t, []string{"//base:base", "//lib:net", "//lib:net_test"}, targetNames(affected))
+}
+
+func TestScanCargo(t *testing.T) {
+	root := t.TempDir()
+	testutils.WriteTree(t, root, map[string]string{
+		"Cargo.toml": `[package]
+name = "tool"
+version = "0.1.0"
+
+[dependencies]
+serde = "1"
+utils = { path = "utils", package = "tool-utils" }
+`,
+		"src/lib.rs": "", "src/parser/mod.rs": "", "src/main.rs": "", "src/bin/extra.rs": "",
+		"tests/cli.rs":     "",
+		"utils/Cargo.toml": "[package]\nname = \"tool-utils\"\n",
+		"utils/src/lib.rs": "",
+	})
+
+	idx, err := Scan(root)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"tool", "tool", "extra", "tool::cli", "tool-utils"}, targetNames(idx.Targets))
+
+	lib := idx.TargetsForFile("src/parser/mod.rs")
+	require.Len(t, lib, 1)
+	assert.Equal(t, KindLibrary, lib[0].Kind)
+	assert.Equal(t, []string{"serde", "tool-utils"}, lib[0].Dependencies)
+
+	main := idx.TargetsForFile("src/main.rs")
+	require.Len(t, main, 1)
+	assert.Equal(t, KindExecutable, main[0].Kind)
+
+	affected := idx.AffectedTargets([]string{"utils/src/lib.rs"})
+	assert.Contains(t, targetNames(affected), "tool::cli")
+}
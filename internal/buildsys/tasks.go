@@ -0,0 +1,364 @@
+package buildsys
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TaskRunner identifies where a task is defined
+type TaskRunner string
+
+const (
+	RunnerMake TaskRunner = "make"
+	RunnerNpm  TaskRunner = "npm"
+	RunnerJust TaskRunner = "just"
+)
+
+// Task categories inferred from task names
+const (
+	CategoryBuild  = "build"
+	CategoryTest   = "test"
+	CategoryLint   = "lint"
+	CategoryFormat = "format"
+	CategoryRun    = "run"
+	CategoryClean  = "clean"
+	CategoryDeploy = "deploy"
+	CategoryOther  = "other"
+)
+
+// maxTaskCommandLength caps the recipe text stored for a task
+const maxTaskCommandLength = 300
+
+// Task is a developer entry point such as a Makefile target or npm script
+type Task struct {
+	Name        string     `json:"name"`
+	Runner      TaskRunner `json:"runner"`
+	File        string     `json:"file"`                  // Relative to the scanned root
+	Invocation  string     `json:"invocation"`            // How to run it, e.g. "make test"
+	Command     string     `json:"command,omitempty"`     // Recipe or script body
+	Description string     `json:"description,omitempty"` // From ## or preceding comments
+	Category    string     `json:"category"`
+}
+
+// makeTargetPattern matches `target [target...]: [deps] [## description]` rule lines
+var makeTargetPattern = regexp.MustCompile(`^([A-Za-z0-9_./-][A-Za-z0-9_./ -]*?)\s*::?(?:[^=]|$)`)
+
+// justRecipePattern matches `[@]name [params]:` recipe headers
+var justRecipePattern = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)(\s[^:]*)?:(?:[^=]|$)`)
+
+// taskCategoryKeywords maps name fragments to categories, checked in order
+var taskCategoryKeywords = []struct {
+	category string
+	keywords []string
+}{
+	{CategoryTest, []string{"test", "spec", "e2e", "coverage", "bench"}},
+	{CategoryLint, []string{"lint", "vet", "check", "typecheck", "tsc"}},
+	{CategoryFormat, []string{"fmt", "format", "prettier"}},
+	{CategoryClean, []string{"clean"}},
+	{CategoryDeploy, []string{"deploy", "release", "publish"}},
+	{CategoryBuild, []string{"build", "compile", "install", "bundle", "dist"}},
+	{CategoryRun, []string{"run", "start", "dev", "serve", "watch"}},
+}
+
+// ScanTasks walks root and indexes Makefile targets, package.json scripts and justfile recipes
+func ScanTasks(root string) ([]*Task, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root %s: %w", root, err)
+	}
+
+	var tasks []*Task
+	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != absRoot && (skipDirs[info.Name()] || strings.HasPrefix(info.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var found []*Task
+		var parseErr error
+		switch info.Name() {
+		case "Makefile", "makefile", "GNUmakefile":
+			found, parseErr = parseMakefile(absRoot, path)
+		case "package.json":
+			found, parseErr = parsePackageScripts(absRoot, path)
+		case "justfile", "Justfile", ".justfile":
+			found, parseErr = parseJustfile(absRoot, path)
+		default:
+			return nil
+		}
+		if parseErr != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, parseErr)
+		}
+		tasks = append(tasks, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		// Root-level tasks first, then by file
+		di, dj := strings.Count(tasks[i].File, "/"), strings.Count(tasks[j].File, "/")
+		if di != dj {
+			return di < dj
+		}
+		return tasks[i].File < tasks[j].File
+	})
+	return tasks, nil
+}
+
+// parseMakefile extracts explicit targets and their recipes
+func parseMakefile(root, path string) ([]*Task, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := relativeTo(root, filepath.Dir(path))
+	invocation := "make"
+	if dir != "." {
+		invocation = "make -C " + dir
+	}
+
+	var tasks []*Task
+	var current []*Task
+	var recipe []string
+	comment := ""
+	flush := func() {
+		for _, task := range current {
+			task.Command = truncateCommand(strings.Join(recipe, "\n"))
+		}
+		current, recipe = nil, nil
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "\t") {
+			if current != nil {
+				recipe = append(recipe, strings.TrimSpace(line))
+			}
+			continue
+		}
+		flush()
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+
+		match := makeTargetPattern.FindStringSubmatch(line)
+		if match == nil {
+			comment = ""
+			continue
+		}
+
+		description := comment
+		if idx := strings.Index(line, "##"); idx != -1 {
+			description = strings.TrimSpace(line[idx+2:])
+		}
+		comment = ""
+
+		for _, name := range strings.Fields(match[1]) {
+			// Special targets (.PHONY), pattern rules and file outputs are not entry points
+			if strings.HasPrefix(name, ".") || strings.Contains(name, "%") || strings.Contains(name, "/") {
+				continue
+			}
+			task := &Task{
+				Name:        name,
+				Runner:      RunnerMake,
+				File:        relativeTo(root, path),
+				Invocation:  invocation + " " + name,
+				Description: description,
+				Category:    categorizeTask(name),
+			}
+			tasks = append(tasks, task)
+			current = append(current, task)
+		}
+	}
+	flush()
+
+	return tasks, nil
+}
+
+// parsePackageScripts extracts npm scripts, choosing the runner from the lockfile present
+func parsePackageScripts(root, path string) ([]*Task, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	runner := "npm run"
+	switch {
+	case FileExists(filepath.Join(dir, "pnpm-lock.yaml")):
+		runner = "pnpm run"
+	case FileExists(filepath.Join(dir, "yarn.lock")):
+		runner = "yarn"
+	case FileExists(filepath.Join(dir, "bun.lockb")):
+		runner = "bun run"
+	}
+	if rel := relativeTo(root, dir); rel != "." {
+		runner = "cd " + rel + " && " + runner
+	}
+
+	names := make([]string, 0, len(pkg.Scripts))
+	for name := range pkg.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tasks := make([]*Task, 0, len(names))
+	for _, name := range names {
+		// pre/post hooks run implicitly around their main script
+		if base, ok := strings.CutPrefix(name, "pre"); ok && pkg.Scripts[base] != "" {
+			continue
+		}
+		if base, ok := strings.CutPrefix(name, "post"); ok && pkg.Scripts[base] != "" {
+			continue
+		}
+		tasks = append(tasks, &Task{
+			Name:       name,
+			Runner:     RunnerNpm,
+			File:       relativeTo(root, path),
+			Invocation: runner + " " + name,
+			Command:    truncateCommand(pkg.Scripts[name]),
+			Category:   categorizeTask(name),
+		})
+	}
+	return tasks, nil
+}
+
+// parseJustfile extracts recipes and their bodies from a justfile
+func parseJustfile(root, path string) ([]*Task, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	invocation := "just"
+	if dir := relativeTo(root, filepath.Dir(path)); dir != "." {
+		invocation = "just --justfile " + relativeTo(root, path)
+	}
+
+	var tasks []*Task
+	var current *Task
+	var body []string
+	comment := ""
+	flush := func() {
+		if current != nil {
+			current.Command = truncateCommand(strings.Join(body, "\n"))
+		}
+		current, body = nil, nil
+	}
+
+	for _, line := range lines {
+		if current != nil && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			if trimmed := strings.TrimSpace(line); trimmed != "" {
+				body = append(body, trimmed)
+			}
+			continue
+		}
+		flush()
+
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			continue // Recipe attributes such as [private] keep the preceding comment
+		}
+		if strings.HasPrefix(trimmed, "set ") || strings.HasPrefix(trimmed, "alias ") || strings.HasPrefix(trimmed, "import ") {
+			comment = ""
+			continue
+		}
+
+		match := justRecipePattern.FindStringSubmatch(line)
+		if match == nil {
+			comment = ""
+			continue
+		}
+
+		current = &Task{
+			Name:        match[1],
+			Runner:      RunnerJust,
+			File:        relativeTo(root, path),
+			Invocation:  invocation + " " + match[1],
+			Description: comment,
+			Category:    categorizeTask(match[1]),
+		}
+		tasks = append(tasks, current)
+		comment = ""
+	}
+	flush()
+
+	return tasks, nil
+}
+
+// categorizeTask infers a task category from its name
+func categorizeTask(name string) string {
+	lower := strings.ToLower(name)
+	for _, entry := range taskCategoryKeywords {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(lower, keyword) {
+				return entry.category
+			}
+		}
+	}
+	return CategoryOther
+}
+
+// truncateCommand bounds the stored recipe text
+func truncateCommand(command string) string {
+	if len(command) > maxTaskCommandLength {
+		return command[:maxTaskCommandLength] + "..."
+	}
+	return command
+}
+
+// readLines reads a file as lines, joining backslash-continued lines
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	var pending string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if pending != "" {
+			line = pending + strings.TrimLeft(line, " \t")
+		}
+		if strings.HasSuffix(line, "\\") {
+			pending = strings.TrimRight(strings.TrimSuffix(line, "\\"), " \t") + " "
+			continue
+		}
+		pending = ""
+		lines = append(lines, line)
+	}
+	if pending != "" {
+		lines = append(lines, pending)
+	}
+	return lines, scanner.Err()
+}
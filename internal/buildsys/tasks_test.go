@@ -0,0 +1,82 @@
+package buildsys
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTasks(t *testing.T) {
+	root := t.TempDir()
+	testutils.WriteTree(t, root, map[string]string{
+		"Makefile": `GO := go
+VERSION ?= $(shell git describe)
+
+.PHONY: build test
+
+# Build the binary
+build: deps
+	$(GO) build -o bin/app \
+		./cmd/app
+
+test: ## Run unit tests
+	$(GO) test ./...
+
+bin/%.o: %.c
+	cc -c $<
+`,
+		"package.json": `{"scripts": {"pretest": "echo pre", "test": "jest", "lint": "eslint .", "dev": "vite"}}`,
+		"yarn.lock":    "",
+		"justfile": `set shell := ["bash", "-c"]
+version := "1.0"
+
+# Format all sources
+[private]
+fmt target="." :
+    gofmt -w {{target}}
+
+deploy env:
+    ./scripts/deploy.sh {{env}}
+`,
+		"web/package.json": `{"scripts": {"build": "next build"}}`,
+	})
+
+	tasks, err := ScanTasks(root)
+	require.NoError(t, err)
+
+	byInvocation := make(map[string]*Task)
+	for _, task := range tasks {
+		byInvocation[task.Invocation] = task
+	}
+
+	build := byInvocation["make build"]
+	require.NotNil(t, build)
+	assert.Equal(t, "Build the binary", build.Description)
+	assert.Equal(t, "$(GO) build -o bin/app ./cmd/app", build.Command)
+	assert.Equal(t, CategoryBuild, build.Category)
+
+	test := byInvocation["make test"]
+	require.NotNil(t, test)
+	assert.Equal(t, "Run unit tests", test.Description)
+	assert.Equal(t, CategoryTest, test.Category)
+
+	assert.NotContains(t, byInvocation, "make GO")
+	assert.NotContains(t, byInvocation, "make VERSION")
+	assert.NotContains(t, byInvocation, "make .PHONY")
+
+	require.Contains(t, byInvocation, "yarn test")
+	assert.Equal(t, "jest", byInvocation["yarn test"].Command)
+	assert.NotContains(t, byInvocation, "yarn pretest", "pre-hooks are folded into their script")
+	assert.Equal(t, CategoryLint, byInvocation["yarn lint"].Category)
+	assert.Contains(t, byInvocation, "cd web && npm run build")
+
+	fmtTask := byInvocation["just fmt"]
+	require.NotNil(t, fmtTask)
+	assert.Equal(t, "Format all sources", fmtTask.Description)
+	assert.Equal(t, "gofmt -w {{target}}", fmtTask.Command)
+	assert.Equal(t, CategoryDeploy, byInvocation["just deploy"].Category)
+	assert.NotContains(t, byInvocation, "just version")
+	assert.NotContains(t, byInvocation, "just set")
+}
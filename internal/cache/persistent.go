@@ -42,6 +42,7 @@ type CacheItem struct {
 	AccessCount int64                  `json:"access_count"`
 	Size        int64                  `json:"size"` // Estimated size in bytes
 	Hash        string                 `json:"hash"` // Content hash for validation
+	Summary     *Summary               `json:"summary,omitempty"`
 }
 
 // CacheMetrics tracks cache performance
@@ -75,8 +76,9 @@ func NewPersistentCache(config *Config) (*PersistentCache, error) {
 
 	// Load existing cache from disk
 	if err := cache.loadFromDisk(); err != nil {
-		// Log error but don't fail - start with empty cache
-		fmt.Printf("Warning: failed to load cache from disk: %v\n", err)
+		// Log error but don't fail - start with empty cache; stderr keeps
+		// stdout clean for the MCP server
+		fmt.Fprintf(os.Stderr, "Warning: failed to load cache from disk: %v\n", err)
 	}
 
 	// Start background cleanup if TTL is enabled
@@ -504,8 +506,8 @@ func (pc *PersistentCache) loadFromDisk() error {
 
 	// Load individual cache files
 	for key, item := range items {
-		// Only load graphs, not ASTs
-		if item.Graph != nil {
+		// Only load graphs and summaries, not ASTs
+		if item.Graph != nil || item.Summary != nil {
 			itemPath := pc.getCacheFilePath(key)
 			if err := pc.loadItemFromDisk(key, itemPath); err == nil {
 				pc.items[key] = item
@@ -520,8 +522,8 @@ func (pc *PersistentCache) loadFromDisk() error {
 }
 
 func (pc *PersistentCache) saveToDisk(key string, item *CacheItem) error {
-	// Only save graphs to disk, not ASTs
-	if item.Graph == nil {
+	// Only save graphs and summaries to disk, not ASTs
+	if item.Graph == nil && item.Summary == nil {
 		return nil
 	}
 
@@ -571,10 +573,10 @@ func (pc *PersistentCache) saveIndexToDisk() error {
 
 	encoder := gob.NewEncoder(file)
 
-	// Only save items that have graphs (not ASTs)
+	// Only save items that have graphs or summaries (not ASTs)
 	persistentItems := make(map[string]*CacheItem)
 	for key, item := range pc.items {
-		if item.Graph != nil {
+		if item.Graph != nil || item.Summary != nil {
 			persistentItems[key] = item
 		}
 	}
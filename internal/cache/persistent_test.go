@@ -470,6 +470,54 @@ func createTestGraph() *types.CodeGraph {
 
 // Benchmark tests
 
+func TestPersistentCache_Summaries(t *testing.T) {
+	tempDir := t.TempDir()
+
+	config := &Config{
+		Directory: tempDir,
+		MaxSize:   10,
+		EnableLRU: true,
+	}
+
+	cache, err := NewPersistentCache(config)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+
+	if cache.GetSummary("abc") != nil {
+		t.Error("Expected no summary before one is set")
+	}
+	summary := &Summary{Path: "main.go", Provider: "fake@1", Text: "Starts the server.", Generated: time.Now().UTC()}
+	if err := cache.SetSummary("abc", summary); err != nil {
+		t.Fatalf("Failed to set summary: %v", err)
+	}
+	if err := cache.SetSummary("def", &Summary{Path: "util.go", Text: "Helpers."}); err != nil {
+		t.Fatalf("Failed to set summary: %v", err)
+	}
+	cache.RemoveSummary("def")
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Failed to close cache: %v", err)
+	}
+
+	// Summaries survive a restart
+	cache, err = NewPersistentCache(config)
+	if err != nil {
+		t.Fatalf("Failed to reopen cache: %v", err)
+	}
+	defer cache.Close()
+
+	loaded := cache.GetSummary("abc")
+	if loaded == nil {
+		t.Fatal("Expected the summary to be loaded from disk")
+	}
+	if loaded.Path != "main.go" || loaded.Text != "Starts the server." || loaded.Provider != "fake@1" {
+		t.Errorf("Unexpected summary: %+v", loaded)
+	}
+	if summaries := cache.Summaries(); len(summaries) != 1 {
+		t.Errorf("Expected 1 summary, got %d", len(summaries))
+	}
+}
+
 func BenchmarkPersistentCache_SetGraph(b *testing.B) {
 	tempDir := b.TempDir()
 
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"strings"
+	"time"
+)
+
+// summaryPrefix starts the keys of cached summaries
+const summaryPrefix = "summary:"
+
+// Summary is a natural language summary of a file, cached by the hash of the
+// content it was written from. Summaries never expire: a file whose content
+// changes gets a new hash.
+type Summary struct {
+	Path      string    `json:"path"`     // File the summary was written for, relative to the project
+	Provider  string    `json:"provider"` // Provider, model and prompt version that wrote it
+	Text      string    `json:"text"`
+	Generated time.Time `json:"generated"`
+}
+
+// GetSummary retrieves the summary of a content hash
+func (pc *PersistentCache) GetSummary(hash string) *Summary {
+	key := summaryPrefix + hash
+
+	pc.mutex.Lock()
+	item, exists := pc.items[key]
+	if exists {
+		item.AccessedAt = time.Now()
+		item.AccessCount++
+		if pc.config.EnableLRU {
+			pc.access[key] = item.AccessedAt
+		}
+	}
+	pc.mutex.Unlock()
+
+	if !exists || item.Summary == nil {
+		pc.recordMiss()
+		return nil
+	}
+	pc.recordHit()
+	return item.Summary
+}
+
+// SetSummary stores the summary of a content hash
+func (pc *PersistentCache) SetSummary(hash string, summary *Summary) error {
+	key := summaryPrefix + hash
+	size := int64(len(summary.Text) + 200) // Estimate entry overhead
+
+	item := &CacheItem{
+		Key:         key,
+		Metadata:    make(map[string]interface{}),
+		CreatedAt:   time.Now(),
+		AccessedAt:  time.Now(),
+		AccessCount: 1,
+		Size:        size,
+		Hash:        hash,
+		Summary:     summary,
+	}
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	// Check if we need to evict items
+	if _, replaced := pc.items[key]; !replaced && len(pc.items) >= pc.config.MaxSize {
+		if err := pc.evictItems(); err != nil {
+			return err
+		}
+	}
+
+	pc.items[key] = item
+	if pc.config.EnableLRU {
+		pc.access[key] = time.Now()
+	}
+
+	pc.metrics.mutex.Lock()
+	pc.metrics.TotalSize += size
+	pc.metrics.mutex.Unlock()
+
+	return pc.saveToDisk(key, item)
+}
+
+// Summaries returns every cached summary by content hash
+func (pc *PersistentCache) Summaries() map[string]*Summary {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+
+	summaries := make(map[string]*Summary)
+	for key, item := range pc.items {
+		if hash, ok := strings.CutPrefix(key, summaryPrefix); ok && item.Summary != nil {
+			summaries[hash] = item.Summary
+		}
+	}
+	return summaries
+}
+
+// RemoveSummary drops the summary of a content hash
+func (pc *PersistentCache) RemoveSummary(hash string) {
+	key := summaryPrefix + hash
+
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	if item, exists := pc.items[key]; exists {
+		pc.metrics.mutex.Lock()
+		pc.metrics.TotalSize -= item.Size
+		pc.metrics.mutex.Unlock()
+	}
+	delete(pc.items, key)
+	delete(pc.access, key)
+	pc.removeFromDisk(key)
+}
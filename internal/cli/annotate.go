@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nuthan-ms/codecontext/internal/annotations"
+	"github.com/spf13/cobra"
+)
+
+var annotateCmd = &cobra.Command{
+	Use:   "annotate [file[#symbol]] [text]",
+	Short: "Attach notes to files and symbols",
+	Long: `Attach a note to a file or symbol. Notes are stored in .codecontext/annotations.json
+(commit it to share them) and returned by the get_file_analysis and get_symbol_info
+MCP tools.
+
+  codecontext annotate internal/db/pool.go "Connections are not released on panic"
+  codecontext annotate "internal/db/pool.go#Acquire" "Caller must Release" --tag gotcha
+  codecontext annotate --list [file[#symbol]]
+  codecontext annotate --remove <id>`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAnnotate(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(annotateCmd)
+	annotateCmd.Flags().StringP("target", "t", ".", "project directory")
+	annotateCmd.Flags().BoolP("list", "l", false, "list annotations, optionally for one file or symbol")
+	annotateCmd.Flags().String("remove", "", "remove the annotation with this id")
+	annotateCmd.Flags().String("author", "", "author of the note")
+	annotateCmd.Flags().StringSlice("tag", nil, "tag for the note (repeatable)")
+}
+
+func runAnnotate(cmd *cobra.Command, args []string) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	list, _ := cmd.Flags().GetBool("list")
+	removeID, _ := cmd.Flags().GetString("remove")
+
+	switch {
+	case removeID != "":
+		return annotations.Update(targetDir, func(store *annotations.Store) error {
+			removed, err := store.Remove(removeID)
+			if err == nil {
+				fmt.Printf("✅ Removed annotation %s from %s\n", removed.ID, removed.Target())
+			}
+			return err
+		})
+
+	case list:
+		store, err := annotations.Load(targetDir)
+		if err != nil {
+			return err
+		}
+		notes := store.Annotations
+		if len(args) > 0 {
+			file, symbol := annotations.ParseTarget(args[0])
+			if symbol != "" {
+				notes = store.ForSymbol(file, symbol)
+			} else {
+				notes = store.ForFile(file)
+			}
+		}
+		if len(notes) == 0 {
+			fmt.Println("No annotations.")
+			return nil
+		}
+		for _, note := range notes {
+			fmt.Printf("%s\n    %s (%s)\n", note.Target(), note.Text, note.Details())
+		}
+		return nil
+	}
+
+	if len(args) != 2 {
+		return fmt.Errorf("expected a target and the note text")
+	}
+	file, symbol := annotations.ParseTarget(args[0])
+	if _, err := os.Stat(filepath.Join(targetDir, filepath.FromSlash(file))); err != nil {
+		return fmt.Errorf("file not found: %s", file)
+	}
+	author, _ := cmd.Flags().GetString("author")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	return annotations.Update(targetDir, func(store *annotations.Store) error {
+		added, err := store.Add(annotations.Annotation{File: file, Symbol: symbol, Text: args[1], Author: author, Tags: tags})
+		if err == nil {
+			fmt.Printf("✅ Added annotation %s to %s\n", added.ID, added.Target())
+		}
+		return err
+	})
+}
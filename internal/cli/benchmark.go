@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/benchmark"
+	"github.com/nuthan-ms/codecontext/internal/benchmark/fixture"
+	"github.com/spf13/cobra"
+)
+
+var benchmarkCmd = &cobra.Command{
+	Use:   "benchmark",
+	Short: "Time the analysis pipeline on generated fixture repositories",
+	Long: `Generate reproducible synthetic repositories (small: 61 files, medium: 601
+files) and time a cold analysis, parsing and exclude pattern matching on them.
+
+  codecontext benchmark
+  codecontext benchmark --size small,medium --iterations 10 --json > bench.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBenchmark(cmd)
+	},
+}
+
+var benchmarkCompareCmd = &cobra.Command{
+	Use:   "compare <base> <head>",
+	Short: "Report performance regressions between two builds",
+	Long: `Compare two benchmark runs. Each argument is either a .json report written by
+"codecontext benchmark --json" or a codecontext binary, which is run with the
+same --size and --iterations.
+
+  codecontext benchmark compare ./codecontext-main ./codecontext
+  codecontext benchmark compare base.json head.json --threshold 15
+
+Exits with a non-zero status when a benchmark is slower than the base by more
+than --threshold percent.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBenchmarkCompare(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(benchmarkCmd)
+	benchmarkCmd.AddCommand(benchmarkCompareCmd)
+	benchmarkCmd.PersistentFlags().StringSlice("size", []string{string(fixture.Small)}, "fixture sizes to run (small, medium)")
+	benchmarkCmd.PersistentFlags().Int("iterations", 5, "timed runs per benchmark; the median is reported")
+	benchmarkCmd.Flags().Bool("json", false, "print the report as JSON")
+	benchmarkCompareCmd.Flags().Float64("threshold", 10, "slowdown in percent that counts as a regression")
+}
+
+func benchmarkSizes(cmd *cobra.Command) ([]fixture.Size, error) {
+	names, _ := cmd.Flags().GetStringSlice("size")
+	var sizes []fixture.Size
+	for _, name := range names {
+		size, err := fixture.ParseSize(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+func runBenchmark(cmd *cobra.Command) error {
+	sizes, err := benchmarkSizes(cmd)
+	if err != nil {
+		return err
+	}
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	report, err := benchmark.Run(sizes, iterations, appVersion)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("codecontext %s, %s, %s\n\n", report.Version, report.GoVersion, report.Platform)
+	fmt.Printf("%-18s %6s %12s %14s %12s\n", "benchmark", "files", "time/op", "bytes/op", "allocs/op")
+	for _, r := range report.Results {
+		fmt.Printf("%-18s %6d %12s %14d %12d\n", r.Name, r.Files, formatNs(r.NsPerOp), r.BytesPerOp, r.AllocsPerOp)
+	}
+	return nil
+}
+
+func runBenchmarkCompare(cmd *cobra.Command, args []string) error {
+	sizes, err := benchmarkSizes(cmd)
+	if err != nil {
+		return err
+	}
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+
+	var reports [2]*benchmark.Report
+	for i, arg := range args {
+		report, err := loadBenchmarkReport(arg, sizes, iterations)
+		if err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		reports[i] = report
+	}
+
+	comparisons := benchmark.Compare(reports[0], reports[1], threshold/100)
+	if len(comparisons) == 0 {
+		return fmt.Errorf("the reports have no benchmarks in common")
+	}
+	fmt.Print(benchmark.FormatComparisons(reports[0], reports[1], comparisons))
+	if n := benchmark.Regressions(comparisons); n > 0 {
+		// Regressions are the expected failure mode, not a usage error
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d benchmarks regressed by more than %.0f%%", n, threshold)
+	}
+	fmt.Printf("\n✅ No regressions above %.0f%%\n", threshold)
+	return nil
+}
+
+// loadBenchmarkReport reads a JSON report, or runs a codecontext binary to produce one
+func loadBenchmarkReport(arg string, sizes []fixture.Size, iterations int) (*benchmark.Report, error) {
+	var data []byte
+	var err error
+	if strings.HasSuffix(arg, ".json") {
+		if data, err = os.ReadFile(arg); err != nil {
+			return nil, err
+		}
+	} else {
+		names := make([]string, len(sizes))
+		for i, size := range sizes {
+			names[i] = string(size)
+		}
+		fmt.Fprintf(os.Stderr, "⏱️  Running %s benchmark...\n", arg)
+		command := exec.Command(arg, "benchmark", "--json",
+			"--size", strings.Join(names, ","), "--iterations", strconv.Itoa(iterations))
+		command.Stderr = os.Stderr
+		if data, err = command.Output(); err != nil {
+			return nil, fmt.Errorf("benchmark run failed: %w", err)
+		}
+	}
+	var report benchmark.Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("invalid benchmark report: %w", err)
+	}
+	return &report, nil
+}
+
+func formatNs(ns int64) string {
+	switch {
+	case ns >= 1e9:
+		return fmt.Sprintf("%.2fs", float64(ns)/1e9)
+	case ns >= 1e6:
+		return fmt.Sprintf("%.1fms", float64(ns)/1e6)
+	default:
+		return fmt.Sprintf("%.1fµs", float64(ns)/1e3)
+	}
+}
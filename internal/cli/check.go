@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the graph rules from the config",
+	Long: `Check the rules declared under "rules" in .codecontext/config.yaml: naming
+conventions, forbidden imports, maximum fan-out, required test files and graph
+query assertions.
+
+  codecontext check
+  codecontext check --format sarif --output codecontext.sarif
+  codecontext check --rule no-db-in-handlers --fail-on warning
+
+Exits with a non-zero status when a violation reaches the --fail-on severity
+(error by default) or a rule is invalid.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCheck(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().StringP("target", "t", ".", "target directory to analyze")
+	checkCmd.Flags().StringP("format", "f", "text", "output format (text, json, sarif)")
+	checkCmd.Flags().StringP("output", "o", "", "write the report to a file instead of stdout")
+	checkCmd.Flags().StringArray("rule", nil, "only check the rule with this id (repeatable)")
+	checkCmd.Flags().String("fail-on", rules.SeverityError, "lowest severity that fails the check (error, warning, note)")
+}
+
+func runCheck(cmd *cobra.Command) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	format, _ := cmd.Flags().GetString("format")
+	outputFile, _ := cmd.Flags().GetString("output")
+	only, _ := cmd.Flags().GetStringArray("rule")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if !rules.ValidSeverity(failOn) {
+		return fmt.Errorf("invalid --fail-on severity %q", failOn)
+	}
+	if format != "text" && format != "json" && format != "sarif" {
+		return fmt.Errorf("unknown format %q (use text, json or sarif)", format)
+	}
+
+	var configs []rules.Config
+	if err := viper.UnmarshalKey("rules", &configs); err != nil {
+		return fmt.Errorf("invalid rules config: %w", err)
+	}
+	// Invalid rules fail the check rather than passing it silently
+	loaded, errs := rules.Load(configs)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		}
+		return fmt.Errorf("%d invalid rules", len(errs))
+	}
+	if len(only) > 0 {
+		var selected []*rules.Rule
+		for _, id := range only {
+			found := false
+			for _, rule := range loaded {
+				if rule.ID() == id {
+					selected = append(selected, rule)
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("unknown rule %q", id)
+			}
+		}
+		loaded = selected
+	}
+	if len(loaded) == 0 {
+		fmt.Fprintln(os.Stderr, "No rules configured. Add them under \"rules\" in .codecontext/config.yaml.")
+		return nil
+	}
+
+	builder := analyzer.NewGraphBuilder()
+	if err := configureGraphBuilder(builder, targetDir); err != nil {
+		return err
+	}
+	graph, err := builder.AnalyzeDirectory(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to analyze directory: %w", err)
+	}
+	violations := rules.Check(graph, targetDir, loaded)
+
+	var output []byte
+	switch format {
+	case "sarif":
+		output, err = rules.SARIF(violations, loaded, appVersion)
+		output = append(output, '\n')
+	case "json":
+		if violations == nil {
+			violations = []rules.Violation{}
+		}
+		output, err = json.MarshalIndent(violations, "", "  ")
+		output = append(output, '\n')
+	default:
+		output = []byte(rules.Text(violations))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format violations: %w", err)
+	}
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, output, 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	} else {
+		os.Stdout.Write(output)
+	}
+
+	failing := rules.AtLeast(violations, failOn)
+	fmt.Fprintf(os.Stderr, "%d rules checked, %d violations (%d at or above %s)\n", len(loaded), len(violations), failing, failOn)
+	if failing > 0 {
+		// Violations are the expected failure mode, not a usage error
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d rule violations", failing)
+	}
+	return nil
+}
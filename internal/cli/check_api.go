@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/apidiff"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+var checkAPICmd = &cobra.Command{
+	Use:   "check-api <from> [to]",
+	Short: "Check that the public API stays compatible between two git refs",
+	Long: `Compare the public symbols and endpoints of the project at two git refs and
+report the changes that can break users: removed or moved exports, kind
+changes, required parameters added, parameters removed, narrowed or changed
+parameter types, changed results and removed endpoints. Without a second ref
+the working tree is compared.
+
+  codecontext check-api v1.2.0
+  codecontext check-api v1.2.0 HEAD --format sarif --output api.sarif
+  codecontext check-api main --fail-on warning
+
+Findings are errors (break callers), warnings (may break some callers) or
+notes (compatible but visible). Exits with a non-zero status when a finding
+reaches the --fail-on severity (error by default).`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCheckAPI(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(checkAPICmd)
+	checkAPICmd.Flags().StringP("target", "t", ".", "project directory")
+	checkAPICmd.Flags().StringP("format", "f", "text", "output format (text, markdown, json, sarif)")
+	checkAPICmd.Flags().StringP("output", "o", "", "write the report to a file instead of stdout")
+	checkAPICmd.Flags().String("fail-on", rules.SeverityError, "lowest severity that fails the check (error, warning, note)")
+}
+
+func runCheckAPI(cmd *cobra.Command, args []string) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	format, _ := cmd.Flags().GetString("format")
+	outputFile, _ := cmd.Flags().GetString("output")
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if !rules.ValidSeverity(failOn) {
+		return fmt.Errorf("invalid --fail-on severity %q", failOn)
+	}
+	if format != "text" && format != "markdown" && format != "json" && format != "sarif" {
+		return fmt.Errorf("unknown format %q (use text, markdown, json or sarif)", format)
+	}
+	to := ""
+	if len(args) == 2 {
+		to = args[1]
+	}
+
+	diff, err := apidiff.CompareRefs(context.Background(), targetDir, args[0], to, analyzer.AnalyzeDirectory)
+	if err != nil {
+		return err
+	}
+	violations := diff.Incompatibilities()
+
+	var output []byte
+	switch format {
+	case "sarif":
+		output, err = rules.SARIF(violations, apidiff.CompatibilityRules, appVersion)
+		output = append(output, '\n')
+	case "json":
+		if violations == nil {
+			violations = []rules.Violation{}
+		}
+		output, err = json.MarshalIndent(violations, "", "  ")
+		output = append(output, '\n')
+	case "markdown":
+		output = []byte(diff.CompatibilityReport(violations, failOn))
+	default:
+		output = []byte(rules.Text(violations))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format findings: %w", err)
+	}
+	if outputFile != "" {
+		if err := os.WriteFile(outputFile, output, 0644); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	} else {
+		os.Stdout.Write(output)
+	}
+
+	failing := rules.AtLeast(violations, failOn)
+	fmt.Fprintf(os.Stderr, "%d API changes checked, %d findings (%d at or above %s)\n", len(diff.Changes)+len(diff.RemovedEndpoints)+len(diff.AddedEndpoints), len(violations), failing, failOn)
+	if failing > 0 {
+		// Breaking changes are the expected failure mode, not a usage error
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d incompatible API changes", failing)
+	}
+	return nil
+}
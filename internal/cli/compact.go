@@ -119,11 +119,10 @@ func executeCompaction(cmd *cobra.Command) error {
 	} else {
 		// Generate and write compacted context map
 		generator := analyzer.NewMarkdownGenerator(result.CompactedGraph)
-		compactedContent := generator.GenerateContextMap()
 		
 		// Write to output file
 		outputFile := inputFile
-		if err := os.WriteFile(outputFile, []byte(compactedContent), 0644); err != nil {
+		if err := writeOutputFile(outputFile, nil, generator.WriteContextMap); err != nil {
 			return fmt.Errorf("failed to write compacted context map: %w", err)
 		}
 		
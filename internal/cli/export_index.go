@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var exportIndexCmd = &cobra.Command{
+	Use:   "export-index",
+	Short: "Export a SCIP or LSIF code navigation index",
+	Long: `Export the definitions of every supported language as a SCIP index
+(index.scip) or LSIF dump (dump.lsif), for code navigation in Sourcegraph and
+other tools that read these formats. Definitions carry their declaration and
+doc comments, and types list the types they extend or implement. References
+are not exported yet.
+
+  codecontext export-index
+  codecontext export-index --format lsif
+  src code-intel upload -file=index.scip`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExportIndex(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportIndexCmd)
+	exportIndexCmd.Flags().StringP("target", "t", ".", "target directory to analyze")
+	exportIndexCmd.Flags().StringP("format", "f", "scip", "index format (scip, lsif)")
+	exportIndexCmd.Flags().StringP("output", "o", "", "index file (default index.scip, or dump.lsif for lsif)")
+}
+
+func runExportIndex(cmd *cobra.Command) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	format, _ := cmd.Flags().GetString("format")
+	outputFile, _ := cmd.Flags().GetString("output")
+	if format != "scip" && format != "lsif" {
+		return fmt.Errorf("unknown index format %q (use scip or lsif)", format)
+	}
+	if outputFile == "" {
+		outputFile = "index.scip"
+		if format == "lsif" {
+			outputFile = "dump.lsif"
+		}
+	}
+
+	builder := analyzer.NewGraphBuilder()
+	if err := configureGraphBuilder(builder, targetDir); err != nil {
+		return err
+	}
+	graph, err := builder.AnalyzeDirectory(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to analyze directory: %w", err)
+	}
+
+	exporter := analyzer.NewIndexExporter(graph, targetDir)
+	exporter.SetVersion(appVersion)
+	exporter.SetRedactor(builder.Redactor())
+	// The exporter masks secrets itself; masking the protobuf stream would
+	// break its length prefixes
+	err = writeOutputFile(outputFile, nil, func(w io.Writer) error {
+		if format == "lsif" {
+			return exporter.WriteLSIF(w)
+		}
+		return exporter.WriteSCIP(w)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	fmt.Printf("✅ %s index written to %s\n", map[string]string{"scip": "SCIP", "lsif": "LSIF"}[format], outputFile)
+	return nil
+}
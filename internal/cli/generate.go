@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,12 @@ import (
 
 	"github.com/nuthan-ms/codecontext/internal/analyzer"
 	"github.com/nuthan-ms/codecontext/internal/cache"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/internal/summarize"
+	"github.com/nuthan-ms/codecontext/internal/trends"
+	"github.com/nuthan-ms/codecontext/pkg/plugin"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -29,6 +36,13 @@ func init() {
 	generateCmd.Flags().StringP("target", "t", ".", "target directory to analyze")
 	generateCmd.Flags().BoolP("watch", "w", false, "enable watch mode for continuous updates")
 	generateCmd.Flags().StringP("format", "f", "markdown", "output format (markdown, json, yaml)")
+	generateCmd.Flags().Bool("onboarding", false, "generate a concise onboarding guide (ONBOARDING.md) instead of the full context map")
+	generateCmd.Flags().Bool("chunks", false, "export source files split along symbol boundaries as JSONL retrieval chunks (chunks.jsonl) instead of the context map")
+	generateCmd.Flags().String("profile", "", "analysis profile: fast, balanced or deep (default from config, else balanced)")
+	generateCmd.Flags().StringSlice("coverage", nil, "coverage report to attach (Go coverprofile, lcov or Cobertura; repeatable, default from config)")
+	generateCmd.Flags().StringSlice("mutation", nil, "mutation testing report to attach (Stryker JSON or go-mutesting output; repeatable, default from config)")
+	generateCmd.Flags().StringSlice("index", nil, "SCIP or LSIF index whose cross-references are merged into the graph (repeatable, default from config)")
+	generateCmd.Flags().Bool("trends", false, "record a metrics snapshot in .codecontext/trends.json for \"codecontext trends\" (default from config)")
 
 	// Bind flags to viper with error handling
 	if err := viper.BindPFlag("target", generateCmd.Flags().Lookup("target")); err != nil {
@@ -40,6 +54,21 @@ func init() {
 	if err := viper.BindPFlag("format", generateCmd.Flags().Lookup("format")); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to bind format flag: %v\n", err)
 	}
+	if err := viper.BindPFlag("profile", generateCmd.Flags().Lookup("profile")); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to bind profile flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("coverage_reports", generateCmd.Flags().Lookup("coverage")); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to bind coverage flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("mutation_reports", generateCmd.Flags().Lookup("mutation")); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to bind mutation flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("indexes", generateCmd.Flags().Lookup("index")); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to bind index flag: %v\n", err)
+	}
+	if err := viper.BindPFlag("record_trends", generateCmd.Flags().Lookup("trends")); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to bind trends flag: %v\n", err)
+	}
 }
 
 func generateContextMap(cmd *cobra.Command) error {
@@ -62,10 +91,22 @@ func generateContextMap(cmd *cobra.Command) error {
 		}
 	}
 
+	onboarding, _ := cmd.Flags().GetBool("onboarding")
+	chunks, _ := cmd.Flags().GetBool("chunks")
+	if onboarding && chunks {
+		return fmt.Errorf("--onboarding and --chunks cannot be combined")
+	}
+
 	outputFile := viper.GetString("output")
 	if outputFile == "" {
 		outputFile = "CLAUDE.md"
 	}
+	if onboarding && !cmd.Flags().Changed("output") {
+		outputFile = "ONBOARDING.md"
+	}
+	if chunks && !cmd.Flags().Changed("output") {
+		outputFile = "chunks.jsonl"
+	}
 
 	if viper.GetBool("verbose") {
 		fmt.Printf("📁 Analyzing directory: %s\n", targetDir)
@@ -101,32 +142,8 @@ func generateContextMap(cmd *cobra.Command) error {
 		builder.SetCache(persistentCache)
 	}
 
-	// Set use_default_excludes from config (default true)
-	useDefaultExcludes := true
-	if viper.IsSet("use_default_excludes") {
-		useDefaultExcludes = viper.GetBool("use_default_excludes")
-	}
-	builder.SetUseDefaultExcludes(useDefaultExcludes)
-	
-	// Set exclude patterns from config
-	excludePatterns := viper.GetStringSlice("exclude_patterns")
-	if len(excludePatterns) > 0 {
-		builder.SetExcludePatterns(excludePatterns)
-		if viper.GetBool("verbose") {
-			// Count include patterns (starting with !)
-			includeCount := 0
-			for _, p := range excludePatterns {
-				if strings.HasPrefix(p, "!") {
-					includeCount++
-				}
-			}
-			excludeCount := len(excludePatterns) - includeCount
-			
-			fmt.Printf("🚫 Exclude patterns: %d, Include overrides: %d\n", excludeCount, includeCount)
-			if !useDefaultExcludes {
-				fmt.Println("   ⚠️  Default excludes disabled")
-			}
-		}
+	if err := configureGraphBuilder(builder, targetDir); err != nil {
+		return err
 	}
 
 	// Set up progress callback for real-time updates
@@ -147,28 +164,188 @@ func generateContextMap(cmd *cobra.Command) error {
 			stats["totalFiles"], stats["totalSymbols"])
 	}
 
-	// Generate markdown content from real data
-	generator := analyzer.NewMarkdownGenerator(graph)
-	content := generator.GenerateContextMap()
-
-	progressManager.UpdateIndeterminate("💾 Writing output file...")
-
-	// Write real content
-	if err := writeOutputFile(outputFile, content); err != nil {
+	// Stream markdown generated from real data into the output file
+	err = writeOutputFile(outputFile, builder.Redactor(), func(w io.Writer) error {
+		if onboarding {
+			_, err := io.WriteString(w, analyzer.NewOnboardingGenerator(graph, targetDir).Generate())
+			return err
+		}
+		if chunks {
+			chunker := analyzer.NewChunker(graph, targetDir)
+			chunker.SetMaxLines(viper.GetInt("output.chunk_max_lines"))
+			chunker.SetRedactor(builder.Redactor())
+			return chunker.WriteJSONL(w)
+		}
+		generator := analyzer.NewMarkdownGenerator(graph)
+		generator.SetTopN(viper.GetInt("output.top_n"))
+		generator.SetPublicOnly(viper.GetBool("output.public_only"))
+		return generator.WriteContextMap(w)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to write output file: %w", err)
 	}
 
+	// Keep a metrics snapshot per run for trend reports when asked to
+	if viper.GetBool("record_trends") {
+		progressManager.UpdateIndeterminate("📈 Recording metrics snapshot...")
+		if err := trends.Record(targetDir, trends.Take(graph, targetDir)); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to record metrics snapshot: %v\n", err)
+		}
+	}
+
 	progressManager.UpdateIndeterminate("✅ Complete")
 
 	progressManager.Stop()
 
 	duration := time.Since(start)
-	fmt.Printf("✅ Context map generated successfully in %v\n", duration)
+	if onboarding {
+		fmt.Printf("✅ Onboarding guide generated successfully in %v\n", duration)
+	} else if chunks {
+		fmt.Printf("✅ Retrieval chunks exported successfully in %v\n", duration)
+	} else {
+		fmt.Printf("✅ Context map generated successfully in %v\n", duration)
+	}
 	fmt.Printf("   Output file: %s\n", outputFile)
 
 	return nil
 }
 
-func writeOutputFile(filename, content string) error {
-	return os.WriteFile(filename, []byte(content), 0644)
+// writeOutputFile streams generated content into filename, masking secrets on the way
+func writeOutputFile(filename string, redactor *redact.Redactor, write func(w io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(redactor.Writer(f)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// configureGraphBuilder applies the analysis settings from the config file (excludes,
+// include directories, WASM grammars and plugins) to a graph builder
+// loadRedactor builds the redactor from the "redaction" config section
+func loadRedactor() (*redact.Redactor, error) {
+	var redaction redact.Config
+	if err := viper.UnmarshalKey("redaction", &redaction); err != nil {
+		return nil, fmt.Errorf("invalid redaction config: %w", err)
+	}
+	return redact.New(redaction)
+}
+
+// loadSemanticConfig reads the "semantic" config section over the default thresholds
+func loadSemanticConfig() (*git.SemanticConfig, error) {
+	if !viper.IsSet("semantic") {
+		return nil, nil
+	}
+	semantic := git.DefaultSemanticConfig()
+	if err := viper.UnmarshalKey("semantic", semantic); err != nil {
+		return nil, fmt.Errorf("invalid semantic config: %w", err)
+	}
+	return semantic, nil
+}
+
+func configureGraphBuilder(builder *analyzer.GraphBuilder, targetDir string) error {
+	// Set use_default_excludes from config (default true)
+	useDefaultExcludes := true
+	if viper.IsSet("use_default_excludes") {
+		useDefaultExcludes = viper.GetBool("use_default_excludes")
+	}
+	builder.SetUseDefaultExcludes(useDefaultExcludes)
+
+	// Set C/C++ include directories from config
+	builder.SetIncludeDirs(viper.GetStringSlice("cpp_include_dirs"))
+
+	// Keep imports of these monorepo packages external instead of resolving them
+	builder.SetExternalWorkspacePackages(viper.GetStringSlice("external_workspace_packages"))
+
+	// Attach test coverage from external reports to files and symbols
+	builder.SetCoverageReports(viper.GetStringSlice("coverage_reports"))
+	builder.SetMutationReports(viper.GetStringSlice("mutation_reports"))
+
+	// Merge precise cross-references from SCIP and LSIF indexes
+	builder.SetIndexes(viper.GetStringSlice("indexes"))
+
+	// Load tree-sitter grammars compiled to WASM for languages without built-in support
+	var wasmGrammars parser.WASMGrammarConfig
+	if err := viper.UnmarshalKey("wasm_grammars", &wasmGrammars); err != nil {
+		return fmt.Errorf("invalid wasm_grammars config: %w", err)
+	}
+	if err := builder.LoadWASMGrammars(wasmGrammars, targetDir); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  WASM grammars: %v\n", err)
+	}
+
+	// Parse files whose extension, shebang or modeline does not tell their language
+	var languageOverrides []parser.LanguageOverride
+	if err := viper.UnmarshalKey("language_overrides", &languageOverrides); err != nil {
+		return fmt.Errorf("invalid language_overrides config: %w", err)
+	}
+	builder.SetLanguageOverrides(languageOverrides)
+
+	// Run external plugins from config alongside the compiled-in ones
+	var pluginConfigs []plugin.ExternalConfig
+	if err := viper.UnmarshalKey("plugins", &pluginConfigs); err != nil {
+		return fmt.Errorf("invalid plugins config: %w", err)
+	}
+	externalPlugins, pluginErrs := plugin.LoadExternal(pluginConfigs)
+	for _, err := range pluginErrs {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping plugin: %v\n", err)
+	}
+	builder.SetPlugins(append(plugin.Registered(), externalPlugins...))
+
+	// Mask secrets in the analyzed graph and generated output
+	redactor, err := loadRedactor()
+	if err != nil {
+		return err
+	}
+	builder.SetRedactor(redactor)
+
+	// Summarize files with an LLM only when a provider is configured
+	var summaries summarize.Config
+	if err := viper.UnmarshalKey("summaries", &summaries); err != nil {
+		return fmt.Errorf("invalid summaries config: %w", err)
+	}
+	summarizer, err := summarize.New(summaries)
+	if err != nil {
+		return err
+	}
+	builder.SetSummarizer(summarizer)
+
+	// Select how much analysis to run
+	profile, err := analyzer.ParseProfile(viper.GetString("profile"))
+	if err != nil {
+		return err
+	}
+	builder.SetProfile(profile)
+
+	// Tune semantic neighborhood analysis
+	semantic, err := loadSemanticConfig()
+	if err != nil {
+		return err
+	}
+	builder.SetSemanticConfig(semantic)
+	
+	// Set exclude patterns from config
+	excludePatterns := viper.GetStringSlice("exclude_patterns")
+	if len(excludePatterns) > 0 {
+		builder.SetExcludePatterns(excludePatterns)
+		if viper.GetBool("verbose") {
+			// Count include patterns (starting with !)
+			includeCount := 0
+			for _, p := range excludePatterns {
+				if strings.HasPrefix(p, "!") {
+					includeCount++
+				}
+			}
+			excludeCount := len(excludePatterns) - includeCount
+			
+			fmt.Printf("🚫 Exclude patterns: %d, Include overrides: %d\n", excludeCount, includeCount)
+			if !useDefaultExcludes {
+				fmt.Println("   ⚠️  Default excludes disabled")
+			}
+		}
+	}
+
+	return nil
 }
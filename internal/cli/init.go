@@ -105,6 +105,9 @@ output:
   template: "default"
   include_metrics: true
   include_toc: true
+  top_n: 10 # entries in ranked lists such as the most imported modules
+  public_only: false # list only exported symbols in the symbol analysis
+  chunk_max_lines: 200 # longest chunk written by generate --chunks
 
 # File Patterns
 include_patterns:
@@ -141,9 +144,171 @@ exclude_patterns:
   # - "!vendor/our-company/**"
   # - "!.github/workflows/ci.yml"
 
+# Extra directories searched when resolving C/C++ #include paths
+# (relative to the analyzed directory). Quoted includes are always tried
+# relative to the including file first.
+cpp_include_dirs:
+  # - "include"
+  # - "third_party/mylib/include"
+
+# Imports of monorepo packages ("@myorg/utils") resolve to the package whose
+# package.json declares that name. Names or patterns listed here stay external.
+external_workspace_packages:
+  # - "@myorg/legacy"
+  # - "@vendor/*"
+
+# Test coverage reports attached to files and symbols (relative to the
+# analyzed directory): Go coverprofiles, lcov tracefiles and Cobertura XML.
+# Hotspots weigh untested code higher; reports are re-read on every analysis.
+coverage_reports:
+  # - "coverage.out"
+  # - "web/coverage/lcov.info"
+
+# Mutation testing results attached to files and symbols: Stryker JSON reports
+# and go-mutesting output. Symbols with surviving mutants are listed under
+# Reliability, most depended on first.
+mutation_reports:
+  # - "reports/mutation/mutation.json"
+
+# SCIP or LSIF indexes from compilers and language servers (scip-go,
+# scip-typescript, lsif-java, ...). Their cross-references confirm and extend
+# the references inferred by the parsers and are marked with their provenance.
+indexes:
+  # - "index.scip"
+
+# Record a metrics snapshot in .codecontext/trends.json on every generate run,
+# for "codecontext trends" (same as generate --trends)
+record_trends: false
+
+# Project-specific feature flag helpers, indexed alongside LaunchDarkly, Unleash,
+# Flagsmith, Split, GrowthBook and OpenFeature calls. The first quoted argument
+# is the flag key; "*" matches any identifier (e.g. "*.isFeatureOn").
+feature_flag_helpers:
+  # - "isFeatureEnabled"
+  # - "flags.Enabled"
+
+# External plugins: each command receives a JSON request on stdin ("describe",
+# "analyze" or "call_tool") and answers with JSON on stdout. Plugins contribute
+# graph nodes, edges and metadata, and may expose extra MCP tools.
+plugins:
+  # - name: "rails_routes"
+  #   command: "./tools/codecontext-rails"
+  #   args: ["--strict"]
+  #   timeout: "30s"
+
+# Languages set by path glob, relative to the analyzed directory, for files
+# whose extension does not tell. Extensionless scripts are also recognized by
+# their shebang (#!/usr/bin/env python3) or a vim/emacs modeline.
+language_overrides:
+  # - path: "hooks/*"
+  #   language: "python"
+
+# Tree-sitter grammars compiled to WASM, for languages without built-in support.
+# Each grammar is loaded from <dir>/tree-sitter-<name>.wasm (dir is relative to
+# the analyzed directory). Requires a build with -tags tree_sitter_wasm.
+wasm_grammars:
+  # dir: ".codecontext/grammars"
+  # extensions:
+  #   ".rb": "ruby"
+  #   ".lua": "lua"
+
+# Saved queries, run with "codecontext report <name>" and exposed as MCP tools
+# named report_<name>. Parameters are passed as $name in the query; group_by and
+# a Go text/template are optional (see docs/MCP.md for the query syntax).
+reports:
+  # - name: "module_importers"
+  #   description: "Files importing a module"
+  #   query: "MATCH a:file->imports->b:file WHERE b.path STARTS WITH $module RETURN a"
+  #   parameters: ["module"]
+  #   group_by: "a.dir"
+
+# Graph rules checked by "codecontext check" (exit status, text/JSON/SARIF) and the
+# check_rules MCP tool. Types: naming, forbidden_import, max_fan_out, require_test
+# and query. files/exclude are "**" globs relative to the analyzed directory.
+rules:
+  # - id: "no-db-in-handlers"
+  #   type: "forbidden_import"
+  #   files: ["internal/handlers/**"]
+  #   imports: ["internal/db/**", "database/sql"]
+  #   message: "handlers must go through the service layer"
+  # - id: "exported-handlers"
+  #   type: "naming"
+  #   kind: "function"
+  #   files: ["internal/handlers/**"]
+  #   pattern: "^[A-Z]"
+  #   severity: "warning"
+  # - id: "fan-out"
+  #   type: "max_fan_out"
+  #   max: 15
+  # - id: "tested-services"
+  #   type: "require_test"
+  #   files: ["internal/services/**/*.go"]
+
+# Naming conventions reported by the get_naming_conventions MCP tool, by language
+# and kind (function, method, class, type, variable, constant, component, file).
+# Styles: camelCase, PascalCase, snake_case, SCREAMING_SNAKE_CASE, kebab-case.
+# Conventions left out follow the majority of each directory, or of the project.
+naming:
+  # python:
+  #   function: "snake_case"
+  #   file: "snake_case"
+  # typescript:
+  #   file: "kebab-case"
+
+# Analysis profile: fast (no git history, symbol usage or call edges),
+# balanced (default) or deep (longer git history, larger neighborhoods).
+# MCP tool calls can pick another profile per call.
+profile: balanced
+
+# Semantic neighborhoods group files that change together in git history.
+# Setting this section overrides the profile's thresholds.
+# semantic:
+#   analysis_period_days: 30
+#   min_change_correlation: 0.4
+#   max_neighborhood_size: 15
+#   include_test_files: true
+
+# Redaction masks secrets in generated maps and MCP tool results. Built-in
+# defaults cover .env files, key files, private keys and common API tokens.
+# Symbols from files matching paths lose their signatures and docs; patterns
+# are regexes masked everywhere (only the first capture group, if any).
+redaction:
+  use_defaults: true
+  paths: [] # e.g. ["config/prod/**", "*.secrets.yaml"]
+  patterns: [] # e.g. ["internal_token\\s*=\\s*\"([^\"]+)\""]
+
+# Language servers asked for precise types by get_symbol_info with the deep
+# profile: gopls, typescript-language-server and pyright-langserver when on PATH.
+# Set a command to use another server, or an empty one to skip a language.
+language_servers:
+  disabled: false
+  timeout: 15s
+  # servers:
+  #   python:
+  #     command: "pylsp"
+
+# MCP server limits. Tool calls for the same directory share one analysis; calls
+# beyond the queue get a "server busy" error instead of piling up.
+mcp:
+  max_concurrent_analyses: 1
+  max_queued_analyses: 8
+  rate_limit_per_minute: 0 # tool calls per session, 0 = unlimited
+  # Directories that tool calls may analyze through target_dir (the server's own
+  # target is always allowed). Leave empty to allow any path; set it when the
+  # server is exposed to remote agents. Denied requests are logged as AUDIT.
+  allowed_roots: []
+  read_only: false # reject tool calls that write (e.g. annotate)
+  # On SIGINT/SIGTERM running tool calls get this long to finish, then the graph
+  # is saved to .codecontext/cache/mcp-graph.json so a restart is ready at once.
+  shutdown_timeout: 10s
+  snapshot: true
+  # Edits to exclude patterns, language settings, profile, semantic, rules and redaction
+  # apply to a running server; other settings need a restart.
+  hot_reload: true
+
 # Default exclude patterns (when use_default_excludes is true):
 # Build outputs: dist/**, build/**, out/**, target/**, bin/**, obj/**
-# Dependencies: node_modules/**, vendor/**, packages/**, bower_components/**
+# Dependencies: node_modules/**, vendor/**, packages/*.*/** (NuGet), bower_components/**
 # Python: __pycache__/**, *.py[cod], .venv/**, venv/**, env/**, .tox/**
 # Dart/Flutter: .dart_tool/**, build/**, *.g.dart, *.freezed.dart
 # Testing: coverage/**, .nyc_output/**, test-results/**, htmlcov/**
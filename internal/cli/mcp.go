@@ -2,11 +2,15 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/nuthan-ms/codecontext/internal/mcp"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -34,27 +38,34 @@ func init() {
 	mcpCmd.Flags().BoolP("watch", "w", true, "enable real-time file watching")
 	mcpCmd.Flags().IntP("debounce", "d", 500, "debounce interval for file changes (ms)")
 	mcpCmd.Flags().StringP("name", "n", "codecontext", "MCP server name")
+	mcpCmd.Flags().Int("max-analyses", 1, "maximum concurrent analyses")
+	mcpCmd.Flags().Int("rate-limit", 0, "maximum tool calls per session per minute (0 = unlimited)")
+	mcpCmd.Flags().StringSlice("allow-root", nil, "directory target_dir may point into (repeatable; default: any)")
+	mcpCmd.Flags().Bool("read-only", false, "reject tool calls that write to the project")
+	mcpCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "time to let in-flight tool calls finish on shutdown")
+	mcpCmd.Flags().Bool("snapshot", true, "save the graph on shutdown and load it on start")
+	mcpCmd.Flags().Bool("hot-reload", true, "apply config file changes without restarting")
+	mcpCmd.Flags().String("pprof-addr", "", "serve pprof endpoints and /healthz on this address, e.g. localhost:6060")
 
 	// Bind flags to viper
 	viper.BindPFlag("mcp.target", mcpCmd.Flags().Lookup("target"))
 	viper.BindPFlag("mcp.watch", mcpCmd.Flags().Lookup("watch"))
 	viper.BindPFlag("mcp.debounce", mcpCmd.Flags().Lookup("debounce"))
 	viper.BindPFlag("mcp.name", mcpCmd.Flags().Lookup("name"))
+	viper.BindPFlag("mcp.max_concurrent_analyses", mcpCmd.Flags().Lookup("max-analyses"))
+	viper.BindPFlag("mcp.rate_limit_per_minute", mcpCmd.Flags().Lookup("rate-limit"))
+	viper.BindPFlag("mcp.allowed_roots", mcpCmd.Flags().Lookup("allow-root"))
+	viper.BindPFlag("mcp.read_only", mcpCmd.Flags().Lookup("read-only"))
+	viper.BindPFlag("mcp.shutdown_timeout", mcpCmd.Flags().Lookup("shutdown-timeout"))
+	viper.BindPFlag("mcp.snapshot", mcpCmd.Flags().Lookup("snapshot"))
+	viper.BindPFlag("mcp.hot_reload", mcpCmd.Flags().Lookup("hot-reload"))
+	viper.BindPFlag("mcp.pprof_addr", mcpCmd.Flags().Lookup("pprof-addr"))
 }
 
 func runMCPServer() error {
-	// Get configuration from flags/config
-	targetDir := viper.GetString("mcp.target")
-	if targetDir == "" {
-		targetDir = "."
-	}
-
-	config := &mcp.MCPConfig{
-		Name:        viper.GetString("mcp.name"),
-		Version:     appVersion,
-		TargetDir:   targetDir,
-		EnableWatch: viper.GetBool("mcp.watch"),
-		DebounceMs:  viper.GetInt("mcp.debounce"),
+	config, err := mcpConfigFromViper()
+	if err != nil {
+		return err
 	}
 
 	if viper.GetBool("verbose") {
@@ -66,6 +77,12 @@ func runMCPServer() error {
 		if config.EnableWatch {
 			fmt.Printf("   Debounce Interval: %dms\n", config.DebounceMs)
 		}
+		if len(config.AllowedRoots) > 0 {
+			fmt.Printf("   Allowed Roots: %v\n", config.AllowedRoots)
+		}
+		if config.ReadOnly {
+			fmt.Printf("   Read-only: true\n")
+		}
 		fmt.Printf("   Transport: Standard I/O\n")
 		fmt.Printf("\n")
 	}
@@ -76,6 +93,30 @@ func runMCPServer() error {
 		return fmt.Errorf("failed to create MCP server: %w", err)
 	}
 
+	// Profiling and health endpoints for diagnosing a long-running server
+	if addr := viper.GetString("mcp.pprof_addr"); addr != "" {
+		pprofServer, listenAddr, err := startPprofServer(addr, server.Healthz)
+		if err != nil {
+			return err
+		}
+		defer pprofServer.Close()
+		fmt.Fprintf(os.Stderr, "📈 pprof endpoints at http://%s/debug/pprof/, health at /healthz\n", listenAddr)
+	}
+
+	// Apply config file edits to the running server
+	if viper.GetBool("mcp.hot_reload") && viper.ConfigFileUsed() != "" {
+		viper.OnConfigChange(func(e fsnotify.Event) {
+			config, err := mcpConfigFromViper()
+			if err == nil {
+				err = server.Reload(config)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Config reload: %v (keeping the previous settings)\n", err)
+			}
+		})
+		viper.WatchConfig()
+	}
+
 	// Setup graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -89,7 +130,7 @@ func runMCPServer() error {
 		if viper.GetBool("verbose") {
 			fmt.Fprintf(os.Stderr, "\n🛑 Received shutdown signal, stopping MCP server...\n")
 		}
-		server.Stop()
+		shutdownMCPServer(server)
 		cancel()
 	}()
 
@@ -103,11 +144,53 @@ func runMCPServer() error {
 		fmt.Printf("   • search_symbols         - Search symbols across codebase\n")
 		fmt.Printf("   • get_dependencies       - Import/dependency analysis\n")
 		fmt.Printf("   • watch_changes          - Real-time change notifications\n")
+		fmt.Printf("   • get_semantic_neighborhoods - Files that change together in git history\n")
+		fmt.Printf("   • get_framework_analysis - Framework detection and component analysis\n")
+		fmt.Printf("   • get_type_hierarchy     - Class/interface supertypes and subtypes\n")
+		fmt.Printf("   • get_build_targets      - Build targets and affected-target queries\n")
+		fmt.Printf("   • get_tasks              - Build/test/lint task entry points\n")
+		fmt.Printf("   • get_k8s_topology       - Kubernetes/Helm deployment topology\n")
+		fmt.Printf("   • get_event_flows        - Pub/sub producers and consumers by topic\n")
+		fmt.Printf("   • get_feature_flags      - Feature flag usage index\n")
+		fmt.Printf("   • query_graph            - Ad-hoc graph queries (MATCH ... WHERE ...)\n")
+		fmt.Printf("   • check_rules            - Rule violations (naming, imports, fan-out, tests)\n")
+		fmt.Printf("   • annotate               - Notes attached to files and symbols\n")
+		fmt.Printf("   • get_unexplored_related - Related code not yet retrieved this session\n")
+		fmt.Printf("   • get_server_status      - Readiness, loaded graph, queue and watcher state\n")
+		fmt.Printf("   • find_string_origin     - Where a log line or error message comes from\n")
+		fmt.Printf("   • get_error_sources      - Where Go errors originate and propagate\n")
+		fmt.Printf("   • get_concurrency_map    - Concurrency constructs per file\n")
+		fmt.Printf("   • get_coupling_metrics   - Package fan-in, fan-out and instability\n")
+		fmt.Printf("   • get_trends             - Metrics across analysis runs\n")
+		fmt.Printf("   • get_release_notes      - Public API changes between two git refs\n")
+		fmt.Printf("   • check_api_compatibility - Breaking API changes between two git refs\n")
+		fmt.Printf("   • suggest_version        - Next semantic version per module\n")
+		fmt.Printf("   • get_bus_factor         - Contributor diversity and bus factor per module\n")
+		fmt.Printf("   • get_stale_code         - Old, unreferenced and untested files\n")
+		fmt.Printf("   • get_benchmarks         - Benchmarks and the code they exercise\n")
+		fmt.Printf("   • get_annotations        - Decorator and annotation usage index\n")
+		fmt.Printf("   • get_middleware_chains  - Ordered middleware per route\n")
+		fmt.Printf("   • get_frontend_routes    - Frontend pages and the endpoints they call\n")
+		fmt.Printf("   • get_asset_usages       - Static assets and the code referring to them\n")
+		fmt.Printf("   • get_stories            - Storybook stories and unstoried components\n")
+		fmt.Printf("   • get_state_flows        - Redux, Pinia, Zustand and Bloc state flows\n")
+		fmt.Printf("   • find_implementations   - Types implementing an interface, Go method sets included\n")
+		fmt.Printf("   • regenerate_summaries   - Drop cached LLM file summaries and write them again\n")
+		fmt.Printf("   • check_dependencies     - Unused, missing and version-skewed manifest dependencies\n")
+		fmt.Printf("   • reachable_from         - Code reachable from entry points, and dead code\n")
+		fmt.Printf("   • get_dependency_path    - Shortest chain of dependencies between two files or symbols\n")
+		fmt.Printf("   • compare_graphs         - Files, symbols and edges added or removed between two trees\n")
+		fmt.Printf("   • simulate_removal       - Dependencies that deleting a file or package would break\n")
+		fmt.Printf("   • suggest_refactorings   - God-files and god-classes with suggested split boundaries\n")
+		fmt.Printf("   • get_naming_conventions - Naming styles per language and module, and names breaking them\n")
+		fmt.Printf("   • most_used_symbols      - Symbols ranked by how widely the code uses them\n")
 		fmt.Printf("\n")
 	}
 
 	err = server.Run(ctx)
-	if err != nil {
+	// Flush state when the client disconnects too; a no-op after a signal
+	shutdownMCPServer(server)
+	if err != nil && !(errors.Is(err, context.Canceled) && ctx.Err() != nil) {
 		return fmt.Errorf("MCP server error: %w", err)
 	}
 
@@ -116,4 +199,87 @@ func runMCPServer() error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// shutdownMCPServer lets in-flight tool calls finish within the shutdown
+// timeout and saves the server state
+func shutdownMCPServer(server *mcp.CodeContextMCPServer) {
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("mcp.shutdown_timeout"))
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Shutdown: %v\n", err)
+	}
+}
+
+// mcpConfigFromViper builds the server configuration from flags and the config file
+func mcpConfigFromViper() (*mcp.MCPConfig, error) {
+	// Get configuration from flags/config
+	targetDir := viper.GetString("mcp.target")
+	if targetDir == "" {
+		targetDir = "."
+	}
+
+	config := &mcp.MCPConfig{
+		Name:        viper.GetString("mcp.name"),
+		Version:     appVersion,
+		TargetDir:   targetDir,
+		EnableWatch: viper.GetBool("mcp.watch"),
+		DebounceMs:  viper.GetInt("mcp.debounce"),
+		IncludeDirs: viper.GetStringSlice("cpp_include_dirs"),
+		FlagHelpers: viper.GetStringSlice("feature_flag_helpers"),
+
+		MaxConcurrentAnalyses: viper.GetInt("mcp.max_concurrent_analyses"),
+		MaxQueuedAnalyses:     viper.GetInt("mcp.max_queued_analyses"),
+		RateLimitPerMinute:    viper.GetInt("mcp.rate_limit_per_minute"),
+
+		AllowedRoots: viper.GetStringSlice("mcp.allowed_roots"),
+		ReadOnly:     viper.GetBool("mcp.read_only"),
+
+		ExcludePatterns:    viper.GetStringSlice("exclude_patterns"),
+		ExternalWorkspaces: viper.GetStringSlice("external_workspace_packages"),
+		CoverageReports:    viper.GetStringSlice("coverage_reports"),
+		MutationReports:    viper.GetStringSlice("mutation_reports"),
+		Indexes:            viper.GetStringSlice("indexes"),
+		Profile:            viper.GetString("profile"),
+	}
+	if viper.GetBool("mcp.snapshot") {
+		config.SnapshotPath = filepath.Join(targetDir, ".codecontext", "cache", "mcp-graph.json")
+	}
+	if err := viper.UnmarshalKey("plugins", &config.Plugins); err != nil {
+		return nil, fmt.Errorf("invalid plugins config: %w", err)
+	}
+	if err := viper.UnmarshalKey("wasm_grammars", &config.WASMGrammars); err != nil {
+		return nil, fmt.Errorf("invalid wasm_grammars config: %w", err)
+	}
+	if err := viper.UnmarshalKey("language_overrides", &config.LanguageOverrides); err != nil {
+		return nil, fmt.Errorf("invalid language_overrides config: %w", err)
+	}
+	if err := viper.UnmarshalKey("reports", &config.Reports); err != nil {
+		return nil, fmt.Errorf("invalid reports config: %w", err)
+	}
+	if err := viper.UnmarshalKey("rules", &config.Rules); err != nil {
+		return nil, fmt.Errorf("invalid rules config: %w", err)
+	}
+	if err := viper.UnmarshalKey("naming", &config.Naming); err != nil {
+		return nil, fmt.Errorf("invalid naming config: %w", err)
+	}
+	if err := viper.UnmarshalKey("redaction", &config.Redaction); err != nil {
+		return nil, fmt.Errorf("invalid redaction config: %w", err)
+	}
+	if err := viper.UnmarshalKey("summaries", &config.Summaries); err != nil {
+		return nil, fmt.Errorf("invalid summaries config: %w", err)
+	}
+	if err := viper.UnmarshalKey("language_servers", &config.LanguageServers); err != nil {
+		return nil, fmt.Errorf("invalid language_servers config: %w", err)
+	}
+	if viper.IsSet("use_default_excludes") {
+		useDefaultExcludes := viper.GetBool("use_default_excludes")
+		config.UseDefaultExcludes = &useDefaultExcludes
+	}
+	semantic, err := loadSemanticConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Semantic = semantic
+	return config, nil
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"runtime"
+	rpprof "runtime/pprof"
+	"time"
+)
+
+// stopProfiling finishes the profiles started for the current command. Execute
+// calls it after the command returns, so profiles are written even on failure.
+var stopProfiling = func() error { return nil }
+
+// startProfiling starts a CPU profile into cpuFile and arranges for a heap
+// profile to be written to memFile when the returned stop function is called.
+// Empty file names disable the respective profile.
+func startProfiling(cpuFile, memFile string) (func() error, error) {
+	var cpu *os.File
+	if cpuFile != "" {
+		f, err := os.Create(cpuFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := rpprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		cpu = f
+	}
+
+	return func() error {
+		var errs []error
+		if cpu != nil {
+			rpprof.StopCPUProfile()
+			errs = append(errs, cpu.Close())
+		}
+		if memFile != "" {
+			errs = append(errs, writeHeapProfile(memFile))
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create heap profile: %w", err)
+	}
+	defer f.Close()
+	// Collect garbage first so the profile shows live memory
+	runtime.GC()
+	if err := rpprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("failed to write heap profile: %w", err)
+	}
+	return nil
+}
+
+// startPprofServer serves the net/http/pprof endpoints under /debug/pprof/ on
+// addr until the returned server is closed, and healthz under /healthz when
+// it is not nil
+func startPprofServer(addr string, healthz http.HandlerFunc) (*http.Server, net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start pprof server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	if healthz != nil {
+		mux.HandleFunc("/healthz", healthz)
+	}
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go server.Serve(listener)
+	return server, listener.Addr(), nil
+}
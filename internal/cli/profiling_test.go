@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartProfiling(t *testing.T) {
+	dir := t.TempDir()
+	cpuFile := filepath.Join(dir, "cpu.prof")
+	memFile := filepath.Join(dir, "mem.prof")
+
+	stop, err := startProfiling(cpuFile, memFile)
+	require.NoError(t, err)
+	require.NoError(t, stop())
+
+	for _, path := range []string{cpuFile, memFile} {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Positive(t, info.Size(), path)
+	}
+
+	stop, err = startProfiling("", "")
+	require.NoError(t, err)
+	assert.NoError(t, stop(), "profiling is off without file names")
+
+	_, err = startProfiling(filepath.Join(dir, "missing", "cpu.prof"), "")
+	assert.Error(t, err)
+}
+
+func TestPprofServer(t *testing.T) {
+	var ready atomic.Bool
+	server, addr, err := startPprofServer("127.0.0.1:0", func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	require.NoError(t, err)
+	defer server.Close()
+
+	resp, err := http.Get("http://" + addr.String() + "/debug/pprof/heap?debug=1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "heap profile")
+
+	health := func() int {
+		resp, err := http.Get("http://" + addr.String() + "/healthz")
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, health())
+	ready.Store(true)
+	assert.Equal(t, http.StatusOK, health())
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/query"
+	"github.com/spf13/cobra"
+)
+
+var queryCmd = &cobra.Command{
+	Use:   "query <query>",
+	Short: "Run an ad-hoc query over the code graph",
+	Long: `Run a Cypher-like query over the files, symbols and relationships of the code graph.
+
+  codecontext query "MATCH a:file->imports->b:file WHERE b.path CONTAINS 'auth' RETURN a"
+  codecontext query "MATCH function WHERE name MATCHES '^handle' LIMIT 20"
+
+Patterns alternate node kinds (file, symbol, function, class, ..., *) and edge types
+(imports, calls, extends, implements, contains, ..., *). See docs/MCP.md for the syntax.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runQuery(cmd, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+	queryCmd.Flags().StringP("target", "t", ".", "target directory to analyze")
+	queryCmd.Flags().Bool("json", false, "print matches as JSON")
+}
+
+func runQuery(cmd *cobra.Command, source string) error {
+	// Parse first so syntax errors don't wait for the analysis
+	q, err := query.Parse(source)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	targetDir, _ := cmd.Flags().GetString("target")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	builder := analyzer.NewGraphBuilder()
+	if err := configureGraphBuilder(builder, targetDir); err != nil {
+		return err
+	}
+	graph, err := builder.AnalyzeDirectory(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to analyze directory: %w", err)
+	}
+
+	result := query.Execute(graph, q, targetDir)
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result.Records())
+	}
+	fmt.Print(result.Markdown())
+	return nil
+}
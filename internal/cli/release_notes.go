@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/apidiff"
+	"github.com/spf13/cobra"
+)
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "release-notes <from> [to]",
+	Short: "Draft release notes from public API changes between two git refs",
+	Long: `Analyze the project at two git refs (tags, branches or commits) and draft a
+changelog grouped by package: public symbols added, changed, moved and removed,
+new and removed HTTP/gRPC endpoints, and renamed or moved files. Without a
+second ref the working tree is compared.
+
+  codecontext release-notes v1.2.0 v1.3.0
+  codecontext release-notes v1.3.0 -o RELEASE_NOTES.md`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReleaseNotes(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(releaseNotesCmd)
+	releaseNotesCmd.Flags().StringP("target", "t", ".", "project directory")
+	releaseNotesCmd.Flags().StringP("output", "o", "", "write the notes to a file instead of stdout")
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	output, _ := cmd.Flags().GetString("output")
+	to := ""
+	if len(args) == 2 {
+		to = args[1]
+	}
+
+	diff, err := apidiff.CompareRefs(context.Background(), targetDir, args[0], to, analyzer.AnalyzeDirectory)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		fmt.Print(diff.ReleaseNotes())
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(diff.ReleaseNotes()), 0644); err != nil {
+		return fmt.Errorf("failed to write release notes: %w", err)
+	}
+	fmt.Printf("✅ Release notes written to %s\n", output)
+	return nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/query"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report [name]",
+	Short: "Run a saved query from the config",
+	Long: `Run a report defined under "reports" in .codecontext/config.yaml.
+Without a name, lists the configured reports.
+
+  codecontext report
+  codecontext report module_importers --param module=auth`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runReport(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringP("target", "t", ".", "target directory to analyze")
+	reportCmd.Flags().StringArrayP("param", "p", nil, "report parameter as name=value (repeatable)")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	var configs []query.ReportConfig
+	if err := viper.UnmarshalKey("reports", &configs); err != nil {
+		return fmt.Errorf("invalid reports config: %w", err)
+	}
+	reports, errs := query.LoadReports(configs)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "⚠️  Skipping report: %v\n", err)
+	}
+
+	if len(args) == 0 {
+		if len(reports) == 0 {
+			fmt.Println("No reports configured. Add them under \"reports\" in .codecontext/config.yaml.")
+			return nil
+		}
+		fmt.Println("Configured reports:")
+		for _, report := range reports {
+			line := fmt.Sprintf("   • %-24s - %s", report.Name(), report.Description())
+			if params := report.Parameters(); len(params) > 0 {
+				line += fmt.Sprintf(" (parameters: %s)", strings.Join(params, ", "))
+			}
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	var report *query.Report
+	for _, candidate := range reports {
+		if candidate.Name() == args[0] {
+			report = candidate
+		}
+	}
+	if report == nil {
+		return fmt.Errorf("unknown report %q", args[0])
+	}
+
+	rawParams, _ := cmd.Flags().GetStringArray("param")
+	params := make(map[string]string, len(rawParams))
+	for _, raw := range rawParams {
+		name, value, ok := strings.Cut(raw, "=")
+		if !ok {
+			return fmt.Errorf("invalid parameter %q: expected name=value", raw)
+		}
+		params[name] = value
+	}
+
+	targetDir, _ := cmd.Flags().GetString("target")
+	builder := analyzer.NewGraphBuilder()
+	if err := configureGraphBuilder(builder, targetDir); err != nil {
+		return err
+	}
+	graph, err := builder.AnalyzeDirectory(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to analyze directory: %w", err)
+	}
+
+	text, err := report.Run(graph, targetDir, params)
+	if err != nil {
+		return err
+	}
+	fmt.Print(text)
+	return nil
+}
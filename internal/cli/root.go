@@ -22,11 +22,25 @@ var (
 intelligent context maps for AI-powered development tools, with a focus on
 token optimization and incremental updates.`,
 		Version: appVersion,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cpuFile, _ := cmd.Flags().GetString("cpuprofile")
+			memFile, _ := cmd.Flags().GetString("memprofile")
+			stop, err := startProfiling(cpuFile, memFile)
+			if err != nil {
+				return err
+			}
+			stopProfiling = stop
+			return nil
+		},
 	}
 )
 
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if perr := stopProfiling(); perr != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", perr)
+	}
+	return err
 }
 
 // SetVersion sets the version information from build time
@@ -55,6 +69,8 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .codecontext/config.yaml)")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
 	rootCmd.PersistentFlags().StringP("output", "o", "CLAUDE.md", "output file")
+	rootCmd.PersistentFlags().String("cpuprofile", "", "write a CPU profile of the command to this file")
+	rootCmd.PersistentFlags().String("memprofile", "", "write a heap profile to this file when the command finishes")
 
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/apidiff"
+	"github.com/spf13/cobra"
+)
+
+var suggestVersionCmd = &cobra.Command{
+	Use:   "suggest-version <from> [to]",
+	Short: "Suggest the next semantic version of each module since a git ref",
+	Long: `Suggest a major, minor or patch bump for every module of the project (the
+root and each directory with a go.mod, package.json, pubspec.yaml, Cargo.toml,
+pyproject.toml or setup.py) from the public API changes and the conventional
+commit messages between two git refs. Without a second ref the working tree
+is compared.
+
+  codecontext suggest-version v1.2.0
+  codecontext suggest-version v1.2.0 main --format json
+
+Incompatible API changes and breaking commits ("feat!:", "BREAKING CHANGE:")
+need a major release, API additions and "feat:" commits a minor one, and other
+changes a patch, except docs, test, chore, ci, build and style commits.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSuggestVersion(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(suggestVersionCmd)
+	suggestVersionCmd.Flags().StringP("target", "t", ".", "project directory")
+	suggestVersionCmd.Flags().StringP("format", "f", "markdown", "output format (markdown, json)")
+}
+
+func runSuggestVersion(cmd *cobra.Command, args []string) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	format, _ := cmd.Flags().GetString("format")
+	if format != "markdown" && format != "json" {
+		return fmt.Errorf("unknown format %q (use markdown or json)", format)
+	}
+	to := ""
+	if len(args) == 2 {
+		to = args[1]
+	}
+
+	report, err := apidiff.SuggestVersions(context.Background(), targetDir, args[0], to, analyzer.AnalyzeDirectory)
+	if err != nil {
+		return err
+	}
+	if format == "json" {
+		if report.Suggestions == nil {
+			report.Suggestions = []apidiff.VersionSuggestion{}
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		os.Stdout.Write(append(data, '\n'))
+		return nil
+	}
+	fmt.Print(report.Markdown())
+	return nil
+}
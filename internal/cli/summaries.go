@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/summarize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var regenerateSummariesCmd = &cobra.Command{
+	Use:   "regenerate-summaries [file...]",
+	Short: "Regenerate the LLM summaries of files",
+	Long: `Drop cached module summaries and analyze the project to write them again.
+Without files, every summary is regenerated. Files are relative to the target
+directory. Needs a provider under "summaries" in .codecontext/config.yaml.
+
+  codecontext regenerate-summaries
+  codecontext regenerate-summaries internal/db/pool.go internal/db/tx.go`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRegenerateSummaries(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(regenerateSummariesCmd)
+	regenerateSummariesCmd.Flags().StringP("target", "t", ".", "target directory to analyze")
+}
+
+func runRegenerateSummaries(cmd *cobra.Command, args []string) error {
+	var config summarize.Config
+	if err := viper.UnmarshalKey("summaries", &config); err != nil {
+		return fmt.Errorf("invalid summaries config: %w", err)
+	}
+	if summarizer, err := summarize.New(config); err != nil {
+		return err
+	} else if summarizer == nil {
+		return fmt.Errorf("summaries are not configured: set a provider under \"summaries\" in .codecontext/config.yaml")
+	}
+
+	targetDir, _ := cmd.Flags().GetString("target")
+	dropped, err := summarize.Invalidate(targetDir, args)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🗑️  Dropped %d cached summaries\n", dropped)
+
+	builder := analyzer.NewGraphBuilder()
+	if err := configureGraphBuilder(builder, targetDir); err != nil {
+		return err
+	}
+	graph, err := builder.AnalyzeDirectory(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to analyze directory: %w", err)
+	}
+
+	if stats, ok := graph.Metadata.Configuration["summary_stats"].(summarize.Stats); ok {
+		fmt.Printf("📝 %d generated, %d cached, %d pending\n", stats.Generated, stats.Cached, stats.Pending)
+	}
+	if errs, ok := graph.Metadata.Configuration["summary_errors"].([]string); ok && len(errs) > 0 {
+		return fmt.Errorf("%s", errs[0])
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/spf13/cobra"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Write a ctags or etags file of the symbol table",
+	Long: `Write the symbols of every supported language to a tags file, so editors
+can jump to definitions. The ctags format (tags) is the extended format read by
+Vim and Neovim, with kind, line, language, scope and signature fields. The
+etags format (TAGS) is read by Emacs. File names are relative to the directory
+of the tags file.
+
+  codecontext tags
+  codecontext tags --format etags
+  codecontext tags --target src -o src/tags`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTags(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.Flags().StringP("target", "t", ".", "target directory to analyze")
+	tagsCmd.Flags().StringP("format", "f", "ctags", "tags format (ctags, etags)")
+	tagsCmd.Flags().StringP("output", "o", "", "tags file (default tags, or TAGS for etags)")
+}
+
+func runTags(cmd *cobra.Command) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	format, _ := cmd.Flags().GetString("format")
+	outputFile, _ := cmd.Flags().GetString("output")
+	if format != "ctags" && format != "etags" {
+		return fmt.Errorf("unknown tags format %q (use ctags or etags)", format)
+	}
+	if outputFile == "" {
+		outputFile = "tags"
+		if format == "etags" {
+			outputFile = "TAGS"
+		}
+	}
+
+	builder := analyzer.NewGraphBuilder()
+	if err := configureGraphBuilder(builder, targetDir); err != nil {
+		return err
+	}
+	graph, err := builder.AnalyzeDirectory(targetDir)
+	if err != nil {
+		return fmt.Errorf("failed to analyze directory: %w", err)
+	}
+
+	generator := analyzer.NewTagGenerator(graph, targetDir)
+	generator.SetBaseDir(filepath.Dir(outputFile))
+	generator.SetRedactor(builder.Redactor())
+	err = writeOutputFile(outputFile, builder.Redactor(), func(w io.Writer) error {
+		if format == "etags" {
+			return generator.WriteEtags(w)
+		}
+		return generator.WriteCtags(w)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write tags file: %w", err)
+	}
+
+	fmt.Printf("✅ Tags written to %s\n", outputFile)
+	return nil
+}
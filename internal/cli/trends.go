@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/nuthan-ms/codecontext/internal/trends"
+	"github.com/spf13/cobra"
+)
+
+var trendsCmd = &cobra.Command{
+	Use:   "trends",
+	Short: "Show how codebase metrics evolved across runs",
+	Long: `Show the metrics snapshots recorded by "codecontext generate --trends" in
+.codecontext/trends.json: files, symbols, function complexity, package coupling
+and the most imported files, with the change from the oldest run shown. Set
+record_trends: true in the config to record on every run.
+
+  codecontext trends
+  codecontext trends --last 5
+  codecontext trends --tags    # one snapshot per tagged commit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTrends(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trendsCmd)
+	trendsCmd.Flags().StringP("target", "t", ".", "project directory")
+	trendsCmd.Flags().IntP("last", "n", 10, "number of snapshots to show (0 for all)")
+	trendsCmd.Flags().Bool("tags", false, "only show snapshots of tagged commits")
+}
+
+func runTrends(cmd *cobra.Command) error {
+	targetDir, _ := cmd.Flags().GetString("target")
+	last, _ := cmd.Flags().GetInt("last")
+	tagsOnly, _ := cmd.Flags().GetBool("tags")
+
+	history, err := trends.Load(targetDir)
+	if err != nil {
+		return err
+	}
+	snapshots := history.Recent(last, tagsOnly)
+	if len(snapshots) == 0 {
+		fmt.Println("No metrics snapshots recorded. Run \"codecontext generate\" to record one.")
+		return nil
+	}
+	fmt.Print(trends.Markdown(snapshots))
+	return nil
+}
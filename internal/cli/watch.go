@@ -14,6 +14,7 @@ import (
 
 	"github.com/nuthan-ms/codecontext/internal/analyzer"
 	"github.com/nuthan-ms/codecontext/internal/cache"
+	"github.com/nuthan-ms/codecontext/internal/redact"
 	"github.com/nuthan-ms/codecontext/internal/watcher"
 	"github.com/nuthan-ms/codecontext/pkg/types"
 )
@@ -50,6 +51,7 @@ type WatchManager struct {
 	watcher     *watcher.FileWatcher
 	analyzer    *analyzer.IncrementalAnalyzer
 	cache       *cache.PersistentCache
+	redactor    *redact.Redactor
 	graph       *types.CodeGraph
 	lastUpdate  time.Time
 	updateMutex sync.RWMutex
@@ -181,6 +183,13 @@ func NewWatchManager(config *WatchConfig) (*WatchManager, error) {
 		cancel: cancel,
 	}
 
+	redactor, err := loadRedactor()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	manager.redactor = redactor
+
 	// Initialize cache if enabled
 	if config.EnableCache {
 		cacheConfig := &cache.Config{
@@ -211,7 +220,6 @@ func NewWatchManager(config *WatchConfig) (*WatchManager, error) {
 		ParallelProcessing: true,
 	}
 
-	var err error
 	manager.analyzer, err = analyzer.NewIncrementalAnalyzer(config.TargetDir, analyzerConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create incremental analyzer: %w", err)
@@ -313,6 +321,7 @@ func (wm *WatchManager) performInitialAnalysis() error {
 	// If no cached graph, perform full analysis
 	if graph == nil {
 		builder := analyzer.NewGraphBuilder()
+		builder.SetRedactor(wm.redactor)
 		graph, err = builder.AnalyzeDirectory(wm.config.TargetDir)
 		if err != nil {
 			return fmt.Errorf("failed to analyze directory: %w", err)
@@ -426,12 +435,11 @@ func (wm *WatchManager) generateOutput() error {
 		return fmt.Errorf("no graph available for output generation")
 	}
 
-	// Generate markdown content
+	// Stream markdown content into the output file
 	generator := analyzer.NewMarkdownGenerator(graph)
-	content := generator.GenerateContextMap()
-
-	// Write to output file
-	return writeOutputFile(wm.config.OutputFile, content)
+	generator.SetTopN(viper.GetInt("output.top_n"))
+	generator.SetPublicOnly(viper.GetBool("output.public_only"))
+	return writeOutputFile(wm.config.OutputFile, wm.redactor, generator.WriteContextMap)
 }
 
 // performFinalUpdate performs a final update before shutdown
@@ -0,0 +1,103 @@
+// Package codeindex reads SCIP and LSIF code navigation indexes produced by
+// compilers and language servers, such as scip-go, scip-typescript or lsif-java.
+package codeindex
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// Index formats
+const (
+	FormatSCIP = "scip" // SCIP protobuf (index.scip)
+	FormatLSIF = "lsif" // LSIF JSON lines (dump.lsif)
+)
+
+// Occurrence is a definition or reference of a symbol
+type Occurrence struct {
+	File       string // Relative to the index's project root, slash-separated
+	Line       int    // 1-based
+	Column     int    // 0-based, in the index's position encoding
+	Definition bool
+}
+
+// Symbol is a symbol and where it occurs
+type Symbol struct {
+	ID          string // SCIP symbol, or LSIF moniker or result set
+	Occurrences []Occurrence
+}
+
+// Definitions returns the occurrences defining the symbol
+func (s *Symbol) Definitions() []Occurrence {
+	var definitions []Occurrence
+	for _, occurrence := range s.Occurrences {
+		if occurrence.Definition {
+			definitions = append(definitions, occurrence)
+		}
+	}
+	return definitions
+}
+
+// References returns the occurrences referring to the symbol
+func (s *Symbol) References() []Occurrence {
+	var references []Occurrence
+	for _, occurrence := range s.Occurrences {
+		if !occurrence.Definition {
+			references = append(references, occurrence)
+		}
+	}
+	return references
+}
+
+// Index is the content of one index file
+type Index struct {
+	Path    string
+	Format  string
+	Root    string             // Project root the files are relative to, as a local path; empty if not recorded
+	Symbols map[string]*Symbol // By ID
+}
+
+// symbol returns the entry of id, creating it
+func (i *Index) symbol(id string) *Symbol {
+	s := i.Symbols[id]
+	if s == nil {
+		s = &Symbol{ID: id}
+		i.Symbols[id] = s
+	}
+	return s
+}
+
+// Load reads an index, detecting its format from the content
+func Load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+	index := &Index{Path: path, Format: DetectFormat(data), Symbols: make(map[string]*Symbol)}
+	switch index.Format {
+	case FormatSCIP:
+		err = index.addSCIP(data)
+	case FormatLSIF:
+		err = index.addLSIF(data)
+	default:
+		err = fmt.Errorf("unrecognized index format (supported: scip, lsif)")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return index, nil
+}
+
+// DetectFormat returns the format of an index, or "" when unknown. LSIF dumps
+// are JSON lines; SCIP indexes start with their metadata or first document.
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return FormatLSIF
+	case len(data) > 0 && (data[0] == 0x0a || data[0] == 0x12):
+		return FormatSCIP
+	}
+	return ""
+}
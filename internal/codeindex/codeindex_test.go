@@ -0,0 +1,103 @@
+package codeindex
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendField(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|protoWireLengthDelimited)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+func scipOccurrence(symbol string, roles uint64, line, column int) []byte {
+	var ranges []byte
+	for _, value := range []int{line, column, column + 3} {
+		ranges = binary.AppendUvarint(ranges, uint64(value))
+	}
+	b := appendField(nil, scipOccurrenceRange, ranges)
+	b = appendField(b, scipOccurrenceSymbol, []byte(symbol))
+	if roles != 0 {
+		b = binary.AppendUvarint(b, scipOccurrenceSymbolRoles<<3|protoWireVarint)
+		b = binary.AppendUvarint(b, roles)
+	}
+	return b
+}
+
+func TestLoadSCIP(t *testing.T) {
+	const add = "scip-go gomod shop v1 `shop`/Cart#Add()."
+	metadata := appendField(nil, scipMetadataProjectRoot, []byte("file:///work/shop"))
+	document := appendField(nil, scipDocumentRelativePath, []byte("cart.go"))
+	document = appendField(document, scipDocumentOccurrences, scipOccurrence(add, scipSymbolRoleDefinition, 9, 15))
+	document = appendField(document, scipDocumentOccurrences, scipOccurrence("local 0", scipSymbolRoleDefinition, 10, 1))
+	other := appendField(nil, scipDocumentRelativePath, []byte("checkout.go"))
+	other = appendField(other, scipDocumentOccurrences, scipOccurrence(add, 0, 4, 6))
+	data := appendField(nil, scipIndexMetadata, metadata)
+	data = appendField(data, scipIndexDocuments, document)
+	data = appendField(data, scipIndexDocuments, other)
+
+	path := filepath.Join(t.TempDir(), "index.scip")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	index, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, FormatSCIP, index.Format)
+	assert.Equal(t, "/work/shop", index.Root)
+	require.Len(t, index.Symbols, 1, "local symbols are left out")
+	symbol := index.Symbols[add]
+	assert.Equal(t, []Occurrence{{File: "cart.go", Line: 10, Column: 15, Definition: true}}, symbol.Definitions())
+	assert.Equal(t, []Occurrence{{File: "checkout.go", Line: 5, Column: 6}}, symbol.References())
+}
+
+func TestLoadLSIF(t *testing.T) {
+	dump := strings.Join([]string{
+		`{"id":1,"type":"vertex","label":"metaData","version":"0.4.3","projectRoot":"file:///work/shop"}`,
+		`{"id":2,"type":"vertex","label":"document","uri":"file:///work/shop/cart.go","languageId":"go"}`,
+		`{"id":3,"type":"vertex","label":"document","uri":"file:///work/shop/checkout.go","languageId":"go"}`,
+		`{"id":4,"type":"vertex","label":"range","start":{"line":9,"character":15},"end":{"line":9,"character":18}}`,
+		`{"id":5,"type":"vertex","label":"range","start":{"line":4,"character":6},"end":{"line":4,"character":9}}`,
+		`{"id":6,"type":"edge","label":"contains","outV":2,"inVs":[4]}`,
+		`{"id":7,"type":"edge","label":"contains","outV":3,"inVs":[5]}`,
+		`{"id":8,"type":"vertex","label":"resultSet"}`,
+		`{"id":9,"type":"edge","label":"next","outV":4,"inV":8}`,
+		`{"id":10,"type":"edge","label":"next","outV":5,"inV":8}`,
+		`{"id":11,"type":"vertex","label":"moniker","scheme":"gomod","identifier":"shop:Cart.Add","kind":"export"}`,
+		`{"id":12,"type":"edge","label":"moniker","outV":8,"inV":11}`,
+		`{"id":13,"type":"vertex","label":"definitionResult"}`,
+		`{"id":14,"type":"edge","label":"textDocument/definition","outV":8,"inV":13}`,
+		`{"id":15,"type":"edge","label":"item","outV":13,"inVs":[4],"document":2}`,
+		`{"id":16,"type":"vertex","label":"referenceResult"}`,
+		`{"id":17,"type":"edge","label":"textDocument/references","outV":8,"inV":16}`,
+		`{"id":18,"type":"edge","label":"item","outV":16,"inVs":[4],"document":2,"property":"definitions"}`,
+		`{"id":19,"type":"edge","label":"item","outV":16,"inVs":[5],"document":3,"property":"references"}`,
+	}, "\n")
+
+	path := filepath.Join(t.TempDir(), "dump.lsif")
+	require.NoError(t, os.WriteFile(path, []byte(dump), 0644))
+	index, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, FormatLSIF, index.Format)
+	assert.Equal(t, "/work/shop", index.Root)
+	require.Len(t, index.Symbols, 1)
+	symbol := index.Symbols["gomod:shop:Cart.Add"]
+	require.NotNil(t, symbol, "symbols are named by their moniker")
+	assert.Equal(t, []Occurrence{{File: "cart.go", Line: 10, Column: 15, Definition: true}}, symbol.Definitions())
+	assert.Equal(t, []Occurrence{{File: "checkout.go", Line: 5, Column: 6}}, symbol.References())
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatLSIF, DetectFormat([]byte("  {\"id\":1}")))
+	assert.Equal(t, FormatSCIP, DetectFormat([]byte{0x0a, 0x00}))
+	assert.Equal(t, "", DetectFormat([]byte("hello")))
+
+	path := filepath.Join(t.TempDir(), "index.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0644))
+	_, err := Load(path)
+	assert.Error(t, err)
+}
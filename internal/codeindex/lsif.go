@@ -0,0 +1,164 @@
+package codeindex
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/nuthan-ms/codecontext/internal/lsp"
+	"github.com/nuthan-ms/codecontext/internal/query"
+)
+
+// lsifID is a vertex or edge id, which LSIF allows to be a number or a string
+type lsifID string
+
+func (id *lsifID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = lsifID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("invalid id %s", data)
+	}
+	*id = lsifID(n.String())
+	return nil
+}
+
+// lsifElement holds the fields of the vertices and edges read from a dump
+type lsifElement struct {
+	ID    lsifID `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+
+	ProjectRoot string `json:"projectRoot"` // metaData
+	URI         string `json:"uri"`         // document
+	Start       *struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	} `json:"start"` // range
+	Scheme     string `json:"scheme"`     // moniker
+	Identifier string `json:"identifier"` // moniker
+
+	OutV     lsifID   `json:"outV"`
+	InV      lsifID   `json:"inV"`
+	InVs     []lsifID `json:"inVs"`
+	Document lsifID   `json:"document"` // item edges (0.4)
+	Shard    lsifID   `json:"shard"`    // item edges (0.6)
+	Property string   `json:"property"` // item edges: definitions or references
+}
+
+// addLSIF reads the definition and reference results of an LSIF dump. A
+// symbol is a result set, named by its moniker when it has one.
+func (i *Index) addLSIF(data []byte) error {
+	uris := make(map[lsifID]string)
+	ranges := make(map[lsifID]lsifElement)
+	rangeDocuments := make(map[lsifID]lsifID)
+	monikers := make(map[lsifID]string)
+	symbolMonikers := make(map[lsifID]lsifID)    // Result set -> moniker
+	definitionResults := make(map[lsifID]lsifID) // Result -> result set
+	referenceResults := make(map[lsifID]lsifID)
+	type item struct {
+		result   lsifID
+		document lsifID
+		ranges   []lsifID
+		property string
+	}
+	var items []item
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var element lsifElement
+		if err := json.Unmarshal(raw, &element); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if element.Type == "vertex" {
+			switch element.Label {
+			case "metaData":
+				i.Root = lsp.LocalPath(element.ProjectRoot)
+			case "document":
+				uris[element.ID] = element.URI
+			case "range":
+				ranges[element.ID] = element
+			case "moniker":
+				monikers[element.ID] = element.Scheme + ":" + element.Identifier
+			}
+			continue
+		}
+		switch element.Label {
+		case "contains":
+			for _, in := range element.InVs {
+				rangeDocuments[in] = element.OutV
+			}
+		case "moniker":
+			symbolMonikers[element.OutV] = element.InV
+		case "textDocument/definition":
+			definitionResults[element.InV] = element.OutV
+		case "textDocument/references":
+			referenceResults[element.InV] = element.OutV
+		case "item":
+			document := element.Document
+			if document == "" {
+				document = element.Shard
+			}
+			items = append(items, item{result: element.OutV, document: document, ranges: element.InVs, property: element.Property})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	// Definition results go first, so a definition also listed among the
+	// references of its symbol stays a definition
+	sort.SliceStable(items, func(a, b int) bool {
+		_, first := definitionResults[items[a].result]
+		_, second := definitionResults[items[b].result]
+		return first && !second
+	})
+	seen := make(map[string]bool)
+	for _, it := range items {
+		resultSet, definition := definitionResults[it.result]
+		if !definition {
+			var ok bool
+			if resultSet, ok = referenceResults[it.result]; !ok {
+				continue
+			}
+			definition = it.property == "definitions"
+		}
+		id := "lsif:" + string(resultSet)
+		if moniker, ok := monikers[symbolMonikers[resultSet]]; ok {
+			id = moniker
+		}
+		for _, rangeID := range it.ranges {
+			r, ok := ranges[rangeID]
+			if !ok || r.Start == nil {
+				continue
+			}
+			document := it.document
+			if document == "" {
+				document = rangeDocuments[rangeID]
+			}
+			path := lsp.LocalPath(uris[document])
+			if path == "" {
+				continue
+			}
+			occurrence := Occurrence{File: query.RelativePath(path, i.Root), Line: r.Start.Line + 1, Column: r.Start.Character, Definition: definition}
+			key := fmt.Sprintf("%s\x00%s:%d:%d", id, occurrence.File, occurrence.Line, occurrence.Column)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			symbol := i.symbol(id)
+			symbol.Occurrences = append(symbol.Occurrences, occurrence)
+		}
+	}
+	return nil
+}
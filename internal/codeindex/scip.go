@@ -0,0 +1,165 @@
+package codeindex
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/lsp"
+)
+
+// SCIP field numbers, from scip.proto
+const (
+	scipIndexMetadata         = 1
+	scipIndexDocuments        = 2
+	scipMetadataProjectRoot   = 3
+	scipDocumentRelativePath  = 1
+	scipDocumentOccurrences   = 2
+	scipOccurrenceRange       = 1
+	scipOccurrenceSymbol      = 2
+	scipOccurrenceSymbolRoles = 3
+	scipSymbolRoleDefinition  = 1
+)
+
+// Protobuf wire types
+const (
+	protoWireVarint          = 0
+	protoWireFixed64         = 1
+	protoWireLengthDelimited = 2
+	protoWireFixed32         = 5
+)
+
+// protoField is one field of an encoded protobuf message
+type protoField struct {
+	number int
+	wire   int
+	varint uint64
+	bytes  []byte
+}
+
+// protoFields decodes the fields of a protobuf message
+func protoFields(b []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("invalid field key")
+		}
+		b = b[n:]
+		field := protoField{number: int(key >> 3), wire: int(key & 7)}
+		switch field.wire {
+		case protoWireVarint:
+			field.varint, n = binary.Uvarint(b)
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid varint in field %d", field.number)
+			}
+			b = b[n:]
+		case protoWireFixed64, protoWireFixed32:
+			size := 8
+			if field.wire == protoWireFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return nil, fmt.Errorf("truncated field %d", field.number)
+			}
+			b = b[size:]
+		case protoWireLengthDelimited:
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return nil, fmt.Errorf("truncated field %d", field.number)
+			}
+			field.bytes = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d in field %d", field.wire, field.number)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// addSCIP reads the occurrences of a SCIP index. Local symbols only occur
+// within one document and are left out.
+func (i *Index) addSCIP(data []byte) error {
+	fields, err := protoFields(data)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		switch field.number {
+		case scipIndexMetadata:
+			metadata, err := protoFields(field.bytes)
+			if err != nil {
+				return fmt.Errorf("metadata: %w", err)
+			}
+			for _, m := range metadata {
+				if m.number == scipMetadataProjectRoot {
+					i.Root = lsp.LocalPath(string(m.bytes))
+				}
+			}
+		case scipIndexDocuments:
+			if err := i.addSCIPDocument(field.bytes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addSCIPDocument reads the occurrences of one document
+func (i *Index) addSCIPDocument(data []byte) error {
+	fields, err := protoFields(data)
+	if err != nil {
+		return fmt.Errorf("document: %w", err)
+	}
+	var path string
+	var occurrences [][]byte
+	for _, field := range fields {
+		switch field.number {
+		case scipDocumentRelativePath:
+			path = string(field.bytes)
+		case scipDocumentOccurrences:
+			occurrences = append(occurrences, field.bytes)
+		}
+	}
+
+	for _, data := range occurrences {
+		fields, err := protoFields(data)
+		if err != nil {
+			return fmt.Errorf("%s: occurrence: %w", path, err)
+		}
+		var symbol string
+		var ranges []uint64
+		var roles uint64
+		for _, field := range fields {
+			switch {
+			case field.number == scipOccurrenceSymbol:
+				symbol = string(field.bytes)
+			case field.number == scipOccurrenceSymbolRoles:
+				roles = field.varint
+			case field.number == scipOccurrenceRange && field.wire == protoWireVarint:
+				ranges = append(ranges, field.varint)
+			case field.number == scipOccurrenceRange:
+				// Packed repeated int32
+				for b := field.bytes; len(b) > 0; {
+					value, n := binary.Uvarint(b)
+					if n <= 0 {
+						return fmt.Errorf("%s: invalid occurrence range", path)
+					}
+					ranges = append(ranges, value)
+					b = b[n:]
+				}
+			}
+		}
+		if symbol == "" || strings.HasPrefix(symbol, "local ") || len(ranges) < 3 {
+			continue
+		}
+		i.symbol(symbol).Occurrences = append(i.symbol(symbol).Occurrences, Occurrence{
+			File:       path,
+			Line:       int(ranges[0]) + 1,
+			Column:     int(ranges[1]),
+			Definition: roles&scipSymbolRoleDefinition != 0,
+		})
+	}
+	return nil
+}
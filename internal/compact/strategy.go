@@ -556,6 +556,8 @@ func (bs *BaseStrategy) copyGraph(graph *types.CodeGraph) *types.CodeGraph {
 			IsTest:       file.IsTest,
 			IsGenerated:  file.IsGenerated,
 			LastModified: file.LastModified,
+			Coverage:     file.Coverage,
+			Mutants:      file.Mutants,
 			Symbols:      make([]types.SymbolId, len(file.Symbols)),
 			Imports:      make([]*types.Import, len(file.Imports)),
 		}
@@ -572,6 +574,8 @@ func (bs *BaseStrategy) copyGraph(graph *types.CodeGraph) *types.CodeGraph {
 			Location:  symbol.Location,
 			Signature: symbol.Signature,
 			Language:  symbol.Language,
+			Coverage:  symbol.Coverage,
+			Mutants:   symbol.Mutants,
 		}
 	}
 
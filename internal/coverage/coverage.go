@@ -0,0 +1,359 @@
+// Package coverage reads test coverage reports produced by external tools.
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Report formats
+const (
+	FormatGo        = "go"        // go test -coverprofile
+	FormatLcov      = "lcov"      // lcov tracefiles (Istanbul, c8, coverage.py, grcov, ...)
+	FormatCobertura = "cobertura" // Cobertura XML (coverage.py, gcovr, coverlet, Jest, ...)
+)
+
+// Function is the coverage of one function as listed in a report
+type Function struct {
+	Name string
+	Line int // First line
+	Hits int // Times the function was entered
+}
+
+// File is the line and function coverage of one source file
+type File struct {
+	Path      string      // As written in the report
+	Lines     map[int]int // Hit count per executable line
+	Functions []Function  // Functions listed by the report, if any
+}
+
+// Total returns the number of executable lines
+func (f *File) Total() int {
+	return len(f.Lines)
+}
+
+// Covered returns the number of executable lines hit at least once
+func (f *File) Covered() int {
+	covered := 0
+	for _, hits := range f.Lines {
+		if hits > 0 {
+			covered++
+		}
+	}
+	return covered
+}
+
+// Percent returns the share of covered lines, from 0 to 100. Files without
+// executable lines count as fully covered.
+func (f *File) Percent() float64 {
+	if f.Total() == 0 {
+		return 100
+	}
+	return float64(f.Covered()) * 100 / float64(f.Total())
+}
+
+// Range returns the number of executable and covered lines between start
+// and end, inclusive
+func (f *File) Range(start, end int) (total, covered int) {
+	for line, hits := range f.Lines {
+		if line >= start && line <= end {
+			total++
+			if hits > 0 {
+				covered++
+			}
+		}
+	}
+	return total, covered
+}
+
+// FunctionAt returns the reported function starting between start and end,
+// preferring one starting exactly at start, or nil
+func (f *File) FunctionAt(start, end int) *Function {
+	var found *Function
+	for i := range f.Functions {
+		fn := &f.Functions[i]
+		if fn.Line == start {
+			return fn
+		}
+		if found == nil && fn.Line > start && fn.Line <= end {
+			found = fn
+		}
+	}
+	return found
+}
+
+// addFunction records a function; functions reported several times keep
+// their highest count
+func (f *File) addFunction(name string, line, hits int) {
+	for i := range f.Functions {
+		if fn := &f.Functions[i]; fn.Name == name && (fn.Line == line || line == 0 || fn.Line == 0) {
+			if line != 0 {
+				fn.Line = line
+			}
+			if hits > fn.Hits {
+				fn.Hits = hits
+			}
+			return
+		}
+	}
+	f.Functions = append(f.Functions, Function{Name: name, Line: line, Hits: hits})
+}
+
+// Report is the coverage of the files listed in one or more reports
+type Report struct {
+	Files map[string]*File // By path as written in the reports
+}
+
+// NewReport returns an empty report
+func NewReport() *Report {
+	return &Report{Files: make(map[string]*File)}
+}
+
+// file returns the entry of path, creating it
+func (r *Report) file(path string) *File {
+	f := r.Files[path]
+	if f == nil {
+		f = &File{Path: path, Lines: make(map[int]int)}
+		r.Files[path] = f
+	}
+	return f
+}
+
+// addHits records hits on a line; lines reported several times keep their
+// highest count
+func (f *File) addHits(line, hits int) {
+	if current, ok := f.Lines[line]; !ok || hits > current {
+		f.Lines[line] = hits
+	}
+}
+
+// Load reads a coverage report, detecting its format from the content
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coverage report: %w", err)
+	}
+	report := NewReport()
+	if err := report.Add(data); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
+
+// LoadAll reads and merges several coverage reports
+func LoadAll(paths []string) (*Report, error) {
+	report := NewReport()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read coverage report: %w", err)
+		}
+		if err := report.Add(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return report, nil
+}
+
+// Add merges the content of a report in any supported format
+func (r *Report) Add(data []byte) error {
+	switch format := DetectFormat(data); format {
+	case FormatGo:
+		return r.addGo(data)
+	case FormatLcov:
+		return r.addLcov(data)
+	case FormatCobertura:
+		return r.addCobertura(data)
+	}
+	return fmt.Errorf("unrecognized coverage format (supported: go coverprofile, lcov, cobertura)")
+}
+
+// DetectFormat returns the format of a report, or "" when unknown
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("mode:")):
+		return FormatGo
+	case bytes.HasPrefix(trimmed, []byte("TN:")), bytes.HasPrefix(trimmed, []byte("SF:")):
+		return FormatLcov
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		// Cobertura's root element follows the XML declaration and DOCTYPE
+		head := trimmed[:min(len(trimmed), 1024)]
+		if bytes.Contains(head, []byte("<coverage")) {
+			return FormatCobertura
+		}
+	}
+	return ""
+}
+
+// addGo parses "file:startLine.startCol,endLine.endCol statements count" blocks
+func (r *Report) addGo(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		colon := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[colon+1:])
+		if colon < 0 || len(fields) != 3 {
+			return fmt.Errorf("invalid coverprofile line %q", line)
+		}
+		var startLine, startCol, endLine, endCol int
+		if _, err := fmt.Sscanf(fields[0], "%d.%d,%d.%d", &startLine, &startCol, &endLine, &endCol); err != nil {
+			return fmt.Errorf("invalid coverprofile block %q", fields[0])
+		}
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return fmt.Errorf("invalid coverprofile count %q", fields[2])
+		}
+		f := r.file(line[:colon])
+		for l := startLine; l <= endLine; l++ {
+			f.addHits(l, count)
+		}
+	}
+	return scanner.Err()
+}
+
+// addLcov parses the SF (source file), DA (line hits), FN (function) and FNDA
+// (function hits) records of a tracefile
+func (r *Report) addLcov(data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var current *File
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			current = r.file(strings.TrimPrefix(line, "SF:"))
+		case strings.HasPrefix(line, "DA:") && current != nil:
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				return fmt.Errorf("invalid lcov line %q", line)
+			}
+			number, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.Atoi(fields[1])
+			if err1 != nil || err2 != nil {
+				return fmt.Errorf("invalid lcov line %q", line)
+			}
+			current.addHits(number, hits)
+		case strings.HasPrefix(line, "FN:") && current != nil:
+			// FN:<line>,<name>, or FN:<line>,<end line>,<name> since lcov 2.0
+			fields := strings.Split(strings.TrimPrefix(line, "FN:"), ",")
+			number, err := strconv.Atoi(fields[0])
+			if len(fields) < 2 || err != nil {
+				return fmt.Errorf("invalid lcov line %q", line)
+			}
+			current.addFunction(fields[len(fields)-1], number, 0)
+		case strings.HasPrefix(line, "FNDA:") && current != nil:
+			hits, name, ok := strings.Cut(strings.TrimPrefix(line, "FNDA:"), ",")
+			count, err := strconv.Atoi(hits)
+			if !ok || err != nil {
+				return fmt.Errorf("invalid lcov line %q", line)
+			}
+			current.addFunction(name, 0, count)
+		case line == "end_of_record":
+			current = nil
+		}
+	}
+	return scanner.Err()
+}
+
+// coberturaReport is the part of a Cobertura report read here
+type coberturaReport struct {
+	Sources []string `xml:"sources>source"`
+	Classes []struct {
+		Filename string          `xml:"filename,attr"`
+		Lines    []coberturaLine `xml:"lines>line"`
+		Methods  []struct {
+			Name  string          `xml:"name,attr"`
+			Lines []coberturaLine `xml:"lines>line"`
+		} `xml:"methods>method"`
+	} `xml:"packages>package>classes>class"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// addCobertura parses the classes of a Cobertura report. File names are
+// relative to the report's source directory; with a single source they are
+// joined to it.
+func (r *Report) addCobertura(data []byte) error {
+	var report coberturaReport
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return fmt.Errorf("invalid cobertura report: %w", err)
+	}
+	for _, class := range report.Classes {
+		path := class.Filename
+		if len(report.Sources) == 1 && !filepath.IsAbs(path) {
+			path = filepath.Join(strings.TrimSpace(report.Sources[0]), path)
+		}
+		f := r.file(path)
+		for _, line := range class.Lines {
+			f.addHits(line.Number, line.Hits)
+		}
+		for _, method := range class.Methods {
+			first, hits := 0, 0
+			for _, line := range method.Lines {
+				f.addHits(line.Number, line.Hits)
+				if first == 0 || line.Number < first {
+					first, hits = line.Number, line.Hits
+				}
+			}
+			f.addFunction(method.Name, first, hits)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the coverage of a file given its path relative to root.
+// Report paths may be absolute, relative to the project or prefixed with a
+// module path (Go import paths); among report paths ending in rel, the
+// shortest wins.
+func (r *Report) Lookup(root, rel string) *File {
+	paths := make([]string, 0, len(r.Files))
+	for path := range r.Files {
+		paths = append(paths, path)
+	}
+	if path, ok := matchPath(paths, root, rel); ok {
+		return r.Files[path]
+	}
+	return nil
+}
+
+// matchPath finds the report path naming the file at rel under root: the
+// absolute path, rel itself or the shortest path ending in "/"+rel
+func matchPath(paths []string, root, rel string) (string, bool) {
+	rel = filepath.ToSlash(rel)
+	abs, err := filepath.Abs(filepath.Join(root, rel))
+	var suffixed []string
+	for _, path := range paths {
+		slashed := filepath.ToSlash(path)
+		if (err == nil && path == abs) || slashed == rel {
+			return path, true
+		}
+		if strings.HasSuffix(slashed, "/"+rel) {
+			suffixed = append(suffixed, path)
+		}
+	}
+	if len(suffixed) == 0 {
+		return "", false
+	}
+	sort.Slice(suffixed, func(i, j int) bool {
+		if len(suffixed[i]) != len(suffixed[j]) {
+			return len(suffixed[i]) < len(suffixed[j])
+		}
+		return suffixed[i] < suffixed[j]
+	})
+	return suffixed[0], true
+}
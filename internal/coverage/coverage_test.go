@@ -0,0 +1,106 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoCoverprofile(t *testing.T) {
+	report := NewReport()
+	require.NoError(t, report.Add([]byte(`mode: count
+example.com/shop/orders/order.go:3.20,5.2 2 4
+example.com/shop/orders/order.go:7.20,9.2 2 0
+example.com/shop/orders/order.go:5.2,5.10 1 0
+`)))
+	file := report.Lookup("/src/shop", "orders/order.go")
+	require.NotNil(t, file)
+	assert.Equal(t, 6, file.Total())
+	assert.Equal(t, 3, file.Covered(), "overlapping blocks keep the highest count")
+	assert.Equal(t, 50.0, file.Percent())
+	assert.Nil(t, report.Lookup("/src/shop", "der.go"), "suffixes match whole path segments")
+}
+
+func TestLcov(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "lcov.info")
+	require.NoError(t, os.WriteFile(path, []byte(`TN:
+SF:src/app.ts
+DA:1,1
+DA:2,0
+DA:3,5
+end_of_record
+SF:`+filepath.Join(dir, "src/util.ts")+`
+DA:1,0
+end_of_record
+`), 0644))
+
+	report, err := Load(path)
+	require.NoError(t, err)
+	assert.InDelta(t, 66.7, report.Lookup(dir, "src/app.ts").Percent(), 0.1)
+	assert.Equal(t, 0.0, report.Lookup(dir, "src/util.ts").Percent(), "absolute paths are matched too")
+	assert.Nil(t, report.Lookup(dir, "src/missing.ts"))
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatGo, DetectFormat([]byte("mode: set\n")))
+	assert.Equal(t, FormatLcov, DetectFormat([]byte("\nSF:a.js\n")))
+	assert.Equal(t, FormatCobertura, DetectFormat([]byte("<?xml version=\"1.0\"?>\n<coverage>")))
+	assert.Equal(t, "", DetectFormat([]byte("<report name=\"jacoco\"/>")))
+	assert.Error(t, NewReport().Add([]byte("{}")))
+}
+
+func TestLcovFunctions(t *testing.T) {
+	report := NewReport()
+	require.NoError(t, report.Add([]byte(`SF:src/app.ts
+FN:3,start
+FN:10,15,stop
+FNDA:2,start
+FNDA:0,stop
+DA:3,2
+DA:4,2
+DA:11,0
+end_of_record
+`)))
+	file := report.Lookup("/repo", "src/app.ts")
+	require.NotNil(t, file)
+	assert.Equal(t, []Function{{Name: "start", Line: 3, Hits: 2}, {Name: "stop", Line: 10, Hits: 0}}, file.Functions)
+	assert.Equal(t, "stop", file.FunctionAt(9, 15).Name)
+	assert.Nil(t, file.FunctionAt(5, 8))
+
+	total, covered := file.Range(3, 9)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 2, covered)
+}
+
+func TestCobertura(t *testing.T) {
+	report := NewReport()
+	require.NoError(t, report.Add([]byte(`<?xml version="1.0" ?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.5" version="7.3">
+	<sources><source>/ci/build/src</source></sources>
+	<packages><package name="shop"><classes>
+		<class name="orders.py" filename="shop/orders.py">
+			<methods>
+				<method name="total" signature="()"><lines><line number="5" hits="3"/><line number="4" hits="3"/></lines></method>
+				<method name="refund" signature="()"><lines><line number="9" hits="0"/></lines></method>
+			</methods>
+			<lines>
+				<line number="1" hits="1"/>
+				<line number="4" hits="3"/>
+				<line number="5" hits="3"/>
+				<line number="9" hits="0"/>
+			</lines>
+		</class>
+	</classes></package></packages>
+</coverage>
+`)))
+	file := report.Lookup("/home/dev/shop", "src/shop/orders.py")
+	require.NotNil(t, file, "the source directory is joined to file names")
+	assert.Equal(t, 4, file.Total())
+	assert.Equal(t, 3, file.Covered())
+	assert.Equal(t, []Function{{Name: "total", Line: 4, Hits: 3}, {Name: "refund", Line: 9, Hits: 0}}, file.Functions)
+}
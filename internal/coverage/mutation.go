@@ -0,0 +1,298 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mutant outcomes
+const (
+	MutantKilled     = "killed"      // A test failed, or timed out, with the mutant
+	MutantSurvived   = "survived"    // Tests ran the mutated code and still passed
+	MutantNoCoverage = "no_coverage" // No test ran the mutated code
+	MutantIgnored    = "ignored"     // Not tested: compile errors, skipped mutants
+)
+
+// Mutant is one mutation of the code and how the tests fared against it
+type Mutant struct {
+	File    string // As written in the report
+	Line    int    // 0 when the report gives no location
+	Mutator string
+	Status  string
+}
+
+// MutationFormat reads the results of one mutation testing tool
+type MutationFormat interface {
+	// Name identifies the tool, e.g. "stryker"
+	Name() string
+
+	// Detect reports whether data was written by the tool
+	Detect(data []byte) bool
+
+	// Parse returns the mutants of a report
+	Parse(data []byte) ([]Mutant, error)
+}
+
+var (
+	mutationFormatsMu sync.RWMutex
+	mutationFormats   = []MutationFormat{strykerFormat{}, goMutestingJSONFormat{}, goMutestingFormat{}}
+)
+
+// RegisterMutationFormat adds support for another mutation testing tool.
+// Formats are tried in registration order after the built-in ones (Stryker
+// and go-mutesting). It panics if the name is empty or already registered.
+func RegisterMutationFormat(format MutationFormat) {
+	if format == nil || format.Name() == "" {
+		panic("coverage: RegisterMutationFormat format has no name")
+	}
+	mutationFormatsMu.Lock()
+	defer mutationFormatsMu.Unlock()
+	for _, registered := range mutationFormats {
+		if registered.Name() == format.Name() {
+			panic("coverage: RegisterMutationFormat called twice for " + format.Name())
+		}
+	}
+	mutationFormats = append(mutationFormats, format)
+}
+
+// MutationReport holds the mutants of one or more mutation testing reports
+type MutationReport struct {
+	Mutants map[string][]Mutant // By path as written in the reports
+}
+
+// NewMutationReport returns an empty report
+func NewMutationReport() *MutationReport {
+	return &MutationReport{Mutants: make(map[string][]Mutant)}
+}
+
+// LoadMutations reads and merges mutation testing reports, detecting the tool
+// that wrote each one
+func LoadMutations(paths []string) (*MutationReport, error) {
+	report := NewMutationReport()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mutation report: %w", err)
+		}
+		if err := report.Add(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return report, nil
+}
+
+// Add merges a report written by any registered tool
+func (r *MutationReport) Add(data []byte) error {
+	mutationFormatsMu.RLock()
+	formats := append([]MutationFormat(nil), mutationFormats...)
+	mutationFormatsMu.RUnlock()
+
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, format.Name())
+		if !format.Detect(data) {
+			continue
+		}
+		mutants, err := format.Parse(data)
+		if err != nil {
+			return fmt.Errorf("invalid %s report: %w", format.Name(), err)
+		}
+		for _, mutant := range mutants {
+			r.Mutants[mutant.File] = append(r.Mutants[mutant.File], mutant)
+		}
+		return nil
+	}
+	return fmt.Errorf("unrecognized mutation report (supported: %s)", strings.Join(names, ", "))
+}
+
+// Lookup returns the mutants of a file given its path relative to root,
+// matching report paths like coverage reports do
+func (r *MutationReport) Lookup(root, rel string) []Mutant {
+	paths := make([]string, 0, len(r.Mutants))
+	for path := range r.Mutants {
+		paths = append(paths, path)
+	}
+	if path, ok := matchPath(paths, root, rel); ok {
+		return r.Mutants[path]
+	}
+	return nil
+}
+
+// strykerFormat reads the mutation-testing-report-schema JSON written by
+// Stryker (JavaScript, C#, Scala) and other tools sharing its schema
+type strykerFormat struct{}
+
+func (strykerFormat) Name() string { return "stryker" }
+
+func (strykerFormat) Detect(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return bytes.HasPrefix(trimmed, []byte("{")) && bytes.Contains(data, []byte(`"schemaVersion"`))
+}
+
+func (strykerFormat) Parse(data []byte) ([]Mutant, error) {
+	var report struct {
+		Files map[string]struct {
+			Mutants []struct {
+				MutatorName string `json:"mutatorName"`
+				Status      string `json:"status"`
+				Location    struct {
+					Start struct {
+						Line int `json:"line"`
+					} `json:"start"`
+				} `json:"location"`
+			} `json:"mutants"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	var mutants []Mutant
+	for path, file := range report.Files {
+		for _, m := range file.Mutants {
+			status := MutantIgnored
+			switch m.Status {
+			case "Killed", "Timeout":
+				status = MutantKilled
+			case "Survived":
+				status = MutantSurvived
+			case "NoCoverage":
+				status = MutantNoCoverage
+			}
+			mutants = append(mutants, Mutant{File: path, Line: m.Location.Start.Line, Mutator: m.MutatorName, Status: status})
+		}
+	}
+	sort.SliceStable(mutants, func(i, j int) bool { return mutants[i].File < mutants[j].File })
+	return mutants, nil
+}
+
+// goMutestingJSONFormat reads the report.json written by go-mutesting forks
+// (avast/go-mutesting)
+type goMutestingJSONFormat struct{}
+
+func (goMutestingJSONFormat) Name() string { return "go-mutesting-json" }
+
+func (goMutestingJSONFormat) Detect(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return bytes.HasPrefix(trimmed, []byte("{")) && bytes.Contains(data, []byte(`"escaped"`))
+}
+
+func (goMutestingJSONFormat) Parse(data []byte) ([]Mutant, error) {
+	type entry struct {
+		Mutator struct {
+			MutatorName       string `json:"mutatorName"`
+			OriginalFilePath  string `json:"originalFilePath"`
+			OriginalStartLine int    `json:"originalStartLine"`
+		} `json:"mutator"`
+	}
+	var report struct {
+		Escaped    []entry `json:"escaped"`
+		Killed     []entry `json:"killed"`
+		Timeouted  []entry `json:"timeouted"`
+		NotCovered []entry `json:"notCovered"`
+		Errored    []entry `json:"errored"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	var mutants []Mutant
+	add := func(entries []entry, status string) {
+		for _, e := range entries {
+			mutants = append(mutants, Mutant{
+				File:    e.Mutator.OriginalFilePath,
+				Line:    e.Mutator.OriginalStartLine,
+				Mutator: e.Mutator.MutatorName,
+				Status:  status,
+			})
+		}
+	}
+	add(report.Escaped, MutantSurvived)
+	add(report.Killed, MutantKilled)
+	add(report.Timeouted, MutantKilled)
+	add(report.NotCovered, MutantNoCoverage)
+	add(report.Errored, MutantIgnored)
+	return mutants, nil
+}
+
+// goMutestingFormat reads the console output of go-mutesting: a PASS (killed),
+// FAIL (survived) or SKIP line per mutant, surviving mutants preceded by their
+// diff
+type goMutestingFormat struct{}
+
+// goMutestingResultPattern matches `PASS "/tmp/go-mutesting-123/pkg/file.go.4" with checksum ...`
+var goMutestingResultPattern = regexp.MustCompile(`^(PASS|FAIL|SKIP) "(.+)\.\d+" with checksum`)
+
+// goMutestingHunkPattern matches unified diff hunk headers
+var goMutestingHunkPattern = regexp.MustCompile(`^@@ -(\d+)`)
+
+func (goMutestingFormat) Name() string { return "go-mutesting" }
+
+func (goMutestingFormat) Detect(data []byte) bool {
+	return bytes.Contains(data, []byte(`" with checksum `)) && bytes.Contains(data, []byte("go-mutesting"))
+}
+
+func (goMutestingFormat) Parse(data []byte) ([]Mutant, error) {
+	var mutants []Mutant
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	// line is the first changed line of the latest diff, -1 outside hunks
+	line, changed := -1, 0
+	for scanner.Scan() {
+		text := scanner.Text()
+		if m := goMutestingHunkPattern.FindStringSubmatch(text); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			changed = 0
+			continue
+		}
+		if m := goMutestingResultPattern.FindStringSubmatch(text); m != nil {
+			mutant := Mutant{File: stripMutestingDir(m[2])}
+			switch m[1] {
+			case "PASS":
+				mutant.Status = MutantKilled
+			case "FAIL":
+				mutant.Status = MutantSurvived
+				if changed > 0 {
+					mutant.Line = changed
+				}
+			default:
+				mutant.Status = MutantIgnored
+			}
+			mutants = append(mutants, mutant)
+			line, changed = -1, 0
+			continue
+		}
+		if line < 0 || changed > 0 || text == "" {
+			continue
+		}
+		switch text[0] {
+		case '-', '+':
+			changed = line
+		case ' ':
+			line++
+		}
+	}
+	return mutants, scanner.Err()
+}
+
+// stripMutestingDir removes the temporary directory go-mutesting copies
+// mutated files to, keeping the original path
+func stripMutestingDir(path string) string {
+	index := strings.Index(path, "go-mutesting-")
+	if index < 0 {
+		return path
+	}
+	rest := path[index:]
+	slash := strings.Index(rest, "/")
+	if slash < 0 {
+		return path
+	}
+	// The original path follows, absolute ("go-mutesting-123//home/...") or relative
+	return rest[slash+1:]
+}
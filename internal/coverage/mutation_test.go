@@ -0,0 +1,99 @@
+package coverage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrykerReport(t *testing.T) {
+	report := NewMutationReport()
+	require.NoError(t, report.Add([]byte(`{
+  "schemaVersion": "1",
+  "thresholds": {"high": 80, "low": 60},
+  "files": {
+    "src/cart.ts": {
+      "language": "typescript",
+      "mutants": [
+        {"id": "1", "mutatorName": "ConditionalExpression", "status": "Killed", "location": {"start": {"line": 4, "column": 7}, "end": {"line": 4, "column": 20}}},
+        {"id": "2", "mutatorName": "ArithmeticOperator", "status": "Survived", "location": {"start": {"line": 9, "column": 3}, "end": {"line": 9, "column": 8}}},
+        {"id": "3", "mutatorName": "StringLiteral", "status": "NoCoverage", "location": {"start": {"line": 12, "column": 1}, "end": {"line": 12, "column": 5}}},
+        {"id": "4", "mutatorName": "BlockStatement", "status": "CompileError", "location": {"start": {"line": 14, "column": 1}, "end": {"line": 14, "column": 5}}}
+      ]
+    }
+  }
+}`)))
+	assert.Equal(t, []Mutant{
+		{File: "src/cart.ts", Line: 4, Mutator: "ConditionalExpression", Status: MutantKilled},
+		{File: "src/cart.ts", Line: 9, Mutator: "ArithmeticOperator", Status: MutantSurvived},
+		{File: "src/cart.ts", Line: 12, Mutator: "StringLiteral", Status: MutantNoCoverage},
+		{File: "src/cart.ts", Line: 14, Mutator: "BlockStatement", Status: MutantIgnored},
+	}, report.Lookup("/repo", "src/cart.ts"))
+}
+
+func TestGoMutestingReports(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		report := NewMutationReport()
+		require.NoError(t, report.Add([]byte(`{
+  "stats": {"totalMutantsCount": 2},
+  "escaped": [{"mutator": {"mutatorName": "branch/if", "originalFilePath": "orders/order.go", "originalStartLine": 12}}],
+  "killed": [{"mutator": {"mutatorName": "expression/remove", "originalFilePath": "orders/order.go", "originalStartLine": 20}}],
+  "timeouted": null,
+  "notCovered": null,
+  "errored": null
+}`)))
+		assert.Equal(t, []Mutant{
+			{File: "orders/order.go", Line: 12, Mutator: "branch/if", Status: MutantSurvived},
+			{File: "orders/order.go", Line: 20, Mutator: "expression/remove", Status: MutantKilled},
+		}, report.Lookup("/src/shop", "orders/order.go"))
+	})
+
+	t.Run("console", func(t *testing.T) {
+		report := NewMutationReport()
+		require.NoError(t, report.Add([]byte(`--- orders/order.go	2024-01-01 10:00:00.000000000 +0000
++++ /tmp/go-mutesting-123//src/shop/orders/order.go.0	2024-01-01 10:00:01.000000000 +0000
+@@ -10,7 +10,7 @@
+ func Total(items []Item) int {
+ 	sum := 0
+ 	for _, item := range items {
+-		sum += item.Price
++		_ = item.Price
+ 	}
+ 	return sum
+ }
+FAIL "/tmp/go-mutesting-123//src/shop/orders/order.go.0" with checksum 0d2a6ea4b7c1
+PASS "/tmp/go-mutesting-123//src/shop/orders/order.go.1" with checksum 4e8f0c1a2b3d
+SKIP "/tmp/go-mutesting-123//src/shop/orders/order.go.2" with checksum 9a8b7c6d5e4f
+The mutation score is 0.500000 (1 passed, 1 failed, 0 duplicated, 1 skipped, total is 3)
+`)))
+		mutants := report.Lookup("/src/shop", "orders/order.go")
+		require.Len(t, mutants, 3)
+		assert.Equal(t, Mutant{File: "/src/shop/orders/order.go", Line: 13, Status: MutantSurvived}, mutants[0], "the line comes from the diff")
+		assert.Equal(t, MutantKilled, mutants[1].Status)
+		assert.Equal(t, MutantIgnored, mutants[2].Status)
+	})
+}
+
+// survivorListFormat is a stand-in for a project-specific mutation tool
+type survivorListFormat struct{}
+
+func (survivorListFormat) Name() string { return "test-survivors" }
+
+func (survivorListFormat) Detect(data []byte) bool { return bytes.HasPrefix(data, []byte("SURVIVOR ")) }
+
+func (survivorListFormat) Parse(data []byte) ([]Mutant, error) {
+	return []Mutant{{File: string(bytes.TrimSpace(bytes.TrimPrefix(data, []byte("SURVIVOR ")))), Status: MutantSurvived}}, nil
+}
+
+func TestRegisterMutationFormat(t *testing.T) {
+	report := NewMutationReport()
+	assert.ErrorContains(t, report.Add([]byte("SURVIVOR lib/a.rb\n")), "unrecognized mutation report")
+
+	RegisterMutationFormat(survivorListFormat{})
+	require.NoError(t, report.Add([]byte("SURVIVOR lib/a.rb\n")))
+	assert.Len(t, report.Lookup("/app", "lib/a.rb"), 1)
+
+	assert.Panics(t, func() { RegisterMutationFormat(survivorListFormat{}) })
+}
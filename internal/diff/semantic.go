@@ -343,8 +343,10 @@ func (sd *SemanticDiffer) isBreakingSignatureChange(oldSymbol, newSymbol *types.
 func (sd *SemanticDiffer) isBreakingVisibilityChange(oldSymbol, newSymbol *types.Symbol) bool {
 	visibilityOrder := map[string]int{
 		"private":   0,
-		"protected": 1,
-		"public":    2,
+		"package":   1,
+		"internal":  1,
+		"protected": 2,
+		"public":    3,
 	}
 
 	oldLevel, okOld := visibilityOrder[oldSymbol.Visibility]
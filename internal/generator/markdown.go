@@ -47,8 +47,8 @@ func (mg *MarkdownGenerator) Generate(graph *types.CodeGraph) (string, error) {
 		sb.WriteString("## Symbols\n\n")
 		for _, symbol := range graph.Symbols {
 			sb.WriteString(fmt.Sprintf("### %s\n\n", symbol.Name))
-			sb.WriteString(fmt.Sprintf("- **Type:** %s\n", symbol.Kind))
-			sb.WriteString(fmt.Sprintf("- **File:** %s\n", symbol.FullyQualifiedName))
+			sb.WriteString(fmt.Sprintf("- **Type:** %s\n", symbol.NormalizedKind()))
+			sb.WriteString(fmt.Sprintf("- **File:** %s\n", types.FilePathFromQualifiedName(symbol.FullyQualifiedName)))
 			if symbol.Documentation != "" {
 				sb.WriteString(fmt.Sprintf("- **Documentation:** %s\n", symbol.Documentation))
 			}
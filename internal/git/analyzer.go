@@ -182,11 +182,7 @@ func (g *GitAnalyzer) GetChangeFrequency(days int) (map[string]int, error) {
 
 // GetLastModified returns the last modification time for each file
 func (g *GitAnalyzer) GetLastModified() (map[string]time.Time, error) {
-	cmd := exec.Command(g.gitPath, "log", "--name-only", "--pretty=format:%at", "-1")
-	cmd.Dir = g.repoPath
-
-	// Get individual file last modified times
-	cmd = exec.Command(g.gitPath, "ls-files", "-z")
+	cmd := exec.Command(g.gitPath, "ls-files", "-z")
 	cmd.Dir = g.repoPath
 	
 	output, err := cmd.Output()
@@ -194,25 +190,35 @@ func (g *GitAnalyzer) GetLastModified() (map[string]time.Time, error) {
 		return nil, fmt.Errorf("failed to list files: %w", err)
 	}
 
-	files := strings.Split(string(output), "\000")
-	result := make(map[string]time.Time)
-	
-	for _, file := range files {
-		if file == "" {
-			continue
+	tracked := make(map[string]bool)
+	for _, file := range strings.Split(string(output), "\000") {
+		if file != "" {
+			tracked[file] = true
 		}
-		
-		// Get last commit for this file
-		cmd := exec.Command(g.gitPath, "log", "-1", "--pretty=format:%at", "--", file)
-		cmd.Dir = g.repoPath
-		
-		output, err := cmd.Output()
+	}
+
+	// A single pass over the history, newest first: the first commit listing
+	// a file is its last modification
+	cmd = exec.Command(g.gitPath, "-c", "core.quotePath=false", "log", "--name-only", "--relative", "--pretty=format:%x1e%at", "--", ".")
+	cmd.Dir = g.repoPath
+	
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get git log: %w", err)
+	}
+
+	result := make(map[string]time.Time)
+	for _, record := range strings.Split(string(output), "\x1e") {
+		lines := strings.Split(record, "\n")
+		timestamp, err := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
 		if err != nil {
-			continue // Skip files that can't be queried
+			continue
 		}
-		
-		if timestamp, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64); err == nil {
-			result[file] = time.Unix(timestamp, 0)
+		for _, file := range lines[1:] {
+			file = strings.TrimSpace(file)
+			if _, seen := result[file]; tracked[file] && !seen {
+				result[file] = time.Unix(timestamp, 0)
+			}
 		}
 	}
 
@@ -338,6 +344,26 @@ func (g *GitAnalyzer) GetBranchInfo() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetHeadCommit returns the hash of the checked-out commit
+func (g *GitAnalyzer) GetHeadCommit() (string, error) {
+	output, err := g.ExecuteGitCommand(context.Background(), "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetHeadTags returns the tags pointing at the checked-out commit
+func (g *GitAnalyzer) GetHeadTags() ([]string, error) {
+	output, err := g.ExecuteGitCommand(context.Background(), "tag", "--points-at", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	
+	return strings.Fields(string(output)), nil
+}
+
 // GetRemoteInfo returns remote repository information
 func (g *GitAnalyzer) GetRemoteInfo() (string, error) {
 	output, err := g.ExecuteGitCommand(context.Background(), "remote", "get-url", "origin")
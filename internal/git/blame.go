@@ -0,0 +1,35 @@
+package git
+
+import (
+	"context"
+	"strings"
+)
+
+// BlameLines returns the commit that last changed each line of file, a path
+// relative to the analyzer's directory, as it is in the working tree. Lines
+// not committed yet are attributed to the all-zero commit.
+func (g *GitAnalyzer) BlameLines(ctx context.Context, file string) ([]string, error) {
+	output, err := g.ExecuteGitCommand(ctx, "blame", "--porcelain", "--", file)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlamePorcelain(string(output)), nil
+}
+
+// parseBlamePorcelain reads the commit of each line from git blame
+// --porcelain output, where every line is introduced by a header holding
+// its commit, original and final line numbers
+func parseBlamePorcelain(output string) []string {
+	var commits []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			continue // Content of the line
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || len(fields[0]) != 40 || strings.Trim(fields[0], "0123456789abcdef") != "" {
+			continue
+		}
+		commits = append(commits, fields[0])
+	}
+	return commits
+}
@@ -0,0 +1,17 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBlamePorcelain(t *testing.T) {
+	first := strings.Repeat("a", 40)
+	second := strings.Repeat("0", 40)
+	output := first + " 1 1 2\nauthor Ana\nsummary init\nfilename main.go\n\tpackage main\n" +
+		first + " 2 2\n\t" + second + " 9 9\n" +
+		second + " 3 3 1\nauthor Not Committed Yet\nsummary Version of main.go from main.go\nfilename main.go\n\tfunc main() {}\n"
+	assert.Equal(t, []string{first, first, second}, parseBlamePorcelain(output))
+}
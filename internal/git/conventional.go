@@ -0,0 +1,211 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// conventionalHeaderPattern matches "type(scope)!: description"
+var conventionalHeaderPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// ConventionalCommit is a commit message following the Conventional Commits
+// format (https://www.conventionalcommits.org)
+type ConventionalCommit struct {
+	Type        string // Lower-cased, e.g. "feat", "fix", "docs"
+	Scope       string
+	Breaking    bool // "!" after the type or a BREAKING CHANGE footer
+	Description string
+}
+
+// ParseConventionalCommit parses the header of a commit message, and its
+// footers when the message has a body. ok is false for other messages.
+func ParseConventionalCommit(message string) (ConventionalCommit, bool) {
+	header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	m := conventionalHeaderPattern.FindStringSubmatch(strings.TrimSpace(header))
+	if m == nil {
+		return ConventionalCommit{}, false
+	}
+	commit := ConventionalCommit{
+		Type:        strings.ToLower(m[1]),
+		Scope:       strings.TrimSpace(m[2]),
+		Breaking:    m[3] == "!",
+		Description: m[4],
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			commit.Breaking = true
+		}
+	}
+	return commit, true
+}
+
+// GetCommitsBetween returns the commits reachable from to but not from, with
+// their full messages and the files they changed relative to the analyzer's
+// directory. Only commits touching that directory are listed. An empty to
+// means HEAD.
+func (g *GitAnalyzer) GetCommitsBetween(ctx context.Context, from, to string) ([]CommitInfo, error) {
+	if to == "" {
+		to = "HEAD"
+	}
+	for _, ref := range []string{from, to} {
+		if ref == "" || strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("invalid git ref %q", ref)
+		}
+	}
+	return g.logCommits(ctx, from+".."+to)
+}
+
+// logCommits runs git log over the revisions with full messages and the
+// files changed relative to the analyzer's directory, limited to commits
+// touching it
+func (g *GitAnalyzer) logCommits(ctx context.Context, revisions ...string) ([]CommitInfo, error) {
+	// Records start with RS and fields are separated by US, since full
+	// messages may contain any other character
+	args := []string{"log", "--no-merges", "--name-only", "--relative", "--pretty=format:%x1e%H%x1f%an%x1f%ae%x1f%at%x1f%B%x1f"}
+	args = append(append(args, revisions...), "--", ".")
+	output, err := g.ExecuteGitCommand(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []CommitInfo
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(record, "\x1f")
+		if len(fields) < 6 {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(fields[3], 10, 64)
+		commit := CommitInfo{
+			Hash:      fields[0],
+			Author:    fields[1],
+			Email:     fields[2],
+			Timestamp: time.Unix(timestamp, 0),
+			Message:   strings.TrimSpace(fields[4]),
+			Files:     []string{},
+		}
+		for _, file := range strings.Split(fields[5], "\n") {
+			if file = strings.TrimSpace(file); file != "" {
+				commit.Files = append(commit.Files, file)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// Activity labels derived from the conventional commit types of an area
+const (
+	LabelFixHeavy      = "fix-heavy area"
+	LabelFeatureActive = "feature-active area"
+	LabelRefactoring   = "refactoring area"
+	LabelMaintenance   = "maintenance area"
+)
+
+// minLabeledCommits is the number of conventional commits needed for a label
+const minLabeledCommits = 3
+
+// maintenanceTypes are conventional commit types that do not change behavior
+var maintenanceTypes = map[string]bool{
+	"docs": true, "test": true, "tests": true, "chore": true, "ci": true, "build": true, "style": true,
+}
+
+// IsMaintenanceType reports whether a conventional commit type leaves the
+// behavior unchanged, such as docs, test or chore
+func IsMaintenanceType(commitType string) bool {
+	return maintenanceTypes[commitType]
+}
+
+// CommitTypeStats counts commits by conventional commit type and scope
+type CommitTypeStats struct {
+	Total        int            `json:"total"`
+	Conventional int            `json:"conventional"` // Commits following the format
+	Breaking     int            `json:"breaking"`
+	Types        map[string]int `json:"types"`
+	Scopes       map[string]int `json:"scopes,omitempty"`
+}
+
+// ClassifyCommits counts the commits by conventional commit type and scope
+func ClassifyCommits(commits []CommitInfo) CommitTypeStats {
+	stats := CommitTypeStats{Types: make(map[string]int), Scopes: make(map[string]int)}
+	for _, commit := range commits {
+		stats.Total++
+		conventional, ok := ParseConventionalCommit(commit.Message)
+		if !ok {
+			continue
+		}
+		stats.Conventional++
+		stats.Types[conventional.Type]++
+		if conventional.Scope != "" {
+			stats.Scopes[conventional.Scope]++
+		}
+		if conventional.Breaking {
+			stats.Breaking++
+		}
+	}
+	return stats
+}
+
+// SortedTypes returns the commit types, most frequent first
+func (s CommitTypeStats) SortedTypes() []string {
+	types := make([]string, 0, len(s.Types))
+	for t := range s.Types {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if s.Types[types[i]] != s.Types[types[j]] {
+			return s.Types[types[i]] > s.Types[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// FormatCommitTypes lists commit type counts, most frequent first
+// ("fix 5, feat 2")
+func FormatCommitTypes(types map[string]int) string {
+	sorted := CommitTypeStats{Types: types}.SortedTypes()
+	for i, commitType := range sorted {
+		sorted[i] = fmt.Sprintf("%s %d", commitType, types[commitType])
+	}
+	return strings.Join(sorted, ", ")
+}
+
+// Label describes the dominant kind of work: fix-heavy when fixes (and
+// performance fixes) make up half of the conventional commits, feature-active
+// when features make up 40%, a refactoring area when refactors do, and a
+// maintenance area when docs, tests and chores make up 60%. Areas with fewer
+// than three conventional commits get no label.
+func (s CommitTypeStats) Label() string {
+	if s.Conventional < minLabeledCommits {
+		return ""
+	}
+	share := func(types ...string) float64 {
+		count := 0
+		for _, t := range types {
+			count += s.Types[t]
+		}
+		return float64(count) / float64(s.Conventional)
+	}
+	maintenance := 0
+	for t, count := range s.Types {
+		if maintenanceTypes[t] {
+			maintenance += count
+		}
+	}
+	switch {
+	case share("fix", "perf") >= 0.5:
+		return LabelFixHeavy
+	case share("feat") >= 0.4:
+		return LabelFeatureActive
+	case share("refactor") >= 0.4:
+		return LabelRefactoring
+	case float64(maintenance)/float64(s.Conventional) >= 0.6:
+		return LabelMaintenance
+	}
+	return ""
+}
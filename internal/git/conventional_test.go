@@ -0,0 +1,130 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		message string
+		want    ConventionalCommit
+		ok      bool
+	}{
+		{"feat(api): add refunds endpoint", ConventionalCommit{Type: "feat", Scope: "api", Description: "add refunds endpoint"}, true},
+		{"fix: handle empty keys", ConventionalCommit{Type: "fix", Description: "handle empty keys"}, true},
+		{"Refactor!: drop the v1 client", ConventionalCommit{Type: "refactor", Breaking: true, Description: "drop the v1 client"}, true},
+		{"feat(store)!: return errors from Get", ConventionalCommit{Type: "feat", Scope: "store", Breaking: true, Description: "return errors from Get"}, true},
+		{"chore: bump deps\n\nBREAKING CHANGE: requires Go 1.24", ConventionalCommit{Type: "chore", Breaking: true, Description: "bump deps"}, true},
+		{"Merge branch 'main' into feature", ConventionalCommit{}, false},
+		{"Update README.md", ConventionalCommit{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseConventionalCommit(tt.message)
+		assert.Equal(t, tt.ok, ok, tt.message)
+		assert.Equal(t, tt.want, got, tt.message)
+	}
+}
+
+func TestGetCommitsBetween(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		testutils.WriteTree(t, dir, map[string]string{name: content})
+	}
+
+	run("init", "-q")
+	write("app/main.go", "package main\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+	run("tag", "v1.0.0")
+	write("app/store.go", "package main\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "feat(store): add store\n\nBREAKING CHANGE: main | moved")
+	write("docs/guide.md", "# Guide\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "docs: add guide")
+
+	analyzer, err := NewGitAnalyzer(filepath.Join(dir, "app"))
+	require.NoError(t, err)
+	commits, err := analyzer.GetCommitsBetween(context.Background(), "v1.0.0", "")
+	require.NoError(t, err)
+	require.Len(t, commits, 1, "commits outside the directory are left out")
+	assert.Equal(t, "feat(store): add store\n\nBREAKING CHANGE: main | moved", commits[0].Message)
+	assert.Equal(t, []string{"store.go"}, commits[0].Files)
+
+	_, err = analyzer.GetCommitsBetween(context.Background(), "--all", "")
+	assert.ErrorContains(t, err, "invalid git ref")
+}
+
+func TestClassifyCommits(t *testing.T) {
+	commits := []CommitInfo{
+		{Message: "fix(store): handle empty keys"},
+		{Message: "fix(store): close on error"},
+		{Message: "feat(api)!: paginate orders"},
+		{Message: "docs: usage"},
+		{Message: "Update README.md"},
+	}
+	stats := ClassifyCommits(commits)
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, 4, stats.Conventional)
+	assert.Equal(t, 1, stats.Breaking)
+	assert.Equal(t, map[string]int{"fix": 2, "feat": 1, "docs": 1}, stats.Types)
+	assert.Equal(t, map[string]int{"store": 2, "api": 1}, stats.Scopes)
+	assert.Equal(t, []string{"fix", "docs", "feat"}, stats.SortedTypes())
+	assert.Equal(t, "fix 2, docs 1, feat 1", FormatCommitTypes(stats.Types))
+	assert.Equal(t, LabelFixHeavy, stats.Label())
+}
+
+func TestCommitTypeStatsLabel(t *testing.T) {
+	tests := []struct {
+		types map[string]int
+		want  string
+	}{
+		{map[string]int{"feat": 2, "fix": 1, "chore": 2}, LabelFeatureActive},
+		{map[string]int{"refactor": 2, "feat": 1, "fix": 1, "test": 1}, LabelRefactoring},
+		{map[string]int{"docs": 2, "ci": 1, "chore": 1, "fix": 1}, LabelMaintenance},
+		{map[string]int{"feat": 1, "fix": 1, "refactor": 1, "docs": 1, "perf": 0}, ""},
+		{map[string]int{"fix": 2}, ""}, // Too few commits
+	}
+	for _, tt := range tests {
+		stats := CommitTypeStats{Types: tt.types}
+		for _, count := range tt.types {
+			stats.Conventional += count
+		}
+		assert.Equal(t, tt.want, stats.Label(), "%v", tt.types)
+	}
+}
+
+func TestLabelNeighborhoods(t *testing.T) {
+	neighborhoods := []SemanticNeighborhood{
+		{Name: "store", Files: []string{"store/store.go", "store/cache.go"}},
+		{Name: "docs", Files: []string{"README.md"}},
+	}
+	commits := []CommitInfo{
+		{Message: "fix(store): handle empty keys", Files: []string{"store/store.go"}},
+		{Message: "fix(store): evict on close", Files: []string{"store/cache.go", "README.md"}},
+		{Message: "feat(store): add TTL", Files: []string{"store/cache.go"}},
+		{Message: "perf: batch writes", Files: []string{"store/store.go"}},
+	}
+
+	(&SemanticAnalyzer{}).labelNeighborhoods(neighborhoods, commits)
+	assert.Equal(t, map[string]int{"fix": 2, "feat": 1, "perf": 1}, neighborhoods[0].CommitTypes)
+	assert.Equal(t, LabelFixHeavy, neighborhoods[0].Label)
+	assert.Equal(t, map[string]int{"fix": 1}, neighborhoods[1].CommitTypes)
+	assert.Empty(t, neighborhoods[1].Label)
+}
@@ -0,0 +1,164 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SingleOwnerShare is the share of recent changes above which one author
+// effectively owns a module alone
+const SingleOwnerShare = 0.9
+
+// coAuthorPattern matches "Co-authored-by: Name <email>" trailers
+var coAuthorPattern = regexp.MustCompile(`(?im)^co-authored-by:\s*([^<\n]*?)\s*(?:<([^>\n]*)>)?\s*$`)
+
+// Person identifies an author or co-author of a commit
+type Person struct {
+	Name  string
+	Email string
+}
+
+// key identifies a person across commits, by email when known
+func (p Person) key() string {
+	if p.Email != "" {
+		return strings.ToLower(p.Email)
+	}
+	return strings.ToLower(p.Name)
+}
+
+// CoAuthors returns the people credited by Co-authored-by trailers
+func CoAuthors(message string) []Person {
+	var people []Person
+	for _, m := range coAuthorPattern.FindAllStringSubmatch(message, -1) {
+		if person := (Person{Name: strings.TrimSpace(m[1]), Email: strings.TrimSpace(m[2])}); person.key() != "" {
+			people = append(people, person)
+		}
+	}
+	return people
+}
+
+// GetRecentCommits returns the commits of the last days with their full
+// messages and the files they changed relative to the analyzer's directory.
+// Only commits touching that directory are listed.
+func (g *GitAnalyzer) GetRecentCommits(ctx context.Context, days int) ([]CommitInfo, error) {
+	since := time.Now().AddDate(0, 0, -days).Format("2006-01-02")
+	return g.logCommits(ctx, fmt.Sprintf("--since=%s", since))
+}
+
+// AuthorShare is the part of a module's changes credited to one person
+type AuthorShare struct {
+	Name    string  `json:"name"`
+	Email   string  `json:"email,omitempty"`
+	Changes int     `json:"changes"` // File changes credited, as author or co-author
+	Share   float64 `json:"share"`   // Of all credited file changes of the module
+}
+
+// ModuleOwnership describes who changed a module (a directory) recently
+type ModuleOwnership struct {
+	Module    string        `json:"module"`  // Directory, "." for the root
+	Commits   int           `json:"commits"` // Commits touching the module
+	Changes   int           `json:"changes"` // File changes
+	Authors   []AuthorShare `json:"authors"` // Largest share first
+	BusFactor int           `json:"bus_factor"`
+}
+
+// TopShare returns the share of the module's main author
+func (m ModuleOwnership) TopShare() float64 {
+	if len(m.Authors) == 0 {
+		return 0
+	}
+	return m.Authors[0].Share
+}
+
+// AtRisk reports whether a single author wrote more than SingleOwnerShare of
+// the module's changes
+func (m ModuleOwnership) AtRisk() bool {
+	return m.TopShare() > SingleOwnerShare
+}
+
+// ComputeOwnership credits every file change of the commits to the commit
+// author and its co-authors, grouped by the file's directory. The bus factor
+// of a module is the smallest number of people whose credited changes add up
+// to more than half of the module's; losing them leaves most of the recent
+// knowledge gone. include filters the files, nil keeps all of them. Modules
+// are sorted by path.
+func ComputeOwnership(commits []CommitInfo, include func(file string) bool) []ModuleOwnership {
+	type credit struct {
+		person  Person
+		changes int
+	}
+	modules := make(map[string]*ModuleOwnership)
+	credits := make(map[string]map[string]*credit)
+
+	for _, commit := range commits {
+		people := append([]Person{{Name: commit.Author, Email: commit.Email}}, CoAuthors(commit.Message)...)
+		touched := make(map[string]bool)
+		for _, file := range commit.Files {
+			if include != nil && !include(file) {
+				continue
+			}
+			dir := path.Dir(file)
+			module := modules[dir]
+			if module == nil {
+				module = &ModuleOwnership{Module: dir}
+				modules[dir] = module
+				credits[dir] = make(map[string]*credit)
+			}
+			if !touched[dir] {
+				touched[dir] = true
+				module.Commits++
+			}
+			module.Changes++
+			credited := make(map[string]bool)
+			for _, person := range people {
+				key := person.key()
+				if key == "" || credited[key] {
+					continue
+				}
+				credited[key] = true
+				if credits[dir][key] == nil {
+					credits[dir][key] = &credit{person: person}
+				}
+				credits[dir][key].changes++
+			}
+		}
+	}
+
+	result := make([]ModuleOwnership, 0, len(modules))
+	for dir, module := range modules {
+		total := 0
+		for _, c := range credits[dir] {
+			total += c.changes
+		}
+		for _, c := range credits[dir] {
+			module.Authors = append(module.Authors, AuthorShare{
+				Name:    c.person.Name,
+				Email:   c.person.Email,
+				Changes: c.changes,
+				Share:   float64(c.changes) / float64(total),
+			})
+		}
+		sort.Slice(module.Authors, func(i, j int) bool {
+			a, b := module.Authors[i], module.Authors[j]
+			if a.Changes != b.Changes {
+				return a.Changes > b.Changes
+			}
+			return a.Name < b.Name
+		})
+		covered := 0
+		for _, author := range module.Authors {
+			module.BusFactor++
+			if covered += author.Changes; covered*2 > total {
+				break
+			}
+		}
+		result = append(result, *module)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Module < result[j].Module })
+	return result
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoAuthors(t *testing.T) {
+	message := "feat: pair on billing\n\nCo-authored-by: Ana Lima <ana@example.com>\nco-authored-by: Bo <BO@example.com>\nCo-authored-by: <>\n"
+	assert.Equal(t, []Person{
+		{Name: "Ana Lima", Email: "ana@example.com"},
+		{Name: "Bo", Email: "BO@example.com"},
+	}, CoAuthors(message))
+	assert.Empty(t, CoAuthors("fix: typo"))
+}
+
+func TestComputeOwnership(t *testing.T) {
+	var commits []CommitInfo
+	for i := 0; i < 19; i++ {
+		commits = append(commits, CommitInfo{Author: "Ana", Email: "ana@example.com", Files: []string{"billing/invoice.go"}})
+	}
+	commits = append(commits,
+		CommitInfo{Author: "Bo", Email: "bo@example.com", Files: []string{"billing/tax.go", "README.md"}},
+		CommitInfo{Author: "Bo", Email: "bo@example.com", Files: []string{"api/routes.go", "api/auth.go"}},
+		CommitInfo{Author: "Cy", Email: "cy@example.com", Files: []string{"api/routes.go"},
+			Message: "feat: login\n\nCo-authored-by: Bo <BO@example.com>\nCo-authored-by: Dee <dee@example.com>"},
+		CommitInfo{Author: "Ana (laptop)", Email: "ana@example.com", Files: []string{"api/users.go"}},
+	)
+
+	modules := ComputeOwnership(commits, func(file string) bool { return file != "README.md" })
+	require.Len(t, modules, 2, "filtered files are not counted")
+
+	api := modules[0]
+	assert.Equal(t, "api", api.Module)
+	assert.Equal(t, 3, api.Commits)
+	assert.Equal(t, 4, api.Changes)
+	require.Len(t, api.Authors, 4)
+	assert.Equal(t, AuthorShare{Name: "Bo", Email: "bo@example.com", Changes: 3, Share: 0.5}, api.Authors[0], "co-authors are matched by email")
+	assert.Equal(t, 2, api.BusFactor)
+	assert.False(t, api.AtRisk())
+
+	billing := modules[1]
+	assert.Equal(t, "billing", billing.Module)
+	assert.Equal(t, 20, billing.Changes)
+	assert.Equal(t, "Ana", billing.Authors[0].Name)
+	assert.InDelta(t, 0.95, billing.TopShare(), 0.001)
+	assert.Equal(t, 1, billing.BusFactor)
+	assert.True(t, billing.AtRisk())
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileMove is a file renamed or moved between two revisions
+type FileMove struct {
+	From       string
+	To         string
+	Similarity int // Percentage of unchanged content reported by git
+}
+
+// ResolveRef returns the commit a tag, branch or revision names
+func (g *GitAnalyzer) ResolveRef(ctx context.Context, ref string) (string, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid git ref %q", ref)
+	}
+	output, err := g.ExecuteGitCommand(ctx, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown git ref %q", ref)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ExportTree writes the files of the repository directory at ref into dir,
+// as they were committed. Only the part of the tree below the analyzer's
+// directory is exported, so dir mirrors the directory at that revision.
+func (g *GitAnalyzer) ExportTree(ctx context.Context, ref, dir string) error {
+	commit, err := g.ResolveRef(ctx, ref)
+	if err != nil {
+		return err
+	}
+	prefix, err := g.ExecuteGitCommand(ctx, "rev-parse", "--show-prefix")
+	if err != nil {
+		return err
+	}
+	archive, err := g.ExecuteGitCommand(ctx, "archive", "--format=tar", commit+":"+strings.TrimSpace(string(prefix)))
+	if err != nil {
+		return err
+	}
+
+	reader := tar.NewReader(bytes.NewReader(archive))
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive of %s: %w", ref, err)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if rel, err := filepath.Rel(dir, target); err != nil || strings.HasPrefix(rel, "..") {
+			continue // Entries escaping dir are never written
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, reader)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// WithTree exports the repository directory at ref into a temporary
+// directory, as ExportTree does, and calls fn with it. The directory is
+// removed once fn returns.
+func (g *GitAnalyzer) WithTree(ctx context.Context, ref string, fn func(dir string) error) error {
+	dir, err := os.MkdirTemp("", "codecontext-ref-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := g.ExportTree(ctx, ref, dir); err != nil {
+		return err
+	}
+	return fn(dir)
+}
+
+// GetFileMoves lists the files renamed or moved between two revisions, with
+// paths relative to the analyzer's directory. An empty to compares with the
+// working tree.
+func (g *GitAnalyzer) GetFileMoves(ctx context.Context, from, to string) ([]FileMove, error) {
+	args := []string{"diff", "--name-status", "-M", "--relative", from}
+	if to != "" {
+		args = append(args, to)
+	}
+	output, err := g.ExecuteGitCommand(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var moves []FileMove
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || !strings.HasPrefix(fields[0], "R") {
+			continue
+		}
+		move := FileMove{From: fields[1], To: fields[2]}
+		fmt.Sscanf(fields[0][1:], "%d", &move.Similarity)
+		moves = append(moves, move)
+	}
+	return moves, nil
+}
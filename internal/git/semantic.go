@@ -16,14 +16,14 @@ type SemanticAnalyzer struct {
 
 // SemanticConfig holds configuration for semantic analysis
 type SemanticConfig struct {
-	AnalysisPeriodDays    int     `json:"analysis_period_days"`
-	MinChangeCorrelation  float64 `json:"min_change_correlation"`
-	MinPatternSupport     float64 `json:"min_pattern_support"`
-	MinPatternConfidence  float64 `json:"min_pattern_confidence"`
-	MaxNeighborhoodSize   int     `json:"max_neighborhood_size"`
-	IncludeTestFiles      bool    `json:"include_test_files"`
-	IncludeDocFiles       bool    `json:"include_doc_files"`
-	IncludeConfigFiles    bool    `json:"include_config_files"`
+	AnalysisPeriodDays    int     `json:"analysis_period_days" mapstructure:"analysis_period_days"`
+	MinChangeCorrelation  float64 `json:"min_change_correlation" mapstructure:"min_change_correlation"`
+	MinPatternSupport     float64 `json:"min_pattern_support" mapstructure:"min_pattern_support"`
+	MinPatternConfidence  float64 `json:"min_pattern_confidence" mapstructure:"min_pattern_confidence"`
+	MaxNeighborhoodSize   int     `json:"max_neighborhood_size" mapstructure:"max_neighborhood_size"`
+	IncludeTestFiles      bool    `json:"include_test_files" mapstructure:"include_test_files"`
+	IncludeDocFiles       bool    `json:"include_doc_files" mapstructure:"include_doc_files"`
+	IncludeConfigFiles    bool    `json:"include_config_files" mapstructure:"include_config_files"`
 }
 
 // DefaultSemanticConfig returns default configuration with optimized thresholds
@@ -50,6 +50,8 @@ type SemanticNeighborhood struct {
 	CorrelationStrength float64                `json:"correlation_strength"`
 	Confidence          float64                `json:"confidence"`
 	Metadata            map[string]interface{} `json:"metadata"`
+	CommitTypes         map[string]int         `json:"commit_types,omitempty"` // Conventional commit types touching the files
+	Label               string                 `json:"label,omitempty"`        // e.g. "fix-heavy area", see CommitTypeStats.Label
 }
 
 // ContextRecommendation provides context recommendations for AI assistants
@@ -70,6 +72,7 @@ type SemanticAnalysisResult struct {
 	FileRelationships    []FileRelationship      `json:"file_relationships"`
 	ModuleGroups         []ModuleGroup           `json:"module_groups"`
 	AnalysisSummary      AnalysisSummary         `json:"analysis_summary"`
+	CommitTypes          CommitTypeStats         `json:"commit_types"`
 }
 
 // AnalysisSummary provides overview statistics
@@ -156,6 +159,13 @@ func (sa *SemanticAnalyzer) AnalyzeRepository() (*SemanticAnalysisResult, error)
 	// Build semantic neighborhoods
 	neighborhoods := sa.buildSemanticNeighborhoods(patterns, relationships, moduleGroups)
 
+	// Classify the commits of the period and label the neighborhoods by their kind of work
+	var commitTypes CommitTypeStats
+	if commits, err := sa.gitAnalyzer.GetCommitHistory(sa.config.AnalysisPeriodDays); err == nil {
+		commitTypes = ClassifyCommits(commits)
+		sa.labelNeighborhoods(neighborhoods, commits)
+	}
+
 	// Generate context recommendations
 	contextRecommendations := sa.generateContextRecommendations(neighborhoods, relationships)
 
@@ -169,9 +179,35 @@ func (sa *SemanticAnalyzer) AnalyzeRepository() (*SemanticAnalysisResult, error)
 		FileRelationships:      relationships,
 		ModuleGroups:           moduleGroups,
 		AnalysisSummary:        summary,
+		CommitTypes:            commitTypes,
 	}, nil
 }
 
+// labelNeighborhoods records the conventional commit types of the commits
+// touching each neighborhood, and the activity label they suggest
+func (sa *SemanticAnalyzer) labelNeighborhoods(neighborhoods []SemanticNeighborhood, commits []CommitInfo) {
+	for i := range neighborhoods {
+		files := make(map[string]bool, len(neighborhoods[i].Files))
+		for _, file := range neighborhoods[i].Files {
+			files[file] = true
+		}
+		var touching []CommitInfo
+		for _, commit := range commits {
+			for _, file := range commit.Files {
+				if files[file] {
+					touching = append(touching, commit)
+					break
+				}
+			}
+		}
+		stats := ClassifyCommits(touching)
+		if stats.Conventional > 0 {
+			neighborhoods[i].CommitTypes = stats.Types
+		}
+		neighborhoods[i].Label = stats.Label()
+	}
+}
+
 // GetContextRecommendationsForFile provides context recommendations for a specific file
 func (sa *SemanticAnalyzer) GetContextRecommendationsForFile(filePath string) ([]ContextRecommendation, error) {
 	// Get neighborhoods
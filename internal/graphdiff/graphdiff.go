@@ -0,0 +1,212 @@
+// Package graphdiff compares the code graphs of two analyses, such as a main
+// branch and a feature branch, by their files, symbols and relationships.
+package graphdiff
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol is a symbol defined in a file
+type Symbol struct {
+	File string `json:"file"` // Relative to the analyzed directory
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Line int    `json:"line"`
+}
+
+// key identifies a symbol across analyses, which may move it within its file
+func (s Symbol) key() string {
+	return s.File + "\x00" + s.Name + "\x00" + s.Kind
+}
+
+// Edge is a relationship between two nodes. Files are named by their path,
+// symbols by "path#name" and anything else, such as third-party imports, by
+// its graph node id.
+type Edge struct {
+	Type string `json:"type"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func (e Edge) key() string {
+	return e.Type + "\x00" + e.From + "\x00" + e.To
+}
+
+// Snapshot is the part of a code graph that can be compared between
+// analyses of different directories
+type Snapshot struct {
+	Files   map[string]string // Language by relative path
+	Symbols map[string]Symbol // By file, name and kind
+	Edges   map[string]Edge   // By type and endpoints
+}
+
+// Extract builds the snapshot of a graph analyzed from root. Imports are
+// compared as edges rather than symbols, and containment edges follow from
+// the symbols, so both are left out, as are parser artifacts.
+func Extract(graph *types.CodeGraph, root string) *Snapshot {
+	snapshot := &Snapshot{
+		Files:   make(map[string]string),
+		Symbols: make(map[string]Symbol),
+		Edges:   make(map[string]Edge),
+	}
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return filepath.ToSlash(path)
+	}
+
+	names := make(map[types.SymbolId]string)
+	for path, file := range graph.Files {
+		rel := relative(path)
+		snapshot.Files[rel] = file.Language
+		for _, id := range file.Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil {
+				continue
+			}
+			names[id] = rel + "#" + symbol.Name
+			if symbol.Type == types.SymbolTypeImport || isArtifact(symbol.Name) {
+				continue
+			}
+			s := Symbol{File: rel, Name: symbol.Name, Kind: string(symbol.Type), Line: symbol.Location.StartLine}
+			if existing, ok := snapshot.Symbols[s.key()]; !ok || s.Line < existing.Line {
+				snapshot.Symbols[s.key()] = s
+			}
+		}
+	}
+
+	node := func(id types.NodeId) string {
+		if path, ok := strings.CutPrefix(string(id), "file-"); ok {
+			return relative(path)
+		}
+		if symbol, ok := strings.CutPrefix(string(id), "symbol-"); ok {
+			return names[types.SymbolId(symbol)]
+		}
+		return string(id)
+	}
+	for _, edge := range graph.Edges {
+		if edge.Type == string(analyzer.RelationshipContains) {
+			continue
+		}
+		e := Edge{Type: edge.Type, From: node(edge.From), To: node(edge.To)}
+		if e.From != "" && e.To != "" {
+			snapshot.Edges[e.key()] = e
+		}
+	}
+	return snapshot
+}
+
+// isArtifact reports names parsers record that are not declarations, such as
+// "api(value)" for a function's parameter list or a bare keyword
+func isArtifact(name string) bool {
+	return name == "" || name == "function" || name == "class" || strings.ContainsAny(name, "( \t")
+}
+
+// LoadDir snapshots the analysis of a directory as it is on disk
+func LoadDir(dir string, analyze analyzer.AnalyzeFunc) (*Snapshot, error) {
+	graph, err := analyze(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", dir, err)
+	}
+	return Extract(graph, dir), nil
+}
+
+// LoadRef snapshots the analysis of the repository directory as it was
+// committed at ref
+func LoadRef(ctx context.Context, repo *git.GitAnalyzer, ref string, analyze analyzer.AnalyzeFunc) (*Snapshot, error) {
+	var snapshot *Snapshot
+	err := repo.WithTree(ctx, ref, func(dir string) error {
+		var err error
+		snapshot, err = LoadDir(dir, analyze)
+		return err
+	})
+	return snapshot, err
+}
+
+// Diff holds what one analysis has that the other does not
+type Diff struct {
+	From           string   `json:"from"`
+	To             string   `json:"to"`
+	AddedFiles     []string `json:"added_files"`
+	RemovedFiles   []string `json:"removed_files"`
+	AddedSymbols   []Symbol `json:"added_symbols"`
+	RemovedSymbols []Symbol `json:"removed_symbols"`
+	AddedEdges     []Edge   `json:"added_edges"`
+	RemovedEdges   []Edge   `json:"removed_edges"`
+}
+
+// Empty reports whether the analyses have the same files, symbols and edges
+func (d *Diff) Empty() bool {
+	return len(d.AddedFiles) == 0 && len(d.RemovedFiles) == 0 &&
+		len(d.AddedSymbols) == 0 && len(d.RemovedSymbols) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Compare lists the files, symbols and edges added and removed between two
+// snapshots. Files and symbols are sorted by path, edges by type and
+// endpoints. Symbols of added and removed files are listed too.
+func Compare(oldSnapshot, newSnapshot *Snapshot) *Diff {
+	diff := &Diff{
+		AddedFiles:   []string{},
+		RemovedFiles: []string{},
+	}
+	for path := range newSnapshot.Files {
+		if _, ok := oldSnapshot.Files[path]; !ok {
+			diff.AddedFiles = append(diff.AddedFiles, path)
+		}
+	}
+	for path := range oldSnapshot.Files {
+		if _, ok := newSnapshot.Files[path]; !ok {
+			diff.RemovedFiles = append(diff.RemovedFiles, path)
+		}
+	}
+	sort.Strings(diff.AddedFiles)
+	sort.Strings(diff.RemovedFiles)
+
+	diff.AddedSymbols = missingSymbols(newSnapshot.Symbols, oldSnapshot.Symbols)
+	diff.RemovedSymbols = missingSymbols(oldSnapshot.Symbols, newSnapshot.Symbols)
+	diff.AddedEdges = missingEdges(newSnapshot.Edges, oldSnapshot.Edges)
+	diff.RemovedEdges = missingEdges(oldSnapshot.Edges, newSnapshot.Edges)
+	return diff
+}
+
+// missingSymbols returns the symbols of a that b does not have
+func missingSymbols(a, b map[string]Symbol) []Symbol {
+	missing := []Symbol{}
+	for key, symbol := range a {
+		if _, ok := b[key]; !ok {
+			missing = append(missing, symbol)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].File != missing[j].File {
+			return missing[i].File < missing[j].File
+		}
+		if missing[i].Line != missing[j].Line {
+			return missing[i].Line < missing[j].Line
+		}
+		return missing[i].key() < missing[j].key()
+	})
+	return missing
+}
+
+// missingEdges returns the edges of a that b does not have
+func missingEdges(a, b map[string]Edge) []Edge {
+	missing := []Edge{}
+	for key, edge := range a {
+		if _, ok := b[key]; !ok {
+			missing = append(missing, edge)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].key() < missing[j].key() })
+	return missing
+}
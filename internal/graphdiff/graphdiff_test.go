@@ -0,0 +1,84 @@
+package graphdiff
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	mainDir, featureDir := t.TempDir(), t.TempDir()
+	testutils.WriteTree(t, mainDir, map[string]string{
+		"src/index.js":  "import { format } from './format';\n\nexport function start() { return format(1); }\n",
+		"src/format.js": "export function format(value) { return String(value); }\n\nexport function pad(value) { return value; }\n",
+		"src/legacy.js": "export function legacy() { return 1; }\n",
+	})
+	testutils.WriteTree(t, featureDir, map[string]string{
+		"src/index.js":  "import { format } from './format';\nimport { api } from './api';\n\nexport function start() { return api(format(1)); }\n",
+		"src/format.js": "export function format(value) { return String(value); }\n",
+		"src/api.js":    "export function api(value) { return value; }\n",
+	})
+
+	oldSnapshot, err := LoadDir(mainDir, analyzer.AnalyzeDirectory)
+	require.NoError(t, err)
+	newSnapshot, err := LoadDir(featureDir, analyzer.AnalyzeDirectory)
+	require.NoError(t, err)
+	assert.Equal(t, "javascript", oldSnapshot.Files["src/index.js"], "paths are relative to the analyzed directory")
+
+	diff := Compare(oldSnapshot, newSnapshot)
+	assert.Equal(t, []string{"src/api.js"}, diff.AddedFiles)
+	assert.Equal(t, []string{"src/legacy.js"}, diff.RemovedFiles)
+	assert.Contains(t, diff.AddedSymbols, Symbol{File: "src/api.js", Name: "api", Kind: "function", Line: 1})
+	assert.Contains(t, diff.RemovedSymbols, Symbol{File: "src/format.js", Name: "pad", Kind: "function", Line: 3})
+	for _, symbol := range append(diff.AddedSymbols, diff.RemovedSymbols...) {
+		assert.NotEqual(t, "src/index.js", symbol.File, "unchanged symbols and imports are not reported")
+	}
+	assert.Contains(t, diff.AddedEdges, Edge{Type: "imports", From: "src/index.js", To: "src/api.js"})
+	assert.NotContains(t, diff.AddedEdges, Edge{Type: "imports", From: "src/index.js", To: "src/format.js"})
+
+	diff.From, diff.To = "main", "feature"
+	summary := diff.Summary(0)
+	assert.Contains(t, summary, "# Graph Diff: main → feature\n\n**Files:** +1 −1 |")
+	assert.Contains(t, summary, "## Files\n\n**Added**\n\n- `src/api.js`\n\n**Removed**\n\n- `src/legacy.js`\n")
+	assert.Contains(t, summary, "- `src/format.js`: `pad` (function)\n")
+	assert.Contains(t, summary, "- imports: `src/index.js` → `src/api.js`\n")
+
+	assert.True(t, Compare(newSnapshot, newSnapshot).Empty())
+	assert.Contains(t, diff.Summary(1), "- _... and ")
+}
+
+func TestLoadRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	testutils.WriteTree(t, dir, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	testutils.WriteTree(t, dir, map[string]string{"util.go": "package main\n\nfunc helper() {}\n"})
+
+	repo, err := git.NewGitAnalyzer(dir)
+	require.NoError(t, err)
+	committed, err := LoadRef(context.Background(), repo, "HEAD", analyzer.AnalyzeDirectory)
+	require.NoError(t, err)
+	working, err := LoadDir(dir, analyzer.AnalyzeDirectory)
+	require.NoError(t, err)
+
+	diff := Compare(committed, working)
+	assert.Equal(t, []string{"util.go"}, diff.AddedFiles)
+	assert.Equal(t, []Symbol{{File: "util.go", Name: "helper", Kind: "function", Line: 3}}, diff.AddedSymbols)
+}
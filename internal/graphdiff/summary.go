@@ -0,0 +1,88 @@
+package graphdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Summary renders the diff for a reviewer: totals, then the added and
+// removed files, symbols grouped by file, and edges grouped by relationship.
+// Each list shows at most limit entries; zero shows all.
+func (d *Diff) Summary(limit int) string {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("# Graph Diff: %s → %s\n\n", d.From, d.To))
+	out.WriteString(fmt.Sprintf("**Files:** +%d −%d | **Symbols:** +%d −%d | **Edges:** +%d −%d\n",
+		len(d.AddedFiles), len(d.RemovedFiles), len(d.AddedSymbols), len(d.RemovedSymbols), len(d.AddedEdges), len(d.RemovedEdges)))
+	if d.Empty() {
+		out.WriteString("\n_The graphs have the same files, symbols and relationships._\n")
+		return out.String()
+	}
+
+	if len(d.AddedFiles) > 0 || len(d.RemovedFiles) > 0 {
+		out.WriteString("\n## Files\n")
+		writeList(&out, "Added", quoted(d.AddedFiles), limit)
+		writeList(&out, "Removed", quoted(d.RemovedFiles), limit)
+	}
+
+	if len(d.AddedSymbols) > 0 || len(d.RemovedSymbols) > 0 {
+		out.WriteString("\n## Symbols\n")
+		writeList(&out, "Added", symbolsByFile(d.AddedSymbols), limit)
+		writeList(&out, "Removed", symbolsByFile(d.RemovedSymbols), limit)
+	}
+
+	if len(d.AddedEdges) > 0 || len(d.RemovedEdges) > 0 {
+		out.WriteString("\n## Relationships\n")
+		writeList(&out, "Added", edgeLines(d.AddedEdges), limit)
+		writeList(&out, "Removed", edgeLines(d.RemovedEdges), limit)
+	}
+	return out.String()
+}
+
+// writeList writes a titled bullet list of at most limit lines, if any
+func writeList(out *strings.Builder, title string, lines []string, limit int) {
+	if len(lines) == 0 {
+		return
+	}
+	out.WriteString(fmt.Sprintf("\n**%s**\n\n", title))
+	shown := lines
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+	for _, line := range shown {
+		out.WriteString("- " + line + "\n")
+	}
+	if len(shown) < len(lines) {
+		out.WriteString(fmt.Sprintf("- _... and %d more_\n", len(lines)-len(shown)))
+	}
+}
+
+func quoted(values []string) []string {
+	lines := make([]string, len(values))
+	for i, value := range values {
+		lines[i] = "`" + value + "`"
+	}
+	return lines
+}
+
+// symbolsByFile gives one line per file listing its symbols, in file order
+func symbolsByFile(symbols []Symbol) []string {
+	var lines []string
+	for i := 0; i < len(symbols); {
+		file := symbols[i].File
+		var names []string
+		for ; i < len(symbols) && symbols[i].File == file; i++ {
+			names = append(names, fmt.Sprintf("`%s` (%s)", symbols[i].Name, symbols[i].Kind))
+		}
+		lines = append(lines, fmt.Sprintf("`%s`: %s", file, strings.Join(names, ", ")))
+	}
+	return lines
+}
+
+// edgeLines gives one line per edge; diffs sort edges by relationship type
+func edgeLines(edges []Edge) []string {
+	lines := make([]string, len(edges))
+	for i, edge := range edges {
+		lines[i] = fmt.Sprintf("%s: `%s` → `%s`", edge.Type, edge.From, edge.To)
+	}
+	return lines
+}
@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// templateOnlyLine matches Helm lines that are pure control flow, e.g. {{- if .Values.enabled }}
+	templateOnlyLine = regexp.MustCompile(`^\s*\{\{-?.*-?\}\}\s*$`)
+	templateAction   = regexp.MustCompile(`\{\{-?\s*(.*?)\s*-?\}\}`)
+)
+
+// document is one decoded YAML document with the line it starts on
+type document struct {
+	object map[string]interface{}
+	line   int
+}
+
+// decodeManifests splits a (possibly multi-document) manifest into objects.
+// Helm templates are rendered loosely first so their structure can still be read.
+func decodeManifests(content string, templated bool) []document {
+	if templated {
+		content = stripTemplates(content)
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	var docs []document
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			break // Keep the documents decoded before the first invalid one
+		}
+		if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+
+		var object map[string]interface{}
+		if err := node.Content[0].Decode(&object); err != nil {
+			continue
+		}
+		if items, ok := object["items"].([]interface{}); ok && object["kind"] == "List" {
+			for _, item := range items {
+				if itemObject, ok := item.(map[string]interface{}); ok {
+					docs = append(docs, document{object: itemObject, line: node.Content[0].Line})
+				}
+			}
+			continue
+		}
+		docs = append(docs, document{object: object, line: node.Content[0].Line})
+	}
+	return docs
+}
+
+// stripTemplates blanks Helm control-flow lines and replaces inline actions with a stable
+// placeholder, so "{{ include "app.fullname" . }}" becomes "tpl(include app.fullname .)".
+// Identical expressions render identically, which keeps name references linkable.
+func stripTemplates(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if templateOnlyLine.MatchString(line) {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = templateAction.ReplaceAllStringFunc(line, func(action string) string {
+			expr := templateAction.FindStringSubmatch(action)[1]
+			expr = strings.NewReplacer(`"`, "", "'", "", ":", "", "#", "").Replace(expr)
+			return "tpl(" + strings.Join(strings.Fields(expr), " ") + ")"
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// str reads a nested string field, e.g. str(obj, "metadata", "name")
+func str(object map[string]interface{}, path ...string) string {
+	value := lookup(object, path...)
+	s, _ := value.(string)
+	return s
+}
+
+// mapping reads a nested object field
+func mapping(object map[string]interface{}, path ...string) map[string]interface{} {
+	value, _ := lookup(object, path...).(map[string]interface{})
+	return value
+}
+
+// list reads a nested list of objects
+func list(object map[string]interface{}, path ...string) []map[string]interface{} {
+	values, _ := lookup(object, path...).([]interface{})
+	result := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		if item, ok := value.(map[string]interface{}); ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func lookup(object map[string]interface{}, path ...string) interface{} {
+	var current interface{} = object
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// stringMap converts a decoded label map to map[string]string
+func stringMap(object map[string]interface{}) map[string]string {
+	if len(object) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(object))
+	for key, value := range object {
+		if value != nil {
+			result[key] = fmt.Sprint(value) // Labels like version: 2 decode as numbers
+		}
+	}
+	return result
+}
@@ -0,0 +1,353 @@
+// Package k8s extracts Kubernetes resources from manifests and Helm charts and links
+// them by name and label references into a deployment topology.
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Relations between resources
+const (
+	RelationRoutesTo   = "routes-to"   // Ingress → Service
+	RelationSelects    = "selects"     // Service → workload via label selector
+	RelationMounts     = "mounts"      // Workload → ConfigMap/Secret/PVC volume
+	RelationEnvFrom    = "env-from"    // Workload → ConfigMap/Secret environment
+	RelationRunsAs     = "runs-as"     // Workload → ServiceAccount
+	RelationScales     = "scales"      // HorizontalPodAutoscaler → workload
+	RelationGovernedBy = "governed-by" // StatefulSet → headless Service
+)
+
+// Resource is a single Kubernetes object declared in a manifest or chart template
+type Resource struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	File      string            `json:"file"` // Relative to the scanned root
+	Line      int               `json:"line"`
+	Chart     string            `json:"chart,omitempty"`    // Helm chart directory for template resources
+	Labels    map[string]string `json:"labels,omitempty"`   // Pod template labels for workloads
+	Selector  map[string]string `json:"selector,omitempty"` // Service label selector
+	Images    []string          `json:"images,omitempty"`
+	Links     []*Link           `json:"links,omitempty"`
+}
+
+// Link is a reference from one resource to another
+type Link struct {
+	Kind     string    `json:"kind"`
+	Name     string    `json:"name"`
+	Relation string    `json:"relation"`
+	Target   *Resource `json:"-"` // nil when the referenced resource is not declared in the repo
+}
+
+// Topology holds all resources found under a root directory
+type Topology struct {
+	Root      string
+	Resources []*Resource
+}
+
+// ID returns the Kind/name form used to refer to a resource
+func (r *Resource) ID() string {
+	return r.Kind + "/" + r.Name
+}
+
+// IsWorkload reports whether the resource runs pods
+func (r *Resource) IsWorkload() bool {
+	switch r.Kind {
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob", "Pod":
+		return true
+	}
+	return false
+}
+
+// skipDirs are never descended into when looking for manifests
+var skipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true, "build": true, ".dart_tool": true,
+}
+
+// Scan walks root, extracts resources from YAML manifests and chart templates, and links them
+func Scan(root string) (*Topology, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve root %s: %w", root, err)
+	}
+
+	topology := &Topology{Root: absRoot}
+	err = filepath.Walk(absRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Unreadable entries are skipped rather than aborting the scan
+		}
+		if info.IsDir() {
+			if path != absRoot && skipDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		content, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil
+		}
+		text := string(content)
+		chart := ""
+		templated := strings.Contains(text, "{{")
+		if templated {
+			chart = chartDir(absRoot, filepath.Dir(path))
+		}
+
+		rel, _ := filepath.Rel(absRoot, path)
+		for _, doc := range decodeManifests(text, templated) {
+			if resource := newResource(doc); resource != nil {
+				resource.File = filepath.ToSlash(rel)
+				resource.Chart = chart
+				topology.Resources = append(topology.Resources, resource)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(topology.Resources, func(i, j int) bool {
+		a, b := topology.Resources[i], topology.Resources[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	topology.link()
+	return topology, nil
+}
+
+// chartDir returns the nearest ancestor of dir containing Chart.yaml, relative to root
+func chartDir(root, dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "Chart.yaml")); err == nil {
+			rel, _ := filepath.Rel(root, dir)
+			return filepath.ToSlash(rel)
+		}
+		if dir == root || filepath.Dir(dir) == dir {
+			return ""
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// newResource builds a resource from a decoded object, or nil if it is not a Kubernetes object
+func newResource(doc document) *Resource {
+	object := doc.object
+	kind, name := str(object, "kind"), str(object, "metadata", "name")
+	if str(object, "apiVersion") == "" || kind == "" || name == "" {
+		return nil
+	}
+
+	resource := &Resource{
+		Kind:      kind,
+		Name:      name,
+		Namespace: str(object, "metadata", "namespace"),
+		Line:      doc.line,
+		Labels:    stringMap(mapping(object, "metadata", "labels")),
+	}
+
+	switch kind {
+	case "Service":
+		resource.Selector = stringMap(mapping(object, "spec", "selector"))
+	case "Ingress":
+		for _, service := range ingressBackends(object) {
+			resource.addLink("Service", service, RelationRoutesTo)
+		}
+	case "HorizontalPodAutoscaler":
+		if target := mapping(object, "spec", "scaleTargetRef"); target != nil {
+			resource.addLink(str(target, "kind"), str(target, "name"), RelationScales)
+		}
+	case "StatefulSet":
+		if service := str(object, "spec", "serviceName"); service != "" {
+			resource.addLink("Service", service, RelationGovernedBy)
+		}
+	}
+
+	if template := podTemplate(object); template != nil {
+		resource.Labels = stringMap(mapping(template, "metadata", "labels"))
+		resource.addPodSpecLinks(mapping(template, "spec"))
+	} else if kind == "Pod" {
+		resource.addPodSpecLinks(mapping(object, "spec"))
+	}
+	return resource
+}
+
+// podTemplate returns the pod template of a workload
+func podTemplate(object map[string]interface{}) map[string]interface{} {
+	if str(object, "kind") == "CronJob" {
+		return mapping(object, "spec", "jobTemplate", "spec", "template")
+	}
+	return mapping(object, "spec", "template")
+}
+
+// ingressBackends returns the service names an Ingress routes to (networking/v1 and v1beta1)
+func ingressBackends(object map[string]interface{}) []string {
+	var services []string
+	addBackend := func(backend map[string]interface{}) {
+		if name := str(backend, "service", "name"); name != "" {
+			services = append(services, name)
+		} else if name := str(backend, "serviceName"); name != "" {
+			services = append(services, name)
+		}
+	}
+
+	addBackend(mapping(object, "spec", "defaultBackend"))
+	addBackend(mapping(object, "spec", "backend"))
+	for _, rule := range list(object, "spec", "rules") {
+		for _, path := range list(rule, "http", "paths") {
+			addBackend(mapping(path, "backend"))
+		}
+	}
+	return services
+}
+
+// addPodSpecLinks records images and ConfigMap/Secret/PVC/ServiceAccount references of a pod spec
+func (r *Resource) addPodSpecLinks(spec map[string]interface{}) {
+	if spec == nil {
+		return
+	}
+	if account := str(spec, "serviceAccountName"); account != "" {
+		r.addLink("ServiceAccount", account, RelationRunsAs)
+	}
+	for _, volume := range list(spec, "volumes") {
+		if name := str(volume, "configMap", "name"); name != "" {
+			r.addLink("ConfigMap", name, RelationMounts)
+		}
+		if name := str(volume, "secret", "secretName"); name != "" {
+			r.addLink("Secret", name, RelationMounts)
+		}
+		if name := str(volume, "persistentVolumeClaim", "claimName"); name != "" {
+			r.addLink("PersistentVolumeClaim", name, RelationMounts)
+		}
+	}
+
+	containers := append(list(spec, "initContainers"), list(spec, "containers")...)
+	for _, container := range containers {
+		if image := str(container, "image"); image != "" {
+			r.Images = appendUnique(r.Images, image)
+		}
+		for _, envFrom := range list(container, "envFrom") {
+			if name := str(envFrom, "configMapRef", "name"); name != "" {
+				r.addLink("ConfigMap", name, RelationEnvFrom)
+			}
+			if name := str(envFrom, "secretRef", "name"); name != "" {
+				r.addLink("Secret", name, RelationEnvFrom)
+			}
+		}
+		for _, env := range list(container, "env") {
+			if name := str(env, "valueFrom", "configMapKeyRef", "name"); name != "" {
+				r.addLink("ConfigMap", name, RelationEnvFrom)
+			}
+			if name := str(env, "valueFrom", "secretKeyRef", "name"); name != "" {
+				r.addLink("Secret", name, RelationEnvFrom)
+			}
+		}
+	}
+}
+
+// addLink records a reference once per kind, name and relation
+func (r *Resource) addLink(kind, name, relation string) {
+	if kind == "" || name == "" {
+		return
+	}
+	for _, link := range r.Links {
+		if link.Kind == kind && link.Name == name && link.Relation == relation {
+			return
+		}
+	}
+	r.Links = append(r.Links, &Link{Kind: kind, Name: name, Relation: relation})
+}
+
+// link resolves name references and Service selectors to declared resources
+func (t *Topology) link() {
+	byID := make(map[string][]*Resource)
+	for _, resource := range t.Resources {
+		byID[resource.ID()] = append(byID[resource.ID()], resource)
+	}
+
+	for _, resource := range t.Resources {
+		for _, link := range resource.Links {
+			link.Target = pickResource(byID[link.Kind+"/"+link.Name], resource)
+		}
+
+		if resource.Kind != "Service" || len(resource.Selector) == 0 {
+			continue
+		}
+		for _, workload := range t.Resources {
+			if workload.IsWorkload() && sameNamespace(resource, workload) && labelsMatch(resource.Selector, workload.Labels) {
+				resource.Links = append(resource.Links, &Link{
+					Kind:     workload.Kind,
+					Name:     workload.Name,
+					Relation: RelationSelects,
+					Target:   workload,
+				})
+			}
+		}
+	}
+}
+
+// pickResource chooses the candidate in the same namespace (and chart) as the referrer
+func pickResource(candidates []*Resource, from *Resource) *Resource {
+	var fallback *Resource
+	for _, candidate := range candidates {
+		if !sameNamespace(candidate, from) {
+			continue
+		}
+		if candidate.Chart == from.Chart {
+			return candidate
+		}
+		if fallback == nil {
+			fallback = candidate
+		}
+	}
+	return fallback
+}
+
+// sameNamespace treats an unset namespace as matching any namespace
+func sameNamespace(a, b *Resource) bool {
+	return a.Namespace == "" || b.Namespace == "" || a.Namespace == b.Namespace
+}
+
+// labelsMatch reports whether every selector label is present on labels
+func labelsMatch(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Referrers returns the resources that link to target
+func (t *Topology) Referrers(target *Resource) []*Resource {
+	var referrers []*Resource
+	for _, resource := range t.Resources {
+		for _, link := range resource.Links {
+			if link.Target == target {
+				referrers = append(referrers, resource)
+				break
+			}
+		}
+	}
+	return referrers
+}
+
+// appendUnique appends value to values unless it is already present
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,150 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func findResource(topology *Topology, id string) *Resource {
+	for _, resource := range topology.Resources {
+		if resource.ID() == id {
+			return resource
+		}
+	}
+	return nil
+}
+
+func TestScanManifests(t *testing.T) {
+	root := t.TempDir()
+	testutils.WriteTree(t, root, map[string]string{
+		"deploy/web.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+        tier: frontend
+    spec:
+      serviceAccountName: web-sa
+      containers:
+        - name: web
+          image: ghcr.io/acme/web:1.4
+          envFrom:
+            - configMapRef:
+                name: web-config
+          env:
+            - name: DB_PASSWORD
+              valueFrom:
+                secretKeyRef:
+                  name: db-credentials
+                  key: password
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+`,
+		"deploy/ingress.yaml": `apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: public
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /
+            backend:
+              service:
+                name: web
+`,
+		"deploy/config.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web-config\ndata:\n  LOG_LEVEL: info\n",
+		"docs/mkdocs.yaml":   "site_name: Docs\n",
+	})
+
+	topology, err := Scan(root)
+	require.NoError(t, err)
+	require.Len(t, topology.Resources, 4)
+
+	deployment := findResource(topology, "Deployment/web")
+	require.NotNil(t, deployment)
+	assert.Equal(t, "deploy/web.yaml", deployment.File)
+	assert.Equal(t, 1, deployment.Line)
+	assert.Equal(t, []string{"ghcr.io/acme/web:1.4"}, deployment.Images)
+
+	links := make(map[string]*Link)
+	for _, link := range deployment.Links {
+		links[link.Relation+" "+link.Kind+"/"+link.Name] = link
+	}
+	require.Contains(t, links, "env-from ConfigMap/web-config")
+	assert.Equal(t, findResource(topology, "ConfigMap/web-config"), links["env-from ConfigMap/web-config"].Target)
+	require.Contains(t, links, "env-from Secret/db-credentials")
+	assert.Nil(t, links["env-from Secret/db-credentials"].Target, "secrets outside the repo stay unresolved")
+	assert.Contains(t, links, "runs-as ServiceAccount/web-sa")
+
+	service := findResource(topology, "Service/web")
+	require.NotNil(t, service)
+	assert.Equal(t, 26, service.Line)
+	require.Len(t, service.Links, 1)
+	assert.Equal(t, RelationSelects, service.Links[0].Relation)
+	assert.Equal(t, deployment, service.Links[0].Target)
+
+	ingress := findResource(topology, "Ingress/public")
+	require.NotNil(t, ingress)
+	require.Len(t, ingress.Links, 1)
+	assert.Equal(t, service, ingress.Links[0].Target)
+	assert.Equal(t, []*Resource{ingress}, topology.Referrers(service))
+}
+
+func TestScanHelmChart(t *testing.T) {
+	root := t.TempDir()
+	testutils.WriteTree(t, root, map[string]string{
+		"charts/api/Chart.yaml":  "apiVersion: v2\nname: api\nversion: 0.1.0\n",
+		"charts/api/values.yaml": "replicas: 2\n",
+		"charts/api/templates/deployment.yaml": `{{- if .Values.enabled }}
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "api.fullname" . }}
+spec:
+  template:
+    spec:
+      containers:
+        - name: api
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"
+      volumes:
+        - name: config
+          configMap:
+            name: {{ include "api.fullname" . }}
+{{- end }}
+`,
+		"charts/api/templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "api.fullname" . }}
+`,
+	})
+
+	topology, err := Scan(root)
+	require.NoError(t, err)
+	require.Len(t, topology.Resources, 2)
+
+	deployment := findResource(topology, "Deployment/tpl(include api.fullname .)")
+	require.NotNil(t, deployment, "templated names should render to a stable placeholder")
+	assert.Equal(t, "charts/api", deployment.Chart)
+	require.Len(t, deployment.Links, 1)
+	assert.Equal(t, findResource(topology, "ConfigMap/tpl(include api.fullname .)"), deployment.Links[0].Target)
+}
+
+func TestStripTemplates(t *testing.T) {
+	input := "{{- with .Values.labels }}\nname: {{ .Release.Name }}-web\n{{- end }}"
+	assert.Equal(t, "\nname: tpl(.Release.Name)-web\n", stripTemplates(input))
+}
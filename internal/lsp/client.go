@@ -0,0 +1,418 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+// shutdownTimeout bounds the shutdown handshake before a server is killed
+const shutdownTimeout = 2 * time.Second
+
+// message is a JSON-RPC 2.0 request, response or notification
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// document is a file opened in the server, resent when its content changes
+type document struct {
+	version int
+	text    string
+}
+
+// Client is a connection to one language server process over stdio
+type Client struct {
+	name string
+	cmd  *exec.Cmd
+
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+
+	mu        sync.Mutex
+	nextID    int
+	pending   map[string]chan *message
+	documents map[string]*document // By URI
+
+	done chan struct{} // Closed when the server's output ends
+	err  error         // Why it ended
+}
+
+// Start launches a language server for a project root and initializes it
+func Start(ctx context.Context, server Server, root string) (*Client, error) {
+	cmd := exec.Command(server.Command, server.Args...)
+	cmd.Dir = root
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", server.Command, err)
+	}
+	c := &Client{
+		name:      filepath.Base(server.Command),
+		cmd:       cmd,
+		stdin:     stdin,
+		pending:   make(map[string]chan *message),
+		documents: make(map[string]*document),
+		done:      make(chan struct{}),
+	}
+	go c.read(bufio.NewReader(stdout))
+
+	rootURI := fileURI(root)
+	params := map[string]interface{}{
+		"processId": os.Getpid(),
+		"rootUri":   rootURI,
+		"capabilities": map[string]interface{}{
+			"textDocument": map[string]interface{}{
+				"hover":      map[string]interface{}{"contentFormat": []string{"markdown", "plaintext"}},
+				"definition": map[string]interface{}{"linkSupport": true},
+			},
+			"workspace": map[string]interface{}{"configuration": true},
+		},
+		"workspaceFolders": []map[string]string{{"uri": rootURI, "name": filepath.Base(root)}},
+	}
+	if err := c.request(ctx, "initialize", params, nil); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("%s: initialize: %w", c.name, err)
+	}
+	if err := c.notify("initialized", map[string]interface{}{}); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Name returns the name of the server executable
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Hover returns the hover text of a position as markdown. Line is 1-based and
+// column a 0-based byte offset in the line.
+func (c *Client) Hover(ctx context.Context, path, languageID string, line, column int) (string, error) {
+	params, err := c.position(path, languageID, line, column)
+	if err != nil {
+		return "", err
+	}
+	var result json.RawMessage
+	if err := c.request(ctx, "textDocument/hover", params, &result); err != nil {
+		return "", fmt.Errorf("%s: hover: %w", c.name, err)
+	}
+	return hoverText(result), nil
+}
+
+// Definition returns where the symbol at a position is defined
+func (c *Client) Definition(ctx context.Context, path, languageID string, line, column int) ([]Location, error) {
+	params, err := c.position(path, languageID, line, column)
+	if err != nil {
+		return nil, err
+	}
+	var result json.RawMessage
+	if err := c.request(ctx, "textDocument/definition", params, &result); err != nil {
+		return nil, fmt.Errorf("%s: definition: %w", c.name, err)
+	}
+	return locations(result), nil
+}
+
+// Close shuts the server down, killing it when it does not exit in time
+func (c *Client) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if c.request(ctx, "shutdown", nil, nil) == nil {
+		c.notify("exit", nil)
+	}
+	c.stdin.Close()
+	exited := make(chan struct{})
+	go func() {
+		c.cmd.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(shutdownTimeout):
+		c.cmd.Process.Kill()
+		<-exited
+	}
+}
+
+// position opens or updates a document and returns the text document
+// position params of a line and byte column, converted to UTF-16
+func (c *Client) position(path, languageID string, line, column int) (map[string]interface{}, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	uri := fileURI(path)
+	if err := c.sync(uri, languageID, string(content)); err != nil {
+		return nil, err
+	}
+	character := column
+	if lines := strings.Split(string(content), "\n"); line >= 1 && line <= len(lines) {
+		text := lines[line-1]
+		character = len(utf16.Encode([]rune(text[:min(column, len(text))])))
+	}
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": uri},
+		"position":     map[string]int{"line": line - 1, "character": character},
+	}, nil
+}
+
+// sync opens a document, or sends its full text again when it changed
+func (c *Client) sync(uri, languageID, text string) error {
+	c.mu.Lock()
+	doc := c.documents[uri]
+	if doc != nil && doc.text == text {
+		c.mu.Unlock()
+		return nil
+	}
+	if doc == nil {
+		doc = &document{}
+		c.documents[uri] = doc
+	}
+	doc.version++
+	doc.text = text
+	version := doc.version
+	c.mu.Unlock()
+
+	if version == 1 {
+		return c.notify("textDocument/didOpen", map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "languageId": languageID, "version": version, "text": text},
+		})
+	}
+	return c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": version},
+		"contentChanges": []map[string]string{{"text": text}},
+	})
+}
+
+// request sends a request and decodes its result into result, if not nil
+func (c *Client) request(ctx context.Context, method string, params, result interface{}) error {
+	c.mu.Lock()
+	c.nextID++
+	id := strconv.Itoa(c.nextID)
+	ch := make(chan *message, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.write(&message{ID: json.RawMessage(id), Method: method, Params: marshal(params)}); err != nil {
+		return err
+	}
+	select {
+	case response := <-ch:
+		if response.Error != nil {
+			return fmt.Errorf("%s (code %d)", response.Error.Message, response.Error.Code)
+		}
+		if result != nil && len(response.Result) > 0 {
+			return json.Unmarshal(response.Result, result)
+		}
+		return nil
+	case <-c.done:
+		return fmt.Errorf("server exited: %v", c.err)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notify sends a notification
+func (c *Client) notify(method string, params interface{}) error {
+	return c.write(&message{Method: method, Params: marshal(params)})
+}
+
+// write sends a message with its Content-Length header
+func (c *Client) write(m *message) error {
+	m.JSONRPC = "2.0"
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := fmt.Fprintf(c.stdin, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.stdin.Write(body)
+	return err
+}
+
+// read dispatches the server's messages until its output ends: responses go
+// to their requests, and requests from the server get empty answers so it
+// never waits on the client
+func (c *Client) read(r *bufio.Reader) {
+	defer close(c.done)
+	headers := textproto.NewReader(r)
+	for {
+		header, err := headers.ReadMIMEHeader()
+		if err != nil {
+			c.err = err
+			return
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			c.err = fmt.Errorf("invalid Content-Length: %w", err)
+			return
+		}
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			c.err = err
+			return
+		}
+		var m message
+		if err := json.Unmarshal(body, &m); err != nil {
+			continue
+		}
+		switch {
+		case m.Method != "" && len(m.ID) > 0:
+			c.write(&message{ID: m.ID, Result: serverRequestResult(&m)})
+		case m.Method == "" && len(m.ID) > 0:
+			c.mu.Lock()
+			ch := c.pending[string(m.ID)]
+			c.mu.Unlock()
+			if ch != nil {
+				ch <- &m
+			}
+		}
+	}
+}
+
+// serverRequestResult answers a request from the server: no settings for
+// workspace/configuration, and null for anything else
+func serverRequestResult(m *message) json.RawMessage {
+	if m.Method == "workspace/configuration" {
+		var params struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		json.Unmarshal(m.Params, &params)
+		return marshal(make([]interface{}, len(params.Items)))
+	}
+	return json.RawMessage("null")
+}
+
+func marshal(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// hoverText returns the contents of a hover result as markdown. Contents are
+// a MarkupContent, a MarkedString or a list of MarkedStrings.
+func hoverText(raw json.RawMessage) string {
+	var hover struct {
+		Contents json.RawMessage `json:"contents"`
+	}
+	if err := json.Unmarshal(raw, &hover); err != nil || len(hover.Contents) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(markedText(hover.Contents))
+}
+
+func markedText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	var marked struct {
+		Language string `json:"language"`
+		Value    string `json:"value"`
+	}
+	if json.Unmarshal(raw, &marked) == nil {
+		if marked.Language != "" {
+			return "```" + marked.Language + "\n" + marked.Value + "\n```"
+		}
+		return marked.Value
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		var parts []string
+		for _, item := range list {
+			if part := strings.TrimSpace(markedText(item)); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		return strings.Join(parts, "\n\n")
+	}
+	return ""
+}
+
+// locations reads a definition result: a Location, a list of Locations or a
+// list of LocationLinks
+func locations(raw json.RawMessage) []Location {
+	type position struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	}
+	type location struct {
+		URI                  string                   `json:"uri"`
+		Range                struct{ Start position } `json:"range"`
+		TargetURI            string                   `json:"targetUri"`
+		TargetSelectionRange struct{ Start position } `json:"targetSelectionRange"`
+	}
+	var list []location
+	if json.Unmarshal(raw, &list) != nil {
+		var single location
+		if json.Unmarshal(raw, &single) != nil {
+			return nil
+		}
+		list = []location{single}
+	}
+	var result []Location
+	for _, l := range list {
+		uri, start := l.URI, l.Range.Start
+		if l.TargetURI != "" {
+			uri, start = l.TargetURI, l.TargetSelectionRange.Start
+		}
+		if path := LocalPath(uri); path != "" {
+			result = append(result, Location{Path: path, Line: start.Line + 1, Column: start.Character})
+		}
+	}
+	return result
+}
+
+// fileURI returns the file URI of a local path
+func fileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// LocalPath returns the local path of a file URI, or "" for other URIs
+func LocalPath(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return ""
+	}
+	return filepath.FromSlash(parsed.Path)
+}
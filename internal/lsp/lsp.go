@@ -0,0 +1,191 @@
+// Package lsp queries language servers such as gopls, typescript-language-server
+// (which drives tsserver) and pyright over the Language Server Protocol, for
+// precise hover and definition data on demand. Servers are started on first
+// use per project and kept running until closed.
+package lsp
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds starting a server or answering a query. The first
+// query may wait for the server to load the workspace.
+const DefaultTimeout = 15 * time.Second
+
+// Server is the command starting a language server speaking LSP on stdio
+type Server struct {
+	Command string   `json:"command,omitempty" mapstructure:"command"`
+	Args    []string `json:"args,omitempty" mapstructure:"args"`
+}
+
+// DefaultServers are the servers used per language unless configured otherwise
+var DefaultServers = map[string]Server{
+	"go":         {Command: "gopls"},
+	"typescript": {Command: "typescript-language-server", Args: []string{"--stdio"}},
+	"javascript": {Command: "typescript-language-server", Args: []string{"--stdio"}},
+	"python":     {Command: "pyright-langserver", Args: []string{"--stdio"}},
+}
+
+// Config selects the language servers queried in deep mode
+type Config struct {
+	Disabled bool              `json:"disabled,omitempty" mapstructure:"disabled"` // Never start language servers
+	Servers  map[string]Server `json:"servers,omitempty" mapstructure:"servers"`   // By language, over the defaults; an empty command turns one off
+	Timeout  time.Duration     `json:"timeout,omitempty" mapstructure:"timeout"`   // Per query, including the server's start
+}
+
+// Location is a position in a file
+type Location struct {
+	Path   string // Local path
+	Line   int    // 1-based
+	Column int    // 0-based, in UTF-16 code units as reported by the server
+}
+
+// Info is what a language server knows about the symbol at a position
+type Info struct {
+	Server      string // Executable name, such as gopls
+	Hover       string // Markdown: the type or signature, usually with its documentation
+	Definitions []Location
+}
+
+// Manager starts language servers on demand and routes queries to them. A
+// nil Manager answers nothing.
+type Manager struct {
+	servers map[string]Server
+	timeout time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*Client // By project root and command
+	failed  map[string]error   // Servers that could not start, not retried
+}
+
+// NewManager creates a manager, or returns nil when language servers are disabled
+func NewManager(config Config) *Manager {
+	if config.Disabled {
+		return nil
+	}
+	servers := make(map[string]Server, len(DefaultServers))
+	for language, server := range DefaultServers {
+		servers[language] = server
+	}
+	for language, server := range config.Servers {
+		language = strings.ToLower(language)
+		if server.Command == "" {
+			delete(servers, language)
+			continue
+		}
+		servers[language] = server
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Manager{servers: servers, timeout: timeout, clients: make(map[string]*Client), failed: make(map[string]error)}
+}
+
+// Languages returns the languages a server is configured for
+func (m *Manager) Languages() []string {
+	if m == nil {
+		return nil
+	}
+	var languages []string
+	for language := range m.servers {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Lookup asks the language server of a language about the symbol at a
+// position of a file in a project: line is 1-based and column a 0-based byte
+// offset. It returns nil without an error when no server is configured for
+// the language.
+func (m *Manager) Lookup(ctx context.Context, root, language, path string, line, column int) (*Info, error) {
+	if m == nil {
+		return nil, nil
+	}
+	server, ok := m.servers[strings.ToLower(language)]
+	if !ok {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	client, err := m.client(ctx, root, server)
+	if err != nil {
+		return nil, err
+	}
+
+	languageID := languageIdentifier(language, path)
+	info := &Info{Server: client.Name()}
+	if info.Hover, err = client.Hover(ctx, path, languageID, line, column); err != nil {
+		return nil, err
+	}
+	if info.Definitions, err = client.Definition(ctx, path, languageID, line, column); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Close shuts down every server started
+func (m *Manager) Close() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	clients := m.clients
+	m.clients = make(map[string]*Client)
+	m.mu.Unlock()
+	for _, client := range clients {
+		client.Close()
+	}
+}
+
+// client returns the running server of a project, starting it
+func (m *Manager) client(ctx context.Context, root string, server Server) (*Client, error) {
+	key := root + "\x00" + server.Command + "\x00" + strings.Join(server.Args, "\x00")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if client := m.clients[key]; client != nil {
+		select {
+		case <-client.done:
+			// Exited: start it again
+			delete(m.clients, key)
+		default:
+			return client, nil
+		}
+	}
+	if err := m.failed[key]; err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath(server.Command); err != nil {
+		m.failed[key] = fmt.Errorf("language server %s not found", server.Command)
+		return nil, m.failed[key]
+	}
+	client, err := Start(ctx, server, root)
+	if err != nil {
+		if ctx.Err() == nil {
+			m.failed[key] = err
+		}
+		return nil, err
+	}
+	m.clients[key] = client
+	return client, nil
+}
+
+// languageIdentifier returns the LSP language id of a file
+func languageIdentifier(language, path string) string {
+	language = strings.ToLower(language)
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case ext == ".tsx":
+		return "typescriptreact"
+	case ext == ".jsx":
+		return "javascriptreact"
+	}
+	return language
+}
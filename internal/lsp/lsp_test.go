@@ -0,0 +1,133 @@
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHelperProcess is not a real test: it is the language server started by
+// the Manager tests below. It asks for workspace configuration before its
+// first hover answer, like gopls does.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("CODECONTEXT_LSP_HELPER") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	r := bufio.NewReader(os.Stdin)
+	send := func(m map[string]interface{}) {
+		m["jsonrpc"] = "2.0"
+		body, _ := json.Marshal(m)
+		fmt.Fprintf(os.Stdout, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	texts := make(map[string]string)
+	asked := false
+	for {
+		header, err := textproto.NewReader(r).ReadMIMEHeader()
+		if err != nil {
+			return
+		}
+		length, _ := strconv.Atoi(header.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return
+		}
+		var m struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				TextDocument struct {
+					URI  string `json:"uri"`
+					Text string `json:"text"`
+				} `json:"textDocument"`
+				Position struct {
+					Line      int `json:"line"`
+					Character int `json:"character"`
+				} `json:"position"`
+			} `json:"params"`
+		}
+		json.Unmarshal(body, &m)
+		switch m.Method {
+		case "initialize":
+			send(map[string]interface{}{"id": m.ID, "result": map[string]interface{}{"capabilities": map[string]interface{}{"hoverProvider": true}}})
+		case "textDocument/didOpen":
+			texts[m.Params.TextDocument.URI] = m.Params.TextDocument.Text
+		case "textDocument/hover":
+			if !asked {
+				send(map[string]interface{}{"id": 99, "method": "workspace/configuration", "params": map[string]interface{}{"items": []interface{}{map[string]string{"section": "gopls"}}}})
+				asked = true
+			}
+			send(map[string]interface{}{"id": m.ID, "result": map[string]interface{}{"contents": map[string]string{
+				"kind":  "markdown",
+				"value": fmt.Sprintf("```go\nfunc Total() int\n```\n\nat %d:%d of %d bytes", m.Params.Position.Line, m.Params.Position.Character, len(texts[m.Params.TextDocument.URI])),
+			}}})
+		case "textDocument/definition":
+			send(map[string]interface{}{"id": m.ID, "result": []interface{}{map[string]interface{}{
+				"targetUri":            m.Params.TextDocument.URI,
+				"targetSelectionRange": map[string]interface{}{"start": map[string]int{"line": 2, "character": 5}},
+			}}})
+		case "shutdown":
+			send(map[string]interface{}{"id": m.ID, "result": nil})
+		case "exit":
+			return
+		}
+	}
+}
+
+func TestManagerLookup(t *testing.T) {
+	t.Setenv("CODECONTEXT_LSP_HELPER", "1")
+	dir := t.TempDir()
+	source := "package shop\n\nfunc Total() int { return 0 } // é\n"
+	path := filepath.Join(dir, "total.go")
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+
+	manager := NewManager(Config{
+		Servers: map[string]Server{"Go": {Command: os.Args[0], Args: []string{"-test.run=TestHelperProcess"}}, "python": {}},
+		Timeout: 30 * time.Second,
+	})
+	defer manager.Close()
+	assert.Equal(t, []string{"go", "javascript", "typescript"}, manager.Languages(), "an empty command turns a default off")
+
+	info, err := manager.Lookup(context.Background(), dir, "go", path, 3, 5)
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, filepath.Base(os.Args[0]), info.Server)
+	assert.Equal(t, fmt.Sprintf("```go\nfunc Total() int\n```\n\nat 2:5 of %d bytes", len(source)), info.Hover)
+	assert.Equal(t, []Location{{Path: path, Line: 3, Column: 5}}, info.Definitions)
+
+	info, err = manager.Lookup(context.Background(), dir, "rust", path, 3, 5)
+	assert.NoError(t, err)
+	assert.Nil(t, info, "no server for the language")
+}
+
+func TestManagerUnavailable(t *testing.T) {
+	manager := NewManager(Config{Servers: map[string]Server{"go": {Command: "codecontext-no-such-server"}}})
+	_, err := manager.Lookup(context.Background(), t.TempDir(), "go", "x.go", 1, 0)
+	assert.ErrorContains(t, err, "not found")
+
+	assert.Nil(t, NewManager(Config{Disabled: true}))
+	var disabled *Manager
+	info, err := disabled.Lookup(context.Background(), t.TempDir(), "go", "x.go", 1, 0)
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+func TestHoverText(t *testing.T) {
+	assert.Equal(t, "plain", hoverText(json.RawMessage(`{"contents":"plain"}`)))
+	assert.Equal(t, "```python\ndef f()\n```\n\nDocs", hoverText(json.RawMessage(`{"contents":[{"language":"python","value":"def f()"},"Docs"]}`)))
+	assert.Equal(t, "", hoverText(json.RawMessage(`null`)))
+	assert.Nil(t, locations(json.RawMessage(`null`)))
+	assert.Equal(t, []Location{{Path: "/a.ts", Line: 1, Column: 2}}, locations(json.RawMessage(`{"uri":"file:///a.ts","range":{"start":{"line":0,"character":2}}}`)))
+}
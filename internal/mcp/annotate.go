@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/annotations"
+)
+
+type AnnotateArgs struct {
+	Action    string   `json:"action,omitempty"`     // add (default), remove or list
+	Target    string   `json:"target,omitempty"`     // File path, optionally with #SymbolName
+	Text      string   `json:"text,omitempty"`       // Note to attach (add)
+	Author    string   `json:"author,omitempty"`     // Optional: who wrote the note (add)
+	Tags      []string `json:"tags,omitempty"`       // Optional: labels such as "gotcha" or "todo" (add)
+	ID        string   `json:"id,omitempty"`         // Annotation to delete (remove)
+	TargetDir string   `json:"target_dir,omitempty"` // Optional: project directory
+}
+
+func (s *CodeContextMCPServer) annotate(ctx context.Context, req *mcp.CallToolRequest, args AnnotateArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: annotate with args: %+v", args)
+	start := time.Now()
+
+	// Annotations live with the project, so no analysis is needed
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, symbol := annotations.ParseTarget(args.Target)
+	if file != "" {
+		file = annotations.RelativePath(file, targetDir)
+	}
+
+	if s.currentConfig().ReadOnly && args.Action != "list" {
+		log.Printf("[MCP] AUDIT: Denied annotate %s in read-only mode", args.Action)
+		return nil, nil, fmt.Errorf("annotations cannot be changed: server is read-only")
+	}
+
+	var text string
+	switch args.Action {
+	case "", "add":
+		if file == "" {
+			return nil, nil, fmt.Errorf("target is required")
+		}
+		var added annotations.Annotation
+		err := annotations.Update(targetDir, func(store *annotations.Store) error {
+			var err error
+			added, err = store.Add(annotations.Annotation{File: file, Symbol: symbol, Text: args.Text, Author: args.Author, Tags: args.Tags})
+			return err
+		})
+		if err != nil {
+			log.Printf("[MCP] ERROR: Failed to add annotation: %v", err)
+			return nil, nil, err
+		}
+		text = fmt.Sprintf("Added annotation %s to `%s`.\n", added.ID, added.Target())
+	case "remove":
+		if args.ID == "" {
+			return nil, nil, fmt.Errorf("id is required")
+		}
+		var removed annotations.Annotation
+		err := annotations.Update(targetDir, func(store *annotations.Store) error {
+			var err error
+			removed, err = store.Remove(args.ID)
+			return err
+		})
+		if err != nil {
+			log.Printf("[MCP] ERROR: Failed to remove annotation: %v", err)
+			return nil, nil, err
+		}
+		text = fmt.Sprintf("Removed annotation %s from `%s`.\n", removed.ID, removed.Target())
+	case "list":
+		store, err := annotations.Load(targetDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		notes := store.Annotations
+		switch {
+		case symbol != "":
+			notes = store.ForSymbol(file, symbol)
+		case file != "":
+			notes = store.ForFile(file)
+		}
+		text = fmt.Sprintf("# Annotations (%d)\n\n", len(notes))
+		if len(notes) == 0 {
+			text += "_No annotations_\n"
+		}
+		for _, note := range notes {
+			text += fmt.Sprintf("- `%s`: %s _(%s)_\n", note.Target(), note.Text, note.Details())
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown action %q (use add, remove or list)", args.Action)
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: annotate (took %v)", elapsed)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
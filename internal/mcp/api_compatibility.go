@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/apidiff"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+)
+
+type CheckAPICompatibilityArgs struct {
+	From      string `json:"from"`                 // Older git ref: tag, branch or commit
+	To        string `json:"to,omitempty"`         // Optional: newer git ref (default: the working tree)
+	FailOn    string `json:"fail_on,omitempty"`    // Optional: lowest severity that makes the change incompatible (default: error)
+	Format    string `json:"format,omitempty"`     // Optional: "markdown" (default) or "sarif"
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) checkAPICompatibility(ctx context.Context, req *mcp.CallToolRequest, args CheckAPICompatibilityArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: check_api_compatibility with args: %+v", args)
+	start := time.Now()
+
+	if args.From == "" {
+		return nil, nil, fmt.Errorf("from is required")
+	}
+	failOn := args.FailOn
+	if failOn == "" {
+		failOn = rules.SeverityError
+	}
+	if !rules.ValidSeverity(failOn) {
+		return nil, nil, fmt.Errorf("invalid fail_on severity %q (use error, warning or note)", failOn)
+	}
+	if args.Format != "" && args.Format != "markdown" && args.Format != "sarif" {
+		return nil, nil, fmt.Errorf("unknown format %q (use markdown or sarif)", args.Format)
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := s.resolveProfile(args.Profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Both revisions are analyzed from git, independently of the live graph
+	diff, err := apidiff.CompareRefs(ctx, targetDir, args.From, args.To, s.analyzeWith(profile))
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to compare refs: %v", err)
+		return nil, nil, err
+	}
+	violations := diff.Incompatibilities()
+
+	text := diff.CompatibilityReport(violations, failOn)
+	if args.Format == "sarif" {
+		data, err := rules.SARIF(violations, apidiff.CompatibilityRules, s.currentConfig().Version)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build SARIF log: %w", err)
+		}
+		text = string(data)
+	}
+
+	log.Printf("[MCP] Tool completed: check_api_compatibility (took %v, %d findings)", time.Since(start), len(violations))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
@@ -0,0 +1,29 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAPICompatibilityErrors(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = t.TempDir()
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, _, err = server.checkAPICompatibility(ctx, nil, CheckAPICompatibilityArgs{})
+	assert.ErrorContains(t, err, "from is required")
+
+	_, _, err = server.checkAPICompatibility(ctx, nil, CheckAPICompatibilityArgs{From: "v1.0.0", FailOn: "fatal"})
+	assert.ErrorContains(t, err, "invalid fail_on severity")
+
+	_, _, err = server.checkAPICompatibility(ctx, nil, CheckAPICompatibilityArgs{From: "v1.0.0", Format: "html"})
+	assert.ErrorContains(t, err, "unknown format")
+
+	_, _, err = server.checkAPICompatibility(ctx, nil, CheckAPICompatibilityArgs{From: "v1.0.0"})
+	assert.ErrorContains(t, err, "not a git repository")
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetAssetUsagesArgs struct {
+	Asset     string `json:"asset,omitempty"`      // Optional: only assets whose path contains this text, with every reference listed
+	Kind      string `json:"kind,omitempty"`       // Optional: only image, style, data, font, media, document or markup assets
+	Unused    bool   `json:"unused,omitempty"`     // Optional: only assets nothing refers to
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum assets listed (default 50)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getAssetUsages(ctx context.Context, req *mcp.CallToolRequest, args GetAssetUsagesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_asset_usages with args: %+v", args)
+	start := time.Now()
+
+	switch args.Kind {
+	case "", analyzer.AssetImage, analyzer.AssetStyle, analyzer.AssetData, analyzer.AssetFont, analyzer.AssetMedia, analyzer.AssetDocument, analyzer.AssetMarkup:
+	default:
+		return nil, nil, fmt.Errorf("unknown asset kind %q (use image, style, data, font, media, document or markup)", args.Kind)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	report := analyzer.FindAssetUsages(graph, targetDir)
+	var assets []analyzer.AssetUsage
+	for _, asset := range report.Assets {
+		if (args.Asset != "" && !strings.Contains(asset.Path, args.Asset)) ||
+			(args.Kind != "" && asset.Kind != args.Kind) ||
+			(args.Unused && len(asset.References) > 0) {
+			continue
+		}
+		assets = append(assets, asset)
+	}
+	var missing []analyzer.AssetReference
+	for _, ref := range report.Missing {
+		if args.Asset == "" || strings.Contains(ref.Text, args.Asset) {
+			missing = append(missing, ref)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Asset Usages\n\n")
+	if len(assets) == 0 {
+		result.WriteString("_No matching assets found_\n")
+	} else if args.Asset != "" {
+		// Everything that breaks if the asset is renamed or removed
+		for i, asset := range assets {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("_... and %d more assets_\n\n", len(assets)-i))
+				break
+			}
+			result.WriteString(fmt.Sprintf("## %s (%s)\n\n", asset.Path, asset.Kind))
+			if len(asset.References) == 0 {
+				result.WriteString("_Not referenced from the code_\n\n")
+				continue
+			}
+			result.WriteString(fmt.Sprintf("**References:** %d\n\n", len(asset.References)))
+			for _, ref := range asset.References {
+				result.WriteString(fmt.Sprintf("- `%s:%d` — `%s`\n", ref.File, ref.Line, ref.Text))
+			}
+			result.WriteString("\n")
+		}
+	} else {
+		referenced := 0
+		for _, asset := range assets {
+			if len(asset.References) > 0 {
+				referenced++
+			}
+		}
+		result.WriteString(fmt.Sprintf("**Assets:** %d, %d referenced\n\n", len(assets), referenced))
+		result.WriteString("| Asset | Kind | References | Files |\n")
+		result.WriteString("|-------|------|------------|-------|\n")
+		for i, asset := range assets {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("\n_... and %d more assets_\n", len(assets)-i))
+				break
+			}
+			files := make(map[string]bool)
+			for _, ref := range asset.References {
+				files[ref.File] = true
+			}
+			result.WriteString(fmt.Sprintf("| `%s` | %s | %d | %d |\n", asset.Path, asset.Kind, len(asset.References), len(files)))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(missing) > 0 && !args.Unused {
+		result.WriteString("## Missing Assets\n\nReferences to files that do not exist:\n\n")
+		for _, ref := range missing {
+			result.WriteString(fmt.Sprintf("- `%s:%d` — `%s`\n", ref.File, ref.Line, ref.Text))
+		}
+	}
+
+	log.Printf("[MCP] Tool completed: get_asset_usages (took %v, %d assets)", time.Since(start), len(assets))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
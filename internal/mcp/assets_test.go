@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAssetUsages(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"public/logo.png":   "png",
+		"public/banner.png": "png",
+		"src/Header.tsx":    "export function Header() {\n  return <img src=\"/logo.png\" />;\n}\n",
+		"src/Footer.tsx":    "import logo from '../public/logo.png';\nimport icon from './icon.svg';\n\nexport const Footer = () => logo;\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getAssetUsages(ctx, nil, GetAssetUsagesArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Assets:** 2, 1 referenced")
+	assert.Contains(t, text, "| `public/logo.png` | image | 2 | 2 |")
+	assert.Contains(t, text, "## Missing Assets\n\nReferences to files that do not exist:\n\n- `src/Footer.tsx:2` — `./icon.svg`")
+
+	// Everything that breaks when the logo is renamed
+	response, _, err = server.getAssetUsages(ctx, nil, GetAssetUsagesArgs{Asset: "logo.png"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## public/logo.png (image)\n\n**References:** 2\n\n- `src/Footer.tsx:1` — `../public/logo.png`\n- `src/Header.tsx:2` — `/logo.png`")
+	assert.NotContains(t, text, "Missing Assets")
+
+	response, _, err = server.getAssetUsages(ctx, nil, GetAssetUsagesArgs{Unused: true})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "`public/banner.png`")
+	assert.NotContains(t, text, "`public/logo.png`")
+
+	_, _, err = server.getAssetUsages(ctx, nil, GetAssetUsagesArgs{Kind: "video"})
+	assert.ErrorContains(t, err, "unknown asset kind")
+}
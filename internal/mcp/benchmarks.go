@@ -0,0 +1,150 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+type GetBenchmarksArgs struct {
+	FilePath  string `json:"file_path,omitempty"`  // Optional: only benchmarks exercising files whose path contains this text
+	Symbol    string `json:"symbol,omitempty"`     // Optional: only benchmarks exercising functions with this name
+	Framework string `json:"framework,omitempty"`  // Optional: only go, criterion, libtest, divan or jmh benchmarks
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum benchmarks listed (default 50)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getBenchmarks(ctx context.Context, req *mcp.CallToolRequest, args GetBenchmarksArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_benchmarks with args: %+v", args)
+	start := time.Now()
+
+	switch args.Framework {
+	case "", analyzer.BenchmarkGo, analyzer.BenchmarkCriterion, analyzer.BenchmarkLibtest, analyzer.BenchmarkDivan, analyzer.BenchmarkJMH:
+	default:
+		return nil, nil, fmt.Errorf("unknown benchmark framework %q (use go, criterion, libtest, divan or jmh)", args.Framework)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+	var benchmarks []analyzer.Benchmark
+	for _, benchmark := range analyzer.FindBenchmarks(graph) {
+		if args.Framework != "" && benchmark.Framework != args.Framework {
+			continue
+		}
+		benchmark.File = relative(benchmark.File)
+		matches := args.FilePath == "" && args.Symbol == ""
+		for i, target := range benchmark.Targets {
+			target.File = relative(target.File)
+			benchmark.Targets[i] = target
+			if (args.FilePath == "" || strings.Contains(target.File, args.FilePath)) &&
+				(args.Symbol == "" || target.Name == args.Symbol) {
+				matches = true
+			}
+		}
+		if matches {
+			benchmarks = append(benchmarks, benchmark)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Benchmarks\n\n")
+	if len(benchmarks) == 0 {
+		result.WriteString("_No benchmarks found_\n")
+	} else {
+		result.WriteString(fmt.Sprintf("**Benchmarks:** %d\n\n", len(benchmarks)))
+		if args.FilePath != "" || args.Symbol != "" {
+			result.WriteString("Run these after changing the matching code to catch performance regressions.\n\n")
+		}
+		for i, benchmark := range benchmarks {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("_... and %d more benchmarks_\n", len(benchmarks)-i))
+				break
+			}
+			result.WriteString(fmt.Sprintf("## `%s` (%s)\n\n", benchmark.Name, benchmark.Framework))
+			result.WriteString(fmt.Sprintf("**Location:** `%s:%d`\n", benchmark.File, benchmark.Line))
+			result.WriteString(fmt.Sprintf("**Run:** `%s`\n", benchmarkCommand(benchmark)))
+			if len(benchmark.Targets) == 0 {
+				result.WriteString("**Exercises:** _no functions found in the graph_\n\n")
+				continue
+			}
+			result.WriteString("**Exercises:**\n")
+			for _, target := range benchmark.Targets {
+				result.WriteString(fmt.Sprintf("- `%s` (`%s:%d`)\n", target.Name, target.File, target.Line))
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	log.Printf("[MCP] Tool completed: get_benchmarks (took %v, %d benchmarks)", time.Since(start), len(benchmarks))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// benchmarkedBy returns the names of the benchmarks exercising a symbol
+func benchmarkedBy(graph *types.CodeGraph, id types.SymbolId) []string {
+	node := types.NodeId("symbol-" + string(id))
+	var names []string
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipBenchmarks) || edge.To != node {
+			continue
+		}
+		if benchmark := graph.Symbols[types.SymbolId(strings.TrimPrefix(string(edge.From), "symbol-"))]; benchmark != nil {
+			names = append(names, "`"+benchmark.Name+"`")
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// benchmarkCommand returns the command running a single benchmark, given
+// its file relative to the project root
+func benchmarkCommand(benchmark analyzer.Benchmark) string {
+	switch benchmark.Framework {
+	case analyzer.BenchmarkGo:
+		pkg := "./" + filepath.ToSlash(filepath.Dir(benchmark.File))
+		if pkg == "./." {
+			pkg = "."
+		}
+		return fmt.Sprintf("go test -run '^$' -bench '^%s$' %s", benchmark.Name, pkg)
+	case analyzer.BenchmarkJMH:
+		class := strings.TrimSuffix(filepath.Base(benchmark.File), filepath.Ext(benchmark.File))
+		return fmt.Sprintf("java -jar benchmarks.jar '%s.%s'", class, benchmark.Name)
+	default:
+		// Cargo names bench targets after their file in benches/
+		if dir := filepath.Base(filepath.Dir(benchmark.File)); dir == "benches" {
+			target := strings.TrimSuffix(filepath.Base(benchmark.File), filepath.Ext(benchmark.File))
+			return fmt.Sprintf("cargo bench --bench %s", target)
+		}
+		return fmt.Sprintf("cargo bench %s", benchmark.Name)
+	}
+}
@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBenchmarks(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":              "module example.com/shop\n\ngo 1.22\n",
+		"cart/cart.go":        "package cart\n\nfunc Total(items []int) int {\n\treturn len(items)\n}\n",
+		"cart/cart_test.go":   "package cart\n\nimport \"testing\"\n\nfunc BenchmarkTotal(b *testing.B) {\n\tfor i := 0; i < b.N; i++ {\n\t\tTotal(nil)\n\t}\n}\n",
+		"store/store.go":      "package store\n\nfunc Open() {}\n",
+		"store/store_test.go": "package store\n\nimport \"testing\"\n\nfunc BenchmarkOpen(b *testing.B) {\n\tOpen()\n}\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	response, _, err := server.getBenchmarks(context.Background(), nil, GetBenchmarksArgs{FilePath: "cart/"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Benchmarks:** 1")
+	assert.Contains(t, text, "## `BenchmarkTotal` (go)")
+	assert.Contains(t, text, "**Run:** `go test -run '^$' -bench '^BenchmarkTotal$' ./cart`")
+	assert.Contains(t, text, "- `Total` (`cart/cart.go:3`)")
+	assert.NotContains(t, text, "BenchmarkOpen")
+
+	response, _, err = server.getSymbolInfo(context.Background(), nil, GetSymbolInfoArgs{SymbolName: "Total"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Benchmarked by:** `BenchmarkTotal`")
+
+	_, _, err = server.getBenchmarks(context.Background(), nil, GetBenchmarksArgs{Framework: "pytest"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/buildsys"
+)
+
+type GetBuildTargetsArgs struct {
+	FilePath     string   `json:"file_path,omitempty"`     // Show targets that compile this file
+	ChangedFiles []string `json:"changed_files,omitempty"` // Show targets affected by these changes
+	System       string   `json:"system,omitempty"`        // Filter by cmake, bazel or cargo
+	TargetDir    string   `json:"target_dir,omitempty"`    // Optional: directory to analyze
+}
+
+func (s *CodeContextMCPServer) getBuildTargets(ctx context.Context, req *mcp.CallToolRequest, args GetBuildTargetsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_build_targets with args: %+v", args)
+	start := time.Now()
+
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	index, err := buildsys.Scan(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to scan build files: %v", err)
+		return nil, nil, fmt.Errorf("failed to scan build files: %w", err)
+	}
+
+	var result string
+	switch {
+	case len(args.ChangedFiles) > 0:
+		result = "# Affected Build Targets\n\n"
+		result += fmt.Sprintf("**Changed files:** %s\n\n", strings.Join(args.ChangedFiles, ", "))
+		result += formatBuildTargets(filterBuildTargets(index.AffectedTargets(args.ChangedFiles), args.System), "_No build targets are affected by these files_\n")
+	case args.FilePath != "":
+		result = fmt.Sprintf("# Build Targets for %s\n\n", args.FilePath)
+		result += formatBuildTargets(filterBuildTargets(index.TargetsForFile(args.FilePath), args.System), "_No build target compiles this file_\n")
+	default:
+		result = "# Build Targets\n\n"
+		result += formatBuildTargets(filterBuildTargets(index.Targets, args.System), "_No CMake, Bazel or Cargo targets found_\n")
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_build_targets (took %v)", elapsed)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result}},
+	}, nil, nil
+}
+
+// filterBuildTargets keeps targets from the requested build system
+func filterBuildTargets(targets []*buildsys.Target, system string) []*buildsys.Target {
+	if system == "" {
+		return targets
+	}
+	var filtered []*buildsys.Target
+	for _, target := range targets {
+		if strings.EqualFold(string(target.System), system) {
+			filtered = append(filtered, target)
+		}
+	}
+	return filtered
+}
+
+// formatBuildTargets renders targets grouped by their build file
+func formatBuildTargets(targets []*buildsys.Target, empty string) string {
+	if len(targets) == 0 {
+		return empty
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("**Total targets:** %d\n\n", len(targets)))
+
+	currentFile := ""
+	for _, target := range targets {
+		if target.BuildFile != currentFile {
+			currentFile = target.BuildFile
+			sb.WriteString(fmt.Sprintf("## %s\n\n", currentFile))
+		}
+		sb.WriteString(fmt.Sprintf("- **%s** (%s %s, `%s`) — %d sources\n", target.Name, target.System, target.Kind, target.Rule, len(target.Sources)))
+		if len(target.Dependencies) > 0 {
+			sb.WriteString(fmt.Sprintf("  - Depends on: %s\n", strings.Join(target.Dependencies, ", ")))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/git"
+)
+
+type GetBusFactorArgs struct {
+	Days       int    `json:"days,omitempty"`        // Optional: history window in days (default 90)
+	MinChanges int    `json:"min_changes,omitempty"` // Optional: ignore modules with fewer file changes (default 5)
+	Path       string `json:"path,omitempty"`        // Optional: only modules whose directory starts with this path
+	Limit      int    `json:"limit,omitempty"`       // Optional: maximum modules listed (default 30)
+	TargetDir  string `json:"target_dir,omitempty"`  // Optional: directory to analyze
+	Profile    string `json:"profile,omitempty"`     // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getBusFactor(ctx context.Context, req *mcp.CallToolRequest, args GetBusFactorArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_bus_factor with args: %+v", args)
+	start := time.Now()
+
+	if args.Days <= 0 {
+		args.Days = 90
+	}
+	if args.MinChanges <= 0 {
+		args.MinChanges = 5
+	}
+	if args.Limit <= 0 {
+		args.Limit = 30
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	repo, err := git.NewGitAnalyzer(targetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	commits, err := repo.GetRecentCommits(ctx, args.Days)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to read git history: %v", err)
+		return nil, nil, fmt.Errorf("failed to read git history: %w", err)
+	}
+
+	// Only analyzed source files count, so lockfiles and generated output do not
+	sourceFiles := make(map[string]bool, len(graph.Files))
+	for path := range graph.Files {
+		if rel, err := filepath.Rel(targetDir, path); err == nil {
+			sourceFiles[filepath.ToSlash(rel)] = true
+		}
+	}
+	prefix := strings.Trim(filepath.ToSlash(args.Path), "/")
+	var modules []git.ModuleOwnership
+	for _, module := range git.ComputeOwnership(commits, func(file string) bool { return sourceFiles[file] }) {
+		if module.Changes < args.MinChanges {
+			continue
+		}
+		if prefix != "" && module.Module != prefix && !strings.HasPrefix(module.Module, prefix+"/") {
+			continue
+		}
+		modules = append(modules, module)
+	}
+	// Riskiest first: lowest bus factor, then most concentrated, then most changed
+	sort.SliceStable(modules, func(i, j int) bool {
+		a, b := modules[i], modules[j]
+		if a.BusFactor != b.BusFactor {
+			return a.BusFactor < b.BusFactor
+		}
+		if a.TopShare() != b.TopShare() {
+			return a.TopShare() > b.TopShare()
+		}
+		return a.Changes > b.Changes
+	})
+
+	var result strings.Builder
+	result.WriteString("# Bus Factor\n\n")
+	if len(modules) == 0 {
+		result.WriteString(fmt.Sprintf("_No modules with at least %d source file changes in the last %d days_\n", args.MinChanges, args.Days))
+	} else {
+		atRisk := 0
+		for _, module := range modules {
+			if module.AtRisk() {
+				atRisk++
+			}
+		}
+		result.WriteString(fmt.Sprintf("**Period:** last %d days | **Modules:** %d | **At risk:** %d (one author wrote more than %.0f%% of the changes)\n\n",
+			args.Days, len(modules), atRisk, git.SingleOwnerShare*100))
+		result.WriteString("The bus factor is the smallest number of people who made more than half of a module's recent changes; co-authors credited with `Co-authored-by` count too.\n\n")
+		result.WriteString("| Module | Commits | Changes | Authors | Bus factor | Main author |\n")
+		result.WriteString("|--------|---------|---------|---------|------------|-------------|\n")
+		shown := modules
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, module := range shown {
+			main := module.Authors[0]
+			marker := ""
+			if module.AtRisk() {
+				marker = " ⚠️"
+			}
+			result.WriteString(fmt.Sprintf("| `%s`%s | %d | %d | %d | %d | %s (%.0f%%) |\n",
+				module.Module, marker, module.Commits, module.Changes, len(module.Authors), module.BusFactor, main.Name, main.Share*100))
+		}
+		if len(shown) < len(modules) {
+			result.WriteString(fmt.Sprintf("\n_... and %d more modules_\n", len(modules)-len(shown)))
+		}
+	}
+
+	log.Printf("[MCP] Tool completed: get_bus_factor (took %v, %d modules)", time.Since(start), len(modules))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBusFactor(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	git := func(author string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=" + author, "-c", "user.email=" + author + "@example.com"}, args...)...)
+		cmd.Dir = tmpDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	commit := func(author, file string, n int) {
+		path := filepath.Join(tmpDir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("package %s\n\nconst Version = %d\n", filepath.Base(filepath.Dir(path)), n)), 0644))
+		git(author, "add", "-A")
+		git(author, "commit", "-q", "-m", fmt.Sprintf("change %s %d", file, n))
+	}
+
+	git("ana", "init", "-q")
+	for i := 0; i < 6; i++ {
+		commit("ana", "billing/invoice.go", i)
+	}
+	for i := 0; i < 6; i++ {
+		author := []string{"ana", "bo", "cy"}[i%3]
+		commit(author, "api/routes.go", i)
+	}
+	commit("bo", "docs/notes.go", 1)
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	response, _, err := server.getBusFactor(context.Background(), nil, GetBusFactorArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Modules:** 2 | **At risk:** 1")
+	assert.Contains(t, text, "| `billing` ⚠️ | 6 | 6 | 1 | 1 | ana (100%) |")
+	assert.Contains(t, text, "| `api` | 6 | 6 | 3 | 2 | ana (33%) |")
+	assert.NotContains(t, text, "docs", "modules below min_changes are left out")
+
+	response, _, err = server.getBusFactor(context.Background(), nil, GetBusFactorArgs{Path: "api/", MinChanges: 1})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Modules:** 1 | **At risk:** 0")
+	assert.NotContains(t, text, "billing")
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+)
+
+type CheckRulesArgs struct {
+	Rule      string `json:"rule,omitempty"`       // Optional: only check the rule with this id
+	Format    string `json:"format,omitempty"`     // Optional: "markdown" (default) or "sarif"
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+}
+
+// loadRules validates the rules from the config
+func loadRules(configs []rules.Config) []*rules.Rule {
+	loaded, errs := rules.Load(configs)
+	for _, err := range errs {
+		log.Printf("[MCP] WARNING: Skipping rule: %v", err)
+	}
+	return loaded
+}
+
+func (s *CodeContextMCPServer) checkRules(ctx context.Context, req *mcp.CallToolRequest, args CheckRulesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: check_rules with args: %+v", args)
+	start := time.Now()
+
+	loaded := s.currentRules()
+	selected := loaded
+	if args.Rule != "" {
+		selected = nil
+		for _, rule := range loaded {
+			if rule.ID() == args.Rule {
+				selected = append(selected, rule)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, nil, fmt.Errorf("unknown rule %q", args.Rule)
+		}
+	}
+	if len(selected) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No rules configured. Add them under \"rules\" in .codecontext/config.yaml."}},
+		}, nil, nil
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	violations := rules.Check(graph, targetDir, selected)
+	var text string
+	switch args.Format {
+	case "", "markdown":
+		text = rules.Markdown(violations, selected)
+	case "sarif":
+		data, err := rules.SARIF(violations, selected, s.currentConfig().Version)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to build SARIF log: %w", err)
+		}
+		text = string(data)
+	default:
+		return nil, nil, fmt.Errorf("unknown format %q (use markdown or sarif)", args.Format)
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: check_rules (took %v, %d violations)", elapsed, len(violations))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
@@ -0,0 +1,109 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/graphdiff"
+)
+
+type CompareGraphsArgs struct {
+	From      string `json:"from"`                 // Older side: a directory (relative to the target directory or absolute) or a git ref
+	To        string `json:"to,omitempty"`         // Optional: newer side, a directory or git ref (default: the working tree)
+	Format    string `json:"format,omitempty"`     // Optional: "markdown" (default) or "json"
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum entries per markdown list (default 50)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) compareGraphs(ctx context.Context, req *mcp.CallToolRequest, args CompareGraphsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: compare_graphs with args: %+v", args)
+	start := time.Now()
+
+	if args.From == "" {
+		return nil, nil, fmt.Errorf("from is required")
+	}
+	if args.Format != "" && args.Format != "markdown" && args.Format != "json" {
+		return nil, nil, fmt.Errorf("unknown format %q (use markdown or json)", args.Format)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := s.resolveProfile(args.Profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	analyze := s.analyzeWith(profile)
+
+	// Both sides are analyzed independently of the live graph
+	oldSnapshot, err := s.loadGraphSnapshot(ctx, targetDir, args.From, analyze)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to analyze %q: %v", args.From, err)
+		return nil, nil, err
+	}
+	newSnapshot, err := s.loadGraphSnapshot(ctx, targetDir, args.To, analyze)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to analyze %q: %v", args.To, err)
+		return nil, nil, err
+	}
+	diff := graphdiff.Compare(oldSnapshot, newSnapshot)
+	diff.From, diff.To = args.From, args.To
+	if diff.To == "" {
+		diff.To = "working tree"
+	}
+
+	text := diff.Summary(args.Limit)
+	if args.Format == "json" {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode diff: %w", err)
+		}
+		text = string(data)
+	}
+
+	log.Printf("[MCP] Tool completed: compare_graphs (took %v, files +%d -%d, symbols +%d -%d, edges +%d -%d)", time.Since(start),
+		len(diff.AddedFiles), len(diff.RemovedFiles), len(diff.AddedSymbols), len(diff.RemovedSymbols), len(diff.AddedEdges), len(diff.RemovedEdges))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
+
+// loadGraphSnapshot analyzes one side of a graph comparison: the working
+// tree of targetDir when source is empty, a directory when one exists at
+// source, and otherwise the git ref source names in targetDir's repository
+func (s *CodeContextMCPServer) loadGraphSnapshot(ctx context.Context, targetDir, source string, analyze analyzer.AnalyzeFunc) (*graphdiff.Snapshot, error) {
+	if source == "" {
+		return graphdiff.LoadDir(targetDir, analyze)
+	}
+	dir := expandPath(source)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(targetDir, dir)
+	}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		if err := s.sandbox.check(dir); err != nil {
+			log.Printf("[MCP] AUDIT: Denied access to %q: %v", source, err)
+			return nil, err
+		}
+		return graphdiff.LoadDir(dir, analyze)
+	}
+	repo, err := git.NewGitAnalyzer(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	return graphdiff.LoadRef(ctx, repo, source, analyze)
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/graphdiff"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareGraphs(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"app/src/index.js":      "import { format } from './format';\n\nexport function start() { return format(1); }\n",
+		"app/src/format.js":     "export function format(value) { return String(value); }\n",
+		"baseline/src/index.js": "export function start() { return 1; }\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = filepath.Join(tmpDir, "app")
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.compareGraphs(ctx, nil, CompareGraphsArgs{From: "../baseline"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "# Graph Diff: ../baseline → working tree\n\n**Files:** +1 −0 | **Symbols:** +1 −0 | **Edges:** +1 −0\n")
+	assert.Contains(t, text, "- imports: `src/index.js` → `src/format.js`\n")
+
+	response, _, err = server.compareGraphs(ctx, nil, CompareGraphsArgs{From: ".", Format: "json"})
+	require.NoError(t, err)
+	var diff graphdiff.Diff
+	require.NoError(t, json.Unmarshal([]byte(response.Content[0].(*mcp.TextContent).Text), &diff))
+	assert.True(t, diff.Empty())
+
+	_, _, err = server.compareGraphs(ctx, nil, CompareGraphsArgs{})
+	assert.ErrorContains(t, err, "from is required")
+	_, _, err = server.compareGraphs(ctx, nil, CompareGraphsArgs{From: ".", Format: "html"})
+	assert.ErrorContains(t, err, "unknown format")
+	_, _, err = server.compareGraphs(ctx, nil, CompareGraphsArgs{From: "main"})
+	assert.ErrorContains(t, err, "not a git repository")
+}
+
+func TestCompareGraphsUsesConfiguredBuilder(t *testing.T) {
+	tmpDir := t.TempDir()
+	testutils.WriteTree(t, tmpDir, map[string]string{
+		"app/src/index.js":        "export function start() { return 1; }\n",
+		"app/generated/client.js": "export function request() { return 1; }\n",
+		"baseline/src/index.js":   "export function start() { return 1; }\n",
+	})
+	config := createTestConfig()
+	config.TargetDir = filepath.Join(tmpDir, "app")
+	config.ExcludePatterns = []string{"generated/**"}
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	response, _, err := server.compareGraphs(context.Background(), nil, CompareGraphsArgs{From: "../baseline", Format: "json"})
+	require.NoError(t, err)
+	var diff graphdiff.Diff
+	require.NoError(t, json.Unmarshal([]byte(response.Content[0].(*mcp.TextContent).Text), &diff))
+	assert.True(t, diff.Empty(), "excluded files are left out of both sides")
+}
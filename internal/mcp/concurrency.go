@@ -0,0 +1,140 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetConcurrencyMapArgs struct {
+	Kind      string `json:"kind,omitempty"`       // Optional: only spawn, async, channel, lock, atomic or sync constructs
+	FilePath  string `json:"file_path,omitempty"`  // Optional: only files whose path contains this text, listed line by line
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum files (default 20)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getConcurrencyMap(ctx context.Context, req *mcp.CallToolRequest, args GetConcurrencyMapArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_concurrency_map with args: %+v", args)
+	start := time.Now()
+
+	switch args.Kind {
+	case "", analyzer.ConcurrencySpawn, analyzer.ConcurrencyAsync, analyzer.ConcurrencyChannel, analyzer.ConcurrencyLock, analyzer.ConcurrencyAtomic, analyzer.ConcurrencySync:
+	default:
+		return nil, nil, fmt.Errorf("unknown concurrency kind %q (use spawn, async, channel, lock, atomic or sync)", args.Kind)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Repeat calls return the rendered response until the watcher sees a change
+	cache := s.cachedCallFor("get_concurrency_map", args, targetDir)
+	if result, ok := cache.result(); ok {
+		log.Printf("[MCP] Tool completed: get_concurrency_map (cached, took %v)", time.Since(start))
+		return result, nil, nil
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var files []analyzer.ConcurrencyFile
+	kindCounts := make(map[string]int)
+	for _, file := range analyzer.ConcurrencyMap(graph) {
+		if rel, err := filepath.Rel(targetDir, file.File); err == nil && !strings.HasPrefix(rel, "..") {
+			file.File = rel
+		}
+		if args.FilePath != "" && !strings.Contains(file.File, args.FilePath) {
+			continue
+		}
+		if args.Kind != "" {
+			var usages []analyzer.ConcurrencyUsage
+			for _, usage := range file.Usages {
+				if usage.Kind == args.Kind {
+					usages = append(usages, usage)
+				}
+			}
+			if len(usages) == 0 {
+				continue
+			}
+			file.Usages = usages
+		}
+		for _, usage := range file.Usages {
+			kindCounts[usage.Kind]++
+		}
+		files = append(files, file)
+	}
+
+	var result strings.Builder
+	result.WriteString("# Concurrency Map\n\n")
+	if len(files) == 0 {
+		result.WriteString("_No concurrency constructs found_\n")
+	} else {
+		kinds := make([]string, 0, len(kindCounts))
+		for kind := range kindCounts {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		summary := make([]string, len(kinds))
+		for i, kind := range kinds {
+			summary[i] = fmt.Sprintf("%s %d", kind, kindCounts[kind])
+		}
+		result.WriteString(fmt.Sprintf("**Files:** %d | **Constructs:** %s\n\n", len(files), strings.Join(summary, ", ")))
+		if args.FilePath == "" {
+			result.WriteString("Files mixing more kinds of constructs come first; spawned work next to locks, atomics or channels deserves the closest review.\n\n")
+		}
+
+		for i, file := range files {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("_... and %d more files_\n", len(files)-i))
+				break
+			}
+			constructs := file.Constructs()
+			names := make([]string, 0, len(constructs))
+			for name := range constructs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for j, name := range names {
+				names[j] = fmt.Sprintf("%s ×%d", name, constructs[name])
+			}
+			result.WriteString(fmt.Sprintf("## `%s` (%s)\n\n", file.File, file.Language))
+			result.WriteString(fmt.Sprintf("**Kinds:** %s | %s\n\n", strings.Join(file.Kinds(), ", "), strings.Join(names, ", ")))
+
+			// Line-level detail for the files asked about
+			if args.FilePath != "" {
+				for _, usage := range file.Usages {
+					result.WriteString(fmt.Sprintf("- line %d: %s (%s)", usage.Line, usage.Construct, usage.Kind))
+					if usage.Symbol != "" {
+						result.WriteString(fmt.Sprintf(" in `%s`", usage.Symbol))
+					}
+					result.WriteString("\n")
+				}
+				result.WriteString("\n")
+			}
+		}
+	}
+
+	cache.store(result.String())
+
+	log.Printf("[MCP] Tool completed: get_concurrency_map (took %v, %d files)", time.Since(start), len(files))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetConcurrencyMap(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "package cache\n\nimport \"sync\"\n\nvar mu sync.Mutex\n\nfunc Warm(keys []string) {\n\tfor _, key := range keys {\n\t\tgo load(key)\n\t}\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "cache.go"), []byte(source), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getConcurrencyMap(ctx, nil, GetConcurrencyMapArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Files:** 1 | **Constructs:** lock 1, spawn 1")
+	assert.Contains(t, text, "## `cache.go` (go)")
+	assert.Contains(t, text, "**Kinds:** lock, spawn | goroutine ×1, sync.Mutex ×1")
+	assert.NotContains(t, text, "line 9")
+
+	response, _, err = server.getConcurrencyMap(ctx, nil, GetConcurrencyMapArgs{FilePath: "cache", Kind: "spawn"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "- line 9: goroutine (spawn) in `Warm`")
+	assert.NotContains(t, text, "sync.Mutex")
+
+	response, _, err = server.getConcurrencyMap(ctx, nil, GetConcurrencyMapArgs{Kind: "atomic"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "_No concurrency constructs found_")
+
+	_, _, err = server.getConcurrencyMap(ctx, nil, GetConcurrencyMapArgs{Kind: "actor"})
+	assert.ErrorContains(t, err, `unknown concurrency kind "actor"`)
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetCouplingMetricsArgs struct {
+	Package   string `json:"package,omitempty"`    // Optional: only packages whose path contains this text, with their dependents and dependencies
+	Sort      string `json:"sort,omitempty"`       // Optional: fan_in (default), fan_out, instability or name
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum packages (default 30)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getCouplingMetrics(ctx context.Context, req *mcp.CallToolRequest, args GetCouplingMetricsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_coupling_metrics with args: %+v", args)
+	start := time.Now()
+
+	if args.Sort == "" {
+		args.Sort = "fan_in"
+	}
+	switch args.Sort {
+	case "fan_in", "fan_out", "instability", "name":
+	default:
+		return nil, nil, fmt.Errorf("unknown sort %q (use fan_in, fan_out, instability or name)", args.Sort)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 30
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Repeat calls return the rendered response until the watcher sees a change
+	cache := s.cachedCallFor("get_coupling_metrics", args, targetDir)
+	if result, ok := cache.result(); ok {
+		log.Printf("[MCP] Tool completed: get_coupling_metrics (cached, took %v)", time.Since(start))
+		return result, nil, nil
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var packages []analyzer.PackageCoupling
+	for _, pkg := range analyzer.AnalyzeCoupling(graph) {
+		if args.Package == "" || strings.Contains(pkg.Package, args.Package) {
+			packages = append(packages, pkg)
+		}
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		switch args.Sort {
+		case "fan_in":
+			if a.Afferent != b.Afferent {
+				return a.Afferent > b.Afferent
+			}
+		case "fan_out":
+			if a.Efferent != b.Efferent {
+				return a.Efferent > b.Efferent
+			}
+		case "instability":
+			if a.Instability != b.Instability {
+				return a.Instability > b.Instability
+			}
+		}
+		return a.Package < b.Package
+	})
+
+	var result strings.Builder
+	result.WriteString("# Coupling Metrics\n\n")
+	if len(packages) == 0 {
+		result.WriteString("_No packages found_\n")
+	} else {
+		result.WriteString(fmt.Sprintf("**Packages:** %d | Fan-in (Ca) counts the packages depending on a package, fan-out (Ce) the packages it depends on; instability is Ce / (Ca + Ce), from 0 (stable) to 1 (unstable).\n\n", len(packages)))
+		result.WriteString("| Package | Files | Fan-in (Ca) | Fan-out (Ce) | Instability |\n")
+		result.WriteString("|---------|-------|-------------|--------------|-------------|\n")
+		shown := packages
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, pkg := range shown {
+			result.WriteString(fmt.Sprintf("| `%s` | %d | %d | %d | %.2f |\n", pkg.Package, pkg.Files, pkg.Afferent, pkg.Efferent, pkg.Instability))
+		}
+		if len(shown) < len(packages) {
+			result.WriteString(fmt.Sprintf("\n_... and %d more packages_\n", len(packages)-len(shown)))
+		}
+
+		// Dependents and dependencies of the packages asked about
+		if args.Package != "" {
+			for _, pkg := range shown {
+				result.WriteString(fmt.Sprintf("\n## `%s`\n\n", pkg.Package))
+				result.WriteString(fmt.Sprintf("**Depended on by:** %s\n\n", backtickList(pkg.Dependents)))
+				result.WriteString(fmt.Sprintf("**Depends on:** %s\n", backtickList(pkg.Dependencies)))
+			}
+		}
+	}
+
+	cache.store(result.String())
+
+	log.Printf("[MCP] Tool completed: get_coupling_metrics (took %v, %d packages)", time.Since(start), len(packages))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+func backtickList(values []string) string {
+	if len(values) == 0 {
+		return "_none_"
+	}
+	return "`" + strings.Join(values, "`, `") + "`"
+}
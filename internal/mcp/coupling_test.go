@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCouplingMetrics(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":         "module example.com/shop\n\ngo 1.24\n",
+		"main.go":        "package main\n\nimport (\n\t\"example.com/shop/api\"\n\t\"example.com/shop/store\"\n)\n\nfunc main() { api.Serve(store.Open()) }\n",
+		"api/api.go":     "package api\n\nimport \"example.com/shop/store\"\n\nfunc Serve(db *store.DB) {}\n",
+		"store/store.go": "package store\n\ntype DB struct{}\n\nfunc Open() *DB { return &DB{} }\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getCouplingMetrics(ctx, nil, GetCouplingMetricsArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Packages:** 3")
+	assert.Contains(t, text, "| `store` | 1 | 2 | 0 | 0.00 |")
+	assert.Contains(t, text, "| `.` | 1 | 0 | 2 | 1.00 |")
+	assert.NotContains(t, text, "**Depends on:**")
+
+	response, _, err = server.getCouplingMetrics(ctx, nil, GetCouplingMetricsArgs{Package: "store"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Depended on by:** `.`, `api`")
+	assert.Contains(t, text, "**Depends on:** _none_")
+
+	_, _, err = server.getCouplingMetrics(ctx, nil, GetCouplingMetricsArgs{Sort: "size"})
+	assert.ErrorContains(t, err, `unknown sort "size"`)
+}
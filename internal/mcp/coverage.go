@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// weakCoverage is the line coverage below which code counts as weakly tested
+const weakCoverage = 50.0
+
+// formatCoverage describes imported coverage ("72% of 50 lines, 4/5 functions")
+func formatCoverage(coverage *types.Coverage) string {
+	text := fmt.Sprintf("%.0f%% of %d lines", coverage.LinePercent(), coverage.Lines)
+	if coverage.Functions > 0 {
+		text += fmt.Sprintf(", %d/%d functions", coverage.CoveredFunctions, coverage.Functions)
+	}
+	return text
+}
+
+// formatMutants describes a mutation score ("75% of 8 mutants killed, 2 survived")
+func formatMutants(mutants *types.MutationScore) string {
+	text := fmt.Sprintf("%.0f%% of %d mutants killed", mutants.Percent(), mutants.Total())
+	if mutants.Survived > 0 {
+		text += fmt.Sprintf(", %d survived", mutants.Survived)
+	}
+	if mutants.NoCoverage > 0 {
+		text += fmt.Sprintf(", %d not covered", mutants.NoCoverage)
+	}
+	return text
+}
+
+// untestedDependents summarizes the coverage of the files importing a file,
+// so the impact of changing it can be weighed by how much of the affected
+// code tests would not catch. Empty without coverage data.
+func untestedDependents(graph *types.CodeGraph, filePath string) string {
+	node := types.NodeId("file-" + filePath)
+	seen := make(map[string]bool)
+	known := 0
+	var weak []string
+	for _, edge := range graph.Edges {
+		if edge.Type != "imports" || (edge.To != node && edge.To != types.NodeId(filePath)) {
+			continue
+		}
+		from := strings.TrimPrefix(string(edge.From), "file-")
+		if seen[from] {
+			continue
+		}
+		seen[from] = true
+		file := graph.Files[from]
+		if file == nil || file.Coverage == nil {
+			continue
+		}
+		known++
+		if percent := file.Coverage.LinePercent(); percent < weakCoverage {
+			weak = append(weak, fmt.Sprintf("- %s (%.0f%%)\n", from, percent))
+		}
+	}
+	if known == 0 {
+		return ""
+	}
+	sort.Strings(weak)
+	return fmt.Sprintf("\n**Dependents with coverage data:** %d, %d below %.0f%% line coverage\n", known, len(weak), weakCoverage) +
+		strings.Join(weak, "")
+}
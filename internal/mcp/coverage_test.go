@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUntestedDependents(t *testing.T) {
+	graph := &types.CodeGraph{
+		Files: map[string]*types.FileNode{
+			"lib.go":     {Path: "lib.go"},
+			"api.go":     {Path: "api.go", Coverage: &types.Coverage{Lines: 10, CoveredLines: 2}},
+			"worker.go":  {Path: "worker.go", Coverage: &types.Coverage{Lines: 10, CoveredLines: 9}},
+			"scripts.go": {Path: "scripts.go"},
+		},
+		Edges: map[types.EdgeId]*types.GraphEdge{
+			"1": {From: "file-api.go", To: "file-lib.go", Type: "imports"},
+			"2": {From: "file-worker.go", To: "file-lib.go", Type: "imports"},
+			"3": {From: "file-scripts.go", To: "file-lib.go", Type: "imports"},
+		},
+	}
+	assert.Equal(t, "\n**Dependents with coverage data:** 2, 1 below 50% line coverage\n- api.go (20%)\n", untestedDependents(graph, "lib.go"))
+	assert.Equal(t, "", untestedDependents(graph, "api.go"))
+
+	assert.Equal(t, "20% of 10 lines", formatCoverage(graph.Files["api.go"].Coverage))
+	assert.Equal(t, "50% of 4 lines, 1/3 functions", formatCoverage(&types.Coverage{Lines: 4, CoveredLines: 2, Functions: 3, CoveredFunctions: 1}))
+}
+
+func TestFormatMutants(t *testing.T) {
+	assert.Equal(t, "75% of 8 mutants killed, 2 survived", formatMutants(&types.MutationScore{Killed: 6, Survived: 2}))
+	assert.Equal(t, "0% of 3 mutants killed, 1 survived, 2 not covered", formatMutants(&types.MutationScore{Survived: 1, NoCoverage: 2}))
+}
@@ -0,0 +1,27 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// cppModuleLines describes the C++20 module unit a file declares and what it
+// exports, or returns "" for files outside named modules
+func cppModuleLines(graph *types.CodeGraph, fileNode *types.FileNode) string {
+	for _, symbolId := range fileNode.Symbols {
+		symbol := graph.Symbols[symbolId]
+		if symbol == nil || symbol.Type != types.SymbolTypeModule {
+			continue
+		}
+		kind := strings.ReplaceAll(symbol.MetadataString(parser.MetadataModuleUnit), "_", " ")
+		lines := fmt.Sprintf("**Module:** `%s` (%s unit)\n", symbol.Name, kind)
+		if exports := symbol.MetadataStrings(parser.MetadataExports); len(exports) > 0 {
+			lines += fmt.Sprintf("**Exports:** `%s`\n", strings.Join(exports, "`, `"))
+		}
+		return lines
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/annotations"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// definitionEdges indexes the declaration-to-definition edges of C/C++
+// functions by the declaring and the defining symbol
+func definitionEdges(graph *types.CodeGraph) (byDeclaration, byDefinition map[types.SymbolId]*types.GraphEdge) {
+	byDeclaration = make(map[types.SymbolId]*types.GraphEdge)
+	byDefinition = make(map[types.SymbolId]*types.GraphEdge)
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipDefinedIn) {
+			continue
+		}
+		byDeclaration[types.SymbolId(strings.TrimPrefix(string(edge.From), "symbol-"))] = edge
+		byDefinition[types.SymbolId(strings.TrimPrefix(string(edge.To), "symbol-"))] = edge
+	}
+	return byDeclaration, byDefinition
+}
+
+// declarationLines lists where a function linked by a defined-in edge is
+// declared and where it is defined, relative to the project root
+func declarationLines(edge *types.GraphEdge, targetDir string) string {
+	location := func(fileKey, lineKey string) string {
+		file, _ := edge.Metadata[fileKey].(string)
+		return fmt.Sprintf("%s:%v", annotations.RelativePath(file, targetDir), edge.Metadata[lineKey])
+	}
+	return fmt.Sprintf("**Declared in:** `%s`\n**Defined in:** `%s`\n",
+		location("declaration_file", "declaration_line"), location("definition_file", "definition_line"))
+}
+
+// headerPairLine names the implementation of a C/C++ header, or the header
+// of an implementation file, or returns "" for unpaired files
+func headerPairLine(graph *types.CodeGraph, path, targetDir string) string {
+	node := types.NodeId("file-" + path)
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipImplementedIn) {
+			continue
+		}
+		switch node {
+		case edge.From:
+			implementation, _ := edge.Metadata["implementation"].(string)
+			return fmt.Sprintf("**Implementation:** `%s`\n", annotations.RelativePath(implementation, targetDir))
+		case edge.To:
+			header, _ := edge.Metadata["header"].(string)
+			return fmt.Sprintf("**Header:** `%s`\n", annotations.RelativePath(header, targetDir))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetAnnotationsArgs struct {
+	Name      string `json:"name,omitempty"`       // Optional: only decorators whose name contains this text (case-insensitive)
+	Language  string `json:"language,omitempty"`   // Optional: only decorators of this language
+	Concern   string `json:"concern,omitempty"`    // Optional: only auth, caching, transactions, resilience, observability, validation or scheduling
+	FilePath  string `json:"file_path,omitempty"`  // Optional: only uses in files whose path contains this text
+	Examples  int    `json:"examples,omitempty"`   // Optional: example sites per decorator (default 3)
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum decorators listed (default 50)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getAnnotations(ctx context.Context, req *mcp.CallToolRequest, args GetAnnotationsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_annotations with args: %+v", args)
+	start := time.Now()
+
+	if args.Concern != "" && !slices.Contains(analyzer.DecoratorConcernNames(), args.Concern) {
+		return nil, nil, fmt.Errorf("unknown concern %q (use %s)", args.Concern, strings.Join(analyzer.DecoratorConcernNames(), ", "))
+	}
+	if args.Examples <= 0 {
+		args.Examples = 3
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+	var usages []analyzer.DecoratorUsage
+	uses := 0
+	for _, usage := range analyzer.FindDecorators(graph) {
+		if (args.Name != "" && !strings.Contains(strings.ToLower(usage.Name), strings.ToLower(args.Name))) ||
+			(args.Language != "" && usage.Language != args.Language) ||
+			(args.Concern != "" && usage.Concern != args.Concern) {
+			continue
+		}
+		if args.FilePath != "" {
+			var sites []analyzer.DecoratorSite
+			files := make(map[string]bool)
+			for _, site := range usage.Sites {
+				if strings.Contains(relative(site.File), args.FilePath) {
+					sites = append(sites, site)
+					files[site.File] = true
+				}
+			}
+			if len(sites) == 0 {
+				continue
+			}
+			usage.Sites, usage.Count, usage.Files = sites, len(sites), len(files)
+		}
+		usages = append(usages, usage)
+		uses += usage.Count
+	}
+
+	var result strings.Builder
+	result.WriteString("# Decorators and Annotations\n\n")
+	if len(usages) == 0 {
+		result.WriteString("_No decorators or annotations found_\n")
+	} else {
+		result.WriteString(fmt.Sprintf("**Decorators:** %d distinct, %d uses\n\n", len(usages), uses))
+		result.WriteString("| Decorator | Language | Uses | Files | Concern |\n")
+		result.WriteString("|-----------|----------|------|-------|---------|\n")
+		shown := usages
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, usage := range shown {
+			concern := usage.Concern
+			if concern == "" {
+				concern = "-"
+			}
+			result.WriteString(fmt.Sprintf("| `%s` | %s | %d | %d | %s |\n", usage.Name, usage.Language, usage.Count, usage.Files, concern))
+		}
+		if len(usages) > len(shown) {
+			result.WriteString(fmt.Sprintf("\n_... and %d more decorators_\n", len(usages)-len(shown)))
+		}
+
+		result.WriteString("\n## Examples\n\n")
+		for _, usage := range shown {
+			result.WriteString(fmt.Sprintf("### `%s` (%s)\n", usage.Name, usage.Language))
+			for i, site := range usage.Sites {
+				if i == args.Examples {
+					result.WriteString(fmt.Sprintf("- _... and %d more_\n", len(usage.Sites)-i))
+					break
+				}
+				line := fmt.Sprintf("- `%s:%d`", relative(site.File), site.Line)
+				if site.Symbol != "" {
+					line += fmt.Sprintf(" on `%s`", site.Symbol)
+				}
+				result.WriteString(line + "\n")
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	log.Printf("[MCP] Tool completed: get_annotations (took %v, %d decorators)", time.Since(start), len(usages))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAnnotations(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"api/views.py":  "@login_required\ndef list_users():\n    return []\n\n\n@login_required\ndef delete_user():\n    pass\n\n\n@login_required\ndef edit_user():\n    pass\n",
+		"api/config.py": "@lru_cache\ndef load_config():\n    return {}\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getAnnotations(ctx, nil, GetAnnotationsArgs{Examples: 2})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Decorators:** 2 distinct, 4 uses")
+	assert.Contains(t, text, "| `@login_required` | python | 3 | 1 | auth |")
+	assert.Contains(t, text, "- `api/views.py:1` on `list_users`")
+	assert.Contains(t, text, "- _... and 1 more_")
+
+	response, _, err = server.getAnnotations(ctx, nil, GetAnnotationsArgs{Concern: "caching"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "`@lru_cache`")
+	assert.NotContains(t, text, "login_required")
+
+	_, _, err = server.getAnnotations(ctx, nil, GetAnnotationsArgs{Concern: "logging"})
+	assert.ErrorContains(t, err, "unknown concern")
+}
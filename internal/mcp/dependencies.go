@@ -0,0 +1,174 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// importLines lists the import edges of a file as markdown bullets: the files
+// it imports, or with dependents the files importing it. Each line shows the
+// statement count, import kinds, lazy loading and imported names. A non-empty
+// kind keeps only edges with at least one statement of that kind; "lazy" keeps
+// the edges loaded only at runtime.
+func importLines(graph *types.CodeGraph, filePath, kind string, dependents bool) []string {
+	node := types.NodeId("file-" + filePath)
+	var lines []string
+	for _, edge := range graph.Edges {
+		if edge.Type != "imports" {
+			continue
+		}
+		self, other := edge.From, edge.To
+		if dependents {
+			self, other = edge.To, edge.From
+		}
+		if self != node && self != types.NodeId(filePath) {
+			continue
+		}
+		info := analyzer.ImportEdgeInfo(edge)
+		if kind == lazyImportFilter {
+			if !info.Lazy {
+				continue
+			}
+		} else if kind != "" && !info.HasKind(types.ImportKind(kind)) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("- %s%s\n", displayNode(other), describeImport(info)))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// injectionLines lists the dependencies injected into the classes and
+// providers of a file as markdown bullets, or with dependents the classes a
+// file's symbols are injected into. Bound and single-implementation
+// dependencies show the declared type they were injected as.
+func injectionLines(graph *types.CodeGraph, filePath string, dependents bool) []string {
+	symbolOf := func(node types.NodeId) *types.Symbol {
+		return graph.Symbols[types.SymbolId(strings.TrimPrefix(string(node), "symbol-"))]
+	}
+	symbolFile := make(map[types.SymbolId]string)
+	for path, file := range graph.Files {
+		for _, id := range file.Symbols {
+			symbolFile[id] = path
+		}
+	}
+	var lines []string
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipInjects) {
+			continue
+		}
+		consumer := symbolOf(edge.From)
+		if consumer == nil {
+			continue
+		}
+		dependency := symbolOf(edge.To)
+		self := consumer
+		if dependents {
+			self = dependency
+		}
+		if self == nil || symbolFile[self.Id] != filePath {
+			continue
+		}
+
+		framework, _ := edge.Metadata["framework"].(string)
+		if dependents {
+			lines = append(lines, fmt.Sprintf("- `%s` (%s) ← `%s` — %s\n", consumer.Name,
+				symbolFile[consumer.Id], dependency.Name, framework))
+			continue
+		}
+		target := fmt.Sprintf("`%s` (external)", strings.TrimPrefix(string(edge.To), "external-type-"))
+		if dependency != nil {
+			target = fmt.Sprintf("`%s` (%s)", dependency.Name, symbolFile[dependency.Id])
+		}
+		if via, ok := edge.Metadata["via"].(string); ok {
+			target += fmt.Sprintf(" as `%s`", via)
+		}
+		lines = append(lines, fmt.Sprintf("- `%s` ← %s — %s\n", consumer.Name, target, framework))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// generatedLines lists the models a generated Dart part was generated from
+// as markdown bullets, or with dependents the generated parts of a file's
+// models, which need regenerating when the models change
+func generatedLines(graph *types.CodeGraph, filePath string, dependents bool) []string {
+	var lines []string
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipGeneratedFrom) {
+			continue
+		}
+		source, _ := edge.Metadata["source_file"].(string)
+		class, _ := edge.Metadata["class"].(string)
+		generator, _ := edge.Metadata["generator"].(string)
+		symbols, _ := edge.Metadata["symbols"].([]string)
+		generated := strings.TrimPrefix(string(edge.From), "file-")
+		if dependents && source == filePath {
+			lines = append(lines, fmt.Sprintf("- `%s` → %s — %s: `%s`\n", class, generated, generator, strings.Join(symbols, "`, `")))
+		} else if !dependents && generated == filePath {
+			lines = append(lines, fmt.Sprintf("- `%s` (%s) — %s\n", class, source, generator))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// displayNode strips the "file-" and "external-" prefixes of graph node ids
+func displayNode(id types.NodeId) string {
+	name := strings.TrimPrefix(string(id), "file-")
+	if rest, ok := strings.CutPrefix(name, "external-"); ok {
+		return rest + " (external)"
+	}
+	return name
+}
+
+func describeImport(info analyzer.ImportEdge) string {
+	var parts []string
+	if info.Count > 1 {
+		parts = append(parts, fmt.Sprintf("%d statements", info.Count))
+	}
+	if len(info.Kinds) > 0 {
+		parts = append(parts, strings.Join(info.Kinds, ", "))
+	}
+	if info.Lazy {
+		lazy := "lazy"
+		if len(info.Loaders) > 0 {
+			lazy += " via " + strings.Join(info.Loaders, ", ")
+		}
+		parts = append(parts, lazy)
+	}
+	if len(info.Specifiers) > 0 {
+		parts = append(parts, "`"+strings.Join(info.Specifiers, "`, `")+"`")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " — " + strings.Join(parts, "; ")
+}
+
+// lazyImportFilter selects runtime-only dependencies in the kind argument
+const lazyImportFilter = "lazy"
+
+func validImportKind(kind string) bool {
+	if kind == lazyImportFilter {
+		return true
+	}
+	for _, k := range types.ImportKinds {
+		if string(k) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func importKindNames() string {
+	names := make([]string, len(types.ImportKinds))
+	for i, k := range types.ImportKinds {
+		names[i] = string(k)
+	}
+	return strings.Join(append(names, lazyImportFilter), ", ")
+}
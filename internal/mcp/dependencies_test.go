@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDependenciesImportMetadata(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	defer server.Stop()
+	ctx := context.Background()
+	mainFile := filepath.Join(tmpDir, "main.ts")
+
+	result, _, err := server.getDependencies(ctx, nil, GetDependenciesArgs{FilePath: mainFile, Direction: "imports"})
+	require.NoError(t, err)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "config.ts — named; `config`")
+	assert.Contains(t, text, "utils.ts — namespace")
+
+	result, _, err = server.getDependencies(ctx, nil, GetDependenciesArgs{FilePath: mainFile, Direction: "imports", Kind: "namespace"})
+	require.NoError(t, err)
+	text = result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "utils.ts")
+	assert.NotContains(t, text, "config.ts")
+
+	result, _, err = server.getDependencies(ctx, nil, GetDependenciesArgs{FilePath: filepath.Join(tmpDir, "config.ts"), Direction: "dependents"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "main.ts — named")
+
+	_, _, err = server.getDependencies(ctx, nil, GetDependenciesArgs{Kind: "eager"})
+	assert.ErrorContains(t, err, "unknown import kind")
+}
+
+func TestGetDependenciesInjection(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"users.service.ts":    "import { Injectable } from '@nestjs/common';\n\n@Injectable()\nexport class UsersService {\n  constructor(private repo: UsersRepository, private config: ConfigService) {}\n}\n",
+		"users.repository.ts": "import { Injectable } from '@nestjs/common';\n\n@Injectable()\nexport class UsersRepository {}\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	defer server.Stop()
+	ctx := context.Background()
+	serviceFile := filepath.Join(tmpDir, "users.service.ts")
+	repositoryFile := filepath.Join(tmpDir, "users.repository.ts")
+
+	result, _, err := server.getDependencies(ctx, nil, GetDependenciesArgs{FilePath: serviceFile, Direction: "imports"})
+	require.NoError(t, err)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "### Injected dependencies:")
+	assert.Contains(t, text, "- `UsersService` ← `UsersRepository` ("+repositoryFile+") — nestjs")
+	assert.Contains(t, text, "- `UsersService` ← `ConfigService` (external) — nestjs")
+
+	result, _, err = server.getDependencies(ctx, nil, GetDependenciesArgs{FilePath: repositoryFile, Direction: "dependents"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "### Injected into:\n- `UsersService` ("+serviceFile+") ← `UsersRepository` — nestjs")
+}
+
+func TestGetDependenciesDartCodegen(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"user.dart": "part 'user.g.dart';\n\n@JsonSerializable()\nclass User {\n  final String name;\n  User(this.name);\n" +
+			"  factory User.fromJson(Map<String, dynamic> json) => _$UserFromJson(json);\n  Map<String, dynamic> toJson() => _$UserToJson(this);\n}\n",
+		"user.g.dart": "// GENERATED CODE - DO NOT MODIFY BY HAND\n\npart of 'user.dart';\n\n" +
+			"User _$UserFromJson(Map<String, dynamic> json) => User(\n      json['name'] as String,\n    );\n\n" +
+			"Map<String, dynamic> _$UserToJson(User instance) => <String, dynamic>{\n      'name': instance.name,\n    };\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	defer server.Stop()
+	ctx := context.Background()
+	modelFile := filepath.Join(tmpDir, "user.dart")
+	generatedFile := filepath.Join(tmpDir, "user.g.dart")
+
+	result, _, err := server.getDependencies(ctx, nil, GetDependenciesArgs{FilePath: modelFile, Direction: "dependents"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "### Generated code (run `dart run build_runner build` after changing these models):\n"+
+		"- `User` → "+generatedFile+" — json_serializable: `_$UserFromJson`, `_$UserToJson`\n")
+
+	result, _, err = server.getDependencies(ctx, nil, GetDependenciesArgs{FilePath: generatedFile, Direction: "imports"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "### Generated from:\n- `User` ("+modelFile+") — json_serializable\n")
+
+	result, _, err = server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: generatedFile})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "**Generated:** yes, do not edit by hand\n")
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type CheckDependenciesArgs struct {
+	Ecosystem string `json:"ecosystem,omitempty"`  // Optional: only npm, go or pypi manifests
+	Manifest  string `json:"manifest,omitempty"`   // Optional: only manifests whose path contains this text
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) checkDependencies(ctx context.Context, req *mcp.CallToolRequest, args CheckDependenciesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: check_dependencies with args: %+v", args)
+	start := time.Now()
+
+	switch args.Ecosystem {
+	case "", analyzer.EcosystemNPM, analyzer.EcosystemGo, analyzer.EcosystemPyPI:
+	default:
+		return nil, nil, fmt.Errorf("unknown ecosystem %q (use npm, go or pypi)", args.Ecosystem)
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var checks []analyzer.ManifestCheck
+	unused, missing := 0, 0
+	for _, check := range analyzer.CheckDependencies(graph, targetDir) {
+		if (args.Ecosystem == "" || check.Ecosystem == args.Ecosystem) && strings.Contains(check.File, args.Manifest) {
+			checks = append(checks, check)
+			unused += len(check.Unused)
+			missing += len(check.Missing)
+		}
+	}
+	var skews []analyzer.VersionSkew
+	for _, skew := range analyzer.FindVersionSkew(graph, targetDir) {
+		if (args.Ecosystem == "" || skew.Ecosystem == args.Ecosystem) && skewInManifest(skew, args.Manifest) {
+			skews = append(skews, skew)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Dependency Check\n\n")
+	if len(checks) == 0 {
+		result.WriteString("_No matching package.json, go.mod or requirements*.txt files found_\n")
+		log.Printf("[MCP] Tool completed: check_dependencies (took %v, no manifests)", time.Since(start))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+		}, nil, nil
+	}
+	result.WriteString(fmt.Sprintf("**Manifests:** %d | **Unused:** %d | **Missing:** %d | **Version skew:** %d\n\n", len(checks), unused, missing, len(skews)))
+
+	for _, check := range checks {
+		result.WriteString(fmt.Sprintf("## `%s` (%s, %d declared)\n\n", check.File, check.Ecosystem, check.Declared))
+		if len(check.Unused) == 0 && len(check.Missing) == 0 {
+			result.WriteString("✅ Every declared dependency is imported and every imported package is declared\n\n")
+			continue
+		}
+		if len(check.Unused) > 0 {
+			result.WriteString("**Unused** (declared but never imported):\n")
+			for _, dep := range check.Unused {
+				version := ""
+				if dep.Version != "" {
+					version = " " + dep.Version
+				}
+				result.WriteString(fmt.Sprintf("- `%s`%s (%s, line %d)\n", dep.Name, version, dep.Scope, dep.Line))
+			}
+			result.WriteString("\n")
+		}
+		if len(check.Missing) > 0 {
+			result.WriteString("**Missing** (imported but not declared):\n")
+			for _, dep := range check.Missing {
+				var uses []string
+				for _, use := range dep.Uses {
+					uses = append(uses, fmt.Sprintf("`%s:%d`", use.File, use.Line))
+				}
+				line := fmt.Sprintf("- `%s`", dep.Name)
+				if dep.DevOnly {
+					line += " _(declared as a development dependency only)_"
+				}
+				line += " — " + strings.Join(uses, ", ")
+				if dep.Files > 1 {
+					line += fmt.Sprintf(" (%d files)", dep.Files)
+				}
+				result.WriteString(line + "\n")
+			}
+			result.WriteString("\n")
+		}
+	}
+	if len(skews) > 0 {
+		result.WriteString("## Version Skew\n\n")
+		result.WriteString("Libraries declared at different versions across manifests:\n\n")
+		for _, skew := range skews {
+			result.WriteString(fmt.Sprintf("### `%s` (%s, %d files)\n", skew.Name, skew.Ecosystem, skew.Uses))
+			for _, version := range skew.Versions {
+				var manifests []string
+				for _, manifest := range version.Manifests {
+					manifests = append(manifests, fmt.Sprintf("`%s:%d` (%s, %d files)", manifest.File, manifest.Line, manifest.Scope, manifest.Uses))
+				}
+				result.WriteString(fmt.Sprintf("- **%s** — %d files: %s\n", version.Version, version.Uses, strings.Join(manifests, ", ")))
+			}
+			result.WriteString("\n")
+		}
+	}
+	result.WriteString("_Packages loaded by name at runtime (plugins, database drivers, CLI tools) are not seen as imports; confirm before removing an unused dependency._\n")
+
+	log.Printf("[MCP] Tool completed: check_dependencies (took %v, %d manifests, %d unused, %d missing, %d skewed)", time.Since(start), len(checks), unused, missing, len(skews))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// skewInManifest reports whether any manifest declaring the skewed library
+// has a path containing text
+func skewInManifest(skew analyzer.VersionSkew, text string) bool {
+	for _, version := range skew.Versions {
+		for _, manifest := range version.Manifests {
+			if strings.Contains(manifest.File, text) {
+				return true
+			}
+		}
+	}
+	return false
+}
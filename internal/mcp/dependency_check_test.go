@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"package.json":     "{\n  \"dependencies\": {\n    \"react\": \"^18.2.0\",\n    \"lodash\": \"^4.17.21\"\n  }\n}\n",
+		"src/app.js":       "import React from 'react';\nimport axios from 'axios';\n",
+		"requirements.txt": "requests==2.31.0\n",
+		"app/main.py":      "import requests\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.checkDependencies(ctx, nil, CheckDependenciesArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Manifests:** 2 | **Unused:** 1 | **Missing:** 1")
+	assert.Contains(t, text, "## `package.json` (npm, 2 declared)")
+	assert.Contains(t, text, "- `lodash` ^4.17.21 (dependencies, line 4)")
+	assert.Contains(t, text, "- `axios` — `src/app.js:2`")
+	assert.Contains(t, text, "## `requirements.txt` (pypi, 1 declared)\n\n✅")
+
+	response, _, err = server.checkDependencies(ctx, nil, CheckDependenciesArgs{Ecosystem: "pypi"})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "package.json")
+
+	_, _, err = server.checkDependencies(ctx, nil, CheckDependenciesArgs{Ecosystem: "cargo"})
+	assert.Error(t, err)
+}
+
+func TestCheckDependenciesVersionSkew(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"packages/web/package.json":   "{\n  \"dependencies\": {\n    \"react\": \"^18.2.0\"\n  }\n}\n",
+		"packages/admin/package.json": "{\n  \"dependencies\": {\n    \"react\": \"^17.0.2\"\n  }\n}\n",
+		"packages/web/src/app.js":     "import React from 'react';\n",
+		"packages/web/src/page.js":    "import React from 'react';\n",
+		"packages/admin/src/app.js":   "import React from 'react';\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.checkDependencies(ctx, nil, CheckDependenciesArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Version skew:** 1")
+	assert.Contains(t, text, "### `react` (npm, 3 files)\n"+
+		"- **^18.2.0** — 2 files: `packages/web/package.json:3` (dependencies, 2 files)\n"+
+		"- **^17.0.2** — 1 files: `packages/admin/package.json:3` (dependencies, 1 files)\n")
+
+	response, _, err = server.checkDependencies(ctx, nil, CheckDependenciesArgs{Ecosystem: "go"})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "Version Skew")
+}
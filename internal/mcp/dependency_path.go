@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetDependencyPathArgs struct {
+	From      string `json:"from"`                 // File (relative to the target directory) or symbol name that depends
+	To        string `json:"to"`                   // File or symbol name depended on
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getDependencyPath(ctx context.Context, req *mcp.CallToolRequest, args GetDependencyPathArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_dependency_path with args: %+v", args)
+	start := time.Now()
+
+	if args.From == "" || args.To == "" {
+		return nil, nil, fmt.Errorf("from and to are required")
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	from, err := resolveEntryFiles(graph, targetDir, args.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := resolveEntryFiles(graph, targetDir, args.To)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result strings.Builder
+	result.WriteString("# Dependency Path\n\n")
+	result.WriteString(fmt.Sprintf("**From:** `%s` | **To:** `%s`\n\n", args.From, args.To))
+
+	hops, found := analyzer.FindDependencyPath(graph, targetDir, from, to)
+	switch {
+	case found && len(hops) == 0:
+		result.WriteString("_Both are in the same file_\n")
+	case found:
+		result.WriteString(fmt.Sprintf("**Length:** %d hops\n\n", len(hops)))
+		writeDependencyHops(&result, hops)
+	default:
+		result.WriteString(fmt.Sprintf("_No dependency path leads from `%s` to `%s`_\n", args.From, args.To))
+		if reverse, ok := analyzer.FindDependencyPath(graph, targetDir, to, from); ok && len(reverse) > 0 {
+			result.WriteString(fmt.Sprintf("\nThe dependency runs the other way, `%s` depends on `%s` in %d hops:\n\n", args.To, args.From, len(reverse)))
+			writeDependencyHops(&result, reverse)
+		}
+	}
+	if found && len(hops) > 0 {
+		result.WriteString("\n_The shortest path is shown; others may exist. Paths follow imports, symbol relationships and packages between files._\n")
+	}
+
+	log.Printf("[MCP] Tool completed: get_dependency_path (took %v, found: %v, %d hops)", time.Since(start), found, len(hops))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// writeDependencyHops writes a numbered list of the hops of a dependency path
+func writeDependencyHops(result *strings.Builder, hops []analyzer.DependencyHop) {
+	for i, hop := range hops {
+		via := hop.Via
+		if hop.Detail != "" {
+			via += " `" + hop.Detail + "`"
+		}
+		result.WriteString(fmt.Sprintf("%d. `%s` → `%s` (%s)\n", i+1, hop.From, hop.To, via))
+	}
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDependencyPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"src/index.js":      "import { api } from './api/client';\n\nexport function start() { return api(); }\n",
+		"src/api/client.js": "import { format } from '../format';\n\nexport function api() { return format(1); }\n",
+		"src/format.js":     "export function format(value) { return String(value); }\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getDependencyPath(ctx, nil, GetDependencyPathArgs{From: "src/index.js", To: "format"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Length:** 2 hops\n\n"+
+		"1. `src/index.js` → `src/api/client.js` (imports `./api/client`)\n"+
+		"2. `src/api/client.js` → `src/format.js` (imports `../format`)\n")
+
+	response, _, err = server.getDependencyPath(ctx, nil, GetDependencyPathArgs{From: "src/format.js", To: "start"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "_No dependency path leads from `src/format.js` to `start`_")
+	assert.Contains(t, text, "`start` depends on `src/format.js` in 2 hops")
+
+	_, _, err = server.getDependencyPath(ctx, nil, GetDependencyPathArgs{From: "src/index.js"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+// maxDjangoListed bounds each list of the Django section
+const maxDjangoListed = 30
+
+// djangoSection renders the Django part of the framework analysis: the
+// apps, the models with their admins, the URL patterns with the views they
+// route to, and the views no pattern routes to
+func djangoSection(report *analyzer.DjangoReport) string {
+	var section strings.Builder
+	section.WriteString("## 🎸 Django\n\n")
+	if report.Settings != "" {
+		section.WriteString(fmt.Sprintf("**Settings:** `%s`\n", report.Settings))
+	}
+	var unrouted []analyzer.DjangoView
+	for _, view := range report.Views {
+		if !view.Routed {
+			unrouted = append(unrouted, view)
+		}
+	}
+	section.WriteString(fmt.Sprintf("**Apps:** %d — **Models:** %d — **Views:** %d — **URL patterns:** %d\n\n",
+		len(report.Apps), len(report.Models), len(report.Views), len(report.URLs)))
+
+	if len(report.Apps) > 0 {
+		section.WriteString("### Apps\n\n")
+		for _, app := range report.Apps {
+			line := fmt.Sprintf("- `%s` — `%s/`", app.Name, app.Dir)
+			if app.Config != "" {
+				line += fmt.Sprintf(" (%s)", app.Config)
+			}
+			if !app.Installed {
+				line += " ⚠️ not in INSTALLED_APPS"
+			}
+			section.WriteString(line + "\n")
+		}
+		section.WriteString("\n")
+	}
+
+	if len(report.Models) > 0 {
+		section.WriteString("### Models\n\n")
+		section.WriteString("| Model | App | Fields | Admin | Location |\n")
+		section.WriteString("|-------|-----|--------|-------|----------|\n")
+		for i, model := range report.Models {
+			if i == maxDjangoListed {
+				section.WriteString(fmt.Sprintf("\n_... and %d more_\n", len(report.Models)-i))
+				break
+			}
+			admin := "—"
+			if model.Admin != "" {
+				admin = "`" + model.Admin + "`"
+			}
+			section.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s | `%s:%d` |\n",
+				model.Name, model.App, strings.Join(model.Fields, ", "), admin, model.File, model.Line))
+		}
+		section.WriteString("\n")
+	}
+
+	if len(report.URLs) > 0 {
+		section.WriteString("### URL Patterns\n\n")
+		section.WriteString("| Pattern | View | Name | Declared |\n")
+		section.WriteString("|---------|------|------|----------|\n")
+		for i, url := range report.URLs {
+			if i == maxDjangoListed {
+				section.WriteString(fmt.Sprintf("\n_... and %d more_\n", len(report.URLs)-i))
+				break
+			}
+			view := fmt.Sprintf("`%s`", url.View)
+			if url.ViewFile != "" {
+				view += fmt.Sprintf(" (`%s:%d`)", url.ViewFile, url.ViewLine)
+			}
+			section.WriteString(fmt.Sprintf("| `%s` | %s | %s | `%s:%d` |\n", url.Pattern, view, url.Name, url.File, url.Line))
+		}
+		section.WriteString("\n")
+	}
+
+	if len(unrouted) > 0 {
+		section.WriteString("### Views Without a URL\n\n")
+		for i, view := range unrouted {
+			if i == maxDjangoListed {
+				section.WriteString(fmt.Sprintf("- _... and %d more_\n", len(unrouted)-i))
+				break
+			}
+			section.WriteString(fmt.Sprintf("- `%s` (%s view) — `%s:%d`\n", view.Name, view.Kind, view.File, view.Line))
+		}
+		section.WriteString("\n")
+	}
+	return section.String()
+}
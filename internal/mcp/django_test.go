@@ -0,0 +1,40 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameworkAnalysisDjango(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"shop/models.py": "from django.db import models\n\n\nclass Product(models.Model):\n    name = models.CharField(max_length=50)\n",
+		"shop/admin.py":  "from django.contrib import admin\nfrom .models import Product\n\nadmin.site.register(Product)\n",
+		"shop/views.py":  "def product_list(request):\n    return None\n\n\ndef legacy(request):\n    return None\n",
+		"shop/urls.py":   "from django.urls import path\nfrom . import views\n\nurlpatterns = [\n    path(\"products/\", views.product_list, name=\"products\"),\n]\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "django"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## 🎸 Django\n\n**Apps:** 1 — **Models:** 1 — **Views:** 2 — **URL patterns:** 1\n")
+	assert.Contains(t, text, "- `shop` — `shop/` ⚠️ not in INSTALLED_APPS\n")
+	assert.Contains(t, text, "| `Product` | shop | name: CharField | `ModelAdmin` | `shop/models.py:4` |\n")
+	assert.Contains(t, text, "| `products/` | `views.product_list` (`shop/views.py:1`) | products | `shop/urls.py:5` |\n")
+	assert.Contains(t, text, "### Views Without a URL\n\n- `legacy` (function view) — `shop/views.py:5`\n")
+
+	response, _, err = server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "React"})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "## 🎸 Django")
+}
@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetErrorSourcesArgs struct {
+	Function  string `json:"function,omitempty"`   // Optional: Go function or method to trace ("Load", "Orders.Load" or "service.Load")
+	Error     string `json:"error,omitempty"`      // Optional: sentinel or error type to trace ("ErrNotFound")
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum traces (default 20)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getErrorSources(ctx context.Context, req *mcp.CallToolRequest, args GetErrorSourcesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_error_sources with args: %+v", args)
+	start := time.Now()
+
+	if args.Function != "" && args.Error != "" {
+		return nil, nil, fmt.Errorf("pass either function or error, not both")
+	}
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Repeat calls return the rendered response until the watcher sees a change
+	cache := s.cachedCallFor("get_error_sources", args, targetDir)
+	if result, ok := cache.result(); ok {
+		log.Printf("[MCP] Tool completed: get_error_sources (cached, took %v)", time.Since(start))
+		return result, nil, nil
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	flow := analyzer.AnalyzeErrorFlow(graph)
+	relative := func(file string) string {
+		if rel, err := filepath.Rel(targetDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return file
+	}
+
+	var result strings.Builder
+	switch {
+	case args.Function != "":
+		result.WriteString(fmt.Sprintf("# Error Sources: %s\n\n", args.Function))
+		functions := flow.FunctionsNamed(args.Function)
+		if len(functions) == 0 {
+			result.WriteString(fmt.Sprintf("_No Go function named '%s' returns or creates an error_\n", args.Function))
+			break
+		}
+		for _, fn := range functions {
+			result.WriteString(fmt.Sprintf("## `%s` (%s:%d)\n\n", fn.QualifiedName(), relative(fn.File), fn.Line))
+			traces := flow.Sources(fn)
+			if len(traces) == 0 {
+				result.WriteString("_Returns error, but no sentinel, error type or new error was found along its calls_\n\n")
+				continue
+			}
+			writeErrorTraces(&result, traces, args.Limit, relative)
+		}
+	case args.Error != "":
+		result.WriteString(fmt.Sprintf("# Error Propagation: %s\n\n", args.Error))
+		if definition, ok := flow.Definition(args.Error); ok {
+			result.WriteString(fmt.Sprintf("Defined as %s at %s:%d", definition.Kind, relative(definition.File), definition.Line))
+			if definition.Message != "" {
+				result.WriteString(fmt.Sprintf(" — `\"%s\"`", definition.Message))
+			}
+			result.WriteString("\n\n")
+		}
+		traces := flow.Propagation(args.Error)
+		if len(traces) == 0 {
+			result.WriteString(fmt.Sprintf("_No function returns '%s'_\n", args.Error))
+			break
+		}
+		writeErrorTraces(&result, traces, args.Limit, relative)
+	default:
+		result.WriteString("# Error Flow\n\n")
+		returning := 0
+		for _, fn := range flow.Functions {
+			if fn.ReturnsError {
+				returning++
+			}
+		}
+		result.WriteString(fmt.Sprintf("%d Go functions return error; %d sentinels and error types defined.\n\n", returning, len(flow.Definitions)))
+		for i, definition := range flow.Definitions {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("- ... and %d more\n", len(flow.Definitions)-i))
+				break
+			}
+			origins := 0
+			for _, fn := range flow.Functions {
+				for _, origin := range fn.Origins {
+					if origin.Name == definition.Name {
+						origins++
+					}
+				}
+			}
+			result.WriteString(fmt.Sprintf("- `%s` (%s, %s:%d) — returned by %d functions\n", definition.Name, definition.Kind, relative(definition.File), definition.Line, origins))
+		}
+		result.WriteString("\nPass `function` to trace where a function's errors originate, or `error` to see which call chains return a sentinel or error type.\n")
+	}
+
+	cache.store(result.String())
+
+	log.Printf("[MCP] Tool completed: get_error_sources (took %v)", time.Since(start))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// writeErrorTraces renders each trace as the error origin followed by the
+// chain of calls it travels through
+func writeErrorTraces(result *strings.Builder, traces []analyzer.ErrorTrace, limit int, relative func(string) string) {
+	for i, trace := range traces {
+		if i == limit {
+			result.WriteString(fmt.Sprintf("- ... and %d more\n", len(traces)-i))
+			break
+		}
+		origin := trace.Origin
+		source := trace.Path[len(trace.Path)-1]
+		label := fmt.Sprintf("`%s`", origin.Name)
+		if origin.Kind == analyzer.ErrorOriginNew {
+			label = fmt.Sprintf("`\"%s\"`", origin.Name)
+		}
+		result.WriteString(fmt.Sprintf("- %s (%s", label, origin.Kind))
+		if origin.Wrapped {
+			result.WriteString(", wrapped")
+		}
+		result.WriteString(fmt.Sprintf(") at %s:%d", relative(source.File), origin.Line))
+		if len(trace.Path) > 1 {
+			names := make([]string, len(trace.Path))
+			for j, fn := range trace.Path {
+				names[j] = fn.QualifiedName()
+			}
+			result.WriteString(" via " + strings.Join(names, " → "))
+		} else {
+			result.WriteString(" in " + source.QualifiedName())
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+}
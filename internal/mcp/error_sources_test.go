@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetErrorSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "package store\n\nimport \"errors\"\n\nvar ErrNotFound = errors.New(\"record not found\")\n\nfunc Get(id int) (*Record, error) {\n\treturn nil, ErrNotFound\n}\n\nfunc Load(id int) (*Record, error) {\n\trec, err := Get(id)\n\tif err != nil {\n\t\treturn nil, fmt.Errorf(\"load %d: %w\", id, err)\n\t}\n\treturn rec, nil\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "store.go"), []byte(source), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getErrorSources(ctx, nil, GetErrorSourcesArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "2 Go functions return error; 1 sentinels and error types defined.")
+	assert.Contains(t, text, "- `ErrNotFound` (sentinel, store.go:5) — returned by 1 functions")
+
+	response, _, err = server.getErrorSources(ctx, nil, GetErrorSourcesArgs{Function: "Load"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## `Load` (store.go:11)")
+	assert.Contains(t, text, "- `ErrNotFound` (sentinel) at store.go:8 via Load → Get")
+
+	response, _, err = server.getErrorSources(ctx, nil, GetErrorSourcesArgs{Error: "ErrNotFound"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Defined as sentinel at store.go:5 — `\"record not found\"`")
+	assert.Contains(t, text, "- `ErrNotFound` (sentinel) at store.go:8 in Get")
+	assert.Contains(t, text, "via Load → Get")
+
+	response, _, err = server.getErrorSources(ctx, nil, GetErrorSourcesArgs{Function: "Missing"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "No Go function named 'Missing'")
+
+	_, _, err = server.getErrorSources(ctx, nil, GetErrorSourcesArgs{Function: "Load", Error: "ErrNotFound"})
+	assert.ErrorContains(t, err, "either function or error")
+}
@@ -0,0 +1,102 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetEventFlowsArgs struct {
+	Topic     string `json:"topic,omitempty"`      // Only show topics containing this text
+	Broker    string `json:"broker,omitempty"`     // kafka, sns, sqs, nats, event-emitter or pubsub
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+}
+
+func (s *CodeContextMCPServer) getEventFlows(ctx context.Context, req *mcp.CallToolRequest, args GetEventFlowsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_event_flows with args: %+v", args)
+	start := time.Now()
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var flows []analyzer.EventFlow
+	for _, flow := range analyzer.EventFlows(graph) {
+		if args.Topic != "" && !strings.Contains(strings.ToLower(flow.Topic), strings.ToLower(args.Topic)) {
+			continue
+		}
+		if args.Broker != "" && !strings.EqualFold(flow.Broker, args.Broker) {
+			continue
+		}
+		flows = append(flows, flow)
+	}
+
+	var result strings.Builder
+	result.WriteString("# Event Flows\n\n")
+	if len(flows) == 0 {
+		result.WriteString("_No message producers or consumers found_\n")
+	} else {
+		result.WriteString(fmt.Sprintf("**Topics:** %d\n\n", len(flows)))
+	}
+
+	relPath := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return path
+	}
+	writeEndpoints := func(label string, endpoints []analyzer.EventEndpoint) {
+		result.WriteString(fmt.Sprintf("**%s:**\n", label))
+		for _, endpoint := range endpoints {
+			result.WriteString(fmt.Sprintf("- %s:%d", relPath(endpoint.File), endpoint.Line))
+			if endpoint.Symbol != "" {
+				result.WriteString(fmt.Sprintf(" in `%s`", endpoint.Symbol))
+			}
+			if strings.ContainsAny(endpoint.Topic, "*>") {
+				result.WriteString(fmt.Sprintf(" (subscribes to `%s`)", endpoint.Topic))
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	for _, flow := range flows {
+		result.WriteString(fmt.Sprintf("## `%s` (%s)\n\n", flow.Topic, flow.Broker))
+		switch {
+		case len(flow.Producers) == 0:
+			result.WriteString("⚠️ No producers found in this codebase (published externally?)\n\n")
+		case len(flow.Consumers) == 0:
+			result.WriteString("⚠️ No consumers found in this codebase (consumed externally?)\n\n")
+		}
+		if len(flow.Producers) > 0 {
+			writeEndpoints("Producers", flow.Producers)
+		}
+		if len(flow.Consumers) > 0 {
+			if len(flow.Producers) > 0 {
+				result.WriteString("\n")
+			}
+			writeEndpoints("Consumers", flow.Consumers)
+		}
+		result.WriteString("\n")
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_event_flows (took %v)", elapsed)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
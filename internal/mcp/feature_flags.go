@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetFeatureFlagsArgs struct {
+	Flag      string   `json:"flag,omitempty"`       // Only show flags containing this text
+	Helpers   []string `json:"helpers,omitempty"`    // Extra helper calls, added to feature_flag_helpers
+	TargetDir string   `json:"target_dir,omitempty"` // Optional: directory to analyze
+}
+
+func (s *CodeContextMCPServer) getFeatureFlags(ctx context.Context, req *mcp.CallToolRequest, args GetFeatureFlagsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_feature_flags with args: %+v", args)
+	start := time.Now()
+
+	helpers, err := analyzer.CompileFlagHelpers(append(append([]string{}, s.currentConfig().FlagHelpers...), args.Helpers...))
+	if err != nil {
+		log.Printf("[MCP] ERROR: %v", err)
+		return nil, nil, err
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var flags []analyzer.FeatureFlag
+	for _, flag := range analyzer.FeatureFlags(graph, helpers) {
+		if args.Flag != "" && !strings.Contains(strings.ToLower(flag.Key), strings.ToLower(args.Flag)) {
+			continue
+		}
+		flags = append(flags, flag)
+	}
+
+	var result strings.Builder
+	result.WriteString("# Feature Flags\n\n")
+	if len(flags) == 0 {
+		result.WriteString("_No feature flag evaluations found_\n")
+		if len(helpers) == 0 {
+			result.WriteString("\nHomegrown flag helpers can be indexed via `feature_flag_helpers` in the config or the `helpers` parameter.\n")
+		}
+	} else {
+		usages := 0
+		var singleUse []string
+		for _, flag := range flags {
+			usages += len(flag.Usages)
+			if len(flag.Usages) == 1 {
+				singleUse = append(singleUse, flag.Key)
+			}
+		}
+		result.WriteString(fmt.Sprintf("**Flags:** %d | **Usages:** %d\n\n", len(flags), usages))
+		if len(singleUse) > 0 {
+			result.WriteString(fmt.Sprintf("🧹 **Cleanup candidates** (evaluated in one place): %s\n\n", strings.Join(singleUse, ", ")))
+		}
+	}
+
+	for _, flag := range flags {
+		result.WriteString(fmt.Sprintf("## `%s`\n\n", flag.Key))
+		result.WriteString(fmt.Sprintf("**Provider:** %s | **Usages:** %d in %d files\n\n",
+			strings.Join(flag.Providers, ", "), len(flag.Usages), flag.Files()))
+		for _, usage := range flag.Usages {
+			file := usage.File
+			if rel, err := filepath.Rel(targetDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+			result.WriteString(fmt.Sprintf("- %s:%d", file, usage.Line))
+			if usage.Symbol != "" {
+				result.WriteString(fmt.Sprintf(" in `%s`", usage.Symbol))
+			}
+			result.WriteString(fmt.Sprintf(" — `%s`\n", usage.Call))
+		}
+		result.WriteString("\n")
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_feature_flags (took %v)", elapsed)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
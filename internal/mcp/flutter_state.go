@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+// maxFlutterConsumersListed bounds the widgets listed per state holder
+const maxFlutterConsumersListed = 10
+
+// flutterStateSection renders the state architecture part of the framework
+// analysis: each Bloc, Cubit, provider and ChangeNotifier with what it
+// depends on and the widgets using it
+func flutterStateSection(report *analyzer.FlutterStateReport) string {
+	var section strings.Builder
+	section.WriteString("## 🐦 Flutter State Architecture\n\n")
+
+	counts := report.Counts()
+	var kinds []string
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var summary []string
+	for _, kind := range kinds {
+		summary = append(summary, fmt.Sprintf("%s: %d", kind, counts[kind]))
+	}
+	section.WriteString(fmt.Sprintf("**State holders:** %d (%s) — used by %d widgets\n", len(report.Holders), strings.Join(summary, ", "), report.Widgets))
+	if len(report.Repositories) > 0 {
+		section.WriteString(fmt.Sprintf("**Repositories and services:** `%s`\n", strings.Join(report.Repositories, "`, `")))
+	}
+	section.WriteString("\n")
+
+	for _, holder := range report.Holders {
+		section.WriteString(fmt.Sprintf("### %s (%s)\n\n", holder.Name, holder.Type))
+		section.WriteString(fmt.Sprintf("**Declared:** `%s:%d`\n", holder.File, holder.Line))
+		if len(holder.Dependencies) > 0 {
+			section.WriteString(fmt.Sprintf("**Depends on:** `%s`\n", strings.Join(holder.Dependencies, "`, `")))
+		}
+		if len(holder.Consumers) == 0 {
+			section.WriteString("_No widget uses it_\n\n")
+			continue
+		}
+		section.WriteString("**Used by:**\n")
+		for i, consumer := range holder.Consumers {
+			if i == maxFlutterConsumersListed {
+				section.WriteString(fmt.Sprintf("- _... and %d more_\n", len(holder.Consumers)-i))
+				break
+			}
+			section.WriteString(fmt.Sprintf("- `%s` (%s) — `%s:%d`\n", consumer.Widget, consumer.Access, consumer.File, consumer.Line))
+		}
+		section.WriteString("\n")
+	}
+	return section.String()
+}
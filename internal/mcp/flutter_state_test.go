@@ -0,0 +1,37 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameworkAnalysisFlutterState(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"lib/todo_repository.dart": "class TodoRepository {\n  List<String> load() => [];\n}\n",
+		"lib/todo_cubit.dart":      "class TodoCubit extends Cubit<List<String>> {\n  TodoCubit(this._repository) : super([]);\n\n  final TodoRepository _repository;\n}\n",
+		"lib/todo_page.dart": "import 'package:flutter/material.dart';\n\nclass TodoPage extends StatelessWidget {\n  Widget build(BuildContext context) {\n" +
+			"    return BlocBuilder<TodoCubit, List<String>>(\n      builder: (context, todos) => Text('${todos.length}'),\n    );\n  }\n}\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "flutter"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## 🐦 Flutter State Architecture\n\n**State holders:** 1 (cubit: 1) — used by 1 widgets\n**Repositories and services:** `TodoRepository`")
+	assert.Contains(t, text, "### TodoCubit (Cubit)\n\n**Declared:** `lib/todo_cubit.dart:1`\n**Depends on:** `TodoRepository`\n**Used by:**\n- `TodoPage` (BlocBuilder) — `lib/todo_page.dart:5`")
+
+	response, _, err = server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "React"})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "Flutter State")
+}
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetFrontendRoutesArgs struct {
+	Path      string `json:"path,omitempty"`       // Optional: only routes whose path contains this text
+	Endpoint  string `json:"endpoint,omitempty"`   // Optional: only routes calling a backend endpoint whose path contains this text
+	FilePath  string `json:"file_path,omitempty"`  // Optional: only routes whose pages use a file whose path contains this text
+	Router    string `json:"router,omitempty"`     // Optional: only react-router, route-config, next-pages or next-app routes
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum routes listed (default 50)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getFrontendRoutes(ctx context.Context, req *mcp.CallToolRequest, args GetFrontendRoutesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_frontend_routes with args: %+v", args)
+	start := time.Now()
+
+	switch args.Router {
+	case "", analyzer.FrontendRouterJSX, analyzer.FrontendRouterConfig, analyzer.FrontendRouterNextPage, analyzer.FrontendRouterNextApp:
+	default:
+		return nil, nil, fmt.Errorf("unknown router %q (use react-router, route-config, next-pages or next-app)", args.Router)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+	var routes []analyzer.FrontendRoute
+	calling := 0
+	for _, route := range analyzer.FindFrontendRoutes(graph) {
+		if (args.Path != "" && !strings.Contains(route.Path, args.Path)) ||
+			(args.Router != "" && route.Router != args.Router) {
+			continue
+		}
+		if args.Endpoint != "" {
+			var calls []analyzer.FrontendAPICall
+			for _, call := range route.Calls {
+				if strings.Contains(call.Endpoint.Path, args.Endpoint) {
+					calls = append(calls, call)
+				}
+			}
+			if len(calls) == 0 {
+				continue
+			}
+			route.Calls = calls
+		}
+		if args.FilePath != "" {
+			uses := false
+			for _, file := range route.Files {
+				uses = uses || strings.Contains(relative(file), args.FilePath)
+			}
+			if !uses {
+				continue
+			}
+		}
+		routes = append(routes, route)
+		if len(route.Calls) > 0 {
+			calling++
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Frontend Routes\n\n")
+	if len(routes) == 0 {
+		result.WriteString("_No frontend routes found_\n")
+	} else {
+		result.WriteString(fmt.Sprintf("**Routes:** %d, %d calling backend endpoints\n\n", len(routes), calling))
+		for i, route := range routes {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("_... and %d more routes_\n", len(routes)-i))
+				break
+			}
+			result.WriteString(fmt.Sprintf("## %s (%s)\n\n", route.Path, route.Router))
+			if route.Component != "" {
+				component := fmt.Sprintf("**Component:** `%s`", route.Component)
+				if route.ComponentFile != "" {
+					component += fmt.Sprintf(" (`%s`)", relative(route.ComponentFile))
+				}
+				result.WriteString(component + "\n")
+			}
+			result.WriteString(fmt.Sprintf("**Declared:** `%s:%d`\n", relative(route.File), route.Line))
+			result.WriteString(fmt.Sprintf("**Files used:** %d\n\n", len(route.Files)))
+			if len(route.Calls) == 0 {
+				result.WriteString("_No backend calls found_\n\n")
+				continue
+			}
+			result.WriteString("| Endpoint | Called from | Handler |\n")
+			result.WriteString("|----------|-------------|---------|\n")
+			for _, call := range route.Calls {
+				handler := fmt.Sprintf("`%s:%d`", relative(call.Endpoint.File), call.Endpoint.Line)
+				if call.Handler != "" {
+					handler = fmt.Sprintf("`%s` (%s)", call.Handler, handler)
+				}
+				result.WriteString(fmt.Sprintf("| `%s` | `%s:%d` | %s |\n", call.Endpoint, relative(call.File), call.Line, handler))
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	log.Printf("[MCP] Tool completed: get_frontend_routes (took %v, %d routes)", time.Since(start), len(routes))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFrontendRoutes(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"server/app.js":      "const express = require('express');\nconst app = express();\napp.get('/api/users', listUsers);\napp.get('/api/orders', listOrders);\n",
+		"web/api.ts":         "export function getUsers() {\n  return fetch('/api/users');\n}\n\nexport function getOrders() {\n  return fetch('/api/orders');\n}\n",
+		"web/useUsers.ts":    "import { getUsers } from './api';\n\nexport function useUsers() {\n  return getUsers();\n}\n",
+		"web/UsersPage.tsx":  "import { useUsers } from './useUsers';\n\nexport function UsersPage() {\n  useUsers();\n  return <ul />;\n}\n",
+		"web/OrdersPage.tsx": "import { getOrders } from './api';\n\nexport function OrdersPage() {\n  getOrders();\n  return <ul />;\n}\n",
+		"web/App.tsx": "import { Route } from 'react-router-dom';\nimport { UsersPage } from './UsersPage';\nimport { OrdersPage } from './OrdersPage';\n\n" +
+			"export function App() {\n  return [\n    <Route path=\"/users\" element={<UsersPage />} />,\n    <Route path=\"/orders\" element={<OrdersPage />} />,\n  ];\n}\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getFrontendRoutes(ctx, nil, GetFrontendRoutesArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Routes:** 2, 2 calling backend endpoints")
+	assert.Contains(t, text, "## /users (react-router)\n\n**Component:** `UsersPage` (`web/UsersPage.tsx`)\n**Declared:** `web/App.tsx:7`\n**Files used:** 3")
+	assert.Contains(t, text, "| `GET /api/users` | `web/api.ts:2` | `listUsers` (`server/app.js:3`) |")
+
+	// Which pages a hook change affects, and which pages call an endpoint
+	response, _, err = server.getFrontendRoutes(ctx, nil, GetFrontendRoutesArgs{FilePath: "useUsers"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## /users")
+	assert.NotContains(t, text, "## /orders")
+
+	response, _, err = server.getFrontendRoutes(ctx, nil, GetFrontendRoutesArgs{Endpoint: "/api/orders"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Routes:** 1, 1 calling backend endpoints")
+	assert.Contains(t, text, "## /orders")
+
+	_, _, err = server.getFrontendRoutes(ctx, nil, GetFrontendRoutesArgs{Router: "ember"})
+	assert.ErrorContains(t, err, "unknown router")
+}
@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/annotations"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+type FindImplementationsArgs struct {
+	SymbolName string `json:"symbol_name"`          // Interface to list the implementations of, or a type to list the interfaces it implements
+	FilePath   string `json:"file_path,omitempty"`  // Optional: only symbols in files whose path ends with this
+	TargetDir  string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile    string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) findImplementations(ctx context.Context, req *mcp.CallToolRequest, args FindImplementationsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: find_implementations with args: %+v", args)
+	start := time.Now()
+
+	if args.SymbolName == "" {
+		log.Printf("[MCP] ERROR: symbol_name is required")
+		return nil, nil, fmt.Errorf("symbol_name is required")
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	implementations := make(map[types.NodeId][]*types.GraphEdge)
+	implemented := make(map[types.NodeId][]*types.GraphEdge)
+	for _, edge := range graph.Edges {
+		if edge.Type == string(analyzer.RelationshipImplements) {
+			implementations[edge.To] = append(implementations[edge.To], edge)
+			implemented[edge.From] = append(implemented[edge.From], edge)
+		}
+	}
+
+	var matches []*types.Symbol
+	for _, symbol := range graph.Symbols {
+		if symbol.Name != args.SymbolName {
+			continue
+		}
+		if args.FilePath != "" && !strings.HasSuffix(getFilePathForSymbol(graph, symbol), args.FilePath) {
+			continue
+		}
+		node := types.NodeId("symbol-" + string(symbol.Id))
+		if symbol.NormalizedKind() == types.SymbolKindInterface || len(implementations[node]) > 0 || len(implemented[node]) > 0 {
+			matches = append(matches, symbol)
+		}
+	}
+	if len(matches) == 0 {
+		log.Printf("[MCP] ERROR: Interface or type not found: %s", args.SymbolName)
+		return nil, nil, fmt.Errorf("no interface or implementing type named '%s' found", args.SymbolName)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Id < matches[j].Id })
+
+	location := func(symbol *types.Symbol) string {
+		return fmt.Sprintf("%s:%d", annotations.RelativePath(getFilePathForSymbol(graph, symbol), targetDir), symbol.Location.StartLine)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# Implementations: %s\n\n", args.SymbolName))
+	for i, symbol := range matches {
+		if i > 0 {
+			result.WriteString("\n---\n\n")
+		}
+		node := types.NodeId("symbol-" + string(symbol.Id))
+		result.WriteString(fmt.Sprintf("**File:** %s\n", location(symbol)))
+		result.WriteString(fmt.Sprintf("**Type:** %s\n\n", symbol.NormalizedKind()))
+
+		if symbol.NormalizedKind() == types.SymbolKindInterface {
+			result.WriteString("## Implemented by\n\n")
+			result.WriteString(formatImplementations(graph, implementations[node], true, location, "_No implementations found_\n"))
+		}
+		if edges := implemented[node]; len(edges) > 0 || symbol.NormalizedKind() != types.SymbolKindInterface {
+			if symbol.NormalizedKind() == types.SymbolKindInterface {
+				result.WriteString("\n")
+			}
+			result.WriteString("## Implements\n\n")
+			result.WriteString(formatImplementations(graph, edges, false, location, "_No interfaces found_\n"))
+		}
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: find_implementations (took %v)", elapsed)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// formatImplementations lists the other end of implements edges, the
+// implementing types when from is set and the interfaces otherwise. Go
+// types satisfying an interface only through pointer receiver methods are
+// marked, since only *T implements it.
+func formatImplementations(graph *types.CodeGraph, edges []*types.GraphEdge, from bool, location func(*types.Symbol) string, empty string) string {
+	var lines []string
+	for _, edge := range edges {
+		node := edge.To
+		if from {
+			node = edge.From
+		}
+		symbol := graph.Symbols[types.SymbolId(strings.TrimPrefix(string(node), "symbol-"))]
+		if symbol == nil {
+			name, _ := edge.Metadata["supertype"].(string)
+			lines = append(lines, fmt.Sprintf("- `%s` *(external)*\n", name))
+			continue
+		}
+		line := fmt.Sprintf("- `%s` — %s", symbol.Name, location(symbol))
+		if pointer, _ := edge.Metadata["pointer_receiver"].(bool); pointer {
+			line += " *(pointer receiver)*"
+		}
+		lines = append(lines, line+"\n")
+	}
+	if len(lines) == 0 {
+		return empty
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
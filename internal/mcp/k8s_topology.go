@@ -0,0 +1,154 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/k8s"
+)
+
+type GetK8sTopologyArgs struct {
+	Namespace string `json:"namespace,omitempty"`  // Only show resources in this namespace
+	Kind      string `json:"kind,omitempty"`       // Only list resources of this kind, e.g. Deployment
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+}
+
+// k8sKindOrder controls the section order of the get_k8s_topology response; other kinds follow
+var k8sKindOrder = []string{
+	"Ingress", "Service", "Deployment", "StatefulSet", "DaemonSet", "CronJob", "Job", "Pod",
+	"HorizontalPodAutoscaler", "ConfigMap", "Secret", "PersistentVolumeClaim", "ServiceAccount",
+}
+
+func (s *CodeContextMCPServer) getK8sTopology(ctx context.Context, req *mcp.CallToolRequest, args GetK8sTopologyArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_k8s_topology with args: %+v", args)
+	start := time.Now()
+
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	topology, err := k8s.Scan(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to scan Kubernetes manifests: %v", err)
+		return nil, nil, fmt.Errorf("failed to scan Kubernetes manifests: %w", err)
+	}
+
+	var resources []*k8s.Resource
+	for _, resource := range topology.Resources {
+		if args.Namespace != "" && resource.Namespace != "" && resource.Namespace != args.Namespace {
+			continue
+		}
+		resources = append(resources, resource)
+	}
+
+	var result strings.Builder
+	result.WriteString("# Kubernetes Topology\n\n")
+	if len(resources) == 0 {
+		result.WriteString("_No Kubernetes manifests or Helm chart templates found_\n")
+	} else {
+		files := make(map[string]bool)
+		for _, resource := range resources {
+			files[resource.File] = true
+		}
+		result.WriteString(fmt.Sprintf("**Resources:** %d in %d files\n\n", len(resources), len(files)))
+
+		if args.Kind == "" {
+			result.WriteString(formatRequestFlows(resources))
+		}
+		result.WriteString(formatK8sResources(resources, args.Kind))
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_k8s_topology (took %v)", elapsed)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// formatRequestFlows renders Ingress → Service → workload chains
+func formatRequestFlows(resources []*k8s.Resource) string {
+	var flows []string
+	for _, ingress := range resources {
+		if ingress.Kind != "Ingress" {
+			continue
+		}
+		for _, route := range ingress.Links {
+			flow := fmt.Sprintf("%s → %s", ingress.ID(), route.Kind+"/"+route.Name)
+			if route.Target == nil {
+				flows = append(flows, flow+" *(not declared)*")
+				continue
+			}
+			var workloads []string
+			for _, link := range route.Target.Links {
+				if link.Relation == k8s.RelationSelects {
+					workloads = append(workloads, link.Target.ID())
+				}
+			}
+			if len(workloads) > 0 {
+				flow += " → " + strings.Join(workloads, ", ")
+			}
+			flows = append(flows, flow)
+		}
+	}
+	if len(flows) == 0 {
+		return ""
+	}
+	return "## 🌐 Request Flow\n\n- " + strings.Join(flows, "\n- ") + "\n\n"
+}
+
+// formatK8sResources renders resources grouped by kind with their outgoing links
+func formatK8sResources(resources []*k8s.Resource, kindFilter string) string {
+	byKind := make(map[string][]*k8s.Resource)
+	var kinds []string
+	for _, kind := range k8sKindOrder {
+		kinds = append(kinds, kind)
+		byKind[kind] = nil
+	}
+	for _, resource := range resources {
+		if kindFilter != "" && !strings.EqualFold(resource.Kind, kindFilter) {
+			continue
+		}
+		if _, known := byKind[resource.Kind]; !known {
+			kinds = append(kinds, resource.Kind)
+		}
+		byKind[resource.Kind] = append(byKind[resource.Kind], resource)
+	}
+
+	var sb strings.Builder
+	for _, kind := range kinds {
+		if len(byKind[kind]) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n", kind))
+		for _, resource := range byKind[kind] {
+			sb.WriteString(fmt.Sprintf("- **%s**", resource.Name))
+			if resource.Namespace != "" {
+				sb.WriteString(fmt.Sprintf(" (namespace `%s`)", resource.Namespace))
+			}
+			sb.WriteString(fmt.Sprintf(" — %s:%d", resource.File, resource.Line))
+			if resource.Chart != "" {
+				sb.WriteString(fmt.Sprintf(" *(chart %s)*", resource.Chart))
+			}
+			sb.WriteString("\n")
+			if len(resource.Images) > 0 {
+				sb.WriteString(fmt.Sprintf("  - images: %s\n", strings.Join(resource.Images, ", ")))
+			}
+			for _, link := range resource.Links {
+				sb.WriteString(fmt.Sprintf("  - %s %s/%s", link.Relation, link.Kind, link.Name))
+				if link.Target == nil {
+					sb.WriteString(" ⚠️ *not declared in this repository*")
+				}
+				sb.WriteString("\n")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	if sb.Len() == 0 {
+		return fmt.Sprintf("_No %s resources found_\n", kindFilter)
+	}
+	return sb.String()
+}
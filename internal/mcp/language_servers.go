@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// maxHoverBytes caps the language server hover text shown per symbol
+const maxHoverBytes = 2000
+
+// languageServerInfo asks the language server of a symbol's language for its
+// type and definition. Lookups that fail are logged and leave the entry as
+// parsed, since language servers are optional.
+func (s *CodeContextMCPServer) languageServerInfo(ctx context.Context, graph *types.CodeGraph, symbol *types.Symbol, targetDir string) string {
+	s.configMu.RLock()
+	languages := s.languages
+	s.configMu.RUnlock()
+	path := getFilePathForSymbol(graph, symbol)
+	if languages == nil || path == "" {
+		return ""
+	}
+
+	line := symbol.Location.StartLine
+	info, err := languages.Lookup(ctx, targetDir, symbol.Language, path, line, nameColumn(path, line, symbol.Name))
+	if err != nil {
+		log.Printf("[MCP] WARNING: Language server lookup of %s failed: %v", symbol.Name, err)
+		return ""
+	}
+	if info == nil {
+		return ""
+	}
+
+	var result string
+	if hover := info.Hover; hover != "" {
+		if len(hover) > maxHoverBytes {
+			hover = strings.ToValidUTF8(hover[:maxHoverBytes], "") + "…"
+		}
+		result += fmt.Sprintf("**Type info (%s):**\n%s\n", info.Server, hover)
+	}
+	for _, definition := range info.Definitions {
+		if definition.Path == path && definition.Line == line {
+			continue
+		}
+		location := definition.Path
+		if rel, err := filepath.Rel(targetDir, location); err == nil && !strings.HasPrefix(rel, "..") {
+			location = rel
+		}
+		result += fmt.Sprintf("**Defined at (%s):** %s:%d\n", info.Server, filepath.ToSlash(location), definition.Line)
+	}
+	return result
+}
+
+// nameColumn returns the byte offset of a symbol's name on its line, where
+// language servers resolve it; 0 when the name is not found there
+func nameColumn(path string, line int, name string) int {
+	content, err := os.ReadFile(path)
+	if err != nil || name == "" {
+		return 0
+	}
+	lines := strings.Split(string(content), "\n")
+	if line < 1 || line > len(lines) {
+		return 0
+	}
+	pattern, err := regexp.Compile(`(^|[^\w$])` + regexp.QuoteMeta(name) + `($|[^\w$])`)
+	if err != nil {
+		return 0
+	}
+	match := pattern.FindStringSubmatchIndex(lines[line-1])
+	if match == nil {
+		return 0
+	}
+	return match[3]
+}
@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/lsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLanguageServerProcess is not a real test: it is the language server
+// queried by TestGetSymbolInfoLanguageServer. Hovers report the position asked
+// for and definitions point at line 1 of the same file.
+func TestLanguageServerProcess(t *testing.T) {
+	if os.Getenv("CODECONTEXT_MCP_LSP_HELPER") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	r := bufio.NewReader(os.Stdin)
+	for {
+		header, err := textproto.NewReader(r).ReadMIMEHeader()
+		if err != nil {
+			return
+		}
+		length, _ := strconv.Atoi(header.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return
+		}
+		var request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				TextDocument struct{ URI string } `json:"textDocument"`
+				Position     struct{ Line, Character int }
+			} `json:"params"`
+		}
+		json.Unmarshal(body, &request)
+		var result interface{}
+		switch request.Method {
+		case "exit":
+			return
+		case "textDocument/hover":
+			result = map[string]interface{}{"contents": map[string]string{"kind": "markdown", "value": fmt.Sprintf("func Total(items []int) int (at %d:%d)", request.Params.Position.Line, request.Params.Position.Character)}}
+		case "textDocument/definition":
+			result = map[string]interface{}{"uri": request.Params.TextDocument.URI, "range": map[string]interface{}{"start": map[string]int{"line": 0, "character": 0}}}
+		}
+		if len(request.ID) == 0 {
+			continue
+		}
+		response, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+		fmt.Fprintf(os.Stdout, "Content-Length: %d\r\n\r\n%s", len(response), response)
+	}
+}
+
+func TestGetSymbolInfoLanguageServer(t *testing.T) {
+	t.Setenv("CODECONTEXT_MCP_LSP_HELPER", "1")
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "cart"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "cart", "cart.go"), []byte("package cart\n\nfunc Total(items []int) int {\n\treturn len(items)\n}\n"), 0644))
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.LanguageServers = lsp.Config{Servers: map[string]lsp.Server{
+		"go": {Command: os.Args[0], Args: []string{"-test.run=TestLanguageServerProcess"}},
+	}}
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	defer server.Stop()
+
+	response, _, err := server.getSymbolInfo(context.Background(), nil, GetSymbolInfoArgs{SymbolName: "Total"})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "Type info", "language servers are only asked in deep mode")
+
+	response, _, err = server.getSymbolInfo(context.Background(), nil, GetSymbolInfoArgs{SymbolName: "Total", Profile: "deep"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	name := filepath.Base(os.Args[0])
+	assert.Contains(t, text, fmt.Sprintf("**Type info (%s):**\nfunc Total(items []int) int (at 2:5)\n", name), "the name is looked up, not the start of the line")
+	assert.Contains(t, text, fmt.Sprintf("**Defined at (%s):** cart/cart.go:1\n", name))
+}
+
+func TestNameColumn(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cart.ts")
+	require.NoError(t, os.WriteFile(path, []byte("export class CartItem {}\nexport function Cart(cart: CartItem) {}\n"), 0644))
+	assert.Equal(t, 16, nameColumn(path, 2, "Cart"), "whole identifiers only")
+	assert.Equal(t, 0, nameColumn(path, 1, "Missing"))
+	assert.Equal(t, 0, nameColumn(path, 9, "Cart"))
+}
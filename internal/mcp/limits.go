@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Defaults for MCPConfig limits left at zero
+const (
+	defaultMaxConcurrentAnalyses = 1
+	defaultMaxQueuedAnalyses     = 8
+)
+
+// BusyError is returned when the analysis queue is full
+type BusyError struct {
+	Running int
+	Queued  int
+}
+
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("server busy: %d analyses running and %d queued, retry in a few seconds", e.Running, e.Queued)
+}
+
+// callLimiter bounds concurrent analyses and the rate of tool calls per session
+type callLimiter struct {
+	mu        sync.Mutex
+	slots     chan struct{}            // One token per running analysis
+	maxQueued int                      // Analyses allowed to wait for a slot
+	waiting   int                      // Analyses waiting for a slot
+	inflight  map[string]*analysisCall // Running or queued analyses by target directory
+
+	ratePerMinute int // Tool calls per session per minute, 0 for unlimited
+	calls         map[mcp.Session][]time.Time
+	now           func() time.Time
+}
+
+// analysisCall is shared by every caller asking for the same directory while it runs
+type analysisCall struct {
+	done    chan struct{}
+	joiners int // Callers waiting on this analysis besides the one running it
+	graph   *types.CodeGraph
+	err     error
+}
+
+func newCallLimiter(maxConcurrent, maxQueued, ratePerMinute int) *callLimiter {
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultMaxConcurrentAnalyses
+	}
+	if maxQueued <= 0 {
+		maxQueued = defaultMaxQueuedAnalyses
+	}
+	return &callLimiter{
+		slots:         make(chan struct{}, maxConcurrent),
+		maxQueued:     maxQueued,
+		inflight:      make(map[string]*analysisCall),
+		ratePerMinute: ratePerMinute,
+		calls:         make(map[mcp.Session][]time.Time),
+		now:           time.Now,
+	}
+}
+
+// analyze runs an analysis of key once a slot is free. Callers asking for a key that
+// is already running or queued wait for that analysis instead of starting another.
+func (l *callLimiter) analyze(key string, run func() (*types.CodeGraph, error)) (*types.CodeGraph, error) {
+	l.mu.Lock()
+	if call := l.inflight[key]; call != nil {
+		call.joiners++
+		l.mu.Unlock()
+		<-call.done
+		return call.graph, call.err
+	}
+	running := len(l.slots)
+	if running == cap(l.slots) && l.waiting >= l.maxQueued {
+		queued := l.waiting
+		l.mu.Unlock()
+		return nil, &BusyError{Running: running, Queued: queued}
+	}
+	call := &analysisCall{done: make(chan struct{})}
+	l.inflight[key] = call
+	l.waiting++
+	l.mu.Unlock()
+
+	if running == cap(l.slots) {
+		log.Printf("[MCP] Analysis of %s queued behind %d running analyses", key, running)
+	}
+	l.slots <- struct{}{}
+	l.mu.Lock()
+	l.waiting--
+	l.mu.Unlock()
+
+	call.graph, call.err = run()
+	<-l.slots
+
+	l.mu.Lock()
+	delete(l.inflight, key)
+	l.mu.Unlock()
+	close(call.done)
+	return call.graph, call.err
+}
+
+// stats returns the number of running and queued analyses
+func (l *callLimiter) stats() (running, queued int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.slots), l.waiting
+}
+
+// allow records a tool call for the session, returning how long to wait if the
+// session has used its calls for the last minute
+func (l *callLimiter) allow(session mcp.Session) (time.Duration, bool) {
+	if l.ratePerMinute <= 0 {
+		return 0, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	cutoff := now.Add(-time.Minute)
+	for key, times := range l.calls {
+		if key != session && (len(times) == 0 || times[len(times)-1].Before(cutoff)) {
+			delete(l.calls, key)
+		}
+	}
+	recent := l.calls[session][:0]
+	for _, t := range l.calls[session] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= l.ratePerMinute {
+		l.calls[session] = recent
+		return recent[0].Sub(cutoff), false
+	}
+	l.calls[session] = append(recent, now)
+	return 0, true
+}
+
+// rateLimitMiddleware answers tool calls over the session's rate with an error result
+func (s *CodeContextMCPServer) rateLimitMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == "tools/call" {
+			if wait, ok := s.limiter.allow(req.GetSession()); !ok {
+				log.Printf("[MCP] WARNING: Rate limit exceeded (%d calls per minute)", s.limiter.ratePerMinute)
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+						"Rate limit exceeded: at most %d tool calls per minute. Retry in %.0f seconds.",
+						s.limiter.ratePerMinute, wait.Round(time.Second).Seconds()+1)}},
+				}, nil
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
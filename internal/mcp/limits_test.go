@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallLimiterSharesAnalyses(t *testing.T) {
+	limiter := newCallLimiter(1, 1, 0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	graph := &types.CodeGraph{}
+	runs := 0
+	run := func() (*types.CodeGraph, error) {
+		runs++
+		close(started)
+		<-release
+		return graph, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]*types.CodeGraph, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = limiter.analyze("/repo", run)
+	}()
+	<-started
+
+	// Same directory joins the running analysis
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[1], _ = limiter.analyze("/repo", func() (*types.CodeGraph, error) {
+			t.Error("duplicate analysis started")
+			return nil, nil
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.inflight["/repo"].joiners == 1
+	}, time.Second, time.Millisecond)
+
+	// Another directory queues; with the queue full the next one is turned away
+	queued := make(chan error, 1)
+	go func() {
+		_, err := limiter.analyze("/other", func() (*types.CodeGraph, error) { return graph, nil })
+		queued <- err
+	}()
+	require.Eventually(t, func() bool {
+		limiter.mu.Lock()
+		defer limiter.mu.Unlock()
+		return limiter.waiting == 1
+	}, time.Second, time.Millisecond)
+
+	_, err := limiter.analyze("/third", func() (*types.CodeGraph, error) { return graph, nil })
+	var busy *BusyError
+	require.True(t, errors.As(err, &busy))
+	assert.Equal(t, BusyError{Running: 1, Queued: 1}, *busy)
+
+	close(release)
+	wg.Wait()
+	require.NoError(t, <-queued)
+	assert.Equal(t, 1, runs)
+	assert.Same(t, graph, results[0])
+	assert.Same(t, graph, results[1])
+	assert.Empty(t, limiter.inflight)
+}
+
+func TestConcurrentAnalysesOfDifferentDirectories(t *testing.T) {
+	goDir, pyDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(goDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pyDir, "app.py"), []byte("def run():\n    pass\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = goDir
+	config.MaxConcurrentAnalyses = 2
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	want := map[string]string{goDir: "main.go", pyDir: "app.py"}
+	var wg sync.WaitGroup
+	for _, dir := range []string{goDir, pyDir, goDir, pyDir} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			graph, err := server.refreshAnalysisWithProfile(dir, "fast")
+			if assert.NoError(t, err) {
+				// Each call gets its own directory's graph, whichever is installed last
+				assert.Len(t, graph.Files, 1)
+				assert.Contains(t, graph.Files, filepath.Join(dir, want[dir]))
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The graph is one directory's analysis, without files of the other
+	require.Len(t, server.graph.Files, 1)
+	assert.Contains(t, server.graph.Files, filepath.Join(server.graphDir, want[server.graphDir]))
+}
+
+func TestCallLimiterRate(t *testing.T) {
+	limiter := newCallLimiter(0, 0, 2)
+	assert.Equal(t, defaultMaxConcurrentAnalyses, cap(limiter.slots))
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter.now = func() time.Time { return now }
+	session := &mcp.ServerSession{}
+
+	_, ok := limiter.allow(session)
+	assert.True(t, ok)
+	now = now.Add(20 * time.Second)
+	_, ok = limiter.allow(session)
+	assert.True(t, ok)
+	wait, ok := limiter.allow(session)
+	assert.False(t, ok)
+	assert.Equal(t, 40*time.Second, wait)
+
+	// Other sessions have their own budget
+	_, ok = limiter.allow(&mcp.ServerSession{})
+	assert.True(t, ok)
+
+	now = now.Add(41 * time.Second)
+	_, ok = limiter.allow(session)
+	assert.True(t, ok)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	server := &CodeContextMCPServer{limiter: newCallLimiter(1, 1, 1)}
+	calls := 0
+	handler := server.rateLimitMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		calls++
+		return &mcp.CallToolResult{}, nil
+	})
+	req := &mcp.CallToolRequest{Session: &mcp.ServerSession{}}
+
+	result, err := handler(context.Background(), "tools/call", req)
+	require.NoError(t, err)
+	assert.False(t, result.(*mcp.CallToolResult).IsError)
+
+	result, err = handler(context.Background(), "tools/call", req)
+	require.NoError(t, err)
+	assert.True(t, result.(*mcp.CallToolResult).IsError)
+	assert.Contains(t, result.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text, "Rate limit exceeded")
+
+	// Other methods are not limited
+	_, err = handler(context.Background(), "tools/list", req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
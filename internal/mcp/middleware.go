@@ -0,0 +1,99 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetMiddlewareChainsArgs struct {
+	Path      string `json:"path,omitempty"`       // Optional: only routes whose full path contains this text
+	Method    string `json:"method,omitempty"`     // Optional: only routes of this HTTP method
+	FilePath  string `json:"file_path,omitempty"`  // Optional: only routes declared in files whose path contains this text
+	Framework string `json:"framework,omitempty"`  // Optional: only express, koa, gin or fastapi routes
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum routes listed (default 50)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getMiddlewareChains(ctx context.Context, req *mcp.CallToolRequest, args GetMiddlewareChainsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_middleware_chains with args: %+v", args)
+	start := time.Now()
+
+	switch args.Framework {
+	case "", analyzer.MiddlewareExpress, analyzer.MiddlewareKoa, analyzer.MiddlewareGin, analyzer.MiddlewareFastAPI:
+	default:
+		return nil, nil, fmt.Errorf("unknown web framework %q (use express, koa, gin or fastapi)", args.Framework)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+	var chains []analyzer.MiddlewareChain
+	for _, chain := range analyzer.FindMiddlewareChains(graph) {
+		if (args.Path != "" && !strings.Contains(chain.Path, args.Path)) ||
+			(args.Method != "" && !strings.EqualFold(chain.Method, args.Method)) ||
+			(args.Framework != "" && chain.Framework != args.Framework) {
+			continue
+		}
+		chain.File = relative(chain.File)
+		if args.FilePath != "" && !strings.Contains(chain.File, args.FilePath) {
+			continue
+		}
+		chains = append(chains, chain)
+	}
+
+	var result strings.Builder
+	result.WriteString("# Middleware Chains\n\n")
+	if len(chains) == 0 {
+		result.WriteString("_No Express, Koa, Gin or FastAPI routes found_\n")
+	} else {
+		result.WriteString(fmt.Sprintf("**Routes:** %d\n\n", len(chains)))
+		for i, chain := range chains {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("_... and %d more routes_\n", len(chains)-i))
+				break
+			}
+			result.WriteString(fmt.Sprintf("## %s %s (%s)\n\n", chain.Method, chain.Path, chain.Framework))
+			result.WriteString(fmt.Sprintf("**Declared:** `%s:%d`\n", chain.File, chain.Line))
+			if !chain.Mounted {
+				result.WriteString("_The router is not mounted on an application in the analyzed code, so the path is relative to it and outer middleware may be missing._\n")
+			}
+			result.WriteString("\n")
+			for j, step := range chain.Middleware {
+				result.WriteString(fmt.Sprintf("%d. `%s` — %s (`%s:%d`)\n", j+1, step.Name, step.Scope, relative(step.File), step.Line))
+			}
+			result.WriteString(fmt.Sprintf("%d. `%s` — handler\n\n", len(chain.Middleware)+1, chain.Handler))
+		}
+	}
+
+	log.Printf("[MCP] Tool completed: get_middleware_chains (took %v, %d routes)", time.Since(start), len(chains))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMiddlewareChains(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"app.js":   "const express = require('express');\nconst users = require('./users');\n\nconst app = express();\napp.use(cors());\napp.use('/users', users);\napp.get('/health', health);\n",
+		"users.js": "const express = require('express');\nconst router = express.Router();\n\nrouter.get('/:id', auth, getUser);\n\nmodule.exports = router;\n",
+		"admin.js": "const express = require('express');\nconst admin = express.Router();\n\nadmin.delete('/cache', flush);\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+	}
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getMiddlewareChains(ctx, nil, GetMiddlewareChainsArgs{Path: "/users"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Routes:** 1")
+	assert.Contains(t, text, "## GET /users/:id (express)")
+	assert.Contains(t, text, "**Declared:** `users.js:4`")
+	assert.Contains(t, text, "1. `cors()` — global (`app.js:5`)\n2. `auth` — route (`users.js:4`)\n3. `getUser` — handler")
+	assert.NotContains(t, text, "/health")
+
+	response, _, err = server.getMiddlewareChains(ctx, nil, GetMiddlewareChainsArgs{Method: "delete"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "not mounted on an application")
+
+	_, _, err = server.getMiddlewareChains(ctx, nil, GetMiddlewareChainsArgs{Framework: "flask"})
+	assert.ErrorContains(t, err, "unknown web framework")
+}
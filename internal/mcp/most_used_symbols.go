@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type MostUsedSymbolsArgs struct {
+	Kind      string `json:"kind,omitempty"`       // Optional: symbol kind or type, e.g. "function"
+	Package   string `json:"package,omitempty"`    // Optional: directory whose symbols are ranked, subdirectories included
+	Prefix    string `json:"prefix,omitempty"`     // Optional: case-insensitive start of the name
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum symbols listed (default 20)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) mostUsedSymbols(ctx context.Context, req *mcp.CallToolRequest, args MostUsedSymbolsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: most_used_symbols with args: %+v", args)
+	start := time.Now()
+
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	ranked := analyzer.RankSymbolPopularity(graph, targetDir, analyzer.PopularityOptions{
+		Kind:    args.Kind,
+		Package: args.Package,
+		Prefix:  args.Prefix,
+	})
+
+	var filters []string
+	if args.Kind != "" {
+		filters = append(filters, "kind `"+args.Kind+"`")
+	}
+	if args.Package != "" {
+		filters = append(filters, "package `"+args.Package+"`")
+	}
+	if args.Prefix != "" {
+		filters = append(filters, "prefix `"+args.Prefix+"`")
+	}
+
+	var result strings.Builder
+	result.WriteString("# Most Used Symbols\n\n")
+	if len(filters) > 0 {
+		result.WriteString(fmt.Sprintf("**Filters:** %s\n\n", strings.Join(filters, ", ")))
+	}
+	if len(ranked) == 0 {
+		result.WriteString("No symbol is used outside its own file.\n")
+	} else {
+		result.WriteString("Prefer these established symbols over writing new ones that do the same.\n\n")
+	}
+	shown := ranked
+	if len(shown) > args.Limit {
+		shown = shown[:args.Limit]
+	}
+	for i, symbol := range shown {
+		result.WriteString(fmt.Sprintf("%d. **`%s`** (%s) in `%s:%d` — used in %d files, %d references\n", i+1, symbol.Name, symbol.Kind, symbol.File, symbol.Line, symbol.Files, symbol.References))
+		if symbol.Signature != "" {
+			result.WriteString(fmt.Sprintf("   - `%s`\n", symbol.Signature))
+		}
+	}
+	if len(shown) < len(ranked) {
+		result.WriteString(fmt.Sprintf("\n_... and %d more symbols_\n", len(ranked)-len(shown)))
+	}
+	if len(ranked) > 0 {
+		result.WriteString("\n_Uses are occurrences of the name in the non-test files importing the symbol's file or package, so a local name shadowing it counts too._\n")
+	}
+
+	log.Printf("[MCP] Tool completed: most_used_symbols (took %v, %d symbols)", time.Since(start), len(ranked))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
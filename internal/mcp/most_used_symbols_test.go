@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMostUsedSymbols(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"src/lib/format.js": "export function formatDate(d) { return String(d); }\nexport function pad(v) { return v; }\n",
+		"src/index.js":      "import { formatDate, pad } from './lib/format';\n\nexport function start() { return formatDate(1) + pad(2); }\n",
+		"src/report.js":     "import { formatDate } from './lib/format';\n\nexport function report() { return formatDate(3) + formatDate(4); }\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.mostUsedSymbols(ctx, nil, MostUsedSymbolsArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "1. **`formatDate`** (function) in `src/lib/format.js:1` — used in 2 files, 3 references\n   - `(d)`\n")
+	assert.Contains(t, text, "2. **`pad`** (function) in `src/lib/format.js:2` — used in 1 files, 1 references\n")
+
+	response, _, err = server.mostUsedSymbols(ctx, nil, MostUsedSymbolsArgs{Prefix: "PA", Limit: 1})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Filters:** prefix `PA`")
+	assert.Contains(t, text, "1. **`pad`**")
+	assert.NotContains(t, text, "formatDate")
+
+	response, _, err = server.mostUsedSymbols(ctx, nil, MostUsedSymbolsArgs{Package: "src/other"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "No symbol is used outside its own file.")
+}
@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetNamingConventionsArgs struct {
+	Path        string   `json:"path,omitempty"`        // Optional: directory to limit modules to, relative to the target directory
+	Language    string   `json:"language,omitempty"`    // Optional: only report this language
+	Conventions []string `json:"conventions,omitempty"` // Optional: conventions overriding the config, as "language.kind=style"
+	Limit       int      `json:"limit,omitempty"`       // Optional: maximum deviations listed per module (default 20)
+	TargetDir   string   `json:"target_dir,omitempty"`  // Optional: directory to analyze
+	Profile     string   `json:"profile,omitempty"`     // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getNamingConventions(ctx context.Context, req *mcp.CallToolRequest, args GetNamingConventionsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_naming_conventions with args: %+v", args)
+	start := time.Now()
+
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	// The config's profile, with the conventions of the call on top
+	profile := make(analyzer.NamingProfile)
+	for language, kinds := range s.currentConfig().Naming {
+		for kind, style := range kinds {
+			setNamingConvention(profile, language, kind, style)
+		}
+	}
+	for _, convention := range args.Conventions {
+		key, style, ok := strings.Cut(convention, "=")
+		language, kind, ok2 := strings.Cut(key, ".")
+		if !ok || !ok2 || language == "" || kind == "" {
+			return nil, nil, fmt.Errorf("invalid convention %q: use language.kind=style, e.g. python.function=snake_case", convention)
+		}
+		setNamingConvention(profile, language, kind, style)
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	report, err := analyzer.AnalyzeNaming(graph, targetDir, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	language := strings.ToLower(args.Language)
+	scope := ""
+	if args.Path != "" {
+		scope = filepath.ToSlash(filepath.Clean(args.Path))
+	}
+	inScope := func(module string) bool {
+		return scope == "" || scope == "." || module == scope || strings.HasPrefix(module, scope+"/")
+	}
+
+	var conventions []analyzer.NamingConvention
+	for _, convention := range report.Conventions {
+		if language == "" || convention.Language == language {
+			conventions = append(conventions, convention)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Naming Conventions\n\n")
+	if len(conventions) == 0 {
+		result.WriteString("No names to compare.\n\n")
+	} else {
+		result.WriteString("| Language | Kind | Convention | Consistency |\n|---|---|---|---|\n")
+	}
+	for _, convention := range conventions {
+		style := "`" + string(convention.Style) + "`"
+		if convention.Configured {
+			style += " (configured)"
+		}
+		result.WriteString(fmt.Sprintf("| %s | %s | %s | %.0f%% of %d |\n", convention.Language, convention.Kind, style,
+			float64(convention.Matching)*100/float64(convention.Names), convention.Names))
+	}
+	if len(conventions) > 0 {
+		result.WriteString("\n")
+	}
+
+	deviations := 0
+	for _, module := range report.Modules {
+		if !inScope(module.Module) {
+			continue
+		}
+		var local []analyzer.LocalConvention
+		for _, convention := range module.Local {
+			if language == "" || convention.Language == language {
+				local = append(local, convention)
+			}
+		}
+		var found []analyzer.NamingDeviation
+		for _, deviation := range module.Deviations {
+			if language == "" || deviation.Language == language {
+				found = append(found, deviation)
+			}
+		}
+		if len(local) == 0 && len(found) == 0 {
+			continue
+		}
+		deviations += len(found)
+		result.WriteString(fmt.Sprintf("## `%s`\n\n", module.Module))
+		for _, convention := range local {
+			result.WriteString(fmt.Sprintf("- Local convention: %s %s names are `%s` (project: `%s`)\n", convention.Language, convention.Kind, convention.Style, convention.Project))
+		}
+		shown := found
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, deviation := range shown {
+			location := deviation.File
+			if deviation.Line > 0 {
+				location = fmt.Sprintf("%s:%d", deviation.File, deviation.Line)
+			}
+			result.WriteString(fmt.Sprintf("- `%s` (%s, `%s`) is %s, expected `%s`\n", deviation.Name, deviation.Kind, location, deviation.Style, deviation.Expected))
+		}
+		if len(shown) < len(found) {
+			result.WriteString(fmt.Sprintf("- _... and %d more_\n", len(found)-len(shown)))
+		}
+		result.WriteString("\n")
+	}
+	if deviations == 0 {
+		result.WriteString("✅ Every name follows the convention of its module\n\n")
+	}
+	result.WriteString("_A module follows its own majority style when it has at least 3 names of a kind, unless the convention is configured under \"naming\" in .codecontext/config.yaml. Single words fit several styles and never deviate._\n")
+
+	log.Printf("[MCP] Tool completed: get_naming_conventions (took %v, %d conventions, %d deviations)", time.Since(start), len(conventions), deviations)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// setNamingConvention adds a convention to a profile, by lowercased language
+// and kind
+func setNamingConvention(profile analyzer.NamingProfile, language, kind, style string) {
+	language, kind = strings.ToLower(strings.TrimSpace(language)), strings.ToLower(strings.TrimSpace(kind))
+	if profile[language] == nil {
+		profile[language] = make(map[string]string)
+	}
+	profile[language][kind] = strings.TrimSpace(style)
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNamingConventions(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"app/models.py": "def load_user():\n    pass\n\ndef delete_user():\n    pass\n\ndef saveUser():\n    pass\n",
+		"app/views.py":  "def render_page():\n    pass\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.Naming = analyzer.NamingProfile{"python": {"file": "kebab-case"}}
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getNamingConventions(ctx, nil, GetNamingConventionsArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "| python | function | `snake_case` | 75% of 4 |\n")
+	assert.Contains(t, text, "| python | file | `kebab-case` (configured) | 100% of 2 |\n")
+	assert.Contains(t, text, "## `app`\n\n- `saveUser` (function, `app/models.py:7`) is camelCase, expected `snake_case`\n")
+
+	response, _, err = server.getNamingConventions(ctx, nil, GetNamingConventionsArgs{Conventions: []string{"Python.Function=camel"}})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "| python | function | `camelCase` (configured) | 25% of 4 |\n")
+	assert.Contains(t, text, "- `render_page` (function, `app/views.py:1`) is snake_case, expected `camelCase`\n")
+
+	response, _, err = server.getNamingConventions(ctx, nil, GetNamingConventionsArgs{Language: "go"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "No names to compare.")
+
+	_, _, err = server.getNamingConventions(ctx, nil, GetNamingConventionsArgs{Conventions: []string{"python=snake"}})
+	assert.ErrorContains(t, err, "invalid convention")
+	_, _, err = server.getNamingConventions(ctx, nil, GetNamingConventionsArgs{Conventions: []string{"python.function=hungarian"}})
+	assert.ErrorContains(t, err, "unknown naming style")
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/pkg/plugin"
+)
+
+// loadPlugins combines the compiled-in plugins with the external ones from the config
+func loadPlugins(configs []plugin.ExternalConfig) []plugin.Analyzer {
+	analyzers := plugin.Registered()
+	external, errs := plugin.LoadExternal(configs)
+	for _, err := range errs {
+		log.Printf("[MCP] WARNING: Skipping plugin: %v", err)
+	}
+	return append(analyzers, external...)
+}
+
+// registerPluginTools registers the MCP tools contributed by plugins. Tool names are
+// prefixed with the plugin name so they cannot shadow built-in tools.
+func (s *CodeContextMCPServer) registerPluginTools() {
+	count := 0
+	for _, analyzer := range s.plugins {
+		provider, ok := analyzer.(plugin.ToolProvider)
+		if !ok {
+			continue
+		}
+		for _, tool := range provider.Tools() {
+			if tool.Name == "" || tool.Handler == nil {
+				continue
+			}
+			name := fmt.Sprintf("%s_%s", analyzer.Name(), tool.Name)
+			log.Printf("[MCP] Registering plugin tool: %s", name)
+			mcp.AddTool(s.server, &mcp.Tool{
+				Name:        name,
+				Description: tool.Description,
+			}, s.pluginToolHandler(name, tool))
+			count++
+		}
+	}
+	if count > 0 {
+		log.Printf("[MCP] Registered %d plugin tools", count)
+	}
+}
+
+// pluginToolHandler adapts a plugin tool to an MCP handler, refreshing the analysis for
+// the optional target_dir argument first
+func (s *CodeContextMCPServer) pluginToolHandler(name string, tool plugin.Tool) mcp.ToolHandlerFor[map[string]any, any] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		log.Printf("[MCP] Tool called: %s with args: %+v", name, args)
+		start := time.Now()
+
+		targetDir, _ := args["target_dir"].(string)
+		targetDir, err := s.resolveTargetDir(targetDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+		if err != nil {
+			log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+			return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+		}
+
+		text, err := tool.Handler(ctx, graph, args)
+		if err != nil {
+			log.Printf("[MCP] ERROR: Plugin tool %s failed: %v", name, err)
+			return nil, nil, fmt.Errorf("plugin tool %s failed: %w", name, err)
+		}
+
+		elapsed := time.Since(start)
+		log.Printf("[MCP] Tool completed: %s (took %v)", name, elapsed)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		}, nil, nil
+	}
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/pkg/plugin"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPlugin contributes graph metadata and a tool reporting it
+type countingPlugin struct{}
+
+func (countingPlugin) Name() string { return "counter" }
+
+func (countingPlugin) Analyze(ctx context.Context, graph *types.CodeGraph) (*plugin.Contribution, error) {
+	return &plugin.Contribution{Metadata: map[string]interface{}{"files": len(graph.Files)}}, nil
+}
+
+func (countingPlugin) Tools() []plugin.Tool {
+	return []plugin.Tool{{
+		Name:        "files",
+		Description: "Report the number of analyzed files",
+		Handler: func(ctx context.Context, graph *types.CodeGraph, args map[string]interface{}) (string, error) {
+			metadata := graph.Metadata.Configuration[plugin.MetadataKey("counter")].(map[string]interface{})
+			return fmt.Sprintf("%v files (%v)", metadata["files"], args["label"]), nil
+		},
+	}}
+}
+
+func TestPluginTools(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.Plugins = []plugin.ExternalConfig{{Name: "no-command"}}
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	assert.Empty(t, server.plugins, "invalid external plugins are skipped")
+
+	server.plugins = []plugin.Analyzer{countingPlugin{}}
+	server.analyzer.SetPlugins(server.plugins)
+	assert.NotPanics(t, server.registerPluginTools)
+
+	handler := server.pluginToolHandler("counter_files", countingPlugin{}.Tools()[0])
+	response, _, err := handler(context.Background(), nil, map[string]any{"label": "demo"})
+	require.NoError(t, err)
+
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Equal(t, fmt.Sprintf("%d files (demo)", len(server.graph.Files)), text)
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+// maxPubAssetsListed bounds the asset entries and undeclared references listed per package
+const maxPubAssetsListed = 20
+
+// pubDependencyStyles is the order constraint styles are summarized in
+var pubDependencyStyles = []string{"caret", "range", "pinned", "any", analyzer.PubSourceSDK, analyzer.PubSourcePath, analyzer.PubSourceGit}
+
+// pubspecSection renders the dependency part of the framework analysis:
+// for each pubspec its SDK constraints, how its dependencies are
+// constrained, well-known packages by category, flavors and declared
+// assets with the code loading them
+func pubspecSection(pubspecs []analyzer.Pubspec) string {
+	var section strings.Builder
+	section.WriteString("## 📦 Flutter Dependencies\n\n")
+
+	for _, pubspec := range pubspecs {
+		name := pubspec.Name
+		if pubspec.Version != "" {
+			name += " " + pubspec.Version
+		}
+		section.WriteString(fmt.Sprintf("### %s (`%s`)\n\n", name, pubspec.File))
+		if pubspec.SDK != "" || pubspec.Flutter != "" {
+			var sdks []string
+			if pubspec.SDK != "" {
+				sdks = append(sdks, fmt.Sprintf("Dart `%s`", pubspec.SDK))
+			}
+			if pubspec.Flutter != "" {
+				sdks = append(sdks, fmt.Sprintf("Flutter `%s`", pubspec.Flutter))
+			}
+			section.WriteString(fmt.Sprintf("**SDK:** %s\n", strings.Join(sdks, ", ")))
+		}
+
+		scopes := make(map[string]int)
+		styles := make(map[string]int)
+		categories := make(map[string][]string)
+		var sourced []analyzer.PubDependency
+		for _, dep := range pubspec.Dependencies {
+			scopes[dep.Scope]++
+			if dep.Scope == "dependency_overrides" {
+				continue
+			}
+			styles[dep.Style()]++
+			if category := dep.Category(); category != "" {
+				categories[category] = append(categories[category], dep.Name)
+			}
+			if dep.Source == analyzer.PubSourcePath || dep.Source == analyzer.PubSourceGit {
+				sourced = append(sourced, dep)
+			}
+		}
+		section.WriteString(fmt.Sprintf("**Dependencies:** %d, dev: %d, overrides: %d\n",
+			scopes["dependencies"], scopes["dev_dependencies"], scopes["dependency_overrides"]))
+		var summary []string
+		for _, style := range pubDependencyStyles {
+			if styles[style] > 0 {
+				summary = append(summary, fmt.Sprintf("%s: %d", style, styles[style]))
+			}
+		}
+		if len(summary) > 0 {
+			section.WriteString(fmt.Sprintf("**Constraints:** %s\n", strings.Join(summary, ", ")))
+		}
+		var names []string
+		for category := range categories {
+			names = append(names, category)
+		}
+		sort.Strings(names)
+		for _, category := range names {
+			section.WriteString(fmt.Sprintf("**%s:** `%s`\n", strings.ToUpper(category[:1])+category[1:], strings.Join(categories[category], "`, `")))
+		}
+		if len(sourced) > 0 {
+			var deps []string
+			for _, dep := range sourced {
+				deps = append(deps, fmt.Sprintf("`%s` (%s `%s`)", dep.Name, dep.Source, dep.Location))
+			}
+			section.WriteString(fmt.Sprintf("**Not from pub.dev:** %s\n", strings.Join(deps, ", ")))
+		}
+		if len(pubspec.Flavors) > 0 {
+			section.WriteString(fmt.Sprintf("**Flavors:** %s\n", strings.Join(pubspec.Flavors, ", ")))
+		}
+		if len(pubspec.Fonts) > 0 {
+			var fonts []string
+			for _, font := range pubspec.Fonts {
+				fonts = append(fonts, font.Family)
+			}
+			section.WriteString(fmt.Sprintf("**Fonts:** %s\n", strings.Join(fonts, ", ")))
+		}
+		section.WriteString("\n")
+
+		if len(pubspec.Assets) > 0 {
+			section.WriteString("| Asset | Files | References | Flavors |\n")
+			section.WriteString("|-------|-------|------------|---------|\n")
+			for i, asset := range pubspec.Assets {
+				if i == maxPubAssetsListed {
+					section.WriteString(fmt.Sprintf("\n_... and %d more assets_\n", len(pubspec.Assets)-i))
+					break
+				}
+				files := fmt.Sprint(len(asset.Files))
+				if asset.Missing {
+					files = "❌ missing"
+				}
+				section.WriteString(fmt.Sprintf("| `%s` | %s | %d | %s |\n", asset.Entry, files, len(asset.References), strings.Join(asset.Flavors, ", ")))
+			}
+			section.WriteString("\n")
+		}
+		if len(pubspec.Undeclared) > 0 {
+			section.WriteString("**Undeclared assets** (not in the flutter assets list, so loading them fails):\n")
+			for i, ref := range pubspec.Undeclared {
+				if i == maxPubAssetsListed {
+					section.WriteString(fmt.Sprintf("- _... and %d more_\n", len(pubspec.Undeclared)-i))
+					break
+				}
+				section.WriteString(fmt.Sprintf("- `%s:%d` — `%s`\n", ref.File, ref.Line, ref.Text))
+			}
+			section.WriteString("\n")
+		}
+	}
+	return section.String()
+}
@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameworkAnalysisPubspec(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"pubspec.yaml": "name: shop\nversion: 1.0.0\nenvironment:\n  sdk: ^3.2.0\n" +
+			"dependencies:\n  flutter:\n    sdk: flutter\n  flutter_bloc: ^8.1.3\n  go_router: 13.0.0\n  core:\n    path: ../core\n" +
+			"dev_dependencies:\n  build_runner: any\n" +
+			"flutter:\n  assets:\n    - assets/images/\n    - path: assets/dev/\n      flavors: [dev]\n",
+		"assets/images/logo.png": "png",
+		"assets/icons/cart.png":  "png",
+		"lib/home.dart": "class Home extends StatelessWidget {\n  Widget build(BuildContext context) {\n" +
+			"    return Column(children: [Image.asset('assets/images/logo.png'), Image.asset('assets/icons/cart.png')]);\n  }\n}\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "flutter"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## 📦 Flutter Dependencies\n\n### shop 1.0.0 (`pubspec.yaml`)\n\n**SDK:** Dart `^3.2.0`\n"+
+		"**Dependencies:** 4, dev: 1, overrides: 0\n**Constraints:** caret: 1, pinned: 1, any: 1, sdk: 1, path: 1\n"+
+		"**Codegen:** `build_runner`\n**Navigation:** `go_router`\n**State:** `flutter_bloc`\n**Not from pub.dev:** `core` (path `../core`)\n**Flavors:** dev\n")
+	assert.Contains(t, text, "| `assets/images/` | 1 | 1 |  |\n| `assets/dev/` | ❌ missing | 0 | dev |\n")
+	assert.Contains(t, text, "**Undeclared assets** (not in the flutter assets list, so loading them fails):\n- `lib/home.dart:3` — `assets/icons/cart.png`\n")
+
+	response, _, err = server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "React"})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "Flutter Dependencies")
+}
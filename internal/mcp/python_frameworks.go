@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+// maxPythonListed bounds each list of the Python section
+const maxPythonListed = 20
+
+// pythonFrameworkFocus reports whether a framework filter of the framework
+// analysis asks for the Python section
+func pythonFrameworkFocus(framework string) bool {
+	switch strings.ToLower(framework) {
+	case "python", "fastapi", "flask", "django", "pydantic", "pytest":
+		return true
+	}
+	return false
+}
+
+// pythonFrameworkSection renders the Python part of the framework analysis:
+// the routes of FastAPI, Flask and Django REST views, the data models with
+// their fields and the pytest fixtures. Focusing on a web framework keeps
+// only its routes.
+func pythonFrameworkSection(report *analyzer.PythonFrameworkReport, framework string) string {
+	var section strings.Builder
+	section.WriteString("## 🐍 Python Frameworks\n\n")
+
+	var routes []analyzer.PythonRoute
+	for _, route := range report.Routes {
+		if !webFrameworkFocus(framework) || strings.EqualFold(route.Framework, framework) {
+			routes = append(routes, route)
+		}
+	}
+	counts := report.Counts()
+	var kinds, summary []string
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		summary = append(summary, fmt.Sprintf("%s: %d", kind, counts[kind]))
+	}
+	section.WriteString(fmt.Sprintf("**Routes:** %d\n", len(routes)))
+	if len(summary) > 0 {
+		section.WriteString(fmt.Sprintf("**Models:** %d (%s)\n", len(report.Models), strings.Join(summary, ", ")))
+	}
+	if len(report.Fixtures) > 0 {
+		section.WriteString(fmt.Sprintf("**Pytest fixtures:** %d\n", len(report.Fixtures)))
+	}
+	section.WriteString("\n")
+
+	if len(routes) > 0 {
+		section.WriteString("### Routes\n\n")
+		section.WriteString("| Method | Path | Handler | Framework | Location |\n")
+		section.WriteString("|--------|------|---------|-----------|----------|\n")
+		for i, route := range routes {
+			if i == maxPythonListed {
+				section.WriteString(fmt.Sprintf("\n_... and %d more_\n", len(routes)-i))
+				break
+			}
+			handler := route.Handler
+			if route.Async {
+				handler = "async " + handler
+			}
+			section.WriteString(fmt.Sprintf("| %s | `%s` | `%s` | %s | `%s:%d` |\n", route.Method, route.Path, handler, route.Framework, route.File, route.Line))
+		}
+		section.WriteString("\n")
+	}
+
+	if len(report.Models) > 0 {
+		section.WriteString("### Models\n\n")
+		for i, model := range report.Models {
+			if i == maxPythonListed {
+				section.WriteString(fmt.Sprintf("- _... and %d more_\n", len(report.Models)-i))
+				break
+			}
+			line := fmt.Sprintf("- `%s` (%s) — `%s:%d`", model.Name, model.Kind, model.File, model.Line)
+			if len(model.Fields) > 0 {
+				line += fmt.Sprintf(": `%s`", strings.Join(model.Fields, "`, `"))
+			}
+			section.WriteString(line + "\n")
+		}
+		section.WriteString("\n")
+	}
+
+	if len(report.Fixtures) > 0 {
+		section.WriteString("### Pytest Fixtures\n\n")
+		for i, fixture := range report.Fixtures {
+			if i == maxPythonListed {
+				section.WriteString(fmt.Sprintf("- _... and %d more_\n", len(report.Fixtures)-i))
+				break
+			}
+			section.WriteString(fmt.Sprintf("- `%s` (%s scope) — `%s:%d`\n", fixture.Name, fixture.Scope, fixture.File, fixture.Line))
+		}
+		section.WriteString("\n")
+	}
+	return section.String()
+}
+
+// webFrameworkFocus reports whether a framework filter names a Python web
+// framework
+func webFrameworkFocus(framework string) bool {
+	switch strings.ToLower(framework) {
+	case "fastapi", "flask", "django":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/query"
+)
+
+type QueryGraphArgs struct {
+	Query     string `json:"query"`                // e.g. MATCH a:file->imports->b:file WHERE b.path CONTAINS 'auth' RETURN a
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+}
+
+func (s *CodeContextMCPServer) queryGraph(ctx context.Context, req *mcp.CallToolRequest, args QueryGraphArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: query_graph with args: %+v", args)
+	start := time.Now()
+
+	// Parse first so syntax errors don't wait for the analysis
+	q, err := query.Parse(args.Query)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Invalid query: %v", err)
+		return nil, nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	result := query.Execute(graph, q, targetDir)
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: query_graph (took %v, %d rows)", elapsed, len(result.Rows))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.Markdown()}},
+	}, nil, nil
+}
@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+type ReachableFromArgs struct {
+	Entries   []string `json:"entries,omitempty"`    // Optional: files (relative to the target directory) or symbol names to start from (default: every entry point)
+	Path      string   `json:"path,omitempty"`       // Optional: only list files under this path
+	Limit     int      `json:"limit,omitempty"`      // Optional: maximum files listed per section (default 50)
+	TargetDir string   `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string   `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) reachableFrom(ctx context.Context, req *mcp.CallToolRequest, args ReachableFromArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: reachable_from with args: %+v", args)
+	start := time.Now()
+
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var roots []string
+	for _, entry := range args.Entries {
+		files, err := resolveEntryFiles(graph, targetDir, entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		roots = append(roots, files...)
+	}
+
+	reachability := analyzer.AnalyzeReachability(graph, targetDir, roots)
+	prefix := strings.Trim(filepath.ToSlash(args.Path), "/")
+	under := func(file string) bool {
+		return prefix == "" || file == prefix || strings.HasPrefix(file, prefix+"/")
+	}
+	var reachable []analyzer.ReachableFile
+	for _, file := range reachability.Reachable {
+		if under(file.File) {
+			reachable = append(reachable, file)
+		}
+	}
+	var unreachable []string
+	for _, file := range reachability.Unreachable {
+		if under(file) {
+			unreachable = append(unreachable, file)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Reachability\n\n")
+	if len(reachability.Roots) == 0 {
+		result.WriteString("_No entry points found; pass the files or symbols to start from as entries_\n")
+		log.Printf("[MCP] Tool completed: reachable_from (took %v, no roots)", time.Since(start))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+		}, nil, nil
+	}
+	if len(args.Entries) == 0 {
+		result.WriteString(fmt.Sprintf("**Roots:** %d entry points\n\n", len(reachability.Roots)))
+	} else {
+		result.WriteString(fmt.Sprintf("**Roots:** `%s`\n\n", strings.Join(reachability.Roots, "`, `")))
+	}
+	result.WriteString(fmt.Sprintf("**Reachable:** %d files | **Unreachable:** %d files\n\n", len(reachable), len(unreachable)))
+
+	if len(reachable) > 0 {
+		result.WriteString("## Reachable Files\n\n")
+		result.WriteString("| File | Depth | Reached from | Via |\n")
+		result.WriteString("|------|-------|--------------|-----|\n")
+		shown := reachable
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, file := range shown {
+			from, via := "_root_", "-"
+			if file.Depth > 0 {
+				from, via = "`"+file.From+"`", file.Via
+			}
+			result.WriteString(fmt.Sprintf("| `%s` | %d | %s | %s |\n", file.File, file.Depth, from, via))
+		}
+		if len(shown) < len(reachable) {
+			result.WriteString(fmt.Sprintf("\n_... and %d more files_\n", len(reachable)-len(shown)))
+		}
+		result.WriteString("\n")
+	}
+
+	if len(unreachable) > 0 {
+		if len(args.Entries) == 0 {
+			result.WriteString("## Unreachable From Any Entry Point\n\n")
+		} else {
+			result.WriteString("## Unreachable From These Roots\n\n")
+		}
+		shown := unreachable
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, file := range shown {
+			result.WriteString(fmt.Sprintf("- `%s`\n", file))
+		}
+		if len(shown) < len(unreachable) {
+			result.WriteString(fmt.Sprintf("\n_... and %d more files_\n", len(unreachable)-len(shown)))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("_Reachability is tracked per file through imports, symbol relationships and packages. Code loaded by reflection, plugins, dependency injection or configuration is not seen; check before deleting unreachable files._\n")
+
+	log.Printf("[MCP] Tool completed: reachable_from (took %v, %d roots, %d reachable, %d unreachable)", time.Since(start), len(reachability.Roots), len(reachable), len(unreachable))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// resolveEntryFiles returns the graph paths of the file an entry names,
+// relative to targetDir or absolute, or else of the files defining symbols
+// with that name. Imported names are not definitions.
+func resolveEntryFiles(graph *types.CodeGraph, targetDir, entry string) ([]string, error) {
+	path := expandPath(entry)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(targetDir, path)
+	}
+	if graph.Files[path] != nil {
+		return []string{path}, nil
+	}
+	var files []string
+	for path, file := range graph.Files {
+		for _, id := range file.Symbols {
+			if symbol := graph.Symbols[id]; symbol != nil && symbol.Name == entry && symbol.Type != types.SymbolTypeImport {
+				files = append(files, path)
+				break
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file or symbol named %q", entry)
+	}
+	sort.Strings(files)
+	return files, nil
+}
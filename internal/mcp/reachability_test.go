@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReachableFrom(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"src/index.js":      "import { format } from './format';\n\nexport function start() { return format(1); }\n",
+		"src/format.js":     "export function format(value) { return String(value); }\n",
+		"src/legacy.js":     "export function legacy() { return 1; }\n",
+		"src/admin/tool.js": "import { format } from '../format';\n\nexport function runTool() { return format(2); }\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.reachableFrom(ctx, nil, ReachableFromArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Roots:** 1 entry points")
+	assert.Contains(t, text, "| `src/format.js` | 1 | `src/index.js` | imports |")
+	assert.Contains(t, text, "## Unreachable From Any Entry Point\n\n- `src/admin/tool.js`\n- `src/legacy.js`\n")
+
+	response, _, err = server.reachableFrom(ctx, nil, ReachableFromArgs{Entries: []string{"runTool"}, Path: "src/admin"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Roots:** `src/admin/tool.js`")
+	assert.Contains(t, text, "**Reachable:** 1 files | **Unreachable:** 0 files")
+
+	_, _, err = server.reachableFrom(ctx, nil, ReachableFromArgs{Entries: []string{"missing.js"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// redactionMiddleware masks secrets in the text of every tool result, including
+// errors, so nothing read from the project reaches the client unfiltered
+func (s *CodeContextMCPServer) redactionMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+		redactor := s.currentAnalyzer().Redactor()
+		if err != nil && redactor != nil {
+			if masked := redactor.Text(err.Error()); masked != err.Error() {
+				err = errors.New(masked)
+			}
+		}
+		if res, ok := result.(*mcp.CallToolResult); ok && res != nil {
+			for _, content := range res.Content {
+				if text, ok := content.(*mcp.TextContent); ok {
+					text.Text = redactor.Text(text.Text)
+				}
+			}
+		}
+		return result, err
+	}
+}
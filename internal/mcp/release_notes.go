@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/apidiff"
+)
+
+type GetReleaseNotesArgs struct {
+	From      string `json:"from"`                 // Older git ref: tag, branch or commit
+	To        string `json:"to,omitempty"`         // Optional: newer git ref (default: the working tree)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getReleaseNotes(ctx context.Context, req *mcp.CallToolRequest, args GetReleaseNotesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_release_notes with args: %+v", args)
+	start := time.Now()
+
+	if args.From == "" {
+		return nil, nil, fmt.Errorf("from is required")
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := s.resolveProfile(args.Profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Both revisions are analyzed from git, independently of the live graph
+	diff, err := apidiff.CompareRefs(ctx, targetDir, args.From, args.To, s.analyzeWith(profile))
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to compare refs: %v", err)
+		return nil, nil, err
+	}
+
+	log.Printf("[MCP] Tool completed: get_release_notes (took %v, %d changes)", time.Since(start), len(diff.Changes))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: diff.ReleaseNotes()}},
+	}, nil, nil
+}
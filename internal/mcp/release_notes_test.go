@@ -0,0 +1,23 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReleaseNotesErrors(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = t.TempDir()
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, _, err = server.getReleaseNotes(ctx, nil, GetReleaseNotesArgs{})
+	assert.ErrorContains(t, err, "from is required")
+
+	_, _, err = server.getReleaseNotes(ctx, nil, GetReleaseNotesArgs{From: "v1.0.0"})
+	assert.ErrorContains(t, err, "not a git repository")
+}
@@ -0,0 +1,151 @@
+package mcp
+
+import (
+	"log"
+	"reflect"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/lsp"
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+	"github.com/nuthan-ms/codecontext/internal/summarize"
+)
+
+// applyAnalysisSettings configures a new analyzer from the config. The profile,
+// redaction and summaries config are validated before anything changes.
+func (s *CodeContextMCPServer) applyAnalysisSettings(config *MCPConfig) error {
+	if _, err := analyzer.ParseProfile(config.Profile); err != nil {
+		return err
+	}
+	redactor, err := redact.New(config.Redaction)
+	if err != nil {
+		return err
+	}
+	summarizer, err := summarize.New(config.Summaries)
+	if err != nil {
+		return err
+	}
+	summarizer.SetReadOnly(config.ReadOnly)
+	builder, err := s.newGraphBuilder(config, redactor, summarizer)
+	if err != nil {
+		log.Printf("[MCP] WARNING: Failed to load WASM grammars: %v", err)
+	}
+	s.analyzer = builder
+	// Servers start again on the next deep lookup, with the new settings
+	s.languages.Close()
+	s.languages = lsp.NewManager(config.LanguageServers)
+	return nil
+}
+
+// newGraphBuilder returns a builder configured from config. AnalyzeDirectory
+// replaces a builder's graph and walk results, so every analysis uses its own.
+// The builder is usable when the WASM grammars fail to load.
+func (s *CodeContextMCPServer) newGraphBuilder(config *MCPConfig, redactor *redact.Redactor, summarizer *summarize.Summarizer) (*analyzer.GraphBuilder, error) {
+	builder := analyzer.NewGraphBuilder()
+	builder.SetPlugins(s.plugins)
+	builder.SetLazySemantic(true) // Only get_semantic_neighborhoods needs the git history
+	builder.SetRedactor(redactor)
+	builder.SetSummarizer(summarizer)
+	builder.SetIncludeDirs(config.IncludeDirs)
+	builder.SetExternalWorkspacePackages(config.ExternalWorkspaces)
+	builder.SetCoverageReports(config.CoverageReports)
+	builder.SetMutationReports(config.MutationReports)
+	builder.SetIndexes(config.Indexes)
+	builder.SetLanguageOverrides(config.LanguageOverrides)
+	builder.SetExcludePatterns(config.ExcludePatterns)
+	builder.SetUseDefaultExcludes(config.UseDefaultExcludes == nil || *config.UseDefaultExcludes)
+	builder.SetSemanticConfig(config.Semantic)
+	return builder, builder.LoadWASMGrammars(config.WASMGrammars, config.TargetDir)
+}
+
+// copyReloadable copies the settings Reload applies at runtime
+func copyReloadable(dst, src *MCPConfig) {
+	dst.ExcludePatterns = src.ExcludePatterns
+	dst.UseDefaultExcludes = src.UseDefaultExcludes
+	dst.IncludeDirs = src.IncludeDirs
+	dst.ExternalWorkspaces = src.ExternalWorkspaces
+	dst.CoverageReports = src.CoverageReports
+	dst.MutationReports = src.MutationReports
+	dst.Indexes = src.Indexes
+	dst.WASMGrammars = src.WASMGrammars
+	dst.LanguageOverrides = src.LanguageOverrides
+	dst.FlagHelpers = src.FlagHelpers
+	dst.Semantic = src.Semantic
+	dst.Profile = src.Profile
+	dst.Rules = src.Rules
+	dst.Redaction = src.Redaction
+	dst.Summaries = src.Summaries
+	dst.LanguageServers = src.LanguageServers
+}
+
+// Reload applies a changed configuration to the running server: exclude
+// patterns, language settings (include dirs, WASM grammars, feature flag
+// helpers), coverage reports, indexes, semantic analysis thresholds, the
+// default profile, rules, redaction, summaries and language servers. It waits
+// for running analyses, replaces the config, rules and analyzer under configMu,
+// clears the semantic and result caches and re-analyzes the target so the server
+// stays warm. Tool calls read the settings through currentConfig, currentRules
+// and currentAnalyzer. Other changes are logged and need a restart. An invalid
+// config leaves the current settings in place.
+func (s *CodeContextMCPServer) Reload(config *MCPConfig) error {
+	changed, err := s.applyReload(config)
+	if err != nil || !changed {
+		return err
+	}
+	s.semantic.clear()
+	s.results.invalidate()
+	s.dropSnapshot()
+	if err := s.refreshAnalysis(); err != nil {
+		log.Printf("[MCP] WARNING: Analysis after config reload failed: %v", err)
+	}
+	return nil
+}
+
+func (s *CodeContextMCPServer) applyReload(config *MCPConfig) (bool, error) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	current := s.config
+	next := *current
+	copyReloadable(&next, config)
+	if reflect.DeepEqual(next, *current) {
+		log.Printf("[MCP] Config reloaded: no changes that apply at runtime")
+		return false, nil
+	}
+	if err := s.applyAnalysisSettings(&next); err != nil {
+		return false, err
+	}
+	s.rules = loadRules(next.Rules)
+	s.config = &next
+	log.Printf("[MCP] Config reloaded: analysis settings updated, caches cleared")
+
+	static := *config
+	copyReloadable(&static, current)
+	static.Version = current.Version
+	if !reflect.DeepEqual(static, *current) {
+		log.Printf("[MCP] WARNING: Config changes besides analysis settings need a server restart")
+	}
+	return true, nil
+}
+
+// currentConfig returns the config in effect. Reload replaces the config rather
+// than changing it, so callers may keep using the one returned.
+func (s *CodeContextMCPServer) currentConfig() *MCPConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
+// currentRules returns the rules loaded from the config in effect
+func (s *CodeContextMCPServer) currentRules() []*rules.Rule {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.rules
+}
+
+// currentAnalyzer returns the builder configured from the config in effect
+func (s *CodeContextMCPServer) currentAnalyzer() *analyzer.GraphBuilder {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.analyzer
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "generated"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "generated", "api.ts"), []byte("export const api = 1;\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	require.NoError(t, server.refreshAnalysis())
+	before := len(server.graph.Files)
+
+	changed := *config
+	changed.ExcludePatterns = []string{"generated/**"}
+	changed.FlagHelpers = []string{"isEnabled"}
+	changed.Semantic = &git.SemanticConfig{AnalysisPeriodDays: 7}
+	changed.Name = "renamed"
+	require.NoError(t, server.Reload(&changed))
+
+	assert.Equal(t, []string{"isEnabled"}, server.config.FlagHelpers)
+	assert.Equal(t, "test-codecontext", server.config.Name, "settings outside analysis need a restart")
+	assert.Empty(t, config.ExcludePatterns, "the caller's config is not modified")
+	assert.Len(t, server.graph.Files, before-1, "the target is re-analyzed without the excluded file")
+
+	invalid := changed
+	invalid.Redaction = redact.Config{Patterns: []string{"("}}
+	assert.Error(t, server.Reload(&invalid))
+	assert.Empty(t, server.config.Redaction.Patterns, "an invalid config keeps the previous settings")
+}
+
+// TestReloadDuringCalls is meant for go test -race: tool calls read the settings
+// while a reload replaces them
+func TestReloadDuringCalls(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = createTestDirectory(t)
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := server.checkRules(ctx, nil, CheckRulesArgs{})
+			assert.NoError(t, err)
+			assert.Equal(t, config.TargetDir, server.status().TargetDir)
+		}()
+	}
+	changed := *config
+	changed.FlagHelpers = []string{"isEnabled"}
+	require.NoError(t, server.Reload(&changed))
+	wg.Wait()
+}
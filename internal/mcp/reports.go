@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/query"
+)
+
+// loadReports validates the saved queries from the config
+func loadReports(configs []query.ReportConfig) []*query.Report {
+	reports, errs := query.LoadReports(configs)
+	for _, err := range errs {
+		log.Printf("[MCP] WARNING: Skipping report: %v", err)
+	}
+	return reports
+}
+
+// registerReportTools registers a report_<name> tool for every saved query
+func (s *CodeContextMCPServer) registerReportTools() {
+	for _, report := range s.reports {
+		name := "report_" + report.Name()
+		description := report.Description()
+		if params := report.Parameters(); len(params) > 0 {
+			description += fmt.Sprintf(" Required parameters: %s.", strings.Join(params, ", "))
+		}
+		description += " Optional target_dir parameter."
+
+		log.Printf("[MCP] Registering report tool: %s", name)
+		mcp.AddTool(s.server, &mcp.Tool{
+			Name:        name,
+			Description: description,
+		}, s.reportToolHandler(name, report))
+	}
+	if len(s.reports) > 0 {
+		log.Printf("[MCP] Registered %d report tools", len(s.reports))
+	}
+}
+
+// reportToolHandler runs a saved query with the tool arguments as its parameters
+func (s *CodeContextMCPServer) reportToolHandler(name string, report *query.Report) mcp.ToolHandlerFor[map[string]any, any] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		log.Printf("[MCP] Tool called: %s with args: %+v", name, args)
+		start := time.Now()
+
+		params := make(map[string]string, len(args))
+		for key, value := range args {
+			params[key] = fmt.Sprint(value)
+		}
+
+		targetDir, err := s.resolveTargetDir(params["target_dir"])
+		if err != nil {
+			return nil, nil, err
+		}
+		graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+		if err != nil {
+			log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+			return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+		}
+
+		text, err := report.Run(graph, targetDir, params)
+		if err != nil {
+			log.Printf("[MCP] ERROR: %v", err)
+			return nil, nil, err
+		}
+
+		elapsed := time.Since(start)
+		log.Printf("[MCP] Tool completed: %s (took %v)", name, elapsed)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		}, nil, nil
+	}
+}
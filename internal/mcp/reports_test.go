@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportTools(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.Reports = []query.ReportConfig{
+		{Name: "named", Query: "MATCH symbol WHERE name = $name", Parameters: []string{"name"}},
+		{Name: "invalid", Query: "MATCH"},
+	}
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	require.Len(t, server.reports, 1, "invalid reports are skipped")
+
+	handler := server.reportToolHandler("report_named", server.reports[0])
+	_, _, err = handler(context.Background(), nil, map[string]any{})
+	assert.ErrorContains(t, err, "missing parameters: name")
+
+	response, _, err := handler(context.Background(), nil, map[string]any{"name": "main"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "# Report: named")
+	assert.Contains(t, text, "name = 'main'")
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resultCache keeps rendered tool responses for the current graph version.
+// The watcher bumps the version when files change, so responses are only
+// cached for directories the watcher observes; elsewhere every call re-analyzes.
+type resultCache struct {
+	mu      sync.Mutex
+	version uint64
+	entries map[string]string
+	hits    int
+	misses  int
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{entries: make(map[string]string)}
+}
+
+// invalidate drops every cached response and starts a new graph version
+func (c *resultCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version++
+	c.entries = make(map[string]string)
+}
+
+func (c *resultCache) stats() (entries, hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries), c.hits, c.misses
+}
+
+// cachedCall is one tool call's slot in the result cache. A nil cachedCall
+// caches nothing.
+type cachedCall struct {
+	cache   *resultCache
+	key     string
+	version uint64
+}
+
+// cachedCallFor returns the cache slot of a call, or nil when the watcher does
+// not observe targetDir and a change could go unnoticed
+func (s *CodeContextMCPServer) cachedCallFor(tool string, args any, targetDir string) *cachedCall {
+	s.stopMutex.RLock()
+	watched := s.watcher != nil && s.watchDir == targetDir
+	s.stopMutex.RUnlock()
+	if !watched {
+		return nil
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil
+	}
+	c := s.results
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &cachedCall{cache: c, key: tool + ":" + targetDir + ":" + string(data), version: c.version}
+}
+
+// result returns the cached response, if the graph has not changed since it was stored
+func (call *cachedCall) result() (*mcp.CallToolResult, bool) {
+	if call == nil {
+		return nil, false
+	}
+	c := call.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	text, ok := c.entries[call.key]
+	if !ok || c.version != call.version {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	log.Printf("[MCP] Returning cached result for %s", call.key)
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, true
+}
+
+// store caches a response unless the graph changed while it was rendered
+func (call *cachedCall) store(text string) {
+	if call == nil {
+		return
+	}
+	c := call.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version == call.version {
+		c.entries[call.key] = text
+	}
+}
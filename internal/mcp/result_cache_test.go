@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultCache(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	defer server.Stop()
+	ctx := context.Background()
+	args := GetCodebaseOverviewArgs{IncludeStats: true}
+
+	_, _, err = server.getCodebaseOverview(ctx, nil, args)
+	require.NoError(t, err)
+	entries, _, _ := server.results.stats()
+	assert.Equal(t, 0, entries, "nothing is cached while no watcher observes the target")
+
+	_, _, err = server.watchChanges(ctx, nil, WatchChangesArgs{Enable: true})
+	require.NoError(t, err)
+
+	first, _, err := server.getCodebaseOverview(ctx, nil, args)
+	require.NoError(t, err)
+	second, _, err := server.getCodebaseOverview(ctx, nil, args)
+	require.NoError(t, err)
+	assert.Equal(t, first.Content[0].(*mcp.TextContent).Text, second.Content[0].(*mcp.TextContent).Text)
+	entries, hits, misses := server.results.stats()
+	assert.Equal(t, 1, entries)
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 1, misses)
+
+	_, _, err = server.getCodebaseOverview(ctx, nil, GetCodebaseOverviewArgs{})
+	require.NoError(t, err)
+	entries, _, _ = server.results.stats()
+	assert.Equal(t, 2, entries, "different arguments are cached separately")
+
+	server.results.invalidate()
+	_, _, err = server.getCodebaseOverview(ctx, nil, args)
+	require.NoError(t, err)
+	_, hits, misses = server.results.stats()
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, 3, misses, "a file change invalidates cached results")
+
+	call := server.cachedCallFor("get_dependencies", GetDependenciesArgs{}, tmpDir)
+	server.results.invalidate()
+	call.store("stale")
+	entries, _, _ = server.results.stats()
+	assert.Equal(t, 0, entries, "results rendered across a change are not stored")
+
+	unwatched := server.cachedCallFor("get_dependencies", GetDependenciesArgs{}, t.TempDir())
+	assert.Nil(t, unwatched)
+	_, ok := unwatched.result()
+	assert.False(t, ok)
+}
+
+func TestResultCacheInvalidatedByAnyLanguage(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.DebounceMs = 10
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	defer server.Stop()
+	ctx := context.Background()
+
+	_, _, err = server.watchChanges(ctx, nil, WatchChangesArgs{Enable: true})
+	require.NoError(t, err)
+	_, _, err = server.getCodebaseOverview(ctx, nil, GetCodebaseOverviewArgs{})
+	require.NoError(t, err)
+	entries, _, _ := server.results.stats()
+	require.Equal(t, 1, entries)
+
+	// Let the watcher register the directory before the edit
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "widget.dart"), []byte("class Widget {}\n"), 0644))
+	assert.Eventually(t, func() bool {
+		entries, _, _ := server.results.stats()
+		return entries == 0
+	}, 5*time.Second, 10*time.Millisecond, "a Dart edit drops cached results")
+}
@@ -0,0 +1,59 @@
+package mcp
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// sandbox restricts the directories tool calls may analyze to a set of roots.
+// A sandbox without roots allows every directory.
+type sandbox struct {
+	roots []string // Absolute, with symlinks resolved
+}
+
+// newSandbox builds the allowlist from the configured roots. The server's own
+// target directory is always allowed.
+func newSandbox(allowedRoots []string, targetDir string) *sandbox {
+	sb := &sandbox{}
+	if len(allowedRoots) == 0 {
+		return sb
+	}
+	for _, root := range append([]string{targetDir}, allowedRoots...) {
+		if root == "" {
+			continue
+		}
+		resolved := canonicalPath(expandPath(root))
+		sb.roots = append(sb.roots, resolved)
+		log.Printf("[MCP] Sandbox: allowing %s", resolved)
+	}
+	return sb
+}
+
+// check returns an error unless dir lies within an allowed root
+func (sb *sandbox) check(dir string) error {
+	if sb == nil || len(sb.roots) == 0 {
+		return nil
+	}
+	resolved := canonicalPath(dir)
+	for _, root := range sb.roots {
+		if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("target_dir %s is outside the allowed roots", dir)
+}
+
+// canonicalPath makes path absolute and resolves symlinks, so links cannot
+// point outside an allowed root. Paths that do not exist are only cleaned.
+func canonicalPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved
+	}
+	return abs
+}
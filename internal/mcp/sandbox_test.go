@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandbox(t *testing.T) {
+	base := t.TempDir()
+	allowed := filepath.Join(base, "allowed")
+	outside := filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(allowed, "project"), outside, filepath.Join(base, "allowed-sibling")} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+	}
+	require.NoError(t, os.Symlink(outside, filepath.Join(allowed, "escape")))
+
+	sb := newSandbox([]string{allowed}, filepath.Join(base, "default"))
+	assert.NoError(t, sb.check(allowed))
+	assert.NoError(t, sb.check(filepath.Join(allowed, "project")))
+	assert.NoError(t, sb.check(filepath.Join(allowed, "missing")))
+	assert.NoError(t, sb.check(filepath.Join(base, "default", "sub")), "the server target is always allowed")
+	assert.Error(t, sb.check(outside))
+	assert.Error(t, sb.check(filepath.Join(allowed, "..", "outside")))
+	assert.Error(t, sb.check(filepath.Join(base, "allowed-sibling")))
+	assert.Error(t, sb.check(filepath.Join(allowed, "escape")), "symlinks are resolved")
+
+	assert.NoError(t, newSandbox(nil, base).check("/"))
+	var none *sandbox
+	assert.NoError(t, none.check("/"))
+}
+
+func TestSandboxedServer(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.AllowedRoots = []string{filepath.Join(tmpDir, "sub")}
+	config.ReadOnly = true
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	dir, err := server.resolveTargetDir("")
+	require.NoError(t, err)
+	assert.Equal(t, tmpDir, dir)
+	_, err = server.resolveTargetDir("/")
+	assert.ErrorContains(t, err, "outside the allowed roots")
+
+	_, _, err = server.getCodebaseOverview(ctx, nil, GetCodebaseOverviewArgs{TargetDir: "/"})
+	assert.ErrorContains(t, err, "outside the allowed roots")
+
+	_, _, err = server.annotate(ctx, nil, AnnotateArgs{Target: "main.ts", Text: "note"})
+	assert.ErrorContains(t, err, "read-only")
+	_, _, err = server.annotate(ctx, nil, AnnotateArgs{Action: "list"})
+	assert.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(tmpDir, ".codecontext", "annotations.json"))
+}
@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// searchScope limits symbol search to files inside a set of directories
+type searchScope struct {
+	dirs  []string // Absolute directories (or a single file) searched
+	label string   // Shown in the results header
+}
+
+// newSearchScope resolves the path_prefix and package arguments of
+// search_symbols. It returns nil when neither is set.
+//
+// path_prefix is a directory or file, relative to targetDir unless absolute.
+// package is a workspace package name from a package.json ("@myorg/utils"),
+// a Go import path or a dotted Python package; the last two match every
+// directory whose path ends with the package path.
+func newSearchScope(graph *types.CodeGraph, targetDir, pathPrefix, pkg string) (*searchScope, error) {
+	if pathPrefix == "" && pkg == "" {
+		return nil, nil
+	}
+
+	var scope searchScope
+	var labels []string
+	if pkg != "" {
+		dirs := packageDirs(graph, targetDir, pkg)
+		if len(dirs) == 0 {
+			return nil, fmt.Errorf("no package %q found in %s", pkg, targetDir)
+		}
+		scope.dirs = dirs
+		labels = append(labels, "package "+pkg)
+	}
+	if pathPrefix != "" {
+		prefix := filepath.Clean(filepath.FromSlash(pathPrefix))
+		if !filepath.IsAbs(prefix) {
+			prefix = filepath.Join(targetDir, prefix)
+		}
+		if scope.dirs == nil {
+			scope.dirs = []string{prefix}
+		} else {
+			// Both set: the prefix narrows the package directories
+			var narrowed []string
+			for _, dir := range scope.dirs {
+				switch {
+				case isWithin(prefix, dir):
+					narrowed = append(narrowed, prefix)
+				case isWithin(dir, prefix):
+					narrowed = append(narrowed, dir)
+				}
+			}
+			scope.dirs = narrowed
+		}
+		labels = append(labels, "path "+pathPrefix)
+	}
+	scope.label = strings.Join(labels, ", ")
+	return &scope, nil
+}
+
+// contains reports whether a file is inside the scope
+func (sc *searchScope) contains(file string) bool {
+	if sc == nil {
+		return true
+	}
+	for _, dir := range sc.dirs {
+		if isWithin(file, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWithin reports whether path is dir or inside it
+func isWithin(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// packageDirs finds the directories of a package, trying workspace package
+// names first and then directories ending in the package path
+func packageDirs(graph *types.CodeGraph, targetDir, pkg string) []string {
+	var manifests []string
+	for path := range graph.Files {
+		if filepath.Base(path) == "package.json" {
+			manifests = append(manifests, path)
+		}
+	}
+	for _, workspace := range analyzer.LoadWorkspacePackages(manifests) {
+		if workspace.Name == pkg {
+			return []string{workspace.Dir}
+		}
+	}
+
+	suffixes := []string{pkg}
+	if module := analyzer.GoModulePath(targetDir); module != "" {
+		if rest, ok := strings.CutPrefix(pkg, module+"/"); ok {
+			suffixes = []string{rest}
+		} else if pkg == module {
+			return []string{targetDir}
+		}
+	}
+	if !strings.Contains(pkg, "/") && strings.Contains(pkg, ".") {
+		suffixes = append(suffixes, strings.ReplaceAll(pkg, ".", "/"))
+	}
+
+	seen := make(map[string]bool)
+	var dirs []string
+	for path := range graph.Files {
+		dir := filepath.Dir(path)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		rel, err := filepath.Rel(targetDir, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, suffix := range suffixes {
+			if rel == suffix || strings.HasSuffix(rel, "/"+suffix) {
+				dirs = append(dirs, dir)
+				break
+			}
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchSymbolsScope(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                          "module example.com/shop\n\ngo 1.22\n",
+		"packages/ui/package.json":        `{"name": "@shop/ui"}`,
+		"packages/ui/src/config.ts":       "export function loadConfig() { return {}; }\n",
+		"packages/api/package.json":       `{"name": "@shop/api"}`,
+		"packages/api/src/config.ts":      "export function loadConfig() { return {}; }\n",
+		"services/billing/config.go":      "package billing\n\nfunc LoadConfig() {}\n",
+		"services/billing/auth/config.go": "package auth\n\nfunc LoadConfig() {}\n",
+		"tools/shop/auth/config.py":       "def load_config():\n    pass\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	search := func(args SearchSymbolsArgs) string {
+		t.Helper()
+		args.Query = "config"
+		response, _, err := server.searchSymbols(ctx, nil, args)
+		require.NoError(t, err)
+		return response.Content[0].(*mcp.TextContent).Text
+	}
+
+	all := search(SearchSymbolsArgs{})
+	assert.Contains(t, all, filepath.Join("packages", "ui", "src", "config.ts"))
+	assert.Contains(t, all, filepath.Join("packages", "api", "src", "config.ts"))
+
+	tests := []struct {
+		name     string
+		args     SearchSymbolsArgs
+		included []string
+		excluded []string
+	}{
+		{
+			name:     "path prefix",
+			args:     SearchSymbolsArgs{PathPrefix: "packages/ui"},
+			included: []string{"**Scope:** path packages/ui", filepath.Join("packages", "ui", "src", "config.ts")},
+			excluded: []string{filepath.Join("packages", "api")},
+		},
+		{
+			name:     "workspace package",
+			args:     SearchSymbolsArgs{Package: "@shop/api"},
+			included: []string{filepath.Join("packages", "api", "src", "config.ts")},
+			excluded: []string{filepath.Join("packages", "ui"), "config.go"},
+		},
+		{
+			name:     "go import path",
+			args:     SearchSymbolsArgs{Package: "example.com/shop/services/billing"},
+			included: []string{filepath.Join("services", "billing", "config.go"), filepath.Join("billing", "auth", "config.go")},
+			excluded: []string{"config.ts", "config.py"},
+		},
+		{
+			name:     "package name matches every directory with that path",
+			args:     SearchSymbolsArgs{Package: "auth"},
+			included: []string{filepath.Join("billing", "auth", "config.go"), filepath.Join("shop", "auth", "config.py")},
+			excluded: []string{filepath.Join("services", "billing", "config.go"), "config.ts"},
+		},
+		{
+			name:     "dotted python package",
+			args:     SearchSymbolsArgs{Package: "shop.auth"},
+			included: []string{filepath.Join("shop", "auth", "config.py")},
+			excluded: []string{"config.go"},
+		},
+		{
+			name:     "package narrowed by path prefix",
+			args:     SearchSymbolsArgs{Package: "auth", PathPrefix: "services"},
+			included: []string{filepath.Join("billing", "auth", "config.go")},
+			excluded: []string{"config.py"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := search(tt.args)
+			for _, want := range tt.included {
+				assert.Contains(t, text, want)
+			}
+			for _, unwanted := range tt.excluded {
+				assert.NotContains(t, text, unwanted)
+			}
+		})
+	}
+
+	assert.Contains(t, search(SearchSymbolsArgs{PathPrefix: "docs"}), "No symbols found matching 'config' in path docs")
+
+	_, _, err = server.searchSymbols(ctx, nil, SearchSymbolsArgs{Query: "config", Package: "@shop/missing"})
+	assert.ErrorContains(t, err, `no package "@shop/missing"`)
+}
@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"strconv"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// valueDefinitions renders where a symbol defines a value found by the value
+// search mode: `= "PAYMENT_FAILED"` for a constant, or `FAILED = "PAYMENT_FAILED"`
+// and plain member names for enums
+func valueDefinitions(symbol *types.Symbol, value string) []string {
+	var definitions []string
+	if symbol.MetadataString(types.ValueMetadataKey) == value {
+		definitions = append(definitions, "= "+formatValue(value))
+	}
+	values := symbol.MetadataStringMap(types.EnumValuesMetadataKey)
+	for _, member := range symbol.MetadataStrings(types.EnumMembersMetadataKey) {
+		memberValue, hasValue := values[member]
+		switch {
+		case hasValue && (member == value || memberValue == value):
+			definitions = append(definitions, member+" = "+formatValue(memberValue))
+		case member == value:
+			definitions = append(definitions, member)
+		}
+	}
+	return definitions
+}
+
+// formatValue quotes values that are not numbers or booleans
+func formatValue(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil || value == "true" || value == "false" {
+		return value
+	}
+	return strconv.Quote(value)
+}
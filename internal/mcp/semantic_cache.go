@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// semanticCache keeps semantic neighborhood results per directory and profile.
+// Git history only changes with a new commit, so an entry stays valid until
+// HEAD moves or the config is reloaded.
+type semanticCache struct {
+	building sync.Mutex // Held while building, so concurrent calls share one git analysis
+	mu       sync.Mutex
+	entries  map[string]*semanticEntry
+}
+
+type semanticEntry struct {
+	head   string // Commit the result was built at ("" outside a git repository)
+	result *analyzer.SemanticAnalysisResult
+}
+
+func newSemanticCache() *semanticCache {
+	return &semanticCache{entries: make(map[string]*semanticEntry)}
+}
+
+func (c *semanticCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*semanticEntry)
+}
+
+func (c *semanticCache) get(key, head string) *analyzer.SemanticAnalysisResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry := c.entries[key]; entry != nil && entry.head == head {
+		return entry.result
+	}
+	return nil
+}
+
+func (c *semanticCache) put(key, head string, result *analyzer.SemanticAnalysisResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = &semanticEntry{head: head, result: result}
+}
+
+func (c *semanticCache) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// semanticNeighborhoods returns the semantic analysis of targetDir, building it
+// from git history against graph on the first request
+func (s *CodeContextMCPServer) semanticNeighborhoods(targetDir string, graph *types.CodeGraph, profile analyzer.Profile) (*analyzer.SemanticAnalysisResult, error) {
+	c := s.semantic
+	key := targetDir + "#" + string(profile)
+	head := gitHead(targetDir)
+
+	c.building.Lock()
+	defer c.building.Unlock()
+	if result := c.get(key, head); result != nil {
+		log.Printf("[MCP] Using cached semantic neighborhoods for %s (%s profile)", targetDir, profile)
+		return result, nil
+	}
+
+	start := time.Now()
+	log.Printf("[MCP] Analyzing git history of %s (%s profile)", targetDir, profile)
+	s.configMu.RLock()
+	result, err := s.analyzer.AnalyzeSemanticNeighborhoods(targetDir, graph, profile)
+	s.configMu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, head, result)
+	log.Printf("[MCP] Semantic analysis completed in %v", time.Since(start))
+	return result, nil
+}
+
+// gitHead returns the checked-out commit of dir, or "" when it has none
+func gitHead(dir string) string {
+	gitAnalyzer, err := git.NewGitAnalyzer(dir)
+	if err != nil {
+		return ""
+	}
+	head, err := gitAnalyzer.GetHeadCommit()
+	if err != nil {
+		return ""
+	}
+	return head
+}
@@ -0,0 +1,57 @@
+package mcp
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazySemanticNeighborhoods(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := createTestDirectory(t)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tmpDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, _, err = server.getCodebaseOverview(ctx, nil, GetCodebaseOverviewArgs{})
+	require.NoError(t, err)
+	_, analyzed := server.graph.Metadata.Configuration["semantic_neighborhoods"]
+	assert.False(t, analyzed, "other tools do not analyze git history")
+	assert.Equal(t, 0, server.semantic.count())
+
+	first, _, err := server.getSemanticNeighborhoods(ctx, nil, GetSemanticNeighborhoodsArgs{})
+	require.NoError(t, err)
+	assert.Contains(t, first.Content[0].(*mcp.TextContent).Text, "# Semantic Code Neighborhoods Analysis")
+	assert.Equal(t, 1, server.semantic.count())
+
+	cached, err := server.semanticNeighborhoods(tmpDir, server.graph, "balanced")
+	require.NoError(t, err)
+	again, err := server.semanticNeighborhoods(tmpDir, server.graph, "balanced")
+	require.NoError(t, err)
+	assert.Same(t, cached, again, "results are cached until HEAD moves")
+
+	_, err = server.semanticNeighborhoods(tmpDir, server.graph, "fast")
+	assert.Error(t, err)
+
+	server.semantic.clear()
+	assert.Equal(t, 0, server.semantic.count())
+}
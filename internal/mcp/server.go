@@ -13,40 +13,109 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/annotations"
 	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/internal/lsp"
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/internal/query"
+	"github.com/nuthan-ms/codecontext/internal/rank"
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/internal/rules"
+	"github.com/nuthan-ms/codecontext/internal/summarize"
 	"github.com/nuthan-ms/codecontext/internal/watcher"
+	"github.com/nuthan-ms/codecontext/pkg/plugin"
 	"github.com/nuthan-ms/codecontext/pkg/types"
 )
 
 // MCPConfig holds configuration for the MCP server
 type MCPConfig struct {
-	Name        string `json:"name"`
-	Version     string `json:"version"`
-	TargetDir   string `json:"target_dir"`
-	EnableWatch bool   `json:"enable_watch"`
-	DebounceMs  int    `json:"debounce_ms"`
+	Name         string                   `json:"name"`
+	Version      string                   `json:"version"`
+	TargetDir    string                   `json:"target_dir"`
+	EnableWatch  bool                     `json:"enable_watch"`
+	DebounceMs   int                      `json:"debounce_ms"`
+	IncludeDirs  []string                 `json:"include_dirs,omitempty"`  // C/C++ include search directories
+	FlagHelpers  []string                 `json:"flag_helpers,omitempty"`  // Project-specific feature flag helper calls
+	Plugins      []plugin.ExternalConfig  `json:"plugins,omitempty"`       // External plugin processes
+	WASMGrammars parser.WASMGrammarConfig `json:"wasm_grammars,omitempty"` // Tree-sitter grammars loaded from WASM
+	Reports      []query.ReportConfig     `json:"reports,omitempty"`       // Saved queries exposed as report_<name> tools
+	Rules        []rules.Config           `json:"rules,omitempty"`         // Graph assertions checked by check_rules
+	Naming       analyzer.NamingProfile   `json:"naming,omitempty"`        // Naming conventions enforced by get_naming_conventions
+
+	MaxConcurrentAnalyses int `json:"max_concurrent_analyses,omitempty"` // Analyses run at once (default 1)
+	MaxQueuedAnalyses     int `json:"max_queued_analyses,omitempty"`     // Analyses waiting before calls get a busy error (default 8)
+	RateLimitPerMinute    int `json:"rate_limit_per_minute,omitempty"`   // Tool calls per session per minute (0 = unlimited)
+
+	AllowedRoots []string `json:"allowed_roots,omitempty"` // Directories target_dir may point into (empty = any)
+	ReadOnly     bool     `json:"read_only,omitempty"`     // Reject tool calls that write to the project
+
+	Redaction redact.Config    `json:"redaction,omitempty"` // Paths and patterns masked in every tool result
+	Summaries summarize.Config `json:"summaries,omitempty"` // LLM writing file summaries (default none)
+
+	SnapshotPath string `json:"snapshot_path,omitempty"` // Graph saved on shutdown and loaded on start (empty = disabled)
+
+	ExcludePatterns    []string            `json:"exclude_patterns,omitempty"`     // Extra exclude patterns, "!" re-includes
+	UseDefaultExcludes *bool               `json:"use_default_excludes,omitempty"` // Merge the built-in excludes (default true)
+	Semantic           *git.SemanticConfig `json:"semantic,omitempty"`             // Semantic neighborhood thresholds (nil = profile defaults)
+	Profile            string              `json:"profile,omitempty"`              // Default analysis profile: fast, balanced or deep
+
+	ExternalWorkspaces []string `json:"external_workspace_packages,omitempty"` // Monorepo package names kept as external imports
+	CoverageReports    []string `json:"coverage_reports,omitempty"`            // Coverage reports attached to files and symbols
+	MutationReports    []string `json:"mutation_reports,omitempty"`            // Mutation testing reports attached to files and symbols
+	Indexes            []string `json:"indexes,omitempty"`                     // SCIP and LSIF indexes merged into the relationships
+
+	LanguageOverrides []parser.LanguageOverride `json:"language_overrides,omitempty"` // Languages set by path glob
+	LanguageServers   lsp.Config                `json:"language_servers,omitempty"`   // Queried by get_symbol_info in deep mode
 }
 
 // CodeContextMCPServer provides codecontext functionality via MCP
 type CodeContextMCPServer struct {
-	server   *mcp.Server
-	config   *MCPConfig
-	watcher  *watcher.FileWatcher
-	graph    *types.CodeGraph
-	analyzer *analyzer.GraphBuilder
-	stopMutex sync.RWMutex // Protect against concurrent stop operations
-	stopped   bool         // Track server state
+	server       *mcp.Server
+	config       *MCPConfig
+	watcher      *watcher.FileWatcher
+	graph        *types.CodeGraph
+	graphDir     string                 // Directory the current graph was analyzed from
+	graphProfile analyzer.Profile       // Profile the current graph was analyzed with
+	analyzer     *analyzer.GraphBuilder // Configured builder for semantic analysis; each analysis builds its own
+	plugins      []plugin.Analyzer      // Compiled-in and external plugins
+	reports      []*query.Report        // Saved queries from the config
+	rules        []*rules.Rule          // Graph assertions from the config
+	memory       *sessionMemory         // Files and symbols each session has retrieved
+	limiter      *callLimiter           // Analysis concurrency and per-session rate limits
+	sandbox      *sandbox               // Directories tool calls may analyze
+	calls        *callTracker           // Tool calls in flight, drained on shutdown
+	semantic     *semanticCache         // Semantic neighborhoods built on demand
+	results      *resultCache           // Rendered responses, invalidated by the watcher
+	languages    *lsp.Manager           // Language servers queried in deep mode, replaced on reload
+	configMu     sync.RWMutex           // Held for writing while a config reload is applied
+	graphMu      sync.Mutex             // Guards the graph and the fields describing it
+	stopMutex    sync.RWMutex           // Protect against concurrent stop operations
+	stopped      bool                   // Track server state
+
+	shutdownOnce sync.Once
+	shutdownErr  error
+
+	// Reported by get_server_status
+	startedAt    time.Time
+	analyzedAt   time.Time     // When the current graph was analyzed or saved
+	analysisTime time.Duration // How long the last analysis took
+	graphSource  string        // "analysis" or "snapshot"
+	useSnapshot  bool          // Answer calls from the loaded snapshot until files change
+	watchDir     string        // Directory the watcher observes
 }
 
 // Tool argument structs
 type GetCodebaseOverviewArgs struct {
 	IncludeStats bool   `json:"include_stats"`
 	TargetDir    string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile      string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+	TopN         int    `json:"top_n,omitempty"`      // Optional: length of ranked lists (default 10)
 }
 
 type GetFileAnalysisArgs struct {
 	FilePath  string `json:"file_path"`
 	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
 }
 
 type GetSymbolInfoArgs struct {
@@ -54,21 +123,30 @@ type GetSymbolInfoArgs struct {
 	FilePath      string `json:"file_path,omitempty"`
 	FrameworkType string `json:"framework_type,omitempty"`
 	TargetDir     string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile       string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
 }
 
 type SearchSymbolsArgs struct {
 	Query         string `json:"query"`
 	FileType      string `json:"file_type,omitempty"`
-	SymbolType    string `json:"symbol_type,omitempty"`
+	SymbolType    string `json:"symbol_type,omitempty"` // Optional: normalized kind ("function", "type-alias") or language-specific type ("hook", "trait")
 	FrameworkType string `json:"framework_type,omitempty"`
+	PathPrefix    string `json:"path_prefix,omitempty"` // Optional: only search files under this directory
+	Package       string `json:"package,omitempty"`     // Optional: only search this workspace, Go or Python package
+	PublicOnly    bool   `json:"public_only,omitempty"` // Optional: skip private, protected and package-private symbols
+	Match         string `json:"match,omitempty"`       // Optional: "name" (default, substring) or "value" (exact constant or enum member value)
 	Limit         int    `json:"limit,omitempty"`
 	TargetDir     string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile       string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
 }
 
 type GetDependenciesArgs struct {
 	FilePath  string `json:"file_path,omitempty"`
 	Direction string `json:"direction,omitempty"`
 	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+	TopN      int    `json:"top_n,omitempty"`      // Optional: length of the most imported files list (default 5)
+	Kind      string `json:"kind,omitempty"`       // Optional: only imports of this kind (named, default, namespace, type_only, side_effect, dynamic, require, reexport, module, header_unit) or "lazy"
 }
 
 type WatchChangesArgs struct {
@@ -82,6 +160,7 @@ type GetSemanticNeighborhoodsArgs struct {
 	IncludeQuality bool `json:"include_quality,omitempty"`
 	MaxResults   int    `json:"max_results,omitempty"`
 	TargetDir    string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile      string `json:"profile,omitempty"`    // Optional: analysis profile (balanced or deep)
 }
 
 type GetFrameworkAnalysisArgs struct {
@@ -104,10 +183,27 @@ func NewCodeContextMCPServer(config *MCPConfig) (*CodeContextMCPServer, error) {
 	log.Printf("[MCP] Created MCP server with name=%s, version=%s", config.Name, config.Version)
 	
 	s := &CodeContextMCPServer{
-		server:   server,
-		config:   config,
-		analyzer: analyzer.NewGraphBuilder(),
-	}
+		server:    server,
+		config:    config,
+		startedAt: time.Now(),
+	}
+	s.plugins = loadPlugins(config.Plugins)
+	s.reports = loadReports(config.Reports)
+	s.rules = loadRules(config.Rules)
+	s.memory = newSessionMemory()
+	s.calls = newCallTracker()
+	s.semantic = newSemanticCache()
+	s.results = newResultCache()
+	server.AddReceivingMiddleware(s.trackCallsMiddleware)
+	s.sandbox = newSandbox(config.AllowedRoots, config.TargetDir)
+	s.limiter = newCallLimiter(config.MaxConcurrentAnalyses, config.MaxQueuedAnalyses, config.RateLimitPerMinute)
+	if config.RateLimitPerMinute > 0 {
+		server.AddReceivingMiddleware(s.rateLimitMiddleware)
+	}
+	if err := s.applyAnalysisSettings(config); err != nil {
+		return nil, err
+	}
+	server.AddReceivingMiddleware(s.redactionMiddleware)
 	log.Printf("[MCP] Created CodeContextMCPServer instance")
 
 	// Register tools
@@ -138,21 +234,21 @@ func (s *CodeContextMCPServer) registerTools() {
 	log.Printf("[MCP] Registering tool: get_symbol_info")
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "get_symbol_info",
-		Description: "Get detailed information about a specific symbol, including framework-specific details (React components, Vue stores, Angular services, etc.). Optional target_dir parameter allows searching symbols in different projects.",
+		Description: "Get detailed information about a specific symbol, including framework-specific details (React components, Vue stores, Angular services, etc.). With the deep profile, also shows the type and definition reported by the project's language server (gopls, typescript-language-server, pyright) when one is installed. Optional target_dir parameter allows searching symbols in different projects.",
 	}, s.getSymbolInfo)
 
 	// Tool 4: Search symbols
 	log.Printf("[MCP] Registering tool: search_symbols")
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "search_symbols",
-		Description: "Search for symbols across a codebase with framework-aware filtering (components, hooks, services, stores, etc.). Optional target_dir parameter allows searching in different projects.",
+		Description: "Search for symbols across a codebase with framework-aware filtering (components, hooks, services, stores, etc.). path_prefix or package limits the search to one directory or package, such as a workspace package in a monorepo; public_only keeps exported symbols only. Set match to value to find the constants and enum members defined with an exact value such as 'PAYMENT_FAILED'. Optional target_dir parameter allows searching in different projects.",
 	}, s.searchSymbols)
 
 	// Tool 5: Get dependencies
 	log.Printf("[MCP] Registering tool: get_dependencies")
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "get_dependencies",
-		Description: "Analyze import dependencies and relationships. Optional target_dir parameter allows analyzing dependencies in different projects.",
+		Description: "Analyze import dependencies and relationships. Optional target_dir parameter allows analyzing dependencies in different projects; top_n sets how many of the most imported files are listed (default 5).",
 	}, s.getDependencies)
 
 	// Tool 6: Watch changes (real-time)
@@ -173,10 +269,279 @@ func (s *CodeContextMCPServer) registerTools() {
 	log.Printf("[MCP] Registering tool: get_framework_analysis")
 	mcp.AddTool(s.server, &mcp.Tool{
 		Name:        "get_framework_analysis",
-		Description: "Get comprehensive framework-specific analysis including component relationships, hook usage patterns, and framework-specific metrics. Optional target_dir parameter allows analyzing different projects.",
+		Description: "Get comprehensive framework-specific analysis including component relationships, hook usage patterns, and framework-specific metrics. Projects using Tailwind CSS also get their most used design tokens and the theme tokens and custom utilities no class uses (framework \"tailwind\" for that part alone). Flutter projects get their state architecture: each Bloc, Cubit, Riverpod provider and ChangeNotifier with the repositories and providers it depends on and the widgets that watch, read or provide it, and the pubspec dependency constraints, flavors and declared assets with the code loading them. Optional target_dir parameter allows analyzing different projects.",
 	}, s.getFrameworkAnalysis)
+
+	// Tool 9: Get type hierarchy
+	log.Printf("[MCP] Registering tool: get_type_hierarchy")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_type_hierarchy",
+		Description: "Get the supertypes and subtypes of a class, interface or mixin (extends, implements, Dart with), or the specializations and explicit instantiations of a C++ template. Optional direction (supertypes/subtypes/both), max_depth and target_dir parameters.",
+	}, s.getTypeHierarchy)
+
+	// Tool 10: Get build targets
+	log.Printf("[MCP] Registering tool: get_build_targets")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_build_targets",
+		Description: "List CMake, Bazel and Cargo build targets and the source files they compile. Pass file_path to see which targets build a file, or changed_files to see every target a change affects (including dependents). Optional system and target_dir parameters.",
+	}, s.getBuildTargets)
+
+	// Tool 11: Get tasks
+	log.Printf("[MCP] Registering tool: get_tasks")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_tasks",
+		Description: "List the project's task entry points (Makefile targets, package.json scripts, justfile recipes) with the commands they run, grouped into build/test/lint/format/run categories. Optional category and target_dir parameters.",
+	}, s.getTasks)
+
+	// Tool 12: Get Kubernetes topology
+	log.Printf("[MCP] Registering tool: get_k8s_topology")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_k8s_topology",
+		Description: "Show the Kubernetes deployment architecture declared in manifests and Helm chart templates: Ingress → Service → workload request flow, plus ConfigMap, Secret, volume and ServiceAccount references between resources. Optional namespace, kind and target_dir parameters.",
+	}, s.getK8sTopology)
+
+	// Tool 13: Get event flows
+	log.Printf("[MCP] Registering tool: get_event_flows")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_event_flows",
+		Description: "Trace event-driven architecture: publish/subscribe calls for Kafka topics, SNS/SQS, NATS subjects, EventEmitter events and generic pub/sub clients, grouped by topic with producers linked to consumers. Flags topics with no producer or no consumer in the codebase. Optional topic, broker and target_dir parameters.",
+	}, s.getEventFlows)
+
+	// Tool 14: Get feature flags
+	log.Printf("[MCP] Registering tool: get_feature_flags")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_feature_flags",
+		Description: "List feature flags with every usage site, detected from LaunchDarkly, Unleash, Flagsmith, Split, GrowthBook and OpenFeature SDK calls plus homegrown helpers (feature_flag_helpers config or the helpers parameter, e.g. \"isFeatureEnabled\" or \"*.FlagOn\"). Highlights single-use flags as cleanup candidates. Optional flag, helpers and target_dir parameters.",
+	}, s.getFeatureFlags)
+
+	// Tool 15: Query graph
+	log.Printf("[MCP] Registering tool: query_graph")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "query_graph",
+		Description: "Run an ad-hoc Cypher-like query over the code graph: MATCH <pattern> [WHERE <conditions>] [RETURN <names>] [LIMIT n]. Patterns alternate node kinds (file, symbol, function, class, ..., *) and edge types (imports, calls, extends, implements, contains, calls-service, ..., *), optionally named: \"MATCH a:file->imports->b:file WHERE b.path CONTAINS 'auth' RETURN a\". Conditions compare fields (path, name, language, lines, type, line, ...) with =, !=, <, >, CONTAINS, STARTS WITH, ENDS WITH or MATCHES and combine with AND, OR, NOT. Optional target_dir parameter.",
+	}, s.queryGraph)
+
+	// Tool 16: Check rules
+	log.Printf("[MCP] Registering tool: check_rules")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "check_rules",
+		Description: "Check the graph rules from the config (naming conventions, forbidden imports, max fan-out, required tests, query assertions) and list violations by rule. Optional rule parameter to check a single rule id, format parameter (markdown or sarif) and target_dir parameter.",
+	}, s.checkRules)
+
+	// Tool 17: Annotate
+	log.Printf("[MCP] Registering tool: annotate")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "annotate",
+		Description: "Attach a note to a file or symbol so it is shown by get_file_analysis and get_symbol_info in later sessions. Target is a project-relative file path, optionally with #SymbolName. Action is add (default, requires text; optional author and tags), remove (requires id) or list (optionally filtered by target). Notes are stored in .codecontext/annotations.json. Optional target_dir parameter.",
+	}, s.annotate)
+
+	// Tool 18: Unexplored related code
+	log.Printf("[MCP] Registering tool: get_unexplored_related")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_unexplored_related",
+		Description: "Suggest files connected to the code this session has already retrieved (through get_file_analysis and get_symbol_info) that it has not looked at yet, ranked by import, call and inheritance links, with the relevant symbols. Optional file_path parameter to start from a single file, max_results (default 10) and target_dir.",
+	}, s.getUnexploredRelated)
+
+	// Tool 19: Server status
+	log.Printf("[MCP] Registering tool: get_server_status")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_server_status",
+		Description: "Report whether the server is warm: version, uptime, the loaded graph (directory, files, symbols, last analysis time), running and queued analyses, watcher state, session memory and the saved graph snapshot. Does not trigger an analysis. Optional format parameter (markdown or json).",
+	}, s.getServerStatus)
+	
+	// Tool 20: Find string origin
+	log.Printf("[MCP] Registering tool: find_string_origin")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "find_string_origin",
+		Description: "Find where a string comes from: the log messages, error strings, route paths and other string literals matching a piece of text, with file, line and enclosing function. A runtime message like \"user 42 not found\" also finds format strings and templates such as \"user %d not found\". Optional kind (log, error, route or string), limit (default 20) and target_dir parameters.",
+	}, s.findStringOrigin)
+	
+	// Tool 21: Get error sources
+	log.Printf("[MCP] Registering tool: get_error_sources")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_error_sources",
+		Description: "Trace Go errors to where they originate. With function, lists the sentinel errors, error types and new errors the function can return, with the call chain each one travels through, including errors wrapped with %w. With error (a sentinel like ErrNotFound or an error type), lists the call chains that return it. Without either, summarizes the sentinels and error types in the codebase. Optional limit (default 20) and target_dir parameters.",
+	}, s.getErrorSources)
+	
+	// Tool 22: Get concurrency map
+	log.Printf("[MCP] Registering tool: get_concurrency_map")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_concurrency_map",
+		Description: "Index concurrency constructs per file: goroutines, threads, isolates and tasks (spawn), async/await and coroutines (async), channels and queues (channel), mutexes and synchronized blocks (lock), atomics (atomic) and wait groups, latches and futures (sync). Files mixing more kinds come first. Optional kind filter, file_path to list one file's constructs line by line, limit (default 20) and target_dir parameters.",
+	}, s.getConcurrencyMap)
+	
+	// Tool 23: Get coupling metrics
+	log.Printf("[MCP] Registering tool: get_coupling_metrics")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_coupling_metrics",
+		Description: "Package coupling metrics: fan-in (afferent coupling, packages depending on a package), fan-out (efferent coupling, packages it depends on) and instability (fan-out / (fan-in + fan-out)) for every directory of source files. Optional package filter (which also lists dependents and dependencies), sort (fan_in, fan_out, instability or name), limit (default 30) and target_dir parameters.",
+	}, s.getCouplingMetrics)
+	
+	// Tool 24: Get trends
+	log.Printf("[MCP] Registering tool: get_trends")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_trends",
+		Description: "How the codebase evolved: metrics snapshots recorded by each codecontext generate run (files, symbols, average and maximum function complexity, package dependencies, average instability, most imported files) followed by the current analysis, with the change since the oldest snapshot shown. Optional last (number of snapshots, default 10), tags (only snapshots of tagged commits), record (also store the current analysis as a snapshot) and target_dir parameters.",
+	}, s.getTrends)
+	
+	// Tool 25: Get release notes
+	log.Printf("[MCP] Registering tool: get_release_notes")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_release_notes",
+		Description: "Draft release notes between two git refs: analyzes the project at both revisions and lists public symbols added, changed (signature or kind), moved between packages and removed, grouped by package, plus new and removed HTTP/gRPC endpoints and renamed or moved files. Requires from (tag, branch or commit); optional to (default: the working tree) and target_dir parameters.",
+	}, s.getReleaseNotes)
+	
+	// Tool 26: Check API compatibility
+	log.Printf("[MCP] Registering tool: check_api_compatibility")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "check_api_compatibility",
+		Description: "Check that the public API stays compatible between two git refs, for CI gates: reports removed or moved exports, kind changes, required parameters added, parameters removed, narrowed or changed parameter types, changed results and removed endpoints as errors, risky changes (renamed Python parameters, Go signature changes that break function values) as warnings and compatible changes (optional parameters, widened types) as notes, with a verdict. Requires from; optional to (default: the working tree), fail_on (error, warning or note; default error), format (markdown or sarif) and target_dir parameters.",
+	}, s.checkAPICompatibility)
+	
+	// Tool 27: Suggest version
+	log.Printf("[MCP] Registering tool: suggest_version")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "suggest_version",
+		Description: "Suggest the next semantic version (major, minor or patch) of each module since a release: modules are the project root and every directory with a go.mod, package.json, pubspec.yaml, Cargo.toml, pyproject.toml or setup.py. Combines the public API diff (incompatible changes need a major release, additions a minor one) with conventional commit messages (feat!/BREAKING CHANGE, feat, fix) and lists the reasons per module. Requires from (tag of the last release); optional to (default: the working tree) and target_dir parameters.",
+	}, s.suggestVersion)
+	
+	// Tool 28: Get bus factor
+	log.Printf("[MCP] Registering tool: get_bus_factor")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_bus_factor",
+		Description: "Contributor diversity per module (directory) from recent git history: commits, source file changes, number of authors, bus factor (the fewest people who made more than half of the changes, counting Co-authored-by trailers) and main author, riskiest first. Flags modules where a single author wrote more than 90% of the changes. Optional days (default 90), min_changes (default 5), path (directory prefix), limit (default 30) and target_dir parameters.",
+	}, s.getBusFactor)
+	
+	// Tool 29: Get stale code
+	log.Printf("[MCP] Registering tool: get_stale_code")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_stale_code",
+		Description: "Candidate-for-removal source files: untouched for months (last change from git history, file times otherwise) and referenced by few other files or importing packages, optionally filtered by low line coverage from Go coverprofile, lcov or Cobertura reports. Entry points, tests and generated files are left out. Optional months (default 6), max_references (default 0), max_coverage (percent, default 20), coverage (report paths), path, limit (default 50) and target_dir parameters.",
+	}, s.getStaleCode)
+	
+	// Tool 30: Get benchmarks
+	log.Printf("[MCP] Registering tool: get_benchmarks")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_benchmarks",
+		Description: "Benchmark functions (Go Benchmark*, Rust criterion, #[bench] and divan, JMH @Benchmark) with the functions each one calls and the command running it, so performance-sensitive changes can be measured. Optional file_path (benchmarks exercising files whose path contains this text), symbol (benchmarks exercising this function), framework (go, criterion, libtest, divan or jmh), limit (default 50) and target_dir parameters.",
+	}, s.getBenchmarks)
+	
+	// Tool 31: Get annotations
+	log.Printf("[MCP] Registering tool: get_annotations")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_annotations",
+		Description: "Index of the decorators, annotations and attributes used in the repo (TypeScript/Python decorators, Java/Dart/Swift annotations, Rust attributes) with usage counts, the cross-cutting concern each suggests (auth, caching, transactions, resilience, observability, validation, scheduling) and example sites. Optional name (substring), language, concern, file_path (uses in files whose path contains this text), examples (sites per decorator, default 3), limit (default 50) and target_dir parameters.",
+	}, s.getAnnotations)
+	
+	// Tool 32: Get middleware chains
+	log.Printf("[MCP] Registering tool: get_middleware_chains")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_middleware_chains",
+		Description: "Ordered middleware chain of each route of Express, Koa, Gin and FastAPI apps: global middleware, then that of the routers and groups the route is mounted under, then the route's own, ending with the handler. Follows routers mounted from other files. Optional path (substring of the full route path), method, file_path, framework (express, koa, gin or fastapi), limit (default 50) and target_dir parameters.",
+	}, s.getMiddlewareChains)
+	
+	// Tool 33: Get frontend routes
+	log.Printf("[MCP] Registering tool: get_frontend_routes")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_frontend_routes",
+		Description: "Frontend routes (React Router elements, React Router/Vue Router/Angular route objects, Next.js pages and app directories) with the backend endpoints each page calls: follows the page component through the components, hooks and API modules it uses to fetch/axios calls matched to server routes and their handlers. For full-stack impact analysis, endpoint lists the pages calling a backend path and file_path the pages using a file. Optional path (substring of the route path), endpoint, file_path, router (react-router, route-config, next-pages or next-app), limit (default 50) and target_dir parameters.",
+	}, s.getFrontendRoutes)
+	
+	// Tool 34: Get asset usages
+	log.Printf("[MCP] Registering tool: get_asset_usages")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_asset_usages",
+		Description: "Static assets (images, stylesheets, JSON/YAML/CSV fixtures, fonts, media, PDFs, HTML templates) and the code referring to them through string literals, CSS url() and @import, and //go:embed, so renaming or removing an asset lists what would break. Also lists references to asset files that do not exist. Optional asset (substring of the asset path; lists every reference), kind, unused (only unreferenced assets), limit (default 50) and target_dir parameters.",
+	}, s.getAssetUsages)
+	
+	// Tool 35: Get Storybook stories
+	log.Printf("[MCP] Registering tool: get_stories")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_stories",
+		Description: "Storybook *.stories.* files (Component Story Format and storiesOf) linked to the components they document, with each story's name, line and Storybook id, plus the components no story documents. get_symbol_info also points at the stories of a component. Optional component (substring of the component name), file_path, unstoried (only components without stories), limit (default 50) and target_dir parameters.",
+	}, s.getStories)
+	
+	// Tool 36: Get state flows
+	log.Printf("[MCP] Registering tool: get_state_flows")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_state_flows",
+		Description: "State management flows: Redux Toolkit slices, Pinia and Zustand stores, and Flutter Bloc and Cubit classes, with their actions or events, the components dispatching them and the components selecting or watching their state. Optional store (substring of the store name), library (redux, pinia, zustand or bloc), limit (default 20 per store) and target_dir parameters.",
+	}, s.getStateFlows)
+	
+	// Tool 37: Find implementations
+	log.Printf("[MCP] Registering tool: find_implementations")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "find_implementations",
+		Description: "List the types implementing an interface, or the interfaces a type implements. Go types are matched to the project's interfaces by method set, including the methods of embedded interfaces, and marked when only the pointer type implements the interface; other languages use their implements clauses. Optional file_path and target_dir parameters.",
+	}, s.findImplementations)
+	
+	// Tool 38: Regenerate summaries
+	log.Printf("[MCP] Registering tool: regenerate_summaries")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "regenerate_summaries",
+		Description: "Drop the cached LLM summaries of files and re-analyze so they are written again from the current content. Needs a provider under summaries in the config. Optional files parameter (project-relative paths, default all) and target_dir parameter.",
+	}, s.regenerateSummaries)
+	
+	// Tool 39: Check dependencies
+	log.Printf("[MCP] Registering tool: check_dependencies")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "check_dependencies",
+		Description: "Cross-reference the dependencies declared in package.json, go.mod and requirements*.txt files with the imports of the code each covers. Reports unused runtime dependencies nothing imports, and missing (phantom) packages the code imports without declaring them, including development dependencies imported by production code, and version skew: third-party libraries that workspace packages declare at different versions, with how many files import each version. Python distributions are matched to their import names (PyYAML to yaml); standard library modules, relative imports and path aliases resolving to project files are skipped. Optional ecosystem (npm, go or pypi), manifest (substring of the manifest path) and target_dir parameters.",
+	}, s.checkDependencies)
+	
+	// Tool 40: Reachability from entry points
+	log.Printf("[MCP] Registering tool: reachable_from")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "reachable_from",
+		Description: "Compute the files reachable from the given entry files or symbols through imports, symbol relationships and packages, with how each was first reached, and the source files those roots never reach. Without entries, every detected entry point (mains, CLIs, serverless handlers, server bootstrap) is a root, so the unreachable files are dead code candidates, including code only tests use. Optional entries (files relative to the target directory or symbol names), path (only list files under it), limit (files per section, default 50) and target_dir parameters.",
+	}, s.reachableFrom)
 	
-	log.Printf("[MCP] Successfully registered 8 tools")
+	// Tool 41: Shortest dependency path
+	log.Printf("[MCP] Registering tool: get_dependency_path")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_dependency_path",
+		Description: "Explain why one piece of code depends on another: the shortest chain of imports, calls and other relationships leading from the from file or symbol to the to file or symbol, with the import path or symbols behind each hop. When there is no path, says so and shows the path in the opposite direction if one exists. Required from and to parameters (files relative to the target directory or symbol names), optional target_dir parameter.",
+	}, s.getDependencyPath)
+	
+	// Tool 42: Graph diff
+	log.Printf("[MCP] Registering tool: compare_graphs")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "compare_graphs",
+		Description: "Compare the code graphs of two directories or git refs, such as main and a feature branch: the files, symbols and relationships (imports, calls, references and the rest) one has that the other does not. Returns a markdown summary or the full diff as JSON for review bots. Required from parameter and optional to parameter (directories relative to the target directory or git refs; to defaults to the working tree), optional format (markdown or json), limit (entries per markdown list, default 50) and target_dir parameters.",
+	}, s.compareGraphs)
+	
+	// Tool 43: What-if removal
+	log.Printf("[MCP] Registering tool: simulate_removal")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "simulate_removal",
+		Description: "Simulate deleting a file or package directory without touching the code: reports every import, call, reference and other dependency that would break, grouped by the package of the dependent file, with test files marked. Use it to plan deprecations and removals. Required path parameter (relative to the target directory), optional limit (dependencies listed per package, default 20) and target_dir parameters.",
+	}, s.simulateRemoval)
+	
+	// Tool 44: God-file and god-class detection
+	log.Printf("[MCP] Registering tool: suggest_refactorings")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "suggest_refactorings",
+		Description: "Find god-files and god-classes, files declaring many symbols and classes with many methods, with their fan-in, fan-out and change neighborhood membership, and suggest where to split them: groups of symbols that name each other, share attributes, are used by the same files and were last changed by the same commits. Optional path (file or directory to limit suggestions to), min_symbols (default 20), min_methods (default 15), limit (candidates listed, default 10) and target_dir parameters.",
+	}, s.suggestRefactorings)
+	
+	// Tool 45: Naming convention consistency
+	log.Printf("[MCP] Registering tool: get_naming_conventions")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "get_naming_conventions",
+		Description: "Report the naming conventions of the project, camelCase, PascalCase, snake_case, SCREAMING_SNAKE_CASE or kebab-case, per language and kind of name (function, method, class, type, variable, constant, component, file), how consistently they are followed, the modules following a local convention of their own, and the names breaking the convention of their module. Use it to name new code the way the surrounding code does. Conventions come from the majority of the code unless set under \"naming\" in .codecontext/config.yaml. Optional path (directory to limit modules to), language, conventions (overrides as \"language.kind=style\"), limit (deviations listed per module, default 20) and target_dir parameters.",
+	}, s.getNamingConventions)
+	
+	// Tool 46: Symbol popularity
+	log.Printf("[MCP] Registering tool: most_used_symbols")
+	mcp.AddTool(s.server, &mcp.Tool{
+		Name:        "most_used_symbols",
+		Description: "Rank the symbols the rest of the code uses most, autocomplete style: each symbol with its definition site, signature, the number of non-test files using it and its references there. Check it before writing a helper, so established utilities are reused rather than duplicated. Optional kind (e.g. function), package (directory, subdirectories included), prefix (case-insensitive start of the name), limit (default 20) and target_dir parameters.",
+	}, s.mostUsedSymbols)
+	
+	log.Printf("[MCP] Successfully registered 46 tools")
+
+	s.registerPluginTools()
+	s.registerReportTools()
 }
 
 // Tool implementations
@@ -186,27 +551,47 @@ func (s *CodeContextMCPServer) getCodebaseOverview(ctx context.Context, req *mcp
 	start := time.Now()
 	
 	// Resolve target directory
-	targetDir := s.resolveTargetDir(args.TargetDir)
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
 	
+	// Repeat calls return the rendered response until the watcher sees a change
+	cache := s.cachedCallFor("get_codebase_overview", args, targetDir)
+	if result, ok := cache.result(); ok {
+		log.Printf("[MCP] Tool completed: get_codebase_overview (cached, took %v)", time.Since(start))
+		return result, nil, nil
+	}
+
 	// Ensure we have fresh analysis
 	log.Printf("[MCP] Refreshing analysis for codebase overview...")
-	if err := s.refreshAnalysisWithTargetDir(targetDir); err != nil {
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
 		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
 		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
 	}
 
 	log.Printf("[MCP] Generating markdown content...")
-	generator := analyzer.NewMarkdownGenerator(s.graph)
-	content := generator.GenerateContextMap()
-	log.Printf("[MCP] Generated markdown content (%d chars)", len(content))
+	// Stdio tool results are single JSON-RPC messages, so the document is built
+	// in one buffer that the statistics are appended to rather than copied
+	var sb strings.Builder
+	generator := analyzer.NewMarkdownGenerator(graph)
+	generator.SetTopN(args.TopN)
+	_ = generator.WriteContextMap(&sb)
+	log.Printf("[MCP] Generated markdown content (%d chars)", sb.Len())
 
 	if args.IncludeStats {
 		log.Printf("[MCP] Including detailed statistics...")
-		stats := s.analyzer.GetFileStats()
+		stats := analyzer.FileStats(graph)
 		statsJson, _ := json.MarshalIndent(stats, "", "  ")
-		content += "\n\n## Detailed Statistics\n```json\n" + string(statsJson) + "\n```"
+		sb.WriteString("\n\n## Detailed Statistics\n```json\n")
+		sb.Write(statsJson)
+		sb.WriteString("\n```")
 		log.Printf("[MCP] Added statistics to content")
 	}
+	content := sb.String()
+
+	cache.store(content)
 
 	elapsed := time.Since(start)
 	log.Printf("[MCP] Tool completed: get_codebase_overview (took %v)", elapsed)
@@ -225,20 +610,24 @@ func (s *CodeContextMCPServer) getFileAnalysis(ctx context.Context, req *mcp.Cal
 	}
 
 	// Resolve target directory
-	targetDir := s.resolveTargetDir(args.TargetDir)
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Ensure we have fresh analysis
 	log.Printf("[MCP] Refreshing analysis for file: %s", args.FilePath)
-	if err := s.refreshAnalysisWithTargetDir(targetDir); err != nil {
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
 		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
 		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
 	}
 
 	// Find the file in our graph
 	log.Printf("[MCP] Looking up file in graph: %s", args.FilePath)
-	fileNode, exists := s.graph.Files[args.FilePath]
+	fileNode, exists := graph.Files[args.FilePath]
 	if !exists {
-		log.Printf("[MCP] ERROR: File not found in graph: %s (available files: %d)", args.FilePath, len(s.graph.Files))
+		log.Printf("[MCP] ERROR: File not found in graph: %s (available files: %d)", args.FilePath, len(graph.Files))
 		return nil, nil, fmt.Errorf("file not found: %s", args.FilePath)
 	}
 	log.Printf("[MCP] Found file in graph: %s (language: %s, lines: %d, symbols: %d)", args.FilePath, fileNode.Language, fileNode.Lines, len(fileNode.Symbols))
@@ -247,15 +636,61 @@ func (s *CodeContextMCPServer) getFileAnalysis(ctx context.Context, req *mcp.Cal
 	analysis := fmt.Sprintf("# File Analysis: %s\n\n", args.FilePath)
 	analysis += fmt.Sprintf("**Language:** %s\n", fileNode.Language)
 	analysis += fmt.Sprintf("**Lines:** %d\n", fileNode.Lines)
-	analysis += fmt.Sprintf("**Symbols:** %d\n\n", len(fileNode.Symbols))
+	analysis += fmt.Sprintf("**Symbols:** %d\n", len(fileNode.Symbols))
+	if fileNode.Summary != "" {
+		analysis += fmt.Sprintf("**Summary:** %s\n", fileNode.Summary)
+	}
+	if fileNode.Coverage != nil {
+		analysis += fmt.Sprintf("**Coverage:** %s\n", formatCoverage(fileNode.Coverage))
+	}
+	if fileNode.Mutants != nil {
+		analysis += fmt.Sprintf("**Mutation score:** %s\n", formatMutants(fileNode.Mutants))
+	}
+	if fileNode.PartOf != "" {
+		analysis += fmt.Sprintf("**Part of:** `%s`\n", dartPartURI(args.FilePath, fileNode.PartOf))
+	}
+	if len(fileNode.Parts) > 0 {
+		parts := make([]string, 0, len(fileNode.Parts))
+		for _, part := range fileNode.Parts {
+			parts = append(parts, dartPartURI(args.FilePath, part))
+		}
+		analysis += fmt.Sprintf("**Parts:** `%s`\n", strings.Join(parts, "`, `"))
+	}
+	if fileNode.IsGenerated {
+		analysis += "**Generated:** yes, do not edit by hand\n"
+	}
+	analysis += cppModuleLines(graph, fileNode)
+	analysis += headerPairLine(graph, args.FilePath, targetDir)
+	analysis += "\n"
 
 	// List symbols in this file
 	if len(fileNode.Symbols) > 0 {
 		analysis += "## Symbols\n\n"
 		for _, symbolId := range fileNode.Symbols {
-			if symbol, exists := s.graph.Symbols[symbolId]; exists {
-				analysis += fmt.Sprintf("- **%s** (%s) - Line %d\n", 
-					symbol.Name, symbol.Kind, symbol.Location.StartLine)
+			if symbol, exists := graph.Symbols[symbolId]; exists {
+				analysis += fmt.Sprintf("- **%s** (%s) - Line %d", 
+					symbol.Name, symbolKindLabel(symbol), symbol.Location.StartLine)
+				if symbol.Coverage != nil && symbol.Coverage.Lines > 0 {
+					analysis += fmt.Sprintf(" - %.0f%% covered", symbol.Coverage.LinePercent())
+				}
+				if symbol.Mutants != nil && symbol.Mutants.Survived+symbol.Mutants.NoCoverage > 0 {
+					analysis += fmt.Sprintf(" - %d surviving mutants", symbol.Mutants.Survived+symbol.Mutants.NoCoverage)
+				}
+				analysis += "\n"
+			}
+		}
+	}
+
+	// A library and its parts are one unit, so list what the parts declare too
+	for _, part := range fileNode.Parts {
+		partNode := graph.Files[part]
+		if partNode == nil || len(partNode.Symbols) == 0 {
+			continue
+		}
+		analysis += fmt.Sprintf("\n### Part `%s`\n\n", dartPartURI(args.FilePath, part))
+		for _, symbolId := range partNode.Symbols {
+			if symbol, exists := graph.Symbols[symbolId]; exists {
+				analysis += fmt.Sprintf("- **%s** (%s) - Line %d\n", symbol.Name, symbolKindLabel(symbol), symbol.Location.StartLine)
 			}
 		}
 	}
@@ -263,21 +698,66 @@ func (s *CodeContextMCPServer) getFileAnalysis(ctx context.Context, req *mcp.Cal
 	// List imports for this file
 	log.Printf("[MCP] Analyzing dependencies for file: %s", args.FilePath)
 	analysis += "\n## Dependencies\n\n"
-	importCount := 0
-	for _, edge := range s.graph.Edges {
-		if edge.Type == "imports" && edge.From == types.NodeId(args.FilePath) {
-			if importCount == 0 {
-				analysis += "### Imports:\n"
-			}
-			analysis += fmt.Sprintf("- %s\n", edge.To)
-			importCount++
-		}
+	imports := importLines(graph, args.FilePath, "", false)
+	importCount := len(imports)
+	if importCount > 0 {
+		analysis += "### Imports:\n" + strings.Join(imports, "")
 	}
 	if importCount == 0 {
 		analysis += "No imports found.\n"
 	}
 	log.Printf("[MCP] Found %d imports for file: %s", importCount, args.FilePath)
 
+	// List markdown documents that link to this file or mention its symbols
+	if docs := analyzer.DocumentationFor(graph, args.FilePath); len(docs) > 0 {
+		analysis += "\n## Related Documentation\n\n"
+		for _, doc := range docs {
+			docPath := doc.DocPath
+			if rel, err := filepath.Rel(targetDir, docPath); err == nil && !strings.HasPrefix(rel, "..") {
+				docPath = rel
+			}
+			switch {
+			case doc.Symbol != "":
+				analysis += fmt.Sprintf("- %s:%d — mentions `%s`\n", docPath, doc.Line, doc.Symbol)
+			case doc.Kind == "code_reference":
+				analysis += fmt.Sprintf("- %s:%d — mentions `%s`\n", docPath, doc.Line, doc.Text)
+			case doc.Text != "":
+				analysis += fmt.Sprintf("- %s:%d — links as \"%s\"\n", docPath, doc.Line, doc.Text)
+			default:
+				analysis += fmt.Sprintf("- %s:%d — links to this file\n", docPath, doc.Line)
+			}
+		}
+	}
+
+	// Show HTTP/gRPC calls that cross service boundaries
+	outgoing, incoming := analyzer.ServiceCallsFor(graph, args.FilePath)
+	if len(outgoing) > 0 || len(incoming) > 0 {
+		analysis += "\n## Service Calls\n\n"
+		relPath := func(path string) string {
+			if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+			return path
+		}
+		for _, call := range outgoing {
+			analysis += fmt.Sprintf("- → %s %s %s served by %s:%d (line %d)\n",
+				strings.ToUpper(call.Protocol), call.Method, call.Path, relPath(call.ServerFile), call.ServerLine, call.ClientLine)
+		}
+		for _, call := range incoming {
+			analysis += fmt.Sprintf("- ← %s %s %s called from %s:%d (line %d)\n",
+				strings.ToUpper(call.Protocol), call.Method, call.Path, relPath(call.ClientFile), call.ClientLine, call.ServerLine)
+		}
+	}
+
+	s.memory.record(sessionOf(req), []string{args.FilePath}, nil)
+
+	// Notes attached to the file and its symbols
+	if store, err := annotations.Load(targetDir); err != nil {
+		log.Printf("[MCP] WARNING: Failed to load annotations: %v", err)
+	} else if notes := store.ForFile(annotations.RelativePath(args.FilePath, targetDir)); len(notes) > 0 {
+		analysis += "\n## Notes\n\n" + annotations.Markdown(notes, true)
+	}
+
 	elapsed := time.Since(start)
 	log.Printf("[MCP] Tool completed: get_file_analysis (took %v)", elapsed)
 	return &mcp.CallToolResult{
@@ -295,29 +775,58 @@ func (s *CodeContextMCPServer) getSymbolInfo(ctx context.Context, req *mcp.CallT
 	}
 
 	// Resolve target directory
-	targetDir := s.resolveTargetDir(args.TargetDir)
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Ensure we have fresh analysis
 	log.Printf("[MCP] Refreshing analysis for symbol lookup: %s", args.SymbolName)
-	if err := s.refreshAnalysisWithTargetDir(targetDir); err != nil {
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
 		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
 		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
 	}
+	profile, _ := s.resolveProfile(args.Profile)
 
-	log.Printf("[MCP] Searching for symbol: %s in %d symbols", args.SymbolName, len(s.graph.Symbols))
+	log.Printf("[MCP] Searching for symbol: %s in %d symbols", args.SymbolName, len(graph.Symbols))
 	var foundSymbols []*types.Symbol
-	for _, symbol := range s.graph.Symbols {
+	for _, symbol := range graph.Symbols {
 		if symbol.Name == args.SymbolName {
 			foundSymbols = append(foundSymbols, symbol)
 		}
 	}
 
+	// A C/C++ declaration and its definition are shown as one entry
+	byDeclaration, byDefinition := definitionEdges(graph)
+	merged := foundSymbols[:0]
+	for _, symbol := range foundSymbols {
+		if edge := byDefinition[symbol.Id]; edge == nil || graph.Symbols[types.SymbolId(strings.TrimPrefix(string(edge.From), "symbol-"))] == nil {
+			merged = append(merged, symbol)
+		}
+	}
+	foundSymbols = merged
+
 	log.Printf("[MCP] Found %d symbols matching '%s'", len(foundSymbols), args.SymbolName)
 	if len(foundSymbols) == 0 {
 		log.Printf("[MCP] ERROR: Symbol not found: %s", args.SymbolName)
 		return nil, nil, fmt.Errorf("symbol '%s' not found", args.SymbolName)
 	}
 
+	store, err := annotations.Load(targetDir)
+	if err != nil {
+		log.Printf("[MCP] WARNING: Failed to load annotations: %v", err)
+		store = &annotations.Store{}
+	}
+
+	var exploredFiles []string
+	var exploredSymbols []types.SymbolId
+	for _, symbol := range foundSymbols {
+		exploredFiles = append(exploredFiles, getFilePathForSymbol(graph, symbol))
+		exploredSymbols = append(exploredSymbols, symbol.Id)
+	}
+	s.memory.record(sessionOf(req), exploredFiles, exploredSymbols)
+
 	result := fmt.Sprintf("# Symbol Information: %s\n\n", args.SymbolName)
 	
 	for i, symbol := range foundSymbols {
@@ -325,12 +834,17 @@ func (s *CodeContextMCPServer) getSymbolInfo(ctx context.Context, req *mcp.CallT
 			result += "\n---\n\n"
 		}
 		result += fmt.Sprintf("**Line:** %d\n", symbol.Location.StartLine)
-		result += fmt.Sprintf("**Type:** %s\n", symbol.Kind)
+		result += fmt.Sprintf("**Type:** %s\n", symbol.NormalizedKind())
 		
-		// Add framework-specific information
-		if symbol.Type != "" && string(symbol.Type) != symbol.Kind {
+		// Add framework-specific or language-specific information
+		if description := s.getFrameworkSpecificDescription(string(symbol.Type)); description != "" {
 			result += fmt.Sprintf("**Framework Type:** %s\n", symbol.Type)
-			result += s.getFrameworkSpecificDescription(string(symbol.Type))
+			result += description
+		} else if subtype := symbol.Subtype(); subtype != "" {
+			result += fmt.Sprintf("**Subtype:** %s\n", subtype)
+		}
+		if symbol.Visibility != "" {
+			result += fmt.Sprintf("**Visibility:** %s\n", symbol.Visibility)
 		}
 		
 		if symbol.Signature != "" {
@@ -339,11 +853,79 @@ func (s *CodeContextMCPServer) getSymbolInfo(ctx context.Context, req *mcp.CallT
 		if symbol.Documentation != "" {
 			result += fmt.Sprintf("**Documentation:** %s\n", symbol.Documentation)
 		}
+		if symbol.Coverage != nil {
+			result += fmt.Sprintf("**Coverage:** %s\n", formatCoverage(symbol.Coverage))
+		}
+		if symbol.Mutants != nil {
+			result += fmt.Sprintf("**Mutation score:** %s\n", formatMutants(symbol.Mutants))
+		}
+		if benchmarks := benchmarkedBy(graph, symbol.Id); len(benchmarks) > 0 {
+			result += fmt.Sprintf("**Benchmarked by:** %s\n", strings.Join(benchmarks, ", "))
+		}
+		result += formatIndexedReferences(indexedReferences(graph, symbol.Id, targetDir))
+		if profile == analyzer.ProfileDeep {
+			result += s.languageServerInfo(ctx, graph, symbol, targetDir)
+			if similar := similarSymbols(graph, symbol, targetDir); len(similar) > 0 {
+				result += fmt.Sprintf("**Similar symbols:** %s\n", strings.Join(similar, ", "))
+			}
+		}
+		if stories := storiesFor(graph, getFilePathForSymbol(graph, symbol), symbol.Name, targetDir); len(stories) > 0 {
+			result += fmt.Sprintf("**Stories:** %s\n", strings.Join(stories, "; "))
+		}
+		if condition := symbol.MetadataString(parser.MetadataPreprocessorCondition); condition != "" {
+			result += fmt.Sprintf("**Compiled when:** `%s`\n", condition)
+		}
+		if props := symbol.MetadataStrings(parser.MetadataProps); len(props) > 0 {
+			result += fmt.Sprintf("**Props:** `%s`\n", strings.Join(props, "`, `"))
+		}
+		result += templateInfo(graph, symbol, getFilePathForSymbol(graph, symbol), targetDir)
+		if commands := symbol.MetadataStrings(parser.MetadataShellCommands); len(commands) > 0 {
+			result += fmt.Sprintf("**Invokes:** %s\n", strings.Join(commands, ", "))
+		}
+		if cell, ok := symbol.MetadataInt(parser.MetadataNotebookCell); ok {
+			result += fmt.Sprintf("**Notebook cell:** #%d (line %d within the cell)\n", cell+1, symbol.Location.StartLine)
+		}
+		if qualifiers := symbol.MetadataStrings(parser.MetadataCudaQualifiers); len(qualifiers) > 0 {
+			result += fmt.Sprintf("**CUDA:** `__%s__`\n", strings.Join(qualifiers, "__` `__"))
+		}
+		if kernels := symbol.MetadataStrings(parser.MetadataKernelLaunches); len(kernels) > 0 {
+			result += fmt.Sprintf("**Launches kernels:** %s\n", strings.Join(kernels, ", "))
+		}
+		if stage := symbol.MetadataString(parser.MetadataShaderStage); stage != "" {
+			result += fmt.Sprintf("**Metal shader stage:** %s\n", stage)
+		}
+		if edge := byDeclaration[symbol.Id]; edge != nil {
+			result += declarationLines(edge, targetDir)
+		}
+		if params := symbol.MetadataStrings(parser.MetadataTypeParameters); len(params) > 0 {
+			result += fmt.Sprintf("**Type parameters:** `%s`\n", strings.Join(params, "`, `"))
+		}
+		if embeds := symbol.MetadataStrings(parser.MetadataEmbeds); len(embeds) > 0 {
+			result += fmt.Sprintf("**Embeds:** `%s`\n", strings.Join(embeds, "`, `"))
+		}
+		if terms := symbol.MetadataStrings(parser.MetadataTypeSet); len(terms) > 0 {
+			result += fmt.Sprintf("**Type set:** `%s`\n", strings.Join(terms, "`, `"))
+		}
+		if decorators := symbol.MetadataStrings(parser.MetadataDecorators); len(decorators) > 0 {
+			result += fmt.Sprintf("**Decorators:** `@%s`\n", strings.Join(decorators, "`, `@"))
+		}
+		if async, _ := symbol.Metadata[parser.MetadataAsync].(bool); async {
+			result += "**Async:** yes\n"
+		}
+		if model := symbol.MetadataString(parser.MetadataModel); model != "" {
+			result += fmt.Sprintf("**Model:** %s\n", model)
+		}
+		if fields := symbol.MetadataStrings(parser.MetadataFields); len(fields) > 0 {
+			result += fmt.Sprintf("**Fields:** `%s`\n", strings.Join(fields, "`, `"))
+		}
 		
 		// Add framework-specific insights
-		if frameworkInsights := s.getFrameworkInsights(symbol); frameworkInsights != "" {
+		if frameworkInsights := s.getFrameworkInsights(graph, symbol); frameworkInsights != "" {
 			result += fmt.Sprintf("**Framework Insights:** %s\n", frameworkInsights)
 		}
+		if notes := store.ForSymbol(annotations.RelativePath(getFilePathForSymbol(graph, symbol), targetDir), symbol.Name); len(notes) > 0 {
+			result += "**Notes:**\n" + annotations.Markdown(notes, false)
+		}
 	}
 
 	elapsed := time.Since(start)
@@ -361,6 +943,9 @@ func (s *CodeContextMCPServer) searchSymbols(ctx context.Context, req *mcp.CallT
 		log.Printf("[MCP] ERROR: query is required")
 		return nil, nil, fmt.Errorf("query is required")
 	}
+	if args.Match != "" && args.Match != "name" && args.Match != "value" {
+		return nil, nil, fmt.Errorf("unknown match mode %q (use name or value)", args.Match)
+	}
 
 	// Set default limit
 	if args.Limit <= 0 {
@@ -369,33 +954,68 @@ func (s *CodeContextMCPServer) searchSymbols(ctx context.Context, req *mcp.CallT
 	log.Printf("[MCP] Searching symbols with query='%s', limit=%d", args.Query, args.Limit)
 
 	// Resolve target directory
-	targetDir := s.resolveTargetDir(args.TargetDir)
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Repeat calls return the rendered response until the watcher sees a change
+	cache := s.cachedCallFor("search_symbols", args, targetDir)
+	if result, ok := cache.result(); ok {
+		log.Printf("[MCP] Tool completed: search_symbols (cached, took %v)", time.Since(start))
+		return result, nil, nil
+	}
 
 	// Ensure we have fresh analysis
 	log.Printf("[MCP] Refreshing analysis for symbol search...")
-	if err := s.refreshAnalysisWithTargetDir(targetDir); err != nil {
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
 		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
 		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
 	}
 
+	scope, err := newSearchScope(graph, targetDir, args.PathPrefix, args.Package)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	symbolFile := make(map[types.SymbolId]string)
+	for path, file := range graph.Files {
+		for _, id := range file.Symbols {
+			symbolFile[id] = path
+		}
+	}
+
 	var matches []*types.Symbol
 	query := strings.ToLower(args.Query)
-	log.Printf("[MCP] Searching through %d symbols for query: %s", len(s.graph.Symbols), query)
-
-	for _, symbol := range s.graph.Symbols {
-		// Check name match
-		nameMatch := strings.Contains(strings.ToLower(symbol.Name), query)
+	value := strings.Trim(args.Query, "\"'`")
+	log.Printf("[MCP] Searching through %d symbols for query: %s", len(graph.Symbols), query)
+
+	for _, symbol := range graph.Symbols {
+		// Check name match, or in value mode the exact value
+		var nameMatch bool
+		if args.Match == "value" {
+			nameMatch = symbol.MatchesValue(value)
+		} else {
+			nameMatch = strings.Contains(strings.ToLower(symbol.Name), query)
+		}
+		if !nameMatch || !scope.contains(symbolFile[symbol.Id]) {
+			continue
+		}
+		if args.PublicOnly && !symbol.IsPublic() {
+			continue
+		}
 		
 		// Check framework type filter
 		frameworkMatch := true
 		if args.FrameworkType != "" {
-			frameworkMatch = s.matchesFramework(symbol, args.FrameworkType)
+			frameworkMatch = matchesFramework(graph, symbol, args.FrameworkType)
 		}
 		
 		// Check symbol type filter
 		symbolTypeMatch := true
 		if args.SymbolType != "" {
-			symbolTypeMatch = strings.EqualFold(string(symbol.Type), args.SymbolType)
+			symbolTypeMatch = symbol.MatchesKind(args.SymbolType)
 		}
 		
 		if nameMatch && frameworkMatch && symbolTypeMatch {
@@ -409,13 +1029,22 @@ func (s *CodeContextMCPServer) searchSymbols(ctx context.Context, req *mcp.CallT
 
 	if len(matches) == 0 {
 		result := fmt.Sprintf("No symbols found matching '%s'", args.Query)
+		if args.Match == "value" {
+			result = fmt.Sprintf("No constants or enums found with the value '%s'", value)
+		}
+		if scope != nil {
+			result += fmt.Sprintf(" in %s", scope.label)
+		}
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: result}},
 		}, nil, nil
 	}
 
 	result := fmt.Sprintf("# Symbol Search Results: '%s'\n\n", args.Query)
-	if args.SymbolType != "" || args.FrameworkType != "" {
+	if scope != nil {
+		result += fmt.Sprintf("**Scope:** %s\n\n", scope.label)
+	}
+	if args.SymbolType != "" || args.FrameworkType != "" || args.PublicOnly || args.Match == "value" {
 		result += fmt.Sprintf("**Filters Applied:** ")
 		if args.SymbolType != "" {
 			result += fmt.Sprintf("Symbol Type: %s ", args.SymbolType)
@@ -423,24 +1052,42 @@ func (s *CodeContextMCPServer) searchSymbols(ctx context.Context, req *mcp.CallT
 		if args.FrameworkType != "" {
 			result += fmt.Sprintf("Framework: %s ", args.FrameworkType)
 		}
+		if args.PublicOnly {
+			result += "Public API only "
+		}
+		if args.Match == "value" {
+			result += "Exact value "
+		}
 		result += "\n\n"
 	}
 	result += fmt.Sprintf("Found %d matches:\n\n", len(matches))
 
 	for _, symbol := range matches {
 		frameworkInfo := ""
-		if symbol.Type != "" && string(symbol.Type) != symbol.Kind {
-			frameworkInfo = fmt.Sprintf(" [%s]", symbol.Type)
+		if subtype := symbol.Subtype(); subtype != "" {
+			frameworkInfo = fmt.Sprintf(" [%s]", subtype)
 		}
-		result += fmt.Sprintf("- **%s**%s (%s) - Line %d\n", 
-			symbol.Name, frameworkInfo, symbol.Kind, symbol.Location.StartLine)
+		// Qualified names carry the path relative to the analyzed directory
+		result += fmt.Sprintf("- **%s**%s (%s) - %s, Line %d\n", 
+			symbol.Name, frameworkInfo, symbol.NormalizedKind(), types.FilePathFromQualifiedName(symbol.FullyQualifiedName), symbol.Location.StartLine)
 		
+		if args.Match == "value" {
+			for _, definition := range valueDefinitions(symbol, value) {
+				result += fmt.Sprintf("  `%s`\n", definition)
+			}
+		}
+		if props := symbol.MetadataStrings(parser.MetadataProps); len(props) > 0 {
+			result += fmt.Sprintf("  Props: `%s`\n", strings.Join(props, "`, `"))
+		}
+
 		// Add framework-specific details
-		if insight := s.getFrameworkInsights(symbol); insight != "" {
+		if insight := s.getFrameworkInsights(graph, symbol); insight != "" {
 			result += fmt.Sprintf("  *%s*\n", insight)
 		}
 	}
 
+	cache.store(result)
+
 	elapsed := time.Since(start)
 	log.Printf("[MCP] Tool completed: search_symbols (took %v, found %d matches)", elapsed, len(matches))
 	return &mcp.CallToolResult{
@@ -451,19 +1098,34 @@ func (s *CodeContextMCPServer) searchSymbols(ctx context.Context, req *mcp.CallT
 func (s *CodeContextMCPServer) getDependencies(ctx context.Context, req *mcp.CallToolRequest, args GetDependenciesArgs) (*mcp.CallToolResult, any, error) {
 	log.Printf("[MCP] Tool called: get_dependencies with args: %+v", args)
 	start := time.Now()
+
+	if args.Kind != "" && !validImportKind(args.Kind) {
+		return nil, nil, fmt.Errorf("unknown import kind %q (use %s)", args.Kind, importKindNames())
+	}
 	
 	// Resolve target directory
-	targetDir := s.resolveTargetDir(args.TargetDir)
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
 	
+	// Repeat calls return the rendered response until the watcher sees a change
+	cache := s.cachedCallFor("get_dependencies", args, targetDir)
+	if result, ok := cache.result(); ok {
+		log.Printf("[MCP] Tool completed: get_dependencies (cached, took %v)", time.Since(start))
+		return result, nil, nil
+	}
+
 	// Ensure we have fresh analysis
 	log.Printf("[MCP] Refreshing analysis for dependency analysis...")
-	if err := s.refreshAnalysisWithTargetDir(targetDir); err != nil {
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
 		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
 		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
 	}
 
 	result := "# Dependency Analysis\n\n"
-	log.Printf("[MCP] Analyzing %d edges for dependencies", len(s.graph.Edges))
+	log.Printf("[MCP] Analyzing %d edges for dependencies", len(graph.Edges))
 
 	if args.FilePath != "" {
 		// File-specific dependencies
@@ -471,38 +1133,41 @@ func (s *CodeContextMCPServer) getDependencies(ctx context.Context, req *mcp.Cal
 		
 		if args.Direction == "" || args.Direction == "imports" {
 			result += "### Imports:\n"
-			found := false
-			for _, edge := range s.graph.Edges {
-				if edge.Type == "imports" && edge.From == types.NodeId(args.FilePath) {
-					result += fmt.Sprintf("- %s\n", edge.To)
-					found = true
-				}
-			}
-			if !found {
+			lines := importLines(graph, args.FilePath, args.Kind, false)
+			if len(lines) == 0 {
 				result += "No imports found.\n"
 			}
+			result += strings.Join(lines, "")
+			if lines := injectionLines(graph, args.FilePath, false); len(lines) > 0 {
+				result += "\n### Injected dependencies:\n" + strings.Join(lines, "")
+			}
+			if lines := generatedLines(graph, args.FilePath, false); len(lines) > 0 {
+				result += "\n### Generated from:\n" + strings.Join(lines, "")
+			}
 		}
 
 		if args.Direction == "" || args.Direction == "dependents" {
 			result += "\n### Dependents (files that import this):\n"
-			found := false
-			for _, edge := range s.graph.Edges {
-				if edge.Type == "imports" && edge.To == types.NodeId(args.FilePath) {
-					result += fmt.Sprintf("- %s\n", edge.From)
-					found = true
-				}
-			}
-			if !found {
+			lines := importLines(graph, args.FilePath, args.Kind, true)
+			if len(lines) == 0 {
 				result += "No dependents found.\n"
 			}
+			result += strings.Join(lines, "")
+			if lines := injectionLines(graph, args.FilePath, true); len(lines) > 0 {
+				result += "\n### Injected into:\n" + strings.Join(lines, "")
+			}
+			if lines := generatedLines(graph, args.FilePath, true); len(lines) > 0 {
+				result += "\n### Generated code (run `dart run build_runner build` after changing these models):\n" + strings.Join(lines, "")
+			}
+			result += untestedDependents(graph, args.FilePath)
 		}
 	} else {
 		// Global dependency overview
 		result += "## Global Dependency Overview\n\n"
 		
-		fileCount := len(s.graph.Files)
+		fileCount := len(graph.Files)
 		importCount := 0
-		for _, edge := range s.graph.Edges {
+		for _, edge := range graph.Edges {
 			if edge.Type == "imports" {
 				importCount++
 			}
@@ -513,7 +1178,7 @@ func (s *CodeContextMCPServer) getDependencies(ctx context.Context, req *mcp.Cal
 		
 		// Most imported files
 		dependentCounts := make(map[string]int)
-		for _, edge := range s.graph.Edges {
+		for _, edge := range graph.Edges {
 			if edge.Type == "imports" {
 				dependentCounts[string(edge.To)]++
 			}
@@ -521,18 +1186,22 @@ func (s *CodeContextMCPServer) getDependencies(ctx context.Context, req *mcp.Cal
 		
 		if len(dependentCounts) > 0 {
 			result += "\n### Most Imported Files:\n"
-			// Simple top 5 most imported
-			count := 0
-			for file, deps := range dependentCounts {
-				if count >= 5 {
-					break
-				}
-				result += fmt.Sprintf("- %s (%d imports)\n", file, deps)
-				count++
+			topN := args.TopN
+			if topN <= 0 {
+				topN = 5
+			}
+			ranking := rank.Top(dependentCounts, topN)
+			for _, entry := range ranking.Entries {
+				result += fmt.Sprintf("- %s (%d imports)\n", entry.Key, entry.Count)
+			}
+			if note := ranking.Note("imports"); note != "" {
+				result += note + "\n"
 			}
 		}
 	}
 
+	cache.store(result)
+
 	elapsed := time.Since(start)
 	log.Printf("[MCP] Tool completed: get_dependencies (took %v)", elapsed)
 	return &mcp.CallToolResult{
@@ -564,14 +1233,23 @@ func (s *CodeContextMCPServer) watchChanges(ctx context.Context, req *mcp.CallTo
 		}
 		
 		// Resolve target directory
-		targetDir := s.resolveTargetDir(args.TargetDir)
+		targetDir, err := s.resolveTargetDir(args.TargetDir)
+		if err != nil {
+			return nil, nil, err
+		}
 		
 		// Create watcher config
 		config := watcher.Config{
 			TargetDir:    targetDir,
-			OutputFile:   "CLAUDE.md", // Not used in MCP mode
-			DebounceTime: time.Duration(s.config.DebounceMs) * time.Millisecond,
-			IncludeExts:  []string{".ts", ".tsx", ".js", ".jsx", ".go", ".py", ".java", ".cpp", ".c", ".rs"},
+			DebounceTime: time.Duration(s.currentConfig().DebounceMs) * time.Millisecond,
+			// Cached results also depend on manifests, reports and files of any
+			// language, so every change under the target drops them
+			Include: func(string) bool { return true },
+			OnChange: func(changes []watcher.FileChange) {
+				log.Printf("[MCP] %d files changed, dropping cached tool results", len(changes))
+				s.results.invalidate()
+				s.dropSnapshot()
+			},
 		}
 		
 		// Start file watcher
@@ -583,6 +1261,9 @@ func (s *CodeContextMCPServer) watchChanges(ctx context.Context, req *mcp.CallTo
 		}
 		
 		s.watcher = fileWatcher
+		s.watchDir = targetDir
+		s.results.invalidate() // Changes made while unwatched were never seen
+		s.dropSnapshot()
 		log.Printf("[MCP] File watcher created successfully")
 		
 		// Start watching in a goroutine
@@ -626,11 +1307,20 @@ func (s *CodeContextMCPServer) getSemanticNeighborhoods(ctx context.Context, req
 	log.Printf("[MCP] Tool called: get_semantic_neighborhoods with args: %+v", args)
 
 	// Resolve target directory
-	targetDir := s.resolveTargetDir(args.TargetDir)
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Ensure we have fresh analysis
-	if s.graph == nil {
-		if err := s.refreshAnalysisWithTargetDir(targetDir); err != nil {
+	profile, err := s.resolveProfile(args.Profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have an analysis of the target to score neighborhoods against
+	graph, graphDir := s.currentGraph()
+	if graph == nil || graphDir != targetDir {
+		if graph, err = s.refreshAnalysisWithTargetDir(targetDir); err != nil {
 			log.Printf("[MCP] Failed to refresh analysis: %v", err)
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{&mcp.TextContent{Text: "Failed to analyze codebase: " + err.Error()}},
@@ -638,8 +1328,8 @@ func (s *CodeContextMCPServer) getSemanticNeighborhoods(ctx context.Context, req
 		}
 	}
 
-	// Get semantic neighborhoods from metadata
-	semanticData, err := s.getSemanticNeighborhoodsData()
+	// Git history is only analyzed here, on demand, and cached per commit
+	semanticData, err := s.semanticNeighborhoods(targetDir, graph, profile)
 	if err != nil {
 		log.Printf("[MCP] Failed to get semantic neighborhoods: %v", err)
 		return &mcp.CallToolResult{
@@ -661,53 +1351,102 @@ func (s *CodeContextMCPServer) getSemanticNeighborhoods(ctx context.Context, req
 // Helper methods
 
 func (s *CodeContextMCPServer) refreshAnalysis() error {
-	return s.refreshAnalysisWithTargetDir(s.config.TargetDir)
+	_, err := s.refreshAnalysisWithTargetDir(s.currentConfig().TargetDir)
+	return err
+}
+
+func (s *CodeContextMCPServer) refreshAnalysisWithTargetDir(targetDir string) (*types.CodeGraph, error) {
+	return s.refreshAnalysisWithProfile(targetDir, "")
 }
 
-func (s *CodeContextMCPServer) refreshAnalysisWithTargetDir(targetDir string) error {
-	log.Printf("[MCP] Starting analysis of directory: %s", targetDir)
-	graph, err := s.analyzer.AnalyzeDirectory(targetDir)
+// refreshAnalysisWithProfile analyzes targetDir with the named profile, or the
+// configured default profile when name is empty. The graph is returned for the
+// caller to use, since concurrent calls may replace the installed one.
+func (s *CodeContextMCPServer) refreshAnalysisWithProfile(targetDir, name string) (*types.CodeGraph, error) {
+	profile, err := s.resolveProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	if graph := s.snapshotGraph(targetDir, profile); graph != nil {
+		return graph, nil
+	}
+	start := time.Now()
+	graph, err := s.analyzeDir(targetDir, profile)
 	if err != nil {
 		log.Printf("[MCP] Analysis failed: %v", err)
-		return err
+		return nil, err
 	}
 	log.Printf("[MCP] Analysis completed successfully - %d files, %d symbols", len(graph.Files), len(graph.Symbols))
-	s.graph = graph
-	return nil
+	s.graphMu.Lock()
+	defer s.graphMu.Unlock()
+	s.graph, s.graphDir, s.graphProfile = graph, targetDir, profile
+	s.analyzedAt, s.analysisTime, s.graphSource = time.Now(), time.Since(start), "analysis"
+	s.useSnapshot = false
+	return graph, nil
 }
 
-func (s *CodeContextMCPServer) resolveTargetDir(targetDir string) string {
-	if targetDir != "" {
-		return expandPath(targetDir)
-	}
-	return s.config.TargetDir
+// analyzeDir analyzes dir with the configured builder settings and profile,
+// once an analysis slot is free. The graph is not installed as the live graph.
+func (s *CodeContextMCPServer) analyzeDir(dir string, profile analyzer.Profile) (*types.CodeGraph, error) {
+	return s.limiter.analyze(dir+"#"+string(profile), func() (*types.CodeGraph, error) {
+		// Hold off config reloads while the builder is configured and in use. Analyses
+		// of other directories may run alongside, so each gets its own builder.
+		s.configMu.RLock()
+		defer s.configMu.RUnlock()
+		builder, _ := s.newGraphBuilder(s.config, s.analyzer.Redactor(), s.analyzer.Summarizer())
+		builder.SetProfile(profile)
+		log.Printf("[MCP] Starting %s analysis of directory: %s", profile, dir)
+		return builder.AnalyzeDirectory(dir)
+	})
 }
 
-func expandPath(path string) string {
-	if strings.HasPrefix(path, "~/") {
-		home, _ := os.UserHomeDir()
-		return filepath.Join(home, path[2:])
+// analyzeWith returns an analysis function for packages that analyze
+// directories of their own, such as git worktrees, with the server's settings
+func (s *CodeContextMCPServer) analyzeWith(profile analyzer.Profile) analyzer.AnalyzeFunc {
+	return func(dir string) (*types.CodeGraph, error) {
+		return s.analyzeDir(dir, profile)
 	}
-	return path
 }
 
-// getSemanticNeighborhoodsData extracts semantic neighborhoods from the graph metadata
-func (s *CodeContextMCPServer) getSemanticNeighborhoodsData() (*analyzer.SemanticAnalysisResult, error) {
-	if s.graph == nil || s.graph.Metadata == nil || s.graph.Metadata.Configuration == nil {
-		return nil, fmt.Errorf("no graph metadata available")
+// resolveProfile parses the named profile, or the configured default profile
+// when name is empty
+func (s *CodeContextMCPServer) resolveProfile(name string) (analyzer.Profile, error) {
+	if name == "" {
+		name = s.currentConfig().Profile
 	}
+	return analyzer.ParseProfile(name)
+}
 
-	semanticInterface, exists := s.graph.Metadata.Configuration["semantic_neighborhoods"]
-	if !exists {
-		return nil, fmt.Errorf("no semantic neighborhoods data found - ensure this is a git repository")
-	}
+// currentGraph returns the installed graph and the directory it was analyzed from
+func (s *CodeContextMCPServer) currentGraph() (*types.CodeGraph, string) {
+	s.graphMu.Lock()
+	defer s.graphMu.Unlock()
+	return s.graph, s.graphDir
+}
 
-	semanticResult, ok := semanticInterface.(*analyzer.SemanticAnalysisResult)
-	if !ok {
-		return nil, fmt.Errorf("invalid semantic neighborhoods data format")
+// resolveTargetDir returns the directory a tool call should analyze, rejecting
+// directories outside the sandbox roots
+func (s *CodeContextMCPServer) resolveTargetDir(targetDir string) (string, error) {
+	if targetDir == "" {
+		return s.currentConfig().TargetDir, nil
+	}
+	dir := expandPath(targetDir)
+	if err := s.sandbox.check(dir); err != nil {
+		log.Printf("[MCP] AUDIT: Denied access to %q: %v", targetDir, err)
+		return "", err
 	}
+	if s.sandbox != nil && len(s.sandbox.roots) > 0 {
+		log.Printf("[MCP] AUDIT: Allowed access to %q", targetDir)
+	}
+	return dir, nil
+}
 
-	return semanticResult, nil
+func expandPath(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[2:])
+	}
+	return path
 }
 
 // buildSemanticNeighborhoodsResponse builds the response string for semantic neighborhoods
@@ -854,6 +1593,13 @@ func (s *CodeContextMCPServer) buildBasicNeighborhoodsResponse(neighborhoods []g
 		response.WriteString(fmt.Sprintf("- **Changes**: %d\n", neighborhood.ChangeFrequency))
 		response.WriteString(fmt.Sprintf("- **Files**: %d\n", len(neighborhood.Files)))
 		response.WriteString(fmt.Sprintf("- **Last Changed**: %s\n", neighborhood.LastChanged.Format("2006-01-02")))
+		if len(neighborhood.CommitTypes) > 0 {
+			activity := git.FormatCommitTypes(neighborhood.CommitTypes)
+			if neighborhood.Label != "" {
+				activity = fmt.Sprintf("%s (%s)", neighborhood.Label, activity)
+			}
+			response.WriteString(fmt.Sprintf("- **Activity**: %s\n", activity))
+		}
 		
 		if len(neighborhood.Files) > 0 {
 			response.WriteString("\n**Files:**\n")
@@ -925,12 +1671,16 @@ func (s *CodeContextMCPServer) buildQualityMetricsResponse(data *analyzer.Semant
 
 // Run starts the MCP server
 func (s *CodeContextMCPServer) Run(ctx context.Context) error {
-	log.Printf("[MCP] CodeContext MCP Server starting - will analyze %s", s.config.TargetDir)
+	log.Printf("[MCP] CodeContext MCP Server starting - will analyze %s", s.currentConfig().TargetDir)
 	
-	// Initial analysis
-	if err := s.refreshAnalysis(); err != nil {
-		log.Printf("[MCP] Initial analysis failed, server will not start: %v", err)
-		return fmt.Errorf("failed to perform initial analysis: %w", err)
+	// Initial analysis, skipped when a snapshot from the last shutdown exists;
+	// tool calls answer from the snapshot until the watcher sees a change or
+	// an explicit refresh, and refresh the analysis before answering after that
+	if !s.loadSnapshot() {
+		if err := s.refreshAnalysis(); err != nil {
+			log.Printf("[MCP] Initial analysis failed, server will not start: %v", err)
+			return fmt.Errorf("failed to perform initial analysis: %w", err)
+		}
 	}
 	
 	log.Printf("[MCP] CodeContext MCP Server ready - analysis complete")
@@ -966,6 +1716,10 @@ func (s *CodeContextMCPServer) Stop() {
 		s.watcher = nil
 		log.Printf("[MCP] File watcher stopped")
 	}
+	s.configMu.Lock()
+	s.languages.Close()
+	s.languages = nil
+	s.configMu.Unlock()
 	log.Printf("[MCP] MCP server stopped successfully")
 }
 
@@ -1001,8 +1755,17 @@ func (s *CodeContextMCPServer) getFrameworkSpecificDescription(symbolType string
 	}
 }
 
+// symbolKindLabel renders the normalized kind of a symbol with its subtype,
+// such as "class, struct"
+func symbolKindLabel(symbol *types.Symbol) string {
+	if subtype := symbol.Subtype(); subtype != "" {
+		return symbol.NormalizedKind() + ", " + subtype
+	}
+	return symbol.NormalizedKind()
+}
+
 // getFrameworkInsights provides framework-specific insights for symbols
-func (s *CodeContextMCPServer) getFrameworkInsights(symbol *types.Symbol) string {
+func (s *CodeContextMCPServer) getFrameworkInsights(graph *types.CodeGraph, symbol *types.Symbol) string {
 	switch string(symbol.Type) {
 	case "component":
 		return "Consider: Props interface, state management, performance optimization"
@@ -1013,7 +1776,7 @@ func (s *CodeContextMCPServer) getFrameworkInsights(symbol *types.Symbol) string
 	case "store":
 		return "Consider: State mutations, subscriptions, persistence"
 	case "route":
-		filePath := s.getFilePathForSymbol(symbol)
+		filePath := getFilePathForSymbol(graph, symbol)
 		if strings.Contains(filePath, "/api/") {
 			return "API Route: Consider request validation, error handling, response types"
 		}
@@ -1024,11 +1787,11 @@ func (s *CodeContextMCPServer) getFrameworkInsights(symbol *types.Symbol) string
 }
 
 // matchesFramework checks if a symbol matches a specific framework
-func (s *CodeContextMCPServer) matchesFramework(symbol *types.Symbol, framework string) bool {
+func matchesFramework(graph *types.CodeGraph, symbol *types.Symbol, framework string) bool {
 	// Get file classification to determine framework
-	if s.graph != nil && s.graph.Files != nil {
-		filePath := s.getFilePathForSymbol(symbol)
-		if _, exists := s.graph.Files[filePath]; exists {
+	if graph != nil && graph.Files != nil {
+		filePath := getFilePathForSymbol(graph, symbol)
+		if _, exists := graph.Files[filePath]; exists {
 			// Check if file has framework metadata
 			// For now, do a simple string match on framework types
 			symbolType := string(symbol.Type)
@@ -1060,14 +1823,18 @@ func (s *CodeContextMCPServer) matchesFramework(symbol *types.Symbol, framework
 func (s *CodeContextMCPServer) getFrameworkAnalysis(ctx context.Context, req *mcp.CallToolRequest, args GetFrameworkAnalysisArgs) (*mcp.CallToolResult, any, error) {
 
 	// Resolve target directory
-	targetDir := s.resolveTargetDir(args.TargetDir)
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Ensure we have fresh analysis
-	if err := s.refreshAnalysisWithTargetDir(targetDir); err != nil {
+	graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+	if err != nil {
 		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
 	}
 
-	if s.graph == nil {
+	if graph == nil {
 		return nil, nil, fmt.Errorf("no graph available - ensure analysis has been performed")
 	}
 
@@ -1075,7 +1842,7 @@ func (s *CodeContextMCPServer) getFrameworkAnalysis(ctx context.Context, req *mc
 	frameworkSymbols := make(map[string][]*types.Symbol)
 	frameworkCounts := make(map[string]map[string]int)
 	
-	for _, symbol := range s.graph.Symbols {
+	for _, symbol := range graph.Symbols {
 		if symbol.Type == types.SymbolTypeComponent || 
 		   symbol.Type == types.SymbolTypeHook || 
 		   symbol.Type == types.SymbolTypeDirective || 
@@ -1086,11 +1853,12 @@ func (s *CodeContextMCPServer) getFrameworkAnalysis(ctx context.Context, req *mc
 		   symbol.Type == types.SymbolTypeLifecycle || 
 		   symbol.Type == types.SymbolTypeRoute || 
 		   symbol.Type == types.SymbolTypeMiddleware || 
-		   symbol.Type == types.SymbolTypeAction {
+		   symbol.Type == types.SymbolTypeAction ||
+		   symbol.Type == types.SymbolTypeWidget {
 			
 			// Determine framework from file classification
-			filePath := s.getFilePathForSymbol(symbol)
-			framework := s.getFrameworkForFile(filePath)
+			filePath := getFilePathForSymbol(graph, symbol)
+			framework := getFrameworkForFile(graph, filePath)
 			if framework == "" {
 				framework = "Unknown"
 			}
@@ -1109,7 +1877,52 @@ func (s *CodeContextMCPServer) getFrameworkAnalysis(ctx context.Context, req *mc
 		}
 	}
 
-	response := s.buildFrameworkAnalysisResponse(frameworkSymbols, frameworkCounts, args)
+	var response string
+	if strings.EqualFold(args.Framework, "tailwind") {
+		response = "# 🚀 Framework Analysis Report\n\n**Focused Analysis for: Tailwind**\n\n"
+	} else {
+		response = s.buildFrameworkAnalysisResponse(graph, frameworkSymbols, frameworkCounts, args)
+	}
+	if args.Framework == "" || strings.EqualFold(args.Framework, "tailwind") {
+		if !strings.HasSuffix(response, "\n\n") {
+			response += "\n"
+		}
+		if tailwind := analyzer.AnalyzeTailwind(graph, targetDir); tailwind != nil {
+			response += tailwindSection(tailwind)
+		} else if args.Framework != "" {
+			response += "❌ **No Tailwind config or stylesheet found**\n"
+		}
+	}
+	if args.Framework == "" || pythonFrameworkFocus(args.Framework) {
+		if python := analyzer.AnalyzePythonFrameworks(graph, targetDir); python != nil {
+			if !strings.HasSuffix(response, "\n\n") {
+				response += "\n"
+			}
+			response += pythonFrameworkSection(python, args.Framework)
+		}
+	}
+	if args.Framework == "" || strings.EqualFold(args.Framework, "django") {
+		if django := analyzer.AnalyzeDjango(graph, targetDir); django != nil {
+			if !strings.HasSuffix(response, "\n\n") {
+				response += "\n"
+			}
+			response += djangoSection(django)
+		}
+	}
+	if args.Framework == "" || strings.EqualFold(args.Framework, "flutter") {
+		if flutter := analyzer.AnalyzeFlutterState(graph, targetDir); flutter != nil {
+			if !strings.HasSuffix(response, "\n\n") {
+				response += "\n"
+			}
+			response += flutterStateSection(flutter)
+		}
+		if pubspecs := analyzer.FindPubspecs(graph, targetDir); len(pubspecs) > 0 {
+			if !strings.HasSuffix(response, "\n\n") {
+				response += "\n"
+			}
+			response += pubspecSection(pubspecs)
+		}
+	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: response}},
@@ -1117,12 +1930,14 @@ func (s *CodeContextMCPServer) getFrameworkAnalysis(ctx context.Context, req *mc
 }
 
 // getFrameworkForFile determines the framework for a given file path
-func (s *CodeContextMCPServer) getFrameworkForFile(filePath string) string {
+func getFrameworkForFile(graph *types.CodeGraph, filePath string) string {
 	// Check if we have file classification data
-	for _, file := range s.graph.Files {
+	for _, file := range graph.Files {
 		if file.Path == filePath {
 			// Try to get framework from metadata or file patterns
-			if strings.Contains(filePath, ".vue") {
+			if strings.HasSuffix(filePath, ".dart") {
+				return "Flutter"
+			} else if strings.Contains(filePath, ".vue") {
 				return "Vue"
 			} else if strings.Contains(filePath, ".svelte") {
 				return "Svelte"
@@ -1139,7 +1954,9 @@ func (s *CodeContextMCPServer) getFrameworkForFile(filePath string) string {
 	}
 	
 	// Fallback to basic pattern matching
-	if strings.Contains(filePath, ".vue") {
+	if strings.HasSuffix(filePath, ".dart") {
+		return "Flutter"
+	} else if strings.Contains(filePath, ".vue") {
 		return "Vue"
 	} else if strings.Contains(filePath, ".svelte") {
 		return "Svelte"
@@ -1157,7 +1974,7 @@ func (s *CodeContextMCPServer) getFrameworkForFile(filePath string) string {
 }
 
 // buildFrameworkAnalysisResponse builds the comprehensive framework analysis response
-func (s *CodeContextMCPServer) buildFrameworkAnalysisResponse(frameworkSymbols map[string][]*types.Symbol, frameworkCounts map[string]map[string]int, args GetFrameworkAnalysisArgs) string {
+func (s *CodeContextMCPServer) buildFrameworkAnalysisResponse(graph *types.CodeGraph, frameworkSymbols map[string][]*types.Symbol, frameworkCounts map[string]map[string]int, args GetFrameworkAnalysisArgs) string {
 	var response strings.Builder
 	
 	response.WriteString("# 🚀 Framework Analysis Report\n\n")
@@ -1214,7 +2031,7 @@ func (s *CodeContextMCPServer) buildFrameworkAnalysisResponse(frameworkSymbols m
 				break
 			}
 			emoji := s.getSymbolTypeEmoji(string(symbol.Type))
-			filePath := s.getFilePathForSymbol(symbol)
+			filePath := getFilePathForSymbol(graph, symbol)
 			location := fmt.Sprintf("%s:%d", filePath, symbol.Location.StartLine)
 			response.WriteString(fmt.Sprintf("- %s **%s** (`%s`) - %s\n", emoji, symbol.Name, symbol.Type, location))
 		}
@@ -1285,6 +2102,12 @@ func (s *CodeContextMCPServer) getFrameworkAnalysisInsights(framework string, sy
 			insights.WriteString("💡 **Consider stores**: Large component count without stores - consider global state management\n")
 		}
 		
+	case "flutter":
+		widgetCount := counts["widget"]
+		if widgetCount > 20 {
+			insights.WriteString("📦 **Large widget tree**: Consider splitting screens into smaller widgets and feature folders\n")
+		}
+
 	case "next.js":
 		routeCount := counts["route"]
 		middlewareCount := counts["middleware"]
@@ -1300,9 +2123,9 @@ func (s *CodeContextMCPServer) getFrameworkAnalysisInsights(framework string, sy
 }
 
 // getFilePathForSymbol finds the file path for a given symbol
-func (s *CodeContextMCPServer) getFilePathForSymbol(symbol *types.Symbol) string {
+func getFilePathForSymbol(graph *types.CodeGraph, symbol *types.Symbol) string {
 	// Look through all files to find which one contains this symbol
-	for filePath, fileNode := range s.graph.Files {
+	for filePath, fileNode := range graph.Files {
 		for _, symbolId := range fileNode.Symbols {
 			if symbolId == symbol.Id {
 				return filePath
@@ -1337,7 +2160,21 @@ func (s *CodeContextMCPServer) getSymbolTypeEmoji(symbolType string) string {
 		return "🔀"
 	case "action":
 		return "⚡"
+	case "macro":
+		return "🔣"
+	case "config_key":
+		return "🔧"
+	case "resource":
+		return "☸️"
 	default:
 		return "📦"
 	}
-}
\ No newline at end of file
+}
+// dartPartURI returns a Dart library or part file the way the other file's
+// part directives name it, relative to that file's directory
+func dartPartURI(from, to string) string {
+	if rel, err := filepath.Rel(filepath.Dir(from), to); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return to
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -340,6 +341,224 @@ func TestSearchSymbols(t *testing.T) {
 	}
 }
 
+func TestSearchSymbolsPublicOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "loader.go"), []byte("package loader\n\nfunc LoadUser() {}\nfunc loadUserCache() {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "loader.ts"), []byte("export function loadUserProfile() {}\nfunction loadUserSettings() {}\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	response, _, err := server.searchSymbols(context.Background(), nil, SearchSymbolsArgs{Query: "loadUser", SymbolType: "function", PublicOnly: true})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Public API only")
+	assert.Contains(t, text, "**LoadUser**")
+	assert.Contains(t, text, "**loadUserProfile**")
+	assert.NotContains(t, text, "loadUserCache")
+	assert.NotContains(t, text, "loadUserSettings")
+}
+
+func TestSearchSymbolsByValue(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "codes.go"), []byte("package codes\n\nconst PaymentFailed = \"PAYMENT_FAILED\"\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "status.py"), []byte("from enum import Enum\n\nclass Status(Enum):\n    FAILED = \"PAYMENT_FAILED\"\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.searchSymbols(ctx, nil, SearchSymbolsArgs{Query: "'PAYMENT_FAILED'", Match: "value"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**PaymentFailed**")
+	assert.Contains(t, text, `= "PAYMENT_FAILED"`)
+	assert.Contains(t, text, "**Status**")
+	assert.Contains(t, text, `FAILED = "PAYMENT_FAILED"`)
+
+	response, _, err = server.searchSymbols(ctx, nil, SearchSymbolsArgs{Query: "PAYMENT", Match: "value"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "No constants or enums found with the value 'PAYMENT'")
+
+	_, _, err = server.searchSymbols(ctx, nil, SearchSymbolsArgs{Query: "x", Match: "regex"})
+	assert.ErrorContains(t, err, `unknown match mode "regex"`)
+}
+
+func TestSearchSymbolsComponentProps(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Button.tsx"), []byte("interface ButtonProps {\n  label: string;\n  size?: 'sm' | 'lg';\n}\n\nexport function Button({ label, size = 'sm' }: ButtonProps) {\n  return <button>{label}</button>;\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Badge.svelte"), []byte("<script>\n  export let count = 0;\n</script>\n\n<span>{count}</span>\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.searchSymbols(ctx, nil, SearchSymbolsArgs{Query: "Button"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "Props: `label: string`, `size?: 'sm' | 'lg' = 'sm'`")
+
+	response, _, err = server.searchSymbols(ctx, nil, SearchSymbolsArgs{Query: "Badge"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "Props: `count? = 0`")
+
+	response, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "Badge"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Props:** `count? = 0`")
+}
+
+func TestGetFileAnalysisDartParts(t *testing.T) {
+	tmpDir := t.TempDir()
+	library := filepath.Join(tmpDir, "user.dart")
+	require.NoError(t, os.WriteFile(library, []byte("part 'user.g.dart';\n\nclass User {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "user.g.dart"), []byte("part of 'user.dart';\n\nclass UserSerializer {}\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: library})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Parts:** `user.g.dart`")
+	assert.Contains(t, text, "### Part `user.g.dart`\n\n")
+	assert.Contains(t, text, "- **UserSerializer** (class) - Line 3")
+
+	response, _, err = server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: filepath.Join(tmpDir, "user.g.dart")})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Part of:** `user.dart`")
+}
+
+func TestGetFileAnalysisCppModule(t *testing.T) {
+	tmpDir := t.TempDir()
+	module := filepath.Join(tmpDir, "math.cppm")
+	require.NoError(t, os.WriteFile(module, []byte("export module math;\n\nexport int add(int a, int b);\nint internal();\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "main.cpp"), []byte("import math;\n\nint main() { return add(1, 2); }\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: module})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Module:** `math` (interface unit)\n**Exports:** `add`\n")
+
+	response, _, err = server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: filepath.Join(tmpDir, "main.cpp")})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.NotContains(t, text, "**Module:**")
+	assert.Contains(t, text, "math.cppm")
+}
+
+func TestGetSymbolInfoCudaKernel(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "kernels.cu"), []byte("__global__ void saxpy(float* y) {}\n"+
+		"void launch(float* y) {\n    saxpy<<<1, 256>>>(y);\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Shaders.metal"), []byte("fragment float4 shade_pixel(float4 c [[stage_in]]) { return c; }\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "saxpy"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**CUDA:** `__global__`\n")
+
+	response, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "launch"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Launches kernels:** saxpy\n")
+
+	response, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "shade_pixel"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Metal shader stage:** fragment\n")
+}
+
+func TestGetSymbolInfoCppDeclaration(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "mesh.h"), []byte("class Mesh {\npublic:\n    void draw(float scale) const;\n};\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "mesh.cpp"), []byte("#include \"mesh.h\"\n\nvoid Mesh::draw(float scale) const {}\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "draw"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Declared in:** `mesh.h:3`\n**Defined in:** `mesh.cpp:3`\n")
+	assert.NotContains(t, text, "---", "the declaration and definition are one entry")
+
+	response, _, err = server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: filepath.Join(tmpDir, "mesh.h")})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Implementation:** `mesh.cpp`\n")
+}
+
+func TestGetSymbolInfoGoComposition(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "cache.go"), []byte("package cache\n\nimport \"sync\"\n\n"+
+		"type Cache[K comparable, V any] struct {\n\tsync.Mutex\n\titems map[K]V\n}\n\ntype Number interface {\n\t~int | ~float64\n}\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "Cache"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Type parameters:** `K comparable`, `V any`\n")
+	assert.Contains(t, text, "**Embeds:** `sync.Mutex`\n")
+
+	response, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "Number"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Type set:** `~int | ~float64`\n")
+}
+
+func TestGetSymbolInfoPythonModel(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "app.py"), []byte("from fastapi import FastAPI\nfrom pydantic import BaseModel\n\napp = FastAPI()\n\n\n"+
+		"class User(BaseModel):\n    id: int\n    name: str\n\n\n@app.get(\"/users/{id}\")\nasync def get_user(id: int) -> User:\n    return None\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "User"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Model:** pydantic\n")
+	assert.Contains(t, text, "**Fields:** `id: int`, `name: str`\n")
+
+	response, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "get_user"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Decorators:** `@app.get(\"/users/{id}\")`\n")
+	assert.Contains(t, text, "**Async:** yes\n")
+
+	response, _, err = server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "FastAPI"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## 🐍 Python Frameworks\n")
+	assert.Contains(t, text, "| GET | `/users/{id}` | `async get_user` | FastAPI | `app.py:13` |\n")
+	assert.Contains(t, text, "- `User` (pydantic) — `app.py:7`: `id: int`, `name: str`\n")
+}
+
 func TestGetSymbolInfo(t *testing.T) {
 	tmpDir := createTestDirectory(t)
 	defer os.RemoveAll(tmpDir)
@@ -787,7 +1006,8 @@ func TestResolveTargetDir(t *testing.T) {
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := server.resolveTargetDir(tt.targetDir)
+			result, err := server.resolveTargetDir(tt.targetDir)
+			require.NoError(t, err)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -1037,4 +1257,47 @@ func TestWatchChangesWithTargetDir(t *testing.T) {
 		content := result.Content[0].(*mcp.TextContent).Text
 		assert.Contains(t, content, "File watching enabled")
 	})
-}
\ No newline at end of file
+}
+func TestAnalysisProfiles(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.Profile = "fast"
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, _, err = server.getCodebaseOverview(ctx, nil, GetCodebaseOverviewArgs{})
+	require.NoError(t, err)
+	assert.Equal(t, analyzer.ProfileFast, server.graphProfile, "the config sets the default profile")
+
+	result, _, err := server.getSemanticNeighborhoods(ctx, nil, GetSemanticNeighborhoodsArgs{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "fast profile skips git analysis")
+
+	_, _, err = server.getCodebaseOverview(ctx, nil, GetCodebaseOverviewArgs{Profile: "deep"})
+	require.NoError(t, err)
+	assert.Equal(t, analyzer.ProfileDeep, server.graphProfile, "a call can pick another profile")
+
+	result, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "testFunction", Profile: "deep"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "`TestClass` (main.ts:5)", "the deep profile embeds symbols")
+
+	result, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "testFunction", Profile: "balanced"})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Content[0].(*mcp.TextContent).Text, "Similar symbols")
+
+	_, _, err = server.mostUsedSymbols(ctx, nil, MostUsedSymbolsArgs{Profile: "balanced"})
+	require.NoError(t, err)
+	assert.Equal(t, analyzer.ProfileBalanced, server.graphProfile, "analysis tools take a profile per call")
+
+	_, _, err = server.getCodebaseOverview(ctx, nil, GetCodebaseOverviewArgs{Profile: "thorough"})
+	assert.Error(t, err)
+	_, _, err = server.compareGraphs(ctx, nil, CompareGraphsArgs{From: ".", Profile: "thorough"})
+	assert.Error(t, err, "graph comparisons resolve the profile of the call")
+
+	config.Profile = "thorough"
+	_, err = NewCodeContextMCPServer(config)
+	assert.Error(t, err, "an unknown default profile is rejected")
+}
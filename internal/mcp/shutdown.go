@@ -0,0 +1,220 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// callTracker counts tool calls in flight so shutdown can let them finish
+type callTracker struct {
+	mu       sync.Mutex
+	running  int
+	draining bool
+	idle     chan struct{} // Closed once draining and no calls are running
+}
+
+func newCallTracker() *callTracker {
+	return &callTracker{idle: make(chan struct{})}
+}
+
+// begin registers a call, or reports false once the server is draining
+func (t *callTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.running++
+	return true
+}
+
+func (t *callTracker) end() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running--
+	if t.draining && t.running == 0 {
+		close(t.idle)
+	}
+}
+
+// drain rejects new calls and returns a channel closed when running calls finish
+func (t *callTracker) drain() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.draining {
+		t.draining = true
+		if t.running == 0 {
+			close(t.idle)
+		}
+	}
+	return t.idle
+}
+
+func (t *callTracker) inFlight() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.running
+}
+
+// trackCallsMiddleware lets shutdown wait for tool calls and turns away calls
+// that arrive after shutdown started
+func (s *CodeContextMCPServer) trackCallsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != "tools/call" {
+			return next(ctx, method, req)
+		}
+		if !s.calls.begin() {
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{&mcp.TextContent{Text: "Server is shutting down."}},
+			}, nil
+		}
+		defer s.calls.end()
+		return next(ctx, method, req)
+	}
+}
+
+// Shutdown stops accepting tool calls, waits for running calls until ctx is
+// done, stops the watcher and saves the graph snapshot. It is safe to call
+// more than once; later calls return once the first has finished.
+func (s *CodeContextMCPServer) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.shutdownErr = s.shutdown(ctx)
+	})
+	return s.shutdownErr
+}
+
+func (s *CodeContextMCPServer) shutdown(ctx context.Context) error {
+	var err error
+	log.Printf("[MCP] Shutting down: waiting for %d in-flight tool calls", s.calls.inFlight())
+	select {
+	case <-s.calls.drain():
+	case <-ctx.Done():
+		err = fmt.Errorf("gave up waiting for %d tool calls: %w", s.calls.inFlight(), ctx.Err())
+		log.Printf("[MCP] WARNING: %v", err)
+	}
+
+	s.Stop()
+
+	if saveErr := s.saveSnapshot(); saveErr != nil {
+		log.Printf("[MCP] WARNING: Failed to save graph snapshot: %v", saveErr)
+		if err == nil {
+			err = saveErr
+		}
+	}
+	return err
+}
+
+// graphSnapshot is the analysis saved on shutdown so a restart can serve
+// requests before its first analysis completes
+type graphSnapshot struct {
+	TargetDir string           `json:"target_dir"`
+	SavedAt   time.Time        `json:"saved_at"`
+	Profile   string           `json:"profile,omitempty"`
+	Graph     *types.CodeGraph `json:"graph"`
+}
+
+// saveSnapshot writes the current graph of the server's target directory. A
+// read-only server leaves the snapshot as it is.
+func (s *CodeContextMCPServer) saveSnapshot() error {
+	config := s.currentConfig()
+	path := config.SnapshotPath
+	if path == "" {
+		return nil
+	}
+	if config.ReadOnly {
+		log.Printf("[MCP] Read-only, not saving graph snapshot to %s", path)
+		return nil
+	}
+	s.graphMu.Lock()
+	graph, dir, profile := s.graph, s.graphDir, s.graphProfile
+	s.graphMu.Unlock()
+	if graph == nil || dir != config.TargetDir {
+		log.Printf("[MCP] No analysis of %s to snapshot", config.TargetDir)
+		return nil
+	}
+
+	data, err := json.Marshal(graphSnapshot{TargetDir: dir, SavedAt: time.Now(), Profile: string(profile), Graph: graph})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	log.Printf("[MCP] Saved graph snapshot to %s (%d files)", path, len(graph.Files))
+	return nil
+}
+
+// loadSnapshot restores the graph saved by a previous run, reporting whether
+// one was found for the server's target directory. Calls are answered from it
+// until dropSnapshot.
+func (s *CodeContextMCPServer) loadSnapshot() bool {
+	config := s.currentConfig()
+	path := config.SnapshotPath
+	if path == "" {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[MCP] WARNING: Failed to read graph snapshot: %v", err)
+		}
+		return false
+	}
+	var snapshot graphSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil || snapshot.Graph == nil {
+		log.Printf("[MCP] WARNING: Ignoring unreadable graph snapshot %s", path)
+		return false
+	}
+	if snapshot.TargetDir != config.TargetDir {
+		return false
+	}
+	s.graphMu.Lock()
+	s.graph, s.graphDir, s.graphProfile = snapshot.Graph, snapshot.TargetDir, analyzer.Profile(snapshot.Profile)
+	s.analyzedAt, s.analysisTime, s.graphSource = snapshot.SavedAt, 0, "snapshot"
+	s.useSnapshot = true
+	s.graphMu.Unlock()
+	log.Printf("[MCP] Loaded graph snapshot from %s (saved %s, %d files)",
+		path, snapshot.SavedAt.Format(time.RFC3339), len(snapshot.Graph.Files))
+	return true
+}
+
+// snapshotGraph returns the loaded snapshot while it still stands in for an
+// analysis of targetDir with profile, or nil
+func (s *CodeContextMCPServer) snapshotGraph(targetDir string, profile analyzer.Profile) *types.CodeGraph {
+	s.graphMu.Lock()
+	defer s.graphMu.Unlock()
+	if !s.useSnapshot || s.graphDir != targetDir || s.graphProfile != profile {
+		return nil
+	}
+	return s.graph
+}
+
+// dropSnapshot stops answering calls from the loaded snapshot, so the next call
+// analyzes the project again
+func (s *CodeContextMCPServer) dropSnapshot() {
+	s.graphMu.Lock()
+	defer s.graphMu.Unlock()
+	if s.useSnapshot {
+		log.Printf("[MCP] Graph snapshot is out of date, analyzing on the next call")
+		s.useSnapshot = false
+	}
+}
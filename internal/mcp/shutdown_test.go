@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownWaitsForToolCalls(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = createTestDirectory(t)
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := server.trackCallsMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		close(started)
+		<-release
+		return &mcp.CallToolResult{}, nil
+	})
+	go handler(context.Background(), "tools/call", nil)
+	<-started
+
+	done := make(chan error, 1)
+	go func() { done <- server.Shutdown(context.Background()) }()
+	require.Eventually(t, func() bool {
+		server.calls.mu.Lock()
+		defer server.calls.mu.Unlock()
+		return server.calls.draining
+	}, time.Second, 10*time.Millisecond)
+	result, err := handler(context.Background(), "tools/call", nil)
+	require.NoError(t, err)
+	assert.True(t, result.(*mcp.CallToolResult).IsError, "new calls are rejected while draining")
+
+	select {
+	case <-done:
+		t.Fatal("shutdown returned before the running call finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	require.NoError(t, <-done)
+	assert.NoError(t, server.Shutdown(context.Background()), "shutdown is idempotent")
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = createTestDirectory(t)
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	require.True(t, server.calls.begin())
+	defer server.calls.end()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, server.stopped)
+}
+
+func TestGraphSnapshot(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = createTestDirectory(t)
+	config.SnapshotPath = filepath.Join(t.TempDir(), "cache", "mcp-graph.json")
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	assert.False(t, server.loadSnapshot(), "no snapshot yet")
+
+	require.NoError(t, server.refreshAnalysis())
+	require.NoError(t, server.Shutdown(context.Background()))
+	require.FileExists(t, config.SnapshotPath)
+
+	restarted, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	require.True(t, restarted.loadSnapshot())
+	assert.Equal(t, len(server.graph.Files), len(restarted.graph.Files))
+	assert.Equal(t, len(server.graph.Symbols), len(restarted.graph.Symbols))
+
+	// Calls are answered from the snapshot until files change
+	snapshot := restarted.graph
+	graph, err := restarted.refreshAnalysisWithTargetDir(config.TargetDir)
+	require.NoError(t, err)
+	assert.Same(t, snapshot, graph)
+	restarted.dropSnapshot()
+	graph, err = restarted.refreshAnalysisWithTargetDir(config.TargetDir)
+	require.NoError(t, err)
+	assert.NotSame(t, snapshot, graph)
+	assert.Equal(t, "analysis", restarted.graphSource)
+
+	other := *config
+	other.TargetDir = t.TempDir()
+	elsewhere, err := NewCodeContextMCPServer(&other)
+	require.NoError(t, err)
+	assert.False(t, elsewhere.loadSnapshot(), "snapshots of other directories are ignored")
+}
+
+func TestGraphSnapshotReadOnly(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = createTestDirectory(t)
+	config.SnapshotPath = filepath.Join(t.TempDir(), "mcp-graph.json")
+	config.ReadOnly = true
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	require.NoError(t, server.refreshAnalysis())
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.NoFileExists(t, config.SnapshotPath)
+}
@@ -0,0 +1,32 @@
+package mcp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// maxSimilarSymbols bounds the similar symbols listed per symbol
+const maxSimilarSymbols = 5
+
+// similarSymbols lists the symbols whose embeddings are closest to a symbol's,
+// as "`Name` (file:line)". Only graphs analyzed with the deep profile have
+// embeddings.
+func similarSymbols(graph *types.CodeGraph, symbol *types.Symbol, targetDir string) []string {
+	var similar []string
+	for _, match := range analyzer.Embeddings(graph).Similar(symbol.Id, maxSimilarSymbols) {
+		other := graph.Symbols[match.Id]
+		if other == nil {
+			continue
+		}
+		path := getFilePathForSymbol(graph, other)
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			path = filepath.ToSlash(rel)
+		}
+		similar = append(similar, fmt.Sprintf("`%s` (%s:%d)", other.Name, path, other.Location.StartLine))
+	}
+	return similar
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type SimulateRemovalArgs struct {
+	Path      string `json:"path"`                 // File or package directory to remove, relative to the target directory
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum dependencies listed per package (default 20)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) simulateRemoval(ctx context.Context, req *mcp.CallToolRequest, args SimulateRemovalArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: simulate_removal with args: %+v", args)
+	start := time.Now()
+
+	if args.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	// A file, or every file under a directory
+	path := expandPath(args.Path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(targetDir, path)
+	}
+	var files []string
+	if graph.Files[path] != nil {
+		files = []string{path}
+	} else {
+		for file := range graph.Files {
+			if strings.HasPrefix(file, path+string(filepath.Separator)) {
+				files = append(files, file)
+			}
+		}
+		sort.Strings(files)
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no analyzed files at %s", args.Path)
+	}
+
+	impact := analyzer.SimulateRemoval(graph, targetDir, files)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# Removal Simulation: `%s`\n\n", args.Path))
+	result.WriteString(fmt.Sprintf("**Removed:** %d files | **Broken dependencies:** %d in %d packages\n\n", len(impact.Removed), impact.Broken, len(impact.Packages)))
+	if impact.Broken == 0 {
+		result.WriteString("✅ No remaining code depends on the removed files\n\n")
+	}
+	for _, pkg := range impact.Packages {
+		result.WriteString(fmt.Sprintf("## `%s` (%d files, %d dependencies)\n\n", pkg.Package, pkg.Files, len(pkg.Dependencies)))
+		shown := pkg.Dependencies
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, dep := range shown {
+			via := dep.Via
+			if dep.Detail != "" {
+				via += " `" + dep.Detail + "`"
+			}
+			line := fmt.Sprintf("- `%s` → `%s` (%s)", dep.File, dep.Target, via)
+			if dep.Test {
+				line += " _(test)_"
+			}
+			result.WriteString(line + "\n")
+		}
+		if len(shown) < len(pkg.Dependencies) {
+			result.WriteString(fmt.Sprintf("- _... and %d more_\n", len(pkg.Dependencies)-len(shown)))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("_Dependencies come from imports, symbol relationships and imports naming packages. Code outside this project, and code loading the removed files by reflection or configuration, is not seen._\n")
+
+	log.Printf("[MCP] Tool completed: simulate_removal (took %v, %d files removed, %d broken dependencies in %d packages)", time.Since(start), len(impact.Removed), impact.Broken, len(impact.Packages))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,42 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateRemoval(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"src/index.js":        "import { format } from './lib/format';\n\nexport function start() { return format(1); }\n",
+		"src/admin/tool.js":   "import { pad } from '../lib/pad';\n\nexport function runTool() { return pad(2); }\n",
+		"src/lib/format.js":   "import { pad } from './pad';\n\nexport function format(value) { return pad(String(value)); }\n",
+		"src/lib/pad.js":      "export function pad(value) { return value; }\n",
+		"src/unused/stale.js": "export function stale() { return 1; }\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.simulateRemoval(ctx, nil, SimulateRemovalArgs{Path: "src/lib"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Removed:** 2 files | **Broken dependencies:** 2 in 2 packages")
+	assert.Contains(t, text, "## `src` (1 files, 1 dependencies)\n\n- `src/index.js` → `src/lib/format.js` (imports `./lib/format`)\n")
+	assert.Contains(t, text, "## `src/admin` (1 files, 1 dependencies)")
+
+	response, _, err = server.simulateRemoval(ctx, nil, SimulateRemovalArgs{Path: "src/unused/stale.js"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "✅ No remaining code depends on the removed files")
+
+	_, _, err = server.simulateRemoval(ctx, nil, SimulateRemovalArgs{Path: "src/missing"})
+	assert.ErrorContains(t, err, "no analyzed files")
+}
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/coverage"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+type GetStaleCodeArgs struct {
+	Months        int      `json:"months,omitempty"`         // Optional: minimum months since the last change (default 6)
+	MaxReferences int      `json:"max_references,omitempty"` // Optional: maximum referring files and packages (default 0)
+	MaxCoverage   float64  `json:"max_coverage,omitempty"`   // Optional: maximum percent of covered lines (default 20)
+	Coverage      []string `json:"coverage,omitempty"`       // Optional: coverage reports (Go coverprofile, lcov or Cobertura), relative to the target directory
+	Path          string   `json:"path,omitempty"`           // Optional: only files under this path
+	Limit         int      `json:"limit,omitempty"`          // Optional: maximum files listed (default 50)
+	TargetDir     string   `json:"target_dir,omitempty"`     // Optional: directory to analyze
+	Profile       string   `json:"profile,omitempty"`        // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getStaleCode(ctx context.Context, req *mcp.CallToolRequest, args GetStaleCodeArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_stale_code with args: %+v", args)
+	start := time.Now()
+
+	if args.Months <= 0 {
+		args.Months = 6
+	}
+	if args.MaxReferences < 0 {
+		return nil, nil, fmt.Errorf("max_references must not be negative")
+	}
+	if args.MaxCoverage < 0 || args.MaxCoverage > 100 {
+		return nil, nil, fmt.Errorf("max_coverage must be between 0 and 100")
+	}
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var report *coverage.Report
+	if len(args.Coverage) > 0 {
+		paths := make([]string, len(args.Coverage))
+		for i, path := range args.Coverage {
+			path = expandPath(path)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(targetDir, path)
+			}
+			if err := s.sandbox.check(filepath.Dir(path)); err != nil {
+				log.Printf("[MCP] AUDIT: Denied access to %q: %v", args.Coverage[i], err)
+				return nil, nil, err
+			}
+			paths[i] = path
+		}
+		if report, err = coverage.LoadAll(paths); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	// Last changes come from git when available, file modification times otherwise
+	source := "git history"
+	var lastModified map[string]time.Time
+	if repo, err := git.NewGitAnalyzer(targetDir); err == nil && repo.IsGitRepository() {
+		if lastModified, err = repo.GetLastModified(); err != nil {
+			log.Printf("[MCP] WARNING: Failed to read git history, using file times: %v", err)
+			lastModified = nil
+		}
+	}
+	if lastModified == nil {
+		source = "file modification times"
+	}
+
+	opts := analyzer.StaleOptions{
+		MinAgeDays:    args.Months * 30,
+		MaxReferences: args.MaxReferences,
+		MaxCoverage:   args.MaxCoverage,
+	}
+	prefix := strings.Trim(filepath.ToSlash(args.Path), "/")
+	var stale []analyzer.StaleFile
+	for _, file := range analyzer.FindStaleCode(graph, targetDir, lastModified, report, opts) {
+		if prefix == "" || file.File == prefix || strings.HasPrefix(file.File, prefix+"/") {
+			stale = append(stale, file)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Stale Code\n\n")
+	criteria := fmt.Sprintf("unchanged for %d+ months, at most %d references", args.Months, args.MaxReferences)
+	if report != nil || graphHasCoverage(graph) {
+		maxCoverage := args.MaxCoverage
+		if maxCoverage == 0 {
+			maxCoverage = 20
+		}
+		criteria += fmt.Sprintf(", at most %.0f%% line coverage", maxCoverage)
+	}
+	result.WriteString(fmt.Sprintf("**Criteria:** %s | **Dates from:** %s\n\n", criteria, source))
+	if len(stale) == 0 {
+		result.WriteString("_No stale files found_\n")
+	} else {
+		lines := 0
+		for _, file := range stale {
+			lines += file.Lines
+		}
+		result.WriteString(fmt.Sprintf("**Candidates for removal:** %d files, %d lines\n\n", len(stale), lines))
+		result.WriteString("| File | Last changed | Age (days) | References | Coverage | Lines |\n")
+		result.WriteString("|------|--------------|------------|------------|----------|-------|\n")
+		shown := stale
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		for _, file := range shown {
+			covered := "-"
+			if file.Coverage >= 0 {
+				covered = fmt.Sprintf("%.0f%%", file.Coverage)
+			}
+			result.WriteString(fmt.Sprintf("| `%s` | %s | %d | %d | %s | %d |\n",
+				file.File, file.LastModified.Format("2006-01-02"), file.AgeDays, file.References, covered, file.Lines))
+		}
+		if len(shown) < len(stale) {
+			result.WriteString(fmt.Sprintf("\n_... and %d more files_\n", len(stale)-len(shown)))
+		}
+		result.WriteString("\nReferences count other non-test files using the file and packages importing its package. Entry points, tests and generated files are never listed; check for reflection, plugins or external callers before deleting.\n")
+	}
+
+	log.Printf("[MCP] Tool completed: get_stale_code (took %v, %d files)", time.Since(start), len(stale))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// graphHasCoverage reports whether coverage reports were attached during analysis
+func graphHasCoverage(graph *types.CodeGraph) bool {
+	for _, file := range graph.Files {
+		if file.Coverage != nil {
+			return true
+		}
+	}
+	return false
+}
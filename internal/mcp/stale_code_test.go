@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStaleCode(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	tmpDir := t.TempDir()
+	git := func(date string, args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=ana", "-c", "user.email=ana@example.com"}, args...)...)
+		cmd.Dir = tmpDir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		testutils.WriteTree(t, tmpDir, map[string]string{name: content})
+	}
+
+	git("2020-01-01T00:00:00", "init", "-q")
+	write("go.mod", "module example.com/shop\n\ngo 1.22\n")
+	write("main.go", "package main\n\nimport \"example.com/shop/orders\"\n\nfunc main() { orders.New() }\n")
+	write("orders/order.go", "package orders\n\nfunc New() int { return 0 }\n")
+	write("legacy/convert.go", "package legacy\n\nfunc Convert() int { return 1 }\n")
+	write("legacy/export.go", "package legacy\n\nfunc Export() int { return 2 }\n")
+	git("2020-01-01T00:00:00", "add", "-A")
+	git("2020-01-01T00:00:00", "commit", "-q", "-m", "initial")
+	write("legacy/export.go", "package legacy\n\nfunc Export() int { return 3 }\n")
+	git("", "commit", "-q", "-am", "touch export")
+	write("coverage.out", "mode: set\nexample.com/shop/legacy/convert.go:3.24,3.34 1 0\n")
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	response, _, err := server.getStaleCode(context.Background(), nil, GetStaleCodeArgs{Coverage: []string{"coverage.out"}})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "at most 20% line coverage | **Dates from:** git history")
+	assert.Contains(t, text, "**Candidates for removal:** 1 files, 4 lines")
+	assert.Contains(t, text, "| `legacy/convert.go` | 2020-01-01 |")
+	assert.Contains(t, text, "| 0 | 0% | 4 |")
+	assert.NotContains(t, text, "export.go", "recently changed files are not stale")
+	assert.NotContains(t, text, "order.go", "imported packages are not stale")
+
+	response, _, err = server.getStaleCode(context.Background(), nil, GetStaleCodeArgs{Path: "orders", MaxReferences: 1})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "| `orders/order.go` | 2020-01-01 |")
+	assert.NotContains(t, text, "legacy/")
+
+	_, _, err = server.getStaleCode(context.Background(), nil, GetStaleCodeArgs{Coverage: []string{"missing.out"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type GetStateFlowsArgs struct {
+	Store     string `json:"store,omitempty"`      // Optional: only stores whose name or symbol contains this text
+	Library   string `json:"library,omitempty"`    // Optional: redux, pinia, zustand or bloc
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum dispatchers and selectors listed per store (default 20)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getStateFlows(ctx context.Context, req *mcp.CallToolRequest, args GetStateFlowsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_state_flows with args: %+v", args)
+	start := time.Now()
+
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+
+	var stores []analyzer.StateStore
+	for _, store := range analyzer.StateFlows(graph) {
+		if args.Library != "" && !strings.EqualFold(store.Library, args.Library) {
+			continue
+		}
+		if args.Store != "" && !strings.Contains(store.Name, args.Store) && !strings.Contains(store.Symbol, args.Store) {
+			continue
+		}
+		stores = append(stores, store)
+	}
+
+	var result strings.Builder
+	result.WriteString("# State Flows\n\n")
+	if len(stores) == 0 {
+		result.WriteString("_No matching Redux slices, Pinia or Zustand stores, or Bloc and Cubit classes found_\n")
+		log.Printf("[MCP] Tool completed: get_state_flows (took %v, no stores)", time.Since(start))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+		}, nil, nil
+	}
+	result.WriteString(fmt.Sprintf("**Stores:** %d\n\n", len(stores)))
+
+	writeAccesses := func(title string, accesses []analyzer.StateAccess) {
+		if len(accesses) == 0 {
+			return
+		}
+		result.WriteString(fmt.Sprintf("**%s (%d):**\n", title, len(accesses)))
+		for i, access := range accesses {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("- _... and %d more_\n", len(accesses)-i))
+				break
+			}
+			line := fmt.Sprintf("- `%s:%d`", relative(access.File), access.Line)
+			if access.Symbol != "" {
+				line += fmt.Sprintf(" in `%s`", access.Symbol)
+			}
+			if access.Name != "" {
+				line += fmt.Sprintf(" → `%s`", access.Name)
+			}
+			result.WriteString(line + "\n")
+		}
+		result.WriteString("\n")
+	}
+
+	for _, store := range stores {
+		result.WriteString(fmt.Sprintf("## %s (%s %s)\n\n", store.Name, store.Library, store.Kind))
+		result.WriteString(fmt.Sprintf("**Declared:** `%s` at `%s:%d`\n", store.Symbol, relative(store.File), store.Line))
+		if len(store.Actions) > 0 {
+			var names []string
+			for _, action := range store.Actions {
+				names = append(names, "`"+action.Name+"`")
+			}
+			label := "Actions"
+			if store.Kind == "bloc" {
+				label = "Events"
+			}
+			result.WriteString(fmt.Sprintf("**%s:** %s\n", label, strings.Join(names, ", ")))
+		}
+		result.WriteString("\n")
+		if len(store.Dispatchers) == 0 && len(store.Selectors) == 0 {
+			result.WriteString("_Not used outside its declaration_\n\n")
+			continue
+		}
+		writeAccesses("Dispatched from", store.Dispatchers)
+		writeAccesses("Selected by", store.Selectors)
+	}
+
+	log.Printf("[MCP] Tool completed: get_state_flows (took %v, %d stores)", time.Since(start), len(stores))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
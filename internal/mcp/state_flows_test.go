@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStateFlows(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"src/todos.ts": "import { createSlice } from '@reduxjs/toolkit';\n\nexport const todosSlice = createSlice({\n  name: 'todos',\n  initialState: [],\n  reducers: {\n    added(state, action) {\n      state.push(action.payload);\n    },\n  },\n});\n",
+		"src/TodoList.tsx": "import { useDispatch, useSelector } from 'react-redux';\nimport { todosSlice } from './todos';\n\n" +
+			"export function TodoList() {\n  const dispatch = useDispatch();\n  const todos = useSelector((state) => state.todos);\n  return <button onClick={() => dispatch(todosSlice.actions.added('x'))}>{todos.length}</button>;\n}\n",
+		"src/store/session.ts": "import { create } from 'zustand';\n\nexport const useSession = create((set) => ({\n  token: null,\n  logout: () => set({ token: null }),\n}));\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getStateFlows(ctx, nil, GetStateFlowsArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Stores:** 2")
+	assert.Contains(t, text, "## todos (redux slice)\n\n**Declared:** `todosSlice` at `src/todos.ts:3`\n**Actions:** `added`")
+	assert.Contains(t, text, "**Dispatched from (1):**\n- `src/TodoList.tsx:7` in `TodoList` → `added`")
+	assert.Contains(t, text, "**Selected by (1):**\n- `src/TodoList.tsx:6` in `TodoList` → `todos`")
+	assert.Contains(t, text, "## useSession (zustand store)")
+	assert.Contains(t, text, "_Not used outside its declaration_")
+
+	response, _, err = server.getStateFlows(ctx, nil, GetStateFlowsArgs{Library: "zustand"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Stores:** 1")
+	assert.NotContains(t, text, "## todos")
+
+	response, _, err = server.getStateFlows(ctx, nil, GetStateFlowsArgs{Store: "cart"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "_No matching")
+}
@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetServerStatusArgs struct {
+	Format string `json:"format,omitempty"` // Optional: "markdown" (default) or "json"
+}
+
+// serverStatus is what get_server_status reports
+type serverStatus struct {
+	Name              string          `json:"name"`
+	Version           string          `json:"version"`
+	Ready             bool            `json:"ready"` // A graph is loaded, so calls avoid a cold analysis
+	StartedAt         time.Time       `json:"started_at"`
+	Uptime            string          `json:"uptime"`
+	TargetDir         string          `json:"target_dir"`
+	ReadOnly          bool            `json:"read_only"`
+	Graph             *graphStatus    `json:"graph,omitempty"`
+	Analyses          analysisStatus  `json:"analyses"`
+	Watcher           watcherStatus   `json:"watcher"`
+	Sessions          int             `json:"sessions"`        // Sessions with remembered files
+	SemanticCached    int             `json:"semantic_cached"` // Semantic analyses cached by directory and profile
+	ResultCache       cacheStatus     `json:"result_cache"`
+	ToolCallsInFlight int             `json:"tool_calls_in_flight"`
+	Snapshot          *snapshotStatus `json:"snapshot,omitempty"`
+}
+
+type graphStatus struct {
+	TargetDir    string         `json:"target_dir"`
+	Source       string         `json:"source"` // "analysis" or "snapshot"
+	Profile      string         `json:"profile,omitempty"`
+	AnalyzedAt   time.Time      `json:"analyzed_at"`
+	AnalysisTime string         `json:"analysis_time,omitempty"`
+	Files        int            `json:"files"`
+	Symbols      int            `json:"symbols"`
+	Languages    map[string]int `json:"languages,omitempty"`
+}
+
+type analysisStatus struct {
+	Running       int `json:"running"`
+	Queued        int `json:"queued"`
+	MaxConcurrent int `json:"max_concurrent"`
+	MaxQueued     int `json:"max_queued"`
+	RateLimit     int `json:"rate_limit_per_minute"`
+}
+
+type watcherStatus struct {
+	Active    bool   `json:"active"`
+	TargetDir string `json:"target_dir,omitempty"`
+}
+
+type cacheStatus struct {
+	Entries int `json:"entries"`
+	Hits    int `json:"hits"`
+	Misses  int `json:"misses"`
+}
+
+type snapshotStatus struct {
+	Path    string     `json:"path"`
+	Exists  bool       `json:"exists"`
+	Size    int64      `json:"size,omitempty"`
+	SavedAt *time.Time `json:"saved_at,omitempty"`
+}
+
+// status collects the server state without triggering an analysis
+func (s *CodeContextMCPServer) status() serverStatus {
+	config := s.currentConfig()
+	status := serverStatus{
+		Name:      config.Name,
+		Version:   config.Version,
+		StartedAt: s.startedAt,
+		Uptime:    time.Since(s.startedAt).Round(time.Second).String(),
+		TargetDir: config.TargetDir,
+		ReadOnly:  config.ReadOnly,
+		Sessions:  s.memory.count(),
+	}
+	status.ToolCallsInFlight = s.calls.inFlight()
+	status.SemanticCached = s.semantic.count()
+	entries, hits, misses := s.results.stats()
+	status.ResultCache = cacheStatus{Entries: entries, Hits: hits, Misses: misses}
+
+	s.graphMu.Lock()
+	if graph := s.graph; graph != nil {
+		status.Ready = true
+		status.Graph = &graphStatus{
+			TargetDir:  s.graphDir,
+			Source:     s.graphSource,
+			Profile:    string(s.graphProfile),
+			AnalyzedAt: s.analyzedAt,
+			Files:      len(graph.Files),
+			Symbols:    len(graph.Symbols),
+		}
+		if s.analysisTime > 0 {
+			status.Graph.AnalysisTime = s.analysisTime.Round(time.Millisecond).String()
+		}
+		if graph.Metadata != nil {
+			status.Graph.Languages = graph.Metadata.Languages
+		}
+	}
+	s.graphMu.Unlock()
+
+	running, queued := s.limiter.stats()
+	status.Analyses = analysisStatus{
+		Running:       running,
+		Queued:        queued,
+		MaxConcurrent: cap(s.limiter.slots),
+		MaxQueued:     s.limiter.maxQueued,
+		RateLimit:     s.limiter.ratePerMinute,
+	}
+
+	s.stopMutex.RLock()
+	status.Watcher = watcherStatus{Active: s.watcher != nil}
+	if s.watcher != nil {
+		status.Watcher.TargetDir = s.watchDir
+	}
+	s.stopMutex.RUnlock()
+
+	if path := config.SnapshotPath; path != "" {
+		status.Snapshot = &snapshotStatus{Path: path}
+		if info, err := os.Stat(path); err == nil {
+			savedAt := info.ModTime()
+			status.Snapshot.Exists = true
+			status.Snapshot.Size = info.Size()
+			status.Snapshot.SavedAt = &savedAt
+		}
+	}
+	return status
+}
+
+// Healthz answers 503 until a graph is loaded, by the first analysis or from
+// the snapshot, and 200 after, for readiness probes on the pprof address
+func (s *CodeContextMCPServer) Healthz(w http.ResponseWriter, r *http.Request) {
+	s.graphMu.Lock()
+	ready := s.graph != nil
+	s.graphMu.Unlock()
+	if !ready {
+		http.Error(w, "warming up", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+func (s *CodeContextMCPServer) getServerStatus(ctx context.Context, req *mcp.CallToolRequest, args GetServerStatusArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_server_status with args: %+v", args)
+	start := time.Now()
+
+	status := s.status()
+	var text string
+	switch args.Format {
+	case "", "markdown":
+		text = formatServerStatus(status)
+	case "json":
+		data, err := json.MarshalIndent(status, "", "  ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode status: %w", err)
+		}
+		text = string(data)
+	default:
+		return nil, nil, fmt.Errorf("unknown format %q (use markdown or json)", args.Format)
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_server_status (took %v)", elapsed)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
+
+func formatServerStatus(status serverStatus) string {
+	var b strings.Builder
+	b.WriteString("# Server Status\n\n")
+	state := "warming up (no graph loaded yet)"
+	if status.Ready {
+		state = "ready"
+	}
+	fmt.Fprintf(&b, "- **Status**: %s\n", state)
+	fmt.Fprintf(&b, "- **Server**: %s %s\n", status.Name, status.Version)
+	fmt.Fprintf(&b, "- **Uptime**: %s (since %s)\n", status.Uptime, status.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Target**: %s\n", status.TargetDir)
+	if status.ReadOnly {
+		b.WriteString("- **Read-only**: yes\n")
+	}
+
+	if g := status.Graph; g != nil {
+		b.WriteString("\n## Loaded Graph\n\n")
+		fmt.Fprintf(&b, "- **Directory**: %s\n", g.TargetDir)
+		fmt.Fprintf(&b, "- **Files**: %d, **Symbols**: %d\n", g.Files, g.Symbols)
+		if g.Profile != "" {
+			fmt.Fprintf(&b, "- **Profile**: %s\n", g.Profile)
+		}
+		fmt.Fprintf(&b, "- **Last analysis**: %s (%s", g.AnalyzedAt.Format(time.RFC3339), g.Source)
+		if g.AnalysisTime != "" {
+			fmt.Fprintf(&b, ", took %s", g.AnalysisTime)
+		}
+		b.WriteString(")\n")
+	}
+
+	a := status.Analyses
+	b.WriteString("\n## Activity\n\n")
+	fmt.Fprintf(&b, "- **Analyses**: %d running, %d queued (limits %d/%d)\n", a.Running, a.Queued, a.MaxConcurrent, a.MaxQueued)
+	fmt.Fprintf(&b, "- **Tool calls in flight**: %d\n", status.ToolCallsInFlight)
+	if a.RateLimit > 0 {
+		fmt.Fprintf(&b, "- **Rate limit**: %d calls per session per minute\n", a.RateLimit)
+	}
+	fmt.Fprintf(&b, "- **Sessions with memory**: %d\n", status.Sessions)
+	fmt.Fprintf(&b, "- **Cached semantic analyses**: %d\n", status.SemanticCached)
+	rc := status.ResultCache
+	fmt.Fprintf(&b, "- **Cached tool results**: %d (%d hits, %d misses)\n", rc.Entries, rc.Hits, rc.Misses)
+	if status.Watcher.Active {
+		fmt.Fprintf(&b, "- **Watcher**: active on %s\n", status.Watcher.TargetDir)
+	} else {
+		b.WriteString("- **Watcher**: inactive\n")
+	}
+
+	if snap := status.Snapshot; snap != nil {
+		b.WriteString("\n## Snapshot\n\n")
+		fmt.Fprintf(&b, "- **Path**: %s\n", snap.Path)
+		if snap.Exists {
+			fmt.Fprintf(&b, "- **Saved**: %s (%d bytes)\n", snap.SavedAt.Format(time.RFC3339), snap.Size)
+		} else {
+			b.WriteString("- **Saved**: not yet (written on shutdown)\n")
+		}
+	}
+	return b.String()
+}
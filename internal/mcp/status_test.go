@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServerStatus(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = createTestDirectory(t)
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	status := server.status()
+	assert.False(t, status.Ready)
+	assert.Nil(t, status.Graph)
+
+	require.NoError(t, server.refreshAnalysis())
+	result, _, err := server.getServerStatus(ctx, nil, GetServerStatusArgs{Format: "json"})
+	require.NoError(t, err)
+	var reported serverStatus
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &reported))
+	assert.True(t, reported.Ready)
+	require.NotNil(t, reported.Graph)
+	assert.Equal(t, config.TargetDir, reported.Graph.TargetDir)
+	assert.Equal(t, "analysis", reported.Graph.Source)
+	assert.Equal(t, len(server.graph.Files), reported.Graph.Files)
+	assert.Equal(t, 1, reported.Analyses.MaxConcurrent)
+	assert.False(t, reported.Watcher.Active)
+
+	result, _, err = server.getServerStatus(ctx, nil, GetServerStatusArgs{})
+	require.NoError(t, err)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Status**: ready")
+	assert.Contains(t, text, "## Loaded Graph")
+
+	_, _, err = server.getServerStatus(ctx, nil, GetServerStatusArgs{Format: "yaml"})
+	assert.Error(t, err)
+}
+
+func TestHealthz(t *testing.T) {
+	config := createTestConfig()
+	config.TargetDir = createTestDirectory(t)
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.Healthz(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "no graph before the first analysis")
+
+	require.NoError(t, server.refreshAnalysis())
+	recorder = httptest.NewRecorder()
+	server.Healthz(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ok\n", recorder.Body.String())
+}
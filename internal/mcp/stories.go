@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+type GetStoriesArgs struct {
+	Component string `json:"component,omitempty"`  // Optional: only stories of components whose name contains this text
+	FilePath  string `json:"file_path,omitempty"`  // Optional: only stories of components in files whose path contains this text
+	Unstoried bool   `json:"unstoried,omitempty"`  // Optional: only list the components without stories
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum stories files and components listed (default 50)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getStories(ctx context.Context, req *mcp.CallToolRequest, args GetStoriesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_stories with args: %+v", args)
+	start := time.Now()
+
+	if args.Limit <= 0 {
+		args.Limit = 50
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+	matches := func(name, file string) bool {
+		return (args.Component == "" || strings.Contains(name, args.Component)) &&
+			(args.FilePath == "" || strings.Contains(relative(file), args.FilePath))
+	}
+
+	var result strings.Builder
+	result.WriteString("# Storybook Stories\n\n")
+	report := analyzer.FindStories(graph)
+	if report == nil {
+		result.WriteString("_No *.stories.* files found_\n")
+		log.Printf("[MCP] Tool completed: get_stories (took %v, no stories)", time.Since(start))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+		}, nil, nil
+	}
+
+	var files []analyzer.StoryFile
+	for _, story := range report.Files {
+		// file_path also matches the stories file itself
+		if !args.Unstoried && (matches(story.Component, story.ComponentFile) || matches(story.Component, story.File)) {
+			files = append(files, story)
+		}
+	}
+	var unstoried []analyzer.StoryComponent
+	for _, component := range report.Unstoried {
+		if matches(component.Name, component.File) {
+			unstoried = append(unstoried, component)
+		}
+	}
+	result.WriteString(fmt.Sprintf("**Stories files:** %d | **Components:** %d | **Without stories:** %d\n\n", len(report.Files), report.Components, len(report.Unstoried)))
+
+	if !args.Unstoried {
+		if len(files) == 0 {
+			result.WriteString("_No matching stories files_\n\n")
+		}
+		for i, story := range files {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("_... and %d more stories files_\n\n", len(files)-i))
+				break
+			}
+			title := story.Title
+			if title == "" {
+				title = relative(story.File)
+			}
+			result.WriteString(fmt.Sprintf("## %s\n\n", title))
+			result.WriteString(fmt.Sprintf("**File:** `%s`\n", relative(story.File)))
+			switch {
+			case story.ComponentFile != "":
+				result.WriteString(fmt.Sprintf("**Component:** `%s` (`%s`)\n", story.Component, relative(story.ComponentFile)))
+			case story.Component != "":
+				result.WriteString(fmt.Sprintf("**Component:** `%s` _(not found in the project)_\n", story.Component))
+			default:
+				result.WriteString("**Component:** _unknown_\n")
+			}
+			if len(story.Stories) == 0 {
+				result.WriteString("_No stories exported_\n\n")
+				continue
+			}
+			result.WriteString("\n| Story | Line | Id |\n|-------|------|----|\n")
+			for _, example := range story.Stories {
+				id := "-"
+				if example.Id != "" {
+					id = "`" + example.Id + "`"
+				}
+				result.WriteString(fmt.Sprintf("| %s | %d | %s |\n", example.Name, example.Line, id))
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	if len(unstoried) > 0 {
+		result.WriteString(fmt.Sprintf("## Components Without Stories (%d)\n\n", len(unstoried)))
+		for i, component := range unstoried {
+			if i == args.Limit {
+				result.WriteString(fmt.Sprintf("_... and %d more_\n", len(unstoried)-i))
+				break
+			}
+			result.WriteString(fmt.Sprintf("- `%s` (`%s:%d`)\n", component.Name, relative(component.File), component.Line))
+		}
+	} else if args.Unstoried {
+		result.WriteString("_Every matching component has stories_\n")
+	}
+
+	log.Printf("[MCP] Tool completed: get_stories (took %v, %d stories files, %d without stories)", time.Since(start), len(files), len(unstoried))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// storiesFor returns the stories files documenting a component declared in
+// a file, each with the names of its stories
+func storiesFor(graph *types.CodeGraph, file, component, targetDir string) []string {
+	node := types.NodeId("file-" + file)
+	var stories []string
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipStories) || edge.To != node || edge.Metadata["component"] != component {
+			continue
+		}
+		storyFile := strings.TrimPrefix(string(edge.From), "file-")
+		if rel, err := filepath.Rel(targetDir, storyFile); err == nil && !strings.HasPrefix(rel, "..") {
+			storyFile = filepath.ToSlash(rel)
+		}
+		entry := "`" + storyFile + "`"
+		if names, _ := edge.Metadata["stories"].(string); names != "" {
+			entry += " (" + names + ")"
+		}
+		stories = append(stories, entry)
+	}
+	sort.Strings(stories)
+	return stories
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStories(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"src/Button.tsx":         "export function Button() {\n  return <button />;\n}\n",
+		"src/Button.stories.tsx": "import { Button } from './Button';\n\nexport default {\n  title: 'Forms/Button',\n  component: Button,\n};\n\nexport const Primary = {};\n",
+		"src/Modal.tsx":          "export const Modal = () => <div />;\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getStories(ctx, nil, GetStoriesArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Stories files:** 1 | **Components:** 2 | **Without stories:** 1")
+	assert.Contains(t, text, "## Forms/Button\n\n**File:** `src/Button.stories.tsx`\n**Component:** `Button` (`src/Button.tsx`)")
+	assert.Contains(t, text, "| Primary | 8 | `forms-button--primary` |")
+	assert.Contains(t, text, "- `Modal` (`src/Modal.tsx:1`)")
+
+	response, _, err = server.getStories(ctx, nil, GetStoriesArgs{Unstoried: true})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.NotContains(t, text, "## Forms/Button")
+	assert.Contains(t, text, "`Modal`")
+
+	// get_symbol_info points at the stories for usage examples
+	response, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "Button"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Stories:** `src/Button.stories.tsx` (Primary)")
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+type FindStringOriginArgs struct {
+	Text      string `json:"text"`                 // Log line, error message or route path to trace
+	Kind      string `json:"kind,omitempty"`       // Optional: only log, error, route or string literals
+	Limit     int    `json:"limit,omitempty"`      // Optional: maximum results (default 20)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) findStringOrigin(ctx context.Context, req *mcp.CallToolRequest, args FindStringOriginArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: find_string_origin with args: %+v", args)
+	start := time.Now()
+
+	if strings.TrimSpace(args.Text) == "" {
+		return nil, nil, fmt.Errorf("text is required")
+	}
+	switch args.Kind {
+	case "", analyzer.StringKindLog, analyzer.StringKindError, analyzer.StringKindRoute, analyzer.StringKindString:
+	default:
+		return nil, nil, fmt.Errorf("unknown string kind %q (use log, error, route or string)", args.Kind)
+	}
+	if args.Limit <= 0 {
+		args.Limit = 20
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Repeat calls return the rendered response until the watcher sees a change
+	cache := s.cachedCallFor("find_string_origin", args, targetDir)
+	if result, ok := cache.result(); ok {
+		log.Printf("[MCP] Tool completed: find_string_origin (cached, took %v)", time.Since(start))
+		return result, nil, nil
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	index := analyzer.BuildStringIndex(graph)
+	matches := index.Find(args.Text, args.Kind)
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# String Origin: '%s'\n\n", strings.TrimSpace(args.Text)))
+	if len(matches) == 0 {
+		result.WriteString(fmt.Sprintf("_No string literal matches among %d indexed literals_\n", len(index.Literals)))
+		result.WriteString("\nOnly Go, JavaScript, TypeScript, Python, Java and Kotlin sources outside tests are indexed. Try a shorter, distinctive part of the message.\n")
+	} else {
+		shown := matches
+		if len(shown) > args.Limit {
+			shown = shown[:args.Limit]
+		}
+		result.WriteString(fmt.Sprintf("Found %d matches", len(matches)))
+		if len(shown) < len(matches) {
+			result.WriteString(fmt.Sprintf(" (showing %d)", len(shown)))
+		}
+		result.WriteString(":\n\n")
+		for _, match := range shown {
+			file := match.File
+			if rel, err := filepath.Rel(targetDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+				file = rel
+			}
+			result.WriteString(fmt.Sprintf("- %s:%d", file, match.Line))
+			if match.Symbol != "" {
+				result.WriteString(fmt.Sprintf(" in `%s`", match.Symbol))
+			}
+			result.WriteString(fmt.Sprintf(" (%s, %s match) — `\"%s\"`\n", match.Kind, match.Match, match.Value))
+		}
+	}
+
+	cache.store(result.String())
+
+	log.Printf("[MCP] Tool completed: find_string_origin (took %v, %d matches)", time.Since(start), len(matches))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStringOrigin(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "package billing\n\nfunc Charge(id int) error {\n\tlog.Printf(\"charging invoice %d\", id)\n\treturn fmt.Errorf(\"invoice %d is already paid\", id)\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "billing.go"), []byte(source), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.findStringOrigin(ctx, nil, FindStringOriginArgs{Text: "invoice 1207 is already paid"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "# String Origin: 'invoice 1207 is already paid'")
+	assert.Contains(t, text, "- billing.go:5 in `Charge` (error, format match)")
+	assert.NotContains(t, text, "charging invoice")
+
+	response, _, err = server.findStringOrigin(ctx, nil, FindStringOriginArgs{Text: "invoice", Kind: "error"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Found 1 matches")
+	assert.NotContains(t, text, "charging invoice")
+
+	response, _, err = server.findStringOrigin(ctx, nil, FindStringOriginArgs{Text: "refund rejected"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "No string literal matches")
+
+	_, _, err = server.findStringOrigin(ctx, nil, FindStringOriginArgs{})
+	assert.ErrorContains(t, err, "text is required")
+	_, _, err = server.findStringOrigin(ctx, nil, FindStringOriginArgs{Text: "x", Kind: "metric"})
+	assert.ErrorContains(t, err, `unknown string kind "metric"`)
+}
@@ -0,0 +1,149 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+)
+
+type SuggestRefactoringsArgs struct {
+	Path       string `json:"path,omitempty"`        // Optional: file or directory to limit suggestions to, relative to the target directory
+	MinSymbols int    `json:"min_symbols,omitempty"` // Optional: symbols a file needs to be reported (default 20)
+	MinMethods int    `json:"min_methods,omitempty"` // Optional: methods a class needs to be reported (default 15)
+	Limit      int    `json:"limit,omitempty"`       // Optional: maximum candidates listed (default 10)
+	TargetDir  string `json:"target_dir,omitempty"`  // Optional: directory to analyze
+	Profile    string `json:"profile,omitempty"`     // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) suggestRefactorings(ctx context.Context, req *mcp.CallToolRequest, args SuggestRefactoringsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: suggest_refactorings with args: %+v", args)
+	start := time.Now()
+
+	if args.Limit <= 0 {
+		args.Limit = 10
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	// Co-change comes from blame and the semantic neighborhoods when the
+	// target is a git repository
+	opts := analyzer.RefactorOptions{MinSymbols: args.MinSymbols, MinMethods: args.MinMethods}
+	if repo, err := git.NewGitAnalyzer(targetDir); err == nil {
+		opts.Blame = func(path string) []string {
+			rel, err := filepath.Rel(targetDir, path)
+			if err != nil {
+				return nil
+			}
+			commits, err := repo.BlameLines(ctx, filepath.ToSlash(rel))
+			if err != nil {
+				return nil
+			}
+			return commits
+		}
+		profile, err := analyzer.ParseProfile(s.currentConfig().Profile)
+		if err != nil {
+			profile = analyzer.ProfileBalanced
+		}
+		if semanticData, err := s.semanticNeighborhoods(targetDir, graph, profile); err == nil {
+			for _, neighborhood := range semanticData.SemanticNeighborhoods {
+				opts.Neighborhoods = append(opts.Neighborhoods, neighborhood.Files)
+			}
+		} else {
+			log.Printf("[MCP] Skipping change neighborhoods: %v", err)
+		}
+	}
+
+	candidates := analyzer.SuggestRefactorings(graph, targetDir, opts)
+	if args.Path != "" {
+		scope := filepath.ToSlash(filepath.Clean(args.Path))
+		var scoped []analyzer.RefactoringCandidate
+		for _, candidate := range candidates {
+			if scope == "." || candidate.File == scope || strings.HasPrefix(candidate.File, scope+"/") {
+				scoped = append(scoped, candidate)
+			}
+		}
+		candidates = scoped
+	}
+
+	var result strings.Builder
+	result.WriteString("# Refactoring Suggestions\n\n")
+	if len(candidates) == 0 {
+		result.WriteString("✅ No god-files or god-classes found\n")
+		log.Printf("[MCP] Tool completed: suggest_refactorings (took %v, no candidates)", time.Since(start))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+		}, nil, nil
+	}
+	splits := 0
+	for _, candidate := range candidates {
+		if len(candidate.Groups) > 0 {
+			splits++
+		}
+	}
+	result.WriteString(fmt.Sprintf("**Candidates:** %d | **With a suggested split:** %d\n\n", len(candidates), splits))
+
+	shown := candidates
+	if len(shown) > args.Limit {
+		shown = shown[:args.Limit]
+	}
+	for _, candidate := range shown {
+		if candidate.Kind == "class" {
+			result.WriteString(fmt.Sprintf("## Class `%s` (`%s:%d`)\n\n", candidate.Class, candidate.File, candidate.Line))
+			result.WriteString(fmt.Sprintf("**Methods:** %d | **Lines:** %d", candidate.Symbols, candidate.Lines))
+		} else {
+			result.WriteString(fmt.Sprintf("## File `%s`\n\n", candidate.File))
+			result.WriteString(fmt.Sprintf("**Symbols:** %d | **Lines:** %d", candidate.Symbols, candidate.Lines))
+		}
+		result.WriteString(fmt.Sprintf(" | **Fan-in:** %d | **Fan-out:** %d | **Change neighborhoods:** %d\n\n", candidate.FanIn, candidate.FanOut, candidate.Neighborhoods))
+		if len(candidate.Groups) == 0 {
+			result.WriteString("_No split boundary found: its symbols are used and changed together._\n\n")
+			continue
+		}
+		result.WriteString("Suggested split:\n\n")
+		for i, group := range candidate.Groups {
+			result.WriteString(fmt.Sprintf("%d. Around `%s` (%d symbols, %d lines): %s\n", i+1, group.Anchor, len(group.Symbols), group.Lines, truncatedBacktickList(group.Symbols, 8)))
+			if len(group.Dependents) > 0 {
+				result.WriteString(fmt.Sprintf("   - Used by %d files: %s\n", len(group.Dependents), truncatedBacktickList(group.Dependents, 5)))
+			}
+		}
+		if len(candidate.Ungrouped) > 0 {
+			result.WriteString(fmt.Sprintf("\nNot tied to a group: %s\n", truncatedBacktickList(candidate.Ungrouped, 8)))
+		}
+		result.WriteString("\n")
+	}
+	if len(shown) < len(candidates) {
+		result.WriteString(fmt.Sprintf("_... and %d more candidates_\n\n", len(candidates)-len(shown)))
+	}
+	result.WriteString("_Symbols are grouped by naming each other, sharing attributes, being used by the same files and being last changed by the same commits._\n")
+
+	log.Printf("[MCP] Tool completed: suggest_refactorings (took %v, %d candidates, %d with a split)", time.Since(start), len(candidates), splits)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
+
+// truncatedBacktickList is backtickList showing at most limit values
+func truncatedBacktickList(values []string, limit int) string {
+	if len(values) <= limit {
+		return backtickList(values)
+	}
+	return fmt.Sprintf("%s and %d more", backtickList(values[:limit]), len(values)-limit)
+}
@@ -0,0 +1,46 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestRefactorings(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"src/utils.js": "export function parseHeader(text) {\n  return splitFields(text);\n}\n\n" +
+			"export function splitFields(text) {\n  return text.split(',');\n}\n\n" +
+			"export function sendMail(to) {\n  return formatMail(to);\n}\n\n" +
+			"export function formatMail(to) {\n  return to;\n}\n\n" +
+			"export function retry(fn) {\n  return fn();\n}\n",
+		"src/reader.js": "import { parseHeader } from './utils';\n\nexport function read(text) { return parseHeader(text); }\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.suggestRefactorings(ctx, nil, SuggestRefactoringsArgs{MinSymbols: 5})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Candidates:** 1 | **With a suggested split:** 1")
+	assert.Contains(t, text, "## File `src/utils.js`\n\n**Symbols:** 5 | **Lines:** 19 | **Fan-in:** 1 | **Fan-out:** 0 | **Change neighborhoods:** 0\n")
+	assert.Contains(t, text, "1. Around `parseHeader` (2 symbols, 6 lines): `parseHeader`, `splitFields`\n   - Used by 1 files: `src/reader.js`\n")
+	assert.Contains(t, text, "2. Around `sendMail` (2 symbols, 6 lines): `sendMail`, `formatMail`\n")
+	assert.Contains(t, text, "Not tied to a group: `retry`")
+
+	response, _, err = server.suggestRefactorings(ctx, nil, SuggestRefactoringsArgs{MinSymbols: 5, Path: "lib"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "✅ No god-files or god-classes found")
+
+	response, _, err = server.suggestRefactorings(ctx, nil, SuggestRefactoringsArgs{})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "✅ No god-files or god-classes found")
+}
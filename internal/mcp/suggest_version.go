@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/apidiff"
+)
+
+type SuggestVersionArgs struct {
+	From      string `json:"from"`                 // Git ref of the last release: tag, branch or commit
+	To        string `json:"to,omitempty"`         // Optional: newer git ref (default: the working tree)
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) suggestVersion(ctx context.Context, req *mcp.CallToolRequest, args SuggestVersionArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: suggest_version with args: %+v", args)
+	start := time.Now()
+
+	if args.From == "" {
+		return nil, nil, fmt.Errorf("from is required")
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := s.resolveProfile(args.Profile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Both revisions are analyzed from git, independently of the live graph
+	report, err := apidiff.SuggestVersions(ctx, targetDir, args.From, args.To, s.analyzeWith(profile))
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to suggest versions: %v", err)
+		return nil, nil, err
+	}
+
+	log.Printf("[MCP] Tool completed: suggest_version (took %v, %d modules changed)", time.Since(start), len(report.Suggestions))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: report.Markdown()}},
+	}, nil, nil
+}
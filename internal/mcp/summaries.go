@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/summarize"
+)
+
+type RegenerateSummariesArgs struct {
+	Files     []string `json:"files,omitempty"`      // Optional: project-relative files (default all)
+	TargetDir string   `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string   `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) regenerateSummaries(ctx context.Context, req *mcp.CallToolRequest, args RegenerateSummariesArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: regenerate_summaries with args: %+v", args)
+	start := time.Now()
+
+	config := s.currentConfig()
+	if config.ReadOnly {
+		log.Printf("[MCP] AUDIT: Denied regenerate_summaries in read-only mode")
+		return nil, nil, fmt.Errorf("summaries cannot be regenerated: server is read-only")
+	}
+	if provider := config.Summaries.Provider; provider == "" || provider == summarize.ProviderNone {
+		return nil, nil, fmt.Errorf("summaries are not configured: set a provider under \"summaries\" in the config")
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	files := make([]string, 0, len(args.Files))
+	for _, file := range args.Files {
+		if filepath.IsAbs(file) {
+			if rel, err := filepath.Rel(targetDir, file); err == nil {
+				file = rel
+			}
+		}
+		files = append(files, file)
+	}
+
+	dropped, err := summarize.Invalidate(targetDir, files)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.results.invalidate()
+	s.dropSnapshot()
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var result strings.Builder
+	result.WriteString("# Summaries Regenerated\n\n")
+	result.WriteString(fmt.Sprintf("**Dropped:** %d cached summaries\n", dropped))
+	if stats, ok := graph.Metadata.Configuration["summary_stats"].(summarize.Stats); ok {
+		result.WriteString(fmt.Sprintf("**Generated:** %d — **Cached:** %d — **Pending:** %d\n", stats.Generated, stats.Cached, stats.Pending))
+	}
+	if errs, ok := graph.Metadata.Configuration["summary_errors"].([]string); ok {
+		for _, err := range errs {
+			result.WriteString(fmt.Sprintf("\n⚠️ %s\n", err))
+		}
+	}
+
+	if len(files) > 0 {
+		var lines []string
+		for path, file := range graph.Files {
+			rel, err := filepath.Rel(targetDir, path)
+			if err != nil {
+				rel = path
+			}
+			for _, requested := range files {
+				if filepath.Clean(requested) == rel && file.Summary != "" {
+					lines = append(lines, fmt.Sprintf("- `%s`: %s\n", filepath.ToSlash(rel), file.Summary))
+				}
+			}
+		}
+		sort.Strings(lines)
+		if len(lines) > 0 {
+			result.WriteString("\n## Summaries\n\n")
+			result.WriteString(strings.Join(lines, ""))
+		}
+	}
+
+	log.Printf("[MCP] Tool completed: regenerate_summaries (took %v, dropped %d)", time.Since(start), dropped)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
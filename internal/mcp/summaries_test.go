@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/summarize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegenerateSummaries(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "shop.go"), []byte("package shop\n\nfunc Price() int { return 1 }\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, _, err = server.regenerateSummaries(ctx, nil, RegenerateSummariesArgs{})
+	assert.ErrorContains(t, err, "summaries are not configured")
+
+	config = createTestConfig()
+	config.TargetDir = tmpDir
+	config.Summaries = summarize.Config{Provider: summarize.ProviderCommand, Command: "sh", Args: []string{"-c", "cat >/dev/null; echo Prices products."}}
+	server, err = NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	response, _, err := server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: filepath.Join(tmpDir, "shop.go")})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Summary:** Prices products.")
+
+	response, _, err = server.regenerateSummaries(ctx, nil, RegenerateSummariesArgs{Files: []string{"shop.go"}})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Dropped:** 1 cached summaries")
+	assert.Contains(t, text, "**Generated:** 1 — **Cached:** 0 — **Pending:** 0")
+	assert.Contains(t, text, "- `shop.go`: Prices products.")
+
+	server.config.ReadOnly = true
+	_, _, err = server.regenerateSummaries(ctx, nil, RegenerateSummariesArgs{})
+	assert.ErrorContains(t, err, "server is read-only")
+}
+
+func TestReadOnlySummaries(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "shop.go"), []byte("package shop\n\nfunc Price() int { return 1 }\n"), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	config.ReadOnly = true
+	config.Summaries = summarize.Config{Provider: summarize.ProviderCommand, Command: "sh", Args: []string{"-c", "cat >/dev/null; echo Prices products."}}
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+
+	response, _, err := server.getFileAnalysis(context.Background(), nil, GetFileAnalysisArgs{FilePath: filepath.Join(tmpDir, "shop.go")})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "**Summary:**")
+	assert.NoDirExists(t, filepath.Join(tmpDir, ".codecontext"), "a read-only server writes no summaries cache")
+}
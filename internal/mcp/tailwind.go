@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+)
+
+// maxTailwindListed bounds each list of the Tailwind section
+const maxTailwindListed = 15
+
+// tailwindSection renders the Tailwind part of the framework analysis: the
+// most used design tokens and the tokens and custom classes nothing uses
+func tailwindSection(report *analyzer.TailwindReport) string {
+	var section strings.Builder
+	section.WriteString("## 🎨 Tailwind CSS\n\n")
+	section.WriteString(fmt.Sprintf("**Config:** `%s` — %d design tokens, %d custom classes, %d files scanned\n\n",
+		report.Config, len(report.Tokens), len(report.Utilities), report.Files))
+
+	var used []analyzer.TailwindToken
+	for _, token := range report.Tokens {
+		if token.Uses > 0 {
+			used = append(used, token)
+		}
+	}
+	if len(used) > 0 {
+		section.WriteString("### Most Used Design Tokens\n\n")
+		section.WriteString("| Token | Section | Value | Uses | Files |\n")
+		section.WriteString("|-------|---------|-------|------|-------|\n")
+		for i, token := range used {
+			if i == maxTailwindListed {
+				break
+			}
+			section.WriteString(fmt.Sprintf("| `%s` | %s | `%s` | %d | %d |\n", token.Name, token.Section, token.Value, token.Uses, token.Files))
+		}
+		section.WriteString("\n")
+	}
+
+	tokens, utilities := report.Unused()
+	if len(tokens) > 0 {
+		section.WriteString(fmt.Sprintf("### Unused Design Tokens (%d)\n\n", len(tokens)))
+		for i, token := range tokens {
+			if i == maxTailwindListed {
+				section.WriteString(fmt.Sprintf("- _... and %d more_\n", len(tokens)-i))
+				break
+			}
+			origin := "theme"
+			if token.Extended {
+				origin = "extend"
+			}
+			section.WriteString(fmt.Sprintf("- `%s` (%s, %s)\n", token.Name, token.Section, origin))
+		}
+		section.WriteString("\n")
+	}
+	if len(utilities) > 0 {
+		section.WriteString(fmt.Sprintf("### Unused Custom Classes (%d)\n\n", len(utilities)))
+		for i, utility := range utilities {
+			if i == maxTailwindListed {
+				section.WriteString(fmt.Sprintf("- _... and %d more_\n", len(utilities)-i))
+				break
+			}
+			section.WriteString(fmt.Sprintf("- `%s` — %s (`%s:%d`)\n", utility.Name, utility.Kind, utility.File, utility.Line))
+		}
+		section.WriteString("\n")
+	}
+	return section.String()
+}
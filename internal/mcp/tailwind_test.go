@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameworkAnalysisTailwind(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"tailwind.config.js": "module.exports = {\n  theme: {\n    extend: {\n      colors: { brand: '#1d4ed8', legacy: '#999' },\n    },\n  },\n};\n",
+		"src/Button.tsx":     "export function Button() {\n  return <button className=\"bg-brand text-white\" />;\n}\n",
+		"src/app.css":        "@tailwind utilities;\n\n@layer utilities {\n  .no-scrollbar {\n    scrollbar-width: none;\n  }\n}\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "## 🎨 Tailwind CSS\n\n**Config:** `tailwind.config.js` — 2 design tokens, 1 custom classes, 2 files scanned")
+	assert.Contains(t, text, "| `brand` | colors | `#1d4ed8` | 1 | 1 |")
+	assert.Contains(t, text, "### Unused Design Tokens (1)\n\n- `legacy` (colors, extend)")
+	assert.Contains(t, text, "### Unused Custom Classes (1)\n\n- `no-scrollbar` — utility (`src/app.css:4`)")
+
+	response, _, err = server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "Tailwind"})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Focused Analysis for: Tailwind**")
+	assert.Contains(t, text, "## 🎨 Tailwind CSS")
+	assert.NotContains(t, text, "No framework-specific symbols found")
+
+	response, _, err = server.getFrameworkAnalysis(ctx, nil, GetFrameworkAnalysisArgs{Framework: "React"})
+	require.NoError(t, err)
+	assert.NotContains(t, response.Content[0].(*mcp.TextContent).Text, "Tailwind")
+}
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/buildsys"
+)
+
+type GetTasksArgs struct {
+	Category  string `json:"category,omitempty"`   // build, test, lint, format, run, clean, deploy or other
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+}
+
+// taskCategoryOrder controls the section order of the get_tasks response
+var taskCategoryOrder = []string{
+	buildsys.CategoryBuild, buildsys.CategoryTest, buildsys.CategoryLint, buildsys.CategoryFormat,
+	buildsys.CategoryRun, buildsys.CategoryClean, buildsys.CategoryDeploy, buildsys.CategoryOther,
+}
+
+func (s *CodeContextMCPServer) getTasks(ctx context.Context, req *mcp.CallToolRequest, args GetTasksArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_tasks with args: %+v", args)
+	start := time.Now()
+
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, err := buildsys.ScanTasks(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to scan tasks: %v", err)
+		return nil, nil, fmt.Errorf("failed to scan tasks: %w", err)
+	}
+
+	byCategory := make(map[string][]*buildsys.Task)
+	for _, task := range tasks {
+		if args.Category == "" || strings.EqualFold(task.Category, args.Category) {
+			byCategory[task.Category] = append(byCategory[task.Category], task)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Project Tasks\n\n")
+	if len(byCategory) == 0 {
+		result.WriteString("_No Makefile targets, package.json scripts or justfile recipes found_\n")
+	}
+
+	for _, category := range taskCategoryOrder {
+		categoryTasks := byCategory[category]
+		if len(categoryTasks) == 0 {
+			continue
+		}
+		result.WriteString(fmt.Sprintf("## %s\n\n", strings.ToUpper(category[:1])+category[1:]))
+		for _, task := range categoryTasks {
+			result.WriteString(fmt.Sprintf("- `%s`", task.Invocation))
+			if task.Description != "" {
+				result.WriteString(fmt.Sprintf(" — %s", task.Description))
+			}
+			result.WriteString(fmt.Sprintf(" *(%s)*\n", task.File))
+			if task.Command != "" {
+				command := strings.ReplaceAll(task.Command, "\n", "; ")
+				result.WriteString(fmt.Sprintf("  - Runs: `%s`\n", command))
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_tasks (took %v)", elapsed)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/trends"
+)
+
+type GetTrendsArgs struct {
+	Last      int    `json:"last,omitempty"`       // Optional: number of recorded snapshots (default 10)
+	Tags      bool   `json:"tags,omitempty"`       // Optional: only snapshots of tagged commits
+	Record    bool   `json:"record,omitempty"`     // Optional: also record the current analysis as a snapshot
+	TargetDir string `json:"target_dir,omitempty"` // Optional: directory to analyze
+	Profile   string `json:"profile,omitempty"`    // Optional: analysis profile (fast, balanced or deep)
+}
+
+func (s *CodeContextMCPServer) getTrends(ctx context.Context, req *mcp.CallToolRequest, args GetTrendsArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_trends with args: %+v", args)
+	start := time.Now()
+
+	if args.Last <= 0 {
+		args.Last = 10
+	}
+	if args.Record && s.currentConfig().ReadOnly {
+		log.Printf("[MCP] AUDIT: Denied get_trends record in read-only mode")
+		return nil, nil, fmt.Errorf("snapshots cannot be recorded: server is read-only")
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Snapshots are written by other processes, so the response is never cached
+	history, err := trends.Load(targetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshots := history.Recent(args.Last, args.Tags)
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithProfile(targetDir, args.Profile)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+	current := trends.Take(graph, targetDir)
+	if args.Record {
+		if err := trends.Record(targetDir, current); err != nil {
+			log.Printf("[MCP] ERROR: Failed to record snapshot: %v", err)
+			return nil, nil, err
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString("# Codebase Trends\n\n")
+	if len(snapshots) == 0 {
+		result.WriteString("_No snapshots recorded yet; `codecontext generate --trends` records one per run, or call this tool with record._\n\n")
+	} else {
+		result.WriteString(fmt.Sprintf("**Snapshots:** %d recorded", len(snapshots)))
+		if args.Tags {
+			result.WriteString(" at tagged commits")
+		}
+		result.WriteString(" | The last row is the current analysis")
+		if args.Record {
+			result.WriteString(" (now recorded)")
+		}
+		result.WriteString("\n\n")
+	}
+	result.WriteString(trends.Markdown(append(snapshots, current)))
+
+	log.Printf("[MCP] Tool completed: get_trends (took %v, %d snapshots)", time.Since(start), len(snapshots))
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result.String()}},
+	}, nil, nil
+}
@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/trends"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTrends(t *testing.T) {
+	tmpDir := t.TempDir()
+	source := "package shop\n\nfunc Price(n int) int {\n\tif n > 10 {\n\t\treturn n * 9\n\t}\n\treturn n * 10\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "shop.go"), []byte(source), 0644))
+
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getTrends(ctx, nil, GetTrendsArgs{})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "_No snapshots recorded yet")
+	assert.Contains(t, text, "| - | ")
+	assert.Contains(t, text, " | 1 | 1 | 1 | 2.00 | 2 | 1 | 0 | 0.00 |")
+	assert.NotContains(t, text, "### Change since")
+
+	previous := trends.Snapshot{Time: time.Date(2026, 1, 5, 8, 0, 0, 0, time.UTC), Tags: []string{"v0.9.0"}, Files: 3, Functions: 4, AvgComplexity: 1.5, MaxComplexity: 3}
+	require.NoError(t, trends.Record(tmpDir, previous))
+
+	response, _, err = server.getTrends(ctx, nil, GetTrendsArgs{Record: true})
+	require.NoError(t, err)
+	text = response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Snapshots:** 1 recorded | The last row is the current analysis (now recorded)")
+	assert.Contains(t, text, "| v0.9.0 | 2026-01-05 08:00 | 3 |")
+	assert.Contains(t, text, "- **Files:** 3 → 1 (-2)")
+	assert.Contains(t, text, "- **Avg complexity:** 1.50 → 2.00 (+0.50)")
+
+	history, err := trends.Load(tmpDir)
+	require.NoError(t, err)
+	assert.Len(t, history.Snapshots, 2)
+
+	server.config.ReadOnly = true
+	_, _, err = server.getTrends(ctx, nil, GetTrendsArgs{Record: true})
+	assert.ErrorContains(t, err, "server is read-only")
+}
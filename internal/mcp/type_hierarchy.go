@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// defaultHierarchyDepth bounds type hierarchy traversal when max_depth is not given
+const defaultHierarchyDepth = 5
+
+type GetTypeHierarchyArgs struct {
+	SymbolName string `json:"symbol_name"`
+	FilePath   string `json:"file_path,omitempty"`
+	Direction  string `json:"direction,omitempty"` // "supertypes", "subtypes" or "both" (default)
+	MaxDepth   int    `json:"max_depth,omitempty"`
+	TargetDir  string `json:"target_dir,omitempty"` // Optional: directory to analyze
+}
+
+func (s *CodeContextMCPServer) getTypeHierarchy(ctx context.Context, req *mcp.CallToolRequest, args GetTypeHierarchyArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_type_hierarchy with args: %+v", args)
+	start := time.Now()
+
+	if args.SymbolName == "" {
+		log.Printf("[MCP] ERROR: symbol_name is required")
+		return nil, nil, fmt.Errorf("symbol_name is required")
+	}
+
+	direction := args.Direction
+	if direction == "" {
+		direction = "both"
+	}
+	if direction != "both" && direction != "supertypes" && direction != "subtypes" {
+		return nil, nil, fmt.Errorf("invalid direction '%s': expected supertypes, subtypes or both", args.Direction)
+	}
+
+	maxDepth := args.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultHierarchyDepth
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	var matches []*types.Symbol
+	for _, symbol := range graph.Symbols {
+		if symbol.Name != args.SymbolName {
+			continue
+		}
+		if args.FilePath != "" && !strings.HasSuffix(getFilePathForSymbol(graph, symbol), args.FilePath) {
+			continue
+		}
+		matches = append(matches, symbol)
+	}
+
+	if len(matches) == 0 {
+		log.Printf("[MCP] ERROR: Symbol not found: %s", args.SymbolName)
+		return nil, nil, fmt.Errorf("symbol '%s' not found", args.SymbolName)
+	}
+
+	hierarchy := analyzer.NewTypeHierarchy(graph)
+	result := fmt.Sprintf("# Type Hierarchy: %s\n\n", args.SymbolName)
+
+	for i, symbol := range matches {
+		if i > 0 {
+			result += "\n---\n\n"
+		}
+		result += fmt.Sprintf("**File:** %s (line %d)\n", getFilePathForSymbol(graph, symbol), symbol.Location.StartLine)
+		result += fmt.Sprintf("**Type:** %s\n\n", symbol.Type)
+
+		if direction != "subtypes" {
+			result += "## ⬆️ Supertypes\n\n"
+			result += formatHierarchyTree(hierarchy.Supertypes(symbol.Id, maxDepth), "_No supertypes found_\n")
+			result += "\n"
+		}
+		if direction != "supertypes" {
+			result += "## ⬇️ Subtypes\n\n"
+			result += formatHierarchyTree(hierarchy.Subtypes(symbol.Id, maxDepth), "_No subtypes found_\n")
+			result += "\n"
+		}
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_type_hierarchy (took %v)", elapsed)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: result}},
+	}, nil, nil
+}
+
+// formatHierarchyTree renders hierarchy nodes as a nested markdown list
+func formatHierarchyTree(nodes []*analyzer.TypeHierarchyNode, empty string) string {
+	if len(nodes) == 0 {
+		return empty
+	}
+
+	var sb strings.Builder
+	var render func(nodes []*analyzer.TypeHierarchyNode, indent int)
+	render = func(nodes []*analyzer.TypeHierarchyNode, indent int) {
+		for _, node := range nodes {
+			sb.WriteString(strings.Repeat("  ", indent))
+			if node.IsExternal {
+				sb.WriteString(fmt.Sprintf("- `%s` *(%s, external)*\n", node.Name, node.Relationship))
+			} else {
+				sb.WriteString(fmt.Sprintf("- `%s` *(%s)* — %s:%d\n", node.Name, node.Relationship, node.FilePath, node.Line))
+			}
+			render(node.Children, indent+1)
+		}
+	}
+	render(nodes, 0)
+	return sb.String()
+}
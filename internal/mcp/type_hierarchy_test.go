@@ -0,0 +1,56 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTypeHierarchyTemplateSpecializations(t *testing.T) {
+	tmpDir := t.TempDir()
+	header := "template <typename T>\nstruct Traits {};\n\ntemplate <>\nstruct Traits<int> {};\n\ntemplate struct Traits<double>;\n"
+	headerFile := filepath.Join(tmpDir, "traits.hpp")
+	require.NoError(t, os.WriteFile(headerFile, []byte(header), 0644))
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	result, _, err := server.getTypeHierarchy(ctx, nil, GetTypeHierarchyArgs{SymbolName: "Traits", Direction: "subtypes"})
+	require.NoError(t, err)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "- `Traits` *(specializes)* — "+headerFile+":4\n")
+	assert.Contains(t, text, "- `Traits<double>` *(instantiates)* — "+headerFile+":7\n")
+
+	result, _, err = server.getTypeHierarchy(ctx, nil, GetTypeHierarchyArgs{SymbolName: "Traits<double>", Direction: "supertypes"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "## ⬆️ Supertypes\n\n- `Traits` *(instantiates)* — "+headerFile+":1\n")
+}
+
+func TestFindImplementationsGo(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "store.go"), []byte("package store\n\ntype Store interface {\n\tGet(key string) (string, error)\n\tClose() error\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "memory.go"), []byte("package store\n\ntype Memory struct{}\n\nfunc (m *Memory) Get(k string) (string, error) { return \"\", nil }\nfunc (m Memory) Close() error { return nil }\n"), 0644))
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	result, _, err := server.findImplementations(ctx, nil, FindImplementationsArgs{SymbolName: "Store"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "## Implemented by\n\n- `Memory` — memory.go:3 *(pointer receiver)*\n")
+
+	result, _, err = server.findImplementations(ctx, nil, FindImplementationsArgs{SymbolName: "Memory"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "## Implements\n\n- `Store` — store.go:3 *(pointer receiver)*\n")
+
+	_, _, err = server.findImplementations(ctx, nil, FindImplementationsArgs{SymbolName: "Missing"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,282 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// sessionMemoryTTL is how long an idle session's explored set is kept
+const sessionMemoryTTL = 6 * time.Hour
+
+type GetUnexploredRelatedArgs struct {
+	FilePath   string `json:"file_path,omitempty"`   // Optional: start from this file instead of everything explored so far
+	MaxResults int    `json:"max_results,omitempty"` // Optional: number of suggestions (default 10)
+	TargetDir  string `json:"target_dir,omitempty"`  // Optional: directory to analyze
+}
+
+// sessionMemory records the files and symbols each MCP session has retrieved.
+// Sessions are keyed by their ServerSession; calls without one share the nil key.
+type sessionMemory struct {
+	mu       sync.Mutex
+	sessions map[*mcp.ServerSession]*exploredSet
+}
+
+// exploredSet is what one session has seen, in retrieval order
+type exploredSet struct {
+	files    []string
+	symbols  map[types.SymbolId]bool
+	lastSeen time.Time
+}
+
+func newSessionMemory() *sessionMemory {
+	return &sessionMemory{sessions: make(map[*mcp.ServerSession]*exploredSet)}
+}
+
+func sessionOf(req *mcp.CallToolRequest) *mcp.ServerSession {
+	if req == nil {
+		return nil
+	}
+	return req.Session
+}
+
+// record adds files and symbols to the session's explored set
+func (m *sessionMemory) record(session *mcp.ServerSession, files []string, symbols []types.SymbolId) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for key, set := range m.sessions {
+		if now.Sub(set.lastSeen) > sessionMemoryTTL {
+			delete(m.sessions, key)
+		}
+	}
+	set := m.sessions[session]
+	if set == nil {
+		set = &exploredSet{symbols: make(map[types.SymbolId]bool)}
+		m.sessions[session] = set
+	}
+	set.lastSeen = now
+	for _, file := range files {
+		if file != "" && !slices.Contains(set.files, file) {
+			set.files = append(set.files, file)
+		}
+	}
+	for _, symbol := range symbols {
+		set.symbols[symbol] = true
+	}
+}
+
+// explored returns a copy of the session's explored files and symbols
+func (m *sessionMemory) explored(session *mcp.ServerSession) ([]string, map[types.SymbolId]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	set := m.sessions[session]
+	if set == nil {
+		return nil, map[types.SymbolId]bool{}
+	}
+	symbols := make(map[types.SymbolId]bool, len(set.symbols))
+	for id := range set.symbols {
+		symbols[id] = true
+	}
+	return append([]string(nil), set.files...), symbols
+}
+
+// count returns the number of sessions with remembered files or symbols
+func (m *sessionMemory) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// unexploredSuggestion is a file related to the explored code that the session has not retrieved
+type unexploredSuggestion struct {
+	File    string
+	Score   int
+	Reasons []string
+	Symbols []string // Unexplored symbols of the file that the explored code uses or is used by
+}
+
+// Relationship weights for ranking unexplored files
+var unexploredWeights = map[string]int{
+	"imports":    3,
+	"calls":      2,
+	"extends":    3,
+	"implements": 3,
+	"references": 1,
+}
+
+// suggestUnexplored ranks files connected to the seed files by import and symbol
+// edges, skipping explored files and symbols
+func suggestUnexplored(graph *types.CodeGraph, seeds []string, explored []string, exploredSymbols map[types.SymbolId]bool, maxResults int) []unexploredSuggestion {
+	seedSet := make(map[string]bool, len(seeds))
+	for _, file := range seeds {
+		seedSet[file] = true
+	}
+	exploredSet := make(map[string]bool, len(explored))
+	for _, file := range explored {
+		exploredSet[file] = true
+	}
+	symbolFile := make(map[types.NodeId]string)
+	for path, file := range graph.Files {
+		for _, id := range file.Symbols {
+			symbolFile[types.NodeId("symbol-"+string(id))] = path
+		}
+	}
+	fileOf := func(node types.NodeId) (string, types.SymbolId) {
+		if path, ok := strings.CutPrefix(string(node), "file-"); ok {
+			return path, ""
+		}
+		return symbolFile[node], types.SymbolId(strings.TrimPrefix(string(node), "symbol-"))
+	}
+
+	byFile := make(map[string]*unexploredSuggestion)
+	reasonSeen := make(map[string]bool)
+	add := func(file, reason string, symbol types.SymbolId, weight int) {
+		if file == "" || exploredSet[file] || seedSet[file] || graph.Files[file] == nil {
+			return
+		}
+		suggestion := byFile[file]
+		if suggestion == nil {
+			suggestion = &unexploredSuggestion{File: file}
+			byFile[file] = suggestion
+		}
+		suggestion.Score += weight
+		if !reasonSeen[file+"\x00"+reason] && len(suggestion.Reasons) < 3 {
+			reasonSeen[file+"\x00"+reason] = true
+			suggestion.Reasons = append(suggestion.Reasons, reason)
+		}
+		if s := graph.Symbols[symbol]; s != nil && !exploredSymbols[symbol] && !slices.Contains(suggestion.Symbols, s.Name) {
+			suggestion.Symbols = append(suggestion.Symbols, s.Name)
+		}
+	}
+
+	// Visit edges in a stable order so reasons and symbols are deterministic
+	edges := make([]*types.GraphEdge, 0, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		if unexploredWeights[edge.Type] > 0 {
+			edges = append(edges, edge)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Id < edges[j].Id })
+
+	for _, edge := range edges {
+		weight := unexploredWeights[edge.Type]
+		fromFile, fromSymbol := fileOf(edge.From)
+		toFile, toSymbol := fileOf(edge.To)
+		if fromFile == toFile {
+			continue
+		}
+		if seedSet[fromFile] {
+			add(toFile, fmt.Sprintf("%s by %s", pastTense(edge.Type), filepath.Base(fromFile)), toSymbol, weight)
+		}
+		if seedSet[toFile] {
+			add(fromFile, fmt.Sprintf("%s %s", edge.Type, filepath.Base(toFile)), fromSymbol, weight-1)
+		}
+	}
+
+	suggestions := make([]unexploredSuggestion, 0, len(byFile))
+	for _, suggestion := range byFile {
+		sort.Strings(suggestion.Symbols)
+		suggestions = append(suggestions, *suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].File < suggestions[j].File
+	})
+	if maxResults > 0 && len(suggestions) > maxResults {
+		suggestions = suggestions[:maxResults]
+	}
+	return suggestions
+}
+
+// pastTense turns an edge type into the passive phrase used for outgoing edges
+func pastTense(edgeType string) string {
+	switch edgeType {
+	case "imports":
+		return "imported"
+	case "calls":
+		return "called"
+	case "extends":
+		return "extended"
+	case "implements":
+		return "implemented"
+	}
+	return "referenced"
+}
+
+func (s *CodeContextMCPServer) getUnexploredRelated(ctx context.Context, req *mcp.CallToolRequest, args GetUnexploredRelatedArgs) (*mcp.CallToolResult, any, error) {
+	log.Printf("[MCP] Tool called: get_unexplored_related with args: %+v", args)
+	start := time.Now()
+
+	maxResults := args.MaxResults
+	if maxResults <= 0 {
+		maxResults = 10
+	}
+
+	// Resolve target directory
+	targetDir, err := s.resolveTargetDir(args.TargetDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ensure we have fresh analysis
+	graph, err := s.refreshAnalysisWithTargetDir(targetDir)
+	if err != nil {
+		log.Printf("[MCP] ERROR: Failed to refresh analysis: %v", err)
+		return nil, nil, fmt.Errorf("failed to refresh analysis: %w", err)
+	}
+
+	explored, exploredSymbols := s.memory.explored(sessionOf(req))
+	seeds := explored
+	if args.FilePath != "" {
+		if graph.Files[args.FilePath] == nil {
+			return nil, nil, fmt.Errorf("file not found: %s", args.FilePath)
+		}
+		seeds = []string{args.FilePath}
+	}
+
+	relPath := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return path
+	}
+
+	var out strings.Builder
+	out.WriteString("# Unexplored Related Code\n\n")
+	if len(seeds) == 0 {
+		out.WriteString("Nothing has been explored in this session yet. Use get_file_analysis or get_symbol_info first, or pass file_path.\n")
+	} else {
+		out.WriteString(fmt.Sprintf("**Explored this session:** %d files, %d symbols\n\n", len(explored), len(exploredSymbols)))
+		suggestions := suggestUnexplored(graph, seeds, explored, exploredSymbols, maxResults)
+		if len(suggestions) == 0 {
+			out.WriteString("_No unexplored files are connected to the explored code._\n")
+		}
+		for i, suggestion := range suggestions {
+			out.WriteString(fmt.Sprintf("%d. **%s** (score %d) - %s\n", i+1, relPath(suggestion.File), suggestion.Score, strings.Join(suggestion.Reasons, "; ")))
+			if len(suggestion.Symbols) > 0 {
+				out.WriteString(fmt.Sprintf("   Symbols: %s\n", strings.Join(suggestion.Symbols, ", ")))
+			}
+		}
+	}
+
+	elapsed := time.Since(start)
+	log.Printf("[MCP] Tool completed: get_unexplored_related (took %v)", elapsed)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: out.String()}},
+	}, nil, nil
+}
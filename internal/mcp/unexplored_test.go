@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuggestUnexplored(t *testing.T) {
+	graph := &types.CodeGraph{
+		Edges:   make(map[types.EdgeId]*types.GraphEdge),
+		Files:   make(map[string]*types.FileNode),
+		Symbols: make(map[types.SymbolId]*types.Symbol),
+	}
+	for path, symbols := range map[string][]string{
+		"app.ts":     {"main"},
+		"service.ts": {"Service", "helper"},
+		"store.ts":   {"Store"},
+		"admin.ts":   {"adminMain"},
+		"seen.ts":    nil,
+	} {
+		file := &types.FileNode{Path: path}
+		for _, name := range symbols {
+			graph.Symbols[types.SymbolId(name)] = &types.Symbol{Id: types.SymbolId(name), Name: name}
+			file.Symbols = append(file.Symbols, types.SymbolId(name))
+		}
+		graph.Files[path] = file
+	}
+	addEdge := func(from, to, edgeType string) {
+		id := types.EdgeId(edgeType + ":" + from + ":" + to)
+		graph.Edges[id] = &types.GraphEdge{Id: id, From: types.NodeId(from), To: types.NodeId(to), Type: edgeType}
+	}
+	addEdge("file-app.ts", "file-service.ts", "imports")
+	addEdge("file-app.ts", "file-seen.ts", "imports")
+	addEdge("symbol-main", "symbol-Service", "calls")
+	addEdge("symbol-main", "symbol-helper", "calls")
+	addEdge("file-service.ts", "file-store.ts", "imports")
+	addEdge("file-admin.ts", "file-app.ts", "imports")
+
+	suggestions := suggestUnexplored(graph, []string{"app.ts"}, []string{"app.ts", "seen.ts"}, map[types.SymbolId]bool{"Service": true}, 10)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, unexploredSuggestion{File: "service.ts", Score: 7, Reasons: []string{"called by app.ts", "imported by app.ts"}, Symbols: []string{"helper"}}, suggestions[0])
+	assert.Equal(t, unexploredSuggestion{File: "admin.ts", Score: 2, Reasons: []string{"imports app.ts"}}, suggestions[1])
+
+	assert.Len(t, suggestUnexplored(graph, []string{"app.ts"}, nil, nil, 1), 1)
+}
+
+func TestGetUnexploredRelated(t *testing.T) {
+	tmpDir := createTestDirectory(t)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getUnexploredRelated(ctx, nil, GetUnexploredRelatedArgs{})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "Nothing has been explored")
+
+	mainTS := filepath.Join(tmpDir, "main.ts")
+	_, _, err = server.getFileAnalysis(ctx, nil, GetFileAnalysisArgs{FilePath: mainTS})
+	require.NoError(t, err)
+	files, _ := server.memory.explored(nil)
+	assert.Equal(t, []string{mainTS}, files)
+
+	response, _, err = server.getUnexploredRelated(ctx, nil, GetUnexploredRelatedArgs{})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Explored this session:** 1 files")
+
+	_, _, err = server.getUnexploredRelated(ctx, nil, GetUnexploredRelatedArgs{FilePath: "missing.ts"})
+	assert.ErrorContains(t, err, "file not found")
+}
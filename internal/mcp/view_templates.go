@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/parser"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// templateInfo describes the variables a template reads, the handlers that
+// render it and, for a handler, the templates it renders
+func templateInfo(graph *types.CodeGraph, symbol *types.Symbol, file, targetDir string) string {
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(targetDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return path
+	}
+
+	var info string
+	if variables := symbol.MetadataStrings(parser.MetadataTemplateVariables); len(variables) > 0 {
+		info += fmt.Sprintf("**Template variables:** `%s`\n", strings.Join(variables, "`, `"))
+	}
+
+	var renderedBy, renders []string
+	isTemplate := parser.IsTemplateLanguage(symbol.Language) && symbol.Subtype() == "template"
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipRenders) {
+			continue
+		}
+		line, _ := edge.Metadata["line"].(int)
+		switch {
+		case isTemplate && edge.To == types.NodeId("file-"+file):
+			from, _ := edge.Metadata["file"].(string)
+			entry := fmt.Sprintf("`%s:%d`", relative(from), line)
+			if handler := graph.Symbols[types.SymbolId(strings.TrimPrefix(string(edge.From), "symbol-"))]; handler != nil {
+				entry = fmt.Sprintf("`%s` (%s)", handler.Name, entry)
+			}
+			renderedBy = append(renderedBy, entry)
+		case edge.From == types.NodeId("symbol-"+string(symbol.Id)):
+			entry := fmt.Sprintf("`%s` (line %d", relative(strings.TrimPrefix(string(edge.To), "file-")), line)
+			if variables := edgeStrings(edge, "variables"); len(variables) > 0 {
+				entry += fmt.Sprintf(", with `%s`", strings.Join(variables, "`, `"))
+			}
+			renders = append(renders, entry+")")
+		}
+	}
+	if len(renderedBy) > 0 {
+		sort.Strings(renderedBy)
+		info += fmt.Sprintf("**Rendered by:** %s\n", strings.Join(renderedBy, ", "))
+	}
+	if len(renders) > 0 {
+		sort.Strings(renders)
+		info += fmt.Sprintf("**Renders:** %s\n", strings.Join(renders, "; "))
+	}
+	return info
+}
+
+// edgeStrings returns a string list from edge metadata, as built or as
+// decoded from a snapshot
+func edgeStrings(edge *types.GraphEdge, key string) []string {
+	switch values := edge.Metadata[key].(type) {
+	case []string:
+		return values
+	case []interface{}:
+		var result []string
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
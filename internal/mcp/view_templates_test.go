@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolInfoTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"app/views.py":                      "def show_order(order_id):\n    return render_template(\"orders/show.html.j2\", order=load(order_id))\n",
+		"app/templates/orders/show.html.j2": "{% block content %}{{ order.id }} {{ request.path }}{% endblock %}\n",
+	}
+	testutils.WriteTree(t, tmpDir, files)
+	config := createTestConfig()
+	config.TargetDir = tmpDir
+	server, err := NewCodeContextMCPServer(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	response, _, err := server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "show.html.j2"})
+	require.NoError(t, err)
+	text := response.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "**Template variables:** `order`, `request`")
+	assert.Contains(t, text, "**Rendered by:** `show_order` (`app/views.py:2`)")
+
+	response, _, err = server.getSymbolInfo(ctx, nil, GetSymbolInfoArgs{SymbolName: "show_order"})
+	require.NoError(t, err)
+	assert.Contains(t, response.Content[0].(*mcp.TextContent).Text, "**Renders:** `app/templates/orders/show.html.j2` (line 2, with `order`)")
+}
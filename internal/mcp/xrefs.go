@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// maxIndexedReferences caps the references listed per symbol
+const maxIndexedReferences = 10
+
+// indexedReferences lists the symbols referring to a symbol according to
+// imported SCIP or LSIF indexes, as "`Name` (file:line)", with the formats
+// they came from
+func indexedReferences(graph *types.CodeGraph, id types.SymbolId, targetDir string) ([]string, []string) {
+	node := types.NodeId("symbol-" + string(id))
+	var references []string
+	formats := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipReferences) || edge.To != node {
+			continue
+		}
+		format, _ := edge.Metadata[analyzer.EdgeProvenanceKey].(string)
+		from := graph.Symbols[types.SymbolId(strings.TrimPrefix(string(edge.From), "symbol-"))]
+		if format == "" || from == nil {
+			continue
+		}
+		formats[format] = true
+		file, _ := edge.Metadata["source_file"].(string)
+		if rel, err := filepath.Rel(targetDir, file); err == nil {
+			file = rel
+		}
+		location := filepath.ToSlash(file)
+		if line := firstLine(edge.Metadata["lines"]); line > 0 {
+			location = fmt.Sprintf("%s:%d", location, line)
+		}
+		references = append(references, fmt.Sprintf("`%s` (%s)", from.Name, location))
+	}
+	sort.Strings(references)
+
+	var names []string
+	for format := range formats {
+		names = append(names, strings.ToUpper(format))
+	}
+	sort.Strings(names)
+	return references, names
+}
+
+// firstLine returns the first of the reference lines recorded on an edge,
+// which are ints until the graph is saved and reloaded as JSON
+func firstLine(value interface{}) int {
+	switch lines := value.(type) {
+	case []int:
+		if len(lines) > 0 {
+			return lines[0]
+		}
+	case []interface{}:
+		if len(lines) > 0 {
+			if line, ok := lines[0].(float64); ok {
+				return int(line)
+			}
+		}
+	}
+	return 0
+}
+
+// formatIndexedReferences renders the references of a symbol for
+// get_symbol_info, or "" without imported references
+func formatIndexedReferences(references, formats []string) string {
+	if len(references) == 0 {
+		return ""
+	}
+	listed := references
+	if len(listed) > maxIndexedReferences {
+		listed = listed[:maxIndexedReferences]
+	}
+	result := fmt.Sprintf("**Referenced by (%s index):** %s", strings.Join(formats, ", "), strings.Join(listed, ", "))
+	if more := len(references) - len(listed); more > 0 {
+		result += fmt.Sprintf(" and %d more", more)
+	}
+	return result + "\n"
+}
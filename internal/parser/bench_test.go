@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/benchmark/fixture"
+)
+
+func BenchmarkParseFixture(b *testing.B) {
+	byLanguage := make(map[string][]string)
+	files := fixture.Files(fixture.Small)
+	for path := range files {
+		if ext := filepath.Ext(path); ext != ".md" {
+			byLanguage[ext] = append(byLanguage[ext], path)
+		}
+	}
+
+	for _, ext := range []string{".go", ".py", ".ts"} {
+		paths := byLanguage[ext]
+		sort.Strings(paths)
+		b.Run(ext[1:], func(b *testing.B) {
+			manager := NewManager()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, path := range paths {
+					ast, err := manager.Parse(files[path], path)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := manager.ExtractSymbols(ast); err != nil {
+						b.Fatal(err)
+					}
+					if _, err := manager.ExtractImports(ast); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,725 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// MetadataProps lists the props a UI component accepts, written like
+// TypeScript members: "label: string", "size?: 'sm' | 'lg' = 'sm'". Props
+// marked "?" are optional; the text after "=" is the default value.
+const MetadataProps = "props"
+
+var (
+	reactFunctionComponent = regexp.MustCompile(`(?m)^(export\s+)?(?:default\s+)?function\s+([A-Z][\w$]*)\s*(?:<[^>(]*>)?\s*\(`)
+	reactVariableComponent = regexp.MustCompile(`(?m)^(export\s+)?(?:const|let|var)\s+([A-Z][\w$]*)\s*(?::\s*([^=\n]+?))?\s*=\s*`)
+	reactClassComponent    = regexp.MustCompile(`(?m)^(export\s+)?(?:default\s+)?class\s+([A-Z][\w$]*)\s+extends\s+(?:React\.)?(?:Pure)?Component\b\s*(<)?`)
+	// Wrappers that return a component from the function they are given
+	reactComponentWrapper = regexp.MustCompile(`^(?:React\.)?(memo|forwardRef)\s*`)
+	singleParameterArrow  = regexp.MustCompile(`^[A-Za-z_$][\w$]*\s*=>`)
+	reactComponentType    = regexp.MustCompile(`\b(?:FC|FunctionComponent|VFC|ComponentType)\s*<`)
+	// Function components return JSX
+	reactJSXReturn = regexp.MustCompile(`(?:\breturn|=>)\s*\(?\s*<[A-Za-z>]`)
+	// Declarations at the start of a line end the one before them
+	topLevelJSDeclaration = regexp.MustCompile(`(?m)^(?:export\s+)?(?:default\s+)?(?:async\s+)?(?:function|class|const|let|var|interface|type)\b`)
+	propTypesAssignment   = `(?:%s\.%s\s*=|static\s+%[2]s\s*=)\s*\{`
+
+	typeMember      = regexp.MustCompile(`^(?:readonly\s+)?([A-Za-z_$][\w$-]*|'[^']*'|"[^"]*")(\?)?\s*(\(.*\))?\s*:\s*([\s\S]+)$`)
+	objectMember    = regexp.MustCompile(`^([A-Za-z_$][\w$-]*|'[^']*'|"[^"]*")\s*:\s*([\s\S]+)$`)
+	destructuredArg = regexp.MustCompile(`^([A-Za-z_$][\w$]*)\s*(?::\s*[A-Za-z_$][\w$]*)?\s*(?:=\s*([\s\S]+))?$`)
+	whitespaceRun   = regexp.MustCompile(`\s+`)
+
+	vueScriptBlock     = regexp.MustCompile(`(?s)<script\b[^>]*>(.*?)</script>`)
+	vueComponentName   = regexp.MustCompile(`\bname\s*:\s*['"]([^'"]+)['"]`)
+	vueDefineProps     = regexp.MustCompile(`\bdefineProps\s*(<)?`)
+	vueWithDefaults    = regexp.MustCompile(`\bwithDefaults\s*\(\s*defineProps\b`)
+	vueOptionsProps    = regexp.MustCompile(`\bprops\s*:\s*([{\[])`)
+	vuePropType        = regexp.MustCompile(`\bas\s+PropType\s*<([\s\S]+)>\s*$`)
+	svelteExportLet    = regexp.MustCompile(`(?m)^\s*export\s+let\s+([A-Za-z_$][\w$]*)\s*(?::\s*([^=;\n]+?))?\s*(?:=\s*([^;\n]+?))?\s*;?\s*$`)
+	svelteRunesProps   = regexp.MustCompile(`\blet\s*\{`)
+	fileComponentWords = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// componentProp is one prop a component accepts
+type componentProp struct {
+	Name     string
+	Type     string
+	Optional bool
+	Default  string
+}
+
+// String writes the prop like a TypeScript member with its default
+func (p componentProp) String() string {
+	text := p.Name
+	if p.Optional {
+		text += "?"
+	}
+	if p.Type != "" {
+		text += ": " + p.Type
+	}
+	if p.Default != "" {
+		text += " = " + p.Default
+	}
+	return text
+}
+
+// componentPropList keeps props in declaration order, merging repeated names
+type componentPropList []componentProp
+
+func (l *componentPropList) add(prop componentProp) *componentProp {
+	for i := range *l {
+		if (*l)[i].Name == prop.Name {
+			existing := &(*l)[i]
+			if existing.Type == "" {
+				existing.Type = prop.Type
+			}
+			if existing.Default == "" {
+				existing.Default = prop.Default
+			}
+			existing.Optional = existing.Optional || prop.Optional
+			return existing
+		}
+	}
+	*l = append(*l, prop)
+	return &(*l)[len(*l)-1]
+}
+
+func (l componentPropList) strings() []string {
+	result := make([]string, len(l))
+	for i, prop := range l {
+		result[i] = prop.String()
+	}
+	return result
+}
+
+// attachComponentProps records the props of React function and class
+// components in JavaScript and TypeScript files, and of Vue and Svelte
+// single-file components. Components are read from the source text because
+// the JavaScript grammar used for TypeScript loses annotated declarations;
+// those the grammar missed are added as component symbols.
+func attachComponentProps(symbols []*types.Symbol, ast *types.AST) []*types.Symbol {
+	switch ast.Language {
+	case "javascript", "typescript":
+		for _, component := range findReactComponents(ast.Content) {
+			symbols = attachProps(symbols, ast, component.name, component.line, component.exported, component.props)
+		}
+	case "vue", "svelte":
+		name, props := singleFileComponentProps(ast.FilePath, ast.Content, ast.Language)
+		// The document node may already have produced an unnamed component symbol
+		for _, symbol := range symbols {
+			if symbol.Type == types.SymbolTypeComponent && symbol.Location.StartLine == 1 {
+				symbol.Name = name
+				symbol.Location.EndLine = strings.Count(strings.TrimRight(ast.Content, "\n"), "\n") + 1
+			}
+		}
+		symbols = attachProps(symbols, ast, name, 1, true, props)
+	}
+	return symbols
+}
+
+// attachProps sets the props of the named component's symbol, adding a
+// component symbol when there is none. Components without props get a
+// symbol but no metadata.
+func attachProps(symbols []*types.Symbol, ast *types.AST, name string, line int, exported bool, props componentPropList) []*types.Symbol {
+	var target *types.Symbol
+	for _, symbol := range symbols {
+		if symbol.Name != name {
+			continue
+		}
+		if target == nil || symbol.Location.StartLine == line {
+			target = symbol
+		}
+	}
+	if target == nil {
+		visibility := types.VisibilityPrivate
+		if exported {
+			visibility = types.VisibilityPublic
+		}
+		endLine := line
+		if line == 1 && (ast.Language == "vue" || ast.Language == "svelte") {
+			endLine = strings.Count(strings.TrimRight(ast.Content, "\n"), "\n") + 1
+		}
+		target = &types.Symbol{
+			Id:           types.SymbolId(fmt.Sprintf("component-%s-%d", ast.FilePath, line)),
+			Name:         name,
+			Type:         types.SymbolTypeComponent,
+			Location:     types.Location{StartLine: line, StartColumn: 1, EndLine: endLine},
+			Language:     ast.Language,
+			Visibility:   visibility,
+			Hash:         calculateHash(name),
+			LastModified: time.Now(),
+		}
+		symbols = append(symbols, target)
+	}
+	if len(props) > 0 {
+		target.SetMetadata(MetadataProps, props.strings())
+	}
+	return symbols
+}
+
+// reactComponent is a React component declared in a file
+type reactComponent struct {
+	name     string
+	line     int
+	exported bool
+	props    componentPropList
+}
+
+// findReactComponents returns the function, arrow and class components of a
+// file: capitalized declarations that return JSX, are typed as components or
+// are wrapped in memo or forwardRef
+func findReactComponents(content string) []reactComponent {
+	var components []reactComponent
+	declarationEnd := func(start int) int {
+		if loc := topLevelJSDeclaration.FindStringIndex(content[start+1:]); loc != nil {
+			return start + 1 + loc[0]
+		}
+		return len(content)
+	}
+	lineOf := func(offset int) int {
+		return strings.Count(content[:offset], "\n") + 1
+	}
+
+	for _, m := range reactFunctionComponent.FindAllStringSubmatchIndex(content, -1) {
+		body := content[m[0]:declarationEnd(m[0])]
+		if !reactJSXReturn.MatchString(body) {
+			continue
+		}
+		name := content[m[4]:m[5]]
+		props := functionProps(content, m[1]-1, "")
+		components = append(components, reactComponent{name, lineOf(m[0]), m[2] >= 0, withPropTypes(content, name, props)})
+	}
+
+	for _, m := range reactVariableComponent.FindAllStringSubmatchIndex(content, -1) {
+		name := content[m[4]:m[5]]
+		annotation := ""
+		if m[6] >= 0 {
+			annotation = content[m[6]:m[7]]
+		}
+		rest := content[m[1]:]
+		wrapped := false
+		propsType := ""
+		for {
+			w := reactComponentWrapper.FindStringSubmatch(rest)
+			if w == nil {
+				break
+			}
+			rest, wrapped = strings.TrimLeft(rest[len(w[0]):], " \t\n"), true
+			// forwardRef<Ref, Props> takes the props type second, memo<Props> first
+			if strings.HasPrefix(rest, "<") {
+				end := closingBracket(rest, 0)
+				if end < 0 {
+					break
+				}
+				if args := splitMembers(rest[1:end]); w[1] == "memo" && len(args) > 0 {
+					propsType = args[0]
+				} else if w[1] == "forwardRef" && len(args) > 1 {
+					propsType = args[1]
+				}
+				rest = strings.TrimLeft(rest[end+1:], " \t\n")
+			}
+			rest = strings.TrimPrefix(rest, "(")
+		}
+		rest = strings.TrimLeft(strings.TrimPrefix(strings.TrimLeft(rest, " \t\n"), "async "), " \t\n")
+		if strings.HasPrefix(rest, "function") {
+			if open := strings.IndexByte(rest, '('); open >= 0 {
+				rest = rest[open:]
+			}
+		}
+		// Anything but a function, such as styled.div`...`, is not a component here
+		if !strings.HasPrefix(rest, "(") && !singleParameterArrow.MatchString(rest) {
+			continue
+		}
+		start := len(content) - len(rest)
+		body := content[m[0]:declarationEnd(m[0])]
+		typed := reactComponentType.MatchString(annotation)
+		if !wrapped && !typed && !reactJSXReturn.MatchString(body) {
+			continue
+		}
+		if typed {
+			propsType = firstTypeArgument(annotation[reactComponentType.FindStringIndex(annotation)[1]-1:])
+		}
+		var props componentPropList
+		if strings.HasPrefix(rest, "(") {
+			props = functionProps(content, start, propsType)
+		} else if propsType != "" {
+			props = typeProps(content, propsType, 0)
+		}
+		components = append(components, reactComponent{name, lineOf(m[0]), m[2] >= 0, withPropTypes(content, name, props)})
+	}
+
+	for _, m := range reactClassComponent.FindAllStringSubmatchIndex(content, -1) {
+		name := content[m[4]:m[5]]
+		var props componentPropList
+		if m[6] >= 0 {
+			props = typeProps(content, firstTypeArgument(content[m[6]:]), 0)
+		}
+		components = append(components, reactComponent{name, lineOf(m[0]), m[2] >= 0, withPropTypes(content, name, props)})
+	}
+	return components
+}
+
+// functionProps reads the props of a component from its parameter list,
+// which opens at the given offset: the members of the first parameter's type
+// (or of propsType, from a component type annotation) and the names and
+// defaults it destructures
+func functionProps(content string, open int, propsType string) componentPropList {
+	close := closingBracket(content, open)
+	if close < 0 {
+		return nil
+	}
+	params := splitMembers(content[open+1 : close])
+	if len(params) == 0 {
+		return typeProps(content, propsType, 0)
+	}
+	param := params[0]
+
+	var destructured componentPropList
+	if strings.HasPrefix(param, "{") {
+		end := closingBracket(param, 0)
+		if end < 0 {
+			return nil
+		}
+		for _, member := range splitMembers(param[1:end]) {
+			if strings.HasPrefix(member, "...") {
+				continue
+			}
+			if m := destructuredArg.FindStringSubmatch(member); m != nil {
+				destructured = append(destructured, componentProp{Name: m[1], Default: compactText(m[2]), Optional: m[2] != ""})
+			}
+		}
+		param = strings.TrimSpace(param[end+1:])
+	} else if colon := strings.Index(param, ":"); colon >= 0 {
+		param = param[colon:]
+	} else {
+		param = ""
+	}
+	if strings.HasPrefix(param, ":") {
+		propsType = strings.TrimSpace(param[1:])
+	}
+
+	props := typeProps(content, propsType, 0)
+	for _, prop := range destructured {
+		props.add(prop)
+	}
+	return props
+}
+
+// typeProps returns the members of a props type: an object type literal, an
+// interface or type alias declared in the file, or an intersection of those
+func typeProps(content, typeText string, depth int) componentPropList {
+	typeText = strings.TrimSpace(typeText)
+	if typeText == "" || depth > 4 {
+		return nil
+	}
+	var props componentPropList
+	if strings.HasPrefix(typeText, "{") {
+		end := closingBracket(typeText, 0)
+		if end < 0 {
+			return nil
+		}
+		for _, member := range splitMembers(stripLineComments(typeText[1:end])) {
+			m := typeMember.FindStringSubmatch(member)
+			if m == nil {
+				continue
+			}
+			memberType := compactText(m[4])
+			if m[3] != "" {
+				memberType = m[3] + " => " + memberType
+			}
+			props.add(componentProp{Name: strings.Trim(m[1], `'"`), Type: memberType, Optional: m[2] != ""})
+		}
+		return props
+	}
+
+	for _, part := range splitTopLevelType(typeText, '&') {
+		name := typeNamePattern.FindString(part)
+		if name == "" {
+			continue
+		}
+		rest := strings.TrimSpace(part[len(name):])
+		// Wrappers such as PropsWithChildren<P> or Readonly<P> hold the props type
+		if strings.HasPrefix(rest, "<") {
+			if local := localTypeBody(content, name); local == "" {
+				for _, prop := range typeProps(content, firstTypeArgument(rest), depth+1) {
+					props.add(prop)
+				}
+				if strings.HasSuffix(name, "PropsWithChildren") {
+					props.add(componentProp{Name: "children", Type: "ReactNode", Optional: true})
+				}
+				continue
+			}
+		}
+		for _, prop := range typeProps(content, localTypeBody(content, name), depth+1) {
+			props.add(prop)
+		}
+		// Interfaces inherit the members of the local interfaces they extend
+		if extends := regexp.MustCompile(`\binterface\s+` + regexp.QuoteMeta(name) + `\b[^{]*?\bextends\s+([^{]+)\{`).FindStringSubmatch(content); extends != nil {
+			for _, parent := range SplitTopLevel(extends[1], ',') {
+				for _, prop := range typeProps(content, parent, depth+1) {
+					props.add(prop)
+				}
+			}
+		}
+	}
+	return props
+}
+
+// localTypeBody returns the object type an interface or type alias of the
+// file declares, including its braces
+func localTypeBody(content, name string) string {
+	declaration := regexp.MustCompile(`\b(?:interface\s+` + regexp.QuoteMeta(name) + `\b[^{=]*|type\s+` + regexp.QuoteMeta(name) + `\s*(?:<[^=]*>)?\s*=\s*)`)
+	loc := declaration.FindStringIndex(content)
+	if loc == nil {
+		return ""
+	}
+	rest := content[loc[1]:]
+	if !strings.HasPrefix(strings.TrimSpace(rest), "{") && strings.Contains(content[loc[0]:loc[1]], "type") {
+		// Aliases of intersections and other named types
+		end := strings.IndexAny(rest, ";\n")
+		if end < 0 {
+			end = len(rest)
+		}
+		return rest[:end]
+	}
+	open := strings.IndexByte(rest, '{')
+	if open < 0 {
+		return ""
+	}
+	end := closingBracket(rest, open)
+	if end < 0 {
+		return ""
+	}
+	return rest[open : end+1]
+}
+
+// withPropTypes adds the props a component declares through propTypes and
+// the defaults of its defaultProps
+func withPropTypes(content, name string, props componentPropList) componentPropList {
+	if loc := regexp.MustCompile(fmt.Sprintf(propTypesAssignment, regexp.QuoteMeta(name), "propTypes")).FindStringIndex(content); loc != nil {
+		if end := closingBracket(content, loc[1]-1); end > 0 {
+			for _, member := range splitMembers(stripLineComments(content[loc[1]:end])) {
+				m := objectMember.FindStringSubmatch(member)
+				if m == nil {
+					continue
+				}
+				validator := compactText(m[2])
+				required := strings.HasSuffix(validator, ".isRequired")
+				validator = strings.TrimPrefix(strings.TrimSuffix(validator, ".isRequired"), "PropTypes.")
+				props.add(componentProp{Name: strings.Trim(m[1], `'"`), Type: validator, Optional: !required})
+			}
+		}
+	}
+	if loc := regexp.MustCompile(fmt.Sprintf(propTypesAssignment, regexp.QuoteMeta(name), "defaultProps")).FindStringIndex(content); loc != nil {
+		if end := closingBracket(content, loc[1]-1); end > 0 {
+			for _, member := range splitMembers(stripLineComments(content[loc[1]:end])) {
+				if m := objectMember.FindStringSubmatch(member); m != nil {
+					props.add(componentProp{Name: strings.Trim(m[1], `'"`), Default: compactText(m[2]), Optional: true})
+				}
+			}
+		}
+	}
+	return props
+}
+
+// singleFileComponentProps returns the name and props of a Vue or Svelte
+// component, read from the <script> blocks of the file
+func singleFileComponentProps(filePath, content, language string) (string, componentPropList) {
+	var script strings.Builder
+	for _, m := range vueScriptBlock.FindAllStringSubmatch(content, -1) {
+		script.WriteString(m[1])
+		script.WriteString("\n")
+	}
+	code := script.String()
+
+	base := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	name := ""
+	for _, word := range fileComponentWords.Split(base, -1) {
+		if word != "" {
+			name += strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	if language == "svelte" {
+		return name, svelteProps(code)
+	}
+	if m := vueComponentName.FindStringSubmatch(code); m != nil && !strings.Contains(code, "defineProps") {
+		name = m[1]
+	}
+	return name, vueProps(code)
+}
+
+// vueProps reads the props of defineProps in <script setup>, typed or
+// runtime, with the defaults of withDefaults, or of the props option
+func vueProps(code string) componentPropList {
+	if loc := vueDefineProps.FindStringSubmatchIndex(code); loc != nil {
+		var props componentPropList
+		if loc[2] >= 0 {
+			props = typeProps(code, firstTypeArgument(code[loc[2]:]), 0)
+		} else if open := strings.IndexAny(code[loc[1]:], "{[)"); open >= 0 && code[loc[1]+open] != ')' {
+			props = vueRuntimeProps(code, loc[1]+open)
+		}
+		// withDefaults(defineProps<...>(), { ... }) has the defaults as its second argument
+		if wd := vueWithDefaults.FindStringIndex(code); wd != nil {
+			open := strings.IndexByte(code[wd[0]:], '(') + wd[0]
+			if end := closingBracket(code, open); end > 0 {
+				args := splitMembers(code[open+1 : end])
+				if len(args) > 1 && strings.HasPrefix(args[1], "{") {
+					if close := closingBracket(args[1], 0); close > 0 {
+						for _, member := range splitMembers(args[1][1:close]) {
+							if m := objectMember.FindStringSubmatch(member); m != nil {
+								props.add(componentProp{Name: strings.Trim(m[1], `'"`), Default: compactText(m[2]), Optional: true})
+							}
+						}
+					}
+				}
+			}
+		}
+		return props
+	}
+	if loc := vueOptionsProps.FindStringSubmatchIndex(code); loc != nil {
+		return vueRuntimeProps(code, loc[2])
+	}
+	return nil
+}
+
+// vueRuntimeProps reads a runtime props declaration: an array of names or an
+// object of constructors and { type, required, default } options
+func vueRuntimeProps(code string, open int) componentPropList {
+	end := closingBracket(code, open)
+	if end < 0 {
+		return nil
+	}
+	var props componentPropList
+	for _, member := range splitMembers(stripLineComments(code[open+1 : end])) {
+		if code[open] == '[' {
+			if name := strings.Trim(member, `'"`); name != "" && name != member {
+				props.add(componentProp{Name: name, Optional: true})
+			}
+			continue
+		}
+		m := objectMember.FindStringSubmatch(member)
+		if m == nil {
+			continue
+		}
+		prop := componentProp{Name: strings.Trim(m[1], `'"`), Optional: true}
+		value := strings.TrimSpace(m[2])
+		if strings.HasPrefix(value, "{") {
+			if close := closingBracket(value, 0); close > 0 {
+				for _, option := range splitMembers(value[1:close]) {
+					o := objectMember.FindStringSubmatch(option)
+					if o == nil {
+						continue
+					}
+					switch o[1] {
+					case "type":
+						prop.Type = vueConstructorType(o[2])
+					case "required":
+						prop.Optional = strings.TrimSpace(o[2]) != "true"
+					case "default":
+						prop.Default = compactText(o[2])
+					}
+				}
+			}
+		} else {
+			prop.Type = vueConstructorType(value)
+		}
+		props.add(prop)
+	}
+	return props
+}
+
+// vueConstructorType turns a Vue prop type such as String, [String, Number]
+// or Object as PropType<User> into a TypeScript type
+func vueConstructorType(value string) string {
+	value = strings.TrimSpace(value)
+	if m := vuePropType.FindStringSubmatch(value); m != nil {
+		return compactText(m[1])
+	}
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		var types []string
+		for _, part := range splitMembers(value[1 : len(value)-1]) {
+			types = append(types, vueConstructorType(part))
+		}
+		return strings.Join(types, " | ")
+	}
+	switch value {
+	case "String", "Number", "Boolean", "Object", "Function", "Symbol", "BigInt":
+		return strings.ToLower(value)
+	case "Array":
+		return "unknown[]"
+	}
+	return compactText(value)
+}
+
+// svelteProps reads the exported variables of a Svelte 4 component, or the
+// destructured $props() of a Svelte 5 one
+func svelteProps(code string) componentPropList {
+	var props componentPropList
+	for _, m := range svelteExportLet.FindAllStringSubmatch(code, -1) {
+		props.add(componentProp{Name: m[1], Type: compactText(m[2]), Default: compactText(m[3]), Optional: m[3] != ""})
+	}
+	for _, loc := range svelteRunesProps.FindAllStringIndex(code, -1) {
+		open := loc[1] - 1
+		end := closingBracket(code, open)
+		if end < 0 {
+			continue
+		}
+		rest := code[end+1:]
+		assign := strings.Index(rest, "=")
+		if assign < 0 || !strings.HasPrefix(strings.TrimSpace(rest[assign+1:]), "$props(") {
+			continue
+		}
+		// let { ... }: Props = $props() is read like a destructured parameter
+		param := code[open:end+1] + rest[:assign]
+		for _, prop := range functionProps("("+param+")", 0, "") {
+			props.add(prop)
+		}
+		if typeText := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest[:assign]), ":")); typeText != "" {
+			for _, prop := range typeProps(code, typeText, 0) {
+				props.add(prop)
+			}
+		}
+	}
+	return props
+}
+
+// firstTypeArgument returns the first argument of a type argument list
+// starting at "<"
+func firstTypeArgument(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "<") {
+		return ""
+	}
+	end := closingBracket(text, 0)
+	if end < 0 {
+		return ""
+	}
+	args := splitMembers(text[1:end])
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// closingBracket returns the offset of the bracket closing the one at open,
+// skipping strings and the ">" of arrows, or -1
+func closingBracket(text string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '[', '{', '<':
+			depth++
+		case '>':
+			if i > 0 && text[i-1] == '=' {
+				continue
+			}
+			depth--
+		case ')', ']', '}':
+			depth--
+		}
+		if depth == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitMembers splits a parameter, member or argument list on the commas,
+// semicolons and line breaks outside brackets and strings. Empty members
+// are dropped.
+func splitMembers(text string) []string {
+	var members []string
+	depth := 0
+	start := 0
+	var quote byte
+	flush := func(end int) {
+		if member := strings.TrimSpace(text[start:end]); member != "" {
+			members = append(members, member)
+		}
+		start = end + 1
+	}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '[', '{', '<':
+			depth++
+		case '>':
+			if i > 0 && text[i-1] == '=' {
+				continue
+			}
+			depth--
+		case ')', ']', '}':
+			depth--
+		case ',', ';':
+			if depth == 0 {
+				flush(i)
+			}
+		case '\n':
+			// Members of multi-line type literals need no separator, but
+			// unions and chained calls continue on the next line
+			if depth == 0 {
+				next := strings.TrimLeft(text[i+1:], " \t\r\n")
+				previous := strings.TrimRight(text[start:i], " \t\r")
+				if next != "" && !strings.ContainsAny(next[:1], "|&.?:=") && !strings.HasSuffix(previous, "|") && !strings.HasSuffix(previous, "&") &&
+					!strings.HasSuffix(previous, ":") && !strings.HasSuffix(previous, "=") {
+					flush(i)
+				}
+			}
+		}
+	}
+	flush(len(text))
+	return members
+}
+
+// splitTopLevelType splits a type on a separator outside brackets
+func splitTopLevelType(text string, separator byte) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			depth--
+		case separator:
+			if depth == 0 {
+				parts = append(parts, text[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, text[start:])
+}
+
+// compactText trims a type or default value and collapses its whitespace
+func compactText(text string) string {
+	return whitespaceRun.ReplaceAllString(strings.TrimSpace(text), " ")
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentProps(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    map[string][]string
+	}{
+		{
+			file: "Button.tsx",
+			content: "interface BaseProps {\n  id?: string;\n}\n\ninterface ButtonProps extends BaseProps {\n  label: string;\n  size?: 'sm' | 'lg';\n  onClick?(event: MouseEvent): void;\n}\n\n" +
+				"export function Button({ label, size = 'sm', onClick }: ButtonProps) {\n  return <button onClick={onClick}>{label}</button>;\n}\n\n" +
+				"export const Card = ({ title, children }: { title: string; children?: React.ReactNode }) => <div>{title}</div>;\n",
+			want: map[string][]string{
+				"Button": {"label: string", "size?: 'sm' | 'lg' = 'sm'", "onClick?: (event: MouseEvent) => void", "id?: string"},
+				"Card":   {"title: string", "children?: React.ReactNode"},
+			},
+		},
+		{
+			file: "Avatar.tsx",
+			content: "type AvatarProps = {\n  user: User\n  rounded?: boolean\n}\n\n" +
+				"export const Avatar: React.FC<AvatarProps> = (props) => <img src={props.user.url} />;\n\n" +
+				"export const Input = React.forwardRef<HTMLInputElement, { value: string }>(({ value }, ref) => <input ref={ref} value={value} />);\n\n" +
+				"export const useAvatar = () => null;\n",
+			want: map[string][]string{
+				"Avatar": {"user: User", "rounded?: boolean"},
+				"Input":  {"value: string"},
+			},
+		},
+		{
+			file: "Panel.jsx",
+			content: "import PropTypes from 'prop-types';\n\nexport default class Panel extends React.Component {\n  render() {\n    return <div>{this.props.title}</div>;\n  }\n}\n\n" +
+				"Panel.propTypes = {\n  title: PropTypes.string.isRequired,\n  collapsed: PropTypes.bool,\n};\n\nPanel.defaultProps = {\n  collapsed: false,\n};\n",
+			want: map[string][]string{
+				"Panel": {"title: string", "collapsed?: bool = false"},
+			},
+		},
+		{
+			file: "user-card.vue",
+			content: "<template><div>{{ user.name }}</div></template>\n<script setup lang=\"ts\">\ninterface Props {\n  user: User\n  compact?: boolean\n}\n" +
+				"const props = withDefaults(defineProps<Props>(), {\n  compact: false,\n})\n</script>\n",
+			want: map[string][]string{
+				"UserCard": {"user: User", "compact?: boolean = false"},
+			},
+		},
+		{
+			file: "Legacy.vue",
+			content: "<template><div /></template>\n<script>\nexport default {\n  name: 'LegacyList',\n  props: {\n    items: { type: Array, required: true },\n    owner: { type: Object as PropType<User> },\n" +
+				"    title: [String, Number],\n    page: { type: Number, default: 1 },\n  },\n}\n</script>\n",
+			want: map[string][]string{
+				"LegacyList": {"items: unknown[]", "owner?: User", "title?: string | number", "page?: number = 1"},
+			},
+		},
+		{
+			file:    "Tag.svelte",
+			content: "<script lang=\"ts\">\n  export let text: string;\n  export let color = 'blue';\n</script>\n\n<span style:color>{text}</span>\n",
+			want: map[string][]string{
+				"Tag": {"text: string", "color? = 'blue'"},
+			},
+		},
+		{
+			file:    "Badge.svelte",
+			content: "<script lang=\"ts\">\n  let { count, max = 99 }: { count: number; max?: number } = $props();\n</script>\n\n<span>{count}</span>\n",
+			want: map[string][]string{
+				"Badge": {"count: number", "max?: number = 99"},
+			},
+		},
+	}
+
+	manager := NewManager()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ast, err := manager.Parse(tt.content, tt.file)
+			require.NoError(t, err)
+			symbols, err := manager.ExtractSymbols(ast)
+			require.NoError(t, err)
+
+			got := make(map[string][]string)
+			for _, symbol := range symbols {
+				if props := symbol.MetadataStrings(MetadataProps); len(props) > 0 {
+					got[symbol.Name] = props
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSingleFileComponentSymbol(t *testing.T) {
+	manager := NewManager()
+	ast, err := manager.Parse("<script>\n  export let name;\n</script>\n\n<h1>Hello {name}</h1>\n", "hello-world.svelte")
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+
+	require.Len(t, symbols, 1)
+	assert.Equal(t, "HelloWorld", symbols[0].Name)
+	assert.Equal(t, types.SymbolKindComponent, symbols[0].Kind)
+	assert.Equal(t, 5, symbols[0].Location.EndLine)
+	assert.Equal(t, []string{"name"}, symbols[0].MetadataStrings(MetadataProps))
+}
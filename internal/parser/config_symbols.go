@@ -0,0 +1,200 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Symbol metadata keys for configuration symbols
+const (
+	MetadataConfigPath   = "config_path"   // Dotted key path, e.g. image.repository
+	MetadataK8sKind      = "k8s_kind"      // Kubernetes resource kind, e.g. Deployment
+	MetadataK8sNamespace = "k8s_namespace" // Kubernetes namespace when declared
+	MetadataK8sAPI       = "k8s_api_version"
+)
+
+// helmValuesDepth bounds how deep Helm values are flattened into dotted keys
+const helmValuesDepth = 3
+
+// maxConfigValueLength truncates scalar values shown in config symbol signatures
+const maxConfigValueLength = 60
+
+// extractConfigSymbols turns YAML and JSON documents into symbols: Kubernetes resources,
+// Helm charts and values, and top-level keys of any other config file.
+// JSON is parsed with the YAML decoder, which accepts it and reports key positions.
+func extractConfigSymbols(ast *types.AST) []*types.Symbol {
+	if strings.TrimSpace(ast.Content) == "" {
+		return nil
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(ast.Content))
+	isValues := isHelmValuesFile(ast.FilePath)
+	isChart := filepath.Base(ast.FilePath) == "Chart.yaml"
+
+	var symbols []*types.Symbol
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// Templated YAML (e.g. Helm templates) is not valid YAML; keep what parsed so far
+			break
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		root := doc.Content[0]
+
+		switch {
+		case isK8sResource(root):
+			symbols = append(symbols, k8sResourceSymbol(root, ast))
+		case isChart:
+			if symbol := helmChartSymbol(root, ast); symbol != nil {
+				symbols = append(symbols, symbol)
+			}
+		case isValues:
+			collectConfigKeys(root, "", helmValuesDepth, ast, &symbols)
+		default:
+			collectConfigKeys(root, "", 1, ast, &symbols)
+		}
+		if len(symbols) >= MaxSymbolsPerFile {
+			break
+		}
+	}
+	return symbols
+}
+
+// isHelmValuesFile reports whether path is a values file next to a Chart.yaml
+func isHelmValuesFile(path string) bool {
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, "values") || (filepath.Ext(base) != ".yaml" && filepath.Ext(base) != ".yml") {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(filepath.Dir(path), "Chart.yaml"))
+	return err == nil
+}
+
+// isK8sResource reports whether a mapping looks like a Kubernetes object
+func isK8sResource(mapping *yaml.Node) bool {
+	return mappingValue(mapping, "apiVersion") != nil && mappingValue(mapping, "kind") != nil &&
+		mappingValue(mappingValue(mapping, "metadata"), "name") != nil
+}
+
+// k8sResourceSymbol creates a symbol named after metadata.name, e.g. Deployment/web
+func k8sResourceSymbol(mapping *yaml.Node, ast *types.AST) *types.Symbol {
+	kind := mappingValue(mapping, "kind").Value
+	metadata := mappingValue(mapping, "metadata")
+	name := mappingValue(metadata, "name").Value
+
+	symbol := newConfigSymbol(name, types.SymbolTypeResource, fmt.Sprintf("%s/%s", kind, name), mapping, ast)
+	symbol.SetMetadata(MetadataK8sKind, kind)
+	symbol.SetMetadata(MetadataK8sAPI, mappingValue(mapping, "apiVersion").Value)
+	if namespace := mappingValue(metadata, "namespace"); namespace != nil {
+		symbol.SetMetadata(MetadataK8sNamespace, namespace.Value)
+	}
+	return symbol
+}
+
+// helmChartSymbol creates a symbol for the chart declared in Chart.yaml
+func helmChartSymbol(mapping *yaml.Node, ast *types.AST) *types.Symbol {
+	name := mappingValue(mapping, "name")
+	if name == nil || name.Value == "" {
+		return nil
+	}
+	signature := "Chart/" + name.Value
+	if version := mappingValue(mapping, "version"); version != nil {
+		signature += "@" + version.Value
+	}
+	symbol := newConfigSymbol(name.Value, types.SymbolTypeResource, signature, mapping, ast)
+	symbol.SetMetadata(MetadataK8sKind, "HelmChart")
+	return symbol
+}
+
+// collectConfigKeys adds a symbol per mapping key, descending into nested mappings up to depth
+func collectConfigKeys(mapping *yaml.Node, prefix string, depth int, ast *types.AST, symbols *[]*types.Symbol) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+
+		signature := path + ":"
+		if value.Kind == yaml.ScalarNode {
+			if isSensitiveConfigKey(key.Value) {
+				signature += " ***"
+			} else {
+				signature += " " + truncateConfigValue(value.Value)
+			}
+		}
+		symbol := newConfigSymbol(path, types.SymbolTypeConfigKey, signature, key, ast)
+		symbol.SetMetadata(MetadataConfigPath, path)
+		*symbols = append(*symbols, symbol)
+
+		if value.Kind == yaml.MappingNode && depth > 1 {
+			collectConfigKeys(value, path, depth-1, ast, symbols)
+		}
+	}
+}
+
+// newConfigSymbol builds a config symbol located at node
+func newConfigSymbol(name string, symbolType types.SymbolType, signature string, node *yaml.Node, ast *types.AST) *types.Symbol {
+	return &types.Symbol{
+		Id:   types.SymbolId(fmt.Sprintf("%s-%s-%d", symbolType, ast.FilePath, node.Line)),
+		Name: name,
+		Type: symbolType,
+		Location: types.Location{
+			StartLine:   node.Line,
+			StartColumn: node.Column,
+			EndLine:     node.Line,
+			EndColumn:   node.Column + len(name),
+		},
+		Signature:    signature,
+		Language:     ast.Language,
+		Hash:         calculateHash(signature),
+		LastModified: time.Now(),
+	}
+}
+
+// mappingValue returns the value node for key in a YAML mapping, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isSensitiveConfigKey reports whether a key likely holds a credential whose value must not be shown
+func isSensitiveConfigKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, marker := range []string{"password", "passwd", "secret", "token", "apikey", "api_key", "private_key", "credential"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateConfigValue shortens long scalar values for display
+func truncateConfigValue(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	if len(value) > maxConfigValueLength {
+		return value[:maxConfigValueLength] + "..."
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func extractFileSymbols(t *testing.T, manager *Manager, path, content string) []*types.Symbol {
+	t.Helper()
+	ast, err := manager.parseContent(content, *manager.detectLanguage(path), path)
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	return symbols
+}
+
+func TestConfigTopLevelKeys(t *testing.T) {
+	manager := NewManager()
+
+	symbols := extractFileSymbols(t, manager, "config/app.yaml", `server:
+  port: 8080
+database_password: hunter2
+log_level: debug
+`)
+	require.Len(t, symbols, 3, "only top-level keys of plain config files become symbols")
+	assert.Equal(t, "server", symbols[0].Name)
+	assert.Equal(t, types.SymbolTypeConfigKey, symbols[0].Type)
+	assert.Equal(t, 1, symbols[0].Location.StartLine)
+	assert.Equal(t, "database_password: ***", symbols[1].Signature)
+	assert.Equal(t, "log_level: debug", symbols[2].Signature)
+	assert.Equal(t, "log_level", symbols[2].MetadataString(MetadataConfigPath))
+
+	symbols = extractFileSymbols(t, manager, "package.json", "{\n  \"name\": \"web\",\n  \"scripts\": {\"build\": \"vite build\"}\n}\n")
+	require.Len(t, symbols, 2)
+	assert.Equal(t, "scripts", symbols[1].Name)
+	assert.Equal(t, 3, symbols[1].Location.StartLine)
+	assert.Equal(t, "json", symbols[1].Language)
+}
+
+func TestConfigKubernetesResources(t *testing.T) {
+	manager := NewManager()
+
+	symbols := extractFileSymbols(t, manager, "k8s/web.yaml", `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  replicas: 2
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	require.Len(t, symbols, 2)
+	assert.Equal(t, types.SymbolTypeResource, symbols[0].Type)
+	assert.Equal(t, "Deployment/web", symbols[0].Signature)
+	assert.Equal(t, "Deployment", symbols[0].MetadataString(MetadataK8sKind))
+	assert.Equal(t, "prod", symbols[0].MetadataString(MetadataK8sNamespace))
+	assert.Equal(t, "Service/web", symbols[1].Signature)
+	assert.Equal(t, 9, symbols[1].Location.StartLine)
+	assert.NotEqual(t, symbols[0].Id, symbols[1].Id)
+}
+
+func TestConfigHelmChart(t *testing.T) {
+	manager := NewManager()
+	dir := t.TempDir()
+	chartPath := filepath.Join(dir, "Chart.yaml")
+	valuesPath := filepath.Join(dir, "values.yaml")
+	require.NoError(t, os.WriteFile(chartPath, []byte("apiVersion: v2\nname: web\nversion: 1.2.0\n"), 0644))
+
+	symbols := extractFileSymbols(t, manager, chartPath, "apiVersion: v2\nname: web\nversion: 1.2.0\n")
+	require.Len(t, symbols, 1)
+	assert.Equal(t, "Chart/web@1.2.0", symbols[0].Signature)
+
+	symbols = extractFileSymbols(t, manager, valuesPath, `image:
+  repository: nginx
+  tag: "1.25"
+ingress:
+  enabled: false
+`)
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.Name)
+	}
+	assert.Equal(t, []string{"image", "image.repository", "image.tag", "ingress", "ingress.enabled"}, names)
+
+	// Template directives make the file invalid YAML; extraction must not fail
+	templated := extractFileSymbols(t, manager, filepath.Join(dir, "templates", "deployment.yaml"), "{{- if .Values.enabled }}\nkind: Deployment\n{{- end }}\n")
+	assert.Empty(t, templated)
+}
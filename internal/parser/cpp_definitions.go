@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys pairing C/C++ function declarations with their definitions
+const (
+	MetadataDefinition     = "definition"      // true when the function has a body, false for a declaration
+	MetadataScope          = "scope"           // Enclosing namespaces and class, such as "math::Vec"
+	MetadataParameterTypes = "parameter_types" // Parameter types without names or defaults, such as "constint&,float*"
+)
+
+// recordDeclaration records whether a function symbol is a definition or a
+// declaration, its scope and its parameter types, so a declaration in a
+// header can be matched with its definition in the implementation file.
+// The scope joins the enclosing namespaces and class with the qualifier of
+// an out-of-line definition such as "void Mesh::draw()".
+func recordDeclaration(symbol *types.Symbol, node *types.ASTNode, parentContext *CppParentContext) {
+	declarator := findFunctionDeclarator(node)
+	if declarator == nil {
+		return
+	}
+
+	var scope []string
+	if parentContext != nil {
+		if parentContext.NamespaceName != "" {
+			scope = append(scope, parentContext.NamespaceName)
+		}
+		if parentContext.InClass && parentContext.ClassName != "" {
+			scope = append(scope, parentContext.ClassName)
+		}
+	}
+	var params []string
+	for _, child := range declarator.Children {
+		switch child.Type {
+		case "qualified_identifier":
+			if qualifier, _ := splitQualifiedName(child.Value); qualifier != "" {
+				scope = append(scope, qualifier)
+			}
+		case "parameter_list":
+			params = cppParameterTypes(child)
+		}
+	}
+
+	symbol.SetMetadata(MetadataDefinition, node.Type == "function_definition")
+	symbol.SetMetadata(MetadataScope, strings.Join(scope, "::"))
+	symbol.SetMetadata(MetadataParameterTypes, strings.Join(params, ","))
+}
+
+// splitQualifiedName splits "math::Vec<T>::size" into the qualifier without
+// template arguments, "math::Vec", and the name, "size"
+func splitQualifiedName(value string) (string, string) {
+	var parts []string
+	depth, start := 0, 0
+	value = strings.TrimSpace(value)
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ':':
+			if depth == 0 && i+1 < len(value) && value[i+1] == ':' {
+				parts = append(parts, value[start:i])
+				start = i + 2
+				i++
+			}
+		}
+	}
+	name := value[start:]
+	for i, part := range parts {
+		if idx := strings.Index(part, "<"); idx >= 0 {
+			parts[i] = part[:idx]
+		}
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return strings.Join(parts, "::"), strings.TrimSpace(name)
+}
+
+// cppParameterTypes returns the parameter types of a parameter list with
+// names, default values and whitespace removed, so "const int& n" and
+// "const int &" compare equal. "(void)" has no parameters.
+func cppParameterTypes(list *types.ASTNode) []string {
+	var params []string
+	for _, param := range list.Children {
+		if param.Type != "parameter_declaration" && param.Type != "optional_parameter_declaration" && param.Type != "variadic_parameter_declaration" {
+			continue
+		}
+		text := param.Value
+		if param.Type == "optional_parameter_declaration" {
+			if idx := strings.Index(text, "="); idx >= 0 {
+				text = text[:idx]
+			}
+		}
+		if name := parameterName(param); name != "" {
+			if idx := strings.LastIndex(text, name); idx >= 0 {
+				text = text[:idx] + text[idx+len(name):]
+			}
+		}
+		params = append(params, strings.Join(strings.Fields(text), ""))
+	}
+	if len(params) == 1 && params[0] == "void" {
+		return nil
+	}
+	return params
+}
+
+// parameterName finds the name a parameter declarator introduces, if any
+func parameterName(node *types.ASTNode) string {
+	for _, child := range node.Children {
+		switch child.Type {
+		case "identifier":
+			return strings.TrimSpace(child.Value)
+		case "pointer_declarator", "reference_declarator", "array_declarator":
+			if name := parameterName(child); name != "" {
+				return name
+			}
+		case "=":
+			return ""
+		}
+	}
+	return ""
+}
+
+// cppNamespaceName returns the name of a namespace definition, with nested
+// names such as "a::b" joined, or "" for an anonymous namespace
+func cppNamespaceName(node *types.ASTNode) string {
+	for _, child := range node.Children {
+		switch child.Type {
+		case "namespace_identifier", "identifier", "nested_namespace_specifier":
+			return strings.Join(strings.Fields(child.Value), "")
+		}
+	}
+	return ""
+}
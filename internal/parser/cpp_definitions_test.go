@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCppDeclarationsAndDefinitions(t *testing.T) {
+	header := "namespace geo {\nclass Mesh {\npublic:\n    Mesh(int vertices);\n    void draw(const Shader& shader, float scale = 1.0f) const;\n};\nint count(void);\n}\n"
+	source := "#include \"mesh.h\"\nnamespace geo {\nMesh::Mesh(int n) {}\nvoid Mesh::draw(const Shader &s, float) const {}\n}\nint geo::count() { return 0; }\n"
+
+	_, declarations, _ := parseDialect(t, "mesh.h", header)
+	_, definitions, _ := parseDialect(t, "mesh.cpp", source)
+
+	for _, name := range []string{"draw", "count"} {
+		require.NotNil(t, declarations[name], name)
+		require.NotNil(t, definitions[name], name)
+		assert.Equal(t, false, declarations[name].Metadata[MetadataDefinition], name)
+		assert.Equal(t, true, definitions[name].Metadata[MetadataDefinition], name)
+	}
+	assert.Equal(t, "geo::Mesh", declarations["draw"].MetadataString(MetadataScope))
+	assert.Equal(t, "geo::Mesh", definitions["draw"].MetadataString(MetadataScope))
+	assert.Equal(t, "constShader&,float", declarations["draw"].MetadataString(MetadataParameterTypes))
+	assert.Equal(t, "constShader&,float", definitions["draw"].MetadataString(MetadataParameterTypes))
+	assert.Equal(t, "geo", declarations["count"].MetadataString(MetadataScope))
+	assert.Equal(t, "geo", definitions["count"].MetadataString(MetadataScope))
+	assert.Equal(t, "", declarations["count"].MetadataString(MetadataParameterTypes), "(void) has no parameters")
+}
+
+func TestSplitQualifiedName(t *testing.T) {
+	qualifier, name := splitQualifiedName("math::Vec<T, std::size_t>::size")
+	assert.Equal(t, "math::Vec", qualifier)
+	assert.Equal(t, "size", name)
+	qualifier, name = splitQualifiedName("draw")
+	assert.Equal(t, "", qualifier)
+	assert.Equal(t, "draw", name)
+}
@@ -0,0 +1,247 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// C++ dialects parsed with the C++ grammar
+const (
+	CppDialectObjectiveCpp = "objective-c++"
+	CppDialectCuda         = "cuda"
+	CppDialectMetal        = "metal"
+)
+
+// Symbol and AST metadata keys for C++ dialects
+const (
+	MetadataDialect         = "dialect"         // Set on the AST root of files outside standard C++
+	MetadataCudaQualifiers  = "cuda_qualifiers" // __global__, __device__ and __host__ without underscores
+	MetadataKernelLaunches  = "kernel_launches" // Kernels a function launches with <<<...>>>
+	MetadataShaderStage     = "shader_stage"    // Metal kernel, vertex, fragment, mesh or object function
+	MetadataObjcCategory    = "category"        // Category of an Objective-C class extension
+	MetadataObjcSelector    = "selector"        // Full selector of an Objective-C method, such as "draw:inRect:"
+	MetadataObjcClassMethod = "class_method"    // Set on Objective-C + methods
+)
+
+// cppDialectExtensions maps file extensions to the dialect they are written in
+var cppDialectExtensions = map[string]string{
+	".mm":    CppDialectObjectiveCpp,
+	".cu":    CppDialectCuda,
+	".cuh":   CppDialectCuda,
+	".metal": CppDialectMetal,
+}
+
+var (
+	// Objective-C containers, closed by @end
+	objcContainer = regexp.MustCompile(`(?m)^[ \t]*@(interface|implementation|protocol)[ \t]+(\w+)[ \t]*(?:\(([^)\n]*)\))?[ \t]*(?::[ \t]*(\w+))?[ \t]*(?:<([^>\n]*)>)?`)
+	objcEnd       = regexp.MustCompile(`(?m)^[ \t]*@end\b`)
+	// Single-line Objective-C declarations: forward declarations and module imports
+	objcDirective = regexp.MustCompile(`(?m)^[ \t]*@(?:class|protocol|import)\b[^;\n{]*;`)
+	// Methods and properties inside a container
+	objcMethod   = regexp.MustCompile(`(?m)^[ \t]*([-+])[ \t]*\([^)\n]*\)[ \t]*(\w+)((?:[ \t]*:[ \t]*(?:\([^)\n]*\))?[ \t]*\w+(?:[ \t]+(\w+))?)*)`)
+	objcKeyword  = regexp.MustCompile(`(?:^|[ \t])(\w+)[ \t]*:`)
+	objcProperty = regexp.MustCompile(`(?m)^[ \t]*@property\b[^;\n]*?[ \t*](\w+)[ \t]*;`)
+	// An Objective-C module import such as @import Foundation;
+	objcModuleImport = regexp.MustCompile(`(?m)^[ \t]*@import[ \t]+([\w.]+)[ \t]*;`)
+	// An Objective-C file included as a header
+	objcMarker = regexp.MustCompile(`(?m)^[ \t]*@(?:interface|implementation|protocol)\b`)
+
+	cudaQualifier = regexp.MustCompile(`\b__(global|device|host)__\b`)
+	kernelLaunch  = regexp.MustCompile(`\b([A-Za-z_]\w*)[ \t]*(?:<[^<>;]*>)?[ \t]*<<<`)
+	shaderStage   = regexp.MustCompile(`^\s*(?:template\s*<[^>]*>\s*)?(?:\[\[[^\]]*\]\]\s*)*(kernel|vertex|fragment|mesh|object)\b`)
+)
+
+// CppDialect returns the dialect of a C++ file, or "" for standard C++.
+// Headers are Objective-C++ when they declare Objective-C classes or protocols.
+func CppDialect(filePath, content string) string {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	if dialect, ok := cppDialectExtensions[ext]; ok {
+		return dialect
+	}
+	if (ext == ".h" || ext == ".hh" || ext == ".hpp") && objcMarker.MatchString(content) {
+		return CppDialectObjectiveCpp
+	}
+	return ""
+}
+
+// maskObjectiveC blanks the Objective-C containers and directives of an
+// Objective-C++ file so the C++ grammar parses the C++ code around them.
+// Newlines are kept, so lines and offsets still match the original content.
+func maskObjectiveC(content string) string {
+	masked := []byte(content)
+	blank := func(start, end int) {
+		for i := start; i < end; i++ {
+			if masked[i] != '\n' {
+				masked[i] = ' '
+			}
+		}
+	}
+	for _, r := range objcContainerRanges(content) {
+		blank(r[0], r[1])
+	}
+	for _, m := range objcDirective.FindAllStringIndex(content, -1) {
+		blank(m[0], m[1])
+	}
+	return string(masked)
+}
+
+// objcContainerRanges returns the byte ranges of the Objective-C containers,
+// each from its @interface, @implementation or @protocol to its @end
+func objcContainerRanges(content string) [][2]int {
+	var ranges [][2]int
+	offset := 0
+	for {
+		m := objcContainer.FindStringIndex(content[offset:])
+		if m == nil {
+			return ranges
+		}
+		start := offset + m[0]
+		end := len(content)
+		if e := objcEnd.FindStringIndex(content[offset+m[1]:]); e != nil {
+			end = offset + m[1] + e[1]
+		}
+		ranges = append(ranges, [2]int{start, end})
+		offset = end
+	}
+}
+
+// objcSymbols extracts the classes, protocols, categories, methods and
+// properties declared in the Objective-C containers of a file
+func objcSymbols(filePath, content string) []*types.Symbol {
+	var symbols []*types.Symbol
+	lineOf := func(offset int) int { return strings.Count(content[:offset], "\n") + 1 }
+	newSymbol := func(kind, name string, symbolType types.SymbolType, offset int, signature string) *types.Symbol {
+		line := lineOf(offset)
+		return &types.Symbol{
+			Id:           types.SymbolId(fmt.Sprintf("objc-%s-%s-%d", kind, filePath, line)),
+			Name:         name,
+			Type:         symbolType,
+			Location:     types.Location{StartLine: line, StartColumn: 1, EndLine: line, EndColumn: len(signature) + 1},
+			Signature:    signature,
+			Language:     "cpp",
+			Hash:         calculateHash(signature),
+			LastModified: time.Now(),
+			Visibility:   "public",
+		}
+	}
+
+	declared := make(map[string]bool)
+	for _, r := range objcContainerRanges(content) {
+		body := content[r[0]:r[1]]
+		m := objcContainer.FindStringSubmatchIndex(body)
+		keyword, name := body[m[2]:m[3]], body[m[4]:m[5]]
+		header := strings.TrimSpace(body[m[0]:m[1]])
+		var protocols []string
+		if m[10] >= 0 {
+			for _, p := range strings.Split(body[m[10]:m[11]], ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					protocols = append(protocols, p)
+				}
+			}
+		}
+
+		var container *types.Symbol
+		switch {
+		case keyword == "protocol":
+			container = newSymbol("protocol", name, types.SymbolTypeInterface, r[0], header)
+			if len(protocols) > 0 {
+				container.SetMetadata(MetadataExtends, protocols)
+			}
+		case m[6] >= 0:
+			container = newSymbol("category", name, types.SymbolTypeExtension, r[0], header)
+			container.SetMetadata(MetadataObjcCategory, strings.TrimSpace(body[m[6]:m[7]]))
+		case keyword == "interface" || !declared[name]:
+			container = newSymbol("class", name, types.SymbolTypeClass, r[0], header)
+			if m[8] >= 0 {
+				container.SetMetadata(MetadataExtends, []string{body[m[8]:m[9]]})
+			}
+			if len(protocols) > 0 {
+				container.SetMetadata(MetadataImplements, protocols)
+			}
+			declared[name] = true
+		}
+		if container != nil {
+			symbols = append(symbols, container)
+		}
+
+		for _, mm := range objcMethod.FindAllStringSubmatchIndex(body, -1) {
+			selector := body[mm[4]:mm[5]]
+			if mm[6] >= 0 && mm[7] > mm[6] {
+				keywords := objcKeyword.FindAllStringSubmatch(selector+body[mm[6]:mm[7]], -1)
+				selector = ""
+				for _, k := range keywords {
+					selector += k[1] + ":"
+				}
+			}
+			signature := strings.TrimSpace(body[mm[0]:mm[1]])
+			method := newSymbol("method", selector, types.SymbolTypeMethod, r[0]+mm[0], signature)
+			method.SetMetadata(MetadataObjcSelector, selector)
+			if body[mm[2]:mm[3]] == "+" {
+				method.SetMetadata(MetadataObjcClassMethod, true)
+			}
+			symbols = append(symbols, method)
+		}
+		for _, pm := range objcProperty.FindAllStringSubmatchIndex(body, -1) {
+			symbols = append(symbols, newSymbol("property", body[pm[2]:pm[3]], types.SymbolTypeProperty, r[0]+pm[0], strings.TrimSpace(body[pm[0]:pm[1]])))
+		}
+	}
+	return symbols
+}
+
+// objcModuleImports returns the @import module imports of an Objective-C++ file
+func objcModuleImports(content, filePath string) []*types.Import {
+	var imports []*types.Import
+	for _, m := range objcModuleImport.FindAllStringSubmatchIndex(content, -1) {
+		imports = append(imports, &types.Import{
+			Path:     content[m[2]:m[3]],
+			Kind:     types.ImportKindModule,
+			Module:   true,
+			Location: types.FileLocation{FilePath: filePath, Line: strings.Count(content[:m[0]], "\n") + 1, Column: 1},
+		})
+	}
+	return imports
+}
+
+// applyDialect records what a CUDA or Metal declaration says beyond
+// standard C++: CUDA execution space qualifiers and kernel launches, and
+// the pipeline stage of Metal shader functions
+func applyDialect(symbol *types.Symbol, node *types.ASTNode, dialect string) {
+	if symbol.Type != types.SymbolTypeFunction && symbol.Type != types.SymbolTypeTemplate && symbol.Type != types.SymbolTypeMethod {
+		return
+	}
+	header := node.Value
+	if idx := strings.IndexAny(header, "({"); idx >= 0 {
+		header = header[:idx]
+	}
+	switch dialect {
+	case CppDialectCuda:
+		var qualifiers []string
+		for _, m := range cudaQualifier.FindAllStringSubmatch(header, -1) {
+			if !slices.Contains(qualifiers, m[1]) {
+				qualifiers = append(qualifiers, m[1])
+			}
+		}
+		if len(qualifiers) > 0 {
+			symbol.SetMetadata(MetadataCudaQualifiers, qualifiers)
+		}
+		var launches []string
+		for _, m := range kernelLaunch.FindAllStringSubmatch(node.Value, -1) {
+			if !slices.Contains(launches, m[1]) {
+				launches = append(launches, m[1])
+			}
+		}
+		if len(launches) > 0 {
+			symbol.SetMetadata(MetadataKernelLaunches, launches)
+		}
+	case CppDialectMetal:
+		if m := shaderStage.FindStringSubmatch(header); m != nil {
+			symbol.SetMetadata(MetadataShaderStage, m[1])
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseDialect parses a file and returns its symbols by name and its imports
+func parseDialect(t *testing.T, filePath, content string) (*types.AST, map[string]*types.Symbol, []*types.Import) {
+	manager := NewManager()
+	ast, err := manager.Parse(content, filePath)
+	require.NoError(t, err)
+	require.Equal(t, "cpp", ast.Language)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	byName := make(map[string]*types.Symbol)
+	for _, symbol := range symbols {
+		if byName[symbol.Name] == nil {
+			byName[symbol.Name] = symbol
+		}
+	}
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+	return ast, byName, imports
+}
+
+func TestCudaDialect(t *testing.T) {
+	content := "#include \"kernels.cuh\"\n__global__ void saxpy(int n, float a, const float* x, float* y) {\n    y[threadIdx.x] += a * x[threadIdx.x];\n}\n" +
+		"__host__ __device__ float sq(float v) { return v * v; }\n" +
+		"void launch(int n, float* x, float* y) {\n    saxpy<<<(n + 255) / 256, 256>>>(n, 2.0f, x, y);\n    fill<float><<<1, 32>>>(y);\n}\n"
+
+	ast, symbols, imports := parseDialect(t, "kernels.cu", content)
+	assert.Equal(t, CppDialectCuda, ast.Root.Metadata[MetadataDialect])
+	require.NotNil(t, symbols["saxpy"])
+	assert.Equal(t, []string{"global"}, symbols["saxpy"].MetadataStrings(MetadataCudaQualifiers))
+	assert.Equal(t, []string{"host", "device"}, symbols["sq"].MetadataStrings(MetadataCudaQualifiers))
+	assert.Equal(t, []string{"saxpy", "fill"}, symbols["launch"].MetadataStrings(MetadataKernelLaunches))
+	assert.Nil(t, symbols["launch"].Metadata[MetadataCudaQualifiers])
+	require.Len(t, imports, 1)
+	assert.Equal(t, "kernels.cuh", imports[0].Path)
+}
+
+func TestMetalDialect(t *testing.T) {
+	content := "#include <metal_stdlib>\nusing namespace metal;\n" +
+		"kernel void add_arrays(device const float* inA [[buffer(0)]], device float* out [[buffer(1)]], uint index [[thread_position_in_grid]]) {\n    out[index] = inA[index];\n}\n" +
+		"vertex float4 vertex_main(uint vid [[vertex_id]], constant float4* pos [[buffer(0)]]) {\n    return pos[vid];\n}\n" +
+		"float helper(float v) { return v; }\n"
+
+	ast, symbols, _ := parseDialect(t, "Shaders.metal", content)
+	assert.Equal(t, CppDialectMetal, ast.Root.Metadata[MetadataDialect])
+	require.NotNil(t, symbols["add_arrays"])
+	assert.Equal(t, "kernel", symbols["add_arrays"].MetadataString(MetadataShaderStage))
+	assert.Equal(t, "vertex", symbols["vertex_main"].MetadataString(MetadataShaderStage))
+	assert.Equal(t, "", symbols["helper"].MetadataString(MetadataShaderStage))
+}
+
+func TestObjectiveCppDialect(t *testing.T) {
+	content := "#import <Foundation/Foundation.h>\n#import \"Renderer.h\"\n@import Metal;\n#include <vector>\n\n" +
+		"@protocol Drawable <NSObject>\n- (void)draw;\n@end\n\n" +
+		"@interface Renderer : NSObject <Drawable, MTKViewDelegate>\n- (void)drawFrame:(int)count inView:(MTKView *)view;\n@property (nonatomic, strong) id<MTLDevice> device;\n@end\n\n" +
+		"class Mesh {\npublic:\n    void upload();\n};\n\n" +
+		"@implementation Renderer\n- (void)drawFrame:(int)count inView:(MTKView *)view {\n    std::vector<int> v;\n    [self setNeedsDisplay:YES];\n}\n+ (instancetype)shared { return nil; }\n@end\n\n" +
+		"@interface Renderer (Debug)\n- (void)dump;\n@end\n"
+
+	ast, symbols, imports := parseDialect(t, "Renderer.mm", content)
+	assert.Equal(t, CppDialectObjectiveCpp, ast.Root.Metadata[MetadataDialect])
+	assert.Equal(t, content, ast.Content, "the AST keeps the original source")
+
+	require.NotNil(t, symbols["Mesh"], "C++ around the Objective-C sections still parses")
+	assert.Equal(t, types.SymbolTypeClass, symbols["Mesh"].Type)
+	assert.NotNil(t, symbols["upload"])
+
+	renderer := symbols["Renderer"]
+	require.NotNil(t, renderer)
+	assert.Equal(t, types.SymbolTypeClass, renderer.Type)
+	assert.Equal(t, 10, renderer.Location.StartLine)
+	assert.Equal(t, []string{"NSObject"}, renderer.MetadataStrings(MetadataExtends))
+	assert.Equal(t, []string{"Drawable", "MTKViewDelegate"}, renderer.MetadataStrings(MetadataImplements))
+	assert.Equal(t, types.SymbolTypeInterface, symbols["Drawable"].Type)
+
+	method := symbols["drawFrame:inView:"]
+	require.NotNil(t, method)
+	assert.Equal(t, types.SymbolTypeMethod, method.Type)
+	assert.Equal(t, 11, method.Location.StartLine)
+	assert.Equal(t, true, symbols["shared"].Metadata[MetadataObjcClassMethod])
+	assert.Equal(t, types.SymbolTypeProperty, symbols["device"].Type)
+	assert.NotNil(t, symbols["dump"])
+
+	var paths []string
+	for _, imp := range imports {
+		paths = append(paths, imp.Path)
+	}
+	assert.ElementsMatch(t, []string{"Foundation/Foundation.h", "Renderer.h", "Metal", "vector"}, paths)
+}
+
+func TestCppDialect(t *testing.T) {
+	assert.Equal(t, CppDialectCuda, CppDialect("ops/kernel.cuh", ""))
+	assert.Equal(t, CppDialectObjectiveCpp, CppDialect("Renderer.h", "#import <UIKit/UIKit.h>\n@interface Renderer : NSObject\n@end\n"))
+	assert.Equal(t, "", CppDialect("vec.h", "struct Vec {};\n"))
+	assert.Equal(t, "", CppDialect("main.cpp", "@interface X\n@end\n"))
+
+	content := "@interface A : NSObject\n- (void)run;\n@end\nint x;\n"
+	masked := maskObjectiveC(content)
+	assert.Equal(t, len(content), len(masked))
+	assert.Equal(t, strings.Count(content, "\n"), strings.Count(masked, "\n"))
+	assert.True(t, strings.HasSuffix(masked, "\nint x;\n"))
+	assert.NotContains(t, masked, "@")
+}
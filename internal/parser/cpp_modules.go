@@ -0,0 +1,245 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for C++20 modules
+const (
+	MetadataModuleUnit = "module_unit" // One of the CppModuleUnit kinds
+	MetadataExports    = "exports"     // Names exported by a module unit
+	MetadataExported   = "exported"    // Set on declarations a module unit exports
+)
+
+// C++20 module unit kinds
+const (
+	CppModuleInterface         = "interface"          // export module m;
+	CppModuleImplementation    = "implementation"     // module m;
+	CppModulePartition         = "partition"          // export module m:part;
+	CppModuleInternalPartition = "internal_partition" // module m:part;
+)
+
+// The grammar predates C++20 modules and parses these lines as broken
+// declarations, so module structure is read from the source text
+var (
+	// A named module declaration; "module;" and "module :private;" have no name
+	cppModuleDeclaration = regexp.MustCompile(`(?m)^[ \t]*(export[ \t]+)?module[ \t]+([A-Za-z_][\w.]*)[ \t]*(?::[ \t]*([A-Za-z_][\w.]*))?[ \t]*;`)
+	// An import of a module, a partition of the current module or a header unit
+	cppModuleImport = regexp.MustCompile(`(?m)^[ \t]*(export[ \t]+)?import[ \t]+(?:([A-Za-z_][\w.]*)|:[ \t]*([A-Za-z_][\w.]*)|<([^>\n]+)>|"([^"\n]+)")[ \t]*;`)
+	// An exported declaration, block or namespace
+	cppModuleExport = regexp.MustCompile(`(?m)^[ \t]*export\b[ \t]*(\{|namespace\b[^{;\n]*\{)?`)
+)
+
+// CppModuleUnit is the module declaration of a C++20 module unit
+type CppModuleUnit struct {
+	Module    string // Module name, such as "math.core"
+	Partition string // Partition name, empty outside partitions
+	Exported  bool   // Declared with export module
+	Line      int
+	Text      string
+}
+
+// Name returns the module name with its partition, such as "math:detail"
+func (u *CppModuleUnit) Name() string {
+	if u.Partition == "" {
+		return u.Module
+	}
+	return u.Module + ":" + u.Partition
+}
+
+// Kind returns the unit kind, one of the CppModule constants
+func (u *CppModuleUnit) Kind() string {
+	switch {
+	case u.Partition == "" && u.Exported:
+		return CppModuleInterface
+	case u.Partition == "":
+		return CppModuleImplementation
+	case u.Exported:
+		return CppModulePartition
+	default:
+		return CppModuleInternalPartition
+	}
+}
+
+// ParseCppModuleUnit returns the module declaration of a C++ source file,
+// or nil when the file is not a named module unit
+func ParseCppModuleUnit(content string) *CppModuleUnit {
+	m := cppModuleDeclaration.FindStringSubmatchIndex(content)
+	if m == nil {
+		return nil
+	}
+	unit := &CppModuleUnit{
+		Module:   content[m[4]:m[5]],
+		Exported: m[2] >= 0,
+		Line:     strings.Count(content[:m[0]], "\n") + 1,
+		Text:     strings.TrimSpace(content[m[0]:m[1]]),
+	}
+	if m[6] >= 0 {
+		unit.Partition = content[m[6]:m[7]]
+	}
+	return unit
+}
+
+// cppModuleImports returns the module and header unit imports of a C++
+// source file. Partition imports such as "import :detail;" are qualified
+// with the current module name. A module implementation unit implicitly
+// imports its primary interface, which is recorded as an import on the
+// module declaration line.
+func cppModuleImports(content, filePath string) []*types.Import {
+	unit := ParseCppModuleUnit(content)
+	var imports []*types.Import
+	if unit != nil && unit.Kind() == CppModuleImplementation {
+		imports = append(imports, &types.Import{
+			Path:     unit.Module,
+			Kind:     types.ImportKindModule,
+			Module:   true,
+			Location: types.FileLocation{FilePath: filePath, Line: unit.Line, Column: 1},
+		})
+	}
+
+	for _, m := range cppModuleImport.FindAllStringSubmatchIndex(content, -1) {
+		imp := &types.Import{
+			Kind:     types.ImportKindModule,
+			Location: types.FileLocation{FilePath: filePath, Line: strings.Count(content[:m[0]], "\n") + 1, Column: 1},
+		}
+		switch {
+		case m[4] >= 0:
+			imp.Path = content[m[4]:m[5]]
+			imp.Module = true
+		case m[6] >= 0:
+			if unit == nil {
+				continue
+			}
+			imp.Path = unit.Module + ":" + content[m[6]:m[7]]
+			imp.Module = true
+		case m[8] >= 0:
+			imp.Path = strings.TrimSpace(content[m[8]:m[9]])
+			imp.IsSystem = true
+			imp.Kind = types.ImportKindHeaderUnit
+		default:
+			imp.Path = content[m[10]:m[11]]
+			imp.Kind = types.ImportKindHeaderUnit
+		}
+		if m[2] >= 0 {
+			imp.Kind = types.ImportKindReexport
+		}
+		imports = append(imports, imp)
+	}
+	return imports
+}
+
+// cppExportedLines returns the line ranges a module unit exports, single
+// export declarations and the bodies of export blocks and exported
+// namespaces, and the names of the exported namespaces
+func cppExportedLines(content string) ([][2]int, []string) {
+	var ranges [][2]int
+	var namespaces []string
+	for _, m := range cppModuleExport.FindAllStringSubmatchIndex(content, -1) {
+		rest := strings.TrimSpace(content[m[1]:])
+		if m[2] < 0 && (strings.HasPrefix(rest, "module") || strings.HasPrefix(rest, "import")) {
+			continue
+		}
+		start := strings.Count(content[:m[0]], "\n") + 1
+		end := start
+		if m[2] >= 0 {
+			end += strings.Count(content[m[0]:cppClosingBrace(content, m[1]-1)], "\n")
+			if block := content[m[2]:m[3]]; strings.HasPrefix(block, "namespace") {
+				if name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(block, "namespace"), "{")); name != "" {
+					namespaces = append(namespaces, name)
+				}
+			}
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+	return ranges, namespaces
+}
+
+// cppClosingBrace returns the offset of the brace closing the one at open,
+// or the end of the content when it is never closed
+func cppClosingBrace(content string, open int) int {
+	depth := 0
+	for i := open; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(content)
+}
+
+// cppExportKinds are the symbol types listed in a module unit's exports;
+// members of exported classes are reachable through their class
+var cppExportKinds = map[types.SymbolType]bool{
+	types.SymbolTypeClass:      true,
+	types.SymbolTypeFunction:   true,
+	types.SymbolTypeTemplate:   true,
+	types.SymbolTypeNamespace:  true,
+	types.SymbolTypeVariable:   true,
+	types.SymbolTypeConstant:   true,
+	types.SymbolTypeOperator:   true,
+	types.SymbolTypeCppTypedef: true,
+	types.SymbolTypeCppUsing:   true,
+}
+
+// cppModuleKeywords are the names the grammar gives declarations it misreads
+// on export, import and module lines
+var cppModuleKeywords = map[string]bool{"export": true, "import": true, "module": true}
+
+// applyModuleUnit marks the exported declarations of a C++20 module unit,
+// drops the declarations misread from module syntax and appends a module
+// symbol naming the unit and what it exports. Files outside named modules
+// are returned unchanged.
+func (cp *CppParser) applyModuleUnit(symbols []*types.Symbol, filePath, content string) []*types.Symbol {
+	unit := ParseCppModuleUnit(content)
+	if unit == nil {
+		return symbols
+	}
+
+	ranges, exports := cppExportedLines(content)
+	kept := symbols[:0]
+	for _, symbol := range symbols {
+		if cppModuleKeywords[symbol.Name] {
+			continue
+		}
+		kept = append(kept, symbol)
+		for _, r := range ranges {
+			if symbol.Location.StartLine >= r[0] && symbol.Location.StartLine <= r[1] {
+				symbol.SetMetadata(MetadataExported, true)
+				if cppExportKinds[symbol.Type] && !slices.Contains(exports, symbol.Name) {
+					exports = append(exports, symbol.Name)
+				}
+				break
+			}
+		}
+	}
+	sort.Strings(exports)
+
+	symbol := &types.Symbol{
+		Id:           types.SymbolId(fmt.Sprintf("module-%s-%d", filePath, unit.Line)),
+		Name:         unit.Name(),
+		Type:         types.SymbolTypeModule,
+		Location:     types.Location{StartLine: unit.Line, StartColumn: 1, EndLine: unit.Line, EndColumn: len(unit.Text) + 1},
+		Signature:    unit.Text,
+		Language:     "cpp",
+		Hash:         calculateHash(unit.Text),
+		LastModified: time.Now(),
+		Visibility:   "public",
+	}
+	symbol.SetMetadata(MetadataModuleUnit, unit.Kind())
+	if len(exports) > 0 {
+		symbol.SetMetadata(MetadataExports, exports)
+	}
+	return append(kept, symbol)
+}
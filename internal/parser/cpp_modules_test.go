@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCppModuleUnit(t *testing.T) {
+	manager := NewManager()
+	content := "module;\n#include <cmath>\nexport module math;\n\nimport std;\nimport :detail;\nexport import math.vec;\nimport <vector>;\nimport \"legacy.h\";\n\n" +
+		"export int add(int a, int b) { return a + b; }\n\nexport namespace geo {\n    double dist(double a, double b);\n}\n\n" +
+		"struct Point { int x, y; };\n\nmodule :private;\nint hidden() { return 1; }\n"
+
+	ast, err := manager.Parse(content, "math.cppm")
+	require.NoError(t, err)
+	assert.Equal(t, "cpp", ast.Language)
+
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	exported := make(map[string]bool)
+	var module *types.Symbol
+	for _, symbol := range symbols {
+		if symbol.Type == types.SymbolTypeModule {
+			module = symbol
+		}
+		exported[symbol.Name] = symbol.Metadata[MetadataExported] == true
+	}
+	require.NotNil(t, module)
+	assert.Equal(t, "math", module.Name)
+	assert.Equal(t, 3, module.Location.StartLine)
+	assert.Equal(t, CppModuleInterface, module.MetadataString(MetadataModuleUnit))
+	assert.Equal(t, []string{"add", "dist", "geo"}, module.MetadataStrings(MetadataExports))
+	assert.True(t, exported["add"])
+	assert.False(t, exported["Point"], "declarations outside export are module-private")
+	assert.False(t, exported["hidden"])
+
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+	got := make(map[string]types.Import)
+	for _, imp := range imports {
+		got[imp.Path] = *imp
+	}
+	assert.Len(t, got, 6, "cmath, std, math:detail, math.vec, vector and legacy.h")
+	assert.True(t, got["std"].Module)
+	assert.Equal(t, types.ImportKindModule, got["std"].Kind)
+	assert.Equal(t, 5, got["std"].Location.Line)
+	assert.True(t, got["math:detail"].Module, "partition imports are qualified with the current module")
+	assert.Equal(t, types.ImportKindReexport, got["math.vec"].Kind)
+	assert.True(t, got["vector"].IsSystem)
+	assert.False(t, got["vector"].Module)
+	assert.Equal(t, types.ImportKindHeaderUnit, got["legacy.h"].Kind)
+	assert.False(t, got["legacy.h"].IsSystem)
+}
+
+func TestParseCppModuleUnit(t *testing.T) {
+	tests := []struct {
+		content string
+		name    string
+		kind    string
+	}{
+		{"export module math;", "math", CppModuleInterface},
+		{"module math.core;\nint f();", "math.core", CppModuleImplementation},
+		{"export module math:detail;", "math:detail", CppModulePartition},
+		{"module;\n#include <x>\nmodule math : impl;", "math:impl", CppModuleInternalPartition},
+	}
+	for _, tt := range tests {
+		unit := ParseCppModuleUnit(tt.content)
+		require.NotNil(t, unit, tt.content)
+		assert.Equal(t, tt.name, unit.Name())
+		assert.Equal(t, tt.kind, unit.Kind())
+	}
+
+	assert.Nil(t, ParseCppModuleUnit("module;\n#include <x>\nint f();\n"), "a global module fragment alone is not a module unit")
+	assert.Nil(t, ParseCppModuleUnit("// module math;\nint f();\n"))
+
+	imports := cppModuleImports("module math;\nimport :detail;\n", "math.cpp")
+	require.Len(t, imports, 2)
+	assert.Equal(t, "math", imports[0].Path, "an implementation unit implicitly imports its interface")
+	assert.Equal(t, 1, imports[0].Location.Line)
+	assert.Equal(t, "math:detail", imports[1].Path)
+}
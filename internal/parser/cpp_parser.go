@@ -116,8 +116,15 @@ func (cp *CppParser) ParseContent(ctx context.Context, content, filePath string)
 		return nil, err
 	}
 	
+	// Objective-C sections are blanked so the C++ grammar only sees C++
+	dialect := CppDialect(filePath, content)
+	parsed := content
+	if dialect == CppDialectObjectiveCpp {
+		parsed = maskObjectiveC(content)
+	}
+
 	// Parse content with tree-sitter
-	tree, err := cp.parseWithTreeSitter(ctx, content, filePath)
+	tree, err := cp.parseWithTreeSitter(ctx, parsed, filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +136,11 @@ func (cp *CppParser) ParseContent(ctx context.Context, content, filePath string)
 	}()
 	
 	// Build and return AST
-	ast := cp.buildAST(tree, content, filePath, start)
+	ast := cp.buildAST(tree, parsed, filePath, start)
+	ast.Content = content
+	if dialect != "" && ast.Root != nil {
+		ast.Root.Metadata[MetadataDialect] = dialect
+	}
 	
 	parseTime := time.Since(start)
 	cp.logger.Info("C++ parsing completed", 
@@ -258,6 +269,9 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 	}
 	switch node.Type {
 	case "class_specifier":
+		if isSpecializedClass(node) {
+			return nil
+		}
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("class-%s-%d", ctx.FilePath, node.Location.Line)),
 			Name:         cp.extractCppClassName(node),
@@ -269,6 +283,9 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 			Visibility:   cp.extractVisibility(node, ctx.ParentCtx),
 		}
 	case "struct_specifier":
+		if isSpecializedClass(node) {
+			return nil
+		}
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("struct-%s-%d", ctx.FilePath, node.Location.Line)),
 			Name:         cp.extractCppClassName(node),
@@ -288,7 +305,7 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 		symbolType, visibility := cp.classifyFunction(node, ctx.ParentCtx)
 		signature := cp.extractEnhancedFunctionSignature(node)
 		
-		return &types.Symbol{
+		symbol := &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("func-%s-%d", ctx.FilePath, node.Location.Line)),
 			Name:         cp.extractCppFunctionName(node),
 			Type:         symbolType,
@@ -299,6 +316,8 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 			LastModified: time.Now(),
 			Visibility:   visibility,
 		}
+		recordDeclaration(symbol, node, ctx.ParentCtx)
+		return symbol
 	case "namespace_definition":
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("namespace-%s-%d", ctx.FilePath, node.Location.Line)),
@@ -315,7 +334,7 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 			symbolType, visibility := cp.classifyFunction(node, ctx.ParentCtx)
 			signature := cp.extractEnhancedFunctionSignature(node)
 			
-			return &types.Symbol{
+			symbol := &types.Symbol{
 				Id:           types.SymbolId(fmt.Sprintf("func-%s-%d", ctx.FilePath, node.Location.Line)),
 				Name:         cp.extractCppFunctionName(node),
 				Type:         symbolType,
@@ -326,6 +345,8 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 				LastModified: time.Now(),
 				Visibility:   visibility,
 			}
+			recordDeclaration(symbol, node, ctx.ParentCtx)
+			return symbol
 		} else {
 			// Regular field declaration
 			fieldName := cp.extractCppFieldName(node)
@@ -344,7 +365,7 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 			}
 		}
 	case "template_declaration":
-		return &types.Symbol{
+		symbol := &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("template-%s-%d", ctx.FilePath, node.Location.Line)),
 			Name:         cp.extractTemplateName(node),
 			Type:         types.SymbolTypeTemplate,
@@ -354,6 +375,12 @@ func (cp *CppParser) NodeToSymbolWithContext(node *types.ASTNode, ctx *SymbolExt
 			LastModified: time.Now(),
 			Signature:    cp.extractTemplateSignature(node),
 		}
+		recordSpecialization(symbol, node)
+		return symbol
+	case "template_instantiation":
+		return cp.instantiationToSymbol(node, ctx)
+	case "preproc_def", "preproc_function_def":
+		return cp.macroToSymbol(node, ctx)
 	case "preproc_include":
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("include-%s-%d", ctx.FilePath, node.Location.Line)),
@@ -375,8 +402,15 @@ type CppParentContext struct {
 	ClassName     string
 	CurrentAccess string // "private", "public", "protected"
 	InNamespace   bool
-	NamespaceName string
+	NamespaceName string // Enclosing namespaces, such as "a::b"
 	TemplateDepth int
+
+	// Preprocessor state
+	Conditions   []string // Active #if/#ifdef guards, outermost first
+	BranchConds  []string // Conditions of earlier branches in the innermost #if chain
+	IncludeGuard string   // Macro name of the enclosing include guard, if any
+
+	Dialect string // CUDA, Metal or Objective-C++; empty for standard C++
 }
 
 // SymbolExtractionContext groups related parameters for symbol extraction
@@ -416,6 +450,24 @@ func (cp *CppParser) classifyFunction(node *types.ASTNode, parentContext *CppPar
 		return types.SymbolTypeMethod, parentContext.CurrentAccess
 	}
 
+	// Out-of-line member definitions are qualified with their class
+	if declarator := findFunctionDeclarator(node); declarator != nil {
+		for _, child := range declarator.Children {
+			if child.Type != "qualified_identifier" {
+				continue
+			}
+			qualifier, _ := splitQualifiedName(child.Value)
+			switch {
+			case functionName == qualifier[strings.LastIndex(qualifier, ":")+1:]:
+				return types.SymbolTypeConstructor, "public"
+			case strings.HasPrefix(functionName, "~"):
+				return types.SymbolTypeDestructor, "public"
+			case strings.HasPrefix(functionName, "operator"):
+				return types.SymbolTypeOperator, "public"
+			}
+		}
+	}
+
 	// Top-level function
 	return types.SymbolTypeFunction, "public"
 }
@@ -538,10 +590,19 @@ func (cp *CppParser) extractCppClassName(node *types.ASTNode) string {
 			return strings.TrimSpace(child.Value)
 		}
 	}
+	// A specialization such as Vec<bool> belongs to Vec
+	if target := specializedClassName(node); target != nil {
+		name, _ := splitTemplateTarget(target)
+		return name
+	}
 	return cp.extractGenericSymbolName(node)
 }
 
 func (cp *CppParser) extractCppFunctionName(node *types.ASTNode) string {
+	// Functions returning pointers or references nest their declarator
+	if declarator := findFunctionDeclarator(node); declarator != nil {
+		node = &types.ASTNode{Children: []*types.ASTNode{declarator}}
+	}
 	// Look for function_declarator -> field_identifier, identifier, destructor_name, template_function, or operator_name
 	for _, child := range node.Children {
 		if child.Type == "function_declarator" {
@@ -549,6 +610,11 @@ func (cp *CppParser) extractCppFunctionName(node *types.ASTNode) string {
 				if grandchild.Type == "field_identifier" || grandchild.Type == "identifier" {
 					return strings.TrimSpace(grandchild.Value)
 				}
+				if grandchild.Type == "qualified_identifier" {
+					// Out-of-line definitions such as Mesh::draw are named without their scope
+					_, name := splitQualifiedName(grandchild.Value)
+					return name
+				}
 				if grandchild.Type == "operator_name" {
 					return strings.TrimSpace(grandchild.Value)
 				}
@@ -604,31 +670,24 @@ func (cp *CppParser) extractCppNamespaceName(node *types.ASTNode) string {
 func (cp *CppParser) extractTemplateName(node *types.ASTNode) string {
 	// Extract template name from template_declaration
 	for _, child := range node.Children {
-		if child.Type == "class_specifier" {
-			// Look for type_identifier (primary template) or template_type (specialization)
+		if child.Type == "class_specifier" || child.Type == "struct_specifier" || child.Type == "union_specifier" {
+			// For specializations like MyTemplate<int> or std::hash<Key>, extract the base name
+			if target := specializedClassName(child); target != nil {
+				name, _ := splitTemplateTarget(target)
+				return name
+			}
+			// Look for type_identifier (primary template)
 			for _, grandchild := range child.Children {
 				if grandchild.Type == "type_identifier" {
 					return strings.TrimSpace(grandchild.Value)
 				}
-				if grandchild.Type == "template_type" {
-					// For specializations like MyTemplate<int>, extract the base name
-					for _, ggchild := range grandchild.Children {
-						if ggchild.Type == "type_identifier" {
-							return strings.TrimSpace(ggchild.Value)
-						}
-					}
-					// Fallback: extract from the full template_type value
-					templateValue := strings.TrimSpace(grandchild.Value)
-					if idx := strings.Index(templateValue, "<"); idx > 0 {
-						return templateValue[:idx]
-					}
-					return templateValue
-				}
 			}
 			return cp.extractGenericSymbolName(child)
 		}
-		if child.Type == "function_definition" || child.Type == "function_declaration" {
-			return cp.extractCppFunctionName(child)
+		if child.Type == "function_definition" || child.Type == "function_declaration" || child.Type == "declaration" {
+			if child.Type != "declaration" || findFunctionDeclarator(child) != nil {
+				return cp.extractCppFunctionName(child)
+			}
 		}
 	}
 	return "template"
@@ -722,11 +781,18 @@ func (cp *CppParser) ExtractSymbolsWithContext(root *types.ASTNode, filePath, co
 	// Start with empty context
 	context := &CppParentContext{
 		CurrentAccess: "private", // C++ class default is private
+		Dialect:       CppDialect(filePath, content),
 	}
 	
 	if err := cp.extractSymbolsRecursive(root, filePath, content, context, &symbols); err != nil {
 		return nil, fmt.Errorf("failed to extract symbols: %w", err)
 	}
+
+	if context.Dialect == CppDialectObjectiveCpp {
+		symbols = append(symbols, objcSymbols(filePath, content)...)
+	}
+
+	symbols = cp.applyModuleUnit(symbols, filePath, content)
 	
 	return symbols, nil
 }
@@ -763,6 +829,9 @@ func (cp *CppParser) extractSymbolsRecursive(node *types.ASTNode, filePath, cont
 	// Extract symbol if this node represents one (but not for access specifiers themselves)
 	if node.Type != "access_specifier" {
 		if symbol := cp.NodeToSymbol(node, filePath, "cpp", content, newContext); symbol != nil {
+			applyPreprocessorConditions(symbol, newContext)
+			applyDialect(symbol, node, newContext.Dialect)
+			setSubtype(symbol, node)
 			*symbols = append(*symbols, symbol)
 		}
 	}
@@ -805,6 +874,7 @@ func (cp *CppParser) processClassBody(bodyNode *types.ASTNode, filePath, content
 				ParentCtx: childContext,
 			}
 			if symbol := cp.NodeToSymbolWithContext(child, childCtx); symbol != nil {
+				applyPreprocessorConditions(symbol, childContext)
 				*symbols = append(*symbols, symbol)
 			}
 			
@@ -841,10 +911,17 @@ func (cp *CppParser) updateContext(node *types.ASTNode, context *CppParentContex
 		
 	case "namespace_definition":
 		context.InNamespace = true
-		context.NamespaceName = cp.extractCppNamespaceName(node)
+		if name := cppNamespaceName(node); name != "" && context.NamespaceName != "" {
+			context.NamespaceName += "::" + name
+		} else if name != "" {
+			context.NamespaceName = name
+		}
 		
 	case "template_declaration":
 		context.TemplateDepth++
+
+	case "preproc_ifdef", "preproc_if", "preproc_elif", "preproc_elifdef", "preproc_else":
+		cp.updatePreprocessorContext(node, context)
 		
 	case "access_specifier":
 		// Update current access level - node.Value should be just "private", "public", or "protected"
@@ -1191,8 +1268,7 @@ func (cp *CppParser) detectCoroutines(content string) bool {
 
 func (cp *CppParser) detectModules(content string) bool {
 	// C++20 modules: import std.core; or module mymodule;
-	return (strings.Contains(content, "import ") && !strings.Contains(content, "#include")) ||
-		   strings.Contains(content, "module ")
+	return ParseCppModuleUnit(content) != nil || cppModuleImport.MatchString(content)
 }
 
 func (cp *CppParser) detectFrameworks(content string, features map[string]interface{}) {
@@ -1335,6 +1411,10 @@ func (cp *CppParser) copyContext(src *CppParentContext) *CppParentContext {
 		InNamespace:   src.InNamespace,
 		NamespaceName: src.NamespaceName,
 		TemplateDepth: src.TemplateDepth,
+		Conditions:    src.Conditions,
+		BranchConds:   src.BranchConds,
+		IncludeGuard:  src.IncludeGuard,
+		Dialect:       src.Dialect,
 	}
 }
 
@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for C/C++ preprocessor information
+const (
+	MetadataPreprocessorCondition = "preprocessor_condition"
+	MetadataMacroValue            = "macro_value"
+)
+
+// maxMacroValueLength caps the macro body stored in symbol metadata
+const maxMacroValueLength = 200
+
+// macroToSymbol converts a #define into a macro symbol, skipping include guard defines
+func (cp *CppParser) macroToSymbol(node *types.ASTNode, ctx *SymbolExtractionContext) *types.Symbol {
+	var name, params, value string
+	for _, child := range node.Children {
+		switch child.Type {
+		case "identifier":
+			if name == "" {
+				name = strings.TrimSpace(child.Value)
+			}
+		case "preproc_params":
+			params = strings.TrimSpace(child.Value)
+		case "preproc_arg":
+			value = strings.TrimSpace(child.Value)
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	if ctx.ParentCtx != nil && name == ctx.ParentCtx.IncludeGuard && value == "" {
+		return nil
+	}
+
+	symbol := &types.Symbol{
+		Id:           types.SymbolId(fmt.Sprintf("macro-%s-%d", ctx.FilePath, node.Location.Line)),
+		Name:         name,
+		Type:         types.SymbolTypeMacro,
+		Location:     convertLocation(node.Location),
+		Signature:    "#define " + name + params,
+		Language:     ctx.Language,
+		Hash:         calculateHash(node.Value),
+		LastModified: time.Now(),
+		Visibility:   "public",
+	}
+	if value != "" {
+		if len(value) > maxMacroValueLength {
+			value = value[:maxMacroValueLength] + "..."
+		}
+		symbol.SetMetadata(MetadataMacroValue, value)
+	}
+	return symbol
+}
+
+// updatePreprocessorContext tracks the #if/#ifdef condition guarding the node's children
+func (cp *CppParser) updatePreprocessorContext(node *types.ASTNode, context *CppParentContext) {
+	switch node.Type {
+	case "preproc_ifdef":
+		if name, ok := includeGuardName(node); ok {
+			context.IncludeGuard = name
+			return
+		}
+		condition := ifdefCondition(node)
+		context.Conditions = appendCondition(context.Conditions, condition)
+		context.BranchConds = []string{condition}
+
+	case "preproc_if":
+		condition := ifCondition(node)
+		context.Conditions = appendCondition(context.Conditions, condition)
+		context.BranchConds = []string{condition}
+
+	case "preproc_elif", "preproc_elifdef":
+		// elif/else nodes are children of the branch before them, whose condition is innermost
+		if len(context.Conditions) == 0 {
+			return
+		}
+		condition := ifCondition(node)
+		if node.Type == "preproc_elifdef" {
+			condition = ifdefCondition(node)
+		}
+		context.Conditions = replaceLastCondition(context.Conditions, joinConditions(append(negateAll(context.BranchConds), condition)))
+		context.BranchConds = append(append([]string{}, context.BranchConds...), condition)
+
+	case "preproc_else":
+		if len(context.Conditions) == 0 {
+			return
+		}
+		context.Conditions = replaceLastCondition(context.Conditions, joinConditions(negateAll(context.BranchConds)))
+	}
+}
+
+// applyPreprocessorConditions records the guarding condition of a symbol in its metadata
+func applyPreprocessorConditions(symbol *types.Symbol, context *CppParentContext) {
+	if context == nil || len(context.Conditions) == 0 {
+		return
+	}
+	symbol.SetMetadata(MetadataPreprocessorCondition, joinConditions(context.Conditions))
+}
+
+// includeGuardName detects the `#ifndef X / #define X` include guard idiom
+func includeGuardName(node *types.ASTNode) (string, bool) {
+	if len(node.Children) < 3 || node.Children[0].Type != "#ifndef" {
+		return "", false
+	}
+	name := strings.TrimSpace(node.Children[1].Value)
+
+	define := node.Children[2]
+	if define.Type != "preproc_def" {
+		return "", false
+	}
+	for _, child := range define.Children {
+		switch child.Type {
+		case "identifier":
+			if strings.TrimSpace(child.Value) != name {
+				return "", false
+			}
+		case "preproc_arg":
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// ifdefCondition renders #ifdef X / #ifndef X as a defined() expression
+func ifdefCondition(node *types.ASTNode) string {
+	var directive, name string
+	for _, child := range node.Children {
+		if strings.HasPrefix(child.Type, "#") && directive == "" {
+			directive = child.Type
+		} else if child.Type == "identifier" && name == "" {
+			name = strings.TrimSpace(child.Value)
+		}
+	}
+	condition := "defined(" + name + ")"
+	if directive == "#ifndef" || directive == "#elifndef" {
+		return "!" + condition
+	}
+	return condition
+}
+
+// ifCondition returns the expression of an #if or #elif directive
+func ifCondition(node *types.ASTNode) string {
+	if len(node.Children) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(node.Children[1].Value)
+}
+
+// negateCondition negates a preprocessor expression, parenthesizing compound ones
+func negateCondition(condition string) string {
+	if strings.HasPrefix(condition, "!defined(") && !strings.ContainsAny(condition, "&|") {
+		return condition[1:]
+	}
+	if isSimpleCondition(condition) {
+		return "!" + condition
+	}
+	return "!(" + condition + ")"
+}
+
+// negateAll negates each condition in turn
+func negateAll(conditions []string) []string {
+	negated := make([]string, 0, len(conditions)+1)
+	for _, condition := range conditions {
+		negated = append(negated, negateCondition(condition))
+	}
+	return negated
+}
+
+// joinConditions combines conditions with &&, parenthesizing compound ones
+func joinConditions(conditions []string) string {
+	parts := make([]string, 0, len(conditions))
+	for _, condition := range conditions {
+		if len(conditions) > 1 && !isSimpleCondition(condition) && !strings.HasPrefix(condition, "!(") {
+			condition = "(" + condition + ")"
+		}
+		parts = append(parts, condition)
+	}
+	return strings.Join(parts, " && ")
+}
+
+// isSimpleCondition reports whether a condition needs no parentheses when combined
+func isSimpleCondition(condition string) bool {
+	return !strings.ContainsAny(condition, " &|<>=+-*/?")
+}
+
+// appendCondition returns a new slice with condition appended, leaving the parent's slice intact
+func appendCondition(conditions []string, condition string) []string {
+	return append(append(make([]string, 0, len(conditions)+1), conditions...), condition)
+}
+
+// replaceLastCondition returns a copy of conditions with the innermost entry replaced
+func replaceLastCondition(conditions []string, condition string) []string {
+	replaced := append([]string{}, conditions...)
+	replaced[len(replaced)-1] = condition
+	return replaced
+}
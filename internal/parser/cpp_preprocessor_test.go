@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCppMacrosAndConditions(t *testing.T) {
+	manager := NewManager()
+
+	cppCode := `#ifndef ENGINE_H
+#define ENGINE_H
+#define MAX_ITEMS 10
+#define SQUARE(x) ((x) * (x))
+
+#ifdef USE_GPU
+void renderGpu();
+#elif defined(USE_CL)
+void renderCl();
+#else
+void renderCpu();
+#endif
+
+class Engine {
+public:
+#ifndef NDEBUG
+    int debugState();
+#endif
+    void run();
+};
+
+#endif
+`
+	ast, err := manager.parseContent(cppCode, *manager.detectLanguage("engine.h"), "engine.h")
+	require.NoError(t, err)
+
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+
+	byName := make(map[string]*types.Symbol)
+	for _, symbol := range symbols {
+		byName[symbol.Name] = symbol
+	}
+
+	t.Run("macros", func(t *testing.T) {
+		require.Contains(t, byName, "MAX_ITEMS")
+		assert.Equal(t, types.SymbolTypeMacro, byName["MAX_ITEMS"].Type)
+		assert.Equal(t, "10", byName["MAX_ITEMS"].MetadataString(MetadataMacroValue))
+
+		require.Contains(t, byName, "SQUARE")
+		assert.Equal(t, "#define SQUARE(x)", byName["SQUARE"].Signature)
+
+		assert.NotContains(t, byName, "ENGINE_H", "include guard macros should be skipped")
+	})
+
+	t.Run("conditions", func(t *testing.T) {
+		assert.Equal(t, "defined(USE_GPU)", byName["renderGpu"].MetadataString(MetadataPreprocessorCondition))
+		assert.Equal(t, "!defined(USE_GPU) && defined(USE_CL)", byName["renderCl"].MetadataString(MetadataPreprocessorCondition))
+		assert.Equal(t, "!defined(USE_GPU) && !defined(USE_CL)", byName["renderCpu"].MetadataString(MetadataPreprocessorCondition))
+
+		require.Contains(t, byName, "debugState")
+		assert.Equal(t, "!defined(NDEBUG)", byName["debugState"].MetadataString(MetadataPreprocessorCondition))
+
+		// The include guard must not leak into every symbol in the header
+		assert.Empty(t, byName["run"].MetadataString(MetadataPreprocessorCondition))
+		assert.Empty(t, byName["Engine"].MetadataString(MetadataPreprocessorCondition))
+	})
+}
@@ -222,4 +222,30 @@ func truncateString(s string, maxLen int) string {
 		return s
 	}
 	return s[:maxLen] + "..."
-}
\ No newline at end of file
+}
+
+func TestCppIncludeImports(t *testing.T) {
+	manager := NewManager()
+
+	cppCode := `#include "engine/core.h"
+#include <vector>
+
+int main() { return 0; }
+`
+	ast, err := manager.parseContent(cppCode, types.Language{
+		Name:       "cpp",
+		Extensions: []string{".cpp"},
+		Parser:     "tree-sitter-cpp",
+		Enabled:    true,
+	}, "main.cpp")
+	require.NoError(t, err)
+
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+	require.Len(t, imports, 2)
+
+	assert.Equal(t, "engine/core.h", imports[0].Path)
+	assert.False(t, imports[0].IsSystem)
+	assert.Equal(t, "vector", imports[1].Path)
+	assert.True(t, imports[1].IsSystem)
+}
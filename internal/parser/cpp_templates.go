@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for C++ template specializations and instantiations
+const (
+	MetadataSpecializes    = "specializes"    // Primary template a specialization refines
+	MetadataSpecialization = "specialization" // "full" or "partial"
+	MetadataInstantiates   = "instantiates"   // Primary template an explicit instantiation instantiates
+	MetadataTemplateArgs   = "template_args"  // Arguments as written, such as "<bool>"
+)
+
+// templateTarget returns the template_type or template_function naming the
+// specialized or instantiated template of a declaration, if any
+func templateTarget(node *types.ASTNode) *types.ASTNode {
+	for _, child := range node.Children {
+		switch child.Type {
+		case "class_specifier", "struct_specifier", "union_specifier":
+			if name := specializedClassName(child); name != nil {
+				return name
+			}
+		case "function_definition", "function_declaration", "declaration", "function_declarator",
+			"pointer_declarator", "reference_declarator":
+			if declarator := findFunctionDeclarator(child); declarator != nil {
+				for _, grandchild := range declarator.Children {
+					if grandchild.Type == "template_function" {
+						return grandchild
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// findFunctionDeclarator finds the function_declarator of a declaration,
+// looking through pointer and reference return types
+func findFunctionDeclarator(node *types.ASTNode) *types.ASTNode {
+	if node.Type == "function_declarator" {
+		return node
+	}
+	for _, child := range node.Children {
+		switch child.Type {
+		case "function_declarator", "pointer_declarator", "reference_declarator":
+			if declarator := findFunctionDeclarator(child); declarator != nil {
+				return declarator
+			}
+		}
+	}
+	return nil
+}
+
+// specializedClassName returns the template_type naming a class, such as
+// Vec<bool>, or the qualified name around one, such as std::hash<Key>
+func specializedClassName(node *types.ASTNode) *types.ASTNode {
+	for _, child := range node.Children {
+		switch child.Type {
+		case "template_type":
+			return child
+		case "qualified_identifier":
+			for _, part := range child.Children {
+				if part.Type == "template_type" {
+					return child
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// splitTemplateTarget splits Vec<bool> into the template name and its arguments
+func splitTemplateTarget(target *types.ASTNode) (string, string) {
+	if target.Type == "qualified_identifier" {
+		value := strings.TrimSpace(target.Value)
+		if idx := strings.Index(value, "<"); idx > 0 {
+			return value[:idx], value[idx:]
+		}
+		return value, ""
+	}
+	var name, args string
+	for _, child := range target.Children {
+		switch child.Type {
+		case "type_identifier", "identifier", "qualified_identifier", "namespace_identifier":
+			if name == "" {
+				name = strings.TrimSpace(child.Value)
+			}
+		case "template_argument_list":
+			args = strings.TrimSpace(child.Value)
+		}
+	}
+	if name == "" {
+		value := strings.TrimSpace(target.Value)
+		if idx := strings.Index(value, "<"); idx > 0 {
+			name, args = value[:idx], value[idx:]
+		}
+	}
+	return name, args
+}
+
+// recordSpecialization marks a template declaration that specializes another
+// template with the primary template's name and the specialization arguments.
+// An empty template parameter list makes it a full specialization.
+func recordSpecialization(symbol *types.Symbol, node *types.ASTNode) {
+	target := templateTarget(node)
+	if target == nil {
+		return
+	}
+	name, args := splitTemplateTarget(target)
+	if name == "" {
+		return
+	}
+	kind := "partial"
+	for _, child := range node.Children {
+		if child.Type == "template_parameter_list" && strings.TrimSpace(strings.Trim(strings.TrimSpace(child.Value), "<>")) == "" {
+			kind = "full"
+		}
+	}
+	symbol.SetMetadata(MetadataSpecializes, name)
+	symbol.SetMetadata(MetadataSpecialization, kind)
+	symbol.SetMetadata(MetadataTemplateArgs, args)
+}
+
+// instantiationToSymbol converts an explicit instantiation such as
+// "template class Vec<int>;" into a template symbol named after the
+// instantiated type
+func (cp *CppParser) instantiationToSymbol(node *types.ASTNode, ctx *SymbolExtractionContext) *types.Symbol {
+	target := templateTarget(node)
+	if target == nil {
+		return nil
+	}
+	name, args := splitTemplateTarget(target)
+	if name == "" {
+		return nil
+	}
+	symbol := &types.Symbol{
+		Id:           types.SymbolId(fmt.Sprintf("instantiation-%s-%d", ctx.FilePath, node.Location.Line)),
+		Name:         name + args,
+		Type:         types.SymbolTypeTemplate,
+		Location:     convertLocation(node.Location),
+		Signature:    strings.TrimSuffix(strings.TrimSpace(node.Value), ";"),
+		Language:     ctx.Language,
+		Hash:         calculateHash(node.Value),
+		LastModified: time.Now(),
+	}
+	symbol.SetMetadata(MetadataInstantiates, name)
+	symbol.SetMetadata(MetadataTemplateArgs, args)
+	return symbol
+}
+
+// isSpecializedClass reports whether a class or struct is named by a
+// template_type: the body of a specialization or an explicit instantiation,
+// both represented by their template symbol
+func isSpecializedClass(node *types.ASTNode) bool {
+	return specializedClassName(node) != nil
+}
@@ -205,4 +205,86 @@ std::unique_ptr<Matrix<double>> createMatrix() {
 	
 	// Phase 2 target: 85% P1 feature coverage
 	assert.GreaterOrEqual(t, coverage, 85.0, "Should achieve 85%+ P1 feature coverage")
-}
\ No newline at end of file
+}
+
+func TestCppTemplateSpecializationMetadata(t *testing.T) {
+	manager := NewManager()
+	cppCode := `template <typename T>
+class Vec {};
+
+template <>
+class Vec<bool> {
+    void flip();
+};
+
+template <typename T>
+class Vec<T*> {};
+
+template <>
+struct std::hash<Key> {};
+
+template <typename T>
+T maxOf(T a, T b) { return a > b ? a : b; }
+
+template <>
+const char* maxOf<const char*>(const char* a, const char* b) { return a; }
+
+template class Vec<int>;
+template int maxOf<int>(int, int);
+`
+	ast, err := manager.parseContent(cppCode, types.Language{
+		Name:       "cpp",
+		Extensions: []string{".hpp"},
+		Parser:     "tree-sitter-cpp",
+		Enabled:    true,
+	}, "vec.hpp")
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+
+	byLine := make(map[int]*types.Symbol)
+	for _, symbol := range symbols {
+		assert.NotEqual(t, "class", symbol.Name, "specialized classes are represented by their template")
+		assert.NotEqual(t, "const", symbol.Name)
+		if symbol.Type == types.SymbolTypeTemplate {
+			byLine[symbol.Location.StartLine] = symbol
+		}
+	}
+
+	primary := byLine[1]
+	require.NotNil(t, primary)
+	assert.Equal(t, "Vec", primary.Name)
+	assert.Empty(t, primary.MetadataString(MetadataSpecializes))
+
+	full := byLine[4]
+	require.NotNil(t, full)
+	assert.Equal(t, "Vec", full.Name)
+	assert.Equal(t, "Vec", full.MetadataString(MetadataSpecializes))
+	assert.Equal(t, "full", full.MetadataString(MetadataSpecialization))
+	assert.Equal(t, "<bool>", full.MetadataString(MetadataTemplateArgs))
+
+	partial := byLine[9]
+	require.NotNil(t, partial)
+	assert.Equal(t, "partial", partial.MetadataString(MetadataSpecialization))
+	assert.Equal(t, "<T*>", partial.MetadataString(MetadataTemplateArgs))
+
+	hash := byLine[12]
+	require.NotNil(t, hash)
+	assert.Equal(t, "std::hash", hash.Name)
+	assert.Equal(t, "std::hash", hash.MetadataString(MetadataSpecializes))
+
+	function := byLine[18]
+	require.NotNil(t, function)
+	assert.Equal(t, "maxOf", function.Name)
+	assert.Equal(t, "maxOf", function.MetadataString(MetadataSpecializes))
+	assert.Equal(t, "<const char*>", function.MetadataString(MetadataTemplateArgs))
+
+	instantiation := byLine[21]
+	require.NotNil(t, instantiation)
+	assert.Equal(t, "Vec<int>", instantiation.Name)
+	assert.Equal(t, "Vec", instantiation.MetadataString(MetadataInstantiates))
+	functionInstantiation := byLine[22]
+	require.NotNil(t, functionInstantiation)
+	assert.Equal(t, "maxOf<int>", functionInstantiation.Name)
+	assert.Equal(t, "maxOf", functionInstantiation.MetadataString(MetadataInstantiates))
+}
@@ -13,14 +13,14 @@ import (
 // Dart language patterns for regex-based parsing (fallback approach)
 var dartPatterns = map[string]*regexp.Regexp{
 	// Class patterns - updated to support Dart 3.0+ modifiers
-	"class":      regexp.MustCompile(`(?m)^(?:(?:sealed|final|base|interface|mixin)\s+)?(?:abstract\s+)?class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w<>]+)?(?:\s+with\s+[\w\s,<>]+)?(?:\s+implements\s+[\w\s,<>]+)?\s*{`),
-	"sealedClass": regexp.MustCompile(`(?m)^sealed\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w<>]+)?(?:\s+with\s+[\w\s,<>]+)?(?:\s+implements\s+[\w\s,<>]+)?\s*{`),
-	"finalClass": regexp.MustCompile(`(?m)^final\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w<>]+)?(?:\s+with\s+[\w\s,<>]+)?(?:\s+implements\s+[\w\s,<>]+)?\s*{`),
-	"baseClass":  regexp.MustCompile(`(?m)^base\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w<>]+)?(?:\s+with\s+[\w\s,<>]+)?(?:\s+implements\s+[\w\s,<>]+)?\s*{`),
-	"interfaceClass": regexp.MustCompile(`(?m)^interface\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w<>]+)?(?:\s+with\s+[\w\s,<>]+)?(?:\s+implements\s+[\w\s,<>]+)?\s*{`),
-	"mixinClassModifier": regexp.MustCompile(`(?m)^mixin\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w<>]+)?(?:\s+with\s+[\w\s,<>]+)?(?:\s+implements\s+[\w\s,<>]+)?\s*{`),
+	"class":      regexp.MustCompile(`(?m)^(?:(?:sealed|final|base|interface|mixin)\s+)?(?:abstract\s+)?class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w$<>]+)?(?:\s+with\s+[\w$\s,<>]+)?(?:\s+implements\s+[\w$\s,<>]+)?\s*{`),
+	"sealedClass": regexp.MustCompile(`(?m)^sealed\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w$<>]+)?(?:\s+with\s+[\w$\s,<>]+)?(?:\s+implements\s+[\w$\s,<>]+)?\s*{`),
+	"finalClass": regexp.MustCompile(`(?m)^final\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w$<>]+)?(?:\s+with\s+[\w$\s,<>]+)?(?:\s+implements\s+[\w$\s,<>]+)?\s*{`),
+	"baseClass":  regexp.MustCompile(`(?m)^base\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w$<>]+)?(?:\s+with\s+[\w$\s,<>]+)?(?:\s+implements\s+[\w$\s,<>]+)?\s*{`),
+	"interfaceClass": regexp.MustCompile(`(?m)^interface\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w$<>]+)?(?:\s+with\s+[\w$\s,<>]+)?(?:\s+implements\s+[\w$\s,<>]+)?\s*{`),
+	"mixinClassModifier": regexp.MustCompile(`(?m)^mixin\s+class\s+(\w+)(?:<[\w\s,<>]+>)?(?:\s+extends\s+[\w$<>]+)?(?:\s+with\s+[\w$\s,<>]+)?(?:\s+implements\s+[\w$\s,<>]+)?\s*{`),
 	"stateClass": regexp.MustCompile(`(?m)^(?:abstract\s+)?class\s+(\w+)\s+extends\s+State<[\w<>]+>`),
-	"mixinClass": regexp.MustCompile(`(?m)^(?:abstract\s+)?class\s+(\w+)(?:\s+extends\s+[\w<>]+)?\s+with\s+([\w\s,<>]+)(?:\s+implements\s+[\w\s,<>]+)?\s*{`),
+	"mixinClass": regexp.MustCompile(`(?m)^(?:abstract\s+)?class\s+(\w+)(?:\s+extends\s+[\w$<>]+)?\s+with\s+([\w$\s,<>]+)(?:\s+implements\s+[\w$\s,<>]+)?\s*{`),
 	
 	// Mixin and extension patterns
 	"mixin":      regexp.MustCompile(`(?m)^mixin\s+(\w+(?:<[\w\s,<>]+>)?)(?:\s+on\s+[\w\s,<>]+)?\s*{`),
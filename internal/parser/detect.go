@@ -0,0 +1,264 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/pathglob"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// LanguageOverride sets the language of the files matching a path glob,
+// overriding their extension and content
+type LanguageOverride struct {
+	Path     string `json:"path" mapstructure:"path"`         // Relative to the project root; "**" spans directories
+	Language string `json:"language" mapstructure:"language"` // Language name such as python, shell or cpp
+}
+
+// languageExtensions maps language names, including the aliases used by vim
+// filetypes and emacs modes, to an extension of the language
+var languageExtensions = map[string]string{
+	"typescript": ".ts", "ts": ".ts", "tsx": ".tsx", "typescriptreact": ".tsx",
+	"javascript": ".js", "js": ".js", "js2": ".js", "jsx": ".jsx", "javascriptreact": ".jsx", "rjsx": ".jsx",
+	"python": ".py", "py": ".py", "python3": ".py",
+	"java": ".java",
+	"go":   ".go", "golang": ".go",
+	"rust": ".rs", "rs": ".rs",
+	"swift": ".swift",
+	"dart":  ".dart",
+	"shell": ".sh", "sh": ".sh", "bash": ".sh", "zsh": ".sh", "ksh": ".sh", "shell-script": ".sh",
+	"cpp": ".cpp", "c++": ".cpp", "c": ".c", "cuda": ".cu", "objcpp": ".mm",
+	"json": ".json", "yaml": ".yaml", "yml": ".yaml",
+	"vue": ".vue", "svelte": ".svelte", "astro": ".astro",
+	"jinja": ".j2", "jinja2": ".j2", "htmldjango": ".j2", "django": ".j2", "nunjucks": ".njk", "twig": ".twig",
+	"erb": ".erb", "eruby": ".erb",
+	"handlebars": ".hbs", "hbs": ".hbs", "mustache": ".hbs",
+	"gotemplate": ".tmpl", "gotmpl": ".tmpl", "gohtmltmpl": ".tmpl",
+}
+
+// templateHostExtensions are the extensions of files that are templates when
+// they hold template syntax
+var templateHostExtensions = map[string]bool{".html": true, ".htm": true}
+
+// interpreterExtensions maps the interpreters named in shebangs to an
+// extension of the language they run
+var interpreterExtensions = map[string]string{
+	"python": ".py", "pypy": ".py",
+	"node": ".js", "nodejs": ".js", "bun": ".js",
+	"deno": ".ts", "ts-node": ".ts", "tsx": ".ts",
+	"sh": ".sh", "bash": ".sh", "zsh": ".sh", "dash": ".sh", "ksh": ".sh", "mksh": ".sh", "ash": ".sh",
+	"dart":        ".dart",
+	"swift":       ".swift",
+	"java":        ".java",
+	"rust-script": ".rs",
+	"gorun":       ".go",
+}
+
+// sniffBytes is how much of the start and end of a file is read for a
+// shebang or modeline
+const sniffBytes = 1024
+
+var (
+	// interpreterVersion strips versions such as python3.12
+	interpreterVersion = regexp.MustCompile(`^(.*?[a-z])[0-9.]*$`)
+	// vimModeline matches "vim: set ft=python:" and "vi: filetype=sh"
+	vimModeline = regexp.MustCompile(`(?:^|\s)(?:vim?|ex):.*?\b(?:ft|filetype|syntax|syn)=([\w+#-]+)`)
+	// emacsModeline matches "-*- mode: python -*-" and "-*- c++ -*-"
+	emacsModeline = regexp.MustCompile(`-\*-\s*(.*?)\s*-\*-`)
+	emacsMode     = regexp.MustCompile(`(?i)(?:^|;)\s*mode:\s*([\w+-]+)`)
+)
+
+// SetLanguageOverrides sets the languages of files by path, matched relative
+// to root. Entries with an invalid glob or an unknown language are skipped and
+// reported in the returned error.
+func (m *Manager) SetLanguageOverrides(root string, overrides []LanguageOverride) error {
+	var valid []LanguageOverride
+	var errs []error
+	for _, override := range overrides {
+		switch {
+		case override.Path == "" || !pathglob.Valid(override.Path):
+			errs = append(errs, fmt.Errorf("invalid language override path %q", override.Path))
+		case m.languageByName(override.Language) == nil:
+			errs = append(errs, fmt.Errorf("unknown language %q for %s", override.Language, override.Path))
+		default:
+			valid = append(valid, override)
+		}
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	m.mu.Lock()
+	m.overrideRoot = root
+	m.overrides = valid
+	m.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// DetectLanguage returns the language of a file, or nil when it is not
+// supported. Language overrides come first, then the extension; files with
+// an unknown extension or none are recognized by their shebang or modeline.
+func (m *Manager) DetectLanguage(filePath string) *types.Language {
+	return m.detectLanguage(filePath)
+}
+
+// overrideLanguage returns the language configured for a file's path
+func (m *Manager) overrideLanguage(filePath string) *types.Language {
+	m.mu.RLock()
+	root, overrides := m.overrideRoot, m.overrides
+	m.mu.RUnlock()
+	if len(overrides) == 0 {
+		return nil
+	}
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	for _, override := range overrides {
+		if pathglob.Match(override.Path, rel) {
+			return m.languageByName(override.Language)
+		}
+	}
+	return nil
+}
+
+// languageByName returns a built-in or WASM language by name or alias
+func (m *Manager) languageByName(name string) *types.Language {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if ext, ok := languageExtensions[name]; ok {
+		return extensionLanguage(ext)
+	}
+	m.mu.RLock()
+	var ext string
+	for e, n := range m.wasmExtensions {
+		if n == name {
+			ext = e
+			break
+		}
+	}
+	m.mu.RUnlock()
+	if ext == "" {
+		return nil
+	}
+	return m.detectWASMLanguage(ext)
+}
+
+// contentLanguage recognizes a file by its shebang, or by a vim modeline in
+// its first or last lines or an emacs modeline on its first lines. HTML files
+// holding template syntax are templates. Binary files and files that cannot
+// be read have no language.
+func (m *Manager) contentLanguage(filePath string) *types.Language {
+	head, tail := sniff(filePath)
+	if len(head) == 0 || bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+	lines := strings.Split(string(head), "\n")
+	if ext := shebangExtension(lines[0]); ext != "" {
+		return extensionLanguage(ext)
+	}
+	if name := modelineLanguage(lines, strings.Split(string(tail), "\n")); name != "" {
+		return m.languageByName(name)
+	}
+	if templateHostExtensions[strings.ToLower(filepath.Ext(filePath))] {
+		return m.languageByName(templateLanguage(head, tail))
+	}
+	return nil
+}
+
+// sniff reads the start and end of a file
+func sniff(filePath string) (head, tail []byte) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return nil, nil
+	}
+	head = make([]byte, sniffBytes)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+	if info.Size() <= sniffBytes {
+		return head, head
+	}
+	// The tail starts after the head, at most sniffBytes before the end
+	offset := info.Size() - sniffBytes
+	if offset < sniffBytes {
+		offset = sniffBytes
+	}
+	tail = make([]byte, sniffBytes)
+	n, _ = f.ReadAt(tail, offset)
+	return head, tail[:n]
+}
+
+// shebangExtension returns an extension of the language a shebang line runs,
+// following /usr/bin/env and its options
+func shebangExtension(line string) string {
+	line, ok := strings.CutPrefix(strings.TrimSpace(line), "#!")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "-") || strings.Contains(field, "=") {
+				continue
+			}
+			interpreter = path.Base(field)
+			break
+		}
+	}
+	if match := interpreterVersion.FindStringSubmatch(interpreter); match != nil {
+		interpreter = match[1]
+	}
+	return interpreterExtensions[interpreter]
+}
+
+// modelineLanguage returns the language named by a vim modeline in the first
+// or last five lines, or an emacs modeline on the first line or the one after
+// a shebang
+func modelineLanguage(head, tail []string) string {
+	for i, line := range head[:min(len(head), 2)] {
+		if i == 1 && !strings.HasPrefix(head[0], "#!") {
+			break
+		}
+		if match := emacsModeline.FindStringSubmatch(line); match != nil {
+			if mode := emacsMode.FindStringSubmatch(match[1]); mode != nil {
+				return strings.TrimSuffix(mode[1], "-mode")
+			}
+			if !strings.Contains(match[1], ":") {
+				return strings.TrimSuffix(match[1], "-mode")
+			}
+		}
+	}
+	lines := append([]string(nil), head[:min(len(head), 5)]...)
+	for len(tail) > 0 && strings.TrimSpace(tail[len(tail)-1]) == "" {
+		tail = tail[:len(tail)-1]
+	}
+	if len(tail) > 5 {
+		tail = tail[len(tail)-5:]
+	}
+	lines = append(lines, tail...)
+	for _, line := range lines {
+		if match := vimModeline.FindStringSubmatch(line); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShebangExtension(t *testing.T) {
+	cases := map[string]string{
+		"#!/usr/bin/env python3":                 ".py",
+		"#!/usr/bin/python3.12 -u":               ".py",
+		"#!/usr/bin/env -S deno run --allow-net": ".ts",
+		"#!/usr/bin/env NODE_ENV=prod node":      ".js",
+		"#! /bin/bash -e":                        ".sh",
+		"#!/bin/sh":                              ".sh",
+		"#!/usr/bin/env ruby":                    "",
+		"# not a shebang":                        "",
+	}
+	for line, want := range cases {
+		assert.Equal(t, want, shebangExtension(line), line)
+	}
+}
+
+func TestModelineLanguage(t *testing.T) {
+	lines := func(text string) []string { return strings.Split(text, "\n") }
+	assert.Equal(t, "python", modelineLanguage(lines("# -*- mode: python; coding: utf-8 -*-\nx = 1"), nil))
+	assert.Equal(t, "C++", modelineLanguage(lines("// -*- C++ -*-\nint x;"), nil))
+	assert.Equal(t, "sh", modelineLanguage(lines("#!/usr/local/bin/custom\n# -*- sh -*-"), nil))
+	assert.Equal(t, "", modelineLanguage(lines("x\n# -*- sh -*-"), nil), "emacs modelines belong on the first line")
+	assert.Equal(t, "typescript", modelineLanguage(lines("let a = 1"), lines("// vim: set ft=typescript:\n\n")))
+	assert.Equal(t, "sh", modelineLanguage(lines("# vi: filetype=sh\necho"), nil))
+	assert.Equal(t, "", modelineLanguage(lines("novim: ft=go"), nil))
+}
+
+func TestDetectLanguageFromContent(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"bin/deploy":     "#!/usr/bin/env bash\necho deploying\n",
+		"bin/migrate":    "#!/usr/bin/env python3\nprint('migrating')\n",
+		"lib/api.inc":    "// vim: ft=cpp\nint handler();\n",
+		"templates/page": "Hello {{ name }}\n",
+		"blob":           "\x00\x01#!/bin/sh\n",
+		"tools/gen.txt":  "def main():\n    pass\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	// A long file keeps its vim modeline in the tail
+	long := strings.Repeat("x = 1\n", 1000) + "# vim: set filetype=python :\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tools", "long.conf"), []byte(long), 0644))
+
+	m := NewManager()
+	language := func(name string) string {
+		if lang := m.DetectLanguage(filepath.Join(dir, name)); lang != nil {
+			return lang.Name
+		}
+		return ""
+	}
+	assert.Equal(t, "shell", language("bin/deploy"))
+	assert.Equal(t, "python", language("bin/migrate"))
+	assert.Equal(t, "cpp", language("lib/api.inc"))
+	assert.Equal(t, "python", language("tools/long.conf"))
+	assert.Equal(t, "", language("templates/page"))
+	assert.Equal(t, "", language("blob"), "binary files are not sniffed")
+	assert.Equal(t, "", language("tools/gen.txt"))
+
+	err := m.SetLanguageOverrides(dir, []LanguageOverride{
+		{Path: "tools/*.txt", Language: "Python"},
+		{Path: "bin/migrate", Language: "shell"},
+		{Path: "**/*.inc", Language: "cobol"},
+		{Path: "[", Language: "go"},
+	})
+	assert.ErrorContains(t, err, `unknown language "cobol"`)
+	assert.ErrorContains(t, err, `invalid language override path "["`)
+	assert.Equal(t, "python", language("tools/gen.txt"))
+	assert.Equal(t, "shell", language("bin/migrate"), "overrides win over shebangs")
+	assert.Equal(t, "cpp", language("lib/api.inc"), "invalid overrides are skipped")
+	assert.Equal(t, "go", m.DetectLanguage("main.go").Name, "paths outside the root keep their extension")
+}
@@ -0,0 +1,280 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Embedded languages recognized in string literals
+const (
+	embeddedGraphQL = "graphql"
+	embeddedSQL     = "sql"
+	embeddedHTML    = "html"
+)
+
+// embeddedHostLanguages are the languages whose string literals are scanned
+// for embedded languages
+var embeddedHostLanguages = map[string]bool{
+	"go": true, "javascript": true, "typescript": true, "python": true,
+	"java": true, "rust": true, "cpp": true,
+}
+
+// stringNodeTypes are the tree-sitter node types of string literals
+var stringNodeTypes = map[string]bool{
+	"string":                     true, // JavaScript, Python
+	"template_string":            true, // JavaScript template literals
+	"interpreted_string_literal": true, // Go
+	"raw_string_literal":         true, // Go, Rust, C++
+	"string_literal":             true, // Java, Rust, C++
+	"text_block":                 true, // Java
+}
+
+// embeddedTags maps template tags and the functions taking a query, such as
+// gql`...` or graphql("..."), to the language of their string argument
+var embeddedTags = map[string]string{
+	"gql": embeddedGraphQL, "graphql": embeddedGraphQL,
+	"sql": embeddedSQL, "SQL": embeddedSQL,
+	"html": embeddedHTML, "svg": embeddedHTML,
+}
+
+// callNodeTypes are the node types of calls and tagged templates
+var callNodeTypes = map[string]bool{
+	"call_expression": true, // JavaScript, Go, Rust, C++
+	"call":            true, // Python
+}
+
+var (
+	// Untagged strings are recognized by how they start
+	graphqlStartPattern = regexp.MustCompile(`^\s*(?:query|mutation|subscription|fragment)\b[^{]*\{`)
+	sqlStartPattern     = regexp.MustCompile(`(?is)^\s*(?:--\s*name:|SELECT\s.+\sFROM\s|INSERT\s+INTO\s|UPDATE\s+\S+\s+SET\s|DELETE\s+FROM\s|WITH\s+\w+\s+AS\s*\(|CREATE\s|ALTER\s+TABLE\s|DROP\s+(?:TABLE|VIEW|FUNCTION)\s)`)
+	htmlStartPattern    = regexp.MustCompile(`(?i)^\s*<(?:!DOCTYPE|!--|[a-z][\w-]*[\s/>])`)
+
+	// GraphQL operations, fragments and schema types
+	graphqlDefinitionPattern = regexp.MustCompile(`(?m)^[ \t]*(?:extend[ \t]+)?(query|mutation|subscription|fragment|type|input|interface|enum|union|scalar)[ \t]+([_A-Za-z]\w*)([^{=\n]*)`)
+
+	// SQL definitions, with an optional schema and quoted names
+	sqlDefinitionPattern = regexp.MustCompile(`(?i)\bCREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?(?:TEMP(?:ORARY)?\s+|UNLOGGED\s+)?(TABLE|VIEW|MATERIALIZED\s+VIEW|FUNCTION|PROCEDURE|TRIGGER|TYPE)\s+(?:IF\s+NOT\s+EXISTS\s+)?((?:[\w$]+|"[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\])(?:\.(?:[\w$]+|"[^"]+"|` + "`[^`]+`" + `|\[[^\]]+\]))*)`)
+	// Named queries in sqlc and yesql style: -- name: GetUser :one
+	sqlNamedQueryPattern = regexp.MustCompile(`(?m)--[ \t]*name:[ \t]*([A-Za-z_][\w-]*)(?:[ \t]+(:\w+))?`)
+
+	// HTML elements with a static id, and Go, Jinja and Django template blocks
+	htmlIdPattern       = regexp.MustCompile(`<([A-Za-z][\w-]*)\b[^<>]*?\bid\s*=\s*["']([A-Za-z][\w:.-]*)["']`)
+	htmlTemplatePattern = regexp.MustCompile(`\{\{-?\s*(?:define|block)\s+"([^"]+)"|\{%-?\s*(?:block|macro)\s+(\w+)`)
+)
+
+// graphqlTypes maps GraphQL definition keywords to symbol types
+var graphqlTypes = map[string]types.SymbolType{
+	"query":        types.SymbolTypeQuery,
+	"mutation":     types.SymbolTypeQuery,
+	"subscription": types.SymbolTypeQuery,
+	"fragment":     types.SymbolTypeQuery,
+	"type":         types.SymbolTypeClass,
+	"input":        types.SymbolTypeClass,
+	"interface":    types.SymbolTypeInterface,
+	"enum":         types.SymbolTypeEnum,
+	"union":        types.SymbolTypeType,
+	"scalar":       types.SymbolTypeType,
+}
+
+// embeddedRegion is a string literal holding another language
+type embeddedRegion struct {
+	node     *types.ASTNode // The literal, with its quotes
+	language string
+	tagged   bool // Named by its tag rather than recognized by its content
+}
+
+// extractEmbeddedSymbols finds GraphQL, SQL and HTML in the string literals
+// of a file and extracts their definitions: GraphQL operations, fragments and
+// types; SQL tables, views, functions and named queries; and HTML element ids
+// and template blocks. Symbols are located at their line in the host file.
+func extractEmbeddedSymbols(ast *types.AST) []*types.Symbol {
+	if !embeddedHostLanguages[ast.Language] || ast.Root == nil {
+		return nil
+	}
+	var regions []embeddedRegion
+	collectEmbeddedRegions(ast.Root, "", &regions)
+
+	var symbols []*types.Symbol
+	for _, region := range regions {
+		switch region.language {
+		case embeddedGraphQL:
+			symbols = append(symbols, graphqlSymbols(region, ast)...)
+		case embeddedSQL:
+			symbols = append(symbols, sqlSymbols(region, ast)...)
+		case embeddedHTML:
+			symbols = append(symbols, htmlSymbols(region, ast)...)
+		}
+		if len(symbols) >= MaxSymbolsPerFile {
+			break
+		}
+	}
+	return symbols
+}
+
+// collectEmbeddedRegions walks the AST for string literals whose tag or
+// content names an embedded language. tag is the function the literal is
+// passed to, if any.
+func collectEmbeddedRegions(node *types.ASTNode, tag string, regions *[]embeddedRegion) {
+	if stringNodeTypes[node.Type] {
+		language, tagged := embeddedTags[tag]
+		if !tagged {
+			language = sniffEmbeddedLanguage(stringBody(node.Value))
+		}
+		if language != "" {
+			*regions = append(*regions, embeddedRegion{node: node, language: language, tagged: tagged})
+		}
+		return
+	}
+
+	childTag := ""
+	if callNodeTypes[node.Type] && len(node.Children) > 0 {
+		callee := node.Children[0].Value
+		childTag = callee[strings.LastIndex(callee, ".")+1:]
+	} else if node.Type == "arguments" || node.Type == "argument_list" {
+		childTag = tag
+	}
+	for _, child := range node.Children {
+		collectEmbeddedRegions(child, childTag, regions)
+	}
+}
+
+// stringBody strips the prefix and opening quotes of a literal
+func stringBody(literal string) string {
+	return strings.TrimLeft(literal, "rRbBuUfFL#\"'`")
+}
+
+// text returns the literal with its prefix and opening quotes blanked, so
+// definitions on its first line start a line while offsets stay those of the
+// literal
+func (r embeddedRegion) text() string {
+	body := stringBody(r.node.Value)
+	return strings.Repeat(" ", len(r.node.Value)-len(body)) + body
+}
+
+// sniffEmbeddedLanguage recognizes GraphQL, SQL and HTML by how a string starts
+func sniffEmbeddedLanguage(body string) string {
+	switch {
+	case graphqlStartPattern.MatchString(body):
+		return embeddedGraphQL
+	case sqlStartPattern.MatchString(body):
+		return embeddedSQL
+	case htmlStartPattern.MatchString(body) && strings.Contains(body, ">"):
+		return embeddedHTML
+	}
+	return ""
+}
+
+// graphqlSymbols extracts the operations, fragments and types of a GraphQL
+// document. Schema types are only read from tagged literals, since untagged
+// strings are recognized by their operations.
+func graphqlSymbols(region embeddedRegion, ast *types.AST) []*types.Symbol {
+	var symbols []*types.Symbol
+	value := region.text()
+	for _, match := range graphqlDefinitionPattern.FindAllStringSubmatchIndex(value, -1) {
+		keyword := value[match[2]:match[3]]
+		name := value[match[4]:match[5]]
+		symbolType := graphqlTypes[keyword]
+		if symbolType != types.SymbolTypeQuery && !region.tagged {
+			continue
+		}
+		signature := strings.Join(strings.Fields(value[match[2]:match[7]]), " ")
+		symbol := newEmbeddedSymbol(name, symbolType, signature, region, match[4], ast)
+		if symbolType == types.SymbolTypeQuery || symbolType == types.SymbolTypeClass {
+			symbol.SetMetadata(types.SubtypeMetadataKey, keyword)
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// sqlSymbols extracts the tables, views, functions, types and named queries
+// of SQL statements
+func sqlSymbols(region embeddedRegion, ast *types.AST) []*types.Symbol {
+	var symbols []*types.Symbol
+	value := region.text()
+	for _, match := range sqlDefinitionPattern.FindAllStringSubmatchIndex(value, -1) {
+		object := strings.ToUpper(strings.Join(strings.Fields(value[match[2]:match[3]]), " "))
+		qualified := value[match[4]:match[5]]
+		parts := strings.Split(qualified, ".")
+		name := strings.Trim(parts[len(parts)-1], "\"`[]")
+		offset := match[5] - len(parts[len(parts)-1])
+
+		var symbolType types.SymbolType
+		subtype := ""
+		switch object {
+		case "TABLE":
+			symbolType = types.SymbolTypeTable
+		case "VIEW", "MATERIALIZED VIEW":
+			symbolType, subtype = types.SymbolTypeTable, "view"
+		case "TYPE":
+			symbolType = types.SymbolTypeType
+		case "FUNCTION":
+			symbolType = types.SymbolTypeFunction
+		default:
+			symbolType, subtype = types.SymbolTypeFunction, strings.ToLower(object)
+		}
+		symbol := newEmbeddedSymbol(name, symbolType, "CREATE "+object+" "+qualified, region, offset, ast)
+		if subtype != "" {
+			symbol.SetMetadata(types.SubtypeMetadataKey, subtype)
+		}
+		symbols = append(symbols, symbol)
+	}
+	for _, match := range sqlNamedQueryPattern.FindAllStringSubmatchIndex(value, -1) {
+		signature := strings.TrimSpace(value[match[0]:match[1]])
+		symbols = append(symbols, newEmbeddedSymbol(value[match[2]:match[3]], types.SymbolTypeQuery, signature, region, match[2], ast))
+	}
+	return symbols
+}
+
+// htmlSymbols extracts elements with a static id and template blocks from HTML
+func htmlSymbols(region embeddedRegion, ast *types.AST) []*types.Symbol {
+	var symbols []*types.Symbol
+	value := region.text()
+	for _, match := range htmlIdPattern.FindAllStringSubmatchIndex(value, -1) {
+		tag, id := value[match[2]:match[3]], value[match[4]:match[5]]
+		signature := fmt.Sprintf("<%s id=%q>", tag, id)
+		symbols = append(symbols, newEmbeddedSymbol(id, types.SymbolTypeElement, signature, region, match[4], ast))
+	}
+	for _, match := range htmlTemplatePattern.FindAllStringSubmatchIndex(value, -1) {
+		start, end := match[2], match[3]
+		if start < 0 {
+			start, end = match[4], match[5]
+		}
+		symbol := newEmbeddedSymbol(value[start:end], types.SymbolTypeFunction, strings.TrimSpace(value[match[0]:match[1]]), region, start, ast)
+		symbol.SetMetadata(types.SubtypeMetadataKey, "template")
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// newEmbeddedSymbol builds a symbol for a name at offset in a region's literal,
+// located at the name's line and column in the host file
+func newEmbeddedSymbol(name string, symbolType types.SymbolType, signature string, region embeddedRegion, offset int, ast *types.AST) *types.Symbol {
+	before := region.node.Value[:offset]
+	line := region.node.Location.Line + strings.Count(before, "\n")
+	column := region.node.Location.Column + offset
+	if newline := strings.LastIndexByte(before, '\n'); newline >= 0 {
+		column = offset - newline
+	}
+	symbol := &types.Symbol{
+		Id:   types.SymbolId(fmt.Sprintf("%s-%s-%d", symbolType, ast.FilePath, line)),
+		Name: name,
+		Type: symbolType,
+		Location: types.Location{
+			StartLine:   line,
+			StartColumn: column,
+			EndLine:     line,
+			EndColumn:   column + len(name),
+		},
+		Signature:    signature,
+		Language:     region.language,
+		Hash:         calculateHash(signature),
+		LastModified: time.Now(),
+	}
+	symbol.SetMetadata(types.EmbeddedMetadataKey, ast.Language)
+	return symbol
+}
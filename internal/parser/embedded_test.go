@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// embeddedSymbols returns the embedded symbols of a file by name
+func embeddedSymbols(t *testing.T, path, content string) map[string]*types.Symbol {
+	t.Helper()
+	found := make(map[string]*types.Symbol)
+	for _, symbol := range extractFileSymbols(t, NewManager(), path, content) {
+		if symbol.IsEmbedded() {
+			found[symbol.Name] = symbol
+		}
+	}
+	return found
+}
+
+func TestEmbeddedGraphQL(t *testing.T) {
+	symbols := embeddedSymbols(t, "web/queries.js", `import { gql } from "@apollo/client";
+
+export const GET_USER = gql`+"`"+`
+  query GetUser($id: ID!) {
+    user(id: $id) { ...UserFields }
+  }
+  fragment UserFields on User { id name }
+`+"`"+`;
+
+export const typeDefs = gql`+"`"+`
+  type User implements Node {
+    id: ID!
+  }
+  enum Role { ADMIN USER }
+`+"`"+`;
+
+const raw = "mutation Logout { logout }";
+const text = "type Foo is not a schema";
+`)
+	require.Contains(t, symbols, "GetUser")
+	query := symbols["GetUser"]
+	assert.Equal(t, types.SymbolTypeQuery, query.Type)
+	assert.Equal(t, "graphql", query.Language)
+	assert.Equal(t, "javascript", query.MetadataString(types.EmbeddedMetadataKey))
+	assert.Equal(t, "query GetUser($id: ID!)", query.Signature)
+	assert.Equal(t, 4, query.Location.StartLine)
+	assert.Equal(t, 9, query.Location.StartColumn)
+	assert.Equal(t, types.SymbolKindFunction, query.Kind)
+
+	assert.Equal(t, "fragment UserFields on User", symbols["UserFields"].Signature)
+	assert.Equal(t, 7, symbols["UserFields"].Location.StartLine)
+	assert.Equal(t, types.SymbolKindClass, symbols["User"].Kind)
+	assert.Equal(t, types.SymbolKindEnum, symbols["Role"].Kind)
+	require.Contains(t, symbols, "Logout", "untagged strings are recognized by their operations")
+	assert.Equal(t, 17, symbols["Logout"].Location.StartLine)
+	assert.Equal(t, 23, symbols["Logout"].Location.StartColumn)
+	assert.NotContains(t, symbols, "Foo")
+}
+
+func TestEmbeddedSQL(t *testing.T) {
+	symbols := embeddedSymbols(t, "store/schema.go", "package store\n\n"+
+		"const schema = `\n"+
+		"CREATE TABLE IF NOT EXISTS public.\"orders\" (id INT);\n"+
+		"CREATE OR REPLACE VIEW open_orders AS SELECT * FROM orders;\n"+
+		"`\n\n"+
+		"const getOrder = `-- name: GetOrder :one\nSELECT * FROM orders WHERE id = $1`\n\n"+
+		"func migrate(db DB) {\n\tdb.Exec(\"CREATE INDEX orders_id ON orders (id)\")\n\tdb.Exec(\"create function total() returns int\")\n}\n")
+
+	require.Contains(t, symbols, "orders")
+	table := symbols["orders"]
+	assert.Equal(t, types.SymbolTypeTable, table.Type)
+	assert.Equal(t, "sql", table.Language)
+	assert.Equal(t, `CREATE TABLE public."orders"`, table.Signature)
+	assert.Equal(t, 4, table.Location.StartLine)
+	assert.Equal(t, "view", symbols["open_orders"].Subtype())
+	assert.Equal(t, types.SymbolTypeQuery, symbols["GetOrder"].Type)
+	assert.Equal(t, "-- name: GetOrder :one", symbols["GetOrder"].Signature)
+	assert.Equal(t, 8, symbols["GetOrder"].Location.StartLine)
+	assert.Equal(t, types.SymbolTypeFunction, symbols["total"].Type)
+	assert.Equal(t, 13, symbols["total"].Location.StartLine)
+	assert.NotContains(t, symbols, "orders_id", "indexes are not definitions")
+}
+
+func TestEmbeddedHTML(t *testing.T) {
+	symbols := embeddedSymbols(t, "app/views.py", `from django.utils.html import format_html
+
+PAGE = """
+<div id="cart" class="panel">
+  {% block items %}{% endblock %}
+  <button id="checkout-button">Pay</button>
+  <span id="{{ dynamic }}"></span>
+</div>
+"""
+
+NOTE = "id='ignored' is not markup"
+`)
+	require.Contains(t, symbols, "cart")
+	cart := symbols["cart"]
+	assert.Equal(t, types.SymbolTypeElement, cart.Type)
+	assert.Equal(t, `<div id="cart">`, cart.Signature)
+	assert.Equal(t, "html", cart.Language)
+	assert.Equal(t, "python", cart.MetadataString(types.EmbeddedMetadataKey))
+	assert.Equal(t, 4, cart.Location.StartLine)
+	assert.Equal(t, 6, symbols["checkout-button"].Location.StartLine)
+	assert.Equal(t, "template", symbols["items"].Subtype())
+	assert.Len(t, symbols, 3, "dynamic ids and text outside markup are skipped")
+}
@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for Go method sets and type composition
+const (
+	MetadataReceiver         = "receiver"          // Type a Go method is declared on, without pointer or type arguments
+	MetadataPointerReceiver  = "pointer_receiver"  // Set on Go methods declared on *T
+	MetadataMethodSignature  = "method_signature"  // Name and parameter and result types, such as "Read([]byte)(int,error)"
+	MetadataInterfaceMethods = "interface_methods" // Method signatures a Go interface declares itself
+	MetadataEmbeds           = "embeds"            // Types embedded in a Go struct or interface, such as "*Base" or "io.Closer"
+	MetadataTypeParameters   = "type_parameters"   // Go type parameters with their constraints, such as "K comparable"
+	MetadataTypeSet          = "type_set"          // Union and ~ terms of a Go constraint interface, such as "~int | ~float64"
+)
+
+// goPackageQualifier matches the package a Go type name is qualified with,
+// which signatures drop so "time.Duration" and "Duration" compare equal
+var goPackageQualifier = regexp.MustCompile(`\b[A-Za-z_]\w*\.`)
+
+// recordGoMethod records the receiver and signature of a Go method so the
+// method sets of the types in a package can be compared with interfaces
+func recordGoMethod(symbol *types.Symbol, node *types.ASTNode) {
+	var lists []*types.ASTNode
+	var name string
+	var result *types.ASTNode
+	for _, child := range node.Children {
+		switch child.Type {
+		case "parameter_list":
+			if name != "" && len(lists) == 2 {
+				result = child
+			} else {
+				lists = append(lists, child)
+			}
+		case "field_identifier":
+			name = strings.TrimSpace(child.Value)
+		case "block", "func", "{":
+		default:
+			if name != "" && len(lists) == 2 {
+				result = child
+			}
+		}
+	}
+	if name == "" || len(lists) != 2 {
+		return
+	}
+	receivers := goParameterTypes(lists[0])
+	if len(receivers) != 1 {
+		return
+	}
+
+	receiver := receivers[0]
+	if strings.HasPrefix(receiver, "*") {
+		symbol.SetMetadata(MetadataPointerReceiver, true)
+		receiver = strings.TrimSpace(receiver[1:])
+	}
+	if idx := strings.Index(receiver, "["); idx >= 0 {
+		receiver = receiver[:idx]
+	}
+	symbol.SetMetadata(MetadataReceiver, receiver)
+	symbol.SetMetadata(MetadataMethodSignature, goMethodSignature(name, lists[1], result))
+}
+
+// recordGoType records what a Go type_spec declares beyond its name: its
+// type parameters, the fields a struct embeds, and the methods, embedded
+// interfaces and type set terms of an interface. The full method set of an
+// interface adds those of the interfaces it embeds.
+func recordGoType(symbol *types.Symbol, spec *types.ASTNode) {
+	recordGoTypeParameters(symbol, spec)
+	for _, child := range spec.Children {
+		switch child.Type {
+		case "struct_type":
+			recordGoStruct(symbol, child)
+		case "interface_type":
+			recordGoInterface(symbol, child)
+		}
+	}
+}
+
+// recordGoTypeParameters records the type parameters of a generic Go type or
+// function, one per name with its constraint, so "K, V any" gives "K any"
+// and "V any"
+func recordGoTypeParameters(symbol *types.Symbol, node *types.ASTNode) {
+	var params []string
+	for _, child := range node.Children {
+		if child.Type != "type_parameter_list" {
+			continue
+		}
+		for _, decl := range child.Children {
+			if decl.Type != "type_parameter_declaration" {
+				continue
+			}
+			var names []string
+			constraint := ""
+			for _, part := range decl.Children {
+				switch part.Type {
+				case "identifier":
+					names = append(names, strings.TrimSpace(part.Value))
+				case "type_constraint":
+					constraint = strings.Join(strings.Fields(part.Value), " ")
+				}
+			}
+			for _, name := range names {
+				params = append(params, strings.TrimSpace(name+" "+constraint))
+			}
+		}
+	}
+	if len(params) > 0 {
+		symbol.SetMetadata(MetadataTypeParameters, params)
+	}
+}
+
+// recordGoStruct records the embedded fields of a struct, the fields
+// declared with a type and no name, such as "*Base" or "sync.Mutex"
+func recordGoStruct(symbol *types.Symbol, structType *types.ASTNode) {
+	var embeds []string
+	for _, list := range structType.Children {
+		if list.Type != "field_declaration_list" {
+			continue
+		}
+		for _, field := range list.Children {
+			if field.Type != "field_declaration" {
+				continue
+			}
+			embedded, pointer, named := "", false, false
+			for _, child := range field.Children {
+				switch child.Type {
+				case "field_identifier":
+					named = true
+				case "*":
+					pointer = true
+				case "type_identifier", "qualified_type", "generic_type":
+					embedded = strings.Join(strings.Fields(child.Value), "")
+				}
+			}
+			if named {
+				continue
+			}
+			if embedded != "" {
+				if pointer {
+					embedded = "*" + embedded
+				}
+				embeds = append(embeds, embedded)
+			}
+		}
+	}
+	if len(embeds) > 0 {
+		symbol.SetMetadata(MetadataEmbeds, embeds)
+	}
+}
+
+// recordGoInterface records the methods an interface declares, the
+// interfaces it embeds and the union and ~ terms of a constraint interface
+func recordGoInterface(symbol *types.Symbol, iface *types.ASTNode) {
+	var methods, embeds, typeSet []string
+	for _, elem := range iface.Children {
+		switch elem.Type {
+		case "method_elem", "method_spec":
+			var name string
+			var params, result *types.ASTNode
+			for _, child := range elem.Children {
+				switch {
+				case child.Type == "field_identifier":
+					name = strings.TrimSpace(child.Value)
+				case child.Type == "parameter_list" && params == nil:
+					params = child
+				case name != "" && params != nil && child.Type != ",":
+					result = child
+				}
+			}
+			if name != "" && params != nil {
+				methods = append(methods, goMethodSignature(name, params, result))
+			}
+		case "type_elem", "constraint_elem", "interface_type_name", "qualified_type", "type_identifier":
+			text := strings.Join(strings.Fields(elem.Value), " ")
+			if goTypeSetTerm(elem) {
+				typeSet = append(typeSet, text)
+			} else {
+				embeds = append(embeds, strings.ReplaceAll(text, " ", ""))
+			}
+		}
+	}
+	if len(methods) > 0 {
+		symbol.SetMetadata(MetadataInterfaceMethods, methods)
+	}
+	if len(embeds) > 0 {
+		symbol.SetMetadata(MetadataEmbeds, embeds)
+	}
+	if len(typeSet) > 0 {
+		symbol.SetMetadata(MetadataTypeSet, typeSet)
+	}
+}
+
+// goTypeSetTerm reports whether an interface element restricts the type set,
+// as unions, ~T terms and non-interface types do, rather than embedding an
+// interface. Predeclared types other than comparable and any are type terms.
+func goTypeSetTerm(elem *types.ASTNode) bool {
+	if elem.Type != "type_elem" && elem.Type != "constraint_elem" {
+		return false
+	}
+	var named []*types.ASTNode
+	for _, child := range elem.Children {
+		if child.Type != "|" {
+			named = append(named, child)
+		}
+	}
+	if len(named) != 1 {
+		return true
+	}
+	switch named[0].Type {
+	case "qualified_type", "generic_type":
+		return false
+	case "type_identifier":
+		name := strings.TrimSpace(named[0].Value)
+		return goPredeclaredTypes[name]
+	}
+	return true
+}
+
+// goPredeclaredTypes are the predeclared non-interface types of Go
+var goPredeclaredTypes = map[string]bool{
+	"bool": true, "byte": true, "rune": true, "string": true, "uintptr": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// goMethodSignature joins a method name with its parameter and result
+// types, without parameter names or package qualifiers
+func goMethodSignature(name string, params, result *types.ASTNode) string {
+	signature := name + "(" + strings.Join(goParameterTypes(params), ",") + ")"
+	switch {
+	case result == nil:
+	case result.Type == "parameter_list":
+		signature += "(" + strings.Join(goParameterTypes(result), ",") + ")"
+	default:
+		signature += goTypeText(result.Value)
+	}
+	return signature
+}
+
+// goParameterTypes returns one type per parameter of a parameter list, so
+// "a, b int" gives "int" twice; variadic parameters keep their "..."
+func goParameterTypes(list *types.ASTNode) []string {
+	var params []string
+	for _, param := range list.Children {
+		if param.Type != "parameter_declaration" && param.Type != "variadic_parameter_declaration" {
+			continue
+		}
+		names := 0
+		var typeNode *types.ASTNode
+		for _, child := range param.Children {
+			switch child.Type {
+			case "identifier":
+				names++
+			case ",", "...":
+			default:
+				typeNode = child
+			}
+		}
+		if typeNode == nil {
+			if len(param.Children) == 0 {
+				continue
+			}
+			// A lone identifier is an unnamed parameter of a named type
+			typeNode, names = param.Children[len(param.Children)-1], 0
+		}
+		text := goTypeText(typeNode.Value)
+		if param.Type == "variadic_parameter_declaration" {
+			text = "..." + text
+		}
+		for i := 0; i < max(names, 1); i++ {
+			params = append(params, text)
+		}
+	}
+	return params
+}
+
+// goTypeText normalizes a Go type for comparison
+func goTypeText(text string) string {
+	return goPackageQualifier.ReplaceAllString(strings.Join(strings.Fields(text), " "), "")
+}
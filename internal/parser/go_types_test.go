@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoMethodSetMetadata(t *testing.T) {
+	content := "package x\n\ntype ReadCloser interface {\n\tio.Closer\n\tRead(p []byte) (n int, err error)\n\tName() string\n}\n\n" +
+		"type File struct{ name string }\n\n" +
+		"func (f *File) Read(buf []byte) (int, error) { return 0, nil }\n" +
+		"func (f File) Join(a, b int, opts ...config.Option) string { return \"\" }\n"
+
+	manager := NewManager()
+	ast, err := manager.Parse(content, "x.go")
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	byName := make(map[string]map[string]interface{})
+	for _, symbol := range symbols {
+		byName[symbol.Name] = symbol.Metadata
+	}
+
+	require.NotNil(t, byName["ReadCloser"])
+	assert.Equal(t, []string{"Read([]byte)(int,error)", "Name()string"}, byName["ReadCloser"][MetadataInterfaceMethods])
+	assert.Equal(t, []string{"io.Closer"}, byName["ReadCloser"][MetadataEmbeds])
+	assert.Equal(t, "File", byName["Read"][MetadataReceiver])
+	assert.Equal(t, true, byName["Read"][MetadataPointerReceiver])
+	assert.Equal(t, "Read([]byte)(int,error)", byName["Read"][MetadataMethodSignature])
+	assert.Equal(t, "Join(int,int,...Option)string", byName["Join"][MetadataMethodSignature], "names and package qualifiers are dropped")
+	assert.Nil(t, byName["Join"][MetadataPointerReceiver])
+}
+
+func TestGoTypeComposition(t *testing.T) {
+	content := "package x\n\ntype Server struct {\n\t*Base\n\tsync.Mutex\n\tList[int] `json:\"list\"`\n\tname string\n}\n\n" +
+		"type Number interface {\n\t~int | ~float64\n\tcomparable\n\tfmt.Stringer\n}\n\n" +
+		"type Pair[K comparable, V any] struct{}\n\n" +
+		"func Map[K, V any](m map[K]V) []V { return nil }\n\n" +
+		"type (\n\tID int\n\tName = string\n)\n"
+
+	manager := NewManager()
+	ast, err := manager.Parse(content, "x.go")
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	byName := make(map[string]map[string]interface{})
+	for _, symbol := range symbols {
+		byName[symbol.Name] = symbol.Metadata
+	}
+
+	assert.Equal(t, []string{"*Base", "sync.Mutex", "List[int]"}, byName["Server"][MetadataEmbeds])
+	assert.Equal(t, []string{"~int | ~float64"}, byName["Number"][MetadataTypeSet])
+	assert.Equal(t, []string{"comparable", "fmt.Stringer"}, byName["Number"][MetadataEmbeds])
+	require.NotNil(t, byName["Pair"], "generic types are named without their type parameters")
+	assert.Equal(t, []string{"K comparable", "V any"}, byName["Pair"][MetadataTypeParameters])
+	assert.Equal(t, []string{"K any", "V any"}, byName["Map"][MetadataTypeParameters])
+	require.NotNil(t, byName["ID"], "grouped declarations yield every type")
+	require.NotNil(t, byName["Name"])
+	assert.Equal(t, "alias", byName["Name"][types.SubtypeMetadataKey])
+}
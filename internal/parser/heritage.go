@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for inheritance information
+const (
+	MetadataExtends    = "extends"
+	MetadataImplements = "implements"
+	MetadataMixins     = "mixins"
+)
+
+// maxHeritageHeaderLines bounds how far we look for the opening brace of a declaration
+const maxHeritageHeaderLines = 10
+
+// heritageKeywordPattern matches the clauses introducing supertypes in C-family languages
+var heritageKeywordPattern = regexp.MustCompile(`\b(extends|implements|with|on)\b`)
+
+// typeNamePattern matches a (possibly qualified) type name at the start of a string
+var typeNamePattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*(?:(?:\.|::)[A-Za-z_$][\w$]*)*`)
+
+// isHeritageCandidate reports whether a symbol type can have supertypes
+func isHeritageCandidate(symbolType types.SymbolType) bool {
+	switch symbolType {
+	case types.SymbolTypeClass, types.SymbolTypeInterface, types.SymbolTypeWidget,
+		types.SymbolTypeStateClass, types.SymbolTypeMixin, types.SymbolTypeComponent,
+		types.SymbolTypeService, types.SymbolTypeDirective:
+		return true
+	}
+	return false
+}
+
+// attachHeritage records extends/implements/mixin clauses in symbol metadata.
+// It reads the declaration header from the source rather than the AST so that it works
+// uniformly for tree-sitter backed languages and the regex-based Dart and Swift parsers.
+func attachHeritage(symbols []*types.Symbol, content, language string) {
+	if content == "" {
+		return
+	}
+
+	var lines []string
+	for _, symbol := range symbols {
+		if symbol == nil || !isHeritageCandidate(symbol.Type) || symbol.Location.StartLine <= 0 {
+			continue
+		}
+		if lines == nil {
+			lines = strings.Split(content, "\n")
+		}
+
+		header := declarationHeader(lines, symbol.Location.StartLine, language)
+		heritage := parseHeritage(language, header, symbol.Name)
+		if len(heritage) == 0 {
+			continue
+		}
+
+		for key, names := range heritage {
+			symbol.SetMetadata(key, names)
+		}
+	}
+}
+
+// declarationHeader returns the declaration text from startLine up to its body
+func declarationHeader(lines []string, startLine int, language string) string {
+	if startLine > len(lines) {
+		return ""
+	}
+
+	end := startLine - 1 + maxHeritageHeaderLines
+	if end > len(lines) {
+		end = len(lines)
+	}
+	header := strings.Join(lines[startLine-1:end], " ")
+
+	if idx := strings.Index(header, "{"); idx != -1 {
+		header = header[:idx]
+	}
+	if language == "python" {
+		if idx := strings.Index(header, "):"); idx != -1 {
+			header = header[:idx+1]
+		}
+	}
+	return header
+}
+
+// parseHeritage extracts supertypes from a declaration header, keyed by relationship
+func parseHeritage(language, header, name string) map[string][]string {
+	rest := afterDeclaredName(header, name)
+	if rest == "" {
+		return nil
+	}
+	rest = strings.TrimSpace(skipGenericParams(strings.TrimSpace(rest)))
+
+	result := make(map[string][]string)
+	add := func(key string, names []string) {
+		for _, n := range names {
+			if cleaned := cleanTypeName(n); cleaned != "" {
+				result[key] = append(result[key], cleaned)
+			}
+		}
+	}
+
+	switch language {
+	case "cpp", "c++":
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, "final"))
+		if !strings.HasPrefix(rest, ":") {
+			return nil
+		}
+		for _, base := range SplitTopLevel(rest[1:], ',') {
+			fields := strings.Fields(base)
+			kept := fields[:0]
+			for _, f := range fields {
+				if f != "public" && f != "protected" && f != "private" && f != "virtual" {
+					kept = append(kept, f)
+				}
+			}
+			add(MetadataExtends, []string{strings.Join(kept, " ")})
+		}
+	case "python":
+		if !strings.HasPrefix(rest, "(") {
+			return nil
+		}
+		inner := rest[1:]
+		if idx := strings.LastIndex(inner, ")"); idx != -1 {
+			inner = inner[:idx]
+		}
+		for _, base := range SplitTopLevel(inner, ',') {
+			if strings.Contains(base, "=") {
+				continue // metaclass=..., total=False etc.
+			}
+			add(MetadataExtends, []string{base})
+		}
+	default:
+		matches := heritageKeywordPattern.FindAllStringSubmatchIndex(rest, -1)
+		for i, match := range matches {
+			keyword := rest[match[2]:match[3]]
+			segmentEnd := len(rest)
+			if i+1 < len(matches) {
+				segmentEnd = matches[i+1][0]
+			}
+			segment := rest[match[1]:segmentEnd]
+
+			switch keyword {
+			case "extends":
+				add(MetadataExtends, SplitTopLevel(segment, ','))
+			case "implements":
+				add(MetadataImplements, SplitTopLevel(segment, ','))
+			case "with":
+				if language == "dart" {
+					add(MetadataMixins, SplitTopLevel(segment, ','))
+				}
+			case "on":
+				// Dart mixin superclass constraints behave like supertypes
+				if language == "dart" {
+					add(MetadataExtends, SplitTopLevel(segment, ','))
+				}
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// afterDeclaredName returns the header text following the declared symbol name
+func afterDeclaredName(header, name string) string {
+	if name == "" || name == "unknown" {
+		return ""
+	}
+	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)
+	loc := pattern.FindStringIndex(header)
+	if loc == nil {
+		return ""
+	}
+	return header[loc[1]:]
+}
+
+// skipGenericParams drops a leading <...> type parameter list, honouring nesting
+func skipGenericParams(s string) string {
+	if !strings.HasPrefix(s, "<") {
+		return s
+	}
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				return s[i+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// SplitTopLevel splits s at each sep outside brackets, such as the commas
+// of a parameter or type list. The "=>" of arrow functions opens nothing.
+// Parts are returned untrimmed.
+func SplitTopLevel(s string, sep rune) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range s {
+		if r == '>' && i > 0 && s[i-1] == '=' {
+			continue
+		}
+		switch r {
+		case '(', '[', '{', '<':
+			depth++
+		case ')', ']', '}', '>':
+			if depth > 0 {
+				depth--
+			}
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// cleanTypeName reduces a supertype expression to its bare type name
+func cleanTypeName(s string) string {
+	return typeNamePattern.FindString(strings.TrimSpace(s))
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHeritage(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		header   string
+		symbol   string
+		expected map[string][]string
+	}{
+		{
+			name:     "typescript extends and implements",
+			language: "typescript",
+			header:   "export class UserService extends BaseService<User> implements Disposable, Loggable ",
+			symbol:   "UserService",
+			expected: map[string][]string{
+				MetadataExtends:    {"BaseService"},
+				MetadataImplements: {"Disposable", "Loggable"},
+			},
+		},
+		{
+			name:     "java generic class",
+			language: "java",
+			header:   "public class Repo<T extends Entity> extends AbstractRepo<T> implements java.io.Serializable ",
+			symbol:   "Repo",
+			expected: map[string][]string{
+				MetadataExtends:    {"AbstractRepo"},
+				MetadataImplements: {"java.io.Serializable"},
+			},
+		},
+		{
+			name:     "dart mixins",
+			language: "dart",
+			header:   "class _HomeState extends State<Home> with TickerProviderStateMixin, RouteAware ",
+			symbol:   "_HomeState",
+			expected: map[string][]string{
+				MetadataExtends: {"State"},
+				MetadataMixins:  {"TickerProviderStateMixin", "RouteAware"},
+			},
+		},
+		{
+			name:     "cpp multiple inheritance",
+			language: "cpp",
+			header:   "class Widget final : public Base, protected virtual ns::Observer<Widget> ",
+			symbol:   "Widget",
+			expected: map[string][]string{
+				MetadataExtends: {"Base", "ns::Observer"},
+			},
+		},
+		{
+			name:     "python bases skip keywords",
+			language: "python",
+			header:   "class Model(Base, metaclass=ABCMeta)",
+			symbol:   "Model",
+			expected: map[string][]string{
+				MetadataExtends: {"Base"},
+			},
+		},
+		{
+			name:     "no heritage",
+			language: "typescript",
+			header:   "class Plain ",
+			symbol:   "Plain",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseHeritage(tt.language, tt.header, tt.symbol)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("parseHeritage() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitTopLevel(t *testing.T) {
+	tests := []struct {
+		input    string
+		sep      rune
+		expected []string
+	}{
+		{"Map<K, V>, Set<T>", ',', []string{"Map<K, V>", " Set<T>"}},
+		{"cb: (a, b) => void, opts: { x, y }", ',', []string{"cb: (a, b) => void", " opts: { x, y }"}},
+		{"string | Array<number | null>", '|', []string{"string ", " Array<number | null>"}},
+		{"a)", ',', []string{"a)"}},
+	}
+	for _, test := range tests {
+		if got := SplitTopLevel(test.input, test.sep); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("SplitTopLevel(%q, %q) = %q, expected %q", test.input, test.sep, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportKinds(t *testing.T) {
+	content := `import React, { useState as useLocalState, useEffect } from 'react';
+import * as path from './path';
+import type { Foo } from './types';
+import { type Bar, baz } from './mixed';
+import type from './type-named';
+import './polyfill';
+export { helper } from './helpers';
+export * from './all';
+export const local = 1;
+const page = import('./page');
+`
+	manager := NewManager()
+	ast, err := manager.Parse(content, "app.ts")
+	require.NoError(t, err)
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+
+	byPath := make(map[string]*types.Import)
+	for _, imp := range imports {
+		byPath[imp.Path] = imp
+	}
+	require.Len(t, byPath, 9, "the local export is not an import")
+
+	tests := []struct {
+		path       string
+		kind       types.ImportKind
+		specifiers []string
+	}{
+		{"react", types.ImportKindDefault, []string{"React", "useState", "useEffect"}},
+		{"./path", types.ImportKindNamespace, nil},
+		{"./types", types.ImportKindTypeOnly, []string{"Foo"}},
+		{"./mixed", types.ImportKindNamed, []string{"Bar", "baz"}},
+		{"./type-named", types.ImportKindDefault, []string{"type"}},
+		{"./polyfill", types.ImportKindSideEffect, nil},
+		{"./helpers", types.ImportKindReexport, []string{"helper"}},
+		{"./all", types.ImportKindReexport, nil},
+		{"./page", types.ImportKindDynamic, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			imp := byPath[tt.path]
+			require.NotNil(t, imp)
+			assert.Equal(t, tt.kind, imp.Kind)
+			assert.Equal(t, tt.specifiers, imp.Specifiers)
+		})
+	}
+	assert.Equal(t, "path", byPath["./path"].Alias)
+}
+
+func TestLazyImports(t *testing.T) {
+	content := `const Settings = React.lazy(() => import('./Settings'));
+const Chart = dynamic(() => import('./Chart'), { ssr: false });
+const routes = [
+  { path: '/a', component: () => import('./views/A.vue') },
+  { path: 'admin', loadChildren: () => import('./admin/admin.module').then(m => m.AdminModule) },
+];
+const eager = require('./eager');
+function load() {
+  return require('./heavy');
+}
+async function open() {
+  const { editor } = await import('./editor');
+}
+`
+	manager := NewManager()
+	ast, err := manager.Parse(content, "app.tsx")
+	require.NoError(t, err)
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+
+	byPath := make(map[string]*types.Import)
+	for _, imp := range imports {
+		byPath[imp.Path] = imp
+	}
+
+	tests := []struct {
+		path   string
+		kind   types.ImportKind
+		lazy   bool
+		loader string
+	}{
+		{"./Settings", types.ImportKindDynamic, true, "React.lazy"},
+		{"./Chart", types.ImportKindDynamic, true, "next/dynamic"},
+		{"./views/A.vue", types.ImportKindDynamic, true, "route"},
+		{"./admin/admin.module", types.ImportKindDynamic, true, "route"},
+		{"./eager", types.ImportKindRequire, false, ""},
+		{"./heavy", types.ImportKindRequire, true, ""},
+		{"./editor", types.ImportKindDynamic, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			imp := byPath[tt.path]
+			require.NotNil(t, imp)
+			assert.Equal(t, tt.kind, imp.Kind)
+			assert.Equal(t, tt.lazy, imp.Lazy)
+			assert.Equal(t, tt.loader, imp.Loader)
+		})
+	}
+}
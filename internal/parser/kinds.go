@@ -0,0 +1,69 @@
+package parser
+
+import "github.com/nuthan-ms/codecontext/pkg/types"
+
+// nodeSubtypes are the language-specific subtypes of tree-sitter declarations
+// whose symbol type alone is too coarse, such as a Rust trait reported as an
+// interface or a Swift struct reported as a class
+var nodeSubtypes = map[string]string{
+	"struct_item":                "struct", // Rust
+	"enum_item":                  "enum",
+	"trait_item":                 "trait",
+	"impl_item":                  "impl",
+	"struct_specifier":           "struct", // C/C++
+	"union_specifier":            "union",
+	"enum_specifier":             "enum",
+	"struct_declaration":         "struct", // Swift
+	"protocol_declaration":       "protocol",
+	"actor_declaration":          "actor",
+	"extension_declaration":      "extension",
+	"init_declaration":           "constructor",
+	"deinit_declaration":         "destructor",
+	"subscript_declaration":      "subscript",
+	"associatedtype_declaration": "associatedtype",
+	"typealias_declaration":      "alias",
+	"result_builder_declaration": "result_builder",
+	"record_declaration":         "record", // Java
+	"enum_declaration":           "enum",
+	"type_alias_declaration":     "alias", // TypeScript
+}
+
+// setSubtype records the language-specific subtype of the declaration a symbol
+// was extracted from. The normalized Kind is derived from it in ExtractSymbols.
+func setSubtype(symbol *types.Symbol, node *types.ASTNode) {
+	subtype := nodeSubtypes[node.Type]
+	if symbol.Language == "go" && (node.Type == "type_spec" || node.Type == "type_alias") {
+		subtype = goTypeSubtype(node)
+	}
+	if subtype == "" || symbol.Subtype() != "" {
+		return
+	}
+	if symbol.Metadata == nil {
+		symbol.Metadata = make(map[string]interface{})
+	}
+	symbol.Metadata[types.SubtypeMetadataKey] = subtype
+}
+
+// goTypeSubtype says what a Go type spec declares: a struct, an interface,
+// an alias (type A = B) or another defined type
+func goTypeSubtype(node *types.ASTNode) string {
+	if node.Type == "type_alias" {
+		return "alias"
+	}
+	for _, spec := range node.Children {
+		switch spec.Type {
+		case "struct_type":
+			return "struct"
+		case "interface_type":
+			return "interface"
+		}
+	}
+	return "defined"
+}
+
+// classifySymbols sets the normalized kind of every extracted symbol
+func classifySymbols(symbols []*types.Symbol) {
+	for _, symbol := range symbols {
+		types.ClassifySymbol(symbol)
+	}
+}
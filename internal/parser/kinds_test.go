@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolKindTaxonomy(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    map[string][2]string // symbol name -> kind, subtype
+	}{
+		{
+			file:    "shapes.go",
+			content: "package shapes\n\ntype Shape interface{ Area() float64 }\ntype Square struct{ Side float64 }\ntype Unit = Square\ntype Meters float64\n\nfunc NewSquare() *Square { return nil }\n",
+			want: map[string][2]string{
+				"Shape":     {types.SymbolKindInterface, ""},
+				"Square":    {types.SymbolKindClass, "struct"},
+				"Unit":      {types.SymbolKindTypeAlias, "alias"},
+				"Meters":    {types.SymbolKindTypeAlias, "defined"},
+				"NewSquare": {types.SymbolKindFunction, ""},
+			},
+		},
+		{
+			file:    "Shapes.java",
+			content: "interface Shape {}\nenum Color { RED }\nrecord Point(int x, int y) {}\nclass Canvas {}\n",
+			want: map[string][2]string{
+				"Shape":  {types.SymbolKindInterface, ""},
+				"Color":  {types.SymbolKindEnum, ""},
+				"Point":  {types.SymbolKindClass, "record"},
+				"Canvas": {types.SymbolKindClass, ""},
+			},
+		},
+		{
+			file:    "shapes.rs",
+			content: "struct Square {}\nenum Color { Red }\ntrait Area {}\n",
+			want: map[string][2]string{
+				"Square": {types.SymbolKindClass, "struct"},
+				"Color":  {types.SymbolKindEnum, ""},
+				"Area":   {types.SymbolKindInterface, "trait"},
+			},
+		},
+	}
+
+	manager := NewManager()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ast, err := manager.Parse(tt.content, tt.file)
+			require.NoError(t, err)
+			symbols, err := manager.ExtractSymbols(ast)
+			require.NoError(t, err)
+
+			got := make(map[string][2]string)
+			for _, symbol := range symbols {
+				got[symbol.Name] = [2]string{symbol.Kind, symbol.Subtype()}
+			}
+			for name, want := range tt.want {
+				assert.Equal(t, want, got[name], name)
+			}
+		})
+	}
+}
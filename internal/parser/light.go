@@ -0,0 +1,546 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Light parsers read declarations and imports line by line with regular
+// expressions instead of tree-sitter. The fast analysis profile uses them:
+// they find functions, types, methods and module-level variables by their
+// keywords, but no local symbols, component or framework details, values or
+// embedded languages. Other languages keep their regular parser.
+
+// Node types of a light AST
+const (
+	lightRootType        = "light_program"
+	lightDeclarationType = "light_declaration"
+	lightImportType      = "light_import"
+)
+
+// lightMaxHeaderLines bounds how far a declaration is searched for its opening brace
+const lightMaxHeaderLines = 5
+
+// lightRule matches a declaration line, with the name in the last group and
+// the receiver of a method, if any, in the first
+type lightRule struct {
+	pattern    *regexp.Regexp
+	symbolType types.SymbolType
+	member     bool // Only matches directly inside a class body
+}
+
+// lightLanguage is the light parser of one language
+type lightLanguage struct {
+	rules    []lightRule
+	classes  map[types.SymbolType]bool // Symbol types whose body holds member rules
+	indented bool                      // Bodies end by indentation rather than braces
+	imports  func(line string) []*types.Import
+	scopes   *regexp.Regexp // Blocks qualifying the declarations in their body without declaring a symbol
+}
+
+var (
+	lightGoImport       = regexp.MustCompile(`^import\s+(?:([\w.]+)\s+)?"([^"]+)"`)
+	lightGoGroupMember  = regexp.MustCompile(`^\t(\w+)\b`)
+	lightGoGroupStart   = regexp.MustCompile(`^(import|type|const|var)\s*\($`)
+	lightPythonImport   = regexp.MustCompile(`^\s*import\s+([\w.]+)(?:\s+as\s+(\w+))?`)
+	lightPythonFrom     = regexp.MustCompile(`^\s*from\s+(\.*[\w.]*)\s+import\s+(.+)`)
+	lightJSImport       = regexp.MustCompile(`^\s*(?:import|export)\b(?:\s+type)?\s*(.*?)\s*(?:\bfrom\s+)?["']([^"']+)["']`)
+	lightJSRequire      = regexp.MustCompile(`\brequire\(\s*["']([^"']+)["']\s*\)`)
+	lightJavaImport     = regexp.MustCompile(`^\s*import\s+(?:static\s+)?([\w.]+(?:\.\*)?)\s*;`)
+	lightRustUse        = regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?use\s+([\w:]+?(?:::\*)?)(?:::\{([^{}]*)\})?(?:\s+as\s+(\w+))?\s*;`)
+	lightJSDefaultName  = regexp.MustCompile(`^(\w+)`)
+	lightJSNamespace    = regexp.MustCompile(`\*\s+as\s+(\w+)`)
+	lightJSNamedImports = regexp.MustCompile(`\{([^}]*)\}`)
+	lightMemberKeywords = map[string]bool{
+		"if": true, "for": true, "while": true, "switch": true, "catch": true, "return": true,
+		"new": true, "else": true, "do": true, "try": true, "function": true, "super": true, "this": true,
+	}
+)
+
+const jsModifiers = `(?:(?:public|private|protected|static|async|readonly|override|abstract|declare|get|set)\s+)*`
+
+var jsLightRules = []lightRule{
+	{regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\s*\*?\s*(\w+)\s*[<(]`), types.SymbolTypeFunction, false},
+	{regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+(\w+)`), types.SymbolTypeClass, false},
+	{regexp.MustCompile(`^\s*(?:export\s+)?(?:declare\s+)?interface\s+(\w+)`), types.SymbolTypeInterface, false},
+	{regexp.MustCompile(`^\s*(?:export\s+)?(?:declare\s+)?type\s+(\w+)\s*(?:<[^=]*>)?\s*=`), types.SymbolTypeType, false},
+	{regexp.MustCompile(`^\s*(?:export\s+)?(?:declare\s+)?(?:const\s+)?enum\s+(\w+)`), types.SymbolTypeType, false},
+	{regexp.MustCompile(`^(?:export\s+)?(?:const|let|var)\s+(\w+)\s*(?::[^=]+)?=\s*(?:async\s+)?(?:function\b|(?:\([^)]*\)|\w+)\s*(?::[^=]+)?=>)`), types.SymbolTypeFunction, false},
+	{regexp.MustCompile(`^(?:export\s+)?(?:const|let|var)\s+(\w+)`), types.SymbolTypeVariable, false},
+	{regexp.MustCompile(`^\s*` + jsModifiers + `\*?(\w+)\s*(?:<[^>]*>)?\s*\([^)]*\)?\s*(?::\s*[^{;]+)?(?:\{.*)?$`), types.SymbolTypeMethod, true},
+}
+
+const javaModifiers = `(?:(?:public|protected|private|static|final|abstract|sealed|non-sealed|strictfp|synchronized|native|default)\s+)*`
+
+// lightLanguages are the languages with a light parser, by language name
+var lightLanguages = map[string]*lightLanguage{
+	"go": {
+		rules: []lightRule{
+			{regexp.MustCompile(`^func\s+\(\s*(?:\w+\s+)?\*?(\w+)[^)]*\)\s*(\w+)\s*[\[(]`), types.SymbolTypeMethod, false},
+			{regexp.MustCompile(`^func\s+(\w+)\s*[\[(]`), types.SymbolTypeFunction, false},
+			{regexp.MustCompile(`^type\s+(\w+)\b`), types.SymbolTypeType, false},
+			{regexp.MustCompile(`^const\s+(\w+)\b`), types.SymbolTypeConstant, false},
+			{regexp.MustCompile(`^var\s+(\w+)\b`), types.SymbolTypeVariable, false},
+		},
+		imports: goLightImports,
+	},
+	"python": {
+		rules: []lightRule{
+			{regexp.MustCompile(`^\s*(?:async\s+)?def\s+(\w+)\s*\(`), types.SymbolTypeFunction, false},
+			{regexp.MustCompile(`^\s*class\s+(\w+)`), types.SymbolTypeClass, false},
+			{regexp.MustCompile(`^([A-Za-z_]\w*)\s*(?::[^=]+)?=[^=]`), types.SymbolTypeVariable, false},
+		},
+		classes:  map[types.SymbolType]bool{types.SymbolTypeClass: true},
+		indented: true,
+		imports:  pythonLightImports,
+	},
+	"javascript": {rules: jsLightRules, classes: map[types.SymbolType]bool{types.SymbolTypeClass: true}, imports: jsLightImports},
+	"typescript": {rules: jsLightRules, classes: map[types.SymbolType]bool{types.SymbolTypeClass: true}, imports: jsLightImports},
+	"java": {
+		rules: []lightRule{
+			{regexp.MustCompile(`^\s*` + javaModifiers + `(?:class|record)\s+(\w+)`), types.SymbolTypeClass, false},
+			{regexp.MustCompile(`^\s*` + javaModifiers + `@?interface\s+(\w+)`), types.SymbolTypeInterface, false},
+			{regexp.MustCompile(`^\s*` + javaModifiers + `enum\s+(\w+)`), types.SymbolTypeEnum, false},
+			{regexp.MustCompile(`^\s*` + javaModifiers + `(?:<[^>]+>\s+)?(?:[\w.]+(?:<[^()]*>)?(?:\[\])*\s+)?(\w+)\s*\(`), types.SymbolTypeMethod, true},
+		},
+		classes: map[types.SymbolType]bool{types.SymbolTypeClass: true, types.SymbolTypeInterface: true, types.SymbolTypeEnum: true},
+		imports: javaLightImports,
+	},
+	"rust": {
+		rules: []lightRule{
+			{regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const\s+)?(?:async\s+)?(?:unsafe\s+)?(?:extern\s+"[^"]*"\s+)?fn\s+(\w+)`), types.SymbolTypeFunction, false},
+			{regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?struct\s+(\w+)`), types.SymbolTypeClass, false},
+			{regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?enum\s+(\w+)`), types.SymbolTypeType, false},
+			{regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:unsafe\s+)?trait\s+(\w+)`), types.SymbolTypeInterface, false},
+			{regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:const|static)\s+(?:mut\s+)?(\w+)\s*:`), types.SymbolTypeConstant, false},
+		},
+		classes: map[types.SymbolType]bool{types.SymbolTypeInterface: true},
+		imports: rustLightImports,
+		// Functions of an impl block belong to the implementing type, which is declared elsewhere
+		scopes: regexp.MustCompile(`^\s*impl\b(?:<[^>]*>)?\s+(?:[\w:]+(?:<[^>]*>)?\s+for\s+)?(\w+)`),
+	},
+}
+
+// HasLightParser reports whether a language has a light parser
+func HasLightParser(language string) bool {
+	return lightLanguages[language] != nil
+}
+
+// SetLightParsing makes ParseFile use the light parser of languages that have
+// one. The fast analysis profile turns it on.
+func (m *Manager) SetLightParsing(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.light = enabled
+}
+
+func (m *Manager) lightParsing() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.light
+}
+
+// parseLightContent builds a flat AST of the declarations and imports found by
+// the light parser of language, or returns nil when it has none
+func parseLightContent(content, language, filePath string) *types.AST {
+	parser := lightLanguages[language]
+	if parser == nil {
+		return nil
+	}
+	root := &types.ASTNode{
+		Id:       "light-root",
+		Type:     lightRootType,
+		Location: types.FileLocation{FilePath: filePath, Line: 1, Column: 1},
+		Metadata: make(map[string]interface{}),
+	}
+
+	lines := strings.Split(content, "\n")
+	code, depths := lightCodeLines(lines, parser.indented)
+	var classes []lightSpan
+	group := ""
+	for i, line := range code {
+		// Go groups declare one import, type, constant or variable per line
+		if language == "go" {
+			if m := lightGoGroupStart.FindStringSubmatch(line); m != nil {
+				group = m[1]
+				continue
+			}
+			if group != "" {
+				if strings.HasPrefix(line, ")") {
+					group = ""
+				} else if group == "import" {
+					imports := parser.imports("import " + strings.TrimSpace(lines[i]))
+					root.Children = append(root.Children, lightImportNodes(imports, filePath, i+1)...)
+				} else if m := lightGoGroupMember.FindStringSubmatch(line); m != nil {
+					symbolType := map[string]types.SymbolType{"type": types.SymbolTypeType, "const": types.SymbolTypeConstant, "var": types.SymbolTypeVariable}[group]
+					root.Children = append(root.Children, lightDeclarationNode(m[1], symbolType, "", lines[i], filePath, i+1, i+1))
+				}
+				continue
+			}
+		}
+
+		// Import paths are string literals, so they are read from the original line
+		if parser.imports != nil && strings.TrimSpace(line) != "" {
+			if imports := parser.imports(lines[i]); len(imports) > 0 {
+				root.Children = append(root.Children, lightImportNodes(imports, filePath, i+1)...)
+				continue
+			}
+		}
+
+		if parser.scopes != nil {
+			if m := parser.scopes.FindStringSubmatch(line); m != nil {
+				end := lightBodyEnd(code, depths, i, parser.indented)
+				classes = append(classes, lightSpan{name: m[len(m)-1], start: i, end: end, depth: depths[i] + 1})
+				continue
+			}
+		}
+
+		for _, rule := range parser.rules {
+			if rule.member && !insideClassBody(classes, i, depths[i]) {
+				continue
+			}
+			m := rule.pattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			name := m[len(m)-1]
+			if rule.member && lightMemberKeywords[name] {
+				break
+			}
+			scope := enclosingClass(classes, i)
+			if len(m) > 2 {
+				scope = m[1]
+			}
+			end := lightBodyEnd(code, depths, i, parser.indented)
+			root.Children = append(root.Children, lightDeclarationNode(name, rule.symbolType, scope, lines[i], filePath, i+1, end+1))
+			if parser.classes[rule.symbolType] {
+				classes = append(classes, lightSpan{name: name, start: i, end: end, depth: depths[i] + 1})
+			}
+			break
+		}
+	}
+
+	return &types.AST{
+		Root:     root,
+		Language: language,
+		Content:  content,
+		FilePath: filePath,
+		Hash:     calculateHash(content),
+		Version:  "1.0",
+		ParsedAt: time.Now(),
+	}
+}
+
+// lightSpan is the line range of a class body and the depth of its members
+type lightSpan struct {
+	name              string
+	start, end, depth int
+}
+
+func insideClassBody(classes []lightSpan, line, depth int) bool {
+	for _, class := range classes {
+		if line > class.start && line <= class.end && depth == class.depth {
+			return true
+		}
+	}
+	return false
+}
+
+// lightCodeLines blanks comments and string contents and returns each line's
+// nesting depth: open braces before it, or its indentation for Python
+func lightCodeLines(lines []string, indented bool) ([]string, []int) {
+	code := make([]string, len(lines))
+	depths := make([]int, len(lines))
+	depth := 0
+	inComment := false
+	for i, line := range lines {
+		var b strings.Builder
+		var quote byte
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case inComment:
+				if c == '*' && j+1 < len(line) && line[j+1] == '/' {
+					inComment = false
+					j++
+				}
+				continue
+			case quote != 0:
+				if c == '\\' {
+					j++
+				} else if c == quote {
+					quote = 0
+					b.WriteByte(c)
+				}
+				continue
+			case c == '"' || c == '\'' || c == '`':
+				quote = c
+			case c == '/' && j+1 < len(line) && line[j+1] == '/', c == '#' && indented:
+				j = len(line)
+				continue
+			case c == '/' && j+1 < len(line) && line[j+1] == '*':
+				inComment = true
+				j++
+				continue
+			}
+			b.WriteByte(c)
+		}
+		code[i] = b.String()
+		if indented {
+			depths[i] = len(line) - len(strings.TrimLeft(line, " \t"))
+			continue
+		}
+		depths[i] = depth
+		depth += strings.Count(code[i], "{") - strings.Count(code[i], "}")
+	}
+	return code, depths
+}
+
+// lightBodyEnd returns the last line of the declaration starting at line start:
+// the line closing its braces, or for Python the last line indented deeper
+// than the declaration. Declarations without a body end on their first line.
+func lightBodyEnd(code []string, depths []int, start int, indented bool) int {
+	if indented {
+		end := start
+		for i := start + 1; i < len(code); i++ {
+			if strings.TrimSpace(code[i]) == "" {
+				continue
+			}
+			if depths[i] <= depths[start] {
+				break
+			}
+			end = i
+		}
+		return end
+	}
+	for i := start; i < len(code) && i < start+lightMaxHeaderLines; i++ {
+		if strings.Contains(code[i], "{") {
+			for j := i; j+1 < len(code); j++ {
+				if depths[j+1] <= depths[start] {
+					return j
+				}
+			}
+			return len(code) - 1
+		}
+		if strings.HasSuffix(strings.TrimSpace(code[i]), ";") {
+			return i
+		}
+	}
+	return start
+}
+
+// enclosingClass returns the name of the innermost class whose body holds line
+func enclosingClass(classes []lightSpan, line int) string {
+	name := ""
+	// Classes are appended in order, so later matches are nested deeper
+	for _, class := range classes {
+		if line > class.start && line <= class.end {
+			name = class.name
+		}
+	}
+	return name
+}
+
+// lightDeclarationNode records a declaration with its name, symbol type and
+// the class or receiver it belongs to
+func lightDeclarationNode(name string, symbolType types.SymbolType, scope, line, filePath string, start, end int) *types.ASTNode {
+	return &types.ASTNode{
+		Id:       fmt.Sprintf("light-%s-%d", name, start),
+		Type:     lightDeclarationType,
+		Value:    strings.TrimSpace(line),
+		Location: types.FileLocation{FilePath: filePath, Line: start, Column: 1, EndLine: end},
+		Metadata: map[string]interface{}{"name": name, "symbol_type": symbolType, "scope": scope},
+	}
+}
+
+func lightImportNodes(imports []*types.Import, filePath string, line int) []*types.ASTNode {
+	nodes := make([]*types.ASTNode, 0, len(imports))
+	for _, imp := range imports {
+		imp.Location = types.FileLocation{FilePath: filePath, Line: line, Column: 1, EndLine: line}
+		nodes = append(nodes, &types.ASTNode{
+			Id:       fmt.Sprintf("light-import-%s-%d", imp.Path, line),
+			Type:     lightImportType,
+			Value:    imp.Path,
+			Location: imp.Location,
+			Metadata: map[string]interface{}{"import": imp},
+		})
+	}
+	return nodes
+}
+
+func isLightAST(ast *types.AST) bool {
+	return ast.Root != nil && ast.Root.Type == lightRootType
+}
+
+// lightSymbols turns the declarations of a light AST into symbols with stable
+// IDs, visibility and supertypes. IDs qualify names by filePath.
+func lightSymbols(ast *types.AST, filePath string) []*types.Symbol {
+	var symbols []*types.Symbol
+	for _, node := range ast.Root.Children {
+		if node.Type != lightDeclarationType {
+			continue
+		}
+		name, _ := node.Metadata["name"].(string)
+		symbolType, _ := node.Metadata["symbol_type"].(types.SymbolType)
+		symbol := &types.Symbol{
+			Name:         name,
+			Type:         symbolType,
+			Location:     convertLocation(node.Location),
+			Language:     ast.Language,
+			Hash:         calculateHash(node.Value),
+			LastModified: time.Now(),
+			Visibility:   lightVisibility(node.Value, name, symbolType, ast.Language),
+		}
+		symbol.Location.EndLine = node.Location.EndLine
+		if scope, _ := node.Metadata["scope"].(string); scope != "" {
+			symbol.SetMetadata(MetadataScope, scope)
+		}
+		if symbolType == types.SymbolTypeFunction || symbolType == types.SymbolTypeMethod {
+			symbol.Signature = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(node.Value), "{"))
+		}
+		symbols = append(symbols, symbol)
+	}
+	attachHeritage(symbols, ast.Content, ast.Language)
+	assignStableIds(symbols, filePath)
+	classifySymbols(symbols)
+	return symbols
+}
+
+// lightImports returns the imports of a light AST
+func lightImports(ast *types.AST) []*types.Import {
+	var imports []*types.Import
+	for _, node := range ast.Root.Children {
+		if imp, ok := node.Metadata["import"].(*types.Import); ok && node.Type == lightImportType {
+			imports = append(imports, imp)
+		}
+	}
+	return imports
+}
+
+// lightVisibility reads visibility from the declaration line: naming in Go and
+// Python, export in JavaScript and TypeScript, modifiers in Java and Rust
+func lightVisibility(line, name string, symbolType types.SymbolType, language string) string {
+	fields := strings.Fields(line)
+	has := func(words ...string) bool {
+		for _, field := range fields {
+			for _, word := range words {
+				if field == word {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	switch language {
+	case "go", "python":
+		return nameVisibility(name, language)
+	case "javascript", "typescript":
+		switch {
+		case has("private"), strings.HasPrefix(name, "#"):
+			return types.VisibilityPrivate
+		case has("protected"):
+			return types.VisibilityProtected
+		case symbolType == types.SymbolTypeMethod, has("export"):
+			return types.VisibilityPublic
+		}
+		return types.VisibilityPrivate
+	case "java":
+		for _, modifier := range []string{types.VisibilityPublic, types.VisibilityProtected, types.VisibilityPrivate} {
+			if has(modifier) {
+				return modifier
+			}
+		}
+		return types.VisibilityPackage
+	case "rust":
+		switch {
+		case has("pub"):
+			return types.VisibilityPublic
+		case strings.Contains(line, "pub("):
+			return types.VisibilityInternal
+		}
+		return types.VisibilityPrivate
+	}
+	return ""
+}
+
+// goLightImports reads an import declaration; lines of an import group are
+// read with the keyword prepended
+func goLightImports(line string) []*types.Import {
+	m := lightGoImport.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	return []*types.Import{{Path: m[2], Alias: m[1]}}
+}
+
+// pythonLightImports reads "import a.b as c" and "from a import b, c"
+func pythonLightImports(line string) []*types.Import {
+	if m := lightPythonFrom.FindStringSubmatch(line); m != nil {
+		imp := &types.Import{Path: m[1]}
+		for _, name := range strings.Split(strings.Trim(m[2], "() \\"), ",") {
+			if name = strings.TrimSpace(strings.SplitN(strings.TrimSpace(name), " as ", 2)[0]); name != "" {
+				imp.Specifiers = append(imp.Specifiers, name)
+			}
+		}
+		return []*types.Import{imp}
+	}
+	if m := lightPythonImport.FindStringSubmatch(line); m != nil {
+		return []*types.Import{{Path: m[1], Alias: m[2]}}
+	}
+	return nil
+}
+
+// jsLightImports reads single-line import and re-export statements and require calls
+func jsLightImports(line string) []*types.Import {
+	m := lightJSImport.FindStringSubmatch(line)
+	if m == nil {
+		if m := lightJSRequire.FindStringSubmatch(line); m != nil {
+			return []*types.Import{{Path: m[1]}}
+		}
+		return nil
+	}
+	imp := &types.Import{Path: m[2]}
+	clause := m[1]
+	if named := lightJSNamedImports.FindStringSubmatch(clause); named != nil {
+		for _, spec := range strings.Split(named[1], ",") {
+			if name := strings.Fields(strings.TrimPrefix(strings.TrimSpace(spec), "type ")); len(name) > 0 {
+				imp.Specifiers = append(imp.Specifiers, name[0])
+			}
+		}
+		clause = strings.Replace(clause, named[0], "", 1)
+	}
+	if ns := lightJSNamespace.FindStringSubmatch(clause); ns != nil {
+		imp.Alias = ns[1]
+	} else if name := lightJSDefaultName.FindStringSubmatch(strings.TrimSpace(clause)); name != nil && !strings.HasPrefix(strings.TrimSpace(line), "export") {
+		imp.IsDefault = true
+		imp.Specifiers = append([]string{name[1]}, imp.Specifiers...)
+	}
+	return []*types.Import{imp}
+}
+
+// rustLightImports reads "use a::b;", "use a::{b, c};" and "use a::b as c;"
+func rustLightImports(line string) []*types.Import {
+	m := lightRustUse.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	imp := &types.Import{Path: m[1], Alias: m[3]}
+	for _, spec := range strings.Split(m[2], ",") {
+		if name := strings.Fields(spec); len(name) > 0 {
+			imp.Specifiers = append(imp.Specifiers, name[0])
+		}
+	}
+	return []*types.Import{imp}
+}
+
+// javaLightImports reads "import a.b.C;" and static imports
+func javaLightImports(line string) []*types.Import {
+	if m := lightJavaImport.FindStringSubmatch(line); m != nil {
+		return []*types.Import{{Path: m[1]}}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseLight parses content with light parsing on and returns its symbols by name
+func parseLight(t *testing.T, filePath, content string) (map[string]*types.Symbol, []*types.Import) {
+	t.Helper()
+	manager := NewManager()
+	manager.SetLightParsing(true)
+
+	lang := manager.detectLanguage(filePath)
+	require.NotNil(t, lang)
+	ast, err := manager.parseContent(content, *lang, filePath)
+	require.NoError(t, err)
+	require.True(t, isLightAST(ast))
+
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+
+	// Constructors share their class's name, so the first declaration wins
+	byName := make(map[string]*types.Symbol)
+	for _, symbol := range symbols {
+		if byName[symbol.Name] == nil {
+			byName[symbol.Name] = symbol
+		}
+	}
+	return byName, imports
+}
+
+func TestLightParserGo(t *testing.T) {
+	symbols, imports := parseLight(t, "server.go", `package server
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+)
+
+import "strings"
+
+// Server handles requests
+type Server struct {
+	name string // "func ignored() {"
+}
+
+type (
+	Handler func()
+	router  struct{}
+)
+
+const Version = "1.0"
+
+var (
+	defaultName = "server"
+)
+
+func New(name string) *Server {
+	return &Server{name: strings.TrimSpace(name)}
+}
+
+func (s *Server) Start() error {
+	fmt.Println("{")
+	log.Info(s.name)
+	return nil
+}
+`)
+
+	require.Len(t, imports, 3)
+	assert.Equal(t, "fmt", imports[0].Path)
+	assert.Equal(t, "github.com/sirupsen/logrus", imports[1].Path)
+	assert.Equal(t, "log", imports[1].Alias)
+	assert.Equal(t, "strings", imports[2].Path)
+
+	require.Len(t, symbols, 7)
+	assert.Equal(t, types.SymbolTypeType, symbols["Server"].Type)
+	assert.Equal(t, 11, symbols["Server"].Location.StartLine)
+	assert.Equal(t, 13, symbols["Server"].Location.EndLine)
+	assert.Equal(t, types.SymbolTypeType, symbols["Handler"].Type)
+	assert.Equal(t, types.VisibilityPrivate, symbols["router"].Visibility)
+	assert.Equal(t, types.SymbolTypeConstant, symbols["Version"].Type)
+	assert.Equal(t, types.SymbolTypeVariable, symbols["defaultName"].Type)
+
+	assert.Equal(t, types.SymbolTypeFunction, symbols["New"].Type)
+	assert.Equal(t, "func New(name string) *Server", symbols["New"].Signature)
+	assert.Equal(t, types.VisibilityPublic, symbols["New"].Visibility)
+
+	start := symbols["Start"]
+	assert.Equal(t, types.SymbolTypeMethod, start.Type)
+	assert.Equal(t, 30, start.Location.StartLine)
+	assert.Equal(t, 34, start.Location.EndLine, "braces in strings do not end the body")
+	assert.NotEmpty(t, start.Id)
+	assert.Equal(t, "server.go::Server.Start", start.FullyQualifiedName)
+}
+
+func TestLightParserPython(t *testing.T) {
+	symbols, imports := parseLight(t, "app/models.py", `import os.path as osp
+from .base import Model, Field as F
+
+DEFAULT_LIMIT = 10
+
+class User(Model):
+    # def not_a_method(): pass
+    def __init__(self, name):
+        self.name = name
+
+    def _secret(self):
+        return osp.join("a", "b")
+
+async def fetch(limit=DEFAULT_LIMIT):
+    return []
+`)
+
+	require.Len(t, imports, 2)
+	assert.Equal(t, "os.path", imports[0].Path)
+	assert.Equal(t, "osp", imports[0].Alias)
+	assert.Equal(t, ".base", imports[1].Path)
+	assert.Equal(t, []string{"Model", "Field"}, imports[1].Specifiers)
+
+	require.Len(t, symbols, 5)
+	user := symbols["User"]
+	assert.Equal(t, types.SymbolTypeClass, user.Type)
+	assert.Equal(t, 6, user.Location.StartLine)
+	assert.Equal(t, 12, user.Location.EndLine)
+	assert.Equal(t, []string{"Model"}, user.Metadata[MetadataExtends])
+	assert.Equal(t, types.VisibilityPrivate, symbols["_secret"].Visibility)
+	assert.Equal(t, "app/models.py::User._secret", symbols["_secret"].FullyQualifiedName)
+	assert.Equal(t, "app/models.py::fetch", symbols["fetch"].FullyQualifiedName)
+	assert.Equal(t, types.VisibilityPublic, symbols["__init__"].Visibility)
+	assert.Equal(t, types.SymbolTypeFunction, symbols["fetch"].Type)
+	assert.Equal(t, types.SymbolTypeVariable, symbols["DEFAULT_LIMIT"].Type)
+}
+
+func TestLightParserTypeScript(t *testing.T) {
+	symbols, imports := parseLight(t, "src/user.ts", `import React, { useState, type FC } from "react";
+import * as path from 'path';
+import './styles.css';
+export { helper } from "./helper";
+const fs = require("fs");
+
+export interface Props {
+  name: string;
+}
+
+export type Id = string | number;
+
+export enum Role { Admin, Guest }
+
+export class UserService extends BaseService {
+  private cache = new Map();
+
+  constructor(private readonly api: Api) {
+    super();
+  }
+
+  async load(id: Id): Promise<User> {
+    if (this.cache.has(id)) {
+      return this.cache.get(id);
+    }
+    return this.api.get(id);
+  }
+
+  protected reset() {}
+}
+
+export const formatName = (user: User): string => {
+  return user.name;
+};
+
+function internal() {}
+
+let counter = 0;
+`)
+
+	paths := make([]string, 0, len(imports))
+	for _, imp := range imports {
+		paths = append(paths, imp.Path)
+	}
+	assert.Equal(t, []string{"react", "path", "./styles.css", "./helper", "fs"}, paths)
+	assert.True(t, imports[0].IsDefault)
+	assert.Equal(t, []string{"React", "useState", "FC"}, imports[0].Specifiers)
+	assert.Equal(t, "path", imports[1].Alias)
+
+	assert.Equal(t, types.SymbolTypeInterface, symbols["Props"].Type)
+	assert.Equal(t, types.SymbolTypeType, symbols["Id"].Type)
+	assert.Equal(t, types.SymbolTypeType, symbols["Role"].Type)
+
+	service := symbols["UserService"]
+	require.NotNil(t, service)
+	assert.Equal(t, types.SymbolTypeClass, service.Type)
+	assert.Equal(t, types.VisibilityPublic, service.Visibility)
+	assert.Equal(t, 15, service.Location.StartLine)
+	assert.Equal(t, 30, service.Location.EndLine)
+	assert.Equal(t, []string{"BaseService"}, service.Metadata[MetadataExtends])
+
+	assert.Equal(t, types.SymbolTypeMethod, symbols["constructor"].Type)
+	assert.Equal(t, types.SymbolTypeMethod, symbols["load"].Type)
+	assert.Equal(t, 22, symbols["load"].Location.StartLine)
+	assert.Equal(t, types.VisibilityProtected, symbols["reset"].Visibility)
+	assert.NotContains(t, symbols, "if", "control flow is not a method")
+
+	assert.Equal(t, types.SymbolTypeFunction, symbols["formatName"].Type)
+	assert.Equal(t, types.VisibilityPrivate, symbols["internal"].Visibility)
+	assert.Equal(t, types.SymbolTypeVariable, symbols["counter"].Type)
+}
+
+func TestLightParserJava(t *testing.T) {
+	symbols, imports := parseLight(t, "src/main/java/com/acme/OrderService.java", `package com.acme;
+
+import java.util.List;
+import static java.util.Objects.requireNonNull;
+
+public class OrderService implements Service {
+    private final Repository repository;
+
+    public OrderService(Repository repository) {
+        this.repository = requireNonNull(repository);
+    }
+
+    public List<Order> findAll() {
+        return repository.findAll();
+    }
+
+    void flush() {
+        if (repository.dirty()) {
+            repository.flush();
+        }
+    }
+
+    enum Status { OPEN, CLOSED }
+}
+`)
+
+	require.Len(t, imports, 2)
+	assert.Equal(t, "java.util.List", imports[0].Path)
+	assert.Equal(t, "java.util.Objects.requireNonNull", imports[1].Path)
+
+	service := symbols["OrderService"]
+	require.NotNil(t, service)
+	assert.Equal(t, types.VisibilityPublic, service.Visibility)
+	assert.Equal(t, 24, service.Location.EndLine)
+	assert.Equal(t, types.SymbolTypeMethod, symbols["findAll"].Type)
+	assert.Equal(t, types.VisibilityPackage, symbols["flush"].Visibility)
+	assert.Equal(t, types.SymbolTypeEnum, symbols["Status"].Type)
+	assert.NotContains(t, symbols, "if")
+	assert.NotContains(t, symbols, "requireNonNull")
+}
+
+func TestLightParserRust(t *testing.T) {
+	const source = `use std::collections::HashMap;
+pub use crate::loader::{Loader as Load, Source};
+use std::io::Result as IoResult;
+
+pub struct Config {
+    pub name: String,
+}
+
+pub(crate) trait Loader {
+    fn load(&self) -> Config;
+}
+
+impl Config {
+    pub fn new() -> Self {
+        Config { name: String::new() }
+    }
+}
+
+impl Loader for Config {
+    fn load(&self) -> Config {
+        self.clone()
+    }
+}
+
+pub const MAX: usize = 10;
+
+enum Mode { Fast, Deep }
+`
+	symbols, imports := parseLight(t, "src/lib.rs", source)
+
+	require.Len(t, imports, 3)
+	assert.Equal(t, "std::collections::HashMap", imports[0].Path)
+	assert.Equal(t, "crate::loader", imports[1].Path)
+	assert.Equal(t, []string{"Loader", "Source"}, imports[1].Specifiers)
+	assert.Equal(t, "std::io::Result", imports[2].Path)
+	assert.Equal(t, "IoResult", imports[2].Alias)
+
+	require.Len(t, symbols, 6)
+	assert.Equal(t, types.SymbolTypeClass, symbols["Config"].Type)
+	assert.Equal(t, 5, symbols["Config"].Location.StartLine)
+	assert.Equal(t, types.VisibilityPublic, symbols["Config"].Visibility)
+	assert.Equal(t, types.SymbolTypeInterface, symbols["Loader"].Type)
+	assert.Equal(t, types.VisibilityInternal, symbols["Loader"].Visibility)
+	assert.Equal(t, types.SymbolTypeFunction, symbols["load"].Type)
+	assert.Equal(t, "src/lib.rs::Loader.load", symbols["load"].FullyQualifiedName)
+	assert.Equal(t, "src/lib.rs::Config.new", symbols["new"].FullyQualifiedName)
+	assert.Equal(t, types.SymbolTypeConstant, symbols["MAX"].Type)
+	assert.Equal(t, types.VisibilityPrivate, symbols["Mode"].Visibility)
+
+	manager := NewManager()
+	manager.SetLightParsing(true)
+	ast, err := manager.parseContent(source, *manager.detectLanguage("src/lib.rs"), "src/lib.rs")
+	require.NoError(t, err)
+	all, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	var configs int
+	for _, symbol := range all {
+		if symbol.Name == "Config" {
+			configs++
+		}
+	}
+	assert.Equal(t, 1, configs, "impl blocks declare no symbol of their own")
+}
+
+func TestLightParsingOnlyForLightLanguages(t *testing.T) {
+	manager := NewManager()
+	manager.SetLightParsing(true)
+
+	lang := manager.detectLanguage("deploy.sh")
+	require.NotNil(t, lang)
+	ast, err := manager.parseContent("deploy() {\n  echo hi\n}\n", *lang, "deploy.sh")
+	require.NoError(t, err)
+	assert.False(t, isLightAST(ast), "languages without a light parser keep their parser")
+
+	manager.SetLightParsing(false)
+	lang = manager.detectLanguage("main.go")
+	require.NotNil(t, lang)
+	ast, err = manager.parseContent("package main\n\nfunc main() {}\n", *lang, "main.go")
+	require.NoError(t, err)
+	assert.False(t, isLightAST(ast))
+}
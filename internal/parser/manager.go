@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -30,6 +31,20 @@ type Manager struct {
 	
 	// Language-specific parsers
 	cppParser *CppParser
+
+	// Grammars loaded from WASM at runtime
+	wasmLanguages  map[string]bool   // Language name -> loaded from WASM
+	wasmExtensions map[string]string // File extension -> WASM language name
+
+	// Languages set by path, matched relative to overrideRoot
+	overrideRoot string
+	overrides    []LanguageOverride
+
+	// Parse with the regex parsers of light.go where a language has one
+	light bool
+
+	// Symbol IDs qualify names by their path relative to root
+	root string
 	
 	// Injected dependencies
 	logger       Logger
@@ -221,13 +236,55 @@ func (m *Manager) ExtractSymbols(ast *types.AST) ([]*types.Symbol, error) {
 		return nil, fmt.Errorf("AST root is nil")
 	}
 
+	// Light ASTs hold only declarations, which carry their own names and types
+	if isLightAST(ast) {
+		return lightSymbols(ast, m.qualifiedPath(ast.FilePath)), nil
+	}
+
+	var symbols []*types.Symbol
+
 	// Use enhanced C++ parser for C++ files
 	if ast.Language == "cpp" && m.cppParser != nil {
-		return m.cppParser.ExtractSymbolsWithContext(ast.Root, ast.FilePath, ast.Content)
+		cppSymbols, err := m.cppParser.ExtractSymbolsWithContext(ast.Root, ast.FilePath, ast.Content)
+		if err != nil {
+			return nil, err
+		}
+		symbols = cppSymbols
+	} else if ast.Language == "json" || ast.Language == "yaml" {
+		symbols = extractConfigSymbols(ast)
+	} else {
+		m.extractSymbolsRecursiveWithContent(ast.Root, ast.FilePath, ast.Language, ast.Content, "", &symbols)
 	}
 
-	var symbols []*types.Symbol
-	m.extractSymbolsRecursiveWithContent(ast.Root, ast.FilePath, ast.Language, ast.Content, &symbols)
+	// Record literal values of constants and enum members for value search
+	symbols = attachValues(symbols, ast)
+
+	// Record component props; TypeScript components the grammar missed are added
+	symbols = attachComponentProps(symbols, ast)
+
+	// Visibility is read from declaration nodes, so it must run while
+	// symbol locations still match the AST
+	assignVisibility(symbols, ast.Root, ast.Language)
+
+	// GraphQL, SQL and HTML in string literals add their own definitions
+	symbols = append(symbols, extractEmbeddedSymbols(ast)...)
+
+	// Notebook symbols are located by cell rather than by line in the concatenated source
+	applyNotebookCells(symbols, ast.Root)
+
+	// Record supertypes before IDs are derived so hierarchy edges can be built later
+	attachHeritage(symbols, ast.Content, ast.Language)
+
+	// Python models are recognized by their bases, so this follows heritage
+	if ast.Language == "python" {
+		attachPythonDefinitions(symbols, ast.Root)
+	}
+
+	// Replace line-based IDs so symbols keep their identity across edits
+	assignStableIds(symbols, m.qualifiedPath(ast.FilePath))
+
+	// Map parser-specific symbol types onto the shared kind taxonomy
+	classifySymbols(symbols)
 
 	return symbols, nil
 }
@@ -238,8 +295,18 @@ func (m *Manager) ExtractImports(ast *types.AST) ([]*types.Import, error) {
 		return nil, fmt.Errorf("AST root is nil")
 	}
 
+	if isLightAST(ast) {
+		return lightImports(ast), nil
+	}
+
 	var imports []*types.Import
-	m.extractImportsRecursive(ast.Root, &imports)
+	m.extractImportsRecursive(ast.Root, nil, &imports)
+
+	// Module imports are not part of the C++ grammar
+	if ast.Language == "cpp" {
+		imports = append(imports, cppModuleImports(ast.Content, ast.FilePath)...)
+		imports = append(imports, objcModuleImports(ast.Content, ast.FilePath)...)
+	}
 
 	return imports, nil
 }
@@ -297,6 +364,15 @@ func (m *Manager) GetSupportedLanguages() []string {
 	// Add C++ support
 	languages = append(languages, "cpp")
 
+	// Add shell script support
+	languages = append(languages, "shell")
+
+	// Add Jupyter notebook support
+	languages = append(languages, "jupyter")
+
+	// Add template engine support
+	languages = append(languages, LanguageJinja, LanguageERB, LanguageHandlebars, LanguageGoTemplate)
+
 	return languages
 }
 
@@ -326,12 +402,17 @@ func (m *Manager) ClassifyFile(filePath string) (*types.FileClassification, erro
 	isGenerated := strings.Contains(baseName, "generated") ||
 		strings.Contains(baseName, "auto") ||
 		strings.HasSuffix(baseName, ".gen.ts") ||
-		strings.HasSuffix(baseName, ".generated.ts")
+		strings.HasSuffix(baseName, ".generated.ts") ||
+		strings.HasSuffix(baseName, ".g.dart") ||
+		strings.HasSuffix(baseName, ".freezed.dart")
 
 	// Detect framework - we need file content for better detection
 	var framework string
 	if content, err := os.ReadFile(filePath); err == nil {
 		framework = m.frameworkDetector.DetectFramework(filePath, lang.Name, string(content))
+		// build_runner and most other code generators write this header
+		header := content[:min(len(content), 512)]
+		isGenerated = isGenerated || strings.Contains(string(header), "GENERATED CODE - DO NOT MODIFY BY HAND")
 	} else {
 		// Fallback to filename-based detection only
 		framework = m.frameworkDetector.DetectFramework(filePath, lang.Name, "")
@@ -355,8 +436,30 @@ func (m *Manager) GetASTCache() Cache {
 // Helper methods
 
 func (m *Manager) detectLanguage(filePath string) *types.Language {
-	ext := filepath.Ext(filePath)
+	if lang := m.overrideLanguage(filePath); lang != nil {
+		return lang
+	}
+
+	if lang := m.languageForExtension(filepath.Ext(filePath)); lang != nil {
+		return lang
+	}
+
+	// Extensionless scripts and files of other languages embedded under
+	// unrelated extensions
+	return m.contentLanguage(filePath)
+}
+
+// languageForExtension returns the language of a file extension
+func (m *Manager) languageForExtension(ext string) *types.Language {
+	// Extensions mapped to WASM grammars take precedence over the built-in ones
+	if lang := m.detectWASMLanguage(ext); lang != nil {
+		return lang
+	}
+	return extensionLanguage(ext)
+}
 
+// extensionLanguage returns the built-in language of a file extension
+func extensionLanguage(ext string) *types.Language {
 	switch ext {
 	case ".ts", ".tsx":
 		return &types.Language{
@@ -449,10 +552,53 @@ func (m *Manager) detectLanguage(filePath string) *types.Language {
 			Parser:     "astro-template", // Framework-specific handling
 			Enabled:    true,
 		}
-	case ".cpp", ".cxx", ".cc", ".c++":
+	case ".sh", ".bash", ".zsh":
+		return &types.Language{
+			Name:       "shell",
+			Extensions: []string{".sh", ".bash", ".zsh"},
+			Parser:     "shell-regex",
+			Enabled:    true,
+		}
+	case ".ipynb":
+		return &types.Language{
+			Name:       "jupyter",
+			Extensions: []string{".ipynb"},
+			Parser:     "notebook", // Code cells are parsed with the kernel language's grammar
+			Enabled:    true,
+		}
+	case ".j2", ".jinja", ".jinja2", ".njk", ".twig":
+		return &types.Language{
+			Name:       LanguageJinja,
+			Extensions: []string{".j2", ".jinja", ".jinja2", ".njk", ".twig"},
+			Parser:     "template-regex",
+			Enabled:    true,
+		}
+	case ".erb":
+		return &types.Language{
+			Name:       LanguageERB,
+			Extensions: []string{".erb"},
+			Parser:     "template-regex",
+			Enabled:    true,
+		}
+	case ".hbs", ".handlebars", ".mustache":
+		return &types.Language{
+			Name:       LanguageHandlebars,
+			Extensions: []string{".hbs", ".handlebars", ".mustache"},
+			Parser:     "template-regex",
+			Enabled:    true,
+		}
+	case ".tmpl", ".gotmpl", ".gohtml":
+		return &types.Language{
+			Name:       LanguageGoTemplate,
+			Extensions: []string{".tmpl", ".gotmpl", ".gohtml"},
+			Parser:     "template-regex",
+			Enabled:    true,
+		}
+	case ".cpp", ".cxx", ".cc", ".c++", ".c":
+		// C is parsed with the C++ grammar so C headers and sources share one pipeline
 		return &types.Language{
 			Name:       "cpp",
-			Extensions: []string{".cpp", ".cxx", ".cc", ".c++"},
+			Extensions: []string{".cpp", ".cxx", ".cc", ".c++", ".c"},
 			Parser:     "tree-sitter-cpp",
 			Enabled:    true,
 		}
@@ -463,6 +609,21 @@ func (m *Manager) detectLanguage(filePath string) *types.Language {
 			Parser:     "tree-sitter-cpp",
 			Enabled:    true,
 		}
+	case ".mm", ".cu", ".cuh", ".metal":
+		// Objective-C++, CUDA and Metal are parsed as C++ dialects
+		return &types.Language{
+			Name:       "cpp",
+			Extensions: []string{".mm", ".cu", ".cuh", ".metal"},
+			Parser:     "tree-sitter-cpp",
+			Enabled:    true,
+		}
+	case ".cppm", ".ixx", ".mpp", ".cxxm", ".ccm", ".c++m":
+		return &types.Language{
+			Name:       "cpp",
+			Extensions: []string{".cppm", ".ixx", ".mpp", ".cxxm", ".ccm", ".c++m"},
+			Parser:     "tree-sitter-cpp",
+			Enabled:    true,
+		}
 	// case ".cs":
 	//	return &types.Language{
 	//		Name:       "csharp",
@@ -480,6 +641,15 @@ func (m *Manager) parseContent(content string, language types.Language, filePath
 }
 
 func (m *Manager) parseContentWithContext(ctx context.Context, content string, language types.Language, filePath ...string) (*types.AST, error) {
+	// Handle languages with a light parser when light parsing is on
+	if HasLightParser(language.Name) && m.lightParsing() {
+		filePathStr := ""
+		if len(filePath) > 0 {
+			filePathStr = filePath[0]
+		}
+		return parseLightContent(content, language.Name, filePathStr), nil
+	}
+
 	// Handle Dart specially with our custom parser
 	if language.Name == "dart" {
 		filePathStr := ""
@@ -498,6 +668,33 @@ func (m *Manager) parseContentWithContext(ctx context.Context, content string, l
 		return m.parseSwiftContentWithContext(ctx, content, filePathStr)
 	}
 
+	// Handle shell scripts with the line-based parser
+	if language.Name == "shell" {
+		filePathStr := ""
+		if len(filePath) > 0 {
+			filePathStr = filePath[0]
+		}
+		return m.parseShellContentWithContext(ctx, content, filePathStr)
+	}
+
+	// Handle templates with the tag-based parser
+	if IsTemplateLanguage(language.Name) {
+		filePathStr := ""
+		if len(filePath) > 0 {
+			filePathStr = filePath[0]
+		}
+		return m.parseTemplateContentWithContext(ctx, content, filePathStr, language.Name)
+	}
+
+	// Handle Jupyter notebooks by parsing their code cells
+	if language.Name == "jupyter" {
+		filePathStr := ""
+		if len(filePath) > 0 {
+			filePathStr = filePath[0]
+		}
+		return m.parseNotebookContentWithContext(ctx, content, filePathStr)
+	}
+
 	// Handle C++ specially with enhanced parser
 	if language.Name == "cpp" || language.Name == "c++" {
 		filePathStr := ""
@@ -662,19 +859,29 @@ func (m *Manager) extractSymbolsRecursive(node *types.ASTNode, filePath, languag
 	}
 }
 
-func (m *Manager) extractSymbolsRecursiveWithContent(node *types.ASTNode, filePath, language, content string, symbols *[]*types.Symbol) {
+// extractSymbolsRecursiveWithContent collects the symbols of node and its
+// children. scope names the innermost class, interface or enum around node.
+func (m *Manager) extractSymbolsRecursiveWithContent(node *types.ASTNode, filePath, language, content, scope string, symbols *[]*types.Symbol) {
 	if node == nil {
 		return
 	}
 
 	// Check if this node represents a symbol
 	if symbol := m.nodeToSymbolWithContent(node, filePath, language, content); symbol != nil {
+		setSubtype(symbol, node)
+		// C and C++ symbols already carry their namespaces and class
+		if scope != "" && symbol.MetadataString(MetadataScope) == "" {
+			symbol.SetMetadata(MetadataScope, scope)
+		}
 		*symbols = append(*symbols, symbol)
+		if scopeTypes[symbol.Type] {
+			scope = symbol.Name
+		}
 	}
 
 	// Recursively extract from children
 	for _, child := range node.Children {
-		m.extractSymbolsRecursiveWithContent(child, filePath, language, content, symbols)
+		m.extractSymbolsRecursiveWithContent(child, filePath, language, content, scope, symbols)
 	}
 }
 
@@ -702,6 +909,10 @@ func (m *Manager) nodeToSymbolWithContent(node *types.ASTNode, filePath, languag
 		return m.nodeToSymbolRust(node, filePath, language)
 	case "swift":
 		return m.nodeToSymbolSwift(node, filePath, language)
+	case "shell":
+		return m.nodeToSymbolShell(node, filePath, language)
+	case LanguageJinja, LanguageERB, LanguageHandlebars, LanguageGoTemplate:
+		return m.nodeToSymbolTemplate(node, filePath, language)
 	case "cpp", "c++":
 		// Use dedicated C++ parser with context tracking
 		if m.cppParser != nil {
@@ -714,6 +925,9 @@ func (m *Manager) nodeToSymbolWithContent(node *types.ASTNode, filePath, languag
 	// case "csharp":
 	//	return m.nodeToSymbolCSharp(node, filePath, language)
 	default:
+		if m.isWASMLanguage(language) {
+			return m.nodeToSymbolGeneric(node, filePath, language)
+		}
 		// Default JavaScript/TypeScript handling
 		return m.nodeToSymbolJS(node, filePath, language)
 	}
@@ -858,13 +1072,54 @@ func (m *Manager) nodeToSymbolPython(node *types.ASTNode, filePath, language str
 	}
 }
 
+// extractDeclaratorName returns the name of the first variable declared by a
+// field declaration such as "static final String NAME = ...", whose first
+// identifier child is the type
+func (m *Manager) extractDeclaratorName(node *types.ASTNode) string {
+	for _, child := range node.Children {
+		if child.Type == "variable_declarator" {
+			return m.extractSymbolName(child)
+		}
+	}
+	return m.extractSymbolName(node)
+}
+
+// extractJavaMethodName returns the name of a method, which follows its
+// return type; a return type such as "Config" is also a type_identifier
+func (m *Manager) extractJavaMethodName(node *types.ASTNode) string {
+	for _, child := range node.Children {
+		if child.Type == "identifier" {
+			return strings.TrimSpace(child.Value)
+		}
+	}
+	return m.extractSymbolName(node)
+}
+
+// hasModifiers reports whether a Java declaration carries all of the given modifiers
+func hasModifiers(node *types.ASTNode, modifiers ...string) bool {
+	present := make(map[string]bool)
+	for _, child := range node.Children {
+		if child.Type == "modifiers" {
+			for _, modifier := range child.Children {
+				present[modifier.Type] = true
+			}
+		}
+	}
+	for _, modifier := range modifiers {
+		if !present[modifier] {
+			return false
+		}
+	}
+	return true
+}
+
 // nodeToSymbolJava extracts symbols for Java language
 func (m *Manager) nodeToSymbolJava(node *types.ASTNode, filePath, language string) *types.Symbol {
 	switch node.Type {
 	case "method_declaration":
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("method-%s-%d", filePath, node.Location.Line)),
-			Name:         m.extractSymbolName(node),
+			Name:         m.extractJavaMethodName(node),
 			Type:         types.SymbolTypeMethod,
 			Location:     convertLocation(node.Location),
 			Signature:    m.extractFunctionSignature(node),
@@ -872,7 +1127,7 @@ func (m *Manager) nodeToSymbolJava(node *types.ASTNode, filePath, language strin
 			Hash:         calculateHash(node.Value),
 			LastModified: time.Now(),
 		}
-	case "class_declaration":
+	case "class_declaration", "record_declaration":
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("class-%s-%d", filePath, node.Location.Line)),
 			Name:         m.extractSymbolName(node),
@@ -892,11 +1147,25 @@ func (m *Manager) nodeToSymbolJava(node *types.ASTNode, filePath, language strin
 			Hash:         calculateHash(node.Value),
 			LastModified: time.Now(),
 		}
+	case "enum_declaration":
+		return &types.Symbol{
+			Id:           types.SymbolId(fmt.Sprintf("enum-%s-%d", filePath, node.Location.Line)),
+			Name:         m.extractSymbolName(node),
+			Type:         types.SymbolTypeEnum,
+			Location:     convertLocation(node.Location),
+			Language:     language,
+			Hash:         calculateHash(node.Value),
+			LastModified: time.Now(),
+		}
 	case "field_declaration":
+		fieldType := types.SymbolTypeVariable
+		if hasModifiers(node, "static", "final") {
+			fieldType = types.SymbolTypeConstant
+		}
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("field-%s-%d", filePath, node.Location.Line)),
-			Name:         m.extractSymbolName(node),
-			Type:         types.SymbolTypeVariable,
+			Name:         m.extractDeclaratorName(node),
+			Type:         fieldType,
 			Location:     convertLocation(node.Location),
 			Language:     language,
 			Hash:         calculateHash(node.Value),
@@ -921,7 +1190,7 @@ func (m *Manager) nodeToSymbolJava(node *types.ASTNode, filePath, language strin
 func (m *Manager) nodeToSymbolGo(node *types.ASTNode, filePath, language string) *types.Symbol {
 	switch node.Type {
 	case "function_declaration":
-		return &types.Symbol{
+		symbol := &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("func-%s-%d", filePath, node.Location.Line)),
 			Name:         m.extractSymbolName(node),
 			Type:         types.SymbolTypeFunction,
@@ -931,8 +1200,10 @@ func (m *Manager) nodeToSymbolGo(node *types.ASTNode, filePath, language string)
 			Hash:         calculateHash(node.Value),
 			LastModified: time.Now(),
 		}
+		recordGoTypeParameters(symbol, node)
+		return symbol
 	case "method_declaration":
-		return &types.Symbol{
+		symbol := &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("method-%s-%d", filePath, node.Location.Line)),
 			Name:         m.extractSymbolName(node),
 			Type:         types.SymbolTypeMethod,
@@ -942,8 +1213,11 @@ func (m *Manager) nodeToSymbolGo(node *types.ASTNode, filePath, language string)
 			Hash:         calculateHash(node.Value),
 			LastModified: time.Now(),
 		}
-	case "type_declaration":
-		return &types.Symbol{
+		recordGoMethod(symbol, node)
+		return symbol
+	case "type_spec", "type_alias":
+		// One symbol per type, so grouped type ( ... ) declarations yield all of them
+		symbol := &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("type-%s-%d", filePath, node.Location.Line)),
 			Name:         m.extractSymbolName(node),
 			Type:         types.SymbolTypeType,
@@ -952,6 +1226,18 @@ func (m *Manager) nodeToSymbolGo(node *types.ASTNode, filePath, language string)
 			Hash:         calculateHash(node.Value),
 			LastModified: time.Now(),
 		}
+		recordGoType(symbol, node)
+		return symbol
+	case "const_spec":
+		return &types.Symbol{
+			Id:           types.SymbolId(fmt.Sprintf("const-%s-%d", filePath, node.Location.Line)),
+			Name:         m.extractSymbolName(node),
+			Type:         types.SymbolTypeConstant,
+			Location:     convertLocation(node.Location),
+			Language:     language,
+			Hash:         calculateHash(node.Value),
+			LastModified: time.Now(),
+		}
 	case "var_declaration":
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("var-%s-%d", filePath, node.Location.Line)),
@@ -1021,6 +1307,16 @@ func (m *Manager) nodeToSymbolRust(node *types.ASTNode, filePath, language strin
 			Hash:         calculateHash(node.Value),
 			LastModified: time.Now(),
 		}
+	case "const_item", "static_item":
+		return &types.Symbol{
+			Id:           types.SymbolId(fmt.Sprintf("const-%s-%d", filePath, node.Location.Line)),
+			Name:         m.extractSymbolName(node),
+			Type:         types.SymbolTypeConstant,
+			Location:     convertLocation(node.Location),
+			Language:     language,
+			Hash:         calculateHash(node.Value),
+			LastModified: time.Now(),
+		}
 	case "trait_item":
 		return &types.Symbol{
 			Id:           types.SymbolId(fmt.Sprintf("trait-%s-%d", filePath, node.Location.Line)),
@@ -1344,24 +1640,45 @@ func (m *Manager) nodeToSymbolCSharp(node *types.ASTNode, filePath, language str
 	}
 }
 
-func (m *Manager) extractImportsRecursive(node *types.ASTNode, imports *[]*types.Import) {
+// extractImportsRecursive collects imports below node. ancestors holds the
+// path from the root, used to tell where runtime imports are loaded.
+func (m *Manager) extractImportsRecursive(node *types.ASTNode, ancestors []*types.ASTNode, imports *[]*types.Import) {
 	if node == nil {
 		return
 	}
 
 	// Check if this node represents an import
 	if imp := m.nodeToImport(node); imp != nil {
+		if imp.Kind == types.ImportKindDynamic || imp.Kind == types.ImportKindRequire {
+			markLazyImport(imp, ancestors)
+		}
 		*imports = append(*imports, imp)
 	}
 
 	// Recursively extract from children
+	ancestors = append(ancestors, node)
 	for _, child := range node.Children {
-		m.extractImportsRecursive(child, imports)
+		m.extractImportsRecursive(child, ancestors, imports)
 	}
 }
 
 func (m *Manager) nodeToImport(node *types.ASTNode) *types.Import {
-	if node.Type != "import_statement" && node.Type != "import_declaration" {
+	switch node.Type {
+	case "preproc_include":
+		return m.nodeToInclude(node)
+	case "preproc_call":
+		return nodeToObjcImport(node)
+	case "export_statement":
+		return m.nodeToReexport(node)
+	case "call_expression":
+		return m.nodeToDynamicImport(node)
+	case "import_spec", "import_from_statement":
+		return nodeToQualifiedImport(node)
+	case "import_statement", "import_declaration":
+		if isQualifiedImport(node) {
+			return nodeToQualifiedImport(node)
+		}
+	default:
 		return nil
 	}
 
@@ -1370,10 +1687,14 @@ func (m *Manager) nodeToImport(node *types.ASTNode) *types.Import {
 	}
 
 	// Extract import path and specifiers from children
+	hasClause := false
 	for _, child := range node.Children {
 		switch child.Type {
 		case "string", "string_literal":
 			imp.Path = strings.Trim(child.Value, `"'`)
+		case "import_clause":
+			hasClause = true
+			m.readImportClause(child, imp)
 		case "import_specifier":
 			if name := m.extractSymbolName(child); name != "unknown" {
 				imp.Specifiers = append(imp.Specifiers, name)
@@ -1391,9 +1712,218 @@ func (m *Manager) nodeToImport(node *types.ASTNode) *types.Import {
 		}
 	}
 
+	if node.Type == "import_statement" {
+		imp.Kind = importKind(node, imp, hasClause)
+	}
 	return imp
 }
 
+// readImportClause collects the default, named and namespace bindings of a
+// JavaScript/TypeScript import clause. Named imports record the imported name,
+// not the local alias.
+func (m *Manager) readImportClause(clause *types.ASTNode, imp *types.Import) {
+	for _, child := range clause.Children {
+		switch child.Type {
+		case "identifier":
+			imp.IsDefault = true
+			imp.Specifiers = append(imp.Specifiers, strings.TrimSpace(child.Value))
+		case "named_imports":
+			specs := child.Children
+			for i, spec := range specs {
+				if spec.Type != "import_specifier" {
+					continue
+				}
+				name := m.extractSymbolName(spec)
+				// The JavaScript grammar reads "{ type T }" as a specifier "type"
+				// followed by an error node holding T
+				if name == "type" && i+1 < len(specs) && specs[i+1].Type == "ERROR" {
+					name = m.extractSymbolName(specs[i+1])
+				}
+				if name != "unknown" {
+					imp.Specifiers = append(imp.Specifiers, name)
+				}
+			}
+		case "namespace_import":
+			for _, part := range child.Children {
+				if part.Type == "identifier" {
+					imp.Alias = strings.TrimSpace(part.Value)
+				}
+			}
+		}
+	}
+}
+
+// typeOnlyImport matches "import type { T }", "import type * as t" and
+// "import type T from", but not a default import named "type"
+var typeOnlyImport = regexp.MustCompile(`^import\s+type\s+(?:\{|\*|[A-Za-z_$][\w$]*\s+from\b)`)
+
+func importKind(node *types.ASTNode, imp *types.Import, hasClause bool) types.ImportKind {
+	switch {
+	case typeOnlyImport.MatchString(strings.TrimSpace(node.Value)):
+		return types.ImportKindTypeOnly
+	case imp.Alias != "":
+		return types.ImportKindNamespace
+	case imp.IsDefault:
+		return types.ImportKindDefault
+	case hasClause:
+		return types.ImportKindNamed
+	default:
+		return types.ImportKindSideEffect
+	}
+}
+
+// nodeToReexport converts "export { a } from 'm'" and "export * from 'm'"
+// into an import, since the file depends on the re-exported module
+func (m *Manager) nodeToReexport(node *types.ASTNode) *types.Import {
+	var imp *types.Import
+	for _, child := range node.Children {
+		if child.Type == "string" {
+			imp = &types.Import{
+				Path:     strings.Trim(child.Value, `"'`),
+				Kind:     types.ImportKindReexport,
+				Location: node.Location,
+			}
+		}
+	}
+	if imp == nil {
+		return nil
+	}
+	for _, child := range node.Children {
+		if child.Type != "export_clause" {
+			continue
+		}
+		for _, spec := range child.Children {
+			if spec.Type == "export_specifier" {
+				if name := m.extractSymbolName(spec); name != "unknown" {
+					imp.Specifiers = append(imp.Specifiers, name)
+				}
+			}
+		}
+	}
+	return imp
+}
+
+// nodeToDynamicImport converts an import("m") or require("m") call with a
+// literal path
+func (m *Manager) nodeToDynamicImport(node *types.ASTNode) *types.Import {
+	if len(node.Children) < 2 || node.Children[1].Type != "arguments" {
+		return nil
+	}
+	var kind types.ImportKind
+	switch callee := node.Children[0]; {
+	case callee.Type == "import":
+		kind = types.ImportKindDynamic
+	case callee.Type == "identifier" && callee.Value == "require":
+		kind = types.ImportKindRequire
+	default:
+		return nil
+	}
+	for _, arg := range node.Children[1].Children {
+		if arg.Type == "string" {
+			return &types.Import{
+				Path:     strings.Trim(arg.Value, `"'`),
+				Kind:     kind,
+				Location: node.Location,
+			}
+		}
+	}
+	return nil
+}
+
+// lazyLoaders maps functions that wrap import() for code splitting to the
+// loader name recorded on the import
+var lazyLoaders = map[string]string{
+	"React.lazy":           "React.lazy",
+	"lazy":                 "React.lazy",
+	"dynamic":              "next/dynamic",
+	"loadable":             "loadable",
+	"defineAsyncComponent": "defineAsyncComponent",
+}
+
+// routeLazyKeys are route config properties whose import() is loaded when the
+// route is visited (React Router, Vue Router, Angular)
+var routeLazyKeys = map[string]bool{
+	"component": true, "lazy": true, "loadChildren": true, "loadComponent": true, "getComponent": true,
+}
+
+// markLazyImport sets Lazy and Loader on an import() or require() call.
+// import() always loads at runtime; require() does when it runs inside a
+// function rather than at module load. The loader is the nearest enclosing
+// code-splitting wrapper or route property within the same statement.
+func markLazyImport(imp *types.Import, ancestors []*types.ASTNode) {
+	imp.Lazy = imp.Kind == types.ImportKindDynamic
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		node := ancestors[i]
+		switch node.Type {
+		case "statement_block":
+			if imp.Kind == types.ImportKindRequire {
+				imp.Lazy = true
+			}
+			return
+		case "program":
+			return
+		case "call_expression":
+			if len(node.Children) > 0 && imp.Loader == "" {
+				if loader, ok := lazyLoaders[strings.TrimSpace(node.Children[0].Value)]; ok {
+					imp.Loader = loader
+				}
+			}
+		case "pair":
+			if len(node.Children) > 0 && imp.Loader == "" && routeLazyKeys[strings.Trim(node.Children[0].Value, `"'`)] {
+				imp.Loader = "route"
+			}
+		case "arrow_function", "function", "function_expression", "method_definition":
+			if imp.Kind == types.ImportKindRequire {
+				imp.Lazy = true
+			}
+		}
+	}
+}
+
+// nodeToInclude converts a C/C++ #include directive into an import
+func (m *Manager) nodeToInclude(node *types.ASTNode) *types.Import {
+	for _, child := range node.Children {
+		switch child.Type {
+		case "string_literal":
+			return &types.Import{
+				Path:     strings.Trim(strings.TrimSpace(child.Value), `"`),
+				Location: node.Location,
+			}
+		case "system_lib_string":
+			return &types.Import{
+				Path:     strings.Trim(strings.TrimSpace(child.Value), "<>"),
+				IsSystem: true,
+				Location: node.Location,
+			}
+		}
+	}
+	return nil
+}
+
+// nodeToObjcImport converts an Objective-C #import directive, which the C++
+// grammar reads as an unknown preprocessor directive, into an import
+func nodeToObjcImport(node *types.ASTNode) *types.Import {
+	var directive, arg string
+	for _, child := range node.Children {
+		switch child.Type {
+		case "preproc_directive":
+			directive = strings.TrimSpace(child.Value)
+		case "preproc_arg":
+			arg = strings.TrimSpace(child.Value)
+		}
+	}
+	if directive != "#import" || len(arg) < 2 {
+		return nil
+	}
+	switch {
+	case arg[0] == '<' && strings.HasSuffix(arg, ">"):
+		return &types.Import{Path: arg[1 : len(arg)-1], IsSystem: true, Location: node.Location}
+	case arg[0] == '"' && strings.HasSuffix(arg, `"`):
+		return &types.Import{Path: arg[1 : len(arg)-1], Location: node.Location}
+	}
+	return nil
+}
+
 func (m *Manager) getExtensionsForLanguage(name string) []string {
 	switch name {
 	case "typescript":
@@ -1438,8 +1968,9 @@ func (m *Manager) extractSymbolName(node *types.ASTNode) string {
 			return strings.TrimSpace(child.Value)
 		}
 
-		// For some nodes, the name might be nested deeper
-		if child.Type == "property_identifier" || child.Type == "name" {
+		// For some nodes, the name might be nested deeper; Go methods name
+		// themselves with a field_identifier after the receiver
+		if child.Type == "property_identifier" || child.Type == "name" || child.Type == "field_identifier" {
 			return strings.TrimSpace(child.Value)
 		}
 		
@@ -2083,7 +2614,7 @@ func (m *Manager) Close() error {
 func (m *Manager) GetParser(language string) (Parser, error) {
 	// For now, return self as all parsing goes through Manager
 	// In a more sophisticated implementation, we might return language-specific parsers
-	if m.detectLanguage(fmt.Sprintf("test.%s", getExtensionForLanguage(language))) != nil {
+	if m.languageForExtension("."+getExtensionForLanguage(language)) != nil {
 		return m, nil
 	}
 	return nil, fmt.Errorf("unsupported language: %s", language)
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for symbols extracted from Jupyter notebooks
+const (
+	MetadataNotebookCell = "notebook_cell" // 0-based index of the cell among all notebook cells
+)
+
+// notebookCellsKey stores the cell line map on the AST root so symbol extraction can remap locations
+const notebookCellsKey = "notebook_cells"
+
+// notebookKernelExtensions maps kernel languages to the extension of the grammar used for their cells
+var notebookKernelExtensions = map[string]string{
+	"python":     ".py",
+	"python3":    ".py",
+	"javascript": ".js",
+	"typescript": ".ts",
+	"java":       ".java",
+	"go":         ".go",
+	"rust":       ".rs",
+	"c++":        ".cpp",
+	"c++11":      ".cpp",
+	"c++14":      ".cpp",
+	"c++17":      ".cpp",
+	"c++20":      ".cpp",
+}
+
+// notebook is the subset of the nbformat v4 schema needed to extract code
+type notebook struct {
+	Cells    []notebookCell `json:"cells"`
+	Metadata struct {
+		Kernelspec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type notebookCell struct {
+	CellType string          `json:"cell_type"`
+	Source   json.RawMessage `json:"source"` // Either a string or a list of lines
+}
+
+// notebookCellSpan records where a code cell landed in the concatenated source
+type notebookCellSpan struct {
+	Index     int // Position of the cell in the notebook
+	StartLine int // 1-based first line in the concatenated source
+	EndLine   int
+}
+
+// parseNotebookContentWithContext concatenates the code cells of a notebook and parses
+// them with the kernel language's grammar. Cell boundaries are kept on the AST root.
+func (m *Manager) parseNotebookContentWithContext(ctx context.Context, content, filePath string) (*types.AST, error) {
+	var nb notebook
+	if err := json.Unmarshal([]byte(content), &nb); err != nil {
+		return nil, NewParseError("parseNotebook", filePath, "jupyter", fmt.Errorf("invalid notebook JSON: %w", err))
+	}
+
+	kernel := strings.ToLower(nb.Metadata.Kernelspec.Language)
+	if kernel == "" {
+		kernel = strings.ToLower(nb.Metadata.LanguageInfo.Name)
+	}
+	if kernel == "" {
+		kernel = "python"
+	}
+
+	var source strings.Builder
+	var spans []notebookCellSpan
+	line := 1
+	for i, cell := range nb.Cells {
+		if cell.CellType != "code" {
+			continue
+		}
+		code := notebookCellSource(cell.Source)
+		if strings.TrimSpace(code) == "" {
+			continue
+		}
+		lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+		for j, l := range lines {
+			// IPython magics and shell escapes are not valid source; blank them to keep line numbers
+			if trimmed := strings.TrimSpace(l); strings.HasPrefix(trimmed, "%") || strings.HasPrefix(trimmed, "!") {
+				lines[j] = ""
+			}
+		}
+		spans = append(spans, notebookCellSpan{Index: i, StartLine: line, EndLine: line + len(lines) - 1})
+		source.WriteString(strings.Join(lines, "\n"))
+		source.WriteString("\n")
+		line += len(lines)
+	}
+
+	ext, supported := notebookKernelExtensions[kernel]
+	code := source.String()
+	if !supported || strings.TrimSpace(code) == "" {
+		// Keep the notebook visible in the graph even when its cells cannot be parsed
+		return &types.AST{
+			Language: "jupyter",
+			Content:  code,
+			FilePath: filePath,
+			Hash:     calculateHash(content),
+			Version:  "1.0",
+			ParsedAt: time.Now(),
+			Root: &types.ASTNode{
+				Id:       "root",
+				Type:     "notebook",
+				Location: types.FileLocation{FilePath: filePath, Line: 1, Column: 1},
+				Metadata: map[string]interface{}{"kernel": kernel},
+			},
+		}, nil
+	}
+
+	lang := m.languageForExtension(ext)
+	ast, err := m.parseContentWithContext(ctx, code, *lang, filePath)
+	if err != nil {
+		return nil, err
+	}
+	if ast.Root == nil {
+		return nil, NewParseError("parseNotebook", filePath, "jupyter", fmt.Errorf("no syntax tree for %s cells", kernel))
+	}
+	if ast.Root.Metadata == nil {
+		ast.Root.Metadata = make(map[string]interface{})
+	}
+	ast.Root.Metadata[notebookCellsKey] = spans
+	ast.Root.Metadata["kernel"] = kernel
+	return ast, nil
+}
+
+// notebookCellSource joins a cell's source, which nbformat allows as a string or list of strings
+func notebookCellSource(raw json.RawMessage) string {
+	var lines []string
+	if err := json.Unmarshal(raw, &lines); err == nil {
+		return strings.Join(lines, "")
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return ""
+}
+
+// applyNotebookCells rewrites symbol locations to be relative to their notebook cell
+func applyNotebookCells(symbols []*types.Symbol, root *types.ASTNode) {
+	if root == nil || root.Metadata == nil {
+		return
+	}
+	spans, ok := root.Metadata[notebookCellsKey].([]notebookCellSpan)
+	if !ok {
+		return
+	}
+
+	for _, symbol := range symbols {
+		for _, span := range spans {
+			if symbol.Location.StartLine < span.StartLine || symbol.Location.StartLine > span.EndLine {
+				continue
+			}
+			offset := span.StartLine - 1
+			symbol.Location.StartLine -= offset
+			symbol.Location.EndLine -= offset
+			symbol.SetMetadata(MetadataNotebookCell, span.Index)
+			break
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotebookSymbols(t *testing.T) {
+	manager := NewManager()
+
+	notebookJSON := `{
+  "cells": [
+    {"cell_type": "markdown", "source": ["# Analysis\n"]},
+    {"cell_type": "code", "source": ["import pandas as pd\n", "%matplotlib inline\n"]},
+    {"cell_type": "code", "source": "def load(path):\n    return pd.read_csv(path)\n"},
+    {"cell_type": "code", "source": []},
+    {"cell_type": "code", "source": ["!pip install numpy\n", "\n", "class Model:\n", "    def fit(self, data):\n", "        pass\n"]}
+  ],
+  "metadata": {"kernelspec": {"language": "python", "name": "python3"}},
+  "nbformat": 4,
+  "nbformat_minor": 5
+}`
+
+	lang := manager.detectLanguage("analysis.ipynb")
+	require.NotNil(t, lang)
+	assert.Equal(t, "jupyter", lang.Name)
+
+	ast, err := manager.parseContent(notebookJSON, *lang, "analysis.ipynb")
+	require.NoError(t, err)
+	assert.Equal(t, "python", ast.Language)
+	assert.NotContains(t, ast.Content, "%matplotlib")
+	assert.NotContains(t, ast.Content, "!pip")
+
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+
+	byName := make(map[string]*types.Symbol)
+	for _, symbol := range symbols {
+		byName[symbol.Name] = symbol
+	}
+
+	load := byName["load"]
+	require.NotNil(t, load, "function in code cell should be extracted")
+	cell, ok := load.MetadataInt(MetadataNotebookCell)
+	require.True(t, ok)
+	assert.Equal(t, 2, cell)
+	assert.Equal(t, 1, load.Location.StartLine)
+
+	model := byName["Model"]
+	require.NotNil(t, model)
+	cell, ok = model.MetadataInt(MetadataNotebookCell)
+	require.True(t, ok)
+	assert.Equal(t, 4, cell)
+	assert.Equal(t, 3, model.Location.StartLine)
+}
+
+func TestNotebookUnsupportedKernel(t *testing.T) {
+	manager := NewManager()
+
+	notebookJSON := `{
+  "cells": [{"cell_type": "code", "source": "x <- c(1, 2, 3)\n"}],
+  "metadata": {"kernelspec": {"language": "R"}}
+}`
+
+	ast, err := manager.parseContent(notebookJSON, *manager.detectLanguage("stats.ipynb"), "stats.ipynb")
+	require.NoError(t, err, "notebooks in unsupported kernels should still be indexed")
+	assert.Equal(t, "jupyter", ast.Language)
+
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	assert.Empty(t, symbols)
+}
+
+func TestNotebookInvalidJSON(t *testing.T) {
+	manager := NewManager()
+
+	_, err := manager.parseContent("not a notebook", *manager.detectLanguage("broken.ipynb"), "broken.ipynb")
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for Python definitions
+const (
+	MetadataDecorators = "decorators" // Decorator expressions without "@", such as "dataclass" or "app.get(\"/users\")"
+	MetadataAsync      = "async"      // Set on async def functions and methods
+	MetadataModel      = "model"      // Kind of data model a Python class declares: dataclass, pydantic, attrs or django
+	MetadataFields     = "fields"     // Fields of a Python model, such as "id: int" or "title: CharField"
+)
+
+// Kinds of Python data models
+const (
+	PythonModelDataclass = "dataclass"
+	PythonModelPydantic  = "pydantic"
+	PythonModelAttrs     = "attrs"
+	PythonModelDjango    = "django"
+)
+
+// pythonModelBases are the base classes that make a class a model
+var pythonModelBases = map[string]string{
+	"BaseModel":              PythonModelPydantic,
+	"pydantic.BaseModel":     PythonModelPydantic,
+	"BaseSettings":           PythonModelPydantic,
+	"pydantic.BaseSettings":  PythonModelPydantic,
+	"SQLModel":               PythonModelPydantic,
+	"models.Model":           PythonModelDjango,
+	"django.db.models.Model": PythonModelDjango,
+}
+
+// pythonModelDecorators are the class decorators that make a class a model
+var pythonModelDecorators = map[string]string{
+	"dataclass":                      PythonModelDataclass,
+	"dataclasses.dataclass":          PythonModelDataclass,
+	"pydantic.dataclasses.dataclass": PythonModelDataclass,
+	"attr.s":                         PythonModelAttrs,
+	"attr.attrs":                     PythonModelAttrs,
+	"attrs.define":                   PythonModelAttrs,
+	"attrs.frozen":                   PythonModelAttrs,
+	"define":                         PythonModelAttrs,
+	"frozen":                         PythonModelAttrs,
+}
+
+// djangoRelationFields are the Django model fields not named "...Field"
+var djangoRelationFields = map[string]bool{
+	"ForeignKey": true, "ManyToManyField": true, "OneToOneField": true,
+}
+
+// attachPythonDefinitions records what the declaration nodes of Python
+// functions and classes say beyond their names: their decorators, whether a
+// function is async and whether it is a method, and the kind and fields of
+// data models. Models are dataclasses, attrs classes, pydantic models and
+// Django models, including classes extending a model of the same file.
+func attachPythonDefinitions(symbols []*types.Symbol, root *types.ASTNode) {
+	selected := func(symbol *types.Symbol) bool {
+		return symbol.Type == types.SymbolTypeFunction || symbol.Type == types.SymbolTypeClass
+	}
+	models := make(map[string]string)
+	forEachSymbolNode(symbols, root, selected, func(symbol *types.Symbol, node *types.ASTNode, ancestors []*types.ASTNode) {
+		var decorators []string
+		parent := len(ancestors) - 1
+		if parent >= 0 && ancestors[parent].Type == "decorated_definition" {
+			for _, child := range ancestors[parent].Children {
+				if child.Type == "decorator" {
+					text := strings.TrimPrefix(strings.TrimSpace(child.Value), "@")
+					decorators = append(decorators, strings.Join(strings.Fields(text), " "))
+				}
+			}
+			parent--
+		}
+		if len(decorators) > 0 {
+			symbol.SetMetadata(MetadataDecorators, decorators)
+		}
+
+		switch node.Type {
+		case "function_definition":
+			if parent >= 1 && ancestors[parent].Type == "block" && ancestors[parent-1].Type == "class_definition" {
+				symbol.Type = types.SymbolTypeMethod
+			}
+			for _, child := range node.Children {
+				if child.Type == "async" {
+					symbol.SetMetadata(MetadataAsync, true)
+				}
+			}
+		case "class_definition":
+			model := pythonModelKind(symbol, decorators, models)
+			if model == "" {
+				return
+			}
+			models[symbol.Name] = model
+			symbol.SetMetadata(MetadataModel, model)
+			if fields := pythonModelFields(node, model); len(fields) > 0 {
+				symbol.SetMetadata(MetadataFields, fields)
+			}
+		}
+	})
+}
+
+// pythonModelKind returns the kind of model a class declares from its
+// decorators and base classes, or "" for other classes. Classes are visited
+// in source order, so models holds the earlier models of the file.
+func pythonModelKind(symbol *types.Symbol, decorators []string, models map[string]string) string {
+	for _, decorator := range decorators {
+		if idx := strings.Index(decorator, "("); idx >= 0 {
+			decorator = decorator[:idx]
+		}
+		if kind, ok := pythonModelDecorators[decorator]; ok {
+			return kind
+		}
+	}
+	for _, base := range symbol.MetadataStrings(MetadataExtends) {
+		if kind, ok := pythonModelBases[base]; ok {
+			return kind
+		}
+		if kind, ok := models[base]; ok {
+			return kind
+		}
+	}
+	return ""
+}
+
+// pythonModelFields lists the fields declared in a model's class body:
+// annotated attributes such as "id: int", and for Django models attributes
+// assigned a field, such as "title = models.CharField()" giving
+// "title: CharField". ClassVar attributes are not fields.
+func pythonModelFields(class *types.ASTNode, model string) []string {
+	var fields []string
+	for _, block := range class.Children {
+		if block.Type != "block" {
+			continue
+		}
+		for _, statement := range block.Children {
+			if statement.Type != "expression_statement" || len(statement.Children) == 0 || statement.Children[0].Type != "assignment" {
+				continue
+			}
+			var name, annotation, field string
+			for _, child := range statement.Children[0].Children {
+				switch child.Type {
+				case "identifier":
+					if name == "" {
+						name = strings.TrimSpace(child.Value)
+					}
+				case "type":
+					annotation = strings.Join(strings.Fields(child.Value), " ")
+				case "call":
+					field = djangoFieldType(child)
+				}
+			}
+			switch {
+			case name == "" || strings.HasPrefix(annotation, "ClassVar"):
+			case annotation != "":
+				fields = append(fields, name+": "+annotation)
+			case model == PythonModelDjango && field != "":
+				fields = append(fields, name+": "+field)
+			}
+		}
+	}
+	return fields
+}
+
+// djangoFieldType returns the field class a call constructs, such as
+// "CharField" for models.CharField(max_length=10), or "" for other calls
+func djangoFieldType(call *types.ASTNode) string {
+	if len(call.Children) == 0 {
+		return ""
+	}
+	callee := strings.TrimSpace(call.Children[0].Value)
+	if idx := strings.LastIndex(callee, "."); idx >= 0 {
+		callee = callee[idx+1:]
+	}
+	if strings.HasSuffix(callee, "Field") || djangoRelationFields[callee] {
+		return callee
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPythonDefinitions(t *testing.T) {
+	content := "from dataclasses import dataclass\nfrom pydantic import BaseModel\nfrom django.db import models\n\n" +
+		"@dataclass(frozen=True)\nclass Point:\n    x: int\n    y: int = 0\n    origin: ClassVar[str] = \"o\"\n    label = \"p\"\n\n" +
+		"class Entity(BaseModel):\n    id: int\n\n" +
+		"class User(Entity, metaclass=Meta):\n    tags: list[str] = []\n\n" +
+		"class Post(models.Model):\n    title = models.CharField(max_length=10)\n    author = models.ForeignKey(User, on_delete=models.CASCADE)\n    count = compute()\n\n" +
+		"@app.get(\"/users/{id}\")\n@cache\nasync def get_user(id: int) -> User:\n    return None\n\n" +
+		"class Service:\n    @staticmethod\n    async def run():\n        pass\n\n    def stop(self):\n        pass\n"
+
+	manager := NewManager()
+	ast, err := manager.Parse(content, "x.py")
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	byName := make(map[string]*types.Symbol)
+	for _, symbol := range symbols {
+		if symbol.Type != types.SymbolTypeVariable {
+			byName[symbol.Name] = symbol
+		}
+	}
+
+	require.NotNil(t, byName["Point"])
+	assert.Equal(t, []string{"dataclass(frozen=True)"}, byName["Point"].Metadata[MetadataDecorators])
+	assert.Equal(t, PythonModelDataclass, byName["Point"].Metadata[MetadataModel])
+	assert.Equal(t, []string{"x: int", "y: int"}, byName["Point"].Metadata[MetadataFields], "class variables and unannotated attributes are not fields")
+	assert.Equal(t, PythonModelPydantic, byName["Entity"].Metadata[MetadataModel])
+	assert.Equal(t, PythonModelPydantic, byName["User"].Metadata[MetadataModel], "subclasses of a model of the same file are models")
+	assert.Equal(t, []string{"tags: list[str]"}, byName["User"].Metadata[MetadataFields])
+	assert.Equal(t, []string{"Entity"}, byName["User"].Metadata[MetadataExtends])
+	assert.Equal(t, PythonModelDjango, byName["Post"].Metadata[MetadataModel])
+	assert.Equal(t, []string{"title: CharField", "author: ForeignKey"}, byName["Post"].Metadata[MetadataFields])
+
+	getUser := byName["get_user"]
+	require.NotNil(t, getUser)
+	assert.Equal(t, types.SymbolTypeFunction, getUser.Type)
+	assert.Equal(t, []string{"app.get(\"/users/{id}\")", "cache"}, getUser.Metadata[MetadataDecorators])
+	assert.Equal(t, true, getUser.Metadata[MetadataAsync])
+
+	assert.Equal(t, types.SymbolTypeMethod, byName["run"].Type)
+	assert.Equal(t, true, byName["run"].Metadata[MetadataAsync])
+	assert.Equal(t, []string{"staticmethod"}, byName["run"].Metadata[MetadataDecorators])
+	assert.Equal(t, types.SymbolTypeMethod, byName["stop"].Type)
+	assert.Nil(t, byName["stop"].Metadata[MetadataAsync])
+	assert.Nil(t, byName["Service"].Metadata[MetadataModel])
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// isQualifiedImport reports whether an import node names a package or module
+// (Go, Java and Python) rather than a JavaScript module string
+func isQualifiedImport(node *types.ASTNode) bool {
+	for _, child := range node.Children {
+		switch child.Type {
+		case "import_spec", "import_spec_list", "scoped_identifier", "dotted_name", "aliased_import":
+			return true
+		}
+	}
+	return false
+}
+
+// nodeToQualifiedImport reads Go import specs, Java import declarations and
+// Python import statements. Go import declarations return nil: each of their
+// specs is an import of its own. Python's "from x import a, b" records a and b
+// as specifiers; relative imports keep their leading dots.
+func nodeToQualifiedImport(node *types.ASTNode) *types.Import {
+	imp := &types.Import{Location: node.Location}
+	switch node.Type {
+	case "import_declaration":
+		for _, child := range node.Children {
+			switch child.Type {
+			case "import_spec", "import_spec_list":
+				return nil // Go
+			case "scoped_identifier", "identifier":
+				imp.Path = child.Value
+			case "asterisk":
+				imp.Path += ".*"
+			}
+		}
+	case "import_spec":
+		for _, child := range node.Children {
+			switch child.Type {
+			case "interpreted_string_literal", "raw_string_literal":
+				imp.Path = strings.Trim(child.Value, "\"`")
+			case "package_identifier", "dot", "blank_identifier":
+				imp.Alias = child.Value
+			}
+		}
+	case "import_statement":
+		// import a.b as c; only the first module of "import a, b" is recorded
+		for _, child := range node.Children {
+			if path, alias := pythonImportName(child); path != "" {
+				imp.Path, imp.Alias = path, alias
+				break
+			}
+		}
+	case "import_from_statement":
+		afterImport := false
+		for _, child := range node.Children {
+			switch {
+			case child.Type == "import":
+				afterImport = true
+			case !afterImport && (child.Type == "dotted_name" || child.Type == "relative_import"):
+				imp.Path = child.Value
+			case afterImport && child.Type == "wildcard_import":
+				imp.Specifiers = append(imp.Specifiers, "*")
+			case afterImport:
+				if name, _ := pythonImportName(child); name != "" {
+					imp.Specifiers = append(imp.Specifiers, name)
+				}
+			}
+		}
+	}
+	if imp.Path == "" {
+		return nil
+	}
+	return imp
+}
+
+// pythonImportName returns the module or name of a dotted_name or
+// aliased_import node, with the alias
+func pythonImportName(node *types.ASTNode) (string, string) {
+	switch node.Type {
+	case "dotted_name":
+		return node.Value, ""
+	case "aliased_import":
+		var name, alias string
+		for _, child := range node.Children {
+			switch child.Type {
+			case "dotted_name":
+				name = child.Value
+			case "identifier":
+				alias = child.Value
+			}
+		}
+		return name, alias
+	}
+	return "", ""
+}
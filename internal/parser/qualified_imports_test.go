@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualifiedImports(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    []types.Import
+	}{
+		{
+			file:    "main.go",
+			content: "package main\n\nimport (\n\t\"fmt\"\n\tst \"example.com/shop/store\"\n)\n\nimport _ \"embed\"\n",
+			want: []types.Import{
+				{Path: "fmt"},
+				{Path: "example.com/shop/store", Alias: "st"},
+				{Path: "embed", Alias: "_"},
+			},
+		},
+		{
+			file:    "Main.java",
+			content: "package com.acme;\n\nimport com.acme.billing.Invoice;\nimport java.util.*;\n\nclass Main {}\n",
+			want: []types.Import{
+				{Path: "com.acme.billing.Invoice"},
+				{Path: "java.util.*"},
+			},
+		},
+		{
+			file:    "views.py",
+			content: "import os.path\nimport numpy as np\nfrom app.models import User, Order\nfrom ..core import settings as conf\nfrom . import *\n",
+			want: []types.Import{
+				{Path: "os.path"},
+				{Path: "numpy", Alias: "np"},
+				{Path: "app.models", Specifiers: []string{"User", "Order"}},
+				{Path: "..core", Specifiers: []string{"settings"}},
+				{Path: ".", Specifiers: []string{"*"}},
+			},
+		},
+	}
+
+	manager := NewManager()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ast, err := manager.Parse(tt.content, tt.file)
+			require.NoError(t, err)
+			imports, err := manager.ExtractImports(ast)
+			require.NoError(t, err)
+
+			got := make([]types.Import, len(imports))
+			for i, imp := range imports {
+				got[i] = types.Import{Path: imp.Path, Alias: imp.Alias, Specifiers: imp.Specifiers}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// MetadataShellCommands lists the commands and functions a shell function invokes
+const MetadataShellCommands = "shell_commands"
+
+var (
+	shellFunctionPattern = regexp.MustCompile(`^\s*(?:function\s+([A-Za-z_][\w:.-]*)\s*(?:\(\s*\))?|([A-Za-z_][\w:.-]*)\s*\(\s*\))\s*([{(])?`)
+	shellSourcePattern   = regexp.MustCompile(`^\s*(?:source|\.)\s+(.+?)\s*(?:;|&&|\|\||$)`)
+	shellHeredocPattern  = regexp.MustCompile(`<<-?\s*['"]?(\w+)['"]?`)
+	shellCaseLabel       = regexp.MustCompile(`^\s*\(?[\w*|"'.,@/-]+\)\s*`)
+	shellAssignment      = regexp.MustCompile(`^[A-Za-z_]\w*(\[[^\]]*\])?\+?=`)
+	shellCommandName     = regexp.MustCompile(`^[A-Za-z_][\w.:+-]*$`)
+	shellSegmentSplitter = regexp.MustCompile("\\|\\|?|&&|;|\\$\\(|`|\\(|\\)|\\{|\\}")
+	shellExpansion       = regexp.MustCompile(`\$\{[^}]*\}`)
+	shellSingleQuoted    = regexp.MustCompile(`'[^']*'`)
+	shellDoubleQuoted    = regexp.MustCompile(`"[^"]*"`)
+	shellSubstitution    = regexp.MustCompile(`\$\(([^)]*)\)`)
+
+	// shellDirPrefix matches leading "$(dirname "$0")/", "${BASH_SOURCE%/*}/" or "$SCRIPT_DIR/",
+	// which almost always point at the sourcing script's own directory
+	shellDirPrefix = regexp.MustCompile(`^"?\$(?:\([^)]*\)|\{[^}]*\}|\w+)"?/`)
+)
+
+// shellKeywords are reserved words and builtins that are not reported as invoked commands
+var shellKeywords = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "fi": true, "for": true, "in": true,
+	"while": true, "until": true, "do": true, "done": true, "case": true, "esac": true,
+	"function": true, "select": true, "return": true, "local": true, "declare": true,
+	"typeset": true, "export": true, "readonly": true, "unset": true, "shift": true,
+	"set": true, "break": true, "continue": true, "true": true, "false": true, "echo": true,
+	"printf": true, "read": true, "eval": true, "exit": true, "trap": true, "wait": true,
+	"cd": true, "test": true, "source": true, "let": true, "shopt": true, "getopts": true,
+	"pushd": true, "popd": true, "exec": true, "command": true, "builtin": true, "time": true,
+	"sudo": true, "env": true, "nohup": true,
+}
+
+// shellWrappers run the following word as the real command
+var shellWrappers = map[string]bool{
+	"sudo": true, "exec": true, "command": true, "builtin": true, "time": true, "env": true, "nohup": true,
+}
+
+// shellCommandPrefixes are control words that are directly followed by a command
+var shellCommandPrefixes = map[string]bool{
+	"if": true, "elif": true, "while": true, "until": true, "then": true, "do": true, "else": true, "!": true,
+}
+
+// parseShellContentWithContext extracts functions and sourced files from bash/zsh scripts
+func (m *Manager) parseShellContentWithContext(ctx context.Context, content, filePath string) (*types.AST, error) {
+	ast := &types.AST{
+		Language:       "shell",
+		Content:        content,
+		FilePath:       filePath,
+		Hash:           calculateHash(content),
+		Version:        "1.0",
+		ParsedAt:       time.Now(),
+		TreeSitterTree: nil,
+	}
+
+	root := &types.ASTNode{
+		Id:   "shell-root",
+		Type: "program",
+		Location: types.FileLocation{
+			FilePath: filePath,
+			Line:     1,
+			Column:   1,
+		},
+		Value:    content,
+		Children: []*types.ASTNode{},
+		Metadata: make(map[string]interface{}),
+	}
+
+	lines := shellCodeLines(strings.Split(content, "\n"))
+	var topLevel []string
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" {
+			continue
+		}
+
+		if match := shellSourcePattern.FindStringSubmatch(line); match != nil {
+			root.Children = append(root.Children, shellSourceNode(match[1], filePath, i+1))
+			continue
+		}
+
+		match := shellFunctionPattern.FindStringSubmatch(line)
+		if match == nil {
+			topLevel = append(topLevel, shellCommands(line)...)
+			continue
+		}
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+
+		end := shellBodyEnd(lines, i)
+		var commands []string
+		for j := i + 1; j < end; j++ {
+			if source := shellSourcePattern.FindStringSubmatch(lines[j]); source != nil {
+				root.Children = append(root.Children, shellSourceNode(source[1], filePath, j+1))
+				continue
+			}
+			commands = append(commands, shellCommands(lines[j])...)
+		}
+
+		root.Children = append(root.Children, &types.ASTNode{
+			Id:   fmt.Sprintf("func-%s-%d", name, i+1),
+			Type: "function_definition",
+			Location: types.FileLocation{
+				FilePath: filePath,
+				Line:     i + 1,
+				Column:   1,
+				EndLine:  end,
+			},
+			Value: strings.TrimSpace(line),
+			Children: []*types.ASTNode{
+				{
+					Id:    fmt.Sprintf("func-name-%s", name),
+					Type:  "identifier",
+					Value: name,
+					Location: types.FileLocation{
+						FilePath: filePath,
+						Line:     i + 1,
+						Column:   strings.Index(line, name) + 1,
+					},
+				},
+			},
+			Metadata: map[string]interface{}{
+				MetadataShellCommands: uniqueSorted(commands),
+			},
+		})
+		i = end - 1
+	}
+	root.Metadata[MetadataShellCommands] = uniqueSorted(topLevel)
+
+	ast.Root = root
+	return ast, nil
+}
+
+// nodeToSymbolShell converts shell AST nodes into symbols
+func (m *Manager) nodeToSymbolShell(node *types.ASTNode, filePath, language string) *types.Symbol {
+	if node.Type != "function_definition" {
+		return nil
+	}
+	name := m.extractSymbolName(node)
+	symbol := &types.Symbol{
+		Id:           types.SymbolId(fmt.Sprintf("func-%s-%d", filePath, node.Location.Line)),
+		Name:         name,
+		Type:         types.SymbolTypeFunction,
+		Location:     convertLocation(node.Location),
+		Signature:    name + "()",
+		Language:     language,
+		Hash:         calculateHash(node.Value),
+		LastModified: time.Now(),
+	}
+	if node.Location.EndLine > node.Location.Line {
+		symbol.Location.EndLine = node.Location.EndLine
+	}
+	if commands, ok := node.Metadata[MetadataShellCommands].([]string); ok && len(commands) > 0 {
+		symbol.SetMetadata(MetadataShellCommands, commands)
+	}
+	return symbol
+}
+
+// shellSourceNode represents `source file` / `. file` as an import of the sourced script
+func shellSourceNode(rawPath, filePath string, line int) *types.ASTNode {
+	path := strings.Trim(rawPath, `"'`)
+	if stripped := shellDirPrefix.ReplaceAllString(rawPath, ""); stripped != rawPath {
+		path = "./" + strings.Trim(stripped, `"'`)
+	}
+	return &types.ASTNode{
+		Id:   fmt.Sprintf("source-%s-%d", path, line),
+		Type: "import_declaration",
+		Location: types.FileLocation{
+			FilePath: filePath,
+			Line:     line,
+			Column:   1,
+		},
+		Value: rawPath,
+		Children: []*types.ASTNode{
+			{Id: fmt.Sprintf("source-path-%d", line), Type: "string", Value: path},
+		},
+	}
+}
+
+// shellCodeLines blanks comments and heredoc bodies so later passes only see code.
+// Line positions are preserved.
+func shellCodeLines(raw []string) []string {
+	lines := make([]string, len(raw))
+	heredoc := ""
+	for i, line := range raw {
+		if heredoc != "" {
+			if strings.TrimSpace(line) == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		code := stripShellComment(line)
+		if match := shellHeredocPattern.FindStringSubmatch(code); match != nil {
+			heredoc = match[1]
+		}
+		lines[i] = strings.TrimRight(code, " \t\r")
+	}
+	return lines
+}
+
+// stripShellComment removes a trailing # comment; quoted text and ${#var} are left alone
+func stripShellComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t' || line[i-1] == ';'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// shellBodyEnd returns the exclusive end index of the function body starting at lines[start]
+func shellBodyEnd(lines []string, start int) int {
+	depth := 0
+	opened := false
+	for i := start; i < len(lines); i++ {
+		// Case labels such as "start)" would otherwise unbalance the count
+		line := shellCaseLabel.ReplaceAllString(lines[i], "")
+		if i == start {
+			// Ignore the "name()" parentheses when counting
+			if idx := shellFunctionPattern.FindStringSubmatchIndex(lines[i]); idx != nil {
+				if idx[6] >= 0 {
+					line = lines[i][idx[6]:]
+				} else {
+					line = lines[i][idx[1]:]
+				}
+			}
+		}
+		var quote rune
+		for _, r := range line {
+			if quote != 0 {
+				if r == quote {
+					quote = 0
+				}
+				continue
+			}
+			switch r {
+			case '\'', '"':
+				quote = r
+			case '{', '(':
+				depth++
+				opened = true
+			case '}', ')':
+				depth--
+			}
+		}
+		if opened && depth <= 0 {
+			return i + 1
+		}
+	}
+	return len(lines)
+}
+
+// shellCommands returns the command names invoked on a line of shell code
+func shellCommands(line string) []string {
+	line = shellCaseLabel.ReplaceAllString(line, "")
+	line = shellExpansion.ReplaceAllString(line, "$$V")
+	line = shellSingleQuoted.ReplaceAllString(line, "''")
+	// Quoted text is data, except for command substitutions inside it
+	line = shellDoubleQuoted.ReplaceAllStringFunc(line, func(quoted string) string {
+		var inner []string
+		for _, sub := range shellSubstitution.FindAllStringSubmatch(quoted, -1) {
+			inner = append(inner, sub[1])
+		}
+		return "; " + strings.Join(inner, "; ") + " ;"
+	})
+	var commands []string
+	for _, segment := range shellSegmentSplitter.Split(line, -1) {
+		words := strings.Fields(segment)
+		for len(words) > 0 && (shellAssignment.MatchString(words[0]) || shellWrappers[words[0]] || shellCommandPrefixes[words[0]]) {
+			words = words[1:]
+		}
+		if len(words) == 0 {
+			continue
+		}
+		name := words[0]
+		if shellKeywords[name] || !shellCommandName.MatchString(name) {
+			continue
+		}
+		commands = append(commands, name)
+	}
+	return commands
+}
+
+// uniqueSorted returns the distinct values in sorted order
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
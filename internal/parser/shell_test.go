@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShellFunctionsAndSources(t *testing.T) {
+	manager := NewManager()
+
+	script := `#!/usr/bin/env bash
+set -euo pipefail
+
+source "$(dirname "$0")/lib/common.sh"
+. ./env.sh
+
+# build() { not_a_function; }
+build() {
+    local target="${1:-all}"
+    log "building ${target}"
+    docker build -t "app:$(git rev-parse --short HEAD)" . | tee build.log
+    case "$target" in
+        all) make all ;;
+        *) make "$target" ;;
+    esac
+}
+
+function deploy {
+    build
+    cat <<MANIFEST
+kubectl apply -f ignored.yaml
+MANIFEST
+    sudo systemctl restart app && notify_team
+}
+
+deploy
+`
+	lang := manager.detectLanguage("scripts/release.sh")
+	require.NotNil(t, lang)
+	assert.Equal(t, "shell", lang.Name)
+
+	ast, err := manager.parseContent(script, *lang, "scripts/release.sh")
+	require.NoError(t, err)
+
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	require.Len(t, symbols, 2)
+
+	byName := make(map[string]*types.Symbol)
+	for _, symbol := range symbols {
+		byName[symbol.Name] = symbol
+	}
+
+	build := byName["build"]
+	require.NotNil(t, build)
+	assert.Equal(t, types.SymbolTypeFunction, build.Type)
+	assert.Equal(t, 8, build.Location.StartLine)
+	assert.Equal(t, 16, build.Location.EndLine)
+	assert.Equal(t, []string{"docker", "git", "log", "make", "tee"}, build.MetadataStrings(MetadataShellCommands))
+
+	deploy := byName["deploy"]
+	require.NotNil(t, deploy)
+	assert.Equal(t, 18, deploy.Location.StartLine)
+	assert.Equal(t, []string{"build", "cat", "notify_team", "systemctl"}, deploy.MetadataStrings(MetadataShellCommands))
+
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+	require.Len(t, imports, 2)
+	assert.Equal(t, "./lib/common.sh", imports[0].Path)
+	assert.Equal(t, "./env.sh", imports[1].Path)
+}
+
+func TestShellCommands(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{`FOO=bar npm run build`, []string{"npm"}},
+		{`if [[ -f x ]]; then rm -f x; fi`, []string{"rm"}},
+		{`echo "${HOME}/bin" | grep bin`, []string{"grep"}},
+		{`result=$(curl -s "$url")`, []string{"curl"}},
+		{`    start) run_server ;;`, []string{"run_server"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.expected, shellCommands(tt.line))
+		})
+	}
+}
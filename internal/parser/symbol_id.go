@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// scopeTypes are the symbol types whose members are qualified by their name
+var scopeTypes = map[types.SymbolType]bool{
+	types.SymbolTypeClass:     true,
+	types.SymbolTypeInterface: true,
+	types.SymbolTypeEnum:      true,
+}
+
+// SetRoot makes symbol IDs qualify names by their path relative to root, so
+// analyses of one tree from different working directories agree. Without a
+// root, paths are used as given.
+func (m *Manager) SetRoot(root string) {
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.root = root
+}
+
+// qualifiedPath returns filePath relative to the root with forward slashes,
+// or filePath itself without a root or outside it
+func (m *Manager) qualifiedPath(filePath string) string {
+	m.mu.RLock()
+	root := m.root
+	m.mu.RUnlock()
+	if root == "" {
+		return filePath
+	}
+	abs, err := filepath.Abs(filePath)
+	if err != nil {
+		return filePath
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filePath
+	}
+	return filepath.ToSlash(rel)
+}
+
+// assignStableIds replaces position-based symbol IDs with IDs derived from the
+// symbol's fully qualified name and signature. The name is qualified by
+// filePath and by the receiver or enclosing type of members. Symbols that
+// would otherwise share an ID (e.g. identical overloads) get an ordinal suffix
+// in declaration order.
+func assignStableIds(symbols []*types.Symbol, filePath string) {
+	seen := make(map[types.SymbolId]int, len(symbols))
+
+	for _, symbol := range symbols {
+		if symbol == nil {
+			continue
+		}
+
+		if symbol.FullyQualifiedName == "" {
+			scope := symbol.MetadataString(MetadataReceiver)
+			if scope == "" {
+				scope = symbol.MetadataString(MetadataScope)
+			}
+			symbol.FullyQualifiedName = types.QualifiedName(filePath, scope, symbol.Name)
+		}
+
+		id := types.StableSymbolId(symbol.Type, symbol.FullyQualifiedName, symbol.Signature)
+		seen[id]++
+		if count := seen[id]; count > 1 {
+			id = types.SymbolId(fmt.Sprintf("%s-%d", id, count))
+		}
+		symbol.Id = id
+	}
+}
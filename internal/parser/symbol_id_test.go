@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+func TestStableSymbolIds(t *testing.T) {
+	manager := NewManager()
+
+	extract := func(content string) map[string]types.SymbolId {
+		lang := manager.detectLanguage("stable.go")
+		ast, err := manager.parseContent(content, *lang, "stable.go")
+		if err != nil {
+			t.Fatalf("Failed to parse content: %v", err)
+		}
+		symbols, err := manager.ExtractSymbols(ast)
+		if err != nil {
+			t.Fatalf("Failed to extract symbols: %v", err)
+		}
+		ids := make(map[string]types.SymbolId)
+		for _, symbol := range symbols {
+			ids[symbol.Name] = symbol.Id
+		}
+		return ids
+	}
+
+	original := extract("package main\n\nfunc Alpha() string {\n\treturn \"a\"\n}\n\nfunc Beta(x int) int {\n\treturn x\n}\n")
+	shifted := extract("package main\n\n// New comment block\n// pushes everything down\n\nfunc Alpha() string {\n\treturn \"a\"\n}\n\nfunc Beta(x int) int {\n\treturn x\n}\n")
+
+	for _, name := range []string{"Alpha", "Beta"} {
+		if original[name] == "" {
+			t.Fatalf("Expected symbol %s to be extracted", name)
+		}
+		if original[name] != shifted[name] {
+			t.Errorf("ID for %s changed after line shift: %s != %s", name, original[name], shifted[name])
+		}
+	}
+
+	if original["Alpha"] == original["Beta"] {
+		t.Error("Distinct symbols must not share an ID")
+	}
+
+	changed := extract("package main\n\nfunc Alpha() string {\n\treturn \"a\"\n}\n\nfunc Beta(x, y int) int {\n\treturn x\n}\n")
+	if changed["Beta"] == original["Beta"] {
+		t.Error("ID should change when the signature changes")
+	}
+}
+
+func TestAssignStableIdsDisambiguatesDuplicates(t *testing.T) {
+	symbols := []*types.Symbol{
+		{Name: "handler", Type: types.SymbolTypeFunction, Signature: "()"},
+		{Name: "handler", Type: types.SymbolTypeFunction, Signature: "()"},
+	}
+
+	assignStableIds(symbols, "dup.js")
+
+	if symbols[0].Id == symbols[1].Id {
+		t.Errorf("Duplicate symbols should get distinct IDs, both got %s", symbols[0].Id)
+	}
+	if symbols[0].FullyQualifiedName != "dup.js::handler" {
+		t.Errorf("Unexpected fully qualified name: %s", symbols[0].FullyQualifiedName)
+	}
+}
+
+func TestStableSymbolIdsQualifyMembers(t *testing.T) {
+	root := t.TempDir()
+	manager := NewManager()
+	manager.SetRoot(root)
+
+	extract := func(filePath, content string) map[string]*types.Symbol {
+		lang := manager.detectLanguage(filePath)
+		ast, err := manager.parseContent(content, *lang, filepath.Join(root, filePath))
+		if err != nil {
+			t.Fatalf("Failed to parse content: %v", err)
+		}
+		symbols, err := manager.ExtractSymbols(ast)
+		if err != nil {
+			t.Fatalf("Failed to extract symbols: %v", err)
+		}
+		byFQN := make(map[string]*types.Symbol)
+		for _, symbol := range symbols {
+			byFQN[symbol.FullyQualifiedName] = symbol
+		}
+		return byFQN
+	}
+
+	members := extract("src/store.ts", "class UserStore {\n  load() {}\n}\n\nclass OrderStore {\n  load() {}\n}\n")
+	users, orders := members["src/store.ts::UserStore.load"], members["src/store.ts::OrderStore.load"]
+	if users == nil || orders == nil {
+		t.Fatalf("Expected methods qualified by their class, got %v", qualifiedNames(members))
+	}
+	if users.Id == orders.Id {
+		t.Errorf("Methods of different classes should get distinct IDs, both got %s", users.Id)
+	}
+
+	methods := extract("store.go", "package store\n\ntype Users struct{}\ntype Orders struct{}\n\nfunc (u *Users) Load() {}\n\nfunc (Orders) Load() {}\n")
+	if methods["store.go::Users.Load"] == nil || methods["store.go::Orders.Load"] == nil {
+		t.Errorf("Expected methods qualified by their receiver, got %v", qualifiedNames(methods))
+	}
+}
+
+func qualifiedNames(symbols map[string]*types.Symbol) []string {
+	names := make([]string, 0, len(symbols))
+	for name := range symbols {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,582 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Symbol metadata keys for template symbols
+const (
+	MetadataTemplateVariables = "template_variables" // Context variables a template or block reads, sorted
+)
+
+// Template languages, parsed by the template-regex parser
+const (
+	LanguageJinja      = "jinja"      // Jinja2, Django, Nunjucks and Twig
+	LanguageERB        = "erb"        // Embedded Ruby
+	LanguageHandlebars = "handlebars" // Handlebars and Mustache
+	LanguageGoTemplate = "gotemplate" // text/template and html/template
+)
+
+// IsTemplateLanguage reports whether a language is a template engine
+func IsTemplateLanguage(language string) bool {
+	switch language {
+	case LanguageJinja, LanguageERB, LanguageHandlebars, LanguageGoTemplate:
+		return true
+	}
+	return false
+}
+
+// templateMarkers recognize the engine of an .html file by its syntax
+var templateMarkers = []struct {
+	language string
+	pattern  *regexp.Regexp
+}{
+	{LanguageJinja, regexp.MustCompile(`\{%-?\s*(?:extends|block|include|for|if|load|macro|set)\b`)},
+	{LanguageERB, regexp.MustCompile(`<%[=-]?\s`)},
+	{LanguageGoTemplate, regexp.MustCompile(`\{\{-?\s*(?:define|template|range|with)\s|\{\{-?\s*\.[A-Z]`)},
+	{LanguageHandlebars, regexp.MustCompile(`\{\{[#>]\s*[\w"]`)},
+}
+
+var (
+	jinjaCommentPattern = regexp.MustCompile(`(?s)\{#.*?#\}`)
+	jinjaTagPattern     = regexp.MustCompile(`(?s)\{%-?\s*(.*?)\s*-?%\}|\{\{-?\s*(.*?)\s*-?\}\}`)
+	erbTagPattern       = regexp.MustCompile(`(?s)<%([=#-]?)(.*?)-?%>`)
+	goTagPattern        = regexp.MustCompile(`(?s)\{\{-?\s*(.*?)\s*-?\}\}`)
+	handlebarsTag       = regexp.MustCompile(`(?s)\{\{\{?~?\s*(.*?)\s*~?\}?\}\}`)
+
+	templateQuoted     = regexp.MustCompile(`"[^"]*"|'[^']*'|` + "`[^`]*`")
+	templateIdentifier = regexp.MustCompile(`[A-Za-z_]\w*`)
+	templateAssignment = regexp.MustCompile(`(\w+)\s*=[^=]`)
+	templateAsName     = regexp.MustCompile(`\bas\s+(\w+)`)
+
+	erbRenderPattern  = regexp.MustCompile(`\brender\s*\(?\s*(?:partial:\s*|:partial\s*=>\s*|template:\s*)?["']([^"']+)["']`)
+	erbContentFor     = regexp.MustCompile(`\b(?:content_for|provide)\s*\(?\s*:(\w+)`)
+	erbInstanceVar    = regexp.MustCompile(`@\w+`)
+	goFieldPattern    = regexp.MustCompile(`\$?\.[A-Za-z_]\w*`)
+	handlebarsAsBlock = regexp.MustCompile(`\bas\s+\|([^|]*)\|`)
+)
+
+// jinjaKeywords are operators, literals and special names of Jinja
+// expressions that are not context variables
+var jinjaKeywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "is": true, "if": true, "else": true,
+	"true": true, "false": true, "none": true, "True": true, "False": true, "None": true,
+	"loop": true, "super": true, "self": true, "caller": true, "varargs": true, "kwargs": true,
+	"recursive": true, "ignore": true, "missing": true, "with": true, "without": true, "context": true, "only": true,
+}
+
+// handlebarsLiterals are the literal values of Handlebars expressions
+var handlebarsLiterals = map[string]bool{
+	"true": true, "false": true, "null": true, "undefined": true, "this": true, "else": true,
+}
+
+// templateDefinition is a block, macro or named template being parsed
+type templateDefinition struct {
+	node      *types.ASTNode
+	variables []string
+	closer    string // Keyword ending the definition, empty when it is not tracked
+}
+
+// templateParser collects the definitions, includes and variables of a
+// template while its tags are read in order
+type templateParser struct {
+	filePath  string
+	content   string
+	root      *types.ASTNode
+	open      []*templateDefinition
+	variables []string
+	locals    map[string]bool
+}
+
+// parseTemplateContentWithContext extracts the blocks, macros and named
+// templates of a template file, the templates it extends or includes, and the
+// context variables it reads
+func (m *Manager) parseTemplateContentWithContext(ctx context.Context, content, filePath, language string) (*types.AST, error) {
+	ast := &types.AST{
+		Language:       language,
+		Content:        content,
+		FilePath:       filePath,
+		Hash:           calculateHash(content),
+		Version:        "1.0",
+		ParsedAt:       time.Now(),
+		TreeSitterTree: nil,
+	}
+
+	p := &templateParser{
+		filePath: filePath,
+		content:  content,
+		root: &types.ASTNode{
+			Id:   "template-root",
+			Type: "template",
+			Location: types.FileLocation{
+				FilePath: filePath,
+				Line:     1,
+				Column:   1,
+				EndLine:  strings.Count(content, "\n") + 1,
+			},
+			Value:    content,
+			Children: []*types.ASTNode{},
+			Metadata: make(map[string]interface{}),
+		},
+		locals: make(map[string]bool),
+	}
+	switch language {
+	case LanguageJinja:
+		p.parseJinja()
+	case LanguageERB:
+		p.parseERB()
+	case LanguageHandlebars:
+		p.parseHandlebars()
+	case LanguageGoTemplate:
+		p.parseGoTemplate()
+	}
+
+	// Loop variables, assignments and macro parameters are not read from the context
+	p.root.Metadata[MetadataTemplateVariables] = p.contextVariables(p.variables)
+	for _, child := range p.root.Children {
+		if variables, ok := child.Metadata[MetadataTemplateVariables].([]string); ok {
+			child.Metadata[MetadataTemplateVariables] = p.contextVariables(variables)
+		}
+	}
+	ast.Root = p.root
+	return ast, nil
+}
+
+// parseJinja reads Jinja2, Django, Nunjucks and Twig tags
+func (p *templateParser) parseJinja() {
+	content := blankMatches(p.content, jinjaCommentPattern)
+	for _, match := range jinjaTagPattern.FindAllStringSubmatchIndex(content, -1) {
+		offset := match[0]
+		if match[2] < 0 {
+			p.reference(jinjaVariables(content[match[4]:match[5]])...)
+			continue
+		}
+		keyword, rest := cutKeyword(content[match[2]:match[3]])
+		switch keyword {
+		case "extends", "include", "import", "from", "embed", "use":
+			path := templateQuoted.FindString(rest)
+			if path == "" {
+				p.reference(jinjaVariables(rest)...)
+				continue
+			}
+			imp := p.importNode(strings.Trim(path, `"'`), offset)
+			if keyword == "import" {
+				if as := templateAsName.FindStringSubmatch(rest); as != nil {
+					imp.Children = append(imp.Children, &types.ASTNode{Type: "namespace_import", Children: []*types.ASTNode{{Type: "identifier", Value: as[1]}}})
+					p.locals[as[1]] = true
+				}
+			}
+			if _, names, ok := strings.Cut(rest, " import "); keyword == "from" && ok {
+				for _, name := range strings.Split(names, ",") {
+					fields := strings.Fields(name)
+					if len(fields) == 0 {
+						continue
+					}
+					imp.Children = append(imp.Children, &types.ASTNode{Type: "import_specifier", Children: []*types.ASTNode{{Type: "identifier", Value: fields[0]}}})
+					p.locals[fields[len(fields)-1]] = true
+				}
+			}
+			// Django passes variables with "with name=value"
+			if _, with, ok := strings.Cut(rest, " with "); ok {
+				p.reference(jinjaVariables(with)...)
+			}
+		case "block":
+			name, _ := cutKeyword(rest)
+			p.define(name, "block", "{% block "+name+" %}", offset, "endblock")
+		case "macro":
+			name, params, _ := strings.Cut(rest, "(")
+			name = strings.TrimSpace(name)
+			for _, param := range strings.Split(strings.TrimSuffix(strings.TrimSpace(params), ")"), ",") {
+				if param, _, _ = strings.Cut(param, "="); strings.TrimSpace(param) != "" {
+					p.locals[strings.TrimSpace(param)] = true
+				}
+			}
+			p.define(name, "macro", "{% macro "+strings.Join(strings.Fields(rest), " ")+" %}", offset, "endmacro")
+		case "endblock", "endmacro":
+			p.close(keyword, offset)
+		case "for":
+			targets, iterable, _ := strings.Cut(rest, " in ")
+			for _, target := range strings.Split(targets, ",") {
+				p.locals[strings.Trim(strings.TrimSpace(target), "()")] = true
+			}
+			p.reference(jinjaVariables(iterable)...)
+		case "set", "with":
+			for _, assignment := range templateAssignment.FindAllStringSubmatch(rest, -1) {
+				p.locals[assignment[1]] = true
+			}
+			if as := templateAsName.FindStringSubmatch(rest); as != nil {
+				p.locals[as[1]] = true
+			}
+			if _, value, ok := strings.Cut(rest, "="); ok {
+				p.reference(jinjaVariables(value)...)
+			} else if keyword == "with" {
+				p.reference(jinjaVariables(templateAsName.ReplaceAllString(rest, ""))...)
+			}
+		case "load", "comment", "endcomment", "csrf_token", "trans", "blocktrans", "now", "url", "static":
+			// Django tags naming libraries, translations and routes rather than variables
+			if keyword == "url" {
+				p.reference(jinjaVariables(templateQuoted.ReplaceAllString(rest, ""))...)
+			}
+		default:
+			if !strings.HasPrefix(keyword, "end") {
+				p.reference(jinjaVariables(rest)...)
+			}
+		}
+	}
+	p.closeAll()
+}
+
+// jinjaVariables returns the context variables a Jinja expression reads:
+// the roots of attribute paths, skipping filters, tests, called functions,
+// keyword arguments and literals
+func jinjaVariables(expression string) []string {
+	expression = blankMatches(expression, templateQuoted)
+	var variables []string
+	for _, loc := range templateIdentifier.FindAllStringIndex(expression, -1) {
+		name := expression[loc[0]:loc[1]]
+		before := strings.TrimRight(expression[:loc[0]], " \t\n")
+		after := strings.TrimLeft(expression[loc[1]:], " \t\n")
+		switch {
+		case jinjaKeywords[name] || (loc[0] > 0 && isWordByte(expression[loc[0]-1])):
+		case strings.HasSuffix(before, ".") || strings.HasSuffix(before, "|"):
+		case strings.HasSuffix(before, " is") || strings.HasSuffix(before, " is not"):
+		case strings.HasPrefix(after, "(") || (strings.HasPrefix(after, "=") && !strings.HasPrefix(after, "==")):
+		default:
+			variables = append(variables, name)
+		}
+	}
+	return variables
+}
+
+// parseERB reads Embedded Ruby tags: rendered partials, content_for blocks
+// and the instance variables set by controllers
+func (p *templateParser) parseERB() {
+	for _, match := range erbTagPattern.FindAllStringSubmatchIndex(p.content, -1) {
+		if p.content[match[2]:match[3]] == "#" {
+			continue
+		}
+		code := p.content[match[4]:match[5]]
+		for _, render := range erbRenderPattern.FindAllStringSubmatch(code, -1) {
+			p.importNode(render[1], match[0])
+		}
+		if name := erbContentFor.FindStringSubmatch(code); name != nil {
+			p.define(name[1], "content_for", strings.TrimSpace(name[0]), match[0], "")
+		}
+		p.reference(erbInstanceVar.FindAllString(templateQuoted.ReplaceAllString(code, ""), -1)...)
+	}
+	p.closeAll()
+}
+
+// parseHandlebars reads Handlebars and Mustache tags: partials, inline
+// partials and the paths of expressions and block helpers
+func (p *templateParser) parseHandlebars() {
+	for _, match := range handlebarsTag.FindAllStringSubmatchIndex(p.content, -1) {
+		tag := p.content[match[2]:match[3]]
+		offset := match[0]
+		switch {
+		case strings.HasPrefix(tag, "!"):
+			continue
+		case strings.HasPrefix(tag, "#*inline"):
+			if name := templateQuoted.FindString(tag); name != "" {
+				name = strings.Trim(name, `"'`)
+				p.define(name, "inline", "{{#*inline \""+name+"\"}}", offset, "/inline")
+			}
+			continue
+		case strings.HasPrefix(tag, "/"):
+			p.close(strings.TrimSpace(tag), offset)
+			continue
+		case strings.HasPrefix(tag, ">") || strings.HasPrefix(tag, "#>"):
+			fields := strings.Fields(strings.TrimLeft(tag, "#> "))
+			if len(fields) > 0 {
+				p.importNode(strings.Trim(fields[0], `"'`), offset)
+				p.reference(handlebarsVariables(fields[1:], false)...)
+			}
+			continue
+		}
+
+		tag = strings.TrimLeft(tag, "#^&{ ")
+		if as := handlebarsAsBlock.FindStringSubmatch(tag); as != nil {
+			for _, param := range strings.Fields(as[1]) {
+				p.locals[param] = true
+			}
+			tag = handlebarsAsBlock.ReplaceAllString(tag, "")
+		}
+		fields := strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(blankMatches(tag, templateQuoted)))
+		// {{name}} and {{#section}} read a variable; {{helper arg}} and
+		// {{#each items}} call a helper
+		p.reference(handlebarsVariables(fields, len(fields) > 1)...)
+	}
+	p.closeAll()
+}
+
+// handlebarsVariables returns the roots of the paths among expression
+// tokens, skipping the helper named by the first token when helper is set
+// and the helpers of subexpressions
+func handlebarsVariables(fields []string, helper bool) []string {
+	var variables []string
+	for i, field := range fields {
+		if (i == 0 && helper) || field == "(" || field == ")" || (i > 0 && fields[i-1] == "(") {
+			continue
+		}
+		if _, value, ok := strings.Cut(field, "="); ok {
+			field = value
+		}
+		for strings.HasPrefix(field, "../") {
+			field = field[3:]
+		}
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "./"), "this.")
+		root := strings.FieldsFunc(field, func(r rune) bool { return r == '.' || r == '/' })
+		if len(root) == 0 || handlebarsLiterals[root[0]] || strings.HasPrefix(root[0], "@") ||
+			!templateIdentifier.MatchString(root[0]) || templateIdentifier.FindString(root[0]) != root[0] {
+			continue
+		}
+		variables = append(variables, root[0])
+	}
+	return variables
+}
+
+// parseGoTemplate reads text/template and html/template actions: named
+// templates, the templates they invoke and the fields of the data they read
+func (p *templateParser) parseGoTemplate() {
+	// Inside range and with the dot is an element, not the template's data
+	var controls []string
+	rebound := 0
+	for _, match := range goTagPattern.FindAllStringSubmatchIndex(p.content, -1) {
+		action := p.content[match[2]:match[3]]
+		offset := match[0]
+		if strings.HasPrefix(action, "/*") {
+			continue
+		}
+		keyword, rest := cutKeyword(action)
+		switch keyword {
+		case "define", "block":
+			name := strings.Trim(templateQuoted.FindString(rest), "\"`")
+			if name != "" {
+				p.define(name, keyword, fmt.Sprintf("{{%s %q}}", keyword, name), offset, "end")
+				controls = append(controls, "define")
+			}
+			if keyword == "define" {
+				continue
+			}
+		case "template":
+			if name := strings.Trim(templateQuoted.FindString(rest), "\"`"); name != "" {
+				p.importNode(name, offset)
+			}
+		case "if", "range", "with":
+			controls = append(controls, keyword)
+		case "end":
+			if len(controls) > 0 {
+				last := controls[len(controls)-1]
+				controls = controls[:len(controls)-1]
+				switch last {
+				case "range", "with":
+					rebound--
+				case "define":
+					p.close("end", offset)
+				}
+			}
+			continue
+		}
+		for _, field := range goFieldPattern.FindAllStringIndex(blankMatches(action, templateQuoted), -1) {
+			if field[0] > 0 && (isWordByte(action[field[0]-1]) || action[field[0]-1] == ')') {
+				continue // A field of a variable or a call result
+			}
+			name := action[field[0]:field[1]]
+			if strings.HasPrefix(name, "$.") || rebound == 0 {
+				p.reference(strings.TrimPrefix(strings.TrimPrefix(name, "$"), "."))
+			}
+		}
+		if keyword == "range" || keyword == "with" {
+			rebound++
+		}
+	}
+	p.closeAll()
+}
+
+// define opens a block, macro or named template at offset
+func (p *templateParser) define(name, subtype, signature string, offset int, closer string) {
+	if name == "" {
+		return
+	}
+	line, column := p.position(offset)
+	node := &types.ASTNode{
+		Id:   fmt.Sprintf("template-%s-%d", name, line),
+		Type: "template_definition",
+		Location: types.FileLocation{
+			FilePath: p.filePath,
+			Line:     line,
+			Column:   column,
+			EndLine:  line,
+		},
+		Value: signature,
+		Children: []*types.ASTNode{
+			{Id: fmt.Sprintf("template-name-%s", name), Type: "identifier", Value: name},
+		},
+		Metadata: map[string]interface{}{types.SubtypeMetadataKey: subtype},
+	}
+	p.root.Children = append(p.root.Children, node)
+	if closer != "" {
+		p.open = append(p.open, &templateDefinition{node: node, closer: closer})
+	} else {
+		node.Metadata[MetadataTemplateVariables] = []string{}
+	}
+}
+
+// close ends the innermost definition closed by keyword; tags such as
+// {% endblock content %} and {{/inline}} may name what they close
+func (p *templateParser) close(keyword string, offset int) {
+	keyword, _ = cutKeyword(keyword)
+	for i := len(p.open) - 1; i >= 0; i-- {
+		if p.open[i].closer != keyword {
+			continue
+		}
+		definition := p.open[i]
+		p.open = append(p.open[:i], p.open[i+1:]...)
+		definition.node.Location.EndLine, _ = p.position(offset)
+		definition.node.Metadata[MetadataTemplateVariables] = definition.variables
+		return
+	}
+}
+
+// closeAll ends the definitions left open at the end of the file
+func (p *templateParser) closeAll() {
+	for len(p.open) > 0 {
+		definition := p.open[len(p.open)-1]
+		p.open = p.open[:len(p.open)-1]
+		definition.node.Location.EndLine = p.root.Location.EndLine
+		definition.node.Metadata[MetadataTemplateVariables] = definition.variables
+	}
+}
+
+// reference records variables read by the template and its open definitions
+func (p *templateParser) reference(variables ...string) {
+	p.variables = append(p.variables, variables...)
+	for _, definition := range p.open {
+		definition.variables = append(definition.variables, variables...)
+	}
+}
+
+// importNode records an extended, included or invoked template
+func (p *templateParser) importNode(path string, offset int) *types.ASTNode {
+	line, column := p.position(offset)
+	node := &types.ASTNode{
+		Id:   fmt.Sprintf("template-import-%s-%d", path, line),
+		Type: "import_declaration",
+		Location: types.FileLocation{
+			FilePath: p.filePath,
+			Line:     line,
+			Column:   column,
+		},
+		Value: path,
+		Children: []*types.ASTNode{
+			{Id: fmt.Sprintf("template-path-%d", line), Type: "string", Value: path},
+		},
+	}
+	p.root.Children = append(p.root.Children, node)
+	return node
+}
+
+// contextVariables drops locals from variables and sorts them
+func (p *templateParser) contextVariables(variables []string) []string {
+	var context []string
+	for _, variable := range variables {
+		if !p.locals[variable] {
+			context = append(context, variable)
+		}
+	}
+	return uniqueSorted(context)
+}
+
+// position returns the 1-based line and column of an offset
+func (p *templateParser) position(offset int) (int, int) {
+	before := p.content[:offset]
+	return strings.Count(before, "\n") + 1, offset - strings.LastIndexByte(before, '\n')
+}
+
+// nodeToSymbolTemplate converts template AST nodes into symbols: the template
+// itself, named after its file, and its blocks, macros and named templates
+func (m *Manager) nodeToSymbolTemplate(node *types.ASTNode, filePath, language string) *types.Symbol {
+	var symbol *types.Symbol
+	switch node.Type {
+	case "template":
+		name := filepath.Base(filePath)
+		symbol = &types.Symbol{
+			Id:           types.SymbolId(fmt.Sprintf("template-%s", filePath)),
+			Name:         name,
+			Type:         types.SymbolTypeComponent,
+			Location:     convertLocation(node.Location),
+			Signature:    name,
+			Language:     language,
+			Hash:         calculateHash(node.Value),
+			LastModified: time.Now(),
+		}
+		symbol.SetMetadata(types.SubtypeMetadataKey, "template")
+	case "template_definition":
+		symbol = &types.Symbol{
+			Id:           types.SymbolId(fmt.Sprintf("template-%s-%d", filePath, node.Location.Line)),
+			Name:         m.extractSymbolName(node),
+			Type:         types.SymbolTypeFunction,
+			Location:     convertLocation(node.Location),
+			Signature:    node.Value,
+			Language:     language,
+			Hash:         calculateHash(node.Value),
+			LastModified: time.Now(),
+		}
+		symbol.SetMetadata(types.SubtypeMetadataKey, node.Metadata[types.SubtypeMetadataKey])
+	default:
+		return nil
+	}
+	symbol.Location.EndLine = node.Location.EndLine
+	if variables, ok := node.Metadata[MetadataTemplateVariables].([]string); ok && len(variables) > 0 {
+		symbol.SetMetadata(MetadataTemplateVariables, variables)
+	}
+	return symbol
+}
+
+// templateLanguage recognizes the engine of an HTML file from template
+// syntax in its head or tail, or returns ""
+func templateLanguage(head, tail []byte) string {
+	text := string(head) + "\n" + string(tail)
+	best, bestIndex := "", len(text)
+	for _, marker := range templateMarkers {
+		if loc := marker.pattern.FindStringIndex(text); loc != nil && loc[0] < bestIndex {
+			best, bestIndex = marker.language, loc[0]
+		}
+	}
+	return best
+}
+
+// blankMatches replaces the matches of a pattern with spaces, keeping
+// offsets and line breaks
+func blankMatches(text string, pattern *regexp.Regexp) string {
+	return pattern.ReplaceAllStringFunc(text, func(match string) string {
+		blank := []byte(match)
+		for i := range blank {
+			if blank[i] != '\n' {
+				blank[i] = ' '
+			}
+		}
+		return string(blank)
+	})
+}
+
+// cutKeyword splits the first word of a tag from the rest
+func cutKeyword(tag string) (string, string) {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexFunc(tag, unicode.IsSpace); i >= 0 {
+		return tag[:i], strings.TrimSpace(tag[i:])
+	}
+	return tag, ""
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseTemplate returns the symbols by name and the import paths of a template
+func parseTemplate(t *testing.T, path, content string) (map[string]*types.Symbol, []string) {
+	t.Helper()
+	manager := NewManager()
+	ast, err := manager.parseContent(content, *manager.detectLanguage(path), path)
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+	imports, err := manager.ExtractImports(ast)
+	require.NoError(t, err)
+
+	byName := make(map[string]*types.Symbol)
+	for _, symbol := range symbols {
+		byName[symbol.Name] = symbol
+	}
+	var paths []string
+	for _, imp := range imports {
+		paths = append(paths, imp.Path)
+	}
+	return byName, paths
+}
+
+func TestJinjaTemplate(t *testing.T) {
+	symbols, imports := parseTemplate(t, "templates/orders.html.j2", `{% extends "base.html" %}
+{% import "forms.html" as forms %}
+{# {{ commented_out }} #}
+{% block content %}
+  <h1>{{ title|upper }}</h1>
+  {% for order in orders if order.total > minimum %}
+    {{ forms.row(order.id, label=order.name) }}
+  {% endfor %}
+  {% set count = orders|length %}
+  {% include "footer.html" with year=current_year %}
+{% endblock content %}
+{% macro badge(text, kind="info") %}<span class="{{ kind }}">{{ text }}{{ user.name }}</span>{% endmacro %}
+`)
+	assert.Equal(t, []string{"base.html", "forms.html", "footer.html"}, imports)
+
+	template := symbols["orders.html.j2"]
+	require.NotNil(t, template)
+	assert.Equal(t, types.SymbolTypeComponent, template.Type)
+	assert.Equal(t, "template", template.Subtype())
+	assert.Equal(t, "jinja", template.Language)
+	assert.Equal(t, []string{"current_year", "minimum", "orders", "title", "user"}, template.MetadataStrings(MetadataTemplateVariables))
+
+	content := symbols["content"]
+	require.NotNil(t, content)
+	assert.Equal(t, types.SymbolTypeFunction, content.Type)
+	assert.Equal(t, "block", content.Subtype())
+	assert.Equal(t, 4, content.Location.StartLine)
+	assert.Equal(t, 11, content.Location.EndLine)
+	assert.Equal(t, []string{"current_year", "minimum", "orders", "title"}, content.MetadataStrings(MetadataTemplateVariables))
+
+	badge := symbols["badge"]
+	require.NotNil(t, badge)
+	assert.Equal(t, "macro", badge.Subtype())
+	assert.Equal(t, `{% macro badge(text, kind="info") %}`, badge.Signature)
+	assert.Equal(t, []string{"user"}, badge.MetadataStrings(MetadataTemplateVariables), "macro parameters are not context variables")
+}
+
+func TestERBTemplate(t *testing.T) {
+	symbols, imports := parseTemplate(t, "app/views/users/show.html.erb", `<% content_for :title, @user.name %>
+<h1><%= @user.name %></h1>
+<%# <%= @ignored %> %>
+<%= render "users/form", user: @user %>
+<%= render partial: "shared/footer" %>
+<% @posts.each do |post| %><%= post.title %><% end %>
+`)
+	assert.Equal(t, []string{"users/form", "shared/footer"}, imports)
+	assert.Equal(t, []string{"@posts", "@user"}, symbols["show.html.erb"].MetadataStrings(MetadataTemplateVariables))
+	require.Contains(t, symbols, "title")
+	assert.Equal(t, "content_for", symbols["title"].Subtype())
+}
+
+func TestHandlebarsTemplate(t *testing.T) {
+	symbols, imports := parseTemplate(t, "views/index.hbs", `{{!-- {{secret}} --}}
+{{#*inline "item"}}<li>{{name}}</li>{{/inline}}
+<h1>{{title}}</h1>
+{{#each items as |item|}}{{> item}}{{item.label}}{{/each}}
+{{> footer year=currentYear}}
+{{formatDate (lookup user "joined") format="short"}}
+`)
+	assert.Equal(t, []string{"item", "footer"}, imports)
+	assert.Equal(t, []string{"currentYear", "items", "name", "title", "user"}, symbols["index.hbs"].MetadataStrings(MetadataTemplateVariables))
+
+	item := symbols["item"]
+	require.NotNil(t, item)
+	assert.Equal(t, "inline", item.Subtype())
+	assert.Equal(t, []string{"name"}, item.MetadataStrings(MetadataTemplateVariables))
+}
+
+func TestGoTemplate(t *testing.T) {
+	symbols, imports := parseTemplate(t, "web/templates/layout.tmpl", `{{define "layout"}}
+<title>{{.Title}}</title>
+{{template "nav" .User}}
+{{range .Items}}<li>{{.Name}} for {{$.Owner}}</li>{{end}}
+{{block "footer" .}}<p>{{.Year}}</p>{{end}}
+{{end}}
+`)
+	assert.Equal(t, []string{"nav"}, imports)
+
+	layout := symbols["layout"]
+	require.NotNil(t, layout)
+	assert.Equal(t, "define", layout.Subtype())
+	assert.Equal(t, 1, layout.Location.StartLine)
+	assert.Equal(t, 6, layout.Location.EndLine)
+	assert.Equal(t, []string{"Items", "Owner", "Title", "User", "Year"}, layout.MetadataStrings(MetadataTemplateVariables), "fields read inside range belong to the elements")
+
+	require.Contains(t, symbols, "footer")
+	assert.Equal(t, "block", symbols["footer"].Subtype())
+	assert.Equal(t, []string{"Year"}, symbols["footer"].MetadataStrings(MetadataTemplateVariables))
+}
+
+func TestTemplateLanguageSniffing(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{`<html>{% extends "base.html" %}`, LanguageJinja},
+		{`<p><%= @user.name %></p>`, LanguageERB},
+		{`{{define "main"}}<p>{{.Name}}</p>{{end}}`, LanguageGoTemplate},
+		{`<ul>{{#each items}}<li>{{this}}</li>{{/each}}</ul>`, LanguageHandlebars},
+		{`<p>{{ name }}</p>`, ""},
+		{`<!DOCTYPE html><html></html>`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			assert.Equal(t, tt.expected, templateLanguage([]byte(tt.content), nil))
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// typeScriptEnumPattern matches TypeScript enum declarations, which the
+// JavaScript grammar used for TypeScript cannot parse
+var typeScriptEnumPattern = regexp.MustCompile(`(?m)^[ \t]*(export[ \t]+)?(?:declare[ \t]+)?(?:const[ \t]+)?enum[ \t]+([A-Za-z_$][\w$]*)[ \t]*\{([^}]*)\}`)
+
+// enumMemberPattern matches one member of a TypeScript enum body
+var enumMemberPattern = regexp.MustCompile(`^\s*(?:([A-Za-z_$][\w$]*)|["']([^"']+)["'])\s*(?:=\s*(.+?))?\s*$`)
+
+// pythonEnumBases are the base classes that make a Python class an enum
+var pythonEnumBases = map[string]bool{
+	"Enum": true, "IntEnum": true, "StrEnum": true, "Flag": true, "IntFlag": true,
+}
+
+// attachValues records the literal values of constants and the members of
+// enums in symbol metadata, so a value like "PAYMENT_FAILED" can be traced
+// back to where it is defined. Only literal strings, numbers and booleans are
+// recorded; computed values are skipped.
+func attachValues(symbols []*types.Symbol, ast *types.AST) []*types.Symbol {
+	switch ast.Language {
+	case "typescript":
+		symbols = append(symbols, extractTypeScriptEnums(ast)...)
+	case "dart":
+		attachDartValues(symbols, ast.Root, ast.Content)
+		return symbols
+	}
+
+	declaresValues := func(symbol *types.Symbol) bool {
+		switch symbol.Type {
+		case types.SymbolTypeConstant, types.SymbolTypeVariable, types.SymbolTypeEnum, types.SymbolTypeType, types.SymbolTypeClass:
+			return true
+		}
+		return false
+	}
+	forEachSymbolNode(symbols, ast.Root, declaresValues, func(symbol *types.Symbol, node *types.ASTNode, _ []*types.ASTNode) {
+		if value, ok := declaredValue(node); ok {
+			symbol.SetMetadata(types.ValueMetadataKey, value)
+		}
+		if members, values := enumMembers(node); len(members) > 0 {
+			setEnumMembers(symbol, members, values)
+		}
+	})
+	return symbols
+}
+
+// declaredValue returns the literal a declaration node initializes its
+// (first) name to
+func declaredValue(node *types.ASTNode) (string, bool) {
+	switch node.Type {
+	case "export_statement", "expression_statement", "field_declaration", "lexical_declaration", "variable_declaration", "var_declaration":
+		// JavaScript, Python, Java and Go wrap the declarator or assignment
+		for _, child := range node.Children {
+			switch child.Type {
+			case "lexical_declaration", "variable_declaration", "variable_declarator", "assignment", "var_spec":
+				return declaredValue(child)
+			}
+		}
+	case "variable_declarator", "assignment", "const_spec", "var_spec", "const_item", "static_item":
+		if value := assignedValue(node); value != nil {
+			return literalValue(value)
+		}
+	}
+	return "", false
+}
+
+// assignedValue returns the node after the "=" of a declaration or enum
+// member, unwrapping Go's single-value expression lists
+func assignedValue(node *types.ASTNode) *types.ASTNode {
+	for i, child := range node.Children {
+		if child.Type == "=" && i+1 < len(node.Children) {
+			value := node.Children[i+1]
+			if value.Type == "expression_list" && len(value.Children) == 1 {
+				value = value.Children[0]
+			}
+			return value
+		}
+	}
+	return nil
+}
+
+// enumMembers returns the member names of an enum declaration and the values
+// of the members that have one. JavaScript objects whose properties are all
+// literals, such as `const Status = { PAID: 'paid' }`, count as enums.
+func enumMembers(node *types.ASTNode) ([]string, map[string]string) {
+	var members []string
+	values := make(map[string]string)
+	add := func(name string, value *types.ASTNode) {
+		members = append(members, name)
+		if value != nil {
+			if literal, ok := literalValue(value); ok {
+				values[name] = literal
+			}
+		}
+	}
+
+	switch node.Type {
+	case "export_statement", "lexical_declaration", "variable_declarator":
+		for _, child := range node.Children {
+			switch child.Type {
+			case "lexical_declaration", "variable_declarator":
+				return enumMembers(child)
+			case "object":
+				for _, pair := range child.Children {
+					switch pair.Type {
+					case "{", "}", ",", "comment":
+						continue
+					case "pair":
+					default:
+						// Methods and spreads make it an ordinary object
+						return nil, nil
+					}
+					if len(pair.Children) < 3 {
+						return nil, nil
+					}
+					key, value := pair.Children[0], pair.Children[len(pair.Children)-1]
+					if _, ok := literalValue(value); !ok {
+						return nil, nil
+					}
+					name, _ := literalValue(key)
+					if key.Type == "property_identifier" {
+						name = key.Value
+					}
+					add(name, value)
+				}
+			}
+		}
+	case "enum_declaration", "enum_item":
+		// Java enum constants take their value as the first constructor
+		// argument; Rust variants may have an explicit discriminant
+		for _, body := range node.Children {
+			if body.Type != "enum_body" && body.Type != "enum_variant_list" {
+				continue
+			}
+			for _, member := range body.Children {
+				if member.Type != "enum_constant" && member.Type != "enum_variant" {
+					continue
+				}
+				var name string
+				value := assignedValue(member)
+				for _, child := range member.Children {
+					switch child.Type {
+					case "identifier":
+						if name == "" {
+							name = child.Value
+						}
+					case "argument_list":
+						if len(child.Children) > 1 {
+							value = child.Children[1]
+						}
+					}
+				}
+				if name != "" {
+					add(name, value)
+				}
+			}
+		}
+	case "class_definition":
+		// Python enums are classes deriving from Enum whose attributes are the members
+		if !isPythonEnum(node) {
+			return nil, nil
+		}
+		for _, block := range node.Children {
+			if block.Type != "block" {
+				continue
+			}
+			for _, statement := range block.Children {
+				if statement.Type != "expression_statement" || len(statement.Children) == 0 {
+					continue
+				}
+				assignment := statement.Children[0]
+				if assignment.Type == "assignment" && len(assignment.Children) > 0 && assignment.Children[0].Type == "identifier" {
+					add(assignment.Children[0].Value, assignedValue(assignment))
+				}
+			}
+		}
+	}
+	return members, values
+}
+
+func isPythonEnum(node *types.ASTNode) bool {
+	for _, child := range node.Children {
+		if child.Type != "argument_list" {
+			continue
+		}
+		for _, base := range child.Children {
+			name := base.Value
+			if i := strings.LastIndex(name, "."); i >= 0 {
+				name = name[i+1:]
+			}
+			if pythonEnumBases[name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// literalValue returns the value of a string, number or boolean literal.
+// Strings lose their quotes; template strings with substitutions and other
+// expressions are not literals.
+func literalValue(node *types.ASTNode) (string, bool) {
+	switch node.Type {
+	case "string", "string_literal", "interpreted_string_literal", "raw_string_literal", "template_string":
+		var content strings.Builder
+		for _, child := range node.Children {
+			switch child.Type {
+			case "string_fragment", "string_content", "interpreted_string_literal_content", "escape_sequence":
+				content.WriteString(child.Value)
+			case "template_substitution", "interpolation":
+				return "", false
+			}
+		}
+		if content.Len() == 0 && len(node.Value) >= 2 {
+			// Grammars that do not split out the content, and empty strings
+			return strings.Trim(node.Value, "\"'`"), true
+		}
+		return content.String(), true
+	case "number", "integer", "float", "int_literal", "float_literal", "integer_literal", "decimal_integer_literal", "hex_integer_literal", "decimal_floating_point_literal", "imaginary_literal":
+		return node.Value, true
+	case "true", "false", "boolean_literal":
+		return node.Value, true
+	case "unary_expression":
+		// Negative numbers
+		if strings.HasPrefix(node.Value, "-") {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimPrefix(node.Value, "-")), 64); err == nil {
+				return node.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+func setEnumMembers(symbol *types.Symbol, members []string, values map[string]string) {
+	symbol.SetMetadata(types.EnumMembersMetadataKey, members)
+	if len(values) > 0 {
+		symbol.SetMetadata(types.EnumValuesMetadataKey, values)
+	}
+}
+
+// extractTypeScriptEnums builds enum symbols from the source text, since
+// TypeScript enums are syntax errors in the JavaScript grammar
+func extractTypeScriptEnums(ast *types.AST) []*types.Symbol {
+	var symbols []*types.Symbol
+	for _, match := range typeScriptEnumPattern.FindAllStringSubmatchIndex(ast.Content, -1) {
+		name := ast.Content[match[4]:match[5]]
+		startLine := strings.Count(ast.Content[:match[0]], "\n") + 1
+		endLine := strings.Count(ast.Content[:match[1]], "\n") + 1
+		text := ast.Content[match[0]:match[1]]
+		column := len(text) - len(strings.TrimLeft(text, " \t")) + 1
+
+		visibility := types.VisibilityPrivate
+		if match[2] >= 0 {
+			visibility = types.VisibilityPublic
+		}
+		symbol := &types.Symbol{
+			Id:   types.SymbolId(fmt.Sprintf("enum-%s-%d", ast.FilePath, startLine)),
+			Name: name,
+			Type: types.SymbolTypeEnum,
+			Location: types.Location{
+				StartLine:   startLine,
+				StartColumn: column,
+				EndLine:     endLine,
+				EndColumn:   match[1] - strings.LastIndex(ast.Content[:match[1]], "\n") - 1,
+			},
+			Language:     ast.Language,
+			Visibility:   visibility,
+			Hash:         calculateHash(text),
+			LastModified: time.Now(),
+		}
+
+		var members []string
+		values := make(map[string]string)
+		for _, part := range strings.Split(stripLineComments(ast.Content[match[6]:match[7]]), ",") {
+			member := enumMemberPattern.FindStringSubmatch(part)
+			if member == nil {
+				continue
+			}
+			memberName := member[1] + member[2]
+			members = append(members, memberName)
+			if value, ok := sourceLiteral(member[3]); ok {
+				values[memberName] = value
+			}
+		}
+		if len(members) > 0 {
+			setEnumMembers(symbol, members, values)
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// sourceLiteral parses a string or number literal from source text
+func sourceLiteral(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	if len(text) >= 2 && strings.ContainsRune(`"'`+"`", rune(text[0])) && text[len(text)-1] == text[0] {
+		return text[1 : len(text)-1], true
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		return text, true
+	}
+	return "", false
+}
+
+func stripLineComments(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			lines[i] = line[:idx]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attachDartValues reads the values of top-level constants from the source
+// line, since the Dart extractor keeps only the text up to "=", and the
+// members of enums from their value nodes
+func attachDartValues(symbols []*types.Symbol, root *types.ASTNode, content string) {
+	lines := strings.Split(content, "\n")
+	forEachSymbolNode(symbols, root, func(symbol *types.Symbol) bool {
+		return symbol.Type == types.SymbolTypeVariable || symbol.Type == types.SymbolTypeEnum
+	}, func(symbol *types.Symbol, node *types.ASTNode, _ []*types.ASTNode) {
+		switch node.Type {
+		case "variable_declaration":
+			line := node.Location.Line - 1
+			if line < 0 || line >= len(lines) {
+				return
+			}
+			_, rest, found := strings.Cut(lines[line], "=")
+			if !found {
+				return
+			}
+			rest, _, _ = strings.Cut(rest, ";")
+			if value, ok := sourceLiteral(rest); ok {
+				symbol.SetMetadata(types.ValueMetadataKey, value)
+			}
+		case "enum_declaration":
+			var members []string
+			for _, child := range node.Children {
+				if child.Type == "enum_value" && len(child.Children) > 0 {
+					members = append(members, child.Children[0].Value)
+				}
+			}
+			if len(members) > 0 {
+				setEnumMembers(symbol, members, nil)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstantAndEnumValues(t *testing.T) {
+	type want struct {
+		value   string
+		members []string
+		values  map[string]string
+	}
+	tests := []struct {
+		file    string
+		content string
+		want    map[string]want
+	}{
+		{
+			file:    "status.ts",
+			content: "export const FAILED = 'PAYMENT_FAILED';\nconst RETRIES = 3;\nconst label = `id-${FAILED}`;\nconst Codes = { PAID: 'paid', FAILED: \"PAYMENT_FAILED\" };\nexport enum Status {\n  Paid = 'PAID',\n  Pending, // not settled\n}\n",
+			want: map[string]want{
+				"FAILED":  {value: "PAYMENT_FAILED"},
+				"RETRIES": {value: "3"},
+				"label":   {},
+				"Status":  {members: []string{"Paid", "Pending"}, values: map[string]string{"Paid": "PAID"}},
+				"Codes":   {members: []string{"PAID", "FAILED"}, values: map[string]string{"PAID": "paid", "FAILED": "PAYMENT_FAILED"}},
+			},
+		},
+		{
+			file:    "status.go",
+			content: "package status\n\nconst Failed = \"PAYMENT_FAILED\"\n\nconst (\n\tLow = -1\n\tHigh\n)\n",
+			want: map[string]want{
+				"Failed": {value: "PAYMENT_FAILED"},
+				"Low":    {value: "-1"},
+				"High":   {},
+			},
+		},
+		{
+			file:    "status.py",
+			content: "from enum import Enum\n\nFAILED = \"PAYMENT_FAILED\"\n\nclass Status(Enum):\n    PAID = \"paid\"\n    RETRIES = 2\n",
+			want: map[string]want{
+				"Status": {members: []string{"PAID", "RETRIES"}, values: map[string]string{"PAID": "paid", "RETRIES": "2"}},
+				"PAID":   {value: "paid"},
+			},
+		},
+		{
+			file:    "Status.java",
+			content: "class Codes {\n  static final String FAILED = \"PAYMENT_FAILED\";\n}\nenum Status { PAID(\"paid\"), PENDING }\n",
+			want: map[string]want{
+				"FAILED": {value: "PAYMENT_FAILED"},
+				"Status": {members: []string{"PAID", "PENDING"}, values: map[string]string{"PAID": "paid"}},
+			},
+		},
+		{
+			file:    "status.rs",
+			content: "pub const FAILED: &str = \"PAYMENT_FAILED\";\nenum Status { Paid = 1, Pending }\n",
+			want: map[string]want{
+				"FAILED": {value: "PAYMENT_FAILED"},
+				"Status": {members: []string{"Paid", "Pending"}, values: map[string]string{"Paid": "1"}},
+			},
+		},
+	}
+
+	manager := NewManager()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ast, err := manager.Parse(tt.content, tt.file)
+			require.NoError(t, err)
+			symbols, err := manager.ExtractSymbols(ast)
+			require.NoError(t, err)
+
+			got := make(map[string]*types.Symbol)
+			for _, symbol := range symbols {
+				if symbol.Type != types.SymbolTypeNamespace {
+					got[symbol.Name] = symbol
+				}
+			}
+			for name, want := range tt.want {
+				symbol := got[name]
+				require.NotNil(t, symbol, name)
+				assert.Equal(t, want.value, symbol.MetadataString(types.ValueMetadataKey), name)
+				assert.Equal(t, want.members, symbol.MetadataStrings(types.EnumMembersMetadataKey), name)
+				if want.values != nil {
+					assert.Equal(t, want.values, symbol.MetadataStringMap(types.EnumValuesMetadataKey), name)
+				}
+			}
+		})
+	}
+}
+
+func TestTypeScriptEnumSymbols(t *testing.T) {
+	manager := NewManager()
+	ast, err := manager.Parse("enum Direction { Up = 1, Down }\n", "direction.ts")
+	require.NoError(t, err)
+	symbols, err := manager.ExtractSymbols(ast)
+	require.NoError(t, err)
+
+	var enum *types.Symbol
+	for _, symbol := range symbols {
+		if symbol.Name == "Direction" {
+			enum = symbol
+		}
+	}
+	require.NotNil(t, enum)
+	assert.Equal(t, types.SymbolKindEnum, enum.Kind)
+	assert.Equal(t, types.VisibilityPrivate, enum.Visibility)
+	assert.Equal(t, 1, enum.Location.StartLine)
+	assert.True(t, enum.MatchesValue("Down"))
+	assert.True(t, enum.MatchesValue("1"))
+	assert.False(t, enum.MatchesValue("Left"))
+}
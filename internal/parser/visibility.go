@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// assignVisibility records whether each symbol is part of its file's public
+// API, using the convention of the language: export statements in
+// JavaScript/TypeScript, capitalization in Go, a leading underscore in Python
+// and Dart, and modifiers in Java and Rust. Symbols that already have a
+// visibility, such as C++ members, are left alone.
+func assignVisibility(symbols []*types.Symbol, root *types.ASTNode, language string) {
+	switch language {
+	case "go", "python", "dart":
+		for _, symbol := range symbols {
+			if symbol.Visibility == "" && symbol.Type != types.SymbolTypeImport {
+				symbol.Visibility = nameVisibility(symbol.Name, language)
+			}
+		}
+	case "javascript", "typescript", "vue", "svelte", "astro", "java", "rust":
+		assignNodeVisibility(symbols, root, language)
+	}
+}
+
+// nameVisibility derives visibility from naming conventions
+func nameVisibility(name, language string) string {
+	if name == "" || name == "unknown" {
+		return ""
+	}
+	switch language {
+	case "go":
+		first := []rune(name)[0]
+		if unicode.IsUpper(first) {
+			return types.VisibilityPublic
+		}
+		return types.VisibilityPrivate
+	case "python":
+		// Dunder names like __init__ are public protocol methods
+		if strings.HasPrefix(name, "_") && !(strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__")) {
+			return types.VisibilityPrivate
+		}
+		return types.VisibilityPublic
+	default:
+		if strings.HasPrefix(name, "_") {
+			return types.VisibilityPrivate
+		}
+		return types.VisibilityPublic
+	}
+}
+
+// assignNodeVisibility reads the visibility of each symbol from its
+// declaration node and the node's ancestors
+func assignNodeVisibility(symbols []*types.Symbol, root *types.ASTNode, language string) {
+	exportedNames := make(map[string]bool)
+	if root != nil && language != "java" && language != "rust" {
+		collectExportedNames(root, exportedNames)
+	}
+
+	pending := func(symbol *types.Symbol) bool {
+		return symbol.Visibility == "" && symbol.Type != types.SymbolTypeImport
+	}
+	forEachSymbolNode(symbols, root, pending, func(symbol *types.Symbol, node *types.ASTNode, ancestors []*types.ASTNode) {
+		switch language {
+		case "java":
+			symbol.Visibility = javaVisibility(node, ancestors)
+		case "rust":
+			symbol.Visibility = rustVisibility(node)
+		default:
+			symbol.Visibility = jsVisibility(node, ancestors, symbol.Name, exportedNames)
+		}
+	})
+}
+
+// forEachSymbolNode finds the declaration node of each selected symbol by its
+// position and calls visit with the node and its ancestors
+func forEachSymbolNode(symbols []*types.Symbol, root *types.ASTNode, selected func(*types.Symbol) bool,
+	visit func(symbol *types.Symbol, node *types.ASTNode, ancestors []*types.ASTNode)) {
+	if root == nil {
+		return
+	}
+	byPosition := make(map[string][]*types.Symbol)
+	for _, symbol := range symbols {
+		if selected(symbol) {
+			key := positionKey(symbol.Location.StartLine, symbol.Location.StartColumn)
+			byPosition[key] = append(byPosition[key], symbol)
+		}
+	}
+	if len(byPosition) == 0 {
+		return
+	}
+
+	var walk func(node *types.ASTNode, ancestors []*types.ASTNode)
+	walk = func(node *types.ASTNode, ancestors []*types.ASTNode) {
+		key := positionKey(node.Location.Line, node.Location.Column)
+		// The outermost node at a position is the declaration; keywords and
+		// names nested at the same position come later. Python blocks start
+		// at their first statement, so they are skipped.
+		if matched := byPosition[key]; len(matched) > 0 && node.Type != "block" {
+			delete(byPosition, key)
+			for _, symbol := range matched {
+				visit(symbol, node, ancestors)
+			}
+		}
+		ancestors = append(ancestors, node)
+		for _, child := range node.Children {
+			walk(child, ancestors)
+		}
+	}
+	// The root spans the file and shares its position with the first declaration
+	for _, child := range root.Children {
+		walk(child, []*types.ASTNode{root})
+	}
+}
+
+func positionKey(line, column int) string {
+	return fmt.Sprintf("%d:%d", line, column)
+}
+
+// collectExportedNames gathers local names listed in export clauses, as in
+// "export { a, b as c }". Re-exports from other modules are not local.
+func collectExportedNames(node *types.ASTNode, names map[string]bool) {
+	if node.Type == "export_statement" {
+		hasSource := false
+		for _, child := range node.Children {
+			if child.Type == "from" {
+				hasSource = true
+			}
+		}
+		for _, child := range node.Children {
+			if child.Type != "export_clause" || hasSource {
+				continue
+			}
+			for _, specifier := range child.Children {
+				if specifier.Type == "export_specifier" && len(specifier.Children) > 0 {
+					names[specifier.Children[0].Value] = true
+				}
+			}
+		}
+		return
+	}
+	for _, child := range node.Children {
+		collectExportedNames(child, names)
+	}
+}
+
+// jsVisibility: declarations inside an export statement or named in an export
+// clause are public. Class members follow their class unless they are
+// #private or marked private/protected; declarations inside function bodies
+// are private.
+func jsVisibility(node *types.ASTNode, ancestors []*types.ASTNode, name string, exportedNames map[string]bool) string {
+	if node.Type == "export_statement" {
+		return types.VisibilityPublic
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		switch ancestors[i].Type {
+		case "export_statement":
+			return types.VisibilityPublic
+		case "statement_block":
+			return types.VisibilityPrivate
+		case "class_body":
+			if isPrivateMember(node) {
+				return types.VisibilityPrivate
+			}
+			if i > 0 {
+				class := ancestors[i-1]
+				return jsVisibility(class, ancestors[:i-1], childName(class), exportedNames)
+			}
+			return types.VisibilityPublic
+		}
+	}
+	if exportedNames[name] {
+		return types.VisibilityPublic
+	}
+	return types.VisibilityPrivate
+}
+
+func isPrivateMember(node *types.ASTNode) bool {
+	if strings.HasPrefix(node.Value, "private ") || strings.HasPrefix(node.Value, "protected ") {
+		return true
+	}
+	for _, child := range node.Children {
+		if child.Type == "private_property_identifier" {
+			return true
+		}
+	}
+	return false
+}
+
+// childName returns the identifier naming a declaration node
+func childName(node *types.ASTNode) string {
+	for _, child := range node.Children {
+		if child.Type == "identifier" || child.Type == "type_identifier" {
+			return child.Value
+		}
+	}
+	return ""
+}
+
+// javaVisibility reads the access modifier. Members of interfaces are public
+// without one; everything else defaults to package-private.
+func javaVisibility(node *types.ASTNode, ancestors []*types.ASTNode) string {
+	for _, child := range node.Children {
+		if child.Type != "modifiers" {
+			continue
+		}
+		for _, modifier := range child.Children {
+			switch modifier.Type {
+			case "public", "private", "protected":
+				return modifier.Type
+			}
+		}
+	}
+	if len(ancestors) > 0 && ancestors[len(ancestors)-1].Type == "interface_body" {
+		return types.VisibilityPublic
+	}
+	return types.VisibilityPackage
+}
+
+// rustVisibility reads the pub modifier: pub is public, restricted forms like
+// pub(crate) are internal and items without one are private
+func rustVisibility(node *types.ASTNode) string {
+	for _, child := range node.Children {
+		if child.Type == "visibility_modifier" {
+			if strings.TrimSpace(child.Value) == "pub" {
+				return types.VisibilityPublic
+			}
+			return types.VisibilityInternal
+		}
+	}
+	return types.VisibilityPrivate
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolVisibility(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    map[string]string // symbol name -> visibility
+	}{
+		{
+			file:    "store.ts",
+			content: "export function load() { function parse() {} }\nfunction save() {}\nconst cache = 1;\nexport const VERSION = 2;\nexport default class Store {\n  get() {}\n  #reset() {}\n}\nclass Helper { run() {} }\nexport { cache };\n",
+			want: map[string]string{
+				"load":    types.VisibilityPublic,
+				"parse":   types.VisibilityPrivate,
+				"save":    types.VisibilityPrivate,
+				"cache":   types.VisibilityPublic,
+				"VERSION": types.VisibilityPublic,
+				"Store":   types.VisibilityPublic,
+				"get":     types.VisibilityPublic,
+				"Helper":  types.VisibilityPrivate,
+				"run":     types.VisibilityPrivate,
+			},
+		},
+		{
+			file:    "store.go",
+			content: "package store\n\ntype Store struct{}\n\nfunc New() *Store { return nil }\nfunc load() {}\n",
+			want: map[string]string{
+				"Store": types.VisibilityPublic,
+				"New":   types.VisibilityPublic,
+				"load":  types.VisibilityPrivate,
+			},
+		},
+		{
+			file:    "store.py",
+			content: "class Store:\n    def __init__(self):\n        pass\n\n    def _load(self):\n        pass\n\ndef _helper():\n    pass\n",
+			want: map[string]string{
+				"Store":    types.VisibilityPublic,
+				"__init__": types.VisibilityPublic,
+				"_load":    types.VisibilityPrivate,
+				"_helper":  types.VisibilityPrivate,
+			},
+		},
+		{
+			file:    "store.dart",
+			content: "class Store {}\nclass _Cache {}\n",
+			want: map[string]string{
+				"Store":  types.VisibilityPublic,
+				"_Cache": types.VisibilityPrivate,
+			},
+		},
+		{
+			file:    "Store.java",
+			content: "public class Store {\n  void load() {}\n  public void save() {}\n  protected void flush() {}\n  private void reset() {}\n}\ninterface Loader { void read(); }\n",
+			want: map[string]string{
+				"Store":  types.VisibilityPublic,
+				"load":   types.VisibilityPackage,
+				"save":   types.VisibilityPublic,
+				"flush":  types.VisibilityProtected,
+				"reset":  types.VisibilityPrivate,
+				"Loader": types.VisibilityPackage,
+				"read":   types.VisibilityPublic,
+			},
+		},
+		{
+			file:    "store.rs",
+			content: "pub fn open() {}\nfn close() {}\npub(crate) struct Store {}\n",
+			want: map[string]string{
+				"open":  types.VisibilityPublic,
+				"close": types.VisibilityPrivate,
+				"Store": types.VisibilityInternal,
+			},
+		},
+	}
+
+	manager := NewManager()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			ast, err := manager.Parse(tt.content, tt.file)
+			require.NoError(t, err)
+			symbols, err := manager.ExtractSymbols(ast)
+			require.NoError(t, err)
+
+			got := make(map[string]string)
+			for _, symbol := range symbols {
+				// JavaScript export statements also produce a namespace symbol
+				if symbol.Type != types.SymbolTypeNamespace {
+					got[symbol.Name] = symbol.Visibility
+				}
+			}
+			for name, want := range tt.want {
+				assert.Equal(t, want, got[name], name)
+			}
+		})
+	}
+}
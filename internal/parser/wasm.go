@@ -0,0 +1,252 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// ErrWASMUnsupported is returned when WASM grammars are configured but the binary was
+// built without WASM support
+var ErrWASMUnsupported = errors.New("WASM grammars require a build with -tags tree_sitter_wasm and the wasmtime library")
+
+// WASMGrammarConfig configures tree-sitter grammars compiled to WASM that are loaded at
+// runtime, for languages without compiled-in bindings
+type WASMGrammarConfig struct {
+	Dir        string            `json:"dir" mapstructure:"dir"`               // Directory holding tree-sitter-<name>.wasm files
+	Extensions map[string]string `json:"extensions" mapstructure:"extensions"` // File extension -> grammar name, e.g. ".rb": "ruby"
+}
+
+// GrammarPath returns the file the named grammar is loaded from
+func (c WASMGrammarConfig) GrammarPath(name string) string {
+	return filepath.Join(c.Dir, "tree-sitter-"+name+".wasm")
+}
+
+// grammarNamePattern matches the names accepted for WASM grammars; tree-sitter uses the
+// name to find the tree_sitter_<name> export inside the module
+var grammarNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// LoadWASMGrammars loads the configured grammars and maps their file extensions to them.
+// Configured extensions take precedence over the built-in ones. Grammars that fail to
+// load are skipped and their errors returned together.
+func (m *Manager) LoadWASMGrammars(config WASMGrammarConfig) error {
+	if len(config.Extensions) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(config.Extensions))
+	for _, name := range config.Extensions {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var errs []error
+	loaded := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !grammarNamePattern.MatchString(name) {
+			errs = append(errs, fmt.Errorf("invalid WASM grammar name %q", name))
+			continue
+		}
+		m.mu.RLock()
+		_, builtin := m.languages[name]
+		builtin = builtin && !m.wasmLanguages[name]
+		m.mu.RUnlock()
+		if builtin {
+			errs = append(errs, fmt.Errorf("WASM grammar %s conflicts with a built-in language", name))
+			continue
+		}
+
+		wasm, err := os.ReadFile(config.GrammarPath(name))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read WASM grammar %s: %w", name, err))
+			continue
+		}
+		language, parser, err := loadWASMLanguage(name, wasm)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to load WASM grammar %s: %w", name, err))
+			continue
+		}
+
+		m.mu.Lock()
+		if old := m.parsers[name]; old != nil {
+			old.Close()
+		}
+		m.languages[name] = language
+		m.parsers[name] = parser
+		if m.wasmLanguages == nil {
+			m.wasmLanguages = make(map[string]bool)
+		}
+		m.wasmLanguages[name] = true
+		m.mu.Unlock()
+		loaded[name] = true
+	}
+
+	m.mu.Lock()
+	if m.wasmExtensions == nil {
+		m.wasmExtensions = make(map[string]string)
+	}
+	for ext, name := range config.Extensions {
+		if loaded[name] {
+			m.wasmExtensions[normalizeExtension(ext)] = name
+		}
+	}
+	m.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// HasWASMExtension reports whether files with the extension are parsed by a WASM grammar
+func (m *Manager) HasWASMExtension(ext string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.wasmExtensions[ext]
+	return ok
+}
+
+// detectWASMLanguage returns the WASM grammar language mapped to the extension, if any
+func (m *Manager) detectWASMLanguage(ext string) *types.Language {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name, ok := m.wasmExtensions[ext]
+	if !ok {
+		return nil
+	}
+	var extensions []string
+	for e, n := range m.wasmExtensions {
+		if n == name {
+			extensions = append(extensions, e)
+		}
+	}
+	sort.Strings(extensions)
+
+	return &types.Language{
+		Name:       name,
+		Extensions: extensions,
+		Parser:     "tree-sitter-" + name + ".wasm",
+		Enabled:    true,
+	}
+}
+
+// isWASMLanguage reports whether the language is backed by a WASM grammar
+func (m *Manager) isWASMLanguage(language string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.wasmLanguages[language]
+}
+
+// genericSymbolKinds maps the words found in declaration node types across tree-sitter
+// grammars to symbol types
+var genericSymbolKinds = map[string]types.SymbolType{
+	"function":  types.SymbolTypeFunction,
+	"func":      types.SymbolTypeFunction,
+	"fn":        types.SymbolTypeFunction,
+	"method":    types.SymbolTypeMethod,
+	"class":     types.SymbolTypeClass,
+	"struct":    types.SymbolTypeClass,
+	"module":    types.SymbolTypeClass,
+	"impl":      types.SymbolTypeClass,
+	"object":    types.SymbolTypeClass,
+	"interface": types.SymbolTypeInterface,
+	"trait":     types.SymbolTypeInterface,
+	"protocol":  types.SymbolTypeInterface,
+	"enum":      types.SymbolTypeType,
+	"type":      types.SymbolTypeType,
+	"union":     types.SymbolTypeType,
+}
+
+// genericDeclarationSuffixes end the node types grammars use for declarations
+var genericDeclarationSuffixes = map[string]bool{
+	"definition":  true,
+	"declaration": true,
+	"item":        true,
+	"statement":   true,
+}
+
+// nodeToSymbolGeneric extracts symbols for languages loaded from WASM grammars, for which
+// there are no language-specific rules. Declarations are recognized by naming
+// conventions shared by most grammars: "function_definition", "class_declaration",
+// "struct_item", or a bare "method" / "class" / "module" node.
+func (m *Manager) nodeToSymbolGeneric(node *types.ASTNode, filePath, language string) *types.Symbol {
+	// Anonymous keyword tokens share their kind with declarations (e.g. Ruby "class")
+	if len(node.Children) == 0 {
+		return nil
+	}
+
+	symbolType, ok := genericSymbolType(node.Type)
+	if !ok {
+		return nil
+	}
+	name := genericSymbolName(node)
+	if name == "" {
+		name = m.extractSymbolName(node)
+	}
+	if name == "" || name == "unknown" {
+		return nil
+	}
+
+	symbol := &types.Symbol{
+		Id:           types.SymbolId(fmt.Sprintf("%s-%s-%d", symbolType, filePath, node.Location.Line)),
+		Name:         name,
+		Type:         symbolType,
+		Location:     convertLocation(node.Location),
+		Language:     language,
+		Hash:         calculateHash(node.Value),
+		LastModified: time.Now(),
+	}
+	if node.Location.EndLine > node.Location.Line {
+		symbol.Location.EndLine = node.Location.EndLine
+	}
+	if symbolType == types.SymbolTypeFunction || symbolType == types.SymbolTypeMethod {
+		symbol.Signature = m.extractFunctionSignature(node)
+	}
+	return symbol
+}
+
+// genericSymbolType classifies a node type such as "function_definition" or "method"
+func genericSymbolType(nodeType string) (types.SymbolType, bool) {
+	words := strings.Split(nodeType, "_")
+	last := words[len(words)-1]
+	if genericDeclarationSuffixes[last] {
+		for _, word := range words[:len(words)-1] {
+			if symbolType, ok := genericSymbolKinds[word]; ok {
+				return symbolType, true
+			}
+		}
+		return "", false
+	}
+	// Bare kinds only; "function_type" or "enum_body" are not declarations
+	if last == "type" || last == "object" {
+		return "", false
+	}
+	symbolType, ok := genericSymbolKinds[last]
+	return symbolType, ok
+}
+
+// genericSymbolName returns the first identifier-like child of a declaration
+func genericSymbolName(node *types.ASTNode) string {
+	for _, child := range node.Children {
+		if strings.HasSuffix(child.Type, "identifier") || child.Type == "name" || child.Type == "constant" {
+			return strings.TrimSpace(child.Value)
+		}
+	}
+	return ""
+}
+
+// normalizeExtension makes sure a configured extension starts with a dot
+func normalizeExtension(ext string) string {
+	if !strings.HasPrefix(ext, ".") {
+		return "." + ext
+	}
+	return ext
+}
@@ -0,0 +1,10 @@
+//go:build !tree_sitter_wasm
+
+package parser
+
+import sitter "github.com/tree-sitter/go-tree-sitter"
+
+// loadWASMLanguage is unavailable in default builds, which do not link wasmtime
+func loadWASMLanguage(name string, wasm []byte) (*sitter.Language, *sitter.Parser, error) {
+	return nil, nil, ErrWASMUnsupported
+}
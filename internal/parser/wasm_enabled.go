@@ -0,0 +1,140 @@
+//go:build tree_sitter_wasm
+
+package parser
+
+// Building with WASM support requires the tree-sitter C sources to be compiled with
+// TREE_SITTER_FEATURE_WASM and the wasmtime C API to be available:
+//
+//	CGO_CFLAGS="-DTREE_SITTER_FEATURE_WASM -I$WASMTIME/include" \
+//	CGO_LDFLAGS="-L$WASMTIME/lib -lwasmtime" \
+//	go build -tags tree_sitter_wasm ./...
+
+/*
+#cgo LDFLAGS: -lwasmtime
+#include <stdint.h>
+#include <stdlib.h>
+#include <wasm.h>
+
+// Declared here because go-tree-sitter does not expose its headers to other packages
+typedef struct TSLanguage TSLanguage;
+typedef struct TSParser TSParser;
+typedef struct TSWasmStore TSWasmStore;
+typedef struct {
+	int kind;
+	char *message;
+} TSWasmError;
+
+TSWasmStore *ts_wasm_store_new(wasm_engine_t *engine, TSWasmError *error);
+void ts_wasm_store_delete(TSWasmStore *store);
+const TSLanguage *ts_wasm_store_load_language(TSWasmStore *store, const char *name, const char *wasm, uint32_t wasm_len, TSWasmError *error);
+void ts_parser_set_wasm_store(TSParser *parser, TSWasmStore *store);
+const TSLanguage *ts_parser_language(const TSParser *parser);
+*/
+import "C"
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unsafe"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+var (
+	wasmEngineOnce sync.Once
+	wasmEngine     *C.wasm_engine_t
+)
+
+// loadWASMLanguage compiles a grammar and returns it with a parser owning the WASM store
+// the grammar runs in
+func loadWASMLanguage(name string, wasm []byte) (*sitter.Language, *sitter.Parser, error) {
+	if len(wasm) == 0 {
+		return nil, nil, fmt.Errorf("empty WASM module")
+	}
+	wasmEngineOnce.Do(func() {
+		wasmEngine = C.wasm_engine_new()
+	})
+	if wasmEngine == nil {
+		return nil, nil, fmt.Errorf("failed to create wasmtime engine")
+	}
+
+	var wasmErr C.TSWasmError
+	store := C.ts_wasm_store_new(wasmEngine, &wasmErr)
+	if store == nil {
+		return nil, nil, wasmError("failed to create WASM store", &wasmErr)
+	}
+
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	cWasm := C.CBytes(wasm)
+	defer C.free(cWasm)
+
+	language := C.ts_wasm_store_load_language(store, cName, (*C.char)(cWasm), C.uint32_t(len(wasm)), &wasmErr)
+	if language == nil {
+		C.ts_wasm_store_delete(store)
+		return nil, nil, wasmError("failed to instantiate grammar", &wasmErr)
+	}
+
+	// go-tree-sitter has no API for WASM stores, so the store is set on the C parser
+	// it wraps. The parser takes ownership of the store and frees it on Close.
+	parser := sitter.NewParser()
+	handle, err := parserHandle(parser)
+	if err != nil {
+		parser.Close()
+		C.ts_wasm_store_delete(store)
+		return nil, nil, err
+	}
+	C.ts_parser_set_wasm_store(handle, store)
+
+	sitterLanguage := sitter.NewLanguage(unsafe.Pointer(language))
+	if err := parser.SetLanguage(sitterLanguage); err != nil {
+		parser.Close()
+		return nil, nil, err
+	}
+	if err := checkParserHandle(parser, unsafe.Pointer(language)); err != nil {
+		parser.Close()
+		return nil, nil, err
+	}
+	return sitterLanguage, parser, nil
+}
+
+// parserHandle returns the C parser a go-tree-sitter Parser wraps. The package
+// has no accessor for it, so this relies on Parser holding the pointer as its
+// only field; the layout is checked first so a dependency update changing it
+// fails loading instead of corrupting memory.
+func parserHandle(parser *sitter.Parser) (*C.TSParser, error) {
+	t := reflect.TypeOf(*parser)
+	if t.NumField() != 1 || t.Size() != unsafe.Sizeof(uintptr(0)) {
+		return nil, fmt.Errorf("unsupported go-tree-sitter Parser layout %v: WASM grammars need a Parser holding only the C parser", t)
+	}
+	field := t.Field(0)
+	if field.Type.Kind() != reflect.Pointer || !strings.HasSuffix(field.Type.Elem().Name(), "TSParser") {
+		return nil, fmt.Errorf("unsupported go-tree-sitter Parser field %s %v: WASM grammars need the C parser", field.Name, field.Type)
+	}
+	return *(**C.TSParser)(unsafe.Pointer(parser)), nil
+}
+
+// checkParserHandle confirms the C parser of parserHandle is the one the Parser
+// uses, by comparing the language set through the Parser with the language
+// the C parser reports
+func checkParserHandle(parser *sitter.Parser, language unsafe.Pointer) error {
+	handle, err := parserHandle(parser)
+	if err != nil {
+		return err
+	}
+	if unsafe.Pointer(C.ts_parser_language(handle)) != language {
+		return fmt.Errorf("go-tree-sitter Parser does not wrap the expected C parser")
+	}
+	return nil
+}
+
+// wasmError converts and frees a tree-sitter WASM error
+func wasmError(message string, wasmErr *C.TSWasmError) error {
+	if wasmErr.message == nil {
+		return fmt.Errorf("%s", message)
+	}
+	defer C.free(unsafe.Pointer(wasmErr.message))
+	return fmt.Errorf("%s: %s", message, C.GoString(wasmErr.message))
+}
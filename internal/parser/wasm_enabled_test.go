@@ -0,0 +1,26 @@
+//go:build tree_sitter_wasm
+
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	golang "github.com/tree-sitter/tree-sitter-go/bindings/go"
+)
+
+// TestParserHandle checks the go-tree-sitter Parser layout loadWASMLanguage
+// relies on to set WASM stores
+func TestParserHandle(t *testing.T) {
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	handle, err := parserHandle(parser)
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+
+	language := golang.Language()
+	require.NoError(t, parser.SetLanguage(sitter.NewLanguage(language)))
+	require.NoError(t, checkParserHandle(parser, language))
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadWASMGrammarsErrors(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tree-sitter-ruby.wasm"), []byte("\x00asm"), 0644))
+
+	manager := NewManager()
+	assert.NoError(t, manager.LoadWASMGrammars(WASMGrammarConfig{Dir: dir}))
+
+	err := manager.LoadWASMGrammars(WASMGrammarConfig{
+		Dir: dir,
+		Extensions: map[string]string{
+			".rb":  "ruby",   // Not a valid module (or no WASM support in this build)
+			".lua": "lua",    // Missing file
+			".gox": "go",     // Built-in language
+			".x":   "Bad-Na", // Invalid name
+		},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load WASM grammar ruby")
+	assert.Contains(t, err.Error(), "failed to read WASM grammar lua")
+	assert.Contains(t, err.Error(), "conflicts with a built-in language")
+	assert.Contains(t, err.Error(), "invalid WASM grammar name")
+
+	for _, ext := range []string{".rb", ".lua", ".gox", ".x"} {
+		assert.False(t, manager.HasWASMExtension(ext), ext)
+	}
+	assert.Equal(t, "go", manager.detectLanguage("main.go").Name)
+}
+
+func TestWASMLanguageDetection(t *testing.T) {
+	manager := NewManager()
+	// Simulate a loaded grammar; loading itself needs a wasmtime build
+	manager.wasmLanguages = map[string]bool{"ruby": true}
+	manager.wasmExtensions = map[string]string{".rb": "ruby", ".rake": "ruby", ".h": "ruby"}
+
+	assert.True(t, manager.HasWASMExtension(".rb"))
+	lang := manager.detectLanguage("app/models/user.rb")
+	require.NotNil(t, lang)
+	assert.Equal(t, "ruby", lang.Name)
+	assert.Equal(t, []string{".h", ".rake", ".rb"}, lang.Extensions)
+	assert.Equal(t, "tree-sitter-ruby.wasm", lang.Parser)
+	assert.Equal(t, "ruby", manager.detectLanguage("legacy.h").Name, "configured extensions override built-in ones")
+	assert.Equal(t, "python", manager.detectLanguage("script.py").Name)
+	assert.Equal(t, ".rb", normalizeExtension("rb"))
+}
+
+func TestGenericSymbolType(t *testing.T) {
+	tests := []struct {
+		nodeType string
+		expected types.SymbolType
+		ok       bool
+	}{
+		{"function_definition", types.SymbolTypeFunction, true},
+		{"function_declaration", types.SymbolTypeFunction, true},
+		{"method", types.SymbolTypeMethod, true},
+		{"singleton_method", types.SymbolTypeMethod, true},
+		{"class", types.SymbolTypeClass, true},
+		{"struct_item", types.SymbolTypeClass, true},
+		{"module", types.SymbolTypeClass, true},
+		{"trait_item", types.SymbolTypeInterface, true},
+		{"enum_declaration", types.SymbolTypeType, true},
+		{"type_alias_declaration", types.SymbolTypeType, true},
+		{"function_type", "", false},
+		{"enum_body", "", false},
+		{"import_statement", "", false},
+		{"method_invocation", "", false},
+		{"identifier", "", false},
+	}
+
+	for _, tt := range tests {
+		symbolType, ok := genericSymbolType(tt.nodeType)
+		assert.Equal(t, tt.ok, ok, tt.nodeType)
+		assert.Equal(t, tt.expected, symbolType, tt.nodeType)
+	}
+}
+
+func TestNodeToSymbolGeneric(t *testing.T) {
+	manager := NewManager()
+	manager.wasmLanguages = map[string]bool{"ruby": true}
+
+	method := &types.ASTNode{
+		Type:     "method",
+		Value:    "def full_name\n  \"#{first} #{last}\"\nend",
+		Location: types.FileLocation{Line: 3, Column: 3, EndLine: 5},
+		Children: []*types.ASTNode{
+			{Type: "def", Value: "def"},
+			{Type: "identifier", Value: "full_name"},
+		},
+	}
+	symbol := manager.nodeToSymbolWithContent(method, "user.rb", "ruby", method.Value)
+	require.NotNil(t, symbol)
+	assert.Equal(t, "full_name", symbol.Name)
+	assert.Equal(t, types.SymbolTypeMethod, symbol.Type)
+	assert.Equal(t, 3, symbol.Location.StartLine)
+	assert.Equal(t, 5, symbol.Location.EndLine)
+
+	class := &types.ASTNode{
+		Type:     "class",
+		Value:    "class User < ApplicationRecord\nend",
+		Location: types.FileLocation{Line: 1, Column: 1},
+		Children: []*types.ASTNode{
+			{Type: "class", Value: "class"},
+			{Type: "constant", Value: "User"},
+		},
+	}
+	symbol = manager.nodeToSymbolWithContent(class, "user.rb", "ruby", class.Value)
+	require.NotNil(t, symbol)
+	assert.Equal(t, "User", symbol.Name)
+	assert.Equal(t, types.SymbolTypeClass, symbol.Type)
+
+	// The "class" keyword token itself is not a declaration
+	assert.Nil(t, manager.nodeToSymbolWithContent(class.Children[0], "user.rb", "ruby", "class"))
+}
@@ -0,0 +1,52 @@
+// Package pathglob matches slash-separated paths against globs where "**" spans
+// directories, as used by config options such as rules and redaction paths.
+package pathglob
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MatchAny reports whether name matches any of the globs
+func MatchAny(globs []string, name string) bool {
+	for _, glob := range globs {
+		if Match(glob, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match matches a path against a glob where "*" stays within a segment and "**"
+// spans any number of segments, including none
+func Match(glob, name string) bool {
+	return matchSegments(strings.Split(glob, "/"), strings.Split(filepath.ToSlash(name), "/"))
+}
+
+// Valid reports whether every segment of the glob is a well-formed pattern
+func Valid(glob string) bool {
+	_, err := path.Match(strings.ReplaceAll(glob, "**", "*"), "")
+	return err == nil
+}
+
+func matchSegments(glob, name []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(glob[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(glob[0], name[0]); err != nil || !ok {
+			return false
+		}
+		glob, name = glob[1:], name[1:]
+	}
+	return len(name) == 0
+}
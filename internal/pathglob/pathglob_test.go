@@ -0,0 +1,23 @@
+package pathglob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	assert.True(t, Match("**/*.go", "main.go"))
+	assert.True(t, Match("**/*.go", "a/b/main.go"))
+	assert.True(t, Match("internal/**", "internal/x/y.go"))
+	assert.True(t, Match("a/**/c", "a/c"))
+	assert.False(t, Match("*.go", "a/main.go"))
+	assert.False(t, Match("internal/**/*.go", "cmd/main.go"))
+	assert.True(t, Match("os/*", "os/exec"))
+
+	assert.True(t, MatchAny([]string{"*.ts", "**/*.go"}, "x/y.go"))
+	assert.False(t, MatchAny(nil, "x/y.go"))
+
+	assert.True(t, Valid("src/**/*.go"))
+	assert.False(t, Valid("[a"))
+}
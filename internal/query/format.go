@@ -0,0 +1,121 @@
+package query
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Record is the JSON form of a matched entity
+type Record struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+// Records returns one map per row, keyed by column
+func (r *Result) Records() []map[string]Record {
+	records := make([]map[string]Record, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		record := make(map[string]Record, len(row.Nodes))
+		for i, entity := range row.Nodes {
+			record[r.Columns[i]] = entity.record()
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Markdown renders the result as a table
+func (r *Result) Markdown() string {
+	var out strings.Builder
+	out.WriteString("# Query Results\n\n")
+	if r.Query != nil && r.Query.Source != "" {
+		out.WriteString(fmt.Sprintf("`%s`\n\n", r.Query.Source))
+	}
+	out.WriteString(r.table())
+	return out.String()
+}
+
+// table renders the match count and the rows
+func (r *Result) table() string {
+	var out strings.Builder
+	if len(r.Rows) == 0 {
+		out.WriteString("_No matches_\n")
+		return out.String()
+	}
+
+	out.WriteString(fmt.Sprintf("**Matches:** %d", len(r.Rows)))
+	if r.Truncated {
+		out.WriteString(fmt.Sprintf(" (limited to %d; add a LIMIT clause to see more)", len(r.Rows)))
+	}
+	out.WriteString("\n\n")
+
+	// Edge types are only worth a column when the pattern left them open
+	showEdges := false
+	if r.Query != nil {
+		for _, edge := range r.Query.Edges {
+			showEdges = showEdges || edge.Type == "*"
+		}
+	}
+
+	header := append([]string{}, r.Columns...)
+	if showEdges {
+		header = append(header, "edges")
+	}
+	out.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	out.WriteString("|" + strings.Repeat("---|", len(header)) + "\n")
+	for _, row := range r.Rows {
+		cells := make([]string, 0, len(header))
+		for _, entity := range row.Nodes {
+			cells = append(cells, entity.describe())
+		}
+		if showEdges {
+			edgeTypes := make([]string, len(row.Edges))
+			for i, edge := range row.Edges {
+				edgeTypes[i] = edge.Type
+			}
+			cells = append(cells, strings.Join(edgeTypes, " → "))
+		}
+		out.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return out.String()
+}
+
+func (e *Entity) record() Record {
+	record := Record{ID: string(e.ID), Kind: e.Kind, Name: e.Name(), File: e.Path()}
+	if e.Symbol != nil {
+		record.Kind = string(e.Symbol.Type)
+		record.Line = e.Symbol.Location.StartLine
+	}
+	return record
+}
+
+// describe renders an entity for a table cell
+func (e *Entity) describe() string {
+	record := e.record()
+	if e.File != nil {
+		return fmt.Sprintf("`%s`", record.File)
+	}
+	text := fmt.Sprintf("`%s` (%s", record.Name, record.Kind)
+	if record.File != "" {
+		text += ", " + record.File
+		if record.Line > 0 {
+			text += fmt.Sprintf(":%d", record.Line)
+		}
+	}
+	return strings.ReplaceAll(text+")", "|", "\\|")
+}
+
+// RelativePath makes path relative to baseDir when it lies inside it
+func RelativePath(path, baseDir string) string {
+	if path == "" || baseDir == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(baseDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
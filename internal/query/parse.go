@@ -0,0 +1,477 @@
+// Package query implements a small Cypher-like query language over the code graph, for
+// ad-hoc analysis without a bespoke tool per question:
+//
+//	MATCH a:file->imports->b:file WHERE b.path CONTAINS 'auth' RETURN a LIMIT 20
+//
+// A pattern alternates node kinds and edge types. Node kinds are "file", "symbol", a
+// symbol type such as "function" or "class", any other graph node type, or "*". Edge
+// types are graph relationship types ("imports", "calls", "extends", ...) or "*"; "contains"
+// also links files to the symbols they declare. Arrows may point either way:
+// "file<-imports<-file" matches files imported by the first file's importers.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultLimit caps the number of rows when a query has no LIMIT clause
+const DefaultLimit = 100
+
+// Query is a parsed graph query
+type Query struct {
+	Nodes  []NodeSpec // Pattern nodes, in order
+	Edges  []EdgeSpec // Edges between consecutive nodes
+	Where  Expr       // Optional filter
+	Return []string   // Returned node aliases; all nodes when empty
+	Limit  int
+	Source string
+}
+
+// NodeSpec is a node in a query pattern
+type NodeSpec struct {
+	Alias string
+	Kind  string // "*" matches any node
+}
+
+// EdgeSpec is an edge between two pattern nodes
+type EdgeSpec struct {
+	Type     string // "*" matches any edge type
+	Incoming bool   // Written as "<-type<-": the edge points from the next node to the previous one
+}
+
+// Expr is a WHERE expression evaluated against a bound row of nodes
+type Expr interface {
+	eval(row map[string]*Entity) bool
+}
+
+type andExpr struct{ left, right Expr }
+type orExpr struct{ left, right Expr }
+type notExpr struct{ expr Expr }
+
+// condition compares a node field with a literal
+type condition struct {
+	alias string
+	field string
+	op    string
+	value literal
+	regex *regexp.Regexp // MATCHES
+}
+
+type literal struct {
+	text    string
+	number  float64
+	numeric bool
+}
+
+func (e andExpr) eval(row map[string]*Entity) bool { return e.left.eval(row) && e.right.eval(row) }
+func (e orExpr) eval(row map[string]*Entity) bool  { return e.left.eval(row) || e.right.eval(row) }
+func (e notExpr) eval(row map[string]*Entity) bool { return !e.expr.eval(row) }
+
+// token kinds
+const (
+	tokenWord = iota
+	tokenString
+	tokenNumber
+	tokenSymbol // Operators, arrows and punctuation
+	tokenEOF
+)
+
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+// Parse parses a query
+func Parse(source string) (*Query, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	q, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	q.Source = strings.TrimSpace(source)
+	return q, nil
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			start := i
+			var text strings.Builder
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				text.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, text: text.String(), pos: start})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case isWordRune(r) || r == '*' && (i+1 >= len(runes) || runes[i+1] != '='):
+			start := i
+			if r == '*' {
+				i++
+			} else {
+				// Edge types contain dashes ("calls-service"), but "->" ends the word
+				for i < len(runes) && (isWordRune(runes[i]) || runes[i] == '-' && !(i+1 < len(runes) && runes[i+1] == '>')) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch two {
+			case "->", "<-", "!=", ">=", "<=", "<>":
+				tokens = append(tokens, token{kind: tokenSymbol, text: two, pos: start})
+				i += 2
+				continue
+			}
+			if !strings.ContainsRune("=<>:.,()", r) {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, start)
+			}
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r), pos: start})
+			i++
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+type queryParser struct {
+	tokens []token
+	pos    int
+	query  *Query
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword consumes the next token if it is the given keyword
+func (p *queryParser) keyword(word string) bool {
+	t := p.peek()
+	if t.kind == tokenWord && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// symbol consumes the next token if it is the given symbol
+func (p *queryParser) symbol(text string) bool {
+	t := p.peek()
+	if t.kind == tokenSymbol && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	near := t.text
+	if t.kind == tokenEOF {
+		near = "end of query"
+	}
+	return fmt.Errorf("%s (near %q at position %d)", fmt.Sprintf(format, args...), near, t.pos)
+}
+
+func (p *queryParser) parseQuery() (*Query, error) {
+	p.query = &Query{Limit: DefaultLimit}
+	if !p.keyword("MATCH") {
+		return nil, p.errorf("query must start with MATCH")
+	}
+	if err := p.parsePattern(); err != nil {
+		return nil, err
+	}
+
+	if p.keyword("WHERE") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.query.Where = expr
+	}
+
+	if p.keyword("RETURN") {
+		for {
+			t := p.next()
+			if t.kind != tokenWord || p.nodeIndex(t.text) < 0 {
+				p.pos--
+				return nil, p.errorf("RETURN expects node names")
+			}
+			p.query.Return = append(p.query.Return, t.text)
+			if !p.symbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.keyword("LIMIT") {
+		t := p.next()
+		limit, err := strconv.Atoi(t.text)
+		if t.kind != tokenNumber || err != nil || limit <= 0 {
+			p.pos--
+			return nil, p.errorf("LIMIT expects a positive number")
+		}
+		p.query.Limit = limit
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("unexpected input")
+	}
+	return p.query, nil
+}
+
+func (p *queryParser) parsePattern() error {
+	for {
+		node, err := p.parseNode()
+		if err != nil {
+			return err
+		}
+		p.query.Nodes = append(p.query.Nodes, node)
+
+		var arrow string
+		switch {
+		case p.symbol("->"):
+			arrow = "->"
+		case p.symbol("<-"):
+			arrow = "<-"
+		default:
+			return nil
+		}
+		t := p.next()
+		if t.kind != tokenWord {
+			p.pos--
+			return p.errorf("expected an edge type")
+		}
+		if !p.symbol(arrow) {
+			return p.errorf("expected %q after edge type %s", arrow, t.text)
+		}
+		p.query.Edges = append(p.query.Edges, EdgeSpec{Type: strings.ToLower(t.text), Incoming: arrow == "<-"})
+	}
+}
+
+// parseNode parses "kind" or "alias:kind". Unnamed nodes are named after their kind,
+// with a number appended when the kind repeats ("file", "file2").
+func (p *queryParser) parseNode() (NodeSpec, error) {
+	t := p.next()
+	if t.kind != tokenWord {
+		p.pos--
+		return NodeSpec{}, p.errorf("expected a node kind")
+	}
+	node := NodeSpec{Kind: strings.ToLower(t.text)}
+	if p.symbol(":") {
+		kind := p.next()
+		if kind.kind != tokenWord {
+			p.pos--
+			return NodeSpec{}, p.errorf("expected a node kind after %s:", t.text)
+		}
+		node.Alias = t.text
+		node.Kind = strings.ToLower(kind.text)
+	} else {
+		node.Alias = node.Kind
+		if node.Kind == "*" {
+			node.Alias = "node"
+		}
+		base := node.Alias
+		for n := 2; p.nodeIndex(node.Alias) >= 0; n++ {
+			node.Alias = fmt.Sprintf("%s%d", base, n)
+		}
+	}
+	if p.nodeIndex(node.Alias) >= 0 {
+		p.pos--
+		return NodeSpec{}, p.errorf("duplicate node name %s", node.Alias)
+	}
+	return node, nil
+}
+
+func (p *queryParser) nodeIndex(alias string) int {
+	for i, node := range p.query.Nodes {
+		if node.Alias == alias {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *queryParser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (Expr, error) {
+	if p.keyword("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+	if p.symbol("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.symbol(")") {
+			return nil, p.errorf("expected )")
+		}
+		return expr, nil
+	}
+	return p.parseCondition()
+}
+
+// parseCondition parses "[alias.]field op value"; unqualified fields refer to the first node
+func (p *queryParser) parseCondition() (Expr, error) {
+	t := p.next()
+	if t.kind != tokenWord {
+		p.pos--
+		return nil, p.errorf("expected a field name")
+	}
+	cond := &condition{alias: p.query.Nodes[0].Alias, field: strings.ToLower(t.text)}
+	if p.symbol(".") {
+		if p.nodeIndex(t.text) < 0 {
+			p.pos -= 2
+			return nil, p.errorf("unknown node %s", t.text)
+		}
+		field := p.next()
+		if field.kind != tokenWord {
+			p.pos--
+			return nil, p.errorf("expected a field name after %s.", t.text)
+		}
+		cond.alias = t.text
+		cond.field = strings.ToLower(field.text)
+	}
+
+	op, err := p.parseOperator()
+	if err != nil {
+		return nil, err
+	}
+	cond.op = op
+
+	value := p.next()
+	switch value.kind {
+	case tokenString:
+		cond.value = literal{text: value.text}
+	case tokenNumber:
+		number, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("invalid number")
+		}
+		cond.value = literal{text: value.text, number: number, numeric: true}
+	case tokenWord:
+		if !strings.EqualFold(value.text, "true") && !strings.EqualFold(value.text, "false") {
+			p.pos--
+			return nil, p.errorf("expected a quoted string, number or boolean")
+		}
+		cond.value = literal{text: strings.ToLower(value.text)}
+	default:
+		p.pos--
+		return nil, p.errorf("expected a value")
+	}
+
+	if op == "MATCHES" {
+		regex, err := regexp.Compile(cond.value.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MATCHES pattern %q: %w", cond.value.text, err)
+		}
+		cond.regex = regex
+	}
+	return cond, nil
+}
+
+func (p *queryParser) parseOperator() (string, error) {
+	t := p.peek()
+	if t.kind == tokenSymbol {
+		switch t.text {
+		case "=", "!=", "<>", "<", ">", "<=", ">=":
+			p.pos++
+			if t.text == "<>" {
+				return "!=", nil
+			}
+			return t.text, nil
+		}
+	}
+	switch {
+	case p.keyword("CONTAINS"):
+		return "CONTAINS", nil
+	case p.keyword("MATCHES"):
+		return "MATCHES", nil
+	case p.keyword("STARTS"):
+		if !p.keyword("WITH") {
+			return "", p.errorf("expected STARTS WITH")
+		}
+		return "STARTS WITH", nil
+	case p.keyword("ENDS"):
+		if !p.keyword("WITH") {
+			return "", p.errorf("expected ENDS WITH")
+		}
+		return "ENDS WITH", nil
+	}
+	return "", p.errorf("expected a comparison operator")
+}
@@ -0,0 +1,411 @@
+package query
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Entity is a file, symbol or other graph node bound to a pattern node
+type Entity struct {
+	ID     types.NodeId
+	Kind   string // "file", "symbol" or the graph node type
+	File   *types.FileNode
+	Symbol *types.Symbol
+	Node   *types.GraphNode
+	path   string // File path relative to the analyzed directory
+}
+
+// Name returns a short human-readable name for the entity
+func (e *Entity) Name() string {
+	switch {
+	case e.File != nil:
+		return e.path
+	case e.Symbol != nil:
+		return e.Symbol.Name
+	case e.Node != nil && e.Node.Label != "":
+		return e.Node.Label
+	}
+	return string(e.ID)
+}
+
+// Path returns the file the entity belongs to, if any, relative to the analyzed directory
+func (e *Entity) Path() string {
+	return e.path
+}
+
+// Field returns the value of a named field. Files expose path, name, dir, language,
+// lines, size, symbols, imports, test and generated; symbols expose name, kind (the
+// normalized kind), type, file, line, end_line, language, signature, visibility and
+// fqn. Unknown fields, such as a symbol's subtype, fall back to the symbol or node
+// metadata.
+func (e *Entity) Field(name string) (interface{}, bool) {
+	switch name {
+	case "id":
+		return string(e.ID), true
+	case "kind":
+		if e.Symbol != nil {
+			return e.Symbol.NormalizedKind(), true
+		}
+		return e.Kind, true
+	}
+
+	if e.File != nil {
+		switch name {
+		case "path", "file":
+			return e.path, true
+		case "name":
+			return filepath.Base(e.path), true
+		case "dir":
+			return filepath.Dir(e.path), true
+		case "language":
+			return e.File.Language, true
+		case "lines":
+			return e.File.Lines, true
+		case "size":
+			return e.File.Size, true
+		case "symbols":
+			return len(e.File.Symbols), true
+		case "imports":
+			return len(e.File.Imports), true
+		case "test":
+			return e.File.IsTest, true
+		case "generated":
+			return e.File.IsGenerated, true
+		}
+		return nil, false
+	}
+
+	if e.Symbol != nil {
+		switch name {
+		case "name":
+			return e.Symbol.Name, true
+		case "type":
+			return string(e.Symbol.Type), true
+		case "file", "path":
+			return e.Path(), true
+		case "line":
+			return e.Symbol.Location.StartLine, true
+		case "end_line":
+			return e.Symbol.Location.EndLine, true
+		case "language":
+			return e.Symbol.Language, true
+		case "signature":
+			return e.Symbol.Signature, true
+		case "visibility":
+			return e.Symbol.Visibility, true
+		case "fqn":
+			return e.Symbol.FullyQualifiedName, true
+		}
+		if value, ok := e.Symbol.Metadata[name]; ok {
+			return value, true
+		}
+	}
+
+	if e.Node != nil {
+		switch name {
+		case "name", "label":
+			return e.Node.Label, true
+		case "type":
+			return e.Node.Type, true
+		case "file", "path":
+			return e.path, true
+		}
+		if value, ok := e.Node.Metadata[name]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// matchesKind reports whether the entity matches a pattern node kind
+func (e *Entity) matchesKind(kind string) bool {
+	switch {
+	case kind == "*" || kind == "node":
+		return true
+	case kind == e.Kind:
+		return true
+	case e.Symbol != nil:
+		return e.Symbol.MatchesKind(kind)
+	case e.Node != nil:
+		return strings.EqualFold(kind, e.Node.Type)
+	}
+	return false
+}
+
+// Row is one match of the pattern
+type Row struct {
+	Nodes []*Entity          // One per returned column
+	Edges []*types.GraphEdge // Traversed edges; empty when the query has a RETURN clause
+}
+
+// Result holds the rows matched by a query
+type Result struct {
+	Query     *Query
+	Columns   []string
+	Rows      []Row
+	Truncated bool // More rows matched than the limit
+}
+
+// Execute runs a parsed query against the graph. Paths are matched and reported relative
+// to baseDir, the analyzed directory.
+func Execute(graph *types.CodeGraph, q *Query, baseDir string) *Result {
+	idx := newIndex(graph, baseDir)
+
+	columns := q.Return
+	if len(columns) == 0 {
+		for _, node := range q.Nodes {
+			columns = append(columns, node.Alias)
+		}
+	}
+	result := &Result{Query: q, Columns: columns}
+
+	seen := make(map[string]bool)
+	bound := make(map[string]*Entity, len(q.Nodes))
+	path := make([]*types.GraphEdge, 0, len(q.Edges))
+
+	var walk func(position int, entity *Entity) bool
+	walk = func(position int, entity *Entity) bool {
+		if !entity.matchesKind(q.Nodes[position].Kind) {
+			return true
+		}
+		alias := q.Nodes[position].Alias
+		bound[alias] = entity
+		defer delete(bound, alias)
+
+		if position == len(q.Nodes)-1 {
+			if q.Where != nil && !q.Where.eval(bound) {
+				return true
+			}
+			row := Row{}
+			key := make([]string, 0, len(columns))
+			for _, column := range columns {
+				row.Nodes = append(row.Nodes, bound[column])
+				key = append(key, string(bound[column].ID))
+			}
+			if len(q.Return) == 0 {
+				row.Edges = append(row.Edges, path...)
+				for _, edge := range path {
+					key = append(key, string(edge.Id))
+				}
+			}
+			rowKey := strings.Join(key, "\x00")
+			if seen[rowKey] {
+				return true
+			}
+			if len(result.Rows) == q.Limit {
+				result.Truncated = true
+				return false
+			}
+			seen[rowKey] = true
+			result.Rows = append(result.Rows, row)
+			return true
+		}
+
+		spec := q.Edges[position]
+		edges := idx.outgoing[entity.ID]
+		if spec.Incoming {
+			edges = idx.incoming[entity.ID]
+		}
+		for _, edge := range edges {
+			if spec.Type != "*" && !strings.EqualFold(edge.Type, spec.Type) {
+				continue
+			}
+			neighborID := edge.To
+			if spec.Incoming {
+				neighborID = edge.From
+			}
+			neighbor := idx.byID[neighborID]
+			if neighbor == nil {
+				continue
+			}
+			path = append(path, edge)
+			more := walk(position+1, neighbor)
+			path = path[:len(path)-1]
+			if !more {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, entity := range idx.entities {
+		if !walk(0, entity) {
+			break
+		}
+	}
+	return result
+}
+
+// Run parses and executes a query
+func Run(graph *types.CodeGraph, source, baseDir string) (*Result, error) {
+	q, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	return Execute(graph, q, baseDir), nil
+}
+
+// index gives the query engine uniform access to files, symbols and nodes
+type index struct {
+	entities []*Entity // Sorted by ID for stable results
+	byID     map[types.NodeId]*Entity
+	outgoing map[types.NodeId][]*types.GraphEdge
+	incoming map[types.NodeId][]*types.GraphEdge
+}
+
+func newIndex(graph *types.CodeGraph, baseDir string) *index {
+	idx := &index{
+		byID:     make(map[types.NodeId]*Entity),
+		outgoing: make(map[types.NodeId][]*types.GraphEdge),
+		incoming: make(map[types.NodeId][]*types.GraphEdge),
+	}
+
+	symbolFiles := make(map[types.SymbolId]string)
+	for path, file := range graph.Files {
+		idx.add(&Entity{ID: types.NodeId("file-" + path), Kind: "file", File: file, path: RelativePath(path, baseDir)})
+		for _, symbolID := range file.Symbols {
+			symbolFiles[symbolID] = path
+		}
+	}
+	for id, symbol := range graph.Symbols {
+		entity := &Entity{ID: types.NodeId("symbol-" + string(id)), Kind: "symbol", Symbol: symbol}
+		if node := graph.Nodes[entity.ID]; node != nil {
+			entity.Node = node
+		} else if path, ok := symbolFiles[id]; ok {
+			entity.Node = &types.GraphNode{Id: entity.ID, Type: "symbol", Label: symbol.Name, FilePath: path}
+		}
+		if entity.Node != nil {
+			entity.path = RelativePath(entity.Node.FilePath, baseDir)
+		}
+		idx.add(entity)
+	}
+	for id, node := range graph.Nodes {
+		if idx.byID[id] == nil {
+			idx.add(&Entity{ID: id, Kind: node.Type, Node: node, path: RelativePath(node.FilePath, baseDir)})
+		}
+	}
+	sort.Slice(idx.entities, func(i, j int) bool { return idx.entities[i].ID < idx.entities[j].ID })
+
+	edgeIDs := make([]string, 0, len(graph.Edges))
+	for id := range graph.Edges {
+		edgeIDs = append(edgeIDs, string(id))
+	}
+	sort.Strings(edgeIDs)
+	contains := make(map[string]bool)
+	for _, id := range edgeIDs {
+		edge := graph.Edges[types.EdgeId(id)]
+		idx.link(edge)
+		if edge.Type == "contains" {
+			contains[string(edge.From)+"\x00"+string(edge.To)] = true
+		}
+	}
+
+	// Files contain the symbols they declare even without explicit edges
+	paths := make([]string, 0, len(graph.Files))
+	for path := range graph.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		from := types.NodeId("file-" + path)
+		for _, symbolID := range graph.Files[path].Symbols {
+			to := types.NodeId("symbol-" + string(symbolID))
+			if contains[string(from)+"\x00"+string(to)] || idx.byID[to] == nil {
+				continue
+			}
+			idx.link(&types.GraphEdge{
+				Id:     types.EdgeId(fmt.Sprintf("contains-%s-%s", path, symbolID)),
+				From:   from,
+				To:     to,
+				Type:   "contains",
+				Weight: 1.0,
+			})
+		}
+	}
+	return idx
+}
+
+func (idx *index) add(entity *Entity) {
+	idx.byID[entity.ID] = entity
+	idx.entities = append(idx.entities, entity)
+}
+
+func (idx *index) link(edge *types.GraphEdge) {
+	idx.outgoing[edge.From] = append(idx.outgoing[edge.From], edge)
+	idx.incoming[edge.To] = append(idx.incoming[edge.To], edge)
+}
+
+// eval compares the field with the literal. Missing fields never match; non-numeric
+// values compared with a number only satisfy "!=".
+func (c *condition) eval(row map[string]*Entity) bool {
+	entity := row[c.alias]
+	if entity == nil {
+		return false
+	}
+	value, ok := entity.Field(c.field)
+	if !ok || value == nil {
+		return false
+	}
+	text := fmt.Sprint(value)
+	if list, ok := value.([]interface{}); ok {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+		text = strings.Join(parts, ",")
+	}
+
+	switch c.op {
+	case "CONTAINS":
+		return strings.Contains(text, c.value.text)
+	case "STARTS WITH":
+		return strings.HasPrefix(text, c.value.text)
+	case "ENDS WITH":
+		return strings.HasSuffix(text, c.value.text)
+	case "MATCHES":
+		return c.regex.MatchString(text)
+	}
+
+	if c.value.numeric {
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return c.op == "!="
+		}
+		switch c.op {
+		case "=":
+			return number == c.value.number
+		case "!=":
+			return number != c.value.number
+		case "<":
+			return number < c.value.number
+		case ">":
+			return number > c.value.number
+		case "<=":
+			return number <= c.value.number
+		case ">=":
+			return number >= c.value.number
+		}
+		return false
+	}
+
+	switch c.op {
+	case "=":
+		return text == c.value.text
+	case "!=":
+		return text != c.value.text
+	case "<":
+		return text < c.value.text
+	case ">":
+		return text > c.value.text
+	case "<=":
+		return text <= c.value.text
+	case ">=":
+		return text >= c.value.text
+	}
+	return false
+}
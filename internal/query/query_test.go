@@ -0,0 +1,157 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGraph: app.ts and admin.ts import auth/session.ts, which imports util.ts
+func testGraph() *types.CodeGraph {
+	graph := &types.CodeGraph{
+		Nodes:   make(map[types.NodeId]*types.GraphNode),
+		Edges:   make(map[types.EdgeId]*types.GraphEdge),
+		Files:   make(map[string]*types.FileNode),
+		Symbols: make(map[types.SymbolId]*types.Symbol),
+	}
+	addFile := func(path string, lines int, symbols ...*types.Symbol) {
+		file := &types.FileNode{Path: "/repo/" + path, Language: "typescript", Lines: lines}
+		for _, symbol := range symbols {
+			graph.Symbols[symbol.Id] = symbol
+			file.Symbols = append(file.Symbols, symbol.Id)
+			graph.Nodes[types.NodeId("symbol-"+string(symbol.Id))] = &types.GraphNode{
+				Id: types.NodeId("symbol-" + string(symbol.Id)), Type: "symbol", Label: symbol.Name, FilePath: file.Path,
+			}
+		}
+		graph.Files[file.Path] = file
+	}
+	addEdge := func(from, to, edgeType string) {
+		id := types.EdgeId(edgeType + "-" + from + "-" + to)
+		graph.Edges[id] = &types.GraphEdge{Id: id, From: types.NodeId(from), To: types.NodeId(to), Type: edgeType}
+	}
+
+	addFile("app.ts", 40, &types.Symbol{Id: "main", Name: "main", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 3}})
+	addFile("admin.ts", 900)
+	addFile("auth/session.ts", 120,
+		&types.Symbol{Id: "Session", Name: "Session", Type: types.SymbolTypeClass, Location: types.Location{StartLine: 5}},
+		&types.Symbol{Id: "login", Name: "login", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 30}})
+	addFile("util.ts", 10)
+
+	addEdge("file-/repo/app.ts", "file-/repo/auth/session.ts", "imports")
+	addEdge("file-/repo/admin.ts", "file-/repo/auth/session.ts", "imports")
+	addEdge("file-/repo/auth/session.ts", "file-/repo/util.ts", "imports")
+	addEdge("symbol-main", "symbol-login", "calls")
+	return graph
+}
+
+func names(result *Result, column int) []string {
+	var out []string
+	for _, row := range result.Rows {
+		out = append(out, row.Nodes[column].Name())
+	}
+	return out
+}
+
+func run(t *testing.T, source string) *Result {
+	t.Helper()
+	result, err := Run(testGraph(), source, "/repo")
+	require.NoError(t, err, source)
+	return result
+}
+
+func TestParse(t *testing.T) {
+	q, err := Parse("match file->imports->file<-IMPORTS<-f:file where file2.path contains 'auth' return file, f limit 5")
+	require.NoError(t, err)
+	assert.Equal(t, []NodeSpec{{Alias: "file", Kind: "file"}, {Alias: "file2", Kind: "file"}, {Alias: "f", Kind: "file"}}, q.Nodes)
+	assert.Equal(t, []EdgeSpec{{Type: "imports"}, {Type: "imports", Incoming: true}}, q.Edges)
+	assert.Equal(t, []string{"file", "f"}, q.Return)
+	assert.Equal(t, 5, q.Limit)
+
+	q, err = Parse("MATCH *->calls-service->*")
+	require.NoError(t, err)
+	assert.Equal(t, "calls-service", q.Edges[0].Type)
+	assert.Equal(t, DefaultLimit, q.Limit)
+
+	q, err = Parse("MATCH class<-mixes-in<-class")
+	require.NoError(t, err)
+	assert.Equal(t, EdgeSpec{Type: "mixes-in", Incoming: true}, q.Edges[0])
+
+	for _, source := range []string{
+		"",
+		"FIND file",
+		"MATCH file->imports",
+		"MATCH file->imports<-file",
+		"MATCH a:file->imports->a:file",
+		"MATCH file WHERE other.path = 'x'",
+		"MATCH file WHERE path ~ 'x'",
+		"MATCH file WHERE path = 'unterminated",
+		"MATCH file WHERE path MATCHES '('",
+		"MATCH file WHERE (path = 'x'",
+		"MATCH file RETURN missing",
+		"MATCH file LIMIT 0",
+		"MATCH file extra",
+	} {
+		_, err := Parse(source)
+		assert.Error(t, err, source)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	result := run(t, "MATCH file->imports->file WHERE file2.path CONTAINS 'auth'")
+	assert.Equal(t, []string{"file", "file2"}, result.Columns)
+	assert.Equal(t, []string{"admin.ts", "app.ts"}, names(result, 0))
+	require.Len(t, result.Rows[0].Edges, 1)
+	assert.Equal(t, "imports", result.Rows[0].Edges[0].Type)
+
+	// Unqualified fields refer to the first node
+	result = run(t, "MATCH file->imports->file WHERE path CONTAINS 'auth'")
+	assert.Equal(t, []string{"auth/session.ts"}, names(result, 0))
+	assert.Equal(t, []string{"util.ts"}, names(result, 1))
+
+	// Two hops, returning distinct endpoints only
+	result = run(t, "MATCH a:file->imports->b:file->imports->c:file RETURN a, c")
+	assert.Equal(t, []string{"admin.ts", "app.ts"}, names(result, 0))
+	assert.Equal(t, []string{"util.ts", "util.ts"}, names(result, 1))
+	assert.Empty(t, result.Rows[0].Edges)
+
+	// Incoming edges
+	result = run(t, "MATCH f:file<-imports<-g:file WHERE f.name = 'session.ts' RETURN g")
+	assert.Equal(t, []string{"admin.ts", "app.ts"}, names(result, 0))
+
+	// Files contain their symbols without explicit edges; symbol types are node kinds
+	result = run(t, "MATCH file->contains->function")
+	assert.Equal(t, []string{"main", "login"}, names(result, 1))
+	result = run(t, "MATCH function->calls->function RETURN function2")
+	assert.Equal(t, []string{"login"}, names(result, 0))
+	result = run(t, "MATCH symbol WHERE type = 'class' OR (name STARTS WITH 'ma' AND line < 10)")
+	assert.Equal(t, []string{"Session", "main"}, names(result, 0))
+
+	// Numeric and negated conditions
+	result = run(t, "MATCH file WHERE lines >= 100 AND NOT path ENDS WITH '.ts'")
+	assert.Empty(t, result.Rows)
+	result = run(t, "MATCH file WHERE lines >= 100 AND NOT name MATCHES '^adm'")
+	assert.Equal(t, []string{"auth/session.ts"}, names(result, 0))
+	result = run(t, "MATCH file WHERE missing = 'x'")
+	assert.Empty(t, result.Rows)
+
+	// Wildcards and limits
+	result = run(t, "MATCH *->*->* LIMIT 2")
+	assert.Len(t, result.Rows, 2)
+	assert.True(t, result.Truncated)
+}
+
+func TestResultOutput(t *testing.T) {
+	result := run(t, "MATCH file->*->symbol")
+	markdown := result.Markdown()
+	assert.Contains(t, markdown, "`MATCH file->*->symbol`")
+	assert.Contains(t, markdown, "| file | symbol | edges |")
+	assert.Contains(t, markdown, "| `auth/session.ts` | `login` (function, auth/session.ts:30) | contains |")
+
+	records := run(t, "MATCH class").Records()
+	require.Len(t, records, 1)
+	assert.Equal(t, Record{ID: "symbol-Session", Kind: "class", Name: "Session", File: "auth/session.ts", Line: 5}, records[0]["class"])
+
+	assert.Contains(t, run(t, "MATCH file WHERE name = 'none'").Markdown(), "_No matches_")
+}
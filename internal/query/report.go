@@ -0,0 +1,270 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// ReportConfig defines a saved query in the config file. Reports run from the command
+// line ("codecontext report <name>") and as MCP tools named report_<name>.
+type ReportConfig struct {
+	Name        string   `json:"name" mapstructure:"name"`
+	Description string   `json:"description,omitempty" mapstructure:"description"`
+	Query       string   `json:"query" mapstructure:"query"`                     // May reference parameters as $name
+	Parameters  []string `json:"parameters,omitempty" mapstructure:"parameters"` // Required arguments substituted into the query
+	GroupBy     string   `json:"group_by,omitempty" mapstructure:"group_by"`     // "[node.]field" to group rows by
+	Template    string   `json:"template,omitempty" mapstructure:"template"`     // text/template rendering ReportData
+}
+
+// ReportData is passed to report templates
+type ReportData struct {
+	Name        string
+	Description string
+	Query       string // After parameter substitution
+	Columns     []string
+	Rows        []map[string]Record
+	Groups      []ReportGroup // Empty unless group_by is set
+	Total       int
+	Truncated   bool
+}
+
+// ReportGroup holds the rows sharing a group_by value
+type ReportGroup struct {
+	Key  string
+	Rows []map[string]Record
+}
+
+// Report is a validated, ready to run report definition
+type Report struct {
+	config     ReportConfig
+	template   *template.Template
+	groupAlias string
+	groupField string
+}
+
+var (
+	reportNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	parameterPattern  = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+// NewReport validates a report definition
+func NewReport(config ReportConfig) (*Report, error) {
+	if !reportNamePattern.MatchString(config.Name) {
+		return nil, fmt.Errorf("invalid report name %q: use lowercase letters, digits and underscores", config.Name)
+	}
+	for _, param := range config.Parameters {
+		if param == "target_dir" || !parameterPattern.MatchString("$"+param) {
+			return nil, fmt.Errorf("report %s: invalid parameter name %q", config.Name, param)
+		}
+	}
+
+	// Check the query with placeholder arguments
+	placeholders := make(map[string]string, len(config.Parameters))
+	for _, param := range config.Parameters {
+		placeholders[param] = param
+	}
+	source, err := substituteParameters(config.Query, placeholders)
+	if err != nil {
+		return nil, fmt.Errorf("report %s: %w", config.Name, err)
+	}
+	q, err := Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("report %s: %w", config.Name, err)
+	}
+
+	report := &Report{config: config}
+	if config.GroupBy != "" {
+		report.groupAlias, report.groupField = q.Nodes[0].Alias, config.GroupBy
+		if alias, field, ok := strings.Cut(config.GroupBy, "."); ok {
+			report.groupAlias, report.groupField = alias, field
+		}
+		columns := q.Return
+		if len(columns) == 0 {
+			for _, node := range q.Nodes {
+				columns = append(columns, node.Alias)
+			}
+		}
+		found := false
+		for _, column := range columns {
+			found = found || column == report.groupAlias
+		}
+		if !found {
+			return nil, fmt.Errorf("report %s: group_by node %s is not returned by the query", config.Name, report.groupAlias)
+		}
+	}
+	if config.Template != "" {
+		tmpl, err := template.New(config.Name).Funcs(template.FuncMap{"join": strings.Join}).Parse(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("report %s: invalid template: %w", config.Name, err)
+		}
+		report.template = tmpl
+	}
+	return report, nil
+}
+
+// LoadReports validates the configured reports, skipping invalid ones
+func LoadReports(configs []ReportConfig) ([]*Report, []error) {
+	var reports []*Report
+	var errs []error
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		if seen[config.Name] {
+			errs = append(errs, fmt.Errorf("duplicate report %s", config.Name))
+			continue
+		}
+		report, err := NewReport(config)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		seen[config.Name] = true
+		reports = append(reports, report)
+	}
+	return reports, errs
+}
+
+// Name returns the report name
+func (r *Report) Name() string {
+	return r.config.Name
+}
+
+// Description returns the report description, falling back to its query
+func (r *Report) Description() string {
+	if r.config.Description != "" {
+		return r.config.Description
+	}
+	return r.config.Query
+}
+
+// Parameters returns the names of the report's required arguments
+func (r *Report) Parameters() []string {
+	return r.config.Parameters
+}
+
+// Run executes the report against the graph and renders it
+func (r *Report) Run(graph *types.CodeGraph, baseDir string, args map[string]string) (string, error) {
+	source, err := substituteParameters(r.config.Query, args)
+	if err != nil {
+		return "", fmt.Errorf("report %s: %w", r.config.Name, err)
+	}
+	result, err := Run(graph, source, baseDir)
+	if err != nil {
+		return "", fmt.Errorf("report %s: %w", r.config.Name, err)
+	}
+
+	records := result.Records()
+	data := ReportData{
+		Name:        r.config.Name,
+		Description: r.config.Description,
+		Query:       source,
+		Columns:     result.Columns,
+		Rows:        records,
+		Total:       len(result.Rows),
+		Truncated:   result.Truncated,
+	}
+	groups := r.groupRows(result)
+	for _, group := range groups {
+		reportGroup := ReportGroup{Key: group.key}
+		for _, i := range group.rows {
+			reportGroup.Rows = append(reportGroup.Rows, records[i])
+		}
+		data.Groups = append(data.Groups, reportGroup)
+	}
+
+	if r.template != nil {
+		var out strings.Builder
+		if err := r.template.Execute(&out, data); err != nil {
+			return "", fmt.Errorf("report %s: template failed: %w", r.config.Name, err)
+		}
+		return out.String(), nil
+	}
+	return r.markdown(result, source, groups), nil
+}
+
+// rowGroup lists the indexes of the result rows sharing a group_by value
+type rowGroup struct {
+	key  string
+	rows []int
+}
+
+// groupRows splits the rows by the group_by field, in key order
+func (r *Report) groupRows(result *Result) []rowGroup {
+	if r.groupField == "" {
+		return nil
+	}
+	column := 0
+	for i, name := range result.Columns {
+		if name == r.groupAlias {
+			column = i
+		}
+	}
+
+	byKey := make(map[string]*rowGroup)
+	var keys []string
+	for i, row := range result.Rows {
+		key := "(none)"
+		if value, ok := row.Nodes[column].Field(r.groupField); ok && value != nil {
+			key = fmt.Sprint(value)
+		}
+		if byKey[key] == nil {
+			byKey[key] = &rowGroup{key: key}
+			keys = append(keys, key)
+		}
+		byKey[key].rows = append(byKey[key].rows, i)
+	}
+	sort.Strings(keys)
+
+	groups := make([]rowGroup, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, *byKey[key])
+	}
+	return groups
+}
+
+// markdown is the default rendering: the query result table, once per group
+func (r *Report) markdown(result *Result, source string, groups []rowGroup) string {
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("# Report: %s\n\n", r.config.Name))
+	if r.config.Description != "" {
+		out.WriteString(r.config.Description + "\n\n")
+	}
+	out.WriteString(fmt.Sprintf("`%s`\n\n", source))
+	if len(groups) == 0 {
+		out.WriteString(result.table())
+		return out.String()
+	}
+
+	out.WriteString(fmt.Sprintf("**Matches:** %d in %d groups\n\n", len(result.Rows), len(groups)))
+	for _, group := range groups {
+		out.WriteString(fmt.Sprintf("## %s (%d)\n\n", group.key, len(group.rows)))
+		sub := &Result{Query: result.Query, Columns: result.Columns}
+		for _, i := range group.rows {
+			sub.Rows = append(sub.Rows, result.Rows[i])
+		}
+		out.WriteString(sub.table())
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// substituteParameters replaces $name with the argument as a quoted string literal
+func substituteParameters(source string, args map[string]string) (string, error) {
+	var missing []string
+	substituted := parameterPattern.ReplaceAllStringFunc(source, func(match string) string {
+		value, ok := args[match[1:]]
+		if !ok {
+			missing = append(missing, match[1:])
+			return match
+		}
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing parameters: %s", strings.Join(missing, ", "))
+	}
+	return substituted, nil
+}
@@ -0,0 +1,64 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadReports(t *testing.T) {
+	reports, errs := LoadReports([]ReportConfig{
+		{Name: "importers", Query: "MATCH a:file->imports->b:file WHERE b.path STARTS WITH $module RETURN a", Parameters: []string{"module"}},
+		{Name: "importers", Query: "MATCH file"},
+		{Name: "Bad Name", Query: "MATCH file"},
+		{Name: "broken", Query: "MATCH file WHERE"},
+		{Name: "unbound", Query: "MATCH file WHERE path = $missing"},
+		{Name: "reserved", Query: "MATCH file", Parameters: []string{"target_dir"}},
+		{Name: "grouped", Query: "MATCH a:file->imports->b:file RETURN a", GroupBy: "b.dir"},
+		{Name: "templated", Query: "MATCH file", Template: "{{.Missing"},
+	})
+	require.Len(t, reports, 1)
+	assert.Equal(t, "importers", reports[0].Name())
+	assert.Equal(t, []string{"module"}, reports[0].Parameters())
+	assert.Equal(t, reports[0].config.Query, reports[0].Description())
+	assert.Len(t, errs, 7)
+}
+
+func TestReportRun(t *testing.T) {
+	report, err := NewReport(ReportConfig{
+		Name:        "importers",
+		Description: "Files importing a module",
+		Query:       "MATCH a:file->imports->b:file WHERE b.path STARTS WITH $module RETURN a, b",
+		Parameters:  []string{"module"},
+		GroupBy:     "b.path",
+	})
+	require.NoError(t, err)
+
+	_, err = report.Run(testGraph(), "/repo", nil)
+	assert.ErrorContains(t, err, "missing parameters: module")
+
+	text, err := report.Run(testGraph(), "/repo", map[string]string{"module": "auth"})
+	require.NoError(t, err)
+	assert.Contains(t, text, "# Report: importers")
+	assert.Contains(t, text, "Files importing a module")
+	assert.Contains(t, text, "STARTS WITH 'auth' RETURN a, b`")
+	assert.Contains(t, text, "**Matches:** 2 in 1 groups")
+	assert.Contains(t, text, "## auth/session.ts (2)")
+
+	// Arguments are quoted, so they cannot change the query
+	text, err = report.Run(testGraph(), "/repo", map[string]string{"module": "x' OR path CONTAINS '"})
+	require.NoError(t, err)
+	assert.Contains(t, text, "_No matches_")
+
+	templated, err := NewReport(ReportConfig{
+		Name:     "sizes",
+		Query:    "MATCH file WHERE lines > 100",
+		GroupBy:  "language",
+		Template: "{{.Total}} large files{{range .Groups}}; {{.Key}}:{{range .Rows}} {{.file.Name}}{{end}}{{end}}",
+	})
+	require.NoError(t, err)
+	text, err = templated.Run(testGraph(), "/repo", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "2 large files; typescript: admin.ts auth/session.ts", text)
+}
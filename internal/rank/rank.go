@@ -0,0 +1,64 @@
+// Package rank selects the highest counts of a tally for "most imported" style
+// summary lists, with a deterministic order for ties.
+package rank
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultN is the length of summary lists when none is configured
+const DefaultN = 10
+
+// Entry is one counted key
+type Entry struct {
+	Key   string
+	Count int
+}
+
+// Ranking is the top of a tally
+type Ranking struct {
+	Entries []Entry
+	Omitted int // Entries left out beyond the top n
+	Tied    int // Omitted entries whose count equals the last shown entry
+}
+
+// Top returns the n keys with the highest counts, ordered by count and then by
+// key. n <= 0 returns every key.
+func Top(counts map[string]int, n int) Ranking {
+	entries := make([]Entry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, Entry{Key: key, Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	if n <= 0 || n >= len(entries) {
+		return Ranking{Entries: entries}
+	}
+
+	ranking := Ranking{Entries: entries[:n], Omitted: len(entries) - n}
+	last := entries[n-1].Count
+	for _, e := range entries[n:] {
+		if e.Count != last {
+			break
+		}
+		ranking.Tied++
+	}
+	return ranking
+}
+
+// Note describes the omitted entries, such as "…and 12 more (3 also with 4)",
+// or returns "" when nothing was omitted. unit names what is counted.
+func (r Ranking) Note(unit string) string {
+	if r.Omitted == 0 {
+		return ""
+	}
+	if r.Tied == 0 {
+		return fmt.Sprintf("…and %d more", r.Omitted)
+	}
+	return fmt.Sprintf("…and %d more (%d also with %d %s)", r.Omitted, r.Tied, r.Entries[len(r.Entries)-1].Count, unit)
+}
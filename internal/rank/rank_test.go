@@ -0,0 +1,28 @@
+package rank
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTop(t *testing.T) {
+	counts := map[string]int{"a": 1, "b": 5, "c": 3, "d": 3, "e": 3, "f": 7}
+
+	ranking := Top(counts, 3)
+	assert.Equal(t, []Entry{{"f", 7}, {"b", 5}, {"c", 3}}, ranking.Entries)
+	assert.Equal(t, 3, ranking.Omitted)
+	assert.Equal(t, 2, ranking.Tied, "d and e share the cut-off count")
+	assert.Equal(t, "…and 3 more (2 also with 3 imports)", ranking.Note("imports"))
+
+	ranking = Top(counts, 2)
+	assert.Equal(t, 0, ranking.Tied)
+	assert.Equal(t, "…and 4 more", ranking.Note("imports"))
+
+	all := Top(counts, 0)
+	assert.Len(t, all.Entries, 6)
+	assert.Equal(t, Entry{"a", 1}, all.Entries[5])
+	assert.Empty(t, all.Note("imports"))
+
+	assert.Empty(t, Top(nil, 5).Entries)
+}
@@ -0,0 +1,198 @@
+// Package redact masks secrets in analysis results and generated context before
+// they are shared with LLMs.
+package redact
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/pathglob"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Mask replaces redacted text
+const Mask = "[REDACTED]"
+
+// Config declares what to redact. Paths are globs over paths relative to the
+// analyzed directory; globs without a "/" match the file name at any depth.
+// Patterns are regular expressions; when a pattern has capture groups only the
+// first group is masked, so "api_key=(\S+)" keeps the key name.
+type Config struct {
+	Paths       []string `json:"paths,omitempty" mapstructure:"paths"`               // Files whose symbol details are removed
+	Patterns    []string `json:"patterns,omitempty" mapstructure:"patterns"`         // Content masked in every output
+	UseDefaults *bool    `json:"use_defaults,omitempty" mapstructure:"use_defaults"` // Add the built-in paths and patterns (default true)
+}
+
+// DefaultPaths are files that typically hold credentials
+var DefaultPaths = []string{
+	".env", ".env.*", "*.pem", "*.key", "*.p12", "*.pfx", "id_rsa*", "id_ed25519*",
+	"credentials*.json", "secrets.*", "*.keystore",
+}
+
+// DefaultPatterns match common key material and credential assignments
+var DefaultPatterns = []string{
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,                                     // AWS access key id
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,                                    // GitHub token
+	`\bgithub_pat_[A-Za-z0-9_]{22,}\b`,                                  // GitHub fine-grained token
+	`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`,                                  // Slack token
+	`\b[rs]k_live_[A-Za-z0-9]{16,}\b`,                                   // Stripe key
+	`\bAIza[0-9A-Za-z_-]{35}\b`,                                         // Google API key
+	`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`, // JWT
+	`(?i)(?:password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token|private[_-]?key)["']?\s*[:=]\s*["']([^"'\s]{8,})["']`,
+}
+
+// Redactor masks configured paths and patterns. A nil Redactor redacts nothing.
+type Redactor struct {
+	paths    []string
+	patterns []*regexp.Regexp
+}
+
+// New validates the configuration and compiles its patterns
+func New(config Config) (*Redactor, error) {
+	r := &Redactor{}
+	paths, patterns := config.Paths, config.Patterns
+	if config.UseDefaults == nil || *config.UseDefaults {
+		paths = append(append([]string{}, DefaultPaths...), paths...)
+		patterns = append(append([]string{}, DefaultPatterns...), patterns...)
+	}
+	for _, glob := range paths {
+		if !pathglob.Valid(glob) {
+			return nil, fmt.Errorf("invalid redaction path %q", glob)
+		}
+		r.paths = append(r.paths, glob)
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Text masks every pattern match in s
+func (r *Redactor) Text(s string) string {
+	if r == nil {
+		return s
+	}
+	for _, re := range r.patterns {
+		if re.NumSubexp() == 0 {
+			s = re.ReplaceAllLiteralString(s, Mask)
+			continue
+		}
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			groups := re.FindStringSubmatchIndex(match)
+			if len(groups) < 4 || groups[2] < 0 {
+				return Mask
+			}
+			return match[:groups[2]] + Mask + match[groups[3]:]
+		})
+	}
+	return s
+}
+
+// Writer returns a writer that masks pattern matches in each write before
+// passing it to w. Matches are found within a single write only, so callers
+// write whole lines or sections.
+func (r *Redactor) Writer(w io.Writer) io.Writer {
+	if r == nil {
+		return w
+	}
+	return &redactingWriter{redactor: r, w: w}
+}
+
+type redactingWriter struct {
+	redactor *Redactor
+	w        io.Writer
+}
+
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, rw.redactor.Text(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// SensitivePath reports whether a path relative to the analyzed directory is redacted
+func (r *Redactor) SensitivePath(rel string) bool {
+	if r == nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	base := path.Base(rel)
+	for _, glob := range r.paths {
+		if strings.Contains(glob, "/") {
+			if pathglob.Match(glob, rel) {
+				return true
+			}
+		} else if ok, _ := path.Match(glob, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Graph redacts an analyzed graph in place. Symbols of sensitive files lose their
+// signatures, documentation and metadata; other symbols and file summaries have
+// patterns masked.
+func (r *Redactor) Graph(graph *types.CodeGraph, baseDir string) {
+	if r == nil || graph == nil {
+		return
+	}
+	for filePath, file := range graph.Files {
+		rel := filePath
+		if relPath, err := filepath.Rel(baseDir, filePath); err == nil && !strings.HasPrefix(relPath, "..") {
+			rel = relPath
+		}
+		sensitive := r.SensitivePath(rel)
+		file.Summary = r.Text(file.Summary)
+		for _, id := range file.Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil {
+				continue
+			}
+			if sensitive {
+				symbol.Signature, symbol.Documentation, symbol.Metadata = "", "", nil
+				continue
+			}
+			symbol.Signature = r.Text(symbol.Signature)
+			symbol.Documentation = r.Text(symbol.Documentation)
+			for key, value := range symbol.Metadata {
+				symbol.Metadata[key] = r.value(value)
+			}
+		}
+	}
+}
+
+// value masks strings inside metadata values
+func (r *Redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return r.Text(v)
+	case []string:
+		masked := make([]string, len(v))
+		for i, s := range v {
+			masked[i] = r.Text(s)
+		}
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = r.value(item)
+		}
+		return masked
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			masked[key] = r.value(item)
+		}
+		return masked
+	}
+	return v
+}
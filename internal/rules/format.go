@@ -0,0 +1,185 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// severityRank orders severities for --fail-on thresholds
+var severityRank = map[string]int{SeverityNote: 1, SeverityWarning: 2, SeverityError: 3}
+
+// ValidSeverity reports whether s is a known severity
+func ValidSeverity(s string) bool {
+	return severityRank[s] > 0
+}
+
+// AtLeast counts the violations with a severity of at least min
+func AtLeast(violations []Violation, min string) int {
+	count := 0
+	for _, v := range violations {
+		if severityRank[v.Severity] >= severityRank[min] {
+			count++
+		}
+	}
+	return count
+}
+
+// Text renders violations as compiler-style lines ("file:line: severity: message [rule]")
+func Text(violations []Violation) string {
+	var out strings.Builder
+	for _, v := range violations {
+		out.WriteString(fmt.Sprintf("%s: %s: %s [%s]\n", location(v), v.Severity, v.Message, v.Rule))
+	}
+	return out.String()
+}
+
+// Markdown renders violations grouped by rule
+func Markdown(violations []Violation, rules []*Rule) string {
+	var out strings.Builder
+	out.WriteString("# Rule Check\n\n")
+	out.WriteString(fmt.Sprintf("**Rules:** %d  **Violations:** %d (%d errors, %d warnings)\n\n",
+		len(rules), len(violations), AtLeast(violations, SeverityError),
+		AtLeast(violations, SeverityWarning)-AtLeast(violations, SeverityError)))
+	if len(violations) == 0 {
+		out.WriteString("_No violations_\n")
+		return out.String()
+	}
+
+	for _, rule := range rules {
+		var matched []Violation
+		for _, v := range violations {
+			if v.Rule == rule.ID() {
+				matched = append(matched, v)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		out.WriteString(fmt.Sprintf("## %s (%s, %d)\n\n", rule.ID(), rule.Severity(), len(matched)))
+		out.WriteString(rule.Description() + "\n\n")
+		for _, v := range matched {
+			out.WriteString(fmt.Sprintf("- `%s` %s\n", location(v), v.Message))
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func location(v Violation) string {
+	switch {
+	case v.File == "":
+		return "(graph)"
+	case v.Line > 0:
+		return fmt.Sprintf("%s:%d", v.File, v.Line)
+	}
+	return v.File
+}
+
+// SARIF log structures (version 2.1.0), limited to the fields codecontext emits
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string            `json:"id"`
+	ShortDescription     sarifMessage      `json:"shortDescription"`
+	DefaultConfiguration sarifRuleDefaults `json:"defaultConfiguration"`
+}
+
+type sarifRuleDefaults struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIF renders violations as a SARIF 2.1.0 log for code scanning integrations
+func SARIF(violations []Violation, rules []*Rule, version string) ([]byte, error) {
+	driver := sarifDriver{
+		Name:           "codecontext",
+		Version:        version,
+		InformationURI: "https://github.com/nuthan-ms/codecontext",
+		Rules:          make([]sarifRule, 0, len(rules)),
+	}
+	index := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		index[rule.ID()] = i
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID(),
+			ShortDescription:     sarifMessage{Text: rule.Description()},
+			DefaultConfiguration: sarifRuleDefaults{Level: rule.Severity()},
+		})
+	}
+
+	results := make([]sarifResult, 0, len(violations))
+	for _, v := range violations {
+		result := sarifResult{
+			RuleID:    v.Rule,
+			RuleIndex: index[v.Rule],
+			Level:     v.Severity,
+			Message:   sarifMessage{Text: v.Message},
+		}
+		if v.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifact{URI: filepath.ToSlash(v.File)},
+			}}
+			if v.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: v.Line}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		results = append(results, result)
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+}
@@ -0,0 +1,463 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nuthan-ms/codecontext/internal/buildsys"
+	"github.com/nuthan-ms/codecontext/internal/pathglob"
+	"github.com/nuthan-ms/codecontext/internal/query"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Rule types
+const (
+	TypeNaming          = "naming"           // Symbol or file names must match a pattern
+	TypeForbiddenImport = "forbidden_import" // Files must not import matching files or modules
+	TypeMaxFanOut       = "max_fan_out"      // Files (or symbols) must not depend on too many others
+	TypeRequireTest     = "require_test"     // Files must have a test sibling
+	TypeQuery           = "query"            // Every row matched by a graph query is a violation
+)
+
+// Severities, in increasing order
+const (
+	SeverityNote    = "note"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// Config declares a rule in the config file. Files and Exclude are globs over paths
+// relative to the analyzed directory ("**" matches any number of directories).
+type Config struct {
+	ID       string   `json:"id" mapstructure:"id"`
+	Type     string   `json:"type" mapstructure:"type"`
+	Severity string   `json:"severity,omitempty" mapstructure:"severity"` // Defaults to error
+	Message  string   `json:"message,omitempty" mapstructure:"message"`   // Replaces the generated message
+	Files    []string `json:"files,omitempty" mapstructure:"files"`       // Files the rule applies to (default: all)
+	Exclude  []string `json:"exclude,omitempty" mapstructure:"exclude"`
+
+	Kind         string   `json:"kind,omitempty" mapstructure:"kind"`                   // naming, max_fan_out: symbol kind or type, or "file"
+	Pattern      string   `json:"pattern,omitempty" mapstructure:"pattern"`             // naming: regular expression names must match
+	Imports      []string `json:"imports,omitempty" mapstructure:"imports"`             // forbidden_import: globs over imported files or module paths
+	Max          int      `json:"max,omitempty" mapstructure:"max"`                     // max_fan_out: allowed number of dependencies
+	TestPatterns []string `json:"test_patterns,omitempty" mapstructure:"test_patterns"` // require_test: e.g. "{dir}/{name}_test{ext}"
+	Query        string   `json:"query,omitempty" mapstructure:"query"`                 // query: graph query whose rows are violations
+}
+
+// Violation is a single rule failure
+type Violation struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	File     string `json:"file,omitempty"` // Relative to the analyzed directory
+	Line     int    `json:"line,omitempty"`
+}
+
+// Rule is a validated, ready to check rule
+type Rule struct {
+	config      Config
+	pattern     *regexp.Regexp
+	query       *query.Query
+	description string // Set for built-in rules
+}
+
+// defaultTestPatterns cover the Go, JavaScript/TypeScript, Python and Dart conventions
+var defaultTestPatterns = []string{
+	"{dir}/{name}_test{ext}",
+	"{dir}/{name}.test{ext}",
+	"{dir}/{name}.spec{ext}",
+	"{dir}/__tests__/{name}{ext}",
+	"{dir}/__tests__/{name}.test{ext}",
+	"{dir}/test_{name}{ext}",
+	"{dir}/tests/test_{name}{ext}",
+	"test/{dir}/{name}_test{ext}",
+}
+
+var ruleIDPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// NewRule validates a rule definition
+func NewRule(config Config) (*Rule, error) {
+	if !ruleIDPattern.MatchString(config.ID) {
+		return nil, fmt.Errorf("invalid rule id %q: use letters, digits, '_', '-' and '.'", config.ID)
+	}
+	switch config.Severity {
+	case "":
+		config.Severity = SeverityError
+	case SeverityNote, SeverityWarning, SeverityError:
+	default:
+		return nil, fmt.Errorf("rule %s: invalid severity %q (use error, warning or note)", config.ID, config.Severity)
+	}
+	for _, glob := range append(append(append([]string{}, config.Files...), config.Exclude...), config.Imports...) {
+		if !pathglob.Valid(glob) {
+			return nil, fmt.Errorf("rule %s: invalid glob %q", config.ID, glob)
+		}
+	}
+
+	rule := &Rule{config: config}
+	switch config.Type {
+	case TypeNaming:
+		if config.Pattern == "" {
+			return nil, fmt.Errorf("rule %s: naming rules need a pattern", config.ID)
+		}
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: invalid pattern: %w", config.ID, err)
+		}
+		rule.pattern = pattern
+	case TypeForbiddenImport:
+		if len(config.Imports) == 0 {
+			return nil, fmt.Errorf("rule %s: forbidden_import rules need imports", config.ID)
+		}
+	case TypeMaxFanOut:
+		if config.Max <= 0 {
+			return nil, fmt.Errorf("rule %s: max_fan_out rules need a positive max", config.ID)
+		}
+	case TypeRequireTest:
+		if len(rule.config.TestPatterns) == 0 {
+			rule.config.TestPatterns = defaultTestPatterns
+		}
+	case TypeQuery:
+		q, err := query.Parse(config.Query)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", config.ID, err)
+		}
+		rule.query = q
+	default:
+		return nil, fmt.Errorf("rule %s: unknown type %q", config.ID, config.Type)
+	}
+	return rule, nil
+}
+
+// Builtin declares a rule that codecontext checks itself rather than from the
+// config, so its violations can share the formatters
+func Builtin(id, severity, description string) *Rule {
+	return &Rule{config: Config{ID: id, Severity: severity}, description: description}
+}
+
+// Load validates the configured rules, skipping invalid ones
+func Load(configs []Config) ([]*Rule, []error) {
+	var rules []*Rule
+	var errs []error
+	seen := make(map[string]bool)
+	for _, config := range configs {
+		if seen[config.ID] {
+			errs = append(errs, fmt.Errorf("duplicate rule %s", config.ID))
+			continue
+		}
+		rule, err := NewRule(config)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		seen[config.ID] = true
+		rules = append(rules, rule)
+	}
+	return rules, errs
+}
+
+// ID returns the rule id
+func (r *Rule) ID() string {
+	return r.config.ID
+}
+
+// Severity returns the rule severity
+func (r *Rule) Severity() string {
+	return r.config.Severity
+}
+
+// Description summarizes what the rule checks
+func (r *Rule) Description() string {
+	if r.description != "" {
+		return r.description
+	}
+	c := r.config
+	switch c.Type {
+	case TypeNaming:
+		return fmt.Sprintf("%s names must match %s", r.kind(), c.Pattern)
+	case TypeForbiddenImport:
+		return fmt.Sprintf("must not import %s", strings.Join(c.Imports, ", "))
+	case TypeMaxFanOut:
+		return fmt.Sprintf("%s fan-out must not exceed %d", r.kind(), c.Max)
+	case TypeRequireTest:
+		return "files must have a test"
+	}
+	return c.Query
+}
+
+// kind is the entity a naming or fan-out rule applies to
+func (r *Rule) kind() string {
+	if r.config.Kind == "" {
+		if r.config.Type == TypeNaming {
+			return "symbol"
+		}
+		return "file"
+	}
+	return r.config.Kind
+}
+
+// Check runs the rules against the graph. Violations are sorted by file and line.
+func Check(graph *types.CodeGraph, baseDir string, rules []*Rule) []Violation {
+	files := make(map[string]*types.FileNode, len(graph.Files))
+	for filePath, file := range graph.Files {
+		files[query.RelativePath(filePath, baseDir)] = file
+	}
+
+	var violations []Violation
+	for _, rule := range rules {
+		var found []Violation
+		switch rule.config.Type {
+		case TypeNaming:
+			found = rule.checkNaming(graph, files)
+		case TypeForbiddenImport:
+			found = rule.checkForbiddenImports(graph, files, baseDir)
+		case TypeMaxFanOut:
+			found = rule.checkFanOut(graph, files)
+		case TypeRequireTest:
+			found = rule.checkRequireTest(files, baseDir)
+		case TypeQuery:
+			found = rule.checkQuery(graph, baseDir)
+		}
+		for i := range found {
+			found[i].Rule = rule.config.ID
+			found[i].Severity = rule.config.Severity
+			if rule.config.Message != "" {
+				found[i].Message = rule.config.Message
+			}
+		}
+		violations = append(violations, found...)
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].File != violations[j].File {
+			return violations[i].File < violations[j].File
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations
+}
+
+// appliesTo reports whether a relative file path is selected by files/exclude
+func (r *Rule) appliesTo(rel string) bool {
+	if len(r.config.Files) > 0 && !pathglob.MatchAny(r.config.Files, rel) {
+		return false
+	}
+	return !pathglob.MatchAny(r.config.Exclude, rel)
+}
+
+// sortedPaths returns the selected files in path order
+func (r *Rule) sortedPaths(files map[string]*types.FileNode) []string {
+	var paths []string
+	for rel := range files {
+		if r.appliesTo(rel) {
+			paths = append(paths, rel)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (r *Rule) checkNaming(graph *types.CodeGraph, files map[string]*types.FileNode) []Violation {
+	var violations []Violation
+	kind := r.kind()
+	for _, rel := range r.sortedPaths(files) {
+		if kind == "file" {
+			name := filepath.Base(rel)
+			if !r.pattern.MatchString(name) {
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("file name %q does not match %s", name, r.config.Pattern),
+					File:    rel,
+				})
+			}
+			continue
+		}
+		for _, id := range files[rel].Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil || (kind != "symbol" && !symbol.MatchesKind(kind)) {
+				continue
+			}
+			if !r.pattern.MatchString(symbol.Name) {
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("%s %q does not match %s", symbol.Type, symbol.Name, r.config.Pattern),
+					File:    rel,
+					Line:    symbol.Location.StartLine,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// checkForbiddenImports matches both the raw import path and, when the import
+// resolved to an analyzed file, that file's path
+func (r *Rule) checkForbiddenImports(graph *types.CodeGraph, files map[string]*types.FileNode, baseDir string) []Violation {
+	resolved := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		if edge.Type != "imports" {
+			continue
+		}
+		from := strings.TrimPrefix(string(edge.From), "file-")
+		to := strings.TrimPrefix(string(edge.To), "file-")
+		if from == string(edge.From) || to == string(edge.To) {
+			continue
+		}
+		rel := query.RelativePath(from, baseDir)
+		resolved[rel] = append(resolved[rel], query.RelativePath(to, baseDir))
+	}
+
+	var violations []Violation
+	for _, rel := range r.sortedPaths(files) {
+		reported := make(map[string]bool)
+		for _, imp := range files[rel].Imports {
+			if pathglob.MatchAny(r.config.Imports, imp.Path) && !reported[imp.Path] {
+				reported[imp.Path] = true
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("forbidden import %q", imp.Path),
+					File:    rel,
+					Line:    imp.Location.Line,
+				})
+			}
+		}
+		targets := resolved[rel]
+		sort.Strings(targets)
+		for _, target := range targets {
+			if pathglob.MatchAny(r.config.Imports, target) && !reported[target] {
+				reported[target] = true
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("forbidden import of %s", target),
+					File:    rel,
+					Line:    importLine(files[rel], target),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// importLine finds the import statement most likely to have resolved to target,
+// by file stem and then by directory (for package imports)
+func importLine(file *types.FileNode, target string) int {
+	slashed := filepath.ToSlash(target)
+	for _, name := range []string{strings.TrimSuffix(path.Base(slashed), path.Ext(slashed)), path.Base(path.Dir(slashed))} {
+		for _, imp := range file.Imports {
+			if name != "." && strings.Contains(imp.Path, name) {
+				return imp.Location.Line
+			}
+		}
+	}
+	return 0
+}
+
+// checkFanOut counts distinct imports for files, or distinct callees for symbols
+func (r *Rule) checkFanOut(graph *types.CodeGraph, files map[string]*types.FileNode) []Violation {
+	var violations []Violation
+	kind := r.kind()
+	if kind == "file" {
+		for _, rel := range r.sortedPaths(files) {
+			distinct := make(map[string]bool)
+			for _, imp := range files[rel].Imports {
+				distinct[imp.Path] = true
+			}
+			if len(distinct) > r.config.Max {
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("imports %d modules (max %d)", len(distinct), r.config.Max),
+					File:    rel,
+				})
+			}
+		}
+		return violations
+	}
+
+	callees := make(map[types.NodeId]map[types.NodeId]bool)
+	for _, edge := range graph.Edges {
+		if edge.Type == "calls" && edge.From != edge.To {
+			if callees[edge.From] == nil {
+				callees[edge.From] = make(map[types.NodeId]bool)
+			}
+			callees[edge.From][edge.To] = true
+		}
+	}
+	for _, rel := range r.sortedPaths(files) {
+		for _, id := range files[rel].Symbols {
+			symbol := graph.Symbols[id]
+			if symbol == nil || (kind != "symbol" && !symbol.MatchesKind(kind)) {
+				continue
+			}
+			if count := len(callees[types.NodeId("symbol-"+string(id))]); count > r.config.Max {
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("%s %q calls %d symbols (max %d)", symbol.Type, symbol.Name, count, r.config.Max),
+					File:    rel,
+					Line:    symbol.Location.StartLine,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// checkRequireTest looks for a test file among the analyzed files or on disk,
+// since test directories are often excluded from the analysis
+func (r *Rule) checkRequireTest(files map[string]*types.FileNode, baseDir string) []Violation {
+	var violations []Violation
+	for _, rel := range r.sortedPaths(files) {
+		if files[rel].IsTest {
+			continue
+		}
+		candidates := testCandidates(rel, r.config.TestPatterns)
+		found := false
+		for _, candidate := range candidates {
+			if files[candidate] != nil || buildsys.FileExists(filepath.Join(baseDir, candidate)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			violations = append(violations, Violation{
+				Message: "no test file found",
+				File:    rel,
+			})
+		}
+	}
+	return violations
+}
+
+// testCandidates expands the {dir}, {name} and {ext} placeholders for a file
+func testCandidates(rel string, patterns []string) []string {
+	slashed := filepath.ToSlash(rel)
+	ext := path.Ext(slashed)
+	replacer := strings.NewReplacer(
+		"{dir}", path.Dir(slashed),
+		"{name}", strings.TrimSuffix(path.Base(slashed), ext),
+		"{ext}", ext,
+	)
+	candidates := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		candidates = append(candidates, filepath.FromSlash(path.Clean(replacer.Replace(pattern))))
+	}
+	return candidates
+}
+
+// checkQuery reports every row of the query, located at its first node
+func (r *Rule) checkQuery(graph *types.CodeGraph, baseDir string) []Violation {
+	result := query.Execute(graph, r.query, baseDir)
+	var violations []Violation
+	for _, row := range result.Records() {
+		record := row[result.Columns[0]]
+		if record.File != "" && !r.appliesTo(record.File) {
+			continue
+		}
+		message := record.Name
+		if record.Kind != "file" {
+			message = fmt.Sprintf("%s %s", record.Kind, record.Name)
+		}
+		violations = append(violations, Violation{
+			Message: fmt.Sprintf("%s matches %s", message, r.config.Query),
+			File:    record.File,
+			Line:    record.Line,
+		})
+	}
+	return violations
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGraph: handlers/user.go imports db/conn.go and several packages;
+// db/conn.go has a test, handlers/user.go does not
+func testGraph(baseDir string) *types.CodeGraph {
+	graph := &types.CodeGraph{
+		Nodes:   make(map[types.NodeId]*types.GraphNode),
+		Edges:   make(map[types.EdgeId]*types.GraphEdge),
+		Files:   make(map[string]*types.FileNode),
+		Symbols: make(map[types.SymbolId]*types.Symbol),
+	}
+	addFile := func(path string, imports []string, symbols ...*types.Symbol) *types.FileNode {
+		file := &types.FileNode{Path: filepath.Join(baseDir, path), Language: "go"}
+		for i, imp := range imports {
+			file.Imports = append(file.Imports, &types.Import{Path: imp, Location: types.FileLocation{Line: i + 3}})
+		}
+		for _, symbol := range symbols {
+			graph.Symbols[symbol.Id] = symbol
+			file.Symbols = append(file.Symbols, symbol.Id)
+		}
+		graph.Files[file.Path] = file
+		return file
+	}
+
+	addFile("handlers/user.go", []string{"example.com/app/db", "fmt", "net/http", "os/exec"},
+		&types.Symbol{Id: "GetUser", Name: "GetUser", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 10}},
+		&types.Symbol{Id: "delete_user", Name: "delete_user", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 20}})
+	addFile("db/conn.go", []string{"database/sql"},
+		&types.Symbol{Id: "Open", Name: "Open", Type: types.SymbolTypeFunction, Location: types.Location{StartLine: 5}})
+	addFile("db/conn_test.go", []string{"testing"}).IsTest = true
+
+	from, to := "file-"+filepath.Join(baseDir, "handlers/user.go"), "file-"+filepath.Join(baseDir, "db/conn.go")
+	graph.Edges["imports-1"] = &types.GraphEdge{Id: "imports-1", From: types.NodeId(from), To: types.NodeId(to), Type: "imports"}
+	for _, callee := range []string{"Open", "GetUser"} {
+		id := types.EdgeId("calls-" + callee)
+		graph.Edges[id] = &types.GraphEdge{Id: id, From: "symbol-delete_user", To: types.NodeId("symbol-" + callee), Type: "calls"}
+	}
+	return graph
+}
+
+func check(t *testing.T, configs ...Config) []Violation {
+	t.Helper()
+	rules, errs := Load(configs)
+	require.Empty(t, errs)
+	return Check(testGraph("/repo"), "/repo", rules)
+}
+
+func TestLoad(t *testing.T) {
+	rules, errs := Load([]Config{
+		{ID: "ok", Type: TypeMaxFanOut, Max: 3},
+		{ID: "ok", Type: TypeMaxFanOut, Max: 3},
+		{ID: "bad id", Type: TypeMaxFanOut, Max: 3},
+		{ID: "severity", Type: TypeMaxFanOut, Max: 3, Severity: "fatal"},
+		{ID: "type", Type: "unknown"},
+		{ID: "pattern", Type: TypeNaming, Pattern: "("},
+		{ID: "imports", Type: TypeForbiddenImport},
+		{ID: "max", Type: TypeMaxFanOut},
+		{ID: "query", Type: TypeQuery, Query: "FIND file"},
+		{ID: "glob", Type: TypeRequireTest, Files: []string{"[a"}},
+	})
+	require.Len(t, rules, 1)
+	assert.Equal(t, SeverityError, rules[0].Severity())
+	assert.Len(t, errs, 9)
+}
+
+func TestCheck(t *testing.T) {
+	violations := check(t, Config{ID: "exported", Type: TypeNaming, Kind: "function", Pattern: "^[A-Z]", Files: []string{"handlers/**"}})
+	require.Len(t, violations, 1)
+	assert.Equal(t, Violation{Rule: "exported", Severity: SeverityError, Message: `function "delete_user" does not match ^[A-Z]`, File: "handlers/user.go", Line: 20}, violations[0])
+
+	violations = check(t, Config{ID: "snake", Type: TypeNaming, Kind: "file", Pattern: "^[a-z]+\\.go$", Severity: SeverityWarning})
+	require.Len(t, violations, 1)
+	assert.Equal(t, "db/conn_test.go", violations[0].File)
+
+	// Raw module paths and resolved files both count
+	violations = check(t, Config{ID: "layers", Type: TypeForbiddenImport, Files: []string{"handlers/**"}, Imports: []string{"db/**", "os/exec"}, Message: "use the service layer"})
+	require.Len(t, violations, 2)
+	assert.Equal(t, "use the service layer", violations[0].Message)
+	assert.Equal(t, []int{3, 6}, []int{violations[0].Line, violations[1].Line})
+
+	violations = check(t, Config{ID: "fanout", Type: TypeMaxFanOut, Max: 3})
+	require.Len(t, violations, 1)
+	assert.Equal(t, "imports 4 modules (max 3)", violations[0].Message)
+	violations = check(t, Config{ID: "calls", Type: TypeMaxFanOut, Kind: "function", Max: 1})
+	require.Len(t, violations, 1)
+	assert.Equal(t, 20, violations[0].Line)
+
+	violations = check(t, Config{ID: "tests", Type: TypeRequireTest, Files: []string{"**/*.go"}})
+	require.Len(t, violations, 1)
+	assert.Equal(t, "handlers/user.go", violations[0].File)
+
+	violations = check(t, Config{ID: "no-db-importers", Type: TypeQuery, Query: "MATCH a:file->imports->b:file WHERE b.dir = 'db' RETURN a", Severity: SeverityNote})
+	require.Len(t, violations, 1)
+	assert.Equal(t, "handlers/user.go", violations[0].File)
+}
+
+func TestRequireTestOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "handlers", "__tests__"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "handlers", "__tests__", "user.go"), nil, 0644))
+
+	rules, errs := Load([]Config{{ID: "tests", Type: TypeRequireTest}})
+	require.Empty(t, errs)
+	assert.Empty(t, Check(testGraph(dir), dir, rules))
+}
+
+func TestOutput(t *testing.T) {
+	rules, _ := Load([]Config{
+		{ID: "fanout", Type: TypeMaxFanOut, Max: 3, Severity: SeverityWarning},
+		{ID: "tests", Type: TypeRequireTest},
+	})
+	violations := Check(testGraph("/repo"), "/repo", rules)
+	require.Len(t, violations, 2)
+	assert.Equal(t, 2, AtLeast(violations, SeverityWarning))
+	assert.Equal(t, 1, AtLeast(violations, SeverityError))
+
+	assert.Contains(t, Text(violations), "handlers/user.go: warning: imports 4 modules (max 3) [fanout]\n")
+	markdown := Markdown(violations, rules)
+	assert.Contains(t, markdown, "**Violations:** 2 (1 errors, 1 warnings)")
+	assert.Contains(t, markdown, "## tests (error, 1)")
+
+	data, err := SARIF(violations, rules, "1.0.0")
+	require.NoError(t, err)
+	var log map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, "2.1.0", log["version"])
+	run := log["runs"].([]interface{})[0].(map[string]interface{})
+	assert.Len(t, run["tool"].(map[string]interface{})["driver"].(map[string]interface{})["rules"], 2)
+	result := run["results"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(t, "tests", result["ruleId"])
+	assert.Equal(t, float64(1), result["ruleIndex"])
+	assert.Equal(t, "error", result["level"])
+
+	assert.Contains(t, Markdown(nil, rules), "_No violations_")
+}
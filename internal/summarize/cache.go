@@ -0,0 +1,75 @@
+package summarize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/nuthan-ms/codecontext/internal/cache"
+)
+
+// CacheDir is the persistent cache summaries are stored in, relative to the
+// project directory
+const CacheDir = ".codecontext/cache"
+
+// cacheSize bounds the cached summaries, well above the files of a project
+const cacheSize = 100000
+
+// cacheMutex serializes read-modify-write cycles within the process
+var cacheMutex sync.Mutex
+
+// openCache opens the persistent cache of a project directory. Summaries are
+// keyed by content hash and never expire; Apply drops those of files that
+// are gone.
+func openCache(baseDir string) (*cache.PersistentCache, error) {
+	c, err := cache.NewPersistentCache(&cache.Config{
+		Directory: filepath.Join(baseDir, filepath.FromSlash(CacheDir)),
+		MaxSize:   cacheSize,
+		EnableLRU: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open summaries cache: %w", err)
+	}
+	return c, nil
+}
+
+// loadCache opens the persistent cache of a project directory for reading,
+// returning nil when there is none yet rather than creating it
+func loadCache(baseDir string) (*cache.PersistentCache, error) {
+	if _, err := os.Stat(filepath.Join(baseDir, filepath.FromSlash(CacheDir))); os.IsNotExist(err) {
+		return nil, nil
+	}
+	return openCache(baseDir)
+}
+
+// Invalidate drops the cached summaries of files, given relative to the
+// project directory, so the next analysis regenerates them. Without files
+// every summary is dropped. It returns how many were dropped.
+func Invalidate(baseDir string, files []string) (int, error) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	c, err := openCache(baseDir)
+	if err != nil {
+		return 0, err
+	}
+	drop := make(map[string]bool)
+	for _, file := range files {
+		drop[filepath.ToSlash(filepath.Clean(file))] = true
+	}
+	dropped := 0
+	for hash, summary := range c.Summaries() {
+		if len(files) == 0 || drop[summary.Path] {
+			c.RemoveSummary(hash)
+			dropped++
+		}
+	}
+	if dropped == 0 {
+		return 0, nil
+	}
+	if err := c.Close(); err != nil {
+		return dropped, fmt.Errorf("failed to write summaries cache: %w", err)
+	}
+	return dropped, nil
+}
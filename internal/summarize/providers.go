@@ -0,0 +1,130 @@
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// openAI calls an OpenAI-compatible chat completions endpoint, which also
+// covers local servers such as Ollama and llama.cpp
+type openAI struct {
+	endpoint string
+	model    string
+	apiKey   string
+	client   *http.Client
+}
+
+func newOpenAI(config Config) *openAI {
+	p := &openAI{endpoint: config.Endpoint, model: config.Model, client: &http.Client{Timeout: config.Timeout}}
+	if p.endpoint == "" {
+		p.endpoint = DefaultEndpoint
+	}
+	if p.client.Timeout <= 0 {
+		p.client.Timeout = DefaultTimeout
+	}
+	keyEnv := config.APIKeyEnv
+	if keyEnv == "" {
+		keyEnv = DefaultAPIKeyEnv
+	}
+	p.apiKey = os.Getenv(keyEnv)
+	return p
+}
+
+func (p *openAI) Name() string {
+	return ProviderOpenAI + ":" + p.model
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func (p *openAI) Summarize(ctx context.Context, request Request) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":       p.model,
+		"temperature": 0.2,
+		"messages": []chatMessage{
+			{Role: "system", Content: "You write short, factual summaries of source code."},
+			{Role: "user", Content: Prompt(request)},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return "", fmt.Errorf("invalid response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("response has no choices")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
+
+// command runs a local program with the prompt on stdin and reads the
+// summary from stdout
+type command struct {
+	config Config
+}
+
+func newCommand(config Config) *command {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	return &command{config: config}
+}
+
+func (c *command) Name() string {
+	return ProviderCommand + ":" + strings.Join(append([]string{c.config.Command}, c.config.Args...), " ")
+}
+
+func (c *command) Summarize(ctx context.Context, request Request) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.config.Command, c.config.Args...)
+	cmd.Stdin = strings.NewReader(Prompt(request))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("timed out after %v", c.config.Timeout)
+		}
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
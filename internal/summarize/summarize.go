@@ -0,0 +1,283 @@
+// Package summarize writes one-paragraph natural language summaries of source
+// files with a configured LLM. Nothing leaves the machine unless a provider is
+// configured; summaries are kept in the persistent cache by the hash of the
+// file they describe so unchanged files are never sent twice.
+package summarize
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/internal/cache"
+	"github.com/nuthan-ms/codecontext/internal/redact"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Providers
+const (
+	ProviderNone    = "none"    // Offline default: no summaries
+	ProviderOpenAI  = "openai"  // OpenAI-compatible chat completions endpoint
+	ProviderCommand = "command" // Local command reading the prompt on stdin
+)
+
+// Defaults for unset configuration
+const (
+	DefaultEndpoint  = "https://api.openai.com/v1/chat/completions"
+	DefaultAPIKeyEnv = "OPENAI_API_KEY"
+	DefaultTimeout   = 60 * time.Second
+	DefaultMaxFiles  = 100       // New summaries per analysis
+	DefaultMaxBytes  = 24 * 1024 // File content sent per summary
+)
+
+// promptVersion is recorded with every cached summary, so changing the prompt
+// regenerates them
+const promptVersion = "1"
+
+// Config selects and tunes the summary provider
+type Config struct {
+	Provider  string        `json:"provider,omitempty" mapstructure:"provider"`       // none (default), openai or command
+	Endpoint  string        `json:"endpoint,omitempty" mapstructure:"endpoint"`       // Chat completions URL for openai
+	Model     string        `json:"model,omitempty" mapstructure:"model"`             // Model name for openai
+	APIKeyEnv string        `json:"api_key_env,omitempty" mapstructure:"api_key_env"` // Environment variable holding the API key
+	Command   string        `json:"command,omitempty" mapstructure:"command"`         // Executable for command
+	Args      []string      `json:"args,omitempty" mapstructure:"args"`
+	Timeout   time.Duration `json:"timeout,omitempty" mapstructure:"timeout"`     // Per summary
+	MaxFiles  int           `json:"max_files,omitempty" mapstructure:"max_files"` // New summaries per analysis
+	MaxBytes  int           `json:"max_bytes,omitempty" mapstructure:"max_bytes"` // Longer files are truncated
+
+	// MaxStaleness is how long a changed file keeps its previous summary before
+	// it is regenerated; 0 regenerates on every change
+	MaxStaleness time.Duration `json:"max_staleness,omitempty" mapstructure:"max_staleness"`
+}
+
+// Request is what a provider summarizes: one file with the names of its symbols
+type Request struct {
+	Path     string // Relative to the analyzed directory
+	Language string
+	Content  string // Redacted and truncated to MaxBytes
+	Symbols  []string
+}
+
+// Provider turns a request into a summary
+type Provider interface {
+	// Name identifies the provider and model; summaries are cached per name
+	Name() string
+	Summarize(ctx context.Context, request Request) (string, error)
+}
+
+// Summarizer adds cached summaries to analyzed graphs. A nil Summarizer adds nothing.
+type Summarizer struct {
+	provider     Provider
+	maxFiles     int
+	maxBytes     int
+	maxStaleness time.Duration
+	readOnly     bool // Serve cached summaries only, without writing the cache
+}
+
+// Stats counts what a run did
+type Stats struct {
+	Cached    int `json:"cached"`    // Summaries reused from the cache
+	Generated int `json:"generated"` // Summaries requested from the provider
+	Stale     int `json:"stale"`     // Previous summaries of changed files, kept within MaxStaleness
+	Pending   int `json:"pending"`   // Changed or new files not summarized, over MaxFiles or after a failure
+}
+
+// New creates the summarizer of a configuration. It returns nil for the
+// offline default.
+func New(config Config) (*Summarizer, error) {
+	var provider Provider
+	switch config.Provider {
+	case "", ProviderNone:
+		return nil, nil
+	case ProviderOpenAI:
+		if config.Model == "" {
+			return nil, fmt.Errorf("summaries provider openai needs a model")
+		}
+		provider = newOpenAI(config)
+	case ProviderCommand:
+		if config.Command == "" {
+			return nil, fmt.Errorf("summaries provider command needs a command")
+		}
+		provider = newCommand(config)
+	default:
+		return nil, fmt.Errorf("unknown summaries provider %q", config.Provider)
+	}
+	return NewWithProvider(provider, config), nil
+}
+
+// NewWithProvider creates a summarizer backed by a custom provider, with the
+// limits of config
+func NewWithProvider(provider Provider, config Config) *Summarizer {
+	s := &Summarizer{provider: provider, maxFiles: config.MaxFiles, maxBytes: config.MaxBytes, maxStaleness: config.MaxStaleness}
+	if s.maxFiles <= 0 {
+		s.maxFiles = DefaultMaxFiles
+	}
+	if s.maxBytes <= 0 {
+		s.maxBytes = DefaultMaxBytes
+	}
+	return s
+}
+
+// SetReadOnly makes Apply serve only summaries already cached: nothing is
+// requested from the provider and the cache is neither created nor written
+func (s *Summarizer) SetReadOnly(readOnly bool) {
+	if s != nil {
+		s.readOnly = readOnly
+	}
+}
+
+// Apply sets the summary of every source file of a graph analyzed from
+// baseDir. Test, generated and redacted files are skipped and file content is
+// masked by the redactor before it is sent. Summaries are only requested for
+// files whose content changed since they were cached, or that are new; a
+// changed file keeps its previous summary while it is younger than
+// MaxStaleness. At most MaxFiles summaries are requested and the first failure
+// stops further requests, so an unreachable endpoint costs one timeout. The
+// cache keeps only the summaries in use by the current files.
+func (s *Summarizer) Apply(ctx context.Context, graph *types.CodeGraph, baseDir string, redactor *redact.Redactor) (Stats, error) {
+	var stats Stats
+	if s == nil || graph == nil {
+		return stats, nil
+	}
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+	var store *cache.PersistentCache
+	var err error
+	if s.readOnly {
+		store, err = loadCache(baseDir)
+	} else {
+		store, err = openCache(baseDir)
+	}
+	if err != nil {
+		return stats, err
+	}
+
+	// Summaries by content hash, and the latest hash of each file for files
+	// whose content changed since
+	summaries := make(map[string]*cache.Summary)
+	if store != nil {
+		summaries = store.Summaries()
+	}
+	latest := make(map[string]string)
+	for hash, summary := range summaries {
+		if previous, ok := latest[summary.Path]; !ok || summary.Generated.After(summaries[previous].Generated) {
+			latest[summary.Path] = hash
+		}
+	}
+
+	paths := make([]string, 0, len(graph.Files))
+	for path, file := range graph.Files {
+		if !file.IsTest && !file.IsGenerated {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	provider := s.provider.Name() + "@" + promptVersion
+	now := time.Now().UTC().Truncate(time.Second)
+	kept := make(map[string]bool)
+	var failure error
+	for _, path := range paths {
+		file := graph.Files[path]
+		rel := path
+		if relPath, err := filepath.Rel(baseDir, path); err == nil && !strings.HasPrefix(relPath, "..") {
+			rel = filepath.ToSlash(relPath)
+		}
+		if redactor.SensitivePath(rel) {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		request := Request{Path: rel, Language: file.Language, Content: truncate(redactor.Text(string(content)), s.maxBytes)}
+		hash := contentHash(request.Content)
+
+		used := hash
+		if summary := summaries[hash]; summary != nil && summary.Provider == provider {
+			stats.Cached++
+		} else if previous := summaries[latest[rel]]; previous != nil && previous.Provider == provider && s.maxStaleness > 0 && now.Sub(previous.Generated) < s.maxStaleness {
+			used = latest[rel]
+			stats.Stale++
+		} else if s.readOnly || failure != nil || stats.Generated >= s.maxFiles {
+			stats.Pending++
+			continue
+		} else {
+			for _, id := range file.Symbols {
+				if symbol := graph.Symbols[id]; symbol != nil {
+					request.Symbols = append(request.Symbols, symbol.Name)
+				}
+			}
+			text, err := s.provider.Summarize(ctx, request)
+			text = strings.Join(strings.Fields(text), " ")
+			if err != nil || text == "" {
+				if err == nil {
+					err = fmt.Errorf("empty summary")
+				}
+				failure = fmt.Errorf("failed to summarize %s: %w", rel, err)
+				stats.Pending++
+				continue
+			}
+			summaries[hash] = &cache.Summary{Path: rel, Provider: provider, Text: text, Generated: now}
+			if err := store.SetSummary(hash, summaries[hash]); err != nil {
+				failure = fmt.Errorf("failed to cache summary of %s: %w", rel, err)
+			}
+			stats.Generated++
+		}
+		file.Summary = summaries[used].Text
+		kept[used] = true
+	}
+
+	if s.readOnly {
+		return stats, nil
+	}
+	for hash := range summaries {
+		if !kept[hash] {
+			store.RemoveSummary(hash)
+		}
+	}
+	if err := store.Close(); err != nil && failure == nil {
+		failure = fmt.Errorf("failed to write summaries cache: %w", err)
+	}
+	return stats, failure
+}
+
+// contentHash identifies the content a summary was written from
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Prompt is the instruction sent to a provider for a request
+func Prompt(request Request) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Summarize what the %s file %s does in one paragraph of at most four sentences, ", request.Language, request.Path)
+	sb.WriteString("for a developer new to the codebase. Describe its responsibility and how the rest of the project uses it. ")
+	sb.WriteString("Answer with the paragraph only.\n\n")
+	if len(request.Symbols) > 0 {
+		fmt.Fprintf(&sb, "Declared symbols: %s\n\n", strings.Join(request.Symbols, ", "))
+	}
+	sb.WriteString("```\n")
+	sb.WriteString(request.Content)
+	sb.WriteString("\n```\n")
+	return sb.String()
+}
+
+// truncate cuts s to at most n bytes at a line boundary
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	if idx := strings.LastIndexByte(s, '\n'); idx > 0 {
+		s = s[:idx]
+	}
+	return s + "\n// ... truncated"
+}
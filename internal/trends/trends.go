@@ -0,0 +1,283 @@
+package trends
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/git"
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// FileName is where snapshots are stored, relative to the project directory
+const FileName = ".codecontext/trends.json"
+
+// MaxSnapshots is how many snapshots are kept; older ones are dropped
+const MaxSnapshots = 200
+
+// hotspotCount is how many of the most imported files a snapshot records
+const hotspotCount = 5
+
+// Snapshot holds the metrics of one analysis run
+type Snapshot struct {
+	Time           time.Time `json:"time"`
+	Commit         string    `json:"commit,omitempty"`
+	Tags           []string  `json:"tags,omitempty"` // Git tags pointing at the commit
+	Files          int       `json:"files"`
+	Symbols        int       `json:"symbols"`
+	Functions      int       `json:"functions"`
+	AvgComplexity  float64   `json:"avg_complexity"`
+	MaxComplexity  int       `json:"max_complexity"`
+	Packages       int       `json:"packages"`
+	Dependencies   int       `json:"dependencies"` // Package-to-package dependencies
+	AvgInstability float64   `json:"avg_instability"`
+	Hotspots       []string  `json:"hotspots,omitempty"` // Most imported files, relative to the project directory
+}
+
+// Label names the snapshot by its tags, else its short commit, else its date
+func (s Snapshot) Label() string {
+	switch {
+	case len(s.Tags) > 0:
+		return strings.Join(s.Tags, ", ")
+	case len(s.Commit) >= 7:
+		return s.Commit[:7]
+	case s.Commit != "":
+		return s.Commit
+	}
+	return s.Time.Format("2006-01-02 15:04")
+}
+
+// History holds the snapshots of one project directory, oldest first
+type History struct {
+	Version   int        `json:"version"`
+	Snapshots []Snapshot `json:"snapshots"`
+
+	path string
+}
+
+// updateMutex serializes read-modify-write cycles within the process
+var updateMutex sync.Mutex
+
+// Take computes the snapshot of a graph analyzed from baseDir. The commit and
+// tags are left empty outside a git repository.
+func Take(graph *types.CodeGraph, baseDir string) Snapshot {
+	snapshot := Snapshot{Time: time.Now().UTC().Truncate(time.Second)}
+	if repo, err := git.NewGitAnalyzer(baseDir); err == nil {
+		snapshot.Commit, _ = repo.GetHeadCommit()
+		snapshot.Tags, _ = repo.GetHeadTags()
+	}
+
+	for _, file := range graph.Files {
+		if !file.IsTest {
+			snapshot.Files++
+		}
+	}
+	snapshot.Symbols = len(graph.Symbols)
+
+	functions := analyzer.AnalyzeComplexity(graph)
+	snapshot.Functions = len(functions)
+	if len(functions) > 0 {
+		total := 0
+		for _, fn := range functions {
+			total += fn.Complexity
+		}
+		snapshot.AvgComplexity = round(float64(total) / float64(len(functions)))
+		snapshot.MaxComplexity = functions[0].Complexity
+	}
+
+	var coupled int
+	var instability float64
+	for _, pkg := range analyzer.AnalyzeCoupling(graph) {
+		snapshot.Packages++
+		snapshot.Dependencies += pkg.Efferent
+		if pkg.Afferent+pkg.Efferent > 0 {
+			coupled++
+			instability += pkg.Instability
+		}
+	}
+	if coupled > 0 {
+		snapshot.AvgInstability = round(instability / float64(coupled))
+	}
+
+	snapshot.Hotspots = mostImported(graph, baseDir)
+	return snapshot
+}
+
+// mostImported returns the files imported by the most other files
+func mostImported(graph *types.CodeGraph, baseDir string) []string {
+	importers := make(map[string]int)
+	for _, edge := range graph.Edges {
+		if edge.Type != string(analyzer.RelationshipImport) {
+			continue
+		}
+		if to, ok := strings.CutPrefix(string(edge.To), "file-"); ok && graph.Files[to] != nil {
+			importers[to]++
+		}
+	}
+	files := make([]string, 0, len(importers))
+	for file := range importers {
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if importers[files[i]] != importers[files[j]] {
+			return importers[files[i]] > importers[files[j]]
+		}
+		return files[i] < files[j]
+	})
+	if len(files) > hotspotCount {
+		files = files[:hotspotCount]
+	}
+	for i, file := range files {
+		if rel, err := filepath.Rel(baseDir, file); err == nil && !strings.HasPrefix(rel, "..") {
+			files[i] = filepath.ToSlash(rel)
+		}
+	}
+	return files
+}
+
+func round(value float64) float64 {
+	return float64(int(value*100+0.5)) / 100
+}
+
+// Load reads the snapshots of a project directory. A missing file is an empty history.
+func Load(baseDir string) (*History, error) {
+	history := &History{Version: 1, path: filepath.Join(baseDir, filepath.FromSlash(FileName))}
+	data, err := os.ReadFile(history.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trends: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("invalid trends file %s: %w", history.path, err)
+	}
+	return history, nil
+}
+
+// Record appends a snapshot to the history of a project directory, dropping
+// the oldest snapshots beyond MaxSnapshots
+func Record(baseDir string, snapshot Snapshot) error {
+	updateMutex.Lock()
+	defer updateMutex.Unlock()
+
+	history, err := Load(baseDir)
+	if err != nil {
+		return err
+	}
+	history.Snapshots = append(history.Snapshots, snapshot)
+	if len(history.Snapshots) > MaxSnapshots {
+		history.Snapshots = history.Snapshots[len(history.Snapshots)-MaxSnapshots:]
+	}
+	return history.save()
+}
+
+// save writes the history through a temporary file so readers never see a partial file
+func (h *History) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("failed to create trends directory: %w", err)
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trends: %w", err)
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write trends: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write trends: %w", err)
+	}
+	return nil
+}
+
+// Recent returns the last n snapshots, oldest first. With tagsOnly, only
+// snapshots of tagged commits count, the latest one per commit.
+func (h *History) Recent(n int, tagsOnly bool) []Snapshot {
+	snapshots := h.Snapshots
+	if tagsOnly {
+		var tagged []Snapshot
+		for _, snapshot := range snapshots {
+			if len(snapshot.Tags) == 0 {
+				continue
+			}
+			if last := len(tagged) - 1; last >= 0 && tagged[last].Commit == snapshot.Commit {
+				tagged[last] = snapshot
+				continue
+			}
+			tagged = append(tagged, snapshot)
+		}
+		snapshots = tagged
+	}
+	if n > 0 && len(snapshots) > n {
+		snapshots = snapshots[len(snapshots)-n:]
+	}
+	return snapshots
+}
+
+// Markdown renders snapshots as a table followed by the change from the
+// first to the last one
+func Markdown(snapshots []Snapshot) string {
+	var out strings.Builder
+	out.WriteString("| Run | Date | Files | Symbols | Functions | Avg Complexity | Max Complexity | Packages | Dependencies | Avg Instability |\n")
+	out.WriteString("|-----|------|-------|---------|-----------|----------------|----------------|----------|--------------|-----------------|\n")
+	for _, s := range snapshots {
+		date := s.Time.Format("2006-01-02 15:04")
+		label := s.Label()
+		if label == date {
+			label = "-" // Outside git the date is the only label
+		}
+		out.WriteString(fmt.Sprintf("| %s | %s | %d | %d | %d | %.2f | %d | %d | %d | %.2f |\n",
+			label, date, s.Files, s.Symbols, s.Functions,
+			s.AvgComplexity, s.MaxComplexity, s.Packages, s.Dependencies, s.AvgInstability))
+	}
+	if len(snapshots) < 2 {
+		return out.String()
+	}
+
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	out.WriteString(fmt.Sprintf("\n### Change since %s\n\n", first.Label()))
+	out.WriteString(fmt.Sprintf("- **Files:** %s\n", intChange(first.Files, last.Files)))
+	out.WriteString(fmt.Sprintf("- **Symbols:** %s\n", intChange(first.Symbols, last.Symbols)))
+	out.WriteString(fmt.Sprintf("- **Functions:** %s\n", intChange(first.Functions, last.Functions)))
+	out.WriteString(fmt.Sprintf("- **Avg complexity:** %s\n", floatChange(first.AvgComplexity, last.AvgComplexity)))
+	out.WriteString(fmt.Sprintf("- **Max complexity:** %s\n", intChange(first.MaxComplexity, last.MaxComplexity)))
+	out.WriteString(fmt.Sprintf("- **Package dependencies:** %s\n", intChange(first.Dependencies, last.Dependencies)))
+	out.WriteString(fmt.Sprintf("- **Avg instability:** %s\n", floatChange(first.AvgInstability, last.AvgInstability)))
+
+	var added, dropped []string
+	for _, file := range last.Hotspots {
+		if !slices.Contains(first.Hotspots, file) {
+			added = append(added, "`"+file+"`")
+		}
+	}
+	for _, file := range first.Hotspots {
+		if !slices.Contains(last.Hotspots, file) {
+			dropped = append(dropped, "`"+file+"`")
+		}
+	}
+	if len(added) > 0 {
+		out.WriteString(fmt.Sprintf("- **New hotspots:** %s\n", strings.Join(added, ", ")))
+	}
+	if len(dropped) > 0 {
+		out.WriteString(fmt.Sprintf("- **No longer hotspots:** %s\n", strings.Join(dropped, ", ")))
+	}
+	return out.String()
+}
+
+func intChange(from, to int) string {
+	return fmt.Sprintf("%d → %d (%+d)", from, to, to-from)
+}
+
+func floatChange(from, to float64) string {
+	return fmt.Sprintf("%.2f → %.2f (%+.2f)", from, to, to-from)
+}
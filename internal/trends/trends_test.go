@@ -0,0 +1,85 @@
+package trends
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/internal/analyzer"
+	"github.com/nuthan-ms/codecontext/internal/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTake(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"src/util.js":   "export function clamp(x) {\n  if (x < 0 || x > 1) {\n    return 0\n  }\n  return x\n}\n",
+		"src/a.js":      "import { clamp } from './util'\nexport function a() { return clamp(1) }\n",
+		"src/b.js":      "import { clamp } from './util'\nexport function b() { return clamp(2) }\n",
+		"src/b.test.js": "import { b } from './b'\n",
+	}
+	testutils.WriteTree(t, dir, files)
+	graph, err := analyzer.NewGraphBuilder().AnalyzeDirectory(dir)
+	require.NoError(t, err)
+
+	snapshot := Take(graph, dir)
+	assert.Empty(t, snapshot.Commit, "not a git repository")
+	assert.Equal(t, 3, snapshot.Files, "test files are left out")
+	assert.Equal(t, 3, snapshot.Functions)
+	assert.Equal(t, 3, snapshot.MaxComplexity)
+	assert.Equal(t, 1.67, snapshot.AvgComplexity)
+	assert.Equal(t, "src/util.js", snapshot.Hotspots[0])
+	assert.Equal(t, snapshot.Time.Format("2006-01-02 15:04"), snapshot.Label())
+}
+
+func TestRecordAndRecent(t *testing.T) {
+	dir := t.TempDir()
+
+	history, err := Load(dir)
+	require.NoError(t, err)
+	assert.Empty(t, history.Snapshots)
+
+	day := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	runs := []Snapshot{
+		{Time: day, Commit: "1111111aaa", Tags: []string{"v1.0.0"}, Files: 10, AvgComplexity: 2.5, Hotspots: []string{"db.go", "api.go"}},
+		{Time: day.Add(time.Hour), Commit: "1111111aaa", Tags: []string{"v1.0.0"}, Files: 11, AvgComplexity: 2.5, Hotspots: []string{"db.go", "api.go"}},
+		{Time: day.Add(2 * time.Hour), Commit: "2222222bbb", Files: 12, AvgComplexity: 3},
+		{Time: day.Add(3 * time.Hour), Commit: "3333333ccc", Tags: []string{"v1.1.0"}, Files: 14, AvgComplexity: 3.25, Hotspots: []string{"db.go", "cache.go"}},
+	}
+	for _, run := range runs {
+		require.NoError(t, Record(dir, run))
+	}
+	assert.FileExists(t, filepath.Join(dir, ".codecontext", "trends.json"))
+
+	history, err = Load(dir)
+	require.NoError(t, err)
+	assert.Len(t, history.Snapshots, 4)
+	assert.Equal(t, []Snapshot{runs[2], runs[3]}, history.Recent(2, false))
+
+	// One snapshot per tagged commit, the latest
+	tagged := history.Recent(0, true)
+	require.Len(t, tagged, 2)
+	assert.Equal(t, 11, tagged[0].Files)
+	assert.Equal(t, "v1.1.0", tagged[1].Label())
+
+	report := Markdown(tagged)
+	assert.Contains(t, report, "| v1.0.0 | 2026-03-01 10:00 | 11 |")
+	assert.Contains(t, report, "### Change since v1.0.0")
+	assert.Contains(t, report, "- **Files:** 11 → 14 (+3)")
+	assert.Contains(t, report, "- **Avg complexity:** 2.50 → 3.25 (+0.75)")
+	assert.Contains(t, report, "- **New hotspots:** `cache.go`")
+	assert.Contains(t, report, "- **No longer hotspots:** `api.go`")
+	assert.Equal(t, "2222222", runs[2].Label())
+}
+
+func TestRecordKeepsLatestSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < MaxSnapshots+3; i++ {
+		require.NoError(t, Record(dir, Snapshot{Files: i}))
+	}
+	history, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, history.Snapshots, MaxSnapshots)
+	assert.Equal(t, 3, history.Snapshots[0].Files)
+}
@@ -30,6 +30,8 @@ type FileWatcher struct {
 	// Configuration
 	excludePatterns []string
 	includeExts     []string
+	include         func(path string) bool
+	onChange        func([]FileChange)
 }
 
 // FileChange represents a file system change event
@@ -42,10 +44,12 @@ type FileChange struct {
 // Config holds configuration for the file watcher
 type Config struct {
 	TargetDir       string
-	OutputFile      string
+	OutputFile      string // Context map rewritten on each change, empty to only call OnChange
 	DebounceTime    time.Duration
 	ExcludePatterns []string
 	IncludeExts     []string
+	Include         func(path string) bool // Reports whether a changed file is watched, instead of IncludeExts
+	OnChange        func([]FileChange)     // Called with each debounced batch before it is processed
 }
 
 // NewFileWatcher creates a new file watcher instance
@@ -86,6 +90,8 @@ func NewFileWatcher(config Config) (*FileWatcher, error) {
 		done:            make(chan struct{}),
 		excludePatterns: config.ExcludePatterns,
 		includeExts:     config.IncludeExts,
+		include:         config.Include,
+		onChange:        config.OnChange,
 	}, nil
 }
 
@@ -121,7 +127,9 @@ func (fw *FileWatcher) Start(ctx context.Context) error {
 
 	log.Printf("🔍 File watcher started for: %s", fw.targetDir)
 	log.Printf("   Debounce time: %v", fw.debounce)
-	log.Printf("   Watching extensions: %v", fw.includeExts)
+	if fw.include == nil {
+		log.Printf("   Watching extensions: %v", fw.includeExts)
+	}
 
 	return nil
 }
@@ -195,6 +203,9 @@ func (fw *FileWatcher) shouldExclude(path string) bool {
 
 // shouldInclude checks if a file should be included based on extension
 func (fw *FileWatcher) shouldInclude(path string) bool {
+	if fw.include != nil {
+		return fw.include(path)
+	}
 	ext := filepath.Ext(path)
 	for _, includeExt := range fw.includeExts {
 		if ext == includeExt {
@@ -270,9 +281,13 @@ func (fw *FileWatcher) processChanges(ctx context.Context) {
 
 		case <-timer.C:
 			if len(pendingChanges) > 0 {
-				err := fw.processFileChanges(pendingChanges)
-				if err != nil {
-					log.Printf("❌ Error processing file changes: %v", err)
+				if fw.onChange != nil {
+					fw.onChange(pendingChanges)
+				}
+				if fw.outputFile != "" {
+					if err := fw.processFileChanges(pendingChanges); err != nil {
+						log.Printf("❌ Error processing file changes: %v", err)
+					}
 				}
 				pendingChanges = nil
 			}
@@ -298,12 +313,8 @@ func (fw *FileWatcher) processFileChanges(changes []FileChange) error {
 		return fmt.Errorf("failed to analyze directory: %w", err)
 	}
 
-	// Generate updated context map
-	generator := analyzer.NewMarkdownGenerator(graph)
-	content := generator.GenerateContextMap()
-
-	// Write to output file
-	err = fw.writeOutput(content)
+	// Stream the updated context map into the output file
+	err = fw.writeOutput(analyzer.NewMarkdownGenerator(graph))
 	if err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
@@ -315,7 +326,15 @@ func (fw *FileWatcher) processFileChanges(changes []FileChange) error {
 	return nil
 }
 
-// writeOutput writes the generated content to the output file
-func (fw *FileWatcher) writeOutput(content string) error {
-	return os.WriteFile(fw.outputFile, []byte(content), 0644)
+// writeOutput writes the generated context map to the output file
+func (fw *FileWatcher) writeOutput(generator *analyzer.MarkdownGenerator) error {
+	f, err := os.Create(fw.outputFile)
+	if err != nil {
+		return err
+	}
+	if err := generator.WriteContextMap(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
@@ -183,12 +183,14 @@ export function hello() {
 	}
 
 	outputFile := filepath.Join(tmpDir, "output.md")
+	batches := make(chan []FileChange, 10)
 
 	// Create watcher
 	config := Config{
 		TargetDir:    tmpDir,
 		OutputFile:   outputFile,
 		DebounceTime: 100 * time.Millisecond,
+		OnChange:     func(changes []FileChange) { batches <- changes },
 	}
 
 	watcher, err := NewFileWatcher(config)
@@ -226,6 +228,16 @@ export function goodbye() {
 	// Wait for debounce and processing
 	time.Sleep(500 * time.Millisecond)
 
+	// Check the change was reported before processing
+	select {
+	case changes := <-batches:
+		if len(changes) == 0 || changes[0].Path != testFile {
+			t.Errorf("OnChange got %v, want a change to %s", changes, testFile)
+		}
+	default:
+		t.Error("OnChange was not called")
+	}
+
 	// Check if output file was created/updated
 	if _, err := os.Stat(outputFile); os.IsNotExist(err) {
 		t.Error("Output file was not created")
@@ -0,0 +1,182 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// DefaultExternalTimeout bounds a single request to an external plugin process
+const DefaultExternalTimeout = 60 * time.Second
+
+// External protocol methods
+const (
+	MethodDescribe = "describe"
+	MethodAnalyze  = "analyze"
+	MethodCallTool = "call_tool"
+)
+
+// ExternalConfig configures a plugin that runs as a separate process
+type ExternalConfig struct {
+	Name    string        `json:"name" mapstructure:"name"`
+	Command string        `json:"command" mapstructure:"command"`
+	Args    []string      `json:"args,omitempty" mapstructure:"args"`
+	Dir     string        `json:"dir,omitempty" mapstructure:"dir"` // Working directory, defaults to the current one
+	Timeout time.Duration `json:"timeout,omitempty" mapstructure:"timeout"`
+}
+
+// Request is written as JSON to the plugin's stdin; the process handles one request and exits
+type Request struct {
+	Method    string                 `json:"method"`
+	Graph     *Graph                 `json:"graph,omitempty"`     // analyze and call_tool
+	Tool      string                 `json:"tool,omitempty"`      // call_tool
+	Arguments map[string]interface{} `json:"arguments,omitempty"` // call_tool
+}
+
+// Graph is the view of the code graph sent to external plugins
+type Graph struct {
+	Files   map[string]*types.FileNode        `json:"files"`
+	Symbols map[types.SymbolId]*types.Symbol  `json:"symbols"`
+	Edges   map[types.EdgeId]*types.GraphEdge `json:"edges"`
+	Nodes   map[types.NodeId]*types.GraphNode `json:"nodes,omitempty"`
+}
+
+// Response is read as JSON from the plugin's stdout
+type Response struct {
+	Error        string        `json:"error,omitempty"`
+	Tools        []Tool        `json:"tools,omitempty"`        // describe
+	Contribution *Contribution `json:"contribution,omitempty"` // analyze
+	Text         string        `json:"text,omitempty"`         // call_tool
+}
+
+// External is an Analyzer and ToolProvider backed by an external process speaking the
+// JSON request/response protocol above, so plugins can be written in any language
+type External struct {
+	config ExternalConfig
+}
+
+// NewExternal creates an external plugin from its configuration
+func NewExternal(config ExternalConfig) (*External, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("external plugin has no name")
+	}
+	if config.Command == "" {
+		return nil, fmt.Errorf("external plugin %s has no command", config.Name)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultExternalTimeout
+	}
+	return &External{config: config}, nil
+}
+
+// LoadExternal creates the configured external plugins, skipping invalid entries
+func LoadExternal(configs []ExternalConfig) ([]Analyzer, []error) {
+	var analyzers []Analyzer
+	var errs []error
+	for _, config := range configs {
+		external, err := NewExternal(config)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		analyzers = append(analyzers, external)
+	}
+	return analyzers, errs
+}
+
+// Name returns the configured plugin name
+func (e *External) Name() string {
+	return e.config.Name
+}
+
+// Analyze sends the graph to the plugin and returns its contribution
+func (e *External) Analyze(ctx context.Context, graph *types.CodeGraph) (*Contribution, error) {
+	response, err := e.call(ctx, &Request{Method: MethodAnalyze, Graph: graphView(graph)})
+	if err != nil {
+		return nil, err
+	}
+	return response.Contribution, nil
+}
+
+// Tools asks the plugin which MCP tools it provides. A plugin that fails to describe
+// itself contributes no tools.
+func (e *External) Tools() []Tool {
+	response, err := e.call(context.Background(), &Request{Method: MethodDescribe})
+	if err != nil {
+		log.Printf("[plugin] %s: describe failed: %v", e.config.Name, err)
+		return nil
+	}
+
+	tools := make([]Tool, 0, len(response.Tools))
+	for _, tool := range response.Tools {
+		if tool.Name == "" {
+			continue
+		}
+		toolName := tool.Name
+		tool.Handler = func(ctx context.Context, graph *types.CodeGraph, args map[string]interface{}) (string, error) {
+			response, err := e.call(ctx, &Request{
+				Method:    MethodCallTool,
+				Graph:     graphView(graph),
+				Tool:      toolName,
+				Arguments: args,
+			})
+			if err != nil {
+				return "", err
+			}
+			return response.Text, nil
+		}
+		tools = append(tools, tool)
+	}
+	return tools
+}
+
+// call runs the plugin process for a single request
+func (e *External) call(ctx context.Context, request *Request) (*Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.config.Timeout)
+	defer cancel()
+
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", request.Method, err)
+	}
+
+	cmd := exec.CommandContext(ctx, e.config.Command, e.config.Args...)
+	cmd.Dir = e.config.Dir
+	cmd.Stdin = bytes.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s timed out after %v", request.Method, e.config.Timeout)
+		}
+		return nil, fmt.Errorf("%s failed: %w: %s", request.Method, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var response Response
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("invalid %s response: %w", request.Method, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s", response.Error)
+	}
+	return &response, nil
+}
+
+// graphView strips analysis metadata that is not part of the plugin protocol
+func graphView(graph *types.CodeGraph) *Graph {
+	return &Graph{
+		Files:   graph.Files,
+		Symbols: graph.Symbols,
+		Edges:   graph.Edges,
+		Nodes:   graph.Nodes,
+	}
+}
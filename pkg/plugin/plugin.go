@@ -0,0 +1,189 @@
+// Package plugin lets third parties extend codecontext without forking it.
+//
+// A plugin is an Analyzer that receives the CodeGraph once the built-in analysis has
+// finished and returns a Contribution of extra nodes, edges and metadata. Analyzers that
+// also implement ToolProvider expose additional MCP tools.
+//
+// Plugins are either compiled in, by calling Register from an init function of a package
+// imported into the codecontext binary, or run as external processes configured under
+// "plugins" in .codecontext/config.yaml (see External).
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+)
+
+// Analyzer is a custom analysis pass over the code graph
+type Analyzer interface {
+	// Name identifies the plugin; it namespaces contributed metadata and tool names
+	Name() string
+
+	// Analyze inspects the graph and returns what the plugin adds to it. The graph must be
+	// treated as read-only; changes are only applied through the returned Contribution.
+	Analyze(ctx context.Context, graph *types.CodeGraph) (*Contribution, error)
+}
+
+// Contribution is what an analyzer adds to the graph
+type Contribution struct {
+	Nodes []*types.GraphNode `json:"nodes,omitempty"`
+	Edges []*types.GraphEdge `json:"edges,omitempty"`
+
+	// SymbolMetadata is merged into the metadata of existing symbols
+	SymbolMetadata map[types.SymbolId]map[string]interface{} `json:"symbol_metadata,omitempty"`
+
+	// Metadata is stored under graph.Metadata.Configuration["plugin:<name>"]
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Tool is an MCP tool contributed by a plugin
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Handler renders the tool result as markdown. args holds the raw tool arguments;
+	// "target_dir" is handled by the server before the handler is called.
+	Handler func(ctx context.Context, graph *types.CodeGraph, args map[string]interface{}) (string, error) `json:"-"`
+}
+
+// ToolProvider is implemented by analyzers that expose MCP tools
+type ToolProvider interface {
+	Tools() []Tool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Analyzer)
+)
+
+// Register makes a compiled-in analyzer available to every analysis.
+// It panics if the analyzer is nil or its name is empty or already registered.
+func Register(analyzer Analyzer) {
+	if analyzer == nil {
+		panic("plugin: Register analyzer is nil")
+	}
+	name := analyzer.Name()
+	if name == "" {
+		panic("plugin: Register analyzer has no name")
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic("plugin: Register called twice for " + name)
+	}
+	registry[name] = analyzer
+}
+
+// Registered returns the compiled-in analyzers sorted by name
+func Registered() []Analyzer {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	analyzers := make([]Analyzer, 0, len(registry))
+	for _, analyzer := range registry {
+		analyzers = append(analyzers, analyzer)
+	}
+	sort.Slice(analyzers, func(i, j int) bool {
+		return analyzers[i].Name() < analyzers[j].Name()
+	})
+	return analyzers
+}
+
+// MetadataKey returns the graph configuration key holding a plugin's metadata
+func MetadataKey(name string) string {
+	return "plugin:" + name
+}
+
+// Apply runs the analyzers in order and merges their contributions into the graph.
+// A failing analyzer does not stop the others; its error is returned alongside theirs.
+func Apply(ctx context.Context, graph *types.CodeGraph, analyzers []Analyzer) []error {
+	var errs []error
+	for _, analyzer := range analyzers {
+		contribution, err := analyzer.Analyze(ctx, graph)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", analyzer.Name(), err))
+			continue
+		}
+		if contribution == nil {
+			continue
+		}
+		if err := merge(graph, analyzer.Name(), contribution); err != nil {
+			errs = append(errs, fmt.Errorf("plugin %s: %w", analyzer.Name(), err))
+		}
+	}
+	return errs
+}
+
+// merge adds a contribution to the graph. Contributed nodes and edges are tagged with the
+// plugin name and never replace ones produced by the built-in analysis.
+func merge(graph *types.CodeGraph, name string, contribution *Contribution) error {
+	var conflicts []string
+
+	for _, node := range contribution.Nodes {
+		if node == nil || node.Id == "" {
+			continue
+		}
+		if _, exists := graph.Nodes[node.Id]; exists {
+			conflicts = append(conflicts, string(node.Id))
+			continue
+		}
+		node.Metadata = withPlugin(node.Metadata, name)
+		graph.Nodes[node.Id] = node
+	}
+
+	for _, edge := range contribution.Edges {
+		if edge == nil || edge.From == "" || edge.To == "" || edge.Type == "" {
+			continue
+		}
+		if edge.Id == "" {
+			edge.Id = types.EdgeId(fmt.Sprintf("plugin-%s-%s-%s-%s", name, edge.Type, edge.From, edge.To))
+		}
+		if _, exists := graph.Edges[edge.Id]; exists {
+			conflicts = append(conflicts, string(edge.Id))
+			continue
+		}
+		if edge.Weight == 0 {
+			edge.Weight = 1.0
+		}
+		edge.Metadata = withPlugin(edge.Metadata, name)
+		graph.Edges[edge.Id] = edge
+	}
+
+	for symbolId, metadata := range contribution.SymbolMetadata {
+		symbol, exists := graph.Symbols[symbolId]
+		if !exists {
+			continue
+		}
+		for key, value := range metadata {
+			symbol.SetMetadata(key, value)
+		}
+	}
+
+	if len(contribution.Metadata) > 0 {
+		if graph.Metadata == nil {
+			graph.Metadata = &types.GraphMetadata{}
+		}
+		if graph.Metadata.Configuration == nil {
+			graph.Metadata.Configuration = make(map[string]interface{})
+		}
+		graph.Metadata.Configuration[MetadataKey(name)] = contribution.Metadata
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("skipped %d nodes/edges that already exist: %v", len(conflicts), conflicts)
+	}
+	return nil
+}
+
+func withPlugin(metadata map[string]interface{}, name string) map[string]interface{} {
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["plugin"] = name
+	return metadata
+}
@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nuthan-ms/codecontext/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAnalyzer struct {
+	name         string
+	contribution *Contribution
+	err          error
+}
+
+func (f *fakeAnalyzer) Name() string { return f.name }
+
+func (f *fakeAnalyzer) Analyze(ctx context.Context, graph *types.CodeGraph) (*Contribution, error) {
+	return f.contribution, f.err
+}
+
+func newGraph() *types.CodeGraph {
+	return &types.CodeGraph{
+		Nodes: map[types.NodeId]*types.GraphNode{"file-app.rb": {Id: "file-app.rb"}},
+		Edges: map[types.EdgeId]*types.GraphEdge{"import-a-b": {Id: "import-a-b", From: "file-a", To: "file-b", Type: "imports"}},
+		Files: map[string]*types.FileNode{"app.rb": {Path: "app.rb", Language: "ruby"}},
+		Symbols: map[types.SymbolId]*types.Symbol{
+			"sym-index": {Id: "sym-index", Name: "index"},
+		},
+		Metadata: &types.GraphMetadata{},
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		registry = make(map[string]Analyzer)
+		registryMu.Unlock()
+	})
+
+	Register(&fakeAnalyzer{name: "zeta"})
+	Register(&fakeAnalyzer{name: "alpha"})
+
+	analyzers := Registered()
+	require.Len(t, analyzers, 2)
+	assert.Equal(t, "alpha", analyzers[0].Name())
+	assert.Equal(t, "zeta", analyzers[1].Name())
+
+	assert.Panics(t, func() { Register(&fakeAnalyzer{name: "alpha"}) })
+	assert.Panics(t, func() { Register(&fakeAnalyzer{}) })
+	assert.Panics(t, func() { Register(nil) })
+}
+
+func TestApply(t *testing.T) {
+	graph := newGraph()
+	routes := &fakeAnalyzer{name: "routes", contribution: &Contribution{
+		Nodes: []*types.GraphNode{
+			{Id: "route-GET /users", Type: "route", Label: "GET /users"},
+			{Id: "file-app.rb"}, // Already exists
+		},
+		Edges: []*types.GraphEdge{
+			{From: "route-GET /users", To: "symbol-sym-index", Type: "routes-to"},
+			{From: "route-GET /users", Type: "incomplete"},
+		},
+		SymbolMetadata: map[types.SymbolId]map[string]interface{}{
+			"sym-index":   {"rails_action": true},
+			"sym-missing": {"ignored": true},
+		},
+		Metadata: map[string]interface{}{"routes": 1},
+	}}
+	broken := &fakeAnalyzer{name: "broken", err: errors.New("boom")}
+	empty := &fakeAnalyzer{name: "empty"}
+
+	errs := Apply(context.Background(), graph, []Analyzer{routes, broken, empty})
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0].Error(), "plugin routes: skipped 1")
+	assert.EqualError(t, errs[1], "plugin broken: boom")
+
+	node := graph.Nodes["route-GET /users"]
+	require.NotNil(t, node)
+	assert.Equal(t, "routes", node.Metadata["plugin"])
+	assert.Nil(t, graph.Nodes["file-app.rb"].Metadata, "existing nodes are not replaced")
+
+	edge := graph.Edges["plugin-routes-routes-to-route-GET /users-symbol-sym-index"]
+	require.NotNil(t, edge)
+	assert.Equal(t, 1.0, edge.Weight)
+	assert.Equal(t, "routes", edge.Metadata["plugin"])
+	assert.Len(t, graph.Edges, 2, "incomplete edges are dropped")
+
+	assert.Equal(t, true, graph.Symbols["sym-index"].Metadata["rails_action"])
+	assert.Equal(t, map[string]interface{}{"routes": 1}, graph.Metadata.Configuration[MetadataKey("routes")])
+}
+
+// TestHelperProcess is not a real test: it is the external plugin process run by the
+// External tests below.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("CODECONTEXT_PLUGIN_HELPER") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	var request Request
+	if err := json.NewDecoder(os.Stdin).Decode(&request); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	var response Response
+	switch request.Method {
+	case MethodDescribe:
+		response.Tools = []Tool{{Name: "file_count", Description: "Count files"}}
+	case MethodAnalyze:
+		response.Contribution = &Contribution{Metadata: map[string]interface{}{"files": len(request.Graph.Files)}}
+	case MethodCallTool:
+		response.Text = fmt.Sprintf("%s: %d files, verbose=%v", request.Tool, len(request.Graph.Files), request.Arguments["verbose"])
+	default:
+		response.Error = "unknown method " + request.Method
+	}
+	json.NewEncoder(os.Stdout).Encode(response)
+}
+
+func helperPlugin(t *testing.T) *External {
+	t.Helper()
+	t.Setenv("CODECONTEXT_PLUGIN_HELPER", "1")
+	external, err := NewExternal(ExternalConfig{
+		Name:    "helper",
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperProcess"},
+		Timeout: 30 * time.Second,
+	})
+	require.NoError(t, err)
+	return external
+}
+
+func TestExternalAnalyze(t *testing.T) {
+	graph := newGraph()
+	errs := Apply(context.Background(), graph, []Analyzer{helperPlugin(t)})
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{"files": float64(1)}, graph.Metadata.Configuration[MetadataKey("helper")])
+}
+
+func TestExternalTools(t *testing.T) {
+	tools := helperPlugin(t).Tools()
+	require.Len(t, tools, 1)
+	assert.Equal(t, "file_count", tools[0].Name)
+
+	text, err := tools[0].Handler(context.Background(), newGraph(), map[string]interface{}{"verbose": true})
+	require.NoError(t, err)
+	assert.Equal(t, "file_count: 1 files, verbose=true", text)
+}
+
+func TestExternalErrors(t *testing.T) {
+	_, err := NewExternal(ExternalConfig{Name: "missing-command"})
+	assert.Error(t, err)
+
+	analyzers, errs := LoadExternal([]ExternalConfig{{Command: "true"}, {Name: "ok", Command: "true"}})
+	assert.Len(t, analyzers, 1)
+	assert.Len(t, errs, 1)
+
+	failing, err := NewExternal(ExternalConfig{Name: "failing", Command: "sh", Args: []string{"-c", "echo broken >&2; exit 3"}})
+	require.NoError(t, err)
+	_, err = failing.Analyze(context.Background(), newGraph())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broken")
+	assert.Empty(t, failing.Tools())
+}
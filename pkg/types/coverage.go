@@ -0,0 +1,50 @@
+package types
+
+// Coverage is the test coverage of a file or symbol, imported from an
+// external report (lcov, Cobertura or a Go coverprofile)
+type Coverage struct {
+	Lines            int `json:"lines"`                       // Executable lines
+	CoveredLines     int `json:"covered_lines"`               // Executable lines run by the tests
+	Functions        int `json:"functions,omitempty"`         // Functions and methods
+	CoveredFunctions int `json:"covered_functions,omitempty"` // Functions and methods entered by the tests
+}
+
+// LinePercent returns the share of covered lines, from 0 to 100. Code
+// without executable lines counts as fully covered.
+func (c *Coverage) LinePercent() float64 {
+	if c.Lines == 0 {
+		return 100
+	}
+	return float64(c.CoveredLines) * 100 / float64(c.Lines)
+}
+
+// Untested returns the share of executable lines no test runs, from 0 to 1.
+// Unknown coverage counts as tested, so weights only grow with evidence.
+func (c *Coverage) Untested() float64 {
+	if c == nil || c.Lines == 0 {
+		return 0
+	}
+	return float64(c.Lines-c.CoveredLines) / float64(c.Lines)
+}
+
+// MutationScore counts the mutants of a file or symbol by outcome, imported
+// from a mutation testing report (Stryker, go-mutesting)
+type MutationScore struct {
+	Killed     int `json:"killed"`                // Caught by a failing test
+	Survived   int `json:"survived"`              // Tests ran the mutated code and passed
+	NoCoverage int `json:"no_coverage,omitempty"` // No test ran the mutated code
+}
+
+// Total returns the number of tested mutants
+func (m *MutationScore) Total() int {
+	return m.Killed + m.Survived + m.NoCoverage
+}
+
+// Percent returns the share of killed mutants, from 0 to 100. Code without
+// mutants counts as fully tested.
+func (m *MutationScore) Percent() float64 {
+	if m.Total() == 0 {
+		return 100
+	}
+	return float64(m.Killed) * 100 / float64(m.Total())
+}
@@ -45,6 +45,17 @@ const (
 	SymbolTypeTemplate     SymbolType = "template"     // C++ templates
 	SymbolTypeCppTypedef   SymbolType = "cpp_typedef"  // C++ typedefs
 	SymbolTypeCppUsing     SymbolType = "cpp_using"    // C++ using declarations
+	SymbolTypeMacro        SymbolType = "macro"        // C/C++ preprocessor macros
+	SymbolTypeModule       SymbolType = "module"       // C++20 module units
+
+	// Configuration symbol types
+	SymbolTypeConfigKey SymbolType = "config_key" // YAML/JSON keys and Helm chart values
+	SymbolTypeResource  SymbolType = "resource"   // Kubernetes resources and Helm charts
+
+	// Embedded language symbol types, found in strings of other languages
+	SymbolTypeTable   SymbolType = "table"   // SQL tables and views
+	SymbolTypeQuery   SymbolType = "query"   // GraphQL operations and fragments, named SQL queries
+	SymbolTypeElement SymbolType = "element" // HTML elements with an id
 )
 
 // FileLocation represents a location in a file
@@ -58,18 +69,23 @@ type FileLocation struct {
 
 // Symbol represents a code symbol
 type Symbol struct {
-	Id                 SymbolId   `json:"id"`
-	Name               string     `json:"name"`
-	Type               SymbolType `json:"type"`
-	Kind               string     `json:"kind"` // For diff compatibility
-	FullyQualifiedName string     `json:"fully_qualified_name"`
-	Location           Location   `json:"location"`
-	Signature          string     `json:"signature,omitempty"`
-	Documentation      string     `json:"documentation,omitempty"`
-	Visibility         string     `json:"visibility,omitempty"`
-	Language           string     `json:"language"`
-	Hash               string     `json:"hash"`
-	LastModified       time.Time  `json:"last_modified"`
+	Id                 SymbolId       `json:"id"`
+	Name               string         `json:"name"`
+	Type               SymbolType     `json:"type"`
+	Kind               string         `json:"kind"` // For diff compatibility
+	FullyQualifiedName string         `json:"fully_qualified_name"`
+	Location           Location       `json:"location"`
+	Signature          string         `json:"signature,omitempty"`
+	Documentation      string         `json:"documentation,omitempty"`
+	Visibility         string         `json:"visibility,omitempty"`
+	Language           string         `json:"language"`
+	Hash               string         `json:"hash"`
+	LastModified       time.Time      `json:"last_modified"`
+	Coverage           *Coverage      `json:"coverage,omitempty"` // Nil unless a coverage report was imported
+	Mutants            *MutationScore `json:"mutants,omitempty"`  // Nil unless a mutation testing report was imported
+
+	// Metadata holds language-specific details such as supertypes or decorators
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // GraphNode represents a node in the code graph
@@ -151,12 +167,40 @@ type PropertyChange struct {
 	NewValue interface{} `json:"new_value"`
 }
 
+// ImportKind says how a module is imported
+type ImportKind string
+
+const (
+	ImportKindNamed      ImportKind = "named"       // import { a } from "m"
+	ImportKindDefault    ImportKind = "default"     // import a from "m"
+	ImportKindNamespace  ImportKind = "namespace"   // import * as m from "m"
+	ImportKindTypeOnly   ImportKind = "type_only"   // import type { T } from "m"
+	ImportKindSideEffect ImportKind = "side_effect" // import "m"
+	ImportKindDynamic    ImportKind = "dynamic"     // import("m")
+	ImportKindRequire    ImportKind = "require"     // require("m")
+	ImportKindReexport   ImportKind = "reexport"    // export { a } from "m"
+	ImportKindModule     ImportKind = "module"      // C++20 import m;
+	ImportKindHeaderUnit ImportKind = "header_unit" // C++20 import <vector>;
+)
+
+// ImportKinds lists the import kinds
+var ImportKinds = []ImportKind{
+	ImportKindNamed, ImportKindDefault, ImportKindNamespace, ImportKindTypeOnly,
+	ImportKindSideEffect, ImportKindDynamic, ImportKindRequire, ImportKindReexport,
+	ImportKindModule, ImportKindHeaderUnit,
+}
+
 // Import represents an import statement
 type Import struct {
 	Path       string       `json:"path"`
 	Alias      string       `json:"alias,omitempty"`
 	Specifiers []string     `json:"specifiers,omitempty"`
 	IsDefault  bool         `json:"is_default"`
+	IsSystem   bool         `json:"is_system,omitempty"` // C/C++ angle-bracket include
+	Kind       ImportKind   `json:"kind,omitempty"`      // Set for JavaScript, TypeScript and C++20 module imports
+	Module     bool         `json:"module,omitempty"`    // C++20 named module import, resolved by module name rather than path
+	Lazy       bool         `json:"lazy,omitempty"`      // Loaded at runtime rather than when the importing module loads
+	Loader     string       `json:"loader,omitempty"`    // Code-splitting wrapper of a lazy import, such as "React.lazy" or "route"
 	Location   FileLocation `json:"location"`
 }
 
@@ -195,17 +239,22 @@ type GraphEdge struct {
 
 // FileNode represents a file in the codebase
 type FileNode struct {
-	Path         string     `json:"path"`
-	Language     string     `json:"language"`
-	Size         int        `json:"size"`
-	Lines        int        `json:"lines"`
-	SymbolCount  int        `json:"symbol_count"`
-	ImportCount  int        `json:"import_count"`
-	IsTest       bool       `json:"is_test"`
-	IsGenerated  bool       `json:"is_generated"`
-	LastModified time.Time  `json:"last_modified"`
-	Symbols      []SymbolId `json:"symbols"`
-	Imports      []*Import  `json:"imports"`
+	Path         string         `json:"path"`
+	Language     string         `json:"language"`
+	Size         int            `json:"size"`
+	Lines        int            `json:"lines"`
+	SymbolCount  int            `json:"symbol_count"`
+	ImportCount  int            `json:"import_count"`
+	IsTest       bool           `json:"is_test"`
+	IsGenerated  bool           `json:"is_generated"`
+	LastModified time.Time      `json:"last_modified"`
+	Coverage     *Coverage      `json:"coverage,omitempty"` // Nil unless a coverage report was imported
+	Mutants      *MutationScore `json:"mutants,omitempty"`  // Nil unless a mutation testing report was imported
+	PartOf       string         `json:"part_of,omitempty"`  // Dart library this part file belongs to
+	Parts        []string       `json:"parts,omitempty"`    // Dart part files of this library
+	Summary      string         `json:"summary,omitempty"`  // Natural language summary, empty unless a summarizer is configured
+	Symbols      []SymbolId     `json:"symbols"`
+	Imports      []*Import      `json:"imports"`
 }
 
 // FileInfo represents file information for diff operations
@@ -0,0 +1,12 @@
+package types
+
+// EmbeddedMetadataKey holds the language of the host file for symbols written
+// in a language embedded in it, such as a SQL table created from a Go string
+// or a GraphQL query in a JavaScript tagged template. Symbol.Language is the
+// embedded language.
+const EmbeddedMetadataKey = "embedded_in"
+
+// IsEmbedded reports whether a symbol was found in a string of another language
+func (s *Symbol) IsEmbedded() bool {
+	return s.MetadataString(EmbeddedMetadataKey) != ""
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// QualifiedNameSeparator separates the file path from the symbol path in a fully qualified name
+const QualifiedNameSeparator = "::"
+
+// QualifiedName builds a fully qualified name for a symbol declared in filePath,
+// which analyses give relative to the analyzed directory.
+// Nested scopes (namespaces, classes) are passed as additional parts, outermost first.
+func QualifiedName(filePath string, parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return filePath + QualifiedNameSeparator + strings.Join(nonEmpty, ".")
+}
+
+// FilePathFromQualifiedName returns the file portion of a fully qualified name.
+// Names without a separator are returned unchanged for backward compatibility.
+func FilePathFromQualifiedName(fqn string) string {
+	if idx := strings.Index(fqn, QualifiedNameSeparator); idx != -1 {
+		return fqn[:idx]
+	}
+	return fqn
+}
+
+// StableSymbolId derives a symbol ID from its type, fully qualified name and signature.
+// Unlike line-based IDs, it does not change when unrelated edits shift the symbol
+// up or down in the file, so external references survive across runs.
+func StableSymbolId(symbolType SymbolType, fqn, signature string) SymbolId {
+	// Normalize whitespace so reformatting a signature doesn't change the ID
+	normalized := strings.Join(strings.Fields(signature), " ")
+
+	sum := sha256.Sum256([]byte(string(symbolType) + "\x00" + fqn + "\x00" + normalized))
+	return SymbolId(fmt.Sprintf("%s-%s-%s", symbolType, fqn, hex.EncodeToString(sum[:4])))
+}
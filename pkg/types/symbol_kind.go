@@ -0,0 +1,173 @@
+package types
+
+import "strings"
+
+// Normalized symbol kinds, shared by every language. Symbol.Type keeps the
+// syntactic or framework type a parser reported ("hook", "widget",
+// "cpp_typedef"); Symbol.Kind holds one of these so the same filter finds
+// functions, classes or enums in any language. The language-specific type is
+// kept in the "subtype" metadata key.
+const (
+	SymbolKindFunction  = "function"
+	SymbolKindMethod    = "method"
+	SymbolKindClass     = "class"
+	SymbolKindInterface = "interface"
+	SymbolKindEnum      = "enum"
+	SymbolKindConstant  = "constant"
+	SymbolKindVariable  = "variable"
+	SymbolKindProperty  = "property"
+	SymbolKindTypeAlias = "type-alias"
+	SymbolKindNamespace = "namespace"
+	SymbolKindComponent = "component"
+	SymbolKindMacro     = "macro"
+	SymbolKindImport    = "import"
+	SymbolKindConfig    = "config"
+)
+
+// SymbolKinds lists the normalized kinds
+var SymbolKinds = []string{
+	SymbolKindFunction, SymbolKindMethod, SymbolKindClass, SymbolKindInterface,
+	SymbolKindEnum, SymbolKindConstant, SymbolKindVariable, SymbolKindProperty,
+	SymbolKindTypeAlias, SymbolKindNamespace, SymbolKindComponent, SymbolKindMacro,
+	SymbolKindImport, SymbolKindConfig,
+}
+
+// SubtypeMetadataKey is the metadata key holding the language-specific subtype
+const SubtypeMetadataKey = "subtype"
+
+// symbolTypeKinds maps each symbol type to its normalized kind
+var symbolTypeKinds = map[SymbolType]string{
+	SymbolTypeFunction:  SymbolKindFunction,
+	SymbolTypeClass:     SymbolKindClass,
+	SymbolTypeInterface: SymbolKindInterface,
+	SymbolTypeType:      SymbolKindTypeAlias,
+	SymbolTypeVariable:  SymbolKindVariable,
+	SymbolTypeConstant:  SymbolKindConstant,
+	SymbolTypeImport:    SymbolKindImport,
+	SymbolTypeNamespace: SymbolKindNamespace,
+	SymbolTypeMethod:    SymbolKindMethod,
+	SymbolTypeProperty:  SymbolKindProperty,
+
+	SymbolTypeComponent:  SymbolKindComponent,
+	SymbolTypeHook:       SymbolKindFunction,
+	SymbolTypeDirective:  SymbolKindClass,
+	SymbolTypeService:    SymbolKindClass,
+	SymbolTypeStore:      SymbolKindVariable,
+	SymbolTypeComputed:   SymbolKindProperty,
+	SymbolTypeWatcher:    SymbolKindFunction,
+	SymbolTypeLifecycle:  SymbolKindMethod,
+	SymbolTypeRoute:      SymbolKindFunction,
+	SymbolTypeMiddleware: SymbolKindFunction,
+	SymbolTypeAction:     SymbolKindFunction,
+
+	SymbolTypeConstructor: SymbolKindMethod,
+	SymbolTypeDestructor:  SymbolKindMethod,
+	SymbolTypeOperator:    SymbolKindFunction,
+	SymbolTypeTemplate:    SymbolKindClass,
+	SymbolTypeCppTypedef:  SymbolKindTypeAlias,
+	SymbolTypeCppUsing:    SymbolKindTypeAlias,
+	SymbolTypeMacro:       SymbolKindMacro,
+	SymbolTypeModule:      SymbolKindNamespace,
+
+	SymbolTypeConfigKey: SymbolKindConfig,
+	SymbolTypeResource:  SymbolKindConfig,
+
+	SymbolTypeTable:   SymbolKindClass,
+	SymbolTypeQuery:   SymbolKindFunction,
+	SymbolTypeElement: SymbolKindVariable,
+
+	SymbolTypeMixin:           SymbolKindClass,
+	SymbolTypeExtension:       SymbolKindClass,
+	SymbolTypeEnum:            SymbolKindEnum,
+	SymbolTypeTypedef:         SymbolKindTypeAlias,
+	SymbolTypeWidget:          SymbolKindComponent,
+	SymbolTypeBuildMethod:     SymbolKindMethod,
+	SymbolTypeLifecycleMethod: SymbolKindMethod,
+	SymbolTypeStateClass:      SymbolKindClass,
+}
+
+// subtypeKinds overrides the kind of a symbol type for subtypes that say more,
+// such as a Go "type" declaring an interface or a Rust enum
+var subtypeKinds = map[string]string{
+	"struct":    SymbolKindClass,
+	"record":    SymbolKindClass,
+	"interface": SymbolKindInterface,
+	"protocol":  SymbolKindInterface,
+	"trait":     SymbolKindInterface,
+	"enum":      SymbolKindEnum,
+	"alias":     SymbolKindTypeAlias,
+}
+
+// kindAliases are alternative spellings accepted by kind filters
+var kindAliases = map[string]string{
+	"func":       SymbolKindFunction,
+	"fn":         SymbolKindFunction,
+	"const":      SymbolKindConstant,
+	"var":        SymbolKindVariable,
+	"field":      SymbolKindProperty,
+	"type_alias": SymbolKindTypeAlias,
+	"typealias":  SymbolKindTypeAlias,
+	"alias":      SymbolKindTypeAlias,
+	"module":     SymbolKindNamespace,
+	"package":    SymbolKindNamespace,
+}
+
+// NormalizeSymbolKind returns the normalized kind of a symbol type and an
+// optional language-specific subtype. Unknown types map to themselves.
+func NormalizeSymbolKind(symbolType SymbolType, subtype string) string {
+	if kind, ok := subtypeKinds[subtype]; ok {
+		return kind
+	}
+	if kind, ok := symbolTypeKinds[symbolType]; ok {
+		return kind
+	}
+	return string(symbolType)
+}
+
+// ClassifySymbol sets the normalized Kind of a symbol. When its Type is more
+// specific than the kind ("hook" is a function) and no subtype was recorded
+// yet, the Type is kept as the subtype. A subtype that only repeats the kind
+// is dropped.
+func ClassifySymbol(symbol *Symbol) {
+	subtype := symbol.Subtype()
+	symbol.Kind = NormalizeSymbolKind(symbol.Type, subtype)
+	if subtype != "" && subtype == symbol.Kind {
+		delete(symbol.Metadata, SubtypeMetadataKey)
+		subtype = ""
+	}
+	if subtype == "" && symbol.Type != "" && symbol.Type != SymbolTypeType && string(symbol.Type) != symbol.Kind {
+		if symbol.Metadata == nil {
+			symbol.Metadata = make(map[string]interface{})
+		}
+		symbol.Metadata[SubtypeMetadataKey] = string(symbol.Type)
+	}
+}
+
+// Subtype returns the language-specific subtype of a symbol, such as "struct",
+// "trait" or "hook", or "" when it has none
+func (s *Symbol) Subtype() string {
+	subtype, _ := s.Metadata[SubtypeMetadataKey].(string)
+	return subtype
+}
+
+// NormalizedKind returns the normalized kind of a symbol, deriving it from
+// Type for symbols from snapshots written before Kind was set
+func (s *Symbol) NormalizedKind() string {
+	if s.Kind != "" {
+		return s.Kind
+	}
+	return NormalizeSymbolKind(s.Type, s.Subtype())
+}
+
+// MatchesKind reports whether a symbol matches a kind filter: a normalized
+// kind or one of its aliases ("type_alias", "const"), the symbol's Type or its
+// subtype. Matching ignores case.
+func (s *Symbol) MatchesKind(filter string) bool {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if alias, ok := kindAliases[filter]; ok && s.NormalizedKind() == alias {
+		return true
+	}
+	return filter == s.NormalizedKind() ||
+		filter == strings.ToLower(string(s.Type)) ||
+		filter == strings.ToLower(s.Subtype())
+}
@@ -0,0 +1,70 @@
+package types
+
+import "testing"
+
+func TestClassifySymbol(t *testing.T) {
+	tests := []struct {
+		name        string
+		symbolType  SymbolType
+		subtype     string
+		wantKind    string
+		wantSubtype string
+	}{
+		{"plain function", SymbolTypeFunction, "", SymbolKindFunction, ""},
+		{"react hook", SymbolTypeHook, "", SymbolKindFunction, "hook"},
+		{"flutter widget", SymbolTypeWidget, "", SymbolKindComponent, "widget"},
+		{"c++ typedef", SymbolTypeCppTypedef, "", SymbolKindTypeAlias, "cpp_typedef"},
+		{"go struct", SymbolTypeType, "struct", SymbolKindClass, "struct"},
+		{"go interface drops redundant subtype", SymbolTypeType, "interface", SymbolKindInterface, ""},
+		{"typescript type alias", SymbolTypeType, "", SymbolKindTypeAlias, ""},
+		{"rust trait", SymbolTypeInterface, "trait", SymbolKindInterface, "trait"},
+		{"rust enum", SymbolTypeType, "enum", SymbolKindEnum, ""},
+		{"unknown type", SymbolType("opaque"), "", "opaque", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			symbol := &Symbol{Type: tt.symbolType}
+			if tt.subtype != "" {
+				symbol.Metadata = map[string]interface{}{SubtypeMetadataKey: tt.subtype}
+			}
+			ClassifySymbol(symbol)
+			if symbol.Kind != tt.wantKind {
+				t.Errorf("Kind = %q, want %q", symbol.Kind, tt.wantKind)
+			}
+			if got := symbol.Subtype(); got != tt.wantSubtype {
+				t.Errorf("Subtype() = %q, want %q", got, tt.wantSubtype)
+			}
+		})
+	}
+}
+
+func TestSymbolMatchesKind(t *testing.T) {
+	hook := &Symbol{Type: SymbolTypeHook}
+	ClassifySymbol(hook)
+	goStruct := &Symbol{Type: SymbolTypeType, Metadata: map[string]interface{}{SubtypeMetadataKey: "struct"}}
+	ClassifySymbol(goStruct)
+	legacy := &Symbol{Type: SymbolTypeType} // Loaded from a snapshot without Kind
+
+	tests := []struct {
+		symbol *Symbol
+		filter string
+		want   bool
+	}{
+		{hook, "function", true},
+		{hook, "Hook", true},
+		{hook, "func", true},
+		{hook, "method", false},
+		{goStruct, "class", true},
+		{goStruct, "struct", true},
+		{goStruct, "type", true},
+		{goStruct, "interface", false},
+		{legacy, "type-alias", true},
+		{legacy, "type_alias", true},
+		{legacy, "class", false},
+	}
+	for _, tt := range tests {
+		if got := tt.symbol.MatchesKind(tt.filter); got != tt.want {
+			t.Errorf("%s/%s MatchesKind(%q) = %v, want %v", tt.symbol.Type, tt.symbol.Subtype(), tt.filter, got, tt.want)
+		}
+	}
+}
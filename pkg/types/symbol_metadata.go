@@ -0,0 +1,75 @@
+package types
+
+// MetadataString returns a string value from symbol metadata, or "" if absent
+func (s *Symbol) MetadataString(key string) string {
+	if s == nil || s.Metadata == nil {
+		return ""
+	}
+	value, _ := s.Metadata[key].(string)
+	return value
+}
+
+// MetadataStrings returns a string list from symbol metadata.
+// Lists decoded from JSON arrive as []interface{} and are converted transparently.
+func (s *Symbol) MetadataStrings(key string) []string {
+	if s == nil || s.Metadata == nil {
+		return nil
+	}
+	switch values := s.Metadata[key].(type) {
+	case []string:
+		return values
+	case []interface{}:
+		result := make([]string, 0, len(values))
+		for _, v := range values {
+			if str, ok := v.(string); ok {
+				result = append(result, str)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// SetMetadata stores a metadata value, allocating the map on first use
+func (s *Symbol) SetMetadata(key string, value interface{}) {
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]interface{})
+	}
+	s.Metadata[key] = value
+}
+
+// MetadataInt returns an integer value from symbol metadata.
+// Numbers decoded from JSON arrive as float64 and are converted transparently.
+func (s *Symbol) MetadataInt(key string) (int, bool) {
+	if s == nil || s.Metadata == nil {
+		return 0, false
+	}
+	switch value := s.Metadata[key].(type) {
+	case int:
+		return value, true
+	case float64:
+		return int(value), true
+	}
+	return 0, false
+}
+
+// MetadataStringMap returns a string map from symbol metadata.
+// Maps decoded from JSON arrive as map[string]interface{} and are converted transparently.
+func (s *Symbol) MetadataStringMap(key string) map[string]string {
+	if s == nil || s.Metadata == nil {
+		return nil
+	}
+	switch values := s.Metadata[key].(type) {
+	case map[string]string:
+		return values
+	case map[string]interface{}:
+		result := make(map[string]string, len(values))
+		for k, v := range values {
+			if str, ok := v.(string); ok {
+				result[k] = str
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package types
+
+// Metadata keys holding the literal values of constants and enums. Strings
+// are stored without their quotes and numbers as written in the source.
+const (
+	// ValueMetadataKey holds the value a constant or variable is initialized to
+	ValueMetadataKey = "value"
+	// EnumMembersMetadataKey lists the member names of an enum in declaration order
+	EnumMembersMetadataKey = "enum_members"
+	// EnumValuesMetadataKey maps enum members with an explicit value to that value
+	EnumValuesMetadataKey = "enum_values"
+)
+
+// MatchesValue reports whether a symbol defines the exact value: the literal
+// value of a constant, or the name or value of one of its enum members
+func (s *Symbol) MatchesValue(value string) bool {
+	if value == "" {
+		return false
+	}
+	if s.MetadataString(ValueMetadataKey) == value {
+		return true
+	}
+	for _, member := range s.MetadataStrings(EnumMembersMetadataKey) {
+		if member == value {
+			return true
+		}
+	}
+	for _, memberValue := range s.MetadataStringMap(EnumValuesMetadataKey) {
+		if memberValue == value {
+			return true
+		}
+	}
+	return false
+}
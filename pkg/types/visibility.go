@@ -0,0 +1,19 @@
+package types
+
+// Symbol visibilities. C++ and Java members can also be "protected"; Java
+// members without a modifier are "package" and Rust items marked pub(crate)
+// or pub(super) are "internal".
+const (
+	VisibilityPublic    = "public"
+	VisibilityProtected = "protected"
+	VisibilityPackage   = "package"
+	VisibilityInternal  = "internal"
+	VisibilityPrivate   = "private"
+)
+
+// IsPublic reports whether a symbol is part of its module's public API.
+// Symbols from languages without a visibility convention have no recorded
+// visibility and count as public.
+func (s *Symbol) IsPublic() bool {
+	return s.Visibility == "" || s.Visibility == VisibilityPublic
+}